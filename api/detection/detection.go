@@ -0,0 +1,185 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package detection defines the GPU detection API served by gfd-extender and consumed by the controller.
+// Both sides import these types, so a field added here reaches the decoder in the same change.
+package detection
+
+import "time"
+
+const (
+	// PathV1 serves a bare JSON array of devices without a schema version.
+	PathV1 = "/api/v1/detect/gpu"
+	// PathV2 serves a Response envelope.
+	PathV2 = "/api/v2/detect/gpu"
+
+	// PortName is the gfd-extender container port serving the detection API.
+	PortName = "detect"
+	// DefaultPort is the port gfd-extender listens on unless the bootstrap values override it.
+	DefaultPort int32 = 2376
+	// TokenAudience is the audience of the projected token the controller scrapes with. gfd-extender
+	// rejects tokens issued for any other audience, so the controller's API token never leaves the pod.
+	TokenAudience = "gfd-extender.gpu.deckhouse.io"
+
+	// SchemaVersion is the Response schema written by this package. Newer versions only add fields,
+	// so a reader decodes any version with the fields it knows.
+	SchemaVersion = 2
+)
+
+// Response is the v2 envelope.
+type Response struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	GeneratedAt   time.Time `json:"generatedAt"`
+	Devices       []Device  `json:"devices"`
+	// Platform holds the host facts collected next to the devices; nil when the extender does not collect them.
+	Platform *Platform `json:"platform,omitempty"`
+}
+
+// Platform describes the host OS, kernel and firmware facts that influence GPU operation. A field the
+// extender could not determine is left empty rather than guessed.
+type Platform struct {
+	OS            *OSRelease `json:"os,omitempty"`
+	KernelRelease string     `json:"kernelRelease,omitempty"`
+	// BareMetal is false on a virtual machine; Hypervisor then names it when DMI or sysfs identify it.
+	BareMetal  *bool  `json:"bareMetal,omitempty"`
+	Hypervisor string `json:"hypervisor,omitempty"`
+	SecureBoot *bool  `json:"secureBoot,omitempty"`
+	// IOMMU reports whether the kernel runs with an IOMMU enabled.
+	IOMMU *bool `json:"iommu,omitempty"`
+	// UnsignedDriverModule is true when the loaded nvidia kernel module tainted the kernel as unsigned;
+	// nil when the module is not loaded.
+	UnsignedDriverModule *bool `json:"unsignedDriverModule,omitempty"`
+}
+
+// OSRelease carries the identifying fields of the host os-release file.
+type OSRelease struct {
+	ID        string `json:"id,omitempty"`
+	VersionID string `json:"versionID,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// Device is the NVML data of one GPU. The v1 endpoint serves the same objects without the envelope.
+type Device struct {
+	Index                       int           `json:"index"`
+	UUID                        string        `json:"uuid"`
+	Name                        string        `json:"name"`
+	Product                     string        `json:"product"`
+	MemoryInfo                  MemoryInfo    `json:"memoryInfo"`
+	MemoryInfoV2                MemoryInfoV2  `json:"memoryInfoV2"`
+	TemperatureC                int32         `json:"temperatureC"`
+	PowerUsage                  uint32        `json:"powerUsage"`
+	PowerState                  PState        `json:"powerState"`
+	PowerManagementDefaultLimit uint32        `json:"powerManagementDefaultLimit"`
+	InformImageVersion          string        `json:"informImageVersion"`
+	DriverVersion               string        `json:"systemGetDriverVersion"`
+	CUDADriverVersion           int           `json:"systemGetCudaDriverVersion"`
+	GraphicsRunningProcesses    []ProcessInfo `json:"graphicsRunningProcesses"`
+	Utilization                 Utilization   `json:"utilization"`
+	MemoryMiB                   int32         `json:"memoryMiB"`
+	ComputeMajor                int32         `json:"computeMajor"`
+	ComputeMinor                int32         `json:"computeMinor"`
+	NUMANode                    *int32        `json:"numaNode,omitempty"`
+	SMCount                     *int32        `json:"smCount,omitempty"`
+	MemoryBandwidthMiB          *int32        `json:"memoryBandwidthMiB,omitempty"`
+	PCI                         PCIInfo       `json:"pci"`
+	PCIE                        PCIELink      `json:"pcie"`
+	Board                       string        `json:"board"`
+	Family                      string        `json:"family"`
+	Serial                      string        `json:"serial"`
+	DisplayMode                 string        `json:"displayMode"`
+	Precision                   []string      `json:"precision,omitempty"`
+	MIG                         MIGInfo       `json:"mig"`
+	// Partial indicates that some fields failed to collect; details are in Warnings.
+	Partial  bool     `json:"partial,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+
+	// MIGInstances lists the MIG devices currently carved out of the GPU (v2).
+	MIGInstances []MIGInstance `json:"migInstances,omitempty"`
+	// ECCErrors holds the volatile ECC counters; nil when ECC is unsupported or disabled (v2).
+	ECCErrors *ECCErrors `json:"eccErrors,omitempty"`
+	// ConfidentialComputing is the system-wide CC mode; nil when the driver cannot report it (v2).
+	ConfidentialComputing *ConfidentialComputing `json:"confidentialComputing,omitempty"`
+	// PersistenceMode reports whether driver persistence mode is on; nil when the driver cannot report it (v2).
+	PersistenceMode *bool `json:"persistenceMode,omitempty"`
+	// InitError is set when the device could not be queried at all; the other fields are then empty (v2).
+	InitError string `json:"initError,omitempty"`
+}
+
+type PCIInfo struct {
+	Address   string `json:"address"`
+	Vendor    string `json:"vendor"`
+	Device    string `json:"device"`
+	Class     string `json:"class"`
+	Subsystem string `json:"subsystem,omitempty"`
+}
+
+type PCIELink struct {
+	Generation *int32 `json:"generation,omitempty"`
+	Width      *int32 `json:"width,omitempty"`
+}
+
+type MIGInfo struct {
+	Capable           bool     `json:"capable"`
+	Mode              string   `json:"mode,omitempty"`
+	ProfilesSupported []string `json:"profilesSupported,omitempty"`
+}
+
+// MIGInstance is one MIG device of a GPU.
+type MIGInstance struct {
+	UUID              string `json:"uuid"`
+	Profile           string `json:"profile,omitempty"`
+	GPUInstanceID     int    `json:"gpuInstanceID"`
+	ComputeInstanceID int    `json:"computeInstanceID"`
+	MemoryMiB         int32  `json:"memoryMiB,omitempty"`
+}
+
+// ECCErrors are the volatile ECC error counters since the last driver load.
+type ECCErrors struct {
+	Corrected   uint64 `json:"corrected"`
+	Uncorrected uint64 `json:"uncorrected"`
+}
+
+// ConfidentialComputing describes the Confidential Computing mode the GPU runs in.
+type ConfidentialComputing struct {
+	Enabled         bool `json:"enabled"`
+	DevToolsAllowed bool `json:"devToolsAllowed"`
+}
+
+type MemoryInfo struct {
+	Total uint64 `json:"Total"`
+	Free  uint64 `json:"Free"`
+	Used  uint64 `json:"Used"`
+}
+
+type MemoryInfoV2 struct {
+	Version  uint32 `json:"Version"`
+	Total    uint64 `json:"Total"`
+	Reserved uint64 `json:"Reserved"`
+	Free     uint64 `json:"Free"`
+	Used     uint64 `json:"Used"`
+}
+
+type ProcessInfo struct {
+	Pid               uint32 `json:"Pid"`
+	UsedGpuMemory     uint64 `json:"UsedGpuMemory"`
+	GpuInstanceId     uint32 `json:"GpuInstanceId"`
+	ComputeInstanceId uint32 `json:"ComputeInstanceId"`
+}
+
+type Utilization struct {
+	GPU    uint32 `json:"Gpu"`
+	Memory uint32 `json:"Memory"`
+}
+
+type PState uint32
@@ -0,0 +1,99 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detection
+
+import "math"
+
+// Bytes is a memory size in bytes, the unit NVML reports totals in and memoryInfo carries.
+type Bytes uint64
+
+// MiB is a memory size in mebibytes, the unit of every memoryMiB field.
+type MiB int32
+
+const bytesPerMiB = 1 << 20
+
+// MemoryTolerancePercent is how far two sources of the same device memory may differ before they are
+// treated as disagreeing. NVML and the NodeFeature labels round differently, so exact equality is not expected.
+const MemoryTolerancePercent = 5
+
+// MiB converts b to whole mebibytes, rounding down like nvidia-smi. Sizes beyond the int32 range saturate.
+func (b Bytes) MiB() MiB {
+	mib := uint64(b) / bytesPerMiB
+	if mib > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return MiB(mib)
+}
+
+// Bytes converts m to bytes; a negative size yields zero.
+func (m MiB) Bytes() Bytes {
+	if m < 0 {
+		return 0
+	}
+	return Bytes(uint64(m) * bytesPerMiB)
+}
+
+// MemorySource names the Device field a memory size was read from.
+type MemorySource string
+
+const (
+	MemorySourceInfoV2 MemorySource = "memoryInfoV2.Total"
+	MemorySourceInfo   MemorySource = "memoryInfo.Total"
+	MemorySourceMiB    MemorySource = "memoryMiB"
+)
+
+// MemoryReading is one non-zero memory size reported for a device.
+type MemoryReading struct {
+	Source MemorySource
+	Size   MiB
+}
+
+// MemoryReadings lists the memory sizes d reports, most precise first: the byte totals of memoryInfoV2 and
+// memoryInfo, then the already rounded memoryMiB. Unset fields are skipped.
+func (d Device) MemoryReadings() []MemoryReading {
+	var readings []MemoryReading
+	if d.MemoryInfoV2.Total > 0 {
+		readings = append(readings, MemoryReading{Source: MemorySourceInfoV2, Size: Bytes(d.MemoryInfoV2.Total).MiB()})
+	}
+	if d.MemoryInfo.Total > 0 {
+		readings = append(readings, MemoryReading{Source: MemorySourceInfo, Size: Bytes(d.MemoryInfo.Total).MiB()})
+	}
+	if d.MemoryMiB > 0 {
+		readings = append(readings, MemoryReading{Source: MemorySourceMiB, Size: MiB(d.MemoryMiB)})
+	}
+	return readings
+}
+
+// TotalMemory returns the most precise memory size d reports; ok is false when it reports none.
+func (d Device) TotalMemory() (MemoryReading, bool) {
+	readings := d.MemoryReadings()
+	if len(readings) == 0 {
+		return MemoryReading{}, false
+	}
+	return readings[0], true
+}
+
+// MemoryDisagrees reports whether a and b differ by more than MemoryTolerancePercent of the larger one.
+// An unknown (zero) size never disagrees.
+func MemoryDisagrees(a, b MiB) bool {
+	if a <= 0 || b <= 0 {
+		return false
+	}
+	larger, diff := int64(a), int64(a)-int64(b)
+	if b > a {
+		larger, diff = int64(b), -diff
+	}
+	return diff*100 > larger*MemoryTolerancePercent
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package detection
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBytesMiBConversion(t *testing.T) {
+	cases := []struct {
+		bytes Bytes
+		mib   MiB
+	}{
+		{bytes: 0, mib: 0},
+		{bytes: 1073741824, mib: 1024},
+		{bytes: 85899345920, mib: 81920},
+		{bytes: 42505273344, mib: 40536},
+		{bytes: 1<<20 - 1, mib: 0},
+		{bytes: math.MaxUint64, mib: math.MaxInt32},
+	}
+	for _, tc := range cases {
+		if got := tc.bytes.MiB(); got != tc.mib {
+			t.Errorf("Bytes(%d).MiB() = %d, want %d", tc.bytes, got, tc.mib)
+		}
+	}
+	if got := MiB(1024).Bytes(); got != 1073741824 {
+		t.Fatalf("MiB(1024).Bytes() = %d", got)
+	}
+	if got := MiB(-1).Bytes(); got != 0 {
+		t.Fatalf("negative MiB must convert to zero bytes, got %d", got)
+	}
+}
+
+func TestTotalMemoryPrecedence(t *testing.T) {
+	cases := []struct {
+		name   string
+		device Device
+		want   MemoryReading
+		ok     bool
+	}{
+		{name: "none"},
+		{
+			name:   "memoryMiB only",
+			device: Device{MemoryMiB: 1024},
+			want:   MemoryReading{Source: MemorySourceMiB, Size: 1024},
+			ok:     true,
+		},
+		{
+			name:   "memoryInfo bytes over memoryMiB",
+			device: Device{MemoryInfo: MemoryInfo{Total: 1073741824}, MemoryMiB: 1000},
+			want:   MemoryReading{Source: MemorySourceInfo, Size: 1024},
+			ok:     true,
+		},
+		{
+			name:   "memoryInfoV2 bytes first",
+			device: Device{MemoryInfoV2: MemoryInfoV2{Total: 2 * 1073741824}, MemoryInfo: MemoryInfo{Total: 1073741824}, MemoryMiB: 1024},
+			want:   MemoryReading{Source: MemorySourceInfoV2, Size: 2048},
+			ok:     true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.device.TotalMemory()
+			if ok != tc.ok || got != tc.want {
+				t.Fatalf("TotalMemory() = %+v, %v; want %+v, %v", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestMemoryDisagrees(t *testing.T) {
+	cases := []struct {
+		a, b MiB
+		want bool
+	}{
+		{a: 81920, b: 81559, want: false},
+		{a: 40960, b: 40536, want: false},
+		{a: 1000, b: 950, want: false},
+		{a: 1000, b: 949, want: true},
+		{a: 1024, b: 1024 * 1024, want: true},
+		{a: 0, b: 1024, want: false},
+	}
+	for _, tc := range cases {
+		if got := MemoryDisagrees(tc.a, tc.b); got != tc.want {
+			t.Errorf("MemoryDisagrees(%d, %d) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+		if got := MemoryDisagrees(tc.b, tc.a); got != tc.want {
+			t.Errorf("MemoryDisagrees(%d, %d) = %v, want %v", tc.b, tc.a, got, tc.want)
+		}
+	}
+}
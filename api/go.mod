@@ -2,7 +2,10 @@ module github.com/aleksandr-podmoskovniy/gpu-control-plane/api
 
 go 1.24.6
 
-require k8s.io/apimachinery v0.30.11
+require (
+	k8s.io/api v0.30.11
+	k8s.io/apimachinery v0.30.11
+)
 
 require (
 	github.com/go-logr/logr v1.4.1 // indirect
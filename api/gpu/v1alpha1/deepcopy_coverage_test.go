@@ -47,6 +47,7 @@ func TestDeepCopyNilReceivers(t *testing.T) {
 		poolCapacity   *GPUPoolCapacityStatus
 		poolSelector   *GPUPoolDeviceSelector
 		poolRef        *GPUPoolReference
+		poolReqs       *GPUPoolRequirements
 		poolResource   *GPUPoolResourceSpec
 		poolScheduling *GPUPoolSchedulingSpec
 		poolRules      *GPUPoolSelectorRules
@@ -126,6 +127,9 @@ func TestDeepCopyNilReceivers(t *testing.T) {
 	if poolSpec.DeepCopy() != nil {
 		t.Fatalf("expected GPUPoolSpec nil deepcopy to return nil")
 	}
+	if poolReqs.DeepCopy() != nil {
+		t.Fatalf("expected GPUPoolRequirements nil deepcopy to return nil")
+	}
 	if poolStatus.DeepCopy() != nil {
 		t.Fatalf("expected GPUPoolStatus nil deepcopy to return nil")
 	}
@@ -149,12 +153,13 @@ func TestDeepCopyCoversAllGeneratedMethods(t *testing.T) {
 	migCapable := ptrBool(true)
 
 	rules := GPUPoolSelectorRules{
-		InventoryIDs: []string{"inv-1"},
-		Products:     []string{"GPU Model"},
-		PCIVendors:   []string{"10de"},
-		PCIDevices:   []string{"1db6"},
-		MIGCapable:   migCapable,
-		MIGProfiles:  []string{"1g.10gb"},
+		InventoryIDs:          []string{"inv-1"},
+		Products:              []string{"GPU Model"},
+		PCIVendors:            []string{"10de"},
+		PCIDevices:            []string{"1db6"},
+		MIGCapable:            migCapable,
+		MIGProfiles:           []string{"1g.10gb"},
+		ConfidentialComputing: ptrBool(false),
 	}
 	if rules.DeepCopy() == nil {
 		t.Fatalf("expected GPUPoolSelectorRules.DeepCopy result")
@@ -173,10 +178,24 @@ func TestDeepCopyCoversAllGeneratedMethods(t *testing.T) {
 	}
 
 	hardware := GPUDeviceHardware{
-		UUID:    "GPU-UUID",
-		Product: "GPU Model",
-		PCI:     PCIAddress{Vendor: "10de", Device: "1db6", Class: "0302", Address: "0000:00:01.0"},
-		MIG:     mig,
+		UUID:                  "GPU-UUID",
+		Product:               "GPU Model",
+		PCI:                   PCIAddress{Vendor: "10de", Device: "1db6", Class: "0302", Address: "0000:00:01.0"},
+		MIG:                   mig,
+		Precision:             []string{"fp16", "fp32"},
+		ConfidentialComputing: &GPUConfidentialComputing{Enabled: true},
+	}
+	hardwareCopy := hardware.DeepCopy()
+	hardwareCopy.Precision[0] = "bf16"
+	if hardware.Precision[0] != "fp16" {
+		t.Fatalf("expected hardware precision to be deep-copied")
+	}
+	hardwareCopy.ConfidentialComputing.Enabled = false
+	if !hardware.ConfidentialComputing.Enabled {
+		t.Fatalf("expected confidential computing state to be deep-copied")
+	}
+	if (*GPUConfidentialComputing)(nil).DeepCopy() != nil {
+		t.Fatalf("expected nil GPUConfidentialComputing to copy to nil")
 	}
 
 	deviceStatus := GPUDeviceStatus{
@@ -188,6 +207,7 @@ func TestDeepCopyCoversAllGeneratedMethods(t *testing.T) {
 		PoolRef:     &GPUPoolReference{Name: "pool", Namespace: "ns"},
 		Hardware:    hardware,
 		Conditions:  []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, LastTransitionTime: transitionTime}},
+		Provenance:  &GPUDataProvenance{NodeFeature: "ns/node-1", ResourceVersion: "1", CapturedAt: transitionTime},
 	}
 	if deviceStatus.DeepCopy() == nil {
 		t.Fatalf("expected GPUDeviceStatus.DeepCopy result")
@@ -195,6 +215,9 @@ func TestDeepCopyCoversAllGeneratedMethods(t *testing.T) {
 	if deviceStatus.DeepCopy().PoolRef == deviceStatus.PoolRef {
 		t.Fatalf("expected GPUDeviceStatus to deep-copy PoolRef")
 	}
+	if deviceStatus.DeepCopy().Provenance == deviceStatus.Provenance {
+		t.Fatalf("expected GPUDeviceStatus to deep-copy Provenance")
+	}
 	if reflect.DeepEqual(deviceStatus.DeepCopy().Hardware.MIG.Types, deviceStatus.Hardware.MIG.Types) && &deviceStatus.DeepCopy().Hardware.MIG.Types[0] == &deviceStatus.Hardware.MIG.Types[0] {
 		t.Fatalf("expected GPUDeviceStatus to deep-copy MIG types slice")
 	}
@@ -238,18 +261,32 @@ func TestDeepCopyCoversAllGeneratedMethods(t *testing.T) {
 			TaintsEnabled: taintsEnabled,
 			Taints:        []GPUPoolTaintSpec{{Key: "k", Value: "v", Effect: "NoSchedule"}},
 		},
+		Requirements: &GPUPoolRequirements{
+			MinDriverVersion:   "535.104.05",
+			RequiredPrecisions: []string{"fp16"},
+		},
 	}
-	if poolSpec.DeepCopy() == nil {
+	poolSpecCopy := poolSpec.DeepCopy()
+	if poolSpecCopy == nil {
 		t.Fatalf("expected GPUPoolSpec.DeepCopy result")
 	}
+	poolSpecCopy.Requirements.RequiredPrecisions[0] = "bf16"
+	if poolSpec.Requirements.RequiredPrecisions[0] != "fp16" {
+		t.Fatalf("expected requirements precisions to be deep-copied")
+	}
 
 	poolStatus := GPUPoolStatus{
-		Capacity:   GPUPoolCapacityStatus{Total: 3},
+		Capacity:   GPUPoolCapacityStatus{Total: 3, PlacementsAvailable: map[string]int32{"1g.10gb": 7}},
 		Conditions: []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, LastTransitionTime: transitionTime}},
 	}
-	if poolStatus.DeepCopy() == nil {
+	poolStatusCopy := poolStatus.DeepCopy()
+	if poolStatusCopy == nil {
 		t.Fatalf("expected GPUPoolStatus.DeepCopy result")
 	}
+	poolStatusCopy.Capacity.PlacementsAvailable["1g.10gb"] = 0
+	if poolStatus.Capacity.PlacementsAvailable["1g.10gb"] != 7 {
+		t.Fatalf("expected placements to be deep-copied")
+	}
 
 	pool := &GPUPool{
 		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
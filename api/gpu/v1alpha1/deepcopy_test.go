@@ -23,6 +23,7 @@ import (
 
 func TestGPUNodeStateStatusDeepCopy(t *testing.T) {
 	ts := metav1.NewTime(time.Unix(1710000000, 0))
+	secureBoot := true
 
 	original := &GPUNodeStateStatus{
 		Conditions: []metav1.Condition{{
@@ -32,6 +33,9 @@ func TestGPUNodeStateStatusDeepCopy(t *testing.T) {
 			Message:            "node ready",
 			LastTransitionTime: ts,
 		}},
+		LastReconcileTime: &ts,
+		Platform:          &GPUNodePlatform{KernelRelease: "6.8.0", SecureBoot: &secureBoot, IOMMU: &secureBoot},
+		DisplayDevices:    []GPUNodeDisplayDevice{{Index: "1", PCI: PCIAddress{Vendor: "1a03", Class: "0300"}}},
 	}
 
 	cloned := original.DeepCopy()
@@ -43,4 +47,15 @@ func TestGPUNodeStateStatusDeepCopy(t *testing.T) {
 	if original.Conditions[0].Reason != "OK" {
 		t.Fatal("conditions slice should be deep-copied")
 	}
+	if cloned.LastReconcileTime == original.LastReconcileTime {
+		t.Fatal("lastReconcileTime should be deep-copied")
+	}
+	*cloned.Platform.SecureBoot = false
+	if !*original.Platform.SecureBoot || cloned.Platform.IOMMU == original.Platform.IOMMU {
+		t.Fatal("platform should be deep-copied")
+	}
+	cloned.DisplayDevices[0].PCI.Vendor = "10de"
+	if original.DisplayDevices[0].PCI.Vendor != "1a03" {
+		t.Fatal("display devices should be deep-copied")
+	}
 }
@@ -17,6 +17,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -94,8 +95,40 @@ type GPUDeviceStatus struct {
 	PoolRef *GPUPoolReference `json:"poolRef,omitempty"`
 	// Hardware stores static hardware characteristics exported by inventory.
 	Hardware GPUDeviceHardware `json:"hardware,omitempty"`
+	// DriverVersion is the NVIDIA driver version reported for the device's node (e.g. 535.104.05).
+	DriverVersion string `json:"driverVersion,omitempty"`
 	// Conditions list high-level conditions maintained by controllers (ReadyForPooling, ManagedDisabled, etc.).
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// History keeps the most recent state transitions of the device, oldest first (at most 10 entries).
+	// +kubebuilder:validation:MaxItems=10
+	History []GPUDeviceStateTransition `json:"history,omitempty"`
+	// Provenance identifies the NodeFeature the hardware data was last taken from.
+	// +optional
+	Provenance *GPUDataProvenance `json:"provenance,omitempty"`
+}
+
+// GPUDataProvenance records which NodeFeature revision the inventory data was captured from. It is only
+// refreshed when that data changes the object, so it points at the revision that produced the current values.
+type GPUDataProvenance struct {
+	// NodeFeature is the namespace/name of the NodeFeature object.
+	NodeFeature string `json:"nodeFeature,omitempty"`
+	// ResourceVersion is the NodeFeature resourceVersion at capture time.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Generation is the NodeFeature generation at capture time.
+	Generation int64 `json:"generation,omitempty"`
+	// CapturedAt is when the inventory controller wrote the data.
+	CapturedAt metav1.Time `json:"capturedAt"`
+}
+
+type GPUDeviceStateTransition struct {
+	// From is the state the device left.
+	From GPUDeviceState `json:"from,omitempty"`
+	// To is the state the device entered.
+	To GPUDeviceState `json:"to"`
+	// Reason is a CamelCase token explaining why the controller moved the device.
+	Reason string `json:"reason,omitempty"`
+	// Time is when the transition was recorded.
+	Time metav1.Time `json:"time"`
 }
 
 type GPUPoolReference struct {
@@ -115,6 +148,24 @@ type GPUDeviceHardware struct {
 	PCI PCIAddress `json:"pci,omitempty"`
 	// MIG describes Multi-Instance GPU capabilities and available profiles.
 	MIG GPUMIGConfig `json:"mig,omitempty"`
+	// MemoryMiB is the total device memory in MiB.
+	MemoryMiB int32 `json:"memoryMiB,omitempty"`
+	// ComputeCapability is the CUDA compute capability in major.minor form (e.g. 8.0).
+	ComputeCapability string `json:"computeCapability,omitempty"`
+	// Precision lists the numeric precisions supported by the device (e.g. fp16, bf16, fp64).
+	Precision []string `json:"precision,omitempty"`
+	// ConfidentialComputing describes the confidential computing mode of the device. It is omitted when the driver
+	// cannot report it.
+	ConfidentialComputing *GPUConfidentialComputing `json:"confidentialComputing,omitempty"`
+	// PersistenceMode is the driver persistence mode of the device. It is omitted when the driver cannot report it.
+	PersistenceMode GPUPersistenceMode `json:"persistenceMode,omitempty"`
+}
+
+type GPUConfidentialComputing struct {
+	// Enabled reports whether the GPU runs in confidential computing mode.
+	Enabled bool `json:"enabled"`
+	// DevToolsAllowed reports whether developer tools (profiling, debugging) are permitted in that mode.
+	DevToolsAllowed bool `json:"devToolsAllowed"`
 }
 
 type PCIAddress struct {
@@ -137,8 +188,29 @@ type GPUMIGConfig struct {
 	ProfilesSupported []string `json:"profilesSupported,omitempty"`
 	// Types lists capacity counters for each MIG profile provisioned on the device.
 	Types []GPUMIGTypeCapacity `json:"types,omitempty"`
+	// Source tells where the MIG data came from: the NodeFeature instance of the device, the node-wide GFD
+	// labels, or both merged with the instance taking precedence.
+	// +optional
+	Source GPUMIGDataSource `json:"source,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Instance;NodeLabels;Merged
+type GPUMIGDataSource string
+
+const (
+	GPUMIGDataSourceInstance   GPUMIGDataSource = "Instance"
+	GPUMIGDataSourceNodeLabels GPUMIGDataSource = "NodeLabels"
+	GPUMIGDataSourceMerged     GPUMIGDataSource = "Merged"
+)
+
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type GPUPersistenceMode string
+
+const (
+	GPUPersistenceModeEnabled  GPUPersistenceMode = "Enabled"
+	GPUPersistenceModeDisabled GPUPersistenceMode = "Disabled"
+)
+
 // +kubebuilder:validation:Enum=None;Single;Mixed
 type GPUMIGStrategy string
 
@@ -167,6 +239,7 @@ type GPUDeviceList struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=gpunodestates,scope=Cluster,categories=deckhouse;gpu
 // +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`
 // +kubebuilder:printcolumn:name="Inventory",type=string,JSONPath=`.status.conditions[?(@.type=="InventoryComplete")].status`
 // +kubebuilder:printcolumn:name="Driver",type=string,JSONPath=`.status.conditions[?(@.type=="DriverReady")].status`
 // +kubebuilder:printcolumn:name="Toolkit",type=string,JSONPath=`.status.conditions[?(@.type=="ToolkitReady")].status`
@@ -192,6 +265,105 @@ type GPUNodeStateSpec struct {
 type GPUNodeStateStatus struct {
 	// Conditions surfaces aggregated readiness/alerting conditions for the node.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastReconcileTime is when the inventory controller last finished reconciling the node.
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+	// LastReconcileError is the error of the last reconcile, truncated to 256 characters; it is
+	// cleared by the next successful reconcile.
+	// +optional
+	LastReconcileError string `json:"lastReconcileError,omitempty"`
+	// Provenance identifies the NodeFeature the node-level inventory data was last taken from.
+	// +optional
+	Provenance *GPUDataProvenance `json:"provenance,omitempty"`
+	// Platform describes the host OS, kernel and firmware as last reported by gfd-extender.
+	// +optional
+	Platform *GPUNodePlatform `json:"platform,omitempty"`
+	// DisplayDevices lists the display-only adapters found on the node. They get no GPUDevice unless
+	// the module setting inventory.includeDisplayDevices is enabled.
+	// +optional
+	DisplayDevices []GPUNodeDisplayDevice `json:"displayDevices,omitempty"`
+	// Health is Healthy while the inventory is complete, the driver is ready and no device of the node
+	// is Faulted, and Degraded otherwise.
+	// +optional
+	Health GPUNodeHealth `json:"health,omitempty"`
+	// HealthReasons lists why the node is Degraded.
+	// +optional
+	HealthReasons []string `json:"healthReasons,omitempty"`
+	// TopologySummary is a printable one-line-per-device view of the node for kubectl describe. It is
+	// derived from the GPUDevices of the node on every reconcile and is never read back by the controller.
+	// +optional
+	TopologySummary *GPUNodeTopologySummary `json:"topologySummary,omitempty"`
+	// ContainerRuntime is the container runtime the kubelet of the node reports.
+	// +optional
+	ContainerRuntime *GPUNodeContainerRuntime `json:"containerRuntime,omitempty"`
+}
+
+// GPUNodeContainerRuntime identifies the container runtime of a node.
+type GPUNodeContainerRuntime struct {
+	// Name is the runtime, for example containerd or cri-o; runtimes without rendering support keep the
+	// name the kubelet reports.
+	Name string `json:"name"`
+	// Version is the runtime version as reported by the kubelet.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// GPUNodeTopologySummary is a human-oriented dump of the devices of a node.
+type GPUNodeTopologySummary struct {
+	// Devices holds one line per device ordered by index: index, product, memory, MIG strategy, state
+	// and the flags raised on the device.
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Devices []string `json:"devices,omitempty"`
+	// Omitted is the number of devices left out past the line cap.
+	// +optional
+	Omitted int32 `json:"omitted,omitempty"`
+	// GeneratedAt is when the summary content last changed.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+}
+
+// GPUNodeHealth is the health class of a node rolled up from its conditions and device states.
+// +kubebuilder:validation:Enum=Healthy;Degraded
+type GPUNodeHealth string
+
+const (
+	GPUNodeHealthHealthy  GPUNodeHealth = "Healthy"
+	GPUNodeHealthDegraded GPUNodeHealth = "Degraded"
+)
+
+// GPUNodeDisplayDevice describes an adapter that can drive a display but offers no compute capability.
+type GPUNodeDisplayDevice struct {
+	// Index is the device index as reported by the node feature discovery.
+	Index string `json:"index,omitempty"`
+	// Product is a human readable model name.
+	Product string `json:"product,omitempty"`
+	// PCI contains vendor/device/class identifiers and the address of the PCI function.
+	PCI PCIAddress `json:"pci,omitempty"`
+	// DisplayMode is the display mode reported by the driver, empty when the driver does not manage the adapter.
+	DisplayMode string `json:"displayMode,omitempty"`
+}
+
+// GPUNodePlatform lists the node facts that influence GPU operation. Fields that could not be determined
+// on the node are omitted.
+type GPUNodePlatform struct {
+	// OSID is the ID field of the host os-release file.
+	OSID string `json:"osID,omitempty"`
+	// OSVersion is the VERSION_ID field of the host os-release file.
+	OSVersion string `json:"osVersion,omitempty"`
+	// OSName is the NAME field of the host os-release file.
+	OSName string `json:"osName,omitempty"`
+	// KernelRelease is the running kernel release.
+	KernelRelease string `json:"kernelRelease,omitempty"`
+	// BareMetal is false when the node is a virtual machine.
+	BareMetal *bool `json:"bareMetal,omitempty"`
+	// Hypervisor names the hypervisor of a virtual machine node.
+	Hypervisor string `json:"hypervisor,omitempty"`
+	// SecureBoot reports whether UEFI Secure Boot is enabled.
+	SecureBoot *bool `json:"secureBoot,omitempty"`
+	// IOMMU reports whether the kernel runs with an IOMMU enabled.
+	IOMMU *bool `json:"iommu,omitempty"`
+	// UnsignedDriverModule is true when the loaded NVIDIA kernel module is not signed.
+	UnsignedDriverModule *bool `json:"unsignedDriverModule,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -235,18 +407,97 @@ type GPUPoolSpec struct {
 	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
 	// DeviceSelector filters devices that may join the pool.
 	DeviceSelector *GPUPoolDeviceSelector `json:"deviceSelector,omitempty"`
+	// Devices pins the pool to an explicit list of devices instead of deviceSelector and nodeSelector.
+	// +kubebuilder:validation:MaxItems=256
+	Devices []GPUDeviceReference `json:"devices,omitempty"`
 	// DeviceAssignment controls manual vs automatic assignment flows.
 	DeviceAssignment GPUPoolAssignmentSpec `json:"deviceAssignment,omitempty"`
 	// Scheduling configures topology spreading, taints and other scheduling hints.
 	Scheduling GPUPoolSchedulingSpec `json:"scheduling,omitempty"`
+	// DriverInstallType tells how the NVIDIA driver is installed on pool nodes:
+	// by the driver container (Operator) or baked into the OS image (Preinstalled).
+	// Defaults to the controller setting when empty.
+	DriverInstallType GPUPoolDriverInstallType `json:"driverInstallType,omitempty"`
+	// Requirements lists minimum driver and hardware characteristics a device must meet to contribute capacity.
+	Requirements *GPUPoolRequirements `json:"requirements,omitempty"`
+	// Advanced holds node-level options of the rendered pool components.
+	Advanced *GPUPoolAdvancedSpec `json:"advanced,omitempty"`
+	// HealthChecks configures the DCGM-based health checks of the device plugin.
+	HealthChecks *GPUPoolHealthChecksSpec `json:"healthChecks,omitempty"`
+	// DevicePlugin adds environment variables and volumes to the device plugin container.
+	DevicePlugin *GPUPoolComponentOverrides `json:"devicePlugin,omitempty"`
+	// MIGManager adds environment variables and volumes to the MIG manager container.
+	MIGManager *GPUPoolComponentOverrides `json:"migManager,omitempty"`
+}
+
+// GPUPoolComponentOverrides is an escape hatch for settings the renderer does not model. The entries are applied after
+// the renderer's own configuration and may not reuse the env names, volume names or mount paths it manages. A
+// namespaced GPUPool is limited to literal env values and emptyDir and downwardAPI volumes, since its components run
+// in the module namespace.
+type GPUPoolComponentOverrides struct {
+	// ExtraEnv is appended to the container environment.
+	// +kubebuilder:validation:MaxItems=32
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+	// ExtraVolumeMounts is appended to the container volume mounts.
+	// +kubebuilder:validation:MaxItems=16
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+	// ExtraVolumes is appended to the pod volumes.
+	// +kubebuilder:validation:MaxItems=16
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+}
+
+type GPUPoolHealthChecksSpec struct {
+	// Enabled makes the device plugin withdraw GPUs that DCGM reports unhealthy. Disabling it also turns
+	// off the plugin's built-in health checks; leave healthChecks unset to keep the plugin defaults.
+	Enabled bool `json:"enabled"`
+	// UnhealthyThreshold is the number of consecutive failed checks before a GPU is withdrawn; defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	UnhealthyThreshold int32 `json:"unhealthyThreshold,omitempty"`
+	// DCGMSocket is the host path of an externally managed nv-hostengine socket. When empty the module's DCGM
+	// host engine is used through its conventional socket path.
+	DCGMSocket string `json:"dcgmSocket,omitempty"`
 }
 
+type GPUPoolAdvancedSpec struct {
+	// GPUDirectRDMA mounts /dev/infiniband and the host kernel modules into the device plugin.
+	GPUDirectRDMA bool `json:"gpuDirectRDMA,omitempty"`
+	// HostNetwork runs the device plugin and validator pods in the host network namespace.
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+}
+
+type GPUPoolRequirements struct {
+	// MinDriverVersion is the lowest NVIDIA driver version accepted (e.g. 535.104.05).
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)*$`
+	MinDriverVersion string `json:"minDriverVersion,omitempty"`
+	// MinComputeCapability is the lowest CUDA compute capability accepted in major.minor form (e.g. 8.0).
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+$`
+	MinComputeCapability string `json:"minComputeCapability,omitempty"`
+	// MinMemoryMiB is the lowest total device memory accepted, in MiB.
+	// +kubebuilder:validation:Minimum=0
+	MinMemoryMiB int32 `json:"minMemoryMiB,omitempty"`
+	// RequiredPrecisions lists numeric precisions every device must support (e.g. fp16, bf16).
+	RequiredPrecisions []string `json:"requiredPrecisions,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Operator;Preinstalled
+type GPUPoolDriverInstallType string
+
+const (
+	GPUPoolDriverInstallOperator     GPUPoolDriverInstallType = "Operator"
+	GPUPoolDriverInstallPreinstalled GPUPoolDriverInstallType = "Preinstalled"
+)
+
 type GPUPoolResourceSpec struct {
 	// Unit describes the resource unit (Card or MIG).
 	// +kubebuilder:validation:Enum=Card;MIG
 	Unit string `json:"unit"`
 	// MIGProfile specifies the MIG profile when Unit=MIG (single profile shortcut).
 	MIGProfile string `json:"migProfile,omitempty"`
+	// MIGLayout partitions every pool device into several MIG profiles at once (the mixed MIG strategy)
+	// when Unit=MIG. Each profile is advertised as its own resource named <resource>-<profile>.
+	// Mutually exclusive with migProfile.
+	// +kubebuilder:validation:MaxItems=8
+	MIGLayout []GPUPoolMIGLayoutEntry `json:"migLayout,omitempty"`
 	// MaxDevicesPerNode caps number of devices contributed per node.
 	MaxDevicesPerNode *int32 `json:"maxDevicesPerNode,omitempty"`
 	// SlicesPerUnit configures oversubscription per base unit (card or MIG partition).
@@ -256,6 +507,15 @@ type GPUPoolResourceSpec struct {
 	SlicesPerUnit int32 `json:"slicesPerUnit,omitempty"`
 }
 
+// GPUPoolMIGLayoutEntry is one MIG profile of a mixed layout.
+type GPUPoolMIGLayoutEntry struct {
+	// Profile is the MIG profile name (for example, 3g.40gb).
+	Profile string `json:"profile"`
+	// Count is the number of instances of the profile created on each device.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+}
+
 type GPUPoolDeviceSelector struct {
 	// Include defines positive selection rules for devices.
 	Include GPUPoolSelectorRules `json:"include,omitempty"`
@@ -263,6 +523,15 @@ type GPUPoolDeviceSelector struct {
 	Exclude GPUPoolSelectorRules `json:"exclude,omitempty"`
 }
 
+// GPUDeviceReference names a device of an explicit pool by its GPU UUID or by the GPUDevice object name.
+// Exactly one of the fields must be set.
+type GPUDeviceReference struct {
+	// UUID is the GPU UUID reported by the inventory (status.hardware.uuid).
+	UUID string `json:"uuid,omitempty"`
+	// Name is the GPUDevice object name.
+	Name string `json:"name,omitempty"`
+}
+
 type GPUPoolSelectorRules struct {
 	// InventoryIDs matches specific devices by inventory identifier.
 	InventoryIDs []string `json:"inventoryIDs,omitempty"`
@@ -276,6 +545,9 @@ type GPUPoolSelectorRules struct {
 	MIGCapable *bool `json:"migCapable,omitempty"`
 	// MIGProfiles matches devices that support at least one of the listed MIG profiles.
 	MIGProfiles []string `json:"migProfiles,omitempty"`
+	// ConfidentialComputing restricts selection to devices that run (or do not run) in confidential computing mode.
+	// Devices that do not report the mode count as not running in it.
+	ConfidentialComputing *bool `json:"confidentialComputing,omitempty"`
 }
 
 type GPUPoolAssignmentSpec struct {
@@ -318,6 +590,37 @@ type GPUPoolStatus struct {
 	Capacity GPUPoolCapacityStatus `json:"capacity"`
 	// Conditions surfaces pool-level status conditions.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Utilization reports rolling GPU utilization of the pool devices.
+	// It is set only when utilization statistics are enabled in the controller.
+	// +optional
+	Utilization *GPUPoolUtilizationStatus `json:"utilization,omitempty"`
+}
+
+// GPUPoolUtilizationStatus summarises GPU utilization telemetry of the pool devices.
+type GPUPoolUtilizationStatus struct {
+	// UpdatedAt is when the statistics were last written.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+	// ReportingDevices is the number of pool devices that reported telemetry in the last sample.
+	ReportingDevices int32 `json:"reportingDevices"`
+	// UnknownDevices is the number of pool devices without telemetry in the last sample.
+	// They are excluded from the statistics rather than counted as idle.
+	UnknownDevices int32 `json:"unknownDevices"`
+	// Windows lists statistics per rolling window; a window without samples is omitted.
+	// +optional
+	Windows []GPUPoolUtilizationWindow `json:"windows,omitempty"`
+}
+
+// GPUPoolUtilizationWindow holds utilization statistics over one rolling window.
+type GPUPoolUtilizationWindow struct {
+	// Window is the length of the rolling window.
+	// +kubebuilder:validation:Enum="1h";"24h"
+	Window string `json:"window"`
+	// AveragePercent is the mean GPU utilization of the pool devices over the window.
+	AveragePercent int32 `json:"averagePercent"`
+	// P95Percent is the 95th percentile of GPU utilization of the pool devices over the window.
+	P95Percent int32 `json:"p95Percent"`
+	// Samples is the number of device samples the statistics are computed from.
+	Samples int64 `json:"samples"`
 }
 
 // +kubebuilder:object:root=true
@@ -356,6 +659,11 @@ type GPUPoolCapacityStatus struct {
 	// Used is the capacity currently allocated to workloads.
 	// It is computed as a sum of requested units for scheduled Pods that request the pool resource.
 	Used int32 `json:"used"`
+	// PlacementsAvailable is reported for MIG pools: for each MIG profile, how many more instances could
+	// still be created on the pool devices given the instances already placed on them.
+	PlacementsAvailable map[string]int32 `json:"placementsAvailable,omitempty"`
+	// ByProfile is reported for MIG pools: the part of Total each MIG profile contributes.
+	ByProfile map[string]int32 `json:"byProfile,omitempty"`
 }
 
 // +kubebuilder:object:root=true
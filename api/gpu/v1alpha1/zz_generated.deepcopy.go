@@ -19,6 +19,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -82,6 +83,37 @@ func (in *ClusterGPUPoolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUConfidentialComputing) DeepCopyInto(out *GPUConfidentialComputing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUConfidentialComputing.
+func (in *GPUConfidentialComputing) DeepCopy() *GPUConfidentialComputing {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUConfidentialComputing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUDataProvenance) DeepCopyInto(out *GPUDataProvenance) {
+	*out = *in
+	in.CapturedAt.DeepCopyInto(&out.CapturedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDataProvenance.
+func (in *GPUDataProvenance) DeepCopy() *GPUDataProvenance {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUDataProvenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUDevice) DeepCopyInto(out *GPUDevice) {
 	*out = *in
@@ -114,6 +146,16 @@ func (in *GPUDeviceHardware) DeepCopyInto(out *GPUDeviceHardware) {
 	*out = *in
 	out.PCI = in.PCI
 	in.MIG.DeepCopyInto(&out.MIG)
+	if in.Precision != nil {
+		in, out := &in.Precision, &out.Precision
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfidentialComputing != nil {
+		in, out := &in.ConfidentialComputing, &out.ConfidentialComputing
+		*out = new(GPUConfidentialComputing)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDeviceHardware.
@@ -158,6 +200,21 @@ func (in *GPUDeviceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUDeviceReference) DeepCopyInto(out *GPUDeviceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDeviceReference.
+func (in *GPUDeviceReference) DeepCopy() *GPUDeviceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUDeviceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUDeviceSpec) DeepCopyInto(out *GPUDeviceSpec) {
 	*out = *in
@@ -173,6 +230,22 @@ func (in *GPUDeviceSpec) DeepCopy() *GPUDeviceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUDeviceStateTransition) DeepCopyInto(out *GPUDeviceStateTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDeviceStateTransition.
+func (in *GPUDeviceStateTransition) DeepCopy() *GPUDeviceStateTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUDeviceStateTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUDeviceStatus) DeepCopyInto(out *GPUDeviceStatus) {
 	*out = *in
@@ -189,6 +262,18 @@ func (in *GPUDeviceStatus) DeepCopyInto(out *GPUDeviceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]GPUDeviceStateTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = new(GPUDataProvenance)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDeviceStatus.
@@ -241,6 +326,72 @@ func (in *GPUMIGTypeCapacity) DeepCopy() *GPUMIGTypeCapacity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUNodeContainerRuntime) DeepCopyInto(out *GPUNodeContainerRuntime) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodeContainerRuntime.
+func (in *GPUNodeContainerRuntime) DeepCopy() *GPUNodeContainerRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUNodeContainerRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUNodeDisplayDevice) DeepCopyInto(out *GPUNodeDisplayDevice) {
+	*out = *in
+	out.PCI = in.PCI
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodeDisplayDevice.
+func (in *GPUNodeDisplayDevice) DeepCopy() *GPUNodeDisplayDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUNodeDisplayDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUNodePlatform) DeepCopyInto(out *GPUNodePlatform) {
+	*out = *in
+	if in.BareMetal != nil {
+		in, out := &in.BareMetal, &out.BareMetal
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecureBoot != nil {
+		in, out := &in.SecureBoot, &out.SecureBoot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IOMMU != nil {
+		in, out := &in.IOMMU, &out.IOMMU
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UnsignedDriverModule != nil {
+		in, out := &in.UnsignedDriverModule, &out.UnsignedDriverModule
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodePlatform.
+func (in *GPUNodePlatform) DeepCopy() *GPUNodePlatform {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUNodePlatform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUNodeState) DeepCopyInto(out *GPUNodeState) {
 	*out = *in
@@ -325,6 +476,40 @@ func (in *GPUNodeStateStatus) DeepCopyInto(out *GPUNodeStateStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = new(GPUDataProvenance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(GPUNodePlatform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisplayDevices != nil {
+		in, out := &in.DisplayDevices, &out.DisplayDevices
+		*out = make([]GPUNodeDisplayDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthReasons != nil {
+		in, out := &in.HealthReasons, &out.HealthReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopologySummary != nil {
+		in, out := &in.TopologySummary, &out.TopologySummary
+		*out = new(GPUNodeTopologySummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(GPUNodeContainerRuntime)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodeStateStatus.
@@ -337,6 +522,27 @@ func (in *GPUNodeStateStatus) DeepCopy() *GPUNodeStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUNodeTopologySummary) DeepCopyInto(out *GPUNodeTopologySummary) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUNodeTopologySummary.
+func (in *GPUNodeTopologySummary) DeepCopy() *GPUNodeTopologySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUNodeTopologySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPool) DeepCopyInto(out *GPUPool) {
 	*out = *in
@@ -364,6 +570,21 @@ func (in *GPUPool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolAdvancedSpec) DeepCopyInto(out *GPUPoolAdvancedSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolAdvancedSpec.
+func (in *GPUPoolAdvancedSpec) DeepCopy() *GPUPoolAdvancedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolAdvancedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolAssignmentSpec) DeepCopyInto(out *GPUPoolAssignmentSpec) {
 	*out = *in
@@ -387,6 +608,20 @@ func (in *GPUPoolAssignmentSpec) DeepCopy() *GPUPoolAssignmentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolCapacityStatus) DeepCopyInto(out *GPUPoolCapacityStatus) {
 	*out = *in
+	if in.PlacementsAvailable != nil {
+		in, out := &in.PlacementsAvailable, &out.PlacementsAvailable
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ByProfile != nil {
+		in, out := &in.ByProfile, &out.ByProfile
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolCapacityStatus.
@@ -399,6 +634,42 @@ func (in *GPUPoolCapacityStatus) DeepCopy() *GPUPoolCapacityStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolComponentOverrides) DeepCopyInto(out *GPUPoolComponentOverrides) {
+	*out = *in
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolComponentOverrides.
+func (in *GPUPoolComponentOverrides) DeepCopy() *GPUPoolComponentOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolComponentOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolDeviceSelector) DeepCopyInto(out *GPUPoolDeviceSelector) {
 	*out = *in
@@ -416,6 +687,21 @@ func (in *GPUPoolDeviceSelector) DeepCopy() *GPUPoolDeviceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolHealthChecksSpec) DeepCopyInto(out *GPUPoolHealthChecksSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolHealthChecksSpec.
+func (in *GPUPoolHealthChecksSpec) DeepCopy() *GPUPoolHealthChecksSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolHealthChecksSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolList) DeepCopyInto(out *GPUPoolList) {
 	*out = *in
@@ -448,6 +734,21 @@ func (in *GPUPoolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolMIGLayoutEntry) DeepCopyInto(out *GPUPoolMIGLayoutEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolMIGLayoutEntry.
+func (in *GPUPoolMIGLayoutEntry) DeepCopy() *GPUPoolMIGLayoutEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolMIGLayoutEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolReference) DeepCopyInto(out *GPUPoolReference) {
 	*out = *in
@@ -463,9 +764,34 @@ func (in *GPUPoolReference) DeepCopy() *GPUPoolReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolRequirements) DeepCopyInto(out *GPUPoolRequirements) {
+	*out = *in
+	if in.RequiredPrecisions != nil {
+		in, out := &in.RequiredPrecisions, &out.RequiredPrecisions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolRequirements.
+func (in *GPUPoolRequirements) DeepCopy() *GPUPoolRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolResourceSpec) DeepCopyInto(out *GPUPoolResourceSpec) {
 	*out = *in
+	if in.MIGLayout != nil {
+		in, out := &in.MIGLayout, &out.MIGLayout
+		*out = make([]GPUPoolMIGLayoutEntry, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaxDevicesPerNode != nil {
 		in, out := &in.MaxDevicesPerNode, &out.MaxDevicesPerNode
 		*out = new(int32)
@@ -541,6 +867,11 @@ func (in *GPUPoolSelectorRules) DeepCopyInto(out *GPUPoolSelectorRules) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConfidentialComputing != nil {
+		in, out := &in.ConfidentialComputing, &out.ConfidentialComputing
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolSelectorRules.
@@ -567,8 +898,38 @@ func (in *GPUPoolSpec) DeepCopyInto(out *GPUPoolSpec) {
 		*out = new(GPUPoolDeviceSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]GPUDeviceReference, len(*in))
+		copy(*out, *in)
+	}
 	in.DeviceAssignment.DeepCopyInto(&out.DeviceAssignment)
 	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	if in.Requirements != nil {
+		in, out := &in.Requirements, &out.Requirements
+		*out = new(GPUPoolRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Advanced != nil {
+		in, out := &in.Advanced, &out.Advanced
+		*out = new(GPUPoolAdvancedSpec)
+		**out = **in
+	}
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = new(GPUPoolHealthChecksSpec)
+		**out = **in
+	}
+	if in.DevicePlugin != nil {
+		in, out := &in.DevicePlugin, &out.DevicePlugin
+		*out = new(GPUPoolComponentOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MIGManager != nil {
+		in, out := &in.MIGManager, &out.MIGManager
+		*out = new(GPUPoolComponentOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolSpec.
@@ -584,7 +945,7 @@ func (in *GPUPoolSpec) DeepCopy() *GPUPoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolStatus) DeepCopyInto(out *GPUPoolStatus) {
 	*out = *in
-	out.Capacity = in.Capacity
+	in.Capacity.DeepCopyInto(&out.Capacity)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -592,6 +953,11 @@ func (in *GPUPoolStatus) DeepCopyInto(out *GPUPoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Utilization != nil {
+		in, out := &in.Utilization, &out.Utilization
+		*out = new(GPUPoolUtilizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolStatus.
@@ -604,6 +970,42 @@ func (in *GPUPoolStatus) DeepCopy() *GPUPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolUtilizationStatus) DeepCopyInto(out *GPUPoolUtilizationStatus) {
+	*out = *in
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]GPUPoolUtilizationWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolUtilizationStatus.
+func (in *GPUPoolUtilizationStatus) DeepCopy() *GPUPoolUtilizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolUtilizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUPoolUtilizationWindow) DeepCopyInto(out *GPUPoolUtilizationWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUPoolUtilizationWindow.
+func (in *GPUPoolUtilizationWindow) DeepCopy() *GPUPoolUtilizationWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUPoolUtilizationWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GPUPoolTaintSpec) DeepCopyInto(out *GPUPoolTaintSpec) {
 	*out = *in
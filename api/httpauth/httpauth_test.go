@@ -0,0 +1,49 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/httpauth"
+)
+
+func TestBearerToken(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "missing", header: "", want: ""},
+		{name: "bearer", header: "Bearer abc", want: "abc"},
+		{name: "case-insensitive scheme", header: "bearer abc", want: "abc"},
+		{name: "surrounding spaces", header: "Bearer  abc ", want: "abc"},
+		{name: "basic", header: "Basic abc", want: ""},
+		{name: "scheme only", header: "Bearer", want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			if got := httpauth.BearerToken(req); got != tc.want {
+				t.Fatalf("BearerToken(%q) = %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package listenaddr validates the listen addresses of the module binaries. A host may be empty (every address of
+// both families), a hostname, an IPv4 literal or a bracketed IPv6 literal, so no default depends on the IP family
+// of the cluster.
+package listenaddr
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LoopbackIPv4 is the loopback literal of IPv4 and dual-stack pods.
+	LoopbackIPv4 = "127.0.0.1"
+	// LoopbackIPv6 is the loopback literal of IPv6-only pods.
+	LoopbackIPv6 = "::1"
+)
+
+// Parse checks a host:port listen address and returns it normalised, with an IPv6 host always bracketed.
+// The port must be numeric; named ports are not resolved.
+func Parse(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return "", fmt.Errorf("listen address is empty")
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		if strings.Count(addr, ":") > 1 && !strings.HasPrefix(addr, "[") {
+			return "", fmt.Errorf("invalid listen address %q: IPv6 hosts must be bracketed, e.g. [::1]:8080", addr)
+		}
+		return "", fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return "", fmt.Errorf("invalid listen address %q: port %q must be a number between 0 and 65535", addr, port)
+	}
+	if err := validateHost(host); err != nil {
+		return "", fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// Join builds host:port, bracketing an IPv6 host. Brackets already around host are accepted.
+func Join(host, port string) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(host), "["), "]")
+	return net.JoinHostPort(host, strings.TrimSpace(port))
+}
+
+// Loopback returns the loopback literal matching the family of podIP: ::1 for an IPv6 pod IP, 127.0.0.1 for
+// an IPv4 or unknown one.
+func Loopback(podIP string) string {
+	if ip, err := netip.ParseAddr(strings.TrimSpace(podIP)); err == nil && ip.Is6() && !ip.Is4In6() {
+		return LoopbackIPv6
+	}
+	return LoopbackIPv4
+}
+
+func validateHost(host string) error {
+	if host == "" {
+		return nil
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return nil
+	}
+	if len(host) > 253 {
+		return fmt.Errorf("host %q is longer than 253 characters", host)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if !validLabel(label) {
+			return fmt.Errorf("host %q is neither an IP address nor a valid hostname", host)
+		}
+	}
+	return nil
+}
+
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listenaddr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name    string
+		addr    string
+		want    string
+		wantErr string
+	}{
+		{name: "all families", addr: ":8081", want: ":8081"},
+		{name: "ipv4", addr: "0.0.0.0:8081", want: "0.0.0.0:8081"},
+		{name: "ipv4 loopback", addr: " 127.0.0.1:9090 ", want: "127.0.0.1:9090"},
+		{name: "ipv6", addr: "[::]:8081", want: "[::]:8081"},
+		{name: "ipv6 loopback", addr: "[::1]:9090", want: "[::1]:9090"},
+		{name: "ipv6 zone", addr: "[fe80::1%eth0]:80", want: "[fe80::1%eth0]:80"},
+		{name: "hostname", addr: "localhost:8080", want: "localhost:8080"},
+		{name: "fqdn", addr: "gpu-controller.d8-gpu.svc.:443", want: "gpu-controller.d8-gpu.svc.:443"},
+		{name: "empty", addr: "", wantErr: "listen address is empty"},
+		{name: "no port", addr: "127.0.0.1", wantErr: "missing port"},
+		{name: "unbracketed ipv6", addr: "::1:8080", wantErr: "IPv6 hosts must be bracketed"},
+		{name: "named port", addr: ":http", wantErr: "must be a number"},
+		{name: "port out of range", addr: ":65536", wantErr: "must be a number"},
+		{name: "bad hostname", addr: "bad_host:80", wantErr: "neither an IP address nor a valid hostname"},
+		{name: "bad label", addr: "-host:80", wantErr: "neither an IP address nor a valid hostname"},
+		{name: "too long", addr: strings.Repeat("a.", 127) + "ab:80", wantErr: "longer than 253"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := listenaddr.Parse(tc.addr)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	cases := map[string][2]string{
+		"127.0.0.1:23915": {"127.0.0.1", "23915"},
+		"[::1]:23915":     {"::1", "23915"},
+		"[::]:9090":       {"[::]", "9090"},
+		":9090":           {"", "9090"},
+		"localhost:80":    {"localhost", "80"},
+	}
+	for want, in := range cases {
+		if got := listenaddr.Join(in[0], in[1]); got != want {
+			t.Fatalf("Join(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	cases := map[string]string{
+		"":                 listenaddr.LoopbackIPv4,
+		"10.0.0.12":        listenaddr.LoopbackIPv4,
+		"::ffff:10.0.0.12": listenaddr.LoopbackIPv4,
+		"fd00::12":         listenaddr.LoopbackIPv6,
+		"garbage":          listenaddr.LoopbackIPv4,
+	}
+	for podIP, want := range cases {
+		if got := listenaddr.Loopback(podIP); got != want {
+			t.Fatalf("Loopback(%q) = %q, want %q", podIP, got, want)
+		}
+	}
+}
@@ -23,15 +23,22 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+
 	"gfd-extender/internal/server"
+	"gfd-extender/pkg/platform"
 )
 
 const (
-	defaultListenAddr       = "0.0.0.0:2376"
-	defaultPath             = "/api/v1/detect/gpu"
+	defaultListenAddr       = ":2376"
+	defaultPath             = detection.PathV1
 	defaultShutdownTimeout  = 5 * time.Second
 	defaultCollectorTimeout = time.Second
 	defaultLogLevel         = "info"
+
+	authModeTokenReview = "TokenReview"
+	authModeNone        = "None"
 )
 
 type configLoader func(interface{}) error
@@ -55,10 +62,19 @@ type config struct {
 	Timeout         time.Duration `env:"GFD_EXTENDER_TIMEOUT"`
 	ShutdownTimeout time.Duration `env:"GFD_EXTENDER_SHUTDOWN_TIMEOUT"`
 	LogLevel        string        `env:"GFD_EXTENDER_LOG_LEVEL" env-default:"info"`
+	AuthMode        string        `env:"GFD_EXTENDER_AUTH_MODE" env-default:"TokenReview"`
+	AllowedUsers    []string      `env:"GFD_EXTENDER_ALLOWED_USERS"`
+	OSReleasePath   string        `env:"GFD_EXTENDER_OS_RELEASE_PATH"`
+	SysRoot         string        `env:"GFD_EXTENDER_SYS_ROOT"`
+	ProcRoot        string        `env:"GFD_EXTENDER_PROC_ROOT"`
 }
 
 var retryInterval = 30 * time.Second
 
+var newAuthenticator = func() (server.Authenticator, error) {
+	return server.NewInClusterTokenReviewAuthenticator()
+}
+
 func loadConfig(loader configLoader) (config, error) {
 	cfg := config{
 		ListenAddr:      defaultListenAddr,
@@ -66,6 +82,7 @@ func loadConfig(loader configLoader) (config, error) {
 		Timeout:         defaultCollectorTimeout,
 		ShutdownTimeout: defaultShutdownTimeout,
 		LogLevel:        defaultLogLevel,
+		AuthMode:        authModeTokenReview,
 	}
 	if loader == nil {
 		loader = func(interface{}) error { return nil }
@@ -76,6 +93,11 @@ func loadConfig(loader configLoader) (config, error) {
 	if cfg.ListenAddr == "" {
 		return config{}, errors.New("listen address must be set")
 	}
+	addr, err := listenaddr.Parse(cfg.ListenAddr)
+	if err != nil {
+		return config{}, fmt.Errorf("GFD_EXTENDER_ADDR: %w", err)
+	}
+	cfg.ListenAddr = addr
 	if cfg.Path == "" {
 		return config{}, errors.New("HTTP path must be set")
 	}
@@ -88,6 +110,13 @@ func loadConfig(loader configLoader) (config, error) {
 	if cfg.LogLevel == "" {
 		cfg.LogLevel = defaultLogLevel
 	}
+	switch cfg.AuthMode {
+	case "":
+		cfg.AuthMode = authModeTokenReview
+	case authModeTokenReview, authModeNone:
+	default:
+		return config{}, fmt.Errorf("unsupported auth mode: %s", cfg.AuthMode)
+	}
 	return cfg, nil
 }
 
@@ -134,11 +163,29 @@ func run(ctx context.Context, log *slog.Logger, cfg config, detectorFn detectorF
 		}()
 	}
 
-	srv := serverFn(server.Config{
+	srvCfg := server.Config{
 		ListenAddr:      cfg.ListenAddr,
 		Path:            cfg.Path,
 		ShutdownTimeout: cfg.ShutdownTimeout,
-	}, swap, log)
+		AllowedUsers:    cfg.AllowedUsers,
+		Platform: platform.NewCollector(platform.Config{
+			OSReleasePath: cfg.OSReleasePath,
+			SysRoot:       cfg.SysRoot,
+			ProcRoot:      cfg.ProcRoot,
+		}),
+	}
+	if cfg.AuthMode == authModeTokenReview {
+		auth, err := newAuthenticator()
+		if err != nil {
+			_ = swap.Close()
+			return fmt.Errorf("init authenticator: %w", err)
+		}
+		srvCfg.Authenticator = auth
+	} else {
+		log.Warn("detect endpoint is served without authentication")
+	}
+
+	srv := serverFn(srvCfg, swap, log)
 	if srv == nil {
 		return errors.New("server factory returned nil")
 	}
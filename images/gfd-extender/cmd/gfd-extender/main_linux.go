@@ -20,6 +20,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -74,6 +75,26 @@ func readEnvConfig(target interface{}) error {
 	if v := os.Getenv("GFD_EXTENDER_LOG_LEVEL"); v != "" {
 		cfg.LogLevel = v
 	}
+	if v := os.Getenv("GFD_EXTENDER_AUTH_MODE"); v != "" {
+		cfg.AuthMode = v
+	}
+	if v := os.Getenv("GFD_EXTENDER_ALLOWED_USERS"); v != "" {
+		cfg.AllowedUsers = nil
+		for _, user := range strings.Split(v, ",") {
+			if user = strings.TrimSpace(user); user != "" {
+				cfg.AllowedUsers = append(cfg.AllowedUsers, user)
+			}
+		}
+	}
+	if v := os.Getenv("GFD_EXTENDER_OS_RELEASE_PATH"); v != "" {
+		cfg.OSReleasePath = v
+	}
+	if v := os.Getenv("GFD_EXTENDER_SYS_ROOT"); v != "" {
+		cfg.SysRoot = v
+	}
+	if v := os.Getenv("GFD_EXTENDER_PROC_ROOT"); v != "" {
+		cfg.ProcRoot = v
+	}
 	if v := os.Getenv("GFD_EXTENDER_TIMEOUT"); v != "" {
 		dur, err := time.ParseDuration(v)
 		if err != nil {
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -76,6 +77,15 @@ func TestLoadConfigErrors(t *testing.T) {
 		t.Fatalf("expected error for empty listen addr")
 	}
 
+	_, err = loadConfig(func(target interface{}) error {
+		out := target.(*config)
+		out.ListenAddr = "fd00::1:2376"
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "GFD_EXTENDER_ADDR") {
+		t.Fatalf("expected error for unbracketed IPv6 listen addr, got %v", err)
+	}
+
 	_, err = loadConfig(func(target interface{}) error {
 		out := target.(*config)
 		out.Path = ""
@@ -413,3 +423,44 @@ func TestRunRetryKeepsFailing(t *testing.T) {
 func discardLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 }
+
+func TestLoadConfigAuthMode(t *testing.T) {
+	cfg, err := loadConfig(nil)
+	if err != nil || cfg.AuthMode != authModeTokenReview {
+		t.Fatalf("expected TokenReview by default, got %q, %v", cfg.AuthMode, err)
+	}
+	if _, err := loadConfig(func(target interface{}) error {
+		target.(*config).AuthMode = "Basic"
+		return nil
+	}); err == nil {
+		t.Fatalf("expected error for unsupported auth mode")
+	}
+}
+
+func TestRunWiresAuthenticator(t *testing.T) {
+	orig := newAuthenticator
+	defer func() { newAuthenticator = orig }()
+
+	var got server.Config
+	newAuthenticator = func() (server.Authenticator, error) { return nil, errors.New("no apiserver") }
+	cfg := config{ListenAddr: "127.0.0.1:0", Path: "/detect", AuthMode: authModeTokenReview}
+	err := run(context.Background(), discardLogger(), cfg,
+		func(time.Duration) (closableDetector, error) { return &fakeDetector{}, nil },
+		func(c server.Config, _ server.Detector, _ *slog.Logger) serverRunner { got = c; return &fakeServer{} },
+	)
+	if err == nil {
+		t.Fatalf("expected authenticator init error")
+	}
+
+	cfg.AuthMode = authModeNone
+	cfg.AllowedUsers = []string{"system:serviceaccount:ns:controller"}
+	if err := run(context.Background(), discardLogger(), cfg,
+		func(time.Duration) (closableDetector, error) { return &fakeDetector{}, nil },
+		func(c server.Config, _ server.Detector, _ *slog.Logger) serverRunner { got = c; return &fakeServer{} },
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Authenticator != nil || len(got.AllowedUsers) != 1 {
+		t.Fatalf("unexpected server config in None mode: %+v", got)
+	}
+}
@@ -4,6 +4,7 @@ go 1.24.6
 
 require (
 	github.com/NVIDIA/go-nvml v0.13.0-1
+	github.com/aleksandr-podmoskovniy/gpu-control-plane/api v0.0.0
 	github.com/prometheus/client_golang v1.23.0
 )
 
@@ -18,3 +19,5 @@ require (
 	golang.org/x/sys v0.34.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
 )
+
+replace github.com/aleksandr-podmoskovniy/gpu-control-plane/api => ../../api
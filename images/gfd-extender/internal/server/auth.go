@@ -0,0 +1,236 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/httpauth"
+)
+
+const (
+	serviceAccountDir     = "/var/run/secrets/kubernetes.io/serviceaccount"
+	tokenReviewPath       = "/apis/authentication.k8s.io/v1/tokenreviews"
+	defaultTokenReviewTTL = time.Minute
+)
+
+// Authenticator resolves a bearer token to the name of the authenticated user.
+// An empty name with a nil error means the token was rejected.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+type tokenReview struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Spec       tokenReviewSpec   `json:"spec"`
+	Status     tokenReviewStatus `json:"status,omitempty"`
+}
+
+type tokenReviewSpec struct {
+	Token     string   `json:"token"`
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool `json:"authenticated"`
+	User          struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Audiences []string `json:"audiences,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+type cachedReview struct {
+	user    string
+	expires time.Time
+}
+
+// TokenReviewAuthenticator validates tokens through the Kubernetes TokenReview API.
+// Only tokens issued for the audience are accepted, and positive answers are cached briefly so
+// periodic scrapes do not hit the apiserver every time.
+type TokenReviewAuthenticator struct {
+	endpoint  string
+	tokenFile string
+	audience  string
+	client    *http.Client
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]cachedReview
+}
+
+// NewInClusterTokenReviewAuthenticator builds an authenticator that talks to the apiserver
+// with the pod's own service account.
+func NewInClusterTokenReviewAuthenticator() (*TokenReviewAuthenticator, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set")
+	}
+	caData, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("read service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, errors.New("service account CA contains no certificates")
+	}
+
+	client := &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+	}
+	return newTokenReviewAuthenticator("https://"+net.JoinHostPort(host, port), serviceAccountDir+"/token", client), nil
+}
+
+func newTokenReviewAuthenticator(apiServer, tokenFile string, client *http.Client) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{
+		endpoint:  strings.TrimSuffix(apiServer, "/") + tokenReviewPath,
+		tokenFile: tokenFile,
+		audience:  detection.TokenAudience,
+		client:    client,
+		ttl:       defaultTokenReviewTTL,
+		now:       time.Now,
+		cache:     make(map[[sha256.Size]byte]cachedReview),
+	}
+}
+
+// Authenticate implements Authenticator.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	key := sha256.Sum256([]byte(token))
+	now := a.now()
+
+	a.mu.Lock()
+	entry, ok := a.cache[key]
+	if ok && now.After(entry.expires) {
+		delete(a.cache, key)
+		ok = false
+	}
+	a.mu.Unlock()
+	if ok {
+		return entry.user, nil
+	}
+
+	// The token is re-read on every review because kubelet rotates projected tokens.
+	own, err := os.ReadFile(a.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("read service account token: %w", err)
+	}
+
+	body, err := json.Marshal(tokenReview{
+		APIVersion: "authentication.k8s.io/v1",
+		Kind:       "TokenReview",
+		Spec:       tokenReviewSpec{Token: token, Audiences: []string{a.audience}},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(own)))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("create TokenReview: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("create TokenReview: unexpected status %d", resp.StatusCode)
+	}
+
+	var review tokenReview
+	if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+		return "", fmt.Errorf("decode TokenReview: %w", err)
+	}
+	if !review.Status.Authenticated || review.Status.User.Username == "" {
+		return "", nil
+	}
+	// The apiserver echoes the requested audiences the token is valid for; an apiserver that ignores
+	// spec.audiences echoes nothing, and the token must not pass as audience-bound then.
+	if !slices.Contains(review.Status.Audiences, a.audience) {
+		return "", nil
+	}
+
+	a.mu.Lock()
+	a.cache[key] = cachedReview{user: review.Status.User.Username, expires: now.Add(a.ttl)}
+	a.mu.Unlock()
+	return review.Status.User.Username, nil
+}
+
+// requireAuth rejects requests without a valid token from one of the allowed users.
+// Without an authenticator the endpoint stays open, which is the migration mode.
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	if s.cfg.Authenticator == nil {
+		return next
+	}
+	allowed := make(map[string]struct{}, len(s.cfg.AllowedUsers))
+	for _, user := range s.cfg.AllowedUsers {
+		allowed[user] = struct{}{}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := httpauth.BearerToken(r)
+		if token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			detectRequests.WithLabelValues("unauthorized").Inc()
+			return
+		}
+		user, err := s.cfg.Authenticator.Authenticate(r.Context(), token)
+		if err != nil {
+			s.logger.Error("failed to authenticate request",
+				slog.String("remote", r.RemoteAddr),
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "authentication failed", http.StatusInternalServerError)
+			detectRequests.WithLabelValues("error").Inc()
+			return
+		}
+		if user == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			detectRequests.WithLabelValues("unauthorized").Inc()
+			return
+		}
+		if _, ok := allowed[user]; len(allowed) > 0 && !ok {
+			s.logger.Warn("rejected detect request from unexpected user",
+				slog.String("remote", r.RemoteAddr),
+				slog.String("user", user),
+			)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			detectRequests.WithLabelValues("forbidden").Inc()
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
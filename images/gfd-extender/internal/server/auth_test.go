@@ -0,0 +1,148 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+)
+
+type staticAuthenticator map[string]string
+
+func (a staticAuthenticator) Authenticate(_ context.Context, token string) (string, error) {
+	if token == "broken" {
+		return "", errors.New("apiserver unavailable")
+	}
+	return a[token], nil
+}
+
+func newAuthServer(users ...string) *Server {
+	return New(Config{
+		ListenAddr:    "127.0.0.1:0",
+		Path:          "/detect",
+		Authenticator: staticAuthenticator{"good": "system:serviceaccount:d8-gpu-control-plane:controller", "other": "system:serviceaccount:default:intruder"},
+		AllowedUsers:  users,
+	}, fakeDetector{}, slogDiscardLogger())
+}
+
+func serveWithToken(srv *Server, token string) int {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/detect", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	srv.requireAuth(ok).ServeHTTP(rr, req)
+	return rr.Code
+}
+
+func TestRequireAuthAcceptsAllowedToken(t *testing.T) {
+	srv := newAuthServer("system:serviceaccount:d8-gpu-control-plane:controller")
+	if code := serveWithToken(srv, "good"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+}
+
+func TestRequireAuthRejectsTokens(t *testing.T) {
+	srv := newAuthServer("system:serviceaccount:d8-gpu-control-plane:controller")
+	cases := map[string]int{
+		"":       http.StatusUnauthorized,
+		"forged": http.StatusUnauthorized,
+		"other":  http.StatusForbidden,
+		"broken": http.StatusInternalServerError,
+	}
+	for token, want := range cases {
+		if code := serveWithToken(srv, token); code != want {
+			t.Fatalf("token %q: expected %d, got %d", token, want, code)
+		}
+	}
+}
+
+func TestRequireAuthDisabled(t *testing.T) {
+	srv := newTestServer(fakeDetector{})
+	if code := serveWithToken(srv, ""); code != http.StatusOK {
+		t.Fatalf("expected open endpoint without authenticator, got %d", code)
+	}
+}
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("own-token\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+
+	reviews := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reviews++
+		if r.URL.Path != tokenReviewPath || r.Header.Get("Authorization") != "Bearer own-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var review tokenReview
+		_ = json.NewDecoder(r.Body).Decode(&review)
+		if !slices.Equal(review.Spec.Audiences, []string{detection.TokenAudience}) {
+			t.Errorf("unexpected audiences in review: %v", review.Spec.Audiences)
+		}
+		switch review.Spec.Token {
+		case "valid":
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:ns:controller"
+			review.Status.Audiences = review.Spec.Audiences
+		case "api-audience":
+			// A token issued for the apiserver: it authenticates, but not for the requested audience.
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:ns:controller"
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	defer api.Close()
+
+	auth := newTokenReviewAuthenticator(api.URL, tokenFile, api.Client())
+
+	for i := 0; i < 2; i++ {
+		user, err := auth.Authenticate(context.Background(), "valid")
+		if err != nil || user != "system:serviceaccount:ns:controller" {
+			t.Fatalf("expected valid token to authenticate, got %q, %v", user, err)
+		}
+	}
+	if reviews != 1 {
+		t.Fatalf("expected positive review to be cached, got %d reviews", reviews)
+	}
+
+	user, err := auth.Authenticate(context.Background(), "invalid")
+	if err != nil || user != "" {
+		t.Fatalf("expected invalid token to be rejected, got %q, %v", user, err)
+	}
+
+	user, err = auth.Authenticate(context.Background(), "api-audience")
+	if err != nil || user != "" {
+		t.Fatalf("expected token for another audience to be rejected, got %q, %v", user, err)
+	}
+
+	auth.tokenFile = filepath.Join(t.TempDir(), "missing")
+	if _, err := auth.Authenticate(context.Background(), "another"); err == nil {
+		t.Fatalf("expected error without own service account token")
+	}
+}
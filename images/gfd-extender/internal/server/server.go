@@ -25,6 +25,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+
+	"gfd-extender/internal/version"
 	"gfd-extender/pkg/detect"
 )
 
@@ -35,6 +38,11 @@ type Detector interface {
 	DetectGPU(ctx context.Context) ([]detect.Info, error)
 }
 
+// PlatformCollector reports the host facts served next to the v2 detections.
+type PlatformCollector interface {
+	Collect() *detection.Platform
+}
+
 type httpServer interface {
 	ListenAndServe() error
 	Shutdown(context.Context) error
@@ -45,6 +53,12 @@ type Config struct {
 	ListenAddr      string
 	Path            string
 	ShutdownTimeout time.Duration
+	// Authenticator guards the detect endpoint; nil leaves it unauthenticated.
+	Authenticator Authenticator
+	// AllowedUsers restricts authenticated callers; empty accepts any valid token.
+	AllowedUsers []string
+	// Platform adds host facts to the v2 envelope; nil leaves them out.
+	Platform PlatformCollector
 }
 
 // Server exposes a read-only API for gpu-control-plane controller.
@@ -65,13 +79,16 @@ func New(cfg Config, detector Detector, logger *slog.Logger) *Server {
 		timeout = defaultShutdownTimeout
 	}
 	registry := prometheus.NewRegistry()
-	registry.MustRegister(detectRequests, detectWarnings, detectDuration)
+	registry.MustRegister(detectRequests, detectWarnings, detectDuration, version.NewBuildInfoCollector())
 
 	return &Server{
 		cfg: Config{
 			ListenAddr:      cfg.ListenAddr,
 			Path:            cfg.Path,
 			ShutdownTimeout: timeout,
+			Authenticator:   cfg.Authenticator,
+			AllowedUsers:    cfg.AllowedUsers,
+			Platform:        cfg.Platform,
 		},
 		detector: detector,
 		logger:   logger,
@@ -89,7 +106,10 @@ func New(cfg Config, detector Detector, logger *slog.Logger) *Server {
 // Run blocks until the context is cancelled or the HTTP server fails.
 func (s *Server) Run(ctx context.Context) error {
 	mux := http.NewServeMux()
-	mux.HandleFunc(s.cfg.Path, s.handleDetect)
+	mux.Handle(s.cfg.Path, s.requireAuth(http.HandlerFunc(s.handleDetect)))
+	if s.cfg.Path != detection.PathV2 {
+		mux.Handle(detection.PathV2, s.requireAuth(http.HandlerFunc(s.handleDetectV2)))
+	}
 	mux.Handle("/metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
 	s.httpSrv = s.factory(s.cfg.ListenAddr, s.wrapMiddleware(mux))
 
@@ -99,6 +119,7 @@ func (s *Server) Run(ctx context.Context) error {
 		s.logger.Info("gfd-extender server started",
 			slog.String("addr", s.cfg.ListenAddr),
 			slog.String("path", s.cfg.Path),
+			slog.String("pathV2", detection.PathV2),
 		)
 		errCh <- s.httpSrv.ListenAndServe()
 	}()
@@ -114,16 +135,52 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 }
 
+// handleDetect serves the unversioned v1 array. Devices that failed to initialise are left out,
+// as v1 consumers have no way to tell them apart from healthy ones.
 func (s *Server) handleDetect(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	infos, ok := s.detect(w, r)
+	if !ok {
+		return
+	}
+	devices := make([]detect.Info, 0, len(infos))
+	for _, info := range infos {
+		if info.InitError == "" {
+			devices = append(devices, info)
+		}
+	}
+	s.respond(w, r, start, devices, len(devices))
+}
+
+// handleDetectV2 serves the versioned envelope including devices that failed to initialise.
+func (s *Server) handleDetectV2(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	infos, ok := s.detect(w, r)
+	if !ok {
+		return
+	}
+	if infos == nil {
+		infos = []detect.Info{}
+	}
+	resp := detection.Response{
+		SchemaVersion: detection.SchemaVersion,
+		GeneratedAt:   start.UTC(),
+		Devices:       infos,
+	}
+	if s.cfg.Platform != nil {
+		resp.Platform = s.cfg.Platform.Collect()
+	}
+	s.respond(w, r, start, resp, len(infos))
+}
+
+func (s *Server) detect(w http.ResponseWriter, r *http.Request) ([]detect.Info, bool) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		detectRequests.WithLabelValues("method_not_allowed").Inc()
-		return
+		return nil, false
 	}
 
-	ctx := r.Context()
-	start := time.Now()
-	infos, err := s.detector.DetectGPU(ctx)
+	infos, err := s.detector.DetectGPU(r.Context())
 	if err != nil {
 		s.logger.Error("detect request failed",
 			slog.String("remote", r.RemoteAddr),
@@ -133,7 +190,7 @@ func (s *Server) handleDetect(w http.ResponseWriter, r *http.Request) {
 		)
 		http.Error(w, "failed to collect GPU data", http.StatusInternalServerError)
 		detectRequests.WithLabelValues("error").Inc()
-		return
+		return nil, false
 	}
 
 	warnCount := 0
@@ -148,13 +205,24 @@ func (s *Server) handleDetect(w http.ResponseWriter, r *http.Request) {
 				slog.String("warning", warn),
 			)
 		}
+		if info.InitError != "" {
+			warnCount++
+			s.logger.Warn("GPU failed to initialise",
+				slog.Int("index", info.Index),
+				slog.String("path", r.URL.Path),
+				slog.String("error", info.InitError),
+			)
+		}
 	}
 	if warnCount > 0 {
 		detectWarnings.Add(float64(warnCount))
 	}
+	return infos, true
+}
 
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, start time.Time, payload any, devices int) {
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(infos); err != nil {
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
 		s.logger.Error("failed to encode response",
 			slog.String("error", err.Error()),
 		)
@@ -164,8 +232,7 @@ func (s *Server) handleDetect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.logger.Info("detect request served",
-		slog.Int("devices", len(infos)),
-		slog.Int("warnings", warnCount),
+		slog.Int("devices", devices),
 		slog.Duration("duration", time.Since(start)),
 		slog.String("path", r.URL.Path),
 		slog.String("remote", r.RemoteAddr),
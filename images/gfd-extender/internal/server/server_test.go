@@ -28,6 +28,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+
 	"gfd-extender/pkg/detect"
 )
 
@@ -75,6 +77,96 @@ func TestHandleDetectSuccess(t *testing.T) {
 	}
 }
 
+func TestHandleDetectV2Envelope(t *testing.T) {
+	detector := fakeDetector{result: []detect.Info{
+		{Index: 0, UUID: "gpu-0", MIGInstances: []detect.MIGInstance{{UUID: "MIG-0", Profile: "1g.5gb"}}, ECCErrors: &detect.ECCErrors{Corrected: 3}},
+		{Index: 1, InitError: "get handle: Unknown Error"},
+	}}
+	srv := newTestServer(detector)
+	req := httptest.NewRequest(http.MethodGet, detection.PathV2, nil)
+	rr := httptest.NewRecorder()
+
+	srv.handleDetectV2(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var payload detection.Response
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if payload.SchemaVersion != detection.SchemaVersion || payload.GeneratedAt.IsZero() {
+		t.Fatalf("unexpected envelope: %+v", payload)
+	}
+	if len(payload.Devices) != 2 || payload.Devices[1].InitError == "" {
+		t.Fatalf("expected failed device to be reported, got %+v", payload.Devices)
+	}
+	if got := payload.Devices[0]; len(got.MIGInstances) != 1 || got.ECCErrors == nil || got.ECCErrors.Corrected != 3 {
+		t.Fatalf("expected MIG instances and ECC counters, got %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	srv.handleDetect(rr, httptest.NewRequest(http.MethodGet, "/detect", nil))
+	var v1 []detect.Info
+	if err := json.Unmarshal(rr.Body.Bytes(), &v1); err != nil {
+		t.Fatalf("unexpected v1 response: %v", err)
+	}
+	if len(v1) != 1 || v1[0].UUID != "gpu-0" {
+		t.Fatalf("expected v1 to omit devices that failed to initialise, got %+v", v1)
+	}
+}
+
+type fakePlatform struct {
+	platform *detection.Platform
+}
+
+func (f fakePlatform) Collect() *detection.Platform {
+	return f.platform
+}
+
+func TestHandleDetectV2Platform(t *testing.T) {
+	secureBoot := true
+	srv := New(Config{
+		ListenAddr: "127.0.0.1:0",
+		Path:       "/detect",
+		Platform:   fakePlatform{platform: &detection.Platform{KernelRelease: "6.8.0", SecureBoot: &secureBoot}},
+	}, fakeDetector{}, slogDiscardLogger())
+	rr := httptest.NewRecorder()
+
+	srv.handleDetectV2(rr, httptest.NewRequest(http.MethodGet, detection.PathV2, nil))
+
+	var payload detection.Response
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if payload.Platform == nil || payload.Platform.KernelRelease != "6.8.0" || payload.Platform.SecureBoot == nil || !*payload.Platform.SecureBoot {
+		t.Fatalf("expected platform facts in the envelope, got %+v", payload.Platform)
+	}
+
+	rr = httptest.NewRecorder()
+	newTestServer(fakeDetector{}).handleDetectV2(rr, httptest.NewRequest(http.MethodGet, detection.PathV2, nil))
+	payload = detection.Response{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if payload.Platform != nil {
+		t.Fatalf("expected no platform without a collector, got %+v", payload.Platform)
+	}
+}
+
+func TestHandleDetectV2EmptyDevices(t *testing.T) {
+	srv := newTestServer(fakeDetector{})
+	rr := httptest.NewRecorder()
+	srv.handleDetectV2(rr, httptest.NewRequest(http.MethodGet, detection.PathV2, nil))
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if string(raw["devices"]) != "[]" {
+		t.Fatalf("expected empty devices array, got %s", raw["devices"])
+	}
+}
+
 func TestHandleDetectDetectorError(t *testing.T) {
 	detector := fakeDetector{err: errors.New("boom")}
 	srv := newTestServer(detector)
@@ -14,7 +14,10 @@
 
 package detect
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func decodeNVMLPciDeviceID(pciDeviceID uint32) (vendor, device string) {
 	// NVML encodes the combined PCI ID as: (deviceID << 16) | vendorID.
@@ -37,3 +40,23 @@ func migInfoFromGetMigMode(supported bool, migMode int) (capable bool, mode stri
 		return true, fmt.Sprintf("%d", migMode)
 	}
 }
+
+// confidentialComputingFromState maps the NVML system CC state, where 1 means
+// CC_SYSTEM_FEATURE_ENABLED and CC_SYSTEM_DEVTOOLS_MODE_ON respectively.
+func confidentialComputingFromState(ccFeature, devToolsMode uint32) *ConfidentialComputing {
+	return &ConfidentialComputing{
+		Enabled:         ccFeature == 1,
+		DevToolsAllowed: devToolsMode == 1,
+	}
+}
+
+// migProfileFromName extracts the profile from an NVML MIG device name such as "NVIDIA A100-SXM4-40GB MIG 1g.5gb".
+func migProfileFromName(name string) string {
+	fields := strings.Fields(name)
+	for i, field := range fields {
+		if field == "MIG" && i+1 < len(fields) {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
@@ -45,3 +45,29 @@ func TestMIGInfoFromGetMigMode(t *testing.T) {
 		t.Fatalf("expected numeric mig mode, got capable=%v mode=%q", capable, mode)
 	}
 }
+
+func TestMIGProfileFromName(t *testing.T) {
+	cases := map[string]string{
+		"NVIDIA A100-SXM4-40GB MIG 1g.5gb":  "1g.5gb",
+		"NVIDIA H100 80GB HBM3 MIG 3g.40gb": "3g.40gb",
+		"NVIDIA A100-SXM4-40GB":             "",
+		"MIG":                               "",
+	}
+	for name, want := range cases {
+		if got := migProfileFromName(name); got != want {
+			t.Fatalf("migProfileFromName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestConfidentialComputingFromState(t *testing.T) {
+	if cc := confidentialComputingFromState(1, 0); !cc.Enabled || cc.DevToolsAllowed {
+		t.Fatalf("expected CC enabled without devtools, got %+v", cc)
+	}
+	if cc := confidentialComputingFromState(1, 1); !cc.Enabled || !cc.DevToolsAllowed {
+		t.Fatalf("expected CC enabled with devtools, got %+v", cc)
+	}
+	if cc := confidentialComputingFromState(0, 0); cc.Enabled || cc.DevToolsAllowed {
+		t.Fatalf("expected CC disabled, got %+v", cc)
+	}
+}
@@ -46,11 +46,14 @@ func queryNVML() ([]Info, error) {
 	}
 
 	infos := make([]Info, 0, count)
+	cc := queryConfidentialComputing()
 
 	for i := 0; i < count; i++ {
 		dev, ret := nvml.DeviceGetHandleByIndex(i)
 		if ret != nvml.SUCCESS {
-			return infos, fmt.Errorf("get handle %d: %s", i, nvml.ErrorString(ret))
+			// One broken GPU must not hide the others; the v2 API reports it per device.
+			infos = append(infos, Info{Index: i, InitError: fmt.Sprintf("get handle: %s", nvml.ErrorString(ret))})
+			continue
 		}
 
 		info := Info{Index: i}
@@ -67,7 +70,7 @@ func queryNVML() ([]Info, error) {
 			info.MemoryInfo = MemoryInfo{Total: mem.Total, Free: mem.Free, Used: mem.Used}
 			info.MemoryInfoV2 = MemoryInfoV2{Total: mem.Total, Free: mem.Free, Used: mem.Used}
 			if mem.Total > 0 {
-				info.MemoryMiB = int32(mem.Total / (1024 * 1024))
+				info.MemoryMiB = int32(Bytes(mem.Total).MiB())
 			}
 		}
 		if temp, ret := dev.GetTemperature(nvml.TEMPERATURE_GPU); ret == nvml.SUCCESS {
@@ -113,10 +116,24 @@ func queryNVML() ([]Info, error) {
 
 		if migMode, _, ret := dev.GetMigMode(); ret == nvml.SUCCESS {
 			info.MIG.Capable, info.MIG.Mode = migInfoFromGetMigMode(true, migMode)
+			if migMode == nvml.DEVICE_MIG_ENABLE {
+				instances, warnings := queryMIGInstances(dev)
+				info.MIGInstances = instances
+				if len(warnings) > 0 {
+					info.Partial = true
+					info.Warnings = append(info.Warnings, warnings...)
+				}
+			}
 		} else if ret != nvml.ERROR_NOT_SUPPORTED {
 			info.Partial = true
 			info.Warnings = append(info.Warnings, fmt.Sprintf("get mig mode: %s", nvml.ErrorString(ret)))
 		}
+		info.ECCErrors = queryECCErrors(dev)
+		if mode, ret := dev.GetPersistenceMode(); ret == nvml.SUCCESS {
+			enabled := mode == nvml.FEATURE_ENABLED
+			info.PersistenceMode = &enabled
+		}
+		info.ConfidentialComputing = cc
 
 		info.Precision = derivePrecisions(info.ComputeMajor, info.ComputeMinor)
 		infos = append(infos, info)
@@ -125,6 +142,69 @@ func queryNVML() ([]Info, error) {
 	return infos, nil
 }
 
+func queryMIGInstances(dev nvml.Device) ([]MIGInstance, []string) {
+	count, ret := dev.GetMaxMigDeviceCount()
+	if ret != nvml.SUCCESS {
+		return nil, []string{fmt.Sprintf("get max mig device count: %s", nvml.ErrorString(ret))}
+	}
+	var instances []MIGInstance
+	var warnings []string
+	for i := 0; i < count; i++ {
+		mig, ret := dev.GetMigDeviceHandleByIndex(i)
+		if ret == nvml.ERROR_NOT_FOUND {
+			continue
+		}
+		if ret != nvml.SUCCESS {
+			warnings = append(warnings, fmt.Sprintf("get mig device %d: %s", i, nvml.ErrorString(ret)))
+			continue
+		}
+		instance := MIGInstance{}
+		if uuid, ret := mig.GetUUID(); ret == nvml.SUCCESS {
+			instance.UUID = uuid
+		}
+		if name, ret := mig.GetName(); ret == nvml.SUCCESS {
+			instance.Profile = migProfileFromName(name)
+		}
+		if id, ret := mig.GetGpuInstanceId(); ret == nvml.SUCCESS {
+			instance.GPUInstanceID = id
+		}
+		if id, ret := mig.GetComputeInstanceId(); ret == nvml.SUCCESS {
+			instance.ComputeInstanceID = id
+		}
+		if mem, ret := mig.GetMemoryInfo(); ret == nvml.SUCCESS && mem.Total > 0 {
+			instance.MemoryMiB = int32(Bytes(mem.Total).MiB())
+		}
+		instances = append(instances, instance)
+	}
+	return instances, warnings
+}
+
+// queryECCErrors returns nil when ECC is unsupported or disabled, so consumers can tell "no errors" from "no data".
+func queryECCErrors(dev nvml.Device) *ECCErrors {
+	current, _, ret := dev.GetEccMode()
+	if ret != nvml.SUCCESS || current != nvml.FEATURE_ENABLED {
+		return nil
+	}
+	corrected, ret := dev.GetTotalEccErrors(nvml.MEMORY_ERROR_TYPE_CORRECTED, nvml.VOLATILE_ECC)
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	uncorrected, ret := dev.GetTotalEccErrors(nvml.MEMORY_ERROR_TYPE_UNCORRECTED, nvml.VOLATILE_ECC)
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	return &ECCErrors{Corrected: corrected, Uncorrected: uncorrected}
+}
+
+// queryConfidentialComputing returns nil when the driver predates the CC API, so the field is omitted rather than reported as disabled.
+func queryConfidentialComputing() *ConfidentialComputing {
+	state, ret := nvml.SystemGetConfComputeState()
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	return confidentialComputingFromState(state.CcFeature, state.DevToolsMode)
+}
+
 // estimateMemoryBandwidth returns an approximate bandwidth in MiB/s based on memory clock and bus width.
 // Not critical; best-effort only.
 func estimateMemoryBandwidth(dev nvml.Device) (uint64, error) {
@@ -0,0 +1,267 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package platform collects the host facts that influence GPU operation: OS release, kernel, virtualization,
+// Secure Boot, IOMMU and whether the loaded NVIDIA module is signed. Every probe reads plain files under
+// configurable roots and leaves its field empty when the files do not answer the question.
+package platform
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+)
+
+const (
+	DefaultOSReleasePath = "/host-etc/os-release"
+	DefaultSysRoot       = "/sys"
+	DefaultProcRoot      = "/proc"
+
+	// secureBootVar is the EFI global variable holding the Secure Boot state.
+	secureBootVar = "SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"
+	// taintUnsigned is the module taint flag the kernel sets for a module loaded without a valid signature.
+	taintUnsigned = "E"
+)
+
+// Config points the probes at the host files; empty fields use the defaults.
+type Config struct {
+	OSReleasePath string
+	SysRoot       string
+	ProcRoot      string
+}
+
+// Collector gathers the platform facts of the host.
+type Collector struct {
+	cfg Config
+}
+
+// NewCollector returns a Collector reading the host through cfg.
+func NewCollector(cfg Config) *Collector {
+	if cfg.OSReleasePath == "" {
+		cfg.OSReleasePath = DefaultOSReleasePath
+	}
+	if cfg.SysRoot == "" {
+		cfg.SysRoot = DefaultSysRoot
+	}
+	if cfg.ProcRoot == "" {
+		cfg.ProcRoot = DefaultProcRoot
+	}
+	return &Collector{cfg: cfg}
+}
+
+// Collect returns the facts found on the host, or nil when none could be determined.
+func (c *Collector) Collect() *detection.Platform {
+	p := &detection.Platform{
+		OS:                   c.osRelease(),
+		KernelRelease:        readTrim(filepath.Join(c.cfg.ProcRoot, "sys/kernel/osrelease")),
+		SecureBoot:           c.secureBoot(),
+		IOMMU:                c.iommu(),
+		UnsignedDriverModule: c.unsignedDriverModule(),
+	}
+	p.BareMetal, p.Hypervisor = c.virtualization()
+	if *p == (detection.Platform{}) {
+		return nil
+	}
+	return p
+}
+
+func (c *Collector) osRelease() *detection.OSRelease {
+	// The container's own os-release would describe the image, not the host, so there is no fallback.
+	fields, err := parseOSRelease(c.cfg.OSReleasePath)
+	if err != nil {
+		return nil
+	}
+	release := detection.OSRelease{ID: fields["ID"], VersionID: fields["VERSION_ID"], Name: fields["NAME"]}
+	if release == (detection.OSRelease{}) {
+		return nil
+	}
+	return &release
+}
+
+func parseOSRelease(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || key == "" || strings.HasPrefix(key, "#") {
+			continue
+		}
+		fields[key] = strings.Trim(value, `"'`)
+	}
+	return fields, scanner.Err()
+}
+
+// secureBoot reads the SecureBoot EFI variable: four attribute bytes followed by the state byte. A host
+// booted without EFI cannot run Secure Boot; an EFI host whose variables are not exposed stays unknown.
+func (c *Collector) secureBoot() *bool {
+	efi := filepath.Join(c.cfg.SysRoot, "firmware/efi")
+	if _, err := os.Stat(efi); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(filepath.Join(c.cfg.SysRoot, "firmware")); err == nil {
+			return ptr(false)
+		}
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(efi, "efivars", secureBootVar))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if _, statErr := os.Stat(filepath.Join(efi, "efivars")); statErr != nil {
+			return nil
+		}
+		// Firmware without Secure Boot support does not define the variable.
+		return ptr(false)
+	case err != nil || len(data) < 5:
+		return nil
+	}
+	return ptr(data[4] == 1)
+}
+
+// iommu trusts an explicit kernel command line switch first and falls back to the IOMMU devices the kernel
+// registered; without either the state depends on firmware and kernel defaults and stays unknown.
+func (c *Collector) iommu() *bool {
+	if cmdline, err := os.ReadFile(filepath.Join(c.cfg.ProcRoot, "cmdline")); err == nil {
+		if enabled, ok := iommuFromCmdline(string(cmdline)); ok {
+			return ptr(enabled)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(c.cfg.SysRoot, "class/iommu"))
+	if err == nil && len(entries) > 0 {
+		return ptr(true)
+	}
+	return nil
+}
+
+func iommuFromCmdline(cmdline string) (bool, bool) {
+	enabled, found := false, false
+	for _, arg := range strings.Fields(cmdline) {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case "intel_iommu", "amd_iommu":
+			for _, opt := range strings.Split(value, ",") {
+				switch opt {
+				case "on", "force":
+					enabled, found = true, true
+				case "off":
+					enabled, found = false, true
+				}
+			}
+		case "iommu":
+			switch value {
+			case "off":
+				enabled, found = false, true
+			case "pt", "on", "force":
+				enabled, found = true, true
+			}
+		}
+	}
+	return enabled, found
+}
+
+// unsignedDriverModule inspects the taint flags of the loaded nvidia module.
+func (c *Collector) unsignedDriverModule() *bool {
+	data, err := os.ReadFile(filepath.Join(c.cfg.SysRoot, "module/nvidia/taint"))
+	if err != nil {
+		return nil
+	}
+	return ptr(strings.Contains(string(data), taintUnsigned))
+}
+
+// hypervisorMarkers maps lowercase DMI vendor/product fragments to the hypervisor they identify.
+var hypervisorMarkers = []struct{ marker, name string }{
+	{"kvm", "KVM"},
+	{"qemu", "KVM"},
+	{"vmware", "VMware"},
+	{"virtualbox", "VirtualBox"},
+	{"innotek", "VirtualBox"},
+	{"xen", "Xen"},
+	{"microsoft corporation", "Hyper-V"},
+	{"amazon ec2", "Amazon EC2"},
+	{"google compute engine", "Google Compute Engine"},
+	{"openstack", "OpenStack"},
+	{"parallels", "Parallels"},
+	{"bhyve", "bhyve"},
+	{"bochs", "Bochs"},
+}
+
+// virtualization combines the CPU hypervisor flag, DMI strings and /sys/hypervisor. A recognised
+// hypervisor also names it; a DMI identity nothing marks as virtual counts as bare metal.
+func (c *Collector) virtualization() (*bool, string) {
+	dmi := filepath.Join(c.cfg.SysRoot, "class/dmi/id")
+	vendor := readTrim(filepath.Join(dmi, "sys_vendor"))
+	product := readTrim(filepath.Join(dmi, "product_name"))
+	combined := strings.ToLower(vendor + " " + product)
+
+	hypervisor := ""
+	for _, m := range hypervisorMarkers {
+		if strings.Contains(combined, m.marker) {
+			hypervisor = m.name
+			break
+		}
+	}
+	if hypervisor == "" {
+		if kind := readTrim(filepath.Join(c.cfg.SysRoot, "hypervisor/type")); kind == "xen" {
+			hypervisor = "Xen"
+		}
+	}
+	if hypervisor != "" || c.cpuHypervisorFlag() {
+		return ptr(false), hypervisor
+	}
+	if vendor == "" && product == "" {
+		return nil, ""
+	}
+	return ptr(true), ""
+}
+
+func (c *Collector) cpuHypervisorFlag() bool {
+	data, err := os.ReadFile(filepath.Join(c.cfg.ProcRoot, "cpuinfo"))
+	if err != nil {
+		return false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+		for _, flag := range strings.Fields(value) {
+			if flag == "hypervisor" {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func readTrim(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func ptr(v bool) *bool {
+	return &v
+}
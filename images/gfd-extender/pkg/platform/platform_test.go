@@ -0,0 +1,187 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func newTestCollector(t *testing.T, files map[string]string) *Collector {
+	t.Helper()
+	root := t.TempDir()
+	writeTree(t, root, files)
+	return NewCollector(Config{
+		OSReleasePath: filepath.Join(root, "etc/os-release"),
+		SysRoot:       filepath.Join(root, "sys"),
+		ProcRoot:      filepath.Join(root, "proc"),
+	})
+}
+
+func secureBootValue(state byte) string {
+	return string([]byte{0x06, 0x00, 0x00, 0x00, state})
+}
+
+func boolValue(v *bool) string {
+	if v == nil {
+		return "<nil>"
+	}
+	if *v {
+		return "true"
+	}
+	return "false"
+}
+
+func TestCollectBareMetalSecureBoot(t *testing.T) {
+	c := newTestCollector(t, map[string]string{
+		"etc/os-release":                            "NAME=\"Ubuntu\"\nID=ubuntu\nVERSION_ID=\"22.04\"\n# comment\n",
+		"proc/sys/kernel/osrelease":                 "5.15.0-105-generic\n",
+		"proc/cpuinfo":                              "processor\t: 0\nflags\t\t: fpu vme sse2\n",
+		"proc/cmdline":                              "BOOT_IMAGE=/vmlinuz ro intel_iommu=on iommu=pt\n",
+		"sys/class/dmi/id/sys_vendor":               "Dell Inc.\n",
+		"sys/class/dmi/id/product_name":             "PowerEdge R750xa\n",
+		"sys/firmware/efi/efivars/" + secureBootVar: secureBootValue(1),
+		"sys/module/nvidia/taint":                   "POE\n",
+	})
+
+	p := c.Collect()
+	if p == nil {
+		t.Fatal("expected platform facts")
+	}
+	if p.OS == nil || *p.OS != (detection.OSRelease{ID: "ubuntu", VersionID: "22.04", Name: "Ubuntu"}) {
+		t.Fatalf("unexpected os release: %+v", p.OS)
+	}
+	if p.KernelRelease != "5.15.0-105-generic" {
+		t.Fatalf("unexpected kernel release %q", p.KernelRelease)
+	}
+	if boolValue(p.BareMetal) != "true" || p.Hypervisor != "" {
+		t.Fatalf("expected bare metal, got bareMetal=%s hypervisor=%q", boolValue(p.BareMetal), p.Hypervisor)
+	}
+	if boolValue(p.SecureBoot) != "true" || boolValue(p.IOMMU) != "true" || boolValue(p.UnsignedDriverModule) != "true" {
+		t.Fatalf("unexpected secureBoot=%s iommu=%s unsigned=%s", boolValue(p.SecureBoot), boolValue(p.IOMMU), boolValue(p.UnsignedDriverModule))
+	}
+}
+
+func TestCollectKVMGuest(t *testing.T) {
+	c := newTestCollector(t, map[string]string{
+		"proc/cpuinfo":                  "processor\t: 0\nflags\t\t: fpu hypervisor sse2\n",
+		"proc/cmdline":                  "ro quiet\n",
+		"sys/class/dmi/id/sys_vendor":   "QEMU\n",
+		"sys/class/dmi/id/product_name": "Standard PC (Q35 + ICH9, 2009)\n",
+		"sys/class/iommu/dmar0/uevent":  "",
+		"sys/firmware/acpi/tables/DSDT": "",
+		"sys/module/nvidia/taint":       "\n",
+	})
+
+	p := c.Collect()
+	if boolValue(p.BareMetal) != "false" || p.Hypervisor != "KVM" {
+		t.Fatalf("expected KVM guest, got bareMetal=%s hypervisor=%q", boolValue(p.BareMetal), p.Hypervisor)
+	}
+	if boolValue(p.SecureBoot) != "false" {
+		t.Fatalf("expected Secure Boot off on a legacy-boot guest, got %s", boolValue(p.SecureBoot))
+	}
+	if boolValue(p.IOMMU) != "true" {
+		t.Fatalf("expected IOMMU from registered devices, got %s", boolValue(p.IOMMU))
+	}
+	if boolValue(p.UnsignedDriverModule) != "false" {
+		t.Fatalf("expected a signed module, got %s", boolValue(p.UnsignedDriverModule))
+	}
+}
+
+func TestCollectUnidentifiedGuest(t *testing.T) {
+	c := newTestCollector(t, map[string]string{
+		"proc/cpuinfo":                        "flags\t\t: fpu hypervisor\n",
+		"proc/cmdline":                        "ro intel_iommu=off\n",
+		"sys/class/iommu/dmar0/uevent":        "",
+		"sys/firmware/efi/efivars/Boot0000-x": "",
+	})
+
+	p := c.Collect()
+	if boolValue(p.BareMetal) != "false" || p.Hypervisor != "" {
+		t.Fatalf("expected an unnamed VM, got bareMetal=%s hypervisor=%q", boolValue(p.BareMetal), p.Hypervisor)
+	}
+	if boolValue(p.SecureBoot) != "false" {
+		t.Fatalf("expected Secure Boot off without the variable, got %s", boolValue(p.SecureBoot))
+	}
+	if boolValue(p.IOMMU) != "false" {
+		t.Fatalf("expected the command line to win, got %s", boolValue(p.IOMMU))
+	}
+	if p.UnsignedDriverModule != nil {
+		t.Fatalf("expected unknown module state without the nvidia module, got %s", boolValue(p.UnsignedDriverModule))
+	}
+}
+
+func TestCollectLeavesUnknownFieldsEmpty(t *testing.T) {
+	c := newTestCollector(t, map[string]string{
+		"proc/sys/kernel/osrelease": "6.8.0\n",
+		"sys/firmware/efi/systab":   "",
+	})
+
+	p := c.Collect()
+	if p == nil || p.KernelRelease != "6.8.0" {
+		t.Fatalf("expected only the kernel release, got %+v", p)
+	}
+	if p.BareMetal != nil || p.SecureBoot != nil || p.IOMMU != nil || p.UnsignedDriverModule != nil || p.OS != nil {
+		t.Fatalf("expected undetermined fields to stay empty, got %+v", p)
+	}
+}
+
+func TestCollectNothingKnown(t *testing.T) {
+	c := NewCollector(Config{
+		OSReleasePath: filepath.Join(t.TempDir(), "missing"),
+		SysRoot:       filepath.Join(t.TempDir(), "sys"),
+		ProcRoot:      filepath.Join(t.TempDir(), "proc"),
+	})
+	if p := c.Collect(); p != nil {
+		t.Fatalf("expected no host facts, got %+v", p)
+	}
+}
+
+func TestIOMMUFromCmdline(t *testing.T) {
+	cases := []struct {
+		cmdline string
+		enabled bool
+		found   bool
+	}{
+		{cmdline: "ro quiet"},
+		{cmdline: "amd_iommu=on", enabled: true, found: true},
+		{cmdline: "intel_iommu=on,sm_on", enabled: true, found: true},
+		{cmdline: "intel_iommu=on iommu=off", found: true},
+		{cmdline: "iommu=pt", enabled: true, found: true},
+		{cmdline: "iommu=soft"},
+	}
+	for _, tc := range cases {
+		enabled, found := iommuFromCmdline(tc.cmdline)
+		if enabled != tc.enabled || found != tc.found {
+			t.Fatalf("%q: got enabled=%v found=%v", tc.cmdline, enabled, found)
+		}
+	}
+}
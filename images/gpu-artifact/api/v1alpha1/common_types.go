@@ -76,3 +76,13 @@ const (
 	MIGModeNA       MIGModeState = "NotAvailable"
 	MIGModeUnknown  MIGModeState = "Unknown"
 )
+
+// +kubebuilder:validation:Enum=Enabled;Disabled;NotAvailable
+// PersistenceModeState is the current driver persistence mode on a GPU.
+type PersistenceModeState string
+
+const (
+	PersistenceModeEnabled  PersistenceModeState = "Enabled"
+	PersistenceModeDisabled PersistenceModeState = "Disabled"
+	PersistenceModeNA       PersistenceModeState = "NotAvailable"
+)
@@ -71,6 +71,8 @@ type PCIInfo struct {
 	Vendor *PCIVendorInfo `json:"vendor,omitempty"`
 	// Device describes the PCI device.
 	Device *PCIDeviceInfo `json:"device,omitempty"`
+	// NUMANode is the NUMA node the device is attached to, omitted when the platform does not report one.
+	NUMANode *int32 `json:"numaNode,omitempty"`
 }
 
 // PCIClassInfo describes PCI class details.
@@ -187,6 +189,8 @@ type NvidiaCurrentState struct {
 	PowerLimitEnforcedW *int64 `json:"powerLimitEnforcedW,omitempty"`
 	// MIG describes the current MIG mode (when supported).
 	MIG *NvidiaMIGState `json:"mig,omitempty"`
+	// PersistenceMode is the current driver persistence mode.
+	PersistenceMode PersistenceModeState `json:"persistenceMode,omitempty"`
 }
 
 // NvidiaMIGState contains current MIG mode info.
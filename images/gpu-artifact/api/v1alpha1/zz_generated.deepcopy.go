@@ -517,6 +517,11 @@ func (in *PCIInfo) DeepCopyInto(out *PCIInfo) {
 		*out = new(PCIDeviceInfo)
 		**out = **in
 	}
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PCIInfo.
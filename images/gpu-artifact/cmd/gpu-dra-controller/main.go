@@ -20,7 +20,6 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.).
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -31,12 +30,16 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/controller/dra"
 	drawebhook "github.com/aleksandr-podmoskovniy/gpu/pkg/controller/dra/webhook"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/env"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/listenaddr"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/version"
 )
 
 const (
@@ -69,14 +72,15 @@ func main() {
 	var leaderElectionID string
 	var deviceStatusMode string
 
-	logLevel := os.Getenv(logLevelEnv)
-	logOutput := os.Getenv(logOutputEnv)
-	logDebugVerbosity := envIntOrDie(logDebugVerbosityEnv)
-	deviceStatusMode = envOr(draDeviceStatusEnv, "auto")
+	envVars := env.Collect(os.Getenv)
+	logLevel := envVars.String(logLevelEnv, "")
+	logOutput := envVars.String(logOutputEnv, "")
+	logDebugVerbosity := envVars.Int(logDebugVerbosityEnv, 0)
+	deviceStatusMode = envVars.String(draDeviceStatusEnv, "auto")
 
-	flag.StringVar(&metricsAddr, "metrics-bind-address", envOr(metricsBindAddrEnv, ":8080"), "The address the metrics endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", envOr(healthProbeBindAddrEnv, ":8083"), "The address the probe endpoint binds to.")
-	flag.StringVar(&pprofAddr, "pprof-bind-address", envOr(pprofBindAddrEnv, ""), "Enable pprof endpoint when set.")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", envVars.String(metricsBindAddrEnv, ":8080"), "The address the metrics endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", envVars.String(healthProbeBindAddrEnv, ":8083"), "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", envVars.String(pprofBindAddrEnv, ""), "Enable pprof endpoint when set.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false, "Enable leader election for the controller.")
 	flag.StringVar(&leaderElectionID, "leader-election-id", "gpu-dra-controller.deckhouse.io", "Leader election ID.")
 	flag.StringVar(&deviceStatusMode, "dra-device-status", deviceStatusMode, "Enable ResourceClaim device status/binding conditions: auto|true|false.")
@@ -84,12 +88,19 @@ func main() {
 	flag.StringVar(&logOutput, "log-output", logOutput, "Log output.")
 	flag.IntVar(&logDebugVerbosity, "log-debug-verbosity", logDebugVerbosity, "Log debug verbosity.")
 	flag.Parse()
+	if err := envVars.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	metricsAddr = listenaddr.OptionalFlagOrDie("metrics-bind-address", metricsAddr)
+	probeAddr = listenaddr.OptionalFlagOrDie("health-probe-bind-address", probeAddr)
+	pprofAddr = listenaddr.OptionalFlagOrDie("pprof-bind-address", pprofAddr)
 
 	rootLog := logger.NewLogger(logLevel, logOutput, logDebugVerbosity)
 	logger.SetDefaultLogger(rootLog)
 	setupLog := rootLog.With(logger.SlogController("gpu-dra-controller"))
 
-	leaderElectionNS := envOr(podNamespaceEnv, "default")
+	leaderElectionNS := envVars.String(podNamespaceEnv, "default")
 	managerOpts := ctrl.Options{
 		Scheme:                        scheme,
 		Metrics:                       metricsserver.Options{BindAddress: metricsAddr},
@@ -107,6 +118,8 @@ func main() {
 		managerOpts.PprofBindAddress = pprofAddr
 	}
 
+	metrics.Registry.MustRegister(version.NewBuildInfoCollector())
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOpts)
 	if err != nil {
 		setupLog.Error("unable to start manager", logger.SlogErr(err))
@@ -134,29 +147,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting gpu-dra-controller")
+	setupLog.Info("starting gpu-dra-controller", "version", version.Version(), "gitCommit", version.GitCommit())
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error("problem running manager", logger.SlogErr(err))
 		os.Exit(1)
 	}
 }
-
-func envOr(name, fallback string) string {
-	if val := os.Getenv(name); val != "" {
-		return val
-	}
-	return fallback
-}
-
-func envIntOrDie(name string) int {
-	raw := os.Getenv(name)
-	if raw == "" {
-		return 0
-	}
-	val, err := strconv.Atoi(raw)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid %s: %v\n", name, err)
-		os.Exit(1)
-	}
-	return val
-}
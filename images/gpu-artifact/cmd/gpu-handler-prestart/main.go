@@ -24,6 +24,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/env"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/prestart"
 )
 
@@ -39,11 +40,20 @@ func main() {
 		os.Exit(0)
 	}()
 
-	runner := prestart.NewRunner(prestart.Options{
-		DriverRoot: os.Getenv("NVIDIA_DRIVER_ROOT"),
-		Out:        os.Stdout,
-		Err:        os.Stderr,
-	})
+	envVars := env.Collect(os.Getenv)
+	opts := prestart.Options{
+		DriverRoot:  envVars.String("NVIDIA_DRIVER_ROOT", ""),
+		CDISpecPath: envVars.String("CDI_SPEC_PATH", ""),
+		CDIRequired: envVars.Bool("CDI_REQUIRED", false),
+		Out:         os.Stdout,
+		Err:         os.Stderr,
+	}
+	if err := envVars.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	runner := prestart.NewRunner(opts)
 
 	if err := runner.Run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "prestart failed: %v\n", err)
@@ -27,7 +27,6 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,8 +36,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/env"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/listenaddr"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 const (
@@ -63,32 +65,47 @@ func main() {
 	var hostDriverRoot string
 	var cdiRoot string
 	var nvidiaCDIHookPath string
-
-	logLevel := os.Getenv(logLevelEnv)
-	logOutput := os.Getenv(logOutputEnv)
-	logDebugVerbosity := envIntOrDie(logDebugVerbosityEnv)
-	consumableCapacityMode = envOr(draConsumableCapacityEnv, "auto")
-	deviceStatusMode = envOr(draDeviceStatusEnv, "auto")
-	driverRoot = envOr(nvidiaDriverRootEnv, "")
-	hostDriverRoot = envOr(hostDriverRootEnv, "/")
-	cdiRoot = envOr(cdiRootEnv, "/etc/cdi")
-	nvidiaCDIHookPath = envOr(nvidiaCDIHookPathEnv, "")
-
-	flag.StringVar(&probeAddr, "health-probe-bind-address", envOr(healthProbeBindAddrEnv, ":8081"), "The address the probe endpoint binds to.")
+	var devRoot string
+	var createDeviceNodes bool
+	var enforcePersistenceMode bool
+
+	envVars := env.Collect(os.Getenv)
+	logLevel := envVars.String(logLevelEnv, "")
+	logOutput := envVars.String(logOutputEnv, "")
+	logDebugVerbosity := envVars.Int(logDebugVerbosityEnv, 0)
+	consumableCapacityMode = envVars.String(draConsumableCapacityEnv, "auto")
+	deviceStatusMode = envVars.String(draDeviceStatusEnv, "auto")
+	driverRoot = envVars.String(nvidiaDriverRootEnv, "")
+	hostDriverRoot = envVars.String(hostDriverRootEnv, "/")
+	cdiRoot = envVars.String(cdiRootEnv, "/etc/cdi")
+	nvidiaCDIHookPath = envVars.String(nvidiaCDIHookPathEnv, "")
+	coord := nodecoord.SettingsFromEnv(os.Getenv)
+
+	flag.StringVar(&probeAddr, "health-probe-bind-address", envVars.String(healthProbeBindAddrEnv, ":8081"), "The address the probe endpoint binds to.")
 	flag.StringVar(&nodeName, "node-name", "", "Node name (defaults to NODE_NAME env var).")
 	flag.StringVar(&logLevel, "log-level", logLevel, "Log level.")
 	flag.StringVar(&logOutput, "log-output", logOutput, "Log output.")
 	flag.IntVar(&logDebugVerbosity, "log-debug-verbosity", logDebugVerbosity, "Log debug verbosity.")
 	flag.StringVar(&consumableCapacityMode, "dra-consumable-capacity", consumableCapacityMode, "Enable DRA consumable capacity: auto|true|false.")
 	flag.StringVar(&deviceStatusMode, "dra-device-status", deviceStatusMode, "Enable ResourceClaim device status/binding conditions: auto|true|false.")
+	flag.StringVar(&devRoot, "dev-root", "/dev", "Path to the host /dev mount used for device node checks.")
+	flag.BoolVar(&createDeviceNodes, "create-device-nodes", false, "Create missing NVIDIA device nodes via mknod.")
+	flag.BoolVar(&enforcePersistenceMode, "enforce-persistence-mode", false, "Turn persistence mode on for GPUs that run without it.")
+	flag.BoolVar(&coord.CoLocated, "co-located", coord.CoLocated, "Run in the same pod as the other node container and coordinate over a unix socket.")
+	flag.StringVar(&coord.SocketPath, "coordination-socket", coord.SocketPath, "Path to the node coordination unix socket.")
 	flag.Parse()
+	if err := envVars.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	probeAddr = listenaddr.FlagOrDie("health-probe-bind-address", probeAddr)
 
 	rootLog := logger.NewLogger(logLevel, logOutput, logDebugVerbosity)
 	logger.SetDefaultLogger(rootLog)
 	log := rootLog.With(logger.SlogController("gpu-handler"))
 
 	if nodeName == "" {
-		nodeName = os.Getenv("NODE_NAME")
+		nodeName = envVars.String("NODE_NAME", "")
 	}
 	if nodeName == "" {
 		log.Error("node name is required")
@@ -115,12 +132,17 @@ func main() {
 		HostDriverRoot:         hostDriverRoot,
 		CDIRoot:                cdiRoot,
 		NvidiaCDIHookPath:      nvidiaCDIHookPath,
+		DevRoot:                devRoot,
+		CreateDeviceNodes:      createDeviceNodes,
+		EnforcePersistenceMode: enforcePersistenceMode,
+		CoLocated:              coord.CoLocated,
+		SocketPath:             coord.SocketPath,
 	}, log)
 
 	ctx := ctrl.SetupSignalHandler()
 	ctx = logger.ToContext(ctx, slog.Default())
 
-	server := &http.Server{Addr: probeAddr, Handler: healthMux()}
+	server := &http.Server{Addr: probeAddr, Handler: healthMux(agent.Healthy)}
 
 	agentErrCh := make(chan error, 1)
 	go func() {
@@ -156,33 +178,18 @@ func main() {
 	}
 }
 
-func healthMux() http.Handler {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
-	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+// healthMux fails both probes once NVML cannot be re-initialized: readiness takes the node out of
+// rotation and liveness makes the kubelet restart the container with a freshly loaded library.
+func healthMux(healthy func() bool) http.Handler {
+	probe := func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy() {
+			http.Error(w, "NVML re-initialization failed", http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
-	})
-	return mux
-}
-
-func envOr(name, fallback string) string {
-	if val := os.Getenv(name); val != "" {
-		return val
 	}
-	return fallback
-}
-
-func envIntOrDie(name string) int {
-	raw := os.Getenv(name)
-	if raw == "" {
-		return 0
-	}
-	val, err := strconv.Atoi(raw)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid %s: %v\n", name, err)
-		os.Exit(1)
-	}
-	return val
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", probe)
+	mux.HandleFunc("/readyz", probe)
+	return mux
 }
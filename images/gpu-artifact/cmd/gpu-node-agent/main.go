@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,8 +36,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/env"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/listenaddr"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 const (
@@ -43,6 +48,8 @@ const (
 	logLevelEnv            = "LOG_LEVEL"
 	logOutputEnv           = "LOG_OUTPUT"
 	healthProbeBindAddrEnv = "HEALTH_PROBE_BIND_ADDRESS"
+	checkpointPathEnv      = "CHECKPOINT_PATH"
+	checkpointMaxAgeEnv    = "CHECKPOINT_MAX_AGE"
 )
 
 func main() {
@@ -51,27 +58,49 @@ func main() {
 	var sysRoot string
 	var osReleasePath string
 	var pciIDsPaths string
+	var checkpointPath string
+	var checkpointMaxAge time.Duration
+	var prepareCheckpointOwner string
 
-	logLevel := os.Getenv(logLevelEnv)
-	logOutput := os.Getenv(logOutputEnv)
-	logDebugVerbosity := envIntOrDie(logDebugVerbosityEnv)
+	envVars := env.Collect(os.Getenv)
+	logLevel := envVars.String(logLevelEnv, "")
+	logOutput := envVars.String(logOutputEnv, "")
+	logDebugVerbosity := envVars.Int(logDebugVerbosityEnv, 0)
+	coord := nodecoord.SettingsFromEnv(os.Getenv)
 
-	flag.StringVar(&probeAddr, "health-probe-bind-address", envOr(healthProbeBindAddrEnv, ":8081"), "The address the probe endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", envVars.String(healthProbeBindAddrEnv, ":8081"), "The address the probe endpoint binds to.")
 	flag.StringVar(&nodeName, "node-name", "", "Node name (defaults to NODE_NAME env var).")
 	flag.StringVar(&sysRoot, "sysfs-path", "/host-sys", "Path to the host sysfs mount.")
 	flag.StringVar(&osReleasePath, "os-release-path", "/host-etc/os-release", "Path to the host os-release file.")
-	flag.StringVar(&pciIDsPaths, "pci-ids-paths", "/host-usr-share/hwdata/pci.ids,/host-usr-share/misc/pci.ids,/host-usr-share/pci.ids,/host-usr-share/pciids/pci.ids", "Comma-separated list of pci.ids paths.")
+	flag.StringVar(&pciIDsPaths, "pci-ids-paths", "/host-usr-share/hwdata/pci.ids,/host-usr-share/misc/pci.ids,/host-usr-share/pci.ids,/host-usr-share/pciids/pci.ids,/host-usr-share/hwdata/pci.ids.gz,/host-usr-share/misc/pci.ids.gz", "Comma-separated list of pci.ids paths; *.gz files are decompressed on the fly.")
+	flag.StringVar(&checkpointPath, "checkpoint-path", envVars.String(checkpointPathEnv, nodeagent.DefaultCheckpointPath), "File that keeps the last published device set across restarts; empty disables it.")
+	flag.DurationVar(&checkpointMaxAge, "checkpoint-max-age", envVars.Duration(checkpointMaxAgeEnv, nodeagent.DefaultCheckpointMaxAge), "How long after the last publish the checkpoint may replace an empty PCI scan.")
+	flag.StringVar(&prepareCheckpointOwner, "prepare-checkpoint-dir", "", "Create the checkpoint directory owned by UID:GID and exit; used by the init container.")
 	flag.StringVar(&logLevel, "log-level", logLevel, "Log level.")
 	flag.StringVar(&logOutput, "log-output", logOutput, "Log output.")
 	flag.IntVar(&logDebugVerbosity, "log-debug-verbosity", logDebugVerbosity, "Log debug verbosity.")
+	flag.BoolVar(&coord.CoLocated, "co-located", coord.CoLocated, "Run in the same pod as the other node container and coordinate over a unix socket.")
+	flag.StringVar(&coord.SocketPath, "coordination-socket", coord.SocketPath, "Path to the node coordination unix socket.")
 	flag.Parse()
+	if err := envVars.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if prepareCheckpointOwner != "" {
+		if err := prepareCheckpointDir(checkpointPath, prepareCheckpointOwner); err != nil {
+			fmt.Fprintf(os.Stderr, "prepare checkpoint directory: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	probeAddr = listenaddr.FlagOrDie("health-probe-bind-address", probeAddr)
 
 	rootLog := logger.NewLogger(logLevel, logOutput, logDebugVerbosity)
 	logger.SetDefaultLogger(rootLog)
 	log := rootLog.With(logger.SlogController("gpu-node-agent"))
 
 	if nodeName == "" {
-		nodeName = os.Getenv("NODE_NAME")
+		nodeName = envVars.String("NODE_NAME", "")
 	}
 	if nodeName == "" {
 		log.Error("node name is required")
@@ -90,19 +119,24 @@ func main() {
 	}
 
 	agent := nodeagent.New(k8sClient, nodeagent.Config{
-		NodeName:      nodeName,
-		SysRoot:       sysRoot,
-		OSReleasePath: osReleasePath,
-		PCIIDsPaths:   splitComma(pciIDsPaths),
-		KubeConfig:    restConfig,
+		NodeName:         nodeName,
+		SysRoot:          sysRoot,
+		OSReleasePath:    osReleasePath,
+		PCIIDsPaths:      env.SplitCSV(pciIDsPaths),
+		KubeConfig:       restConfig,
+		CoLocated:        coord.CoLocated,
+		SocketPath:       coord.SocketPath,
+		CheckpointPath:   checkpointPath,
+		CheckpointMaxAge: checkpointMaxAge,
 	}, log)
 
 	ctx := ctrl.SetupSignalHandler()
+	rescan := rescanOnSIGHUP(ctx)
 	server := &http.Server{Addr: probeAddr, Handler: healthMux()}
 
 	agentErrCh := make(chan error, 1)
 	go func() {
-		if err := agent.Run(ctx); err != nil {
+		if err := agent.Run(ctx, rescan); err != nil {
 			agentErrCh <- err
 		}
 	}()
@@ -134,6 +168,42 @@ func main() {
 	}
 }
 
+// prepareCheckpointDir hands the checkpoint directory to the UID:GID the agent runs as.
+func prepareCheckpointDir(path, owner string) error {
+	if path == "" {
+		return nil
+	}
+	uidStr, gidStr, ok := strings.Cut(owner, ":")
+	uid, uidErr := strconv.Atoi(uidStr)
+	gid, gidErr := strconv.Atoi(gidStr)
+	if !ok || uidErr != nil || gidErr != nil || uid < 0 || gid < 0 {
+		return fmt.Errorf("owner %q is not UID:GID", owner)
+	}
+	return nodeagent.PrepareCheckpointDir(path, uid, gid)
+}
+
+// rescanOnSIGHUP turns SIGHUP into rescan requests, coalescing signals that arrive before the agent reads them.
+func rescanOnSIGHUP(ctx context.Context) <-chan struct{} {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	rescan := make(chan struct{}, 1)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				select {
+				case rescan <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return rescan
+}
+
 func healthMux() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
@@ -144,39 +214,3 @@ func healthMux() http.Handler {
 	})
 	return mux
 }
-
-func envOr(name, fallback string) string {
-	if val := os.Getenv(name); val != "" {
-		return val
-	}
-	return fallback
-}
-
-func envIntOrDie(name string) int {
-	raw := os.Getenv(name)
-	if raw == "" {
-		return 0
-	}
-	val, err := strconv.Atoi(raw)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid %s: %v\n", name, err)
-		os.Exit(1)
-	}
-	return val
-}
-
-func splitComma(value string) []string {
-	if value == "" {
-		return nil
-	}
-	raw := strings.Split(value, ",")
-	paths := make([]string, 0, len(raw))
-	for _, item := range raw {
-		item = strings.TrimSpace(item)
-		if item == "" {
-			continue
-		}
-		paths = append(paths, item)
-	}
-	return paths
-}
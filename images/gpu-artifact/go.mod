@@ -3,12 +3,16 @@ module github.com/aleksandr-podmoskovniy/gpu
 go 1.25.0
 
 require (
+	github.com/NVIDIA/go-nvlib v0.9.0
 	github.com/NVIDIA/go-nvml v0.13.0-1
+	github.com/NVIDIA/nvidia-container-toolkit v1.18.1
+	github.com/aleksandr-podmoskovniy/gpu-control-plane/api v0.0.0
 	github.com/deckhouse/deckhouse/pkg/log v0.0.0-20250226105106-176cd3afcdd5
 	github.com/go-logr/logr v1.4.3
 	github.com/gogo/protobuf v1.3.2
 	github.com/prometheus/client_golang v1.23.2
 	github.com/prometheus/client_model v0.6.2
+	github.com/sirupsen/logrus v1.9.3
 	golang.org/x/sys v0.38.0
 	google.golang.org/protobuf v1.36.10
 	k8s.io/api v0.34.1
@@ -23,8 +27,6 @@ require (
 require (
 	cel.dev/expr v0.24.0 // indirect
 	github.com/DataDog/gostackparse v0.7.0 // indirect
-	github.com/NVIDIA/go-nvlib v0.9.0 // indirect
-	github.com/NVIDIA/nvidia-container-toolkit v1.18.1 // indirect
 	github.com/antlr4-go/antlr/v4 v4.13.0 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/blang/semver/v4 v4.0.0 // indirect
@@ -57,7 +59,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/prometheus/common v0.66.1 // indirect
 	github.com/prometheus/procfs v0.16.1 // indirect
-	github.com/sirupsen/logrus v1.9.3 // indirect
 	github.com/spf13/cobra v1.10.0 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
 	github.com/stoewer/go-strcase v1.3.0 // indirect
@@ -96,3 +97,5 @@ require (
 	tags.cncf.io/container-device-interface v1.0.2-0.20251114135136-1b24d969689f // indirect
 	tags.cncf.io/container-device-interface/specs-go v1.0.0 // indirect
 )
+
+replace github.com/aleksandr-podmoskovniy/gpu-control-plane/api => ../../api
@@ -79,6 +79,10 @@ func (h *ValidatorHandler) Handle(ctx context.Context, st state.PhysicalGPUState
 		driverReady = driverReady.Status(metav1.ConditionUnknown).
 			Reason(reasonValidatorMissing).
 			Message(res.Message)
+	case res.Ready && deviceNodesMissing(obj):
+		driverReady = driverReady.Status(metav1.ConditionFalse).
+			Reason(reasonDeviceNodesMissing).
+			Message(deviceNodesMessage(obj))
 	case res.Ready:
 		driverReady = driverReady.Status(metav1.ConditionTrue).
 			Reason(reasonValidatorReady).
@@ -16,18 +16,27 @@ limitations under the License.
 
 package handler
 
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+)
+
 type conditionType string
 
 type conditionReason string
 
 const (
-	conditionDriverReady conditionType = "DriverReady"
+	conditionDriverReady      conditionType = "DriverReady"
+	conditionDeviceNodesReady conditionType = "DeviceNodesReady"
 )
 
 const (
-	reasonValidatorReady    conditionReason = "ValidatorReady"
-	reasonValidatorNotReady conditionReason = "ValidatorNotReady"
-	reasonValidatorMissing  conditionReason = "ValidatorMissing"
+	reasonValidatorReady     conditionReason = "ValidatorReady"
+	reasonValidatorNotReady  conditionReason = "ValidatorNotReady"
+	reasonValidatorMissing   conditionReason = "ValidatorMissing"
+	reasonDeviceNodesMissing conditionReason = "DeviceNodesMissing"
 )
 
 func (ct conditionType) String() string {
@@ -37,3 +46,18 @@ func (ct conditionType) String() string {
 func (cr conditionReason) String() string {
 	return string(cr)
 }
+
+// deviceNodesMissing reports whether gpu-handler found device nodes missing on the node.
+// Unknown is not treated as missing: the validator stays authoritative when the check cannot run.
+func deviceNodesMissing(obj *gpuv1alpha1.PhysicalGPU) bool {
+	cond := meta.FindStatusCondition(obj.Status.Conditions, conditionDeviceNodesReady.String())
+	return cond != nil && cond.Status == metav1.ConditionFalse
+}
+
+func deviceNodesMessage(obj *gpuv1alpha1.PhysicalGPU) string {
+	cond := meta.FindStatusCondition(obj.Status.Conditions, conditionDeviceNodesReady.String())
+	if cond == nil || cond.Message == "" {
+		return "device nodes are missing"
+	}
+	return "validator is ready, but " + cond.Message
+}
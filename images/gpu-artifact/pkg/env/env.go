@@ -0,0 +1,180 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package env reads typed settings from environment variables.
+//
+// Values are trimmed before parsing and an empty value selects the default. Getters return an error naming
+// the variable; a Collector gathers the errors of several getters so a binary reports every bad variable at
+// once and exits from main.
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lookup returns the raw value of a variable, "" when it is unset. os.Getenv in production.
+type Lookup func(string) string
+
+// Error reports a variable whose value cannot be parsed.
+type Error struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s=%q: %v", e.Name, e.Value, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func value(lookup Lookup, name string) string {
+	if lookup == nil {
+		lookup = os.Getenv
+	}
+	return strings.TrimSpace(lookup(name))
+}
+
+// String returns the trimmed value of the variable or def when it is empty.
+func String(lookup Lookup, name, def string) string {
+	if raw := value(lookup, name); raw != "" {
+		return raw
+	}
+	return def
+}
+
+// Int parses a decimal integer that fits into int.
+func Int(lookup Lookup, name string, def int) (int, error) {
+	raw := value(lookup, name)
+	if raw == "" {
+		return def, nil
+	}
+	parsed, err := strconv.Atoi(raw)
+	if err != nil {
+		return def, &Error{Name: name, Value: raw, Err: numError(err)}
+	}
+	return parsed, nil
+}
+
+// Duration parses a Go duration such as "30s" or "5m".
+func Duration(lookup Lookup, name string, def time.Duration) (time.Duration, error) {
+	raw := value(lookup, name)
+	if raw == "" {
+		return def, nil
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return def, &Error{Name: name, Value: raw, Err: errors.New("not a duration")}
+	}
+	return parsed, nil
+}
+
+// Bool accepts the values of strconv.ParseBool: 1, t, true, 0, f, false in any case.
+func Bool(lookup Lookup, name string, def bool) (bool, error) {
+	raw := value(lookup, name)
+	if raw == "" {
+		return def, nil
+	}
+	parsed, err := strconv.ParseBool(raw)
+	if err != nil {
+		return def, &Error{Name: name, Value: raw, Err: errors.New("not a boolean")}
+	}
+	return parsed, nil
+}
+
+// CSV splits a comma-separated list; def is returned when the list has no items.
+func CSV(lookup Lookup, name string, def []string) []string {
+	if items := SplitCSV(value(lookup, name)); len(items) > 0 {
+		return items
+	}
+	return def
+}
+
+// SplitCSV splits a comma-separated list, trimming the items and dropping empty ones. It returns nil for a
+// list without items.
+func SplitCSV(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// numError drops the strconv prefix that repeats the input, keeping "value out of range" or "invalid syntax".
+func numError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+	return err
+}
+
+// Collector reads several variables and keeps the parse errors; a bad variable yields its default.
+type Collector struct {
+	lookup Lookup
+	errs   []error
+}
+
+// Collect starts reading variables through lookup; nil uses os.Getenv.
+func Collect(lookup Lookup) *Collector {
+	return &Collector{lookup: lookup}
+}
+
+func (c *Collector) String(name, def string) string {
+	return String(c.lookup, name, def)
+}
+
+func (c *Collector) Int(name string, def int) int {
+	parsed, err := Int(c.lookup, name, def)
+	c.add(err)
+	return parsed
+}
+
+func (c *Collector) Duration(name string, def time.Duration) time.Duration {
+	parsed, err := Duration(c.lookup, name, def)
+	c.add(err)
+	return parsed
+}
+
+func (c *Collector) Bool(name string, def bool) bool {
+	parsed, err := Bool(c.lookup, name, def)
+	c.add(err)
+	return parsed
+}
+
+func (c *Collector) CSV(name string, def []string) []string {
+	return CSV(c.lookup, name, def)
+}
+
+func (c *Collector) add(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Err joins the errors of every variable that failed to parse, one per line; nil when all were valid.
+func (c *Collector) Err() error {
+	return errors.Join(c.errs...)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lookupOf(vars map[string]string) Lookup {
+	return func(name string) string { return vars[name] }
+}
+
+func TestInt(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr string
+	}{
+		{name: "unset", raw: "", want: 7},
+		{name: "whitespace only", raw: "  ", want: 7},
+		{name: "plain", raw: "2", want: 2},
+		{name: "trailing space", raw: "2 ", want: 2},
+		{name: "surrounding whitespace", raw: "\t-3\n", want: -3},
+		{name: "overflow", raw: "99999999999999999999", want: 7, wantErr: `invalid LOG_DEBUG_VERBOSITY="99999999999999999999": value out of range`},
+		{name: "not a number", raw: "two", want: 7, wantErr: `invalid LOG_DEBUG_VERBOSITY="two": invalid syntax`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Int(lookupOf(map[string]string{"LOG_DEBUG_VERBOSITY": tc.raw}), "LOG_DEBUG_VERBOSITY", 7)
+			checkErr(t, err, tc.wantErr)
+			if got != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "unset", raw: "", want: time.Minute},
+		{name: "plain", raw: "30s", want: 30 * time.Second},
+		{name: "trailing space", raw: "5m ", want: 5 * time.Minute},
+		{name: "missing unit", raw: "30", want: time.Minute, wantErr: `invalid RESYNC="30": not a duration`},
+		{name: "overflow", raw: "9999999999h", want: time.Minute, wantErr: "not a duration"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Duration(lookupOf(map[string]string{"RESYNC": tc.raw}), "RESYNC", time.Minute)
+			checkErr(t, err, tc.wantErr)
+			if got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBool(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		def     bool
+		want    bool
+		wantErr string
+	}{
+		{name: "unset keeps default", raw: "", def: true, want: true},
+		{name: "true", raw: "true", want: true},
+		{name: "upper case with space", raw: " TRUE ", want: true},
+		{name: "zero", raw: "0", def: true, want: false},
+		{name: "yes is rejected", raw: "yes", def: true, want: true, wantErr: `invalid CDI_REQUIRED="yes": not a boolean`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Bool(lookupOf(map[string]string{"CDI_REQUIRED": tc.raw}), "CDI_REQUIRED", tc.def)
+			checkErr(t, err, tc.wantErr)
+			if got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStringAndCSV(t *testing.T) {
+	lookup := lookupOf(map[string]string{
+		"ADDR":  " :8080 ",
+		"BLANK": "   ",
+		"LIST":  " a, b ,,c ",
+		"EMPTY": " , ",
+	})
+	if got := String(lookup, "ADDR", ":9090"); got != ":8080" {
+		t.Fatalf("expected trimmed value, got %q", got)
+	}
+	if got := String(lookup, "BLANK", ":9090"); got != ":9090" {
+		t.Fatalf("expected default for a blank value, got %q", got)
+	}
+	if got := CSV(lookup, "LIST", nil); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected list: %#v", got)
+	}
+	if got := CSV(lookup, "EMPTY", []string{"d"}); !reflect.DeepEqual(got, []string{"d"}) {
+		t.Fatalf("expected default for a list without items, got %#v", got)
+	}
+	if got := SplitCSV(""); got != nil {
+		t.Fatalf("expected nil for an empty list, got %#v", got)
+	}
+}
+
+func TestCollectorReportsEveryBadVariable(t *testing.T) {
+	c := Collect(lookupOf(map[string]string{
+		"LOG_DEBUG_VERBOSITY": "2 ",
+		"WORKERS":             "many",
+		"RESYNC":              "soon",
+		"CO_LOCATED":          "true",
+		"STRICT":              "maybe",
+	}))
+	verbosity := c.Int("LOG_DEBUG_VERBOSITY", 0)
+	workers := c.Int("WORKERS", 4)
+	resync := c.Duration("RESYNC", time.Minute)
+	coLocated := c.Bool("CO_LOCATED", false)
+	strict := c.Bool("STRICT", false)
+	level := c.String("LOG_LEVEL", "info")
+
+	if verbosity != 2 || workers != 4 || resync != time.Minute || !coLocated || strict || level != "info" {
+		t.Fatalf("unexpected values: %d %d %s %t %t %q", verbosity, workers, resync, coLocated, strict, level)
+	}
+	err := c.Err()
+	if err == nil {
+		t.Fatal("expected the collected errors")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "WORKERS") || !strings.Contains(lines[1], "RESYNC") || !strings.Contains(lines[2], "STRICT") {
+		t.Fatalf("expected one line per bad variable in read order, got %q", err.Error())
+	}
+	var envErr *Error
+	if !errors.As(err, &envErr) || envErr.Name != "WORKERS" {
+		t.Fatalf("expected the first error to be an *Error for WORKERS, got %#v", envErr)
+	}
+
+	if err := Collect(lookupOf(nil)).Err(); err != nil {
+		t.Fatalf("expected no error without bad variables, got %v", err)
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected error containing %q, got %v", want, err)
+	}
+}
@@ -27,11 +27,14 @@ import (
 
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/common/steptaker"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/dra/driver"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/eventrecord"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/health"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/capabilities"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/inventory"
 	nvmlsvc "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/nvml"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/persistence"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/state"
 )
 
@@ -40,31 +43,48 @@ type Agent struct {
 	cfg Config
 	log *log.Logger
 
-	scheme     *runtime.Scheme
-	store      *service.PhysicalGPUService
-	reader     capabilities.CapabilitiesReader
-	placements inventory.MigPlacementReader
-	tracker    handler.FailureTracker
-	steps      steptaker.StepTakers[state.State]
-	draDriver  *driver.Driver
-	stop       func()
+	scheme      *runtime.Scheme
+	store       *service.PhysicalGPUService
+	reader      capabilities.CapabilitiesReader
+	placements  inventory.MigPlacementReader
+	tracker     handler.FailureTracker
+	persistence health.PersistenceModeEnabler
+	nvml        *nvmlsvc.Watcher
+	recovery    *nvmlsvc.Recovery
+	steps       steptaker.StepTakers[state.State]
+	draDriver   *driver.Driver
+	recorder    eventrecord.EventRecorderLogger
+	notify      func()
+	stop        func()
 }
 
 // New creates a new gpu-handler agent.
 func New(client client.Client, cfg Config, log *log.Logger) *Agent {
 	store := service.NewPhysicalGPUService(client)
-	nvmlService := nvmlsvc.NewNVML()
+	nvmlService := nvmlsvc.NewWatcher(nvmlsvc.NewNVML())
 	reader := capabilities.NewNVMLReader(nvmlService)
 	placements := inventory.NewNVMLMigPlacementReader(nvmlService)
 	tracker := state.NewNVMLFailureTracker(nil)
+	var enabler health.PersistenceModeEnabler
+	if cfg.EnforcePersistenceMode {
+		enabler = persistence.NewNVMLEnabler(nvmlService)
+	}
 
 	return &Agent{
-		cfg:        cfg,
-		log:        log,
-		scheme:     client.Scheme(),
-		store:      store,
-		reader:     reader,
-		placements: placements,
-		tracker:    tracker,
+		cfg:         cfg,
+		log:         log,
+		scheme:      client.Scheme(),
+		store:       store,
+		reader:      reader,
+		placements:  placements,
+		tracker:     tracker,
+		persistence: enabler,
+		nvml:        nvmlService,
+		recovery:    nvmlsvc.NewRecovery(nvmlService),
 	}
 }
+
+// Healthy is false once NVML could not be re-initialized after a driver restart.
+func (a *Agent) Healthy() bool {
+	return a.recovery == nil || a.recovery.Healthy()
+}
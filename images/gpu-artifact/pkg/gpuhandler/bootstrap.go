@@ -25,6 +25,7 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/dra/driver"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/eventrecord"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/health"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/capabilities"
 	inventorysvc "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/inventory"
@@ -32,13 +33,14 @@ import (
 )
 
 type bootstrapService struct {
-	cfg        Config
-	log        *log.Logger
-	scheme     *runtime.Scheme
-	store      *service.PhysicalGPUService
-	reader     capabilities.CapabilitiesReader
-	placements inventorysvc.MigPlacementReader
-	tracker    handler.FailureTracker
+	cfg         Config
+	log         *log.Logger
+	scheme      *runtime.Scheme
+	store       *service.PhysicalGPUService
+	reader      capabilities.CapabilitiesReader
+	placements  inventorysvc.MigPlacementReader
+	tracker     handler.FailureTracker
+	persistence health.PersistenceModeEnabler
 }
 
 type bootstrapResult struct {
@@ -48,14 +50,15 @@ type bootstrapResult struct {
 	recorder eventrecord.EventRecorderLogger
 }
 
-func newBootstrapService(cfg Config, log *log.Logger, scheme *runtime.Scheme, store *service.PhysicalGPUService, reader capabilities.CapabilitiesReader, placements inventorysvc.MigPlacementReader, tracker handler.FailureTracker) *bootstrapService {
+func newBootstrapService(cfg Config, log *log.Logger, scheme *runtime.Scheme, store *service.PhysicalGPUService, reader capabilities.CapabilitiesReader, placements inventorysvc.MigPlacementReader, tracker handler.FailureTracker, persistence health.PersistenceModeEnabler) *bootstrapService {
 	return &bootstrapService{
-		cfg:        cfg,
-		log:        log,
-		scheme:     scheme,
-		store:      store,
-		reader:     reader,
-		placements: placements,
-		tracker:    tracker,
+		cfg:         cfg,
+		log:         log,
+		scheme:      scheme,
+		store:       store,
+		reader:      reader,
+		placements:  placements,
+		tracker:     tracker,
+		persistence: persistence,
 	}
 }
@@ -29,10 +29,13 @@ import (
 	draallocator "github.com/aleksandr-podmoskovniy/gpu/pkg/dra/services/allocator"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/eventrecord"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/featuregates"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/health"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/publish"
 	cdisvc "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/cdi"
 	handlerresourceslice "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/resourceslice"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 func (b *bootstrapService) initFeatureGates(kubeClient kubernetes.Interface, builder *handlerresourceslice.Builder, recorder eventrecord.EventRecorderLogger, notify func()) *featuregates.Service {
@@ -43,6 +46,31 @@ func (b *bootstrapService) initFeatureGates(kubeClient kubernetes.Interface, bui
 	return featureGates
 }
 
+// startCoordination serves NVML data to gpu-node-agent when both run in the same pod.
+func (b *bootstrapService) startCoordination(ctx context.Context, capabilities *health.CapabilitiesHandler) {
+	if !b.cfg.CoLocated {
+		return
+	}
+	store := nodecoord.NewStore()
+	capabilities.SetPublisher(store)
+	server := nodecoord.NewServer(b.cfg.SocketPath, store, b.log)
+	go func() {
+		if err := server.Run(ctx); err != nil {
+			b.log.Error("coordination socket stopped", "path", b.cfg.SocketPath, logger.SlogErr(err))
+		}
+	}()
+	b.log.Info("co-located mode enabled, NVML data is served to gpu-node-agent", "path", b.cfg.SocketPath)
+}
+
+// persistenceModeHandler returns nil unless persistence mode is enforced; the step pipeline skips nil handlers.
+func (b *bootstrapService) persistenceModeHandler(recorder eventrecord.EventRecorderLogger) handler.Handler {
+	if b.persistence == nil {
+		return nil
+	}
+	b.log.Info("persistence mode enforcement enabled")
+	return health.NewPersistenceModeHandler(b.persistence, b.store, recorder)
+}
+
 func (b *bootstrapService) resolveDeviceStatus(kubeClient kubernetes.Interface) bool {
 	deviceStatusEnabled, source, serverVersion, err := drafeaturegates.ResolveDeviceStatus(kubeClient, b.cfg.DeviceStatusMode)
 	if err != nil {
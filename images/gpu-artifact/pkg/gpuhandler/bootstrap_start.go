@@ -26,6 +26,7 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/health"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/inventory"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler/publish"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/devnodes"
 	handlerresourceslice "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/resourceslice"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
 )
@@ -56,13 +57,20 @@ func (b *bootstrapService) Start(ctx context.Context, notify func()) (*bootstrap
 		return nil, fmt.Errorf("start DRA driver: %w", err)
 	}
 	cdiSyncer := b.buildCDISyncer()
+	capabilitiesHandler := health.NewCapabilitiesHandler(b.reader, b.store, b.tracker, recorder)
+	b.startCoordination(ctx, capabilitiesHandler)
 
 	steps := handler.NewSteps(
 		b.log,
 		inventory.NewDiscoverHandler(b.store),
 		health.NewMarkNotReadyHandler(b.store, b.tracker, recorder),
+		health.NewDeviceNodesHandler(devnodes.NewChecker(devnodes.Options{
+			DevRoot: b.cfg.DevRoot,
+			Create:  b.cfg.CreateDeviceNodes,
+		}), b.store, recorder),
 		inventory.NewFilterReadyHandler(),
-		health.NewCapabilitiesHandler(b.reader, b.store, b.tracker, recorder),
+		capabilitiesHandler,
+		b.persistenceModeHandler(recorder),
 		health.NewFilterHealthyHandler(),
 		publish.NewPublishResourcesHandler(builder, draDriver, cdiSyncer, recorder, featureGates.HandleError),
 	)
@@ -28,4 +28,13 @@ type Config struct {
 	HostDriverRoot         string
 	CDIRoot                string
 	NvidiaCDIHookPath      string
+	// DevRoot is where the host /dev is mounted for device node checks.
+	DevRoot string
+	// CreateDeviceNodes enables mknod for missing NVIDIA device nodes.
+	CreateDeviceNodes bool
+	// EnforcePersistenceMode turns persistence mode on for GPUs that run without it.
+	EnforcePersistenceMode bool
+	// CoLocated serves NVML data to gpu-node-agent over SocketPath instead of writing it to PhysicalGPU status.
+	CoLocated  bool
+	SocketPath string
 }
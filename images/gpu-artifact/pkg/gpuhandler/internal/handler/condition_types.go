@@ -17,6 +17,8 @@ limitations under the License.
 package handler
 
 const (
-	DriverReadyType     = "DriverReady"
-	HardwareHealthyType = "HardwareHealthy"
+	DriverReadyType          = "DriverReady"
+	HardwareHealthyType      = "HardwareHealthy"
+	DeviceNodesReadyType     = "DeviceNodesReady"
+	PersistenceModeReadyType = "PersistenceModeReady"
 )
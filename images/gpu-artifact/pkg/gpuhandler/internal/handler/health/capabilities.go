@@ -21,6 +21,7 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/capabilities"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 const capabilitiesHandlerName = "capabilities"
@@ -31,6 +32,13 @@ type CapabilitiesHandler struct {
 	store    *service.PhysicalGPUService
 	tracker  handler.FailureTracker
 	recorder eventrecord.EventRecorderLogger
+	// publisher is set in co-located mode: NVML data goes to gpu-node-agent instead of the PhysicalGPU status.
+	publisher Publisher
+}
+
+// Publisher receives NVML data for gpu-node-agent running in the same pod.
+type Publisher interface {
+	Publish(device nodecoord.Device)
 }
 
 // NewCapabilitiesHandler constructs a capabilities handler.
@@ -43,6 +51,12 @@ func NewCapabilitiesHandler(reader capabilities.CapabilitiesReader, store *servi
 	}
 }
 
+// SetPublisher hands capabilities and currentState.nvidia over to gpu-node-agent.
+// The handler keeps writing the HardwareHealthy condition itself.
+func (h *CapabilitiesHandler) SetPublisher(publisher Publisher) {
+	h.publisher = publisher
+}
+
 // Name returns the handler name.
 func (h *CapabilitiesHandler) Name() string {
 	return capabilitiesHandlerName
@@ -32,6 +32,7 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/capabilities"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 func TestCapabilitiesHandlerSuccess(t *testing.T) {
@@ -316,3 +317,82 @@ func (t *fakeTracker) RecordFailure(_ string) bool {
 func (t *fakeTracker) Clear(name string) {
 	t.cleared = append(t.cleared, name)
 }
+
+type recordingPublisher struct {
+	devices []nodecoord.Device
+}
+
+func (p *recordingPublisher) Publish(device nodecoord.Device) {
+	p.devices = append(p.devices, device)
+}
+
+func TestCapabilitiesHandlerCoLocatedPublishesNVMLData(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gpuv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("scheme: %v", err)
+	}
+
+	pgpu := &gpuv1alpha1.PhysicalGPU{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-0"},
+		Status: gpuv1alpha1.PhysicalGPUStatus{
+			PCIInfo: &gpuv1alpha1.PCIInfo{Address: "0000:02:00.0"},
+			CurrentState: &gpuv1alpha1.GPUCurrentState{
+				DriverType: gpuv1alpha1.DriverTypeNvidia,
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&gpuv1alpha1.PhysicalGPU{}).
+		WithObjects(pgpu).
+		Build()
+
+	snapshot := &capabilities.DeviceSnapshot{
+		Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "NVIDIA A30"},
+		CurrentState: &gpuv1alpha1.GPUCurrentState{
+			Nvidia: &gpuv1alpha1.NvidiaCurrentState{CUDAVersion: "13.0"},
+		},
+	}
+	reader := &fakeReader{session: &fakeSession{snapshot: snapshot}}
+	tracker := &fakeTracker{shouldAttempt: true, recordFailure: true}
+	publisher := &recordingPublisher{}
+
+	h := NewCapabilitiesHandler(reader, service.NewPhysicalGPUService(client), tracker, nil)
+	h.SetPublisher(publisher)
+
+	st := state.New("node-1")
+	st.SetReady([]gpuv1alpha1.PhysicalGPU{*pgpu})
+	if err := h.Handle(context.Background(), st); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	if len(publisher.devices) != 1 {
+		t.Fatalf("expected one published device, got %d", len(publisher.devices))
+	}
+	published := publisher.devices[0]
+	if published.PCIAddress != "0000:02:00.0" || published.Capabilities == nil || published.Capabilities.ProductName != "NVIDIA A30" {
+		t.Fatalf("unexpected published device %+v", published)
+	}
+	if published.Nvidia == nil || published.Nvidia.CUDAVersion != "13.0" {
+		t.Fatalf("expected nvidia state to be published, got %+v", published.Nvidia)
+	}
+
+	updated := &gpuv1alpha1.PhysicalGPU{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: "gpu-0"}, updated); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if updated.Status.Capabilities != nil {
+		t.Fatalf("capabilities must be left to gpu-node-agent, got %+v", updated.Status.Capabilities)
+	}
+	if updated.Status.CurrentState == nil || updated.Status.CurrentState.Nvidia != nil {
+		t.Fatalf("currentState.nvidia must be left to gpu-node-agent, got %+v", updated.Status.CurrentState)
+	}
+	cond := meta.FindStatusCondition(updated.Status.Conditions, handler.HardwareHealthyType)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("expected HardwareHealthy True, got %#v", cond)
+	}
+	if len(st.Ready()) != 1 || st.Ready()[0].Status.Capabilities == nil {
+		t.Fatalf("expected ready devices to carry NVML data for publishing, got %+v", st.Ready())
+	}
+}
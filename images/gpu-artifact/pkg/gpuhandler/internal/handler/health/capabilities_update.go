@@ -24,6 +24,7 @@ import (
 
 	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/capabilities"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 func (h *CapabilitiesHandler) updateDevice(ctx context.Context, session capabilities.CapabilitiesSession, pgpu gpuv1alpha1.PhysicalGPU) (*gpuv1alpha1.PhysicalGPU, error) {
@@ -45,12 +46,34 @@ func (h *CapabilitiesHandler) updateDevice(ctx context.Context, session capabili
 	setHardwareCondition(obj, metav1.ConditionTrue, reasonNVMLHealthy, "NVML is available")
 
 	h.tracker.Clear(obj.Name)
-	if err := h.store.PatchStatus(ctx, obj, base); err != nil {
+	if err := h.store.PatchStatus(ctx, h.statusPatch(obj, base), base); err != nil {
 		return nil, err
 	}
 	return obj, nil
 }
 
+// statusPatch returns the object to patch with. In co-located mode NVML data is published
+// to gpu-node-agent and the NVML-owned fields are left as they are in the stored object.
+func (h *CapabilitiesHandler) statusPatch(obj, base *gpuv1alpha1.PhysicalGPU) *gpuv1alpha1.PhysicalGPU {
+	if h.publisher == nil {
+		return obj
+	}
+
+	device := nodecoord.Device{Capabilities: obj.Status.Capabilities.DeepCopy()}
+	if obj.Status.PCIInfo != nil {
+		device.PCIAddress = obj.Status.PCIInfo.Address
+	}
+	if obj.Status.CurrentState != nil {
+		device.Nvidia = obj.Status.CurrentState.Nvidia.DeepCopy()
+	}
+	h.publisher.Publish(device)
+
+	patched := obj.DeepCopy()
+	patched.Status.Capabilities = base.Status.Capabilities.DeepCopy()
+	patched.Status.CurrentState = base.Status.CurrentState.DeepCopy()
+	return patched
+}
+
 func (h *CapabilitiesHandler) markDriverTypeNotNvidia(ctx context.Context, pgpu gpuv1alpha1.PhysicalGPU) error {
 	base := pgpu.DeepCopy()
 	obj := pgpu.DeepCopy()
@@ -59,7 +82,7 @@ func (h *CapabilitiesHandler) markDriverTypeNotNvidia(ctx context.Context, pgpu
 		obj.Status.CurrentState.Nvidia = nil
 	}
 	setHardwareCondition(obj, metav1.ConditionUnknown, reasonDriverTypeNotNvidia, "current driver is not Nvidia")
-	return h.store.PatchStatus(ctx, obj, base)
+	return h.store.PatchStatus(ctx, h.statusPatch(obj, base), base)
 }
 
 func (h *CapabilitiesHandler) applyFailure(ctx context.Context, devices []gpuv1alpha1.PhysicalGPU, err error) error {
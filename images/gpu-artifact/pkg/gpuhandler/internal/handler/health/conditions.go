@@ -24,12 +24,16 @@ import (
 )
 
 func hardwareConditionChanged(prev, next *gpuv1alpha1.PhysicalGPU) bool {
+	return conditionChanged(prev, next, handler.HardwareHealthyType)
+}
+
+func conditionChanged(prev, next *gpuv1alpha1.PhysicalGPU, condType string) bool {
 	if prev == nil || next == nil {
 		return false
 	}
 
-	oldCond := meta.FindStatusCondition(prev.Status.Conditions, handler.HardwareHealthyType)
-	newCond := meta.FindStatusCondition(next.Status.Conditions, handler.HardwareHealthyType)
+	oldCond := meta.FindStatusCondition(prev.Status.Conditions, condType)
+	newCond := meta.FindStatusCondition(next.Status.Conditions, condType)
 	if newCond == nil {
 		return false
 	}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/eventrecord"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/devnodes"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+)
+
+const deviceNodesHandlerName = "device-nodes"
+
+// DeviceNodesChecker verifies NVIDIA device nodes on the host.
+type DeviceNodesChecker interface {
+	Check(gpus []devnodes.GPU) (devnodes.Result, error)
+}
+
+// DeviceNodesHandler publishes DeviceNodesReady for NVIDIA GPUs of the node.
+type DeviceNodesHandler struct {
+	checker  DeviceNodesChecker
+	store    *service.PhysicalGPUService
+	recorder eventrecord.EventRecorderLogger
+}
+
+// NewDeviceNodesHandler constructs a device nodes handler.
+func NewDeviceNodesHandler(checker DeviceNodesChecker, store *service.PhysicalGPUService, recorder eventrecord.EventRecorderLogger) *DeviceNodesHandler {
+	return &DeviceNodesHandler{
+		checker:  checker,
+		store:    store,
+		recorder: recorder,
+	}
+}
+
+// Name returns the handler name.
+func (h *DeviceNodesHandler) Name() string {
+	return deviceNodesHandlerName
+}
+
+// Handle checks device nodes once per node and stamps the result on every NVIDIA GPU.
+// It runs on all GPUs rather than DriverReady ones: the controller folds this condition
+// into DriverReady, so filtering first would never let a missing node recover.
+func (h *DeviceNodesHandler) Handle(ctx context.Context, st state.State) error {
+	if h.checker == nil || h.store == nil {
+		return nil
+	}
+
+	all := st.All()
+	gpus := make([]devnodes.GPU, 0, len(all))
+	for _, pgpu := range all {
+		if !isDriverTypeNvidia(pgpu) || pgpu.Status.PCIInfo == nil || pgpu.Status.PCIInfo.Address == "" {
+			continue
+		}
+		gpus = append(gpus, devnodes.GPU{PCIAddress: pgpu.Status.PCIInfo.Address, MIGEnabled: migEnabled(pgpu)})
+	}
+	if len(gpus) == 0 {
+		return nil
+	}
+
+	result, err := h.checker.Check(gpus)
+	status, reason, message := deviceNodesCondition(result, err)
+
+	var errs []error
+	updated := make([]gpuv1alpha1.PhysicalGPU, 0, len(all))
+	for _, pgpu := range all {
+		if !isDriverTypeNvidia(pgpu) || pgpu.Status.PCIInfo == nil || pgpu.Status.PCIInfo.Address == "" {
+			updated = append(updated, pgpu)
+			continue
+		}
+
+		base := pgpu.DeepCopy()
+		obj := pgpu.DeepCopy()
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:               handler.DeviceNodesReadyType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: obj.Generation,
+		})
+		if !conditionChanged(base, obj, handler.DeviceNodesReadyType) {
+			updated = append(updated, pgpu)
+			continue
+		}
+		if err := h.store.PatchStatus(ctx, obj, base); err != nil {
+			errs = append(errs, err)
+			updated = append(updated, pgpu)
+			continue
+		}
+		h.recordDeviceNodesEvent(ctx, obj, status, reason, message, result.Created)
+		updated = append(updated, *obj)
+	}
+	st.SetAll(updated)
+
+	return errors.Join(errs...)
+}
+
+func deviceNodesCondition(result devnodes.Result, err error) (metav1.ConditionStatus, string, string) {
+	if err != nil {
+		return metav1.ConditionUnknown, reasonDeviceNodesCheckError, err.Error()
+	}
+	if result.Ready() {
+		if len(result.Created) > 0 {
+			return metav1.ConditionTrue, reasonDeviceNodesPresent, "device nodes are present, created " + strings.Join(result.Created, ", ")
+		}
+		return metav1.ConditionTrue, reasonDeviceNodesPresent, "device nodes are present"
+	}
+
+	reason := reasonDeviceNodesMissing
+	if result.OnlyUVMMissing() {
+		reason = reasonUVMDeviceNodesMissing
+	}
+	message := "missing " + strings.Join(result.MissingPaths(), ", ")
+	if len(result.CreateErrors) > 0 {
+		message = fmt.Sprintf("%s; create failed: %v", message, result.CreateErrors[0])
+	}
+	return metav1.ConditionFalse, reason, message
+}
+
+func migEnabled(pgpu gpuv1alpha1.PhysicalGPU) bool {
+	current := pgpu.Status.CurrentState
+	if current == nil || current.Nvidia == nil || current.Nvidia.MIG == nil {
+		return false
+	}
+	return current.Nvidia.MIG.Mode == gpuv1alpha1.MIGModeEnabled
+}
+
+func (h *DeviceNodesHandler) recordDeviceNodesEvent(ctx context.Context, obj *gpuv1alpha1.PhysicalGPU, status metav1.ConditionStatus, reason, message string, created []string) {
+	if h.recorder == nil {
+		return
+	}
+
+	log := logger.FromContext(ctx)
+	if obj.Status.NodeInfo != nil && obj.Status.NodeInfo.NodeName != "" {
+		log = log.With("node", obj.Status.NodeInfo.NodeName)
+	}
+	recorder := h.recorder.WithLogging(log)
+
+	if len(created) > 0 {
+		recorder.Event(obj, corev1.EventTypeNormal, reasonDeviceNodesCreated, "created "+strings.Join(created, ", "))
+	}
+	if status != metav1.ConditionTrue {
+		recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/eventrecord"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/persistence"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+)
+
+const (
+	persistenceModeHandlerName = "persistence-mode"
+	// defaultPersistenceRetryInterval spaces out attempts on a GPU that refused persistence mode.
+	defaultPersistenceRetryInterval = 10 * time.Minute
+)
+
+// PersistenceModeEnabler turns persistence mode on and reports whether it had to change anything.
+type PersistenceModeEnabler interface {
+	Enable(pciAddress string) (bool, error)
+}
+
+// PersistenceModeHandler turns persistence mode on for NVIDIA GPUs that run without it
+// and publishes the outcome as PersistenceModeReady.
+type PersistenceModeHandler struct {
+	enabler  PersistenceModeEnabler
+	store    *service.PhysicalGPUService
+	recorder eventrecord.EventRecorderLogger
+	interval time.Duration
+	now      func() time.Time
+	// attempts holds the last failed attempt per GPU; unsupported holds GPUs that need nvidia-persistenced
+	// and are not retried until the handler restarts.
+	attempts    map[string]time.Time
+	unsupported map[string]struct{}
+}
+
+// NewPersistenceModeHandler constructs a persistence mode handler.
+func NewPersistenceModeHandler(enabler PersistenceModeEnabler, store *service.PhysicalGPUService, recorder eventrecord.EventRecorderLogger) *PersistenceModeHandler {
+	return &PersistenceModeHandler{
+		enabler:     enabler,
+		store:       store,
+		recorder:    recorder,
+		interval:    defaultPersistenceRetryInterval,
+		now:         time.Now,
+		attempts:    map[string]time.Time{},
+		unsupported: map[string]struct{}{},
+	}
+}
+
+// Name returns the handler name.
+func (h *PersistenceModeHandler) Name() string {
+	return persistenceModeHandlerName
+}
+
+// Handle runs after the capabilities handler, so the persistence mode it checks was just read from NVML.
+// GPUs whose driver cannot report the mode are left without the condition.
+func (h *PersistenceModeHandler) Handle(ctx context.Context, st state.State) error {
+	if h.enabler == nil || h.store == nil {
+		return nil
+	}
+
+	var errs []error
+	ready := st.Ready()
+	updated := make([]gpuv1alpha1.PhysicalGPU, 0, len(ready))
+	for _, pgpu := range ready {
+		status, reason, message, ok := h.enforce(pgpu)
+		if !ok {
+			updated = append(updated, pgpu)
+			continue
+		}
+
+		base := pgpu.DeepCopy()
+		obj := pgpu.DeepCopy()
+		meta.SetStatusCondition(&obj.Status.Conditions, metav1.Condition{
+			Type:               handler.PersistenceModeReadyType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: obj.Generation,
+		})
+		if !conditionChanged(base, obj, handler.PersistenceModeReadyType) {
+			updated = append(updated, pgpu)
+			continue
+		}
+		if err := h.store.PatchStatus(ctx, obj, base); err != nil {
+			errs = append(errs, err)
+			updated = append(updated, pgpu)
+			continue
+		}
+		h.recordPersistenceEvent(ctx, obj, status, reason, message)
+		updated = append(updated, *obj)
+	}
+	st.SetReady(updated)
+
+	return errors.Join(errs...)
+}
+
+// enforce returns the condition for pgpu; ok is false when the condition must be left as it is.
+func (h *PersistenceModeHandler) enforce(pgpu gpuv1alpha1.PhysicalGPU) (status metav1.ConditionStatus, reason, message string, ok bool) {
+	if !isDriverTypeNvidia(pgpu) || pgpu.Status.PCIInfo == nil || pgpu.Status.PCIInfo.Address == "" {
+		return "", "", "", false
+	}
+	current := pgpu.Status.CurrentState.Nvidia
+	if current == nil {
+		return "", "", "", false
+	}
+
+	switch current.PersistenceMode {
+	case gpuv1alpha1.PersistenceModeEnabled:
+		delete(h.attempts, pgpu.Name)
+		delete(h.unsupported, pgpu.Name)
+		return metav1.ConditionTrue, reasonPersistenceModeEnabled, "persistence mode is enabled", true
+	case gpuv1alpha1.PersistenceModeDisabled:
+	default:
+		return "", "", "", false
+	}
+
+	if _, found := h.unsupported[pgpu.Name]; found {
+		return "", "", "", false
+	}
+	now := h.now()
+	if last, found := h.attempts[pgpu.Name]; found && now.Sub(last) < h.interval {
+		return "", "", "", false
+	}
+
+	changed, err := h.enabler.Enable(pgpu.Status.PCIInfo.Address)
+	switch {
+	case errors.Is(err, persistence.ErrDaemonRequired):
+		h.unsupported[pgpu.Name] = struct{}{}
+		return metav1.ConditionFalse, reasonPersistenceDaemonRequired, err.Error(), true
+	case err != nil:
+		h.attempts[pgpu.Name] = now
+		return metav1.ConditionFalse, reasonPersistenceModeEnableFailed, err.Error(), true
+	}
+	delete(h.attempts, pgpu.Name)
+	if !changed {
+		return metav1.ConditionTrue, reasonPersistenceModeEnabled, "persistence mode is enabled", true
+	}
+	return metav1.ConditionTrue, reasonPersistenceModeEnforced, "persistence mode was turned on by gpu-handler", true
+}
+
+func (h *PersistenceModeHandler) recordPersistenceEvent(ctx context.Context, obj *gpuv1alpha1.PhysicalGPU, status metav1.ConditionStatus, reason, message string) {
+	if h.recorder == nil {
+		return
+	}
+
+	log := logger.FromContext(ctx)
+	if obj.Status.NodeInfo != nil && obj.Status.NodeInfo.NodeName != "" {
+		log = log.With("node", obj.Status.NodeInfo.NodeName)
+	}
+	recorder := h.recorder.WithLogging(log)
+
+	switch {
+	case status != metav1.ConditionTrue:
+		recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+	case reason == reasonPersistenceModeEnforced:
+		recorder.Event(obj, corev1.EventTypeNormal, reason, message)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/persistence"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/state"
+)
+
+func TestPersistenceModeHandlerAlreadyEnabled(t *testing.T) {
+	pgpu := persistenceGPU(gpuv1alpha1.PersistenceModeEnabled)
+	cl := persistenceClient(t, pgpu)
+	enabler := &fakeEnabler{}
+	h := NewPersistenceModeHandler(enabler, service.NewPhysicalGPUService(cl), nil)
+
+	runPersistence(t, h, cl, pgpu)
+
+	if enabler.calls != 0 {
+		t.Fatalf("expected no enable call for an enabled GPU, got %d", enabler.calls)
+	}
+	assertPersistenceCondition(t, cl, metav1.ConditionTrue, reasonPersistenceModeEnabled)
+}
+
+func TestPersistenceModeHandlerTurnsModeOn(t *testing.T) {
+	pgpu := persistenceGPU(gpuv1alpha1.PersistenceModeDisabled)
+	cl := persistenceClient(t, pgpu)
+	enabler := &fakeEnabler{changed: true}
+	h := NewPersistenceModeHandler(enabler, service.NewPhysicalGPUService(cl), nil)
+
+	runPersistence(t, h, cl, pgpu)
+
+	if enabler.calls != 1 || enabler.pci != "0000:02:00.0" {
+		t.Fatalf("expected one enable call for 0000:02:00.0, got %d for %q", enabler.calls, enabler.pci)
+	}
+	assertPersistenceCondition(t, cl, metav1.ConditionTrue, reasonPersistenceModeEnforced)
+}
+
+func TestPersistenceModeHandlerDaemonRequiredIsNotRetried(t *testing.T) {
+	pgpu := persistenceGPU(gpuv1alpha1.PersistenceModeDisabled)
+	cl := persistenceClient(t, pgpu)
+	enabler := &fakeEnabler{err: fmt.Errorf("%w: use nvidia-persistenced", persistence.ErrDaemonRequired)}
+	h := NewPersistenceModeHandler(enabler, service.NewPhysicalGPUService(cl), nil)
+	clock := time.Now()
+	h.now = func() time.Time { return clock }
+
+	runPersistence(t, h, cl, pgpu)
+	assertPersistenceCondition(t, cl, metav1.ConditionFalse, reasonPersistenceDaemonRequired)
+
+	clock = clock.Add(24 * time.Hour)
+	runPersistence(t, h, cl, pgpu)
+	if enabler.calls != 1 {
+		t.Fatalf("expected the daemon-required failure to stop retries, got %d calls", enabler.calls)
+	}
+}
+
+func TestPersistenceModeHandlerRateLimitsFailures(t *testing.T) {
+	pgpu := persistenceGPU(gpuv1alpha1.PersistenceModeDisabled)
+	cl := persistenceClient(t, pgpu)
+	enabler := &fakeEnabler{err: fmt.Errorf("%w: Insufficient Permissions", persistence.ErrEnableFailed)}
+	h := NewPersistenceModeHandler(enabler, service.NewPhysicalGPUService(cl), nil)
+	clock := time.Now()
+	h.now = func() time.Time { return clock }
+
+	runPersistence(t, h, cl, pgpu)
+	assertPersistenceCondition(t, cl, metav1.ConditionFalse, reasonPersistenceModeEnableFailed)
+
+	clock = clock.Add(h.interval / 2)
+	runPersistence(t, h, cl, pgpu)
+	if enabler.calls != 1 {
+		t.Fatalf("expected no retry within the interval, got %d calls", enabler.calls)
+	}
+
+	clock = clock.Add(h.interval)
+	runPersistence(t, h, cl, pgpu)
+	if enabler.calls != 2 {
+		t.Fatalf("expected a retry after the interval, got %d calls", enabler.calls)
+	}
+}
+
+func TestPersistenceModeHandlerSkipsUnknownMode(t *testing.T) {
+	pgpu := persistenceGPU(gpuv1alpha1.PersistenceModeNA)
+	cl := persistenceClient(t, pgpu)
+	enabler := &fakeEnabler{}
+	h := NewPersistenceModeHandler(enabler, service.NewPhysicalGPUService(cl), nil)
+
+	runPersistence(t, h, cl, pgpu)
+
+	if enabler.calls != 0 {
+		t.Fatalf("expected no enable call, got %d", enabler.calls)
+	}
+	updated := &gpuv1alpha1.PhysicalGPU{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: pgpu.Name}, updated); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if cond := meta.FindStatusCondition(updated.Status.Conditions, handler.PersistenceModeReadyType); cond != nil {
+		t.Fatalf("expected no condition when the mode is not available, got %#v", cond)
+	}
+}
+
+func persistenceGPU(mode gpuv1alpha1.PersistenceModeState) *gpuv1alpha1.PhysicalGPU {
+	return &gpuv1alpha1.PhysicalGPU{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-0"},
+		Status: gpuv1alpha1.PhysicalGPUStatus{
+			PCIInfo: &gpuv1alpha1.PCIInfo{Address: "0000:02:00.0"},
+			CurrentState: &gpuv1alpha1.GPUCurrentState{
+				DriverType: gpuv1alpha1.DriverTypeNvidia,
+				Nvidia:     &gpuv1alpha1.NvidiaCurrentState{PersistenceMode: mode},
+			},
+		},
+	}
+}
+
+func persistenceClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := gpuv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("scheme: %v", err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&gpuv1alpha1.PhysicalGPU{}).
+		WithObjects(objs...).
+		Build()
+}
+
+// runPersistence feeds the stored object back in, the way every sync starts from a fresh list.
+func runPersistence(t *testing.T, h *PersistenceModeHandler, cl client.Client, pgpu *gpuv1alpha1.PhysicalGPU) {
+	t.Helper()
+	current := &gpuv1alpha1.PhysicalGPU{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: pgpu.Name}, current); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	current.Status.CurrentState = pgpu.Status.CurrentState.DeepCopy()
+
+	st := state.New("node-1")
+	st.SetReady([]gpuv1alpha1.PhysicalGPU{*current})
+	if err := h.Handle(context.Background(), st); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+}
+
+func assertPersistenceCondition(t *testing.T, cl client.Client, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	updated := &gpuv1alpha1.PhysicalGPU{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "gpu-0"}, updated); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	cond := meta.FindStatusCondition(updated.Status.Conditions, handler.PersistenceModeReadyType)
+	if cond == nil || cond.Status != status || cond.Reason != reason {
+		t.Fatalf("expected PersistenceModeReady %s/%s, got %#v", status, reason, cond)
+	}
+}
+
+type fakeEnabler struct {
+	changed bool
+	err     error
+	calls   int
+	pci     string
+}
+
+func (f *fakeEnabler) Enable(pciAddress string) (bool, error) {
+	f.calls++
+	f.pci = pciAddress
+	return f.changed, f.err
+}
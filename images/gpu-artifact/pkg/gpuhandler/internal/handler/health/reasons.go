@@ -23,4 +23,15 @@ const (
 	reasonDriverTypeNotNvidia = "DriverTypeNotNvidia"
 	reasonDriverNotReady      = "DriverNotReady"
 	reasonMissingPCIAddress   = "MissingPCIAddress"
+
+	reasonDeviceNodesPresent    = "DeviceNodesPresent"
+	reasonDeviceNodesMissing    = "DeviceNodesMissing"
+	reasonUVMDeviceNodesMissing = "UVMDeviceNodesMissing"
+	reasonDeviceNodesCheckError = "DeviceNodesCheckFailed"
+	reasonDeviceNodesCreated    = "DeviceNodesCreated"
+
+	reasonPersistenceModeEnabled      = "PersistenceModeEnabled"
+	reasonPersistenceModeEnforced     = "PersistenceModeEnforced"
+	reasonPersistenceModeEnableFailed = "PersistenceModeEnableFailed"
+	reasonPersistenceDaemonRequired   = "PersistenceDaemonRequired"
 )
@@ -72,6 +72,13 @@ func migModeFromNVML(mode int) gpuv1alpha1.MIGModeState {
 	}
 }
 
+func persistenceModeFromNVML(mode nvml.EnableState) gpuv1alpha1.PersistenceModeState {
+	if mode == nvml.FEATURE_ENABLED {
+		return gpuv1alpha1.PersistenceModeEnabled
+	}
+	return gpuv1alpha1.PersistenceModeDisabled
+}
+
 func nvmlName(raw []uint8) string {
 	for i, b := range raw {
 		if b == 0 {
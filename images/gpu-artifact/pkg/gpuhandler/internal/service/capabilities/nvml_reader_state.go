@@ -19,7 +19,11 @@ limitations under the License.
 
 package capabilities
 
-import gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+)
 
 func buildCurrentState(dev NVMLDevice, driverVersion, cudaVersion string) (*gpuv1alpha1.GPUCurrentState, error) {
 	current := &gpuv1alpha1.GPUCurrentState{
@@ -29,6 +33,7 @@ func buildCurrentState(dev NVMLDevice, driverVersion, cudaVersion string) (*gpuv
 			CUDAVersion:         cudaVersion,
 			PowerLimitCurrentW:  nvmlPowerLimit(dev.GetPowerManagementLimit),
 			PowerLimitEnforcedW: nvmlPowerLimit(dev.GetEnforcedPowerLimit),
+			PersistenceMode:     readPersistenceMode(dev),
 		},
 	}
 
@@ -38,3 +43,16 @@ func buildCurrentState(dev NVMLDevice, driverVersion, cudaVersion string) (*gpuv
 
 	return current, nil
 }
+
+// readPersistenceMode leaves the mode empty when NVML fails for another reason than lacking support.
+func readPersistenceMode(dev NVMLDevice) gpuv1alpha1.PersistenceModeState {
+	mode, ret := dev.GetPersistenceMode()
+	switch ret {
+	case nvml.SUCCESS:
+		return persistenceModeFromNVML(mode)
+	case nvml.ERROR_NOT_SUPPORTED:
+		return gpuv1alpha1.PersistenceModeNA
+	default:
+		return ""
+	}
+}
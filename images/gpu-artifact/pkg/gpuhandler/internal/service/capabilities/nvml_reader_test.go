@@ -26,6 +26,7 @@ import (
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
 	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	nvmlsvc "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/nvml"
 )
 
 func TestNVMLReaderSuccess(t *testing.T) {
@@ -417,6 +418,45 @@ func TestNVMLReaderPartialData(t *testing.T) {
 	}
 }
 
+func TestNVMLReaderPersistenceMode(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mode nvml.EnableState
+		ret  nvml.Return
+		want gpuv1alpha1.PersistenceModeState
+	}{
+		{name: "enabled", mode: nvml.FEATURE_ENABLED, ret: nvml.SUCCESS, want: gpuv1alpha1.PersistenceModeEnabled},
+		{name: "disabled", mode: nvml.FEATURE_DISABLED, ret: nvml.SUCCESS, want: gpuv1alpha1.PersistenceModeDisabled},
+		{name: "unsupported", ret: nvml.ERROR_NOT_SUPPORTED, want: gpuv1alpha1.PersistenceModeNA},
+		{name: "query failed", ret: nvml.ERROR_UNKNOWN, want: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dev := &fakeNVMLDevice{
+				name:           "NVIDIA A30",
+				migRet:         nvml.ERROR_NOT_SUPPORTED,
+				profileRet:     nvml.ERROR_NOT_SUPPORTED,
+				persistence:    tc.mode,
+				persistenceRet: tc.ret,
+			}
+			reader := NewNVMLReader(&fakeNVML{initRet: nvml.SUCCESS, device: dev, deviceRet: nvml.SUCCESS})
+
+			session, err := reader.Open()
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			defer session.Close()
+
+			snapshot, err := session.ReadDevice("0000:02:00.0")
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if got := snapshot.CurrentState.Nvidia.PersistenceMode; got != tc.want {
+				t.Fatalf("expected persistence mode %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestNVMLReaderMissingPCI(t *testing.T) {
 	reader := NewNVMLReader(&fakeNVML{initRet: nvml.SUCCESS})
 	session, err := reader.Open()
@@ -494,6 +534,8 @@ type fakeNVMLDevice struct {
 	profileRet     nvml.Return
 	placements     map[int][]nvml.GpuInstancePlacement
 	placementsRet  nvml.Return
+	persistence    nvml.EnableState
+	persistenceRet nvml.Return
 }
 
 func (d *fakeNVMLDevice) GetName() (string, nvml.Return) {
@@ -542,6 +584,15 @@ func (d *fakeNVMLDevice) GetMigMode() (int, int, nvml.Return) {
 	return d.migMode, 0, d.migRet
 }
 
+func (d *fakeNVMLDevice) GetPersistenceMode() (nvml.EnableState, nvml.Return) {
+	return d.persistence, d.persistenceRet
+}
+
+func (d *fakeNVMLDevice) SetPersistenceMode(mode nvml.EnableState) nvml.Return {
+	d.persistence = mode
+	return nvml.SUCCESS
+}
+
 func (d *fakeNVMLDevice) GetGpuInstanceProfileInfo(profile int) (nvml.GpuInstanceProfileInfo, nvml.Return) {
 	if info, ok := d.profileInfo[profile]; ok {
 		return nvml.GpuInstanceProfileInfo{
@@ -636,3 +687,27 @@ func profileInfoV2(id, sliceCount, instances, memoryMiB uint32, name string) nvm
 	copy(info.Name[:], []byte(name))
 	return info
 }
+
+func TestNVMLReaderThroughWatcherFlagsInvalidHandle(t *testing.T) {
+	lib := &fakeNVML{
+		initRet:       nvml.SUCCESS,
+		driverVersion: "580.76.05",
+		driverRet:     nvml.SUCCESS,
+		cudaRet:       nvml.SUCCESS,
+		deviceRet:     nvml.ERROR_UNINITIALIZED,
+	}
+	watcher := nvmlsvc.NewWatcher(lib)
+
+	session, err := NewNVMLReader(watcher).Open()
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer session.Close()
+
+	if _, err := session.ReadDevice("0000:02:00.0"); !errors.Is(err, ErrNVMLUnavailable) {
+		t.Fatalf("expected ErrNVMLUnavailable, got %v", err)
+	}
+	if !watcher.Invalidated() {
+		t.Fatalf("expected the watcher to record the invalid handle")
+	}
+}
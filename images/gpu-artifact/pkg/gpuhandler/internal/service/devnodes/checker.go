@@ -0,0 +1,233 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devnodes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultDevRoot  = "/dev"
+	defaultProcRoot = "/proc"
+
+	// nvidiaMajor is the fixed major of /dev/nvidiaN and /dev/nvidiactl.
+	nvidiaMajor    uint32 = 195
+	nvidiactlMinor uint32 = 255
+
+	uvmDeviceName  = "nvidia-uvm"
+	capsDeviceName = "nvidia-caps"
+)
+
+// Node is a character device node expected under the dev root.
+type Node struct {
+	// Path is relative to the dev root, for example "nvidia0" or "nvidia-caps/nvidia-cap1".
+	Path  string
+	Major uint32
+	Minor uint32
+	UVM   bool
+}
+
+// GPU identifies a GPU on the node for the check.
+type GPU struct {
+	PCIAddress string
+	MIGEnabled bool
+}
+
+// MknodFunc creates a character device node.
+type MknodFunc func(path string, major, minor uint32) error
+
+// Options configures the checker.
+type Options struct {
+	DevRoot  string
+	ProcRoot string
+	// Create enables mknod for missing nodes whose major is known.
+	Create bool
+	Mknod  MknodFunc
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Missing      []Node
+	Created      []string
+	CreateErrors []error
+}
+
+// Ready reports whether all expected nodes are present.
+func (r Result) Ready() bool {
+	return len(r.Missing) == 0
+}
+
+// OnlyUVMMissing reports whether the only missing nodes are the nvidia-uvm ones.
+func (r Result) OnlyUVMMissing() bool {
+	if len(r.Missing) == 0 {
+		return false
+	}
+	for _, node := range r.Missing {
+		if !node.UVM {
+			return false
+		}
+	}
+	return true
+}
+
+// MissingPaths returns the missing nodes as /dev paths.
+func (r Result) MissingPaths() []string {
+	paths := make([]string, 0, len(r.Missing))
+	for _, node := range r.Missing {
+		paths = append(paths, filepath.Join(defaultDevRoot, node.Path))
+	}
+	return paths
+}
+
+// Checker verifies NVIDIA device nodes for the GPUs of a node.
+type Checker struct {
+	devRoot  string
+	procRoot string
+	create   bool
+	mknod    MknodFunc
+}
+
+// NewChecker returns a checker configured with sensible defaults.
+func NewChecker(opts Options) *Checker {
+	devRoot := opts.DevRoot
+	if devRoot == "" {
+		devRoot = defaultDevRoot
+	}
+	procRoot := opts.ProcRoot
+	if procRoot == "" {
+		procRoot = defaultProcRoot
+	}
+	mknod := opts.Mknod
+	if mknod == nil {
+		mknod = mknodChar
+	}
+	return &Checker{
+		devRoot:  devRoot,
+		procRoot: procRoot,
+		create:   opts.Create,
+		mknod:    mknod,
+	}
+}
+
+// Check verifies that the device nodes for the given GPUs exist and creates missing ones
+// when enabled. An error means the expected set could not be resolved from procfs.
+func (c *Checker) Check(gpus []GPU) (Result, error) {
+	expected, err := c.Expected(gpus)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, node := range expected {
+		path := filepath.Join(c.devRoot, node.Path)
+		if _, err := os.Lstat(path); err == nil {
+			continue
+		}
+		if c.create && node.Major != 0 {
+			if err := c.createNode(path, node); err != nil {
+				result.CreateErrors = append(result.CreateErrors, err)
+			} else {
+				result.Created = append(result.Created, filepath.Join(defaultDevRoot, node.Path))
+				continue
+			}
+		}
+		result.Missing = append(result.Missing, node)
+	}
+	return result, nil
+}
+
+// Expected returns the device nodes the given GPUs need, ordered by path.
+func (c *Checker) Expected(gpus []GPU) ([]Node, error) {
+	if len(gpus) == 0 {
+		return nil, nil
+	}
+
+	majors, err := charMajors(c.procRoot)
+	if err != nil {
+		return nil, fmt.Errorf("read character device majors: %w", err)
+	}
+
+	nodes := map[string]Node{}
+	add := func(node Node) { nodes[node.Path] = node }
+
+	add(Node{Path: "nvidiactl", Major: nvidiaMajor, Minor: nvidiactlMinor})
+	add(Node{Path: uvmDeviceName, Major: majors[uvmDeviceName], Minor: 0, UVM: true})
+	add(Node{Path: uvmDeviceName + "-tools", Major: majors[uvmDeviceName], Minor: 1, UVM: true})
+
+	migEnabled := false
+	for _, gpu := range gpus {
+		minor, err := deviceMinor(c.procRoot, gpu.PCIAddress)
+		if err != nil {
+			return nil, fmt.Errorf("resolve device minor for %s: %w", gpu.PCIAddress, err)
+		}
+		add(Node{Path: fmt.Sprintf("nvidia%d", minor), Major: nvidiaMajor, Minor: minor})
+		if !gpu.MIGEnabled {
+			continue
+		}
+		migEnabled = true
+		files, err := migCapFiles(c.procRoot, minor)
+		if err != nil {
+			return nil, fmt.Errorf("list MIG capabilities for %s: %w", gpu.PCIAddress, err)
+		}
+		if err := c.addCaps(add, majors[capsDeviceName], files); err != nil {
+			return nil, err
+		}
+	}
+	if migEnabled {
+		global := []string{
+			filepath.Join(c.procRoot, "driver/nvidia/capabilities/mig/config"),
+			filepath.Join(c.procRoot, "driver/nvidia/capabilities/mig/monitor"),
+		}
+		if err := c.addCaps(add, majors[capsDeviceName], global); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		out = append(out, node)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+func (c *Checker) addCaps(add func(Node), major uint32, files []string) error {
+	for _, file := range files {
+		minor, err := capMinor(file)
+		if err != nil {
+			return fmt.Errorf("resolve capability minor: %w", err)
+		}
+		add(Node{Path: fmt.Sprintf("%s/nvidia-cap%d", capsDeviceName, minor), Major: major, Minor: minor})
+	}
+	return nil
+}
+
+func (c *Checker) createNode(path string, node Node) error {
+	if strings.Contains(node.Path, "/") {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+		}
+	}
+	if err := c.mknod(path, node.Major, node.Minor); err != nil {
+		return fmt.Errorf("mknod %s: %w", path, err)
+	}
+	return nil
+}
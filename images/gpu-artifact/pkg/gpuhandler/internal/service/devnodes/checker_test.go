@@ -0,0 +1,185 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devnodes
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testPCIAddress = "0000:02:00.0"
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func newProcRoot(t *testing.T, mig bool) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "devices"), "Character devices:\n  1 mem\n195 nvidia-frontend\n508 nvidia-caps\n509 nvidia-uvm\n\nBlock devices:\n  8 sd\n")
+	writeFile(t, filepath.Join(root, "driver/nvidia/gpus", testPCIAddress, "information"), "Model: \t\t NVIDIA A30\nDevice Minor: \t 3\n")
+	if mig {
+		caps := filepath.Join(root, "driver/nvidia/capabilities")
+		writeFile(t, filepath.Join(caps, "mig/config"), "DeviceFileMinor: 1\nDeviceFileMode: 256\n")
+		writeFile(t, filepath.Join(caps, "mig/monitor"), "DeviceFileMinor: 2\nDeviceFileMode: 292\n")
+		writeFile(t, filepath.Join(caps, "gpu3/mig/gi1/access"), "DeviceFileMinor: 30\n")
+		writeFile(t, filepath.Join(caps, "gpu3/mig/gi1/ci0/access"), "DeviceFileMinor: 31\n")
+	}
+	return root
+}
+
+func touchNodes(t *testing.T, devRoot string, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		writeFile(t, filepath.Join(devRoot, path), "")
+	}
+}
+
+func TestCheckAllPresent(t *testing.T) {
+	devRoot := t.TempDir()
+	touchNodes(t, devRoot, "nvidiactl", "nvidia3", "nvidia-uvm", "nvidia-uvm-tools",
+		"nvidia-caps/nvidia-cap1", "nvidia-caps/nvidia-cap2", "nvidia-caps/nvidia-cap30", "nvidia-caps/nvidia-cap31")
+	checker := NewChecker(Options{DevRoot: devRoot, ProcRoot: newProcRoot(t, true)})
+
+	result, err := checker.Check([]GPU{{PCIAddress: testPCIAddress, MIGEnabled: true}})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !result.Ready() {
+		t.Fatalf("expected all nodes present, missing %v", result.MissingPaths())
+	}
+}
+
+func TestCheckOnlyUVMMissing(t *testing.T) {
+	devRoot := t.TempDir()
+	touchNodes(t, devRoot, "nvidiactl", "nvidia3")
+	checker := NewChecker(Options{DevRoot: devRoot, ProcRoot: newProcRoot(t, false)})
+
+	result, err := checker.Check([]GPU{{PCIAddress: testPCIAddress}})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !result.OnlyUVMMissing() {
+		t.Fatalf("expected only uvm nodes missing, got %v", result.MissingPaths())
+	}
+	want := []string{"/dev/nvidia-uvm", "/dev/nvidia-uvm-tools"}
+	if got := result.MissingPaths(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("missing = %v, want %v", got, want)
+	}
+}
+
+func TestCheckEverythingMissing(t *testing.T) {
+	checker := NewChecker(Options{DevRoot: t.TempDir(), ProcRoot: newProcRoot(t, true)})
+
+	result, err := checker.Check([]GPU{{PCIAddress: testPCIAddress, MIGEnabled: true}})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if result.Ready() || result.OnlyUVMMissing() {
+		t.Fatalf("expected non-uvm nodes missing, got %v", result.MissingPaths())
+	}
+	if len(result.Missing) != 8 {
+		t.Fatalf("expected 8 missing nodes, got %v", result.MissingPaths())
+	}
+}
+
+func TestCheckCreatesMissingNodes(t *testing.T) {
+	devRoot := t.TempDir()
+	touchNodes(t, devRoot, "nvidiactl")
+	created := map[string][2]uint32{}
+	checker := NewChecker(Options{
+		DevRoot:  devRoot,
+		ProcRoot: newProcRoot(t, true),
+		Create:   true,
+		Mknod: func(path string, major, minor uint32) error {
+			rel, _ := filepath.Rel(devRoot, path)
+			created[rel] = [2]uint32{major, minor}
+			return os.WriteFile(path, nil, 0o644)
+		},
+	})
+
+	result, err := checker.Check([]GPU{{PCIAddress: testPCIAddress, MIGEnabled: true}})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !result.Ready() {
+		t.Fatalf("expected missing nodes to be created, missing %v", result.MissingPaths())
+	}
+	want := map[string][2]uint32{
+		"nvidia3":                  {195, 3},
+		"nvidia-uvm":               {509, 0},
+		"nvidia-uvm-tools":         {509, 1},
+		"nvidia-caps/nvidia-cap1":  {508, 1},
+		"nvidia-caps/nvidia-cap2":  {508, 2},
+		"nvidia-caps/nvidia-cap30": {508, 30},
+		"nvidia-caps/nvidia-cap31": {508, 31},
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Fatalf("created = %v, want %v", created, want)
+	}
+	if len(result.Created) != len(want) {
+		t.Fatalf("expected %d created paths, got %v", len(want), result.Created)
+	}
+
+	again, err := checker.Check([]GPU{{PCIAddress: testPCIAddress, MIGEnabled: true}})
+	if err != nil {
+		t.Fatalf("second check: %v", err)
+	}
+	if len(again.Created) != 0 || !again.Ready() {
+		t.Fatalf("expected idempotent second check, got %+v", again)
+	}
+}
+
+func TestCheckSkipsCreateWithoutMajor(t *testing.T) {
+	procRoot := newProcRoot(t, false)
+	writeFile(t, filepath.Join(procRoot, "devices"), "Character devices:\n195 nvidia-frontend\n")
+	devRoot := t.TempDir()
+	touchNodes(t, devRoot, "nvidiactl", "nvidia3")
+	checker := NewChecker(Options{
+		DevRoot:  devRoot,
+		ProcRoot: procRoot,
+		Create:   true,
+		Mknod: func(string, uint32, uint32) error {
+			t.Fatalf("mknod must not be called without a known major")
+			return nil
+		},
+	})
+
+	result, err := checker.Check([]GPU{{PCIAddress: testPCIAddress}})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if !result.OnlyUVMMissing() {
+		t.Fatalf("expected uvm nodes to stay missing, got %v", result.MissingPaths())
+	}
+}
+
+func TestCheckUnknownMinor(t *testing.T) {
+	checker := NewChecker(Options{DevRoot: t.TempDir(), ProcRoot: newProcRoot(t, false)})
+
+	if _, err := checker.Check([]GPU{{PCIAddress: "0000:03:00.0"}}); err == nil {
+		t.Fatalf("expected error for GPU without driver procfs entry")
+	}
+}
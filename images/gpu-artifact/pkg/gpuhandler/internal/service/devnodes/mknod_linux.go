@@ -0,0 +1,33 @@
+//go:build linux
+
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devnodes
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func mknodChar(path string, major, minor uint32) error {
+	if err := unix.Mknod(path, unix.S_IFCHR|0o666, int(unix.Mkdev(major, minor))); err != nil {
+		return err
+	}
+	// Mknod honours the umask; containers need the node world-accessible like udev creates it.
+	return os.Chmod(path, 0o666)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devnodes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	deviceMinorKey     = "Device Minor"
+	deviceFileMinorKey = "DeviceFileMinor"
+)
+
+// deviceMinor resolves the /dev/nvidiaN minor for a GPU from the driver procfs entry.
+func deviceMinor(procRoot, pciAddress string) (uint32, error) {
+	path := filepath.Join(procRoot, "driver/nvidia/gpus", strings.ToLower(pciAddress), "information")
+	value, err := readKey(path, deviceMinorKey)
+	if err != nil {
+		return 0, err
+	}
+	return parseUint32(path, value)
+}
+
+// capMinor resolves the nvidia-caps minor from a capability access file.
+func capMinor(path string) (uint32, error) {
+	value, err := readKey(path, deviceFileMinorKey)
+	if err != nil {
+		return 0, err
+	}
+	return parseUint32(path, value)
+}
+
+// migCapFiles lists the capability access files for the MIG instances of a GPU.
+func migCapFiles(procRoot string, minor uint32) ([]string, error) {
+	base := filepath.Join(procRoot, "driver/nvidia/capabilities", fmt.Sprintf("gpu%d", minor), "mig")
+	var files []string
+	for _, pattern := range []string{"gi*/access", "gi*/ci*/access"} {
+		matches, err := filepath.Glob(filepath.Join(base, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// charMajors reads character device majors from /proc/devices.
+func charMajors(procRoot string) (map[string]uint32, error) {
+	f, err := os.Open(filepath.Join(procRoot, "devices"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	majors := map[string]uint32{}
+	inChar := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasSuffix(line, "devices:"):
+			inChar = line == "Character devices:"
+			continue
+		case !inChar:
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		major, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			continue
+		}
+		majors[fields[1]] = uint32(major)
+	}
+	return majors, scanner.Err()
+}
+
+func readKey(path, key string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(name) == key {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", fmt.Errorf("%s: %q not found", path, key)
+}
+
+func parseUint32(path, value string) (uint32, error) {
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: parse %q: %w", path, value, err)
+	}
+	return uint32(v), nil
+}
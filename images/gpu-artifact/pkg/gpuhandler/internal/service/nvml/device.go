@@ -65,6 +65,14 @@ func (d nvmlDevice) GetMigMode() (int, int, nvmlapi.Return) {
 	return d.device.GetMigMode()
 }
 
+func (d nvmlDevice) GetPersistenceMode() (nvmlapi.EnableState, nvmlapi.Return) {
+	return d.device.GetPersistenceMode()
+}
+
+func (d nvmlDevice) SetPersistenceMode(mode nvmlapi.EnableState) nvmlapi.Return {
+	return d.device.SetPersistenceMode(mode)
+}
+
 func (d nvmlDevice) GetGpuInstanceProfileInfo(profile int) (nvmlapi.GpuInstanceProfileInfo, nvmlapi.Return) {
 	return d.device.GetGpuInstanceProfileInfo(profile)
 }
@@ -43,6 +43,8 @@ type NVMLDevice interface {
 	GetEnforcedPowerLimit() (uint32, nvmlapi.Return)
 	GetPowerManagementLimitConstraints() (uint32, uint32, nvmlapi.Return)
 	GetMigMode() (int, int, nvmlapi.Return)
+	GetPersistenceMode() (nvmlapi.EnableState, nvmlapi.Return)
+	SetPersistenceMode(mode nvmlapi.EnableState) nvmlapi.Return
 	GetGpuInstanceProfileInfo(profile int) (nvmlapi.GpuInstanceProfileInfo, nvmlapi.Return)
 	GetGpuInstanceProfileInfoV2(profile int) (nvmlapi.GpuInstanceProfileInfo_v2, nvmlapi.Return)
 	GetGpuInstanceProfileInfoV3(profile int) (nvmlapi.GpuInstanceProfileInfo_v3, nvmlapi.Return)
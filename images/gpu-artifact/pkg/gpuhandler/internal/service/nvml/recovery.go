@@ -0,0 +1,172 @@
+//go:build linux && cgo && nvml
+// +build linux,cgo,nvml
+
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nvml
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	nvmlapi "github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+const (
+	defaultRecoveryAttempts = 6
+	defaultRecoveryDelay    = 2 * time.Second
+	maxRecoveryDelay        = time.Minute
+	// maxShutdownCalls bounds the teardown loop that drops leaked NVML references.
+	maxShutdownCalls = 16
+)
+
+// HandleInvalid reports NVML codes meaning the loaded library no longer talks to a live driver,
+// which is what every call returns after the driver is reloaded underneath the process.
+func HandleInvalid(ret nvmlapi.Return) bool {
+	switch ret {
+	case nvmlapi.ERROR_UNINITIALIZED, nvmlapi.ERROR_LIB_RM_VERSION_MISMATCH:
+		return true
+	default:
+		return false
+	}
+}
+
+// Watcher wraps an NVML and remembers whether any call reported an invalid handle.
+type Watcher struct {
+	NVML
+	invalid atomic.Bool
+}
+
+// NewWatcher wraps lib.
+func NewWatcher(lib NVML) *Watcher {
+	return &Watcher{NVML: lib}
+}
+
+// Invalidated reports whether a call failed with an invalid handle since the last recovery.
+func (w *Watcher) Invalidated() bool {
+	return w.invalid.Load()
+}
+
+func (w *Watcher) observe(ret nvmlapi.Return) nvmlapi.Return {
+	if HandleInvalid(ret) {
+		w.invalid.Store(true)
+	}
+	return ret
+}
+
+func (w *Watcher) Init() nvmlapi.Return {
+	return w.observe(w.NVML.Init())
+}
+
+func (w *Watcher) SystemGetDriverVersion() (string, nvmlapi.Return) {
+	version, ret := w.NVML.SystemGetDriverVersion()
+	return version, w.observe(ret)
+}
+
+func (w *Watcher) SystemGetCudaDriverVersion() (int, nvmlapi.Return) {
+	version, ret := w.NVML.SystemGetCudaDriverVersion()
+	return version, w.observe(ret)
+}
+
+func (w *Watcher) DeviceByPCI(pciBusID string) (NVMLDevice, nvmlapi.Return) {
+	dev, ret := w.NVML.DeviceByPCI(pciBusID)
+	return dev, w.observe(ret)
+}
+
+// Recovery re-initializes NVML after the watcher saw an invalid handle.
+type Recovery struct {
+	watcher  *Watcher
+	attempts int
+	delay    time.Duration
+	sleep    func(context.Context, time.Duration) error
+	failed   atomic.Bool
+}
+
+// NewRecovery constructs a Recovery with the default attempt budget.
+func NewRecovery(w *Watcher) *Recovery {
+	return &Recovery{
+		watcher:  w,
+		attempts: defaultRecoveryAttempts,
+		delay:    defaultRecoveryDelay,
+		sleep:    sleepContext,
+	}
+}
+
+// Healthy is false once recovery gave up; only a process restart loads a fresh library then.
+func (r *Recovery) Healthy() bool {
+	return !r.failed.Load()
+}
+
+// Recover tears NVML down and re-initializes it with exponential backoff. It returns the number of
+// attempts it took, or an error once every attempt failed and the recovery is marked unhealthy.
+func (r *Recovery) Recover(ctx context.Context) (int, error) {
+	lib := r.watcher.NVML
+	delay := r.delay
+	var lastErr error
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		drain(lib)
+		lastErr = probe(lib)
+		if lastErr == nil {
+			r.watcher.invalid.Store(false)
+			r.failed.Store(false)
+			return attempt, nil
+		}
+		if attempt == r.attempts {
+			break
+		}
+		if err := r.sleep(ctx, delay); err != nil {
+			return attempt, err
+		}
+		delay = min(delay*2, maxRecoveryDelay)
+	}
+	r.failed.Store(true)
+	return r.attempts, fmt.Errorf("NVML re-initialization failed after %d attempts: %w", r.attempts, lastErr)
+}
+
+// drain drops every NVML reference. Sessions pair Init with Shutdown, so only a leaked reference can
+// keep the stale library loaded; once the count reaches zero the next Init loads it again.
+func drain(lib NVML) {
+	for i := 0; i < maxShutdownCalls; i++ {
+		if lib.Shutdown() != nvmlapi.SUCCESS {
+			return
+		}
+	}
+}
+
+func probe(lib NVML) error {
+	if ret := lib.Init(); ret != nvmlapi.SUCCESS && ret != nvmlapi.ERROR_ALREADY_INITIALIZED {
+		return fmt.Errorf("init: %s", lib.ErrorString(ret))
+	}
+	defer lib.Shutdown()
+	if _, ret := lib.SystemGetDriverVersion(); ret != nvmlapi.SUCCESS {
+		return fmt.Errorf("driver version: %s", lib.ErrorString(ret))
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
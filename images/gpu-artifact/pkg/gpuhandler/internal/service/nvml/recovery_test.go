@@ -0,0 +1,192 @@
+//go:build linux && cgo && nvml
+// +build linux,cgo,nvml
+
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nvml
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	nvmlapi "github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// scriptedNVML replays Init results in order and tracks the NVML reference count.
+type scriptedNVML struct {
+	initRets  []nvmlapi.Return
+	driverRet nvmlapi.Return
+	refs      int
+	inits     int
+}
+
+func (s *scriptedNVML) Init() nvmlapi.Return {
+	ret := nvmlapi.SUCCESS
+	if s.inits < len(s.initRets) {
+		ret = s.initRets[s.inits]
+	}
+	s.inits++
+	if ret == nvmlapi.SUCCESS {
+		s.refs++
+	}
+	return ret
+}
+
+func (s *scriptedNVML) Shutdown() nvmlapi.Return {
+	if s.refs == 0 {
+		return nvmlapi.ERROR_UNINITIALIZED
+	}
+	s.refs--
+	return nvmlapi.SUCCESS
+}
+
+func (s *scriptedNVML) SystemGetDriverVersion() (string, nvmlapi.Return) {
+	if s.refs == 0 {
+		return "", nvmlapi.ERROR_UNINITIALIZED
+	}
+	return "580.76.05", s.driverRet
+}
+
+func (s *scriptedNVML) SystemGetCudaDriverVersion() (int, nvmlapi.Return) {
+	return 13000, nvmlapi.SUCCESS
+}
+
+func (s *scriptedNVML) DeviceByPCI(string) (NVMLDevice, nvmlapi.Return) {
+	return nil, s.driverRet
+}
+
+func (s *scriptedNVML) ErrorString(ret nvmlapi.Return) string {
+	return ret.String()
+}
+
+func newTestRecovery(lib NVML) (*Watcher, *Recovery, *[]time.Duration) {
+	watcher := NewWatcher(lib)
+	recovery := NewRecovery(watcher)
+	var sleeps []time.Duration
+	recovery.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return watcher, recovery, &sleeps
+}
+
+func TestWatcherDetectsInvalidHandle(t *testing.T) {
+	lib := &scriptedNVML{refs: 1, driverRet: nvmlapi.SUCCESS}
+	watcher := NewWatcher(lib)
+
+	if _, ret := watcher.SystemGetDriverVersion(); ret != nvmlapi.SUCCESS || watcher.Invalidated() {
+		t.Fatalf("expected healthy handle, got %v invalidated=%v", ret, watcher.Invalidated())
+	}
+	if _, ret := watcher.DeviceByPCI("0000:02:00.0"); ret != nvmlapi.SUCCESS {
+		t.Fatalf("unexpected device lookup result %v", ret)
+	}
+
+	lib.driverRet = nvmlapi.ERROR_GPU_IS_LOST
+	watcher.DeviceByPCI("0000:02:00.0")
+	if watcher.Invalidated() {
+		t.Fatalf("a lost GPU must not trigger re-initialization")
+	}
+
+	lib.driverRet = nvmlapi.ERROR_UNINITIALIZED
+	watcher.DeviceByPCI("0000:02:00.0")
+	if !watcher.Invalidated() {
+		t.Fatalf("expected ERROR_UNINITIALIZED to invalidate the handle")
+	}
+}
+
+func TestRecoveryReinitializesWithBackoff(t *testing.T) {
+	// A leaked reference keeps the stale library loaded; the driver comes back on the third Init.
+	lib := &scriptedNVML{
+		refs:      2,
+		driverRet: nvmlapi.SUCCESS,
+		initRets:  []nvmlapi.Return{nvmlapi.ERROR_DRIVER_NOT_LOADED, nvmlapi.ERROR_DRIVER_NOT_LOADED, nvmlapi.SUCCESS},
+	}
+	watcher, recovery, sleeps := newTestRecovery(lib)
+	watcher.DeviceByPCI("")
+	lib.driverRet = nvmlapi.ERROR_UNINITIALIZED
+	watcher.DeviceByPCI("")
+	lib.driverRet = nvmlapi.SUCCESS
+
+	attempts, err := recovery.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Recover returned error: %v", err)
+	}
+	if attempts != 3 || lib.inits != 3 {
+		t.Fatalf("expected success on the third attempt, got attempts=%d inits=%d", attempts, lib.inits)
+	}
+	if want := []time.Duration{2 * time.Second, 4 * time.Second}; !reflect.DeepEqual(*sleeps, want) {
+		t.Fatalf("unexpected backoff %v, want %v", *sleeps, want)
+	}
+	if lib.refs != 0 {
+		t.Fatalf("expected leaked references to be drained and the probe to shut down, got %d refs", lib.refs)
+	}
+	if watcher.Invalidated() || !recovery.Healthy() {
+		t.Fatalf("expected a healthy handle after recovery")
+	}
+}
+
+func TestRecoveryGivesUpAndReportsUnhealthy(t *testing.T) {
+	lib := &scriptedNVML{driverRet: nvmlapi.ERROR_LIB_RM_VERSION_MISMATCH}
+	watcher, recovery, sleeps := newTestRecovery(lib)
+	watcher.DeviceByPCI("")
+
+	attempts, err := recovery.Recover(context.Background())
+	if err == nil {
+		t.Fatalf("expected recovery to fail while the library mismatches the driver")
+	}
+	if attempts != defaultRecoveryAttempts || len(*sleeps) != defaultRecoveryAttempts-1 {
+		t.Fatalf("expected %d attempts, got %d with %d sleeps", defaultRecoveryAttempts, attempts, len(*sleeps))
+	}
+	if last := (*sleeps)[len(*sleeps)-1]; last != 32*time.Second {
+		t.Fatalf("unexpected final backoff %v", last)
+	}
+	if recovery.Healthy() || !watcher.Invalidated() {
+		t.Fatalf("expected unhealthy recovery with the handle still invalid")
+	}
+}
+
+func TestRecoveryBackoffIsCapped(t *testing.T) {
+	lib := &scriptedNVML{initRets: make([]nvmlapi.Return, 10)}
+	for i := range lib.initRets {
+		lib.initRets[i] = nvmlapi.ERROR_DRIVER_NOT_LOADED
+	}
+	_, recovery, sleeps := newTestRecovery(lib)
+	recovery.attempts = 8
+
+	if _, err := recovery.Recover(context.Background()); err == nil {
+		t.Fatalf("expected recovery to fail")
+	}
+	if last := (*sleeps)[len(*sleeps)-1]; last != maxRecoveryDelay {
+		t.Fatalf("expected backoff to be capped at %v, got %v", maxRecoveryDelay, last)
+	}
+}
+
+func TestRecoveryStopsOnContextCancel(t *testing.T) {
+	lib := &scriptedNVML{initRets: []nvmlapi.Return{nvmlapi.ERROR_DRIVER_NOT_LOADED}}
+	_, recovery, _ := newTestRecovery(lib)
+	recovery.sleep = func(context.Context, time.Duration) error { return context.Canceled }
+
+	if _, err := recovery.Recover(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation, got %v", err)
+	}
+	if !recovery.Healthy() {
+		t.Fatalf("cancellation must not mark the handler unhealthy")
+	}
+}
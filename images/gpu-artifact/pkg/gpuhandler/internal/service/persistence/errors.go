@@ -0,0 +1,26 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistence
+
+import "errors"
+
+var (
+	// ErrDaemonRequired reports drivers that only switch persistence mode through nvidia-persistenced.
+	ErrDaemonRequired = errors.New("persistence daemon required")
+	// ErrEnableFailed reports any other failure to turn persistence mode on.
+	ErrEnableFailed = errors.New("enable persistence mode failed")
+)
@@ -0,0 +1,71 @@
+//go:build linux && cgo && nvml
+// +build linux,cgo,nvml
+
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistence
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	nvmlsvc "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/nvml"
+)
+
+// NVMLEnabler switches persistence mode on with nvmlDeviceSetPersistenceMode.
+type NVMLEnabler struct {
+	nvml nvmlsvc.NVML
+}
+
+// NewNVMLEnabler constructs an enabler for NVML.
+func NewNVMLEnabler(lib nvmlsvc.NVML) *NVMLEnabler {
+	return &NVMLEnabler{nvml: lib}
+}
+
+// Enable turns persistence mode on for the GPU at pciAddress. It reports whether the mode was changed,
+// so a GPU that already runs with persistence mode is left untouched.
+func (e *NVMLEnabler) Enable(pciAddress string) (bool, error) {
+	if e == nil || e.nvml == nil {
+		return false, fmt.Errorf("%w: NVML is not configured", ErrEnableFailed)
+	}
+
+	ret := e.nvml.Init()
+	if ret != nvml.SUCCESS && ret != nvml.ERROR_ALREADY_INITIALIZED {
+		return false, fmt.Errorf("%w: NVML init failed: %s", ErrEnableFailed, e.nvml.ErrorString(ret))
+	}
+	defer e.nvml.Shutdown()
+
+	dev, ret := e.nvml.DeviceByPCI(pciAddress)
+	if ret != nvml.SUCCESS {
+		return false, fmt.Errorf("%w: NVML device lookup failed: %s", ErrEnableFailed, e.nvml.ErrorString(ret))
+	}
+
+	if mode, ret := dev.GetPersistenceMode(); ret == nvml.SUCCESS && mode == nvml.FEATURE_ENABLED {
+		return false, nil
+	}
+
+	switch ret := dev.SetPersistenceMode(nvml.FEATURE_ENABLED); ret {
+	case nvml.SUCCESS:
+		return true, nil
+	case nvml.ERROR_NOT_SUPPORTED:
+		// Drivers that dropped the legacy ioctl keep the device initialized only while nvidia-persistenced holds it open.
+		return false, fmt.Errorf("%w: the driver manages persistence mode through nvidia-persistenced", ErrDaemonRequired)
+	default:
+		return false, fmt.Errorf("%w: %s", ErrEnableFailed, e.nvml.ErrorString(ret))
+	}
+}
@@ -0,0 +1,127 @@
+//go:build linux && cgo && nvml
+// +build linux,cgo,nvml
+
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistence
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	nvmlsvc "github.com/aleksandr-podmoskovniy/gpu/pkg/gpuhandler/internal/service/nvml"
+)
+
+func TestNVMLEnablerAlreadyEnabled(t *testing.T) {
+	dev := &fakeDevice{mode: nvml.FEATURE_ENABLED}
+	lib := &fakeNVML{device: dev}
+
+	changed, err := NewNVMLEnabler(lib).Enable("0000:02:00.0")
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if changed || dev.setCalls != 0 {
+		t.Fatalf("expected an enabled GPU to be left alone, changed=%v set calls=%d", changed, dev.setCalls)
+	}
+	if lib.shutdowns != 1 {
+		t.Fatalf("expected NVML to be shut down once, got %d", lib.shutdowns)
+	}
+}
+
+func TestNVMLEnablerTurnsModeOn(t *testing.T) {
+	dev := &fakeDevice{mode: nvml.FEATURE_DISABLED, setRet: nvml.SUCCESS}
+
+	changed, err := NewNVMLEnabler(&fakeNVML{device: dev}).Enable("0000:02:00.0")
+	if err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if !changed || dev.mode != nvml.FEATURE_ENABLED || dev.setCalls != 1 {
+		t.Fatalf("expected persistence mode to be turned on once, changed=%v mode=%v set calls=%d", changed, dev.mode, dev.setCalls)
+	}
+}
+
+func TestNVMLEnablerReportsDaemonRequired(t *testing.T) {
+	dev := &fakeDevice{mode: nvml.FEATURE_DISABLED, setRet: nvml.ERROR_NOT_SUPPORTED}
+
+	changed, err := NewNVMLEnabler(&fakeNVML{device: dev}).Enable("0000:02:00.0")
+	if !errors.Is(err, ErrDaemonRequired) {
+		t.Fatalf("expected ErrDaemonRequired, got %v", err)
+	}
+	if changed {
+		t.Fatalf("expected no change on failure")
+	}
+}
+
+func TestNVMLEnablerReportsOtherFailures(t *testing.T) {
+	_, err := NewNVMLEnabler(&fakeNVML{device: &fakeDevice{setRet: nvml.ERROR_NO_PERMISSION}}).Enable("0000:02:00.0")
+	if !errors.Is(err, ErrEnableFailed) || errors.Is(err, ErrDaemonRequired) {
+		t.Fatalf("expected ErrEnableFailed, got %v", err)
+	}
+
+	_, err = NewNVMLEnabler(&fakeNVML{deviceRet: nvml.ERROR_NOT_FOUND}).Enable("0000:02:00.0")
+	if !errors.Is(err, ErrEnableFailed) {
+		t.Fatalf("expected ErrEnableFailed for a missing device, got %v", err)
+	}
+}
+
+type fakeNVML struct {
+	nvmlsvc.NVML
+	device    *fakeDevice
+	deviceRet nvml.Return
+	shutdowns int
+}
+
+func (f *fakeNVML) Init() nvml.Return {
+	return nvml.SUCCESS
+}
+
+func (f *fakeNVML) Shutdown() nvml.Return {
+	f.shutdowns++
+	return nvml.SUCCESS
+}
+
+func (f *fakeNVML) DeviceByPCI(_ string) (nvmlsvc.NVMLDevice, nvml.Return) {
+	if f.deviceRet != nvml.SUCCESS {
+		return nil, f.deviceRet
+	}
+	return f.device, nvml.SUCCESS
+}
+
+func (f *fakeNVML) ErrorString(ret nvml.Return) string {
+	return ret.String()
+}
+
+type fakeDevice struct {
+	nvmlsvc.NVMLDevice
+	mode     nvml.EnableState
+	setRet   nvml.Return
+	setCalls int
+}
+
+func (d *fakeDevice) GetPersistenceMode() (nvml.EnableState, nvml.Return) {
+	return d.mode, nvml.SUCCESS
+}
+
+func (d *fakeDevice) SetPersistenceMode(mode nvml.EnableState) nvml.Return {
+	d.setCalls++
+	if d.setRet == nvml.SUCCESS {
+		d.mode = mode
+	}
+	return d.setRet
+}
@@ -0,0 +1,62 @@
+//go:build linux && cgo && nvml
+// +build linux,cgo,nvml
+
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuhandler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+)
+
+const reasonDriverReinitialized = "DriverReinitialized"
+
+// recoverNVML runs after a sync saw an invalid NVML handle, typically because the driver DaemonSet
+// reloaded the driver. On success devices are re-enumerated by the next sync.
+func (a *Agent) recoverNVML(ctx context.Context) error {
+	a.log.Warn("NVML handle is invalid, re-initializing")
+	attempts, err := a.recovery.Recover(ctx)
+	if err != nil {
+		return fmt.Errorf("recover NVML: %w", err)
+	}
+	a.log.Info("NVML re-initialized", "attempts", attempts)
+	a.recordReinitialized(ctx, attempts)
+	if a.notify != nil {
+		a.notify()
+	}
+	return nil
+}
+
+func (a *Agent) recordReinitialized(ctx context.Context, attempts int) {
+	if a.recorder == nil || a.store == nil {
+		return
+	}
+	gpus, err := a.store.ListByNode(ctx, a.cfg.NodeName)
+	if err != nil {
+		a.log.Warn("unable to load PhysicalGPU for NVML re-initialization event", logger.SlogErr(err))
+		return
+	}
+	msg := fmt.Sprintf("NVML re-initialized after %d attempt(s)", attempts)
+	for i := range gpus {
+		a.recorder.Event(&gpus[i], corev1.EventTypeNormal, reasonDriverReinitialized, msg)
+	}
+}
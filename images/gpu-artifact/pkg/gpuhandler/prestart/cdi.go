@@ -0,0 +1,279 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prestart
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	cdiapi "tags.cncf.io/container-device-interface/pkg/cdi"
+	cdispec "tags.cncf.io/container-device-interface/specs-go"
+)
+
+const (
+	defaultCDISpecPath = "/var/run/cdi/gpu.deckhouse.io.json"
+	defaultDevRoot     = "/dev"
+	defaultProcRoot    = "/proc"
+
+	cdiKind = "gpu.deckhouse.io/gpu"
+)
+
+var (
+	gpuDeviceNodePattern = regexp.MustCompile(`^nvidia([0-9]+)$`)
+	migGIPattern         = regexp.MustCompile(`^gi([0-9]+)$`)
+	migCIPattern         = regexp.MustCompile(`^ci([0-9]+)$`)
+)
+
+// controlDeviceNodes are shared by every GPU and injected once through the spec-level edits.
+var controlDeviceNodes = []string{"nvidiactl", "nvidia-uvm", "nvidia-uvm-tools"}
+
+// driverLibraries are the user-space driver libraries a CUDA workload needs; the ones found are bind-mounted.
+var driverLibraries = []string{
+	"libcuda.so.1",
+	"libnvidia-ml.so.1",
+	"libnvidia-ptxjitcompiler.so.1",
+	"libnvidia-nvvm.so.4",
+}
+
+var driverMountOptions = []string{"ro", "nosuid", "nodev", "bind"}
+
+// writeCDISpec regenerates the CDI spec and replaces the file only when its content changed, so a restart on
+// unchanged hardware leaves it untouched. The new spec is validated before it atomically replaces the old one.
+func (r *Runner) writeCDISpec() error {
+	spec, err := r.buildCDISpec()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode CDI spec: %w", err)
+	}
+	data = append(data, '\n')
+
+	if current, err := r.fs.ReadFile(r.cdiSpecPath); err == nil && bytes.Equal(current, data) {
+		r.logf("CDI spec %s is up to date (%d devices)\n", r.cdiSpecPath, len(spec.Devices))
+		return nil
+	}
+
+	dir := filepath.Dir(r.cdiSpecPath)
+	if err := r.fs.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create CDI spec directory %s: %w", dir, err)
+	}
+	tmp := filepath.Join(dir, "."+filepath.Base(r.cdiSpecPath)+".tmp")
+	if err := r.fs.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write CDI spec %s: %w", tmp, err)
+	}
+	if _, err := cdiapi.ReadSpec(tmp, 0); err != nil {
+		_ = r.fs.Remove(tmp)
+		return fmt.Errorf("validate CDI spec: %w", err)
+	}
+	if err := r.fs.Rename(tmp, r.cdiSpecPath); err != nil {
+		_ = r.fs.Remove(tmp)
+		return fmt.Errorf("replace CDI spec %s: %w", r.cdiSpecPath, err)
+	}
+	r.logf("CDI spec %s written (%d devices)\n", r.cdiSpecPath, len(spec.Devices))
+	return nil
+}
+
+// buildCDISpec enumerates the GPUs under the device root and the MIG instances the driver publishes in its
+// capabilities tree. Library mounts point at the host driver root, as the spec is consumed by the host runtime.
+func (r *Runner) buildCDISpec() (*cdispec.Spec, error) {
+	minors, err := r.gpuMinors()
+	if err != nil {
+		return nil, err
+	}
+	if len(minors) == 0 {
+		return nil, fmt.Errorf("no GPU device nodes found in %s", r.devRoot)
+	}
+
+	spec := &cdispec.Spec{
+		Kind: cdiKind,
+		ContainerEdits: cdispec.ContainerEdits{
+			Env: []string{"NVIDIA_VISIBLE_DEVICES=void"},
+		},
+	}
+	for _, name := range controlDeviceNodes {
+		if r.exists(filepath.Join(r.devRoot, name)) {
+			spec.ContainerEdits.DeviceNodes = append(spec.ContainerEdits.DeviceNodes, &cdispec.DeviceNode{Path: "/dev/" + name})
+		}
+	}
+	spec.ContainerEdits.Mounts = r.driverMounts()
+
+	for _, minor := range minors {
+		gpuNode := &cdispec.DeviceNode{Path: fmt.Sprintf("/dev/nvidia%d", minor)}
+		spec.Devices = append(spec.Devices, cdispec.Device{
+			Name: strconv.Itoa(minor),
+			ContainerEdits: cdispec.ContainerEdits{
+				DeviceNodes: []*cdispec.DeviceNode{gpuNode},
+				Env:         []string{fmt.Sprintf("GPU_DECKHOUSE_IO_MINOR=%d", minor)},
+			},
+		})
+
+		instances, err := r.migInstances(minor)
+		if err != nil {
+			return nil, err
+		}
+		for _, mig := range instances {
+			spec.Devices = append(spec.Devices, cdispec.Device{
+				Name: fmt.Sprintf("%d-mig-%d-%d", minor, mig.gi, mig.ci),
+				ContainerEdits: cdispec.ContainerEdits{
+					DeviceNodes: []*cdispec.DeviceNode{
+						{Path: gpuNode.Path},
+						{Path: mig.giPath},
+						{Path: mig.ciPath},
+					},
+					Env: []string{fmt.Sprintf("GPU_DECKHOUSE_IO_MINOR=%d", minor)},
+				},
+			})
+		}
+	}
+
+	version, err := cdispec.MinimumRequiredVersion(spec)
+	if err != nil {
+		return nil, fmt.Errorf("detect CDI spec version: %w", err)
+	}
+	spec.Version = version
+	return spec, nil
+}
+
+func (r *Runner) gpuMinors() ([]int, error) {
+	entries, err := r.fs.ReadDir(r.devRoot)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", r.devRoot, err)
+	}
+	var minors []int
+	for _, entry := range entries {
+		match := gpuDeviceNodePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		minor, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		minors = append(minors, minor)
+	}
+	sort.Ints(minors)
+	return minors, nil
+}
+
+func (r *Runner) driverMounts() []*cdispec.Mount {
+	var mounts []*cdispec.Mount
+	if smi := r.findFirstFile(nvidiaSMIRelDirs, "nvidia-smi"); smi != "" {
+		mounts = append(mounts, r.driverMount(smi))
+	}
+	for _, lib := range driverLibraries {
+		if path := r.findFirstFile(nvmlLibRelDirs, lib); path != "" {
+			mounts = append(mounts, r.driverMount(path))
+		}
+	}
+	return mounts
+}
+
+// driverMount maps a file found under the driver root mount to the host path and the path the container expects.
+func (r *Runner) driverMount(mounted string) *cdispec.Mount {
+	rel := strings.TrimPrefix(mounted, strings.TrimSuffix(r.driverRootMount, "/"))
+	return &cdispec.Mount{
+		HostPath:      filepath.Join(r.driverRoot, rel),
+		ContainerPath: filepath.Join("/", rel),
+		Options:       driverMountOptions,
+	}
+}
+
+type migInstance struct {
+	gi, ci         int
+	giPath, ciPath string
+}
+
+// migInstances reads the compute instances of a GPU from the driver capabilities tree; each access file names the
+// minor of the nvidia-caps device node guarding it. A GPU without MIG has no mig directory.
+func (r *Runner) migInstances(minor int) ([]migInstance, error) {
+	migDir := filepath.Join(r.procRoot, "driver/nvidia/capabilities", fmt.Sprintf("gpu%d", minor), "mig")
+	giEntries, err := r.fs.ReadDir(migDir)
+	if err != nil {
+		return nil, nil
+	}
+	var instances []migInstance
+	for _, giEntry := range giEntries {
+		giMatch := migGIPattern.FindStringSubmatch(giEntry.Name())
+		if giMatch == nil {
+			continue
+		}
+		gi, _ := strconv.Atoi(giMatch[1])
+		giDir := filepath.Join(migDir, giEntry.Name())
+		giPath, err := r.nvCapDevicePath(filepath.Join(giDir, "access"))
+		if err != nil {
+			return nil, err
+		}
+		ciEntries, err := r.fs.ReadDir(giDir)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", giDir, err)
+		}
+		for _, ciEntry := range ciEntries {
+			ciMatch := migCIPattern.FindStringSubmatch(ciEntry.Name())
+			if ciMatch == nil {
+				continue
+			}
+			ci, _ := strconv.Atoi(ciMatch[1])
+			ciPath, err := r.nvCapDevicePath(filepath.Join(giDir, ciEntry.Name(), "access"))
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, migInstance{gi: gi, ci: ci, giPath: giPath, ciPath: ciPath})
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].gi != instances[j].gi {
+			return instances[i].gi < instances[j].gi
+		}
+		return instances[i].ci < instances[j].ci
+	})
+	return instances, nil
+}
+
+func (r *Runner) nvCapDevicePath(accessFile string) (string, error) {
+	data, err := r.fs.ReadFile(accessFile)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", accessFile, err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "DeviceFileMinor" {
+			continue
+		}
+		minor, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("parse DeviceFileMinor in %s: %w", accessFile, err)
+		}
+		return fmt.Sprintf("/dev/nvidia-caps/nvidia-cap%d", minor), nil
+	}
+	return "", errors.New("DeviceFileMinor not found in " + accessFile)
+}
+
+func (r *Runner) exists(path string) bool {
+	_, err := r.fs.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prestart
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	cdispec "tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestWriteCDISpecGPUAndMIG(t *testing.T) {
+	t.Parallel()
+
+	runner, devRoot, specPath, out := newCDIRunner(t)
+	writeFile(t, filepath.Join(devRoot, "nvidia1"))
+
+	if err := runner.writeCDISpec(); err != nil {
+		t.Fatalf("write CDI spec: %v", err)
+	}
+
+	spec := readCDISpec(t, specPath)
+	if spec.Kind != cdiKind {
+		t.Fatalf("unexpected kind %q", spec.Kind)
+	}
+
+	var names []string
+	for _, dev := range spec.Devices {
+		names = append(names, dev.Name)
+	}
+	if want := []string{"0", "0-mig-1-0", "1"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected devices %v, want %v", names, want)
+	}
+	if got := deviceNodePaths(spec.Devices[0].ContainerEdits.DeviceNodes); !reflect.DeepEqual(got, []string{"/dev/nvidia0"}) {
+		t.Fatalf("unexpected GPU device nodes %v", got)
+	}
+	wantMIG := []string{"/dev/nvidia0", "/dev/nvidia-caps/nvidia-cap12", "/dev/nvidia-caps/nvidia-cap13"}
+	if got := deviceNodePaths(spec.Devices[1].ContainerEdits.DeviceNodes); !reflect.DeepEqual(got, wantMIG) {
+		t.Fatalf("unexpected MIG device nodes %v, want %v", got, wantMIG)
+	}
+
+	if got := deviceNodePaths(spec.ContainerEdits.DeviceNodes); !reflect.DeepEqual(got, []string{"/dev/nvidiactl"}) {
+		t.Fatalf("unexpected control device nodes %v", got)
+	}
+	var mounts []string
+	for _, mount := range spec.ContainerEdits.Mounts {
+		mounts = append(mounts, mount.HostPath+":"+mount.ContainerPath)
+	}
+	wantMounts := []string{
+		"/run/nvidia/driver/usr/bin/nvidia-smi:/usr/bin/nvidia-smi",
+		"/run/nvidia/driver/usr/lib64/libcuda.so.1:/usr/lib64/libcuda.so.1",
+		"/run/nvidia/driver/usr/lib64/libnvidia-ml.so.1:/usr/lib64/libnvidia-ml.so.1",
+	}
+	if !reflect.DeepEqual(mounts, wantMounts) {
+		t.Fatalf("unexpected mounts %v, want %v", mounts, wantMounts)
+	}
+	assertContains(t, out.String(), "written (3 devices)")
+}
+
+func TestWriteCDISpecSkipsUnchangedSpec(t *testing.T) {
+	t.Parallel()
+
+	runner, _, specPath, out := newCDIRunner(t)
+	if err := runner.writeCDISpec(); err != nil {
+		t.Fatalf("write CDI spec: %v", err)
+	}
+	first, err := os.Stat(specPath)
+	if err != nil {
+		t.Fatalf("stat spec: %v", err)
+	}
+
+	out.Reset()
+	if err := runner.writeCDISpec(); err != nil {
+		t.Fatalf("rewrite CDI spec: %v", err)
+	}
+	assertContains(t, out.String(), "is up to date")
+	second, err := os.Stat(specPath)
+	if err != nil {
+		t.Fatalf("stat spec: %v", err)
+	}
+	if !second.ModTime().Equal(first.ModTime()) {
+		t.Fatalf("expected unchanged spec to be left in place")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(specPath), "."+filepath.Base(specPath)+".tmp")); !os.IsNotExist(err) {
+		t.Fatalf("expected no temporary file, got %v", err)
+	}
+}
+
+func TestRunCDISpecFailure(t *testing.T) {
+	t.Parallel()
+
+	for _, required := range []bool{false, true} {
+		runner, _, specPath, out := newCDIRunner(t)
+		// A regular file in place of the spec directory makes the write fail.
+		blocker := filepath.Dir(specPath)
+		writeFile(t, blocker)
+		runner.cdiRequired = required
+
+		err := runner.Run(context.Background())
+		if required {
+			if err == nil || !strings.Contains(err.Error(), "CDI spec") {
+				t.Fatalf("expected CDI failure to be fatal, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("expected CDI failure to be a warning, got %v", err)
+		}
+		assertContains(t, out.String(), "warning: CDI spec not written")
+	}
+}
+
+func newCDIRunner(t *testing.T) (*Runner, string, string, *bytes.Buffer) {
+	t.Helper()
+
+	driverRootMount, parentMount := newTempRoots(t)
+	tmp := filepath.Dir(driverRootMount)
+	devRoot := filepath.Join(tmp, "dev")
+	procRoot := filepath.Join(tmp, "proc")
+	specPath := filepath.Join(tmp, "cdi", "gpu.deckhouse.io.json")
+
+	writeFile(t, filepath.Join(driverRootMount, "usr/bin/nvidia-smi"))
+	writeFile(t, filepath.Join(driverRootMount, "usr/lib64/libnvidia-ml.so.1"))
+	writeFile(t, filepath.Join(driverRootMount, "usr/lib64/libcuda.so.1"))
+	writeFile(t, filepath.Join(devRoot, "nvidia0"))
+	writeFile(t, filepath.Join(devRoot, "nvidiactl"))
+	writeFile(t, filepath.Join(devRoot, "nvidia-caps/nvidia-cap12"))
+
+	migDir := filepath.Join(procRoot, "driver/nvidia/capabilities/gpu0/mig/gi1")
+	writeAccessFile(t, filepath.Join(migDir, "access"), 12)
+	writeAccessFile(t, filepath.Join(migDir, "ci0", "access"), 13)
+
+	out := &bytes.Buffer{}
+	runner := NewRunner(Options{
+		DriverRoot:            "/run/nvidia/driver",
+		DriverRootMount:       driverRootMount,
+		DriverRootParentMount: parentMount,
+		CDISpecPath:           specPath,
+		DevRoot:               devRoot,
+		ProcRoot:              procRoot,
+		Now:                   fixedNow,
+		Out:                   out,
+		Err:                   out,
+		Exec:                  stubExec(0, nil),
+	})
+	return runner, devRoot, specPath, out
+}
+
+func writeAccessFile(t *testing.T, path string, minor int) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := "DeviceFileMinor: " + strconv.Itoa(minor) + "\nDeviceFileMode: 292\nDeviceFileModify: 1\n"
+	if err := os.WriteFile(path, []byte(content), 0o444); err != nil {
+		t.Fatalf("write access file: %v", err)
+	}
+}
+
+func readCDISpec(t *testing.T, path string) *cdispec.Spec {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+	spec := &cdispec.Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	return spec
+}
+
+func deviceNodePaths(nodes []*cdispec.DeviceNode) []string {
+	var paths []string
+	for _, node := range nodes {
+		paths = append(paths, node.Path)
+	}
+	return paths
+}
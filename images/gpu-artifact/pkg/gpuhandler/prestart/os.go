@@ -29,6 +29,11 @@ type FS interface {
 	Symlink(oldname, newname string) error
 	Stat(name string) (os.FileInfo, error)
 	ReadDir(name string) ([]os.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
 }
 
 // ExecFunc runs a command and returns its exit code.
@@ -39,6 +44,13 @@ type osFS struct{}
 func (osFS) Symlink(oldname, newname string) error      { return os.Symlink(oldname, newname) }
 func (osFS) Stat(name string) (os.FileInfo, error)      { return os.Stat(name) }
 func (osFS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+func (osFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (osFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+func (osFS) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+func (osFS) Rename(oldpath, newpath string) error         { return os.Rename(oldpath, newpath) }
+func (osFS) Remove(name string) error                     { return os.Remove(name) }
 
 func execCommand(ctx context.Context, path string, env []string, stdout, stderr io.Writer) (int, error) {
 	cmd := exec.CommandContext(ctx, path)
@@ -25,7 +25,7 @@ import (
 	"time"
 )
 
-// Run checks driver readiness until it succeeds or the context is canceled.
+// Run checks driver readiness until it succeeds or the context is canceled, then publishes the CDI spec.
 func (r *Runner) Run(ctx context.Context) error {
 	target := r.driverRootSymlinkTarget()
 	r.logf("create symlink: %s -> %s\n", r.driverRootMount, target)
@@ -36,7 +36,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	attempt := 0
 	for {
 		if r.checkOnce(ctx, attempt) {
-			return nil
+			return r.publishCDISpec()
 		}
 
 		select {
@@ -85,6 +85,18 @@ func (r *Runner) checkOnce(ctx context.Context, attempt int) bool {
 	return false
 }
 
+func (r *Runner) publishCDISpec() error {
+	err := r.writeCDISpec()
+	if err == nil {
+		return nil
+	}
+	if r.cdiRequired {
+		return fmt.Errorf("CDI spec: %w", err)
+	}
+	r.errf("warning: CDI spec not written: %v\n", err)
+	return nil
+}
+
 func (r *Runner) driverRootSymlinkTarget() string {
 	rootTrim := strings.TrimSuffix(r.driverRoot, "/")
 	base := path.Base(rootTrim)
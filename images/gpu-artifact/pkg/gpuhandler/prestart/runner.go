@@ -40,6 +40,12 @@ type Options struct {
 	Err                   io.Writer
 	FS                    FS
 	Exec                  ExecFunc
+	// CDISpecPath is where the CDI spec for the discovered devices is written.
+	CDISpecPath string
+	// CDIRequired makes a failure to write the CDI spec fatal instead of a warning.
+	CDIRequired bool
+	DevRoot     string
+	ProcRoot    string
 }
 
 // Runner executes driver readiness checks until they pass.
@@ -54,6 +60,10 @@ type Runner struct {
 	err                   io.Writer
 	fs                    FS
 	exec                  ExecFunc
+	cdiSpecPath           string
+	cdiRequired           bool
+	devRoot               string
+	procRoot              string
 }
 
 // NewRunner returns a runner configured with sensible defaults.
@@ -98,6 +108,18 @@ func NewRunner(opts Options) *Runner {
 	if exec == nil {
 		exec = execCommand
 	}
+	cdiSpecPath := opts.CDISpecPath
+	if cdiSpecPath == "" {
+		cdiSpecPath = defaultCDISpecPath
+	}
+	devRoot := opts.DevRoot
+	if devRoot == "" {
+		devRoot = defaultDevRoot
+	}
+	procRoot := opts.ProcRoot
+	if procRoot == "" {
+		procRoot = defaultProcRoot
+	}
 
 	return &Runner{
 		driverRoot:            driverRoot,
@@ -110,5 +132,9 @@ func NewRunner(opts Options) *Runner {
 		err:                   err,
 		fs:                    fs,
 		exec:                  exec,
+		cdiSpecPath:           cdiSpecPath,
+		cdiRequired:           opts.CDIRequired,
+		devRoot:               devRoot,
+		procRoot:              procRoot,
 	}
 }
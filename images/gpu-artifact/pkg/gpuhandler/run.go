@@ -32,7 +32,7 @@ func (a *Agent) Run(ctx context.Context) error {
 	}
 
 	notifier := newNotifier()
-	bootstrap := newBootstrapService(a.cfg, a.log, a.scheme, a.store, a.reader, a.placements, a.tracker)
+	bootstrap := newBootstrapService(a.cfg, a.log, a.scheme, a.store, a.reader, a.placements, a.tracker, a.persistence)
 	result, err := bootstrap.Start(ctx, notifier.Notify)
 	if err != nil {
 		return err
@@ -40,6 +40,8 @@ func (a *Agent) Run(ctx context.Context) error {
 
 	a.draDriver = result.driver
 	a.steps = result.steps
+	a.recorder = result.recorder
+	a.notify = notifier.Notify
 	a.stop = result.stop
 	if a.stop != nil {
 		defer a.stop()
@@ -56,7 +58,11 @@ func (a *Agent) sync(ctx context.Context) error {
 	}
 
 	st := state.New(a.cfg.NodeName)
-	if _, err := a.steps.Run(ctx, st); err != nil {
+	_, err := a.steps.Run(ctx, st)
+	if a.nvml != nil && a.nvml.Invalidated() {
+		return a.recoverNVML(ctx)
+	}
+	if err != nil {
 		return err
 	}
 	a.log.Info("sync completed", "all", len(st.All()), "ready", len(st.Ready()))
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package listenaddr validates the bind address flags of the gpu-artifact binaries, so a typo fails the
+// container at start instead of the first probe or scrape.
+package listenaddr
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+)
+
+// Flag checks the value of a bind address flag and returns it normalised. Unless optional, "" and "0"
+// are rejected; for an optional listener they keep their meaning of a disabled listener.
+func Flag(name, addr string, optional bool) (string, error) {
+	if optional && (addr == "" || addr == "0") {
+		return addr, nil
+	}
+	parsed, err := listenaddr.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	return parsed, nil
+}
+
+// FlagOrDie validates a required bind address flag and exits the binary when it is invalid.
+func FlagOrDie(name, addr string) string {
+	return orDie(Flag(name, addr, false))
+}
+
+// OptionalFlagOrDie validates a bind address flag that "" or "0" disable and exits the binary when it is invalid.
+func OptionalFlagOrDie(name, addr string) string {
+	return orDie(Flag(name, addr, true))
+}
+
+func orDie(addr string, err error) string {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return addr
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listenaddr
+
+import "testing"
+
+func TestFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		optional bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "normalised", addr: " :8081 ", want: ":8081"},
+		{name: "ipv6", addr: "[::1]:8081", want: "[::1]:8081"},
+		{name: "unbracketed ipv6", addr: "::1:8081", wantErr: true},
+		{name: "required empty", addr: "", wantErr: true},
+		{name: "required zero", addr: "0", wantErr: true},
+		{name: "optional empty", addr: "", optional: true, want: ""},
+		{name: "optional zero", addr: "0", optional: true, want: "0"},
+		{name: "optional invalid", addr: "localhost", optional: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Flag("bind-address", tt.addr, tt.optional)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Flag(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Fatalf("Flag(%q) = %q, want %q", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
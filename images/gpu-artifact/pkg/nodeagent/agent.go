@@ -23,9 +23,9 @@ import (
 	"github.com/deckhouse/deckhouse/pkg/log"
 
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/common/steptaker"
-	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/trigger"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/sys/pciids"
 )
 
@@ -35,29 +35,28 @@ type Agent struct {
 	log   *log.Logger
 	steps steptaker.StepTakers[state.State]
 
-	scheme   *runtime.Scheme
-	store    service.Store
-	pci      service.PCIProvider
-	hostInfo service.HostInfoProvider
+	scheme     *runtime.Scheme
+	store      service.Store
+	pci        service.PCIProvider
+	hostInfo   service.HostInfoProvider
+	checkpoint *service.FileCheckpoint
+	rescan     *trigger.NodeRescan
 }
 
 // New creates a new node-agent.
 func New(client client.Client, cfg Config, log *log.Logger) *Agent {
 	store := service.NewClientStore(client)
-	resolver, usedPath, err := pciids.LoadFirst(cfg.PCIIDsPaths)
-	if err != nil {
-		log.Error("failed to load pci.ids", logger.SlogErr(err))
-	}
-	if resolver == nil {
-		log.Info("pci.ids not found, PCI names will be empty", "paths", cfg.PCIIDsPaths)
+	names := pciids.NewCache(cfg.PCIIDsPaths)
+	if path := names.Source(); path == "" {
+		log.Info("pci.ids not found, device names fall back to PCI IDs", "paths", cfg.PCIIDsPaths)
 	} else {
-		log.Info("pci.ids loaded", "path", usedPath)
+		log.Info("pci.ids found", "path", path)
 	}
 
-	pci := service.NewSysfsPCIProvider(cfg.SysRoot, resolver)
+	pci := service.NewSysfsPCIProvider(cfg.SysRoot, names)
 	hostInfo := service.NewHostInfoCollector(cfg.OSReleasePath, cfg.SysRoot)
 
-	return &Agent{
+	agent := &Agent{
 		cfg:      cfg,
 		log:      log,
 		scheme:   client.Scheme(),
@@ -65,4 +64,8 @@ func New(client client.Client, cfg Config, log *log.Logger) *Agent {
 		pci:      pci,
 		hostInfo: hostInfo,
 	}
+	if cfg.CheckpointPath != "" {
+		agent.checkpoint = service.NewFileCheckpoint(cfg.CheckpointPath, cfg.CheckpointMaxAge)
+	}
+	return agent
 }
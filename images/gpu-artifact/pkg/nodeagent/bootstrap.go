@@ -29,10 +29,12 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/apply"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/checkpoint"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/cleanup"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/discover"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 const nodeAgentComponent = "gpu-node-agent"
@@ -44,6 +46,8 @@ type bootstrapService struct {
 	store    service.Store
 	pci      service.PCIProvider
 	hostInfo service.HostInfoProvider
+	// checkpoint is optional; without it an empty scan is published as is.
+	checkpoint service.Checkpoint
 }
 
 type bootstrapResult struct {
@@ -64,12 +68,21 @@ func newBootstrapService(cfg Config, log *log.Logger, scheme *runtime.Scheme, st
 
 func (b *bootstrapService) Start() (*bootstrapResult, error) {
 	recorder, stopRecorder := b.startEventRecorder()
-	steps := handler.NewSteps(
-		b.log,
-		discover.NewDiscoverHandler(b.pci, b.hostInfo),
+	handlers := []handler.Handler{discover.NewDiscoverHandler(b.pci, b.hostInfo)}
+	if b.checkpoint != nil {
+		handlers = append(handlers, checkpoint.NewRestoreHandler(b.checkpoint))
+	}
+	if b.cfg.CoLocated {
+		handlers = append(handlers, discover.NewHandlerDataHandler(nodecoord.NewClient(b.cfg.SocketPath)))
+	}
+	handlers = append(handlers,
 		apply.NewApplyHandler(b.store, recorder),
 		cleanup.NewCleanupHandler(b.store, recorder),
 	)
+	if b.checkpoint != nil {
+		handlers = append(handlers, checkpoint.NewSaveHandler(b.checkpoint))
+	}
+	steps := handler.NewSteps(b.log, handlers...)
 
 	stop := func() {
 		if stopRecorder != nil {
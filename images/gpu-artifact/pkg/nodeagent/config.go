@@ -16,7 +16,26 @@ limitations under the License.
 
 package nodeagent
 
-import "k8s.io/client-go/rest"
+import (
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
+)
+
+const (
+	// DefaultCheckpointPath is where the node-agent keeps its last published device set.
+	DefaultCheckpointPath = service.DefaultCheckpointPath
+	// DefaultCheckpointMaxAge bounds how long a checkpoint may stand in for an empty scan.
+	DefaultCheckpointMaxAge = service.DefaultCheckpointMaxAge
+)
+
+// PrepareCheckpointDir creates the checkpoint directory and hands it to uid:gid, so the agent can
+// run unprivileged. It needs CAP_CHOWN and is meant for an init container.
+func PrepareCheckpointDir(path string, uid, gid int) error {
+	return service.PrepareCheckpointDir(path, uid, gid)
+}
 
 // Config defines the node-agent settings.
 type Config struct {
@@ -25,4 +44,11 @@ type Config struct {
 	OSReleasePath string
 	PCIIDsPaths   []string
 	KubeConfig    *rest.Config
+	// CoLocated enables reading NVML data from gpu-handler running in the same pod.
+	CoLocated  bool
+	SocketPath string
+	// CheckpointPath keeps the last published device set across restarts; empty disables it.
+	CheckpointPath string
+	// CheckpointMaxAge is how long after the last publish the checkpoint may replace an empty scan.
+	CheckpointMaxAge time.Duration
 }
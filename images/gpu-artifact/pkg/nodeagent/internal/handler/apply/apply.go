@@ -49,7 +49,7 @@ func (h *ApplyHandler) Handle(ctx context.Context, st state.State) error {
 	var errs []error
 	for _, dev := range st.Devices() {
 		name := state.PhysicalGPUName(st.NodeName(), dev)
-		if err := h.applyDevice(ctx, name, st.NodeName(), dev, st.NodeInfo()); err != nil {
+		if err := h.applyDevice(ctx, name, st.NodeName(), dev, st.NodeInfo(), st.HandlerDevice(dev.Address)); err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", name, err))
 		}
 	}
@@ -28,9 +28,10 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/builder"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
-func (h *ApplyHandler) applyDevice(ctx context.Context, name, nodeName string, dev state.Device, nodeInfo *gpuv1alpha1.NodeInfo) error {
+func (h *ApplyHandler) applyDevice(ctx context.Context, name, nodeName string, dev state.Device, nodeInfo *gpuv1alpha1.NodeInfo, handlerDev *nodecoord.Device) error {
 	var log *slog.Logger
 	logFor := func() *slog.Logger {
 		if log == nil {
@@ -65,6 +66,7 @@ func (h *ApplyHandler) applyDevice(ctx context.Context, name, nodeName string, d
 	}
 
 	desiredStatus := buildStatus(obj, dev, nodeName, nodeInfo)
+	nodecoord.Merge(&desiredStatus, handlerDev)
 	patchBase := obj.DeepCopy()
 	obj.Status = desiredStatus
 	if err := h.store.PatchStatus(ctx, obj, patchBase); err != nil {
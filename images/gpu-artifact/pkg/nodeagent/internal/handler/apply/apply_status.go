@@ -37,7 +37,8 @@ func buildStatus(obj *gpuv1alpha1.PhysicalGPU, dev state.Device, nodeName string
 
 func buildPCIInfo(dev state.Device) *gpuv1alpha1.PCIInfo {
 	pci := &gpuv1alpha1.PCIInfo{
-		Address: dev.Address,
+		Address:  dev.Address,
+		NUMANode: dev.NUMANode,
 	}
 	if dev.ClassCode != "" || dev.ClassName != "" {
 		pci.Class = &gpuv1alpha1.PCIClassInfo{
@@ -27,6 +27,7 @@ import (
 	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
 func TestApplyHandlerCreatesPhysicalGPU(t *testing.T) {
@@ -82,3 +83,54 @@ func TestApplyHandlerCreatesPhysicalGPU(t *testing.T) {
 	}
 
 }
+
+func TestApplyHandlerMergesCoLocatedHandlerData(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gpuv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&gpuv1alpha1.PhysicalGPU{}).
+		Build()
+
+	handler := NewApplyHandler(service.NewClientStore(cl), nil)
+	numa := int32(1)
+	dev := state.Device{
+		Address:    "0000:3b:00.0",
+		ClassCode:  "0302",
+		Index:      "0",
+		VendorID:   "10de",
+		DeviceID:   "20b7",
+		DeviceName: "GA100GL [A30 PCIe]",
+		DriverName: "nvidia",
+		NUMANode:   &numa,
+	}
+	st := state.New("node-1")
+	st.SetDevices([]state.Device{dev})
+	st.SetHandlerDevices([]nodecoord.Device{{
+		PCIAddress:   "00000000:3B:00.0",
+		Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "NVIDIA A30"},
+		Nvidia:       &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-1"},
+	}})
+
+	if err := handler.Handle(context.Background(), st); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	obj := &gpuv1alpha1.PhysicalGPU{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: state.PhysicalGPUName("node-1", dev)}, obj); err != nil {
+		t.Fatalf("get PhysicalGPU: %v", err)
+	}
+	if obj.Status.PCIInfo == nil || obj.Status.PCIInfo.NUMANode == nil || *obj.Status.PCIInfo.NUMANode != 1 {
+		t.Fatalf("expected NUMA node from PCI scan, got %#v", obj.Status.PCIInfo)
+	}
+	if obj.Status.Capabilities == nil || obj.Status.Capabilities.ProductName != "NVIDIA A30" {
+		t.Fatalf("expected capabilities from gpu-handler, got %#v", obj.Status.Capabilities)
+	}
+	if obj.Status.CurrentState == nil || obj.Status.CurrentState.DriverType != gpuv1alpha1.DriverTypeNvidia ||
+		obj.Status.CurrentState.Nvidia == nil || obj.Status.CurrentState.Nvidia.GPUUUID != "GPU-1" {
+		t.Fatalf("unexpected current state %#v", obj.Status.CurrentState)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+)
+
+const (
+	restoreHandlerName = "CheckpointRestore"
+	saveHandlerName    = "CheckpointSave"
+)
+
+// RestoreHandler replaces an empty scan with the checkpointed device set.
+type RestoreHandler struct {
+	checkpoint service.Checkpoint
+}
+
+// NewRestoreHandler constructs a handler that runs right after discovery.
+func NewRestoreHandler(checkpoint service.Checkpoint) *RestoreHandler {
+	return &RestoreHandler{checkpoint: checkpoint}
+}
+
+// Name returns the handler name.
+func (h *RestoreHandler) Name() string {
+	return restoreHandlerName
+}
+
+// Handle keeps the devices found before a restart when the scan right after it comes back empty,
+// so cleanup does not delete every PhysicalGPU of the node.
+func (h *RestoreHandler) Handle(ctx context.Context, st state.State) error {
+	devices, writtenAt, restored := h.checkpoint.Restore(st.NodeName(), st.Devices())
+	if !restored {
+		return nil
+	}
+	st.SetDevices(devices)
+	logger.FromContext(ctx).Warn("PCI scan found no devices, publishing the checkpointed set",
+		"devices", len(devices), "checkpointAge", time.Since(writtenAt).Round(time.Second).String())
+	return nil
+}
+
+// SaveHandler records the device set once it has been published.
+type SaveHandler struct {
+	checkpoint service.Checkpoint
+}
+
+// NewSaveHandler constructs a handler that runs last in the chain.
+func NewSaveHandler(checkpoint service.Checkpoint) *SaveHandler {
+	return &SaveHandler{checkpoint: checkpoint}
+}
+
+// Name returns the handler name.
+func (h *SaveHandler) Name() string {
+	return saveHandlerName
+}
+
+// Handle writes the checkpoint. A write failure only costs the protection after the next restart,
+// so it is reported and the sync still succeeds.
+func (h *SaveHandler) Handle(ctx context.Context, st state.State) error {
+	if err := h.checkpoint.Save(st.NodeName(), st.Devices()); err != nil {
+		logger.FromContext(ctx).Warn("failed to write device checkpoint", logger.SlogErr(err))
+	}
+	return nil
+}
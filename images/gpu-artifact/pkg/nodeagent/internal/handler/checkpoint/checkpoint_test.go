@@ -0,0 +1,139 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/apply"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/cleanup"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/handler/discover"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+)
+
+type stubPCI struct {
+	devices []state.Device
+}
+
+func (p *stubPCI) Scan(context.Context) ([]state.Device, error) {
+	return p.devices, nil
+}
+
+type stubHostInfo struct{}
+
+func (stubHostInfo) NodeInfo(context.Context) *gpuv1alpha1.NodeInfo {
+	return &gpuv1alpha1.NodeInfo{NodeName: "node-1"}
+}
+
+// agentRun mirrors the node-agent pipeline built by bootstrap with a checkpoint loaded at start.
+type agentRun struct {
+	t     *testing.T
+	cl    client.Client
+	pci   *stubPCI
+	steps func(context.Context, state.State) error
+}
+
+func startAgent(t *testing.T, cl client.Client, pci *stubPCI, path string) *agentRun {
+	t.Helper()
+	cp := service.NewFileCheckpoint(path, time.Hour)
+	if err := cp.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	store := service.NewClientStore(cl)
+	steps := handler.NewSteps(logger.NewLogger("info", "discard", 0),
+		discover.NewDiscoverHandler(pci, stubHostInfo{}),
+		NewRestoreHandler(cp),
+		apply.NewApplyHandler(store, nil),
+		cleanup.NewCleanupHandler(store, nil),
+		NewSaveHandler(cp),
+	)
+	return &agentRun{t: t, cl: cl, pci: pci, steps: func(ctx context.Context, st state.State) error {
+		_, err := steps.Run(ctx, st)
+		return err
+	}}
+}
+
+func (a *agentRun) sync() {
+	a.t.Helper()
+	ctx := logger.ToContext(context.Background(), slog.New(slog.DiscardHandler))
+	if err := a.steps(ctx, state.New("node-1")); err != nil {
+		a.t.Fatalf("sync: %v", err)
+	}
+}
+
+func (a *agentRun) exists(name string) bool {
+	a.t.Helper()
+	err := a.cl.Get(context.Background(), client.ObjectKey{Name: name}, &gpuv1alpha1.PhysicalGPU{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		a.t.Fatalf("get PhysicalGPU: %v", err)
+	}
+	return err == nil
+}
+
+func TestRestartDuringOutageKeepsDevices(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gpuv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&gpuv1alpha1.PhysicalGPU{}).Build()
+	path := filepath.Join(t.TempDir(), "state.json")
+	device := state.Device{Address: "0000:01:00.0", ClassCode: "0302", Index: "0", VendorID: "10de", DeviceID: "20b5"}
+	name := state.PhysicalGPUName("node-1", device)
+
+	before := startAgent(t, cl, &stubPCI{devices: []state.Device{device}}, path)
+	before.sync()
+	if !before.exists(name) {
+		t.Fatalf("expected PhysicalGPU %s to be published", name)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected a checkpoint after the publish: %v", err)
+	}
+
+	// The agent restarts while the apiserver is down and its first scan afterwards comes back empty.
+	after := startAgent(t, cl, &stubPCI{}, path)
+	after.sync()
+	if !after.exists(name) {
+		t.Fatalf("expected the checkpointed PhysicalGPU to survive the empty scan")
+	}
+	if current, _ := os.ReadFile(path); string(current) != string(written) {
+		t.Fatalf("expected the checkpoint to stay untouched while it is restored")
+	}
+
+	// A scan that finds devices retires the checkpoint; a real removal afterwards is published.
+	after.pci.devices = []state.Device{device}
+	after.sync()
+	after.pci.devices = nil
+	after.sync()
+	if after.exists(name) {
+		t.Fatalf("expected PhysicalGPU to be deleted once the checkpoint is retired")
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discover
+
+import (
+	"context"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+)
+
+const handlerDataHandlerName = "HandlerData"
+
+// HandlerDataHandler fetches NVML data from a co-located gpu-handler.
+type HandlerDataHandler struct {
+	provider service.HandlerProvider
+}
+
+// NewHandlerDataHandler constructs a handler that reads the coordination socket.
+func NewHandlerDataHandler(provider service.HandlerProvider) *HandlerDataHandler {
+	return &HandlerDataHandler{provider: provider}
+}
+
+// Name returns the handler name.
+func (h *HandlerDataHandler) Name() string {
+	return handlerDataHandlerName
+}
+
+// Handle stores the handler data in the state.
+// An unreachable handler is not fatal: the PCI-owned fields are still applied and NVML-owned fields stay untouched.
+func (h *HandlerDataHandler) Handle(ctx context.Context, st state.State) error {
+	log, sourceCtx := logger.GetDataSourceContext(ctx, "gpu-handler")
+	devices, err := h.provider.ListDevices(sourceCtx)
+	if err != nil {
+		log.Warn("gpu-handler data is unavailable, keeping NVML fields as is", logger.SlogErr(err))
+		return nil
+	}
+	st.SetHandlerDevices(devices)
+	log.Debug("gpu-handler data collected", "devices", len(devices))
+	return nil
+}
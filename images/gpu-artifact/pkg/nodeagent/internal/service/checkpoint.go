@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+)
+
+const (
+	// DefaultCheckpointPath is where the node-agent keeps its last published device set.
+	DefaultCheckpointPath = "/var/lib/gpu-agent/state.json"
+	// DefaultCheckpointMaxAge bounds how long a checkpoint may stand in for an empty scan.
+	DefaultCheckpointMaxAge = time.Hour
+
+	checkpointVersion = 1
+)
+
+// Checkpoint keeps the last published device set across restarts, so an agent restarted while
+// the apiserver is unreachable does not publish an empty device set once it is back.
+type Checkpoint interface {
+	// Restore returns the checkpointed devices in place of an empty scan while the checkpoint
+	// loaded at start is fresh; any other scan is returned as is and retires the checkpoint.
+	Restore(nodeName string, scanned []state.Device) ([]state.Device, time.Time, bool)
+	// Save records a published device set. It is a no-op while the agent publishes a restored set,
+	// so the checkpoint keeps the time of the last real scan.
+	Save(nodeName string, devices []state.Device) error
+}
+
+type checkpointData struct {
+	Version   int            `json:"version"`
+	NodeName  string         `json:"nodeName"`
+	WrittenAt time.Time      `json:"writtenAt"`
+	Devices   []state.Device `json:"devices"`
+}
+
+// FileCheckpoint stores the checkpoint as a JSON file on the host.
+type FileCheckpoint struct {
+	path   string
+	maxAge time.Duration
+	now    func() time.Time
+
+	pending *checkpointData
+}
+
+// NewFileCheckpoint creates a checkpoint at path. A non-positive maxAge uses DefaultCheckpointMaxAge.
+func NewFileCheckpoint(path string, maxAge time.Duration) *FileCheckpoint {
+	if maxAge <= 0 {
+		maxAge = DefaultCheckpointMaxAge
+	}
+	return &FileCheckpoint{path: path, maxAge: maxAge, now: time.Now}
+}
+
+// Load reads the checkpoint left by a previous run. A missing file is not an error.
+func (c *FileCheckpoint) Load() error {
+	c.pending = nil
+	raw, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read checkpoint: %w", err)
+	}
+	var data checkpointData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("decode checkpoint %s: %w", c.path, err)
+	}
+	if data.Version != checkpointVersion {
+		return fmt.Errorf("checkpoint %s has unsupported version %d", c.path, data.Version)
+	}
+	c.pending = &data
+	return nil
+}
+
+// Restore implements Checkpoint.
+func (c *FileCheckpoint) Restore(nodeName string, scanned []state.Device) ([]state.Device, time.Time, bool) {
+	data := c.pending
+	if len(scanned) > 0 || data == nil || data.NodeName != nodeName || len(data.Devices) == 0 || c.now().Sub(data.WrittenAt) > c.maxAge {
+		c.pending = nil
+		return scanned, time.Time{}, false
+	}
+	return append([]state.Device(nil), data.Devices...), data.WrittenAt, true
+}
+
+// Save implements Checkpoint. The file is replaced atomically.
+func (c *FileCheckpoint) Save(nodeName string, devices []state.Device) error {
+	if c.pending != nil {
+		return nil
+	}
+	raw, err := json.Marshal(checkpointData{
+		Version:   checkpointVersion,
+		NodeName:  nodeName,
+		WrittenAt: c.now().UTC(),
+		Devices:   devices,
+	})
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// PrepareCheckpointDir creates the directory of the checkpoint at path and hands it, together with a
+// checkpoint left by an earlier run, to uid:gid. It runs once as root before the agent starts, so the
+// agent itself writes the checkpoint unprivileged.
+func PrepareCheckpointDir(path string, uid, gid int) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create checkpoint directory: %w", err)
+	}
+	if err := os.Lchown(dir, uid, gid); err != nil {
+		return fmt.Errorf("chown checkpoint directory: %w", err)
+	}
+	if err := os.Lchown(path, uid, gid); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("chown checkpoint: %w", err)
+	}
+	return nil
+}
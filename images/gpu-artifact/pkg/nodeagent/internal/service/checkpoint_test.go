@@ -0,0 +1,188 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
+)
+
+func TestFileCheckpointRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	numa := int32(1)
+	devices := []state.Device{{Address: "0000:01:00.0", Index: "0", VendorID: "10de", DeviceID: "20b5", NUMANode: &numa}}
+
+	if err := NewFileCheckpoint(path, time.Hour).Save("node-1", devices); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if leftovers, _ := filepath.Glob(path + ".tmp-*"); len(leftovers) != 0 {
+		t.Fatalf("expected no temporary files, got %v", leftovers)
+	}
+
+	restarted := NewFileCheckpoint(path, time.Hour)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got, writtenAt, ok := restarted.Restore("node-1", nil)
+	if !ok || len(got) != 1 || got[0].Address != "0000:01:00.0" || got[0].NUMANode == nil || *got[0].NUMANode != 1 {
+		t.Fatalf("expected the saved devices to be restored, got %+v (ok=%v)", got, ok)
+	}
+	if time.Since(writtenAt) > time.Minute {
+		t.Fatalf("unexpected checkpoint time %s", writtenAt)
+	}
+}
+
+func TestFileCheckpointLoadMissingOrBroken(t *testing.T) {
+	dir := t.TempDir()
+	missing := NewFileCheckpoint(filepath.Join(dir, "missing.json"), 0)
+	if err := missing.Load(); err != nil {
+		t.Fatalf("expected a missing checkpoint to be ignored, got %v", err)
+	}
+	if _, _, ok := missing.Restore("node-1", nil); ok {
+		t.Fatalf("expected nothing to restore without a checkpoint")
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := NewFileCheckpoint(broken, 0).Load(); err == nil {
+		t.Fatalf("expected an error for a broken checkpoint")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version":2}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := NewFileCheckpoint(future, 0).Load(); err == nil {
+		t.Fatalf("expected an error for an unknown checkpoint version")
+	}
+}
+
+func TestFileCheckpointRestore(t *testing.T) {
+	saved := []state.Device{{Address: "0000:01:00.0"}, {Address: "0000:02:00.0"}}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name    string
+		node    string
+		scanned []state.Device
+		age     time.Duration
+		want    bool
+	}{
+		{name: "empty scan with fresh checkpoint", node: "node-1", age: 30 * time.Minute, want: true},
+		{name: "stale checkpoint", node: "node-1", age: 2 * time.Hour},
+		{name: "checkpoint of another node", node: "node-2", age: time.Minute},
+		{name: "scan found devices", node: "node-1", scanned: saved[:1], age: time.Minute},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			writer := NewFileCheckpoint(path, time.Hour)
+			writer.now = func() time.Time { return now.Add(-tc.age) }
+			if err := writer.Save("node-1", saved); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			cp := NewFileCheckpoint(path, time.Hour)
+			cp.now = func() time.Time { return now }
+			if err := cp.Load(); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			got, _, ok := cp.Restore(tc.node, tc.scanned)
+			if ok != tc.want {
+				t.Fatalf("expected restored=%v, got %v", tc.want, ok)
+			}
+			want := tc.scanned
+			if tc.want {
+				want = saved
+			}
+			if len(got) != len(want) {
+				t.Fatalf("expected %d devices, got %+v", len(want), got)
+			}
+			if !tc.want {
+				// A checkpoint that was not used is retired: later empty scans are published as is.
+				if _, _, ok := cp.Restore(tc.node, nil); ok {
+					t.Fatalf("expected the checkpoint to be retired")
+				}
+			}
+		})
+	}
+}
+
+func TestFileCheckpointSaveKeepsTimeWhileRestoring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	saved := []state.Device{{Address: "0000:01:00.0"}}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := (&FileCheckpoint{path: path, maxAge: time.Hour, now: func() time.Time { return start }}).Save("node-1", saved); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	now := start.Add(10 * time.Minute)
+	cp := NewFileCheckpoint(path, time.Hour)
+	cp.now = func() time.Time { return now }
+	if err := cp.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	devices, _, _ := cp.Restore("node-1", nil)
+	if err := cp.Save("node-1", devices); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Publishing the restored set does not refresh the checkpoint, so the staleness bound still
+	// counts from the last real scan.
+	now = start.Add(61 * time.Minute)
+	if _, _, ok := cp.Restore("node-1", nil); ok {
+		t.Fatalf("expected the checkpoint to expire an hour after the last real scan")
+	}
+	if err := cp.Save("node-1", nil); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reloaded := NewFileCheckpoint(path, time.Hour)
+	reloaded.now = func() time.Time { return now }
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, _, ok := reloaded.Restore("node-1", nil); ok {
+		t.Fatalf("expected an empty published set to clear the checkpoint")
+	}
+}
+
+func TestPrepareCheckpointDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gpu-agent", "state.json")
+	uid, gid := os.Getuid(), os.Getgid()
+
+	if err := PrepareCheckpointDir(path, uid, gid); err != nil {
+		t.Fatalf("prepare without a checkpoint: %v", err)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		t.Fatalf("expected the checkpoint directory to be created, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := PrepareCheckpointDir(path, uid, gid); err != nil {
+		t.Fatalf("prepare with a checkpoint: %v", err)
+	}
+	if err := NewFileCheckpoint(path, 0).Save("node-1", nil); err != nil {
+		t.Fatalf("expected the prepared directory to take a checkpoint, got %v", err)
+	}
+}
@@ -37,19 +37,24 @@ type PCIProvider interface {
 	Scan(ctx context.Context) ([]state.Device, error)
 }
 
+// PCINameResolver resolves human-readable names for PCI IDs.
+type PCINameResolver interface {
+	Lookup(classCode, vendorID, deviceID string) pciids.Names
+}
+
 // SysfsPCIProvider scans sysfs for PCI devices.
 type SysfsPCIProvider struct {
-	SysRoot  string
-	Resolver *pciids.Resolver
-	Reader   pci.Reader
+	SysRoot string
+	Names   PCINameResolver
+	Reader  pci.Reader
 }
 
 // NewSysfsPCIProvider creates a sysfs-based PCI provider.
-func NewSysfsPCIProvider(sysRoot string, resolver *pciids.Resolver) *SysfsPCIProvider {
+func NewSysfsPCIProvider(sysRoot string, names PCINameResolver) *SysfsPCIProvider {
 	return &SysfsPCIProvider{
-		SysRoot:  sysRoot,
-		Resolver: resolver,
-		Reader:   pci.NewSysfsReader(sysRoot),
+		SysRoot: sysRoot,
+		Names:   names,
+		Reader:  pci.NewSysfsReader(sysRoot),
 	}
 }
 
@@ -80,12 +85,14 @@ func (p *SysfsPCIProvider) Scan(ctx context.Context) ([]state.Device, error) {
 			VendorID:   raw.VendorID,
 			DeviceID:   raw.DeviceID,
 			DriverName: raw.DriverName,
+			NUMANode:   raw.NUMANode,
 		}
 
-		if p.Resolver != nil {
-			device.ClassName = p.Resolver.ClassName(device.ClassCode)
-			device.VendorName = p.Resolver.VendorName(device.VendorID)
-			device.DeviceName = p.Resolver.DeviceName(device.VendorID, device.DeviceID)
+		if p.Names != nil {
+			names := p.Names.Lookup(device.ClassCode, device.VendorID, device.DeviceID)
+			device.ClassName = names.Class
+			device.VendorName = names.Vendor
+			device.DeviceName = names.Device
 		}
 
 		devices = append(devices, device)
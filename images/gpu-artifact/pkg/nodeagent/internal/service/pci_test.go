@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/common/testutil"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/sys/pciids"
 )
 
 func TestSysfsPCIProviderScan(t *testing.T) {
@@ -85,3 +86,29 @@ func TestSysfsPCIProviderScan(t *testing.T) {
 		t.Fatalf("unexpected driver name %q", dev.DriverName)
 	}
 }
+
+func TestSysfsPCIProviderScanFallsBackWithoutPCIIDs(t *testing.T) {
+	root := t.TempDir()
+	gpuDir := filepath.Join(root, "bus/pci/devices", "0000:01:00.0")
+	if err := os.MkdirAll(gpuDir, 0o755); err != nil {
+		t.Fatalf("mkdir gpu: %v", err)
+	}
+	testutil.WriteFile(t, filepath.Join(gpuDir, "class"), "0x0302")
+	testutil.WriteFile(t, filepath.Join(gpuDir, "vendor"), "0x10de")
+	testutil.WriteFile(t, filepath.Join(gpuDir, "device"), "0x1db5")
+
+	provider := NewSysfsPCIProvider(root, pciids.NewCache([]string{filepath.Join(root, "missing/pci.ids")}))
+	devices, err := provider.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("expected 1 device, got %d", len(devices))
+	}
+	if devices[0].DeviceName != "unknown (10de:1db5)" {
+		t.Fatalf("unexpected fallback device name %q", devices[0].DeviceName)
+	}
+	if devices[0].VendorName != "" || devices[0].ClassName != "" {
+		t.Fatalf("expected empty vendor/class names, got %q/%q", devices[0].VendorName, devices[0].ClassName)
+	}
+}
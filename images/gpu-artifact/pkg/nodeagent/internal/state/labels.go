@@ -16,7 +16,11 @@ limitations under the License.
 
 package state
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/sys/pciids"
+)
 
 // LabelsForDevice returns the labels applied to a PhysicalGPU object.
 func LabelsForDevice(nodeName string, dev Device) map[string]string {
@@ -61,7 +65,7 @@ func VendorLabel(dev Device) string {
 // DeviceLabel returns a normalized device label value.
 func DeviceLabel(deviceName string) string {
 	deviceName = strings.TrimSpace(deviceName)
-	if deviceName == "" {
+	if deviceName == "" || pciids.IsUnknownDeviceName(deviceName) {
 		return ""
 	}
 
@@ -50,6 +50,12 @@ func TestDeviceLabelEmpty(t *testing.T) {
 	}
 }
 
+func TestDeviceLabelSkipsUnknownPlaceholder(t *testing.T) {
+	if got := DeviceLabel("unknown (10de:1db5)"); got != "" {
+		t.Fatalf("expected empty label for placeholder name, got %q", got)
+	}
+}
+
 func TestLabelsForDeviceSkipEmpty(t *testing.T) {
 	labels := LabelsForDevice("node-1", Device{})
 	if labels[LabelNode] != "node-1" {
@@ -16,7 +16,10 @@ limitations under the License.
 
 package state
 
-import gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+import (
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
+)
 
 const (
 	// LabelNode marks PhysicalGPU objects with the node name.
@@ -29,16 +32,18 @@ const (
 )
 
 // Device represents a GPU-like PCI device detected on the node.
+// The JSON form is used by the node-agent checkpoint.
 type Device struct {
-	Address    string
-	ClassCode  string
-	ClassName  string
-	Index      string
-	VendorID   string
-	VendorName string
-	DeviceID   string
-	DeviceName string
-	DriverName string
+	Address    string `json:"address"`
+	ClassCode  string `json:"classCode,omitempty"`
+	ClassName  string `json:"className,omitempty"`
+	Index      string `json:"index,omitempty"`
+	VendorID   string `json:"vendorID,omitempty"`
+	VendorName string `json:"vendorName,omitempty"`
+	DeviceID   string `json:"deviceID,omitempty"`
+	DeviceName string `json:"deviceName,omitempty"`
+	DriverName string `json:"driverName,omitempty"`
+	NUMANode   *int32 `json:"numaNode,omitempty"`
 }
 
 // State provides access to a node-agent sync snapshot.
@@ -49,6 +54,8 @@ type State interface {
 	Devices() []Device
 	SetDevices(devices []Device)
 	Expected() map[string]Device
+	HandlerDevice(address string) *nodecoord.Device
+	SetHandlerDevices(devices []nodecoord.Device)
 }
 
 type state struct {
@@ -56,6 +63,7 @@ type state struct {
 	nodeInfo *gpuv1alpha1.NodeInfo
 	devices  []Device
 	expected map[string]Device
+	handler  map[string]nodecoord.Device
 }
 
 // New initializes the state for a single sync loop.
@@ -94,3 +102,16 @@ func (s *state) SetDevices(devices []Device) {
 func (s *state) Expected() map[string]Device {
 	return s.expected
 }
+
+// HandlerDevice returns the NVML data for a PCI address, or nil when gpu-handler did not report it.
+func (s *state) HandlerDevice(address string) *nodecoord.Device {
+	device, ok := s.handler[nodecoord.NormalizeAddress(address)]
+	if !ok {
+		return nil
+	}
+	return &device
+}
+
+func (s *state) SetHandlerDevices(devices []nodecoord.Device) {
+	s.handler = nodecoord.Index(devices)
+}
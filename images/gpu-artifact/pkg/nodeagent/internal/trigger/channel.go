@@ -0,0 +1,55 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+
+	"github.com/deckhouse/deckhouse/pkg/log"
+)
+
+// Channel triggers a sync for every value received on a channel, e.g. a SIGHUP forwarded by main.
+type Channel struct {
+	log    *log.Logger
+	name   string
+	events <-chan struct{}
+}
+
+// NewChannel constructs a source fed by events; name identifies it in logs.
+func NewChannel(name string, events <-chan struct{}, log *log.Logger) *Channel {
+	return &Channel{log: log, name: name, events: events}
+}
+
+// Run forwards events until ctx is done. A closed channel only stops the forwarding.
+func (c *Channel) Run(ctx context.Context, notify NotifyFunc) error {
+	events := c.events
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if c.log != nil {
+				c.log.Info("rescan requested", "source", c.name)
+			}
+			notify()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/deckhouse/deckhouse/pkg/log"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
+)
+
+const defaultHandlerPollInterval = 10 * time.Second
+
+// HandlerLister lists the devices published by gpu-handler.
+type HandlerLister interface {
+	ListDevices(ctx context.Context) ([]nodecoord.Device, error)
+}
+
+// HandlerPoller triggers a sync when the data published by a co-located gpu-handler changes.
+type HandlerPoller struct {
+	log      *log.Logger
+	lister   HandlerLister
+	interval time.Duration
+}
+
+// NewHandlerPoller constructs a poller for the coordination socket.
+func NewHandlerPoller(lister HandlerLister, log *log.Logger) *HandlerPoller {
+	return &HandlerPoller{log: log, lister: lister, interval: defaultHandlerPollInterval}
+}
+
+// Run polls gpu-handler until ctx is done.
+// Unreachable handler is not an error: it is expected while the container restarts.
+func (p *HandlerPoller) Run(ctx context.Context, notify NotifyFunc) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var last string
+	reachable := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		devices, err := p.lister.ListDevices(ctx)
+		if err != nil {
+			if reachable && p.log != nil {
+				p.log.Warn("gpu-handler became unreachable", logger.SlogErr(err))
+			}
+			reachable = false
+			continue
+		}
+		reachable = true
+
+		raw, err := json.Marshal(devices)
+		if err != nil {
+			continue
+		}
+		if current := string(raw); current != last {
+			last = current
+			notify()
+		}
+	}
+}
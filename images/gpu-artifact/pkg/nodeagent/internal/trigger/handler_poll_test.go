@@ -0,0 +1,80 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
+)
+
+type sequenceLister struct {
+	mu    sync.Mutex
+	calls int
+	steps []func() ([]nodecoord.Device, error)
+}
+
+func (l *sequenceLister) ListDevices(context.Context) ([]nodecoord.Device, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	step := l.steps[len(l.steps)-1]
+	if l.calls < len(l.steps) {
+		step = l.steps[l.calls]
+	}
+	l.calls++
+	return step()
+}
+
+func TestHandlerPollerNotifiesOnChange(t *testing.T) {
+	a30 := []nodecoord.Device{{PCIAddress: "0000:3b:00.0", Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "A30"}}}
+	uuid := []nodecoord.Device{{PCIAddress: "0000:3b:00.0", Nvidia: &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-1"}}}
+	lister := &sequenceLister{steps: []func() ([]nodecoord.Device, error){
+		func() ([]nodecoord.Device, error) { return a30, nil },
+		func() ([]nodecoord.Device, error) { return a30, nil },
+		func() ([]nodecoord.Device, error) { return nil, errors.New("connection refused") },
+		func() ([]nodecoord.Device, error) { return a30, nil },
+		func() ([]nodecoord.Device, error) { return uuid, nil },
+	}}
+
+	poller := &HandlerPoller{lister: lister, interval: time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	notified := make(chan struct{}, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- poller.Run(ctx, func() { notified <- struct{}{} })
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-notified:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected notification %d", i+1)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("poller returned %v", err)
+	}
+	if extra := len(notified); extra != 0 {
+		t.Fatalf("expected two notifications (initial and changed data), got %d more", extra)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicinformer "k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/deckhouse/deckhouse/pkg/log"
+)
+
+const (
+	// AnnotationRescanRequested asks the node agent for an immediate rescan; the value is an RFC 3339 timestamp.
+	AnnotationRescanRequested = "gpu.deckhouse.io/rescan-requested"
+	// AnnotationRescanCompleted is written by the node agent with the start time of the scan that served a request.
+	AnnotationRescanCompleted = "gpu.deckhouse.io/rescan-completed"
+)
+
+var nodesGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+// NodeRescan triggers a sync when the rescan-requested annotation of the agent's Node is newer than the
+// last completed scan, and acknowledges the request once a scan that started after it has finished.
+type NodeRescan struct {
+	log      *log.Logger
+	client   dynamic.Interface
+	nodeName string
+	now      func() time.Time
+
+	mu sync.Mutex
+	// pending is the request value being served and seenAt the local time it was observed.
+	pending string
+	seenAt  time.Time
+	// handled is the last acknowledged request; it keeps a clock-skewed request from triggering again.
+	handled string
+}
+
+// NewNodeRescan constructs a watcher for the annotations of the given node.
+func NewNodeRescan(client dynamic.Interface, nodeName string, log *log.Logger) *NodeRescan {
+	return &NodeRescan{log: log, client: client, nodeName: nodeName, now: time.Now}
+}
+
+// Run watches the Node until ctx is done.
+func (w *NodeRescan) Run(ctx context.Context, notify NotifyFunc) error {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+		w.client,
+		0,
+		metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.nodeName).String()
+		},
+	)
+	informer := factory.ForResource(nodesGVR).Informer()
+	handle := func(obj interface{}) {
+		node, ok := obj.(*unstructured.Unstructured)
+		if !ok || node.GetName() != w.nodeName {
+			return
+		}
+		if w.observe(node.GetAnnotations()) {
+			notify()
+		}
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    handle,
+		UpdateFunc: func(_, obj interface{}) { handle(obj) },
+	}); err != nil {
+		return fmt.Errorf("register node rescan watcher: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("node rescan watcher sync failed")
+	}
+
+	if w.log != nil {
+		w.log.Info("node rescan watcher started", "node", w.nodeName)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// observe records a new rescan request and reports whether it should trigger a sync.
+func (w *NodeRescan) observe(annotations map[string]string) bool {
+	requested := strings.TrimSpace(annotations[AnnotationRescanRequested])
+	if requested == "" {
+		return false
+	}
+	requestedAt, err := time.Parse(time.RFC3339, requested)
+	if err != nil {
+		if w.log != nil {
+			w.log.Warn("ignoring malformed rescan request", "annotation", AnnotationRescanRequested, "value", requested)
+		}
+		return false
+	}
+	if completedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(annotations[AnnotationRescanCompleted])); err == nil && !requestedAt.After(completedAt) {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if requested == w.pending || requested == w.handled {
+		return false
+	}
+	w.pending = requested
+	w.seenAt = w.now()
+	return true
+}
+
+// Acknowledge writes the rescan-completed annotation for the pending request if the scan that started at
+// scanStarted began after the request was observed. Scans that started earlier leave the request pending.
+func (w *NodeRescan) Acknowledge(ctx context.Context, scanStarted time.Time) error {
+	w.mu.Lock()
+	request := w.pending
+	servedBy := request != "" && !w.seenAt.After(scanStarted)
+	w.mu.Unlock()
+	if !servedBy {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{AnnotationRescanCompleted: scanStarted.UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := w.client.Resource(nodesGVR).Patch(ctx, w.nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("acknowledge rescan request on node %s: %w", w.nodeName, err)
+	}
+
+	w.mu.Lock()
+	w.handled = request
+	if w.pending == request {
+		w.pending = ""
+	}
+	w.mu.Unlock()
+	return nil
+}
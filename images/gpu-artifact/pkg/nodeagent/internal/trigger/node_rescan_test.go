@@ -0,0 +1,145 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newRescanNode(annotations map[string]string) *unstructured.Unstructured {
+	node := &unstructured.Unstructured{}
+	node.SetAPIVersion("v1")
+	node.SetKind("Node")
+	node.SetName("node-a")
+	node.SetAnnotations(annotations)
+	return node
+}
+
+func newRescanClient(node *unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{nodesGVR: "NodeList"}, node)
+}
+
+func nodeAnnotations(t *testing.T, w *NodeRescan) map[string]string {
+	t.Helper()
+	node, err := w.client.Resource(nodesGVR).Get(context.Background(), w.nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	return node.GetAnnotations()
+}
+
+func TestNodeRescanAcknowledgeCycle(t *testing.T) {
+	requested := map[string]string{AnnotationRescanRequested: "2025-03-01T10:00:00Z"}
+	client := newRescanClient(newRescanNode(requested))
+	w := NewNodeRescan(client, "node-a", nil)
+	now := time.Date(2025, 3, 1, 10, 0, 5, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	if !w.observe(requested) {
+		t.Fatalf("expected a request without a completed scan to trigger")
+	}
+	if w.observe(requested) {
+		t.Fatalf("expected the same request not to trigger twice")
+	}
+
+	// A scan that was already running when the request arrived does not serve it.
+	if err := w.Acknowledge(context.Background(), now.Add(-time.Second)); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	if _, ok := nodeAnnotations(t, w)[AnnotationRescanCompleted]; ok {
+		t.Fatalf("expected an earlier scan to leave the request pending")
+	}
+
+	scan := now.Add(time.Second)
+	if err := w.Acknowledge(context.Background(), scan); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	annotations := nodeAnnotations(t, w)
+	if got := annotations[AnnotationRescanCompleted]; got != "2025-03-01T10:00:06Z" {
+		t.Fatalf("expected the scan time to be written, got %q", got)
+	}
+	if w.observe(annotations) {
+		t.Fatalf("expected an acknowledged request not to trigger again")
+	}
+
+	// Later acknowledgements without a new request leave the node alone.
+	if err := w.Acknowledge(context.Background(), scan.Add(time.Minute)); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	if got := nodeAnnotations(t, w)[AnnotationRescanCompleted]; got != "2025-03-01T10:00:06Z" {
+		t.Fatalf("expected no acknowledgement without a pending request, got %q", got)
+	}
+
+	annotations[AnnotationRescanRequested] = "2025-03-01T11:00:00Z"
+	if !w.observe(annotations) {
+		t.Fatalf("expected a request newer than the completed scan to trigger")
+	}
+}
+
+func TestNodeRescanIgnoresStaleAndMalformedRequests(t *testing.T) {
+	w := NewNodeRescan(nil, "node-a", nil)
+	for name, annotations := range map[string]map[string]string{
+		"none":      nil,
+		"malformed": {AnnotationRescanRequested: "now please"},
+		"stale": {
+			AnnotationRescanRequested: "2025-03-01T10:00:00Z",
+			AnnotationRescanCompleted: "2025-03-01T10:00:00Z",
+		},
+	} {
+		if w.observe(annotations) {
+			t.Fatalf("%s: expected no rescan", name)
+		}
+	}
+}
+
+func TestNodeRescanRunTriggersOnAnnotationUpdate(t *testing.T) {
+	client := newRescanClient(newRescanNode(nil))
+	w := NewNodeRescan(client, "node-a", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notified := make(chan struct{}, 4)
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx, func() { notified <- struct{}{} }) }()
+
+	node := newRescanNode(map[string]string{AnnotationRescanRequested: time.Now().UTC().Format(time.RFC3339)})
+	deadline := time.After(5 * time.Second)
+	for {
+		if _, err := client.Resource(nodesGVR).Update(ctx, node, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("update node: %v", err)
+		}
+		select {
+		case <-notified:
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatalf("Run returned %v", err)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("expected the annotation to trigger a rescan")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeagent
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/trigger"
+)
+
+// burstSource fires n notifications as soon as the loop starts.
+type burstSource struct{ n int }
+
+func (s burstSource) Run(ctx context.Context, notify trigger.NotifyFunc) error {
+	for i := 0; i < s.n; i++ {
+		notify()
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func runLoop(t *testing.T, sources []trigger.Source, sync func(context.Context) error) context.CancelFunc {
+	t.Helper()
+	loop := &syncLoop{quietPeriod: 20 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- loop.Run(ctx, sources, sync) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("loop returned %v", err)
+		}
+	})
+	return cancel
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSyncLoopDebouncesTriggerBursts(t *testing.T) {
+	var syncs atomic.Int32
+	runLoop(t, []trigger.Source{burstSource{n: 10}}, func(context.Context) error {
+		syncs.Add(1)
+		return nil
+	})
+
+	waitFor(t, "the first sync", func() bool { return syncs.Load() >= 1 })
+	time.Sleep(100 * time.Millisecond)
+	if got := syncs.Load(); got != 1 {
+		t.Fatalf("expected a burst of triggers to collapse into one scan, got %d", got)
+	}
+}
+
+func TestSyncLoopDoesNotOverlapScans(t *testing.T) {
+	rescan := make(chan struct{})
+	release := make(chan struct{})
+	var running, maxRunning, syncs atomic.Int32
+	runLoop(t, []trigger.Source{trigger.NewChannel("test", rescan, nil)}, func(context.Context) error {
+		if n := running.Add(1); n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		defer running.Add(-1)
+		if syncs.Add(1) == 1 {
+			<-release
+		}
+		return nil
+	})
+
+	waitFor(t, "the first scan to start", func() bool { return running.Load() == 1 })
+	for i := 0; i < 3; i++ {
+		rescan <- struct{}{}
+	}
+	time.Sleep(60 * time.Millisecond)
+	if got := syncs.Load(); got != 1 {
+		t.Fatalf("expected triggers during a scan to wait for it, got %d scans", got)
+	}
+	close(release)
+
+	waitFor(t, "the follow-up scan", func() bool { return syncs.Load() == 2 })
+	time.Sleep(60 * time.Millisecond)
+	if got := syncs.Load(); got != 2 {
+		t.Fatalf("expected one follow-up scan for the queued triggers, got %d scans", got)
+	}
+	if got := maxRunning.Load(); got != 1 {
+		t.Fatalf("expected scans never to overlap, saw %d at once", got)
+	}
+}
+
+func TestSyncLoopRescansOnSignalChannel(t *testing.T) {
+	rescan := make(chan struct{}, 1)
+	var syncs atomic.Int32
+	runLoop(t, []trigger.Source{trigger.NewChannel("signal", rescan, nil)}, func(context.Context) error {
+		syncs.Add(1)
+		return nil
+	})
+
+	waitFor(t, "the startup scan", func() bool { return syncs.Load() == 1 })
+	rescan <- struct{}{}
+	waitFor(t, "the signal-triggered scan", func() bool { return syncs.Load() == 2 })
+}
@@ -19,17 +19,25 @@ package nodeagent
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/state"
 )
 
-// Run starts the event-driven sync loop.
-func (a *Agent) Run(ctx context.Context) error {
+// Run starts the event-driven sync loop. Every value received on rescan requests an immediate rescan;
+// the channel may be nil.
+func (a *Agent) Run(ctx context.Context, rescan <-chan struct{}) error {
 	bootstrap := newBootstrapService(a.cfg, a.log, a.scheme, a.store, a.pci, a.hostInfo)
 	if err := bootstrap.validate(); err != nil {
 		return err
 	}
+	if a.checkpoint != nil {
+		if err := a.checkpoint.Load(); err != nil {
+			a.log.Warn("ignoring device checkpoint", logger.SlogErr(err))
+		}
+		bootstrap.checkpoint = a.checkpoint
+	}
 	result, err := bootstrap.Start()
 	if err != nil {
 		return err
@@ -39,10 +47,11 @@ func (a *Agent) Run(ctx context.Context) error {
 	}
 	a.steps = result.steps
 
-	sources, err := buildSources(a.cfg, a.log)
+	sources, nodeRescan, err := buildSources(a.cfg, a.log, rescan)
 	if err != nil {
 		return err
 	}
+	a.rescan = nodeRescan
 
 	loop := newSyncLoop(a.log)
 	return loop.Run(ctx, sources, a.sync)
@@ -50,10 +59,16 @@ func (a *Agent) Run(ctx context.Context) error {
 
 func (a *Agent) sync(ctx context.Context) error {
 	ctx = logger.ToContext(ctx, slog.Default())
+	started := time.Now()
 	st := state.New(a.cfg.NodeName)
 	if _, err := a.steps.Run(ctx, st); err != nil {
 		return err
 	}
 	a.log.Info("sync completed", "devices", len(st.Devices()))
+	if a.rescan != nil {
+		if err := a.rescan.Acknowledge(ctx, started); err != nil {
+			a.log.Warn("rescan acknowledgement failed", logger.SlogErr(err))
+		}
+	}
 	return nil
 }
@@ -24,16 +24,27 @@ import (
 	"github.com/deckhouse/deckhouse/pkg/log"
 
 	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodeagent/internal/trigger"
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/nodecoord"
 )
 
-func buildSources(cfg Config, log *log.Logger) ([]trigger.Source, error) {
+// buildSources returns the sync triggers and the node annotation watcher that acknowledges on-demand rescans.
+func buildSources(cfg Config, log *log.Logger, rescan <-chan struct{}) ([]trigger.Source, *trigger.NodeRescan, error) {
 	dyn, err := dynamic.NewForConfig(cfg.KubeConfig)
 	if err != nil {
-		return nil, fmt.Errorf("create dynamic client: %w", err)
+		return nil, nil, fmt.Errorf("create dynamic client: %w", err)
 	}
 
-	return []trigger.Source{
+	nodeRescan := trigger.NewNodeRescan(dyn, cfg.NodeName, log)
+	sources := []trigger.Source{
 		trigger.NewUdevPCI(log),
 		trigger.NewPhysicalGPUWatcher(dyn, cfg.NodeName, log),
-	}, nil
+		nodeRescan,
+	}
+	if rescan != nil {
+		sources = append(sources, trigger.NewChannel("signal", rescan, log))
+	}
+	if cfg.CoLocated {
+		sources = append(sources, trigger.NewHandlerPoller(nodecoord.NewClient(cfg.SocketPath), log))
+	}
+	return sources, nodeRescan, nil
 }
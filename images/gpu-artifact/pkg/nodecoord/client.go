@@ -0,0 +1,68 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Client queries gpu-handler over the coordination socket.
+type Client struct {
+	path    string
+	timeout time.Duration
+}
+
+// NewClient creates a client for the socket path.
+func NewClient(path string) *Client {
+	return &Client{path: path, timeout: requestTimeout}
+}
+
+// ListDevices returns the NVML data currently published by gpu-handler.
+func (c *Client) ListDevices(ctx context.Context) ([]Device, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "unix", c.path)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", c.path, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(Request{Version: ProtocolVersion, Method: MethodListDevices}); err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	if resp.Version != ProtocolVersion {
+		return nil, fmt.Errorf("unsupported protocol version %d", resp.Version)
+	}
+	return resp.Devices, nil
+}
@@ -0,0 +1,21 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package nodecoord defines how gpu-handler and gpu-node-agent coordinate when they run in the same pod.
+//
+// In co-located mode gpu-handler serves the NVML data it reads over a unix socket and gpu-node-agent becomes the
+// single writer of PhysicalGPU status, merging that data with its PCI scan. With the mode off both binaries keep
+// working standalone.
+package nodecoord
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+)
+
+// Merge folds the NVML data served by gpu-handler into a PhysicalGPU status built from the PCI scan.
+//
+// Precedence:
+//   - the PCI scan owns pciInfo (address, NUMA node, PCI IDs and names) and currentState.driverType;
+//   - NVML owns capabilities (product name, memory, MIG profiles) and currentState.nvidia.
+//
+// A nil device leaves the NVML-owned fields as they are, so a temporarily unreachable handler never wipes them.
+func Merge(status *gpuv1alpha1.PhysicalGPUStatus, device *Device) {
+	if status == nil || device == nil {
+		return
+	}
+	if device.Capabilities != nil {
+		status.Capabilities = device.Capabilities.DeepCopy()
+	}
+
+	if device.Nvidia == nil {
+		if status.CurrentState != nil {
+			status.CurrentState.Nvidia = nil
+		}
+		return
+	}
+	if status.CurrentState == nil {
+		status.CurrentState = &gpuv1alpha1.GPUCurrentState{}
+	}
+	status.CurrentState.Nvidia = device.Nvidia.DeepCopy()
+}
+
+// Index keys devices by normalized PCI address.
+func Index(devices []Device) map[string]Device {
+	index := make(map[string]Device, len(devices))
+	for _, device := range devices {
+		index[NormalizeAddress(device.PCIAddress)] = device
+	}
+	return index
+}
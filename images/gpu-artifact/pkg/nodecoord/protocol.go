@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+)
+
+const (
+	// CoLocatedEnv enables co-located mode in both binaries when set to a true value.
+	CoLocatedEnv = "GPU_NODE_COLOCATED"
+	// SocketPathEnv overrides DefaultSocketPath.
+	SocketPathEnv = "GPU_NODE_SOCKET"
+	// DefaultSocketPath is where gpu-handler listens; the directory is shared by the containers of the pod.
+	DefaultSocketPath = "/run/gpu-node/handler.sock"
+
+	// ProtocolVersion is bumped on incompatible changes of the request or response payload.
+	ProtocolVersion = 1
+	// MethodListDevices returns the latest NVML data for every device known to gpu-handler.
+	MethodListDevices = "ListDevices"
+)
+
+// Request is a single newline-terminated JSON request sent over the socket.
+type Request struct {
+	Version int    `json:"version"`
+	Method  string `json:"method"`
+}
+
+// Response answers a Request; Error is set instead of Devices when the request failed.
+type Response struct {
+	Version int      `json:"version"`
+	Devices []Device `json:"devices,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Device carries the NVML-derived data gpu-handler collected for one PCI device.
+type Device struct {
+	// PCIAddress identifies the device; it is matched against the PCI scan with NormalizeAddress.
+	PCIAddress string `json:"pciAddress"`
+	// Capabilities holds NVML capabilities (product name, memory, MIG profiles).
+	Capabilities *gpuv1alpha1.GPUCapabilities `json:"capabilities,omitempty"`
+	// Nvidia holds the NVML view of the current state. Nil means the device is not driven by the NVIDIA driver.
+	Nvidia *gpuv1alpha1.NvidiaCurrentState `json:"nvidia,omitempty"`
+}
+
+// Settings controls co-located mode.
+type Settings struct {
+	CoLocated  bool
+	SocketPath string
+}
+
+// SettingsFromEnv reads co-located mode settings using the provided lookup (os.Getenv in production).
+func SettingsFromEnv(getenv func(string) string) Settings {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	enabled, _ := strconv.ParseBool(strings.TrimSpace(getenv(CoLocatedEnv)))
+	path := strings.TrimSpace(getenv(SocketPathEnv))
+	if path == "" {
+		path = DefaultSocketPath
+	}
+	return Settings{CoLocated: enabled, SocketPath: path}
+}
+
+// NormalizeAddress converts sysfs ("0000:3b:00.0") and NVML ("00000000:3B:00.0") PCI bus IDs to one form.
+func NormalizeAddress(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	domain, rest, ok := strings.Cut(address, ":")
+	if !ok || !strings.Contains(rest, ":") {
+		return address
+	}
+	if len(domain) > 4 {
+		domain = domain[len(domain)-4:]
+	}
+	for len(domain) < 4 {
+		domain = "0" + domain
+	}
+	return domain + ":" + rest
+}
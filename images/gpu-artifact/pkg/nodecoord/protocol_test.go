@@ -0,0 +1,148 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	"testing"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestSettingsFromEnv(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	if got := SettingsFromEnv(getenv); got.CoLocated || got.SocketPath != DefaultSocketPath {
+		t.Fatalf("unexpected defaults %+v", got)
+	}
+
+	env[CoLocatedEnv] = "true"
+	env[SocketPathEnv] = "/tmp/handler.sock"
+	if got := SettingsFromEnv(getenv); !got.CoLocated || got.SocketPath != "/tmp/handler.sock" {
+		t.Fatalf("unexpected settings %+v", got)
+	}
+
+	env[CoLocatedEnv] = "maybe"
+	if got := SettingsFromEnv(getenv); got.CoLocated {
+		t.Fatalf("invalid flag must keep standalone mode, got %+v", got)
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	cases := map[string]string{
+		"0000:3b:00.0":     "0000:3b:00.0",
+		"00000000:3B:00.0": "0000:3b:00.0",
+		"3b:00.0":          "3b:00.0",
+		"1:3b:00.0":        "0001:3b:00.0",
+		" 0000:3B:00.0 ":   "0000:3b:00.0",
+	}
+	for in, want := range cases {
+		if got := NormalizeAddress(in); got != want {
+			t.Fatalf("NormalizeAddress(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func scannedStatus() *gpuv1alpha1.PhysicalGPUStatus {
+	return &gpuv1alpha1.PhysicalGPUStatus{
+		PCIInfo: &gpuv1alpha1.PCIInfo{
+			Address:  "0000:3b:00.0",
+			NUMANode: int32Ptr(1),
+			Device:   &gpuv1alpha1.PCIDeviceInfo{ID: "20b7", Name: "GA100GL [A30 PCIe]"},
+		},
+		Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "stale", MemoryMiB: int64Ptr(1)},
+		CurrentState: &gpuv1alpha1.GPUCurrentState{DriverType: gpuv1alpha1.DriverTypeNvidia},
+	}
+}
+
+func TestMergePrecedence(t *testing.T) {
+	status := scannedStatus()
+	Merge(status, &Device{
+		PCIAddress:   "00000000:3B:00.0",
+		Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "NVIDIA A30", MemoryMiB: int64Ptr(24576)},
+		Nvidia:       &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-1", DriverVersion: "550.54"},
+	})
+
+	if status.PCIInfo.Address != "0000:3b:00.0" || status.PCIInfo.NUMANode == nil || *status.PCIInfo.NUMANode != 1 {
+		t.Fatalf("PCI scan must own address and NUMA node, got %+v", status.PCIInfo)
+	}
+	if status.PCIInfo.Device.Name != "GA100GL [A30 PCIe]" {
+		t.Fatalf("PCI names must be kept, got %+v", status.PCIInfo.Device)
+	}
+	if status.Capabilities.ProductName != "NVIDIA A30" || *status.Capabilities.MemoryMiB != 24576 {
+		t.Fatalf("NVML must own product and memory, got %+v", status.Capabilities)
+	}
+	if status.CurrentState.DriverType != gpuv1alpha1.DriverTypeNvidia || status.CurrentState.Nvidia.GPUUUID != "GPU-1" {
+		t.Fatalf("unexpected current state %+v", status.CurrentState)
+	}
+}
+
+func TestMergeWithoutHandlerDataKeepsNVMLFields(t *testing.T) {
+	status := scannedStatus()
+	status.CurrentState.Nvidia = &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-1"}
+	Merge(status, nil)
+	if status.Capabilities.ProductName != "stale" || status.CurrentState.Nvidia == nil {
+		t.Fatalf("nil device must not touch NVML fields, got %+v", status)
+	}
+}
+
+func TestMergeClearsNvidiaStateForNonNvidiaDriver(t *testing.T) {
+	status := scannedStatus()
+	status.CurrentState.Nvidia = &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-1"}
+	Merge(status, &Device{PCIAddress: "0000:3b:00.0"})
+	if status.CurrentState.Nvidia != nil {
+		t.Fatalf("expected nvidia state to be cleared, got %+v", status.CurrentState.Nvidia)
+	}
+	if status.Capabilities.ProductName != "stale" {
+		t.Fatalf("capabilities must be kept when the handler has none, got %+v", status.Capabilities)
+	}
+
+	empty := &gpuv1alpha1.PhysicalGPUStatus{}
+	Merge(empty, &Device{Nvidia: &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-2"}})
+	if empty.CurrentState == nil || empty.CurrentState.Nvidia.GPUUUID != "GPU-2" {
+		t.Fatalf("expected current state to be created, got %+v", empty.CurrentState)
+	}
+}
+
+func TestMergeCopiesHandlerData(t *testing.T) {
+	device := &Device{Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "A30"}}
+	status := scannedStatus()
+	Merge(status, device)
+	status.Capabilities.ProductName = "changed"
+	if device.Capabilities.ProductName != "A30" {
+		t.Fatal("merge must not alias handler data")
+	}
+}
+
+func TestIndexAndStore(t *testing.T) {
+	store := NewStore()
+	store.Publish(Device{PCIAddress: "0000:82:00.0"})
+	store.Publish(Device{PCIAddress: "00000000:3B:00.0"})
+	store.Publish(Device{PCIAddress: "0000:3b:00.0", Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "A30"}})
+
+	devices := store.Devices()
+	if len(devices) != 2 || devices[0].PCIAddress != "0000:3b:00.0" || devices[1].PCIAddress != "0000:82:00.0" {
+		t.Fatalf("unexpected devices %+v", devices)
+	}
+	index := Index(devices)
+	if index["0000:3b:00.0"].Capabilities == nil {
+		t.Fatalf("expected latest publish to win, got %+v", index)
+	}
+}
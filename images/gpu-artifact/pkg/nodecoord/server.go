@@ -0,0 +1,130 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/deckhouse/deckhouse/pkg/log"
+
+	"github.com/aleksandr-podmoskovniy/gpu/pkg/logger"
+)
+
+const (
+	requestTimeout  = 5 * time.Second
+	maxRequestBytes = 4096
+)
+
+// DeviceSource returns the devices served by ListDevices.
+type DeviceSource interface {
+	Devices() []Device
+}
+
+// Server answers node-agent requests on a unix socket.
+type Server struct {
+	path   string
+	source DeviceSource
+	log    *log.Logger
+}
+
+// NewServer creates a server for the socket path.
+func NewServer(path string, source DeviceSource, log *log.Logger) *Server {
+	return &Server{path: path, source: source, log: log}
+}
+
+// Run listens until the context is cancelled. A stale socket left by a previous run is removed first.
+func (s *Server) Run(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return fmt.Errorf("create socket dir: %w", err)
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "unix", s.path)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", s.path, err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+	if s.log != nil {
+		s.log.Info("serving node coordination socket", "path", s.path)
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	var req Request
+	resp := Response{Version: ProtocolVersion}
+	line, err := bufio.NewReader(&limitedReader{r: conn, n: maxRequestBytes}).ReadBytes('\n')
+	switch {
+	case err != nil:
+		resp.Error = fmt.Sprintf("read request: %v", err)
+	case json.Unmarshal(line, &req) != nil:
+		resp.Error = "malformed request"
+	case req.Version != ProtocolVersion:
+		resp.Error = fmt.Sprintf("unsupported protocol version %d", req.Version)
+	case req.Method != MethodListDevices:
+		resp.Error = fmt.Sprintf("unknown method %q", req.Method)
+	default:
+		resp.Devices = s.source.Devices()
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil && s.log != nil {
+		s.log.Warn("failed to write coordination response", logger.SlogErr(err))
+	}
+}
+
+type limitedReader struct {
+	r net.Conn
+	n int
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, errors.New("request too large")
+	}
+	if len(p) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= n
+	return n, err
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gpuv1alpha1 "github.com/aleksandr-podmoskovniy/gpu/api/v1alpha1"
+)
+
+// socketPath keeps the path short: unix socket paths are limited to about 100 bytes.
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "nc")
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "run", "h.sock")
+}
+
+func startServer(t *testing.T, path string, source DeviceSource) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(path, source, nil).Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("server returned %v", err)
+		}
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClientServerRoundTrip(t *testing.T) {
+	path := socketPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// A socket file left by a crashed handler must not block the restart.
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("stale socket: %v", err)
+	}
+
+	store := NewStore()
+	store.Publish(Device{
+		PCIAddress:   "0000:3b:00.0",
+		Capabilities: &gpuv1alpha1.GPUCapabilities{ProductName: "NVIDIA A30"},
+		Nvidia:       &gpuv1alpha1.NvidiaCurrentState{GPUUUID: "GPU-1"},
+	})
+	startServer(t, path, store)
+
+	devices, err := NewClient(path).ListDevices(context.Background())
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if len(devices) != 1 || devices[0].Capabilities.ProductName != "NVIDIA A30" || devices[0].Nvidia.GPUUUID != "GPU-1" {
+		t.Fatalf("unexpected devices %+v", devices)
+	}
+}
+
+func TestServerRejectsBadRequests(t *testing.T) {
+	path := socketPath(t)
+	startServer(t, path, NewStore())
+
+	call := func(payload string) Response {
+		t.Helper()
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+		if _, err := conn.Write([]byte(payload + "\n")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		var resp Response
+		if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+
+	cases := map[string]string{
+		`not json`:                              "malformed request",
+		`{"version":99,"method":"ListDevices"}`: "unsupported protocol version",
+		`{"version":1,"method":"Drop"}`:         "unknown method",
+		strings.Repeat("x", 2*maxRequestBytes):  "read request",
+	}
+	for payload, want := range cases {
+		if resp := call(payload); !strings.Contains(resp.Error, want) || resp.Version != ProtocolVersion {
+			t.Fatalf("payload %.20q: expected error %q, got %+v", payload, want, resp)
+		}
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	if _, err := NewClient(filepath.Join(t.TempDir(), "missing.sock")).ListDevices(context.Background()); err == nil {
+		t.Fatal("expected dial error")
+	}
+
+	path := socketPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = bufio.NewReader(conn).ReadBytes('\n')
+			_ = json.NewEncoder(conn).Encode(Response{Version: ProtocolVersion + 1})
+			conn.Close()
+		}
+	}()
+	if _, err := NewClient(path).ListDevices(context.Background()); err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecoord
+
+import (
+	"sort"
+	"sync"
+)
+
+// Store keeps the latest Device published for every PCI address. It is safe for concurrent use.
+type Store struct {
+	mu      sync.RWMutex
+	devices map[string]Device
+}
+
+// NewStore creates an empty store.
+func NewStore() *Store {
+	return &Store{devices: map[string]Device{}}
+}
+
+// Publish records the latest data for a device.
+func (s *Store) Publish(device Device) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices[NormalizeAddress(device.PCIAddress)] = device
+}
+
+// Devices returns the published devices ordered by PCI address.
+func (s *Store) Devices() []Device {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Device, 0, len(s.devices))
+	for _, device := range s.devices {
+		out = append(out, device)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return NormalizeAddress(out[i].PCIAddress) < NormalizeAddress(out[j].PCIAddress)
+	})
+	return out
+}
@@ -19,6 +19,7 @@ package pci
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -51,3 +52,16 @@ func readDriverName(devicePath string) string {
 	}
 	return filepath.Base(link)
 }
+
+func readNUMANode(devicePath string) *int32 {
+	raw, err := readTrim(filepath.Join(devicePath, "numa_node"))
+	if err != nil {
+		return nil
+	}
+	node, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || node < 0 {
+		return nil
+	}
+	value := int32(node)
+	return &value
+}
@@ -35,6 +35,8 @@ type Device struct {
 	VendorID   string
 	DeviceID   string
 	DriverName string
+	// NUMANode is nil when sysfs reports no NUMA affinity (-1) or the file is missing.
+	NUMANode *int32
 }
 
 // Reader lists PCI devices.
@@ -106,6 +108,7 @@ func (r *SysfsReader) List(ctx context.Context) ([]Device, error) {
 		if driverName := readDriverName(devicePath); driverName != "" {
 			dev.DriverName = driverName
 		}
+		dev.NUMANode = readNUMANode(devicePath)
 
 		devices = append(devices, dev)
 	}
@@ -39,6 +39,7 @@ func TestSysfsReaderList(t *testing.T) {
 	testutil.WriteFile(t, filepath.Join(gpuDir, "class"), "0x030200")
 	testutil.WriteFile(t, filepath.Join(gpuDir, "vendor"), "0x10de")
 	testutil.WriteFile(t, filepath.Join(gpuDir, "device"), "0x20b7")
+	testutil.WriteFile(t, filepath.Join(gpuDir, "numa_node"), "1\n")
 	if err := os.Symlink("/sys/bus/pci/drivers/nvidia", filepath.Join(gpuDir, "driver")); err != nil {
 		t.Fatalf("symlink driver: %v", err)
 	}
@@ -68,6 +69,24 @@ func TestSysfsReaderList(t *testing.T) {
 	if dev.DriverName != "nvidia" {
 		t.Fatalf("unexpected driver name %q", dev.DriverName)
 	}
+	if dev.NUMANode == nil || *dev.NUMANode != 1 {
+		t.Fatalf("unexpected numa node %v", dev.NUMANode)
+	}
+}
+
+func TestReadNUMANode(t *testing.T) {
+	dir := t.TempDir()
+	if node := readNUMANode(dir); node != nil {
+		t.Fatalf("expected nil for missing file, got %d", *node)
+	}
+	testutil.WriteFile(t, filepath.Join(dir, "numa_node"), "-1")
+	if node := readNUMANode(dir); node != nil {
+		t.Fatalf("expected nil for -1, got %d", *node)
+	}
+	testutil.WriteFile(t, filepath.Join(dir, "numa_node"), "garbage")
+	if node := readNUMANode(dir); node != nil {
+		t.Fatalf("expected nil for garbage, got %d", *node)
+	}
 }
 
 func TestNormalizeHexID(t *testing.T) {
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pciids
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names holds resolved PCI names for a single device.
+type Names struct {
+	Class  string
+	Vendor string
+	Device string
+}
+
+// Cache resolves PCI names from the first readable pci.ids candidate.
+// The database is parsed lazily and only for vendors that were looked up,
+// resolved names are memoized, and everything is dropped when the file's
+// modification time changes.
+type Cache struct {
+	paths []string
+
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	indexed map[string]struct{}
+	res     *Resolver
+	names   map[string]Names
+}
+
+// NewCache creates a cache over the given pci.ids candidates, tried in order.
+func NewCache(paths []string) *Cache {
+	cleaned := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path != "" {
+			cleaned = append(cleaned, path)
+		}
+	}
+	return &Cache{paths: cleaned}
+}
+
+// Source returns the pci.ids file currently in use, or an empty string when none is readable.
+func (c *Cache) Source() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshLocked()
+	return c.path
+}
+
+// Lookup resolves names for a device. When no database is readable or the device is not listed,
+// the device name falls back to "unknown (vendor:device)".
+func (c *Cache) Lookup(classCode, vendorID, deviceID string) Names {
+	vendorID = strings.ToLower(vendorID)
+	deviceID = strings.ToLower(deviceID)
+	classCode = strings.ToLower(classCode)
+	if c == nil {
+		return Names{Device: UnknownDeviceName(vendorID, deviceID)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshLocked()
+
+	key := classCode + "|" + vendorID + "|" + deviceID
+	if names, ok := c.names[key]; ok {
+		return names
+	}
+
+	names := Names{}
+	if res := c.resolverLocked(vendorID); res != nil {
+		names = Names{
+			Class:  res.ClassName(classCode),
+			Vendor: res.VendorName(vendorID),
+			Device: res.DeviceName(vendorID, deviceID),
+		}
+	}
+	if names.Device == "" {
+		names.Device = UnknownDeviceName(vendorID, deviceID)
+	}
+	if c.path != "" {
+		c.names[key] = names
+	}
+	return names
+}
+
+// refreshLocked picks the first readable candidate and drops cached data when the file changed.
+func (c *Cache) refreshLocked() {
+	path, modTime := c.pickSource()
+	if path == c.path && modTime.Equal(c.modTime) {
+		return
+	}
+	c.path = path
+	c.modTime = modTime
+	c.indexed = map[string]struct{}{}
+	c.res = nil
+	c.names = map[string]Names{}
+}
+
+// resolverLocked returns a resolver that covers vendorID, re-parsing the file when the vendor
+// has not been indexed yet. A database that fails to parse is treated as missing.
+func (c *Cache) resolverLocked(vendorID string) *Resolver {
+	if c.path == "" {
+		return nil
+	}
+	if _, ok := c.indexed[vendorID]; ok {
+		return c.res
+	}
+
+	vendors := make(map[string]struct{}, len(c.indexed)+1)
+	for id := range c.indexed {
+		vendors[id] = struct{}{}
+	}
+	vendors[vendorID] = struct{}{}
+
+	res, err := load(c.path, vendors)
+	if err != nil {
+		return c.res
+	}
+	c.res = res
+	c.indexed = vendors
+	return c.res
+}
+
+func (c *Cache) pickSource() (string, time.Time) {
+	for _, path := range c.paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if path == c.path && info.ModTime().Equal(c.modTime) {
+			return path, c.modTime
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		file.Close()
+		return path, info.ModTime()
+	}
+	return "", time.Time{}
+}
+
+// UnknownDeviceName is the placeholder name for devices missing from pci.ids.
+func UnknownDeviceName(vendorID, deviceID string) string {
+	return fmt.Sprintf("unknown (%s:%s)", strings.ToLower(vendorID), strings.ToLower(deviceID))
+}
+
+// IsUnknownDeviceName reports whether name is a placeholder produced by UnknownDeviceName.
+func IsUnknownDeviceName(name string) bool {
+	return strings.HasPrefix(name, "unknown (") && strings.HasSuffix(name, ")")
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pciids
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheLookup(t *testing.T) {
+	cache := NewCache([]string{"", filepath.Join(t.TempDir(), "missing"), filepath.Join("testdata", "pci.ids")})
+	if got := cache.Source(); got != filepath.Join("testdata", "pci.ids") {
+		t.Fatalf("unexpected source %q", got)
+	}
+
+	names := cache.Lookup("0302", "10DE", "20B7")
+	want := Names{Class: "3D controller", Vendor: "NVIDIA Corporation", Device: "GA100GL [A30 PCIe]"}
+	if names != want {
+		t.Fatalf("unexpected names %+v", names)
+	}
+	if got := cache.Lookup("0302", "10de", "1db5").Device; got != "unknown (10de:1db5)" {
+		t.Fatalf("unexpected fallback for unlisted device %q", got)
+	}
+}
+
+func TestCacheLookupGzip(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "pci.ids"))
+	if err != nil {
+		t.Fatalf("read pci.ids: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "pci.ids.gz")
+	writeGzip(t, path, raw)
+
+	names := NewCache([]string{path}).Lookup("0300", "10de", "20b7")
+	if names.Device != "GA100GL [A30 PCIe]" || names.Class != "VGA compatible controller" {
+		t.Fatalf("unexpected names from gz database %+v", names)
+	}
+
+	res, err := Load(path)
+	if err != nil {
+		t.Fatalf("load gz: %v", err)
+	}
+	if got := res.VendorName("10de"); got != "NVIDIA Corporation" {
+		t.Fatalf("unexpected vendor name %q", got)
+	}
+}
+
+func TestCacheLookupWithoutDatabase(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "pci.ids.gz")
+	if err := os.WriteFile(broken, []byte("not gzip"), 0o600); err != nil {
+		t.Fatalf("write broken gz: %v", err)
+	}
+
+	for name, cache := range map[string]*Cache{
+		"missing": NewCache([]string{filepath.Join(dir, "missing")}),
+		"broken":  NewCache([]string{broken}),
+		"nil":     nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			names := cache.Lookup("0302", "10de", "1db5")
+			if names != (Names{Device: "unknown (10de:1db5)"}) {
+				t.Fatalf("unexpected fallback names %+v", names)
+			}
+		})
+	}
+}
+
+func TestCacheIndexesOnlyRequestedVendors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pci.ids")
+	content := "10de  NVIDIA Corporation\n\t20b7  GA100GL [A30 PCIe]\n1002  Advanced Micro Devices, Inc. [AMD/ATI]\n\t740f  Aldebaran [Instinct MI210]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write pci.ids: %v", err)
+	}
+
+	cache := NewCache([]string{path})
+	cache.Lookup("0302", "10de", "20b7")
+	if _, ok := cache.res.devices["1002"]; ok {
+		t.Fatalf("expected devices of unrequested vendor to be skipped")
+	}
+	if got := cache.Lookup("0380", "1002", "740f").Device; got != "Aldebaran [Instinct MI210]" {
+		t.Fatalf("unexpected device name after indexing another vendor %q", got)
+	}
+	if got := cache.Lookup("0302", "10de", "20b7").Device; got != "GA100GL [A30 PCIe]" {
+		t.Fatalf("previously indexed vendor lost after re-index: %q", got)
+	}
+}
+
+func TestCacheInvalidatesOnModTimeChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pci.ids")
+	if err := os.WriteFile(path, []byte("10de  NVIDIA Corporation\n\t1db5  GV100GL [Tesla V100 SXM2 32GB]\n"), 0o600); err != nil {
+		t.Fatalf("write pci.ids: %v", err)
+	}
+
+	stamp := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	cache := NewCache([]string{path})
+	if got := cache.Lookup("0302", "10de", "1db5").Device; got != "GV100GL [Tesla V100 SXM2 32GB]" {
+		t.Fatalf("unexpected device name %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte("10de  NVIDIA Corporation\n\t1db5  Tesla V100\n"), 0o600); err != nil {
+		t.Fatalf("rewrite pci.ids: %v", err)
+	}
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got := cache.Lookup("0302", "10de", "1db5").Device; got != "GV100GL [Tesla V100 SXM2 32GB]" {
+		t.Fatalf("expected cached name while mtime is unchanged, got %q", got)
+	}
+
+	if err := os.Chtimes(path, time.Now(), time.Now()); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got := cache.Lookup("0302", "10de", "1db5").Device; got != "Tesla V100" {
+		t.Fatalf("expected refreshed name after mtime change, got %q", got)
+	}
+}
+
+func TestIsUnknownDeviceName(t *testing.T) {
+	if !IsUnknownDeviceName(UnknownDeviceName("10DE", "1DB5")) {
+		t.Fatalf("expected placeholder to be recognized")
+	}
+	if IsUnknownDeviceName("GA100GL [A30 PCIe]") {
+		t.Fatalf("real device name recognized as placeholder")
+	}
+}
+
+func BenchmarkLoadFull(b *testing.B) {
+	path := writeSyntheticDatabase(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCacheFirstLookup(b *testing.B) {
+	path := writeSyntheticDatabase(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewCache([]string{path}).Lookup("0302", "10de", "0001")
+	}
+}
+
+func BenchmarkCacheCachedLookup(b *testing.B) {
+	path := writeSyntheticDatabase(b)
+	cache := NewCache([]string{path})
+	cache.Lookup("0302", "10de", "0001")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Lookup("0302", "10de", "0001")
+	}
+}
+
+// writeSyntheticDatabase produces a pci.ids of roughly the upstream size: a few thousand vendors
+// with a dozen devices and subsystems each, followed by the class section.
+func writeSyntheticDatabase(b *testing.B) string {
+	b.Helper()
+	var buf bytes.Buffer
+	for v := 0; v < 2500; v++ {
+		vendor := fmt.Sprintf("%04x", v)
+		if v == 0x10de {
+			vendor = "10de"
+		}
+		fmt.Fprintf(&buf, "%s  Vendor %d\n", vendor, v)
+		for d := 0; d < 12; d++ {
+			fmt.Fprintf(&buf, "\t%04x  Device %d of vendor %d\n", d, d, v)
+			fmt.Fprintf(&buf, "\t\t%s %04x  Subsystem %d\n", vendor, d, d)
+		}
+	}
+	buf.WriteString("10de  NVIDIA Corporation\n\t0001  Test GPU\n")
+	buf.WriteString("C 03  Display controller\n\t00  VGA compatible controller\n\t02  3D controller\n")
+
+	path := filepath.Join(b.TempDir(), "pci.ids")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		b.Fatalf("write pci.ids: %v", err)
+	}
+	return path
+}
+
+func writeGzip(t *testing.T, path string, content []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -18,7 +18,9 @@ package pciids
 
 import (
 	"bufio"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -29,25 +31,54 @@ func Load(path string) (*Resolver, error) {
 		return nil, fmt.Errorf("pci.ids path is empty")
 	}
 
-	file, err := os.Open(path)
+	return load(path, nil)
+}
+
+// load parses path, keeping device entries only for the given vendors (all when nil).
+func load(path string, vendors map[string]struct{}) (*Resolver, error) {
+	reader, err := open(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	defer reader.Close()
 
-	res := &Resolver{
-		vendors:   map[string]string{},
-		devices:   map[string]map[string]string{},
-		classBase: map[string]string{},
-		classSub:  map[string]map[string]string{},
+	res := newResolver()
+	scanner := bufio.NewScanner(reader)
+	if err := parsePCIIDs(scanner, res, vendors); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
 	}
 
-	scanner := bufio.NewScanner(file)
-	if err := parsePCIIDs(scanner, res); err != nil {
+	return res, nil
+}
+
+// open returns a reader over the pci.ids content, transparently decompressing *.gz files.
+func open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
 		return nil, err
 	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	return gzipReadCloser{Reader: gz, file: file}, nil
+}
 
-	return res, nil
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipReadCloser) Close() error {
+	gzErr := g.Reader.Close()
+	if err := g.file.Close(); err != nil {
+		return err
+	}
+	return gzErr
 }
 
 // LoadFirst tries to load the first existing pci.ids from paths.
@@ -21,7 +21,9 @@ import (
 	"strings"
 )
 
-func parsePCIIDs(scanner *bufio.Scanner, res *Resolver) error {
+// parsePCIIDs fills res from scanner. When vendors is not nil, device entries are kept only for
+// the listed vendor IDs; vendor and class names are always kept since they are cheap.
+func parsePCIIDs(scanner *bufio.Scanner, res *Resolver, vendors map[string]struct{}) error {
 	mode := ""
 	currentVendor := ""
 	currentClass := ""
@@ -70,6 +72,9 @@ func parsePCIIDs(scanner *bufio.Scanner, res *Resolver) error {
 		if indentTabs >= 2 {
 			continue
 		}
+		if mode == "vendor" && !wantVendor(vendors, currentVendor) {
+			continue
+		}
 		if indentTabs == 1 || (indentTabs == 0 && indentSpaces > 0) {
 			fields := strings.Fields(trimmed)
 			if len(fields) < 2 {
@@ -111,3 +116,11 @@ func parsePCIIDs(scanner *bufio.Scanner, res *Resolver) error {
 
 	return scanner.Err()
 }
+
+func wantVendor(vendors map[string]struct{}, id string) bool {
+	if vendors == nil {
+		return true
+	}
+	_, ok := vendors[id]
+	return ok
+}
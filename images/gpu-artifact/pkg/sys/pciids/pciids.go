@@ -26,6 +26,15 @@ type Resolver struct {
 	classSub  map[string]map[string]string
 }
 
+func newResolver() *Resolver {
+	return &Resolver{
+		vendors:   map[string]string{},
+		devices:   map[string]map[string]string{},
+		classBase: map[string]string{},
+		classSub:  map[string]map[string]string{},
+	}
+}
+
 // VendorName returns the vendor name for a vendor ID.
 func (r *Resolver) VendorName(vendorID string) string {
 	if r == nil {
@@ -0,0 +1,37 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// BuildInfoMetric is exported by every module binary that serves metrics.
+const BuildInfoMetric = "gpu_control_plane_build_info"
+
+// NewBuildInfoCollector returns a constant gauge labelled with the build information.
+func NewBuildInfoCollector() prometheus.Collector {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: BuildInfoMetric,
+		Help: "Build information of the running binary; the value is always 1.",
+		ConstLabels: prometheus.Labels{
+			"version":    Version(),
+			"git_commit": GitCommit(),
+			"go_version": GoVersion(),
+		},
+	})
+	gauge.Set(1)
+	return gauge
+}
@@ -37,19 +37,40 @@ import (
 	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/adminapi"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/bootstrap"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/deviceprotection"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/fleet"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/nodehealth"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/clustergpupool"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/gpupool"
+	poolshared "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/shared"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pooltemplate"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/nfdapi"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/snapshot"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/utilization"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventoryapi"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
 	cpmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
 	bootmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/bootstrap"
+	httpmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/httpcall"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+	healthmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/nodehealth"
+	sizemetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/objsize"
+	ownmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
@@ -57,14 +78,27 @@ import (
 var (
 	// Log is the base logger for the controller manager.
 	Log = ctrl.Log.WithName("gpu-control-plane")
-
-	newManager                    = ctrl.NewManager
-	setupInventoryController      = inventory.SetupController
-	setupBootstrapController      = bootstrap.SetupController
-	setupGPUPoolController        = gpupool.SetupController
-	setupClusterGPUPoolController = clustergpupool.SetupController
-	setupPoolUsageController      = usage.SetupController
-	setupControllers              = setupControllersDefault
+	// LogLevel backs the level of the controller loggers and follows settings.logLevel at runtime.
+	// It is nil when the level was pinned with a command-line flag.
+	LogLevel = logger.NewLevel(moduleconfig.DefaultLogLevel)
+
+	newManager                      = ctrl.NewManager
+	setupInventoryController        = inventory.SetupController
+	setupBootstrapController        = bootstrap.SetupController
+	setupGPUPoolController          = gpupool.SetupController
+	setupClusterGPUPoolController   = clustergpupool.SetupController
+	setupPoolUsageController        = usage.SetupController
+	setupDeviceProtectionController = deviceprotection.SetupController
+	setupNodeHealthController       = nodehealth.SetupController
+	setupControllers                = setupControllersDefault
+	setupSnapshotRunner             = snapshot.SetupRunner
+	setupNodeFeatureAPIChecker      = nfdapi.SetupChecker
+	setupPoolTemplateRunner         = pooltemplate.SetupRunner
+	setupUtilizationRunner          = utilization.SetupRunner
+	setupInventoryAPI               = inventoryapi.SetupServer
+	setupAdminAPI                   = adminapi.SetupServer
+	setupModuleStatus               = modulestatus.SetupRunner
+	setupSimulator                  = fleet.SetupSimulator
 
 	newLabelRequirement   = labels.NewRequirement
 	getConfigOrDie        = ctrl.GetConfigOrDie
@@ -73,20 +107,29 @@ var (
 	addModuleConfigScheme = mcapi.AddToScheme
 )
 
-func setupControllersDefault(ctx context.Context, mgr ctrl.Manager, cfg config.ControllersConfig, store *moduleconfig.ModuleConfigStore) error {
-	if err := setupInventoryController(ctx, mgr, Log, cfg.GPUInventory, store); err != nil {
+// setupControllersDefault registers the controllers; guard is shared by every write path and may be nil, sweeps and
+// usage are fed for the module status and nodeQueue is shared with the admin API.
+func setupControllersDefault(ctx context.Context, mgr ctrl.Manager, cfg config.ControllersConfig, store *moduleconfig.ModuleConfigStore, guard *ownership.Guard, sweeps *modulestatus.SweepTracker, usage *modulestatus.UsageTracker, nodeQueue *inventory.NodeQueue) error {
+	if err := setupInventoryController(ctx, mgr, Log, cfg.GPUInventory, store, guard, sweeps, nodeQueue); err != nil {
+		return err
+	}
+	if err := setupBootstrapController(ctx, mgr, Log, cfg.GPUBootstrap, store, guard); err != nil {
 		return err
 	}
-	if err := setupBootstrapController(ctx, mgr, Log, cfg.GPUBootstrap, store); err != nil {
+	poolDeps := poolshared.NewDependencies(mgr.GetAPIReader(), store, guard)
+	if err := setupGPUPoolController(ctx, mgr, Log, cfg.GPUPool, store, poolDeps); err != nil {
 		return err
 	}
-	if err := setupGPUPoolController(ctx, mgr, Log, cfg.GPUPool, store); err != nil {
+	if err := setupClusterGPUPoolController(ctx, mgr, Log, cfg.GPUPool, store, poolDeps); err != nil {
 		return err
 	}
-	if err := setupClusterGPUPoolController(ctx, mgr, Log, cfg.GPUPool, store); err != nil {
+	if err := setupPoolUsageController(ctx, mgr, Log, cfg.GPUPool, store, usage); err != nil {
 		return err
 	}
-	if err := setupPoolUsageController(ctx, mgr, Log, cfg.GPUPool, store); err != nil {
+	if err := setupDeviceProtectionController(ctx, mgr, Log, cfg.GPUInventory, store, guard); err != nil {
+		return err
+	}
+	if err := setupNodeHealthController(ctx, mgr, Log, cfg.GPUInventory, store, guard); err != nil {
 		return err
 	}
 	return nil
@@ -101,6 +144,11 @@ func Run(ctx context.Context, restCfg *rest.Config, sysCfg config.System) error
 	cpmetrics.Register()
 	bootmetrics.Register()
 	invmetrics.Register()
+	ownmetrics.Register()
+	sizemetrics.Register()
+	healthmetrics.Register()
+	httpmetrics.Register()
+	objsize.SetMaxObjectBytes(sysCfg.ObjectSize.MaxBytes)
 
 	metricsOpts, err := metricsOptionsFromEnv()
 	if err != nil {
@@ -112,6 +160,13 @@ func Run(ctx context.Context, restCfg *rest.Config, sysCfg config.System) error
 			"cert", metricsOpts.CertName,
 			"key", metricsOpts.KeyName)
 	}
+	if err := validateMetricsBindAddress(metricsOpts.BindAddress); err != nil {
+		return err
+	}
+	probeAddr, err := healthProbeBindAddressFromEnv()
+	if err != nil {
+		return err
+	}
 
 	podReq, err := newLabelRequirement(poolcommon.PoolNameKey, selection.Exists, nil)
 	if err != nil {
@@ -121,7 +176,7 @@ func Run(ctx context.Context, restCfg *rest.Config, sysCfg config.System) error
 
 	options := manager.Options{
 		Metrics:                metricsOpts,
-		HealthProbeBindAddress: ":8081",
+		HealthProbeBindAddress: probeAddr,
 		Cache: cache.Options{
 			ByObject: map[client.Object]cache.ByObject{
 				&corev1.Pod{}: {
@@ -133,6 +188,8 @@ func Run(ctx context.Context, restCfg *rest.Config, sysCfg config.System) error
 						cache.AllNamespaces: {LabelSelector: gpuPodSelector},
 					},
 				},
+				// Nodes are watched cluster-wide; only labels, taints and conditions are read from the cache.
+				&corev1.Node{}: {Transform: commonobject.NodeCacheTransform()},
 			},
 		},
 		WebhookServer: crwebhook.NewServer(crwebhook.Options{
@@ -184,22 +241,128 @@ func Run(ctx context.Context, restCfg *rest.Config, sysCfg config.System) error
 		return fmt.Errorf("convert module settings: %w", err)
 	}
 	store := moduleconfig.NewModuleConfigStore(moduleState)
+	if path := sysCfg.ModuleSettingsFile; path != "" {
+		source := moduleconfig.NewFileSource(Log.WithName("module-settings-file"), path, store, mgr.GetAPIReader())
+		if err := source.Sync(ctx); err != nil {
+			Log.Error(err, "initial module settings file load failed, keeping configured settings", "path", path)
+		}
+		if err := mgr.Add(source); err != nil {
+			return fmt.Errorf("register module settings file source: %w", err)
+		}
+	}
+
+	if LogLevel != nil {
+		if _, err := LogLevel.Set(moduleState.Settings.LogLevel); err != nil {
+			Log.Error(err, "keeping default log level", "level", LogLevel.Name())
+		}
+		if err := mgr.Add(moduleconfig.NewLogLevelWatcher(Log.WithName("log-level"), store, LogLevel, mgr.GetAPIReader())); err != nil {
+			return fmt.Errorf("register log level watcher: %w", err)
+		}
+	}
 
 	if err := moduleconfig.SetupWebhookWithManager(mgr, Log); err != nil {
 		return fmt.Errorf("register moduleconfig webhook: %w", err)
 	}
 
-	if err := setupControllers(ctx, mgr, sysCfg.Controllers, store); err != nil {
+	guard, err := setupOwnership(mgr, sysCfg)
+	if err != nil {
+		return fmt.Errorf("register ownership guard: %w", err)
+	}
+
+	// The inventory controller decides on its NodeFeature watch at setup, so the first check runs before it.
+	if err := setupNodeFeatureAPIChecker(mgr, Log); err != nil {
+		return fmt.Errorf("register NodeFeature API checker: %w", err)
+	}
+
+	if err := setupSimulator(mgr, Log, sysCfg.Simulation); err != nil {
+		return fmt.Errorf("register node simulator: %w", err)
+	}
+
+	sweeps := modulestatus.NewSweepTracker()
+	usage := modulestatus.NewUsageTracker()
+	nodeQueue := inventory.NewNodeQueue()
+	if err := setupControllers(ctx, mgr, sysCfg.Controllers, store, guard, sweeps, usage, nodeQueue); err != nil {
 		return fmt.Errorf("register controllers: %w", err)
 	}
 
-	Log.Info("starting manager", "goVersion", runtime.Version())
+	if err := setupSnapshotRunner(mgr, Log, sysCfg.Snapshot, store); err != nil {
+		return fmt.Errorf("register snapshot runner: %w", err)
+	}
+
+	if err := setupPoolTemplateRunner(mgr, Log, store); err != nil {
+		return fmt.Errorf("register pool template runner: %w", err)
+	}
+
+	if err := setupUtilizationRunner(mgr, Log, sysCfg.Utilization, inventory.NewTelemetrySource(mgr.GetClient(), sysCfg.Controllers.GPUInventory.HTTPTimeouts.Telemetry)); err != nil {
+		return fmt.Errorf("register utilization runner: %w", err)
+	}
+
+	if err := setupInventoryAPI(mgr, Log.WithName("inventory-api"), sysCfg.InventoryAPI, store); err != nil {
+		return fmt.Errorf("register inventory API: %w", err)
+	}
+
+	if err := setupAdminAPI(mgr, Log.WithName("admin-api"), sysCfg.AdminAPI, nodeQueue); err != nil {
+		return fmt.Errorf("register admin API: %w", err)
+	}
+
+	leader, _ := os.Hostname()
+	if err := setupModuleStatus(mgr, Log, store, sweeps, usage, leader); err != nil {
+		return fmt.Errorf("register module status runner: %w", err)
+	}
+
+	Log.Info("starting manager", "version", version.Version(), "gitCommit", version.GitCommit(), "goVersion", runtime.Version())
 	if err := mgr.Start(ctx); err != nil {
 		return fmt.Errorf("manager start: %w", err)
 	}
 	return nil
 }
 
+// setupOwnership builds the controller-instance guard for the shared write paths and registers the
+// instance heartbeat Lease other installations check before touching objects claimed by this one.
+// The guard is nil when the instance namespace is unknown.
+func setupOwnership(mgr ctrl.Manager, sysCfg config.System) (*ownership.Guard, error) {
+	if sysCfg.Ownership.Namespace == "" {
+		Log.Info("instance namespace unknown, controller-instance ownership claims disabled")
+		return nil, nil
+	}
+	self := ownership.Identity{Namespace: sysCfg.Ownership.Namespace, Name: sysCfg.LeaderElection.ID}
+	holder, _ := os.Hostname()
+	guardLog := Log.WithName("ownership")
+	if err := mgr.Add(ownership.NewHeartbeat(guardLog, mgr.GetClient(), mgr.GetAPIReader(), self, holder)); err != nil {
+		return nil, fmt.Errorf("add instance heartbeat: %w", err)
+	}
+	guardLog.Info("controller-instance ownership enabled", "instance", self.String(), "forceAdopt", sysCfg.Ownership.ForceAdopt)
+	return ownership.NewGuard(guardLog, self, mgr.GetAPIReader(), sysCfg.Ownership.ForceAdopt), nil
+}
+
+// defaultHealthProbeBindAddress has no host, so the probes are served on every address of both IP families.
+const defaultHealthProbeBindAddress = ":8081"
+
+// healthProbeBindAddressFromEnv returns HEALTH_PROBE_BIND_ADDRESS or the default, rejecting an address the manager
+// could not bind so the pod fails at startup instead of on its first probe.
+func healthProbeBindAddressFromEnv() (string, error) {
+	addr := strings.TrimSpace(os.Getenv("HEALTH_PROBE_BIND_ADDRESS"))
+	if addr == "" {
+		return defaultHealthProbeBindAddress, nil
+	}
+	parsed, err := listenaddr.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("HEALTH_PROBE_BIND_ADDRESS: %w", err)
+	}
+	return parsed, nil
+}
+
+// validateMetricsBindAddress checks METRICS_BIND_ADDRESS; "0" is controller-runtime's way to disable the listener.
+func validateMetricsBindAddress(addr string) error {
+	if addr == "0" {
+		return nil
+	}
+	if _, err := listenaddr.Parse(addr); err != nil {
+		return fmt.Errorf("METRICS_BIND_ADDRESS: %w", err)
+	}
+	return nil
+}
+
 func metricsOptionsFromEnv() (server.Options, error) {
 	opts := server.Options{BindAddress: server.DefaultBindAddress}
 
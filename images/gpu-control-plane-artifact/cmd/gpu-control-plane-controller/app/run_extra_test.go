@@ -22,7 +22,10 @@ import (
 	ctrlmanager "sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 )
 
 func TestRunUsesProvidedRestConfig(t *testing.T) {
@@ -42,7 +45,7 @@ func TestRunUsesProvidedRestConfig(t *testing.T) {
 		capturedCfg = rc
 		return newFakeManager(), nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config {
@@ -57,3 +60,26 @@ func TestRunUsesProvidedRestConfig(t *testing.T) {
 		t.Fatalf("expected provided rest config to be used")
 	}
 }
+
+func TestSetupOwnership(t *testing.T) {
+	sysCfg := config.DefaultSystem()
+	guard, err := setupOwnership(newFakeManager(), sysCfg)
+	if err != nil {
+		t.Fatalf("setupOwnership without namespace: %v", err)
+	}
+	if guard != nil {
+		t.Fatalf("expected ownership guard disabled without instance namespace")
+	}
+
+	sysCfg.Ownership.Namespace = "d8-gpu"
+	guard, err = setupOwnership(newFakeManager(), sysCfg)
+	if err != nil {
+		t.Fatalf("setupOwnership: %v", err)
+	}
+	if guard == nil {
+		t.Fatalf("expected ownership guard built")
+	}
+	if got, want := guard.Self().String(), "d8-gpu/"+config.DefaultLeaderElectionID; got != want {
+		t.Fatalf("unexpected instance identity %q, want %q", got, want)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	crconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -86,7 +87,7 @@ func newFakeManager() *fakeManager {
 // cluster.Cluster methods.
 func (f *fakeManager) GetHTTPClient() *http.Client                     { return nil }
 func (f *fakeManager) GetConfig() *rest.Config                         { return f.config }
-func (f *fakeManager) GetCache() cache.Cache                           { return nil }
+func (f *fakeManager) GetCache() cache.Cache                           { return &informertest.FakeInformers{} }
 func (f *fakeManager) GetScheme() *runtime.Scheme                      { return f.scheme }
 func (f *fakeManager) GetClient() client.Client                        { return nil }
 func (f *fakeManager) GetFieldIndexer() client.FieldIndexer            { return nil }
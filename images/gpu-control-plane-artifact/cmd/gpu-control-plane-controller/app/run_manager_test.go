@@ -22,12 +22,16 @@ import (
 	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	ctrlmanager "sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 )
 
 func TestRunSuccess(t *testing.T) {
@@ -54,7 +58,7 @@ func TestRunSuccess(t *testing.T) {
 
 	controllersCalled := false
 	var receivedCtx context.Context
-	setupControllers = func(ctx context.Context, mgr ctrlmanager.Manager, cfg config.ControllersConfig, store *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(ctx context.Context, mgr ctrlmanager.Manager, cfg config.ControllersConfig, store *moduleconfig.ModuleConfigStore, _ *ownership.Guard, _ *modulestatus.SweepTracker, _ *modulestatus.UsageTracker, _ *inventory.NodeQueue) error {
 		controllersCalled = true
 		receivedCtx = ctx
 		if mgr != fakeMgr {
@@ -104,6 +108,15 @@ func TestRunSuccess(t *testing.T) {
 	if capturedOptions.HealthProbeBindAddress != ":8081" {
 		t.Fatalf("unexpected health probe address: %s", capturedOptions.HealthProbeBindAddress)
 	}
+	nodeCacheConfigured := false
+	for obj, byObject := range capturedOptions.Cache.ByObject {
+		if _, ok := obj.(*corev1.Node); ok {
+			nodeCacheConfigured = byObject.Transform != nil
+		}
+	}
+	if !nodeCacheConfigured {
+		t.Fatalf("expected Node cache transform to be configured")
+	}
 }
 
 func TestRunMetricsTLSFallbackOnError(t *testing.T) {
@@ -123,7 +136,7 @@ func TestRunMetricsTLSFallbackOnError(t *testing.T) {
 		capturedOptions = opts
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -154,7 +167,7 @@ func TestRunFailsOnInvalidModuleSettings(t *testing.T) {
 	newManager = func(cfg *rest.Config, opts ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return newFakeManager(), nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		t.Fatalf("setupControllers must not be called when module settings are invalid")
 		return nil
 	}
@@ -201,7 +214,7 @@ func TestRunWithSecureMetrics(t *testing.T) {
 		capturedOptions = opts
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -258,7 +271,7 @@ func TestRunRegisterControllersError(t *testing.T) {
 	newManager = func(*rest.Config, ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return errors.New("controllers failed")
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -289,7 +302,7 @@ func TestRunManagerStartError(t *testing.T) {
 	newManager = func(*rest.Config, ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -316,7 +329,7 @@ func TestRunRegisterGPUSchemeError(t *testing.T) {
 	newManager = func(*rest.Config, ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -346,7 +359,7 @@ func TestRunRegisterNFDSchemeError(t *testing.T) {
 	newManager = func(*rest.Config, ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -373,7 +386,7 @@ func TestRunHealthzCheckError(t *testing.T) {
 	newManager = func(*rest.Config, ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -399,7 +412,7 @@ func TestRunReadyzCheckError(t *testing.T) {
 	newManager = func(*rest.Config, ctrlmanager.Options) (ctrlmanager.Manager, error) {
 		return fakeMgr, nil
 	}
-	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore) error {
+	setupControllers = func(context.Context, ctrlmanager.Manager, config.ControllersConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *modulestatus.UsageTracker, *inventory.NodeQueue) error {
 		return nil
 	}
 	getConfigOrDie = func() *rest.Config { return &rest.Config{} }
@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -178,3 +179,31 @@ func TestMetricsOptionsFromEnvCustomBindAddress(t *testing.T) {
 		t.Fatalf("expected bind address :9444, got %s", opts.BindAddress)
 	}
 }
+
+func TestHealthProbeBindAddressFromEnv(t *testing.T) {
+	t.Setenv("HEALTH_PROBE_BIND_ADDRESS", "")
+	if addr, err := healthProbeBindAddressFromEnv(); err != nil || addr != ":8081" {
+		t.Fatalf("expected default address, got %q, %v", addr, err)
+	}
+
+	t.Setenv("HEALTH_PROBE_BIND_ADDRESS", " [::]:8081 ")
+	if addr, err := healthProbeBindAddressFromEnv(); err != nil || addr != "[::]:8081" {
+		t.Fatalf("expected IPv6 wildcard, got %q, %v", addr, err)
+	}
+
+	t.Setenv("HEALTH_PROBE_BIND_ADDRESS", "::8081")
+	if _, err := healthProbeBindAddressFromEnv(); err == nil || !strings.Contains(err.Error(), "HEALTH_PROBE_BIND_ADDRESS") {
+		t.Fatalf("expected error naming the variable, got %v", err)
+	}
+}
+
+func TestValidateMetricsBindAddress(t *testing.T) {
+	for _, addr := range []string{"0", ":8080", "127.0.0.1:8080", "[::1]:8080"} {
+		if err := validateMetricsBindAddress(addr); err != nil {
+			t.Fatalf("unexpected error for %q: %v", addr, err)
+		}
+	}
+	if err := validateMetricsBindAddress("127.0.0.1"); err == nil || !strings.Contains(err.Error(), "METRICS_BIND_ADDRESS") {
+		t.Fatalf("expected error naming the variable, got %v", err)
+	}
+}
@@ -24,7 +24,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	poolshared "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/shared"
 )
 
 func TestSetupControllersDefaultBranches(t *testing.T) {
@@ -33,6 +37,8 @@ func TestSetupControllersDefaultBranches(t *testing.T) {
 	origGPUPool := setupGPUPoolController
 	origClusterPool := setupClusterGPUPoolController
 	origPoolUsage := setupPoolUsageController
+	origDeviceProtection := setupDeviceProtectionController
+	origNodeHealth := setupNodeHealthController
 
 	t.Cleanup(func() {
 		setupInventoryController = origInventory
@@ -40,6 +46,8 @@ func TestSetupControllersDefaultBranches(t *testing.T) {
 		setupGPUPoolController = origGPUPool
 		setupClusterGPUPoolController = origClusterPool
 		setupPoolUsageController = origPoolUsage
+		setupDeviceProtectionController = origDeviceProtection
+		setupNodeHealthController = origNodeHealth
 	})
 
 	sysCfg := config.DefaultSystem()
@@ -58,7 +66,9 @@ func TestSetupControllersDefaultBranches(t *testing.T) {
 		{name: "fails-gpupool", failAt: "gpupool", wantCalls: []string{"inventory", "bootstrap", "gpupool"}},
 		{name: "fails-clustergpupool", failAt: "clustergpupool", wantCalls: []string{"inventory", "bootstrap", "gpupool", "clustergpupool"}},
 		{name: "fails-pool-usage", failAt: "pool-usage", wantCalls: []string{"inventory", "bootstrap", "gpupool", "clustergpupool", "pool-usage"}},
-		{name: "success", wantCalls: []string{"inventory", "bootstrap", "gpupool", "clustergpupool", "pool-usage"}},
+		{name: "fails-device-protection", failAt: "device-protection", wantCalls: []string{"inventory", "bootstrap", "gpupool", "clustergpupool", "pool-usage", "device-protection"}},
+		{name: "fails-node-health", failAt: "node-health", wantCalls: []string{"inventory", "bootstrap", "gpupool", "clustergpupool", "pool-usage", "device-protection", "node-health"}},
+		{name: "success", wantCalls: []string{"inventory", "bootstrap", "gpupool", "clustergpupool", "pool-usage", "device-protection", "node-health"}},
 	}
 
 	for _, tc := range cases {
@@ -66,43 +76,57 @@ func TestSetupControllersDefaultBranches(t *testing.T) {
 			calls := make([]string, 0, len(tc.wantCalls))
 			errSentinel := errors.New("boom")
 
-			setupInventoryController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore) error {
+			setupInventoryController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard, *modulestatus.SweepTracker, *inventory.NodeQueue) error {
 				calls = append(calls, "inventory")
 				if tc.failAt == "inventory" {
 					return errSentinel
 				}
 				return nil
 			}
-			setupBootstrapController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore) error {
+			setupBootstrapController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard) error {
 				calls = append(calls, "bootstrap")
 				if tc.failAt == "bootstrap" {
 					return errSentinel
 				}
 				return nil
 			}
-			setupGPUPoolController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore) error {
+			setupGPUPoolController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, poolshared.Dependencies) error {
 				calls = append(calls, "gpupool")
 				if tc.failAt == "gpupool" {
 					return errSentinel
 				}
 				return nil
 			}
-			setupClusterGPUPoolController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore) error {
+			setupClusterGPUPoolController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, poolshared.Dependencies) error {
 				calls = append(calls, "clustergpupool")
 				if tc.failAt == "clustergpupool" {
 					return errSentinel
 				}
 				return nil
 			}
-			setupPoolUsageController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore) error {
+			setupPoolUsageController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, *modulestatus.UsageTracker) error {
 				calls = append(calls, "pool-usage")
 				if tc.failAt == "pool-usage" {
 					return errSentinel
 				}
 				return nil
 			}
+			setupDeviceProtectionController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard) error {
+				calls = append(calls, "device-protection")
+				if tc.failAt == "device-protection" {
+					return errSentinel
+				}
+				return nil
+			}
+			setupNodeHealthController = func(context.Context, ctrl.Manager, logr.Logger, config.ControllerConfig, *moduleconfig.ModuleConfigStore, *ownership.Guard) error {
+				calls = append(calls, "node-health")
+				if tc.failAt == "node-health" {
+					return errSentinel
+				}
+				return nil
+			}
 
-			err := setupControllersDefault(context.Background(), mgr, sysCfg.Controllers, store)
+			err := setupControllersDefault(context.Background(), mgr, sysCfg.Controllers, store, nil, nil, nil, nil)
 			if tc.failAt == "" {
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
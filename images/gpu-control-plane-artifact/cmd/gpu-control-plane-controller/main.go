@@ -48,10 +48,23 @@ func runMain(args []string, getenv func(string) string) int {
 	flagSet.SetOutput(io.Discard)
 	opts := zap.Options{Development: true}
 	opts.BindFlags(flagSet)
+	moduleSettingsFile := flagSet.String("module-settings-file", getenv("MODULE_SETTINGS_FILE"), "YAML file with module settings for installs without the ModuleConfig CRD.")
+	forceAdopt := flagSet.Bool("force-adopt", false, "Take over objects claimed by another live gpu-control-plane installation.")
+	simulateNodes := flagSet.String("simulate-nodes", "", "YAML fixture of fake GPU nodes to simulate, for development clusters without GPUs.")
+	simulateForce := flagSet.Bool("simulate-force", false, "Simulate nodes even when the cluster has real GPU nodes.")
 	if err := flagSet.Parse(args); err != nil {
 		app.Log.Error(err, "failed to parse flags")
 		return 1
 	}
+	// An explicit --zap-log-level pins the level; otherwise it follows the module settings.
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == "zap-log-level" {
+			app.LogLevel = nil
+		}
+	})
+	if app.LogLevel != nil {
+		opts.Level = app.LogLevel
+	}
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	sysCfg := config.DefaultSystem()
@@ -78,6 +91,24 @@ func runMain(args []string, getenv func(string) string) int {
 	}
 
 	applyLeaderElectionFromEnv(&sysCfg, getenv)
+	if path := strings.TrimSpace(*moduleSettingsFile); path != "" {
+		sysCfg.ModuleSettingsFile = path
+	}
+	if *forceAdopt {
+		sysCfg.Ownership.ForceAdopt = true
+	}
+	if path := strings.TrimSpace(*simulateNodes); path != "" {
+		sysCfg.Simulation.NodesFile = path
+	}
+	if *simulateForce {
+		sysCfg.Simulation.Force = true
+	}
+	if sysCfg.Ownership.Namespace == "" {
+		sysCfg.Ownership.Namespace = sysCfg.LeaderElection.Namespace
+	}
+	if sysCfg.Ownership.Namespace == "" {
+		sysCfg.Ownership.Namespace = strings.TrimSpace(getenv("POD_NAMESPACE"))
+	}
 
 	restCfg := getRESTConfig()
 	ctx := setupSignals()
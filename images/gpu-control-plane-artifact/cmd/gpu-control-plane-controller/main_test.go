@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/client-go/rest"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/cmd/gpu-control-plane-controller/app"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
 )
 
@@ -198,6 +199,120 @@ func TestRunMainFlagParseError(t *testing.T) {
 	}
 }
 
+func TestRunMainModuleSettingsFile(t *testing.T) {
+	origRun := runManager
+	origGet := getRESTConfig
+	origSetup := setupSignals
+	t.Cleanup(func() {
+		runManager = origRun
+		getRESTConfig = origGet
+		setupSignals = origSetup
+	})
+	getRESTConfig = func() *rest.Config { return &rest.Config{} }
+	setupSignals = func() context.Context { return context.Background() }
+
+	var got string
+	runManager = func(_ context.Context, _ *rest.Config, sysCfg config.System) error {
+		got = sysCfg.ModuleSettingsFile
+		return nil
+	}
+
+	env := func(key string) string {
+		if key == "MODULE_SETTINGS_FILE" {
+			return "/etc/gpu/env.yaml"
+		}
+		return ""
+	}
+	if code := runMain(nil, env); code != 0 || got != "/etc/gpu/env.yaml" {
+		t.Fatalf("expected settings file from env, got %q (code %d)", got, code)
+	}
+	if code := runMain([]string{"--module-settings-file=/etc/gpu/flag.yaml"}, env); code != 0 || got != "/etc/gpu/flag.yaml" {
+		t.Fatalf("expected flag to override env, got %q (code %d)", got, code)
+	}
+}
+
+func TestRunMainLogLevelFlagPinsLevel(t *testing.T) {
+	origRun := runManager
+	origGet := getRESTConfig
+	origSetup := setupSignals
+	origLevel := app.LogLevel
+	t.Cleanup(func() {
+		runManager = origRun
+		getRESTConfig = origGet
+		setupSignals = origSetup
+		app.LogLevel = origLevel
+	})
+	getRESTConfig = func() *rest.Config { return &rest.Config{} }
+	setupSignals = func() context.Context { return context.Background() }
+	runManager = func(context.Context, *rest.Config, config.System) error { return nil }
+
+	if code := runMain(nil, func(string) string { return "" }); code != 0 || app.LogLevel == nil {
+		t.Fatalf("expected the module log level to drive the loggers (code %d)", code)
+	}
+	if code := runMain([]string{"--zap-log-level=debug"}, func(string) string { return "" }); code != 0 || app.LogLevel != nil {
+		t.Fatalf("expected --zap-log-level to pin the level (code %d)", code)
+	}
+}
+
+func TestRunMainOwnershipSettings(t *testing.T) {
+	origRun := runManager
+	origGet := getRESTConfig
+	origSetup := setupSignals
+	t.Cleanup(func() {
+		runManager = origRun
+		getRESTConfig = origGet
+		setupSignals = origSetup
+	})
+	getRESTConfig = func() *rest.Config { return &rest.Config{} }
+	setupSignals = func() context.Context { return context.Background() }
+
+	var got config.OwnershipConfig
+	runManager = func(_ context.Context, _ *rest.Config, sysCfg config.System) error {
+		got = sysCfg.Ownership
+		return nil
+	}
+
+	env := func(key string) string {
+		if key == "POD_NAMESPACE" {
+			return "d8-gpu"
+		}
+		return ""
+	}
+	if code := runMain(nil, env); code != 0 || got.ForceAdopt || got.Namespace != "d8-gpu" {
+		t.Fatalf("unexpected ownership defaults %+v (code %d)", got, code)
+	}
+	if code := runMain([]string{"--force-adopt"}, env); code != 0 || !got.ForceAdopt {
+		t.Fatalf("expected --force-adopt to enable adoption, got %+v (code %d)", got, code)
+	}
+}
+
+func TestRunMainSimulationFlags(t *testing.T) {
+	origRun := runManager
+	origGet := getRESTConfig
+	origSetup := setupSignals
+	t.Cleanup(func() {
+		runManager = origRun
+		getRESTConfig = origGet
+		setupSignals = origSetup
+	})
+	getRESTConfig = func() *rest.Config { return &rest.Config{} }
+	setupSignals = func() context.Context { return context.Background() }
+
+	var got config.SimulationConfig
+	runManager = func(_ context.Context, _ *rest.Config, sysCfg config.System) error {
+		got = sysCfg.Simulation
+		return nil
+	}
+
+	env := func(string) string { return "" }
+	if code := runMain(nil, env); code != 0 || got.NodesFile != "" || got.Force {
+		t.Fatalf("expected simulation to be off by default, got %+v (code %d)", got, code)
+	}
+	if code := runMain([]string{"--simulate-nodes=/etc/gpu/nodes.yaml", "--simulate-force"}, env); code != 0 || got.NodesFile != "/etc/gpu/nodes.yaml" || !got.Force {
+		t.Fatalf("expected simulation flags to be applied, got %+v (code %d)", got, code)
+	}
+}
+
 func TestRunMainLoadsConfigFile(t *testing.T) {
 	origLoad := loadConfigFile
 	origRun := runManager
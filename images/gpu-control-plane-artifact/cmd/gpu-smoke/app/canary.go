@@ -0,0 +1,126 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+)
+
+const (
+	DefaultCanaryImage = "nvcr.io/nvidia/cuda:12.4.1-base-ubuntu22.04"
+	canaryLabelKey     = "app.kubernetes.io/name"
+	canaryLabelValue   = "gpu-smoke"
+)
+
+// canary runs nvidia-smi in a pod that requests one unit of the pool resource on the node under test.
+func (c *checker) canary(ctx context.Context) (string, error) {
+	if c.canaryPod == nil {
+		pod := c.canaryPodSpec()
+		if err := c.client.Create(ctx, pod); err != nil {
+			return "", Permanent(fmt.Errorf("create canary pod: %w", err))
+		}
+		c.canaryPod = pod
+	}
+
+	key := types.NamespacedName{Namespace: c.canaryPod.Namespace, Name: c.canaryPod.Name}
+	pod, err := commonobject.FetchObject(ctx, key, c.client, &corev1.Pod{})
+	if err != nil {
+		return "", fmt.Errorf("get canary pod %s: %w", key, err)
+	}
+	if pod == nil {
+		return "", Permanent(fmt.Errorf("canary pod %s disappeared", key))
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return fmt.Sprintf("nvidia-smi succeeded in pod %s", key), nil
+	case corev1.PodFailed:
+		return "", Permanent(fmt.Errorf("canary pod %s failed: %s", key, podFailure(pod)))
+	default:
+		phase := pod.Status.Phase
+		if phase == "" {
+			phase = corev1.PodPending
+		}
+		return "", fmt.Errorf("canary pod %s is %s", key, phase)
+	}
+}
+
+func (c *checker) canaryPodSpec() *corev1.Pod {
+	units := corev1.ResourceList{c.resource: resource.MustParse("1")}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "gpu-smoke-",
+			Namespace:    c.opts.CanaryNamespace,
+			Labels:       map[string]string{canaryLabelKey: canaryLabelValue},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchFields: []corev1.NodeSelectorRequirement{{
+								Key:      "metadata.name",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{c.opts.Node},
+							}},
+						}},
+					},
+				},
+			},
+			// GPU nodes are commonly tainted for dedicated workloads; the canary must land regardless.
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:      "nvidia-smi",
+				Image:     c.opts.CanaryImage,
+				Command:   []string{"nvidia-smi"},
+				Resources: corev1.ResourceRequirements{Requests: units, Limits: units},
+			}},
+		},
+	}
+}
+
+func podFailure(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if term := status.State.Terminated; term != nil {
+			return fmt.Sprintf("container %s exited with code %d (%s) %s", status.Name, term.ExitCode, term.Reason, term.Message)
+		}
+	}
+	if pod.Status.Message != "" {
+		return pod.Status.Message
+	}
+	return pod.Status.Reason
+}
+
+func (c *checker) cleanup(ctx context.Context) error {
+	if c.canaryPod == nil {
+		return nil
+	}
+	err := commonobject.DeleteObject(ctx, c.client, c.canaryPod, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil {
+		return fmt.Errorf("delete canary pod %s/%s: %w", c.canaryPod.Namespace, c.canaryPod.Name, err)
+	}
+	c.canaryPod = nil
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/names"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+const (
+	StepNodeLabels      = "node-pci-labels"
+	StepNodeFeature     = "node-feature"
+	StepGPUDevice       = "gpu-device"
+	StepInventory       = "inventory-complete"
+	StepPoolCapacity    = "pool-capacity"
+	StepNodeAllocatable = "node-allocatable"
+	StepCanary          = "canary-pod"
+)
+
+const (
+	nodeFeatureNodeLabel = "nfd.node.kubernetes.io/node-name"
+	conditionInventory   = "InventoryComplete"
+)
+
+// Options select the node and pool under test.
+type Options struct {
+	Node string
+	// Pool is the pool expected to expose capacity on Node. Pool steps are omitted when it is empty.
+	Pool string
+	// PoolNamespace selects a namespaced GPUPool; an empty value means a ClusterGPUPool.
+	PoolNamespace string
+
+	StepTimeout time.Duration
+
+	Canary          bool
+	CanaryImage     string
+	CanaryNamespace string
+	CanaryTimeout   time.Duration
+}
+
+type checker struct {
+	client client.Client
+	opts   Options
+
+	// resource is resolved by the pool step and consumed by the allocatable and canary steps.
+	resource corev1.ResourceName
+	// canaryPod is the pod created by the canary step, removed by the cleanup returned from Steps.
+	canaryPod *corev1.Pod
+}
+
+// Steps builds the smoke chain for opts. The returned cleanup removes objects created by the steps and must be
+// called once the run is over.
+func Steps(cl client.Client, opts Options) ([]Step, func(context.Context) error) {
+	c := &checker{client: cl, opts: opts}
+	steps := []Step{
+		{Name: StepNodeLabels, Timeout: opts.StepTimeout, Check: c.nodeLabels},
+		{Name: StepNodeFeature, Timeout: opts.StepTimeout, Check: c.nodeFeature},
+		{Name: StepGPUDevice, Timeout: opts.StepTimeout, Check: c.gpuDevice},
+		{Name: StepInventory, Timeout: opts.StepTimeout, Check: c.inventoryComplete},
+	}
+	if opts.Pool == "" {
+		return steps, c.cleanup
+	}
+	steps = append(steps,
+		Step{Name: StepPoolCapacity, Timeout: opts.StepTimeout, Check: c.poolCapacity},
+		Step{Name: StepNodeAllocatable, Timeout: opts.StepTimeout, Check: c.nodeAllocatable},
+	)
+	if opts.Canary {
+		steps = append(steps, Step{Name: StepCanary, Timeout: opts.CanaryTimeout, Check: c.canary})
+	}
+	return steps, c.cleanup
+}
+
+func (c *checker) node(ctx context.Context) (*corev1.Node, error) {
+	node, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: c.opts.Node}, c.client, &corev1.Node{})
+	if err != nil {
+		return nil, fmt.Errorf("get node %s: %w", c.opts.Node, err)
+	}
+	if node == nil {
+		return nil, Permanent(fmt.Errorf("node %s not found", c.opts.Node))
+	}
+	return node, nil
+}
+
+func (c *checker) nodeLabels(ctx context.Context) (string, error) {
+	node, err := c.node(ctx)
+	if err != nil {
+		return "", err
+	}
+	count := 0
+	for key := range node.Labels {
+		if strings.HasPrefix(key, snapshot.DeviceLabelPrefix) && strings.HasSuffix(key, ".vendor") {
+			count++
+		}
+	}
+	if count == 0 {
+		return "", fmt.Errorf("node has no %s<slot>.vendor labels; check the NodeFeatureRule and node-feature-discovery", snapshot.DeviceLabelPrefix)
+	}
+	return fmt.Sprintf("%d PCI device(s) labelled", count), nil
+}
+
+func (c *checker) nodeFeature(ctx context.Context) (string, error) {
+	list := &nfdv1alpha1.NodeFeatureList{}
+	if err := c.client.List(ctx, list, client.MatchingLabels{nodeFeatureNodeLabel: c.opts.Node}); err != nil {
+		return "", fmt.Errorf("list NodeFeatures: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return "", errors.New("no NodeFeature published for the node")
+	}
+	feature := list.Items[0]
+	return fmt.Sprintf("%s/%s", feature.Namespace, feature.Name), nil
+}
+
+func (c *checker) nodeDevices(ctx context.Context) ([]v1alpha1.GPUDevice, error) {
+	list := &v1alpha1.GPUDeviceList{}
+	if err := c.client.List(ctx, list, client.MatchingLabels{poolcommon.DeviceNodeLabelKey: c.opts.Node}); err != nil {
+		return nil, fmt.Errorf("list GPUDevices: %w", err)
+	}
+	return list.Items, nil
+}
+
+func (c *checker) gpuDevice(ctx context.Context) (string, error) {
+	devices, err := c.nodeDevices(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(devices) == 0 {
+		return "", errors.New("no GPUDevice for the node")
+	}
+	var missing []string
+	for _, device := range devices {
+		hw := device.Status.Hardware
+		if hw.PCI.Vendor == "" || hw.PCI.Device == "" || hw.Product == "" {
+			missing = append(missing, device.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("GPUDevice hardware not populated: %s", strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("%d device(s), %s", len(devices), devices[0].Status.Hardware.Product), nil
+}
+
+func (c *checker) inventoryComplete(ctx context.Context) (string, error) {
+	state, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: c.opts.Node}, c.client, &v1alpha1.GPUNodeState{})
+	if err != nil {
+		return "", fmt.Errorf("get GPUNodeState: %w", err)
+	}
+	if state == nil {
+		return "", errors.New("GPUNodeState not found")
+	}
+	cond := apimeta.FindStatusCondition(state.Status.Conditions, conditionInventory)
+	if cond == nil {
+		return "", fmt.Errorf("%s condition not reported", conditionInventory)
+	}
+	if cond.Status != metav1.ConditionTrue {
+		return "", fmt.Errorf("%s=%s: %s: %s", conditionInventory, cond.Status, cond.Reason, cond.Message)
+	}
+	return fmt.Sprintf("%s=True", conditionInventory), nil
+}
+
+func (c *checker) poolCapacity(ctx context.Context) (string, error) {
+	pool, err := c.pool(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.resource = corev1.ResourceName(names.PoolResourceName(pool))
+	capacity := pool.Status.Capacity
+	if capacity.Total <= 0 {
+		return "", fmt.Errorf("pool reports no capacity (total=%d)", capacity.Total)
+	}
+	return fmt.Sprintf("total=%d available=%d", capacity.Total, capacity.Available), nil
+}
+
+// pool returns the target pool as a GPUPool; a ClusterGPUPool keeps its kind so the resource prefix resolves.
+func (c *checker) pool(ctx context.Context) (*v1alpha1.GPUPool, error) {
+	if c.opts.PoolNamespace != "" {
+		pool, err := commonobject.FetchObject(ctx, types.NamespacedName{Namespace: c.opts.PoolNamespace, Name: c.opts.Pool}, c.client, &v1alpha1.GPUPool{})
+		if err != nil {
+			return nil, fmt.Errorf("get GPUPool %s/%s: %w", c.opts.PoolNamespace, c.opts.Pool, err)
+		}
+		if pool == nil {
+			return nil, Permanent(fmt.Errorf("GPUPool %s/%s not found", c.opts.PoolNamespace, c.opts.Pool))
+		}
+		pool.Kind = "GPUPool"
+		return pool, nil
+	}
+
+	cluster, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: c.opts.Pool}, c.client, &v1alpha1.ClusterGPUPool{})
+	if err != nil {
+		return nil, fmt.Errorf("get ClusterGPUPool %s: %w", c.opts.Pool, err)
+	}
+	if cluster == nil {
+		return nil, Permanent(fmt.Errorf("ClusterGPUPool %s not found", c.opts.Pool))
+	}
+	return &v1alpha1.GPUPool{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterGPUPool"},
+		ObjectMeta: cluster.ObjectMeta,
+		Spec:       cluster.Spec,
+		Status:     cluster.Status,
+	}, nil
+}
+
+func (c *checker) nodeAllocatable(ctx context.Context) (string, error) {
+	node, err := c.node(ctx)
+	if err != nil {
+		return "", err
+	}
+	quantity, ok := node.Status.Allocatable[c.resource]
+	if !ok || quantity.IsZero() {
+		return "", fmt.Errorf("node allocatable has no %s", c.resource)
+	}
+	return fmt.Sprintf("%s=%s", c.resource, quantity.String()), nil
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+const testNode = "gpu-1"
+
+func healthyObjects() []client.Object {
+	return []client.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: testNode,
+				Labels: map[string]string{
+					"gpu.deckhouse.io/device.00.vendor": "10de",
+					"gpu.deckhouse.io/device.00.device": "20b0",
+				},
+			},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				"cluster.gpu.deckhouse.io/a100": resource.MustParse("1"),
+			}},
+		},
+		&nfdv1alpha1.NodeFeature{ObjectMeta: metav1.ObjectMeta{
+			Name:      testNode,
+			Namespace: "d8-nfd",
+			Labels:    map[string]string{nodeFeatureNodeLabel: testNode},
+		}},
+		&v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: testNode + "-0", Labels: map[string]string{"gpu.deckhouse.io/node": testNode}},
+			Status: v1alpha1.GPUDeviceStatus{Hardware: v1alpha1.GPUDeviceHardware{
+				Product: "NVIDIA A100",
+				PCI:     v1alpha1.PCIAddress{Vendor: "10de", Device: "20b0"},
+			}},
+		},
+		&v1alpha1.GPUNodeState{
+			ObjectMeta: metav1.ObjectMeta{Name: testNode},
+			Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{{
+				Type: conditionInventory, Status: metav1.ConditionTrue, Reason: "InventorySynced",
+			}}},
+		},
+		&v1alpha1.ClusterGPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "a100"},
+			Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1, Available: 1}},
+		},
+	}
+}
+
+func newFakeClient(t *testing.T, funcs interceptor.Funcs, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("scheme: %v", err)
+	}
+	return clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+func testOptions() Options {
+	return Options{Node: testNode, Pool: "a100", StepTimeout: 20 * time.Millisecond, CanaryTimeout: 20 * time.Millisecond}
+}
+
+func TestRunHealthyChainPasses(t *testing.T) {
+	cl := newFakeClient(t, interceptor.Funcs{}, healthyObjects()...)
+
+	report, err := Run(context.Background(), cl, testOptions(), time.Millisecond)
+	if err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if !report.Passed {
+		t.Fatalf("expected pass, got %+v", report)
+	}
+	names := make([]string, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+	}
+	want := []string{StepNodeLabels, StepNodeFeature, StepGPUDevice, StepInventory, StepPoolCapacity, StepNodeAllocatable}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected steps %v", names)
+	}
+	if got := report.Steps[5].Message; got != "cluster.gpu.deckhouse.io/a100=1" {
+		t.Fatalf("unexpected allocatable detail %q", got)
+	}
+}
+
+func TestRunReportsFirstBrokenLink(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func([]client.Object) []client.Object
+		step   string
+		reason string
+	}{
+		{
+			name: "missing PCI labels",
+			mutate: func(objs []client.Object) []client.Object {
+				objs[0].(*corev1.Node).Labels = nil
+				return objs
+			},
+			step:   StepNodeLabels,
+			reason: "vendor labels",
+		},
+		{
+			name:   "missing NodeFeature",
+			mutate: func(objs []client.Object) []client.Object { return append(objs[:1:1], objs[2:]...) },
+			step:   StepNodeFeature,
+			reason: "no NodeFeature",
+		},
+		{
+			name: "hardware not populated",
+			mutate: func(objs []client.Object) []client.Object {
+				objs[2].(*v1alpha1.GPUDevice).Status.Hardware = v1alpha1.GPUDeviceHardware{}
+				return objs
+			},
+			step:   StepGPUDevice,
+			reason: "hardware not populated",
+		},
+		{
+			name: "inventory incomplete",
+			mutate: func(objs []client.Object) []client.Object {
+				objs[3].(*v1alpha1.GPUNodeState).Status.Conditions[0].Status = metav1.ConditionFalse
+				return objs
+			},
+			step:   StepInventory,
+			reason: "InventoryComplete=False",
+		},
+		{
+			name: "pool without capacity",
+			mutate: func(objs []client.Object) []client.Object {
+				objs[4].(*v1alpha1.ClusterGPUPool).Status.Capacity.Total = 0
+				return objs
+			},
+			step:   StepPoolCapacity,
+			reason: "no capacity",
+		},
+		{
+			name: "resource not allocatable",
+			mutate: func(objs []client.Object) []client.Object {
+				objs[0].(*corev1.Node).Status.Allocatable = nil
+				return objs
+			},
+			step:   StepNodeAllocatable,
+			reason: "cluster.gpu.deckhouse.io/a100",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := newFakeClient(t, interceptor.Funcs{}, tc.mutate(healthyObjects())...)
+			report, _ := Run(context.Background(), cl, testOptions(), time.Millisecond)
+			if report.Passed || report.FirstFailure != tc.step {
+				t.Fatalf("expected failure at %s, got %+v", tc.step, report)
+			}
+			for _, step := range report.Steps {
+				if step.Name == tc.step && !strings.Contains(step.Message, tc.reason) {
+					t.Fatalf("expected message to mention %q, got %q", tc.reason, step.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestRunNamespacedPoolUsesNamespacedResource(t *testing.T) {
+	objs := healthyObjects()
+	objs[0].(*corev1.Node).Status.Allocatable = corev1.ResourceList{"gpu.deckhouse.io/team": resource.MustParse("2")}
+	objs[4] = &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "ml"},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 2}},
+	}
+	opts := testOptions()
+	opts.Pool, opts.PoolNamespace = "team", "ml"
+
+	report, _ := Run(context.Background(), newFakeClient(t, interceptor.Funcs{}, objs...), opts, time.Millisecond)
+	if !report.Passed {
+		t.Fatalf("expected pass, got %+v", report)
+	}
+	if report.Pool != "ml/team" {
+		t.Fatalf("expected namespaced pool in report, got %q", report.Pool)
+	}
+}
+
+func TestRunCanaryPod(t *testing.T) {
+	cases := []struct {
+		name   string
+		phase  corev1.PodPhase
+		passed bool
+		reason string
+	}{
+		{name: "succeeded", phase: corev1.PodSucceeded, passed: true},
+		{name: "failed", phase: corev1.PodFailed, reason: "exited with code 9"},
+		{name: "pending", phase: corev1.PodPending, reason: "is Pending"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var created *corev1.Pod
+			funcs := interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if err := c.Create(ctx, obj, opts...); err != nil {
+						return err
+					}
+					if pod, ok := obj.(*corev1.Pod); ok {
+						created = pod.DeepCopy()
+					}
+					return nil
+				},
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := c.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if pod, ok := obj.(*corev1.Pod); ok {
+						pod.Status.Phase = tc.phase
+						pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+							Name:  "nvidia-smi",
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 9}},
+						}}
+					}
+					return nil
+				},
+			}
+			cl := newFakeClient(t, funcs, healthyObjects()...)
+			opts := testOptions()
+			opts.Canary = true
+			opts.CanaryImage = DefaultCanaryImage
+			opts.CanaryNamespace = "default"
+
+			report, err := Run(context.Background(), cl, opts, time.Millisecond)
+			if err != nil {
+				t.Fatalf("cleanup: %v", err)
+			}
+			if report.Passed != tc.passed {
+				t.Fatalf("expected passed=%t, got %+v", tc.passed, report)
+			}
+			last := report.Steps[len(report.Steps)-1]
+			if last.Name != StepCanary || !strings.Contains(last.Message, tc.reason) {
+				t.Fatalf("unexpected canary result %+v", last)
+			}
+
+			if created == nil {
+				t.Fatal("expected canary pod to be created")
+			}
+			limit := created.Spec.Containers[0].Resources.Limits["cluster.gpu.deckhouse.io/a100"]
+			if limit.Value() != 1 {
+				t.Fatalf("expected canary to request one pool unit, got %v", created.Spec.Containers[0].Resources.Limits)
+			}
+			pods := &corev1.PodList{}
+			if err := cl.List(context.Background(), pods); err != nil {
+				t.Fatalf("list pods: %v", err)
+			}
+			if len(pods.Items) != 0 {
+				t.Fatalf("expected canary pod to be cleaned up, got %d", len(pods.Items))
+			}
+		})
+	}
+}
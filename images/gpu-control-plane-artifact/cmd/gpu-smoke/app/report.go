@@ -0,0 +1,81 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// WriteReport renders report in the requested format.
+func WriteReport(w io.Writer, report Report, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case FormatText, "":
+		return writeText(w, report)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+func writeText(w io.Writer, report Report) error {
+	target := "node " + report.Node
+	if report.Pool != "" {
+		target += ", pool " + report.Pool
+	}
+	verdict := "PASS"
+	if !report.Passed {
+		verdict = "FAIL"
+	}
+	if _, err := fmt.Fprintf(w, "gpu-smoke %s: %s\n", target, verdict); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, step := range report.Steps {
+		// The first failing step is the one to look at; mark it so it stands out in long outputs.
+		marker := " "
+		if step.Name == report.FirstFailure {
+			marker = ">"
+		}
+		duration := ""
+		if step.Status != StatusSkipped {
+			duration = fmt.Sprintf("%.1fs", step.DurationSeconds)
+		}
+		if _, err := fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", marker, strings.ToUpper(string(step.Status)), step.Name, duration, step.Message); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if report.FirstFailure != "" {
+		_, err := fmt.Fprintf(w, "first failing step: %s\n", report.FirstFailure)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func failingReport() Report {
+	return Report{
+		Node:         "gpu-1",
+		Pool:         "a100",
+		FirstFailure: StepGPUDevice,
+		Steps: []StepResult{
+			{Name: StepNodeLabels, Status: StatusPass, Message: "1 PCI device(s) labelled", DurationSeconds: 0.1},
+			{Name: StepGPUDevice, Status: StatusFail, Message: "no GPUDevice for the node", DurationSeconds: 120},
+			{Name: StepInventory, Status: StatusSkipped},
+		},
+	}
+}
+
+func TestWriteReportTextHighlightsFirstFailure(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, failingReport(), FormatText); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "gpu-smoke node gpu-1, pool a100: FAIL" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "> FAIL") || !strings.Contains(lines[2], "120.0s") {
+		t.Fatalf("expected highlighted failing step, got %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[1], "  PASS") || !strings.HasPrefix(lines[3], "  SKIPPED") {
+		t.Fatalf("unexpected step lines %q", lines[1:4])
+	}
+	if lines[len(lines)-1] != "first failing step: gpu-device" {
+		t.Fatalf("unexpected footer %q", lines[len(lines)-1])
+	}
+}
+
+func TestWriteReportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, failingReport(), FormatJSON); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.Passed || decoded.FirstFailure != StepGPUDevice || len(decoded.Steps) != 3 {
+		t.Fatalf("unexpected decoded report %+v", decoded)
+	}
+}
+
+func TestWriteReportRejectsUnknownFormat(t *testing.T) {
+	if err := WriteReport(&bytes.Buffer{}, Report{}, "yaml"); err == nil {
+		t.Fatal("expected unknown format error")
+	}
+}
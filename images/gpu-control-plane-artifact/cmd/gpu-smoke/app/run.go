@@ -0,0 +1,64 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// cleanupTimeout bounds object removal after the run, which proceeds even when the run context is done.
+const cleanupTimeout = 30 * time.Second
+
+// NewScheme returns the scheme with every kind the smoke steps read or create.
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("register core scheme: %w", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("register gpu scheme: %w", err)
+	}
+	if err := nfdv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("register nfd scheme: %w", err)
+	}
+	// Register list types explicitly because upstream AddToScheme omits them.
+	scheme.AddKnownTypes(nfdv1alpha1.SchemeGroupVersion, &nfdv1alpha1.NodeFeatureList{})
+	return scheme, nil
+}
+
+// Run executes the smoke chain for opts and removes the objects it created. The cleanup error is returned
+// alongside the report and does not change its verdict.
+func Run(ctx context.Context, cl client.Client, opts Options, interval time.Duration) (Report, error) {
+	steps, cleanup := Steps(cl, opts)
+	report := Runner{Interval: interval}.Run(ctx, steps)
+	report.Node = opts.Node
+	report.Pool = opts.Pool
+	if opts.Pool != "" && opts.PoolNamespace != "" {
+		report.Pool = opts.PoolNamespace + "/" + opts.Pool
+	}
+
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+	return report, cleanup(cleanupCtx)
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Status is the outcome of a single smoke step.
+type Status string
+
+const (
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"
+	StatusSkipped Status = "skipped"
+)
+
+// Step is one link of the chain. Check is retried until it succeeds, returns a permanent error or the step
+// timeout expires; the returned string is a short human-readable detail for the report.
+type Step struct {
+	Name    string
+	Timeout time.Duration
+	Check   func(ctx context.Context) (string, error)
+}
+
+// StepResult records the outcome of a step.
+type StepResult struct {
+	Name            string  `json:"name"`
+	Status          Status  `json:"status"`
+	Message         string  `json:"message,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// Report is the structured result of a smoke run.
+type Report struct {
+	Node         string       `json:"node"`
+	Pool         string       `json:"pool,omitempty"`
+	Passed       bool         `json:"passed"`
+	FirstFailure string       `json:"firstFailure,omitempty"`
+	Steps        []StepResult `json:"steps"`
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks a check error that retrying cannot fix, so the step fails immediately.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// Runner executes steps in order. A failed step stops the chain: later steps depend on it and are reported
+// as skipped.
+type Runner struct {
+	Interval time.Duration
+	Now      func() time.Time
+}
+
+func (r Runner) Run(ctx context.Context, steps []Step) Report {
+	now := r.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	report := Report{Passed: true, Steps: make([]StepResult, 0, len(steps))}
+	for _, step := range steps {
+		if !report.Passed {
+			report.Steps = append(report.Steps, StepResult{Name: step.Name, Status: StatusSkipped})
+			continue
+		}
+
+		started := now()
+		message, err := r.runStep(ctx, step)
+		result := StepResult{
+			Name:            step.Name,
+			Status:          StatusPass,
+			Message:         message,
+			DurationSeconds: now().Sub(started).Seconds(),
+		}
+		if err != nil {
+			result.Status = StatusFail
+			result.Message = err.Error()
+			report.Passed = false
+			report.FirstFailure = step.Name
+		}
+		report.Steps = append(report.Steps, result)
+	}
+	return report
+}
+
+func (r Runner) runStep(ctx context.Context, step Step) (string, error) {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	for {
+		message, err := step.Check(ctx)
+		if err == nil {
+			return message, nil
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return "", permanent.err
+		}
+
+		timer := time.NewTimer(r.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunnerRetriesUntilStepPasses(t *testing.T) {
+	calls := 0
+	steps := []Step{{
+		Name:    "eventually",
+		Timeout: time.Second,
+		Check: func(context.Context) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", errors.New("not yet")
+			}
+			return "ok", nil
+		},
+	}}
+
+	report := Runner{Interval: time.Millisecond}.Run(context.Background(), steps)
+	if !report.Passed || report.FirstFailure != "" {
+		t.Fatalf("expected passing report, got %+v", report)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+	if got := report.Steps[0]; got.Status != StatusPass || got.Message != "ok" {
+		t.Fatalf("unexpected step result %+v", got)
+	}
+}
+
+func TestRunnerStopsChainAtFirstFailure(t *testing.T) {
+	laterCalled := false
+	steps := []Step{
+		{Name: "first", Check: func(context.Context) (string, error) { return "fine", nil }},
+		{Name: "second", Timeout: 5 * time.Millisecond, Check: func(context.Context) (string, error) {
+			return "", errors.New("still missing")
+		}},
+		{Name: "third", Check: func(context.Context) (string, error) {
+			laterCalled = true
+			return "", nil
+		}},
+	}
+
+	report := Runner{Interval: time.Millisecond}.Run(context.Background(), steps)
+	if report.Passed || report.FirstFailure != "second" {
+		t.Fatalf("expected failure at second step, got %+v", report)
+	}
+	if laterCalled {
+		t.Fatal("steps after the failure must not run")
+	}
+	want := []Status{StatusPass, StatusFail, StatusSkipped}
+	for i, status := range want {
+		if report.Steps[i].Status != status {
+			t.Fatalf("step %d: expected %s, got %s", i, status, report.Steps[i].Status)
+		}
+	}
+	if report.Steps[1].Message != "still missing" {
+		t.Fatalf("expected last check error as message, got %q", report.Steps[1].Message)
+	}
+}
+
+func TestRunnerPermanentErrorFailsImmediately(t *testing.T) {
+	calls := 0
+	steps := []Step{{
+		Name:    "permanent",
+		Timeout: time.Minute,
+		Check: func(context.Context) (string, error) {
+			calls++
+			return "", Permanent(errors.New("gone"))
+		},
+	}}
+
+	report := Runner{Interval: time.Millisecond}.Run(context.Background(), steps)
+	if report.Passed || calls != 1 {
+		t.Fatalf("expected a single failing attempt, got calls=%d report=%+v", calls, report)
+	}
+	if report.Steps[0].Message != "gone" {
+		t.Fatalf("expected unwrapped message, got %q", report.Steps[0].Message)
+	}
+	if Permanent(nil) != nil {
+		t.Fatal("Permanent(nil) must return nil")
+	}
+}
+
+func TestRunnerRecordsDuration(t *testing.T) {
+	ticks := []time.Time{time.Unix(100, 0), time.Unix(103, 0)}
+	now := func() time.Time {
+		next := ticks[0]
+		ticks = ticks[1:]
+		return next
+	}
+	steps := []Step{{Name: "timed", Check: func(context.Context) (string, error) { return "", nil }}}
+
+	report := Runner{Now: now}.Run(context.Background(), steps)
+	if report.Steps[0].DurationSeconds != 3 {
+		t.Fatalf("expected 3s duration, got %v", report.Steps[0].DurationSeconds)
+	}
+}
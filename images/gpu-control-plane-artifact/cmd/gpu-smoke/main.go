@@ -0,0 +1,127 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command gpu-smoke checks that a GPU node is usable end to end: discovery labels, NodeFeature, GPUDevice,
+// inventory, pool capacity and node allocatable, optionally running nvidia-smi in a canary pod.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/cmd/gpu-smoke/app"
+)
+
+const (
+	exitPassed = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+var (
+	newClient = buildClient
+	exit      = os.Exit
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := runMain(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	exit(code)
+}
+
+func runMain(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flagSet := flag.NewFlagSet("gpu-smoke", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	kubeconfig := flagSet.String("kubeconfig", os.Getenv("KUBECONFIG"), "Path to the kubeconfig; in-cluster config is used when empty.")
+	opts := app.Options{}
+	flagSet.StringVar(&opts.Node, "node", "", "Name of the GPU node to check (required).")
+	flagSet.StringVar(&opts.Pool, "pool", "", "Pool expected to expose capacity on the node; pool steps are skipped when empty.")
+	flagSet.StringVar(&opts.PoolNamespace, "pool-namespace", "", "Namespace of a GPUPool; leave empty for a ClusterGPUPool.")
+	flagSet.DurationVar(&opts.StepTimeout, "step-timeout", 2*time.Minute, "How long each step may wait for its condition.")
+	flagSet.BoolVar(&opts.Canary, "canary", false, "Run nvidia-smi in a pod requesting one unit of the pool resource.")
+	flagSet.StringVar(&opts.CanaryImage, "canary-image", app.DefaultCanaryImage, "Image providing nvidia-smi for the canary pod.")
+	flagSet.StringVar(&opts.CanaryNamespace, "canary-namespace", "", "Namespace for the canary pod; defaults to the pool namespace or \"default\".")
+	flagSet.DurationVar(&opts.CanaryTimeout, "canary-timeout", 5*time.Minute, "How long the canary pod may take to complete.")
+	interval := flagSet.Duration("interval", 2*time.Second, "Delay between retries of a step.")
+	output := flagSet.String("output", app.FormatText, "Report format: text or json.")
+	if err := flagSet.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitPassed
+		}
+		return exitUsage
+	}
+
+	opts.Node = strings.TrimSpace(opts.Node)
+	if opts.Node == "" {
+		fmt.Fprintln(stderr, "--node is required")
+		return exitUsage
+	}
+	if *output != app.FormatText && *output != app.FormatJSON {
+		fmt.Fprintf(stderr, "unknown --output %q, expected text or json\n", *output)
+		return exitUsage
+	}
+	if opts.Canary && opts.Pool == "" {
+		fmt.Fprintln(stderr, "--canary requires --pool")
+		return exitUsage
+	}
+	if opts.CanaryNamespace == "" {
+		opts.CanaryNamespace = opts.PoolNamespace
+	}
+	if opts.CanaryNamespace == "" {
+		opts.CanaryNamespace = "default"
+	}
+
+	cl, err := newClient(*kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "build client: %v\n", err)
+		return exitFailed
+	}
+
+	report, cleanupErr := app.Run(ctx, cl, opts, *interval)
+	if err := app.WriteReport(stdout, report, *output); err != nil {
+		fmt.Fprintf(stderr, "write report: %v\n", err)
+		return exitFailed
+	}
+	if cleanupErr != nil {
+		fmt.Fprintf(stderr, "cleanup: %v\n", cleanupErr)
+	}
+	if !report.Passed {
+		return exitFailed
+	}
+	return exitPassed
+}
+
+func buildClient(kubeconfig string) (client.Client, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := app.NewScheme()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
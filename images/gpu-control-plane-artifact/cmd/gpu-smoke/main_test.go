@@ -0,0 +1,92 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/cmd/gpu-smoke/app"
+)
+
+func stubClient(t *testing.T, objs ...client.Object) {
+	t.Helper()
+	orig := newClient
+	t.Cleanup(func() { newClient = orig })
+
+	scheme, err := app.NewScheme()
+	if err != nil {
+		t.Fatalf("scheme: %v", err)
+	}
+	newClient = func(string) (client.Client, error) {
+		return clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), nil
+	}
+}
+
+func TestRunMainUsageErrors(t *testing.T) {
+	stubClient(t)
+	cases := map[string][]string{
+		"missing node":   {},
+		"unknown output": {"--node", "gpu-1", "--output", "yaml"},
+		"canary no pool": {"--node", "gpu-1", "--canary"},
+		"unknown flag":   {"--bogus"},
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			if code := runMain(context.Background(), args, &bytes.Buffer{}, &bytes.Buffer{}); code != exitUsage {
+				t.Fatalf("expected usage exit code, got %d", code)
+			}
+		})
+	}
+}
+
+func TestRunMainFailingChainExitsNonZero(t *testing.T) {
+	stubClient(t, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"}})
+
+	var stdout bytes.Buffer
+	code := runMain(context.Background(), []string{"--node", "gpu-1", "--step-timeout", "10ms", "--interval", "1ms", "--output", "json"}, &stdout, &bytes.Buffer{})
+	if code != exitFailed {
+		t.Fatalf("expected failure exit code, got %d", code)
+	}
+	var report app.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.FirstFailure != app.StepNodeLabels || report.Node != "gpu-1" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestRunMainClientError(t *testing.T) {
+	orig := newClient
+	t.Cleanup(func() { newClient = orig })
+	newClient = func(string) (client.Client, error) { return nil, errors.New("no kubeconfig") }
+
+	var stderr bytes.Buffer
+	if code := runMain(context.Background(), []string{"--node", "gpu-1"}, &bytes.Buffer{}, &stderr); code != exitFailed {
+		t.Fatalf("expected failure exit code, got %d", code)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("no kubeconfig")) {
+		t.Fatalf("expected client error on stderr, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// IndexFile is the bundle entry describing what every collector gathered or why it failed.
+const IndexFile = "index.json"
+
+// Options selects what the support bundle gathers.
+type Options struct {
+	// Node narrows CRs, handler logs and Events to one node and enables the node diagnostics; empty gathers
+	// cluster-wide data only.
+	Node string
+	// Since bounds the pod logs; zero fetches the whole retained log.
+	Since time.Duration
+	// Namespace is where the module workloads run.
+	Namespace string
+}
+
+// Sources are the API clients the collectors read from: CRs and Events go through Client, pod logs and the
+// pod proxy need Kube.
+type Sources struct {
+	Client client.Client
+	Kube   kubernetes.Interface
+}
+
+// NewScheme returns the scheme with every kind the collectors read.
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("register core scheme: %w", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("register gpu scheme: %w", err)
+	}
+	return scheme, nil
+}
+
+// Index is written to the bundle root as IndexFile.
+type Index struct {
+	GeneratedAt time.Time        `json:"generatedAt"`
+	Node        string           `json:"node,omitempty"`
+	Since       string           `json:"since,omitempty"`
+	Collectors  []CollectorIndex `json:"collectors"`
+}
+
+// CollectorIndex reports one collector run.
+type CollectorIndex struct {
+	Name       string   `json:"name"`
+	Files      []string `json:"files,omitempty"`
+	Redactions int      `json:"redactions,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// Bundle accumulates redacted files in memory until it is archived.
+type Bundle struct {
+	files   map[string][]byte
+	current *CollectorIndex
+}
+
+func newBundle() *Bundle {
+	return &Bundle{files: make(map[string][]byte)}
+}
+
+// Add stores data under name with secrets redacted. Names are slash-separated paths inside the bundle.
+func (b *Bundle) Add(name string, data []byte) {
+	redacted, count := Redact(data)
+	b.files[name] = redacted
+	if b.current != nil {
+		b.current.Files = append(b.current.Files, name)
+		b.current.Redactions += count
+	}
+}
+
+// AddJSON stores v as indented JSON.
+func (b *Bundle) AddJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	b.Add(name, append(data, '\n'))
+	return nil
+}
+
+// Collect runs every collector for opts. A failing collector is recorded in the index and the others still run.
+func Collect(ctx context.Context, src Sources, opts Options, now time.Time) (*Bundle, Index) {
+	bundle := newBundle()
+	index := Index{GeneratedAt: now.UTC(), Node: opts.Node, Collectors: make([]CollectorIndex, 0, len(collectors))}
+	if opts.Since > 0 {
+		index.Since = opts.Since.String()
+	}
+	for _, c := range collectors {
+		entry := CollectorIndex{Name: c.name}
+		bundle.current = &entry
+		if err := c.collect(ctx, src, opts, bundle); err != nil {
+			entry.Error = err.Error()
+		}
+		bundle.current = nil
+		index.Collectors = append(index.Collectors, entry)
+	}
+	return bundle, index
+}
+
+// ArchiveName is the timestamped base name of the bundle for node.
+func ArchiveName(node string, now time.Time) string {
+	name := "gpu-support-bundle"
+	if node != "" {
+		name += "-" + node
+	}
+	return name + "-" + now.UTC().Format("20060102T150405Z")
+}
+
+// WriteArchive packs the bundle and its index into a tar.gz whose entries sit under a root directory.
+func WriteArchive(w io.Writer, root string, bundle *Bundle, index Index) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode index: %w", err)
+	}
+	files := make(map[string][]byte, len(bundle.files)+1)
+	for name, content := range bundle.files {
+		files[name] = content
+	}
+	files[IndexFile] = append(data, '\n')
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := files[name]
+		hdr := &tar.Header{
+			Name:    root + "/" + name,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: index.GeneratedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close tar: %w", err)
+	}
+	return gz.Close()
+}
+
+// Run gathers the bundle and writes it into dir, returning the archive path and the index.
+func Run(ctx context.Context, src Sources, opts Options, dir string, now time.Time) (string, Index, error) {
+	bundle, index := Collect(ctx, src, opts, now)
+	root := ArchiveName(opts.Node, now)
+	path := filepath.Join(dir, root+".tar.gz")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", index, err
+	}
+	if err := WriteArchive(f, root, bundle, index); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return "", index, err
+	}
+	if err := f.Close(); err != nil {
+		return "", index, err
+	}
+	return path, index, nil
+}
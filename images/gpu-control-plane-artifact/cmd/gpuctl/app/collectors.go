@@ -0,0 +1,279 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	commonpod "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/pod"
+)
+
+const (
+	handlerAppName       = "gpu-handler"
+	gfdExtenderContainer = "gfd-extender"
+	gfdExtenderMetrics   = "/metrics"
+	maxLogBytesPerStream = 32 << 20
+	collectorResources   = "resources"
+	collectorLogs        = "logs"
+	collectorDetection   = "detection"
+	collectorEvents      = "events"
+)
+
+type collector struct {
+	name    string
+	collect func(ctx context.Context, src Sources, opts Options, out *Bundle) error
+}
+
+var collectors = []collector{
+	{name: collectorResources, collect: collectResources},
+	{name: collectorLogs, collect: collectLogs},
+	{name: collectorDetection, collect: collectDetection},
+	{name: collectorEvents, collect: collectEvents},
+}
+
+// collectResources dumps every gpu.deckhouse.io object, keeping only the node's devices and state when a node
+// is selected. Pools are always dumped whole because a node's capacity is only meaningful next to them.
+func collectResources(ctx context.Context, src Sources, opts Options, out *Bundle) error {
+	var errs []error
+
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := src.Client.List(ctx, devices); err != nil {
+		errs = append(errs, fmt.Errorf("list GPUDevices: %w", err))
+	} else {
+		items := devices.Items[:0]
+		for _, device := range devices.Items {
+			if opts.Node == "" || device.Status.NodeName == opts.Node {
+				device.ManagedFields = nil
+				items = append(items, device)
+			}
+		}
+		devices.Items = items
+		errs = append(errs, out.AddJSON("resources/gpudevices.json", devices))
+	}
+
+	states := &v1alpha1.GPUNodeStateList{}
+	if err := src.Client.List(ctx, states); err != nil {
+		errs = append(errs, fmt.Errorf("list GPUNodeStates: %w", err))
+	} else {
+		items := states.Items[:0]
+		for _, state := range states.Items {
+			if opts.Node == "" || state.Name == opts.Node || state.Spec.NodeName == opts.Node {
+				state.ManagedFields = nil
+				items = append(items, state)
+			}
+		}
+		states.Items = items
+		errs = append(errs, out.AddJSON("resources/gpunodestates.json", states))
+	}
+
+	pools := &v1alpha1.GPUPoolList{}
+	if err := src.Client.List(ctx, pools); err != nil {
+		errs = append(errs, fmt.Errorf("list GPUPools: %w", err))
+	} else {
+		for i := range pools.Items {
+			pools.Items[i].ManagedFields = nil
+		}
+		errs = append(errs, out.AddJSON("resources/gpupools.json", pools))
+	}
+
+	clusterPools := &v1alpha1.ClusterGPUPoolList{}
+	if err := src.Client.List(ctx, clusterPools); err != nil {
+		errs = append(errs, fmt.Errorf("list ClusterGPUPools: %w", err))
+	} else {
+		for i := range clusterPools.Items {
+			clusterPools.Items[i].ManagedFields = nil
+		}
+		errs = append(errs, out.AddJSON("resources/clustergpupools.json", clusterPools))
+	}
+
+	return errors.Join(errs...)
+}
+
+// collectLogs fetches the logs of every controller pod and of the gpu-handler pods on the node. A stream that
+// cannot be read is reported and the remaining containers are still fetched.
+func collectLogs(ctx context.Context, src Sources, opts Options, out *Bundle) error {
+	var errs []error
+	var pods []corev1.Pod
+	for _, app := range []string{common.ControllerDeploymentName, handlerAppName} {
+		list, err := src.Kube.CoreV1().Pods(opts.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + app})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list %s pods: %w", app, err))
+			continue
+		}
+		for _, pod := range list.Items {
+			if app == handlerAppName && opts.Node != "" && pod.Spec.NodeName != opts.Node {
+				continue
+			}
+			pods = append(pods, pod)
+		}
+	}
+
+	logOpts := corev1.PodLogOptions{}
+	if opts.Since > 0 {
+		seconds := int64(opts.Since.Seconds())
+		logOpts.SinceSeconds = &seconds
+	}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			podLogOpts := logOpts
+			podLogOpts.Container = container.Name
+			data, err := readLogs(ctx, src, pod, &podLogOpts)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("logs %s/%s: %w", pod.Name, container.Name, err))
+				continue
+			}
+			out.Add("logs/"+pod.Name+"/"+container.Name+".log", data)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func readLogs(ctx context.Context, src Sources, pod corev1.Pod, opts *corev1.PodLogOptions) ([]byte, error) {
+	stream, err := src.Kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.Close() }()
+	return io.ReadAll(io.LimitReader(stream, maxLogBytesPerStream))
+}
+
+// collectDetection fetches the gfd-extender detection payload and metrics and the dcgm-exporter metrics of the
+// node through the API server pod proxy, so it works from outside the pod network.
+func collectDetection(ctx context.Context, src Sources, opts Options, out *Bundle) error {
+	if opts.Node == "" {
+		return nil
+	}
+	return errors.Join(
+		proxyNodePod(ctx, src, opts, out, common.AppName(common.ComponentGFDExtender), extenderPorts, map[string]string{
+			detection.PathV2:   "node/detection.json",
+			gfdExtenderMetrics: "node/gfd-extender-metrics.txt",
+		}),
+		proxyNodePod(ctx, src, opts, out, common.AppName(common.ComponentDCGMExporter), dcgmExporterPorts, map[string]string{
+			"/metrics": "node/dcgm-exporter-metrics.txt",
+		}),
+	)
+}
+
+var (
+	extenderPorts     = commonpod.PortLookup{Name: detection.PortName, Container: gfdExtenderContainer, Fallback: detection.DefaultPort}
+	dcgmExporterPorts = commonpod.PortLookup{Name: commonpod.MetricsPortName, Fallback: commonpod.DCGMExporterPort}
+)
+
+// proxyNodePod stores the bodies of paths served by the Ready app pod on the node under the mapped bundle names.
+func proxyNodePod(ctx context.Context, src Sources, opts Options, out *Bundle, app string, ports commonpod.PortLookup, paths map[string]string) error {
+	list, err := src.Kube.CoreV1().Pods(opts.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=" + app,
+		FieldSelector: "spec.nodeName=" + opts.Node,
+	})
+	if err != nil {
+		return fmt.Errorf("list %s pods: %w", app, err)
+	}
+
+	var skipped []error
+	for _, pod := range list.Items {
+		if pod.Spec.NodeName != opts.Node {
+			continue
+		}
+		if !commonpod.IsReady(&pod) {
+			skipped = append(skipped, fmt.Errorf("pod %s is not ready", pod.Name))
+			continue
+		}
+		port, ok := commonpod.ResolvePort(&pod, ports)
+		if !ok {
+			skipped = append(skipped, fmt.Errorf("pod %s has no container port named %q", pod.Name, ports.Name))
+			continue
+		}
+		var errs []error
+		for path, name := range paths {
+			data, err := src.Kube.CoreV1().Pods(pod.Namespace).ProxyGet("http", pod.Name, strconv.Itoa(int(port)), path, nil).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("get %s from %s: %w", path, pod.Name, err))
+				continue
+			}
+			out.Add(name, data)
+		}
+		return errors.Join(errs...)
+	}
+	return errors.Join(append(skipped, fmt.Errorf("no usable %s pod on node %s", app, opts.Node))...)
+}
+
+// collectEvents keeps the Events whose involved object is a gpu.deckhouse.io object, or the selected Node and the
+// node's devices and state when a node is set.
+func collectEvents(ctx context.Context, src Sources, opts Options, out *Bundle) error {
+	events := &corev1.EventList{}
+	if err := src.Client.List(ctx, events); err != nil {
+		return fmt.Errorf("list events: %w", err)
+	}
+
+	var names map[string]struct{}
+	if opts.Node != "" {
+		var err error
+		if names, err = nodeObjectNames(ctx, src.Client, opts.Node); err != nil {
+			return err
+		}
+	}
+
+	items := make([]corev1.Event, 0)
+	for _, event := range events.Items {
+		if !keepEvent(event.InvolvedObject, opts.Node, names) {
+			continue
+		}
+		event.ManagedFields = nil
+		items = append(items, event)
+	}
+	events.Items = items
+	return out.AddJSON("events.json", events)
+}
+
+func keepEvent(ref corev1.ObjectReference, node string, names map[string]struct{}) bool {
+	if node != "" && ref.Kind == "Node" {
+		return ref.Name == node
+	}
+	if !strings.HasPrefix(ref.APIVersion, v1alpha1.GroupVersion.Group+"/") {
+		return false
+	}
+	if node == "" {
+		return true
+	}
+	_, ok := names[ref.Name]
+	return ok
+}
+
+// nodeObjectNames returns the names of the GPUDevices and the GPUNodeState that belong to node.
+func nodeObjectNames(ctx context.Context, c client.Client, node string) (map[string]struct{}, error) {
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := c.List(ctx, devices); err != nil {
+		return nil, fmt.Errorf("list GPUDevices: %w", err)
+	}
+	names := map[string]struct{}{node: {}}
+	for _, device := range devices.Items {
+		if device.Status.NodeName == node {
+			names[device.Name] = struct{}{}
+		}
+	}
+	return names, nil
+}
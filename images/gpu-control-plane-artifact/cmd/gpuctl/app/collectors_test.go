@@ -0,0 +1,368 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+)
+
+const testNamespace = "d8-gpu-control-plane"
+
+func newTestClient(t *testing.T, objs ...client.Object) client.WithWatch {
+	t.Helper()
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("scheme: %v", err)
+	}
+	return clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func collected(t *testing.T, collect func(context.Context, Sources, Options, *Bundle) error, src Sources, opts Options) (*Bundle, error) {
+	t.Helper()
+	bundle := newBundle()
+	err := collect(context.Background(), src, opts, bundle)
+	return bundle, err
+}
+
+func decodeItems[T any](t *testing.T, bundle *Bundle, name string) []T {
+	t.Helper()
+	data, ok := bundle.files[name]
+	if !ok {
+		t.Fatalf("bundle has no %s, files: %v", name, fileNames(bundle))
+	}
+	var list struct {
+		Items []T `json:"items"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	return list.Items
+}
+
+func fileNames(bundle *Bundle) []string {
+	names := make([]string, 0, len(bundle.files))
+	for name := range bundle.files {
+		names = append(names, name)
+	}
+	return names
+}
+
+func device(name, node string) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: node},
+	}
+}
+
+func TestCollectResourcesFiltersByNode(t *testing.T) {
+	cl := newTestClient(t,
+		device("gpu-a-0", "node-a"),
+		device("gpu-b-0", "node-b"),
+		&v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Spec: v1alpha1.GPUNodeStateSpec{NodeName: "node-a"}},
+		&v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}, Spec: v1alpha1.GPUNodeStateSpec{NodeName: "node-b"}},
+		&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "team"}},
+		&v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+	)
+
+	bundle, err := collected(t, collectResources, Sources{Client: cl}, Options{Node: "node-a"})
+	if err != nil {
+		t.Fatalf("collectResources: %v", err)
+	}
+	if devices := decodeItems[v1alpha1.GPUDevice](t, bundle, "resources/gpudevices.json"); len(devices) != 1 || devices[0].Name != "gpu-a-0" {
+		t.Fatalf("expected only the node-a device, got %+v", devices)
+	}
+	if states := decodeItems[v1alpha1.GPUNodeState](t, bundle, "resources/gpunodestates.json"); len(states) != 1 || states[0].Name != "node-a" {
+		t.Fatalf("expected only the node-a state, got %+v", states)
+	}
+	if pools := decodeItems[v1alpha1.GPUPool](t, bundle, "resources/gpupools.json"); len(pools) != 1 {
+		t.Fatalf("expected pools to be dumped whole, got %+v", pools)
+	}
+	if pools := decodeItems[v1alpha1.ClusterGPUPool](t, bundle, "resources/clustergpupools.json"); len(pools) != 1 {
+		t.Fatalf("expected cluster pools to be dumped whole, got %+v", pools)
+	}
+
+	bundle, err = collected(t, collectResources, Sources{Client: cl}, Options{})
+	if err != nil {
+		t.Fatalf("collectResources: %v", err)
+	}
+	if devices := decodeItems[v1alpha1.GPUDevice](t, bundle, "resources/gpudevices.json"); len(devices) != 2 {
+		t.Fatalf("expected every device without a node filter, got %d", len(devices))
+	}
+}
+
+func TestCollectResourcesKeepsGoingOnListError(t *testing.T) {
+	cl := interceptor.NewClient(newTestClient(t, &v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "shared"}}), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*v1alpha1.GPUDeviceList); ok {
+				return errors.New("forbidden")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	})
+
+	bundle, err := collected(t, collectResources, Sources{Client: cl}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "list GPUDevices") {
+		t.Fatalf("expected the GPUDevice list error, got %v", err)
+	}
+	if _, ok := bundle.files["resources/gpudevices.json"]; ok {
+		t.Fatalf("expected no device dump after the list error")
+	}
+	if pools := decodeItems[v1alpha1.ClusterGPUPool](t, bundle, "resources/clustergpupools.json"); len(pools) != 1 {
+		t.Fatalf("expected the other kinds to be collected, got %+v", pools)
+	}
+}
+
+func pod(name, app, node string, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: containers},
+	}
+}
+
+func TestCollectLogsSelectsControllerAndNodeHandler(t *testing.T) {
+	kube := kubefake.NewSimpleClientset(
+		pod("controller-0", common.ControllerDeploymentName, "master-0", corev1.Container{Name: "controller"}, corev1.Container{Name: "kube-rbac-proxy"}),
+		pod("handler-a", handlerAppName, "node-a", corev1.Container{Name: "gpu-handler"}),
+		pod("handler-b", handlerAppName, "node-b", corev1.Container{Name: "gpu-handler"}),
+	)
+
+	bundle, err := collected(t, collectLogs, Sources{Kube: kube}, Options{Node: "node-a", Namespace: testNamespace, Since: time.Hour})
+	if err != nil {
+		t.Fatalf("collectLogs: %v", err)
+	}
+	for _, name := range []string{"logs/controller-0/controller.log", "logs/controller-0/kube-rbac-proxy.log", "logs/handler-a/gpu-handler.log"} {
+		if _, ok := bundle.files[name]; !ok {
+			t.Fatalf("expected %s, got %v", name, fileNames(bundle))
+		}
+	}
+	if len(bundle.files) != 3 {
+		t.Fatalf("expected the node-b handler to be skipped, got %v", fileNames(bundle))
+	}
+
+	var since *int64
+	for _, action := range kube.Actions() {
+		if action.GetSubresource() != "log" {
+			continue
+		}
+		if opts, ok := action.(k8stesting.GenericActionImpl).Value.(*corev1.PodLogOptions); ok {
+			since = opts.SinceSeconds
+		}
+	}
+	if since == nil || *since != 3600 {
+		t.Fatalf("expected logs since 3600s, got %v", since)
+	}
+}
+
+func TestCollectLogsReportsListError(t *testing.T) {
+	kube := kubefake.NewSimpleClientset(pod("handler-a", handlerAppName, "node-a", corev1.Container{Name: "gpu-handler"}))
+	kube.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.ListAction).GetListRestrictions().Labels.String() == "app="+common.ControllerDeploymentName {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+
+	bundle, err := collected(t, collectLogs, Sources{Kube: kube}, Options{Namespace: testNamespace})
+	if err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("expected the controller pod list error, got %v", err)
+	}
+	if _, ok := bundle.files["logs/handler-a/gpu-handler.log"]; !ok {
+		t.Fatalf("expected handler logs despite the controller error, got %v", fileNames(bundle))
+	}
+}
+
+// proxyResponse serves a pod proxy request from an httptest server standing in for gfd-extender.
+type proxyResponse struct {
+	url string
+}
+
+func (p proxyResponse) DoRaw(ctx context.Context) ([]byte, error) {
+	stream, err := p.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.Close() }()
+	return io.ReadAll(stream)
+}
+
+func (p proxyResponse) Stream(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, errors.New(resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func readyPod(p *corev1.Pod) *corev1.Pod {
+	p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	return p
+}
+
+func extenderPod(name, node string) *corev1.Pod {
+	return readyPod(pod(name, common.AppName(common.ComponentGFDExtender), node,
+		corev1.Container{Name: gfdExtenderContainer, Ports: []corev1.ContainerPort{{Name: detection.PortName, ContainerPort: 2376}}},
+	))
+}
+
+func TestCollectDetectionFetchesThroughPodProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case detection.PathV2:
+			_, _ = w.Write([]byte(`{"schemaVersion":2,"devices":[{"index":0,"uuid":"GPU-1"}]}`))
+		case "/metrics":
+			_, _ = w.Write([]byte("DCGM_FI_DEV_GPU_TEMP 41\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// The exporter puts a sidecar first and exposes health next to metrics; the named port must win.
+	exporter := readyPod(pod("dcgm-a", common.AppName(common.ComponentDCGMExporter), "node-a",
+		corev1.Container{Name: "kube-rbac-proxy", Ports: []corev1.ContainerPort{{Name: "https", ContainerPort: 8443}}},
+		corev1.Container{Name: "dcgm-exporter", Ports: []corev1.ContainerPort{{Name: "health", ContainerPort: 8080}, {Name: "metrics", ContainerPort: 9500}}},
+	))
+	kube := kubefake.NewSimpleClientset(extenderPod("gfd-b", "node-b"), extenderPod("gfd-a", "node-a"), exporter)
+	proxied := map[string]bool{}
+	kube.PrependProxyReactor("pods", func(action k8stesting.Action) (bool, restclient.ResponseWrapper, error) {
+		proxy := action.(k8stesting.ProxyGetAction)
+		proxied[proxy.GetName()+":"+proxy.GetPort()+proxy.GetPath()] = true
+		if proxy.GetName() == "gfd-a" && proxy.GetPath() == gfdExtenderMetrics {
+			return true, proxyResponse{url: srv.URL + "/missing"}, nil
+		}
+		return true, proxyResponse{url: srv.URL + proxy.GetPath()}, nil
+	})
+
+	bundle, err := collected(t, collectDetection, Sources{Kube: kube}, Options{Node: "node-a", Namespace: testNamespace})
+	if err == nil || !strings.Contains(err.Error(), gfdExtenderMetrics) {
+		t.Fatalf("expected the missing extender metrics to be reported, got %v", err)
+	}
+	if got := string(bundle.files["node/detection.json"]); !strings.Contains(got, "GPU-1") {
+		t.Fatalf("expected the detection payload, got %q", got)
+	}
+	if got := string(bundle.files["node/dcgm-exporter-metrics.txt"]); !strings.Contains(got, "DCGM_FI_DEV_GPU_TEMP") {
+		t.Fatalf("expected the dcgm-exporter metrics, got %q", got)
+	}
+	want := map[string]bool{
+		"gfd-a:2376" + detection.PathV2:   true,
+		"gfd-a:2376" + gfdExtenderMetrics: true,
+		"dcgm-a:9500/metrics":             true,
+	}
+	if len(proxied) != len(want) {
+		t.Fatalf("expected proxy requests %v, got %v", want, proxied)
+	}
+	for target := range want {
+		if !proxied[target] {
+			t.Fatalf("expected proxy requests %v, got %v", want, proxied)
+		}
+	}
+}
+
+func TestCollectDetectionWithoutUsablePods(t *testing.T) {
+	notReady := extenderPod("gfd-a", "node-a")
+	notReady.Status.Conditions = nil
+	kube := kubefake.NewSimpleClientset(extenderPod("gfd-b", "node-b"), notReady)
+
+	_, err := collected(t, collectDetection, Sources{Kube: kube}, Options{Node: "node-a", Namespace: testNamespace})
+	if err == nil || !strings.Contains(err.Error(), "pod gfd-a is not ready") || !strings.Contains(err.Error(), "no usable "+common.AppName(common.ComponentDCGMExporter)) {
+		t.Fatalf("expected both lookups to explain the failure, got %v", err)
+	}
+	if bundle, err := collected(t, collectDetection, Sources{Kube: kube}, Options{Namespace: testNamespace}); err != nil || len(bundle.files) != 0 {
+		t.Fatalf("expected detection to be skipped without a node, got %v %v", err, fileNames(bundle))
+	}
+}
+
+func event(name string, ref corev1.ObjectReference) *corev1.Event {
+	return &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, InvolvedObject: ref}
+}
+
+func TestCollectEventsKeepsGPUAndNodeObjects(t *testing.T) {
+	gpuAPI := v1alpha1.GroupVersion.String()
+	cl := newTestClient(t,
+		device("gpu-a-0", "node-a"),
+		device("gpu-b-0", "node-b"),
+		event("device-a", corev1.ObjectReference{APIVersion: gpuAPI, Kind: "GPUDevice", Name: "gpu-a-0"}),
+		event("device-b", corev1.ObjectReference{APIVersion: gpuAPI, Kind: "GPUDevice", Name: "gpu-b-0"}),
+		event("state-a", corev1.ObjectReference{APIVersion: gpuAPI, Kind: "GPUNodeState", Name: "node-a"}),
+		event("node-a", corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: "node-a"}),
+		event("node-b", corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: "node-b"}),
+		event("pod", corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "gpu-a-0"}),
+	)
+
+	eventNames := func(bundle *Bundle) map[string]bool {
+		names := map[string]bool{}
+		for _, e := range decodeItems[corev1.Event](t, bundle, "events.json") {
+			names[e.Name] = true
+		}
+		return names
+	}
+
+	bundle, err := collected(t, collectEvents, Sources{Client: cl}, Options{Node: "node-a"})
+	if err != nil {
+		t.Fatalf("collectEvents: %v", err)
+	}
+	if got := eventNames(bundle); len(got) != 3 || !got["device-a"] || !got["state-a"] || !got["node-a"] {
+		t.Fatalf("unexpected node-a events %v", got)
+	}
+
+	bundle, err = collected(t, collectEvents, Sources{Client: cl}, Options{})
+	if err != nil {
+		t.Fatalf("collectEvents: %v", err)
+	}
+	if got := eventNames(bundle); len(got) != 3 || !got["device-a"] || !got["device-b"] || !got["state-a"] {
+		t.Fatalf("unexpected cluster-wide events %v", got)
+	}
+}
+
+func TestCollectEventsListError(t *testing.T) {
+	cl := interceptor.NewClient(newTestClient(t), interceptor.Funcs{
+		List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+			return errors.New("forbidden")
+		},
+	})
+	if bundle, err := collected(t, collectEvents, Sources{Client: cl}, Options{}); err == nil || len(bundle.files) != 0 {
+		t.Fatalf("expected the list error and no events file, got %v %v", err, fileNames(bundle))
+	}
+}
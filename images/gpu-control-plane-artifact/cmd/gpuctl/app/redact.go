@@ -0,0 +1,51 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import "regexp"
+
+const redactedValue = "[REDACTED]"
+
+// redactionRule replaces the secret part of a match while keeping the prefix that makes the line readable.
+type redactionRule struct {
+	pattern *regexp.Regexp
+	replace string
+}
+
+// redactionRules cover the token shapes that show up in controller and handler logs: bearer headers, JWTs
+// such as service account tokens, and key=value or "key": "value" pairs named like credentials.
+var redactionRules = []redactionRule{
+	{pattern: regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`), replace: "${1}" + redactedValue},
+	{pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), replace: redactedValue},
+	{
+		pattern: regexp.MustCompile(`(?i)((?:token|password|passwd|secret|api[_-]?key)"?\s*[:=]\s*"?)[^\s",}]+`),
+		replace: "${1}" + redactedValue,
+	},
+}
+
+// Redact masks secrets in data and reports how many values were replaced.
+func Redact(data []byte) ([]byte, int) {
+	count := 0
+	for _, rule := range redactionRules {
+		data = rule.pattern.ReplaceAllFunc(data, func(match []byte) []byte {
+			replaced := rule.pattern.ReplaceAll(match, []byte(rule.replace))
+			if string(replaced) != string(match) {
+				count++
+			}
+			return replaced
+		})
+	}
+	return data, count
+}
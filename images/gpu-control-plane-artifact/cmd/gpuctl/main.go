@@ -0,0 +1,183 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command gpuctl holds operator tooling for the GPU control plane. The support-bundle command gathers the CRs,
+// logs, Events and node detection data needed to escalate a GPU issue into a single tar.gz; alerts export renders
+// the alert catalog registered next to the controller metrics as Prometheus rules.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/cmd/gpuctl/app"
+	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/alerts"
+)
+
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+var (
+	newSources = buildSources
+	now        = time.Now
+	exit       = os.Exit
+)
+
+const usage = `Usage: gpuctl <command> [flags]
+
+Commands:
+  support-bundle  Collect CRs, logs, Events and node diagnostics into a tar.gz.
+  alerts export   Print the alerts defined for the controller metrics as a PrometheusRule.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := runMain(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	exit(code)
+}
+
+func runMain(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	switch args[0] {
+	case "support-bundle":
+		return runSupportBundle(ctx, args[1:], stdout, stderr)
+	case "alerts":
+		return runAlerts(args[1:], stdout, stderr)
+	case "-h", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n%s", args[0], usage)
+		return exitUsage
+	}
+}
+
+func runSupportBundle(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flagSet := flag.NewFlagSet("gpuctl support-bundle", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	kubeconfig := flagSet.String("kubeconfig", os.Getenv("KUBECONFIG"), "Path to the kubeconfig; in-cluster config is used when empty.")
+	opts := app.Options{}
+	flagSet.StringVar(&opts.Node, "node", "", "Node to collect diagnostics for; cluster-wide data only when empty.")
+	flagSet.DurationVar(&opts.Since, "since", 2*time.Hour, "How far back to fetch pod logs; 0 fetches the whole retained log.")
+	flagSet.StringVar(&opts.Namespace, "namespace", common.WorkloadsNamespace, "Namespace of the module workloads.")
+	dir := flagSet.String("output-dir", ".", "Directory to write the bundle to.")
+	if err := flagSet.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	opts.Node = strings.TrimSpace(opts.Node)
+	if opts.Since < 0 {
+		fmt.Fprintln(stderr, "--since must not be negative")
+		return exitUsage
+	}
+
+	src, err := newSources(*kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "build client: %v\n", err)
+		return exitFailed
+	}
+
+	path, index, err := app.Run(ctx, src, opts, *dir, now())
+	if err != nil {
+		fmt.Fprintf(stderr, "write bundle: %v\n", err)
+		return exitFailed
+	}
+	for _, c := range index.Collectors {
+		if c.Error != "" {
+			fmt.Fprintf(stderr, "collector %s: %s\n", c.Name, c.Error)
+		}
+	}
+	fmt.Fprintln(stdout, path)
+	return exitOK
+}
+
+func runAlerts(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprint(stderr, "Usage: gpuctl alerts export [flags]\n")
+		return exitUsage
+	}
+	flagSet := flag.NewFlagSet("gpuctl alerts export", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	groupsOnly := flagSet.Bool("groups", false, "Print only the rule groups, in the layout of monitoring/prometheus-rules.")
+	name := flagSet.String("name", "gpu-control-plane-controller-metrics", "Name of the PrometheusRule object.")
+	namespace := flagSet.String("namespace", common.WorkloadsNamespace, "Namespace of the PrometheusRule object.")
+	if err := flagSet.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	catalog := alerts.Catalog()
+	if err := alerts.Validate(catalog); err != nil {
+		fmt.Fprintf(stderr, "invalid alert catalog: %v\n", err)
+		return exitFailed
+	}
+	var doc any = alerts.NewPrometheusRule(*name, *namespace, catalog)
+	if *groupsOnly {
+		doc = alerts.Groups(catalog)
+	}
+	data, err := alerts.Marshal(doc)
+	if err != nil {
+		fmt.Fprintf(stderr, "render alerts: %v\n", err)
+		return exitFailed
+	}
+	if *groupsOnly {
+		fmt.Fprint(stdout, alerts.GeneratedHeader)
+	}
+	_, _ = stdout.Write(data)
+	return exitOK
+}
+
+func buildSources(kubeconfig string) (app.Sources, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return app.Sources{}, err
+	}
+	scheme, err := app.NewScheme()
+	if err != nil {
+		return app.Sources{}, err
+	}
+	cl, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return app.Sources{}, err
+	}
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return app.Sources{}, err
+	}
+	return app.Sources{Client: cl, Kube: kube}, nil
+}
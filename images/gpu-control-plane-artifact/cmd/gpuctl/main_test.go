@@ -0,0 +1,124 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/cmd/gpuctl/app"
+)
+
+func stubSources(t *testing.T) {
+	t.Helper()
+	origSources, origNow := newSources, now
+	t.Cleanup(func() { newSources, now = origSources, origNow })
+
+	scheme, err := app.NewScheme()
+	if err != nil {
+		t.Fatalf("scheme: %v", err)
+	}
+	newSources = func(string) (app.Sources, error) {
+		return app.Sources{
+			Client: clientfake.NewClientBuilder().WithScheme(scheme).Build(),
+			Kube:   kubefake.NewSimpleClientset(),
+		}, nil
+	}
+	now = func() time.Time { return time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC) }
+}
+
+func TestRunMainUsageErrors(t *testing.T) {
+	stubSources(t)
+	cases := map[string][]string{
+		"no command":      {},
+		"unknown command": {"bogus"},
+		"unknown flag":    {"support-bundle", "--bogus"},
+		"negative since":  {"support-bundle", "--since", "-1h"},
+		"alerts no verb":  {"alerts"},
+		"alerts bad verb": {"alerts", "import"},
+		"alerts bad flag": {"alerts", "export", "--bogus"},
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			if code := runMain(context.Background(), args, &bytes.Buffer{}, &bytes.Buffer{}); code != exitUsage {
+				t.Fatalf("expected usage exit code, got %d", code)
+			}
+		})
+	}
+}
+
+func TestRunMainSupportBundleWritesArchive(t *testing.T) {
+	stubSources(t)
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	code := runMain(context.Background(), []string{"support-bundle", "--node", "node-a", "--output-dir", dir}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr.String())
+	}
+	want := filepath.Join(dir, "gpu-support-bundle-node-a-20250301T123000Z.tar.gz")
+	if strings.TrimSpace(stdout.String()) != want {
+		t.Fatalf("expected the archive path on stdout, got %q", stdout.String())
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+	// The node has no gfd-extender pod, which is reported without failing the bundle.
+	if !strings.Contains(stderr.String(), "collector detection") {
+		t.Fatalf("expected the detection failure on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRunMainSupportBundleFailures(t *testing.T) {
+	stubSources(t)
+	missing := filepath.Join(t.TempDir(), "missing")
+	if code := runMain(context.Background(), []string{"support-bundle", "--output-dir", missing}, &bytes.Buffer{}, &bytes.Buffer{}); code != exitFailed {
+		t.Fatalf("expected failure for an unwritable directory, got %d", code)
+	}
+
+	newSources = func(string) (app.Sources, error) { return app.Sources{}, errors.New("no kubeconfig") }
+	if code := runMain(context.Background(), []string{"support-bundle"}, &bytes.Buffer{}, &bytes.Buffer{}); code != exitFailed {
+		t.Fatalf("expected failure when the client cannot be built, got %d", code)
+	}
+}
+
+func TestRunMainAlertsExport(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runMain(context.Background(), []string{"alerts", "export", "--namespace", "d8-test"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr.String())
+	}
+	for _, want := range []string{"kind: PrometheusRule", "namespace: d8-test", "alert: D8GPUInventoryDeletionsThrottled"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in the output:\n%s", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := runMain(context.Background(), []string{"alerts", "export", "--groups"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "# Code generated") || strings.Contains(stdout.String(), "PrometheusRule") {
+		t.Fatalf("expected a bare generated rule file, got:\n%s", stdout.String())
+	}
+}
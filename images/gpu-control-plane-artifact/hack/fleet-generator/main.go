@@ -0,0 +1,99 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command fleet-generator populates a kind or envtest cluster with the synthetic GPU fleet the inventory
+// benchmarks use, so reconcile cost can be measured end to end against a real API server:
+//
+//	go run ./hack/fleet-generator --nodes 1000 --gpus-per-node 8
+//	go run ./hack/fleet-generator --delete
+//
+// Run the controller against the cluster afterwards and watch the reconcile metrics; the NodeFeature
+// namespace has to be in inventory.trustedNodeFeatureNamespaces. Never point it at a production cluster.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/fleet"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flagSet := flag.NewFlagSet("fleet-generator", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	kubeconfig := flagSet.String("kubeconfig", os.Getenv("KUBECONFIG"), "Path to the kubeconfig; in-cluster config is used when empty.")
+	opts := fleet.Options{}
+	flagSet.IntVar(&opts.Nodes, "nodes", 100, "Number of synthetic nodes.")
+	flagSet.IntVar(&opts.GPUsPerNode, "gpus-per-node", fleet.DefaultGPUsPerNode, "GPUs published per node.")
+	flagSet.StringVar(&opts.NodePrefix, "node-prefix", fleet.DefaultNodePrefix, "Name prefix of the synthetic nodes.")
+	flagSet.StringVar(&opts.Namespace, "namespace", "", "Namespace of the NodeFeatures; the default trusted NFD namespace when empty.")
+	remove := flagSet.Bool("delete", false, "Remove the synthetic fleet instead of creating it.")
+	if err := flagSet.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cl, err := buildClient(*kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "build client: %v\n", err)
+		return 1
+	}
+
+	if *remove {
+		if err := fleet.Delete(ctx, cl); err != nil {
+			fmt.Fprintf(stderr, "delete fleet: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, "synthetic fleet removed")
+		return 0
+	}
+
+	if err := fleet.Apply(ctx, cl, fleet.Generate(opts)); err != nil {
+		fmt.Fprintf(stderr, "apply fleet: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "synthetic fleet of %d nodes applied\n", opts.Nodes)
+	return 0
+}
+
+func buildClient(kubeconfig string) (client.Client, error) {
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := fleet.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
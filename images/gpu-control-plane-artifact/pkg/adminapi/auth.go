@@ -0,0 +1,84 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/httpauth"
+)
+
+// Authenticator resolves bearer tokens to the identity of the caller.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (user string, ok bool, err error)
+}
+
+// TokenReviewAuthenticator validates tokens through the TokenReview API. Admin calls are rare, so answers
+// are not cached and a revoked token stops working immediately.
+type TokenReviewAuthenticator struct {
+	client client.Client
+}
+
+// NewTokenReviewAuthenticator constructs an authenticator backed by TokenReview.
+func NewTokenReviewAuthenticator(c client.Client) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{client: c}
+}
+
+// Authenticate implements Authenticator.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (string, bool, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := a.client.Create(ctx, review); err != nil {
+		return "", false, fmt.Errorf("create TokenReview: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", false, nil
+	}
+	return review.Status.User.Username, true, nil
+}
+
+type callerKey struct{}
+
+// caller returns the identity stored by withAuth.
+func caller(ctx context.Context) string {
+	user, _ := ctx.Value(callerKey{}).(string)
+	return user
+}
+
+func (h *Handler) withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := httpauth.BearerToken(r)
+		if token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		user, ok, err := h.auth.Authenticate(r.Context(), token)
+		if err != nil {
+			h.log.Error(err, "failed to authenticate request", "path", r.URL.Path)
+			http.Error(w, "authentication failed", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			h.log.Info("rejected unauthenticated admin request", "path", r.URL.Path)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, user)))
+	})
+}
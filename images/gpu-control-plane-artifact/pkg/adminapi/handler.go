@@ -0,0 +1,130 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
+)
+
+const (
+	defaultSuppressTTL = 30 * time.Minute
+	maxSuppressTTL     = 24 * time.Hour
+)
+
+// NodeQueue is the part of the inventory work queue the admin API drives.
+type NodeQueue interface {
+	RequeueAllNodes(ctx context.Context) (int, error)
+	Suppress(node string, ttl time.Duration) time.Time
+}
+
+// RequeueResult is returned by POST /admin/requeue-all.
+type RequeueResult struct {
+	Requeued int `json:"requeued"`
+}
+
+// SuppressResult is returned by POST /admin/suppress-node.
+type SuppressResult struct {
+	Node  string    `json:"node"`
+	Until time.Time `json:"until"`
+}
+
+// Handler serves the admin actions. Every action is logged with the identity of the caller.
+type Handler struct {
+	queue NodeQueue
+	auth  Authenticator
+	log   logr.Logger
+}
+
+// NewHandler constructs the admin handler.
+func NewHandler(log logr.Logger, queue NodeQueue, auth Authenticator) *Handler {
+	return &Handler{queue: queue, auth: auth, log: log}
+}
+
+// Routes returns the HTTP routes with authentication applied.
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/requeue-all", h.requeueAll)
+	mux.HandleFunc("POST /admin/suppress-node", h.suppressNode)
+	return h.withAuth(mux)
+}
+
+func (h *Handler) requeueAll(w http.ResponseWriter, r *http.Request) {
+	user := caller(r.Context())
+	count, err := h.queue.RequeueAllNodes(r.Context())
+	if err != nil {
+		h.log.Error(err, "failed to requeue nodes", "user", user)
+		status := http.StatusInternalServerError
+		if errors.Is(err, inventory.ErrQueueNotStarted) {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, "failed to requeue nodes", status)
+		return
+	}
+	h.log.Info("requeued all nodes", "user", user, "count", count)
+	h.writeJSON(w, RequeueResult{Requeued: count})
+}
+
+func (h *Handler) suppressNode(w http.ResponseWriter, r *http.Request) {
+	user := caller(r.Context())
+	query := r.URL.Query()
+	name := strings.TrimSpace(query.Get("name"))
+	if name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	ttl, err := parseTTL(query.Get("ttl"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid ttl: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	until := h.queue.Suppress(name, ttl)
+	h.log.Info("suppressed node reconciles", "user", user, "node", name, "ttl", ttl.String(), "until", until)
+	h.writeJSON(w, SuppressResult{Node: name, Until: until})
+}
+
+func parseTTL(raw string) (time.Duration, error) {
+	if raw == "" {
+		return defaultSuppressTTL, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	if ttl > maxSuppressTTL {
+		return 0, fmt.Errorf("must not exceed %s", maxSuppressTTL)
+	}
+	return ttl, nil
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		h.log.Error(err, "failed to encode response")
+	}
+}
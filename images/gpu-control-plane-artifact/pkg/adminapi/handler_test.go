@@ -0,0 +1,147 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
+)
+
+const testToken = "valid-token"
+
+type staticAuthenticator struct{}
+
+func (staticAuthenticator) Authenticate(_ context.Context, token string) (string, bool, error) {
+	return "admin", token == testToken, nil
+}
+
+type fakeQueue struct {
+	nodes      int
+	err        error
+	suppressed map[string]time.Duration
+}
+
+func (q *fakeQueue) RequeueAllNodes(context.Context) (int, error) {
+	return q.nodes, q.err
+}
+
+func (q *fakeQueue) Suppress(node string, ttl time.Duration) time.Time {
+	if q.suppressed == nil {
+		q.suppressed = map[string]time.Duration{}
+	}
+	q.suppressed[node] = ttl
+	return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(ttl)
+}
+
+func doRequest(t *testing.T, queue NodeQueue, method, target, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	NewHandler(testr.New(t), queue, staticAuthenticator{}).Routes().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminAPIRequiresAuthentication(t *testing.T) {
+	queue := &fakeQueue{nodes: 3}
+	if rec := doRequest(t, queue, http.MethodPost, "/admin/requeue-all", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+	if rec := doRequest(t, queue, http.MethodPost, "/admin/suppress-node?name=node-a", "bad"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for invalid token, got %d", rec.Code)
+	}
+	if len(queue.suppressed) != 0 {
+		t.Fatalf("expected no action for unauthenticated requests")
+	}
+}
+
+func TestAdminAPIRequeueAll(t *testing.T) {
+	rec := doRequest(t, &fakeQueue{nodes: 3}, http.MethodPost, "/admin/requeue-all", testToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp RequeueResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Requeued != 3 {
+		t.Fatalf("expected 3 requeued nodes, got %d", resp.Requeued)
+	}
+
+	if rec := doRequest(t, &fakeQueue{}, http.MethodGet, "/admin/requeue-all", testToken); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", rec.Code)
+	}
+	notStarted := &fakeQueue{err: fmt.Errorf("list: %w", inventory.ErrQueueNotStarted)}
+	if rec := doRequest(t, notStarted, http.MethodPost, "/admin/requeue-all", testToken); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the controller starts, got %d", rec.Code)
+	}
+}
+
+func TestAdminAPISuppressNode(t *testing.T) {
+	queue := &fakeQueue{}
+	rec := doRequest(t, queue, http.MethodPost, "/admin/suppress-node?name=node-a&ttl=10m", testToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp SuppressResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Node != "node-a" || queue.suppressed["node-a"] != 10*time.Minute {
+		t.Fatalf("unexpected suppression %+v, %v", resp, queue.suppressed)
+	}
+
+	if rec := doRequest(t, queue, http.MethodPost, "/admin/suppress-node?name=node-b", testToken); rec.Code != http.StatusOK || queue.suppressed["node-b"] != defaultSuppressTTL {
+		t.Fatalf("expected the default TTL, got %d, %v", rec.Code, queue.suppressed["node-b"])
+	}
+
+	for _, target := range []string{
+		"/admin/suppress-node",
+		"/admin/suppress-node?name=node-c&ttl=soon",
+		"/admin/suppress-node?name=node-c&ttl=-1m",
+		"/admin/suppress-node?name=node-c&ttl=48h",
+	} {
+		if rec := doRequest(t, queue, http.MethodPost, target, testToken); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", target, rec.Code)
+		}
+	}
+	if _, ok := queue.suppressed["node-c"]; ok {
+		t.Fatalf("expected invalid requests to leave the node alone")
+	}
+}
+
+func TestValidateBindAddress(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:9444", "localhost:9444", "[::1]:9444"} {
+		if err := validateBindAddress(addr); err != nil {
+			t.Fatalf("expected %s to be accepted: %v", addr, err)
+		}
+	}
+	for _, addr := range []string{":9444", "0.0.0.0:9444", "10.0.0.1:9444", "127.0.0.1", "::1:9444"} {
+		if err := validateBindAddress(addr); err == nil {
+			t.Fatalf("expected %s to be rejected", addr)
+		}
+	}
+}
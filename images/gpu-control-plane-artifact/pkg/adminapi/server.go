@@ -0,0 +1,112 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
+)
+
+const (
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Server runs the admin API on its own loopback listener.
+type Server struct {
+	cfg     config.AdminAPIConfig
+	handler http.Handler
+	log     logr.Logger
+}
+
+// NewServer constructs a Server.
+func NewServer(log logr.Logger, cfg config.AdminAPIConfig, handler *Handler) *Server {
+	return &Server{cfg: cfg, handler: handler.Routes(), log: log}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; the work queue and the suppression list belong
+// to the leader's controllers, so the other replicas have nothing to administer.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.cfg.BindAddress,
+		Handler:           s.handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.log.Info("admin API started", "addr", s.cfg.BindAddress)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// validateBindAddress rejects listeners reachable from outside the pod.
+func validateBindAddress(addr string) error {
+	parsed, err := listenaddr.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("admin API bind address: %w", err)
+	}
+	host, _, _ := net.SplitHostPort(parsed)
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("admin API bind address %q is not a loopback address", addr)
+	}
+	return nil
+}
+
+// SetupServer registers the admin API with the manager. It is a no-op when no bind address is configured.
+func SetupServer(mgr manager.Manager, log logr.Logger, cfg config.AdminAPIConfig, queue *inventory.NodeQueue) error {
+	if cfg.BindAddress == "" {
+		return nil
+	}
+	if err := validateBindAddress(cfg.BindAddress); err != nil {
+		return err
+	}
+	if queue == nil {
+		return errors.New("admin API requires the inventory node queue")
+	}
+	handler := NewHandler(log, queue, NewTokenReviewAuthenticator(mgr.GetClient()))
+	return mgr.Add(NewServer(log, cfg, handler))
+}
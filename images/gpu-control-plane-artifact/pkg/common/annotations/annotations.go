@@ -17,4 +17,11 @@ package annotations
 const (
 	GPUDeviceAssignment        = "gpu.deckhouse.io/assignment"
 	ClusterGPUDeviceAssignment = "cluster.gpu.deckhouse.io/assignment"
+
+	// LastReconciledBy records the controller version that last wrote the object.
+	LastReconciledBy = "gpu.deckhouse.io/last-reconciled-by"
+	// SchemaVersion records the status schema version the object was last written with.
+	SchemaVersion = "gpu.deckhouse.io/schema-version"
+	// ControllerInstance records the control-plane installation (namespace/leader election ID) that manages the object.
+	ControllerInstance = "gpu.deckhouse.io/controller-instance"
 )
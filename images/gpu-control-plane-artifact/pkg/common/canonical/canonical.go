@@ -0,0 +1,90 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package canonical puts set-like status slices into a stable order, so the same hardware reported along
+// different code paths (label parsing, vendor enrichment, gfd-extender detection) always compares equal.
+package canonical
+
+import (
+	"slices"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// Strings returns the values sorted and deduplicated; an empty input yields nil.
+func Strings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := slices.Clone(values)
+	sort.Strings(out)
+	return slices.Compact(out)
+}
+
+// MIG sorts the supported profiles and the per-type capacities by name.
+func MIG(cfg *v1alpha1.GPUMIGConfig) {
+	cfg.ProfilesSupported = Strings(cfg.ProfilesSupported)
+	if len(cfg.Types) == 0 {
+		cfg.Types = nil
+		return
+	}
+	sort.SliceStable(cfg.Types, func(i, j int) bool {
+		return cfg.Types[i].Name < cfg.Types[j].Name
+	})
+}
+
+// Hardware canonicalizes the set-like fields of a device's hardware description.
+func Hardware(hw *v1alpha1.GPUDeviceHardware) {
+	hw.Precision = Strings(hw.Precision)
+	MIG(&hw.MIG)
+}
+
+// Devices orders devices by inventoryID, falling back to the name for devices without one yet.
+func Devices(devices []*v1alpha1.GPUDevice) {
+	sort.SliceStable(devices, func(i, j int) bool {
+		a, b := devices[i], devices[j]
+		if a.Status.InventoryID != b.Status.InventoryID {
+			return a.Status.InventoryID < b.Status.InventoryID
+		}
+		return a.Name < b.Name
+	})
+}
+
+// ConditionsEqual reports whether both lists hold the same conditions regardless of their order. The
+// transition time is ignored: it only moves together with the status, so a difference in it alone is
+// an artifact of a condition being rebuilt rather than a change worth writing.
+func ConditionsEqual(a, b []metav1.Condition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byType := make(map[string]metav1.Condition, len(a))
+	for _, cond := range a {
+		byType[cond.Type] = cond
+	}
+	for _, cond := range b {
+		other, ok := byType[cond.Type]
+		if !ok ||
+			other.Status != cond.Status ||
+			other.Reason != cond.Reason ||
+			other.Message != cond.Message ||
+			other.ObservedGeneration != cond.ObservedGeneration {
+			return false
+		}
+		delete(byType, cond.Type)
+	}
+	return len(byType) == 0
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canonical
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestStrings(t *testing.T) {
+	if got := Strings(nil); got != nil {
+		t.Fatalf("expected nil for empty input, got %v", got)
+	}
+	in := []string{"fp32", "bf16", "fp32", "fp16"}
+	if got, want := Strings(in), []string{"bf16", "fp16", "fp32"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Strings=%v, want %v", got, want)
+	}
+	if in[0] != "fp32" || in[1] != "bf16" {
+		t.Fatalf("input must not be modified, got %v", in)
+	}
+}
+
+func TestHardwareOrdersSetFields(t *testing.T) {
+	hw := v1alpha1.GPUDeviceHardware{
+		Precision: []string{"tf32", "bf16"},
+		MIG: v1alpha1.GPUMIGConfig{
+			ProfilesSupported: []string{"7g.80gb", "1g.10gb"},
+			Types: []v1alpha1.GPUMIGTypeCapacity{
+				{Name: "7g.80gb", Count: 1},
+				{Name: "1g.10gb", Count: 7},
+			},
+		},
+	}
+	Hardware(&hw)
+	if want := []string{"bf16", "tf32"}; !reflect.DeepEqual(hw.Precision, want) {
+		t.Fatalf("precision=%v, want %v", hw.Precision, want)
+	}
+	if want := []string{"1g.10gb", "7g.80gb"}; !reflect.DeepEqual(hw.MIG.ProfilesSupported, want) {
+		t.Fatalf("profiles=%v, want %v", hw.MIG.ProfilesSupported, want)
+	}
+	if hw.MIG.Types[0].Name != "1g.10gb" || hw.MIG.Types[0].Count != 7 || hw.MIG.Types[1].Name != "7g.80gb" {
+		t.Fatalf("expected MIG types ordered by name, got %+v", hw.MIG.Types)
+	}
+}
+
+func TestDevicesOrdersByInventoryID(t *testing.T) {
+	device := func(name, id string) *v1alpha1.GPUDevice {
+		dev := &v1alpha1.GPUDevice{}
+		dev.Name = name
+		dev.Status.InventoryID = id
+		return dev
+	}
+	devices := []*v1alpha1.GPUDevice{device("c", "node-1-0002"), device("b", ""), device("a", "node-1-0001"), device("a0", "")}
+	Devices(devices)
+
+	var got []string
+	for _, dev := range devices {
+		got = append(got, dev.Name)
+	}
+	if want := []string{"a0", "b", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order=%v, want %v", got, want)
+	}
+}
+
+func TestConditionsEqual(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1700000000, 0))
+	later := metav1.NewTime(now.Add(time.Minute))
+	ready := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ok", LastTransitionTime: now}
+	healthy := metav1.Condition{Type: "Healthy", Status: metav1.ConditionFalse, Reason: "Probe", Message: "timeout", LastTransitionTime: now}
+
+	restamped := ready
+	restamped.LastTransitionTime = later
+	flipped := ready
+	flipped.Status = metav1.ConditionFalse
+	observed := ready
+	observed.ObservedGeneration = 2
+
+	cases := []struct {
+		name string
+		a, b []metav1.Condition
+		want bool
+	}{
+		{name: "both empty", want: true},
+		{name: "nil and empty", a: nil, b: []metav1.Condition{}, want: true},
+		{name: "reordered", a: []metav1.Condition{ready, healthy}, b: []metav1.Condition{healthy, ready}, want: true},
+		{name: "transition time only", a: []metav1.Condition{ready}, b: []metav1.Condition{restamped}, want: true},
+		{name: "status changed", a: []metav1.Condition{ready}, b: []metav1.Condition{flipped}, want: false},
+		{name: "generation changed", a: []metav1.Condition{ready}, b: []metav1.Condition{observed}, want: false},
+		{name: "added", a: []metav1.Condition{ready}, b: []metav1.Condition{ready, healthy}, want: false},
+		{name: "duplicate type", a: []metav1.Condition{ready, ready}, b: []metav1.Condition{ready, healthy}, want: false},
+	}
+	for _, tc := range cases {
+		if got := ConditionsEqual(tc.a, tc.b); got != tc.want {
+			t.Errorf("%s: ConditionsEqual=%t, want %t", tc.name, got, tc.want)
+		}
+		if got := ConditionsEqual(tc.b, tc.a); got != tc.want {
+			t.Errorf("%s (swapped): ConditionsEqual=%t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
@@ -37,6 +37,8 @@ const (
 	ComponentValidator           Component = "validator"
 	ComponentDCGM                Component = "dcgm"
 	ComponentDCGMExporter        Component = "dcgm-exporter"
+	// ComponentGFDExtender is rendered by the controller itself rather than by the module templates.
+	ComponentGFDExtender Component = "gfd-extender"
 )
 
 var managedComponents = []Component{
@@ -44,6 +46,7 @@ var managedComponents = []Component{
 	ComponentValidator,
 	ComponentDCGM,
 	ComponentDCGMExporter,
+	ComponentGFDExtender,
 }
 
 // AppName returns the value stored in the pod label `app` for the component.
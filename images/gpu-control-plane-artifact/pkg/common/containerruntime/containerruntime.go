@@ -0,0 +1,115 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerruntime
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Name identifies a container runtime as the kubelet reports it.
+type Name string
+
+const (
+	Containerd Name = "containerd"
+	CRIO       Name = "cri-o"
+
+	// LabelKey carries the runtime name on GPU nodes when nodeLabeling is enabled.
+	LabelKey = "gpu.deckhouse.io/container-runtime"
+)
+
+// Runtime is the runtime of a node.
+type Runtime struct {
+	Name    Name
+	Version string
+}
+
+// Profile holds the runtime-specific host paths rendered into pool workloads.
+type Profile struct {
+	Runtime    Name
+	Socket     string
+	ConfigPath string
+	// HooksDir is the OCI hooks directory; empty when the runtime reads hooks from its config only.
+	HooksDir string
+}
+
+var profiles = map[Name]Profile{
+	Containerd: {
+		Runtime:    Containerd,
+		Socket:     "/run/containerd/containerd.sock",
+		ConfigPath: "/etc/containerd/config.toml",
+	},
+	CRIO: {
+		Runtime:    CRIO,
+		Socket:     "/var/run/crio/crio.sock",
+		ConfigPath: "/etc/crio/crio.conf",
+		HooksDir:   "/usr/share/containers/oci/hooks.d",
+	},
+}
+
+// Parse splits a node.status.nodeInfo.containerRuntimeVersion value such as "containerd://1.7.2";
+// an empty value yields the zero Runtime.
+func Parse(raw string) Runtime {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Runtime{}
+	}
+	name, version, _ := strings.Cut(raw, "://")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "crio" {
+		name = string(CRIO)
+	}
+	return Runtime{Name: Name(name), Version: strings.TrimSpace(version)}
+}
+
+// FromNode returns the runtime the kubelet of node reports.
+func FromNode(node *corev1.Node) Runtime {
+	if node == nil {
+		return Runtime{}
+	}
+	return Parse(node.Status.NodeInfo.ContainerRuntimeVersion)
+}
+
+// LabelValue returns the LabelKey value for the runtime, or "" when it has no valid one.
+func (r Runtime) LabelValue() string {
+	if r.Name == "" || len(validation.IsValidLabelValue(string(r.Name))) > 0 {
+		return ""
+	}
+	return string(r.Name)
+}
+
+// ProfileFor returns the profile of name. Runtimes without a profile get the containerd one, which
+// is what rendered workloads assumed before runtimes were detected.
+func ProfileFor(name Name) Profile {
+	if profile, ok := profiles[name]; ok {
+		return profile
+	}
+	return DefaultProfile()
+}
+
+// OrDefault returns p, or DefaultProfile when p is the zero Profile.
+func (p Profile) OrDefault() Profile {
+	if p.Runtime == "" {
+		return DefaultProfile()
+	}
+	return p
+}
+
+// DefaultProfile is the containerd profile.
+func DefaultProfile() Profile {
+	return profiles[Containerd]
+}
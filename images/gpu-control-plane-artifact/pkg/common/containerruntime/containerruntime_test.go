@@ -0,0 +1,70 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerruntime
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want Runtime
+	}{
+		{raw: "containerd://1.7.2", want: Runtime{Name: Containerd, Version: "1.7.2"}},
+		{raw: "cri-o://1.28.1", want: Runtime{Name: CRIO, Version: "1.28.1"}},
+		{raw: " CRIO://1.30.0 ", want: Runtime{Name: CRIO, Version: "1.30.0"}},
+		{raw: "docker://24.0.7", want: Runtime{Name: "docker", Version: "24.0.7"}},
+		{raw: "containerd", want: Runtime{Name: Containerd}},
+		{raw: "", want: Runtime{}},
+	} {
+		if got := Parse(tc.raw); got != tc.want {
+			t.Fatalf("Parse(%q) = %+v, want %+v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestFromNode(t *testing.T) {
+	if got := FromNode(nil); got != (Runtime{}) {
+		t.Fatalf("expected zero runtime for nil node, got %+v", got)
+	}
+	node := &corev1.Node{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{ContainerRuntimeVersion: "cri-o://1.28.1"}}}
+	if got := FromNode(node); got.Name != CRIO || got.Version != "1.28.1" {
+		t.Fatalf("unexpected runtime %+v", got)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	if got := Parse("cri-o://1.28.1").LabelValue(); got != "cri-o" {
+		t.Fatalf("expected cri-o, got %q", got)
+	}
+	if got := Parse("").LabelValue(); got != "" {
+		t.Fatalf("expected no value for unknown runtime, got %q", got)
+	}
+	if got := (Runtime{Name: "bad name"}).LabelValue(); got != "" {
+		t.Fatalf("expected invalid label value to be dropped, got %q", got)
+	}
+}
+
+func TestProfileFor(t *testing.T) {
+	if got := ProfileFor(CRIO); got.Socket != "/var/run/crio/crio.sock" || got.HooksDir == "" {
+		t.Fatalf("unexpected cri-o profile %+v", got)
+	}
+	if got := ProfileFor("docker"); got != DefaultProfile() || got.Runtime != Containerd {
+		t.Fatalf("expected unknown runtimes to fall back to containerd, got %+v", got)
+	}
+}
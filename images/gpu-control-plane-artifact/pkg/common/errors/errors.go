@@ -0,0 +1,53 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errors defines the error classes shared by the controllers. Services wrap the errors they
+// return with a class at the boundary where context is added, and the top-level Reconcile maps the
+// class onto a retry, a delayed requeue or a drop via PolicyFor.
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	// ErrNodeFeatureMissing means the node has no NodeFeature the controller trusts yet.
+	ErrNodeFeatureMissing = stderrors.New("node feature missing")
+	// ErrTelemetryUnavailable means gfd-extender could not be reached or returned unusable detections.
+	ErrTelemetryUnavailable = stderrors.New("telemetry unavailable")
+	// ErrConfigInvalid means the module settings cannot be applied; retrying does not help until they change.
+	ErrConfigInvalid = stderrors.New("configuration invalid")
+	// ErrConflictRetryable means a write lost an optimistic-concurrency race and can be retried right away.
+	ErrConflictRetryable = stderrors.New("retryable conflict")
+)
+
+// Wrap marks err with class while keeping err in the chain, so both errors.Is(err, class) and checks
+// against the original error keep working. A nil err stays nil and an already marked err is returned as is.
+func Wrap(class, err error) error {
+	if err == nil || stderrors.Is(err, class) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", class, err)
+}
+
+// WrapConflict marks API conflicts with ErrConflictRetryable and returns other errors unchanged.
+func WrapConflict(err error) error {
+	if apierrors.IsConflict(err) {
+		return Wrap(ErrConflictRetryable, err)
+	}
+	return err
+}
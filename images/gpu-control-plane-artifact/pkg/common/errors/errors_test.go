@@ -0,0 +1,107 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestWrapKeepsClassAndCause(t *testing.T) {
+	cause := stderrors.New("dial tcp: connection refused")
+	err := Wrap(ErrTelemetryUnavailable, cause)
+	if !stderrors.Is(err, ErrTelemetryUnavailable) || !stderrors.Is(err, cause) {
+		t.Fatalf("expected class and cause in the chain, got %v", err)
+	}
+	if again := Wrap(ErrTelemetryUnavailable, err); again != err {
+		t.Fatalf("expected marked error to be returned as is, got %v", again)
+	}
+	if Wrap(ErrTelemetryUnavailable, nil) != nil {
+		t.Fatalf("expected nil error to stay nil")
+	}
+}
+
+func TestWrapConflict(t *testing.T) {
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "gpudevices"}, "gpu-0", stderrors.New("modified"))
+	err := WrapConflict(conflict)
+	if !stderrors.Is(err, ErrConflictRetryable) || !apierrors.IsConflict(err) {
+		t.Fatalf("expected conflict marked retryable, got %v", err)
+	}
+	other := stderrors.New("boom")
+	if WrapConflict(other) != other {
+		t.Fatalf("expected non-conflict error unchanged")
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		action Action
+		after  time.Duration
+		reason string
+	}{
+		{name: "node feature missing", err: fmt.Errorf("node worker-1: %w", ErrNodeFeatureMissing), action: ActionDrop},
+		{name: "telemetry unavailable", err: Wrap(ErrTelemetryUnavailable, stderrors.New("timeout")), action: ActionRequeueAfter, after: TelemetryRequeueAfter},
+		{name: "config invalid", err: Wrap(ErrConfigInvalid, stderrors.New("bad selector")), action: ActionDrop, reason: EventReasonConfigInvalid},
+		{name: "conflict retryable", err: Wrap(ErrConflictRetryable, stderrors.New("stale")), action: ActionRequeueAfter, after: ConflictRequeueAfter},
+		{name: "unmarked api conflict", err: apierrors.NewConflict(schema.GroupResource{Resource: "nodes"}, "n", stderrors.New("x")), action: ActionRequeueAfter, after: ConflictRequeueAfter},
+		{name: "joined", err: stderrors.Join(stderrors.New("other"), ErrNodeFeatureMissing), action: ActionDrop},
+		{name: "unknown", err: stderrors.New("boom"), action: ActionRetry},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := PolicyFor(tc.err)
+			if policy.Action != tc.action || policy.RequeueAfter != tc.after || policy.EventReason != tc.reason {
+				t.Fatalf("unexpected policy %+v", policy)
+			}
+			if Classified(tc.err) != (tc.action != ActionRetry) {
+				t.Fatalf("unexpected Classified for %v", tc.err)
+			}
+		})
+	}
+	if Classified(nil) {
+		t.Fatalf("nil error must not be classified")
+	}
+}
+
+func TestResult(t *testing.T) {
+	res, err := Result(reconcile.Result{RequeueAfter: time.Minute}, Wrap(ErrTelemetryUnavailable, stderrors.New("timeout")))
+	if err != nil || res.RequeueAfter != TelemetryRequeueAfter {
+		t.Fatalf("expected telemetry requeue, got %+v %v", res, err)
+	}
+	res, err = Result(reconcile.Result{RequeueAfter: time.Second}, Wrap(ErrTelemetryUnavailable, stderrors.New("timeout")))
+	if err != nil || res.RequeueAfter != time.Second {
+		t.Fatalf("expected the sooner requeue to win, got %+v %v", res, err)
+	}
+	res, err = Result(reconcile.Result{RequeueAfter: time.Minute}, ErrConfigInvalid)
+	if err != nil || res != (reconcile.Result{}) {
+		t.Fatalf("expected config error to be dropped, got %+v %v", res, err)
+	}
+	boom := stderrors.New("boom")
+	if _, err = Result(reconcile.Result{}, boom); err != boom {
+		t.Fatalf("expected unknown error returned, got %v", err)
+	}
+	res, err = Result(reconcile.Result{Requeue: true}, nil)
+	if err != nil || !res.Requeue {
+		t.Fatalf("expected result unchanged without error, got %+v %v", res, err)
+	}
+}
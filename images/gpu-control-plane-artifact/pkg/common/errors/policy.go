@@ -0,0 +1,103 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	stderrors "errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Action is what the top-level Reconcile does with an error of a known class.
+type Action string
+
+const (
+	// ActionRetry returns the error, so the workqueue retries with its rate-limited backoff.
+	ActionRetry Action = "Retry"
+	// ActionRequeueAfter swallows the error and requeues after Policy.RequeueAfter.
+	ActionRequeueAfter Action = "RequeueAfter"
+	// ActionDrop swallows the error without a requeue: a watch event brings the object back once the
+	// cause is gone. A non-empty Policy.EventReason asks the caller to report the drop as a Warning event.
+	ActionDrop Action = "Drop"
+)
+
+const (
+	ConflictRequeueAfter  = 100 * time.Millisecond
+	TelemetryRequeueAfter = 30 * time.Second
+
+	// EventReasonConfigInvalid is reported when a reconcile is dropped because of invalid module settings.
+	EventReasonConfigInvalid = "GPUConfigInvalid"
+)
+
+// Policy describes how an error class is handled.
+type Policy struct {
+	Action       Action
+	RequeueAfter time.Duration
+	EventReason  string
+}
+
+// policies is the mapping table; the first class found in the error chain wins.
+var policies = []struct {
+	class  error
+	policy Policy
+}{
+	{class: ErrConflictRetryable, policy: Policy{Action: ActionRequeueAfter, RequeueAfter: ConflictRequeueAfter}},
+	{class: ErrTelemetryUnavailable, policy: Policy{Action: ActionRequeueAfter, RequeueAfter: TelemetryRequeueAfter}},
+	// The NodeFeature watch enqueues the node as soon as NFD publishes it.
+	{class: ErrNodeFeatureMissing, policy: Policy{Action: ActionDrop}},
+	// The ModuleConfig watch enqueues everything again once the settings are fixed.
+	{class: ErrConfigInvalid, policy: Policy{Action: ActionDrop, EventReason: EventReasonConfigInvalid}},
+}
+
+// PolicyFor returns the policy of the error class err belongs to. Unmarked API conflicts count as
+// ErrConflictRetryable, and errors of no known class are retried.
+func PolicyFor(err error) Policy {
+	if err == nil {
+		return Policy{}
+	}
+	for _, entry := range policies {
+		if stderrors.Is(err, entry.class) {
+			return entry.policy
+		}
+	}
+	if apierrors.IsConflict(err) {
+		return policies[0].policy
+	}
+	return Policy{Action: ActionRetry}
+}
+
+// Classified reports whether err belongs to a class that is not plainly retried.
+func Classified(err error) bool {
+	return err != nil && PolicyFor(err).Action != ActionRetry
+}
+
+// Result applies the policy of err to res: retried errors are returned unchanged, requeued ones keep the sooner of their
+// RequeueAfter and the one already in res, and dropped ones become an empty result.
+func Result(res reconcile.Result, err error) (reconcile.Result, error) {
+	policy := PolicyFor(err)
+	switch policy.Action {
+	case ActionRequeueAfter:
+		if res.RequeueAfter == 0 || policy.RequeueAfter < res.RequeueAfter {
+			res.RequeueAfter = policy.RequeueAfter
+		}
+		return res, nil
+	case ActionDrop:
+		return reconcile.Result{}, nil
+	default:
+		return res, err
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpcall runs the controllers' outbound HTTP calls under a deadline of their own, so a stuck remote
+// cannot use up the reconcile budget, and tells a call that ran out of time from one whose reconcile ended first.
+package httpcall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	httpmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/httpcall"
+)
+
+// Category groups the calls that share a deadline.
+type Category string
+
+const (
+	CategoryTelemetry  Category = "telemetry"
+	CategoryDetection  Category = "detection"
+	CategoryValidation Category = "validation"
+	CategoryRegistry   Category = "registry"
+)
+
+// Outcome classifies a finished call in logs and metrics.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeError   Outcome = "error"
+	// OutcomeTimeout means the call's own deadline fired while the caller's context was still live.
+	OutcomeTimeout Outcome = "timeout"
+	// OutcomeCanceled means the caller's context ended before the call finished.
+	OutcomeCanceled Outcome = "canceled"
+)
+
+// DefaultTimeout applies to a Caller built without a timeout.
+const DefaultTimeout = 5 * time.Second
+
+// ErrDeadlineExceeded marks calls stopped by their own deadline.
+var ErrDeadlineExceeded = errors.New("call deadline exceeded")
+
+// sharedTransport keeps at most one idle connection per pod and drops it quickly: pod IPs are reused after a
+// restart, and a kept-alive connection to a dead pod would only surface as a timeout.
+var sharedTransport = &http.Transport{
+	DialContext:         (&net.Dialer{Timeout: time.Second, KeepAlive: 15 * time.Second}).DialContext,
+	MaxIdleConns:        64,
+	MaxIdleConnsPerHost: 1,
+	MaxConnsPerHost:     4,
+	IdleConnTimeout:     30 * time.Second,
+}
+
+var sharedClient = &http.Client{Transport: sharedTransport}
+
+// Caller runs the calls of one category.
+type Caller struct {
+	category Category
+	timeout  time.Duration
+	client   *http.Client
+}
+
+// New returns a Caller using the shared client; a non-positive timeout falls back to DefaultTimeout.
+func New(category Category, timeout time.Duration) *Caller {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Caller{category: category, timeout: timeout, client: sharedClient}
+}
+
+// WithClient returns a copy of c sending through client.
+func (c *Caller) WithClient(client *http.Client) *Caller {
+	cp := *c
+	cp.client = client
+	return &cp
+}
+
+// Timeout is the deadline of every call.
+func (c *Caller) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Do sends req under a deadline derived from ctx and hands the response to handle before the deadline is released,
+// so the body is read under it too; the body is closed afterwards. A call stopped by its own deadline returns an error
+// wrapping ErrDeadlineExceeded; one stopped by ctx returns ctx's error.
+func (c *Caller) Do(ctx context.Context, req *http.Request, handle func(*http.Response) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.client.Do(req.WithContext(callCtx))
+	if err == nil {
+		err = handle(resp)
+		_ = resp.Body.Close()
+	}
+
+	outcome := classify(ctx, callCtx, err)
+	httpmetrics.CallInc(string(c.category), string(outcome))
+	if outcome != OutcomeSuccess {
+		logr.FromContextOrDiscard(ctx).V(1).Info("outbound HTTP call failed",
+			"category", c.category, "outcome", outcome, "url", req.URL.Redacted(), "timeout", c.timeout, "error", err.Error())
+	}
+
+	switch outcome {
+	case OutcomeTimeout:
+		if sharedTransport == c.client.Transport {
+			// The remote stopped answering; drop idle connections so none to it is reused.
+			sharedTransport.CloseIdleConnections()
+		}
+		return fmt.Errorf("%s call to %s: %w after %s", c.category, req.URL.Redacted(), ErrDeadlineExceeded, c.timeout)
+	case OutcomeCanceled:
+		return fmt.Errorf("%s call to %s: %w", c.category, req.URL.Redacted(), ctx.Err())
+	}
+	return err
+}
+
+// classify looks at the contexts rather than at err, since a deadline that fires while the body is read surfaces as
+// whatever error the reader returns.
+func classify(parent, call context.Context, err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case parent.Err() != nil:
+		return OutcomeCanceled
+	case errors.Is(call.Err(), context.DeadlineExceeded):
+		return OutcomeTimeout
+	default:
+		return OutcomeError
+	}
+}
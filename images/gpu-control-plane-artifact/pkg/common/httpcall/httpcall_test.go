@@ -0,0 +1,169 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcall
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// slowServer answers after headerDelay and writes its body bodyDelay later; release unblocks pending handlers.
+func slowServer(t *testing.T, headerDelay, bodyDelay time.Duration) *httptest.Server {
+	t.Helper()
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wait := func(d time.Duration) bool {
+			select {
+			case <-time.After(d):
+				return true
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return false
+		}
+		if !wait(headerDelay) {
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		if !wait(bodyDelay) {
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+	return server
+}
+
+func get(t *testing.T, ctx context.Context, caller *Caller, url string) (string, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	var body string
+	err = caller.Do(ctx, req, func(resp *http.Response) error {
+		data, err := io.ReadAll(resp.Body)
+		body = string(data)
+		return err
+	})
+	return body, err
+}
+
+func TestDoSucceeds(t *testing.T) {
+	server := slowServer(t, 0, 0)
+	body, err := get(t, context.Background(), New(CategoryDetection, time.Second), server.URL)
+	if err != nil || body != "ok" {
+		t.Fatalf("expected body ok, got %q (err=%v)", body, err)
+	}
+}
+
+func TestDoChildDeadlineFiresWhileParentIsLive(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		headerDelay, bodyDelay time.Duration
+	}{
+		{name: "slow headers", headerDelay: time.Minute},
+		{name: "slow body", bodyDelay: time.Minute},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := slowServer(t, tc.headerDelay, tc.bodyDelay)
+			parent, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			_, err := get(t, parent, New(CategoryTelemetry, 50*time.Millisecond), server.URL)
+			if !errors.Is(err, ErrDeadlineExceeded) {
+				t.Fatalf("expected ErrDeadlineExceeded, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("expected the call deadline to stop the call early, took %s", elapsed)
+			}
+			if parent.Err() != nil {
+				t.Fatalf("expected the parent context to outlive the call, got %v", parent.Err())
+			}
+
+			// The reconcile goes on and can still make calls that fit their deadline.
+			fast := slowServer(t, 0, 0)
+			if body, err := get(t, parent, New(CategoryTelemetry, time.Second), fast.URL); err != nil || body != "ok" {
+				t.Fatalf("expected a later call to succeed, got %q (err=%v)", body, err)
+			}
+		})
+	}
+}
+
+func TestDoParentCancellationIsNotATimeout(t *testing.T) {
+	server := slowServer(t, time.Minute, 0)
+	parent, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := get(t, parent, New(CategoryValidation, 10*time.Second), server.URL)
+	if errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("parent expiry must not be reported as the call deadline, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the parent's error, got %v", err)
+	}
+}
+
+func TestDoPassesHandlerErrors(t *testing.T) {
+	server := slowServer(t, 0, 0)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	boom := errors.New("boom")
+	if err := New(CategoryDetection, time.Second).Do(context.Background(), req, func(*http.Response) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	live := context.Background()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	boom := errors.New("boom")
+
+	cases := []struct {
+		name         string
+		parent, call context.Context
+		err          error
+		want         Outcome
+	}{
+		{name: "success", parent: live, call: live, want: OutcomeSuccess},
+		{name: "error", parent: live, call: live, err: boom, want: OutcomeError},
+		{name: "child deadline", parent: live, call: expired, err: boom, want: OutcomeTimeout},
+		{name: "parent canceled", parent: canceled, call: canceled, err: boom, want: OutcomeCanceled},
+		{name: "parent expired", parent: expired, call: expired, err: boom, want: OutcomeCanceled},
+	}
+	for _, tc := range cases {
+		if got := classify(tc.parent, tc.call, tc.err); got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestNewDefaultsTimeout(t *testing.T) {
+	if got := New(CategoryDetection, 0).Timeout(); got != DefaultTimeout {
+		t.Fatalf("expected %s, got %s", DefaultTimeout, got)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagedigest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// credential is the registry login taken from the pull secret; the zero value pulls anonymously.
+type credential struct {
+	username string
+	password string
+}
+
+func (c credential) empty() bool {
+	return c.username == "" && c.password == ""
+}
+
+type dockerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// credentialFor reads pullSecret and returns its login for registry. Without a configured secret, and for
+// registries the secret has no entry for, the lookup is anonymous.
+func (r *Resolver) credentialFor(ctx context.Context, pullSecret types.NamespacedName, registry string) (credential, error) {
+	if pullSecret.Name == "" {
+		return credential{}, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.reader.Get(ctx, pullSecret, secret); err != nil {
+		return credential{}, fmt.Errorf("read pull secret %s: %w", pullSecret, err)
+	}
+
+	auths := map[string]dockerAuth{}
+	switch {
+	case len(secret.Data[corev1.DockerConfigJsonKey]) > 0:
+		var config struct {
+			Auths map[string]dockerAuth `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return credential{}, fmt.Errorf("decode pull secret %s: %w", pullSecret, err)
+		}
+		auths = config.Auths
+	case len(secret.Data[corev1.DockerConfigKey]) > 0:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return credential{}, fmt.Errorf("decode pull secret %s: %w", pullSecret, err)
+		}
+	}
+
+	for server, auth := range auths {
+		if normalizeRegistry(server) != normalizeRegistry(registry) {
+			continue
+		}
+		if auth.Username == "" && auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return credential{}, fmt.Errorf("decode pull secret %s auth of %s: %w", pullSecret, server, err)
+			}
+			auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		return credential{username: auth.Username, password: auth.Password}, nil
+	}
+	return credential{}, nil
+}
+
+// normalizeRegistry reduces a docker config server key (https://host/v1/, host) to its host and maps the docker.io
+// aliases onto one name.
+func normalizeRegistry(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(server), "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", dockerHubAPIHost:
+		return dockerHubRegistry
+	}
+	return host
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagedigest
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubRegistry = "docker.io"
+	// dockerHubAPIHost serves the registry API of docker.io references.
+	dockerHubAPIHost = "registry-1.docker.io"
+)
+
+// Reference is an image reference split into the parts the registry API addresses.
+type Reference struct {
+	// Registry is the registry host, docker.io for references without one.
+	Registry   string
+	Repository string
+	// Tag is latest for references with neither a tag nor a digest.
+	Tag    string
+	Digest string
+}
+
+// ParseReference splits image the way the container runtimes do: the first path component is the registry only
+// when it looks like a host, and single-component docker.io names live under library/.
+func ParseReference(image string) (Reference, error) {
+	var ref Reference
+	name := strings.TrimSpace(image)
+	if before, digest, found := strings.Cut(name, "@"); found {
+		if algorithm, hex, ok := strings.Cut(digest, ":"); !ok || algorithm == "" || hex == "" {
+			return ref, fmt.Errorf("image %q has a malformed digest", image)
+		}
+		name, ref.Digest = before, digest
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	switch {
+	case found && (strings.ContainsAny(first, ".:") || first == "localhost"):
+		ref.Registry, ref.Repository = first, rest
+	case found:
+		ref.Registry, ref.Repository = dockerHubRegistry, name
+	default:
+		ref.Registry, ref.Repository = dockerHubRegistry, "library/"+name
+	}
+	if ref.Repository == "" || ref.Repository == "library/" {
+		return ref, fmt.Errorf("image %q has no repository", image)
+	}
+	return ref, nil
+}
+
+// apiHost is the host serving the registry API of the reference.
+func (r Reference) apiHost() string {
+	if r.Registry == dockerHubRegistry {
+		return dockerHubAPIHost
+	}
+	return r.Registry
+}
+
+// pin appends digest to image, keeping the tag so the rendered reference stays readable; the runtimes pull by the
+// digest alone.
+func pin(image, digest string) string {
+	return strings.TrimSpace(image) + "@" + digest
+}
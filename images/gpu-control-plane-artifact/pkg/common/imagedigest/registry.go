@@ -0,0 +1,177 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagedigest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const digestHeader = "Docker-Content-Digest"
+
+// manifestMediaTypes are accepted from the manifests endpoint. The indexes come first so a multi-arch tag resolves
+// to the digest of its index, the one every node architecture can pull.
+var manifestMediaTypes = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+var challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchDigest asks the registry API for the manifest digest of the tag, answering one authentication challenge.
+func (r *Resolver) fetchDigest(ctx context.Context, ref Reference, cred credential) (string, error) {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.apiHost(), ref.Repository, ref.Tag)
+	digest, challenge, err := r.requestManifest(ctx, manifestURL, "")
+	if err != nil || challenge == "" {
+		return digest, err
+	}
+	authorization, err := r.authorize(ctx, challenge, ref, cred)
+	if err != nil {
+		return "", err
+	}
+	digest, challenge, err = r.requestManifest(ctx, manifestURL, authorization)
+	if err == nil && challenge != "" {
+		return "", fmt.Errorf("registry %s rejected the credentials for %s", ref.Registry, ref.Repository)
+	}
+	return digest, err
+}
+
+// requestManifest sends HEAD to the manifest and falls back to GET, hashing the body, for registries that leave the
+// digest header out of HEAD responses. challenge is the WWW-Authenticate header of a 401 answer.
+func (r *Resolver) requestManifest(ctx context.Context, manifestURL, authorization string) (digest, challenge string, err error) {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, manifestURL, nil)
+		if err != nil {
+			return "", "", err
+		}
+		req.Header.Set("Accept", manifestMediaTypes)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		err = r.caller.Do(ctx, req, func(resp *http.Response) error {
+			switch resp.StatusCode {
+			case http.StatusOK:
+			case http.StatusUnauthorized:
+				if challenge = resp.Header.Get("WWW-Authenticate"); challenge == "" {
+					return fmt.Errorf("manifest %s: unauthorized without a challenge", manifestURL)
+				}
+				return nil
+			default:
+				return fmt.Errorf("manifest %s: unexpected status %s", manifestURL, resp.Status)
+			}
+			if digest = resp.Header.Get(digestHeader); digest != "" || method == http.MethodHead {
+				return nil
+			}
+			hash := sha256.New()
+			if _, err := io.Copy(hash, resp.Body); err != nil {
+				return fmt.Errorf("read manifest %s: %w", manifestURL, err)
+			}
+			digest = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+			return nil
+		})
+		if err != nil || challenge != "" || digest != "" {
+			return digest, challenge, err
+		}
+	}
+	return "", "", fmt.Errorf("manifest %s: registry returned no digest", manifestURL)
+}
+
+// authorize turns a Basic or Bearer challenge into the Authorization header of the retried request.
+func (r *Resolver) authorize(ctx context.Context, challenge string, ref Reference, cred credential) (string, error) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if cred.empty() {
+			return "", fmt.Errorf("registry %s requires credentials", ref.Registry)
+		}
+		return "Basic " + basicAuth(cred), nil
+	case "bearer":
+		token, err := r.fetchToken(ctx, params, ref, cred)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("registry %s: unsupported authentication scheme %q", ref.Registry, scheme)
+	}
+}
+
+// fetchToken gets a pull token for the repository from the realm of a Bearer challenge.
+func (r *Resolver) fetchToken(ctx context.Context, params map[string]string, ref Reference, cred credential) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry %s: bearer challenge has no usable realm %q", ref.Registry, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if !cred.empty() {
+		req.Header.Set("Authorization", "Basic "+basicAuth(cred))
+	}
+	var token string
+	err = r.caller.Do(ctx, req, func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("token %s: unexpected status %s", realm.Redacted(), resp.Status)
+		}
+		var payload struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return fmt.Errorf("decode token %s: %w", realm.Redacted(), err)
+		}
+		token = payload.Token
+		if token == "" {
+			token = payload.AccessToken
+		}
+		if token == "" {
+			return fmt.Errorf("token %s: empty token", realm.Redacted())
+		}
+		return nil
+	})
+	return token, err
+}
+
+func basicAuth(cred credential) string {
+	return base64.StdEncoding.EncodeToString([]byte(cred.username + ":" + cred.password))
+}
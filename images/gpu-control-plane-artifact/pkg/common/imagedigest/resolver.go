@@ -0,0 +1,190 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imagedigest resolves image tags to manifest digests through the registry API, so rendered workloads can
+// reference their images by digest. Resolved digests are cached per image for a TTL; when the registry cannot be
+// reached the caller decides whether to fall back to the tag.
+package imagedigest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
+)
+
+const (
+	// DefaultCacheTTL applies to a Resolver built without a TTL.
+	DefaultCacheTTL = time.Hour
+	// RetryInterval is how long a failed resolution is remembered before the registry is asked again, so an
+	// air-gapped cluster does not pay a registry timeout on every reconcile.
+	RetryInterval = time.Minute
+	// callTimeout bounds every registry and token request.
+	callTimeout = 10 * time.Second
+)
+
+// ErrUnresolved marks images that could not be resolved and have no earlier digest to fall back to.
+var ErrUnresolved = errors.New("image digest is not resolved")
+
+// Options configures a Resolver.
+type Options struct {
+	// PullSecret is the dockerconfigjson Secret holding the registry logins; an empty name resolves anonymously.
+	PullSecret types.NamespacedName
+	// CacheTTL is how long a resolved digest is used before the tag is resolved again.
+	CacheTTL time.Duration
+	// RequireDigests asks the callers to stop rendering images that cannot be resolved instead of using their tags.
+	RequireDigests bool
+}
+
+type cacheEntry struct {
+	digest     string
+	resolvedAt time.Time
+	err        error
+	failedAt   time.Time
+}
+
+// Resolver resolves and caches image digests. It is safe for concurrent use.
+type Resolver struct {
+	reader client.Reader
+	opts   Options
+	caller *httpcall.Caller
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewResolver returns a Resolver reading the pull secret through reader.
+func NewResolver(reader client.Reader, opts Options) *Resolver {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	return &Resolver{
+		reader: reader,
+		opts:   opts.withDefaults(),
+		caller: httpcall.New(httpcall.CategoryRegistry, callTimeout).WithClient(&http.Client{Transport: transport}),
+		now:    time.Now,
+		cache:  map[string]cacheEntry{},
+	}
+}
+
+func (o Options) withDefaults() Options {
+	if o.CacheTTL <= 0 {
+		o.CacheTTL = DefaultCacheTTL
+	}
+	return o
+}
+
+// Configure replaces the options of r while keeping the resolved digests. A changed pull secret forgets the
+// remembered failures, so the new login is tried on the next Resolve instead of after RetryInterval.
+func (r *Resolver) Configure(opts Options) {
+	opts = opts.withDefaults()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if opts.PullSecret != r.opts.PullSecret {
+		for image, entry := range r.cache {
+			if entry.digest == "" {
+				delete(r.cache, image)
+				continue
+			}
+			entry.err, entry.failedAt = nil, time.Time{}
+			r.cache[image] = entry
+		}
+	}
+	r.opts = opts
+}
+
+func (r *Resolver) options() Options {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.opts
+}
+
+// WithHTTPClient makes r talk to the registries through client, for registries with private CAs and for tests.
+func (r *Resolver) WithHTTPClient(client *http.Client) *Resolver {
+	r.caller = r.caller.WithClient(client)
+	return r
+}
+
+// RequireDigests reports whether images that cannot be resolved must not be rendered.
+func (r *Resolver) RequireDigests() bool {
+	return r.options().RequireDigests
+}
+
+// CacheTTL is how long a resolved digest is used; callers requeue after it so a moved tag is picked up.
+func (r *Resolver) CacheTTL() time.Duration {
+	return r.options().CacheTTL
+}
+
+// Resolve returns image pinned to the digest its tag points at. Images already carrying a digest are returned as
+// they are. When the registry cannot be asked, a digest resolved earlier is kept past its TTL, so an outage neither
+// rolls the workloads back to the tag nor blocks them; without one, image is returned with an error wrapping
+// ErrUnresolved.
+func (r *Resolver) Resolve(ctx context.Context, image string) (string, error) {
+	if image == "" {
+		return image, nil
+	}
+	ref, err := ParseReference(image)
+	if err != nil {
+		return image, err
+	}
+	if ref.Digest != "" {
+		return image, nil
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	entry, cached := r.cache[image]
+	opts := r.opts
+	r.mu.Unlock()
+	if cached && entry.digest != "" && now.Before(entry.resolvedAt.Add(opts.CacheTTL)) {
+		return pin(image, entry.digest), nil
+	}
+	if cached && entry.err != nil && now.Before(entry.failedAt.Add(RetryInterval)) {
+		return fallback(image, entry)
+	}
+
+	digest, err := r.lookup(ctx, ref, opts.PullSecret)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry = r.cache[image]
+	if err != nil {
+		entry.err, entry.failedAt = err, now
+		r.cache[image] = entry
+		return fallback(image, entry)
+	}
+	r.cache[image] = cacheEntry{digest: digest, resolvedAt: now}
+	return pin(image, digest), nil
+}
+
+func (r *Resolver) lookup(ctx context.Context, ref Reference, pullSecret types.NamespacedName) (string, error) {
+	cred, err := r.credentialFor(ctx, pullSecret, ref.Registry)
+	if err != nil {
+		return "", err
+	}
+	return r.fetchDigest(ctx, ref, cred)
+}
+
+func fallback(image string, entry cacheEntry) (string, error) {
+	if entry.digest != "" {
+		return pin(image, entry.digest), nil
+	}
+	return image, fmt.Errorf("%w for %s: %v", ErrUnresolved, image, entry.err)
+}
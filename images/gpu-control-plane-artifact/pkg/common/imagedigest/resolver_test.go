@@ -0,0 +1,306 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagedigest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeRegistry serves manifest digests behind a bearer token flow like docker.io and most registries do.
+type fakeRegistry struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	digests  map[string]string
+	requests int
+	down     bool
+	// omitHeadDigest answers HEAD without the digest header.
+	omitHeadDigest bool
+	// login, when set, is the only basic auth the token endpoint accepts.
+	login string
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	t.Helper()
+	reg := &fakeRegistry{digests: map[string]string{}}
+	reg.server = httptest.NewTLSServer(http.HandlerFunc(reg.serve))
+	t.Cleanup(reg.server.Close)
+	return reg
+}
+
+func (f *fakeRegistry) host() string {
+	return strings.TrimPrefix(f.server.URL, "https://")
+}
+
+func (f *fakeRegistry) set(repoTag, digest string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.digests[repoTag] = digest
+}
+
+func (f *fakeRegistry) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *fakeRegistry) manifestRequests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func (f *fakeRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.URL.Path == "/token" {
+		if f.login != "" {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user+":"+pass != f.login {
+				http.Error(w, "denied", http.StatusUnauthorized)
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "tok-" + r.URL.Query().Get("scope")})
+		return
+	}
+
+	repo, tag, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	f.requests++
+	if r.Header.Get("Authorization") != "Bearer tok-repository:"+repo+":pull" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, f.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+		http.Error(w, "missing accept", http.StatusBadRequest)
+		return
+	}
+	digest, found := f.digests[repo+":"+tag]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method == http.MethodHead && f.omitHeadDigest {
+		return
+	}
+	w.Header().Set(digestHeader, digest)
+}
+
+func newTestResolver(t *testing.T, reg *fakeRegistry, opts Options, objs ...runtime.Object) (*Resolver, *time.Time) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewResolver(reader, opts).WithHTTPClient(reg.server.Client())
+	r.now = func() time.Time { return now }
+	return r, &now
+}
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		image string
+		want  Reference
+	}{
+		{image: "nginx", want: Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{image: "nvidia/k8s-device-plugin:v0.17.0", want: Reference{Registry: "docker.io", Repository: "nvidia/k8s-device-plugin", Tag: "v0.17.0"}},
+		{image: "registry.example.com:5000/gpu/dp:v1", want: Reference{Registry: "registry.example.com:5000", Repository: "gpu/dp", Tag: "v1"}},
+		{image: "localhost/dp", want: Reference{Registry: "localhost", Repository: "dp", Tag: "latest"}},
+		{image: "nvcr.io/nvidia/dp:v1@sha256:abc", want: Reference{Registry: "nvcr.io", Repository: "nvidia/dp", Tag: "v1", Digest: "sha256:abc"}},
+		{image: "nvcr.io/nvidia/dp@sha256:abc", want: Reference{Registry: "nvcr.io", Repository: "nvidia/dp", Digest: "sha256:abc"}},
+	}
+	for _, tc := range cases {
+		got, err := ParseReference(tc.image)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseReference(%q) = %+v, %v; want %+v", tc.image, got, err, tc.want)
+		}
+	}
+	for _, image := range []string{"dp@sha256", "dp@:abc", "registry.example.com/"} {
+		if _, err := ParseReference(image); err == nil {
+			t.Errorf("ParseReference(%q) must fail", image)
+		}
+	}
+}
+
+func TestResolvePinsAndCaches(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.set("gpu/dp:v1", "sha256:1111")
+	r, now := newTestResolver(t, reg, Options{CacheTTL: time.Hour})
+	image := reg.host() + "/gpu/dp:v1"
+
+	got, err := r.Resolve(context.Background(), image)
+	if err != nil || got != image+"@sha256:1111" {
+		t.Fatalf("Resolve() = %q, %v", got, err)
+	}
+	afterFirst := reg.manifestRequests()
+
+	reg.set("gpu/dp:v1", "sha256:2222")
+	*now = now.Add(30 * time.Minute)
+	if got, _ := r.Resolve(context.Background(), image); got != image+"@sha256:1111" {
+		t.Fatalf("expected the cached digest within the TTL, got %q", got)
+	}
+	if reg.manifestRequests() != afterFirst {
+		t.Fatalf("cached digest must not hit the registry")
+	}
+
+	*now = now.Add(time.Hour)
+	if got, _ := r.Resolve(context.Background(), image); got != image+"@sha256:2222" {
+		t.Fatalf("expected the moved tag after the TTL, got %q", got)
+	}
+
+	reg.set("gpu/dp:v2", "sha256:3333")
+	if got, _ := r.Resolve(context.Background(), reg.host()+"/gpu/dp:v2"); got != reg.host()+"/gpu/dp:v2@sha256:3333" {
+		t.Fatalf("expected a changed tag to resolve at once, got %q", got)
+	}
+
+	pinned := reg.host() + "/gpu/dp:v1@sha256:9999"
+	if got, err := r.Resolve(context.Background(), pinned); err != nil || got != pinned {
+		t.Fatalf("digest references must pass through, got %q, %v", got, err)
+	}
+}
+
+func TestResolveHashesManifestWithoutDigestHeader(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.omitHeadDigest = true
+	reg.set("gpu/dp:v1", "sha256:1111")
+	r, _ := newTestResolver(t, reg, Options{})
+
+	got, err := r.Resolve(context.Background(), reg.host()+"/gpu/dp:v1")
+	if err != nil || !strings.HasSuffix(got, "@sha256:1111") {
+		t.Fatalf("expected the GET digest header, got %q, %v", got, err)
+	}
+}
+
+func TestResolveFailureFallsBack(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.set("gpu/dp:v1", "sha256:1111")
+	reg.setDown(true)
+	r, now := newTestResolver(t, reg, Options{CacheTTL: time.Hour})
+	image := reg.host() + "/gpu/dp:v1"
+
+	got, err := r.Resolve(context.Background(), image)
+	if !errors.Is(err, ErrUnresolved) || got != image {
+		t.Fatalf("expected the tag with ErrUnresolved, got %q, %v", got, err)
+	}
+	requests := reg.manifestRequests()
+	if _, err := r.Resolve(context.Background(), image); !errors.Is(err, ErrUnresolved) {
+		t.Fatalf("expected the remembered failure, got %v", err)
+	}
+	if reg.manifestRequests() != requests {
+		t.Fatalf("failure must not be retried before RetryInterval")
+	}
+
+	reg.setDown(false)
+	*now = now.Add(RetryInterval)
+	if got, err := r.Resolve(context.Background(), image); err != nil || got != image+"@sha256:1111" {
+		t.Fatalf("expected resolution after the retry interval, got %q, %v", got, err)
+	}
+
+	reg.setDown(true)
+	*now = now.Add(2 * time.Hour)
+	if got, err := r.Resolve(context.Background(), image); err != nil || got != image+"@sha256:1111" {
+		t.Fatalf("expected the stale digest while the registry is down, got %q, %v", got, err)
+	}
+}
+
+func TestResolveUsesPullSecret(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.login = "robot:secret"
+	reg.set("gpu/dp:v1", "sha256:1111")
+	config := fmt.Sprintf(`{"auths":{"https://%s":{"auth":"cm9ib3Q6c2VjcmV0"}}}`, reg.host())
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull", Namespace: "d8-gpu-control-plane"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(config)},
+	}
+	key := types.NamespacedName{Namespace: "d8-gpu-control-plane", Name: "pull"}
+
+	r, _ := newTestResolver(t, reg, Options{PullSecret: key}, secret)
+	if got, err := r.Resolve(context.Background(), reg.host()+"/gpu/dp:v1"); err != nil || !strings.HasSuffix(got, "@sha256:1111") {
+		t.Fatalf("expected resolution with the pull secret, got %q, %v", got, err)
+	}
+
+	anonymous, _ := newTestResolver(t, reg, Options{})
+	if _, err := anonymous.Resolve(context.Background(), reg.host()+"/gpu/dp:v1"); !errors.Is(err, ErrUnresolved) {
+		t.Fatalf("expected anonymous resolution to be denied, got %v", err)
+	}
+
+	missing, _ := newTestResolver(t, reg, Options{PullSecret: key})
+	if _, err := missing.Resolve(context.Background(), reg.host()+"/gpu/dp:v1"); err == nil || !strings.Contains(err.Error(), "read pull secret") {
+		t.Fatalf("expected a missing pull secret to fail resolution, got %v", err)
+	}
+}
+
+func TestConfigureKeepsDigestsAndRetriesNewPullSecret(t *testing.T) {
+	reg := newFakeRegistry(t)
+	reg.login = "robot:secret"
+	reg.set("gpu/dp:v1", "sha256:1111")
+	config := fmt.Sprintf(`{"auths":{"https://%s":{"auth":"cm9ib3Q6c2VjcmV0"}}}`, reg.host())
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull", Namespace: "d8-gpu-control-plane"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(config)},
+	}
+	key := types.NamespacedName{Namespace: "d8-gpu-control-plane", Name: "pull"}
+	image := reg.host() + "/gpu/dp:v1"
+
+	r, _ := newTestResolver(t, reg, Options{}, secret)
+	if _, err := r.Resolve(context.Background(), image); !errors.Is(err, ErrUnresolved) {
+		t.Fatalf("expected anonymous resolution to be denied, got %v", err)
+	}
+
+	r.Configure(Options{PullSecret: key, RequireDigests: true})
+	if !r.RequireDigests() || r.CacheTTL() != DefaultCacheTTL {
+		t.Fatalf("expected the new options with the default TTL, got require=%v ttl=%v", r.RequireDigests(), r.CacheTTL())
+	}
+	if got, err := r.Resolve(context.Background(), image); err != nil || got != image+"@sha256:1111" {
+		t.Fatalf("expected the new pull secret to be tried at once, got %q, %v", got, err)
+	}
+
+	requests := reg.manifestRequests()
+	r.Configure(Options{PullSecret: key, CacheTTL: 2 * time.Hour})
+	if got, err := r.Resolve(context.Background(), image); err != nil || got != image+"@sha256:1111" {
+		t.Fatalf("expected the cached digest, got %q, %v", got, err)
+	}
+	if reg.manifestRequests() != requests {
+		t.Fatalf("reconfiguring must keep the resolved digests")
+	}
+}
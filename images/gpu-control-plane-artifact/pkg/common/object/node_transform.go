@@ -0,0 +1,51 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// NodeCacheTransform trims Node objects before they enter the informer cache. Controllers only read
+// metadata, spec (taints, unschedulable) and status conditions; image lists, attached volumes and
+// managed fields can make up most of a Node on large clusters and are dropped. Objects of other
+// types are returned unchanged, so the function is safe to use for any informer.
+func NodeCacheTransform() toolscache.TransformFunc {
+	return func(obj any) (any, error) {
+		node, ok := obj.(*corev1.Node)
+		if !ok {
+			return obj, nil
+		}
+		StripNode(node)
+		return node, nil
+	}
+}
+
+// StripNode removes the heavy Node fields that no controller reads. It is idempotent.
+func StripNode(node *corev1.Node) {
+	if node == nil {
+		return
+	}
+	node.ManagedFields = nil
+	if _, ok := node.Annotations[lastAppliedConfigAnnotation]; ok {
+		delete(node.Annotations, lastAppliedConfigAnnotation)
+	}
+	node.Status.Images = nil
+	node.Status.VolumesInUse = nil
+	node.Status.VolumesAttached = nil
+}
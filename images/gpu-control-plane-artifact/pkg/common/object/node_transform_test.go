@@ -0,0 +1,90 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func heavyNode() *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "node-a",
+			UID:           "uid-a",
+			Labels:        map[string]string{"gpu.deckhouse.io/enabled": "true"},
+			Annotations:   map[string]string{"keep": "me", lastAppliedConfigAnnotation: "{}"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		Status: corev1.NodeStatus{
+			Conditions:      []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+			Images:          []corev1.ContainerImage{{Names: []string{"registry/image:tag"}, SizeBytes: 1 << 30}},
+			VolumesInUse:    []corev1.UniqueVolumeName{"kubernetes.io/csi/vol"},
+			VolumesAttached: []corev1.AttachedVolume{{Name: "kubernetes.io/csi/vol"}},
+		},
+	}
+}
+
+func TestNodeCacheTransformStripsHeavyFields(t *testing.T) {
+	out, err := NodeCacheTransform()(heavyNode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node := out.(*corev1.Node)
+
+	if node.ManagedFields != nil || node.Status.Images != nil || node.Status.VolumesInUse != nil || node.Status.VolumesAttached != nil {
+		t.Fatalf("expected heavy fields to be stripped, got %+v", node)
+	}
+	if _, ok := node.Annotations[lastAppliedConfigAnnotation]; ok {
+		t.Fatalf("expected last-applied annotation to be stripped")
+	}
+	if node.Name != "node-a" || node.UID != "uid-a" || node.Labels["gpu.deckhouse.io/enabled"] != "true" || node.Annotations["keep"] != "me" {
+		t.Fatalf("expected metadata to be kept, got %+v", node.ObjectMeta)
+	}
+	if !node.Spec.Unschedulable || len(node.Spec.Taints) != 1 {
+		t.Fatalf("expected spec to be kept, got %+v", node.Spec)
+	}
+	if len(node.Status.Conditions) != 1 || node.Status.Conditions[0].Type != corev1.NodeReady {
+		t.Fatalf("expected conditions to be kept, got %+v", node.Status.Conditions)
+	}
+
+	again, err := NodeCacheTransform()(node)
+	if err != nil || again.(*corev1.Node).Name != "node-a" {
+		t.Fatalf("expected transform to be idempotent, got %v (%v)", again, err)
+	}
+}
+
+func TestNodeCacheTransformPassesOtherObjects(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "x"}}}}
+	out, err := NodeCacheTransform()(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != pod || len(pod.ManagedFields) != 1 {
+		t.Fatalf("expected non-node objects to be returned unchanged")
+	}
+
+	tombstone := "not-an-object"
+	if out, _ := NodeCacheTransform()(tombstone); out != tombstone {
+		t.Fatalf("expected unknown values to pass through")
+	}
+	StripNode(nil)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// MetricsPortName is the conventional container port name of a metrics endpoint.
+	MetricsPortName = "metrics"
+	// DCGMExporterPort is the dcgm-exporter metrics port used when the pod does not name one.
+	DCGMExporterPort int32 = 9400
+)
+
+// PortLookup describes how to find the container port serving an endpoint.
+type PortLookup struct {
+	// Name is searched across all containers, so sidecars in front of the serving container do not matter.
+	Name string
+	// Container, when set, also accepts the first port of that container, for pods rendered before ports were named.
+	Container string
+	// Fallback is used when no port carries Name; zero means the lookup fails instead.
+	Fallback int32
+}
+
+// ResolvePort returns the port for lookup, or false when the pod declares no such port and there is no fallback.
+func ResolvePort(pod *corev1.Pod, lookup PortLookup) (int32, bool) {
+	if pod == nil {
+		return 0, false
+	}
+	if lookup.Name != "" {
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == lookup.Name && port.ContainerPort > 0 {
+					return port.ContainerPort, true
+				}
+			}
+		}
+	}
+	if lookup.Container != "" {
+		for _, container := range pod.Spec.Containers {
+			if container.Name != lookup.Container {
+				continue
+			}
+			for _, port := range container.Ports {
+				if port.ContainerPort > 0 {
+					return port.ContainerPort, true
+				}
+			}
+		}
+	}
+	if lookup.Fallback > 0 {
+		return lookup.Fallback, true
+	}
+	return 0, false
+}
+
+// Endpoint returns host:port of a Ready pod for lookup. Host-network pods report the node address as pod IP,
+// which is where their ports are bound, so they need no special casing. The error names the pod and the reason.
+func Endpoint(pod *corev1.Pod, lookup PortLookup) (string, error) {
+	if pod == nil {
+		return "", fmt.Errorf("pod is nil")
+	}
+	if pod.Status.PodIP == "" {
+		return "", fmt.Errorf("pod %s/%s has no IP yet", pod.Namespace, pod.Name)
+	}
+	if !IsReady(pod) {
+		return "", fmt.Errorf("pod %s/%s is not ready", pod.Namespace, pod.Name)
+	}
+	port, ok := ResolvePort(pod, lookup)
+	if !ok {
+		return "", fmt.Errorf("pod %s/%s has no container port named %q", pod.Namespace, pod.Name, lookup.Name)
+	}
+	return net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))), nil
+}
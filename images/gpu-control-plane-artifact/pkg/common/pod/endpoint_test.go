@@ -0,0 +1,130 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func readyPod(ip string, hostNetwork bool, containers ...corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "exporter", Namespace: "d8-gpu-control-plane"},
+		Spec:       corev1.PodSpec{HostNetwork: hostNetwork, Containers: containers},
+		Status: corev1.PodStatus{
+			PodIP:      ip,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	metricsLookup := PortLookup{Name: MetricsPortName, Fallback: DCGMExporterPort}
+	cases := []struct {
+		name    string
+		pod     *corev1.Pod
+		lookup  PortLookup
+		want    string
+		wantErr string
+	}{
+		{
+			name: "named port",
+			pod: readyPod("10.0.0.5", false, corev1.Container{Name: "dcgm-exporter", Ports: []corev1.ContainerPort{
+				{Name: "health", ContainerPort: 8080},
+				{Name: MetricsPortName, ContainerPort: 9500},
+			}}),
+			lookup: metricsLookup,
+			want:   "10.0.0.5:9500",
+		},
+		{
+			name: "named port in a later container",
+			pod: readyPod("10.0.0.5", false,
+				corev1.Container{Name: "sidecar", Ports: []corev1.ContainerPort{{Name: "proxy", ContainerPort: 15001}}},
+				corev1.Container{Name: "dcgm-exporter", Ports: []corev1.ContainerPort{{Name: MetricsPortName, ContainerPort: 9400}}},
+			),
+			lookup: metricsLookup,
+			want:   "10.0.0.5:9400",
+		},
+		{
+			name:   "legacy unnamed port of the serving container",
+			pod:    readyPod("10.0.0.6", false, corev1.Container{Name: "gfd"}, corev1.Container{Name: "gfd-extender", Ports: []corev1.ContainerPort{{ContainerPort: 1234}}}),
+			lookup: PortLookup{Name: "detect", Container: "gfd-extender", Fallback: 2376},
+			want:   "10.0.0.6:1234",
+		},
+		{
+			name:   "no port uses the fallback",
+			pod:    readyPod("10.0.0.7", false, corev1.Container{Name: "dcgm-exporter"}),
+			lookup: metricsLookup,
+			want:   "10.0.0.7:9400",
+		},
+		{
+			name:    "no port without fallback",
+			pod:     readyPod("10.0.0.7", false, corev1.Container{Name: "dcgm-exporter", Ports: []corev1.ContainerPort{{Name: "health", ContainerPort: 8080}}}),
+			lookup:  PortLookup{Name: MetricsPortName},
+			wantErr: `d8-gpu-control-plane/exporter has no container port named "metrics"`,
+		},
+		{
+			name:   "host network pod",
+			pod:    readyPod("192.168.1.20", true, corev1.Container{Name: "dcgm-exporter", Ports: []corev1.ContainerPort{{Name: MetricsPortName, ContainerPort: 9400, HostPort: 9400}}}),
+			lookup: metricsLookup,
+			want:   "192.168.1.20:9400",
+		},
+		{
+			name:   "IPv6 pod",
+			pod:    readyPod("fd00::5", false, corev1.Container{Name: "dcgm-exporter", Ports: []corev1.ContainerPort{{Name: MetricsPortName, ContainerPort: 9400}}}),
+			lookup: metricsLookup,
+			want:   "[fd00::5]:9400",
+		},
+		{
+			name:    "no pod IP",
+			pod:     readyPod("", false, corev1.Container{Name: "dcgm-exporter"}),
+			lookup:  metricsLookup,
+			wantErr: "has no IP yet",
+		},
+		{
+			name: "not ready",
+			pod: func() *corev1.Pod {
+				p := readyPod("10.0.0.8", false, corev1.Container{Name: "dcgm-exporter"})
+				p.Status.Conditions[0].Status = corev1.ConditionFalse
+				return p
+			}(),
+			lookup:  metricsLookup,
+			wantErr: "is not ready",
+		},
+		{
+			name:    "nil pod",
+			lookup:  metricsLookup,
+			wantErr: "pod is nil",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Endpoint(tc.pod, tc.lookup)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v (endpoint %q)", tc.wantErr, err, got)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Fatalf("Endpoint() = %q, %v; want %q", got, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statistics keeps rolling-window summaries of ratio samples in bounded memory.
+package statistics
+
+import (
+	"math"
+	"time"
+)
+
+// percentileBins resolves percentiles to 1% of the ratio range.
+const percentileBins = 101
+
+// Summary describes the samples of a window.
+type Summary struct {
+	// Average is the mean of the samples, as a ratio in [0,1].
+	Average float64
+	// P95 is the 95th percentile of the samples, as a ratio in [0,1].
+	P95 float64
+	// Samples is the number of samples the summary is computed from.
+	Samples int64
+}
+
+// Window summarises ratio samples observed within the last span. Samples are folded into fixed-width
+// buckets kept in a ring, so memory is bounded by span/width regardless of the sample rate.
+// A Window is not safe for concurrent use.
+type Window struct {
+	span     time.Duration
+	width    time.Duration
+	buckets  []bucket
+	baseline *baseline
+}
+
+type bucket struct {
+	start time.Time
+	count int64
+	sum   float64
+	hist  [percentileBins]uint32
+}
+
+// baseline is a summary restored after a restart. It stands in for the samples that were lost and
+// fades out linearly as the window moves past the time it was taken.
+type baseline struct {
+	summary Summary
+	at      time.Time
+}
+
+// NewWindow creates a window over span with buckets of the given width.
+func NewWindow(span, width time.Duration) *Window {
+	if width <= 0 || width > span {
+		width = span
+	}
+	n := int((span + width - 1) / width)
+	return &Window{span: span, width: width, buckets: make([]bucket, n)}
+}
+
+// Span returns the window length.
+func (w *Window) Span() time.Duration {
+	return w.span
+}
+
+// Add records a sample taken at the given time. Values are clamped to [0,1]; samples that are
+// already outside of the ring are dropped.
+func (w *Window) Add(at time.Time, value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	value = math.Min(math.Max(value, 0), 1)
+
+	start := at.Truncate(w.width)
+	b := &w.buckets[w.slot(start)]
+	switch {
+	case b.start.Equal(start):
+	case b.start.After(start):
+		return
+	default:
+		*b = bucket{start: start}
+	}
+	b.count++
+	b.sum += value
+	b.hist[int(math.Round(value*100))]++
+}
+
+// Restore seeds the window with a summary taken at the given time, e.g. one persisted before a restart.
+func (w *Window) Restore(summary Summary, at time.Time) {
+	if summary.Samples <= 0 {
+		w.baseline = nil
+		return
+	}
+	w.baseline = &baseline{summary: summary, at: at}
+}
+
+// Summary returns the statistics of the samples within the window that ends at now.
+func (w *Window) Summary(now time.Time) Summary {
+	var (
+		count int64
+		sum   float64
+		hist  [percentileBins]uint64
+	)
+	for i := range w.buckets {
+		b := &w.buckets[i]
+		if b.count == 0 || !w.inWindow(b.start, now) {
+			continue
+		}
+		count += b.count
+		sum += b.sum
+		for bin, n := range b.hist {
+			hist[bin] += uint64(n)
+		}
+	}
+
+	var live Summary
+	if count > 0 {
+		live = Summary{Average: sum / float64(count), P95: percentile(hist, count, 0.95), Samples: count}
+	}
+	return w.withBaseline(live, now)
+}
+
+// withBaseline blends the restored summary into the live one, weighted by the samples it still represents.
+// The blended P95 is an approximation: the restored samples are known only by their summary.
+func (w *Window) withBaseline(live Summary, now time.Time) Summary {
+	if w.baseline == nil {
+		return live
+	}
+	age := now.Sub(w.baseline.at)
+	if age < 0 {
+		age = 0
+	}
+	if age >= w.span {
+		w.baseline = nil
+		return live
+	}
+	restored := int64(math.Round(float64(w.baseline.summary.Samples) * float64(w.span-age) / float64(w.span)))
+	if restored <= 0 {
+		return live
+	}
+
+	total := live.Samples + restored
+	weight := float64(restored) / float64(total)
+	return Summary{
+		Average: live.Average*(1-weight) + w.baseline.summary.Average*weight,
+		P95:     live.P95*(1-weight) + w.baseline.summary.P95*weight,
+		Samples: total,
+	}
+}
+
+func (w *Window) inWindow(start, now time.Time) bool {
+	return !start.After(now) && now.Sub(start) < w.span
+}
+
+func (w *Window) slot(start time.Time) int {
+	n := int64(len(w.buckets))
+	return int(((start.UnixNano()/int64(w.width))%n + n) % n)
+}
+
+// percentile returns the smallest bin value below or at which the given share of samples lies.
+func percentile(hist [percentileBins]uint64, count int64, share float64) float64 {
+	rank := uint64(math.Ceil(share * float64(count)))
+	var seen uint64
+	for bin, n := range hist {
+		seen += n
+		if seen >= rank {
+			return float64(bin) / 100
+		}
+	}
+	return 1
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func approx(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestWindowEmpty(t *testing.T) {
+	w := NewWindow(time.Hour, time.Minute)
+	if got := w.Summary(epoch); got != (Summary{}) {
+		t.Fatalf("expected empty summary, got %+v", got)
+	}
+}
+
+func TestWindowAverageAndP95(t *testing.T) {
+	w := NewWindow(time.Hour, time.Minute)
+	// 100 samples 0.01..1.00, one per 30s.
+	for i := 1; i <= 100; i++ {
+		w.Add(epoch.Add(time.Duration(i)*30*time.Second), float64(i)/100)
+	}
+
+	got := w.Summary(epoch.Add(50 * time.Minute))
+	if got.Samples != 100 {
+		t.Fatalf("expected 100 samples, got %d", got.Samples)
+	}
+	approx(t, "average", got.Average, 0.505)
+	approx(t, "p95", got.P95, 0.95)
+}
+
+func TestWindowExpiresOldBuckets(t *testing.T) {
+	w := NewWindow(time.Hour, time.Minute)
+	w.Add(epoch, 1)
+	w.Add(epoch.Add(30*time.Minute), 0.5)
+
+	if got := w.Summary(epoch.Add(59 * time.Minute)); got.Samples != 2 {
+		t.Fatalf("expected both samples inside the window, got %+v", got)
+	}
+	got := w.Summary(epoch.Add(time.Hour))
+	if got.Samples != 1 {
+		t.Fatalf("expected the first sample to expire, got %+v", got)
+	}
+	approx(t, "average", got.Average, 0.5)
+
+	// A sample landing in a reused slot replaces the expired bucket.
+	w.Add(epoch.Add(time.Hour), 0)
+	got = w.Summary(epoch.Add(time.Hour))
+	if got.Samples != 2 {
+		t.Fatalf("expected two samples after the slot is reused, got %+v", got)
+	}
+	approx(t, "average", got.Average, 0.25)
+}
+
+func TestWindowDropsLateAndClampsSamples(t *testing.T) {
+	w := NewWindow(time.Hour, time.Minute)
+	w.Add(epoch.Add(time.Hour), 2)
+	// Same ring slot, one full span earlier: already outside of the window.
+	w.Add(epoch, 0)
+	w.Add(epoch.Add(time.Hour), -1)
+	w.Add(epoch.Add(time.Hour), math.NaN())
+
+	got := w.Summary(epoch.Add(time.Hour))
+	if got.Samples != 2 {
+		t.Fatalf("expected late and NaN samples to be dropped, got %+v", got)
+	}
+	approx(t, "average", got.Average, 0.5)
+	approx(t, "p95", got.P95, 1)
+}
+
+func TestWindowRestoreFadesOut(t *testing.T) {
+	w := NewWindow(time.Hour, time.Minute)
+	w.Restore(Summary{Average: 0.8, P95: 0.9, Samples: 120}, epoch)
+
+	got := w.Summary(epoch)
+	if got.Samples != 120 {
+		t.Fatalf("expected the restored samples right after restore, got %+v", got)
+	}
+	approx(t, "average", got.Average, 0.8)
+
+	// Half a span later half of the restored samples are still in the window.
+	for i := 0; i < 60; i++ {
+		w.Add(epoch.Add(time.Duration(i)*30*time.Second), 0.2)
+	}
+	got = w.Summary(epoch.Add(30 * time.Minute))
+	if got.Samples != 120 {
+		t.Fatalf("expected 60 live and 60 restored samples, got %+v", got)
+	}
+	approx(t, "average", got.Average, 0.5)
+	approx(t, "p95", got.P95, 0.55)
+
+	// A span later the restored summary is gone, as is the first minute of live samples.
+	got = w.Summary(epoch.Add(time.Hour))
+	if got.Samples != 58 {
+		t.Fatalf("expected the restored summary to fade out after a span, got %+v", got)
+	}
+	approx(t, "average", got.Average, 0.2)
+}
+
+func TestWindowRestoreEmptySummary(t *testing.T) {
+	w := NewWindow(24*time.Hour, 15*time.Minute)
+	w.Restore(Summary{Average: 0.5, P95: 0.5}, epoch)
+	if got := w.Summary(epoch); got.Samples != 0 {
+		t.Fatalf("expected no samples from an empty summary, got %+v", got)
+	}
+}
+
+func TestNewWindowNormalizesWidth(t *testing.T) {
+	w := NewWindow(time.Hour, 0)
+	if len(w.buckets) != 1 || w.Span() != time.Hour {
+		t.Fatalf("expected a single bucket spanning the window, got %d buckets", len(w.buckets))
+	}
+	if n := len(NewWindow(24*time.Hour, 15*time.Minute).buckets); n != 96 {
+		t.Fatalf("expected 96 buckets, got %d", n)
+	}
+}
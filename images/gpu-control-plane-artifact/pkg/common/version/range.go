@@ -0,0 +1,129 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range is a conjunction of version constraints such as ">=535.104 <535.105". A bare version matches
+// itself and every more specific version, so "535.104" matches 535.104.05; an empty Range matches anything.
+type Range struct {
+	raw         string
+	constraints []constraint
+}
+
+type constraint struct {
+	op      string
+	version []uint64
+}
+
+// ParseRange parses whitespace- or comma-separated constraints, each one of >=, >, <=, < or = followed by a
+// version, or a bare version.
+func ParseRange(raw string) (Range, error) {
+	return parseRange(raw, strings.TrimSpace)
+}
+
+// ParseKernelRange is ParseRange for kernel releases: every version goes through Kernel first, so
+// "<5.15.0-100" is written the way the node reports its kernel. Match it against Kernel(release).
+func ParseKernelRange(raw string) (Range, error) {
+	return parseRange(raw, Kernel)
+}
+
+func parseRange(raw string, normalize func(string) string) (Range, error) {
+	r := Range{raw: strings.TrimSpace(raw)}
+	for _, field := range strings.FieldsFunc(r.raw, func(c rune) bool { return c == ',' || c == ' ' || c == '\t' }) {
+		op := ""
+		for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(field, candidate) {
+				op = candidate
+				break
+			}
+		}
+		value := normalize(strings.TrimPrefix(field, op))
+		if value == "" {
+			// Keep the written value in the error rather than the empty normalized one.
+			value = strings.TrimPrefix(field, op)
+		}
+		parsed, err := parse(value)
+		if err != nil {
+			return Range{}, fmt.Errorf("range %q: %w", r.raw, err)
+		}
+		r.constraints = append(r.constraints, constraint{op: op, version: parsed})
+	}
+	return r, nil
+}
+
+// String returns the range as written.
+func (r Range) String() string {
+	return r.raw
+}
+
+// IsEmpty reports whether the range has no constraints.
+func (r Range) IsEmpty() bool {
+	return len(r.constraints) == 0
+}
+
+// Contains reports whether v satisfies every constraint of the range.
+func (r Range) Contains(v string) (bool, error) {
+	parsed, err := parse(v)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range r.constraints {
+		if !c.matches(parsed) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c constraint) matches(v []uint64) bool {
+	if c.op == "" {
+		if len(v) < len(c.version) {
+			return compareParsed(v, c.version) == 0
+		}
+		return compareParsed(v[:len(c.version)], c.version) == 0
+	}
+	cmp := compareParsed(v, c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Kernel reduces a kernel release such as 5.15.0-91-generic to a comparable version (5.15.0.91): the leading
+// digits, dots and dashes are kept and dashes become separators. It returns "" when nothing numeric leads.
+func Kernel(release string) string {
+	release = strings.TrimSpace(release)
+	end := 0
+	for end < len(release) && (release[end] >= '0' && release[end] <= '9' || release[end] == '.' || release[end] == '-') {
+		end++
+	}
+	normalized := strings.Trim(strings.ReplaceAll(release[:end], "-", "."), ".")
+	for strings.Contains(normalized, "..") {
+		normalized = strings.ReplaceAll(normalized, "..", ".")
+	}
+	return normalized
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		rng, version string
+		want         bool
+	}{
+		{"535.104", "535.104.05", true},
+		{"535.104", "535.104", true},
+		{"535.104", "535.1040.1", false},
+		{"535.104", "535", false},
+		{"535.104.05", "535.104.5", true},
+		{">=535.104 <535.105", "535.104.12", true},
+		{">=535.104 <535.105", "535.104", true},
+		{">=535.104 <535.105", "535.105", false},
+		{">=535.104 <535.105", "535.103.99", false},
+		{">535.104,<=550", "535.104", false},
+		{">535.104,<=550", "535.104.1", true},
+		{">535.104,<=550", "550.0.0", true},
+		{">535.104,<=550", "550.0.1", false},
+		{"=1.14.2", "1.14.2", true},
+		{"=1.14.2", "1.14.2.1", false},
+		{"", "1.0", true},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.rng)
+		if err != nil {
+			t.Fatalf("ParseRange(%q): unexpected error: %v", tt.rng, err)
+		}
+		got, err := r.Contains(tt.version)
+		if err != nil {
+			t.Fatalf("%q.Contains(%q): unexpected error: %v", tt.rng, tt.version, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q.Contains(%q) = %t, want %t", tt.rng, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseRangeRejectsMalformed(t *testing.T) {
+	for _, raw := range []string{">=", "~535", ">=535.x", "535 <"} {
+		if _, err := ParseRange(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+	r, _ := ParseRange(">=535")
+	if _, err := r.Contains("v535"); err == nil {
+		t.Fatal("expected error for a malformed version")
+	}
+	if empty, _ := ParseRange("  "); !empty.IsEmpty() {
+		t.Fatal("expected a blank range to be empty")
+	}
+}
+
+func TestKernel(t *testing.T) {
+	for release, want := range map[string]string{
+		"5.15.0-91-generic":     "5.15.0.91",
+		"6.8.0-1015-aws":        "6.8.0.1015",
+		"4.18.0-513.el8.x86_64": "4.18.0.513",
+		"6.1.0":                 "6.1.0",
+		"generic":               "",
+		" 6.6.30-- ":            "6.6.30",
+	} {
+		if got := Kernel(release); got != want {
+			t.Fatalf("Kernel(%q) = %q, want %q", release, got, want)
+		}
+	}
+}
+
+func TestParseKernelRange(t *testing.T) {
+	r, err := ParseKernelRange(">=5.15.0-80 <5.15.0-100-generic")
+	if err != nil {
+		t.Fatalf("ParseKernelRange: %v", err)
+	}
+	for release, want := range map[string]bool{
+		"5.15.0-79-generic":  false,
+		"5.15.0-80-generic":  true,
+		"5.15.0-99-generic":  true,
+		"5.15.0-100-generic": false,
+	} {
+		if got, err := r.Contains(Kernel(release)); err != nil || got != want {
+			t.Fatalf("Contains(%q) = %t, %v; want %t", release, got, err, want)
+		}
+	}
+	if _, err := ParseKernelRange("<generic"); err == nil || !strings.Contains(err.Error(), "generic") {
+		t.Fatalf("expected the written value in the error, got %v", err)
+	}
+}
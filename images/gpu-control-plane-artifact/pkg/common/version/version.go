@@ -0,0 +1,82 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package version compares dotted numeric versions such as NVIDIA driver versions (535.104.05)
+// and CUDA compute capabilities (8.6), and matches them against version ranges.
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Compare returns -1, 0 or 1 when a is lower than, equal to or greater than b.
+// Missing trailing components count as zero, so "535" equals "535.0".
+func Compare(a, b string) (int, error) {
+	left, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	right, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return compareParsed(left, right), nil
+}
+
+func compareParsed(left, right []uint64) int {
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r uint64
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether have is greater than or equal to minimum.
+func AtLeast(have, minimum string) (bool, error) {
+	cmp, err := Compare(have, minimum)
+	if err != nil {
+		return false, err
+	}
+	return cmp >= 0, nil
+}
+
+func parse(raw string) ([]uint64, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil, fmt.Errorf("empty version")
+	}
+	parts := strings.Split(value, ".")
+	out := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: component %q is not a number", raw, part)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"535.104.05", "535.104.05", 0},
+		{"535.104.05", "535.104.5", 0},
+		{"535", "535.0.0", 0},
+		{"535.54.03", "535.104.05", -1},
+		{"550.54.15", "535.104.05", 1},
+		{"8.6", "8.0", 1},
+		{"7.5", "8.0", -1},
+		{"9.0", "10.0", -1},
+		{" 12.2 ", "12.2", 0},
+	}
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("Compare(%q, %q): unexpected error: %v", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Fatalf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCompareRejectsMalformed(t *testing.T) {
+	for _, raw := range []string{"", "535.x", "v535", "535..1", "-1"} {
+		if _, err := Compare(raw, "535"); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+		if _, err := Compare("535", raw); err == nil {
+			t.Fatalf("expected error for %q as second argument", raw)
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	ok, err := AtLeast("535.104.05", "535.54.03")
+	if err != nil || !ok {
+		t.Fatalf("expected 535.104.05 >= 535.54.03, got %v (%v)", ok, err)
+	}
+	ok, err = AtLeast("470.82.01", "535")
+	if err != nil || ok {
+		t.Fatalf("expected 470.82.01 < 535, got %v (%v)", ok, err)
+	}
+	if _, err := AtLeast("bad", "535"); err == nil {
+		t.Fatal("expected error for malformed version")
+	}
+}
@@ -25,8 +25,10 @@ func ModuleSettingsToState(settings ModuleSettings) (moduleconfig.State, error)
 	input := moduleconfig.Input{
 		Settings: map[string]any{
 			"managedNodes": map[string]any{
-				"labelKey":         settings.ManagedNodes.LabelKey,
-				"enabledByDefault": settings.ManagedNodes.EnabledByDefault,
+				"labelKey":                 settings.ManagedNodes.LabelKey,
+				"enabledByDefault":         settings.ManagedNodes.EnabledByDefault,
+				"previousLabelKey":         settings.ManagedNodes.PreviousLabelKey,
+				"labelKeyTransitionWindow": settings.ManagedNodes.LabelKeyTransitionWindow,
 			},
 			"deviceApproval": map[string]any{
 				"mode": string(settings.DeviceApproval.Mode),
@@ -34,6 +36,9 @@ func ModuleSettingsToState(settings ModuleSettings) (moduleconfig.State, error)
 			"scheduling": map[string]any{
 				"defaultStrategy": settings.Scheduling.DefaultStrategy,
 				"topologyKey":     settings.Scheduling.TopologyKey,
+				"poolNodeLabels":  settings.Scheduling.PoolNodeLabels,
+				"colocationHints": settings.Scheduling.ColocationHints,
+				"sharedPoolNames": settings.Scheduling.SharedPoolNames,
 			},
 			"placement": map[string]any{
 				"customTolerationKeys": settings.Placement.CustomTolerationKeys,
@@ -41,8 +46,18 @@ func ModuleSettingsToState(settings ModuleSettings) (moduleconfig.State, error)
 			"monitoring": map[string]any{
 				"serviceMonitor": settings.Monitoring.ServiceMonitor,
 			},
+			"nodeLabeling": map[string]any{
+				"enabled": settings.NodeLabeling.Enabled,
+			},
 			"inventory": map[string]any{
-				"resyncPeriod": settings.Inventory.ResyncPeriod,
+				"resyncPeriod":                 settings.Inventory.ResyncPeriod,
+				"deviceNameTemplate":           settings.Inventory.DeviceNameTemplate,
+				"staleNodeThreshold":           settings.Inventory.StaleNodeThreshold,
+				"staleDeviceRetention":         settings.Inventory.StaleDeviceRetention,
+				"maxDeletionsPerSweep":         settings.Inventory.MaxDeletionsPerSweep,
+				"requeueSpreadWindow":          settings.Inventory.RequeueSpreadWindow,
+				"attributePassthroughPrefixes": settings.Inventory.AttributePassthroughPrefixes,
+				"includeDisplayDevices":        settings.Inventory.IncludeDisplayDevices,
 			},
 			"https": map[string]any{
 				"mode": string(settings.HTTPS.Mode),
@@ -68,9 +83,56 @@ func ModuleSettingsToState(settings ModuleSettings) (moduleconfig.State, error)
 		}
 	}
 
+	if settings.LogLevel != "" {
+		input.Settings["logLevel"] = settings.LogLevel
+	}
+
 	if settings.HighAvailability != nil {
 		input.Settings["highAvailability"] = *settings.HighAvailability
 	}
 
+	if settings.Paused {
+		input.Settings["paused"] = true
+	}
+
+	if settings.MigrateFromGPUOperator {
+		input.Settings["migrateFromGPUOperator"] = true
+	}
+	if settings.AdoptExisting {
+		input.Settings["adoptExisting"] = true
+	}
+	if settings.LegacyPrivileged {
+		input.Settings["legacyPrivileged"] = true
+	}
+	if len(settings.ImageDigests) > 0 {
+		input.Settings["imageDigests"] = settings.ImageDigests
+	}
+
+	if len(settings.Handlers) > 0 {
+		handlers := make(map[string]any, len(settings.Handlers))
+		for name, handler := range settings.Handlers {
+			entry := map[string]any{}
+			if handler.Enabled != nil {
+				entry["enabled"] = *handler.Enabled
+			}
+			if handler.Settings != nil {
+				entry["settings"] = handler.Settings
+			}
+			handlers[name] = entry
+		}
+		input.Settings["handlers"] = handlers
+	}
+
+	if len(settings.Inventory.CompatibilityRules) > 0 {
+		input.Settings["inventory"].(map[string]any)["compatibilityRules"] = settings.Inventory.CompatibilityRules
+	}
+	if len(settings.Inventory.ConditionPolicy) > 0 {
+		input.Settings["inventory"].(map[string]any)["conditionPolicy"] = settings.Inventory.ConditionPolicy
+	}
+
+	if len(settings.PoolTemplates) > 0 {
+		input.Settings["poolTemplates"] = settings.PoolTemplates
+	}
+
 	return moduleconfig.Parse(input)
 }
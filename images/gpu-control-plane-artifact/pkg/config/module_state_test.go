@@ -16,6 +16,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -24,9 +25,12 @@ import (
 
 func TestModuleSettingsToState(t *testing.T) {
 	settings := ModuleSettings{
+		LogLevel: "debug",
 		ManagedNodes: ManagedNodesSettings{
-			LabelKey:         "gpu.deckhouse.io/custom",
-			EnabledByDefault: false,
+			LabelKey:                 "gpu.deckhouse.io/custom",
+			EnabledByDefault:         false,
+			PreviousLabelKey:         "gpu.deckhouse.io/enabled",
+			LabelKeyTransitionWindow: "24h",
 		},
 		DeviceApproval: DeviceApprovalSettings{
 			Mode: DeviceApprovalModeSelector,
@@ -40,15 +44,40 @@ func TestModuleSettingsToState(t *testing.T) {
 		Monitoring: MonitoringSettings{
 			ServiceMonitor: false,
 		},
+		NodeLabeling: NodeLabelingSettings{
+			Enabled: true,
+		},
 		Inventory: InventorySettings{
-			ResyncPeriod: "5m",
+			ResyncPeriod:                 "5m",
+			DeviceNameTemplate:           "{node}-{uuid8}",
+			StaleNodeThreshold:           "12h",
+			StaleDeviceRetention:         "48h",
+			MaxDeletionsPerSweep:         "25",
+			AttributePassthroughPrefixes: []string{"acme.com/"},
+			IncludeDisplayDevices:        true,
+			CompatibilityRules: []map[string]any{
+				{"name": "old-kernel", "kernelRange": "<5.4", "effect": "Warn"},
+			},
+			ConditionPolicy: map[string]any{
+				"InventoryComplete": map[string]any{"severity": "Critical"},
+			},
 		},
 		HTTPS: HTTPSSettings{
 			Mode:                    HTTPSModeCustomCertificate,
 			CertManagerIssuer:       "ignored",
 			CustomCertificateSecret: "my-secret",
 		},
-		HighAvailability: boolPtr(true),
+		HighAvailability:       boolPtr(true),
+		Paused:                 true,
+		MigrateFromGPUOperator: true,
+		AdoptExisting:          true,
+		ImageDigests:           map[string]any{"enabled": true, "cacheTTL": "2h"},
+		Handlers: map[string]HandlerSettings{
+			"device-state": {Enabled: boolPtr(false), Settings: map[string]any{"mode": "strict"}},
+		},
+		PoolTemplates: []map[string]any{
+			{"name": "l4", "match": map[string]any{"product": "L4"}, "poolSpec": map[string]any{"resource": map[string]any{"unit": "Card"}}},
+		},
 	}
 
 	state, err := ModuleSettingsToState(settings)
@@ -56,12 +85,18 @@ func TestModuleSettingsToState(t *testing.T) {
 		t.Fatalf("ModuleSettingsToState returned error: %v", err)
 	}
 
+	if state.Settings.LogLevel != "Debug" {
+		t.Fatalf("unexpected log level: %s", state.Settings.LogLevel)
+	}
 	if state.Settings.ManagedNodes.LabelKey != "gpu.deckhouse.io/custom" {
 		t.Fatalf("unexpected managed label key: %s", state.Settings.ManagedNodes.LabelKey)
 	}
 	if state.Settings.ManagedNodes.EnabledByDefault {
 		t.Fatalf("expected enabled by default to be false")
 	}
+	if state.Settings.ManagedNodes.PreviousLabelKey != "gpu.deckhouse.io/enabled" || state.Settings.ManagedNodes.TransitionWindow != 24*time.Hour {
+		t.Fatalf("unexpected label key transition: %+v", state.Settings.ManagedNodes)
+	}
 	if state.Settings.DeviceApproval.Mode != "Selector" {
 		t.Fatalf("unexpected device approval mode: %s", state.Settings.DeviceApproval.Mode)
 	}
@@ -77,15 +112,54 @@ func TestModuleSettingsToState(t *testing.T) {
 	if state.Settings.Monitoring.ServiceMonitor {
 		t.Fatalf("expected monitoring service monitor false when disabled explicitly")
 	}
+	if !state.Settings.NodeLabeling.Enabled {
+		t.Fatalf("expected node labeling to be enabled")
+	}
 	if state.Inventory.ResyncPeriod != "5m" {
 		t.Fatalf("unexpected inventory resync period: %s", state.Inventory.ResyncPeriod)
 	}
+	if state.Inventory.DeviceNameTemplate != "{node}-{uuid8}" {
+		t.Fatalf("unexpected device name template: %s", state.Inventory.DeviceNameTemplate)
+	}
+	if state.Inventory.StaleNodeThreshold != 12*time.Hour || state.Inventory.StaleDeviceRetention != 48*time.Hour {
+		t.Fatalf("unexpected stale node settings: %+v", state.Inventory)
+	}
+	if state.Inventory.MaxDeletionsPerSweep != "25" {
+		t.Fatalf("unexpected deletion limit: %q", state.Inventory.MaxDeletionsPerSweep)
+	}
+	if len(state.Inventory.AttributePassthroughPrefixes) != 1 || state.Inventory.AttributePassthroughPrefixes[0] != "acme.com/" {
+		t.Fatalf("unexpected attribute passthrough prefixes: %v", state.Inventory.AttributePassthroughPrefixes)
+	}
+	if !state.Inventory.IncludeDisplayDevices {
+		t.Fatalf("expected includeDisplayDevices to be passed through")
+	}
+	if rules := state.Inventory.CompatibilityRules; len(rules) != 1 || rules[0].Name != "old-kernel" || rules[0].Effect != moduleconfig.CompatibilityEffectWarn {
+		t.Fatalf("unexpected compatibility rules: %+v", rules)
+	}
+	if policy := state.Inventory.ConditionPolicy["InventoryComplete"]; policy.Severity != moduleconfig.ConditionSeverityCritical {
+		t.Fatalf("unexpected condition policy: %+v", state.Inventory.ConditionPolicy)
+	}
 	if state.HTTPS.Mode != moduleconfig.HTTPSModeCustomCertificate || state.HTTPS.CustomCertificateSecret != "my-secret" {
 		t.Fatalf("unexpected https settings: %+v", state.HTTPS)
 	}
 	if state.HighAvailability == nil || !*state.HighAvailability {
 		t.Fatalf("expected highAvailability true, got %+v", state.HighAvailability)
 	}
+	if !state.Paused {
+		t.Fatalf("expected paused to be carried over")
+	}
+	if !state.Settings.Migration.FromGPUOperator || !state.Settings.Migration.AdoptExisting {
+		t.Fatalf("expected migration settings to be carried over, got %+v", state.Settings.Migration)
+	}
+	if !state.Settings.ImageDigests.Enabled || state.Settings.ImageDigests.CacheTTL != 2*time.Hour {
+		t.Fatalf("expected imageDigests to be carried over, got %+v", state.Settings.ImageDigests)
+	}
+	if state.HandlerEnabled("device-state") || string(state.Handlers["device-state"].Settings) != `{"mode":"strict"}` {
+		t.Fatalf("unexpected handler settings: %+v", state.Handlers)
+	}
+	if len(state.PoolTemplates) != 1 || state.PoolTemplates[0].ProductPattern != "L4" {
+		t.Fatalf("unexpected pool templates: %+v", state.PoolTemplates)
+	}
 }
 
 func boolPtr(v bool) *bool {
@@ -29,6 +29,17 @@ type System struct {
 	Controllers    ControllersConfig    `json:"controllers" yaml:"controllers"`
 	LeaderElection LeaderElectionConfig `json:"leaderElection" yaml:"leaderElection"`
 	Module         ModuleSettings       `json:"module" yaml:"module"`
+	Snapshot       SnapshotConfig       `json:"snapshot" yaml:"snapshot"`
+	InventoryAPI   InventoryAPIConfig   `json:"inventoryAPI" yaml:"inventoryAPI"`
+	AdminAPI       AdminAPIConfig       `json:"adminAPI" yaml:"adminAPI"`
+	Ownership      OwnershipConfig      `json:"ownership" yaml:"ownership"`
+	Utilization    UtilizationConfig    `json:"utilization" yaml:"utilization"`
+	ObjectSize     ObjectSizeConfig     `json:"objectSize" yaml:"objectSize"`
+	// ModuleSettingsFile points at a YAML file with ModuleConfig spec.settings for installs without
+	// the ModuleConfig CRD. It is watched for changes; a ModuleConfig object, when present, wins.
+	ModuleSettingsFile string `json:"moduleSettingsFile,omitempty" yaml:"moduleSettingsFile,omitempty"`
+	// Simulation fabricates GPU nodes for development clusters; off unless a fixture is configured.
+	Simulation SimulationConfig `json:"simulation,omitempty" yaml:"simulation,omitempty"`
 }
 
 // ControllersConfig holds per-controller tuning knobs.
@@ -42,6 +53,34 @@ type ControllersConfig struct {
 type ControllerConfig struct {
 	Workers      int           `json:"workers" yaml:"workers"`
 	ResyncPeriod time.Duration `json:"resyncPeriod" yaml:"resyncPeriod"`
+	// StatusWriteWorkers bounds concurrent status writes per reconcile; zero keeps the controller default.
+	StatusWriteWorkers int `json:"statusWriteWorkers,omitempty" yaml:"statusWriteWorkers,omitempty"`
+	// HTTPTimeouts bounds each outbound HTTP call the controller makes, independently of the reconcile context.
+	HTTPTimeouts HTTPTimeouts `json:"httpTimeouts,omitempty" yaml:"httpTimeouts,omitempty"`
+}
+
+// HTTPTimeouts holds the per-call deadline of each category of outbound HTTP calls; zero keeps the default.
+type HTTPTimeouts struct {
+	// Telemetry covers the utilization scrapes of gfd-extender.
+	Telemetry time.Duration `json:"telemetry,omitempty" yaml:"telemetry,omitempty"`
+	// Detection covers the inventory scrapes of gfd-extender.
+	Detection time.Duration `json:"detection,omitempty" yaml:"detection,omitempty"`
+	// Validation covers polls of validator and webhook endpoints.
+	Validation time.Duration `json:"validation,omitempty" yaml:"validation,omitempty"`
+}
+
+// OrDefaults fills unset or negative timeouts with the defaults.
+func (t HTTPTimeouts) OrDefaults() HTTPTimeouts {
+	if t.Telemetry <= 0 {
+		t.Telemetry = defaultTelemetryTimeout
+	}
+	if t.Detection <= 0 {
+		t.Detection = defaultDetectionTimeout
+	}
+	if t.Validation <= 0 {
+		t.Validation = defaultValidationTimeout
+	}
+	return t
 }
 
 // LeaderElectionConfig describes controller-runtime leader election settings.
@@ -52,6 +91,60 @@ type LeaderElectionConfig struct {
 	ResourceLock string `json:"resourceLock" yaml:"resourceLock"`
 }
 
+// SnapshotConfig controls periodic export of the cluster-wide inventory summary used in support bundles.
+type SnapshotConfig struct {
+	// Interval between snapshots; zero disables the feature.
+	Interval time.Duration `json:"interval" yaml:"interval"`
+	// Retain is the number of most recent snapshots kept in the workloads namespace.
+	Retain int `json:"retain" yaml:"retain"`
+}
+
+// UtilizationConfig controls the rolling per-pool GPU utilization statistics built from gfd-extender telemetry.
+type UtilizationConfig struct {
+	// SampleInterval between telemetry samples; zero disables the feature.
+	SampleInterval time.Duration `json:"sampleInterval" yaml:"sampleInterval"`
+	// PersistInterval between writes of the statistics into pool status.
+	PersistInterval time.Duration `json:"persistInterval" yaml:"persistInterval"`
+}
+
+// ObjectSizeConfig bounds the size of the objects whose status the controllers write.
+type ObjectSizeConfig struct {
+	// MaxBytes is the serialized object size above which a status write is refused; zero keeps the controller default.
+	MaxBytes int64 `json:"maxBytes,omitempty" yaml:"maxBytes,omitempty"`
+}
+
+// InventoryAPIConfig controls the read-only inventory HTTP API served from the controller cache.
+type InventoryAPIConfig struct {
+	// BindAddress of the dedicated listener; empty disables the API.
+	BindAddress string `json:"bindAddress" yaml:"bindAddress"`
+	// CertFile and KeyFile enable TLS when both are set.
+	CertFile string `json:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+}
+
+// AdminAPIConfig controls the work-queue administration endpoints of the controller.
+type AdminAPIConfig struct {
+	// BindAddress of the dedicated listener; it must be a loopback address, empty disables the API.
+	BindAddress string `json:"bindAddress" yaml:"bindAddress"`
+}
+
+// OwnershipConfig controls the controller-instance claim that keeps two installations from managing the same objects.
+type OwnershipConfig struct {
+	// Namespace of this installation; empty falls back to the leader election namespace. Without a namespace
+	// the claim and the heartbeat Lease are disabled.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// ForceAdopt takes over objects claimed by another live instance, for intentional migrations.
+	ForceAdopt bool `json:"forceAdopt,omitempty" yaml:"forceAdopt,omitempty"`
+}
+
+// SimulationConfig runs the node simulator of development and demo clusters without GPUs.
+type SimulationConfig struct {
+	// NodesFile points at the YAML fixture describing the simulated nodes; empty disables simulation.
+	NodesFile string `json:"nodesFile,omitempty" yaml:"nodesFile,omitempty"`
+	// Force simulates even when the cluster already has real GPU nodes.
+	Force bool `json:"force,omitempty" yaml:"force,omitempty"`
+}
+
 // DeviceApprovalMode describes how newly detected devices should be approved.
 type DeviceApprovalMode string
 
@@ -63,20 +156,42 @@ const (
 
 // ModuleSettings holds high-level module policies delivered via ModuleConfig.
 type ModuleSettings struct {
-	ManagedNodes     ManagedNodesSettings   `json:"managedNodes" yaml:"managedNodes"`
-	DeviceApproval   DeviceApprovalSettings `json:"deviceApproval" yaml:"deviceApproval"`
-	Scheduling       SchedulingSettings     `json:"scheduling" yaml:"scheduling"`
-	Placement        PlacementSettings      `json:"placement" yaml:"placement"`
-	Monitoring       MonitoringSettings     `json:"monitoring" yaml:"monitoring"`
-	Inventory        InventorySettings      `json:"inventory" yaml:"inventory"`
-	HTTPS            HTTPSSettings          `json:"https" yaml:"https"`
-	HighAvailability *bool                  `json:"highAvailability,omitempty" yaml:"highAvailability,omitempty"`
+	LogLevel         string                     `json:"logLevel,omitempty" yaml:"logLevel,omitempty"`
+	ManagedNodes     ManagedNodesSettings       `json:"managedNodes" yaml:"managedNodes"`
+	DeviceApproval   DeviceApprovalSettings     `json:"deviceApproval" yaml:"deviceApproval"`
+	Scheduling       SchedulingSettings         `json:"scheduling" yaml:"scheduling"`
+	Placement        PlacementSettings          `json:"placement" yaml:"placement"`
+	Monitoring       MonitoringSettings         `json:"monitoring" yaml:"monitoring"`
+	NodeLabeling     NodeLabelingSettings       `json:"nodeLabeling" yaml:"nodeLabeling"`
+	Inventory        InventorySettings          `json:"inventory" yaml:"inventory"`
+	HTTPS            HTTPSSettings              `json:"https" yaml:"https"`
+	HighAvailability *bool                      `json:"highAvailability,omitempty" yaml:"highAvailability,omitempty"`
+	Paused           bool                       `json:"paused,omitempty" yaml:"paused,omitempty"`
+	Handlers         map[string]HandlerSettings `json:"handlers,omitempty" yaml:"handlers,omitempty"`
+	// PoolTemplates is passed to the moduleconfig parser as is; see moduleconfig.PoolTemplate.
+	PoolTemplates []map[string]any `json:"poolTemplates,omitempty" yaml:"poolTemplates,omitempty"`
+	// MigrateFromGPUOperator and AdoptExisting drive the migration from an upstream gpu-operator installation.
+	MigrateFromGPUOperator bool `json:"migrateFromGPUOperator,omitempty" yaml:"migrateFromGPUOperator,omitempty"`
+	AdoptExisting          bool `json:"adoptExisting,omitempty" yaml:"adoptExisting,omitempty"`
+	// LegacyPrivileged renders the privileged security contexts of earlier releases.
+	LegacyPrivileged bool `json:"legacyPrivileged,omitempty" yaml:"legacyPrivileged,omitempty"`
+	// ImageDigests is passed to the moduleconfig parser as is; see moduleconfig.ImageDigestSettings.
+	ImageDigests map[string]any `json:"imageDigests,omitempty" yaml:"imageDigests,omitempty"`
+}
+
+// HandlerSettings toggles a reconcile handler and carries its opaque settings.
+type HandlerSettings struct {
+	Enabled  *bool          `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Settings map[string]any `json:"settings,omitempty" yaml:"settings,omitempty"`
 }
 
 // ManagedNodesSettings controls which nodes are considered managed by default.
 type ManagedNodesSettings struct {
 	LabelKey         string `json:"labelKey" yaml:"labelKey"`
 	EnabledByDefault bool   `json:"enabledByDefault" yaml:"enabledByDefault"`
+	// PreviousLabelKey keeps nodes labelled with the old key managed while they are relabeled.
+	PreviousLabelKey         string `json:"previousLabelKey,omitempty" yaml:"previousLabelKey,omitempty"`
+	LabelKeyTransitionWindow string `json:"labelKeyTransitionWindow,omitempty" yaml:"labelKeyTransitionWindow,omitempty"`
 }
 
 // DeviceApprovalSettings controls default approval workflow for new devices.
@@ -89,6 +204,9 @@ type DeviceApprovalSettings struct {
 type SchedulingSettings struct {
 	DefaultStrategy string `json:"defaultStrategy" yaml:"defaultStrategy"`
 	TopologyKey     string `json:"topologyKey,omitempty" yaml:"topologyKey,omitempty"`
+	PoolNodeLabels  bool   `json:"poolNodeLabels,omitempty" yaml:"poolNodeLabels,omitempty"`
+	ColocationHints bool   `json:"colocationHints,omitempty" yaml:"colocationHints,omitempty"`
+	SharedPoolNames bool   `json:"sharedPoolNames,omitempty" yaml:"sharedPoolNames,omitempty"`
 }
 
 // PlacementSettings carries cluster-wide toleration knobs.
@@ -100,8 +218,26 @@ type MonitoringSettings struct {
 	ServiceMonitor bool `json:"serviceMonitor" yaml:"serviceMonitor"`
 }
 
+// NodeLabelingSettings toggles the GPU capability labels written onto Node objects.
+type NodeLabelingSettings struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
 type InventorySettings struct {
-	ResyncPeriod string `json:"resyncPeriod" yaml:"resyncPeriod"`
+	ResyncPeriod         string `json:"resyncPeriod" yaml:"resyncPeriod"`
+	DeviceNameTemplate   string `json:"deviceNameTemplate,omitempty" yaml:"deviceNameTemplate,omitempty"`
+	StaleNodeThreshold   string `json:"staleNodeThreshold,omitempty" yaml:"staleNodeThreshold,omitempty"`
+	StaleDeviceRetention string `json:"staleDeviceRetention,omitempty" yaml:"staleDeviceRetention,omitempty"`
+	MaxDeletionsPerSweep string `json:"maxDeletionsPerSweep,omitempty" yaml:"maxDeletionsPerSweep,omitempty"`
+	RequeueSpreadWindow  string `json:"requeueSpreadWindow,omitempty" yaml:"requeueSpreadWindow,omitempty"`
+	// AttributePassthroughPrefixes selects NodeFeature instance attributes mirrored onto GPUDevice annotations.
+	AttributePassthroughPrefixes []string `json:"attributePassthroughPrefixes,omitempty" yaml:"attributePassthroughPrefixes,omitempty"`
+	// IncludeDisplayDevices keeps display-only adapters as GPUDevices.
+	IncludeDisplayDevices bool `json:"includeDisplayDevices,omitempty" yaml:"includeDisplayDevices,omitempty"`
+	// CompatibilityRules is passed to the moduleconfig parser as is; see moduleconfig.CompatibilityRule.
+	CompatibilityRules []map[string]any `json:"compatibilityRules,omitempty" yaml:"compatibilityRules,omitempty"`
+	// ConditionPolicy is passed to the moduleconfig parser as is; see moduleconfig.ConditionPolicy.
+	ConditionPolicy map[string]any `json:"conditionPolicy,omitempty" yaml:"conditionPolicy,omitempty"`
 }
 
 type HTTPSMode string
@@ -124,6 +260,11 @@ const (
 	DefaultLeaderElectionResourceLock = "leases"
 	defaultControllerWorkers          = 1
 	defaultControllerResyncPeriod     = 30 * time.Second
+	defaultSnapshotRetain             = 3
+	defaultUtilizationPersistInterval = 5 * time.Minute
+	defaultTelemetryTimeout           = 2 * time.Second
+	defaultDetectionTimeout           = 3 * time.Second
+	defaultValidationTimeout          = 5 * time.Second
 
 	defaultManagedNodeLabelKey    = "gpu.deckhouse.io/enabled"
 	defaultSchedulingStrategy     = "Spread"
@@ -170,6 +311,12 @@ func DefaultSystem() System {
 			ID:           DefaultLeaderElectionID,
 			ResourceLock: DefaultLeaderElectionResourceLock,
 		},
+		Snapshot: SnapshotConfig{
+			Retain: defaultSnapshotRetain,
+		},
+		Utilization: UtilizationConfig{
+			PersistInterval: defaultUtilizationPersistInterval,
+		},
 	}
 }
 
@@ -177,6 +324,7 @@ func defaultControllerConfig() ControllerConfig {
 	return ControllerConfig{
 		Workers:      defaultControllerWorkers,
 		ResyncPeriod: defaultControllerResyncPeriod,
+		HTTPTimeouts: HTTPTimeouts{}.OrDefaults(),
 	}
 }
 
@@ -199,8 +347,18 @@ func LoadFile(path string) (System, error) {
 	normalizeControllerResync(&cfg.Controllers.GPUInventory)
 	normalizeControllerResync(&cfg.Controllers.GPUBootstrap)
 	normalizeControllerResync(&cfg.Controllers.GPUPool)
+	cfg.Controllers.GPUInventory.HTTPTimeouts = cfg.Controllers.GPUInventory.HTTPTimeouts.OrDefaults()
+	cfg.Controllers.GPUBootstrap.HTTPTimeouts = cfg.Controllers.GPUBootstrap.HTTPTimeouts.OrDefaults()
+	cfg.Controllers.GPUPool.HTTPTimeouts = cfg.Controllers.GPUPool.HTTPTimeouts.OrDefaults()
 	normalizeLeaderElection(&cfg.LeaderElection)
 	normalizeModuleSettings(&cfg.Module)
+	normalizeSnapshot(&cfg.Snapshot)
+	normalizeInventoryAPI(&cfg.InventoryAPI)
+	cfg.AdminAPI.BindAddress = strings.TrimSpace(cfg.AdminAPI.BindAddress)
+	normalizeUtilization(&cfg.Utilization)
+	if cfg.ObjectSize.MaxBytes < 0 {
+		cfg.ObjectSize.MaxBytes = 0
+	}
 
 	return cfg, nil
 }
@@ -227,11 +385,41 @@ func normalizeLeaderElection(cfg *LeaderElectionConfig) {
 	cfg.Namespace = strings.TrimSpace(cfg.Namespace)
 }
 
+func normalizeSnapshot(cfg *SnapshotConfig) {
+	if cfg.Interval < 0 {
+		cfg.Interval = 0
+	}
+	if cfg.Retain <= 0 {
+		cfg.Retain = defaultSnapshotRetain
+	}
+}
+
+func normalizeUtilization(cfg *UtilizationConfig) {
+	if cfg.SampleInterval < 0 {
+		cfg.SampleInterval = 0
+	}
+	if cfg.PersistInterval <= 0 {
+		cfg.PersistInterval = defaultUtilizationPersistInterval
+	}
+}
+
+func normalizeInventoryAPI(cfg *InventoryAPIConfig) {
+	cfg.BindAddress = strings.TrimSpace(cfg.BindAddress)
+	cfg.CertFile = strings.TrimSpace(cfg.CertFile)
+	cfg.KeyFile = strings.TrimSpace(cfg.KeyFile)
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		cfg.CertFile = ""
+		cfg.KeyFile = ""
+	}
+}
+
 func normalizeModuleSettings(cfg *ModuleSettings) {
 	cfg.ManagedNodes.LabelKey = strings.TrimSpace(cfg.ManagedNodes.LabelKey)
 	if cfg.ManagedNodes.LabelKey == "" {
 		cfg.ManagedNodes.LabelKey = defaultManagedNodeLabelKey
 	}
+	cfg.ManagedNodes.PreviousLabelKey = strings.TrimSpace(cfg.ManagedNodes.PreviousLabelKey)
+	cfg.ManagedNodes.LabelKeyTransitionWindow = strings.TrimSpace(cfg.ManagedNodes.LabelKeyTransitionWindow)
 
 	switch cfg.DeviceApproval.Mode {
 	case DeviceApprovalModeAutomatic, DeviceApprovalModeSelector, DeviceApprovalModeManual:
@@ -277,6 +465,11 @@ func normalizeModuleSettings(cfg *ModuleSettings) {
 	cfg.Placement.CustomTolerationKeys = keys
 
 	cfg.Inventory.ResyncPeriod = strings.TrimSpace(cfg.Inventory.ResyncPeriod)
+	cfg.Inventory.DeviceNameTemplate = strings.TrimSpace(cfg.Inventory.DeviceNameTemplate)
+	cfg.Inventory.StaleNodeThreshold = strings.TrimSpace(cfg.Inventory.StaleNodeThreshold)
+	cfg.Inventory.StaleDeviceRetention = strings.TrimSpace(cfg.Inventory.StaleDeviceRetention)
+	cfg.Inventory.MaxDeletionsPerSweep = strings.TrimSpace(cfg.Inventory.MaxDeletionsPerSweep)
+	cfg.Inventory.RequeueSpreadWindow = strings.TrimSpace(cfg.Inventory.RequeueSpreadWindow)
 	if cfg.Inventory.ResyncPeriod == "" {
 		cfg.Inventory.ResyncPeriod = defaultInventoryResyncPeriod
 	}
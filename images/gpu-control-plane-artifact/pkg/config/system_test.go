@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -88,6 +89,26 @@ func TestLoadFileNormalisesWorkers(t *testing.T) {
 	}
 }
 
+func TestLoadFileHTTPTimeouts(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("controllers:\n  gpuInventory:\n    httpTimeouts:\n      detection: 10s\n      telemetry: -1s\n"), 0o600); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+
+	cfg, err := LoadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	got := cfg.Controllers.GPUInventory.HTTPTimeouts
+	if got.Detection != 10*time.Second || got.Telemetry != defaultTelemetryTimeout || got.Validation != defaultValidationTimeout {
+		t.Fatalf("unexpected inventory timeouts: %+v", got)
+	}
+	if want := (HTTPTimeouts{Telemetry: 2 * time.Second, Detection: 3 * time.Second, Validation: 5 * time.Second}); cfg.Controllers.GPUPool.HTTPTimeouts != want {
+		t.Fatalf("expected default timeouts %+v, got %+v", want, cfg.Controllers.GPUPool.HTTPTimeouts)
+	}
+}
+
 func TestLoadFileLeaderElectionOverrides(t *testing.T) {
 	tmp := t.TempDir()
 	cfgPath := filepath.Join(tmp, "config.yaml")
@@ -404,6 +425,79 @@ func TestNormalizeModuleSettingsSpreadDefaultsTopology(t *testing.T) {
 	}
 }
 
+func TestLoadFileSnapshotDefaultsDisabled(t *testing.T) {
+	cfg := DefaultSystem()
+	if cfg.Snapshot.Interval != 0 {
+		t.Fatalf("expected snapshots disabled by default, got interval %s", cfg.Snapshot.Interval)
+	}
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("snapshot:\n  interval: 15m\n  retain: 0\n"), 0o600); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+
+	loaded, err := LoadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if loaded.Snapshot.Interval != 15*time.Minute {
+		t.Fatalf("expected snapshot interval 15m, got %s", loaded.Snapshot.Interval)
+	}
+	if loaded.Snapshot.Retain != defaultSnapshotRetain {
+		t.Fatalf("expected retain to be normalised to %d, got %d", defaultSnapshotRetain, loaded.Snapshot.Retain)
+	}
+}
+
+func TestLoadFileUtilizationDefaultsDisabled(t *testing.T) {
+	cfg := DefaultSystem()
+	if cfg.Utilization.SampleInterval != 0 {
+		t.Fatalf("expected utilization statistics disabled by default, got interval %s", cfg.Utilization.SampleInterval)
+	}
+
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("utilization:\n  sampleInterval: 1m\n  persistInterval: 0s\n"), 0o600); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+
+	loaded, err := LoadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if loaded.Utilization.SampleInterval != time.Minute {
+		t.Fatalf("expected sample interval 1m, got %s", loaded.Utilization.SampleInterval)
+	}
+	if loaded.Utilization.PersistInterval != defaultUtilizationPersistInterval {
+		t.Fatalf("expected persist interval to be normalised to %s, got %s", defaultUtilizationPersistInterval, loaded.Utilization.PersistInterval)
+	}
+}
+
+func TestLoadFileObjectSize(t *testing.T) {
+	tmp := t.TempDir()
+	cfgPath := filepath.Join(tmp, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("objectSize:\n  maxBytes: 262144\n"), 0o600); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+	loaded, err := LoadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if loaded.ObjectSize.MaxBytes != 262144 {
+		t.Fatalf("expected maxBytes 262144, got %d", loaded.ObjectSize.MaxBytes)
+	}
+
+	if err := os.WriteFile(cfgPath, []byte("objectSize:\n  maxBytes: -5\n"), 0o600); err != nil {
+		t.Fatalf("write temp config: %v", err)
+	}
+	if loaded, err = LoadFile(cfgPath); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if loaded.ObjectSize.MaxBytes != 0 {
+		t.Fatalf("expected a negative maxBytes to fall back to the default, got %d", loaded.ObjectSize.MaxBytes)
+	}
+}
+
 func TestLoadFileDecodeError(t *testing.T) {
 	tmp := t.TempDir()
 	cfgPath := filepath.Join(tmp, "bad.yaml")
@@ -0,0 +1,83 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// RequireCondition fails the test unless conditions hold conditionType with status and, when reason is not
+// empty, reason. It returns the condition for further checks.
+func RequireCondition(t testing.TB, conditions []metav1.Condition, conditionType string, status metav1.ConditionStatus, reason string) *metav1.Condition {
+	t.Helper()
+	cond := meta.FindStatusCondition(conditions, conditionType)
+	if cond == nil {
+		t.Fatalf("condition %s not found in %+v", conditionType, conditions)
+		return nil
+	}
+	if cond.Status != status || (reason != "" && cond.Reason != reason) {
+		t.Fatalf("condition %s = %s/%s, want %s/%s", conditionType, cond.Status, cond.Reason, status, reason)
+	}
+	return cond
+}
+
+// RequireNoCondition fails the test when conditions hold conditionType.
+func RequireNoCondition(t testing.TB, conditions []metav1.Condition, conditionType string) {
+	t.Helper()
+	if cond := meta.FindStatusCondition(conditions, conditionType); cond != nil {
+		t.Fatalf("expected no condition %s, got %+v", conditionType, *cond)
+	}
+}
+
+// RequireState fails the test unless the device is in state.
+func RequireState(t testing.TB, device *v1alpha1.GPUDevice, state v1alpha1.GPUDeviceState) {
+	t.Helper()
+	if device.Status.State != state {
+		t.Fatalf("device %s state = %q, want %q", device.Name, device.Status.State, state)
+	}
+}
+
+// RequireLabels fails the test unless obj carries every label of want; other labels are ignored. An empty
+// value in want requires the label to be absent.
+func RequireLabels(t testing.TB, obj metav1.Object, want map[string]string) {
+	t.Helper()
+	labels := obj.GetLabels()
+	for key, value := range want {
+		got, ok := labels[key]
+		switch {
+		case value == "" && ok:
+			t.Fatalf("%s: expected no label %s, got %q", obj.GetName(), key, got)
+		case value != "" && got != value:
+			t.Fatalf("%s: label %s = %q, want %q (labels %v)", obj.GetName(), key, got, value, labels)
+		}
+	}
+}
+
+// RequireSucceeded fails the test unless the chain ran without an error and returned want.
+func RequireSucceeded(t testing.TB, outcome Outcome, want reconcile.Result) {
+	t.Helper()
+	if outcome.Err != nil {
+		t.Fatalf("handler chain failed after %v: %v", outcome.Ran, outcome.Err)
+	}
+	if outcome.Result != want {
+		t.Fatalf("handler chain result = %+v, want %+v", outcome.Result, want)
+	}
+}
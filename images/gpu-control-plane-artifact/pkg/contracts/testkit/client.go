@@ -0,0 +1,63 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+// NewScheme returns a scheme with the core and gpu.deckhouse.io types.
+func NewScheme(t testing.TB) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	return scheme
+}
+
+// NewClientBuilder returns a fake client builder for scheme with the GPUDevice and GPUNodeState status
+// subresources and the GPUDevice field indexes of the inventory controller.
+func NewClientBuilder(scheme *runtime.Scheme) *fake.ClientBuilder {
+	builder := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}, &v1alpha1.GPUNodeState{})
+	for _, index := range []func() (client.Object, string, client.IndexerFunc){
+		indexer.IndexGPUDeviceByNode,
+		indexer.IndexGPUDeviceByInventoryID,
+		indexer.IndexGPUDeviceByUUID,
+	} {
+		obj, field, extract := index()
+		builder = builder.WithIndex(obj, field, extract)
+	}
+	return builder
+}
+
+// NewClient returns a fake client holding objs; see NewClientBuilder.
+func NewClient(t testing.TB, objs ...client.Object) client.Client {
+	t.Helper()
+	return NewClientBuilder(NewScheme(t)).WithObjects(objs...).Build()
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testkit helps writing and testing GPUDevice handlers for the inventory controller outside of its
+// internal packages.
+//
+// It offers three pieces that mirror what the controller does at runtime:
+//
+//   - DeviceBuilder and NodeStateBuilder build GPUDevice and GPUNodeState objects shaped like the ones the
+//     inventory controller writes (names, labels, status.nodeName, status.inventoryID, hardware facts);
+//   - Harness runs a handler chain through the same reconciler.BaseReconciler the controller uses, with the
+//     same ordering, result merging, settings.handlers semantics and error handling;
+//   - the Require* helpers assert on the resulting status, conditions and labels.
+//
+// NewClient returns a fake client with the status subresources and field indexes the controller registers,
+// for handlers that read other objects. Failure injection is left to controller-runtime's interceptor package.
+//
+// The controller's own device handler tests use this package, so a behavior change in the handler chain
+// shows up here first.
+package testkit
@@ -0,0 +1,58 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit_test
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/contracts/testkit"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// costCenterHandler tags large-memory devices with the cost center that pays for them.
+type costCenterHandler struct{}
+
+func (costCenterHandler) Name() string { return "cost-center" }
+
+func (costCenterHandler) HandleDevice(_ context.Context, device *v1alpha1.GPUDevice) (reconcile.Result, error) {
+	if device.Status.Hardware.MemoryMiB >= 40*1024 {
+		device.Labels["example.com/cost-center"] = "training"
+	}
+	return reconcile.Result{}, nil
+}
+
+func ExampleHarness() {
+	large := testkit.NewDevice("worker-a", 0).Build()
+	small := testkit.NewDevice("worker-a", 1).Product("Tesla T4", 15360).Build()
+
+	harness := testkit.NewHarness(costCenterHandler{})
+	for _, device := range []*v1alpha1.GPUDevice{large, small} {
+		outcome := harness.Run(context.Background(), device)
+		fmt.Printf("%s ran=%v err=%v cost-center=%q\n", device.Name, outcome.Ran, outcome.Err, device.Labels["example.com/cost-center"])
+	}
+
+	// settings.handlers.cost-center.enabled: false switches the handler off.
+	harness.Apply(map[string]moduleconfig.HandlerSettings{"cost-center": {Enabled: false}})
+	fmt.Println("disabled ran:", harness.Run(context.Background(), testkit.NewDevice("worker-a", 2).Build()).Ran)
+
+	// Output:
+	// worker-a-0-10de-2330 ran=[cost-center] err=<nil> cost-center="training"
+	// worker-a-1-10de-2330 ran=[cost-center] err=<nil> cost-center=""
+	// disabled ran: []
+}
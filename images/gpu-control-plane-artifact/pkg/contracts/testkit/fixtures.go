@@ -0,0 +1,186 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+const (
+	// DeviceNodeLabelKey and DeviceIndexLabelKey are the labels the inventory controller puts on every GPUDevice.
+	DeviceNodeLabelKey  = "gpu.deckhouse.io/node"
+	DeviceIndexLabelKey = "gpu.deckhouse.io/device-index"
+
+	defaultVendor    = "10de"
+	defaultDevice    = "2330"
+	defaultClass     = "0302"
+	defaultProduct   = "NVIDIA H100 80GB HBM3"
+	defaultMemoryMiB = 81559
+)
+
+// DeviceBuilder builds a GPUDevice as the inventory controller publishes it for an NVIDIA H100 unless told
+// otherwise.
+type DeviceBuilder struct {
+	device *v1alpha1.GPUDevice
+}
+
+// NewDevice starts a device at index on node. The name and inventory ID follow the controller's default
+// "<node>-<index>-<vendor>-<device>" format.
+func NewDevice(node string, index int) *DeviceBuilder {
+	idx := fmt.Sprint(index)
+	name := strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", node, idx, defaultVendor, defaultDevice))
+	return &DeviceBuilder{device: &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Generation: 1,
+			Labels: map[string]string{
+				DeviceNodeLabelKey:  node,
+				DeviceIndexLabelKey: idx,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node,
+				UID:        types.UID(node),
+			}},
+		},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName:    node,
+			InventoryID: name,
+			Managed:     true,
+			State:       v1alpha1.GPUDeviceStateDiscovered,
+			Hardware: v1alpha1.GPUDeviceHardware{
+				UUID:      fmt.Sprintf("GPU-%08d-0000-0000-0000-%012d", index, index),
+				Product:   defaultProduct,
+				MemoryMiB: defaultMemoryMiB,
+				PCI: v1alpha1.PCIAddress{
+					Vendor:  defaultVendor,
+					Device:  defaultDevice,
+					Class:   defaultClass,
+					Address: fmt.Sprintf("0000:%02x:00.0", 0x17+index),
+				},
+				ComputeCapability: "9.0",
+			},
+		},
+	}}
+}
+
+// Product sets the product name and memory size.
+func (b *DeviceBuilder) Product(product string, memoryMiB int32) *DeviceBuilder {
+	b.device.Status.Hardware.Product = product
+	b.device.Status.Hardware.MemoryMiB = memoryMiB
+	return b
+}
+
+// UUID sets the device UUID.
+func (b *DeviceBuilder) UUID(uuid string) *DeviceBuilder {
+	b.device.Status.Hardware.UUID = uuid
+	return b
+}
+
+// MIG marks the device MIG capable with the given profiles.
+func (b *DeviceBuilder) MIG(profiles ...string) *DeviceBuilder {
+	b.device.Status.Hardware.MIG = v1alpha1.GPUMIGConfig{Capable: true, ProfilesSupported: profiles}
+	return b
+}
+
+// State sets the lifecycle state.
+func (b *DeviceBuilder) State(state v1alpha1.GPUDeviceState) *DeviceBuilder {
+	b.device.Status.State = state
+	return b
+}
+
+// Unmanaged marks the device as excluded from management.
+func (b *DeviceBuilder) Unmanaged() *DeviceBuilder {
+	b.device.Status.Managed = false
+	return b
+}
+
+// Label sets a label.
+func (b *DeviceBuilder) Label(key, value string) *DeviceBuilder {
+	b.device.Labels[key] = value
+	return b
+}
+
+// Condition sets a status condition observed at the current generation.
+func (b *DeviceBuilder) Condition(conditionType string, status metav1.ConditionStatus, reason string) *DeviceBuilder {
+	setCondition(&b.device.Status.Conditions, conditionType, status, reason, b.device.Generation)
+	return b
+}
+
+// Build returns a copy of the device, so one builder can produce several variants.
+func (b *DeviceBuilder) Build() *v1alpha1.GPUDevice {
+	return b.device.DeepCopy()
+}
+
+// NodeStateBuilder builds the GPUNodeState the inventory controller keeps for a node.
+type NodeStateBuilder struct {
+	state *v1alpha1.GPUNodeState
+}
+
+// NewNodeState starts the GPUNodeState of node, owned by the Node as the controller creates it.
+func NewNodeState(node string) *NodeStateBuilder {
+	return &NodeStateBuilder{state: &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       node,
+			Generation: 1,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node,
+				UID:        types.UID(node),
+			}},
+		},
+		Spec: v1alpha1.GPUNodeStateSpec{NodeName: node},
+	}}
+}
+
+// Condition sets a status condition observed at the current generation.
+func (b *NodeStateBuilder) Condition(conditionType string, status metav1.ConditionStatus, reason string) *NodeStateBuilder {
+	setCondition(&b.state.Status.Conditions, conditionType, status, reason, b.state.Generation)
+	return b
+}
+
+// Build returns a copy of the node state.
+func (b *NodeStateBuilder) Build() *v1alpha1.GPUNodeState {
+	return b.state.DeepCopy()
+}
+
+// NewNode returns a Ready Node with the given labels, the owner of the objects above.
+func NewNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name), Labels: labels},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func setCondition(conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus, reason string, generation int64) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		ObservedGeneration: generation,
+	})
+}
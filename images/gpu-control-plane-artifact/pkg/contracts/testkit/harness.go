@@ -0,0 +1,109 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+// DeviceHandler is the contract of a handler the inventory controller runs on every GPUDevice of a node after
+// writing its hardware facts. Name is the key of the handler in ModuleConfig settings.handlers; a handler may
+// also implement reconciler.Configurable to receive its settings blob and reconciler.Finalizer.
+type DeviceHandler interface {
+	Name() string
+	HandleDevice(ctx context.Context, device *v1alpha1.GPUDevice) (reconcile.Result, error)
+}
+
+var _ reconciler.Named = DeviceHandler(nil)
+
+// Outcome is what a chain run left behind.
+type Outcome struct {
+	// Result is the merged reconcile.Result of the handlers that ran.
+	Result reconcile.Result
+	// Err is the chain error: failures stop the chain and are joined, classified errors from
+	// pkg/common/errors are returned after the remaining handlers ran, and reconciler.ErrStopHandlerChain
+	// ends the chain without an error.
+	Err error
+	// Ran lists the handlers that were called, in order.
+	Ran []string
+}
+
+// Harness runs a device handler chain the way the inventory controller does.
+type Harness struct {
+	handlers  []DeviceHandler
+	settings  map[string]moduleconfig.HandlerSettings
+	configure map[string]error
+}
+
+// NewHarness returns a harness running handlers in the given order, all enabled.
+func NewHarness(handlers ...DeviceHandler) *Harness {
+	return &Harness{handlers: handlers, configure: map[string]error{}}
+}
+
+// Apply sets settings.handlers the way the controller applies ModuleConfig: a handler listed with
+// enabled: false is skipped, and a reconciler.Configurable handler receives its blob (on every Apply, while
+// the controller skips unchanged blobs). Unlisted handlers run with no settings.
+func (h *Harness) Apply(settings map[string]moduleconfig.HandlerSettings) *Harness {
+	h.settings = settings
+	for _, handler := range h.handlers {
+		configurable, ok := handler.(reconciler.Configurable)
+		if !ok {
+			continue
+		}
+		if err := configurable.Configure(settings[handler.Name()].Settings); err != nil {
+			h.configure[handler.Name()] = err
+		} else {
+			delete(h.configure, handler.Name())
+		}
+	}
+	return h
+}
+
+// ConfigureErrors returns the handlers whose last Configure call failed, sorted by name. The controller
+// keeps running them and reports them on the HandlersConfigured condition of every GPUNodeState.
+func (h *Harness) ConfigureErrors() []string {
+	names := make([]string, 0, len(h.configure))
+	for name := range h.configure {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run passes device through the chain once. The device is changed in place, as the controller does before
+// writing its status.
+func (h *Harness) Run(ctx context.Context, device *v1alpha1.GPUDevice) Outcome {
+	var ran []string
+	rec := reconciler.NewBaseReconciler(h.handlers)
+	rec.SetHandlerFilter(func(name string) bool {
+		settings, ok := h.settings[name]
+		return !ok || settings.Enabled
+	})
+	rec.SetHandlerExecutor(func(ctx context.Context, handler DeviceHandler) (reconcile.Result, error) {
+		ran = append(ran, handler.Name())
+		return handler.HandleDevice(ctx, device)
+	})
+	rec.SetResourceUpdater(func(context.Context) error { return nil })
+
+	result, err := rec.Reconcile(ctx)
+	return Outcome{Result: result, Err: err, Ran: ran}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+type stubHandler struct {
+	name   string
+	result reconcile.Result
+	err    error
+	label  string
+}
+
+func (h stubHandler) Name() string { return h.name }
+
+func (h stubHandler) HandleDevice(_ context.Context, device *v1alpha1.GPUDevice) (reconcile.Result, error) {
+	if h.label != "" {
+		device.Labels[h.label] = h.name
+	}
+	return h.result, h.err
+}
+
+type configurableHandler struct {
+	stubHandler
+	threshold int
+}
+
+func (h *configurableHandler) Configure(settings json.RawMessage) error {
+	if len(settings) == 0 {
+		h.threshold = 0
+		return nil
+	}
+	var parsed struct {
+		Threshold int `json:"threshold"`
+	}
+	if err := json.Unmarshal(settings, &parsed); err != nil {
+		return err
+	}
+	if parsed.Threshold < 0 {
+		return fmt.Errorf("threshold must not be negative")
+	}
+	h.threshold = parsed.Threshold
+	return nil
+}
+
+func TestHarnessRunsHandlersInOrderAndMergesResults(t *testing.T) {
+	device := NewDevice("worker", 0).Build()
+	outcome := NewHarness(
+		stubHandler{name: "first", result: reconcile.Result{RequeueAfter: time.Minute}, label: "example.com/first"},
+		stubHandler{name: "second", result: reconcile.Result{RequeueAfter: 10 * time.Second}},
+	).Run(context.Background(), device)
+
+	if !slices.Equal(outcome.Ran, []string{"first", "second"}) {
+		t.Fatalf("unexpected order %v", outcome.Ran)
+	}
+	RequireSucceeded(t, outcome, reconcile.Result{RequeueAfter: 10 * time.Second})
+	RequireLabels(t, device, map[string]string{"example.com/first": "first", DeviceNodeLabelKey: "worker"})
+}
+
+func TestHarnessErrorSemantics(t *testing.T) {
+	boom := errors.New("boom")
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "gpudevices"}, "dev", errors.New("changed"))
+	deferred := fmt.Errorf("scrape: %w", commonerrors.ErrTelemetryUnavailable)
+	for _, tc := range []struct {
+		name    string
+		err     error
+		wantRan []string
+		wantErr error
+		requeue bool
+	}{
+		{name: "failure stops the chain", err: boom, wantRan: []string{"faulty"}, wantErr: boom},
+		{name: "stop ends the chain quietly", err: reconciler.ErrStopHandlerChain, wantRan: []string{"faulty"}},
+		{name: "conflict requeues", err: conflict, wantRan: []string{"faulty", "next"}, requeue: true},
+		{name: "classified error is deferred", err: deferred, wantRan: []string{"faulty", "next"}, wantErr: commonerrors.ErrTelemetryUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			outcome := NewHarness(stubHandler{name: "faulty", err: tc.err}, stubHandler{name: "next"}).
+				Run(context.Background(), NewDevice("worker", 0).Build())
+			if !slices.Equal(outcome.Ran, tc.wantRan) {
+				t.Fatalf("ran %v, want %v", outcome.Ran, tc.wantRan)
+			}
+			if tc.wantErr == nil && outcome.Err != nil || tc.wantErr != nil && !errors.Is(outcome.Err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", outcome.Err, tc.wantErr)
+			}
+			if tc.requeue != (outcome.Result.RequeueAfter > 0) {
+				t.Fatalf("unexpected result %+v", outcome.Result)
+			}
+		})
+	}
+}
+
+func TestHarnessApplySettings(t *testing.T) {
+	tuned := &configurableHandler{stubHandler: stubHandler{name: "tuned"}}
+	harness := NewHarness(stubHandler{name: "plain"}, tuned)
+
+	harness.Apply(map[string]moduleconfig.HandlerSettings{
+		"plain": {Enabled: false},
+		"tuned": {Enabled: true, Settings: json.RawMessage(`{"threshold":3}`)},
+	})
+	if outcome := harness.Run(context.Background(), NewDevice("worker", 0).Build()); !slices.Equal(outcome.Ran, []string{"tuned"}) {
+		t.Fatalf("expected the disabled handler to be skipped, ran %v", outcome.Ran)
+	}
+	if tuned.threshold != 3 || len(harness.ConfigureErrors()) != 0 {
+		t.Fatalf("expected the settings to be applied, got threshold=%d errors=%v", tuned.threshold, harness.ConfigureErrors())
+	}
+
+	harness.Apply(map[string]moduleconfig.HandlerSettings{"tuned": {Enabled: true, Settings: json.RawMessage(`{"threshold":-1}`)}})
+	if got := harness.ConfigureErrors(); !slices.Equal(got, []string{"tuned"}) {
+		t.Fatalf("expected a configure error for tuned, got %v", got)
+	}
+	// A handler with rejected settings keeps running, as in the controller.
+	if outcome := harness.Run(context.Background(), NewDevice("worker", 0).Build()); !slices.Equal(outcome.Ran, []string{"plain", "tuned"}) {
+		t.Fatalf("unexpected handlers %v", outcome.Ran)
+	}
+}
+
+func TestDeviceBuilder(t *testing.T) {
+	builder := NewDevice("Worker-A", 1).MIG("1g.10gb").Condition("ReadyForPooling", metav1.ConditionTrue, "Ready")
+	device := builder.Build()
+	if device.Name != "worker-a-1-10de-2330" || device.Status.InventoryID != device.Name || device.Status.NodeName != "Worker-A" {
+		t.Fatalf("unexpected identity %s/%s/%s", device.Name, device.Status.InventoryID, device.Status.NodeName)
+	}
+	if device.Status.Hardware.PCI.Address != "0000:18:00.0" || !device.Status.Hardware.MIG.Capable {
+		t.Fatalf("unexpected hardware %+v", device.Status.Hardware)
+	}
+	RequireState(t, device, v1alpha1.GPUDeviceStateDiscovered)
+	RequireCondition(t, device.Status.Conditions, "ReadyForPooling", metav1.ConditionTrue, "Ready")
+	RequireNoCondition(t, device.Status.Conditions, "Stale")
+
+	device.Labels["mutated"] = "true"
+	RequireLabels(t, builder.Build(), map[string]string{"mutated": ""})
+}
+
+func TestNewClientIndexesDevices(t *testing.T) {
+	device := NewDevice("worker", 0).Build()
+	c := NewClient(t, NewNode("worker", nil), NewNodeState("worker").Build(), device, NewDevice("other", 0).Build())
+
+	list := &v1alpha1.GPUDeviceList{}
+	if err := c.List(context.Background(), list, client.MatchingFields{indexer.GPUDeviceNodeField: "worker"}); err != nil {
+		t.Fatalf("list by node: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != device.Name {
+		t.Fatalf("unexpected devices %+v", list.Items)
+	}
+
+	device.Status.State = v1alpha1.GPUDeviceStateReady
+	if err := c.Status().Update(context.Background(), device); err != nil {
+		t.Fatalf("status update: %v", err)
+	}
+}
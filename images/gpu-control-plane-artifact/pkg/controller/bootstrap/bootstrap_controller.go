@@ -16,6 +16,7 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -26,7 +27,10 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
 	bshandler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/bootstrap/internal/handler"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/gfdextender"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
 )
 
@@ -36,11 +40,14 @@ func SetupController(
 	log logr.Logger,
 	cfg config.ControllerConfig,
 	store *moduleconfig.ModuleConfigStore,
+	guard *ownership.Guard,
 ) error {
 	baseLog := log.WithName("bootstrap")
+	deviceStateSync := bshandler.NewDeviceStateSyncHandler(baseLog.WithName("device-state-sync"))
+	deviceStateSync.SetRecorder(eventrecord.NewEventRecorderLogger(mgr, ControllerName))
 	handlers := []bshandler.Handler{
 		bshandler.WrapBootstrapHandler(bshandler.NewWorkloadStatusHandler(baseLog.WithName("workload-status"))),
-		bshandler.WrapBootstrapHandler(bshandler.NewDeviceStateSyncHandler(baseLog.WithName("device-state-sync"))),
+		bshandler.WrapBootstrapHandler(deviceStateSync),
 	}
 
 	workers := cfg.Workers
@@ -48,7 +55,7 @@ func SetupController(
 		workers = 1
 	}
 
-	r := New(baseLog, cfg, store, handlers)
+	r := New(baseLog, cfg, store, handlers, guard)
 
 	c, err := controller.New(ControllerName, mgr, controller.Options{
 		Reconciler:              r,
@@ -66,6 +73,11 @@ func SetupController(
 		return err
 	}
 
+	extender := NewGFDExtenderRunner(baseLog.WithName("gfd-extender"), mgr.GetClient(), store, gfdextender.DefaultsFromEnv(), guard)
+	if err := mgr.Add(extender); err != nil {
+		return fmt.Errorf("add gfd-extender runner: %w", err)
+	}
+
 	baseLog.Info("Initialized GPU bootstrap controller")
 	return nil
 }
@@ -41,7 +41,10 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/bootstrap/internal/state"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/bootstrap/internal/watcher"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	poolconfig "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/watchers"
 	bootmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/bootstrap"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/validation"
 )
@@ -86,9 +89,10 @@ type Reconciler struct {
 	store     *moduleconfig.ModuleConfigStore
 	handlers  []Handler
 	validator validation.Validator
+	guard     *ownership.Guard
 }
 
-func New(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []Handler) *Reconciler {
+func New(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []Handler, guard *ownership.Guard) *Reconciler {
 	if cfg.Workers <= 0 {
 		cfg.Workers = 1
 	}
@@ -97,6 +101,7 @@ func New(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.Modul
 		cfg:      cfg,
 		store:    store,
 		handlers: handlers,
+		guard:    guard,
 	}
 	return rec
 }
@@ -130,6 +135,7 @@ func (r *Reconciler) SetupController(ctx context.Context, mgr manager.Manager, c
 	for _, w := range []Watcher{
 		watcher.NewWorkloadPodWatcher(r.log.WithName("watcher.workloadPod")),
 		watcher.NewGPUDeviceWatcher(r.log.WithName("watcher.device")),
+		watchers.NewModulePauseWatcher(r.log.WithName("watcher.modulePause"), r.store, func() client.ObjectList { return &v1alpha1.GPUNodeStateList{} }),
 	} {
 		if err := w.Watch(mgr, ctr); err != nil {
 			return fmt.Errorf("failed to run watcher %s: %w", reflect.TypeOf(w).Elem().Name(), err)
@@ -160,6 +166,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	resource := reconciler.NewResource(
 		types.NamespacedName{Name: req.Name},
 		r.client,
+		r.guard,
 		func() *v1alpha1.GPUNodeState { return &v1alpha1.GPUNodeState{} },
 		func(obj *v1alpha1.GPUNodeState) v1alpha1.GPUNodeStateStatus { return obj.Status },
 	)
@@ -180,6 +187,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// While paused only the ModulePaused condition is maintained; metrics keep their last values.
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping bootstrap reconciliation")
+		reconciler.MarkModulePaused(inventory)
+		return ctrl.Result{}, resource.Update(ctx)
+	}
+	reconciler.ClearModulePaused(inventory)
+
 	if r.validator == nil {
 		r.validator = validation.NewValidator(r.client, r.validatorConfig())
 	}
@@ -229,6 +244,7 @@ func (r *Reconciler) validatorConfig() validation.Config {
 		GFDApp:             common.AppName(common.ComponentGPUFeatureDiscovery),
 		DCGMApp:            common.AppName(common.ComponentDCGM),
 		DCGMExporterApp:    common.AppName(common.ComponentDCGMExporter),
+		DriverInstallType:  poolconfig.DefaultsFromEnv().DriverInstallType,
 	}
 	return cfg
 }
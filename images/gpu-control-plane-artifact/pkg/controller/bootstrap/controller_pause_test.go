@@ -0,0 +1,125 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	bshandler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/bootstrap/internal/handler"
+	moduleconfig "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+func TestReconcilePausedDoesNotWriteAfterMarking(t *testing.T) {
+	scheme := newScheme(t)
+	inventory := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node", Generation: 1}}
+	writes := 0
+	countWrite := func() { writes++ }
+	cl := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(inventory).
+		WithStatusSubresource(&v1alpha1.GPUNodeState{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				countWrite()
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				countWrite()
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, sub string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				countWrite()
+				return c.SubResource(sub).Update(ctx, obj, opts...)
+			},
+			SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				countWrite()
+				return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	paused := moduleconfig.DefaultState()
+	paused.Enabled = true
+	paused.Paused = true
+	store := moduleconfig.NewModuleConfigStore(paused)
+
+	handler := &stubBootstrapHandler{name: "paused"}
+	rec := New(testr.New(t), config.ControllerConfig{}, store, []Handler{bshandler.WrapBootstrapHandler(handler)}, nil)
+	rec.client = cl
+	rec.validator = &stubValidator{}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}}
+
+	res, err := rec.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Requeue || res.RequeueAfter != 0 {
+		t.Fatalf("expected paused reconcile not to requeue, got %+v", res)
+	}
+	if writes == 0 {
+		t.Fatalf("expected the first paused reconcile to record ModulePaused")
+	}
+
+	// Further reconciles while paused, including after a spec edit, must not write.
+	stored := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(context.Background(), req.NamespacedName, stored); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	stored.Spec.NodeName = "node"
+	if err := cl.Update(context.Background(), stored); err != nil {
+		t.Fatalf("update inventory: %v", err)
+	}
+	writes = 0
+	for i := 0; i < 3; i++ {
+		if _, err := rec.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if writes != 0 {
+		t.Fatalf("expected zero writes while paused, got %d", writes)
+	}
+	if handler.calls != 0 || rec.validator.(*stubValidator).statusCalls != 0 {
+		t.Fatalf("expected handlers and validator to be skipped, got handler=%d validator=%d", handler.calls, rec.validator.(*stubValidator).statusCalls)
+	}
+
+	resumed := paused.Clone()
+	resumed.Paused = false
+	store.Update(resumed)
+	if _, err := rec.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handler.calls != 1 {
+		t.Fatalf("expected handlers to run after resume, got %d", handler.calls)
+	}
+	if err := cl.Get(context.Background(), req.NamespacedName, stored); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if apimeta.FindStatusCondition(stored.Status.Conditions, reconciler.ConditionModulePaused) != nil {
+		t.Fatalf("expected ModulePaused condition cleared after resume, got %+v", stored.Status.Conditions)
+	}
+}
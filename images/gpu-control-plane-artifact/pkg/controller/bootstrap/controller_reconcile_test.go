@@ -45,7 +45,7 @@ func TestReconcileAggregatesResults(t *testing.T) {
 	handlerA := &stubBootstrapHandler{name: "a", result: reconcile.Result{Requeue: true}}
 	handlerB := &stubBootstrapHandler{name: "b", result: reconcile.Result{RequeueAfter: time.Second}}
 
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(handlerA), bshandler.WrapBootstrapHandler(handlerB)})
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(handlerA), bshandler.WrapBootstrapHandler(handlerB)}, nil)
 	rec.client = client
 
 	res, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}})
@@ -67,7 +67,7 @@ func TestReconcileHandlerError(t *testing.T) {
 
 	handlerName := "boom-" + t.Name()
 	handler := &stubBootstrapHandler{name: handlerName, err: errors.New("handler fail")}
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(handler)})
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(handler)}, nil)
 	rec.client = client
 
 	if _, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}}); err == nil {
@@ -87,7 +87,7 @@ func TestReconcileSkipsWhenModuleDisabled(t *testing.T) {
 	client := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(inventory).Build()
 	store := moduleconfig.NewModuleConfigStore(moduleconfig.State{Enabled: false, Settings: moduleconfig.DefaultState().Settings})
 
-	rec := New(testr.New(t), config.ControllerConfig{}, store, nil)
+	rec := New(testr.New(t), config.ControllerConfig{}, store, nil, nil)
 	rec.client = client
 	rec.validator = &stubValidator{}
 
@@ -102,7 +102,7 @@ func TestReconcileSkipsWhenModuleDisabled(t *testing.T) {
 }
 
 func TestReconcileGetError(t *testing.T) {
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = &failingClient{err: errors.New("get fail")}
 
 	if _, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}}); err == nil {
@@ -114,7 +114,7 @@ func TestReconcileNotFound(t *testing.T) {
 	scheme := newScheme(t)
 	client := clientfake.NewClientBuilder().WithScheme(scheme).Build()
 
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = client
 
 	if _, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "missing"}}); err != nil {
@@ -127,7 +127,7 @@ func TestReconcileNoHandlers(t *testing.T) {
 	node := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
 	client := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
 
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = client
 
 	res, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}})
@@ -148,7 +148,7 @@ func TestReconcilePersistsStatusChanges(t *testing.T) {
 		WithStatusSubresource(&v1alpha1.GPUNodeState{}).
 		Build()
 
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(statusChangingHandler{})})
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(statusChangingHandler{})}, nil)
 	rec.client = client
 
 	if _, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}}); err != nil {
@@ -173,7 +173,7 @@ func TestReconcileLogsValidatorStatusErrorsAndUsesSpecNodeName(t *testing.T) {
 	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(inventory).Build()
 
 	validator := &capturingValidator{err: errors.New("validator status failed")}
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = cl
 	rec.validator = validator
 
@@ -195,7 +195,7 @@ func TestReconcilePassesValidatorStatusToHandlers(t *testing.T) {
 	validator := &capturingValidator{result: validation.Result{DriverReady: true}}
 	handler := &statusReadingHandler{}
 
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(handler)})
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, []Handler{bshandler.WrapBootstrapHandler(handler)}, nil)
 	rec.client = cl
 	rec.validator = validator
 
@@ -208,7 +208,7 @@ func TestReconcilePassesValidatorStatusToHandlers(t *testing.T) {
 }
 
 func TestReconcileWrapsAPIError(t *testing.T) {
-	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := New(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = &failingClient{err: apierrors.NewConflict(schema.GroupResource{Group: v1alpha1.GroupVersion.Group, Resource: "gpunodestates"}, "node", errors.New("boom"))}
 
 	if _, err := rec.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "node"}}); err == nil {
@@ -0,0 +1,88 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/gfdextender"
+)
+
+// gfdExtenderInterval bounds how long a drifted or deleted gfd-extender DaemonSet stays that way.
+const gfdExtenderInterval = 30 * time.Second
+
+// GFDExtenderRunner keeps the gfd-extender DaemonSet rendered while this replica is the leader.
+type GFDExtenderRunner struct {
+	log      logr.Logger
+	client   client.Client
+	store    *moduleconfig.ModuleConfigStore
+	cfg      gfdextender.Config
+	guard    *ownership.Guard
+	interval time.Duration
+}
+
+// NewGFDExtenderRunner builds the runner; cfg normally comes from gfdextender.DefaultsFromEnv.
+func NewGFDExtenderRunner(log logr.Logger, c client.Client, store *moduleconfig.ModuleConfigStore, cfg gfdextender.Config, guard *ownership.Guard) *GFDExtenderRunner {
+	return &GFDExtenderRunner{
+		log:      log,
+		client:   c,
+		store:    store,
+		cfg:      cfg,
+		guard:    guard,
+		interval: gfdExtenderInterval,
+	}
+}
+
+// NeedLeaderElection keeps a single writer of the DaemonSet.
+func (r *GFDExtenderRunner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start reconciles the DaemonSet until the context is cancelled.
+func (r *GFDExtenderRunner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RunOnce(ctx); err != nil {
+			r.log.Error(err, "failed to reconcile gfd-extender DaemonSet")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce renders or removes the DaemonSet for the current module state; a paused module is left as is. Without a
+// store the module counts as enabled, as it does for the bootstrap reconciler.
+func (r *GFDExtenderRunner) RunOnce(ctx context.Context) error {
+	state := moduleconfig.DefaultState()
+	state.Enabled = true
+	if r.store != nil {
+		state = r.store.Current()
+	}
+	if state.Paused {
+		return nil
+	}
+	return gfdextender.Reconcile(ctx, r.client, r.guard, r.cfg, state)
+}
@@ -17,6 +17,7 @@ package handler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,12 +27,16 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/devicestate"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
 // DeviceStateSyncHandler keeps GPUDevice.state in sync with bootstrap readiness.
 type DeviceStateSyncHandler struct {
-	log    logr.Logger
-	client client.Client
+	log      logr.Logger
+	client   client.Client
+	recorder eventrecord.EventRecorderLogger
 }
 
 // NewDeviceStateSyncHandler creates handler that updates device states on the node.
@@ -44,6 +49,11 @@ func (h *DeviceStateSyncHandler) SetClient(cl client.Client) {
 	h.client = cl
 }
 
+// SetRecorder enables Events for the device state transitions made by the handler.
+func (h *DeviceStateSyncHandler) SetRecorder(recorder eventrecord.EventRecorderLogger) {
+	h.recorder = recorder
+}
+
 func (h *DeviceStateSyncHandler) Name() string {
 	return "device-state-sync"
 }
@@ -84,7 +94,11 @@ func (h *DeviceStateSyncHandler) HandleNode(ctx context.Context, inventory *v1al
 		}
 
 		original := device.DeepCopy()
-		device.Status.State = target
+		transition := devicestate.SetState(device, target, bootstrapReason(target), time.Now())
+		if err := objsize.Guard(device); err != nil {
+			errs = append(errs, err)
+			continue
+		}
 		patch := client.MergeFrom(original)
 		if original.GetResourceVersion() != "" {
 			patch = client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
@@ -93,6 +107,7 @@ func (h *DeviceStateSyncHandler) HandleNode(ctx context.Context, inventory *v1al
 			errs = append(errs, fmt.Errorf("patch device %s: %w", device.Name, err))
 			continue
 		}
+		devicestate.Notify(h.recorder, h.log, device, transition)
 		h.log.V(1).Info("updated device state", "device", device.Name, "node", nodeName, "state", target)
 	}
 
@@ -109,8 +124,12 @@ func isConditionTrue(inventory *v1alpha1.GPUNodeState, condType string) bool {
 }
 
 func desiredDeviceState(device *v1alpha1.GPUDevice, driverAndToolkitReady, infraReady bool) (v1alpha1.GPUDeviceState, bool) {
-	state := normalizeDeviceState(device.Status.State)
 	current := device.Status.State
+	if devicestate.Foreign(current) {
+		// Written by a newer controller: the state is terminal for this version.
+		return current, false
+	}
+	state := normalizeDeviceState(current)
 
 	switch state {
 	case v1alpha1.GPUDeviceStateAssigned,
@@ -143,6 +162,18 @@ func desiredDeviceState(device *v1alpha1.GPUDevice, driverAndToolkitReady, infra
 	}
 }
 
+// bootstrapReason names the node readiness that drove the device into the target state.
+func bootstrapReason(target v1alpha1.GPUDeviceState) string {
+	switch target {
+	case v1alpha1.GPUDeviceStateReady:
+		return "InfrastructureReady"
+	case v1alpha1.GPUDeviceStateValidating:
+		return "DriverAndToolkitReady"
+	default:
+		return "BootstrapSync"
+	}
+}
+
 func normalizeDeviceState(state v1alpha1.GPUDeviceState) v1alpha1.GPUDeviceState {
 	if state == "" {
 		return v1alpha1.GPUDeviceStateDiscovered
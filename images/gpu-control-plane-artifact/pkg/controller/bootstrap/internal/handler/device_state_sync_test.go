@@ -24,13 +24,24 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
 func newDeviceClient(t *testing.T, objs ...runtime.Object) client.Client {
 	t.Helper()
 	scheme := runtime.NewScheme()
@@ -269,6 +280,37 @@ func TestDeviceStateSyncHandlerKeepsAssignedStates(t *testing.T) {
 	}
 }
 
+func TestDeviceStateSyncHandlerLeavesForeignStateUntouched(t *testing.T) {
+	device := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-dev"},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: "node-a", State: "Quarantined"},
+	}
+	base := newDeviceClient(t, device)
+	patches := 0
+	client := interceptor.NewClient(base.(client.WithWatch), interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			patches++
+			return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+		},
+	})
+	handler := NewDeviceStateSyncHandler(testr.New(t))
+	handler.SetClient(client)
+
+	if _, err := handler.HandleNode(context.Background(), inventoryWithInfraReady("node-a")); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if patches != 0 {
+		t.Fatalf("expected no status writes for a foreign state, got %d", patches)
+	}
+	updated := &v1alpha1.GPUDevice{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: "gpu-dev"}, updated); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if updated.Status.State != "Quarantined" || len(updated.Status.History) != 0 {
+		t.Fatalf("expected foreign state to remain, got %+v", updated.Status)
+	}
+}
+
 func TestDeviceStateSyncHandlerMovesDiscoveredToReadyWhenInfraReady(t *testing.T) {
 	device := &v1alpha1.GPUDevice{
 		ObjectMeta: metav1.ObjectMeta{Name: "gpu-dev"},
@@ -299,6 +341,8 @@ func TestDeviceStateSyncHandlerRecoversFaultedWhenInfraStable(t *testing.T) {
 	client := newDeviceClient(t, device)
 	handler := NewDeviceStateSyncHandler(testr.New(t))
 	handler.SetClient(client)
+	events := record.NewFakeRecorder(4)
+	handler.SetRecorder(eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: events}, "test"))
 
 	inventory := inventoryWithInfraReady("node-a")
 	for i := 0; i < 2; i++ {
@@ -313,6 +357,15 @@ func TestDeviceStateSyncHandlerRecoversFaultedWhenInfraStable(t *testing.T) {
 	if updated.Status.State != v1alpha1.GPUDeviceStateReady {
 		t.Fatalf("expected Ready, got %s", updated.Status.State)
 	}
+	history := updated.Status.History
+	if len(history) != 2 ||
+		history[0].From != v1alpha1.GPUDeviceStateFaulted || history[0].To != v1alpha1.GPUDeviceStateValidating ||
+		history[1].To != v1alpha1.GPUDeviceStateReady || history[1].Reason != "InfrastructureReady" {
+		t.Fatalf("unexpected history %+v", history)
+	}
+	if len(events.Events) != 2 {
+		t.Fatalf("expected an event per transition, got %d", len(events.Events))
+	}
 }
 
 func TestDeviceStateSyncHandlerDoesNotFaultReadyWhenDriverMissing(t *testing.T) {
@@ -429,6 +482,7 @@ func TestDesiredDeviceState(t *testing.T) {
 		{"empty-state-normalizes", newDevice(""), false, false, v1alpha1.GPUDeviceStateDiscovered, true},
 		{"empty-state-promotes-to-validating", newDevice(""), true, false, v1alpha1.GPUDeviceStateValidating, true},
 		{"empty-state-promotes-to-ready", newDevice(""), true, true, v1alpha1.GPUDeviceStateReady, true},
+		{"foreign-state-is-terminal", newDevice("Quarantined"), true, true, "Quarantined", false},
 	}
 
 	for _, tt := range tests {
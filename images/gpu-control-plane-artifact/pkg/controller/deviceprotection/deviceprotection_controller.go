@@ -0,0 +1,73 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deviceprotection keeps GPUDevices that a pool has reserved or attached from being deleted: it manages
+// the in-use-protection finalizer and reports blocked deletions on the device.
+package deviceprotection
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
+)
+
+const ControllerName = "gpu-device-protection-controller"
+
+func SetupController(
+	ctx context.Context,
+	mgr ctrl.Manager,
+	log logr.Logger,
+	cfg config.ControllerConfig,
+	store *moduleconfig.ModuleConfigStore,
+	guard *ownership.Guard,
+) error {
+	baseLog := log.WithName("device-protection")
+	r := New(baseLog, store, guard)
+	r.recorder = eventrecord.NewEventRecorderLogger(mgr, ControllerName)
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: workers,
+		RecoverPanic:            ptr.To(true),
+		LogConstructor:          logger.NewConstructor(baseLog),
+		CacheSyncTimeout:        10 * time.Minute,
+		NewQueue:                reconciler.NewNamedQueue(reconciler.UsePriorityQueue()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := r.SetupController(ctx, mgr, c); err != nil {
+		return err
+	}
+
+	baseLog.Info("Initialized GPU device protection controller")
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceprotection
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/canonical"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/inuse"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+// Reconciler keeps the in-use-protection finalizer of one GPUDevice in step with its state.
+type Reconciler struct {
+	client   client.Client
+	log      logr.Logger
+	store    *moduleconfig.ModuleConfigStore
+	recorder eventrecord.EventRecorderLogger
+	guard    *ownership.Guard
+}
+
+// New builds the reconciler; a nil guard lets it patch every device.
+func New(log logr.Logger, store *moduleconfig.ModuleConfigStore, guard *ownership.Guard) *Reconciler {
+	return &Reconciler{log: log, store: store, guard: guard}
+}
+
+var _ reconcile.Reconciler = (*Reconciler)(nil)
+
+func (r *Reconciler) SetupController(_ context.Context, mgr manager.Manager, ctr controller.Controller) error {
+	r.client = mgr.GetClient()
+
+	c := mgr.GetCache()
+	if c == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+
+	if err := ctr.Watch(
+		source.Kind(c, &v1alpha1.GPUDevice{}, &handler.TypedEnqueueRequestForObject[*v1alpha1.GPUDevice]{}, devicePredicates()),
+	); err != nil {
+		return fmt.Errorf("error setting watch on GPUDevice: %w", err)
+	}
+	return nil
+}
+
+func devicePredicates() predicate.TypedPredicate[*v1alpha1.GPUDevice] {
+	return predicate.TypedFuncs[*v1alpha1.GPUDevice]{
+		CreateFunc:  func(event.TypedCreateEvent[*v1alpha1.GPUDevice]) bool { return true },
+		DeleteFunc:  func(event.TypedDeleteEvent[*v1alpha1.GPUDevice]) bool { return false },
+		GenericFunc: func(event.TypedGenericEvent[*v1alpha1.GPUDevice]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[*v1alpha1.GPUDevice]) bool {
+			oldDev, newDev := e.ObjectOld, e.ObjectNew
+			if oldDev == nil || newDev == nil {
+				return true
+			}
+			return oldDev.Status.State != newDev.Status.State ||
+				(oldDev.DeletionTimestamp == nil) != (newDev.DeletionTimestamp == nil) ||
+				inuse.Forced(oldDev) != inuse.Forced(newDev) ||
+				controllerutil.ContainsFinalizer(oldDev, inuse.FinalizerName) != controllerutil.ContainsFinalizer(newDev, inuse.FinalizerName)
+		},
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx).WithValues("device", req.Name)
+	ctx = logr.NewContext(ctx, log)
+
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping device protection")
+		return ctrl.Result{}, nil
+	}
+
+	device, err := commonobject.FetchObject(ctx, req.NamespacedName, r.client, &v1alpha1.GPUDevice{})
+	if err != nil || device == nil {
+		return ctrl.Result{}, err
+	}
+	if _, tooNew := reconciler.SchemaTooNew(device); tooNew {
+		return ctrl.Result{}, nil
+	}
+	if allowed, err := r.guard.MayWrite(ctx, device); err != nil || !allowed {
+		return ctrl.Result{}, err
+	}
+
+	// Without the module nothing would ever release the device, so a disabled module drops the protection.
+	disabled := r.store != nil && !r.store.Current().Enabled
+	original := device.DeepCopy()
+	changed := inuse.SyncFinalizer(device)
+	if disabled {
+		changed = controllerutil.RemoveFinalizer(device, inuse.FinalizerName) || changed
+	}
+	if changed {
+		if err := r.client.Patch(ctx, device, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		released := !controllerutil.ContainsFinalizer(device, inuse.FinalizerName)
+		if released && inuse.Forced(device) && inuse.InUse(device) {
+			r.event(log, device, corev1.EventTypeWarning, inuse.EventForceDeleted,
+				"in-use protection lifted through %s while the device is %s", inuse.ForceDeleteAnnotation, device.Status.State)
+		}
+		if released && device.DeletionTimestamp != nil {
+			// The API server removes the device now; there is no status left to report on.
+			return ctrl.Result{}, nil
+		}
+	}
+
+	return ctrl.Result{}, r.syncDeletionBlocked(ctx, log, device)
+}
+
+// syncDeletionBlocked keeps DeletionBlocked on a device that was deleted while protected and records an event the
+// first time the deletion is blocked.
+func (r *Reconciler) syncDeletionBlocked(ctx context.Context, log logr.Logger, device *v1alpha1.GPUDevice) error {
+	blocked := device.DeletionTimestamp != nil && controllerutil.ContainsFinalizer(device, inuse.FinalizerName)
+	before := device.DeepCopy()
+	previous := apimeta.FindStatusCondition(before.Status.Conditions, inuse.ConditionDeletionBlocked)
+	if blocked {
+		conditions.SetCondition(
+			conditions.NewConditionBuilder(conditions.ConditionType(inuse.ConditionDeletionBlocked)).
+				Status(metav1.ConditionTrue).
+				Reason(conditions.CommonReason(inuse.ReasonDeviceInUse)).
+				Message(inuse.Describe(device)).
+				Generation(device.Generation),
+			&device.Status.Conditions,
+		)
+	} else {
+		apimeta.RemoveStatusCondition(&device.Status.Conditions, inuse.ConditionDeletionBlocked)
+	}
+	if canonical.ConditionsEqual(before.Status.Conditions, device.Status.Conditions) {
+		return nil
+	}
+	if err := objsize.Guard(device); err != nil {
+		return err
+	}
+	if err := r.client.Status().Patch(ctx, device, client.MergeFrom(before)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if blocked && (previous == nil || previous.Status != metav1.ConditionTrue) {
+		r.event(log, device, corev1.EventTypeWarning, inuse.EventDeletionBlocked, "GPU device deletion blocked: %s", inuse.Describe(device))
+	}
+	return nil
+}
+
+func (r *Reconciler) event(log logr.Logger, device *v1alpha1.GPUDevice, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.WithLogging(log).Eventf(device, eventType, reason, messageFmt, args...)
+}
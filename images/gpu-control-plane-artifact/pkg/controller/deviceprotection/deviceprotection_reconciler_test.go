@@ -0,0 +1,191 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceprotection
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/inuse"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
+func newTestReconciler(t *testing.T, enabled bool, objs ...client.Object) (*Reconciler, client.Client, *record.FakeRecorder) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}).
+		Build()
+
+	rec := record.NewFakeRecorder(10)
+	r := New(testr.New(t), moduleconfig.NewModuleConfigStore(moduleconfig.State{Enabled: enabled}), nil)
+	r.client = cl
+	r.recorder = eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: rec}, "test")
+	return r, cl, rec
+}
+
+func reconcileDevice(t *testing.T, r *Reconciler, name string) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+}
+
+func fetchDevice(t *testing.T, cl client.Client, name string) *v1alpha1.GPUDevice {
+	t.Helper()
+	device := &v1alpha1.GPUDevice{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: name}, device); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	return device
+}
+
+func expectEvent(t *testing.T, rec *record.FakeRecorder, reason string) {
+	t.Helper()
+	select {
+	case event := <-rec.Events:
+		if !strings.Contains(event, reason) {
+			t.Fatalf("expected %q event, got %q", reason, event)
+		}
+	default:
+		t.Fatalf("expected %q event to be recorded", reason)
+	}
+}
+
+func assignedDevice(name string) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1alpha1.GPUDeviceStatus{
+			State:   v1alpha1.GPUDeviceStateAssigned,
+			PoolRef: &v1alpha1.GPUPoolReference{Name: "pool", Namespace: "team"},
+		},
+	}
+}
+
+func TestReconcileBlocksDeletionUntilReleased(t *testing.T) {
+	ctx := context.Background()
+	r, cl, rec := newTestReconciler(t, true, assignedDevice("gpu-0"))
+
+	reconcileDevice(t, r, "gpu-0")
+	device := fetchDevice(t, cl, "gpu-0")
+	if !controllerutil.ContainsFinalizer(device, inuse.FinalizerName) {
+		t.Fatalf("expected finalizer on an assigned device, got %v", device.Finalizers)
+	}
+
+	if err := cl.Delete(ctx, device); err != nil {
+		t.Fatalf("delete device: %v", err)
+	}
+	reconcileDevice(t, r, "gpu-0")
+	device = fetchDevice(t, cl, "gpu-0")
+	blocked := apimeta.FindStatusCondition(device.Status.Conditions, inuse.ConditionDeletionBlocked)
+	if blocked == nil || blocked.Status != metav1.ConditionTrue || blocked.Reason != inuse.ReasonDeviceInUse || !strings.Contains(blocked.Message, "team/pool") {
+		t.Fatalf("unexpected DeletionBlocked condition: %+v", blocked)
+	}
+	expectEvent(t, rec, inuse.EventDeletionBlocked)
+
+	// A repeated reconcile keeps the condition and does not record the event again.
+	reconcileDevice(t, r, "gpu-0")
+	if len(rec.Events) != 0 {
+		t.Fatalf("expected no further events, got %d", len(rec.Events))
+	}
+
+	device.Status.State = v1alpha1.GPUDeviceStateReady
+	if err := cl.Status().Update(ctx, device); err != nil {
+		t.Fatalf("release device: %v", err)
+	}
+	reconcileDevice(t, r, "gpu-0")
+	if err := cl.Get(ctx, types.NamespacedName{Name: "gpu-0"}, &v1alpha1.GPUDevice{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected released device to be deleted, got err=%v", err)
+	}
+}
+
+func TestReconcileForceDeleteLiftsProtection(t *testing.T) {
+	ctx := context.Background()
+	r, cl, rec := newTestReconciler(t, true, assignedDevice("gpu-0"))
+
+	reconcileDevice(t, r, "gpu-0")
+	device := fetchDevice(t, cl, "gpu-0")
+	device.Annotations = map[string]string{inuse.ForceDeleteAnnotation: "true"}
+	if err := cl.Update(ctx, device); err != nil {
+		t.Fatalf("annotate device: %v", err)
+	}
+	if err := cl.Delete(ctx, device); err != nil {
+		t.Fatalf("delete device: %v", err)
+	}
+
+	reconcileDevice(t, r, "gpu-0")
+	if err := cl.Get(ctx, types.NamespacedName{Name: "gpu-0"}, &v1alpha1.GPUDevice{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected forced device to be deleted, got err=%v", err)
+	}
+	expectEvent(t, rec, inuse.EventForceDeleted)
+}
+
+func TestReconcileLeavesReleasedDevicesUnprotected(t *testing.T) {
+	device := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-0"},
+		Status:     v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateReady},
+	}
+	r, cl, _ := newTestReconciler(t, true, device)
+
+	reconcileDevice(t, r, "gpu-0")
+	if got := fetchDevice(t, cl, "gpu-0"); controllerutil.ContainsFinalizer(got, inuse.FinalizerName) {
+		t.Fatalf("expected no finalizer on a ready device")
+	}
+}
+
+func TestReconcileDisabledModuleRemovesFinalizer(t *testing.T) {
+	device := assignedDevice("gpu-0")
+	device.Finalizers = []string{inuse.FinalizerName}
+	r, cl, _ := newTestReconciler(t, false, device)
+
+	reconcileDevice(t, r, "gpu-0")
+	if got := fetchDevice(t, cl, "gpu-0"); controllerutil.ContainsFinalizer(got, inuse.FinalizerName) {
+		t.Fatalf("expected a disabled module to drop the finalizer")
+	}
+}
+
+func TestReconcileIgnoresMissingDevice(t *testing.T) {
+	r, _, _ := newTestReconciler(t, true)
+	reconcileDevice(t, r, "missing")
+}
@@ -33,6 +33,26 @@ func IndexGPUDeviceByNode() (obj client.Object, field string, extractValue clien
 	}
 }
 
+func IndexGPUDeviceByInventoryID() (obj client.Object, field string, extractValue client.IndexerFunc) {
+	return &v1alpha1.GPUDevice{}, GPUDeviceInventoryIDField, func(object client.Object) []string {
+		dev, ok := object.(*v1alpha1.GPUDevice)
+		if !ok || dev.Status.InventoryID == "" {
+			return nil
+		}
+		return []string{dev.Status.InventoryID}
+	}
+}
+
+func IndexGPUDeviceByUUID() (obj client.Object, field string, extractValue client.IndexerFunc) {
+	return &v1alpha1.GPUDevice{}, GPUDeviceUUIDField, func(object client.Object) []string {
+		dev, ok := object.(*v1alpha1.GPUDevice)
+		if !ok || dev.Status.Hardware.UUID == "" {
+			return nil
+		}
+		return []string{dev.Status.Hardware.UUID}
+	}
+}
+
 func IndexGPUDeviceByPoolRefName() (obj client.Object, field string, extractValue client.IndexerFunc) {
 	return &v1alpha1.GPUDevice{}, GPUDevicePoolRefNameField, func(object client.Object) []string {
 		dev, ok := object.(*v1alpha1.GPUDevice)
@@ -25,6 +25,10 @@ import (
 const (
 	// GPUDeviceNodeField indexes GPUDevice by status.nodeName for fast lookups.
 	GPUDeviceNodeField = "status.nodeName"
+	// GPUDeviceInventoryIDField indexes GPUDevice by status.inventoryID to find a device regardless of its name.
+	GPUDeviceInventoryIDField = "status.inventoryID"
+	// GPUDeviceUUIDField indexes GPUDevice by status.hardware.uuid to resolve explicit pool device references.
+	GPUDeviceUUIDField = "status.hardware.uuid"
 	// GPUDevicePoolRefNameField indexes GPUDevice by status.poolRef.name for pool-specific queries.
 	GPUDevicePoolRefNameField = "status.poolRef.name"
 	// GPUDeviceNamespacedAssignmentField indexes GPUDevice by gpu.deckhouse.io/assignment annotation value.
@@ -33,17 +37,25 @@ const (
 	GPUDeviceClusterAssignmentField = "metadata.annotations." + commonannotations.ClusterGPUDeviceAssignment
 	// GPUPoolNameField indexes namespaced GPUPools by metadata.name (cluster-unique by policy).
 	GPUPoolNameField = "metadata.name"
+	// GPUPoolResourceNameField indexes namespaced GPUPools by the extended resource name their device plugin advertises.
+	GPUPoolResourceNameField = "gpu.deckhouse.io/resourceName"
 	// NodeTaintKeyField indexes Nodes by spec.taints.key for taint cleanup operations.
 	NodeTaintKeyField = "spec.taints.key"
+	// PodGPUResourceField indexes Pods by the GPU pool extended resources requested by their containers.
+	PodGPUResourceField = "spec.containers.resources.gpu"
 )
 
 type IndexGetter func() (obj client.Object, field string, extractValue client.IndexerFunc)
 
 var IndexGetters = []IndexGetter{
 	IndexGPUDeviceByNode,
+	IndexGPUDeviceByInventoryID,
+	IndexGPUDeviceByUUID,
 	IndexGPUDeviceByPoolRefName,
 	IndexGPUDeviceByNamespacedAssignment,
 	IndexGPUDeviceByClusterAssignment,
 	IndexGPUPoolByName,
+	IndexGPUPoolByResourceName,
 	IndexNodeByTaintKey,
+	IndexPodByGPUResource,
 }
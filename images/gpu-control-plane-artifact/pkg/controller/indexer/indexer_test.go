@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
@@ -53,6 +54,29 @@ func TestIndexGPUDeviceByNode(t *testing.T) {
 	}
 }
 
+func TestIndexGPUDeviceByInventoryID(t *testing.T) {
+	obj, field, extractor := IndexGPUDeviceByInventoryID()
+	if _, ok := obj.(*v1alpha1.GPUDevice); !ok {
+		t.Fatalf("expected GPUDevice object, got %T", obj)
+	}
+	if field != GPUDeviceInventoryIDField {
+		t.Fatalf("expected field %s, got %s", GPUDeviceInventoryIDField, field)
+	}
+
+	device := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{InventoryID: "node-a-0000:17:00.0"}}
+	if got := extractor(device); len(got) != 1 || got[0] != "node-a-0000:17:00.0" {
+		t.Fatalf("expected inventoryID indexed, got %+v", got)
+	}
+
+	device.Status.InventoryID = ""
+	if got := extractor(device); got != nil {
+		t.Fatalf("expected nil for empty inventoryID, got %+v", got)
+	}
+	if got := extractor(&corev1.Pod{}); got != nil {
+		t.Fatalf("expected nil for non-GPUDevice object, got %+v", got)
+	}
+}
+
 func TestIndexGPUDeviceByPoolRefName(t *testing.T) {
 	obj, field, extractor := IndexGPUDeviceByPoolRefName()
 	if _, ok := obj.(*v1alpha1.GPUDevice); !ok {
@@ -99,6 +123,29 @@ func TestIndexGPUDeviceByNamespacedAssignment(t *testing.T) {
 	}
 }
 
+func TestIndexGPUDeviceByUUID(t *testing.T) {
+	obj, field, extractor := IndexGPUDeviceByUUID()
+	if _, ok := obj.(*v1alpha1.GPUDevice); !ok {
+		t.Fatalf("expected GPUDevice object, got %T", obj)
+	}
+	if field != GPUDeviceUUIDField {
+		t.Fatalf("expected field %s, got %s", GPUDeviceUUIDField, field)
+	}
+
+	device := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{Hardware: v1alpha1.GPUDeviceHardware{UUID: "GPU-1234"}}}
+	if got := extractor(device); len(got) != 1 || got[0] != "GPU-1234" {
+		t.Fatalf("expected uuid indexed, got %+v", got)
+	}
+
+	device.Status.Hardware.UUID = ""
+	if got := extractor(device); got != nil {
+		t.Fatalf("expected nil for empty uuid, got %+v", got)
+	}
+	if got := extractor(&corev1.Pod{}); got != nil {
+		t.Fatalf("expected nil for non-GPUDevice object, got %+v", got)
+	}
+}
+
 func TestIndexGPUDeviceByClusterAssignment(t *testing.T) {
 	obj, field, extractor := IndexGPUDeviceByClusterAssignment()
 	if _, ok := obj.(*v1alpha1.GPUDevice); !ok {
@@ -143,6 +190,30 @@ func TestIndexGPUPoolByName(t *testing.T) {
 	}
 }
 
+func TestIndexGPUPoolByResourceName(t *testing.T) {
+	obj, field, extractor := IndexGPUPoolByResourceName()
+	if _, ok := obj.(*v1alpha1.GPUPool); !ok {
+		t.Fatalf("expected GPUPool object, got %T", obj)
+	}
+	if field != GPUPoolResourceNameField {
+		t.Fatalf("expected field %s, got %s", GPUPoolResourceNameField, field)
+	}
+
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "ns"}}
+	if got := extractor(pool); len(got) != 1 || got[0] != "gpu.deckhouse.io/pool-a" {
+		t.Fatalf("expected resource name indexed, got %+v", got)
+	}
+
+	pool.Name = ""
+	if got := extractor(pool); got != nil {
+		t.Fatalf("expected nil for empty pool name, got %+v", got)
+	}
+
+	if got := extractor(&corev1.Pod{}); got != nil {
+		t.Fatalf("expected nil for non-GPUPool object, got %+v", got)
+	}
+}
+
 func TestIndexNodeByTaintKey(t *testing.T) {
 	obj, field, extractor := IndexNodeByTaintKey()
 	if _, ok := obj.(*corev1.Node); !ok {
@@ -189,11 +260,15 @@ func TestIndexNodeByTaintKey(t *testing.T) {
 func TestIndexGettersList(t *testing.T) {
 	expected := []string{
 		GPUDeviceNodeField,
+		GPUDeviceInventoryIDField,
+		GPUDeviceUUIDField,
 		GPUDevicePoolRefNameField,
 		GPUDeviceNamespacedAssignmentField,
 		GPUDeviceClusterAssignmentField,
 		GPUPoolNameField,
+		GPUPoolResourceNameField,
 		NodeTaintKeyField,
+		PodGPUResourceField,
 	}
 	fields := make([]string, 0, len(IndexGetters))
 	for _, getter := range IndexGetters {
@@ -204,3 +279,43 @@ func TestIndexGettersList(t *testing.T) {
 		t.Fatalf("unexpected fields list: %+v", fields)
 	}
 }
+
+func TestIndexPodByGPUResource(t *testing.T) {
+	obj, field, extractor := IndexPodByGPUResource()
+	if _, ok := obj.(*corev1.Pod); !ok {
+		t.Fatalf("expected Pod object, got %T", obj)
+	}
+	if field != PodGPUResourceField {
+		t.Fatalf("expected field %s, got %s", PodGPUResourceField, field)
+	}
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{"cluster.gpu.deckhouse.io/shared": resource.MustParse("1")},
+		}}},
+		Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"gpu.deckhouse.io/alpha": resource.MustParse("2"),
+					corev1.ResourceCPU:       resource.MustParse("1"),
+				},
+			}},
+			{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{"gpu.deckhouse.io/alpha": resource.MustParse("1")},
+			}},
+		},
+	}}
+	if got := extractor(pod); !reflect.DeepEqual(got, []string{"cluster.gpu.deckhouse.io/shared", "gpu.deckhouse.io/alpha"}) {
+		t.Fatalf("unexpected pool resources indexed: %+v", got)
+	}
+
+	cpuOnly := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), "nvidia.com/gpu": resource.MustParse("1")},
+	}}}}}
+	if got := extractor(cpuOnly); got != nil {
+		t.Fatalf("expected nil for pods without pool resources, got %+v", got)
+	}
+	if got := extractor(&v1alpha1.GPUDevice{}); got != nil {
+		t.Fatalf("expected nil for non-Pod object, got %+v", got)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 Flant JSC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexer
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+// IndexPodByGPUResource indexes Pods by the pool extended resources their containers request, so pool
+// usage is computed from the pods of one pool instead of every pod in the cache.
+func IndexPodByGPUResource() (obj client.Object, field string, extractValue client.IndexerFunc) {
+	return &corev1.Pod{}, PodGPUResourceField, func(object client.Object) []string {
+		pod, ok := object.(*corev1.Pod)
+		if !ok {
+			return nil
+		}
+		return PodGPUResources(pod)
+	}
+}
+
+// PodGPUResources returns the sorted pool extended resources requested or limited by the containers of the pod.
+func PodGPUResources(pod *corev1.Pod) []string {
+	if pod == nil {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	collect := func(containers []corev1.Container) {
+		for i := range containers {
+			for _, list := range []corev1.ResourceList{containers[i].Resources.Limits, containers[i].Resources.Requests} {
+				for name := range list {
+					if isPoolResource(string(name)) {
+						seen[string(name)] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+	collect(pod.Spec.InitContainers)
+	collect(pod.Spec.Containers)
+	if len(seen) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(seen))
+	for name := range seen {
+		values = append(values, name)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func isPoolResource(name string) bool {
+	return strings.HasPrefix(name, poolcommon.NamespacedPoolResourcePrefix+"/") ||
+		strings.HasPrefix(name, poolcommon.ClusterPoolResourcePrefix+"/")
+}
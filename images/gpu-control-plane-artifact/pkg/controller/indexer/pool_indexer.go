@@ -20,6 +20,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/names"
 )
 
 func IndexGPUPoolByName() (obj client.Object, field string, extractValue client.IndexerFunc) {
@@ -31,3 +32,13 @@ func IndexGPUPoolByName() (obj client.Object, field string, extractValue client.
 		return []string{pool.Name}
 	}
 }
+
+func IndexGPUPoolByResourceName() (obj client.Object, field string, extractValue client.IndexerFunc) {
+	return &v1alpha1.GPUPool{}, GPUPoolResourceNameField, func(object client.Object) []string {
+		pool, ok := object.(*v1alpha1.GPUPool)
+		if !ok || pool.Name == "" {
+			return nil
+		}
+		return []string{names.PoolResourceName(pool)}
+	}
+}
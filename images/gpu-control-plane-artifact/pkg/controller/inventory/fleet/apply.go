@@ -0,0 +1,89 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+// Apply creates the fleet in a cluster. Objects that already exist are left alone, so an interrupted run
+// can be repeated. Node status is written separately because the API server drops it on create; without a
+// kubelet the node lifecycle controller eventually flips the Ready condition back, which the inventory
+// then treats like any other NotReady node.
+func Apply(ctx context.Context, c client.Client, fleet Fleet) error {
+	namespaces := map[string]struct{}{}
+	for _, feature := range fleet.NodeFeatures {
+		namespaces[feature.Namespace] = struct{}{}
+	}
+	for namespace := range namespaces {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		if err := c.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("create namespace %s: %w", namespace, err)
+		}
+	}
+
+	for _, node := range fleet.Nodes {
+		status := node.Status
+		obj := node.DeepCopy()
+		if err := c.Create(ctx, obj); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			return fmt.Errorf("create node %s: %w", node.Name, err)
+		}
+		obj.Status = status
+		if err := c.Status().Update(ctx, obj); err != nil {
+			return fmt.Errorf("update node %s status: %w", node.Name, err)
+		}
+	}
+	for _, feature := range fleet.NodeFeatures {
+		if err := c.Create(ctx, feature.DeepCopy()); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("create nodefeature %s/%s: %w", feature.Namespace, feature.Name, err)
+		}
+	}
+	return nil
+}
+
+// Delete removes every object carrying FleetLabel. The GPUDevices and GPUNodeStates of the removed nodes
+// are garbage collected through their owner references.
+func Delete(ctx context.Context, c client.Client) error {
+	selector := client.MatchingLabels{FleetLabel: "true"}
+	features := &nfdv1alpha1.NodeFeatureList{}
+	if err := c.List(ctx, features, selector); err != nil {
+		return fmt.Errorf("list nodefeatures: %w", err)
+	}
+	for i := range features.Items {
+		if err := c.Delete(ctx, &features.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete nodefeature %s/%s: %w", features.Items[i].Namespace, features.Items[i].Name, err)
+		}
+	}
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes, selector); err != nil {
+		return fmt.Errorf("list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		if err := c.Delete(ctx, &nodes.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete node %s: %w", nodes.Items[i].Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleet
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// EventType names a scripted change the simulator applies to its nodes.
+type EventType string
+
+const (
+	// EventDeviceFailure drops one GPU from the node's NodeFeature, as when a device falls off the bus.
+	EventDeviceFailure EventType = "DeviceFailure"
+	// EventDriverUpgrade publishes a new driver version for the node.
+	EventDriverUpgrade EventType = "DriverUpgrade"
+	// EventNodeNotReady stops reporting the node as Ready.
+	EventNodeNotReady EventType = "NodeNotReady"
+	// EventNodeReady reports the node as Ready again.
+	EventNodeReady EventType = "NodeReady"
+)
+
+// Fixture describes a simulated fleet and the events played against it.
+type Fixture struct {
+	// Namespace receives the NodeFeatures; the default trusted NFD namespace when empty.
+	Namespace string        `json:"namespace,omitempty"`
+	Nodes     []FixtureNode `json:"nodes"`
+	Events    []Event       `json:"events,omitempty"`
+}
+
+// FixtureNode describes one node, or Count identical nodes named <name>-NN. Unset hardware fields fall
+// back to an 8x A100 node.
+type FixtureNode struct {
+	Name      string `json:"name"`
+	Count     int    `json:"count,omitempty"`
+	GPUs      int    `json:"gpus,omitempty"`
+	Product   string `json:"product,omitempty"`
+	PCIDevice string `json:"pciDevice,omitempty"`
+	MemoryMiB int    `json:"memoryMiB,omitempty"`
+	Driver    string `json:"driver,omitempty"`
+	MIG       *bool  `json:"mig,omitempty"`
+}
+
+// Event is a scripted change applied After the simulator started.
+type Event struct {
+	After metav1.Duration `json:"after"`
+	Type  EventType       `json:"type"`
+	Node  string          `json:"node"`
+	// GPU is the index of the failing device of a DeviceFailure.
+	GPU *int `json:"gpu,omitempty"`
+	// Driver is the version a DriverUpgrade installs.
+	Driver string `json:"driver,omitempty"`
+}
+
+// LoadFixture reads and validates a fixture file.
+func LoadFixture(path string) (Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("read fixture: %w", err)
+	}
+	return ParseFixture(data)
+}
+
+// ParseFixture decodes a fixture, rejecting unknown fields so typos do not silently shrink the fleet.
+func ParseFixture(data []byte) (Fixture, error) {
+	var fixture Fixture
+	if err := yaml.UnmarshalStrict(data, &fixture); err != nil {
+		return Fixture{}, fmt.Errorf("decode fixture: %w", err)
+	}
+	if err := fixture.validate(); err != nil {
+		return Fixture{}, err
+	}
+	return fixture, nil
+}
+
+func (f Fixture) validate() error {
+	if len(f.Nodes) == 0 {
+		return fmt.Errorf("fixture has no nodes")
+	}
+	gpus := map[string]int{}
+	for i, spec := range f.Nodes {
+		if spec.Name == "" {
+			return fmt.Errorf("nodes[%d]: name is required", i)
+		}
+		if spec.Count < 0 || spec.GPUs < 0 || spec.MemoryMiB < 0 {
+			return fmt.Errorf("nodes[%d]: count, gpus and memoryMiB must not be negative", i)
+		}
+		hw := spec.hardware()
+		for _, name := range spec.names() {
+			if _, ok := gpus[name]; ok {
+				return fmt.Errorf("nodes[%d]: duplicate node %q", i, name)
+			}
+			gpus[name] = hw.gpus
+		}
+	}
+	for i, event := range f.Events {
+		count, ok := gpus[event.Node]
+		if !ok {
+			return fmt.Errorf("events[%d]: unknown node %q", i, event.Node)
+		}
+		if event.After.Duration < 0 {
+			return fmt.Errorf("events[%d]: after must not be negative", i)
+		}
+		switch event.Type {
+		case EventDeviceFailure:
+			if event.GPU == nil || *event.GPU < 0 || *event.GPU >= count {
+				return fmt.Errorf("events[%d]: gpu must be an index below %d", i, count)
+			}
+		case EventDriverUpgrade:
+			if event.Driver == "" {
+				return fmt.Errorf("events[%d]: driver is required", i)
+			}
+		case EventNodeNotReady, EventNodeReady:
+		default:
+			return fmt.Errorf("events[%d]: unknown type %q", i, event.Type)
+		}
+	}
+	return nil
+}
+
+// Fleet builds the simulated objects. Every node and NodeFeature carries SimulatedLabel.
+func (f Fixture) Fleet() Fleet {
+	namespace := f.Namespace
+	if namespace == "" {
+		namespace = moduleconfig.DefaultTrustedNodeFeatureNamespace
+	}
+	var fleet Fleet
+	for _, spec := range f.Nodes {
+		hw := spec.hardware()
+		for _, name := range spec.names() {
+			n := node(name, hw)
+			n.Labels[SimulatedLabel] = "true"
+			feature := nodeFeature(name, namespace, len(fleet.Nodes), hw)
+			feature.Labels[SimulatedLabel] = "true"
+			fleet.Nodes = append(fleet.Nodes, n)
+			fleet.NodeFeatures = append(fleet.NodeFeatures, feature)
+		}
+	}
+	return fleet
+}
+
+// sortedEvents returns the events in the order they are due; events due at the same time keep their order.
+func (f Fixture) sortedEvents() []Event {
+	events := slices.Clone(f.Events)
+	slices.SortStableFunc(events, func(a, b Event) int {
+		return cmp.Compare(a.After.Duration, b.After.Duration)
+	})
+	return events
+}
+
+func (n FixtureNode) names() []string {
+	if n.Count <= 1 {
+		return []string{n.Name}
+	}
+	names := make([]string, 0, n.Count)
+	for i := 0; i < n.Count; i++ {
+		names = append(names, fmt.Sprintf("%s-%02d", n.Name, i))
+	}
+	return names
+}
+
+func (n FixtureNode) hardware() hardware {
+	hw := defaultHardware
+	if n.GPUs > 0 {
+		hw.gpus = n.GPUs
+	}
+	if n.Product != "" {
+		hw.product = n.Product
+	}
+	if n.PCIDevice != "" {
+		hw.pciDevice = n.PCIDevice
+	}
+	if n.MemoryMiB > 0 {
+		hw.memoryMiB = n.MemoryMiB
+	}
+	if n.Driver != "" {
+		hw.driver = n.Driver
+	}
+	if n.MIG != nil {
+		hw.mig = *n.MIG
+	}
+	return hw
+}
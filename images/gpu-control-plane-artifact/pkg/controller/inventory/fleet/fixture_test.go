@@ -0,0 +1,123 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleet
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+const testFixture = `
+nodes:
+- name: sim-a100
+  count: 2
+- name: sim-l4
+  gpus: 1
+  product: NVIDIA L4
+  pciDevice: 27b8
+  memoryMiB: 23034
+  driver: 535.104.05
+  mig: false
+events:
+- after: 10m
+  type: DriverUpgrade
+  node: sim-l4
+  driver: 550.54.15
+- after: 5m
+  type: DeviceFailure
+  node: sim-a100-01
+  gpu: 3
+`
+
+func TestLoadFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes.yaml")
+	if err := os.WriteFile(path, []byte(testFixture), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	fleet := fixture.Fleet()
+	var names []string
+	for _, node := range fleet.Nodes {
+		names = append(names, node.Name)
+	}
+	if got := strings.Join(names, ","); got != "sim-a100-00,sim-a100-01,sim-l4" {
+		t.Fatalf("unexpected nodes %s", got)
+	}
+
+	policy := invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey}
+	for i, node := range fleet.Nodes {
+		feature := fleet.NodeFeatures[i]
+		if node.Labels[SimulatedLabel] != "true" || feature.Labels[SimulatedLabel] != "true" {
+			t.Fatalf("expected %s objects to be marked simulated", node.Name)
+		}
+		if feature.Namespace != moduleconfig.DefaultTrustedNodeFeatureNamespace {
+			t.Fatalf("expected default namespace, got %q", feature.Namespace)
+		}
+		snapshot := invstate.BuildNodeSnapshot(node, feature, policy)
+		if len(snapshot.Errors) != 0 || len(snapshot.Warnings) != 0 {
+			t.Fatalf("expected clean snapshot of %s, got errors %v warnings %v", node.Name, snapshot.Errors, snapshot.Warnings)
+		}
+	}
+
+	l4 := invstate.BuildNodeSnapshot(fleet.Nodes[2], fleet.NodeFeatures[2], policy)
+	if len(l4.Devices) != 1 || l4.Devices[0].Product != "NVIDIA L4" || l4.Devices[0].MemoryMiB != 23034 || l4.Devices[0].MIG.Capable {
+		t.Fatalf("unexpected L4 devices %+v", l4.Devices)
+	}
+	if l4.Driver.Version != "535.104.05" {
+		t.Fatalf("expected fixture driver, got %q", l4.Driver.Version)
+	}
+	if a100 := invstate.BuildNodeSnapshot(fleet.Nodes[0], fleet.NodeFeatures[0], policy); len(a100.Devices) != DefaultGPUsPerNode {
+		t.Fatalf("expected default GPU count, got %d", len(a100.Devices))
+	}
+
+	events := fixture.sortedEvents()
+	if len(events) != 2 || events[0].Type != EventDeviceFailure || events[0].After.Duration != 5*time.Minute {
+		t.Fatalf("expected events ordered by due time, got %+v", events)
+	}
+}
+
+func TestParseFixtureRejectsInvalidFixtures(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{name: "empty", fixture: `nodes: []`, want: "no nodes"},
+		{name: "unknown field", fixture: "nodes:\n- name: a\n  gpu: 2\n", want: "decode fixture"},
+		{name: "missing name", fixture: "nodes:\n- gpus: 2\n", want: "name is required"},
+		{name: "duplicate", fixture: "nodes:\n- name: a-00\n- name: a\n  count: 2\n", want: `duplicate node "a-00"`},
+		{name: "unknown node", fixture: "nodes:\n- name: a\nevents:\n- {after: 1m, type: NodeReady, node: b}\n", want: `unknown node "b"`},
+		{name: "unknown type", fixture: "nodes:\n- name: a\nevents:\n- {after: 1m, type: Explode, node: a}\n", want: `unknown type "Explode"`},
+		{name: "gpu out of range", fixture: "nodes:\n- name: a\n  gpus: 2\nevents:\n- {after: 1m, type: DeviceFailure, node: a, gpu: 2}\n", want: "gpu must be an index below 2"},
+		{name: "upgrade without driver", fixture: "nodes:\n- name: a\nevents:\n- {after: 1m, type: DriverUpgrade, node: a}\n", want: "driver is required"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseFixture([]byte(tc.fixture))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
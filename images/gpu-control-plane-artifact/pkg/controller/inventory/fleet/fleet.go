@@ -0,0 +1,226 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fleet generates a synthetic GPU fleet: Nodes and the NodeFeatures NFD and gfd-extender would
+// publish for them. The same objects seed the fake client of the inventory benchmarks and, through
+// hack/fleet-generator, a kind or envtest cluster for end-to-end measurements. The Simulator keeps a fleet
+// described by a fixture alive in a development cluster without GPUs. GPUDevices are not
+// generated: the inventory controller creates them on its first pass, exactly as in a real cluster.
+package fleet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+const (
+	DefaultNodePrefix  = "fleet-node"
+	DefaultGPUsPerNode = 8
+
+	// FleetLabel marks every generated object, so a fleet can be listed and removed as a whole.
+	FleetLabel = "gpu.deckhouse.io/synthetic-fleet"
+)
+
+// Options sizes the fleet; zero values fall back to the defaults.
+type Options struct {
+	Nodes       int
+	GPUsPerNode int
+	NodePrefix  string
+	// Namespace receives the NodeFeatures; the default trusted NFD namespace when empty.
+	Namespace string
+}
+
+// Fleet holds the generated objects, in the order they have to be created.
+type Fleet struct {
+	Nodes        []*corev1.Node
+	NodeFeatures []*nfdv1alpha1.NodeFeature
+}
+
+// Objects returns the fleet as a flat list, nodes first.
+func (f Fleet) Objects() []client.Object {
+	objs := make([]client.Object, 0, len(f.Nodes)+len(f.NodeFeatures))
+	for _, node := range f.Nodes {
+		objs = append(objs, node)
+	}
+	for _, feature := range f.NodeFeatures {
+		objs = append(objs, feature)
+	}
+	return objs
+}
+
+// Generate builds a fleet of managed A100 nodes. Every node carries the labels GFD puts on the Node and
+// a NodeFeature with the device labels and per-GPU instance attributes, so the snapshot parser walks the
+// same paths as in production.
+func Generate(opts Options) Fleet {
+	opts = opts.withDefaults()
+	fleet := Fleet{
+		Nodes:        make([]*corev1.Node, 0, opts.Nodes),
+		NodeFeatures: make([]*nfdv1alpha1.NodeFeature, 0, opts.Nodes),
+	}
+	for i := 0; i < opts.Nodes; i++ {
+		name := fmt.Sprintf("%s-%04d", opts.NodePrefix, i)
+		hw := defaultHardware
+		hw.gpus = opts.GPUsPerNode
+		fleet.Nodes = append(fleet.Nodes, node(name, hw))
+		fleet.NodeFeatures = append(fleet.NodeFeatures, nodeFeature(name, opts.Namespace, i, hw))
+	}
+	return fleet
+}
+
+func (o Options) withDefaults() Options {
+	if o.GPUsPerNode <= 0 {
+		o.GPUsPerNode = DefaultGPUsPerNode
+	}
+	if o.NodePrefix == "" {
+		o.NodePrefix = DefaultNodePrefix
+	}
+	if o.Namespace == "" {
+		o.Namespace = moduleconfig.DefaultTrustedNodeFeatureNamespace
+	}
+	return o
+}
+
+// hardware describes the GPUs of one generated node.
+type hardware struct {
+	gpus      int
+	product   string
+	pciDevice string
+	memoryMiB int
+	driver    string
+	mig       bool
+}
+
+var defaultHardware = hardware{
+	gpus:      DefaultGPUsPerNode,
+	product:   "NVIDIA A100-SXM4-80GB",
+	pciDevice: "20b2",
+	memoryMiB: 81920,
+	driver:    "550.54.15",
+	mig:       true,
+}
+
+func node(name string, hw hardware) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				FleetLabel:                          "true",
+				corev1.LabelHostname:                name,
+				corev1.LabelOSStable:                "linux",
+				corev1.LabelArchStable:              "amd64",
+				invstate.DefaultManagedNodeLabelKey: "true",
+				"nvidia.com/gpu.present":            "true",
+				snapshot.GFDMemoryLabel:             fmt.Sprint(hw.memoryMiB),
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionTrue,
+				Reason:             "KubeletReady",
+				LastTransitionTime: metav1.Now(),
+			}},
+		},
+	}
+}
+
+func nodeFeature(nodeName, namespace string, nodeIndex int, hw hardware) *nfdv1alpha1.NodeFeature {
+	migStrategy := "none"
+	if hw.mig {
+		migStrategy = "single"
+	}
+	labels := map[string]string{
+		snapshot.GFDProductLabel:            strings.ReplaceAll(hw.product, " ", "-"),
+		snapshot.GFDComputeMajorLabel:       "8",
+		snapshot.GFDComputeMinorLabel:       "0",
+		snapshot.GFDDriverVersionLabel:      hw.driver,
+		snapshot.GFDCudaRuntimeVersionLabel: "12.4",
+		snapshot.GFDCudaDriverMajorLabel:    "12",
+		snapshot.GFDCudaDriverMinorLabel:    "4",
+		snapshot.GFDMigCapableLabel:         strconv.FormatBool(hw.mig),
+		snapshot.GFDMigStrategyLabel:        migStrategy,
+		snapshot.DeckhouseToolkitInstalled:  "true",
+		snapshot.DeckhouseToolkitReadyLabel: "true",
+		"nvidia.com/gpu.family":             "ampere",
+		"nvidia.com/gpu.count":              fmt.Sprint(hw.gpus),
+	}
+	elements := make([]nfdv1alpha1.InstanceFeature, 0, hw.gpus)
+	for gpu := 0; gpu < hw.gpus; gpu++ {
+		prefix := fmt.Sprintf("%s%02d.", snapshot.DeviceLabelPrefix, gpu)
+		labels[prefix+"vendor"] = snapshot.VendorNvidia
+		labels[prefix+"device"] = hw.pciDevice
+		labels[prefix+"class"] = "0302"
+		labels[prefix+"product"] = hw.product
+		labels[prefix+"memoryMiB"] = fmt.Sprint(hw.memoryMiB)
+
+		attributes := map[string]string{
+			"index":            fmt.Sprint(gpu),
+			"uuid":             fmt.Sprintf("GPU-%08x-0000-4000-8000-%012x", nodeIndex, gpu),
+			"pci.address":      fmt.Sprintf("0000:%02x:00.0", 0x10+gpu),
+			"vendor":           snapshot.VendorNvidia,
+			"device":           hw.pciDevice,
+			"class":            "0302",
+			"product":          hw.product,
+			"memory.total":     fmt.Sprint(hw.memoryMiB),
+			"compute.major":    "8",
+			"compute.minor":    "0",
+			"numa.node":        fmt.Sprint(gpu / 4),
+			"power.limit":      "400000",
+			"sm.count":         "108",
+			"pcie.gen":         "4",
+			"pcie.link.width":  "16",
+			"serial":           fmt.Sprintf("1324%06d%02d", nodeIndex, gpu),
+			"precision":        "fp64,fp32,fp16,tf32",
+			"precision.bf16":   "true",
+			"mig.capable":      strconv.FormatBool(hw.mig),
+			"mig.strategy":     migStrategy,
+			"display_mode":     "Disabled",
+			"pstate":           "P0",
+			"memory.bandwidth": "2039",
+		}
+		if hw.mig {
+			attributes["mig.profiles"] = "1g.10gb,2g.20gb,3g.40gb,7g.80gb"
+		}
+		elements = append(elements, nfdv1alpha1.InstanceFeature{Attributes: attributes})
+	}
+	return &nfdv1alpha1.NodeFeature{
+		TypeMeta: metav1.TypeMeta{APIVersion: nfdv1alpha1.SchemeGroupVersion.String(), Kind: "NodeFeature"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				FleetLabel:                        "true",
+				invstate.NodeFeatureNodeNameLabel: nodeName,
+			},
+		},
+		Spec: nfdv1alpha1.NodeFeatureSpec{
+			Labels: labels,
+			Features: nfdv1alpha1.Features{
+				Instances: map[string]nfdv1alpha1.InstanceFeatureSet{
+					snapshot.GPUInstanceFeature: {Elements: elements},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleet
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestGenerateProducesParsableNodes(t *testing.T) {
+	fleet := Generate(Options{Nodes: 3, GPUsPerNode: 4})
+	if len(fleet.Nodes) != 3 || len(fleet.NodeFeatures) != 3 || len(fleet.Objects()) != 6 {
+		t.Fatalf("unexpected fleet size: %d nodes, %d features", len(fleet.Nodes), len(fleet.NodeFeatures))
+	}
+
+	policy := invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey}
+	uuids := map[string]struct{}{}
+	for i, node := range fleet.Nodes {
+		feature := fleet.NodeFeatures[i]
+		if feature.Name != node.Name || feature.Labels[invstate.NodeFeatureNodeNameLabel] != node.Name {
+			t.Fatalf("feature %s/%s does not point at node %s", feature.Namespace, feature.Name, node.Name)
+		}
+		snapshot := invstate.BuildNodeSnapshot(node, feature, policy)
+		if !snapshot.Managed || !snapshot.FeatureDetected {
+			t.Fatalf("expected managed node with feature, got %+v", snapshot)
+		}
+		if len(snapshot.Devices) != 4 {
+			t.Fatalf("expected 4 devices on %s, got %d", node.Name, len(snapshot.Devices))
+		}
+		if len(snapshot.Errors) != 0 || len(snapshot.Warnings) != 0 {
+			t.Fatalf("expected clean snapshot, got errors %v warnings %v", snapshot.Errors, snapshot.Warnings)
+		}
+		for _, device := range snapshot.Devices {
+			uuids[device.UUID] = struct{}{}
+		}
+	}
+	if len(uuids) != 12 {
+		t.Fatalf("expected unique GPU UUIDs across the fleet, got %d", len(uuids))
+	}
+}
+
+func TestApplyAndDelete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&corev1.Node{}).Build()
+	fleet := Generate(Options{Nodes: 2})
+	ctx := context.Background()
+
+	for range 2 {
+		if err := Apply(ctx, cl, fleet); err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+	}
+	nodes := &corev1.NodeList{}
+	if err := cl.List(ctx, nodes); err != nil {
+		t.Fatalf("list nodes: %v", err)
+	}
+	if len(nodes.Items) != 2 || len(nodes.Items[0].Status.Conditions) != 1 {
+		t.Fatalf("expected two ready nodes, got %+v", nodes.Items)
+	}
+
+	if err := Delete(ctx, cl); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	features := &nfdv1alpha1.NodeFeatureList{}
+	if err := cl.List(ctx, features); err != nil {
+		t.Fatalf("list features: %v", err)
+	}
+	if err := cl.List(ctx, nodes); err != nil {
+		t.Fatalf("list nodes: %v", err)
+	}
+	if len(features.Items) != 0 || len(nodes.Items) != 0 {
+		t.Fatalf("expected fleet removed, got %d features and %d nodes", len(features.Items), len(nodes.Items))
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+const (
+	// SimulatedLabel marks the nodes and NodeFeatures the simulator fabricated.
+	SimulatedLabel = "gpu.deckhouse.io/simulated"
+
+	// DefaultSimulatorInterval is how often the simulator plays due events and renews node heartbeats,
+	// well within the node lifecycle controller's grace period.
+	DefaultSimulatorInterval = 10 * time.Second
+
+	simulatedReason = "GPUControlPlaneSimulated"
+)
+
+// ErrRealGPUNodes is returned when the cluster already has GPU nodes the simulator did not create.
+var ErrRealGPUNodes = errors.New("cluster has real GPU nodes")
+
+// Simulator creates a fixture's nodes and NodeFeatures and keeps them alive without a kubelet, NFD or
+// GFD, so the inventory, pools and renderer run against fabricated hardware. Scripted events change the
+// published hardware while the controllers are running.
+type Simulator struct {
+	log      logr.Logger
+	client   client.Client
+	fleet    Fleet
+	events   []Event
+	force    bool
+	interval time.Duration
+	now      func() time.Time
+
+	next     int
+	notReady map[string]bool
+}
+
+// NewSimulator creates the simulator runnable; force skips the check for real GPU nodes.
+func NewSimulator(log logr.Logger, c client.Client, fixture Fixture, force bool) *Simulator {
+	return &Simulator{
+		log:      log,
+		client:   c,
+		fleet:    fixture.Fleet(),
+		events:   fixture.sortedEvents(),
+		force:    force,
+		interval: DefaultSimulatorInterval,
+		now:      time.Now,
+		notReady: map[string]bool{},
+	}
+}
+
+// SetupSimulator registers the simulator when a fixture file is configured.
+func SetupSimulator(mgr ctrl.Manager, log logr.Logger, cfg config.SimulationConfig) error {
+	if cfg.NodesFile == "" {
+		return nil
+	}
+	fixture, err := LoadFixture(cfg.NodesFile)
+	if err != nil {
+		return fmt.Errorf("load simulated nodes %s: %w", cfg.NodesFile, err)
+	}
+	simLog := log.WithName("simulator")
+	if err := mgr.Add(NewSimulator(simLog, mgr.GetClient(), fixture, cfg.Force)); err != nil {
+		return fmt.Errorf("add node simulator: %w", err)
+	}
+	simLog.Info("node simulation enabled", "file", cfg.NodesFile, "nodes", len(fixture.Fleet().Nodes), "events", len(fixture.Events), "force", cfg.Force)
+	return nil
+}
+
+// NeedLeaderElection keeps a single writer of the simulated objects.
+func (s *Simulator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start checks the cluster, creates the fleet and then plays events and renews heartbeats until the
+// context is cancelled. Refusing to simulate stops the manager.
+func (s *Simulator) Start(ctx context.Context) error {
+	if err := s.Check(ctx); err != nil {
+		return err
+	}
+	if err := Apply(ctx, s.client, s.fleet); err != nil {
+		return fmt.Errorf("create simulated nodes: %w", err)
+	}
+	start := s.now()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Step(ctx, s.now().Sub(start)); err != nil {
+			s.log.Error(err, "simulation step failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check refuses to simulate on a cluster with GPU nodes the simulator did not create, unless forced,
+// and never takes over an existing node that lacks SimulatedLabel.
+func (s *Simulator) Check(ctx context.Context) error {
+	nodes := &corev1.NodeList{}
+	if err := s.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("list nodes: %w", err)
+	}
+	wanted := make(map[string]struct{}, len(s.fleet.Nodes))
+	for _, node := range s.fleet.Nodes {
+		wanted[node.Name] = struct{}{}
+	}
+	var real []string
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if fabricated(node) {
+			continue
+		}
+		if _, ok := wanted[node.Name]; ok {
+			return fmt.Errorf("simulated node %s already exists and was not created by the simulator", node.Name)
+		}
+		if hasGPULabels(node) {
+			real = append(real, node.Name)
+		}
+	}
+	if len(real) == 0 {
+		return nil
+	}
+	sort.Strings(real)
+	if !s.force {
+		return fmt.Errorf("%w: %s; refusing to simulate without force", ErrRealGPUNodes, strings.Join(real, ", "))
+	}
+	s.log.Info("simulating next to real GPU nodes", "nodes", real)
+	return nil
+}
+
+// Step plays the events due after elapsed and renews the heartbeat of every simulated node. An event
+// that fails is retried on the next step.
+func (s *Simulator) Step(ctx context.Context, elapsed time.Duration) error {
+	for s.next < len(s.events) && s.events[s.next].After.Duration <= elapsed {
+		event := s.events[s.next]
+		if err := s.play(ctx, event); err != nil {
+			return fmt.Errorf("play %s on %s: %w", event.Type, event.Node, err)
+		}
+		s.log.Info("played simulated event", "type", event.Type, "node", event.Node, "after", event.After.Duration.String())
+		s.next++
+	}
+	for _, node := range s.fleet.Nodes {
+		if err := s.heartbeat(ctx, node.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) play(ctx context.Context, event Event) error {
+	switch event.Type {
+	case EventDeviceFailure:
+		return s.updateFeature(ctx, event.Node, func(feature *nfdv1alpha1.NodeFeature) {
+			removeDevice(feature, *event.GPU)
+		})
+	case EventDriverUpgrade:
+		return s.updateFeature(ctx, event.Node, func(feature *nfdv1alpha1.NodeFeature) {
+			feature.Spec.Labels[snapshot.GFDDriverVersionLabel] = event.Driver
+		})
+	case EventNodeNotReady:
+		s.notReady[event.Node] = true
+	case EventNodeReady:
+		delete(s.notReady, event.Node)
+	}
+	return nil
+}
+
+func (s *Simulator) updateFeature(ctx context.Context, nodeName string, mutate func(*nfdv1alpha1.NodeFeature)) error {
+	var key client.ObjectKey
+	for _, feature := range s.fleet.NodeFeatures {
+		if feature.Name == nodeName {
+			key = client.ObjectKeyFromObject(feature)
+		}
+	}
+	feature := &nfdv1alpha1.NodeFeature{}
+	if err := s.client.Get(ctx, key, feature); err != nil {
+		return fmt.Errorf("get nodefeature %s: %w", key, err)
+	}
+	if feature.Spec.Labels == nil {
+		feature.Spec.Labels = map[string]string{}
+	}
+	mutate(feature)
+	if err := s.client.Update(ctx, feature); err != nil {
+		return fmt.Errorf("update nodefeature %s: %w", key, err)
+	}
+	return nil
+}
+
+// heartbeat refreshes the Ready condition the way a kubelet would, so the node lifecycle controller
+// leaves simulated nodes alone.
+func (s *Simulator) heartbeat(ctx context.Context, name string) error {
+	node := &corev1.Node{}
+	if err := s.client.Get(ctx, client.ObjectKey{Name: name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			s.log.Info("simulated node is gone, it is recreated on restart", "node", name)
+			return nil
+		}
+		return fmt.Errorf("get node %s: %w", name, err)
+	}
+	now := metav1.NewTime(s.now())
+	status, message := corev1.ConditionTrue, "simulated node is ready"
+	if s.notReady[name] {
+		status, message = corev1.ConditionFalse, "simulated node is not ready"
+	}
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, LastTransitionTime: now}
+	idx := -1
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			idx = i
+			ready = node.Status.Conditions[i]
+		}
+	}
+	if ready.Status != status {
+		ready.LastTransitionTime = now
+	}
+	ready.Status = status
+	ready.Reason = simulatedReason
+	ready.Message = message
+	ready.LastHeartbeatTime = now
+	if idx < 0 {
+		node.Status.Conditions = append(node.Status.Conditions, ready)
+	} else {
+		node.Status.Conditions[idx] = ready
+	}
+	if err := s.client.Status().Update(ctx, node); err != nil {
+		// A controller updated the node first; the next step retries with a fresh copy.
+		if apierrors.IsConflict(err) {
+			return nil
+		}
+		return fmt.Errorf("update node %s status: %w", name, err)
+	}
+	return nil
+}
+
+// removeDevice drops a GPU's labels and instance attributes, which the inventory sees as a lost device.
+func removeDevice(feature *nfdv1alpha1.NodeFeature, gpu int) {
+	prefix := fmt.Sprintf("%s%02d.", snapshot.DeviceLabelPrefix, gpu)
+	for key := range feature.Spec.Labels {
+		if strings.HasPrefix(key, prefix) {
+			delete(feature.Spec.Labels, key)
+		}
+	}
+	set, ok := feature.Spec.Features.Instances[snapshot.GPUInstanceFeature]
+	if !ok {
+		return
+	}
+	index := fmt.Sprint(gpu)
+	kept := set.Elements[:0]
+	for _, element := range set.Elements {
+		if element.Attributes["index"] != index {
+			kept = append(kept, element)
+		}
+	}
+	set.Elements = kept
+	feature.Spec.Features.Instances[snapshot.GPUInstanceFeature] = set
+	feature.Spec.Labels["nvidia.com/gpu.count"] = fmt.Sprint(len(kept))
+}
+
+func fabricated(node *corev1.Node) bool {
+	return node.Labels[SimulatedLabel] == "true" || node.Labels[FleetLabel] == "true"
+}
+
+func hasGPULabels(node *corev1.Node) bool {
+	for key := range node.Labels {
+		if key == "nvidia.com/gpu.present" || key == snapshot.GFDProductLabel || strings.HasPrefix(key, snapshot.DeviceLabelPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func newSimulatorClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	return clientfake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&corev1.Node{}).WithObjects(objs...).Build()
+}
+
+func TestSimulatorPlaysScriptedEvents(t *testing.T) {
+	fixture, err := ParseFixture([]byte(`
+nodes:
+- name: sim
+  gpus: 4
+events:
+- {after: 5m, type: DeviceFailure, node: sim, gpu: 1}
+- {after: 5m, type: NodeNotReady, node: sim}
+- {after: 10m, type: DriverUpgrade, node: sim, driver: 560.35.03}
+`))
+	if err != nil {
+		t.Fatalf("parse fixture: %v", err)
+	}
+	cl := newSimulatorClient(t)
+	sim := NewSimulator(logr.Discard(), cl, fixture, false)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	sim.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := sim.Check(ctx); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if err := Apply(ctx, cl, sim.fleet); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	policy := invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey}
+	observe := func(elapsed time.Duration) (invstate.NodeSnapshot, corev1.NodeCondition) {
+		t.Helper()
+		now = start.Add(elapsed)
+		if err := sim.Step(ctx, elapsed); err != nil {
+			t.Fatalf("step at %s: %v", elapsed, err)
+		}
+		node := &corev1.Node{}
+		if err := cl.Get(ctx, client.ObjectKey{Name: "sim"}, node); err != nil {
+			t.Fatalf("get node: %v", err)
+		}
+		feature := &nfdv1alpha1.NodeFeature{}
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(sim.fleet.NodeFeatures[0]), feature); err != nil {
+			t.Fatalf("get nodefeature: %v", err)
+		}
+		var ready corev1.NodeCondition
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady {
+				ready = cond
+			}
+		}
+		return invstate.BuildNodeSnapshot(node, feature, policy), ready
+	}
+
+	snapshot, ready := observe(time.Minute)
+	if len(snapshot.Devices) != 4 || ready.Status != corev1.ConditionTrue || !ready.LastHeartbeatTime.Time.Equal(now) {
+		t.Fatalf("expected a healthy node with a fresh heartbeat, got %d devices and %+v", len(snapshot.Devices), ready)
+	}
+
+	snapshot, ready = observe(5 * time.Minute)
+	if len(snapshot.Devices) != 3 || len(snapshot.Errors) != 0 {
+		t.Fatalf("expected GPU 1 to be gone after the failure, got %+v errors %v", snapshot.Devices, snapshot.Errors)
+	}
+	for _, device := range snapshot.Devices {
+		if device.Index == "1" {
+			t.Fatalf("failed GPU is still published: %+v", device)
+		}
+	}
+	if ready.Status != corev1.ConditionFalse || !ready.LastTransitionTime.Time.Equal(now) {
+		t.Fatalf("expected the node to turn NotReady, got %+v", ready)
+	}
+	if snapshot.Driver.Version != defaultHardware.driver {
+		t.Fatalf("driver upgraded too early: %q", snapshot.Driver.Version)
+	}
+
+	snapshot, _ = observe(10 * time.Minute)
+	if snapshot.Driver.Version != "560.35.03" {
+		t.Fatalf("expected the upgraded driver, got %q", snapshot.Driver.Version)
+	}
+	if sim.next != len(sim.events) {
+		t.Fatalf("expected every event to be played once, next=%d", sim.next)
+	}
+}
+
+func TestSimulatorRefusesRealGPUNodes(t *testing.T) {
+	fixture, err := ParseFixture([]byte("nodes:\n- name: sim\n"))
+	if err != nil {
+		t.Fatalf("parse fixture: %v", err)
+	}
+	real := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{"nvidia.com/gpu.present": "true"}}}
+	ctx := context.Background()
+
+	if err := NewSimulator(logr.Discard(), newSimulatorClient(t, real), fixture, false).Check(ctx); !errors.Is(err, ErrRealGPUNodes) {
+		t.Fatalf("expected ErrRealGPUNodes, got %v", err)
+	}
+	if err := NewSimulator(logr.Discard(), newSimulatorClient(t, real), fixture, true).Check(ctx); err != nil {
+		t.Fatalf("expected force to allow simulation, got %v", err)
+	}
+
+	// An existing node of the same name is never taken over, even when forced.
+	taken := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "sim"}}
+	if err := NewSimulator(logr.Discard(), newSimulatorClient(t, taken), fixture, true).Check(ctx); err == nil {
+		t.Fatalf("expected an existing real node to block simulation")
+	}
+
+	// Nodes of a synthetic fleet are fabricated too.
+	leftover := Generate(Options{Nodes: 1}).Nodes[0]
+	if err := NewSimulator(logr.Discard(), newSimulatorClient(t, leftover), fixture, false).Check(ctx); err != nil {
+		t.Fatalf("expected fabricated nodes to be ignored, got %v", err)
+	}
+}
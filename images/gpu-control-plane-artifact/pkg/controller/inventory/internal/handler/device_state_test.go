@@ -19,35 +19,27 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr/testr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/contracts/testkit"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 )
 
 func TestDeviceStateHandlerDefaultsToDiscovered(t *testing.T) {
-	h := NewDeviceStateHandler(testr.New(t))
-	dev := &v1alpha1.GPUDevice{}
+	dev := testkit.NewDevice("worker", 0).State("").Build()
 
-	_, err := h.HandleDevice(context.Background(), dev)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if dev.Status.State != v1alpha1.GPUDeviceStateDiscovered {
-		t.Fatalf("expected state to be Discovered, got %q", dev.Status.State)
-	}
+	outcome := testkit.NewHarness(NewDeviceStateHandler(testr.New(t))).Run(context.Background(), dev)
+	testkit.RequireSucceeded(t, outcome, reconcile.Result{})
+	testkit.RequireState(t, dev, v1alpha1.GPUDeviceStateDiscovered)
 }
 
 func TestDeviceStateHandlerPreservesExistingState(t *testing.T) {
-	h := NewDeviceStateHandler(testr.New(t))
-	dev := &v1alpha1.GPUDevice{}
-	dev.Status.State = v1alpha1.GPUDeviceStateReady
+	dev := testkit.NewDevice("worker", 0).State(v1alpha1.GPUDeviceStateReady).Build()
 
-	_, err := h.HandleDevice(context.Background(), dev)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if dev.Status.State != v1alpha1.GPUDeviceStateReady {
-		t.Fatalf("expected state to stay Ready, got %q", dev.Status.State)
-	}
+	outcome := testkit.NewHarness(NewDeviceStateHandler(testr.New(t))).Run(context.Background(), dev)
+	testkit.RequireSucceeded(t, outcome, reconcile.Result{})
+	testkit.RequireState(t, dev, v1alpha1.GPUDeviceStateReady)
 }
 
 func TestDeviceStateHandlerName(t *testing.T) {
@@ -56,3 +48,14 @@ func TestDeviceStateHandlerName(t *testing.T) {
 		t.Fatalf("unexpected handler name: %q", h.Name())
 	}
 }
+
+func TestDeviceStateHandlerCanBeDisabled(t *testing.T) {
+	dev := testkit.NewDevice("worker", 0).State("").Build()
+
+	outcome := testkit.NewHarness(NewDeviceStateHandler(testr.New(t))).
+		Apply(map[string]moduleconfig.HandlerSettings{"device-state": {Enabled: false}}).
+		Run(context.Background(), dev)
+	if len(outcome.Ran) != 0 || dev.Status.State != "" {
+		t.Fatalf("expected a disabled handler to leave the device alone, ran %v state %q", outcome.Ran, dev.Status.State)
+	}
+}
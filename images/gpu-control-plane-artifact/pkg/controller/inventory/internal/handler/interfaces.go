@@ -16,6 +16,7 @@ package handler
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -26,20 +27,26 @@ import (
 )
 
 type DeviceService interface {
-	Reconcile(
+	ReconcileNode(
 		ctx context.Context,
 		node *corev1.Node,
-		snapshot invstate.DeviceSnapshot,
+		snapshots []invstate.DeviceSnapshot,
 		nodeLabels map[string]string,
 		managed bool,
 		approval invstate.DeviceApprovalPolicy,
+		source *v1alpha1.GPUDataProvenance,
 		applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
-	) (*v1alpha1.GPUDevice, reconcile.Result, error)
+	) ([]*v1alpha1.GPUDevice, reconcile.Result, error)
+	MarkUnreachable(ctx context.Context, node *corev1.Node, since time.Time) (int, reconcile.Result, error)
 }
 
 type InventoryService interface {
 	Reconcile(ctx context.Context, node *corev1.Node, snapshot invstate.NodeSnapshot, devices []*v1alpha1.GPUDevice) error
+	MarkDraining(ctx context.Context, node *corev1.Node, reason string) error
+	MarkNodeFeatureAPIUnsupported(ctx context.Context, node *corev1.Node, message string) error
 	UpdateDeviceMetrics(nodeName string, devices []*v1alpha1.GPUDevice)
+	RecordReconcile(ctx context.Context, nodeName string, reconcileErr error)
+	ReportUntrustedNodeFeatures(ctx context.Context, node *corev1.Node, skipped []string)
 }
 
 type DetectionCollector = invservice.DetectionCollector
@@ -16,18 +16,24 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
 	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
-	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 )
 
 // InventoryHandler reconciles GPUDevice and GPUNodeState resources for a node.
@@ -39,6 +45,10 @@ type InventoryHandler struct {
 	cleanupSvc   CleanupService
 	detectionSvc DetectionCollector
 	recorder     eventrecord.EventRecorderLogger
+	clock        clock.PassiveClock
+	metrics      *invmetrics.Metrics
+	// includeDisplay reports inventory.includeDisplayDevices; nil keeps display-only adapters out.
+	includeDisplay func() bool
 }
 
 func NewInventoryHandler(
@@ -49,6 +59,7 @@ func NewInventoryHandler(
 	cleanupSvc CleanupService,
 	detectionSvc DetectionCollector,
 	recorder eventrecord.EventRecorderLogger,
+	metrics *invmetrics.Metrics,
 ) *InventoryHandler {
 	return &InventoryHandler{
 		log:          log,
@@ -58,9 +69,21 @@ func NewInventoryHandler(
 		cleanupSvc:   cleanupSvc,
 		detectionSvc: detectionSvc,
 		recorder:     recorder,
+		clock:        clock.RealClock{},
+		metrics:      metrics,
 	}
 }
 
+// SetClock replaces the clock used to evaluate how long a node has been NotReady.
+func (h *InventoryHandler) SetClock(c clock.PassiveClock) {
+	h.clock = c
+}
+
+// SetIncludeDisplayDevices supplies whether display-only adapters get GPUDevice objects.
+func (h *InventoryHandler) SetIncludeDisplayDevices(include func() bool) {
+	h.includeDisplay = include
+}
+
 func (h *InventoryHandler) Name() string {
 	return "inventory"
 }
@@ -72,25 +95,63 @@ func (h *InventoryHandler) Handle(ctx context.Context, state invstate.InventoryS
 	}
 	log := logr.FromContextOrDiscard(ctx).WithValues("node", node.Name)
 
-	nodeSnapshot := state.Snapshot()
-	snapshotList := nodeSnapshot.Devices
-
-	if !nodeSnapshot.FeatureDetected && len(snapshotList) == 0 {
-		log.V(1).Info("node feature not detected yet, skip reconcile")
+	// A node that is being deleted or scaled down keeps its last known device states; the Node
+	// delete event triggers the final cleanup reconcile, and removing the taint resumes the normal path.
+	if reason, draining := invstate.NodeDrainReason(node); draining {
+		log.V(1).Info("node is draining, skip inventory collection", "reason", reason)
+		if err := h.inventorySvc.MarkDraining(ctx, node, reason); err != nil {
+			if errors.Is(err, commonerrors.ErrConflictRetryable) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{}, nil
 	}
 
-	var orphanDevices map[string]struct{}
-	if state.AllowCleanup() {
-		var err error
-		orphanDevices, err = state.OrphanDevices(ctx, h.client)
-		if err != nil {
+	// Devices of a node that stays NotReady keep their last known state but stop counting towards
+	// pool capacity, and are deleted once the retention runs out. Events do not fire while a node is
+	// down, so the transitions are driven by time-based requeues.
+	staleness := state.StalenessPolicy().Evaluate(node, h.clock.Now())
+	if staleness.Expired {
+		log.Info("node NotReady beyond stale device retention, removing its inventory", "notReadySince", staleness.NotReadySince)
+		if err := h.cleanupSvc.CleanupNode(ctx, node.Name); err != nil {
+			var throttled *invservice.DeletionsThrottledError
+			if errors.As(err, &throttled) {
+				log.Info("GPU device deletions throttled", "deferred", throttled.Deferred, "retryAfter", throttled.RetryAfter)
+				return reconcile.Result{RequeueAfter: throttled.RetryAfter}, nil
+			}
+			var held *invservice.DevicesInUseError
+			if errors.As(err, &held) {
+				log.Info("GPU devices still in use, deletion deferred", "held", held.Held, "retryAfter", held.RetryAfter)
+				return reconcile.Result{RequeueAfter: held.RetryAfter}, nil
+			}
 			return reconcile.Result{}, err
 		}
+		if h.recorder != nil {
+			h.recorder.WithLogging(log).Eventf(
+				node,
+				corev1.EventTypeNormal,
+				invstate.EventStaleDevicesRemoved,
+				"node %s has been NotReady since %s; its GPU inventory was removed",
+				node.Name,
+				staleness.NotReadySince.UTC().Format(time.RFC3339),
+			)
+		}
+		return reconcile.Result{}, nil
+	}
+	if staleness.Unreachable {
+		return h.markUnreachable(ctx, log, node, staleness)
 	}
 
-	reconciledDevices := make([]*v1alpha1.GPUDevice, 0, len(snapshotList))
-	aggregate := reconcile.Result{}
+	nodeSnapshot := state.Snapshot()
+	snapshotList := nodeSnapshot.Devices
+	h.reportLabelMigration(log, node, nodeSnapshot)
+	logSnapshotIssues(log, nodeSnapshot)
+
+	if !nodeSnapshot.FeatureDetected && len(snapshotList) == 0 {
+		log.V(1).Info("node feature not detected yet, skip reconcile")
+		return reconcile.Result{}, fmt.Errorf("node %s: %w", node.Name, commonerrors.ErrNodeFeatureMissing)
+	}
 
 	var detections invservice.NodeDetection
 	if state.HasDevices() {
@@ -111,28 +172,33 @@ func (h *InventoryHandler) Handle(ctx context.Context, state invstate.InventoryS
 		}
 	}
 
-	for _, snapshot := range snapshotList {
-		device, res, err := h.deviceSvc.Reconcile(ctx, node, snapshot, nodeSnapshot.Labels, nodeSnapshot.Managed, state.ApprovalPolicy(), func(device *v1alpha1.GPUDevice, snapshot invstate.DeviceSnapshot) {
-			invservice.ApplyDetection(device, snapshot, detections)
-		})
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-		if orphanDevices != nil && device != nil {
-			delete(orphanDevices, device.Name)
-		}
-		reconciledDevices = append(reconciledDevices, device)
-		aggregate = ctrlreconciler.MergeResults(aggregate, res)
+	nodeSnapshot.Platform = detections.Platform()
+	nodeSnapshot.Compatibility = state.CompatibilityPolicy().Evaluate(invstate.NodeCompatibilityFacts(node, nodeSnapshot.Driver, nodeSnapshot.Platform))
+	previous, err := h.previousNodeState(ctx, node.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	computeList, displayDevices := invservice.ClassifyDisplayDevices(snapshotList, detections, previous)
+	nodeSnapshot.DisplayDevices = displayDevices
+	if len(displayDevices) > 0 {
+		log.V(1).Info("display-only adapters found", "devices", len(displayDevices), "included", h.includeDisplayDevices())
+	}
+	if !h.includeDisplayDevices() {
+		snapshotList = computeList
+		nodeSnapshot.Devices = computeList
 	}
 
-	if node.GetDeletionTimestamp() != nil {
-		if err := h.cleanupSvc.RemoveOrphans(ctx, node, orphanDevices); err != nil {
-			return reconcile.Result{}, err
-		}
+	reconciledDevices, aggregate, err := h.deviceSvc.ReconcileNode(ctx, node, snapshotList, nodeSnapshot.Labels, nodeSnapshot.Managed, state.ApprovalPolicy(), nodeSnapshot.Provenance, func(device *v1alpha1.GPUDevice, snapshot invstate.DeviceSnapshot) {
+		device.Status.DriverVersion = nodeSnapshot.Driver.Version
+		invservice.ApplyDetection(device, snapshot, detections)
+		invservice.ApplyCompatibility(device, nodeSnapshot.Compatibility)
+	})
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	if err := h.inventorySvc.Reconcile(ctx, node, nodeSnapshot, reconciledDevices); err != nil {
-		if apierrors.IsConflict(err) {
+		if errors.Is(err, commonerrors.ErrConflictRetryable) {
 			return reconcile.Result{Requeue: true}, nil
 		}
 		return reconcile.Result{}, err
@@ -150,6 +216,10 @@ func (h *InventoryHandler) Handle(ctx context.Context, state invstate.InventoryS
 		}
 	}
 
+	if staleness.RequeueAfter > 0 && (ctrlResult.RequeueAfter == 0 || staleness.RequeueAfter < ctrlResult.RequeueAfter) {
+		ctrlResult.RequeueAfter = staleness.RequeueAfter
+	}
+
 	if ctrlResult.Requeue || ctrlResult.RequeueAfter > 0 {
 		log.V(1).Info("inventory reconcile scheduled follow-up", "requeue", ctrlResult.Requeue, "after", ctrlResult.RequeueAfter)
 	} else {
@@ -158,3 +228,71 @@ func (h *InventoryHandler) Handle(ctx context.Context, state invstate.InventoryS
 
 	return ctrlResult, nil
 }
+
+func (h *InventoryHandler) includeDisplayDevices() bool {
+	return h.includeDisplay != nil && h.includeDisplay()
+}
+
+// previousNodeState reads the GPUNodeState the last reconcile left; nil when there is none yet.
+func (h *InventoryHandler) previousNodeState(ctx context.Context, name string) (*v1alpha1.GPUNodeState, error) {
+	if h.client == nil {
+		return nil, nil
+	}
+	return commonobject.FetchObject(ctx, types.NamespacedName{Name: name}, h.client, &v1alpha1.GPUNodeState{})
+}
+
+func (h *InventoryHandler) markUnreachable(ctx context.Context, log logr.Logger, node *corev1.Node, staleness invstate.NodeStaleness) (reconcile.Result, error) {
+	marked, result, err := h.deviceSvc.MarkUnreachable(ctx, node, staleness.NotReadySince)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if marked > 0 {
+		log.Info("node NotReady beyond stale threshold, devices marked unreachable", "devices", marked, "notReadySince", staleness.NotReadySince)
+		if h.recorder != nil {
+			h.recorder.WithLogging(log).Eventf(
+				node,
+				corev1.EventTypeWarning,
+				invstate.EventNodeUnreachable,
+				"node %s has been NotReady since %s; %d GPU device(s) excluded from pool capacity",
+				node.Name,
+				staleness.NotReadySince.UTC().Format(time.RFC3339),
+				marked,
+			)
+		}
+	}
+	if staleness.RequeueAfter > 0 && (result.RequeueAfter == 0 || staleness.RequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = staleness.RequeueAfter
+	}
+	return result, nil
+}
+
+// logSnapshotIssues reports labels and instance attributes the snapshot parser could not use.
+func logSnapshotIssues(log logr.Logger, snapshot invstate.NodeSnapshot) {
+	for _, issue := range snapshot.Errors {
+		log.Info("GPU device dropped from hardware snapshot", "issue", issue.String())
+	}
+	for _, issue := range snapshot.Warnings {
+		log.V(1).Info("malformed hardware attribute ignored", "issue", issue.String())
+	}
+}
+
+// reportLabelMigration surfaces nodes that still carry only the previous managed-node label key
+// while the transition window is open, so they can be relabeled before it closes. The gauge tracks
+// the node on every reconcile; the event fires only when the key first appears or changes.
+func (h *InventoryHandler) reportLabelMigration(log logr.Logger, node *corev1.Node, snapshot invstate.NodeSnapshot) {
+	if snapshot.UnmigratedLabelKey == "" {
+		h.metrics.InventoryUnmigratedLabelKeyDelete(node.Name)
+		return
+	}
+	changed := h.metrics.InventoryUnmigratedLabelKeySet(node.Name, snapshot.UnmigratedLabelKey)
+	if changed && h.recorder != nil {
+		h.recorder.WithLogging(log).Eventf(
+			node,
+			corev1.EventTypeWarning,
+			invstate.EventLabelNotMigrated,
+			"node %s carries only the previous managed-node label %s; relabel it before the transition window closes",
+			node.Name,
+			snapshot.UnmigratedLabelKey,
+		)
+	}
+}
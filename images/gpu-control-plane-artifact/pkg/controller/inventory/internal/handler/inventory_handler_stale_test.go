@@ -0,0 +1,178 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+func notReadyNode(name string, since time.Time) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+			Type:               corev1.NodeReady,
+			Status:             corev1.ConditionUnknown,
+			LastTransitionTime: metav1.NewTime(since),
+		}}},
+	}
+}
+
+func staleTestState(node *corev1.Node, policy invstate.StalenessPolicy) stubState {
+	state := drainingTestState(node)
+	state.staleness = policy
+	return state
+}
+
+func TestInventoryHandlerStaleNodeLifecycle(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(since.Add(time.Hour))
+	node := notReadyNode("node-stale", since)
+	policy := invstate.StalenessPolicy{Threshold: 24 * time.Hour, Retention: 48 * time.Hour}
+
+	rec := record.NewFakeRecorder(4)
+	recorder := eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: rec}, "test")
+	deviceSvc := &stubDeviceService{unreachable: 1}
+	inventorySvc := &stubInventoryService{}
+	cleanupSvc := &stubCleanupService{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, cleanupSvc, &stubDetectionCollector{}, recorder, nil)
+	handler.SetClock(clock)
+
+	// NotReady for an hour: the regular path runs and a requeue is scheduled for the threshold.
+	res, err := handler.Handle(context.Background(), staleTestState(node, policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.calls != 1 || deviceSvc.unreachableCalls != 0 {
+		t.Fatalf("expected regular reconcile before the threshold, got reconcile=%d unreachable=%d", deviceSvc.calls, deviceSvc.unreachableCalls)
+	}
+	if res.RequeueAfter != 23*time.Hour {
+		t.Fatalf("expected requeue at the threshold, got %+v", res)
+	}
+
+	// Past the threshold: devices are marked, the regular path is skipped until retention runs out.
+	clock.SetTime(since.Add(25 * time.Hour))
+	res, err = handler.Handle(context.Background(), staleTestState(node, policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.calls != 1 || deviceSvc.unreachableCalls != 1 || !deviceSvc.unreachableSince.Equal(since) {
+		t.Fatalf("expected devices to be marked unreachable, got reconcile=%d unreachable=%d since=%s", deviceSvc.calls, deviceSvc.unreachableCalls, deviceSvc.unreachableSince)
+	}
+	if res.RequeueAfter != 47*time.Hour {
+		t.Fatalf("expected requeue at the retention deadline, got %+v", res)
+	}
+	select {
+	case event := <-rec.Events:
+		if !strings.Contains(event, invstate.EventNodeUnreachable) {
+			t.Fatalf("unexpected event: %s", event)
+		}
+	default:
+		t.Fatalf("expected an event for the unreachable node")
+	}
+
+	// Retention expired: the inventory of the node is removed.
+	clock.SetTime(since.Add(72 * time.Hour))
+	res, err = handler.Handle(context.Background(), staleTestState(node, policy))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cleanupSvc.cleanupNodes) != 1 || cleanupSvc.cleanupNodes[0] != node.Name {
+		t.Fatalf("expected node inventory cleanup, got %v", cleanupSvc.cleanupNodes)
+	}
+	if res.RequeueAfter != 0 || deviceSvc.calls != 1 {
+		t.Fatalf("expected no further work after cleanup, got %+v reconcile=%d", res, deviceSvc.calls)
+	}
+
+	// The node comes back: the regular path recreates devices and clears the condition.
+	node.Status.Conditions[0].Status = corev1.ConditionTrue
+	if _, err := handler.Handle(context.Background(), staleTestState(node, policy)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.calls != 2 || deviceSvc.unreachableCalls != 1 || len(cleanupSvc.cleanupNodes) != 1 {
+		t.Fatalf("expected regular reconcile after recovery, got reconcile=%d unreachable=%d cleanup=%d", deviceSvc.calls, deviceSvc.unreachableCalls, len(cleanupSvc.cleanupNodes))
+	}
+}
+
+func TestInventoryHandlerKeepsUnreachableDevicesWithoutRetention(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(since.Add(30 * 24 * time.Hour))
+	node := notReadyNode("node-kept", since)
+
+	deviceSvc := &stubDeviceService{}
+	cleanupSvc := &stubCleanupService{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, &stubInventoryService{}, cleanupSvc, &stubDetectionCollector{}, nil, nil)
+	handler.SetClock(clock)
+
+	res, err := handler.Handle(context.Background(), staleTestState(node, invstate.StalenessPolicy{Threshold: time.Hour}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.unreachableCalls != 1 || len(cleanupSvc.cleanupNodes) != 0 || res.RequeueAfter != 0 {
+		t.Fatalf("expected devices to stay marked without a deletion requeue, got unreachable=%d cleanup=%v result=%+v", deviceSvc.unreachableCalls, cleanupSvc.cleanupNodes, res)
+	}
+}
+
+func TestInventoryHandlerRequeuesThrottledStaleCleanup(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	node := notReadyNode("node-throttled", since)
+
+	rec := record.NewFakeRecorder(4)
+	recorder := eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: rec}, "test")
+	cleanupSvc := &stubCleanupService{cleanupErr: &invservice.DeletionsThrottledError{Node: node.Name, Deferred: 3, RetryAfter: 7 * time.Minute}}
+	deviceSvc := &stubDeviceService{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, &stubInventoryService{}, cleanupSvc, &stubDetectionCollector{}, recorder, nil)
+	handler.SetClock(clocktesting.NewFakePassiveClock(since.Add(72 * time.Hour)))
+
+	res, err := handler.Handle(context.Background(), staleTestState(node, invstate.StalenessPolicy{Threshold: time.Hour, Retention: 24 * time.Hour}))
+	if err != nil {
+		t.Fatalf("throttled deletions must not surface as an error, got %v", err)
+	}
+	if res.RequeueAfter != 7*time.Minute || len(cleanupSvc.cleanupNodes) != 1 || deviceSvc.calls != 0 {
+		t.Fatalf("expected a requeue when the limiter frees up, got %+v cleanup=%v reconcile=%d", res, cleanupSvc.cleanupNodes, deviceSvc.calls)
+	}
+	if len(rec.Events) != 0 {
+		t.Fatalf("expected no removal event while deletions are deferred, got %d", len(rec.Events))
+	}
+}
+
+func TestInventoryHandlerIgnoresNotReadyNodesWhenDisabled(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	node := notReadyNode("node-disabled", since)
+
+	deviceSvc := &stubDeviceService{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, &stubInventoryService{}, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
+	handler.SetClock(clocktesting.NewFakePassiveClock(since.Add(365 * 24 * time.Hour)))
+
+	res, err := handler.Handle(context.Background(), staleTestState(node, invstate.StalenessPolicy{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.calls != 1 || deviceSvc.unreachableCalls != 0 || res.RequeueAfter != 0 {
+		t.Fatalf("expected regular reconcile with the policy disabled, got reconcile=%d unreachable=%d result=%+v", deviceSvc.calls, deviceSvc.unreachableCalls, res)
+	}
+}
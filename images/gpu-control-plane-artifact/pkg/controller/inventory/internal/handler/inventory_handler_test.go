@@ -17,21 +17,28 @@ package handler
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr/testr"
+	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
 	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
@@ -40,20 +47,19 @@ type stubState struct {
 	node          *corev1.Node
 	snapshot      invstate.NodeSnapshot
 	approval      invstate.DeviceApprovalPolicy
-	allowCleanup  bool
-	orphanDevices map[string]struct{}
-	orphanErr     error
+	staleness     invstate.StalenessPolicy
+	compatibility invstate.CompatibilityPolicy
 }
 
 func (s stubState) Node() *corev1.Node                            { return s.node }
 func (s stubState) NodeFeature() *nfdv1alpha1.NodeFeature         { return nil }
 func (s stubState) Snapshot() invstate.NodeSnapshot               { return s.snapshot }
 func (s stubState) ApprovalPolicy() invstate.DeviceApprovalPolicy { return s.approval }
-func (s stubState) AllowCleanup() bool                            { return s.allowCleanup }
-func (s stubState) HasDevices() bool                              { return len(s.snapshot.Devices) > 0 }
-func (s stubState) OrphanDevices(context.Context, client.Client) (map[string]struct{}, error) {
-	return s.orphanDevices, s.orphanErr
+func (s stubState) StalenessPolicy() invstate.StalenessPolicy     { return s.staleness }
+func (s stubState) CompatibilityPolicy() invstate.CompatibilityPolicy {
+	return s.compatibility
 }
+func (s stubState) HasDevices() bool { return len(s.snapshot.Devices) > 0 }
 
 type stubDeviceService struct {
 	calls       int
@@ -61,37 +67,69 @@ type stubDeviceService struct {
 	result      reconcile.Result
 	err         error
 	applyCalled bool
+	snapshots   []invstate.DeviceSnapshot
+
+	unreachableCalls int
+	unreachableSince time.Time
+	unreachable      int
 }
 
-func (s *stubDeviceService) Reconcile(
+func (s *stubDeviceService) ReconcileNode(
 	_ context.Context,
 	_ *corev1.Node,
-	snapshot invstate.DeviceSnapshot,
+	snapshots []invstate.DeviceSnapshot,
 	_ map[string]string,
 	_ bool,
 	_ invstate.DeviceApprovalPolicy,
+	_ *v1alpha1.GPUDataProvenance,
 	applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
-) (*v1alpha1.GPUDevice, reconcile.Result, error) {
+) ([]*v1alpha1.GPUDevice, reconcile.Result, error) {
 	s.calls++
-	device := s.device
-	if device == nil {
-		device = &v1alpha1.GPUDevice{}
+	s.snapshots = snapshots
+	devices := make([]*v1alpha1.GPUDevice, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		device := s.device
+		if device == nil {
+			device = &v1alpha1.GPUDevice{}
+		}
+		if applyDetection != nil {
+			s.applyCalled = true
+			applyDetection(device, snapshot)
+		}
+		devices = append(devices, device)
 	}
-	if applyDetection != nil {
-		s.applyCalled = true
-		applyDetection(device, snapshot)
+	if s.err != nil {
+		return nil, s.result, s.err
 	}
-	return device, s.result, s.err
+	return devices, s.result, nil
+}
+
+func (s *stubDeviceService) MarkUnreachable(_ context.Context, _ *corev1.Node, since time.Time) (int, reconcile.Result, error) {
+	s.unreachableCalls++
+	s.unreachableSince = since
+	return s.unreachable, reconcile.Result{}, s.err
 }
 
 type stubInventoryService struct {
 	calls        int
 	metricsCalls int
+	drainReason  string
+	snapshot     invstate.NodeSnapshot
 	err          error
 }
 
-func (s *stubInventoryService) Reconcile(context.Context, *corev1.Node, invstate.NodeSnapshot, []*v1alpha1.GPUDevice) error {
+func (s *stubInventoryService) Reconcile(_ context.Context, _ *corev1.Node, snapshot invstate.NodeSnapshot, _ []*v1alpha1.GPUDevice) error {
 	s.calls++
+	s.snapshot = snapshot
+	return s.err
+}
+
+func (s *stubInventoryService) MarkDraining(_ context.Context, _ *corev1.Node, reason string) error {
+	s.drainReason = reason
+	return s.err
+}
+
+func (s *stubInventoryService) MarkNodeFeatureAPIUnsupported(context.Context, *corev1.Node, string) error {
 	return s.err
 }
 
@@ -99,22 +137,24 @@ func (s *stubInventoryService) UpdateDeviceMetrics(string, []*v1alpha1.GPUDevice
 	s.metricsCalls++
 }
 
+func (s *stubInventoryService) RecordReconcile(context.Context, string, error)                      {}
+func (s *stubInventoryService) ReportUntrustedNodeFeatures(context.Context, *corev1.Node, []string) {}
+
 type stubCleanupService struct {
-	calls       int
-	lastOrphans map[string]struct{}
-	err         error
+	calls        int
+	cleanupNodes []string
+	err          error
+	cleanupErr   error
 }
 
-func (s *stubCleanupService) CleanupNode(context.Context, string) error { return nil }
+func (s *stubCleanupService) CleanupNode(_ context.Context, nodeName string) error {
+	s.cleanupNodes = append(s.cleanupNodes, nodeName)
+	return s.cleanupErr
+}
 func (s *stubCleanupService) DeleteInventory(context.Context, string) error {
 	return nil
 }
 func (s *stubCleanupService) ClearMetrics(string) {}
-func (s *stubCleanupService) RemoveOrphans(_ context.Context, _ *corev1.Node, orphans map[string]struct{}) error {
-	s.calls++
-	s.lastOrphans = orphans
-	return s.err
-}
 
 type stubDetectionCollector struct {
 	calls int
@@ -137,10 +177,10 @@ func TestInventoryHandlerSkipsWhenNoFeatureAndNoDevices(t *testing.T) {
 	cleanupSvc := &stubCleanupService{}
 	detectionSvc := &stubDetectionCollector{}
 
-	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, cleanupSvc, detectionSvc, nil)
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, cleanupSvc, detectionSvc, nil, nil)
 	res, err := handler.Handle(context.Background(), state)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if !errors.Is(err, commonerrors.ErrNodeFeatureMissing) {
+		t.Fatalf("expected ErrNodeFeatureMissing, got %v", err)
 	}
 	if res != (reconcile.Result{}) {
 		t.Fatalf("expected empty result, got %+v", res)
@@ -169,7 +209,7 @@ func TestInventoryHandlerMergesRequeueResults(t *testing.T) {
 		result: reconcile.Result{RequeueAfter: 2 * time.Second},
 	}
 	inventorySvc := &stubInventoryService{}
-	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil)
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
 
 	res, err := handler.Handle(context.Background(), state)
 	if err != nil {
@@ -200,10 +240,10 @@ func TestInventoryHandlerReturnsRequeueOnInventoryConflict(t *testing.T) {
 	}
 	deviceSvc := &stubDeviceService{}
 	inventorySvc := &stubInventoryService{
-		err: apierrors.NewConflict(schema.GroupResource{Group: "gpu.deckhouse.io", Resource: "gpunodestates"}, "node-conflict", errors.New("conflict")),
+		err: commonerrors.WrapConflict(apierrors.NewConflict(schema.GroupResource{Group: "gpu.deckhouse.io", Resource: "gpunodestates"}, "node-conflict", errors.New("conflict"))),
 	}
 
-	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil)
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
 	res, err := handler.Handle(context.Background(), state)
 	if err != nil {
 		t.Fatalf("expected conflict to be swallowed, got %v", err)
@@ -213,21 +253,9 @@ func TestInventoryHandlerReturnsRequeueOnInventoryConflict(t *testing.T) {
 	}
 }
 
-func TestInventoryHandlerCleansOrphansOnNodeDeletion(t *testing.T) {
-	now := metav1.Now()
-	node := &corev1.Node{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:              "node-delete",
-			DeletionTimestamp: &now,
-		},
-	}
-	state := stubState{
-		node:         node,
-		allowCleanup: true,
-		orphanDevices: map[string]struct{}{
-			"device-a": {},
-			"device-b": {},
-		},
+func drainingTestState(node *corev1.Node) stubState {
+	return stubState{
+		node: node,
 		snapshot: invstate.NodeSnapshot{
 			FeatureDetected: true,
 			Managed:         true,
@@ -239,24 +267,83 @@ func TestInventoryHandlerCleansOrphansOnNodeDeletion(t *testing.T) {
 			}},
 		},
 	}
+}
 
-	deviceSvc := &stubDeviceService{
-		device: &v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{Name: "device-a"}},
+func TestInventoryHandlerFreezesOnNodeDeletion(t *testing.T) {
+	now := metav1.Now()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "node-delete",
+			DeletionTimestamp: &now,
+		},
 	}
-	cleanupSvc := &stubCleanupService{}
-	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, &stubInventoryService{}, cleanupSvc, &stubDetectionCollector{}, nil)
 
-	if _, err := handler.Handle(context.Background(), state); err != nil {
+	deviceSvc := &stubDeviceService{}
+	inventorySvc := &stubInventoryService{}
+	detection := &stubDetectionCollector{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, detection, nil, nil)
+
+	if _, err := handler.Handle(context.Background(), drainingTestState(node)); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if cleanupSvc.calls != 1 {
-		t.Fatalf("expected cleanup service to be called, got %d", cleanupSvc.calls)
+	if deviceSvc.calls != 0 || detection.calls != 0 || inventorySvc.calls != 0 {
+		t.Fatalf("expected device state to be frozen, got device=%d detection=%d inventory=%d", deviceSvc.calls, detection.calls, inventorySvc.calls)
 	}
-	if _, ok := cleanupSvc.lastOrphans["device-a"]; ok {
-		t.Fatalf("expected reconciled device to be removed from orphan set")
+	if inventorySvc.drainReason != invstate.ReasonNodeDeleting {
+		t.Fatalf("expected NodeDeleting drain reason, got %q", inventorySvc.drainReason)
+	}
+}
+
+func TestInventoryHandlerFreezesOnAutoscalerTaint(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-scale-down"},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{{
+			Key:    invstate.ToBeDeletedByAutoscalerKey,
+			Effect: corev1.TaintEffectNoSchedule,
+		}}},
+	}
+
+	deviceSvc := &stubDeviceService{}
+	inventorySvc := &stubInventoryService{}
+	detection := &stubDetectionCollector{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, detection, nil, nil)
+
+	if _, err := handler.Handle(context.Background(), drainingTestState(node)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.calls != 0 || detection.calls != 0 {
+		t.Fatalf("expected telemetry collection to be skipped, got device=%d detection=%d", deviceSvc.calls, detection.calls)
+	}
+	if inventorySvc.drainReason != invstate.ReasonAutoscalerScaleDown {
+		t.Fatalf("expected autoscaler drain reason, got %q", inventorySvc.drainReason)
+	}
+
+	// Scale-down aborted: the taint is gone and the normal path resumes.
+	node.Spec.Taints = nil
+	inventorySvc.drainReason = ""
+	if _, err := handler.Handle(context.Background(), drainingTestState(node)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deviceSvc.calls != 1 || detection.calls != 1 || inventorySvc.calls != 1 {
+		t.Fatalf("expected reconciliation to resume, got device=%d detection=%d inventory=%d", deviceSvc.calls, detection.calls, inventorySvc.calls)
+	}
+	if inventorySvc.drainReason != "" {
+		t.Fatalf("expected node not to be marked draining after abort")
+	}
+}
+
+func TestInventoryHandlerRequeuesOnDrainingConflict(t *testing.T) {
+	now := metav1.Now()
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-conflict", DeletionTimestamp: &now}}
+	inventorySvc := &stubInventoryService{err: commonerrors.WrapConflict(apierrors.NewConflict(schema.GroupResource{Resource: "gpunodestates"}, node.Name, errors.New("conflict")))}
+	handler := NewInventoryHandler(testr.New(t), nil, &stubDeviceService{}, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
+
+	res, err := handler.Handle(context.Background(), drainingTestState(node))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if _, ok := cleanupSvc.lastOrphans["device-b"]; !ok {
-		t.Fatalf("expected remaining orphan to be present")
+	if !res.Requeue {
+		t.Fatalf("expected requeue on conflict, got %+v", res)
 	}
 }
 
@@ -273,12 +360,13 @@ func TestInventoryHandlerCallsDetectionCollectorWhenDevicesPresent(t *testing.T)
 				Device: "2203",
 				Class:  "0302",
 			}},
+			Driver: invstate.NodeDriverSnapshot{Version: "535.104.05"},
 		},
 	}
 
-	deviceSvc := &stubDeviceService{}
+	deviceSvc := &stubDeviceService{device: &v1alpha1.GPUDevice{}}
 	detectionSvc := &stubDetectionCollector{}
-	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, &stubInventoryService{}, &stubCleanupService{}, detectionSvc, nil)
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, &stubInventoryService{}, &stubCleanupService{}, detectionSvc, nil, nil)
 
 	if _, err := handler.Handle(context.Background(), state); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -289,10 +377,71 @@ func TestInventoryHandlerCallsDetectionCollectorWhenDevicesPresent(t *testing.T)
 	if !deviceSvc.applyCalled {
 		t.Fatalf("expected applyDetection to be invoked by device service")
 	}
+	if deviceSvc.device.Status.DriverVersion != "535.104.05" {
+		t.Fatalf("expected node driver version on the device, got %q", deviceSvc.device.Status.DriverVersion)
+	}
+}
+
+func TestInventoryHandlerBlocksIncompatibleNode(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-blocked"}}
+	node.Status.NodeInfo.KernelVersion = "6.8.0-45-generic"
+	state := stubState{
+		node: node,
+		snapshot: invstate.NodeSnapshot{
+			FeatureDetected: true,
+			Managed:         true,
+			Devices:         []invstate.DeviceSnapshot{{Index: "0", Vendor: "10de", Device: "2203", Class: "0302"}},
+			Driver:          invstate.NodeDriverSnapshot{Version: "535.104.05", ToolkitVersion: "1.14.2"},
+		},
+		compatibility: invstate.NewCompatibilityPolicy([]moduleconfig.CompatibilityRule{
+			{Name: "kernel", KernelRange: ">=6.8", Effect: moduleconfig.CompatibilityEffectWarn},
+		}),
+	}
+
+	deviceSvc := &stubDeviceService{device: &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{AutoAttach: true}}}
+	inventorySvc := &stubInventoryService{}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
+
+	if _, err := handler.Handle(context.Background(), state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verdict := inventorySvc.snapshot.Compatibility; !verdict.Blocked() || len(verdict.Matched) != 2 {
+		t.Fatalf("expected the default Block rule and the kernel Warn rule to match, got %+v", verdict)
+	}
+	if deviceSvc.device.Status.AutoAttach {
+		t.Fatalf("expected AutoAttach to be held back on a blocked node")
+	}
+	if !apimeta.IsStatusConditionTrue(deviceSvc.device.Status.Conditions, invstate.ConditionCompatibilityBlocked) {
+		t.Fatalf("expected the CompatibilityBlocked condition, got %+v", deviceSvc.device.Status.Conditions)
+	}
+}
+
+func TestInventoryHandlerCompletesWhenDetectionHitsItsDeadline(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-stuck"}}
+	state := stubState{
+		node: node,
+		snapshot: invstate.NodeSnapshot{
+			FeatureDetected: true,
+			Managed:         true,
+			Devices:         []invstate.DeviceSnapshot{{Index: "0", Vendor: "10de", Device: "2203", Class: "0302"}},
+		},
+	}
+
+	deviceSvc := &stubDeviceService{device: &v1alpha1.GPUDevice{}}
+	inventorySvc := &stubInventoryService{}
+	detectionSvc := &stubDetectionCollector{err: commonerrors.Wrap(commonerrors.ErrTelemetryUnavailable, httpcall.ErrDeadlineExceeded)}
+	handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, detectionSvc, nil, nil)
+
+	if _, err := handler.Handle(context.Background(), state); err != nil {
+		t.Fatalf("expected the reconcile to complete without detections, got %v", err)
+	}
+	if detectionSvc.calls != 1 || inventorySvc.calls == 0 {
+		t.Fatalf("expected the node to be reconciled after the failed scrape, got detection=%d inventory=%d", detectionSvc.calls, inventorySvc.calls)
+	}
 }
 
 func TestInventoryHandlerSkipsWhenNodeMissing(t *testing.T) {
-	handler := NewInventoryHandler(testr.New(t), nil, &stubDeviceService{}, &stubInventoryService{}, &stubCleanupService{}, &stubDetectionCollector{}, nil)
+	handler := NewInventoryHandler(testr.New(t), nil, &stubDeviceService{}, &stubInventoryService{}, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
 	res, err := handler.Handle(context.Background(), stubState{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -302,26 +451,108 @@ func TestInventoryHandlerSkipsWhenNodeMissing(t *testing.T) {
 	}
 }
 
-func TestInventoryHandlerOrphanDevicesError(t *testing.T) {
-	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-orphan-error", UID: types.UID("node-orphan-error")}}
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
+func TestInventoryHandlerReportsUnmigratedLabelKey(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-legacy"}}
 	state := stubState{
-		node:         node,
-		allowCleanup: true,
-		orphanErr:    errors.New("orphan list failed"),
+		node: node,
+		snapshot: invstate.NodeSnapshot{
+			FeatureDetected:    true,
+			Managed:            true,
+			UnmigratedLabelKey: "gpu.deckhouse.io/enabled",
+		},
+	}
+	rec := record.NewFakeRecorder(8)
+	recorder := eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: rec}, "test")
+	metrics, err := invmetrics.New(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+
+	handler := NewInventoryHandler(testr.New(t), nil, &stubDeviceService{}, &stubInventoryService{}, &stubCleanupService{}, &stubDetectionCollector{}, recorder, metrics)
+	handle := func() {
+		t.Helper()
+		if _, err := handler.Handle(context.Background(), state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expectEvent := func(key string) {
+		t.Helper()
+		select {
+		case event := <-rec.Events:
+			if !strings.Contains(event, invstate.EventLabelNotMigrated) || !strings.Contains(event, key) {
+				t.Fatalf("unexpected event: %s", event)
+			}
+		default:
+			t.Fatalf("expected an event for the unmigrated node")
+		}
+	}
+	expectNoEvent := func() {
+		t.Helper()
+		select {
+		case event := <-rec.Events:
+			t.Fatalf("unexpected event: %s", event)
+		default:
+		}
+	}
+
+	handle()
+	expectEvent("gpu.deckhouse.io/enabled")
+	handle()
+	expectNoEvent()
+
+	state.snapshot.UnmigratedLabelKey = "gpu.deckhouse.io/managed"
+	handle()
+	expectEvent("gpu.deckhouse.io/managed")
+
+	// Once migrated, the node is reported again if it ever falls back to the previous key.
+	state.snapshot.UnmigratedLabelKey = ""
+	handle()
+	expectNoEvent()
+	state.snapshot.UnmigratedLabelKey = "gpu.deckhouse.io/managed"
+	handle()
+	expectEvent("gpu.deckhouse.io/managed")
+}
+
+func TestInventoryHandlerExcludesDisplayOnlyAdapters(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	state := stubState{
+		node: node,
 		snapshot: invstate.NodeSnapshot{
 			FeatureDetected: true,
-			Managed:         true,
-			Devices: []invstate.DeviceSnapshot{{
-				Index:  "0",
-				Vendor: "10de",
-				Device: "2203",
-				Class:  "0302",
-			}},
+			Devices: []invstate.DeviceSnapshot{
+				{Index: "0", Vendor: "10de", Device: "2330", Class: "0302"},
+				{Index: "1", Vendor: "1a03", Device: "2000", Class: "0300", Product: "ASPEED Graphics Family"},
+			},
 		},
 	}
 
-	handler := NewInventoryHandler(testr.New(t), nil, &stubDeviceService{}, &stubInventoryService{}, &stubCleanupService{}, &stubDetectionCollector{}, nil)
-	if _, err := handler.Handle(context.Background(), state); err == nil {
-		t.Fatalf("expected orphan list error")
+	for _, include := range []bool{false, true} {
+		deviceSvc := &stubDeviceService{}
+		inventorySvc := &stubInventoryService{}
+		handler := NewInventoryHandler(testr.New(t), nil, deviceSvc, inventorySvc, &stubCleanupService{}, &stubDetectionCollector{}, nil, nil)
+		handler.SetIncludeDisplayDevices(func() bool { return include })
+
+		if _, err := handler.Handle(context.Background(), state); err != nil {
+			t.Fatalf("include=%t: unexpected error: %v", include, err)
+		}
+		wantDevices := 1
+		if include {
+			wantDevices = 2
+		}
+		if len(deviceSvc.snapshots) != wantDevices {
+			t.Fatalf("include=%t: expected %d devices reconciled, got %+v", include, wantDevices, deviceSvc.snapshots)
+		}
+		display := inventorySvc.snapshot.DisplayDevices
+		if len(display) != 1 || display[0].Index != "1" || display[0].PCI.Vendor != "1a03" {
+			t.Fatalf("include=%t: expected the ASPEED adapter to be listed as display-only, got %+v", include, display)
+		}
 	}
 }
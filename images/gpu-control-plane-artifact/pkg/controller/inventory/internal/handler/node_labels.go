@@ -0,0 +1,171 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/patch"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+var labelPathEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// NodeLabelsHandler projects inventory facts onto Node labels for nodeSelector-based scheduling.
+// It only ever writes the keys in invstate.NodeCapabilityLabelKeys.
+type NodeLabelsHandler struct {
+	client  client.Client
+	enabled func() bool
+	managed func() invstate.ManagedNodesPolicy
+
+	mu      sync.RWMutex
+	skipped []string
+}
+
+// nodeLabelsSettings is the settings.handlers.node-labels.settings blob.
+type nodeLabelsSettings struct {
+	// SkipLabels lists capability labels the handler leaves alone, for clusters where another labeler owns them.
+	SkipLabels []string `json:"skipLabels,omitempty"`
+}
+
+// NewNodeLabelsHandler builds the handler; enabled reports nodeLabeling.enabled and managed the
+// current managed-nodes policy, whose label keys are never touched.
+func NewNodeLabelsHandler(client client.Client, enabled func() bool, managed func() invstate.ManagedNodesPolicy) *NodeLabelsHandler {
+	return &NodeLabelsHandler{client: client, enabled: enabled, managed: managed}
+}
+
+func (h *NodeLabelsHandler) Name() string {
+	return "node-labels"
+}
+
+// Configure applies the handler settings from ModuleConfig; an empty blob restores the defaults. Keys outside
+// invstate.NodeCapabilityLabelKeys are rejected and the previous settings stay in effect.
+func (h *NodeLabelsHandler) Configure(settings json.RawMessage) error {
+	var parsed nodeLabelsSettings
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &parsed); err != nil {
+			return fmt.Errorf("decode settings: %w", err)
+		}
+	}
+	for _, key := range parsed.SkipLabels {
+		if !slices.Contains(invstate.NodeCapabilityLabelKeys, key) {
+			return fmt.Errorf("skipLabels: %q is not a label this handler writes", key)
+		}
+	}
+	h.mu.Lock()
+	h.skipped = parsed.SkipLabels
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *NodeLabelsHandler) Handle(ctx context.Context, state invstate.InventoryState) (reconcile.Result, error) {
+	node := state.Node()
+	if node == nil {
+		return reconcile.Result{}, nil
+	}
+
+	// Disabling the feature strips the labels it wrote.
+	var desired map[string]string
+	if h.enabled != nil && h.enabled() {
+		snapshot := state.Snapshot()
+		if !snapshot.FeatureDetected && len(snapshot.Devices) == 0 {
+			return reconcile.Result{}, nil
+		}
+		desired = invstate.NodeCapabilityLabels(snapshot.Devices)
+		// The runtime label lets per-runtime workloads select GPU nodes, so it follows the GPU labels.
+		if value := containerruntime.FromNode(node).LabelValue(); value != "" && len(desired) > 0 {
+			desired[invstate.NodeContainerRuntimeLabelKey] = value
+		}
+	}
+
+	ops := nodeLabelOps(node.Labels, desired, h.reservedKeys())
+	if ops.Len() == 0 {
+		return reconcile.Result{}, nil
+	}
+	data, err := ops.Bytes()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := h.client.Patch(ctx, node, client.RawPatch(types.JSONPatchType, data)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("patch node %s capability labels: %w", node.Name, err)
+	}
+	logr.FromContextOrDiscard(ctx).V(1).Info("node capability labels updated", "labels", desired)
+	return reconcile.Result{}, nil
+}
+
+func (h *NodeLabelsHandler) reservedKeys() map[string]struct{} {
+	reserved := map[string]struct{}{}
+	h.mu.RLock()
+	for _, key := range h.skipped {
+		reserved[key] = struct{}{}
+	}
+	h.mu.RUnlock()
+	if h.managed == nil {
+		return reserved
+	}
+	policy := h.managed()
+	for _, key := range []string{policy.LabelKey, policy.PreviousLabelKey} {
+		if key != "" {
+			reserved[key] = struct{}{}
+		}
+	}
+	return reserved
+}
+
+// nodeLabelOps returns per-key operations moving the owned labels from current to desired; keys in
+// reserved are left alone, and labels outside the owned set are never addressed.
+func nodeLabelOps(current, desired map[string]string, reserved map[string]struct{}) *patch.JSONPatch {
+	ops := patch.NewJSONPatch()
+	if current == nil {
+		initial := make(map[string]string, len(desired))
+		for key, value := range desired {
+			if _, skip := reserved[key]; !skip {
+				initial[key] = value
+			}
+		}
+		if len(initial) > 0 {
+			ops.Append(patch.NewJSONPatchOperation(patch.PatchAddOp, "/metadata/labels", initial))
+		}
+		return ops
+	}
+	for _, key := range invstate.NodeCapabilityLabelKeys {
+		if _, skip := reserved[key]; skip {
+			continue
+		}
+		path := "/metadata/labels/" + labelPathEscaper.Replace(key)
+		value, want := desired[key]
+		old, has := current[key]
+		switch {
+		case want && !has:
+			ops.Append(patch.NewJSONPatchOperation(patch.PatchAddOp, path, value))
+		case want && old != value:
+			ops.Append(patch.NewJSONPatchOperation(patch.PatchReplaceOp, path, value))
+		case !want && has:
+			ops.Append(patch.NewJSONPatchOperation(patch.PatchRemoveOp, path, nil))
+		}
+	}
+	return ops
+}
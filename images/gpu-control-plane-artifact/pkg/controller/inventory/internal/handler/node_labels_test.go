@@ -0,0 +1,272 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+type nodeLabelsFixture struct {
+	client  client.Client
+	handler *NodeLabelsHandler
+	enabled bool
+	managed invstate.ManagedNodesPolicy
+	patches []string
+}
+
+func newNodeLabelsFixture(t *testing.T, node *corev1.Node) *nodeLabelsFixture {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	f := &nodeLabelsFixture{enabled: true, managed: invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey}}
+	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
+	f.client = interceptor.NewClient(base, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.JSONPatchType {
+				t.Fatalf("expected a JSON patch, got %s", patch.Type())
+			}
+			data, err := patch.Data(obj)
+			if err != nil {
+				t.Fatalf("patch data: %v", err)
+			}
+			f.patches = append(f.patches, string(data))
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	})
+	f.handler = NewNodeLabelsHandler(f.client, func() bool { return f.enabled }, func() invstate.ManagedNodesPolicy { return f.managed })
+	return f
+}
+
+func (f *nodeLabelsFixture) handle(t *testing.T, devices ...invstate.DeviceSnapshot) map[string]string {
+	t.Helper()
+	node := &corev1.Node{}
+	if err := f.client.Get(context.Background(), types.NamespacedName{Name: "worker"}, node); err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	state := stubState{node: node, snapshot: invstate.NodeSnapshot{FeatureDetected: true, Devices: devices}}
+	if _, err := f.handler.Handle(context.Background(), state); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if err := f.client.Get(context.Background(), types.NamespacedName{Name: "worker"}, node); err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	return node.Labels
+}
+
+func TestNodeLabelsHandlerLifecycle(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{
+		invstate.DefaultManagedNodeLabelKey: "true",
+		"team":                              "ml",
+	}}}
+	f := newNodeLabelsFixture(t, node)
+	h100 := invstate.DeviceSnapshot{Product: "NVIDIA H100 80GB HBM3/PCIe", MemoryMiB: 81559}
+
+	labels := f.handle(t, h100, h100)
+	want := map[string]string{
+		invstate.NodeGPUPresentLabelKey:      "true",
+		invstate.NodeGPUCountLabelKey:        "2",
+		invstate.NodeGPUProductLabelKey:      "nvidia-h100-80gb-hbm3-pcie",
+		invstate.NodeGPUMinMemoryGiBLabelKey: "79",
+		invstate.DefaultManagedNodeLabelKey:  "true",
+		"team":                               "ml",
+	}
+	assertLabels(t, labels, want)
+
+	// Same facts: nothing to write.
+	f.patches = nil
+	f.handle(t, h100, h100)
+	if len(f.patches) != 0 {
+		t.Fatalf("expected no patch for unchanged facts, got %v", f.patches)
+	}
+
+	// One H100 replaced by a smaller card with an unknown product.
+	labels = f.handle(t, h100, invstate.DeviceSnapshot{MemoryMiB: 24576})
+	want[invstate.NodeGPUMinMemoryGiBLabelKey] = "24"
+	assertLabels(t, labels, want)
+	if len(f.patches) != 1 || strings.Contains(f.patches[0], "team") || strings.Contains(f.patches[0], "enabled") {
+		t.Fatalf("expected a patch touching only owned keys, got %v", f.patches)
+	}
+	var ops []map[string]any
+	if err := json.Unmarshal([]byte(f.patches[0]), &ops); err != nil || len(ops) != 1 || ops[0]["op"] != "replace" || ops[0]["path"] != "/metadata/labels/gpu.deckhouse.io~1min-memory-gib" {
+		t.Fatalf("unexpected update patch %s", f.patches[0])
+	}
+
+	// The node loses its GPUs.
+	labels = f.handle(t)
+	assertLabels(t, labels, map[string]string{invstate.DefaultManagedNodeLabelKey: "true", "team": "ml"})
+}
+
+func TestNodeLabelsHandlerDisabledStripsLabels(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{
+		invstate.NodeGPUPresentLabelKey: "true",
+		invstate.NodeGPUCountLabelKey:   "4",
+		"team":                          "ml",
+	}}}
+	f := newNodeLabelsFixture(t, node)
+	f.enabled = false
+
+	labels := f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	assertLabels(t, labels, map[string]string{"team": "ml"})
+
+	f.patches = nil
+	f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	if len(f.patches) != 0 {
+		t.Fatalf("expected no writes while disabled and clean, got %v", f.patches)
+	}
+}
+
+func TestNodeLabelsHandlerSkipsManagedLabelKey(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{
+		invstate.NodeGPUPresentLabelKey: "yes",
+	}}}
+	f := newNodeLabelsFixture(t, node)
+	f.managed = invstate.ManagedNodesPolicy{LabelKey: invstate.NodeGPUPresentLabelKey}
+
+	labels := f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4", MemoryMiB: 15360})
+	assertLabels(t, labels, map[string]string{
+		invstate.NodeGPUPresentLabelKey:      "yes",
+		invstate.NodeGPUCountLabelKey:        "1",
+		invstate.NodeGPUProductLabelKey:      "tesla-t4",
+		invstate.NodeGPUMinMemoryGiBLabelKey: "15",
+	})
+
+	labels = f.handle(t)
+	assertLabels(t, labels, map[string]string{invstate.NodeGPUPresentLabelKey: "yes"})
+}
+
+func TestNodeLabelsHandlerNodeWithoutLabels(t *testing.T) {
+	f := newNodeLabelsFixture(t, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}})
+
+	labels := f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	assertLabels(t, labels, map[string]string{
+		invstate.NodeGPUPresentLabelKey: "true",
+		invstate.NodeGPUCountLabelKey:   "1",
+		invstate.NodeGPUProductLabelKey: "tesla-t4",
+	})
+}
+
+func TestNodeLabelsHandlerContainerRuntime(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{"team": "ml"}},
+		Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{ContainerRuntimeVersion: "cri-o://1.28.1"}},
+	}
+	f := newNodeLabelsFixture(t, node)
+
+	labels := f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	assertLabels(t, labels, map[string]string{
+		invstate.NodeGPUPresentLabelKey:       "true",
+		invstate.NodeGPUCountLabelKey:         "1",
+		invstate.NodeGPUProductLabelKey:       "tesla-t4",
+		invstate.NodeContainerRuntimeLabelKey: "cri-o",
+		"team":                                "ml",
+	})
+
+	// The node is switched to containerd.
+	current := &corev1.Node{}
+	if err := f.client.Get(context.Background(), types.NamespacedName{Name: "worker"}, current); err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	current.Status.NodeInfo.ContainerRuntimeVersion = "containerd://1.7.2"
+	if err := f.client.Status().Update(context.Background(), current); err != nil {
+		t.Fatalf("update node status: %v", err)
+	}
+	labels = f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	if labels[invstate.NodeContainerRuntimeLabelKey] != "containerd" {
+		t.Fatalf("expected the runtime label to follow the node, got %v", labels)
+	}
+
+	// Without GPUs the runtime label goes away with the others.
+	labels = f.handle(t)
+	assertLabels(t, labels, map[string]string{"team": "ml"})
+}
+
+func TestNodeLabelsHandlerWaitsForFeature(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{invstate.NodeGPUCountLabelKey: "2"}}}
+	f := newNodeLabelsFixture(t, node)
+
+	if _, err := f.handler.Handle(context.Background(), stubState{node: node}); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if len(f.patches) != 0 {
+		t.Fatalf("expected labels to stay until the node feature is detected, got %v", f.patches)
+	}
+}
+
+func TestNodeLabelsHandlerConfigureSkipLabels(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{
+		invstate.NodeGPUProductLabelKey: "set-by-another-labeler",
+	}}}
+	f := newNodeLabelsFixture(t, node)
+	if err := f.handler.Configure(json.RawMessage(`{"skipLabels":["` + invstate.NodeGPUProductLabelKey + `"]}`)); err != nil {
+		t.Fatalf("Configure returned error: %v", err)
+	}
+
+	labels := f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	assertLabels(t, labels, map[string]string{
+		invstate.NodeGPUPresentLabelKey: "true",
+		invstate.NodeGPUCountLabelKey:   "1",
+		invstate.NodeGPUProductLabelKey: "set-by-another-labeler",
+	})
+	labels = f.handle(t)
+	assertLabels(t, labels, map[string]string{invstate.NodeGPUProductLabelKey: "set-by-another-labeler"})
+
+	// A key the handler does not own is rejected and the previous settings stay.
+	if err := f.handler.Configure(json.RawMessage(`{"skipLabels":["team"]}`)); err == nil || !strings.Contains(err.Error(), `"team"`) {
+		t.Fatalf("expected an unknown label to be rejected, got %v", err)
+	}
+	if err := f.handler.Configure(json.RawMessage(`{"skipLabels":`)); err == nil {
+		t.Fatalf("expected malformed settings to be rejected")
+	}
+	labels = f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	if labels[invstate.NodeGPUProductLabelKey] != "set-by-another-labeler" {
+		t.Fatalf("expected the skipped label to stay untouched, got %v", labels)
+	}
+
+	// Clearing the settings hands the label back to the handler.
+	if err := f.handler.Configure(nil); err != nil {
+		t.Fatalf("Configure returned error: %v", err)
+	}
+	labels = f.handle(t, invstate.DeviceSnapshot{Product: "Tesla T4"})
+	if labels[invstate.NodeGPUProductLabelKey] != "tesla-t4" {
+		t.Fatalf("expected the product label to be written again, got %v", labels)
+	}
+}
+
+func assertLabels(t *testing.T, got, want map[string]string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("labels = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("labels = %v, want %v", got, want)
+		}
+	}
+}
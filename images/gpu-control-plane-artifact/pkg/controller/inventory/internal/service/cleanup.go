@@ -16,14 +16,25 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/inuse"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 )
@@ -33,16 +44,20 @@ type CleanupService interface {
 	CleanupNode(ctx context.Context, nodeName string) error
 	DeleteInventory(ctx context.Context, nodeName string) error
 	ClearMetrics(nodeName string)
-	RemoveOrphans(ctx context.Context, node *corev1.Node, orphanDevices map[string]struct{}) error
 }
 
 type cleanupService struct {
 	client   client.Client
 	recorder eventrecord.EventRecorderLogger
+	limiter  *DeletionLimiter
+	metrics  *invmetrics.Metrics
+	guard    *ownership.Guard
 }
 
-func NewCleanupService(c client.Client, recorder eventrecord.EventRecorderLogger) CleanupService {
-	return &cleanupService{client: c, recorder: recorder}
+// NewCleanupService builds the cleanup service; a nil limiter leaves deletions uncapped and a nil guard
+// lets it update every node state.
+func NewCleanupService(c client.Client, recorder eventrecord.EventRecorderLogger, limiter *DeletionLimiter, metrics *invmetrics.Metrics, guard *ownership.Guard) CleanupService {
+	return &cleanupService{client: c, recorder: recorder, limiter: limiter, metrics: metrics, guard: guard}
 }
 
 func (c *cleanupService) DeleteInventory(ctx context.Context, nodeName string) error {
@@ -54,10 +69,14 @@ func (c *cleanupService) DeleteInventory(ctx context.Context, nodeName string) e
 }
 
 func (c *cleanupService) ClearMetrics(nodeName string) {
-	invmetrics.InventoryDevicesDelete(nodeName)
-	invmetrics.InventoryConditionDelete(nodeName, invstate.ConditionInventoryComplete)
+	c.metrics.InventoryDevicesDelete(nodeName)
+	c.metrics.InventoryDevicesUnallocatedDelete(nodeName)
+	c.metrics.InventoryDeviceWritesDelete(nodeName)
+	c.metrics.InventoryUnmigratedLabelKeyDelete(nodeName)
+	c.metrics.InventoryDetectionSchemaDelete(nodeName)
+	c.metrics.InventoryConditionDelete(nodeName, invstate.ConditionInventoryComplete)
 	for _, state := range knownDeviceStates {
-		invmetrics.InventoryDeviceStateDelete(nodeName, string(state))
+		c.metrics.InventoryDeviceStateDelete(nodeName, string(state))
 	}
 }
 
@@ -66,12 +85,25 @@ func (c *cleanupService) CleanupNode(ctx context.Context, nodeName string) error
 	if err := c.client.List(ctx, deviceList, client.MatchingFields{invstate.DeviceNodeIndexKey: nodeName}); err != nil {
 		return err
 	}
+	names := make([]string, 0, len(deviceList.Items))
 	for i := range deviceList.Items {
-		device := &v1alpha1.GPUDevice{}
-		device.Name = deviceList.Items[i].Name
-		if err := commonobject.DeleteObject(ctx, c.client, device); err != nil {
-			return err
-		}
+		names = append(names, deviceList.Items[i].Name)
+	}
+	names, held, err := c.holdInUse(ctx, nodeName, names)
+	if err != nil {
+		return err
+	}
+
+	deleted, retryAfter, err := c.deleteDevices(ctx, nodeName, names)
+	if err != nil {
+		return err
+	}
+	if deleted < len(names) {
+		return c.throttled(ctx, nodeName, len(names)-deleted, retryAfter)
+	}
+	if held > 0 {
+		// The GPUNodeState stays until the last device is gone.
+		return &DevicesInUseError{Node: nodeName, Held: held, RetryAfter: InUseRecheckInterval}
 	}
 
 	if err := c.DeleteInventory(ctx, nodeName); err != nil {
@@ -82,26 +114,138 @@ func (c *cleanupService) CleanupNode(ctx context.Context, nodeName string) error
 	return nil
 }
 
-func (c *cleanupService) RemoveOrphans(ctx context.Context, node *corev1.Node, orphanDevices map[string]struct{}) error {
-	if len(orphanDevices) == 0 {
+// InUseRecheckInterval is how often the removal of devices held back by DevicesInUseError is retried.
+const InUseRecheckInterval = time.Minute
+
+// DevicesInUseError reports GPUDevices of a node whose deletion waits until their pool releases them.
+type DevicesInUseError struct {
+	Node       string
+	Held       int
+	RetryAfter time.Duration
+}
+
+func (e *DevicesInUseError) Error() string {
+	return fmt.Sprintf("%d GPU devices on node %s are still in use, deletion deferred, retry in %s", e.Held, e.Node, e.RetryAfter)
+}
+
+// holdInUse drops the devices a pool still uses from names and marks them Stale instead, and returns the names left
+// to delete and how many were held back. Their removal is retried once they are released.
+func (c *cleanupService) holdInUse(ctx context.Context, nodeName string, names []string) ([]string, int, error) {
+	deletable := make([]string, 0, len(names))
+	held := 0
+	for _, name := range names {
+		device, err := commonobject.FetchObject(ctx, client.ObjectKey{Name: name}, c.client, &v1alpha1.GPUDevice{})
+		if err != nil {
+			return nil, 0, err
+		}
+		if device == nil || !inuse.Protected(device) {
+			deletable = append(deletable, name)
+			continue
+		}
+		held++
+		if err := c.markStale(ctx, nodeName, device); err != nil {
+			return nil, 0, err
+		}
+	}
+	return deletable, held, nil
+}
+
+func (c *cleanupService) markStale(ctx context.Context, nodeName string, device *v1alpha1.GPUDevice) error {
+	original := device.DeepCopy()
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionStale)).
+			Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(inuse.ReasonDeviceInUse)).
+			Message(fmt.Sprintf("removed from the inventory of node %s, deletion deferred: %s", nodeName, inuse.Describe(device))).
+			Generation(device.Generation),
+		&device.Status.Conditions,
+	)
+	if equality.Semantic.DeepEqual(original.Status, device.Status) {
 		return nil
 	}
-	for name := range orphanDevices {
+	if err := objsize.Guard(device); err != nil {
+		return err
+	}
+	return client.IgnoreNotFound(c.client.Status().Patch(ctx, device, client.MergeFrom(original)))
+}
+
+// deleteDevices deletes as many of the named devices as the limiter allows, in name order, and
+// returns how many were deleted and, when some were held back, when to retry. The node's
+// GPUNodeState may opt out of the limit.
+func (c *cleanupService) deleteDevices(ctx context.Context, nodeName string, names []string) (int, time.Duration, error) {
+	sort.Strings(names)
+
+	allowed, retryAfter := len(names), time.Duration(0)
+	if c.limiter != nil && allowed > 0 {
+		bypass, err := c.bypassLimit(ctx, nodeName)
+		if err != nil {
+			return 0, 0, err
+		}
+		if !bypass {
+			known := &v1alpha1.GPUDeviceList{}
+			if err := c.client.List(ctx, known); err != nil {
+				return 0, 0, err
+			}
+			allowed, retryAfter = c.limiter.Reserve(len(names), len(known.Items))
+		}
+	}
+
+	for i, name := range names[:allowed] {
 		device := &v1alpha1.GPUDevice{}
 		device.Name = name
 		if err := commonobject.DeleteObject(ctx, c.client, device); err != nil {
-			return err
+			return i, 0, err
 		}
-		if c.recorder != nil {
-			log := logr.FromContextOrDiscard(ctx).WithValues("node", node.Name, "device", name)
-			c.recorder.WithLogging(log).Eventf(
-				node,
-				corev1.EventTypeNormal,
-				invstate.EventDeviceRemoved,
-				"GPU device %s removed from inventory",
-				name,
-			)
+	}
+	return allowed, retryAfter, nil
+}
+
+func (c *cleanupService) bypassLimit(ctx context.Context, nodeName string) (bool, error) {
+	inventory, err := commonobject.FetchObject(ctx, client.ObjectKey{Name: nodeName}, c.client, &v1alpha1.GPUNodeState{})
+	if err != nil || inventory == nil {
+		return false, err
+	}
+	return inventory.Annotations[invstate.AllowMassDeletionAnnotation] == "true", nil
+}
+
+// throttled records deferred deletions on the node's GPUNodeState, in an event and in a metric, and
+// returns the error that tells the caller when to retry.
+func (c *cleanupService) throttled(ctx context.Context, nodeName string, deferred int, retryAfter time.Duration) error {
+	throttledErr := &DeletionsThrottledError{Node: nodeName, Deferred: deferred, RetryAfter: retryAfter}
+	c.metrics.InventoryDeletionsThrottledAdd(nodeName, deferred)
+
+	resource := reconciler.NewResource(
+		types.NamespacedName{Name: nodeName},
+		c.client,
+		c.guard,
+		func() *v1alpha1.GPUNodeState { return &v1alpha1.GPUNodeState{} },
+		func(obj *v1alpha1.GPUNodeState) v1alpha1.GPUNodeStateStatus { return obj.Status },
+	)
+	if err := resource.Fetch(ctx); err != nil {
+		return err
+	}
+	if resource.IsEmpty() {
+		return throttledErr
+	}
+
+	inventory := resource.Changed()
+	message := fmt.Sprintf("%d GPU device deletions deferred by inventory.maxDeletionsPerSweep; annotate with %s=true to allow them", deferred, invstate.AllowMassDeletionAnnotation)
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionDeletionsThrottled)).
+			Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(invstate.ReasonDeletionLimitReached)).
+			Message(message).
+			Generation(inventory.Generation),
+		&inventory.Status.Conditions,
+	)
+	if c.recorder != nil {
+		log := logr.FromContextOrDiscard(ctx).WithValues("node", nodeName)
+		c.recorder.WithLogging(log).Eventf(inventory, corev1.EventTypeWarning, invstate.EventDeletionsThrottled, "%s", message)
+	}
+	if !equality.Semantic.DeepEqual(resource.Current().Status, inventory.Status) {
+		if err := resource.Update(ctx); err != nil {
+			return err
 		}
 	}
-	return nil
+	return throttledErr
 }
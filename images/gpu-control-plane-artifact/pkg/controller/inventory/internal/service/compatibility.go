@@ -0,0 +1,63 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// ApplyCompatibility holds a device of a node that matched a Block rule out of AutoAttach and marks it
+// CompatibilityBlocked, which pools read to drop it from capacity. The condition goes away with the rule match.
+func ApplyCompatibility(device *v1alpha1.GPUDevice, verdict invstate.CompatibilityVerdict) {
+	if !verdict.Blocked() {
+		apimeta.RemoveStatusCondition(&device.Status.Conditions, invstate.ConditionCompatibilityBlocked)
+		return
+	}
+	device.Status.AutoAttach = false
+	conditions.SetCondition(conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionCompatibilityBlocked)).
+		Status(metav1.ConditionTrue).
+		Reason(conditions.CommonReason(invstate.ReasonCompatibilityBlocked)).
+		Message(verdict.Message()).
+		Generation(device.Generation), &device.Status.Conditions)
+}
+
+// setCompatibility lists the matched rules on the node state and reports whether the condition changed,
+// so the caller records an event only when a rule starts or stops matching.
+func setCompatibility(inventory *v1alpha1.GPUNodeState, verdict invstate.CompatibilityVerdict) bool {
+	previous := apimeta.FindStatusCondition(inventory.Status.Conditions, invstate.ConditionCompatibilityRuleMatched)
+	if len(verdict.Matched) == 0 {
+		return apimeta.RemoveStatusCondition(&inventory.Status.Conditions, invstate.ConditionCompatibilityRuleMatched)
+	}
+	reason := invstate.ReasonCompatibilityWarning
+	if verdict.Blocked() {
+		reason = invstate.ReasonCompatibilityBlocked
+	}
+	message := verdict.Message()
+	changed := previous == nil || previous.Reason != reason || previous.Message != message
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionCompatibilityRuleMatched)).
+			Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(reason)).
+			Message(message).
+			Generation(inventory.Generation),
+		&inventory.Status.Conditions,
+	)
+	return changed
+}
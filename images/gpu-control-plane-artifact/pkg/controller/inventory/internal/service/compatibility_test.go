@@ -0,0 +1,125 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func TestApplyCompatibility(t *testing.T) {
+	warn := invstate.CompatibilityVerdict{
+		Effect:  moduleconfig.CompatibilityEffectWarn,
+		Matched: []moduleconfig.CompatibilityRule{{Name: "old-kernel", Effect: moduleconfig.CompatibilityEffectWarn}},
+	}
+	block := invstate.CompatibilityVerdict{
+		Effect:  moduleconfig.CompatibilityEffectBlock,
+		Matched: []moduleconfig.CompatibilityRule{{Name: "mig", Effect: moduleconfig.CompatibilityEffectBlock, Message: "breaks MIG"}},
+	}
+
+	device := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{AutoAttach: true}}
+	ApplyCompatibility(device, warn)
+	if !device.Status.AutoAttach || findCondition(device.Status.Conditions, invstate.ConditionCompatibilityBlocked) != nil {
+		t.Fatalf("expected a Warn rule to leave the device alone, got %+v", device.Status)
+	}
+
+	ApplyCompatibility(device, block)
+	cond := findCondition(device.Status.Conditions, invstate.ConditionCompatibilityBlocked)
+	if device.Status.AutoAttach || cond == nil || cond.Status != metav1.ConditionTrue || cond.Message != "mig: breaks MIG" {
+		t.Fatalf("expected a Block rule to hold AutoAttach back, got autoAttach=%t condition=%+v", device.Status.AutoAttach, cond)
+	}
+
+	ApplyCompatibility(device, invstate.CompatibilityVerdict{})
+	if findCondition(device.Status.Conditions, invstate.ConditionCompatibilityBlocked) != nil {
+		t.Fatalf("expected the condition to be removed once no rule matches")
+	}
+}
+
+func TestInventoryServiceReconcileReportsCompatibility(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-compat")
+	base := newTestClient(t, scheme, node)
+	rec, recorder := newTestRecorder(10)
+	svc := NewInventoryService(base, scheme, recorder, nil, nil)
+
+	snap := invstate.NodeSnapshot{
+		FeatureDetected: true,
+		Devices:         []invstate.DeviceSnapshot{{Index: "0"}},
+		Compatibility: invstate.CompatibilityVerdict{
+			Effect:  moduleconfig.CompatibilityEffectWarn,
+			Matched: []moduleconfig.CompatibilityRule{{Name: "old-kernel", Effect: moduleconfig.CompatibilityEffectWarn, Message: "upgrade"}},
+		},
+	}
+	reconcile := func() *v1alpha1.GPUNodeState {
+		t.Helper()
+		if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+			t.Fatalf("Reconcile returned error: %v", err)
+		}
+		got := &v1alpha1.GPUNodeState{}
+		if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+			t.Fatalf("get inventory: %v", err)
+		}
+		return got
+	}
+	compatibilityEvents := func() int {
+		count := 0
+		for len(rec.Events) > 0 {
+			if strings.Contains(<-rec.Events, invstate.EventCompatibilityRuleMatched) {
+				count++
+			}
+		}
+		return count
+	}
+
+	cond := findCondition(reconcile().Status.Conditions, invstate.ConditionCompatibilityRuleMatched)
+	if cond == nil || cond.Reason != invstate.ReasonCompatibilityWarning || cond.Message != "old-kernel: upgrade" {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+	if got := compatibilityEvents(); got != 1 {
+		t.Fatalf("expected one compatibility event, got %d", got)
+	}
+
+	reconcile()
+	if got := compatibilityEvents(); got != 0 {
+		t.Fatalf("expected no event while the matched rules stay the same, got %d", got)
+	}
+
+	snap.Compatibility.Effect = moduleconfig.CompatibilityEffectBlock
+	snap.Compatibility.Matched[0].Effect = moduleconfig.CompatibilityEffectBlock
+	cond = findCondition(reconcile().Status.Conditions, invstate.ConditionCompatibilityRuleMatched)
+	if cond == nil || cond.Reason != invstate.ReasonCompatibilityBlocked {
+		t.Fatalf("expected the blocked reason, got %+v", cond)
+	}
+	if got := compatibilityEvents(); got != 1 {
+		t.Fatalf("expected an event when the effect changes, got %d", got)
+	}
+
+	snap.Compatibility = invstate.CompatibilityVerdict{}
+	if cond := findCondition(reconcile().Status.Conditions, invstate.ConditionCompatibilityRuleMatched); cond != nil {
+		t.Fatalf("expected the condition to be removed once resolved, got %+v", cond)
+	}
+	if got := compatibilityEvents(); got != 0 {
+		t.Fatalf("expected no event for a resolved node, got %d", got)
+	}
+}
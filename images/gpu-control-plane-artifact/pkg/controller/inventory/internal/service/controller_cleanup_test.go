@@ -17,12 +17,15 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/inuse"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -59,16 +62,23 @@ func (d *cleanupDelegatingClient) Delete(ctx context.Context, obj client.Object,
 
 func TestCleanupNodeDeletesMetrics(t *testing.T) {
 	const nodeName = "cleanup-metrics"
-	invmetrics.InventoryDevicesSet(nodeName, 2)
-	invmetrics.InventoryConditionSet(nodeName, invstate.ConditionInventoryComplete, true)
+	metrics, gatherer := newTestMetrics(t)
+	metrics.InventoryDevicesSet(nodeName, 2)
+	metrics.InventoryConditionSet(nodeName, invstate.ConditionInventoryComplete, "", true)
 
 	scheme := newTestScheme(t)
 	cl := newTestClient(t, scheme)
-	svc := NewCleanupService(cl, newTestRecorderLogger(1))
+	svc := NewCleanupService(cl, newTestRecorderLogger(1), nil, metrics, nil)
 
 	if err := svc.CleanupNode(context.Background(), nodeName); err != nil {
 		t.Fatalf("cleanupNode returned error: %v", err)
 	}
+	if _, ok := findMetric(t, gatherer, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": nodeName}); ok {
+		t.Fatalf("expected devices gauge cleared")
+	}
+	if _, ok := findMetric(t, gatherer, invmetrics.InventoryConditionMetric, map[string]string{"node": nodeName}); ok {
+		t.Fatalf("expected condition gauge cleared")
+	}
 }
 
 func TestDeleteInventoryRemovesResource(t *testing.T) {
@@ -77,7 +87,7 @@ func TestDeleteInventoryRemovesResource(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "node-cleanup"},
 	}
 	fixtureClient := newTestClient(t, scheme, inventory)
-	svc := NewCleanupService(fixtureClient, newTestRecorderLogger(1))
+	svc := NewCleanupService(fixtureClient, newTestRecorderLogger(1), nil, nil, nil)
 
 	if err := svc.DeleteInventory(context.Background(), "node-cleanup"); err != nil {
 		t.Fatalf("deleteInventory returned error: %v", err)
@@ -101,7 +111,7 @@ func TestDeleteInventoryRemovesResource(t *testing.T) {
 			return apierrors.NewNotFound(schema.GroupResource{Group: v1alpha1.GroupVersion.Group, Resource: "gpunodestates"}, obj.GetName())
 		},
 	}
-	delSvc := NewCleanupService(delClient, newTestRecorderLogger(1))
+	delSvc := NewCleanupService(delClient, newTestRecorderLogger(1), nil, nil, nil)
 
 	if err := delSvc.DeleteInventory(context.Background(), "node-delete-race"); err != nil {
 		t.Fatalf("deleteInventory should ignore not found error from delete, got %v", err)
@@ -120,7 +130,7 @@ func TestDeleteInventoryPropagatesGetError(t *testing.T) {
 		},
 	}
 
-	svc := NewCleanupService(cl, newTestRecorderLogger(1))
+	svc := NewCleanupService(cl, newTestRecorderLogger(1), nil, nil, nil)
 
 	if err := svc.DeleteInventory(context.Background(), "node-error"); !errors.Is(err, boom) {
 		t.Fatalf("expected error %v, got %v", boom, err)
@@ -138,7 +148,7 @@ func TestCleanupNodeReturnsListError(t *testing.T) {
 		},
 	}
 
-	svc := NewCleanupService(cl, newTestRecorderLogger(1))
+	svc := NewCleanupService(cl, newTestRecorderLogger(1), nil, nil, nil)
 	if err := svc.CleanupNode(context.Background(), "worker-list-error"); !errors.Is(err, listErr) {
 		t.Fatalf("expected list error, got %v", err)
 	}
@@ -168,7 +178,7 @@ func TestCleanupNodeReturnsDeviceDeleteError(t *testing.T) {
 		},
 	}
 
-	svc := NewCleanupService(cl, newTestRecorderLogger(1))
+	svc := NewCleanupService(cl, newTestRecorderLogger(1), nil, nil, nil)
 	if err := svc.CleanupNode(context.Background(), "worker-delete"); !errors.Is(err, deleteErr) {
 		t.Fatalf("expected device delete error, got %v", err)
 	}
@@ -195,8 +205,64 @@ func TestCleanupNodeReturnsInventoryDeleteError(t *testing.T) {
 		},
 	}
 
-	svc := NewCleanupService(cl, newTestRecorderLogger(1))
+	svc := NewCleanupService(cl, newTestRecorderLogger(1), nil, nil, nil)
 	if err := svc.CleanupNode(context.Background(), "worker-inventory"); !errors.Is(err, deleteErr) {
 		t.Fatalf("expected inventory delete error, got %v", err)
 	}
 }
+
+func TestCleanupNodeHoldsDevicesInUse(t *testing.T) {
+	ctx := context.Background()
+	const nodeName = "cleanup-in-use"
+	scheme := newTestScheme(t)
+	inventory := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	assigned := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "assigned"},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: nodeName,
+			State:    v1alpha1.GPUDeviceStateAssigned,
+			PoolRef:  &v1alpha1.GPUPoolReference{Name: "pool", Namespace: "team"},
+		},
+	}
+	ready := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: nodeName, State: v1alpha1.GPUDeviceStateReady},
+	}
+	cl := newTestClient(t, scheme, inventory, assigned, ready)
+	svc := NewCleanupService(cl, newTestRecorderLogger(10), nil, nil, nil)
+
+	err := svc.CleanupNode(ctx, nodeName)
+	var held *DevicesInUseError
+	if !errors.As(err, &held) || held.Held != 1 || held.RetryAfter != InUseRecheckInterval {
+		t.Fatalf("expected DevicesInUseError for one device, got %v", err)
+	}
+
+	if err := cl.Get(ctx, types.NamespacedName{Name: ready.Name}, &v1alpha1.GPUDevice{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected released device to be deleted, got err=%v", err)
+	}
+	kept := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: assigned.Name}, kept); err != nil {
+		t.Fatalf("expected in-use device to be kept: %v", err)
+	}
+	stale := apimeta.FindStatusCondition(kept.Status.Conditions, invstate.ConditionStale)
+	if stale == nil || stale.Status != metav1.ConditionTrue || stale.Reason != inuse.ReasonDeviceInUse || !strings.Contains(stale.Message, "team/pool") {
+		t.Fatalf("unexpected Stale condition: %+v", stale)
+	}
+	if err := cl.Get(ctx, types.NamespacedName{Name: nodeName}, &v1alpha1.GPUNodeState{}); err != nil {
+		t.Fatalf("expected GPUNodeState to stay while a device is held: %v", err)
+	}
+
+	kept.Status.State = v1alpha1.GPUDeviceStateReady
+	if err := cl.Status().Update(ctx, kept); err != nil {
+		t.Fatalf("release device: %v", err)
+	}
+	if err := svc.CleanupNode(ctx, nodeName); err != nil {
+		t.Fatalf("CleanupNode after release returned error: %v", err)
+	}
+	if err := cl.Get(ctx, types.NamespacedName{Name: assigned.Name}, &v1alpha1.GPUDevice{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected released device to be deleted, got err=%v", err)
+	}
+	if err := cl.Get(ctx, types.NamespacedName{Name: nodeName}, &v1alpha1.GPUNodeState{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected GPUNodeState to be deleted, got err=%v", err)
+	}
+}
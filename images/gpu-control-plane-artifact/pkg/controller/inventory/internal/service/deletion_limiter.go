@@ -0,0 +1,95 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// DeletionWindow is the sliding window over which inventory.maxDeletionsPerSweep is enforced.
+const DeletionWindow = 10 * time.Minute
+
+// DeletionLimiter caps how many GPUDevices the inventory controller deletes within DeletionWindow.
+// One limiter is shared by all reconciles, so a fleet-wide event that makes every node look gone at
+// once removes devices at the configured rate instead of wiping the inventory. It only gates
+// deletions: creations and status writes never consult it.
+type DeletionLimiter struct {
+	mu      sync.Mutex
+	clock   clock.PassiveClock
+	limit   func(known int) int
+	granted []time.Time
+}
+
+// NewDeletionLimiter returns a limiter that resolves its cap through limit on every reservation, so
+// ModuleConfig changes apply immediately. A non-positive cap disables the limiter.
+func NewDeletionLimiter(limit func(known int) int) *DeletionLimiter {
+	return &DeletionLimiter{clock: clock.RealClock{}, limit: limit}
+}
+
+// SetClock replaces the clock used to slide the window.
+func (l *DeletionLimiter) SetClock(c clock.PassiveClock) {
+	l.clock = c
+}
+
+// Reserve grants up to want deletions given known devices in the cluster. When fewer are granted,
+// retryAfter is the time until the oldest grant leaves the window. Grants are kept even if the
+// delete later fails, which errs on the side of deleting less.
+func (l *DeletionLimiter) Reserve(want, known int) (granted int, retryAfter time.Duration) {
+	if l == nil || l.limit == nil || want <= 0 {
+		return want, 0
+	}
+	max := l.limit(known)
+	if max <= 0 {
+		return want, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	cutoff := now.Add(-DeletionWindow)
+	expired := 0
+	for expired < len(l.granted) && !l.granted[expired].After(cutoff) {
+		expired++
+	}
+	l.granted = l.granted[expired:]
+
+	granted = min(want, max-len(l.granted))
+	if granted < 0 {
+		granted = 0
+	}
+	for i := 0; i < granted; i++ {
+		l.granted = append(l.granted, now)
+	}
+	if granted == want {
+		return granted, 0
+	}
+	return granted, l.granted[0].Add(DeletionWindow).Sub(now)
+}
+
+// DeletionsThrottledError reports GPUDevice deletions of a node deferred by the DeletionLimiter.
+type DeletionsThrottledError struct {
+	Node       string
+	Deferred   int
+	RetryAfter time.Duration
+}
+
+func (e *DeletionsThrottledError) Error() string {
+	return fmt.Sprintf("%d GPU device deletions on node %s deferred by inventory.maxDeletionsPerSweep, retry in %s", e.Deferred, e.Node, e.RetryAfter)
+}
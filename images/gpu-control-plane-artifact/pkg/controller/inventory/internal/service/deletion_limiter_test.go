@@ -0,0 +1,186 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+)
+
+func fixedLimit(n int) func(int) int {
+	return func(int) int { return n }
+}
+
+func TestDeletionLimiterSlidesWindow(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(start)
+	limiter := NewDeletionLimiter(fixedLimit(5))
+	limiter.SetClock(clock)
+
+	if granted, retry := limiter.Reserve(3, 100); granted != 3 || retry != 0 {
+		t.Fatalf("expected full grant, got %d retry=%s", granted, retry)
+	}
+	clock.SetTime(start.Add(4 * time.Minute))
+	if granted, retry := limiter.Reserve(4, 100); granted != 2 || retry != 6*time.Minute {
+		t.Fatalf("expected the remaining two with a retry when the first grant expires, got %d retry=%s", granted, retry)
+	}
+	if granted, _ := limiter.Reserve(1, 100); granted != 0 {
+		t.Fatalf("expected the window to be exhausted, got %d", granted)
+	}
+
+	// The first three grants leave the window; the last two still count.
+	clock.SetTime(start.Add(DeletionWindow))
+	if granted, retry := limiter.Reserve(5, 100); granted != 3 || retry != 4*time.Minute {
+		t.Fatalf("expected three slots after the window slid, got %d retry=%s", granted, retry)
+	}
+}
+
+func TestDeletionLimiterDisabled(t *testing.T) {
+	var nilLimiter *DeletionLimiter
+	if granted, _ := nilLimiter.Reserve(7, 10); granted != 7 {
+		t.Fatalf("nil limiter must not cap, got %d", granted)
+	}
+	if granted, _ := NewDeletionLimiter(fixedLimit(0)).Reserve(7, 10); granted != 7 {
+		t.Fatalf("zero limit must not cap, got %d", granted)
+	}
+}
+
+func TestDeletionLimiterPassesKnownDevices(t *testing.T) {
+	var seen int
+	limiter := NewDeletionLimiter(func(known int) int { seen = known; return known / 10 })
+	if granted, _ := limiter.Reserve(50, 200); granted != 20 || seen != 200 {
+		t.Fatalf("expected a 10%% cap of 200 devices, got %d (known=%d)", granted, seen)
+	}
+}
+
+// fleetFixture builds nodes that all lost their devices at once, each with a draining GPUNodeState.
+func fleetFixture(nodes, devicesPerNode int) []client.Object {
+	var objs []client.Object
+	for n := 0; n < nodes; n++ {
+		nodeName := fmt.Sprintf("fleet-%02d", n)
+		objs = append(objs, &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})
+		for d := 0; d < devicesPerNode; d++ {
+			objs = append(objs, &v1alpha1.GPUDevice{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", nodeName, d)},
+				Status:     v1alpha1.GPUDeviceStatus{NodeName: nodeName},
+			})
+		}
+	}
+	return objs
+}
+
+func TestCleanupNodeCapsFleetWideDeletions(t *testing.T) {
+	const nodes, perNode = 20, 5
+	scheme := newTestScheme(t)
+	cl := newTestClient(t, scheme, fleetFixture(nodes, perNode)...)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(start)
+	// 10% of the 100 known devices.
+	limiter := NewDeletionLimiter(func(known int) int { return (known*10 + 99) / 100 })
+	limiter.SetClock(clock)
+	rec, recorder := newTestRecorder(2 * nodes)
+	metrics, gatherer := newTestMetrics(t)
+	svc := NewCleanupService(cl, recorder, limiter, metrics, nil)
+
+	throttledNodes := 0
+	for n := 0; n < nodes; n++ {
+		err := svc.CleanupNode(context.Background(), fmt.Sprintf("fleet-%02d", n))
+		var throttled *DeletionsThrottledError
+		switch {
+		case err == nil:
+		case errors.As(err, &throttled):
+			throttledNodes++
+			if throttled.RetryAfter != DeletionWindow {
+				t.Fatalf("expected retry after the window, got %s", throttled.RetryAfter)
+			}
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	remaining := &v1alpha1.GPUDeviceList{}
+	if err := cl.List(context.Background(), remaining); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	if deleted := nodes*perNode - len(remaining.Items); deleted != 10 {
+		t.Fatalf("expected exactly 10 deletions, got %d", deleted)
+	}
+	if throttledNodes != nodes-2 {
+		t.Fatalf("expected all but the first two nodes to be throttled, got %d", throttledNodes)
+	}
+	if len(rec.Events) != throttledNodes {
+		t.Fatalf("expected one event per throttled node, got %d", len(rec.Events))
+	}
+
+	inventory := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "fleet-19"}, inventory); err != nil {
+		t.Fatalf("throttled inventory must be kept: %v", err)
+	}
+	if !apimeta.IsStatusConditionTrue(inventory.Status.Conditions, invstate.ConditionDeletionsThrottled) {
+		t.Fatalf("expected DeletionsThrottled condition, got %+v", inventory.Status.Conditions)
+	}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "fleet-00"}, &v1alpha1.GPUNodeState{}); err == nil {
+		t.Fatalf("expected the first node's inventory to be removed")
+	}
+	metric, ok := findMetric(t, gatherer, invmetrics.InventoryDeletionsThrottled, map[string]string{"node": "fleet-19"})
+	if !ok || metric.Counter == nil || metric.Counter.GetValue() != perNode {
+		t.Fatalf("expected throttled counter=%d, got %+v (present=%t)", perNode, metric, ok)
+	}
+
+	// A retry within the window still defers; once it slid, 10% of the 90 devices left may go.
+	if err := svc.CleanupNode(context.Background(), "fleet-02"); err == nil {
+		t.Fatalf("expected deletions to stay throttled within the window")
+	}
+	clock.SetTime(start.Add(DeletionWindow))
+	if err := svc.CleanupNode(context.Background(), "fleet-02"); err != nil {
+		t.Fatalf("expected cleanup after the window slid, got %v", err)
+	}
+	var throttled *DeletionsThrottledError
+	if err := svc.CleanupNode(context.Background(), "fleet-03"); !errors.As(err, &throttled) || throttled.Deferred != 1 {
+		t.Fatalf("expected one deferred deletion on the next node, got %v", err)
+	}
+}
+
+func TestCleanupNodeAllowMassDeletionBypassesLimit(t *testing.T) {
+	scheme := newTestScheme(t)
+	objs := fleetFixture(1, 4)
+	objs[0].SetAnnotations(map[string]string{invstate.AllowMassDeletionAnnotation: "true"})
+	cl := newTestClient(t, scheme, objs...)
+	svc := NewCleanupService(cl, nil, NewDeletionLimiter(fixedLimit(1)), nil, nil)
+
+	if err := svc.CleanupNode(context.Background(), "fleet-00"); err != nil {
+		t.Fatalf("expected annotated inventory to bypass the limiter, got %v", err)
+	}
+	remaining := &v1alpha1.GPUDeviceList{}
+	if err := cl.List(context.Background(), remaining); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	if len(remaining.Items) != 0 {
+		t.Fatalf("expected all devices deleted, got %d", len(remaining.Items))
+	}
+}
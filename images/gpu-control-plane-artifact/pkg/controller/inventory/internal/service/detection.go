@@ -17,20 +17,28 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
+	commonpod "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/pod"
 	invpci "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/pci"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 )
 
 // DetectionCollector fetches detections from gfd-extender for a node.
@@ -39,163 +47,232 @@ type DetectionCollector interface {
 }
 
 type detectionCollector struct {
-	client client.Client
+	client  client.Client
+	ports   commonpod.PortLookup
+	http    *httpcall.Caller
+	metrics *invmetrics.Metrics
 }
 
-func NewDetectionCollector(c client.Client) DetectionCollector {
-	return &detectionCollector{client: c}
+// NewDetectionCollector scrapes gfd-extender through caller, whose category and deadline apply to every request.
+func NewDetectionCollector(c client.Client, caller *httpcall.Caller, metrics *invmetrics.Metrics) DetectionCollector {
+	return &detectionCollector{client: c, ports: detectPortLookup(os.Getenv), http: caller, metrics: metrics}
 }
 
-var detectHTTPClient = &http.Client{Timeout: 2 * time.Second}
+// detectPortEnv overrides the fallback port used for extender pods that do not name their detection port;
+// 0 disables the fallback.
+const detectPortEnv = "GFD_EXTENDER_PORT"
 
-const detectGPUPath = "/api/v1/detect/gpu"
-
-type detectGPUMemory struct {
-	Total uint64 `json:"Total"`
-	Free  uint64 `json:"Free"`
-	Used  uint64 `json:"Used"`
-}
-
-type detectGPUUtilization struct {
-	GPU    uint32 `json:"Gpu"`
-	Memory uint32 `json:"Memory"`
-}
-
-type detectGPUPCI struct {
-	Address   string `json:"address"`
-	Vendor    string `json:"vendor"`
-	Device    string `json:"device"`
-	Class     string `json:"class"`
-	Subsystem string `json:"subsystem"`
-}
-
-type detectGPUPCIELink struct {
-	Generation *int32 `json:"generation"`
-	Width      *int32 `json:"width"`
+func detectPortLookup(getenv func(string) string) commonpod.PortLookup {
+	lookup := commonpod.PortLookup{Name: detection.PortName, Container: "gfd-extender", Fallback: detection.DefaultPort}
+	if raw := strings.TrimSpace(getenv(detectPortEnv)); raw != "" {
+		if port, err := strconv.ParseInt(raw, 10, 32); err == nil && port >= 0 {
+			lookup.Fallback = int32(port)
+		}
+	}
+	return lookup
 }
 
-type detectGPUMIG struct {
-	Capable           bool     `json:"capable"`
-	Mode              string   `json:"mode"`
-	ProfilesSupported []string `json:"profilesSupported"`
-}
+// detectHTTPClient replaces the shared client in tests when set.
+var detectHTTPClient *http.Client
 
-type detectGPUEntry struct {
-	Index                       int                  `json:"index"`
-	UUID                        string               `json:"uuid"`
-	Product                     string               `json:"product"`
-	MemoryInfo                  detectGPUMemory      `json:"memoryInfo"`
-	PowerUsage                  uint32               `json:"powerUsage"`
-	PowerManagementDefaultLimit uint32               `json:"powerManagementDefaultLimit"`
-	Utilization                 detectGPUUtilization `json:"utilization"`
-	PowerState                  uint32               `json:"powerState"`
-	TemperatureC                int32                `json:"temperatureC"`
-	MemoryMiB                   int32                `json:"memoryMiB"`
-	ComputeMajor                int32                `json:"computeMajor"`
-	ComputeMinor                int32                `json:"computeMinor"`
-	NUMANode                    *int32               `json:"numaNode"`
-	SMCount                     *int32               `json:"smCount"`
-	MemoryBandwidthMiB          *int32               `json:"memoryBandwidthMiB"`
-	PCI                         detectGPUPCI         `json:"pci"`
-	PCIE                        detectGPUPCIELink    `json:"pcie"`
-	Board                       string               `json:"board"`
-	Family                      string               `json:"family"`
-	Serial                      string               `json:"serial"`
-	DisplayMode                 string               `json:"displayMode"`
-	Precision                   []string             `json:"precision"`
-	MIG                         detectGPUMIG         `json:"mig"`
-}
+// serviceAccountTokenPath is the projected token attached to scrape requests. It is issued for
+// detection.TokenAudience only, so the scrape never carries a token the apiserver would accept.
+var serviceAccountTokenPath = "/var/run/secrets/gpu.deckhouse.io/gfd-extender/token"
 
 type NodeDetection struct {
-	byUUID  map[string]detectGPUEntry
-	byIndex map[string]detectGPUEntry
+	byUUID   map[string]detection.Device
+	byIndex  map[string]detection.Device
+	platform *v1alpha1.GPUNodePlatform
+	// reported is set once the extender answered, so an empty device list means the driver sees no GPU.
+	reported bool
+	// initFailed holds the indices of GPUs the driver sees but could not query.
+	initFailed map[string]struct{}
 }
 
 func (c *detectionCollector) Collect(ctx context.Context, node string) (NodeDetection, error) {
 	result := NodeDetection{
-		byUUID:  make(map[string]detectGPUEntry),
-		byIndex: make(map[string]detectGPUEntry),
+		byUUID:  make(map[string]detection.Device),
+		byIndex: make(map[string]detection.Device),
 	}
 
 	pods := &corev1.PodList{}
 	if err := c.client.List(ctx, pods,
 		client.InNamespace(common.WorkloadsNamespace),
-		client.MatchingLabels{"app": common.AppName(common.ComponentGPUFeatureDiscovery)}); err != nil {
+		client.MatchingLabels{"app": common.AppName(common.ComponentGFDExtender)}); err != nil {
 		return result, err
 	}
 
-	var targetPod *corev1.Pod
-	var port int32
+	log := logr.FromContextOrDiscard(ctx).WithValues("node", node)
+	var endpoint string
 	for i := range pods.Items {
 		pod := &pods.Items[i]
-		if pod.Spec.NodeName != node {
+		if pod.Spec.NodeName != node || !isTrustedDetectionPod(pod) {
 			continue
 		}
-		if pod.Status.PodIP == "" || !isPodReady(pod) {
+		if pod.Status.PodIP == "" || !commonpod.IsReady(pod) {
 			continue
 		}
-		p := detectGPUPort(pod)
-		if p == 0 {
+		addr, err := commonpod.Endpoint(pod, c.ports)
+		if err != nil {
+			log.Info("gfd-extender pod exposes no usable detection port, skipping it", "pod", pod.Name, "reason", err.Error())
 			continue
 		}
-		targetPod = pod
-		port = p
+		endpoint = addr
 		break
 	}
 
-	if targetPod == nil {
+	if endpoint == "" {
 		// GFD DaemonSet ещё не готов — не считаем это ошибкой, просто пропускаем цикл.
 		return result, nil
 	}
 
-	url := fmt.Sprintf("http://%s:%d%s", targetPod.Status.PodIP, port, detectGPUPath)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	base := "http://" + endpoint
+
+	// Extenders built before the versioned API answer v2 with 404; anything unusable there falls back to v1.
+	caller := c.http
+	if detectHTTPClient != nil {
+		caller = caller.WithClient(detectHTTPClient)
+	}
+	payload, err := fetchDetectionsV2(ctx, caller, base)
+	devices, consumed := payload.Devices, payload.SchemaVersion
 	if err != nil {
-		return result, nil
+		log.V(1).Info("detection API v2 unavailable, falling back to v1", "reason", err.Error())
+		var ok bool
+		devices, ok, err = fetchDetectionsV1(ctx, caller, base)
+		if err != nil || !ok {
+			return result, commonerrors.Wrap(commonerrors.ErrTelemetryUnavailable, err)
+		}
+		consumed = 1
+	} else {
+		result.platform = platformStatus(payload.Platform)
+		if consumed > detection.SchemaVersion {
+			log.V(1).Info("gfd-extender serves a newer detection schema, using the fields this controller knows",
+				"servedSchemaVersion", consumed, "schemaVersion", detection.SchemaVersion)
+			consumed = detection.SchemaVersion
+		}
+	}
+	log.V(1).Info("consumed GPU detections", "schemaVersion", consumed, "devices", len(devices))
+	c.metrics.InventoryDetectionSchemaSet(node, consumed)
+	result.reported = true
+
+	for _, entry := range devices {
+		if entry.InitError != "" {
+			log.V(1).Info("gfd-extender could not initialise GPU", "index", entry.Index, "error", entry.InitError)
+			if entry.Index >= 0 {
+				if result.initFailed == nil {
+					result.initFailed = make(map[string]struct{})
+				}
+				result.initFailed[strconv.Itoa(entry.Index)] = struct{}{}
+			}
+			continue
+		}
+		if entry.UUID != "" {
+			result.byUUID[entry.UUID] = entry
+		}
+		if entry.Index < 0 {
+			log.V(1).Info("gfd-extender reported a negative GPU index, matching the device by UUID only", "index", entry.Index, "uuid", entry.UUID)
+			continue
+		}
+		result.byIndex[strconv.Itoa(entry.Index)] = entry
 	}
 
-	resp, err := detectHTTPClient.Do(req)
+	return result, nil
+}
+
+// fetchDetectionsV2 returns the envelope served by the extender.
+func fetchDetectionsV2(ctx context.Context, caller *httpcall.Caller, base string) (detection.Response, error) {
+	var payload detection.Response
+	err := getDetections(ctx, caller, base+detection.PathV2, func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		// При старте pod может не слушать ещё; не шумим и не блокируем reconcile.
-		return result, nil
+		return payload, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return result, nil
+	if payload.SchemaVersion < 2 {
+		return payload, fmt.Errorf("unexpected schema version %d", payload.SchemaVersion)
 	}
+	return payload, nil
+}
 
-	var entries []detectGPUEntry
-	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
-		return result, err
+// platformStatus converts the reported host facts; fields the extender left empty stay omitted.
+func platformStatus(p *detection.Platform) *v1alpha1.GPUNodePlatform {
+	if p == nil {
+		return nil
 	}
-
-	for _, entry := range entries {
-		if entry.UUID != "" {
-			result.byUUID[entry.UUID] = entry
-		}
-		indexKey := strconv.Itoa(entry.Index)
-		result.byIndex[indexKey] = entry
+	status := &v1alpha1.GPUNodePlatform{
+		KernelRelease:        p.KernelRelease,
+		BareMetal:            p.BareMetal,
+		Hypervisor:           p.Hypervisor,
+		SecureBoot:           p.SecureBoot,
+		IOMMU:                p.IOMMU,
+		UnsignedDriverModule: p.UnsignedDriverModule,
 	}
+	if p.OS != nil {
+		status.OSID, status.OSVersion, status.OSName = p.OS.ID, p.OS.VersionID, p.OS.Name
+	}
+	return status
+}
 
-	return result, nil
+// Platform returns the host facts of the node, or nil when the extender did not report them.
+func (n NodeDetection) Platform() *v1alpha1.GPUNodePlatform {
+	return n.platform
 }
 
-func detectGPUPort(pod *corev1.Pod) int32 {
-	for _, container := range pod.Spec.Containers {
-		if container.Name != "gfd-extender" {
-			continue
+// errMalformedDetections marks a v1 body that does not decode.
+var errMalformedDetections = errors.New("malformed detections")
+
+// fetchDetectionsV1 reports ok=false when the extender is not reachable yet; a malformed body and a call cut short by
+// its deadline or by ctx are errors.
+func fetchDetectionsV1(ctx context.Context, caller *httpcall.Caller, base string) ([]detection.Device, bool, error) {
+	var entries []detection.Device
+	ok := false
+	err := getDetections(ctx, caller, base+detection.PathV1, func(resp *http.Response) error {
+		if resp.StatusCode != http.StatusOK {
+			return nil
 		}
-		for _, port := range container.Ports {
-			if port.ContainerPort > 0 {
-				return port.ContainerPort
-			}
+		if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			return fmt.Errorf("%w: %w", errMalformedDetections, err)
 		}
+		ok = true
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMalformedDetections) && !errors.Is(err, httpcall.ErrDeadlineExceeded) && ctx.Err() == nil {
+		// При старте pod может не слушать ещё; не шумим и не блокируем reconcile.
+		return nil, false, nil
+	}
+	return entries, ok, err
+}
+
+func getDetections(ctx context.Context, caller *httpcall.Caller, url string, handle func(*http.Response) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	// The token is re-read on every scrape because kubelet rotates projected tokens.
+	if token, err := os.ReadFile(serviceAccountTokenPath); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return caller.Do(ctx, req, handle)
+}
+
+// isTrustedDetectionPod checks that the pod runs under the gfd-extender service account and is owned by its
+// DaemonSet, so a pod that merely copies the app label cannot feed detections.
+func isTrustedDetectionPod(pod *corev1.Pod) bool {
+	name := common.AppName(common.ComponentGFDExtender)
+	if pod.Spec.ServiceAccountName != name {
+		return false
 	}
-	return 0
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.APIVersion == "apps/v1" && owner.Kind == "DaemonSet" && owner.Name == name
 }
 
-func (n NodeDetection) find(snapshot invstate.DeviceSnapshot) (detectGPUEntry, bool) {
+func (n NodeDetection) find(snapshot invstate.DeviceSnapshot) (detection.Device, bool) {
 	if snapshot.UUID != "" {
 		if entry, ok := n.byUUID[snapshot.UUID]; ok {
 			return entry, true
@@ -204,28 +281,33 @@ func (n NodeDetection) find(snapshot invstate.DeviceSnapshot) (detectGPUEntry, b
 	if entry, ok := n.byIndex[snapshot.Index]; ok {
 		return entry, true
 	}
-	return detectGPUEntry{}, false
+	return detection.Device{}, false
+}
+
+// GPUUtilization returns the GPU utilization ratio reported for the device with the given UUID or index.
+func (n NodeDetection) GPUUtilization(uuid, index string) (float64, bool) {
+	entry, ok := n.find(invstate.DeviceSnapshot{UUID: uuid, Index: index})
+	if !ok {
+		return 0, false
+	}
+	if entry.Utilization.GPU > 100 {
+		return 0, false
+	}
+	return float64(entry.Utilization.GPU) / 100, true
 }
 
 func ApplyDetection(device *v1alpha1.GPUDevice, snapshot invstate.DeviceSnapshot, detections NodeDetection) {
 	entry, ok := detections.find(snapshot)
 	if !ok {
+		applyMemory(device, snapshot.MemoryMiB, detection.Device{})
 		return
 	}
 
 	applyDetectionHardware(device, entry)
+	applyMemory(device, snapshot.MemoryMiB, entry)
 }
 
-func isPodReady(pod *corev1.Pod) bool {
-	for _, cond := range pod.Status.Conditions {
-		if cond.Type == corev1.PodReady {
-			return cond.Status == corev1.ConditionTrue
-		}
-	}
-	return false
-}
-
-func applyDetectionHardware(device *v1alpha1.GPUDevice, entry detectGPUEntry) {
+func applyDetectionHardware(device *v1alpha1.GPUDevice, entry detection.Device) {
 	hw := &device.Status.Hardware
 
 	if entry.Product != "" {
@@ -248,6 +330,29 @@ func applyDetectionHardware(device *v1alpha1.GPUDevice, entry detectGPUEntry) {
 			hw.PCI.Address = addr
 		}
 	}
+	if capability := ComputeCapability(entry.ComputeMajor, entry.ComputeMinor); capability != "" {
+		hw.ComputeCapability = capability
+	}
+	if precision := normalizePrecision(entry.Precision); len(precision) > 0 {
+		hw.Precision = precision
+	}
+	// A reported entry without the CC block means the driver cannot tell, so the mode is omitted rather than kept stale.
+	if entry.ConfidentialComputing != nil {
+		hw.ConfidentialComputing = &v1alpha1.GPUConfidentialComputing{
+			Enabled:         entry.ConfidentialComputing.Enabled,
+			DevToolsAllowed: entry.ConfidentialComputing.DevToolsAllowed,
+		}
+	} else {
+		hw.ConfidentialComputing = nil
+	}
+	switch {
+	case entry.PersistenceMode == nil:
+		hw.PersistenceMode = ""
+	case *entry.PersistenceMode:
+		hw.PersistenceMode = v1alpha1.GPUPersistenceModeEnabled
+	default:
+		hw.PersistenceMode = v1alpha1.GPUPersistenceModeDisabled
+	}
 	if entry.MIG.Capable {
 		hw.MIG.Capable = true
 	}
@@ -282,7 +387,53 @@ func applyDetectionHardware(device *v1alpha1.GPUDevice, entry detectGPUEntry) {
 		sort.Strings(profiles)
 		hw.MIG.ProfilesSupported = profiles
 	}
+	if types := migTypesFromInstances(entry.MIGInstances); len(types) > 0 {
+		hw.MIG.Types = types
+	}
 	if !hw.MIG.Capable && len(hw.MIG.ProfilesSupported) > 0 {
 		hw.MIG.Capable = true
 	}
 }
+
+// migTypesFromInstances counts the MIG instances carved out of a GPU per profile. NVML sees every instance,
+// so a mixed layout reports all of its profiles even where the GFD labels only carry the node-wide counts.
+func migTypesFromInstances(instances []detection.MIGInstance) []v1alpha1.GPUMIGTypeCapacity {
+	counts := make(map[string]int32, len(instances))
+	for _, instance := range instances {
+		profile := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(instance.Profile)), "mig-")
+		if profile == "" {
+			continue
+		}
+		counts[profile]++
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	types := make([]v1alpha1.GPUMIGTypeCapacity, 0, len(counts))
+	for name, count := range counts {
+		types = append(types, v1alpha1.GPUMIGTypeCapacity{Name: name, Count: count})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+func normalizePrecision(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, raw := range values {
+		value := strings.ToLower(strings.TrimSpace(raw))
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestConfidentialComputingFromDetection(t *testing.T) {
+	cases := []struct {
+		name      string
+		device    string
+		want      *v1alpha1.GPUConfidentialComputing
+		wantLabel string
+	}{
+		{
+			name:      "enabled",
+			device:    `{"index":0,"uuid":"GPU-0","confidentialComputing":{"enabled":true,"devToolsAllowed":true}}`,
+			want:      &v1alpha1.GPUConfidentialComputing{Enabled: true, DevToolsAllowed: true},
+			wantLabel: "true",
+		},
+		{
+			name:      "disabled",
+			device:    `{"index":0,"uuid":"GPU-0","confidentialComputing":{"enabled":false,"devToolsAllowed":false}}`,
+			want:      &v1alpha1.GPUConfidentialComputing{},
+			wantLabel: "false",
+		},
+		{
+			// Older drivers cannot report the mode, so gfd-extender omits the block.
+			name:   "unsupported driver",
+			device: `{"index":0,"uuid":"GPU-0"}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			detections := collectFrom(t, func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"schemaVersion":2,"generatedAt":"2025-01-01T00:00:00Z","devices":[` + tc.device + `]}`))
+			})
+
+			scheme := newTestScheme(t)
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-cc", UID: types.UID("node-cc")}}
+			device := &v1alpha1.GPUDevice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-cc-0",
+					Labels: map[string]string{invstate.DeviceConfidentialComputingLabelKey: "stale"},
+				},
+			}
+			device.Status.Hardware.ConfidentialComputing = &v1alpha1.GPUConfidentialComputing{Enabled: true}
+			svc := &DeviceService{client: newTestClient(t, scheme, node, device), scheme: scheme}
+
+			snapshot := invstate.DeviceSnapshot{Index: "0", UUID: "GPU-0"}
+			apply := func(dev *v1alpha1.GPUDevice, snap invstate.DeviceSnapshot) { ApplyDetection(dev, snap, detections) }
+			if _, err := svc.ensureDeviceMetadata(context.Background(), node, device, snapshot, previewConfidentialComputing(device, snapshot, apply)); err != nil {
+				t.Fatalf("ensureDeviceMetadata returned error: %v", err)
+			}
+			apply(device, snapshot)
+
+			got := device.Status.Hardware.ConfidentialComputing
+			if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+				t.Fatalf("unexpected confidential computing state: got %+v, want %+v", got, tc.want)
+			}
+			label, ok := device.Labels[invstate.DeviceConfidentialComputingLabelKey]
+			if tc.wantLabel == "" {
+				if ok {
+					t.Fatalf("expected label to be removed, got %q", label)
+				}
+				return
+			}
+			if label != tc.wantLabel {
+				t.Fatalf("unexpected label value %q, want %q", label, tc.wantLabel)
+			}
+		})
+	}
+}
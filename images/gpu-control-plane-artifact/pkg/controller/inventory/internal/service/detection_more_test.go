@@ -17,9 +17,12 @@ package service
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -36,7 +39,7 @@ func TestCollectNodeDetectionsListError(t *testing.T) {
 		},
 	}
 
-	collector := NewDetectionCollector(cl)
+	collector := NewDetectionCollector(cl, httpcall.New(httpcall.CategoryDetection, 0), nil)
 	if _, err := collector.Collect(context.Background(), "node"); !errors.Is(err, boom) {
 		t.Fatalf("expected error %v, got %v", boom, err)
 	}
@@ -54,13 +57,13 @@ func TestApplyDetectionHardwareMIGNormalization(t *testing.T) {
 		},
 	}
 
-	entry := detectGPUEntry{
-		MIG: detectGPUMIG{
+	entry := detection.Device{
+		MIG: detection.MIGInfo{
 			Capable:           false,
 			Mode:              " single ",
 			ProfilesSupported: []string{"", "mig-2g.20gb", "MIG-1g.10gb", "mig-2g.20gb", " 1g.10gb "},
 		},
-		PCI: detectGPUPCI{
+		PCI: detection.PCIInfo{
 			Address: "00000000:65:00.0",
 			Vendor:  "10DE",
 			Device:  "2203",
@@ -119,10 +122,10 @@ func TestApplyDetectionDoesNotOverridePCIFieldsWhenAlreadySet(t *testing.T) {
 	}
 
 	ApplyDetection(device, invstate.DeviceSnapshot{Index: "0"}, NodeDetection{
-		byIndex: map[string]detectGPUEntry{
+		byIndex: map[string]detection.Device{
 			"0": {
 				Index: 0,
-				PCI:   detectGPUPCI{Vendor: "ffff", Device: "ffff", Class: "ffff"},
+				PCI:   detection.PCIInfo{Vendor: "ffff", Device: "ffff", Class: "ffff"},
 			},
 		},
 	})
@@ -131,3 +134,54 @@ func TestApplyDetectionDoesNotOverridePCIFieldsWhenAlreadySet(t *testing.T) {
 		t.Fatalf("pci fields must not be overwritten: %+v", device.Status.Hardware.PCI)
 	}
 }
+
+func TestApplyDetectionHardwarePersistenceMode(t *testing.T) {
+	enabled, disabled := true, false
+	for _, tc := range []struct {
+		name string
+		mode *bool
+		want v1alpha1.GPUPersistenceMode
+	}{
+		{name: "enabled", mode: &enabled, want: v1alpha1.GPUPersistenceModeEnabled},
+		{name: "disabled", mode: &disabled, want: v1alpha1.GPUPersistenceModeDisabled},
+		// A driver that cannot report the mode clears it instead of leaving a stale value.
+		{name: "unreported", want: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			device := &v1alpha1.GPUDevice{}
+			device.Status.Hardware.PersistenceMode = v1alpha1.GPUPersistenceModeEnabled
+
+			applyDetectionHardware(device, detection.Device{PersistenceMode: tc.mode})
+
+			if got := device.Status.Hardware.PersistenceMode; got != tc.want {
+				t.Fatalf("expected persistence mode %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyDetectionCountsMixedMIGInstances(t *testing.T) {
+	device := &v1alpha1.GPUDevice{}
+	device.Status.Hardware.MIG.Types = []v1alpha1.GPUMIGTypeCapacity{{Name: "1g.10gb", Count: 7}}
+
+	applyDetectionHardware(device, detection.Device{
+		MIG: detection.MIGInfo{Capable: true, Mode: "mixed"},
+		MIGInstances: []detection.MIGInstance{
+			{UUID: "MIG-a", Profile: "3g.40gb"},
+			{UUID: "MIG-b", Profile: "1g.10gb"},
+			{UUID: "MIG-c", Profile: "1G.10GB"},
+			{UUID: "MIG-d", Profile: "1g.10gb"},
+			{UUID: "MIG-e"},
+		},
+	})
+
+	want := []v1alpha1.GPUMIGTypeCapacity{{Name: "1g.10gb", Count: 3}, {Name: "3g.40gb", Count: 1}}
+	if !reflect.DeepEqual(device.Status.Hardware.MIG.Types, want) {
+		t.Fatalf("unexpected MIG types: %+v", device.Status.Hardware.MIG.Types)
+	}
+
+	applyDetectionHardware(device, detection.Device{MIG: detection.MIGInfo{Capable: true}})
+	if !reflect.DeepEqual(device.Status.Hardware.MIG.Types, want) {
+		t.Fatalf("types must be kept when detection reports no instances, got %+v", device.Status.Hardware.MIG.Types)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
+)
+
+// collectFrom runs the collector against an extender answering with handler.
+func collectFrom(t *testing.T, handler http.HandlerFunc) NodeDetection {
+	t.Helper()
+	detections, err := tryCollectFrom(t, handler)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return detections
+}
+
+func tryCollectFrom(t *testing.T, handler http.HandlerFunc) (NodeDetection, error) {
+	t.Helper()
+	return tryCollectWith(t, handler, httpcall.New(httpcall.CategoryDetection, 0))
+}
+
+func tryCollectWith(t *testing.T, handler http.HandlerFunc, caller *httpcall.Caller) (NodeDetection, error) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	host, portStr, _ := strings.Cut(server.Listener.Addr().String(), ":")
+	port, _ := strconv.Atoi(portStr)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-schema"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "gfd-pod",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
+			Containers: []corev1.Container{{
+				Name:  "gfd-extender",
+				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			PodIP:      host,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	orig := detectHTTPClient
+	detectHTTPClient = server.Client()
+	t.Cleanup(func() { detectHTTPClient = orig })
+
+	collector := NewDetectionCollector(newTestClient(t, newTestScheme(t), node, pod), caller, nil)
+	return collector.Collect(context.Background(), node.Name)
+}
+
+func TestCollectNodeDetectionsStuckExtenderHitsCallDeadline(t *testing.T) {
+	start := time.Now()
+	_, err := tryCollectWith(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Minute):
+		}
+	}, httpcall.New(httpcall.CategoryDetection, 50*time.Millisecond))
+
+	if !errors.Is(err, commonerrors.ErrTelemetryUnavailable) || !errors.Is(err, httpcall.ErrDeadlineExceeded) {
+		t.Fatalf("expected a call deadline reported as telemetry unavailable, got %v", err)
+	}
+	// Both the v2 attempt and the v1 fallback are bounded by the call deadline, not by the reconcile.
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the scrape to give up after its deadline, took %s", elapsed)
+	}
+}
+
+func TestCollectNodeDetectionsMarksUnreadableTelemetry(t *testing.T) {
+	_, err := tryCollectFrom(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != detection.PathV1 {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`not json`))
+	})
+	if !errors.Is(err, commonerrors.ErrTelemetryUnavailable) {
+		t.Fatalf("expected ErrTelemetryUnavailable, got %v", err)
+	}
+}
+
+func TestCollectNodeDetectionsPrefersV2(t *testing.T) {
+	var paths []string
+	detections := collectFrom(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != detection.PathV2 {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"schemaVersion":2,"generatedAt":"2025-01-01T00:00:00Z","devices":[
+			{"index":0,"uuid":"GPU-0","product":"NVIDIA A100","migInstances":[{"uuid":"MIG-0","profile":"1g.5gb"}],"eccErrors":{"corrected":1,"uncorrected":0}},
+			{"index":1,"initError":"get handle: Unknown Error"}
+		]}`))
+	})
+
+	if len(paths) != 1 {
+		t.Fatalf("expected a single v2 request, got %v", paths)
+	}
+	entry, ok := detections.byUUID["GPU-0"]
+	if !ok || entry.Product != "NVIDIA A100" {
+		t.Fatalf("expected v2 device to be stored, got %+v", detections.byUUID)
+	}
+	if len(entry.MIGInstances) != 1 || entry.ECCErrors == nil || entry.ECCErrors.Corrected != 1 {
+		t.Fatalf("expected v2-only fields to be decoded, got %+v", entry)
+	}
+	if _, ok := detections.byIndex["1"]; ok {
+		t.Fatalf("expected device with init error to be skipped, got %+v", detections.byIndex)
+	}
+}
+
+func TestCollectNodeDetectionsFallsBackToV1(t *testing.T) {
+	var paths []string
+	detections := collectFrom(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != detection.PathV1 {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"index":0,"uuid":"GPU-legacy"}]`))
+	})
+
+	if len(paths) != 2 || paths[0] != detection.PathV2 || paths[1] != detection.PathV1 {
+		t.Fatalf("expected v2 then v1 requests, got %v", paths)
+	}
+	if _, ok := detections.byUUID["GPU-legacy"]; !ok {
+		t.Fatalf("expected v1 devices after fallback, got %+v", detections.byUUID)
+	}
+}
+
+func TestCollectNodeDetectionsFutureSchemaDegradesGracefully(t *testing.T) {
+	detections := collectFrom(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != detection.PathV2 {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"schemaVersion":7,"generatedAt":"2030-01-01T00:00:00Z","topology":{"nvlink":true},"devices":[
+			{"index":0,"uuid":"GPU-future","memoryMiB":81920,"thermalThrottle":{"active":false}}
+		]}`))
+	})
+
+	entry, ok := detections.byUUID["GPU-future"]
+	if !ok || entry.MemoryMiB != 81920 {
+		t.Fatalf("expected known fields of a newer schema to be used, got %+v", detections.byUUID)
+	}
+}
+
+func TestFetchDetectionsV2RejectsUnversionedPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"devices":[]}`))
+	}))
+	defer server.Close()
+	orig := detectHTTPClient
+	detectHTTPClient = server.Client()
+	defer func() { detectHTTPClient = orig }()
+
+	if _, err := fetchDetectionsV2(context.Background(), httpcall.New(httpcall.CategoryDetection, 0).WithClient(detectHTTPClient), server.URL); err == nil {
+		t.Fatalf("expected payload without schemaVersion to be rejected")
+	}
+}
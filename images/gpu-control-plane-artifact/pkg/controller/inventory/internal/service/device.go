@@ -16,11 +16,13 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -29,9 +31,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/canonical"
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
 	invpci "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/pci"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
@@ -43,21 +48,51 @@ type DeviceHandler interface {
 }
 
 type DeviceService struct {
-	client   client.Client
-	scheme   *runtime.Scheme
-	recorder eventrecord.EventRecorderLogger
-	handlers []DeviceHandler
+	client       client.Client
+	scheme       *runtime.Scheme
+	recorder     eventrecord.EventRecorderLogger
+	handlers     []DeviceHandler
+	runtime      *HandlerRuntime
+	writeWorkers int
+	nameTemplate func() string
+	// attributePrefixes returns inventory.attributePassthroughPrefixes.
+	attributePrefixes func() []string
+	metrics           *invmetrics.Metrics
+	guard             *ownership.Guard
 }
 
-func NewDeviceService(c client.Client, scheme *runtime.Scheme, recorder eventrecord.EventRecorderLogger, handlers []DeviceHandler) *DeviceService {
+// NewDeviceService builds the device service; a nil guard lets it write every device.
+func NewDeviceService(c client.Client, scheme *runtime.Scheme, recorder eventrecord.EventRecorderLogger, handlers []DeviceHandler, metrics *invmetrics.Metrics, guard *ownership.Guard) *DeviceService {
 	return &DeviceService{
 		client:   c,
 		scheme:   scheme,
 		recorder: recorder,
 		handlers: handlers,
+		metrics:  metrics,
+		guard:    guard,
 	}
 }
 
+// SetStatusWriteWorkers bounds the number of concurrent device status writes issued for one node.
+func (s *DeviceService) SetStatusWriteWorkers(workers int) {
+	s.writeWorkers = workers
+}
+
+// SetNameTemplate supplies the inventory.deviceNameTemplate used for newly created devices.
+func (s *DeviceService) SetNameTemplate(template func() string) {
+	s.nameTemplate = template
+}
+
+// SetAttributePassthroughPrefixes supplies the instance attribute prefixes mirrored into device annotations.
+func (s *DeviceService) SetAttributePassthroughPrefixes(prefixes func() []string) {
+	s.attributePrefixes = prefixes
+}
+
+// SetHandlerRuntime makes device handlers honour the runtime settings from ModuleConfig.
+func (s *DeviceService) SetHandlerRuntime(runtime *HandlerRuntime) {
+	s.runtime = runtime
+}
+
 func (s *DeviceService) Reconcile(
 	ctx context.Context,
 	node *corev1.Node,
@@ -67,27 +102,167 @@ func (s *DeviceService) Reconcile(
 	approval invstate.DeviceApprovalPolicy,
 	applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
 ) (*v1alpha1.GPUDevice, reconcile.Result, error) {
-	deviceName := invstate.BuildDeviceName(node.Name, snapshot)
-	device := &v1alpha1.GPUDevice{}
-	device, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: deviceName}, s.client, device)
+	devices, result, err := s.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{snapshot}, nodeLabels, managed, approval, nil, applyDetection)
 	if err != nil {
-		return nil, reconcile.Result{}, err
+		return nil, result, err
 	}
+	return devices[0], result, nil
+}
+
+// ReconcileNode computes the desired status of every device on the node before writing anything, skips
+// devices whose status did not change and issues the remaining status writes through a bounded worker pool.
+// source identifies the NodeFeature the snapshots were parsed from; it is recorded on devices whose data it changed.
+func (s *DeviceService) ReconcileNode(
+	ctx context.Context,
+	node *corev1.Node,
+	snapshots []invstate.DeviceSnapshot,
+	nodeLabels map[string]string,
+	managed bool,
+	approval invstate.DeviceApprovalPolicy,
+	source *v1alpha1.GPUDataProvenance,
+	applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
+) ([]*v1alpha1.GPUDevice, reconcile.Result, error) {
+	devices := make([]*v1alpha1.GPUDevice, 0, len(snapshots))
+	pending := make([]*statusWrite, 0, len(snapshots))
+	aggregate := reconcile.Result{}
+	writes := 0
+	defer func() { s.metrics.InventoryDeviceWritesSet(node.Name, writes) }()
+
+	lookup, n, err := s.newDeviceLookup(ctx, node.Name)
+	writes += n
+	if err != nil {
+		return nil, aggregate, err
+	}
+	validation, err := s.nodeValidation(ctx, node.Name, approval)
+	if err != nil {
+		return nil, aggregate, err
+	}
+
+	for _, snapshot := range snapshots {
+		write, result, n, err := s.prepare(ctx, node, snapshot, lookup, nodeLabels, managed, approval, validation, source, applyDetection)
+		writes += n
+		aggregate = reconciler.MergeResults(aggregate, result)
+		if err != nil {
+			return nil, aggregate, err
+		}
+		devices = append(devices, write.device)
+		if write.needed() {
+			pending = append(pending, write)
+		}
+	}
+
+	result, n, err := s.writeStatuses(ctx, pending)
+	writes += n
+	aggregate = reconciler.MergeResults(aggregate, result)
+	if err != nil {
+		return nil, aggregate, err
+	}
+	canonical.Devices(devices)
+	return devices, aggregate, nil
+}
+
+// deviceLookup resolves the GPUDevice of a snapshot. Existing devices are matched by inventoryID, so
+// changing the naming template neither renames nor duplicates them; only new devices get templated names.
+type deviceLookup struct {
+	byInventoryID map[string]*v1alpha1.GPUDevice
+	template      string
+}
+
+// newDeviceLookup lists the devices of the node and merges devices that share an inventoryID into the oldest
+// one. It returns the number of API writes the merge issued.
+func (s *DeviceService) newDeviceLookup(ctx context.Context, nodeName string) (*deviceLookup, int, error) {
+	lookup := &deviceLookup{byInventoryID: make(map[string]*v1alpha1.GPUDevice)}
+	if s.nameTemplate != nil {
+		lookup.template = s.nameTemplate()
+	}
+	list := &v1alpha1.GPUDeviceList{}
+	if err := s.client.List(ctx, list, client.MatchingFields{invstate.DeviceNodeIndexKey: nodeName}); err != nil {
+		return nil, 0, err
+	}
+	groups := make(map[string][]*v1alpha1.GPUDevice)
+	for i := range list.Items {
+		if id := list.Items[i].Status.InventoryID; id != "" {
+			groups[id] = append(groups[id], &list.Items[i])
+		}
+	}
+	writes := 0
+	for id, devices := range groups {
+		device, n, err := s.mergeDuplicates(ctx, devices)
+		writes += n
+		if err != nil {
+			return nil, writes, err
+		}
+		lookup.byInventoryID[id] = device
+	}
+	return lookup, writes, nil
+}
+
+// prepare brings the device object and its metadata in place and computes the desired status without
+// writing it. It returns the number of API writes it had to issue.
+func (s *DeviceService) prepare(
+	ctx context.Context,
+	node *corev1.Node,
+	snapshot invstate.DeviceSnapshot,
+	lookup *deviceLookup,
+	nodeLabels map[string]string,
+	managed bool,
+	approval invstate.DeviceApprovalPolicy,
+	validation invstate.NodeValidation,
+	source *v1alpha1.GPUDataProvenance,
+	applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
+) (*statusWrite, reconcile.Result, int, error) {
+	device := lookup.byInventoryID[invstate.BuildInventoryID(node.Name, snapshot)]
 	if device == nil {
-		return s.createDevice(ctx, node, snapshot, nodeLabels, managed, approval, applyDetection)
+		// Devices whose first status write failed have no inventoryID yet and are found by name.
+		deviceName := invstate.BuildTemplatedDeviceName(lookup.template, node.Name, snapshot)
+		fetched, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: deviceName}, s.client, &v1alpha1.GPUDevice{})
+		if err != nil {
+			return nil, reconcile.Result{}, 0, err
+		}
+		if fetched == nil {
+			fetched, err = s.findByInventoryID(ctx, invstate.BuildInventoryID(node.Name, snapshot))
+			if err != nil {
+				return nil, reconcile.Result{}, 0, err
+			}
+		}
+		if fetched == nil {
+			return s.prepareCreate(ctx, node, snapshot, deviceName, nodeLabels, managed, approval, validation, source, applyDetection)
+		}
+		device = fetched
 	}
+	deviceName := device.Name
 
-	metaUpdated, err := s.ensureDeviceMetadata(ctx, node, device, snapshot)
+	if allowed, err := s.guard.MayWrite(ctx, device); err != nil {
+		return nil, reconcile.Result{}, 0, err
+	} else if !allowed {
+		// Another live installation manages this device; report it as is and write nothing.
+		return &statusWrite{device: device, base: device.DeepCopy()}, reconcile.Result{}, 0, nil
+	}
+
+	if observed, tooNew := reconciler.SchemaTooNew(device); tooNew {
+		// A newer controller owns this status layout; only surface the condition.
+		base := device.DeepCopy()
+		reconciler.MarkSchemaTooNew(device, observed)
+		return &statusWrite{device: device, base: base}, reconcile.Result{}, 0, nil
+	}
+
+	writes := 0
+	metaUpdated, err := s.ensureDeviceMetadata(ctx, node, device, snapshot, previewConfidentialComputing(device, snapshot, applyDetection))
+	if metaUpdated {
+		writes++
+	}
 	if err != nil {
-		return nil, reconcile.Result{}, err
+		return nil, reconcile.Result{}, writes, err
 	}
 	if metaUpdated {
 		if err := s.client.Get(ctx, types.NamespacedName{Name: deviceName}, device); err != nil {
-			return nil, reconcile.Result{}, err
+			return nil, reconcile.Result{}, writes, err
 		}
 	}
 
 	statusBefore := device.DeepCopy()
+	meta.RemoveStatusCondition(&device.Status.Conditions, reconciler.ConditionSchemaTooNew)
+	meta.RemoveStatusCondition(&device.Status.Conditions, invstate.ConditionNodeUnreachable)
 	desiredInventoryID := invstate.BuildInventoryID(node.Name, snapshot)
 	desiredPCIAddress := invpci.CanonicalizePCIAddress(snapshot.PCIAddress)
 
@@ -118,6 +293,8 @@ func (s *DeviceService) Reconcile(
 	if !equality.Semantic.DeepEqual(device.Status.Hardware.MIG, snapshot.MIG) {
 		device.Status.Hardware.MIG = snapshot.MIG
 	}
+	applySnapshotCapabilities(&device.Status.Hardware, snapshot)
+	applyMIGConflictCondition(device, snapshot)
 	autoAttach := approval.AutoAttach(managed, invstate.LabelsForDevice(snapshot, nodeLabels))
 	if device.Status.AutoAttach != autoAttach {
 		device.Status.AutoAttach = autoAttach
@@ -127,48 +304,53 @@ func (s *DeviceService) Reconcile(
 		applyDetection(device, snapshot)
 	}
 	device.Status.Hardware.PCI.Address = invpci.CanonicalizePCIAddress(device.Status.Hardware.PCI.Address)
+	canonical.Hardware(&device.Status.Hardware)
+	gate := applyValidationGate(device, statusBefore.Status.DriverVersion, approval, validation)
 
 	result, err := s.invokeHandlers(ctx, device)
+	result = reconciler.MergeResults(result, gate)
 	if err != nil {
-		return nil, result, err
+		return nil, result, writes, err
 	}
 
-	if !equality.Semantic.DeepEqual(statusBefore.Status, device.Status) {
-		if err := s.client.Status().Patch(ctx, device, client.MergeFrom(statusBefore)); err != nil {
-			if apierrors.IsConflict(err) {
-				return device, reconciler.MergeResults(result, reconcile.Result{Requeue: true}), nil
-			}
-			return nil, result, err
-		}
+	if inventoryStatusPatch(statusBefore, device).Len() > 0 {
+		stampProvenance(device, source)
 	}
-
-	return device, result, nil
+	return &statusWrite{device: device, base: statusBefore}, result, writes, nil
 }
 
-func (s *DeviceService) createDevice(
+func (s *DeviceService) prepareCreate(
 	ctx context.Context,
 	node *corev1.Node,
 	snapshot invstate.DeviceSnapshot,
+	name string,
 	nodeLabels map[string]string,
 	managed bool,
 	approval invstate.DeviceApprovalPolicy,
+	validation invstate.NodeValidation,
+	source *v1alpha1.GPUDataProvenance,
 	applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
-) (*v1alpha1.GPUDevice, reconcile.Result, error) {
+) (*statusWrite, reconcile.Result, int, error) {
 	device := &v1alpha1.GPUDevice{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: invstate.BuildDeviceName(node.Name, snapshot),
+			Name: name,
 			Labels: map[string]string{
 				invstate.DeviceNodeLabelKey:  node.Name,
 				invstate.DeviceIndexLabelKey: snapshot.Index,
 			},
 		},
 	}
+	setConfidentialComputingLabel(device.Labels, previewConfidentialComputing(device, snapshot, applyDetection))
+	s.applyAttributeAnnotations(device, snapshot)
 	if err := controllerutil.SetOwnerReference(node, device, s.scheme); err != nil {
-		return nil, reconcile.Result{}, err
+		return nil, reconcile.Result{}, 0, err
 	}
+	reconciler.StampReconciledBy(device, s.guard)
 
+	// Labels, annotations, the version stamp and the owner reference go into the create itself, so a new device costs exactly two writes:
+	// the create and a single status update below.
 	if err := s.client.Create(ctx, device); err != nil {
-		return nil, reconcile.Result{}, err
+		return nil, reconcile.Result{}, 1, err
 	}
 	if s.recorder != nil {
 		log := logr.FromContextOrDiscard(ctx).WithValues(
@@ -199,6 +381,8 @@ func (s *DeviceService) createDevice(
 	device.Status.Hardware.Product = snapshot.Product
 	device.Status.Hardware.UUID = snapshot.UUID
 	device.Status.Hardware.MIG = snapshot.MIG
+	applySnapshotCapabilities(&device.Status.Hardware, snapshot)
+	applyMIGConflictCondition(device, snapshot)
 	device.Status.State = v1alpha1.GPUDeviceStateDiscovered
 	device.Status.AutoAttach = approval.AutoAttach(managed, invstate.LabelsForDevice(snapshot, nodeLabels))
 
@@ -206,23 +390,95 @@ func (s *DeviceService) createDevice(
 		applyDetection(device, snapshot)
 	}
 	device.Status.Hardware.PCI.Address = invpci.CanonicalizePCIAddress(device.Status.Hardware.PCI.Address)
+	canonical.Hardware(&device.Status.Hardware)
+	gate := applyValidationGate(device, "", approval, validation)
 
 	result, err := s.invokeHandlers(ctx, device)
+	result = reconciler.MergeResults(result, gate)
 	if err != nil {
-		return nil, result, err
+		return nil, result, 1, err
 	}
+	stampProvenance(device, source)
 
-	if err := s.client.Status().Update(ctx, device); err != nil {
-		if apierrors.IsConflict(err) {
-			return device, reconciler.MergeResults(result, reconcile.Result{Requeue: true}), nil
-		}
-		return nil, result, err
+	return &statusWrite{device: device}, result, 1, nil
+}
+
+// stampProvenance records the NodeFeature revision that produced the device data. Callers stamp only when that
+// data changed the status, so a resync against the same NodeFeature leaves the previous capture in place.
+func stampProvenance(device *v1alpha1.GPUDevice, source *v1alpha1.GPUDataProvenance) {
+	if source == nil {
+		return
+	}
+	provenance := source.DeepCopy()
+	provenance.CapturedAt = metav1.Now()
+	device.Status.Provenance = provenance
+}
+
+// applySnapshotCapabilities copies the characteristics pool requirements are evaluated against.
+func applySnapshotCapabilities(hw *v1alpha1.GPUDeviceHardware, snapshot invstate.DeviceSnapshot) {
+	hw.MemoryMiB = snapshot.MemoryMiB
+	hw.ComputeCapability = ComputeCapability(snapshot.ComputeMajor, snapshot.ComputeMinor)
+	hw.Precision = nil
+	if len(snapshot.Precision) > 0 {
+		hw.Precision = append([]string(nil), snapshot.Precision...)
+	}
+}
+
+// applyMIGConflictCondition reports a MIG strategy the device instance and the node labels disagree on; the
+// condition is dropped once they agree again.
+func applyMIGConflictCondition(device *v1alpha1.GPUDevice, snapshot invstate.DeviceSnapshot) {
+	if snapshot.MIGConflict == "" {
+		meta.RemoveStatusCondition(&device.Status.Conditions, invstate.ConditionMIGDataConflict)
+		return
+	}
+	conditions.SetCondition(conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionMIGDataConflict)).
+		Status(metav1.ConditionTrue).
+		Reason(conditions.CommonReason(invstate.ReasonMIGStrategyMismatch)).
+		Message(snapshot.MIGConflict).
+		Generation(device.Generation), &device.Status.Conditions)
+}
+
+// ComputeCapability formats a CUDA compute capability as major.minor; it is empty when unknown.
+func ComputeCapability(major, minor int32) string {
+	if major <= 0 || minor < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d", major, minor)
+}
+
+// previewConfidentialComputing returns the CC mode the detection data will leave in the status, so the label can be
+// written together with the other metadata before the status is built.
+func previewConfidentialComputing(
+	device *v1alpha1.GPUDevice,
+	snapshot invstate.DeviceSnapshot,
+	applyDetection func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot),
+) *v1alpha1.GPUConfidentialComputing {
+	preview := device.DeepCopy()
+	if applyDetection != nil {
+		applyDetection(preview, snapshot)
 	}
+	return preview.Status.Hardware.ConfidentialComputing
+}
 
-	return device, result, nil
+// setConfidentialComputingLabel reports whether the labels changed; the label is dropped while the mode is unknown.
+func setConfidentialComputingLabel(labels map[string]string, cc *v1alpha1.GPUConfidentialComputing) bool {
+	current, present := labels[invstate.DeviceConfidentialComputingLabelKey]
+	if cc == nil {
+		delete(labels, invstate.DeviceConfidentialComputingLabelKey)
+		return present
+	}
+	desired := strconv.FormatBool(cc.Enabled)
+	labels[invstate.DeviceConfidentialComputingLabelKey] = desired
+	return !present || current != desired
 }
 
-func (s *DeviceService) ensureDeviceMetadata(ctx context.Context, node *corev1.Node, device *v1alpha1.GPUDevice, snapshot invstate.DeviceSnapshot) (bool, error) {
+func (s *DeviceService) ensureDeviceMetadata(
+	ctx context.Context,
+	node *corev1.Node,
+	device *v1alpha1.GPUDevice,
+	snapshot invstate.DeviceSnapshot,
+	cc *v1alpha1.GPUConfidentialComputing,
+) (bool, error) {
 	desired := device.DeepCopy()
 	changed := false
 
@@ -237,16 +493,26 @@ func (s *DeviceService) ensureDeviceMetadata(ctx context.Context, node *corev1.N
 		desired.Labels[invstate.DeviceIndexLabelKey] = snapshot.Index
 		changed = true
 	}
+	if setConfidentialComputingLabel(desired.Labels, cc) {
+		changed = true
+	}
+	if s.applyAttributeAnnotations(desired, snapshot) {
+		changed = true
+	}
 	if err := controllerutil.SetOwnerReference(node, desired, s.scheme); err != nil {
 		return false, err
 	}
 	if !equality.Semantic.DeepEqual(device.GetOwnerReferences(), desired.GetOwnerReferences()) {
 		changed = true
 	}
+	if s.guard.Stamp(desired) {
+		changed = true
+	}
 
 	if !changed {
 		return false, nil
 	}
+	reconciler.StampReconciledBy(desired, s.guard)
 
 	if err := s.client.Patch(ctx, desired, client.MergeFrom(device)); err != nil {
 		return false, err
@@ -261,10 +527,11 @@ func (s *DeviceService) invokeHandlers(ctx context.Context, device *v1alpha1.GPU
 	rec.SetHandlerExecutor(func(ctx context.Context, handler DeviceHandler) (reconcile.Result, error) {
 		result, err := handler.HandleDevice(ctx, device)
 		if err != nil {
-			invmetrics.InventoryHandlerErrorInc(handler.Name())
+			s.metrics.InventoryHandlerErrorInc(handler.Name())
 		}
 		return result, err
 	})
+	rec.SetHandlerFilter(s.runtime.Enabled)
 	rec.SetResourceUpdater(func(context.Context) error { return nil })
 
 	return rec.Reconcile(ctx)
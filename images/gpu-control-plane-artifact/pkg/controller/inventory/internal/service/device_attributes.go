@@ -0,0 +1,146 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+const (
+	// maxPassthroughAttributes and maxPassthroughValueBytes keep mirrored attributes from bloating GPUDevice objects.
+	maxPassthroughAttributes = 20
+	maxPassthroughValueBytes = 256
+	// maxAnnotationNameLength bounds the part of a qualified annotation key after the "/".
+	maxAnnotationNameLength = 63
+)
+
+// applyAttributeAnnotations mirrors the instance attributes selected by inventory.attributePassthroughPrefixes
+// onto the device annotations and reports whether they changed.
+func (s *DeviceService) applyAttributeAnnotations(device *v1alpha1.GPUDevice, snapshot invstate.DeviceSnapshot) bool {
+	var prefixes []string
+	if s.attributePrefixes != nil {
+		prefixes = s.attributePrefixes()
+	}
+	desired := passthroughAnnotations(snapshot.Attributes, prefixes)
+	if device.Annotations == nil {
+		if len(desired) == 0 {
+			return false
+		}
+		device.Annotations = make(map[string]string, len(desired))
+	}
+	return syncAttributeAnnotations(device.Annotations, desired)
+}
+
+// passthroughAnnotations selects the instance attributes starting with one of the prefixes and maps them to
+// annotation keys. Attributes are taken in key order up to maxPassthroughAttributes; values over
+// maxPassthroughValueBytes and keys that sanitize to an already used annotation are skipped.
+func passthroughAnnotations(attrs map[string]string, prefixes []string) map[string]string {
+	if len(attrs) == 0 || len(prefixes) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		if hasAnyPrefix(key, prefixes) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string)
+	for _, key := range keys {
+		if len(result) == maxPassthroughAttributes {
+			break
+		}
+		value := attrs[key]
+		if len(value) > maxPassthroughValueBytes {
+			continue
+		}
+		annotation, ok := attributeAnnotationKey(key)
+		if !ok {
+			continue
+		}
+		if _, taken := result[annotation]; taken {
+			continue
+		}
+		result[annotation] = value
+	}
+	return result
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeAnnotationKey turns an attribute key into gpu.deckhouse.io/attr.<key>: characters not allowed in
+// annotation names become "-" and the name is cut to 63 characters.
+func attributeAnnotationKey(attr string) (string, bool) {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, attr)
+
+	domain, namePrefix, _ := strings.Cut(invstate.DeviceAttributeAnnotationPrefix, "/")
+	name = namePrefix + name
+	if len(name) > maxAnnotationNameLength {
+		name = name[:maxAnnotationNameLength]
+	}
+	name = strings.TrimRight(name, "-_.")
+	if len(name) <= len(namePrefix) {
+		return "", false
+	}
+
+	key := domain + "/" + name
+	if len(validation.IsQualifiedName(key)) > 0 {
+		return "", false
+	}
+	return key, true
+}
+
+// syncAttributeAnnotations makes the mirrored annotations equal to desired and reports whether anything changed;
+// annotations outside DeviceAttributeAnnotationPrefix are left alone.
+func syncAttributeAnnotations(annotations map[string]string, desired map[string]string) bool {
+	changed := false
+	for key := range annotations {
+		if !strings.HasPrefix(key, invstate.DeviceAttributeAnnotationPrefix) {
+			continue
+		}
+		if _, keep := desired[key]; !keep {
+			delete(annotations, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if current, ok := annotations[key]; !ok || current != value {
+			annotations[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestEnsureDeviceMetadataMirrorsAttributes(t *testing.T) {
+	scheme := newTestScheme(t)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "attr-node", UID: types.UID("attr-node")}}
+	device := &v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{
+		Name:        "attr-device",
+		Annotations: map[string]string{"example.com/keep": "yes"},
+	}}
+	cl := newTestClient(t, scheme, node, device)
+
+	prefixes := []string{"acme."}
+	svc := &DeviceService{client: cl, scheme: scheme, attributePrefixes: func() []string { return prefixes }}
+	ctx := context.Background()
+	snapshot := invstate.DeviceSnapshot{Index: "0", Attributes: map[string]string{
+		"acme.rack":  "r12",
+		"acme.batch": "2024-q1",
+		"uuid":       "GPU-1",
+	}}
+
+	// add
+	if changed, err := svc.ensureDeviceMetadata(ctx, node, device, snapshot, nil); err != nil || !changed {
+		t.Fatalf("expected annotations to be added, changed=%t err=%v", changed, err)
+	}
+	assertAnnotations(t, cl, device.Name, map[string]string{
+		"gpu.deckhouse.io/attr.acme.rack":  "r12",
+		"gpu.deckhouse.io/attr.acme.batch": "2024-q1",
+		"example.com/keep":                 "yes",
+	}, "gpu.deckhouse.io/attr.uuid")
+
+	if changed, err := svc.ensureDeviceMetadata(ctx, node, device, snapshot, nil); err != nil || changed {
+		t.Fatalf("expected no change on resync, changed=%t err=%v", changed, err)
+	}
+
+	// update and removal
+	snapshot.Attributes = map[string]string{"acme.rack": "r13"}
+	if changed, err := svc.ensureDeviceMetadata(ctx, node, device, snapshot, nil); err != nil || !changed {
+		t.Fatalf("expected annotations to be updated, changed=%t err=%v", changed, err)
+	}
+	assertAnnotations(t, cl, device.Name, map[string]string{
+		"gpu.deckhouse.io/attr.acme.rack": "r13",
+		"example.com/keep":                "yes",
+	}, "gpu.deckhouse.io/attr.acme.batch")
+
+	// dropping the prefix removes every mirrored annotation
+	prefixes = nil
+	if changed, err := svc.ensureDeviceMetadata(ctx, node, device, snapshot, nil); err != nil || !changed {
+		t.Fatalf("expected annotations to be removed, changed=%t err=%v", changed, err)
+	}
+	assertAnnotations(t, cl, device.Name, map[string]string{"example.com/keep": "yes"}, "gpu.deckhouse.io/attr.acme.rack")
+}
+
+func assertAnnotations(t *testing.T, cl client.Client, name string, want map[string]string, absent ...string) {
+	t.Helper()
+
+	stored := &v1alpha1.GPUDevice{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: name}, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	for key, value := range want {
+		if stored.Annotations[key] != value {
+			t.Fatalf("expected annotation %s=%q, got %v", key, value, stored.Annotations)
+		}
+	}
+	for _, key := range absent {
+		if _, ok := stored.Annotations[key]; ok {
+			t.Fatalf("expected annotation %s to be absent, got %v", key, stored.Annotations)
+		}
+	}
+}
+
+func TestAttributeAnnotationKeySanitizes(t *testing.T) {
+	tests := []struct {
+		attr string
+		want string
+		ok   bool
+	}{
+		{attr: "acme.rack", want: "gpu.deckhouse.io/attr.acme.rack", ok: true},
+		{attr: "acme.com/rack position", want: "gpu.deckhouse.io/attr.acme.com-rack-position", ok: true},
+		{attr: "acme.rack.", want: "gpu.deckhouse.io/attr.acme.rack", ok: true},
+		{attr: "acme." + strings.Repeat("x", 80), want: "gpu.deckhouse.io/attr.acme." + strings.Repeat("x", 53), ok: true},
+		{attr: "///", ok: false},
+		{attr: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := attributeAnnotationKey(tt.attr)
+		if ok != tt.ok || got != tt.want {
+			t.Fatalf("attributeAnnotationKey(%q) = %q, %t; want %q, %t", tt.attr, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPassthroughAnnotationsCaps(t *testing.T) {
+	attrs := map[string]string{
+		"acme.huge":     strings.Repeat("v", maxPassthroughValueBytes+1),
+		"acme.limit":    strings.Repeat("v", maxPassthroughValueBytes),
+		"acme.rack/pos": "a",
+		"acme.rack pos": "b",
+		"other.ignored": "c",
+	}
+	for i := 0; i < maxPassthroughAttributes+5; i++ {
+		attrs[fmt.Sprintf("acme.z%02d", i)] = "x"
+	}
+
+	got := passthroughAnnotations(attrs, []string{"acme."})
+	if len(got) != maxPassthroughAttributes {
+		t.Fatalf("expected %d annotations, got %d: %v", maxPassthroughAttributes, len(got), got)
+	}
+	if _, ok := got["gpu.deckhouse.io/attr.acme.huge"]; ok {
+		t.Fatalf("expected oversized value to be skipped")
+	}
+	if got["gpu.deckhouse.io/attr.acme.limit"] != attrs["acme.limit"] {
+		t.Fatalf("expected value at the size limit to be kept")
+	}
+	// "acme.rack pos" sorts first and wins the sanitized key.
+	if got["gpu.deckhouse.io/attr.acme.rack-pos"] != "b" {
+		t.Fatalf("expected first attribute to win a sanitized key collision, got %v", got)
+	}
+	if _, ok := got["gpu.deckhouse.io/attr.acme.z17"]; !ok {
+		t.Fatalf("expected attributes up to the cap to be kept, got %v", got)
+	}
+	if _, ok := got["gpu.deckhouse.io/attr.acme.z18"]; ok {
+		t.Fatalf("expected attributes past the cap to be dropped in key order, got %v", got)
+	}
+	if _, ok := got["gpu.deckhouse.io/attr.other.ignored"]; ok {
+		t.Fatalf("expected attributes without a configured prefix to be ignored")
+	}
+
+	if got := passthroughAnnotations(attrs, nil); got != nil {
+		t.Fatalf("expected no annotations without prefixes, got %v", got)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonannotations "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/inuse"
+)
+
+// approvalAnnotations are the user-set fields of a GPUDevice; they are carried over when duplicates are merged.
+var approvalAnnotations = []string{
+	commonannotations.GPUDeviceAssignment,
+	commonannotations.ClusterGPUDeviceAssignment,
+}
+
+// findByInventoryID returns the oldest device carrying the inventoryID, or nil. It guards creation against a
+// device that exists under a name the current template does not produce.
+func (s *DeviceService) findByInventoryID(ctx context.Context, inventoryID string) (*v1alpha1.GPUDevice, error) {
+	list := &v1alpha1.GPUDeviceList{}
+	if err := s.client.List(ctx, list, client.MatchingFields{invstate.DeviceInventoryIDIndexKey: inventoryID}); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	devices := make([]*v1alpha1.GPUDevice, 0, len(list.Items))
+	for i := range list.Items {
+		devices = append(devices, &list.Items[i])
+	}
+	sortOldestFirst(devices)
+	return devices[0], nil
+}
+
+// mergeDuplicates keeps the oldest of the devices sharing one inventoryID and deletes the younger ones, after
+// moving the pool assignments only they carry onto the kept device. It returns the kept device and the number of
+// API writes issued; devices another installation manages are left alone.
+func (s *DeviceService) mergeDuplicates(ctx context.Context, devices []*v1alpha1.GPUDevice) (*v1alpha1.GPUDevice, int, error) {
+	sortOldestFirst(devices)
+	keep := devices[0]
+	if len(devices) == 1 {
+		return keep, 0, nil
+	}
+	if allowed, err := s.guard.MayWrite(ctx, keep); err != nil || !allowed {
+		return keep, 0, err
+	}
+
+	duplicates := make([]*v1alpha1.GPUDevice, 0, len(devices)-1)
+	for _, dup := range devices[1:] {
+		allowed, err := s.guard.MayWrite(ctx, dup)
+		if err != nil {
+			return nil, 0, err
+		}
+		if allowed {
+			duplicates = append(duplicates, dup)
+		}
+	}
+
+	writes := 0
+	merged := keep.DeepCopy()
+	changed := false
+	for _, dup := range duplicates {
+		for _, key := range approvalAnnotations {
+			value := dup.Annotations[key]
+			if value == "" || merged.Annotations[key] != "" {
+				continue
+			}
+			if merged.Annotations == nil {
+				merged.Annotations = make(map[string]string)
+			}
+			merged.Annotations[key] = value
+			changed = true
+		}
+	}
+	if changed {
+		if err := s.client.Patch(ctx, merged, client.MergeFrom(keep)); err != nil {
+			return nil, writes, err
+		}
+		writes++
+		*keep = *merged
+	}
+
+	log := logr.FromContextOrDiscard(ctx).WithValues("inventoryID", keep.Status.InventoryID, "device", keep.Name)
+	for _, dup := range duplicates {
+		if inuse.Protected(dup) {
+			log.Info("duplicate GPUDevice is in use, deletion deferred", "duplicate", dup.Name, "state", dup.Status.State)
+			continue
+		}
+		if err := commonobject.DeleteObject(ctx, s.client, dup); err != nil {
+			return nil, writes, err
+		}
+		writes++
+		if s.recorder != nil {
+			s.recorder.WithLogging(log).Eventf(
+				keep,
+				corev1.EventTypeNormal,
+				invstate.EventDuplicateDeviceMerged,
+				"Merged duplicate GPUDevice %s with inventoryID %s into %s",
+				dup.Name,
+				keep.Status.InventoryID,
+				keep.Name,
+			)
+		}
+	}
+	return keep, writes, nil
+}
+
+// sortOldestFirst orders devices by creation time, breaking ties by name so the kept device is deterministic.
+func sortOldestFirst(devices []*v1alpha1.GPUDevice) {
+	sort.SliceStable(devices, func(i, j int) bool {
+		a, b := devices[i], devices[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+}
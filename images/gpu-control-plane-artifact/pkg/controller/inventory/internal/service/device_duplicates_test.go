@@ -0,0 +1,125 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonannotations "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func TestDeviceServiceMergesDuplicateInventoryIDs(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-dup")
+	snapshot := newTestSnapshot()
+	inventoryID := invstate.BuildInventoryID(node.Name, snapshot)
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	older := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "legacy-device-name",
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{commonannotations.ClusterGPUDeviceAssignment: "kept-pool"},
+		},
+		Status: v1alpha1.GPUDeviceStatus{NodeName: node.Name, InventoryID: inventoryID},
+	}
+	younger := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              invstate.BuildDeviceName(node.Name, snapshot),
+			CreationTimestamp: metav1.NewTime(created.Add(time.Hour)),
+			Annotations: map[string]string{
+				commonannotations.GPUDeviceAssignment:        "team-pool",
+				commonannotations.ClusterGPUDeviceAssignment: "other-pool",
+			},
+		},
+		Status: v1alpha1.GPUDeviceStatus{NodeName: node.Name, InventoryID: inventoryID},
+	}
+	cl := newTestClient(t, scheme, node, older, younger)
+	rec, recorder := newTestRecorder(10)
+	svc := NewDeviceService(cl, scheme, recorder, nil, nil, nil)
+
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeManual}
+	got, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if got.Name != older.Name {
+		t.Fatalf("expected the oldest device to be kept, got %s", got.Name)
+	}
+
+	list := &v1alpha1.GPUDeviceList{}
+	if err := cl.List(ctx, list); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != older.Name {
+		t.Fatalf("expected exactly the younger duplicate to be removed, got %+v", list.Items)
+	}
+	annotations := list.Items[0].Annotations
+	if annotations[commonannotations.GPUDeviceAssignment] != "team-pool" {
+		t.Fatalf("expected the approval of the duplicate to be merged, got %v", annotations)
+	}
+	if annotations[commonannotations.ClusterGPUDeviceAssignment] != "kept-pool" {
+		t.Fatalf("expected the approval of the kept device to win, got %v", annotations)
+	}
+
+	select {
+	case event := <-rec.Events:
+		if !strings.Contains(event, invstate.EventDuplicateDeviceMerged) || !strings.Contains(event, younger.Name) {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected %s event", invstate.EventDuplicateDeviceMerged)
+	}
+}
+
+func TestDeviceServiceReusesDeviceFoundByInventoryID(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-renamed")
+	snapshot := newTestSnapshot()
+
+	// The node index misses the device, and its name is not the one the template produces.
+	existing := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "unexpected-name"},
+		Status:     v1alpha1.GPUDeviceStatus{InventoryID: invstate.BuildInventoryID(node.Name, snapshot)},
+	}
+	cl := newTestClient(t, scheme, node, existing)
+	svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
+
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeManual}
+	got, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil)
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if got.Name != existing.Name {
+		t.Fatalf("expected the existing device to be reused, got %s", got.Name)
+	}
+	list := &v1alpha1.GPUDeviceList{}
+	if err := cl.List(ctx, list); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("expected no duplicate to be created, got %d devices", len(list.Items))
+	}
+}
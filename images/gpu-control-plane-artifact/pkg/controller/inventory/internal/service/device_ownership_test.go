@@ -0,0 +1,163 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+)
+
+func newLiveGuards(t *testing.T) (*ownership.Guard, *ownership.Guard) {
+	t.Helper()
+	leases := fake.NewClientBuilder().Build()
+	guards := make([]*ownership.Guard, 0, 2)
+	for _, self := range []ownership.Identity{
+		{Namespace: "d8-gpu", Name: "leader"},
+		{Namespace: "d8-gpu-migration", Name: "leader"},
+	} {
+		if err := ownership.NewHeartbeat(logr.Discard(), leases, leases, self, "pod").Renew(context.Background()); err != nil {
+			t.Fatalf("renew heartbeat: %v", err)
+		}
+		guards = append(guards, ownership.NewGuard(logr.Discard(), self, leases, false))
+	}
+	return guards[0], guards[1]
+}
+
+func TestDeviceServiceTwoInstancesOnlyClaimHolderWrites(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-claim")
+	snapshots := newTestSnapshots(1)
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+	guardA, guardB := newLiveGuards(t)
+
+	base := newTestClient(t, scheme, node)
+	counter := &writeCounter{}
+	svcA := NewDeviceService(counter.wrap(base), scheme, newTestRecorderLogger(32), nil, nil, guardA)
+	svcB := NewDeviceService(counter.wrap(base), scheme, newTestRecorderLogger(32), nil, nil, guardB)
+
+	devices, _, err := svcA.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if got := devices[0].Annotations[annotations.ControllerInstance]; got != guardA.Self().String() {
+		t.Fatalf("expected created device to be claimed by %s, got %q", guardA.Self(), got)
+	}
+	key := types.NamespacedName{Name: devices[0].Name}
+
+	*counter = writeCounter{}
+	snapshots[0].Product = "NVIDIA H100"
+	if _, _, err := svcB.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if counter.total() != 0 {
+		t.Fatalf("expected the second instance to write nothing, got %d writes", counter.total())
+	}
+	stored := &v1alpha1.GPUDevice{}
+	if err := base.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if stored.Status.Hardware.Product == "NVIDIA H100" {
+		t.Fatalf("expected status untouched by the second instance")
+	}
+	if _, _, err := svcB.MarkUnreachable(ctx, node, stored.CreationTimestamp.Time); err != nil || counter.total() != 0 {
+		t.Fatalf("expected MarkUnreachable to skip the foreign device, got %d writes (err %v)", counter.total(), err)
+	}
+
+	if _, _, err := svcA.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if err := base.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if stored.Status.Hardware.Product != "NVIDIA H100" {
+		t.Fatalf("expected claim holder to update the status, got product %q", stored.Status.Hardware.Product)
+	}
+}
+
+func TestDeviceServiceClaimsUnclaimedDevice(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-unclaimed")
+	snapshots := newTestSnapshots(1)
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	base := newTestClient(t, scheme, node)
+	svc := NewDeviceService(base, scheme, newTestRecorderLogger(32), nil, nil, nil)
+	devices, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if _, ok := devices[0].Annotations[annotations.ControllerInstance]; ok {
+		t.Fatalf("expected no claim without an ownership guard")
+	}
+
+	guardA, _ := newLiveGuards(t)
+	svc = NewDeviceService(base, scheme, newTestRecorderLogger(32), nil, nil, guardA)
+	if _, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	stored := &v1alpha1.GPUDevice{}
+	if err := base.Get(ctx, client.ObjectKeyFromObject(devices[0]), stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if got := stored.Annotations[annotations.ControllerInstance]; got != guardA.Self().String() {
+		t.Fatalf("expected existing device to be claimed, got %q", got)
+	}
+}
+
+func TestInventoryServiceSkipsNodeStateClaimedByAnotherInstance(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-state-claim")
+	snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+	devices := []*v1alpha1.GPUDevice{{}}
+	guardA, guardB := newLiveGuards(t)
+
+	base := newTestClient(t, scheme, node)
+	counter := &writeCounter{}
+	svcA := NewInventoryService(counter.wrap(base), scheme, nil, nil, guardA)
+	svcB := NewInventoryService(counter.wrap(base), scheme, nil, nil, guardB)
+
+	if err := svcA.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	stored := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, stored); err != nil {
+		t.Fatalf("get node state: %v", err)
+	}
+	if got := stored.Annotations[annotations.ControllerInstance]; got != guardA.Self().String() {
+		t.Fatalf("expected node state claimed by %s, got %q", guardA.Self(), got)
+	}
+
+	*counter = writeCounter{}
+	if err := svcB.Reconcile(ctx, node, invstate.NodeSnapshot{}, devices); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if counter.total() != 0 {
+		t.Fatalf("expected the second instance to write nothing, got %d writes", counter.total())
+	}
+}
@@ -40,7 +40,7 @@ func TestDeviceServiceReconcileCreateBranches(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		base := newTestClient(t, scheme, node)
 		recorder := newTestRecorderLogger(10)
-		svc := NewDeviceService(base, scheme, recorder, nil)
+		svc := NewDeviceService(base, scheme, recorder, nil, nil, nil)
 
 		device, res, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, func(d *v1alpha1.GPUDevice, _ invstate.DeviceSnapshot) {
 			d.Status.Hardware.Product = "from-detection"
@@ -64,7 +64,7 @@ func TestDeviceServiceReconcileCreateBranches(t *testing.T) {
 		base := newTestClient(t, scheme, node)
 		badScheme := runtime.NewScheme()
 
-		svc := NewDeviceService(base, badScheme, nil, nil)
+		svc := NewDeviceService(base, badScheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); err == nil {
 			t.Fatalf("expected owner reference error")
 		}
@@ -80,7 +80,7 @@ func TestDeviceServiceReconcileCreateBranches(t *testing.T) {
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -91,7 +91,7 @@ func TestDeviceServiceReconcileCreateBranches(t *testing.T) {
 		handlerErr := errors.New("handler boom")
 		svc := NewDeviceService(base, scheme, nil, []DeviceHandler{
 			namedErrorHandler{name: "handler-error", err: handlerErr},
-		})
+		}, nil, nil)
 
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); !errors.Is(err, handlerErr) {
 			t.Fatalf("expected error %v, got %v", handlerErr, err)
@@ -107,10 +107,13 @@ func TestDeviceServiceReconcileCreateBranches(t *testing.T) {
 				update: func(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
 					return apierrors.NewConflict(schema.GroupResource{Group: v1alpha1.GroupVersion.Group, Resource: "gpudevices"}, "conflict", errors.New("conflict"))
 				},
+				patch: func(context.Context, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return apierrors.NewConflict(schema.GroupResource{Group: v1alpha1.GroupVersion.Group, Resource: "gpudevices"}, "conflict", errors.New("conflict"))
+				},
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		device, res, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil)
 		if err != nil {
 			t.Fatalf("expected conflict to be handled, got %v", err)
@@ -136,9 +139,54 @@ func TestDeviceServiceReconcileCreateBranches(t *testing.T) {
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
 	})
 }
+
+func TestDeviceServiceReconcileNameTemplate(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-template")
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	existing := newTestSnapshot()
+	existing.UUID = "GPU-aabbccdd-0000"
+	added := newTestSnapshot()
+	added.Index = "1"
+	added.UUID = "GPU-11223344-0000"
+	added.PCIAddress = "00000000:66:00.0"
+
+	cl := newTestClient(t, scheme, node)
+	svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
+	first, _, err := svc.Reconcile(ctx, node, existing, nil, true, approval, nil)
+	if err != nil {
+		t.Fatalf("initial Reconcile returned error: %v", err)
+	}
+	legacyName := invstate.BuildDeviceName(node.Name, existing)
+	if first.Name != legacyName {
+		t.Fatalf("expected default template to produce %s, got %s", legacyName, first.Name)
+	}
+
+	svc.SetNameTemplate(func() string { return "{node}-gpu-{uuid8}" })
+	devices, _, err := svc.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{existing, added}, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if devices[0].Name != legacyName {
+		t.Fatalf("expected existing device to keep %s, got %s", legacyName, devices[0].Name)
+	}
+	if devices[1].Name != "node-template-gpu-11223344" {
+		t.Fatalf("expected new device to use the template, got %s", devices[1].Name)
+	}
+
+	list := &v1alpha1.GPUDeviceList{}
+	if err := cl.List(ctx, list); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("expected two devices after template change, got %d", len(list.Items))
+	}
+}
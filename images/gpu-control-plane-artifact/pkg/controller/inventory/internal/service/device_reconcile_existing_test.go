@@ -44,7 +44,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 		base := newTestClient(t, scheme, node, device)
 
 		badScheme := runtime.NewScheme()
-		svc := NewDeviceService(base, badScheme, nil, nil)
+		svc := NewDeviceService(base, badScheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); err == nil {
 			t.Fatalf("expected metadata owner reference error")
 		}
@@ -67,7 +67,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -80,7 +80,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 		handlerErr := errors.New("handler boom")
 		svc := NewDeviceService(base, scheme, nil, []DeviceHandler{
 			namedErrorHandler{name: "handler-error", err: handlerErr},
-		})
+		}, nil, nil)
 
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); !errors.Is(err, handlerErr) {
 			t.Fatalf("expected error %v, got %v", handlerErr, err)
@@ -96,7 +96,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 			Status:     v1alpha1.GPUDeviceStatus{AutoAttach: false},
 		}
 		base := newTestClient(t, scheme, node, device)
-		svc := NewDeviceService(base, scheme, nil, nil)
+		svc := NewDeviceService(base, scheme, nil, nil, nil, nil)
 
 		got, res, err := svc.Reconcile(ctx, node, snap, map[string]string{}, true, approval, func(d *v1alpha1.GPUDevice, _ invstate.DeviceSnapshot) {
 			d.Status.State = v1alpha1.GPUDeviceStateReady
@@ -129,7 +129,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		device, res, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil)
 		if err != nil {
 			t.Fatalf("expected conflict to be handled, got %v", err)
@@ -157,7 +157,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -206,7 +206,7 @@ func TestDeviceServiceReconcileExistingDeviceBranches(t *testing.T) {
 			},
 		}
 
-		svc := NewDeviceService(cl, scheme, nil, nil)
+		svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 		if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); err != nil {
 			t.Fatalf("expected no patch, got %v", err)
 		}
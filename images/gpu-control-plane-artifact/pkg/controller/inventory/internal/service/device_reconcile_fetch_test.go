@@ -37,7 +37,7 @@ func TestDeviceServiceReconcileFetchError(t *testing.T) {
 		},
 	}
 
-	svc := NewDeviceService(cl, scheme, nil, nil)
+	svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
 	if _, _, err := svc.Reconcile(context.Background(), node, newTestSnapshot(), nil, true, invstate.DeviceApprovalPolicy{}, nil); !errors.Is(err, boom) {
 		t.Fatalf("expected error %v, got %v", boom, err)
 	}
@@ -61,8 +61,12 @@ func TestDeviceServiceReconcileUpdatesHardware(t *testing.T) {
 			{Name: "1g.10gb", Count: 7},
 		},
 	}
+	snapshot.MemoryMiB = 40960
+	snapshot.ComputeMajor = 8
+	snapshot.ComputeMinor = 0
+	snapshot.Precision = []string{"bf16", "fp16"}
 
-	svc := NewDeviceService(base, scheme, nil, nil)
+	svc := NewDeviceService(base, scheme, nil, nil, nil, nil)
 	updated, _, err := svc.Reconcile(ctx, node, snapshot, map[string]string{}, true, approval, nil)
 	if err != nil {
 		t.Fatalf("Reconcile returned error: %v", err)
@@ -78,6 +82,9 @@ func TestDeviceServiceReconcileUpdatesHardware(t *testing.T) {
 	if !equality.Semantic.DeepEqual(hw.MIG, snapshot.MIG) {
 		t.Fatalf("expected MIG config updated, got %+v", hw.MIG)
 	}
+	if hw.MemoryMiB != 40960 || hw.ComputeCapability != "8.0" || !equality.Semantic.DeepEqual(hw.Precision, snapshot.Precision) {
+		t.Fatalf("expected capabilities updated, got %+v", hw)
+	}
 	if !updated.Status.AutoAttach {
 		t.Fatalf("expected autoAttach=true")
 	}
@@ -94,7 +94,7 @@ func TestDeviceServiceReconcileAutoAttachPolicies(t *testing.T) {
 				t.Fatalf("unexpected policy error: %v", err)
 			}
 
-			svc := NewDeviceService(base, scheme, nil, nil)
+			svc := NewDeviceService(base, scheme, nil, nil, nil, nil)
 			device, _, err := svc.Reconcile(ctx, node, snapshot, map[string]string{}, tt.managed, policy, nil)
 			if err != nil {
 				t.Fatalf("Reconcile returned error: %v", err)
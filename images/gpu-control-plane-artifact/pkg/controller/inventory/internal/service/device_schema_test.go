@@ -0,0 +1,178 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
+)
+
+func TestDeviceServiceReconcileNodeSchemaTooNew(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-schema")
+	snapshots := newTestSnapshots(2)
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	base := newTestClient(t, scheme, node)
+	counter := &writeCounter{}
+	svc := NewDeviceService(counter.wrap(base), scheme, newTestRecorderLogger(32), nil, nil, nil)
+
+	devices, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if got := devices[0].Annotations[annotations.LastReconciledBy]; got != version.Version() {
+		t.Fatalf("expected created device to carry the version stamp, got %q", got)
+	}
+
+	key := types.NamespacedName{Name: devices[0].Name}
+	stored := &v1alpha1.GPUDevice{}
+	if err := base.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	stored.Annotations[annotations.SchemaVersion] = strconv.Itoa(version.SchemaVersion + 1)
+	if err := base.Update(ctx, stored); err != nil {
+		t.Fatalf("update device: %v", err)
+	}
+	product := stored.Status.Hardware.Product
+
+	*counter = writeCounter{}
+	snapshots[0].Product = "NVIDIA H100"
+	if _, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if counter.statusPatches.Load() != 1 || counter.total() != 1 {
+		t.Fatalf("expected only the condition patch, got %d writes", counter.total())
+	}
+	if err := base.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if stored.Status.Hardware.Product != product {
+		t.Fatalf("expected status to stay untouched, product changed to %q", stored.Status.Hardware.Product)
+	}
+	if !meta.IsStatusConditionTrue(stored.Status.Conditions, reconciler.ConditionSchemaTooNew) {
+		t.Fatalf("expected SchemaTooNew condition, got %+v", stored.Status.Conditions)
+	}
+
+	*counter = writeCounter{}
+	if _, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if counter.total() != 0 {
+		t.Fatalf("expected no writes once the condition is surfaced, got %d", counter.total())
+	}
+}
+
+func TestDeviceServiceReconcileNodeKeepsForeignStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-foreign")
+	snapshots := newTestSnapshots(1)
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	base := newTestClient(t, scheme, node)
+	devices, _, err := NewDeviceService(base, scheme, newTestRecorderLogger(32), nil, nil, nil).ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	key := types.NamespacedName{Name: devices[0].Name}
+	stored := &v1alpha1.GPUDevice{}
+	if err := base.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	stored.Status.State = "Quarantined"
+	if err := base.Status().Update(ctx, stored); err != nil {
+		t.Fatalf("update device status: %v", err)
+	}
+
+	// The stored status as a newer controller left it: an unknown state plus a field this version does not know.
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatalf("encode device: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("decode device: %v", err)
+	}
+	doc["status"].(map[string]any)["quarantine"] = map[string]any{"reason": "XidStorm"}
+	if raw, err = json.Marshal(doc); err != nil {
+		t.Fatalf("encode device: %v", err)
+	}
+
+	var patches []client.Patch
+	hooked := &hookClient{
+		Client: base,
+		status: hookStatusWriter{
+			base: base.Status(),
+			patch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches = append(patches, patch)
+				return base.Status().Patch(ctx, obj, patch, opts...)
+			},
+		},
+	}
+	snapshots[0].Product = "NVIDIA H100"
+	if _, _, err := NewDeviceService(hooked, scheme, newTestRecorderLogger(32), nil, nil, nil).ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if len(patches) != 1 || patches[0].Type() != types.JSONPatchType {
+		t.Fatalf("expected a single JSON patch of owned fields, got %d patches", len(patches))
+	}
+	data, err := patches[0].Data(stored)
+	if err != nil {
+		t.Fatalf("patch data: %v", err)
+	}
+	ops, err := jsonpatch.DecodePatch(data)
+	if err != nil {
+		t.Fatalf("decode patch: %v", err)
+	}
+	patched, err := ops.Apply(raw)
+	if err != nil {
+		t.Fatalf("apply patch: %v", err)
+	}
+
+	var result struct {
+		Status struct {
+			State      string         `json:"state"`
+			Quarantine map[string]any `json:"quarantine"`
+			Hardware   struct {
+				Product string `json:"product"`
+			} `json:"hardware"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		t.Fatalf("decode patched device: %v", err)
+	}
+	if result.Status.Hardware.Product != "NVIDIA H100" {
+		t.Fatalf("expected owned hardware to be refreshed, got %q", result.Status.Hardware.Product)
+	}
+	if result.Status.State != "Quarantined" || result.Status.Quarantine["reason"] != "XidStorm" {
+		t.Fatalf("expected foreign state and fields to survive, got %s", patched)
+	}
+}
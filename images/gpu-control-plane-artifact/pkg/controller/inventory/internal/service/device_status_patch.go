@@ -0,0 +1,57 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/canonical"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/patch"
+)
+
+// inventoryStatusPatch builds JSON Patch operations for the status fields the inventory reconciler owns: node binding,
+// managed/autoAttach flags, hardware, driver version, provenance and device conditions. State, history and poolRef belong to the
+// pool and bootstrap controllers; the inventory only seeds the initial state, so a stale copy never reverts their writes.
+func inventoryStatusPatch(base, device *v1alpha1.GPUDevice) *patch.JSONPatch {
+	before, after := &base.Status, &device.Status
+	jp := patch.NewJSONPatch()
+	set := func(field string, old, current interface{}) {
+		if !equality.Semantic.DeepEqual(old, current) {
+			jp.Append(patch.NewJSONPatchOperation(patch.PatchAddOp, "/status/"+field, current))
+		}
+	}
+
+	set("nodeName", before.NodeName, after.NodeName)
+	set("inventoryID", before.InventoryID, after.InventoryID)
+	set("managed", before.Managed, after.Managed)
+	set("autoAttach", before.AutoAttach, after.AutoAttach)
+	set("hardware", before.Hardware, after.Hardware)
+	set("driverVersion", before.DriverVersion, after.DriverVersion)
+	set("provenance", before.Provenance, after.Provenance)
+	if !canonical.ConditionsEqual(before.Conditions, after.Conditions) {
+		conds := after.Conditions
+		if conds == nil {
+			// A nil value would be dropped from the operation and make it invalid.
+			conds = []metav1.Condition{}
+		}
+		jp.Append(patch.NewJSONPatchOperation(patch.PatchAddOp, "/status/conditions", conds))
+	}
+	if before.State == "" && after.State != "" {
+		jp.Append(patch.NewJSONPatchOperation(patch.PatchAddOp, "/status/state", after.State))
+	}
+	return jp
+}
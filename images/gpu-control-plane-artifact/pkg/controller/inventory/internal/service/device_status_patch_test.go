@@ -0,0 +1,167 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/patch"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func patchPaths(t *testing.T, jp *patch.JSONPatch) map[string]interface{} {
+	t.Helper()
+	if jp.Len() == 0 {
+		return map[string]interface{}{}
+	}
+	data, err := jp.Bytes()
+	if err != nil {
+		t.Fatalf("marshal patch: %v", err)
+	}
+	var ops []patch.JSONPatchOperation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		t.Fatalf("unmarshal patch: %v", err)
+	}
+	paths := make(map[string]interface{}, len(ops))
+	for _, op := range ops {
+		if op.Op != patch.PatchAddOp {
+			t.Fatalf("unexpected op %q for %s", op.Op, op.Path)
+		}
+		paths[op.Path] = op.Value
+	}
+	return paths
+}
+
+func TestInventoryStatusPatchOwnedFieldsOnly(t *testing.T) {
+	base := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{
+		NodeName: "node-a",
+		State:    v1alpha1.GPUDeviceStateReady,
+		Managed:  true,
+		Hardware: v1alpha1.GPUDeviceHardware{Product: "A100"},
+	}}
+	device := base.DeepCopy()
+	device.Status.Managed = false
+	device.Status.Hardware.Product = "H100"
+	device.Status.DriverVersion = "550.54.15"
+	// Pool-owned fields changed on the in-memory copy must not be sent.
+	device.Status.State = v1alpha1.GPUDeviceStateAssigned
+	device.Status.PoolRef = &v1alpha1.GPUPoolReference{Name: "pool"}
+
+	paths := patchPaths(t, inventoryStatusPatch(base, device))
+	for _, want := range []string{"/status/managed", "/status/hardware", "/status/driverVersion"} {
+		if _, ok := paths[want]; !ok {
+			t.Fatalf("expected %s in patch, got %v", want, paths)
+		}
+	}
+	if paths["/status/managed"] != false {
+		t.Fatalf("expected managed=false to be sent explicitly, got %v", paths["/status/managed"])
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected only changed owned fields, got %v", paths)
+	}
+}
+
+func TestInventoryStatusPatchSeedsStateAndClearsConditions(t *testing.T) {
+	base := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{
+		NodeName:   "node-a",
+		Conditions: []metav1.Condition{{Type: invstate.ConditionNodeUnreachable, Status: metav1.ConditionTrue}},
+	}}
+	device := base.DeepCopy()
+	device.Status.State = v1alpha1.GPUDeviceStateDiscovered
+	device.Status.Conditions = nil
+
+	paths := patchPaths(t, inventoryStatusPatch(base, device))
+	if paths["/status/state"] != string(v1alpha1.GPUDeviceStateDiscovered) {
+		t.Fatalf("expected empty state to be seeded, got %v", paths)
+	}
+	if conds, ok := paths["/status/conditions"].([]interface{}); !ok || len(conds) != 0 {
+		t.Fatalf("expected conditions to be replaced with an empty list, got %#v", paths["/status/conditions"])
+	}
+}
+
+func TestInventoryStatusPatchUnchanged(t *testing.T) {
+	base := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{NodeName: "node-a"}}
+	if jp := inventoryStatusPatch(base, base.DeepCopy()); jp.Len() != 0 {
+		t.Fatalf("expected empty patch, got %d operations", jp.Len())
+	}
+}
+
+// TestDeviceStatusWriteKeepsConcurrentPoolWrites races the inventory reconcile against the pool selection writer:
+// the pool assigns the device between the inventory read and its status write.
+func TestDeviceStatusWriteKeepsConcurrentPoolWrites(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-race")
+	snapshot := newTestSnapshot()
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+	base := newTestClient(t, scheme, node)
+
+	svc := NewDeviceService(base, scheme, newTestRecorderLogger(8), nil, nil, nil)
+	devices, _, err := svc.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{snapshot}, nil, true, approval, nil, nil)
+	if err != nil || len(devices) != 1 {
+		t.Fatalf("initial reconcile: devices=%d err=%v", len(devices), err)
+	}
+	key := types.NamespacedName{Name: devices[0].Name}
+
+	poolWrites := 0
+	racing := &hookClient{
+		Client: base,
+		status: hookStatusWriter{
+			base: base.Status(),
+			patch: func(ctx context.Context, obj client.Object, p client.Patch, opts ...client.SubResourcePatchOption) error {
+				current := &v1alpha1.GPUDevice{}
+				if err := base.Get(ctx, key, current); err != nil {
+					return err
+				}
+				orig := current.DeepCopy()
+				current.Status.PoolRef = &v1alpha1.GPUPoolReference{Name: "pool-a"}
+				current.Status.State = v1alpha1.GPUDeviceStateAssigned
+				if err := base.Status().Patch(ctx, current, client.MergeFrom(orig)); err != nil {
+					return err
+				}
+				poolWrites++
+				return base.Status().Patch(ctx, obj, p, opts...)
+			},
+		},
+	}
+	svc = NewDeviceService(racing, scheme, newTestRecorderLogger(8), nil, nil, nil)
+
+	snapshot.Product = "NVIDIA H100"
+	if _, _, err := svc.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{snapshot}, nil, false, approval, nil, nil); err != nil {
+		t.Fatalf("racing reconcile: %v", err)
+	}
+	if poolWrites != 1 {
+		t.Fatalf("expected the pool writer to run once, got %d", poolWrites)
+	}
+
+	got := &v1alpha1.GPUDevice{}
+	if err := base.Get(ctx, key, got); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if got.Status.PoolRef == nil || got.Status.PoolRef.Name != "pool-a" || got.Status.State != v1alpha1.GPUDeviceStateAssigned {
+		t.Fatalf("expected pool assignment to survive, got poolRef=%+v state=%s", got.Status.PoolRef, got.Status.State)
+	}
+	if got.Status.Hardware.Product != "NVIDIA H100" || got.Status.Managed {
+		t.Fatalf("expected inventory fields to be written, got product=%q managed=%v", got.Status.Hardware.Product, got.Status.Managed)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+const (
+	defaultStatusWriteWorkers = 4
+	// statusWriteRetryBudget is shared by all status writes of one node reconcile.
+	statusWriteRetryBudget = 3
+)
+
+// statusWrite is the computed status of one device waiting to be written.
+type statusWrite struct {
+	device *v1alpha1.GPUDevice
+	// base is the status before the reconcile; nil means the device was just created and gets a full update.
+	base *v1alpha1.GPUDevice
+}
+
+// statusPatch returns the patch sending only the inventory-owned fields. A status that was never written has no
+// /status to address, and no other controller owns a field of it yet, so it is merged as a whole.
+func (w *statusWrite) statusPatch() (client.Patch, bool, error) {
+	if equality.Semantic.DeepEqual(w.base.Status, v1alpha1.GPUDeviceStatus{}) {
+		return client.MergeFrom(w.base), true, nil
+	}
+	ops := inventoryStatusPatch(w.base, w.device)
+	if ops.Len() == 0 {
+		return nil, false, nil
+	}
+	data, err := ops.Bytes()
+	if err != nil {
+		return nil, false, err
+	}
+	return client.RawPatch(types.JSONPatchType, data), true, nil
+}
+
+func (w *statusWrite) needed() bool {
+	return w.base == nil || !equality.Semantic.DeepEqual(w.base.Status, w.device.Status)
+}
+
+type retryBudget struct {
+	left atomic.Int32
+}
+
+func newRetryBudget(n int32) *retryBudget {
+	b := &retryBudget{}
+	b.left.Store(n)
+	return b
+}
+
+func (b *retryBudget) take() bool {
+	return b.left.Add(-1) >= 0
+}
+
+func isRetryableWriteError(err error) bool {
+	return apierrors.IsConflict(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
+
+// writeStatuses issues the pending writes concurrently and returns the merged result and the number of writes.
+// Conflicts left after the retry budget is spent requeue the node instead of failing it.
+func (s *DeviceService) writeStatuses(ctx context.Context, pending []*statusWrite) (reconcile.Result, int, error) {
+	if len(pending) == 0 {
+		return reconcile.Result{}, 0, nil
+	}
+
+	workers := s.writeWorkers
+	if workers <= 0 {
+		workers = defaultStatusWriteWorkers
+	}
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+
+	budget := newRetryBudget(statusWriteRetryBudget)
+	results := make([]reconcile.Result, len(pending))
+	counts := make([]int, len(pending))
+	errs := make([]error, len(pending))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx], counts[idx], errs[idx] = s.writeStatus(ctx, pending[idx], budget)
+			}
+		}()
+	}
+	for i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	aggregate := reconcile.Result{}
+	writes := 0
+	for i := range pending {
+		aggregate = reconciler.MergeResults(aggregate, results[i])
+		writes += counts[i]
+	}
+	return aggregate, writes, errors.Join(errs...)
+}
+
+func (s *DeviceService) writeStatus(ctx context.Context, w *statusWrite, budget *retryBudget) (reconcile.Result, int, error) {
+	if err := objsize.Guard(w.device); err != nil {
+		return reconcile.Result{}, 0, err
+	}
+	writes := 0
+	backoff := retry.DefaultBackoff
+	for {
+		var err error
+		if w.base == nil {
+			err = s.client.Status().Update(ctx, w.device)
+		} else {
+			statusPatch, ok, buildErr := w.statusPatch()
+			if buildErr != nil {
+				return reconcile.Result{}, writes, buildErr
+			}
+			if !ok {
+				// Only fields owned by other controllers differ; they are not ours to write.
+				return reconcile.Result{}, writes, nil
+			}
+			err = s.client.Status().Patch(ctx, w.device, statusPatch)
+		}
+		writes++
+		if err == nil {
+			return reconcile.Result{}, writes, nil
+		}
+		if !isRetryableWriteError(err) || !budget.take() {
+			if apierrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, writes, nil
+			}
+			return reconcile.Result{}, writes, err
+		}
+		if w.base == nil && apierrors.IsConflict(err) {
+			// The status was written since the device was created. Rebasing onto the latest object makes the
+			// retry patch only the inventory-owned fields, so whatever the other writer set is kept.
+			latest := &v1alpha1.GPUDevice{}
+			if err := s.client.Get(ctx, client.ObjectKeyFromObject(w.device), latest); err != nil {
+				return reconcile.Result{}, writes, err
+			}
+			w.device.ResourceVersion = latest.ResourceVersion
+			w.base = latest
+		}
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return reconcile.Result{}, writes, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// writeCounter wraps a client and counts every write the device service issues.
+type writeCounter struct {
+	creates       atomic.Int32
+	patches       atomic.Int32
+	statusUpdates atomic.Int32
+	statusPatches atomic.Int32
+}
+
+func (c *writeCounter) total() int32 {
+	return c.creates.Load() + c.patches.Load() + c.statusUpdates.Load() + c.statusPatches.Load()
+}
+
+func (c *writeCounter) wrap(base client.Client) *hookClient {
+	return &hookClient{
+		Client: base,
+		create: func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+			c.creates.Add(1)
+			return base.Create(ctx, obj, opts...)
+		},
+		patch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			c.patches.Add(1)
+			return base.Patch(ctx, obj, patch, opts...)
+		},
+		status: hookStatusWriter{
+			base: base.Status(),
+			update: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				c.statusUpdates.Add(1)
+				return base.Status().Update(ctx, obj, opts...)
+			},
+			patch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				c.statusPatches.Add(1)
+				return base.Status().Patch(ctx, obj, patch, opts...)
+			},
+		},
+	}
+}
+
+func newTestSnapshots(n int) []invstate.DeviceSnapshot {
+	snapshots := make([]invstate.DeviceSnapshot, 0, n)
+	for i := 0; i < n; i++ {
+		snapshot := newTestSnapshot()
+		snapshot.Index = fmt.Sprintf("%d", i)
+		snapshot.UUID = fmt.Sprintf("GPU-%d", i)
+		snapshot.PCIAddress = fmt.Sprintf("00000000:%02x:00.0", 0x10+i)
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+func TestDeviceServiceReconcileNodeWriteCount(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-batch")
+	snapshots := newTestSnapshots(8)
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	counter := &writeCounter{}
+	svc := NewDeviceService(counter.wrap(newTestClient(t, scheme, node)), scheme, newTestRecorderLogger(32), nil, nil, nil)
+
+	devices, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if len(devices) != len(snapshots) {
+		t.Fatalf("expected %d devices, got %d", len(snapshots), len(devices))
+	}
+	// A new device costs exactly its create and one full status update.
+	if counter.creates.Load() != 8 || counter.statusUpdates.Load() != 8 || counter.total() != 16 {
+		t.Fatalf("unexpected writes on first reconcile: creates=%d patches=%d statusUpdates=%d statusPatches=%d",
+			counter.creates.Load(), counter.patches.Load(), counter.statusUpdates.Load(), counter.statusPatches.Load())
+	}
+
+	*counter = writeCounter{}
+	if _, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if counter.total() != 0 {
+		t.Fatalf("expected no writes for an unchanged node, got %d", counter.total())
+	}
+
+	*counter = writeCounter{}
+	snapshots[3].Product = "NVIDIA H100"
+	if _, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if counter.statusPatches.Load() != 1 || counter.total() != 1 {
+		t.Fatalf("expected a single status patch for one changed device, got %d writes", counter.total())
+	}
+}
+
+func TestDeviceServiceReconcileNodeBoundsConcurrency(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-workers")
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+	base := newTestClient(t, scheme, node)
+
+	var (
+		mu       sync.Mutex
+		inflight int
+		peak     int
+	)
+	cl := &hookClient{Client: base, status: hookStatusWriter{
+		base: base.Status(),
+		update: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			mu.Lock()
+			inflight++
+			if inflight > peak {
+				peak = inflight
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			inflight--
+			mu.Unlock()
+			return base.Status().Update(ctx, obj, opts...)
+		},
+	}}
+	svc := NewDeviceService(cl, scheme, newTestRecorderLogger(32), nil, nil, nil)
+	svc.SetStatusWriteWorkers(2)
+
+	if _, _, err := svc.ReconcileNode(ctx, node, newTestSnapshots(8), nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if peak > 2 {
+		t.Fatalf("expected at most 2 concurrent status writes, got %d", peak)
+	}
+}
+
+func TestDeviceServiceReconcileNodeRetryBudget(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-retry")
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+	gr := schema.GroupResource{Group: "gpu.deckhouse.io", Resource: "gpudevices"}
+
+	t.Run("transient errors are retried", func(t *testing.T) {
+		base := newTestClient(t, scheme, node)
+		var failures atomic.Int32
+		cl := &hookClient{Client: base, status: hookStatusWriter{
+			base: base.Status(),
+			update: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if failures.Add(1) <= 2 {
+					return apierrors.NewServiceUnavailable("busy")
+				}
+				return base.Status().Update(ctx, obj, opts...)
+			},
+		}}
+		svc := NewDeviceService(cl, scheme, newTestRecorderLogger(32), nil, nil, nil)
+
+		_, res, err := svc.ReconcileNode(ctx, node, newTestSnapshots(2), nil, true, approval, nil, nil)
+		if err != nil {
+			t.Fatalf("ReconcileNode returned error: %v", err)
+		}
+		if res.Requeue {
+			t.Fatalf("expected no requeue, got %+v", res)
+		}
+	})
+
+	t.Run("exhausted budget requeues conflicts", func(t *testing.T) {
+		base := newTestClient(t, scheme, node)
+		var attempts atomic.Int32
+		cl := &hookClient{Client: base, status: hookStatusWriter{
+			base: base.Status(),
+			update: func(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+				attempts.Add(1)
+				return apierrors.NewConflict(gr, "device", fmt.Errorf("stale"))
+			},
+			patch: func(context.Context, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				attempts.Add(1)
+				return apierrors.NewConflict(gr, "device", fmt.Errorf("stale"))
+			},
+		}}
+		svc := NewDeviceService(cl, scheme, newTestRecorderLogger(32), nil, nil, nil)
+
+		_, res, err := svc.ReconcileNode(ctx, node, newTestSnapshots(4), nil, true, approval, nil, nil)
+		if err != nil {
+			t.Fatalf("ReconcileNode returned error: %v", err)
+		}
+		if !res.Requeue {
+			t.Fatalf("expected requeue after conflicts, got %+v", res)
+		}
+		if got := attempts.Load(); got != 4+statusWriteRetryBudget {
+			t.Fatalf("expected %d status attempts, got %d", 4+statusWriteRetryBudget, got)
+		}
+	})
+
+	t.Run("conflicting create keeps the status written meanwhile", func(t *testing.T) {
+		base := newTestClient(t, scheme, node)
+		var updates, patches atomic.Int32
+		cl := &hookClient{Client: base, status: hookStatusWriter{
+			base: base.Status(),
+			update: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if updates.Add(1) > 1 {
+					return base.Status().Update(ctx, obj, opts...)
+				}
+				// Another controller claims the device between its create and the first status write.
+				other := &v1alpha1.GPUDevice{}
+				if err := base.Get(ctx, client.ObjectKeyFromObject(obj), other); err != nil {
+					return err
+				}
+				other.Status.PoolRef = &v1alpha1.GPUPoolReference{Name: "train", Namespace: "team"}
+				if err := base.Status().Update(ctx, other); err != nil {
+					return err
+				}
+				return apierrors.NewConflict(gr, obj.GetName(), fmt.Errorf("stale"))
+			},
+			patch: func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches.Add(1)
+				return base.Status().Patch(ctx, obj, patch, opts...)
+			},
+		}}
+		svc := NewDeviceService(cl, scheme, newTestRecorderLogger(32), nil, nil, nil)
+
+		_, res, err := svc.ReconcileNode(ctx, node, newTestSnapshots(1), nil, true, approval, nil, nil)
+		if err != nil || res.Requeue {
+			t.Fatalf("expected the conflict to be resolved by a retry, got %+v, %v", res, err)
+		}
+		if updates.Load() != 1 || patches.Load() != 1 {
+			t.Fatalf("expected the retry to patch the latest object, got %d updates and %d patches", updates.Load(), patches.Load())
+		}
+
+		stored := &v1alpha1.GPUDeviceList{}
+		if err := base.List(ctx, stored); err != nil || len(stored.Items) != 1 {
+			t.Fatalf("list devices: %d, %v", len(stored.Items), err)
+		}
+		status := stored.Items[0].Status
+		if status.PoolRef == nil || status.PoolRef.Name != "train" {
+			t.Fatalf("expected the concurrent pool claim to survive, got %+v", status.PoolRef)
+		}
+		if status.NodeName != node.Name {
+			t.Fatalf("expected inventory fields to be written, got node %q", status.NodeName)
+		}
+	})
+
+	t.Run("non-retryable errors are returned", func(t *testing.T) {
+		base := newTestClient(t, scheme, node)
+		cl := &hookClient{Client: base, status: hookStatusWriter{
+			base: base.Status(),
+			update: func(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+				return apierrors.NewForbidden(gr, "device", fmt.Errorf("denied"))
+			},
+		}}
+		svc := NewDeviceService(cl, scheme, newTestRecorderLogger(32), nil, nil, nil)
+
+		if _, _, err := svc.ReconcileNode(ctx, node, newTestSnapshots(2), nil, true, approval, nil, nil); !apierrors.IsForbidden(err) {
+			t.Fatalf("expected forbidden error, got %v", err)
+		}
+	})
+}
+
+// reorderingHandler rebuilds its conditions on every call, alternating their order and stamping a fresh
+// transition time, the way handlers assembling conditions from a map do.
+type reorderingHandler struct {
+	calls atomic.Int32
+}
+
+func (h *reorderingHandler) Name() string { return "reordering" }
+
+func (h *reorderingHandler) HandleDevice(_ context.Context, device *v1alpha1.GPUDevice) (reconcile.Result, error) {
+	call := h.calls.Add(1)
+	stamp := metav1.NewTime(time.Unix(1700000000+int64(call), 0))
+	conds := []metav1.Condition{
+		{Type: "Probed", Status: metav1.ConditionTrue, Reason: "Probed", LastTransitionTime: stamp},
+		{Type: "Healthy", Status: metav1.ConditionTrue, Reason: "Healthy", LastTransitionTime: stamp},
+	}
+	if call%2 == 0 {
+		conds[0], conds[1] = conds[1], conds[0]
+	}
+	for _, cond := range device.Status.Conditions {
+		if cond.Type != "Probed" && cond.Type != "Healthy" {
+			conds = append(conds, cond)
+		}
+	}
+	device.Status.Conditions = conds
+	return reconcile.Result{}, nil
+}
+
+func TestDeviceServiceReconcileNodeIgnoresSetOrder(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-order")
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	snapshots := newTestSnapshots(2)
+	for i := range snapshots {
+		snapshots[i].Precision = []string{"tf32", "fp16", "bf16"}
+		snapshots[i].MIG.ProfilesSupported = []string{"7g.80gb", "1g.10gb", "3g.40gb"}
+		snapshots[i].MIG.Types = []v1alpha1.GPUMIGTypeCapacity{{Name: "7g.80gb", Count: 1}, {Name: "1g.10gb", Count: 7}}
+	}
+
+	base := newTestClient(t, scheme, node)
+	counter := &writeCounter{}
+	svc := NewDeviceService(counter.wrap(base), scheme, newTestRecorderLogger(32), []DeviceHandler{&reorderingHandler{}}, nil, nil)
+
+	if _, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil); err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+
+	stored := &v1alpha1.GPUDeviceList{}
+	if err := base.List(ctx, stored); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	for _, device := range stored.Items {
+		if got := device.Status.Hardware.Precision; len(got) != 3 || got[0] != "bf16" || got[2] != "tf32" {
+			t.Fatalf("expected precision to be stored sorted, got %v", got)
+		}
+		if got := device.Status.Hardware.MIG.Types; len(got) != 2 || got[0].Name != "1g.10gb" {
+			t.Fatalf("expected MIG types to be stored sorted, got %+v", got)
+		}
+	}
+
+	// The same data in a different order must not produce a write.
+	for i := range snapshots {
+		slices.Reverse(snapshots[i].Precision)
+		slices.Reverse(snapshots[i].MIG.ProfilesSupported)
+		slices.Reverse(snapshots[i].MIG.Types)
+	}
+	slices.Reverse(snapshots)
+	*counter = writeCounter{}
+	devices, _, err := svc.ReconcileNode(ctx, node, snapshots, nil, true, approval, nil, nil)
+	if err != nil {
+		t.Fatalf("ReconcileNode returned error: %v", err)
+	}
+	if counter.total() != 0 {
+		t.Fatalf("expected no writes for reordered data, got creates=%d patches=%d statusUpdates=%d statusPatches=%d",
+			counter.creates.Load(), counter.patches.Load(), counter.statusUpdates.Load(), counter.statusPatches.Load())
+	}
+	if len(devices) != 2 || devices[0].Status.InventoryID > devices[1].Status.InventoryID {
+		t.Fatalf("expected devices ordered by inventoryID, got %s, %s", devices[0].Status.InventoryID, devices[1].Status.InventoryID)
+	}
+}
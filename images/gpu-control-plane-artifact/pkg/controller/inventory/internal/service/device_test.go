@@ -19,18 +19,19 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	promdto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/contracts/testkit"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 )
@@ -77,10 +78,22 @@ func labelsMatch(metric *promdto.Metric, expected map[string]string) bool {
 	return true
 }
 
-func findMetric(t *testing.T, name string, labels map[string]string) (*promdto.Metric, bool) {
+// newTestMetrics returns inventory metrics on a private registry, so tests neither share nor reset series.
+func newTestMetrics(t *testing.T) (*invmetrics.Metrics, prometheus.Gatherer) {
 	t.Helper()
 
-	families, err := crmetrics.Registry.Gather()
+	registry := prometheus.NewRegistry()
+	metrics, err := invmetrics.New(registry)
+	if err != nil {
+		t.Fatalf("register inventory metrics: %v", err)
+	}
+	return metrics, registry
+}
+
+func findMetric(t *testing.T, gatherer prometheus.Gatherer, name string, labels map[string]string) (*promdto.Metric, bool) {
+	t.Helper()
+
+	families, err := gatherer.Gather()
 	if err != nil {
 		t.Fatalf("gather metrics: %v", err)
 	}
@@ -102,45 +115,24 @@ func findMetric(t *testing.T, name string, labels map[string]string) (*promdto.M
 
 func newTestScheme(t *testing.T) *runtime.Scheme {
 	t.Helper()
-
-	scheme := runtime.NewScheme()
-	if err := v1alpha1.AddToScheme(scheme); err != nil {
-		t.Fatalf("add gpu scheme: %v", err)
-	}
-	if err := corev1.AddToScheme(scheme); err != nil {
-		t.Fatalf("add core scheme: %v", err)
-	}
-	return scheme
+	return testkit.NewScheme(t)
 }
 
 func newTestClient(t *testing.T, scheme *runtime.Scheme, objs ...client.Object) client.Client {
 	t.Helper()
-
-	builder := clientfake.NewClientBuilder().
-		WithScheme(scheme).
-		WithObjects(objs...).
-		WithStatusSubresource(&v1alpha1.GPUDevice{}, &v1alpha1.GPUNodeState{})
-
-	builder = builder.WithIndex(&v1alpha1.GPUDevice{}, invstate.DeviceNodeIndexKey, func(obj client.Object) []string {
-		device, ok := obj.(*v1alpha1.GPUDevice)
-		if !ok || device.Status.NodeName == "" {
-			return nil
-		}
-		return []string{device.Status.NodeName}
-	})
-
-	return builder.Build()
+	return testkit.NewClientBuilder(scheme).WithObjects(objs...).Build()
 }
 
 func TestDeviceServiceInvokeHandlersIncrementsErrorMetric(t *testing.T) {
 	handler := namedErrorHandler{name: "error-" + t.Name(), err: errors.New("boom")}
 
-	svc := &DeviceService{handlers: []DeviceHandler{handler}}
+	metrics, gatherer := newTestMetrics(t)
+	svc := &DeviceService{handlers: []DeviceHandler{handler}, metrics: metrics}
 	if _, err := svc.invokeHandlers(context.Background(), &v1alpha1.GPUDevice{}); err == nil {
 		t.Fatalf("expected handler error")
 	}
 
-	metric, ok := findMetric(t, invmetrics.InventoryHandlerErrorsTotal, map[string]string{"handler": handler.name})
+	metric, ok := findMetric(t, gatherer, invmetrics.InventoryHandlerErrorsTotal, map[string]string{"handler": handler.name})
 	if !ok || metric.Counter == nil || metric.Counter.GetValue() != 1 {
 		value := 0.0
 		if ok && metric.Counter != nil {
@@ -167,7 +159,7 @@ func TestEnsureDeviceMetadataUpdatesLabelsAndOwner(t *testing.T) {
 	cl := newTestClient(t, scheme, node, device)
 	svc := &DeviceService{client: cl, scheme: scheme}
 
-	changed, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "1"})
+	changed, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "1"}, nil)
 	if err != nil {
 		t.Fatalf("ensureDeviceMetadata returned error: %v", err)
 	}
@@ -211,7 +203,7 @@ func TestEnsureDeviceMetadataNoChanges(t *testing.T) {
 		scheme: scheme,
 	}
 
-	changed, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "0"})
+	changed, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "0"}, nil)
 	if err != nil {
 		t.Fatalf("ensureDeviceMetadata returned error: %v", err)
 	}
@@ -239,7 +231,7 @@ func TestEnsureDeviceMetadataPatchError(t *testing.T) {
 	}
 
 	svc := &DeviceService{client: cl, scheme: scheme}
-	_, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "0"})
+	_, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "0"}, nil)
 	if err == nil {
 		t.Fatalf("expected patch error from ensureDeviceMetadata")
 	}
@@ -255,7 +247,7 @@ func TestEnsureDeviceMetadataOwnerReferenceError(t *testing.T) {
 		scheme: scheme,
 	}
 
-	changed, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "0"})
+	changed, err := svc.ensureDeviceMetadata(context.Background(), node, device, invstate.DeviceSnapshot{Index: "0"}, nil)
 	if err == nil {
 		t.Fatal("expected owner reference error")
 	}
@@ -263,3 +255,22 @@ func TestEnsureDeviceMetadataOwnerReferenceError(t *testing.T) {
 		t.Fatal("expected changed=false on error")
 	}
 }
+
+func TestApplyMIGConflictCondition(t *testing.T) {
+	device := &v1alpha1.GPUDevice{}
+	applyMIGConflictCondition(device, invstate.DeviceSnapshot{})
+	if len(device.Status.Conditions) != 0 {
+		t.Fatalf("expected no condition without a conflict, got %+v", device.Status.Conditions)
+	}
+
+	applyMIGConflictCondition(device, invstate.DeviceSnapshot{MIGConflict: "instance reports MIG strategy Mixed, node labels report Single"})
+	cond := meta.FindStatusCondition(device.Status.Conditions, invstate.ConditionMIGDataConflict)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != invstate.ReasonMIGStrategyMismatch || cond.Message == "" {
+		t.Fatalf("expected MIGDataConflict=True, got %+v", device.Status.Conditions)
+	}
+
+	applyMIGConflictCondition(device, invstate.DeviceSnapshot{})
+	if meta.FindStatusCondition(device.Status.Conditions, invstate.ConditionMIGDataConflict) != nil {
+		t.Fatalf("expected the condition to be removed once the sources agree, got %+v", device.Status.Conditions)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+// MarkUnreachable sets the NodeUnreachable condition on every device of the node and returns how many
+// devices changed. Device states and hardware stay as last observed; the condition is removed by the
+// next regular reconcile once the node is Ready again.
+func (s *DeviceService) MarkUnreachable(ctx context.Context, node *corev1.Node, since time.Time) (int, reconcile.Result, error) {
+	list := &v1alpha1.GPUDeviceList{}
+	if err := s.client.List(ctx, list, client.MatchingFields{invstate.DeviceNodeIndexKey: node.Name}); err != nil {
+		return 0, reconcile.Result{}, err
+	}
+
+	message := fmt.Sprintf("node %s has been NotReady since %s", node.Name, since.UTC().Format(time.RFC3339))
+	pending := make([]*statusWrite, 0, len(list.Items))
+	for i := range list.Items {
+		device := &list.Items[i]
+		if _, tooNew := reconciler.SchemaTooNew(device); tooNew {
+			continue
+		}
+		if allowed, err := s.guard.MayWrite(ctx, device); err != nil {
+			return 0, reconcile.Result{}, err
+		} else if !allowed {
+			continue
+		}
+		write := &statusWrite{device: device, base: device.DeepCopy()}
+		conditions.SetCondition(
+			conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionNodeUnreachable)).
+				Status(metav1.ConditionTrue).
+				Reason(conditions.CommonReason(invstate.ReasonNodeNotReady)).
+				Message(message).
+				Generation(device.Generation),
+			&device.Status.Conditions,
+		)
+		if write.needed() {
+			pending = append(pending, write)
+		}
+	}
+
+	result, _, err := s.writeStatuses(ctx, pending)
+	if err != nil {
+		return 0, result, err
+	}
+	return len(pending), result, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func TestDeviceServiceMarkUnreachableAndRecover(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-unreachable")
+	snapshot := newTestSnapshot()
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+
+	cl := newTestClient(t, scheme, node)
+	svc := NewDeviceService(cl, scheme, nil, nil, nil, nil)
+	device, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil)
+	if err != nil {
+		t.Fatalf("initial reconcile: %v", err)
+	}
+	key := types.NamespacedName{Name: device.Name}
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	marked, _, err := svc.MarkUnreachable(ctx, node, since)
+	if err != nil {
+		t.Fatalf("MarkUnreachable: %v", err)
+	}
+	if marked != 1 {
+		t.Fatalf("expected one device to be marked, got %d", marked)
+	}
+	stored := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	cond := apimeta.FindStatusCondition(stored.Status.Conditions, invstate.ConditionNodeUnreachable)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != invstate.ReasonNodeNotReady {
+		t.Fatalf("expected NodeUnreachable condition, got %+v", stored.Status.Conditions)
+	}
+	if stored.Status.State != device.Status.State || stored.Status.Hardware.UUID != snapshot.UUID {
+		t.Fatalf("expected last known state to be kept, got %+v", stored.Status)
+	}
+
+	// Marking again is a no-op.
+	if marked, _, err = svc.MarkUnreachable(ctx, node, since); err != nil || marked != 0 {
+		t.Fatalf("expected repeated marking to be a no-op, got %d (%v)", marked, err)
+	}
+
+	// The regular reconcile after the node is back removes the condition.
+	if _, _, err := svc.Reconcile(ctx, node, snapshot, nil, true, approval, nil); err != nil {
+		t.Fatalf("recovery reconcile: %v", err)
+	}
+	if err := cl.Get(ctx, key, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if apimeta.FindStatusCondition(stored.Status.Conditions, invstate.ConditionNodeUnreachable) != nil {
+		t.Fatalf("expected NodeUnreachable to be cleared, got %+v", stored.Status.Conditions)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// validationGateRequeue is how often a device held back by the validation gate is re-checked; GPUNodeState
+// condition updates do not trigger the inventory reconciler.
+const validationGateRequeue = 30 * time.Second
+
+// nodeValidation reads the validator verdict of the node, only when the approval policy asks for it.
+func (s *DeviceService) nodeValidation(ctx context.Context, nodeName string, approval invstate.DeviceApprovalPolicy) (invstate.NodeValidation, error) {
+	if !approval.RequireValidation {
+		return invstate.NodeValidation{}, nil
+	}
+	state, err := commonobject.FetchObject(ctx, client.ObjectKey{Name: nodeName}, s.client, &v1alpha1.GPUNodeState{})
+	if err != nil {
+		return invstate.NodeValidation{}, err
+	}
+	return invstate.NodeValidationFromState(state), nil
+}
+
+// applyValidationGate keeps AutoAttach off until the node passed a validator run. The WaitingForValidation condition
+// stays on the device while the gate is enabled: its LastTransitionTime holds the run that opened the gate, so a driver
+// change re-arms it until a newer run lands. previousDriver is the driver version the device reported before this pass.
+func applyValidationGate(
+	device *v1alpha1.GPUDevice,
+	previousDriver string,
+	approval invstate.DeviceApprovalPolicy,
+	validation invstate.NodeValidation,
+) reconcile.Result {
+	if !approval.RequireValidation || !device.Status.AutoAttach {
+		meta.RemoveStatusCondition(&device.Status.Conditions, invstate.ConditionWaitingForValidation)
+		return reconcile.Result{}
+	}
+
+	current := meta.FindStatusCondition(device.Status.Conditions, invstate.ConditionWaitingForValidation)
+	driverChanged := previousDriver != "" && previousDriver != device.Status.DriverVersion
+	var baseline time.Time
+	switch {
+	case current == nil:
+	case current.Status == metav1.ConditionFalse && driverChanged:
+		baseline = current.LastTransitionTime.Time
+	case current.Status == metav1.ConditionTrue && current.Reason == invstate.ReasonDriverChanged:
+		baseline = current.LastTransitionTime.Time
+	}
+
+	switch {
+	case !validation.Passed:
+		since := time.Now()
+		if current != nil && current.Status == metav1.ConditionTrue && current.Reason == invstate.ReasonValidationPending {
+			since = current.LastTransitionTime.Time
+		}
+		device.Status.AutoAttach = false
+		setValidationCondition(device, metav1.ConditionTrue, invstate.ReasonValidationPending,
+			"waiting for the node to pass driver and toolkit validation", since)
+		return reconcile.Result{RequeueAfter: validationGateRequeue}
+	case !baseline.IsZero() && !validation.Since.After(baseline):
+		device.Status.AutoAttach = false
+		setValidationCondition(device, metav1.ConditionTrue, invstate.ReasonDriverChanged,
+			fmt.Sprintf("driver changed to %q, waiting for the node to be validated again", device.Status.DriverVersion), baseline)
+		return reconcile.Result{RequeueAfter: validationGateRequeue}
+	default:
+		setValidationCondition(device, metav1.ConditionFalse, invstate.ReasonValidationPassed,
+			"node passed driver and toolkit validation", validation.Since)
+		return reconcile.Result{}
+	}
+}
+
+// setValidationCondition writes the condition with the given transition time as is, since the gate keys its
+// freshness check on it.
+func setValidationCondition(device *v1alpha1.GPUDevice, status metav1.ConditionStatus, reason, message string, at time.Time) {
+	condition := metav1.Condition{
+		Type:               invstate.ConditionWaitingForValidation,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: device.Generation,
+		LastTransitionTime: metav1.NewTime(at),
+	}
+	if existing := meta.FindStatusCondition(device.Status.Conditions, condition.Type); existing != nil {
+		*existing = condition
+		return
+	}
+	device.Status.Conditions = append(device.Status.Conditions, condition)
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func gatedPolicy(t *testing.T, require bool) invstate.DeviceApprovalPolicy {
+	t.Helper()
+	policy, err := invstate.NewDeviceApprovalPolicy(moduleconfig.DeviceApprovalSettings{
+		Mode:              moduleconfig.DeviceApprovalModeAutomatic,
+		RequireValidation: require,
+	})
+	if err != nil {
+		t.Fatalf("unexpected policy error: %v", err)
+	}
+	return policy
+}
+
+func withDriver(version string) func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot) {
+	return func(device *v1alpha1.GPUDevice, _ invstate.DeviceSnapshot) {
+		device.Status.DriverVersion = version
+	}
+}
+
+// setNodeValidation records the validator verdict on the GPUNodeState the way the bootstrap controller does.
+func setNodeValidation(t *testing.T, c client.Client, nodeName string, status metav1.ConditionStatus, at time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	state := &v1alpha1.GPUNodeState{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, state); err != nil {
+		state = &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+		if err := c.Create(ctx, state); err != nil {
+			t.Fatalf("create node state: %v", err)
+		}
+	}
+	for _, conditionType := range []string{invstate.NodeConditionDriverReady, invstate.NodeConditionToolkitReady} {
+		meta.RemoveStatusCondition(&state.Status.Conditions, conditionType)
+		state.Status.Conditions = append(state.Status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             "Validator",
+			LastTransitionTime: metav1.NewTime(at),
+		})
+	}
+	if err := c.Status().Update(ctx, state); err != nil {
+		t.Fatalf("update node state status: %v", err)
+	}
+}
+
+func expectGate(t *testing.T, device *v1alpha1.GPUDevice, result reconcile.Result, autoAttach bool, reason string) {
+	t.Helper()
+	if device.Status.AutoAttach != autoAttach {
+		t.Fatalf("autoAttach mismatch: want %v got %v", autoAttach, device.Status.AutoAttach)
+	}
+	cond := meta.FindStatusCondition(device.Status.Conditions, invstate.ConditionWaitingForValidation)
+	if reason == "" {
+		if cond != nil {
+			t.Fatalf("unexpected WaitingForValidation condition: %+v", cond)
+		}
+		return
+	}
+	if cond == nil || cond.Reason != reason {
+		t.Fatalf("expected WaitingForValidation reason %s, got %+v", reason, cond)
+	}
+	waiting := cond.Status == metav1.ConditionTrue
+	if waiting == autoAttach {
+		t.Fatalf("condition status %s disagrees with autoAttach=%v", cond.Status, autoAttach)
+	}
+	if waiting && result.RequeueAfter != validationGateRequeue {
+		t.Fatalf("expected a requeue while waiting for validation, got %+v", result)
+	}
+}
+
+func TestValidationGateDisabledKeepsAutoAttach(t *testing.T) {
+	scheme := newTestScheme(t)
+	node := newTestNode("node-gate-off")
+	svc := NewDeviceService(newTestClient(t, scheme, node), scheme, nil, nil, nil, nil)
+
+	device, result, err := svc.Reconcile(context.Background(), node, newTestSnapshot(), nil, true, gatedPolicy(t, false), withDriver("550.54"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, true, "")
+}
+
+func TestValidationGateHoldsAutoAttachUntilValidationLands(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-gate-on")
+	c := newTestClient(t, scheme, node)
+	svc := NewDeviceService(c, scheme, nil, nil, nil, nil)
+	policy := gatedPolicy(t, true)
+
+	device, result, err := svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("550.54"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, false, invstate.ReasonValidationPending)
+
+	setNodeValidation(t, c, node.Name, metav1.ConditionFalse, time.Now().Add(-time.Minute))
+	device, result, err = svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("550.54"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, false, invstate.ReasonValidationPending)
+
+	setNodeValidation(t, c, node.Name, metav1.ConditionTrue, time.Now())
+	device, result, err = svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("550.54"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, true, invstate.ReasonValidationPassed)
+
+	stored := &v1alpha1.GPUDevice{}
+	if err := c.Get(ctx, client.ObjectKey{Name: device.Name}, stored); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	expectGate(t, stored, reconcile.Result{}, true, invstate.ReasonValidationPassed)
+}
+
+func TestValidationGateReArmsAfterDriverUpgrade(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-gate-upgrade")
+	c := newTestClient(t, scheme, node)
+	svc := NewDeviceService(c, scheme, nil, nil, nil, nil)
+	policy := gatedPolicy(t, true)
+	validated := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	setNodeValidation(t, c, node.Name, metav1.ConditionTrue, validated)
+	device, result, err := svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("550.54"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, true, invstate.ReasonValidationPassed)
+
+	// The new driver is reported while the node still carries the verdict of the old one.
+	device, result, err = svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("560.28"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, false, invstate.ReasonDriverChanged)
+
+	// The gate stays armed on later passes with the same driver and the stale verdict.
+	device, result, err = svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("560.28"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, false, invstate.ReasonDriverChanged)
+
+	// DriverUpgrading episode: the validator restarts and reports not ready first.
+	setNodeValidation(t, c, node.Name, metav1.ConditionFalse, validated.Add(time.Minute))
+	device, result, err = svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("560.28"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, false, invstate.ReasonValidationPending)
+
+	setNodeValidation(t, c, node.Name, metav1.ConditionTrue, validated.Add(2*time.Minute))
+	device, result, err = svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("560.28"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, true, invstate.ReasonValidationPassed)
+}
+
+func TestValidationGateAcceptsRunNewerThanDriverChange(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-gate-revalidated")
+	c := newTestClient(t, scheme, node)
+	svc := NewDeviceService(c, scheme, nil, nil, nil, nil)
+	policy := gatedPolicy(t, true)
+	validated := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	setNodeValidation(t, c, node.Name, metav1.ConditionTrue, validated)
+	if _, _, err := svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("550.54")); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+
+	// The validator already re-ran on the new driver before the inventory observed the version change.
+	setNodeValidation(t, c, node.Name, metav1.ConditionTrue, validated.Add(time.Minute))
+	device, result, err := svc.Reconcile(ctx, node, newTestSnapshot(), nil, true, policy, withDriver("560.28"))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	expectGate(t, device, result, true, invstate.ReasonValidationPassed)
+}
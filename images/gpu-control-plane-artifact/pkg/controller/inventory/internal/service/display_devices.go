@@ -0,0 +1,112 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invpci "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/pci"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// vgaClassPrefix is the PCI class of VGA-compatible controllers: BMC chips and display-class GPUs.
+const vgaClassPrefix = "0300"
+
+// ClassifyDisplayDevices splits the snapshot into compute GPUs and display-only adapters. An adapter is
+// display-only when the driver reports it with an active display mode but no compute capability, or when
+// it is a VGA controller the driver does not know at all (an ASPEED BMC chip, a GPU without a loaded driver).
+//
+// Without an answer from gfd-extender the driver view is unknown, so a node that was reconciled before
+// (previous is its GPUNodeState, nil for a new node) keeps the classification it already has; only a new
+// node falls back to the PCI class alone. The split is recomputed on every reconcile, so an adapter that
+// later reports compute capability moves to the compute list and gets its GPUDevice then.
+func ClassifyDisplayDevices(devices []invstate.DeviceSnapshot, detections NodeDetection, previous *v1alpha1.GPUNodeState) ([]invstate.DeviceSnapshot, []v1alpha1.GPUNodeDisplayDevice) {
+	var persisted []v1alpha1.GPUNodeDisplayDevice
+	known := previous != nil && previous.Status.LastReconcileTime != nil
+	if known {
+		persisted = previous.Status.DisplayDevices
+	}
+
+	compute := make([]invstate.DeviceSnapshot, 0, len(devices))
+	var display []v1alpha1.GPUNodeDisplayDevice
+	for _, dev := range devices {
+		var displayOnly bool
+		displayMode := dev.DisplayMode
+		switch {
+		case detections.reported:
+			if entry, ok := detections.find(dev); ok {
+				displayOnly = entry.ComputeMajor == 0 && displayActive(entry.DisplayMode)
+				if entry.DisplayMode != "" {
+					displayMode = entry.DisplayMode
+				}
+			} else if _, failed := detections.initFailed[dev.Index]; !failed {
+				displayOnly = vgaWithoutDriver(dev)
+			}
+		case known:
+			displayOnly = persistedDisplayDevice(persisted, dev)
+		default:
+			displayOnly = vgaWithoutDriver(dev)
+		}
+		if !displayOnly {
+			compute = append(compute, dev)
+			continue
+		}
+		display = append(display, v1alpha1.GPUNodeDisplayDevice{
+			Index:   dev.Index,
+			Product: dev.Product,
+			PCI: v1alpha1.PCIAddress{
+				Vendor:  dev.Vendor,
+				Device:  dev.Device,
+				Class:   dev.Class,
+				Address: invpci.CanonicalizePCIAddress(dev.PCIAddress),
+			},
+			DisplayMode: displayMode,
+		})
+	}
+	return compute, display
+}
+
+// vgaWithoutDriver matches a VGA controller that carries no driver-assigned UUID.
+func vgaWithoutDriver(dev invstate.DeviceSnapshot) bool {
+	return dev.UUID == "" && strings.HasPrefix(dev.Class, vgaClassPrefix)
+}
+
+// displayActive treats an empty mode and the NVML values for a disabled or unsupported display as inactive.
+func displayActive(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "disabled", "n/a", "not supported":
+		return false
+	}
+	return true
+}
+
+// persistedDisplayDevice reports whether the device was display-only in the previous reconcile. The PCI
+// address identifies it when both sides know it; the index is the fallback.
+func persistedDisplayDevice(persisted []v1alpha1.GPUNodeDisplayDevice, dev invstate.DeviceSnapshot) bool {
+	addr := invpci.CanonicalizePCIAddress(dev.PCIAddress)
+	for _, item := range persisted {
+		if addr != "" && item.PCI.Address != "" {
+			if item.PCI.Address == addr {
+				return true
+			}
+			continue
+		}
+		if item.Index == dev.Index {
+			return true
+		}
+	}
+	return false
+}
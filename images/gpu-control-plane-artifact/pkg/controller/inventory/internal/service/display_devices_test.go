@@ -0,0 +1,150 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strconv"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func reportedDetections(entries ...detection.Device) NodeDetection {
+	result := NodeDetection{
+		byUUID:   map[string]detection.Device{},
+		byIndex:  map[string]detection.Device{},
+		reported: true,
+	}
+	for _, entry := range entries {
+		if entry.UUID != "" {
+			result.byUUID[entry.UUID] = entry
+		}
+		result.byIndex[strconv.Itoa(entry.Index)] = entry
+	}
+	return result
+}
+
+func indices(devices []invstate.DeviceSnapshot) []string {
+	out := make([]string, 0, len(devices))
+	for _, dev := range devices {
+		out = append(out, dev.Index)
+	}
+	return out
+}
+
+func TestClassifyDisplayDevicesASPEEDAdapter(t *testing.T) {
+	devices := []invstate.DeviceSnapshot{
+		{Index: "0", Vendor: "10de", Device: "2330", Class: "0302", UUID: "GPU-H100"},
+		{Index: "1", Vendor: "1a03", Device: "2000", Class: "0300", PCIAddress: "0000:02:00.0", Product: "ASPEED Graphics Family"},
+	}
+	detections := reportedDetections(detection.Device{Index: 0, UUID: "GPU-H100", ComputeMajor: 9, DisplayMode: "Disabled"})
+
+	compute, display := ClassifyDisplayDevices(devices, detections, nil)
+	if got := indices(compute); len(got) != 1 || got[0] != "0" {
+		t.Fatalf("expected only the H100 to stay a compute device, got %v", got)
+	}
+	if len(display) != 1 {
+		t.Fatalf("expected the ASPEED adapter to be display-only, got %+v", display)
+	}
+	want := v1alpha1.GPUNodeDisplayDevice{
+		Index:   "1",
+		Product: "ASPEED Graphics Family",
+		PCI:     v1alpha1.PCIAddress{Vendor: "1a03", Device: "2000", Class: "0300", Address: "0000:02:00.0"},
+	}
+	if display[0] != want {
+		t.Fatalf("unexpected display device: %+v", display[0])
+	}
+
+	// Before gfd-extender answers, a new node is classified by the PCI class alone.
+	compute, display = ClassifyDisplayDevices(devices, NodeDetection{}, nil)
+	if len(compute) != 1 || len(display) != 1 || display[0].Index != "1" {
+		t.Fatalf("expected the PCI class to classify a new node, got compute=%v display=%+v", indices(compute), display)
+	}
+}
+
+func TestClassifyDisplayDevicesNVIDIADisplayClass(t *testing.T) {
+	devices := []invstate.DeviceSnapshot{
+		{Index: "0", Vendor: "10de", Device: "1eb8", Class: "0302", UUID: "GPU-T4"},
+		{Index: "1", Vendor: "10de", Device: "1cb3", Class: "0300", UUID: "GPU-P400", Product: "Quadro P400"},
+	}
+	detections := reportedDetections(
+		detection.Device{Index: 0, UUID: "GPU-T4", ComputeMajor: 7, ComputeMinor: 5, DisplayMode: "Disabled"},
+		detection.Device{Index: 1, UUID: "GPU-P400", DisplayMode: "Enabled"},
+	)
+
+	compute, display := ClassifyDisplayDevices(devices, detections, nil)
+	if got := indices(compute); len(got) != 1 || got[0] != "0" {
+		t.Fatalf("expected only the T4 to stay a compute device, got %v", got)
+	}
+	if len(display) != 1 || display[0].Index != "1" || display[0].DisplayMode != "Enabled" {
+		t.Fatalf("expected the display-class GPU to be display-only, got %+v", display)
+	}
+
+	// A display-class GPU with compute capability is a compute GPU driving a monitor.
+	detections = reportedDetections(
+		detection.Device{Index: 0, UUID: "GPU-T4", ComputeMajor: 7, ComputeMinor: 5},
+		detection.Device{Index: 1, UUID: "GPU-P400", ComputeMajor: 6, ComputeMinor: 1, DisplayMode: "Enabled"},
+	)
+	if compute, display = ClassifyDisplayDevices(devices, detections, nil); len(compute) != 2 || len(display) != 0 {
+		t.Fatalf("expected both GPUs to be compute devices, got compute=%v display=%+v", indices(compute), display)
+	}
+}
+
+func TestClassifyDisplayDevicesLateReclassification(t *testing.T) {
+	devices := []invstate.DeviceSnapshot{
+		{Index: "0", Vendor: "10de", Device: "2204", Class: "0300", PCIAddress: "0000:65:00.0"},
+	}
+
+	// The driver is not loaded yet: the VGA function is display-only.
+	compute, display := ClassifyDisplayDevices(devices, reportedDetections(), nil)
+	if len(compute) != 0 || len(display) != 1 {
+		t.Fatalf("expected a display-only adapter, got compute=%v display=%+v", indices(compute), display)
+	}
+	now := metav1.Now()
+	previous := &v1alpha1.GPUNodeState{Status: v1alpha1.GPUNodeStateStatus{LastReconcileTime: &now, DisplayDevices: display}}
+
+	// gfd-extender is unreachable for a while: the known classification is kept.
+	if compute, display = ClassifyDisplayDevices(devices, NodeDetection{}, previous); len(compute) != 0 || len(display) != 1 {
+		t.Fatalf("expected the persisted classification to be kept, got compute=%v display=%+v", indices(compute), display)
+	}
+
+	// The driver comes up and reports compute capability: the device becomes a compute GPU.
+	detections := reportedDetections(detection.Device{Index: 0, UUID: "GPU-3090", ComputeMajor: 8, ComputeMinor: 6, DisplayMode: "Enabled"})
+	compute, display = ClassifyDisplayDevices(devices, detections, previous)
+	if got := indices(compute); len(got) != 1 || got[0] != "0" || len(display) != 0 {
+		t.Fatalf("expected the adapter to be reclassified as compute, got compute=%v display=%+v", got, display)
+	}
+}
+
+func TestClassifyDisplayDevicesKeepsUnqueryableGPUs(t *testing.T) {
+	devices := []invstate.DeviceSnapshot{{Index: "0", Vendor: "10de", Device: "2204", Class: "0300"}}
+	detections := reportedDetections()
+	detections.initFailed = map[string]struct{}{"0": {}}
+
+	if compute, display := ClassifyDisplayDevices(devices, detections, nil); len(compute) != 1 || len(display) != 0 {
+		t.Fatalf("expected a GPU the driver failed to query to stay a compute device, got compute=%v display=%+v", indices(compute), display)
+	}
+
+	// A node reconciled before with no display devices keeps its GPUs while gfd-extender is away.
+	now := metav1.Now()
+	previous := &v1alpha1.GPUNodeState{Status: v1alpha1.GPUNodeStateStatus{LastReconcileTime: &now}}
+	if compute, display := ClassifyDisplayDevices(devices, NodeDetection{}, previous); len(compute) != 1 || len(display) != 0 {
+		t.Fatalf("expected a known compute GPU to stay one, got compute=%v display=%+v", indices(compute), display)
+	}
+}
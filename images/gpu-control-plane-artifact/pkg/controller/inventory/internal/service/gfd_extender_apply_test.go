@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
 )
@@ -26,19 +27,19 @@ func TestApplyDetectionPopulatesHardware(t *testing.T) {
 	device := &v1alpha1.GPUDevice{}
 	snapshot := invstate.DeviceSnapshot{Index: "0", UUID: "GPU-AAA"}
 	detections := NodeDetection{
-		byUUID: map[string]detectGPUEntry{
+		byUUID: map[string]detection.Device{
 			"GPU-AAA": {
 				Index:   0,
 				UUID:    "GPU-AAA",
 				Product: "A100",
-				MemoryInfo: detectGPUMemory{
+				MemoryInfo: detection.MemoryInfo{
 					Total: 80 * 1024 * 1024 * 1024,
 					Free:  60 * 1024 * 1024 * 1024,
 					Used:  20 * 1024 * 1024 * 1024,
 				},
 				PowerUsage:                  120000,
 				PowerManagementDefaultLimit: 150000,
-				Utilization: detectGPUUtilization{
+				Utilization: detection.Utilization{
 					GPU:    75,
 					Memory: 40,
 				},
@@ -48,7 +49,7 @@ func TestApplyDetectionPopulatesHardware(t *testing.T) {
 				NUMANode:           detectionPtrInt32(1),
 				SMCount:            detectionPtrInt32(108),
 				MemoryBandwidthMiB: detectionPtrInt32(1555),
-				PCI: detectGPUPCI{
+				PCI: detection.PCIInfo{
 					Address: "0000:17:00.0",
 					Vendor:  "10de",
 					Device:  "2203",
@@ -58,11 +59,12 @@ func TestApplyDetectionPopulatesHardware(t *testing.T) {
 				Family:      "ampere",
 				Serial:      "serial-123",
 				DisplayMode: "Enabled",
-				MIG:         detectGPUMIG{Capable: true, ProfilesSupported: []string{"mig-1g.10gb"}},
+				Precision:   []string{"FP16", "bf16", "fp16"},
+				MIG:         detection.MIGInfo{Capable: true, ProfilesSupported: []string{"mig-1g.10gb"}},
 				PowerState:  0,
 			},
 		},
-		byIndex: map[string]detectGPUEntry{},
+		byIndex: map[string]detection.Device{},
 	}
 
 	ApplyDetection(device, snapshot, detections)
@@ -73,6 +75,10 @@ func TestApplyDetectionPopulatesHardware(t *testing.T) {
 	if !device.Status.Hardware.MIG.Capable || len(device.Status.Hardware.MIG.ProfilesSupported) != 1 || device.Status.Hardware.MIG.ProfilesSupported[0] != "1g.10gb" {
 		t.Fatalf("expected MIG profiles propagated, got %+v", device.Status.Hardware.MIG)
 	}
+	hw := device.Status.Hardware
+	if hw.MemoryMiB != 80*1024 || hw.ComputeCapability != "8.0" || !reflect.DeepEqual(hw.Precision, []string{"bf16", "fp16"}) {
+		t.Fatalf("expected capabilities propagated, got memory=%d compute=%q precision=%v", hw.MemoryMiB, hw.ComputeCapability, hw.Precision)
+	}
 }
 
 func TestApplyDetectionMissingEntriesDoesNothing(t *testing.T) {
@@ -87,10 +93,10 @@ func TestApplyDetectionMissingEntriesDoesNothing(t *testing.T) {
 
 func TestNodeDetectionFallbacks(t *testing.T) {
 	detections := NodeDetection{
-		byUUID: map[string]detectGPUEntry{
+		byUUID: map[string]detection.Device{
 			"GPU-A": {UUID: "GPU-A", Index: 1},
 		},
-		byIndex: map[string]detectGPUEntry{
+		byIndex: map[string]detection.Device{
 			"5": {Index: 5, UUID: "GPU-B"},
 		},
 	}
@@ -26,12 +26,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
 )
 
 func TestCollectNodeDetectionsMissingPodIsSilent(t *testing.T) {
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme))
+	collector := NewDetectionCollector(newTestClient(t, scheme), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	detections, err := collector.Collect(context.Background(), "node-no-pod")
 	if err != nil {
@@ -54,12 +57,14 @@ func TestCollectNodeDetectionsHTTPError(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-http-error"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-pod",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-pod",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
@@ -76,7 +81,7 @@ func TestCollectNodeDetectionsHTTPError(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	orig := detectHTTPClient
 	detectHTTPClient = server.Client()
@@ -101,12 +106,14 @@ func TestCollectNodeDetectionsStoresEntriesByUUID(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-uuid"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-pod-uuid",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-pod-uuid",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
@@ -123,7 +130,7 @@ func TestCollectNodeDetectionsStoresEntriesByUUID(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	orig := detectHTTPClient
 	detectHTTPClient = server.Client()
@@ -153,12 +160,14 @@ func TestCollectNodeDetectionsDecodeError(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-decode-error"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-pod-decode",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-pod-decode",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
@@ -175,7 +184,7 @@ func TestCollectNodeDetectionsDecodeError(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	orig := detectHTTPClient
 	detectHTTPClient = server.Client()
@@ -187,15 +196,18 @@ func TestCollectNodeDetectionsDecodeError(t *testing.T) {
 }
 
 func TestCollectNodeDetectionsWithoutPort(t *testing.T) {
+	t.Setenv(detectPortEnv, "0")
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-no-port"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-no-port",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-no-port",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name: "gfd-extender",
 			}},
@@ -211,7 +223,7 @@ func TestCollectNodeDetectionsWithoutPort(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	if detections, err := collector.Collect(context.Background(), node.Name); err != nil {
 		t.Fatalf("unexpected error when gfd-extender port is missing: %v", err)
@@ -233,12 +245,14 @@ func TestCollectNodeDetectionsSuccessIndexFallback(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-detect-success"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-ok",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-ok",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
@@ -255,7 +269,7 @@ func TestCollectNodeDetectionsSuccessIndexFallback(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	orig := detectHTTPClient
 	detectHTTPClient = server.Client()
@@ -275,12 +289,14 @@ func TestCollectNodeDetectionsSkipsNonReadyAndOtherNode(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-skip"}}
 	otherNodePod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-other",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-other",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: "other-node",
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           "other-node",
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: 1234}},
@@ -294,12 +310,14 @@ func TestCollectNodeDetectionsSkipsNonReadyAndOtherNode(t *testing.T) {
 	}
 	notReadyPod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-notready",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-notready",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: 1234}},
@@ -313,7 +331,7 @@ func TestCollectNodeDetectionsSkipsNonReadyAndOtherNode(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, otherNodePod, notReadyPod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, otherNodePod, notReadyPod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	detections, err := collector.Collect(context.Background(), node.Name)
 	if err != nil {
@@ -334,12 +352,14 @@ func TestCollectNodeDetectionsDoError(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-do-error"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-do-error",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-do-error",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: 1234}},
@@ -353,7 +373,7 @@ func TestCollectNodeDetectionsDoError(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	orig := detectHTTPClient
 	detectHTTPClient = &http.Client{Transport: failingRoundTripper{}}
@@ -372,12 +392,14 @@ func TestCollectNodeDetectionsBadURL(t *testing.T) {
 	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-bad-url"}}
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gfd-bad-url",
-			Namespace: common.WorkloadsNamespace,
-			Labels:    map[string]string{"app": common.AppName(common.ComponentGPUFeatureDiscovery)},
+			Name:            "gfd-bad-url",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
 		},
 		Spec: corev1.PodSpec{
-			NodeName: node.Name,
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
 			Containers: []corev1.Container{{
 				Name:  "gfd-extender",
 				Ports: []corev1.ContainerPort{{ContainerPort: 1234}},
@@ -391,7 +413,7 @@ func TestCollectNodeDetectionsBadURL(t *testing.T) {
 	}
 
 	scheme := newTestScheme(t)
-	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod))
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
 
 	detections, err := collector.Collect(context.Background(), node.Name)
 	if err != nil {
@@ -401,3 +423,63 @@ func TestCollectNodeDetectionsBadURL(t *testing.T) {
 		t.Fatalf("expected empty detections on bad URL, got %+v", detections)
 	}
 }
+
+func TestCollectNodeDetectionsRejectsOutOfRangeValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"index":-1,"uuid":"GPU-negative","computeMajor":8,"computeMinor":-1},{"index":1,"uuid":"GPU-busy","utilization":{"Gpu":250}}]`))
+	}))
+	defer server.Close()
+
+	host, portStr, _ := strings.Cut(server.Listener.Addr().String(), ":")
+	port, _ := strconv.Atoi(portStr)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-range"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "gfd-pod-range",
+			Namespace:       common.WorkloadsNamespace,
+			Labels:          map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+			OwnerReferences: gfdOwnerReferences(),
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: common.AppName(common.ComponentGFDExtender),
+			NodeName:           node.Name,
+			Containers: []corev1.Container{{
+				Name:  "gfd-extender",
+				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      host,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	scheme := newTestScheme(t)
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
+
+	orig := detectHTTPClient
+	detectHTTPClient = server.Client()
+	defer func() { detectHTTPClient = orig }()
+
+	detections, err := collector.Collect(context.Background(), node.Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := detections.byIndex["-1"]; ok {
+		t.Fatalf("expected negative index not to be indexed, got %+v", detections.byIndex)
+	}
+	if _, ok := detections.byUUID["GPU-negative"]; !ok {
+		t.Fatalf("expected entry with negative index to stay reachable by uuid, got %+v", detections.byUUID)
+	}
+	if _, ok := detections.GPUUtilization("GPU-busy", "1"); ok {
+		t.Fatal("expected utilization above 100% to be rejected")
+	}
+
+	device := &v1alpha1.GPUDevice{}
+	ApplyDetection(device, invstate.DeviceSnapshot{UUID: "GPU-negative"}, detections)
+	if device.Status.Hardware.ComputeCapability != "" {
+		t.Fatalf("expected negative compute minor to be ignored, got %q", device.Status.Hardware.ComputeCapability)
+	}
+}
@@ -15,28 +15,55 @@
 package service
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	common "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
+	commonpod "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/pod"
 )
 
-func TestDetectGPUPort(t *testing.T) {
-	pod := &corev1.Pod{
-		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{
-				{
-					Name:  "gfd-extender",
-					Ports: []corev1.ContainerPort{{ContainerPort: 1234}},
-				},
-			},
-		},
+func gfdOwnerReferences() []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       "DaemonSet",
+		Name:       common.AppName(common.ComponentGFDExtender),
+		UID:        "gfd-uid",
+		Controller: ptr.To(true),
+	}}
+}
+
+func TestDetectPortLookup(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	lookup := detectPortLookup(getenv)
+	if lookup.Name != detection.PortName || lookup.Container != "gfd-extender" || lookup.Fallback != detection.DefaultPort {
+		t.Fatalf("unexpected default lookup %+v", lookup)
+	}
+
+	env[detectPortEnv] = "8086"
+	if lookup := detectPortLookup(getenv); lookup.Fallback != 8086 {
+		t.Fatalf("expected the env override, got %+v", lookup)
 	}
-	if port := detectGPUPort(pod); port != 1234 {
-		t.Fatalf("expected gfd-extender port from container, got %d", port)
+	env[detectPortEnv] = "0"
+	if lookup := detectPortLookup(getenv); lookup.Fallback != 0 {
+		t.Fatalf("expected 0 to disable the fallback, got %+v", lookup)
 	}
-	pod.Spec.Containers[0].Name = "other"
-	if port := detectGPUPort(pod); port != 0 {
-		t.Fatalf("expected zero port when container missing, got %d", port)
+	env[detectPortEnv] = "not-a-port"
+	if lookup := detectPortLookup(getenv); lookup.Fallback != detection.DefaultPort {
+		t.Fatalf("expected an invalid override to keep the default, got %+v", lookup)
 	}
 }
 
@@ -46,16 +73,125 @@ func TestIsPodReadyFalseCases(t *testing.T) {
 			Phase: corev1.PodPending,
 		},
 	}
-	if isPodReady(pod) {
+	if commonpod.IsReady(pod) {
 		t.Fatalf("pending pod should not be ready")
 	}
 	pod.Status.Phase = corev1.PodRunning
 	pod.Status.PodIP = "1.2.3.4"
-	if isPodReady(pod) {
+	if commonpod.IsReady(pod) {
 		t.Fatalf("pod without ready condition should not be ready")
 	}
 	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
-	if isPodReady(pod) {
+	if commonpod.IsReady(pod) {
 		t.Fatalf("pod with ready=false should not be ready")
 	}
 }
+
+func TestIsTrustedDetectionPod(t *testing.T) {
+	name := common.AppName(common.ComponentGFDExtender)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{OwnerReferences: gfdOwnerReferences()},
+		Spec:       corev1.PodSpec{ServiceAccountName: name},
+	}
+	if !isTrustedDetectionPod(pod) {
+		t.Fatalf("expected pod owned by the gfd DaemonSet to be trusted")
+	}
+
+	wrongSA := pod.DeepCopy()
+	wrongSA.Spec.ServiceAccountName = "default"
+	if isTrustedDetectionPod(wrongSA) {
+		t.Fatalf("expected pod with foreign service account to be rejected")
+	}
+
+	noOwner := pod.DeepCopy()
+	noOwner.OwnerReferences = nil
+	if isTrustedDetectionPod(noOwner) {
+		t.Fatalf("expected pod without owner to be rejected")
+	}
+
+	wrongOwner := pod.DeepCopy()
+	wrongOwner.OwnerReferences[0].Kind = "ReplicaSet"
+	if isTrustedDetectionPod(wrongOwner) {
+		t.Fatalf("expected pod owned by another workload kind to be rejected")
+	}
+
+	otherDS := pod.DeepCopy()
+	otherDS.OwnerReferences[0].Name = "spoofed"
+	if isTrustedDetectionPod(otherDS) {
+		t.Fatalf("expected pod owned by another DaemonSet to be rejected")
+	}
+}
+
+func TestCollectNodeDetectionsSkipsUntrustedPodAndSendsToken(t *testing.T) {
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != detection.PathV1 {
+			http.NotFound(w, r)
+			return
+		}
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"index":0,"uuid":"GPU-uuid-1"}]`))
+	}))
+	defer server.Close()
+
+	host, portStr, _ := strings.Cut(server.Listener.Addr().String(), ":")
+	port, _ := strconv.Atoi(portStr)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("controller-token\n"), 0o600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+	origToken := serviceAccountTokenPath
+	serviceAccountTokenPath = tokenFile
+	defer func() { serviceAccountTokenPath = origToken }()
+
+	origClient := detectHTTPClient
+	detectHTTPClient = server.Client()
+	defer func() { detectHTTPClient = origClient }()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-trust"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gfd-spoofed",
+			Namespace: common.WorkloadsNamespace,
+			Labels:    map[string]string{"app": common.AppName(common.ComponentGFDExtender)},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node.Name,
+			Containers: []corev1.Container{{
+				Name:  "gfd-extender",
+				Ports: []corev1.ContainerPort{{ContainerPort: int32(port)}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			PodIP:      host,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	scheme := newTestScheme(t)
+	collector := NewDetectionCollector(newTestClient(t, scheme, node, pod), httpcall.New(httpcall.CategoryDetection, 0), nil)
+	detections, err := collector.Collect(context.Background(), node.Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(detections.byUUID) != 0 || len(authHeaders) != 0 {
+		t.Fatalf("expected spoofed pod to be ignored, got %+v and %d requests", detections, len(authHeaders))
+	}
+
+	trusted := pod.DeepCopy()
+	trusted.Name = "gfd-trusted"
+	trusted.OwnerReferences = gfdOwnerReferences()
+	trusted.Spec.ServiceAccountName = common.AppName(common.ComponentGFDExtender)
+	collector = NewDetectionCollector(newTestClient(t, scheme, node, trusted), httpcall.New(httpcall.CategoryDetection, 0), nil)
+	detections, err = collector.Collect(context.Background(), node.Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := detections.byUUID["GPU-uuid-1"]; !ok {
+		t.Fatalf("expected detections from trusted pod, got %+v", detections)
+	}
+	if len(authHeaders) != 1 || authHeaders[0] != "Bearer controller-token" {
+		t.Fatalf("expected controller token to be attached, got %v", authHeaders)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+// HandlerRuntime tracks the per-handler settings from ModuleConfig. Enabled is consulted on every
+// reconcile, while Configure is only called when a handler's settings blob changes; rejected settings
+// are reported on every GPUNodeState through the HandlersConfigured condition.
+// A nil HandlerRuntime enables every handler.
+type HandlerRuntime struct {
+	mu       sync.RWMutex
+	settings map[string]moduleconfig.HandlerSettings
+	applied  map[string]string
+	errs     map[string]error
+	unknown  map[string]struct{}
+}
+
+func NewHandlerRuntime() *HandlerRuntime {
+	return &HandlerRuntime{
+		applied: make(map[string]string),
+		errs:    make(map[string]error),
+		unknown: make(map[string]struct{}),
+	}
+}
+
+// Apply stores the settings and configures handlers whose settings changed. It returns the configured
+// names that match none of the known handlers and were not reported by a previous call.
+func (r *HandlerRuntime) Apply(settings map[string]moduleconfig.HandlerSettings, handlers []reconciler.Named) []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings = settings
+	known := make(map[string]struct{}, len(handlers))
+	for _, handler := range handlers {
+		name := handler.Name()
+		known[name] = struct{}{}
+
+		configurable, ok := handler.(reconciler.Configurable)
+		if !ok {
+			continue
+		}
+		blob := string(settings[name].Settings)
+		if prev, seen := r.applied[name]; seen && prev == blob {
+			continue
+		}
+		r.applied[name] = blob
+		if err := configurable.Configure(settings[name].Settings); err != nil {
+			r.errs[name] = err
+		} else {
+			delete(r.errs, name)
+		}
+	}
+
+	var fresh []string
+	current := make(map[string]struct{}, len(settings))
+	for name := range settings {
+		if _, ok := known[name]; ok {
+			continue
+		}
+		current[name] = struct{}{}
+		if _, reported := r.unknown[name]; !reported {
+			fresh = append(fresh, name)
+		}
+	}
+	r.unknown = current
+	sort.Strings(fresh)
+	return fresh
+}
+
+// Enabled reports whether the named handler should run.
+func (r *HandlerRuntime) Enabled(name string) bool {
+	if r == nil {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	settings, ok := r.settings[name]
+	return !ok || settings.Enabled
+}
+
+// ConfigureErrors returns the last Configure error per handler name.
+func (r *HandlerRuntime) ConfigureErrors() map[string]error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.errs) == 0 {
+		return nil
+	}
+	out := make(map[string]error, len(r.errs))
+	for name, err := range r.errs {
+		out[name] = err
+	}
+	return out
+}
+
+// setHandlersConfigured reports handlers whose runtime settings were rejected on the GPUNodeState. The condition is
+// only added once a failure happens, so node states stay untouched while handler settings are not used.
+func setHandlersConfigured(inventory *v1alpha1.GPUNodeState, errs map[string]error) {
+	failed := make([]string, 0, len(errs))
+	for name, err := range errs {
+		failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+	}
+
+	builder := conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionHandlersConfigured)).
+		Generation(inventory.Generation)
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		builder.Status(metav1.ConditionFalse).
+			Reason(conditions.CommonReason(invstate.ReasonHandlerConfigureFailed)).
+			Message(strings.Join(failed, "; "))
+	} else {
+		if conditions.FindStatusCondition(inventory.Status.Conditions, invstate.ConditionHandlersConfigured) == nil {
+			return
+		}
+		builder.Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(invstate.ReasonHandlersConfigured)).
+			Message("handler settings applied")
+	}
+	conditions.SetCondition(builder, &inventory.Status.Conditions)
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+type configurableHandler struct {
+	name       string
+	calls      int
+	configured []string
+	err        error
+}
+
+func (h *configurableHandler) Name() string { return h.name }
+
+func (h *configurableHandler) HandleDevice(context.Context, *v1alpha1.GPUDevice) (reconcile.Result, error) {
+	h.calls++
+	return reconcile.Result{}, nil
+}
+
+func (h *configurableHandler) Configure(settings json.RawMessage) error {
+	h.configured = append(h.configured, string(settings))
+	return h.err
+}
+
+func TestHandlerRuntimeDisableTakesEffectWithoutRestart(t *testing.T) {
+	handler := &configurableHandler{name: "telemetry"}
+	runtime := NewHandlerRuntime()
+	svc := NewDeviceService(nil, nil, nil, []DeviceHandler{handler}, nil, nil)
+	svc.SetHandlerRuntime(runtime)
+	device := &v1alpha1.GPUDevice{}
+
+	if _, err := svc.invokeHandlers(context.Background(), device); err != nil {
+		t.Fatalf("invokeHandlers: %v", err)
+	}
+	if handler.calls != 1 {
+		t.Fatalf("expected handler to run by default, got %d calls", handler.calls)
+	}
+
+	runtime.Apply(map[string]moduleconfig.HandlerSettings{"telemetry": {Enabled: false}}, []reconciler.Named{handler})
+	if _, err := svc.invokeHandlers(context.Background(), device); err != nil {
+		t.Fatalf("invokeHandlers: %v", err)
+	}
+	if handler.calls != 1 {
+		t.Fatalf("expected disabled handler to be skipped, got %d calls", handler.calls)
+	}
+
+	runtime.Apply(nil, []reconciler.Named{handler})
+	if _, err := svc.invokeHandlers(context.Background(), device); err != nil {
+		t.Fatalf("invokeHandlers: %v", err)
+	}
+	if handler.calls != 2 {
+		t.Fatalf("expected handler to run again once re-enabled, got %d calls", handler.calls)
+	}
+}
+
+func TestHandlerRuntimeConfigureOnlyOnChange(t *testing.T) {
+	handler := &configurableHandler{name: "telemetry"}
+	runtime := NewHandlerRuntime()
+	settings := map[string]moduleconfig.HandlerSettings{"telemetry": {Enabled: true, Settings: json.RawMessage(`{"a":1}`)}}
+
+	runtime.Apply(settings, []reconciler.Named{handler})
+	runtime.Apply(settings, []reconciler.Named{handler})
+	if len(handler.configured) != 1 || handler.configured[0] != `{"a":1}` {
+		t.Fatalf("expected a single Configure call, got %v", handler.configured)
+	}
+
+	runtime.Apply(map[string]moduleconfig.HandlerSettings{"telemetry": {Enabled: true, Settings: json.RawMessage(`{"a":2}`)}}, []reconciler.Named{handler})
+	if len(handler.configured) != 2 {
+		t.Fatalf("expected Configure on settings change, got %v", handler.configured)
+	}
+}
+
+func TestHandlerRuntimeReportsUnknownHandlersOnce(t *testing.T) {
+	handler := &configurableHandler{name: "telemetry"}
+	runtime := NewHandlerRuntime()
+	settings := map[string]moduleconfig.HandlerSettings{"typo": {Enabled: false}}
+
+	if unknown := runtime.Apply(settings, []reconciler.Named{handler}); len(unknown) != 1 || unknown[0] != "typo" {
+		t.Fatalf("expected unknown handler to be reported, got %v", unknown)
+	}
+	if unknown := runtime.Apply(settings, []reconciler.Named{handler}); len(unknown) != 0 {
+		t.Fatalf("expected unknown handler to be reported once, got %v", unknown)
+	}
+}
+
+func TestHandlerRuntimeConfigureErrorSurfacesOnNodeState(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-handlers")
+	base := newTestClient(t, scheme, node)
+
+	handler := &configurableHandler{name: "telemetry", err: errors.New("bad interval")}
+	runtime := NewHandlerRuntime()
+	devices := NewDeviceService(nil, nil, nil, []DeviceHandler{handler}, nil, nil)
+	devices.SetHandlerRuntime(runtime)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
+	svc.SetHandlerRuntime(runtime)
+	snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+	reconcileNode := func() *metav1.Condition {
+		t.Helper()
+		if err := svc.Reconcile(ctx, node, snapshot, []*v1alpha1.GPUDevice{{}}); err != nil {
+			t.Fatalf("Reconcile returned error: %v", err)
+		}
+		inventory := &v1alpha1.GPUNodeState{}
+		if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, inventory); err != nil {
+			t.Fatalf("get inventory: %v", err)
+		}
+		return findCondition(inventory.Status.Conditions, invstate.ConditionHandlersConfigured)
+	}
+
+	if cond := reconcileNode(); cond != nil {
+		t.Fatalf("expected no condition without handler settings, got %+v", cond)
+	}
+
+	runtime.Apply(map[string]moduleconfig.HandlerSettings{"telemetry": {Enabled: true, Settings: json.RawMessage(`{"interval":"x"}`)}}, []reconciler.Named{handler})
+	cond := reconcileNode()
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != invstate.ReasonHandlerConfigureFailed || cond.Message != "telemetry: bad interval" {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+	device := &v1alpha1.GPUDevice{}
+	if _, err := devices.invokeHandlers(ctx, device); err != nil {
+		t.Fatalf("invokeHandlers: %v", err)
+	}
+	if handler.calls != 1 {
+		t.Fatalf("expected handler to keep running with previous settings, got %d calls", handler.calls)
+	}
+	if len(device.Status.Conditions) != 0 {
+		t.Fatalf("expected devices to stay free of handler conditions, got %+v", device.Status.Conditions)
+	}
+
+	handler.err = nil
+	runtime.Apply(map[string]moduleconfig.HandlerSettings{"telemetry": {Enabled: true, Settings: json.RawMessage(`{"interval":"30s"}`)}}, []reconciler.Named{handler})
+	if cond := reconcileNode(); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("expected condition to recover, got %+v", cond)
+	}
+}
@@ -16,39 +16,92 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/canonical"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/devicestate"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+	schemametrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/schema"
 )
 
 type InventoryService struct {
 	client   client.Client
 	scheme   *runtime.Scheme
 	recorder eventrecord.EventRecorderLogger
+	clock    clock.PassiveClock
+	// conditionPolicy returns the compiled inventory.conditionPolicy.
+	conditionPolicy func() invstate.ConditionPolicy
+	runtime         *HandlerRuntime
+	metrics         *invmetrics.Metrics
+	guard           *ownership.Guard
 }
 
-func NewInventoryService(c client.Client, scheme *runtime.Scheme, recorder eventrecord.EventRecorderLogger) *InventoryService {
+// NewInventoryService builds the GPUNodeState service; a nil guard lets it write every node state.
+func NewInventoryService(c client.Client, scheme *runtime.Scheme, recorder eventrecord.EventRecorderLogger, metrics *invmetrics.Metrics, guard *ownership.Guard) *InventoryService {
 	return &InventoryService{
 		client:   c,
 		scheme:   scheme,
 		recorder: recorder,
+		clock:    clock.RealClock{},
+		metrics:  metrics,
+		guard:    guard,
 	}
 }
 
+// SetClock replaces the clock used to stamp reconcile outcomes.
+func (s *InventoryService) SetClock(c clock.PassiveClock) {
+	s.clock = c
+}
+
+// SetConditionPolicy supplies the inventory.conditionPolicy applied to the GPUNodeState conditions.
+func (s *InventoryService) SetConditionPolicy(policy func() invstate.ConditionPolicy) {
+	s.conditionPolicy = policy
+}
+
+// SetHandlerRuntime makes the GPUNodeState report handlers whose runtime settings were rejected.
+func (s *InventoryService) SetHandlerRuntime(runtime *HandlerRuntime) {
+	s.runtime = runtime
+}
+
+func (s *InventoryService) currentConditionPolicy() invstate.ConditionPolicy {
+	if s.conditionPolicy == nil {
+		return invstate.ConditionPolicy{}
+	}
+	return s.conditionPolicy()
+}
+
+// setConditionMetric exports the condition with its configured severity; a suppressed condition is left out.
+func (s *InventoryService) setConditionMetric(policy invstate.ConditionPolicy, node *corev1.Node, conditionType string, value bool) {
+	if policy.Suppressed(conditionType, node.Labels) {
+		s.metrics.InventoryConditionDelete(node.Name, conditionType)
+		return
+	}
+	s.metrics.InventoryConditionSet(node.Name, conditionType, policy.Severity(conditionType), value)
+}
+
 func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, snapshot invstate.NodeSnapshot, devices []*v1alpha1.GPUDevice) error {
 	inventory := &v1alpha1.GPUNodeState{}
 	inventory, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: node.Name}, s.client, inventory)
@@ -70,11 +123,16 @@ func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, sna
 		if err := controllerutil.SetOwnerReference(node, inventory, s.scheme); err != nil {
 			return err
 		}
+		reconciler.StampReconciledBy(inventory, s.guard)
 		if err := s.client.Create(ctx, inventory); err != nil {
 			return err
 		}
 	}
 
+	if allowed, err := s.guard.MayWrite(ctx, inventory); err != nil || !allowed {
+		return err
+	}
+
 	specBefore := inventory.DeepCopy()
 	changed := false
 
@@ -88,8 +146,12 @@ func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, sna
 	if !equality.Semantic.DeepEqual(specBefore.OwnerReferences, inventory.OwnerReferences) {
 		changed = true
 	}
+	if s.guard.Stamp(inventory) {
+		changed = true
+	}
 
 	if changed {
+		reconciler.StampReconciledBy(inventory, s.guard)
 		if err := s.client.Patch(ctx, inventory, client.MergeFrom(specBefore)); err != nil {
 			return err
 		}
@@ -101,6 +163,7 @@ func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, sna
 	resource := reconciler.NewResource(
 		types.NamespacedName{Name: node.Name},
 		s.client,
+		s.guard,
 		func() *v1alpha1.GPUNodeState { return &v1alpha1.GPUNodeState{} },
 		func(obj *v1alpha1.GPUNodeState) v1alpha1.GPUNodeStateStatus { return obj.Status },
 	)
@@ -112,6 +175,8 @@ func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, sna
 	}
 
 	inventory = resource.Changed()
+	policy := s.currentConditionPolicy()
+	invstate.UnsuppressConditions(inventory.Status.Conditions)
 
 	inventoryComplete := snapshot.FeatureDetected && len(snapshot.Devices) > 0
 	inventoryReason := invstate.ReasonInventorySynced
@@ -133,7 +198,32 @@ func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, sna
 	prevComplete := conditions.FindStatusCondition(inventory.Status.Conditions, completeCond.Type)
 	inventoryChanged := prevComplete == nil || prevComplete.Status != completeCond.Status || prevComplete.Reason != completeCond.Reason || prevComplete.Message != completeCond.Message
 	conditions.SetCondition(condBuilder, &inventory.Status.Conditions)
-	invmetrics.InventoryConditionSet(node.Name, invstate.ConditionInventoryComplete, inventoryComplete)
+	s.setConditionMetric(policy, node, invstate.ConditionInventoryComplete, inventoryComplete)
+	// Reaching the normal path means the node is no longer draining (e.g. scale-down was aborted).
+	apimeta.RemoveStatusCondition(&inventory.Status.Conditions, invstate.ConditionNodeDraining)
+	// Likewise a node on the normal path has nothing left to delete.
+	apimeta.RemoveStatusCondition(&inventory.Status.Conditions, invstate.ConditionDeletionsThrottled)
+	setFieldParseWarning(inventory, snapshot.Warnings)
+	if snapshot.Platform != nil {
+		inventory.Status.Platform = snapshot.Platform.DeepCopy()
+	}
+	inventory.Status.DisplayDevices = snapshot.DisplayDevices
+	setTopologySummary(&inventory.Status, devices, s.clock.Now())
+	setContainerRuntime(&inventory.Status, node)
+	setSecureBootUnsignedDriver(inventory)
+	setHandlersConfigured(inventory, s.runtime.ConfigureErrors())
+	if setCompatibility(inventory, snapshot.Compatibility) && len(snapshot.Compatibility.Matched) > 0 && s.recorder != nil {
+		log := logr.FromContextOrDiscard(ctx).WithValues("node", node.Name)
+		s.recorder.WithLogging(log).Eventf(
+			node,
+			corev1.EventTypeWarning,
+			invstate.EventCompatibilityRuleMatched,
+			"node %s matched compatibility rules (%s): %s",
+			node.Name,
+			snapshot.Compatibility.Effect,
+			snapshot.Compatibility.Message(),
+		)
+	}
 
 	if inventoryChanged && s.recorder != nil {
 		eventType := corev1.EventTypeNormal
@@ -151,17 +241,183 @@ func (s *InventoryService) Reconcile(ctx context.Context, node *corev1.Node, sna
 			inventoryReason,
 		)
 	}
+	policy.Apply(inventory.Status.Conditions, node.Labels)
+
+	if nodeStateStatusEqual(resource.Current().Status, inventory.Status) {
+		return nil
+	}
+	// The snapshot changed the node state, so it now reflects this NodeFeature revision.
+	if snapshot.Provenance != nil {
+		provenance := snapshot.Provenance.DeepCopy()
+		provenance.CapturedAt = metav1.NewTime(s.clock.Now())
+		inventory.Status.Provenance = provenance
+	}
+
+	return commonerrors.WrapConflict(resource.Update(ctx))
+}
+
+// MarkDraining sets the NodeDraining condition on an existing GPUNodeState without touching devices.
+func (s *InventoryService) MarkDraining(ctx context.Context, node *corev1.Node, reason string) error {
+	resource := reconciler.NewResource(
+		types.NamespacedName{Name: node.Name},
+		s.client,
+		s.guard,
+		func() *v1alpha1.GPUNodeState { return &v1alpha1.GPUNodeState{} },
+		func(obj *v1alpha1.GPUNodeState) v1alpha1.GPUNodeStateStatus { return obj.Status },
+	)
+	if err := resource.Fetch(ctx); err != nil {
+		return err
+	}
+	if resource.IsEmpty() {
+		return nil
+	}
+
+	inventory := resource.Changed()
+	policy := s.currentConditionPolicy()
+	invstate.UnsuppressConditions(inventory.Status.Conditions)
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionNodeDraining)).
+			Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(reason)).
+			Message("node is being removed, device states are frozen").
+			Generation(inventory.Generation),
+		&inventory.Status.Conditions,
+	)
+	policy.Apply(inventory.Status.Conditions, node.Labels)
+
+	if nodeStateStatusEqual(resource.Current().Status, inventory.Status) {
+		return nil
+	}
+	return commonerrors.WrapConflict(resource.Update(ctx))
+}
+
+// MarkNodeFeatureAPIUnsupported reports on an existing GPUNodeState that its inventory cannot be collected because
+// the cluster does not serve the NodeFeature version the controller reads. Device states are left as they are.
+func (s *InventoryService) MarkNodeFeatureAPIUnsupported(ctx context.Context, node *corev1.Node, message string) error {
+	resource := reconciler.NewResource(
+		types.NamespacedName{Name: node.Name},
+		s.client,
+		s.guard,
+		func() *v1alpha1.GPUNodeState { return &v1alpha1.GPUNodeState{} },
+		func(obj *v1alpha1.GPUNodeState) v1alpha1.GPUNodeStateStatus { return obj.Status },
+	)
+	if err := resource.Fetch(ctx); err != nil {
+		return err
+	}
+	if resource.IsEmpty() {
+		return nil
+	}
 
-	if equality.Semantic.DeepEqual(resource.Current().Status, inventory.Status) {
+	inventory := resource.Changed()
+	policy := s.currentConditionPolicy()
+	invstate.UnsuppressConditions(inventory.Status.Conditions)
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionInventoryComplete)).
+			Status(metav1.ConditionFalse).
+			Reason(conditions.CommonReason(invstate.ReasonDiscoveryAPIUnsupported)).
+			Message(message).
+			Generation(inventory.Generation),
+		&inventory.Status.Conditions,
+	)
+	s.setConditionMetric(policy, node, invstate.ConditionInventoryComplete, false)
+	policy.Apply(inventory.Status.Conditions, node.Labels)
+
+	if nodeStateStatusEqual(resource.Current().Status, inventory.Status) {
 		return nil
 	}
+	return commonerrors.WrapConflict(resource.Update(ctx))
+}
+
+// maxReportedParseWarnings bounds the FieldParseWarning message on nodes with many broken attributes.
+const maxReportedParseWarnings = 10
+
+// setFieldParseWarning lists the labels and instance attributes the snapshot parser ignored, or drops the
+// condition once they are well-formed again.
+func setFieldParseWarning(inventory *v1alpha1.GPUNodeState, warnings []snapshot.Issue) {
+	if len(warnings) == 0 {
+		apimeta.RemoveStatusCondition(&inventory.Status.Conditions, invstate.ConditionFieldParseWarning)
+		return
+	}
+	fields := make([]string, 0, len(warnings))
+	seen := make(map[string]struct{}, len(warnings))
+	for _, issue := range warnings {
+		field := issue.Source + " " + issue.Key
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fields = append(fields, field)
+	}
+	message := "malformed hardware attributes ignored: "
+	if len(fields) > maxReportedParseWarnings {
+		message += strings.Join(fields[:maxReportedParseWarnings], ", ") + fmt.Sprintf(" and %d more", len(fields)-maxReportedParseWarnings)
+	} else {
+		message += strings.Join(fields, ", ")
+	}
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionFieldParseWarning)).
+			Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(invstate.ReasonMalformedAttributes)).
+			Message(message).
+			Generation(inventory.Generation),
+		&inventory.Status.Conditions,
+	)
+}
 
-	return resource.Update(ctx)
+// setSecureBootUnsignedDriver flags a node whose kernel enforces Secure Boot while the NVIDIA module is unsigned:
+// the next driver reload or reboot is refused by the kernel and GPUs disappear without a driver error to show for it.
+func setSecureBootUnsignedDriver(inventory *v1alpha1.GPUNodeState) {
+	platform := inventory.Status.Platform
+	if platform == nil || platform.SecureBoot == nil || !*platform.SecureBoot ||
+		platform.UnsignedDriverModule == nil || !*platform.UnsignedDriverModule {
+		apimeta.RemoveStatusCondition(&inventory.Status.Conditions, invstate.ConditionSecureBootUnsignedDriver)
+		return
+	}
+	conditions.SetCondition(
+		conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionSecureBootUnsignedDriver)).
+			Status(metav1.ConditionTrue).
+			Reason(conditions.CommonReason(invstate.ReasonUnsignedDriverModule)).
+			Message("Secure Boot is enabled but the loaded NVIDIA kernel module is unsigned; sign it with an enrolled key or the kernel will refuse to load it").
+			Generation(inventory.Generation),
+		&inventory.Status.Conditions,
+	)
 }
 
 func (s *InventoryService) UpdateDeviceMetrics(nodeName string, devices []*v1alpha1.GPUDevice) {
-	updateDeviceStateMetrics(nodeName, devices)
-	invmetrics.InventoryDevicesSet(nodeName, len(devices))
+	s.updateDeviceStateMetrics(nodeName, devices)
+	s.metrics.InventoryDevicesSet(nodeName, len(devices))
+	s.metrics.InventoryDevicesUnallocatedSet(nodeName, countUnallocated(devices))
+}
+
+// countUnallocated counts devices that could still join a pool: not faulted and not owned by any pool.
+func countUnallocated(devices []*v1alpha1.GPUDevice) int {
+	count := 0
+	for _, device := range devices {
+		if device.Status.PoolRef == nil && device.Status.State != v1alpha1.GPUDeviceStateFaulted {
+			count++
+		}
+	}
+	return count
+}
+
+// nodeStateStatusEqual compares the statuses with conditions treated as a set, so a reordered or merely
+// re-stamped condition list does not cost a write.
+func nodeStateStatusEqual(a, b v1alpha1.GPUNodeStateStatus) bool {
+	if !canonical.ConditionsEqual(a.Conditions, b.Conditions) {
+		return false
+	}
+	a.Conditions, b.Conditions = nil, nil
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+// setContainerRuntime mirrors the runtime the kubelet reports; a node that reports none clears it.
+func setContainerRuntime(status *v1alpha1.GPUNodeStateStatus, node *corev1.Node) {
+	detected := containerruntime.FromNode(node)
+	if detected.Name == "" {
+		status.ContainerRuntime = nil
+		return
+	}
+	status.ContainerRuntime = &v1alpha1.GPUNodeContainerRuntime{Name: string(detected.Name), Version: detected.Version}
 }
 
 func boolToConditionStatus(value bool) metav1.ConditionStatus {
@@ -171,25 +427,33 @@ func boolToConditionStatus(value bool) metav1.ConditionStatus {
 	return metav1.ConditionFalse
 }
 
-func updateDeviceStateMetrics(nodeName string, devices []*v1alpha1.GPUDevice) {
+func (s *InventoryService) updateDeviceStateMetrics(nodeName string, devices []*v1alpha1.GPUDevice) {
 	counts := make(map[string]int, len(devices))
 	for _, device := range devices {
-		stateKey := string(normalizeDeviceState(device.Status.State))
-		counts[stateKey]++
+		state := normalizeDeviceState(device.Status.State)
+		if devicestate.Foreign(state) {
+			schemametrics.NewerSchemaObservedInc("GPUDevice", schemametrics.MarkerState)
+			state = foreignDeviceState
+		}
+		counts[string(state)]++
 	}
 	seen := make(map[string]struct{}, len(counts))
 	for state, count := range counts {
-		invmetrics.InventoryDeviceStateSet(nodeName, state, count)
+		s.metrics.InventoryDeviceStateSet(nodeName, state, count)
 		seen[state] = struct{}{}
 	}
 	for _, state := range knownDeviceStates {
 		key := string(state)
 		if _, ok := seen[key]; !ok {
-			invmetrics.InventoryDeviceStateDelete(nodeName, key)
+			s.metrics.InventoryDeviceStateDelete(nodeName, key)
 		}
 	}
 }
 
+// foreignDeviceState labels devices in a state written by a newer controller, so unknown values do not
+// leave series behind once the devices move on.
+const foreignDeviceState v1alpha1.GPUDeviceState = "Foreign"
+
 func normalizeDeviceState(state v1alpha1.GPUDeviceState) v1alpha1.GPUDeviceState {
 	if state == "" {
 		return v1alpha1.GPUDeviceStateDiscovered
@@ -197,13 +461,4 @@ func normalizeDeviceState(state v1alpha1.GPUDeviceState) v1alpha1.GPUDeviceState
 	return state
 }
 
-var knownDeviceStates = []v1alpha1.GPUDeviceState{
-	v1alpha1.GPUDeviceStateDiscovered,
-	v1alpha1.GPUDeviceStateValidating,
-	v1alpha1.GPUDeviceStateReady,
-	v1alpha1.GPUDeviceStatePendingAssignment,
-	v1alpha1.GPUDeviceStateAssigned,
-	v1alpha1.GPUDeviceStateReserved,
-	v1alpha1.GPUDeviceStateInUse,
-	v1alpha1.GPUDeviceStateFaulted,
-}
+var knownDeviceStates = append(slices.Clone(devicestate.States), foreignDeviceState)
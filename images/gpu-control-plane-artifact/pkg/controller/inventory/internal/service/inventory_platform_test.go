@@ -0,0 +1,152 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestCollectNodeDetectionsDecodesPlatform(t *testing.T) {
+	detections := collectFrom(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != detection.PathV2 {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"schemaVersion":2,"generatedAt":"2025-01-01T00:00:00Z","devices":[],
+			"platform":{"os":{"id":"ubuntu","versionID":"22.04","name":"Ubuntu"},"kernelRelease":"5.15.0","bareMetal":false,"hypervisor":"KVM","iommu":true}}`))
+	})
+
+	platform := detections.Platform()
+	if platform == nil {
+		t.Fatal("expected platform facts")
+	}
+	if platform.OSID != "ubuntu" || platform.OSVersion != "22.04" || platform.OSName != "Ubuntu" || platform.KernelRelease != "5.15.0" {
+		t.Fatalf("unexpected OS facts: %+v", platform)
+	}
+	if platform.BareMetal == nil || *platform.BareMetal || platform.Hypervisor != "KVM" || platform.IOMMU == nil || !*platform.IOMMU {
+		t.Fatalf("unexpected virtualization facts: %+v", platform)
+	}
+	if platform.SecureBoot != nil || platform.UnsignedDriverModule != nil {
+		t.Fatalf("expected unreported facts to stay empty, got %+v", platform)
+	}
+}
+
+func TestCollectNodeDetectionsV1HasNoPlatform(t *testing.T) {
+	detections := collectFrom(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != detection.PathV1 {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	if detections.Platform() != nil {
+		t.Fatalf("expected no platform from the v1 API, got %+v", detections.Platform())
+	}
+}
+
+func TestInventoryServiceReconcilePersistsPlatform(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-platform")
+	base := newTestClient(t, scheme, node)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
+
+	enabled, unsigned := true, true
+	snap := invstate.NodeSnapshot{
+		FeatureDetected: true,
+		Devices:         []invstate.DeviceSnapshot{{Index: "0"}},
+		Platform:        &v1alpha1.GPUNodePlatform{KernelRelease: "6.8.0", SecureBoot: &enabled, UnsignedDriverModule: &unsigned},
+	}
+	get := func() *v1alpha1.GPUNodeState {
+		t.Helper()
+		got := &v1alpha1.GPUNodeState{}
+		if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+			t.Fatalf("get inventory: %v", err)
+		}
+		return got
+	}
+
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got := get()
+	if got.Status.Platform == nil || got.Status.Platform.KernelRelease != "6.8.0" {
+		t.Fatalf("expected platform to be persisted, got %+v", got.Status.Platform)
+	}
+	cond := findCondition(got.Status.Conditions, invstate.ConditionSecureBootUnsignedDriver)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != invstate.ReasonUnsignedDriverModule {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+
+	// A reconcile without fresh facts keeps the persisted platform and its condition.
+	snap.Platform = nil
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got = get()
+	if got.Status.Platform == nil || findCondition(got.Status.Conditions, invstate.ConditionSecureBootUnsignedDriver) == nil {
+		t.Fatalf("expected the last reported platform to be kept, got %+v", got.Status)
+	}
+
+	signed := false
+	snap.Platform = &v1alpha1.GPUNodePlatform{KernelRelease: "6.8.0", SecureBoot: &enabled, UnsignedDriverModule: &signed}
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if cond := findCondition(get().Status.Conditions, invstate.ConditionSecureBootUnsignedDriver); cond != nil {
+		t.Fatalf("expected the condition to be removed for a signed module, got %+v", cond)
+	}
+}
+
+func TestInventoryServiceReconcilePersistsContainerRuntime(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-runtime")
+	node.Status.NodeInfo.ContainerRuntimeVersion = "cri-o://1.28.1"
+	base := newTestClient(t, scheme, node)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
+	snap := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if rt := got.Status.ContainerRuntime; rt == nil || rt.Name != "cri-o" || rt.Version != "1.28.1" {
+		t.Fatalf("expected the cri-o runtime to be persisted, got %+v", rt)
+	}
+
+	node.Status.NodeInfo.ContainerRuntimeVersion = ""
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if got.Status.ContainerRuntime != nil {
+		t.Fatalf("expected an unreported runtime to be cleared, got %+v", got.Status.ContainerRuntime)
+	}
+}
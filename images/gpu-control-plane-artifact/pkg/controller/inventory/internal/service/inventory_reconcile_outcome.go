@@ -0,0 +1,71 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+)
+
+const (
+	// reconcileStampInterval suppresses writes that would only move LastReconcileTime forward.
+	reconcileStampInterval = time.Minute
+)
+
+// RecordReconcile stamps the outcome of a node reconcile on its GPUNodeState. A write that would
+// only refresh the timestamp is skipped until reconcileStampInterval has passed. Failures are logged
+// and never fail the reconcile: this is diagnostics, not state.
+func (s *InventoryService) RecordReconcile(ctx context.Context, nodeName string, reconcileErr error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	inventory, err := commonobject.FetchObject(ctx, client.ObjectKey{Name: nodeName}, s.client, &v1alpha1.GPUNodeState{})
+	if err != nil {
+		log.Error(err, "failed to read GPUNodeState to record reconcile outcome")
+		return
+	}
+	if inventory == nil {
+		return
+	}
+
+	message := ""
+	if reconcileErr != nil {
+		message, _ = objsize.TruncateString(reconcileErr.Error(), objsize.MaxReconcileErrorLength)
+	}
+	now := s.clock.Now()
+	last := inventory.Status.LastReconcileTime
+	if inventory.Status.LastReconcileError == message && last != nil && now.Sub(last.Time) < reconcileStampInterval {
+		return
+	}
+
+	original := inventory.DeepCopy()
+	stamp := metav1.NewTime(now)
+	inventory.Status.LastReconcileTime = &stamp
+	inventory.Status.LastReconcileError = message
+	if err := objsize.Guard(inventory); err != nil {
+		log.Error(err, "refusing to record reconcile outcome on GPUNodeState")
+		return
+	}
+	if err := s.client.Status().Patch(ctx, inventory, client.MergeFrom(original)); err != nil {
+		log.Error(err, "failed to record reconcile outcome on GPUNodeState")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+)
+
+func TestInventoryServiceRecordReconcile(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	inventory := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node-stamp"}}
+
+	writes := 0
+	base := newTestClient(t, scheme, inventory)
+	cl := interceptor.NewClient(base.(client.WithWatch), interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			writes++
+			return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	})
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(start)
+	svc := NewInventoryService(cl, scheme, nil, nil, nil)
+	svc.SetClock(clock)
+
+	get := func() v1alpha1.GPUNodeStateStatus {
+		t.Helper()
+		got := &v1alpha1.GPUNodeState{}
+		if err := base.Get(ctx, types.NamespacedName{Name: inventory.Name}, got); err != nil {
+			t.Fatalf("get inventory: %v", err)
+		}
+		return got.Status
+	}
+
+	// Success: the timestamp is stamped and no error recorded.
+	svc.RecordReconcile(ctx, inventory.Name, nil)
+	status := get()
+	if writes != 1 || status.LastReconcileTime == nil || !status.LastReconcileTime.Time.Equal(start) || status.LastReconcileError != "" {
+		t.Fatalf("unexpected status after success: writes=%d %+v", writes, status)
+	}
+
+	// Another success within the interval would only move the timestamp: suppressed.
+	clock.SetTime(start.Add(30 * time.Second))
+	svc.RecordReconcile(ctx, inventory.Name, nil)
+	if writes != 1 {
+		t.Fatalf("expected the timestamp-only write to be suppressed, got %d writes", writes)
+	}
+
+	// An error is written immediately and truncated.
+	svc.RecordReconcile(ctx, inventory.Name, errors.New(strings.Repeat("x", 300)))
+	status = get()
+	if writes != 2 || len(status.LastReconcileError) != objsize.MaxReconcileErrorLength || !status.LastReconcileTime.Time.Equal(start.Add(30*time.Second)) {
+		t.Fatalf("unexpected status after error: writes=%d error=%d chars time=%v", writes, len(status.LastReconcileError), status.LastReconcileTime)
+	}
+
+	// The same error within the interval is not rewritten.
+	clock.SetTime(start.Add(45 * time.Second))
+	svc.RecordReconcile(ctx, inventory.Name, errors.New(strings.Repeat("x", 300)))
+	if writes != 2 {
+		t.Fatalf("expected the repeated error to be suppressed, got %d writes", writes)
+	}
+
+	// A success clears the error right away.
+	svc.RecordReconcile(ctx, inventory.Name, nil)
+	if status = get(); writes != 3 || status.LastReconcileError != "" {
+		t.Fatalf("expected the error to be cleared, got writes=%d %+v", writes, status)
+	}
+
+	// Past the interval the timestamp is refreshed.
+	clock.SetTime(start.Add(2 * time.Minute))
+	svc.RecordReconcile(ctx, inventory.Name, nil)
+	if status = get(); writes != 4 || !status.LastReconcileTime.Time.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("expected a refreshed timestamp, got writes=%d %+v", writes, status)
+	}
+}
+
+func TestInventoryServiceRecordReconcileIgnoresFailures(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	inventory := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node-stamp-error"}}
+	base := newTestClient(t, scheme, inventory)
+	cl := interceptor.NewClient(base.(client.WithWatch), interceptor.Funcs{
+		SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+			return errors.New("patch failed")
+		},
+	})
+
+	// Neither a failing write nor a missing inventory may panic or surface.
+	NewInventoryService(cl, scheme, nil, nil, nil).RecordReconcile(ctx, inventory.Name, errors.New("boom"))
+	NewInventoryService(base, scheme, nil, nil, nil).RecordReconcile(ctx, "missing-node", nil)
+
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: inventory.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if got.Status.LastReconcileTime != nil {
+		t.Fatalf("expected no stamp after a failed write, got %+v", got.Status)
+	}
+}
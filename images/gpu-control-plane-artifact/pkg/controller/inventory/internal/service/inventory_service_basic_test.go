@@ -16,6 +16,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,6 +26,7 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
 )
 
 func TestInventoryServiceReconcileNoDevicesNoInventory(t *testing.T) {
@@ -32,7 +35,7 @@ func TestInventoryServiceReconcileNoDevicesNoInventory(t *testing.T) {
 	node := newTestNode("node-empty")
 	base := newTestClient(t, scheme, node)
 
-	svc := NewInventoryService(base, scheme, nil)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
 	if err := svc.Reconcile(ctx, node, invstate.NodeSnapshot{}, nil); err != nil {
 		t.Fatalf("Reconcile returned error: %v", err)
 	}
@@ -49,7 +52,7 @@ func TestInventoryServiceReconcileCreatesInventoryAndSetsCondition(t *testing.T)
 	node := newTestNode("node-create-inv")
 	base := newTestClient(t, scheme, node)
 
-	svc := NewInventoryService(base, scheme, newTestRecorderLogger(10))
+	svc := NewInventoryService(base, scheme, newTestRecorderLogger(10), nil, nil)
 	snapshot := invstate.NodeSnapshot{
 		FeatureDetected: true,
 		Devices:         []invstate.DeviceSnapshot{{Index: "0"}},
@@ -87,7 +90,7 @@ func TestInventoryServiceReconcileFeatureMissingAndNoDevicesBranches(t *testing.
 	}
 
 	base := newTestClient(t, scheme, node, inventory)
-	svc := NewInventoryService(base, scheme, newTestRecorderLogger(10))
+	svc := NewInventoryService(base, scheme, newTestRecorderLogger(10), nil, nil)
 
 	t.Run("feature missing", func(t *testing.T) {
 		snap := invstate.NodeSnapshot{FeatureDetected: false, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
@@ -119,3 +122,60 @@ func TestInventoryServiceReconcileFeatureMissingAndNoDevicesBranches(t *testing.
 		}
 	})
 }
+
+func TestInventoryServiceReconcileReportsFieldParseWarnings(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-parse-warnings")
+	base := newTestClient(t, scheme, node)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
+
+	snap := invstate.NodeSnapshot{
+		FeatureDetected: true,
+		Devices:         []invstate.DeviceSnapshot{{Index: "0"}},
+		Warnings: []snapshot.Issue{
+			{Source: "instance/0", Key: "compute.major", Value: "8.0", Reason: "major version: unexpected suffix \".0\""},
+			{Source: "instance/0", Key: "memory.total", Value: "NaN MiB", Reason: "no numeric value in \"NaN MiB\""},
+			{Source: "instance/0", Key: "memory.total", Value: "NaN MiB", Reason: "duplicate"},
+		},
+	}
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	cond := findCondition(got.Status.Conditions, invstate.ConditionFieldParseWarning)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != invstate.ReasonMalformedAttributes {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+	if want := "malformed hardware attributes ignored: instance/0 compute.major, instance/0 memory.total"; cond.Message != want {
+		t.Fatalf("expected message %q, got %q", want, cond.Message)
+	}
+
+	snap.Warnings = nil
+	for i := 0; i < maxReportedParseWarnings+2; i++ {
+		snap.Warnings = append(snap.Warnings, snapshot.Issue{Source: fmt.Sprintf("instance/%d", i), Key: "sm.count", Reason: "not a number"})
+	}
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if cond := findCondition(got.Status.Conditions, invstate.ConditionFieldParseWarning); cond == nil || !strings.HasSuffix(cond.Message, " and 2 more") {
+		t.Fatalf("expected truncated field list, got %+v", cond)
+	}
+
+	snap.Warnings = nil
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if cond := findCondition(got.Status.Conditions, invstate.ConditionFieldParseWarning); cond != nil {
+		t.Fatalf("expected condition to be removed once attributes are valid, got %+v", cond)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+)
+
+func TestInventoryServiceReconcileWithoutConditionPolicy(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-policy-default")
+	base := newTestClient(t, scheme, node)
+	metrics, gatherer := newTestMetrics(t)
+	svc := NewInventoryService(base, scheme, nil, metrics, nil)
+
+	snap := invstate.NodeSnapshot{Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if cond := findCondition(got.Status.Conditions, invstate.ConditionInventoryComplete); cond == nil || cond.Reason != invstate.ReasonNodeFeatureMissing {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+	labels := map[string]string{"node": node.Name, "condition": invstate.ConditionInventoryComplete, "severity": ""}
+	if metric, ok := findMetric(t, gatherer, invmetrics.InventoryConditionMetric, labels); !ok || metric.Gauge.GetValue() != 0 {
+		t.Fatalf("expected the condition gauge without severity, got %+v (present=%t)", metric, ok)
+	}
+}
+
+func TestInventoryServiceReconcileAppliesConditionPolicy(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-policy")
+	node.Labels = map[string]string{"node-role/ingest": "true"}
+	base := newTestClient(t, scheme, node)
+	metrics, gatherer := newTestMetrics(t)
+	svc := NewInventoryService(base, scheme, newTestRecorderLogger(10), metrics, nil)
+	svc.SetConditionPolicy(func() invstate.ConditionPolicy {
+		return invstate.NewConditionPolicy(map[string]moduleconfig.ConditionPolicy{
+			invstate.ConditionInventoryComplete: {
+				Severity:                moduleconfig.ConditionSeverityCritical,
+				SuppressOnNodesMatching: &metav1.LabelSelector{MatchLabels: map[string]string{"node-role/ingest": "true"}},
+			},
+		})
+	})
+	snap := invstate.NodeSnapshot{Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+	metricLabels := map[string]string{"node": node.Name, "condition": invstate.ConditionInventoryComplete}
+
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	cond := findCondition(got.Status.Conditions, invstate.ConditionInventoryComplete)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "SuppressedNodeFeatureMissing" {
+		t.Fatalf("expected the suppressed condition, got %+v", cond)
+	}
+	if _, ok := findMetric(t, gatherer, invmetrics.InventoryConditionMetric, metricLabels); ok {
+		t.Fatalf("expected the suppressed condition to be left out of the gauge")
+	}
+
+	// A repeated reconcile computes the same suppressed condition and writes nothing.
+	resourceVersion := got.ResourceVersion
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if got.ResourceVersion != resourceVersion {
+		t.Fatalf("expected no status write for an unchanged suppressed condition")
+	}
+
+	node.Labels = nil
+	if err := svc.Reconcile(ctx, node, snap, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if cond := findCondition(got.Status.Conditions, invstate.ConditionInventoryComplete); cond == nil || cond.Reason != invstate.ReasonNodeFeatureMissing {
+		t.Fatalf("expected the plain reason once the node no longer matches, got %+v", cond)
+	}
+	metricLabels["severity"] = "Critical"
+	if metric, ok := findMetric(t, gatherer, invmetrics.InventoryConditionMetric, metricLabels); !ok || metric.Gauge.GetValue() != 0 {
+		t.Fatalf("expected the condition gauge with severity, got %+v (present=%t)", metric, ok)
+	}
+}
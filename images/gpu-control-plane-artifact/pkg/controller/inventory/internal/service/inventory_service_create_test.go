@@ -44,7 +44,7 @@ func TestInventoryServiceReconcileCreateAndErrorBranches(t *testing.T) {
 				return boom
 			},
 		}
-		svc := NewInventoryService(cl, scheme, nil)
+		svc := NewInventoryService(cl, scheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, snapshot, devices); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -53,7 +53,7 @@ func TestInventoryServiceReconcileCreateAndErrorBranches(t *testing.T) {
 	t.Run("ownerref error", func(t *testing.T) {
 		base := newTestClient(t, scheme, node)
 		badScheme := runtime.NewScheme()
-		svc := NewInventoryService(base, badScheme, nil)
+		svc := NewInventoryService(base, badScheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, snapshot, devices); err == nil {
 			t.Fatalf("expected owner reference error")
 		}
@@ -68,7 +68,7 @@ func TestInventoryServiceReconcileCreateAndErrorBranches(t *testing.T) {
 				return boom
 			},
 		}
-		svc := NewInventoryService(cl, scheme, nil)
+		svc := NewInventoryService(cl, scheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, snapshot, devices); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -78,7 +78,7 @@ func TestInventoryServiceReconcileCreateAndErrorBranches(t *testing.T) {
 		inv := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: node.Name}, Spec: v1alpha1.GPUNodeStateSpec{NodeName: node.Name}}
 		base := newTestClient(t, scheme, node, inv)
 		badScheme := runtime.NewScheme()
-		svc := NewInventoryService(base, badScheme, nil)
+		svc := NewInventoryService(base, badScheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, snapshot, devices); err == nil {
 			t.Fatalf("expected owner reference error")
 		}
@@ -96,13 +96,13 @@ func TestInventoryServiceReconcileCreateAndErrorBranches(t *testing.T) {
 			Client: base,
 			status: hookStatusWriter{
 				base: base.Status(),
-				update: func(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+				patch: func(context.Context, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
 					return boom
 				},
 			},
 		}
 
-		svc := NewInventoryService(cl, scheme, nil)
+		svc := NewInventoryService(cl, scheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, snapshot, devices); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -0,0 +1,82 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestInventoryServiceMarkDrainingAndResume(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-drain")
+	inventory := &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		Spec:       v1alpha1.GPUNodeStateSpec{NodeName: node.Name},
+		Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{{
+			Type:   invstate.ConditionDeletionsThrottled,
+			Status: metav1.ConditionTrue,
+			Reason: invstate.ReasonDeletionLimitReached,
+		}}},
+	}
+	base := newTestClient(t, scheme, node, inventory)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
+
+	if err := svc.MarkDraining(ctx, node, invstate.ReasonAutoscalerScaleDown); err != nil {
+		t.Fatalf("MarkDraining returned error: %v", err)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	cond := findCondition(got.Status.Conditions, invstate.ConditionNodeDraining)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != invstate.ReasonAutoscalerScaleDown {
+		t.Fatalf("unexpected draining condition: %+v", cond)
+	}
+
+	snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+	if err := svc.Reconcile(ctx, node, snapshot, []*v1alpha1.GPUDevice{{}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if cond := findCondition(got.Status.Conditions, invstate.ConditionNodeDraining); cond != nil {
+		t.Fatalf("expected draining condition to be cleared on resume, got %+v", cond)
+	}
+	if cond := findCondition(got.Status.Conditions, invstate.ConditionDeletionsThrottled); cond != nil {
+		t.Fatalf("expected throttled condition to be cleared on resume, got %+v", cond)
+	}
+}
+
+func TestInventoryServiceMarkDrainingWithoutInventory(t *testing.T) {
+	scheme := newTestScheme(t)
+	node := newTestNode("node-drain-missing")
+	base := newTestClient(t, scheme, node)
+
+	if err := NewInventoryService(base, scheme, nil, nil, nil).MarkDraining(context.Background(), node, invstate.ReasonNodeDeleting); err != nil {
+		t.Fatalf("MarkDraining returned error: %v", err)
+	}
+	if err := base.Get(context.Background(), types.NamespacedName{Name: node.Name}, &v1alpha1.GPUNodeState{}); err == nil {
+		t.Fatalf("expected inventory to not be created for a draining node")
+	}
+}
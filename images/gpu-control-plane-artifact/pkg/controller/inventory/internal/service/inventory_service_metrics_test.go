@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 )
 
 func TestInventoryServiceUpdateDeviceMetrics(t *testing.T) {
@@ -28,3 +29,25 @@ func TestInventoryServiceUpdateDeviceMetrics(t *testing.T) {
 		{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateReady}},
 	})
 }
+
+func TestInventoryServiceUnallocatedDevicesMetric(t *testing.T) {
+	metrics, gatherer := newTestMetrics(t)
+	svc := &InventoryService{metrics: metrics}
+	nodeName := "node-unallocated"
+	svc.UpdateDeviceMetrics(nodeName, []*v1alpha1.GPUDevice{
+		{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateReady}},
+		{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateDiscovered}},
+		{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateFaulted}},
+		{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateAssigned, PoolRef: &v1alpha1.GPUPoolReference{Name: "pool"}}},
+	})
+
+	metric, ok := findMetric(t, gatherer, invmetrics.InventoryDevicesUnallocated, map[string]string{"node": nodeName})
+	if !ok || metric.Gauge == nil || metric.Gauge.GetValue() != 2 {
+		t.Fatalf("expected 2 unallocated devices, got %+v (present=%t)", metric, ok)
+	}
+
+	NewCleanupService(nil, nil, nil, metrics, nil).ClearMetrics(nodeName)
+	if _, ok := findMetric(t, gatherer, invmetrics.InventoryDevicesUnallocated, map[string]string{"node": nodeName}); ok {
+		t.Fatalf("expected unallocated devices metric to be deleted with the node")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestInventoryServiceMarkNodeFeatureAPIUnsupported(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-nfd")
+	inventory := &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		Spec:       v1alpha1.GPUNodeStateSpec{NodeName: node.Name},
+		Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{{
+			Type:   invstate.ConditionInventoryComplete,
+			Status: metav1.ConditionTrue,
+			Reason: invstate.ReasonInventorySynced,
+		}}},
+	}
+	base := newTestClient(t, scheme, node, inventory)
+	svc := NewInventoryService(base, scheme, nil, nil, nil)
+
+	if err := svc.MarkNodeFeatureAPIUnsupported(ctx, node, "v1alpha1 is not served"); err != nil {
+		t.Fatalf("MarkNodeFeatureAPIUnsupported returned error: %v", err)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	cond := findCondition(got.Status.Conditions, invstate.ConditionInventoryComplete)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != invstate.ReasonDiscoveryAPIUnsupported || cond.Message != "v1alpha1 is not served" {
+		t.Fatalf("unexpected inventory condition: %+v", cond)
+	}
+
+	missing := newTestNode("node-nfd-missing")
+	if err := NewInventoryService(newTestClient(t, scheme, missing), scheme, nil, nil, nil).MarkNodeFeatureAPIUnsupported(ctx, missing, "x"); err != nil {
+		t.Fatalf("expected nodes without inventory to be skipped, got %v", err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,13 +37,18 @@ func TestInventoryServiceReconcileSkipsPatchWhenUnchanged(t *testing.T) {
 	inventory := &v1alpha1.GPUNodeState{
 		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
 		Spec:       v1alpha1.GPUNodeStateSpec{NodeName: node.Name},
-		Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{{
-			Type:               invstate.ConditionInventoryComplete,
-			Status:             metav1.ConditionTrue,
-			Reason:             invstate.ReasonInventorySynced,
-			Message:            "inventory data collected",
-			ObservedGeneration: 0,
-		}}},
+		Status: v1alpha1.GPUNodeStateStatus{
+			Conditions: []metav1.Condition{{
+				Type:               invstate.ConditionInventoryComplete,
+				Status:             metav1.ConditionTrue,
+				Reason:             invstate.ReasonInventorySynced,
+				Message:            "inventory data collected",
+				ObservedGeneration: 0,
+			}},
+			TopologySummary: &v1alpha1.GPUNodeTopologySummary{
+				Devices: []string{"? unknown 0MiB mig=n/a state=Discovered [unmanaged]"},
+			},
+		},
 	}
 	if err := controllerutil.SetOwnerReference(node, inventory, scheme); err != nil {
 		t.Fatalf("set owner: %v", err)
@@ -59,7 +65,7 @@ func TestInventoryServiceReconcileSkipsPatchWhenUnchanged(t *testing.T) {
 		},
 	}
 
-	svc := NewInventoryService(cl, scheme, nil)
+	svc := NewInventoryService(cl, scheme, nil, nil, nil)
 	snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
 	if err := svc.Reconcile(ctx, node, snapshot, []*v1alpha1.GPUDevice{{}}); err != nil {
 		t.Fatalf("expected no status patch, got %v", err)
@@ -78,7 +84,7 @@ func TestInventoryServiceReconcileSpecPatchAndErrors(t *testing.T) {
 		}
 		base := newTestClient(t, scheme, node, inventory)
 
-		svc := NewInventoryService(base, scheme, nil)
+		svc := NewInventoryService(base, scheme, nil, nil, nil)
 		snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
 		if err := svc.Reconcile(ctx, node, snapshot, []*v1alpha1.GPUDevice{{}}); err != nil {
 			t.Fatalf("Reconcile returned error: %v", err)
@@ -106,7 +112,7 @@ func TestInventoryServiceReconcileSpecPatchAndErrors(t *testing.T) {
 				return boom
 			},
 		}
-		svc := NewInventoryService(cl, scheme, nil)
+		svc := NewInventoryService(cl, scheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}, []*v1alpha1.GPUDevice{{}}); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
@@ -131,10 +137,32 @@ func TestInventoryServiceReconcileSpecPatchAndErrors(t *testing.T) {
 				return boom
 			},
 		}
-		svc := NewInventoryService(cl, scheme, nil)
+		svc := NewInventoryService(cl, scheme, nil, nil, nil)
 		if err := svc.Reconcile(ctx, node, invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}, []*v1alpha1.GPUDevice{{}}); !errors.Is(err, boom) {
 			t.Fatalf("expected error %v, got %v", boom, err)
 		}
 	})
 
 }
+
+func TestNodeStateStatusEqualIgnoresConditionOrderAndTransitionTime(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1700000000, 0))
+	complete := metav1.Condition{Type: invstate.ConditionInventoryComplete, Status: metav1.ConditionTrue, Reason: invstate.ReasonInventorySynced, LastTransitionTime: now}
+	warning := metav1.Condition{Type: invstate.ConditionFieldParseWarning, Status: metav1.ConditionTrue, Reason: "Malformed", LastTransitionTime: now}
+	current := v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{complete, warning}, LastReconcileTime: &now}
+
+	restamped := warning
+	restamped.LastTransitionTime = metav1.NewTime(now.Add(time.Minute))
+	desired := v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{restamped, complete}, LastReconcileTime: &now}
+	if !nodeStateStatusEqual(current, desired) {
+		t.Fatal("expected reordered and re-stamped conditions to compare equal")
+	}
+	if len(desired.Conditions) != 2 || desired.Conditions[0].Type != invstate.ConditionFieldParseWarning {
+		t.Fatalf("comparison must not modify the statuses, got %+v", desired.Conditions)
+	}
+
+	desired.LastReconcileError = "boom"
+	if nodeStateStatusEqual(current, desired) {
+		t.Fatal("expected a differing non-condition field to be detected")
+	}
+}
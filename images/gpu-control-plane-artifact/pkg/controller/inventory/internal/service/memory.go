@@ -0,0 +1,79 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// memorySourceNodeFeature names the NodeFeature labels and instance attributes the snapshot parsed memory from.
+const memorySourceNodeFeature detection.MemorySource = "nodeFeature"
+
+// resolveMemory picks the memory size of a device from the detection entry and the NodeFeature value. The
+// detection byte totals come first, then the detection memoryMiB, then NodeFeature: the precision drops in
+// that order. conflict lists every reading when any of them disagrees with the chosen size, so a unit mix-up
+// on one source is reported instead of silently losing to another.
+func resolveMemory(nodeFeatureMiB int32, entry detection.Device) (size detection.MiB, conflict string) {
+	readings := entry.MemoryReadings()
+	if nodeFeatureMiB > 0 {
+		readings = append(readings, detection.MemoryReading{Source: memorySourceNodeFeature, Size: detection.MiB(nodeFeatureMiB)})
+	}
+	if len(readings) == 0 {
+		return 0, ""
+	}
+
+	size = readings[0].Size
+	disagree := false
+	for _, reading := range readings[1:] {
+		if detection.MemoryDisagrees(size, reading.Size) {
+			disagree = true
+			break
+		}
+	}
+	if !disagree {
+		return size, ""
+	}
+
+	parts := make([]string, 0, len(readings))
+	for _, reading := range readings {
+		parts = append(parts, fmt.Sprintf("%s=%dMiB", reading.Source, reading.Size))
+	}
+	return size, fmt.Sprintf("memory sources differ by more than %d%%: %s; using %s",
+		detection.MemoryTolerancePercent, strings.Join(parts, ", "), readings[0].Source)
+}
+
+// applyMemory writes the resolved memory size into the device hardware and sets or drops MemoryDataConflict.
+func applyMemory(device *v1alpha1.GPUDevice, nodeFeatureMiB int32, entry detection.Device) {
+	size, conflict := resolveMemory(nodeFeatureMiB, entry)
+	device.Status.Hardware.MemoryMiB = int32(size)
+	if conflict == "" {
+		apimeta.RemoveStatusCondition(&device.Status.Conditions, invstate.ConditionMemoryDataConflict)
+		return
+	}
+	conditions.SetCondition(conditions.NewConditionBuilder(conditions.ConditionType(invstate.ConditionMemoryDataConflict)).
+		Status(metav1.ConditionTrue).
+		Reason(conditions.CommonReason(invstate.ReasonMemorySizeMismatch)).
+		Message(conflict).
+		Generation(device.Generation), &device.Status.Conditions)
+}
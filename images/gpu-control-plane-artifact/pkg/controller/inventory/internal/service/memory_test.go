@@ -0,0 +1,115 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+func TestResolveMemorySourceShapes(t *testing.T) {
+	nodeFeature, err := snapshot.ParseMemoryMiB("40536 MiB")
+	if err != nil {
+		t.Fatalf("parse NodeFeature memory: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		nodeFeature int32
+		entry       detection.Device
+		want        detection.MiB
+	}{
+		{name: "NodeFeature string only", nodeFeature: nodeFeature, want: 40536},
+		{name: "detection memoryMiB only", entry: detection.Device{MemoryMiB: 1024}, want: 1024},
+		{name: "detection bytes only", entry: detection.Device{MemoryInfo: detection.MemoryInfo{Total: 1073741824}}, want: 1024},
+		{
+			name:  "fixture bytes and memoryMiB agree",
+			entry: detection.Device{MemoryInfo: detection.MemoryInfo{Total: 1073741824}, MemoryMiB: 1024},
+			want:  1024,
+		},
+		{
+			name:        "bytes win over a rounded NodeFeature value",
+			nodeFeature: 40960,
+			entry:       detection.Device{MemoryInfoV2: detection.MemoryInfoV2{Total: 42505273344}},
+			want:        40536,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, conflict := resolveMemory(tc.nodeFeature, tc.entry)
+			if got != tc.want || conflict != "" {
+				t.Fatalf("resolveMemory() = %d, %q; want %d without conflict", got, conflict, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyMemoryFlagsDisagreeingSources(t *testing.T) {
+	device := &v1alpha1.GPUDevice{}
+	// memoryMiB carries the byte count scaled once too few: the sources differ by 1024x.
+	entry := detection.Device{MemoryInfo: detection.MemoryInfo{Total: 1073741824}, MemoryMiB: 1024 * 1024}
+
+	applyMemory(device, 1024, entry)
+
+	if device.Status.Hardware.MemoryMiB != 1024 {
+		t.Fatalf("expected the byte total to win, got %d", device.Status.Hardware.MemoryMiB)
+	}
+	cond := apimeta.FindStatusCondition(device.Status.Conditions, invstate.ConditionMemoryDataConflict)
+	if cond == nil || cond.Reason != invstate.ReasonMemorySizeMismatch {
+		t.Fatalf("expected MemoryDataConflict condition, got %+v", device.Status.Conditions)
+	}
+	for _, want := range []string{"memoryInfo.Total=1024MiB", "memoryMiB=1048576MiB", "nodeFeature=1024MiB", "using memoryInfo.Total"} {
+		if !strings.Contains(cond.Message, want) {
+			t.Fatalf("condition message %q lacks %q", cond.Message, want)
+		}
+	}
+
+	applyMemory(device, 1024, detection.Device{MemoryInfo: detection.MemoryInfo{Total: 1073741824}, MemoryMiB: 1024})
+	if apimeta.FindStatusCondition(device.Status.Conditions, invstate.ConditionMemoryDataConflict) != nil {
+		t.Fatalf("condition must be dropped once the sources agree")
+	}
+}
+
+func TestApplyDetectionFlagsNodeFeatureDisagreement(t *testing.T) {
+	device := &v1alpha1.GPUDevice{}
+	snap := invstate.DeviceSnapshot{Index: "0", MemoryMiB: 40536}
+	detections := NodeDetection{byIndex: map[string]detection.Device{
+		"0": {Index: 0, MemoryInfo: detection.MemoryInfo{Total: 85899345920}},
+	}}
+
+	ApplyDetection(device, snap, detections)
+
+	if device.Status.Hardware.MemoryMiB != 81920 {
+		t.Fatalf("expected detection memory, got %d", device.Status.Hardware.MemoryMiB)
+	}
+	if apimeta.FindStatusCondition(device.Status.Conditions, invstate.ConditionMemoryDataConflict) == nil {
+		t.Fatalf("expected MemoryDataConflict for a 2x NodeFeature mismatch")
+	}
+
+	ApplyDetection(device, snap, NodeDetection{})
+	if device.Status.Hardware.MemoryMiB != 40536 {
+		t.Fatalf("expected NodeFeature memory without detection, got %d", device.Status.Hardware.MemoryMiB)
+	}
+	if apimeta.FindStatusCondition(device.Status.Conditions, invstate.ConditionMemoryDataConflict) != nil {
+		t.Fatalf("condition must be dropped without a second source")
+	}
+}
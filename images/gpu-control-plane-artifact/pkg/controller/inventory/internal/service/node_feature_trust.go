@@ -0,0 +1,46 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// ReportUntrustedNodeFeatures logs the NodeFeatures FindNodeFeature ignored for the node and records a single
+// warning event listing them, so a spoofing attempt is visible without flooding the node's events.
+func (s *InventoryService) ReportUntrustedNodeFeatures(ctx context.Context, node *corev1.Node, skipped []string) {
+	if len(skipped) == 0 {
+		return
+	}
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("ignoring NodeFeatures outside inventory.trustedNodeFeatureNamespaces", "nodeFeatures", skipped)
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.WithLogging(log).Eventf(
+		node,
+		corev1.EventTypeWarning,
+		invstate.EventUntrustedNodeFeature,
+		"ignored NodeFeatures labelled for node %s outside the trusted namespaces: %s",
+		node.Name,
+		strings.Join(skipped, ", "),
+	)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+func TestReportUntrustedNodeFeaturesRecordsSingleWarning(t *testing.T) {
+	rec, recorder := newTestRecorder(4)
+	svc := NewInventoryService(nil, nil, recorder, nil, nil)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-spoofed"}}
+
+	svc.ReportUntrustedNodeFeatures(context.Background(), node, nil)
+	svc.ReportUntrustedNodeFeatures(context.Background(), node, []string{"tenant-a/worker-spoofed", "tenant-b/fake"})
+
+	if len(rec.Events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(rec.Events))
+	}
+	event := <-rec.Events
+	if !strings.Contains(event, corev1.EventTypeWarning) || !strings.Contains(event, invstate.EventUntrustedNodeFeature) {
+		t.Fatalf("unexpected event: %s", event)
+	}
+	if !strings.Contains(event, "tenant-a/worker-spoofed, tenant-b/fake") {
+		t.Fatalf("event must list the skipped NodeFeatures: %s", event)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// Policies is the set of inventory policies derived from one moduleconfig.State.
+type Policies struct {
+	Managed  invstate.ManagedNodesPolicy
+	Approval invstate.DeviceApprovalPolicy
+	// TrustedNodeFeatureNamespaces selects the NodeFeatures the node snapshots are built from.
+	TrustedNodeFeatureNamespaces []string
+}
+
+// ImpactReport describes what the inventory controller would change if the proposed policies replaced
+// the current ones. It only covers existing objects: devices that are not created yet are not listed.
+type ImpactReport struct {
+	ManagedNodesChanged int            `json:"managedNodesChanged"`
+	AutoAttachChanged   int            `json:"autoAttachChanged"`
+	Nodes               []NodeImpact   `json:"nodes"`
+	Devices             []DeviceImpact `json:"devices"`
+	Pools               []PoolImpact   `json:"pools"`
+}
+
+// NodeImpact is a node whose managed flag would flip.
+type NodeImpact struct {
+	Name    string `json:"name"`
+	Managed Change `json:"managed"`
+}
+
+// DeviceImpact is a GPUDevice whose managed or autoAttach status would change.
+type DeviceImpact struct {
+	Name       string  `json:"name"`
+	Node       string  `json:"node"`
+	Pool       string  `json:"pool,omitempty"`
+	Managed    *Change `json:"managed,omitempty"`
+	AutoAttach *Change `json:"autoAttach,omitempty"`
+}
+
+// PoolImpact is a pool that holds affected devices; the capacity it can actually serve follows them.
+type PoolImpact struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Devices   int    `json:"devices"`
+}
+
+// Change is a boolean transition.
+type Change struct {
+	From bool `json:"from"`
+	To   bool `json:"to"`
+}
+
+// PreviewImpact evaluates both policy sets against the objects visible through the client, using the
+// same node snapshot and approval code as the reconcile path, and writes nothing. Draining nodes are
+// skipped because the inventory handler keeps their last known device states.
+func PreviewImpact(ctx context.Context, c client.Client, current, proposed Policies) (ImpactReport, error) {
+	report := ImpactReport{Nodes: []NodeImpact{}, Devices: []DeviceImpact{}, Pools: []PoolImpact{}}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		return report, err
+	}
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := c.List(ctx, devices); err != nil {
+		return report, err
+	}
+	byInventoryID := make(map[string]*v1alpha1.GPUDevice, len(devices.Items))
+	for i := range devices.Items {
+		if id := devices.Items[i].Status.InventoryID; id != "" {
+			byInventoryID[id] = &devices.Items[i]
+		}
+	}
+
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+	pools := map[PoolImpact]int{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if _, draining := invstate.NodeDrainReason(node); draining {
+			continue
+		}
+		feature, _, err := invstate.FindNodeFeature(ctx, c, node.Name, current.TrustedNodeFeatureNamespaces)
+		if err != nil {
+			return report, err
+		}
+		proposedFeature := feature
+		if !slices.Equal(current.TrustedNodeFeatureNamespaces, proposed.TrustedNodeFeatureNamespaces) {
+			if proposedFeature, _, err = invstate.FindNodeFeature(ctx, c, node.Name, proposed.TrustedNodeFeatureNamespaces); err != nil {
+				return report, err
+			}
+		}
+		before := invstate.BuildNodeSnapshot(node, feature, current.Managed)
+		after := invstate.BuildNodeSnapshot(node, proposedFeature, proposed.Managed)
+		if !before.FeatureDetected && len(before.Devices) == 0 {
+			continue
+		}
+		if before.Managed != after.Managed {
+			report.Nodes = append(report.Nodes, NodeImpact{Name: node.Name, Managed: Change{From: before.Managed, To: after.Managed}})
+		}
+
+		for _, snapshot := range after.Devices {
+			device := byInventoryID[invstate.BuildInventoryID(node.Name, snapshot)]
+			if device == nil {
+				continue
+			}
+			impact := DeviceImpact{Name: device.Name, Node: node.Name}
+			if before.Managed != after.Managed {
+				impact.Managed = &Change{From: before.Managed, To: after.Managed}
+			}
+			autoBefore := current.Approval.AutoAttach(before.Managed, invstate.LabelsForDevice(snapshot, before.Labels))
+			autoAfter := proposed.Approval.AutoAttach(after.Managed, invstate.LabelsForDevice(snapshot, after.Labels))
+			if autoBefore != autoAfter {
+				impact.AutoAttach = &Change{From: autoBefore, To: autoAfter}
+				report.AutoAttachChanged++
+			}
+			if impact.Managed == nil && impact.AutoAttach == nil {
+				continue
+			}
+			if ref := device.Status.PoolRef; ref != nil && ref.Name != "" {
+				impact.Pool = ref.Name
+				pools[PoolImpact{Name: ref.Name, Namespace: ref.Namespace}]++
+			}
+			report.Devices = append(report.Devices, impact)
+		}
+	}
+	report.ManagedNodesChanged = len(report.Nodes)
+
+	for pool, count := range pools {
+		pool.Devices = count
+		report.Pools = append(report.Pools, pool)
+	}
+	sort.Slice(report.Pools, func(i, j int) bool {
+		if report.Pools[i].Namespace != report.Pools[j].Namespace {
+			return report.Pools[i].Namespace < report.Pools[j].Namespace
+		}
+		return report.Pools[i].Name < report.Pools[j].Name
+	})
+	return report, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func newPreviewNode(name string, extra map[string]string) *corev1.Node {
+	nodeLabels := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "10de",
+		"gpu.deckhouse.io/device.00.device": "20b0",
+		"gpu.deckhouse.io/device.00.class":  "0302",
+		"gpu.deckhouse.io/device.01.vendor": "10de",
+		"gpu.deckhouse.io/device.01.device": "20b0",
+		"gpu.deckhouse.io/device.01.class":  "0302",
+	}
+	for k, v := range extra {
+		nodeLabels[k] = v
+	}
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name), Labels: nodeLabels}}
+}
+
+func newPreviewScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := newTestScheme(t)
+	if err := nfdv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add nfd scheme: %v", err)
+	}
+	scheme.AddKnownTypes(nfdv1alpha1.SchemeGroupVersion, &nfdv1alpha1.NodeFeatureList{})
+	return scheme
+}
+
+type deviceFlags struct {
+	Managed    bool
+	AutoAttach bool
+}
+
+// applyPolicies runs the real device reconcile path for every node and returns the resulting flags.
+func applyPolicies(t *testing.T, ctx context.Context, c client.Client, policies Policies) map[string]deviceFlags {
+	t.Helper()
+	svc := NewDeviceService(c, c.Scheme(), nil, nil, nil, nil)
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		t.Fatalf("list nodes: %v", err)
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if _, draining := invstate.NodeDrainReason(node); draining {
+			continue
+		}
+		snapshot := invstate.BuildNodeSnapshot(node, nil, policies.Managed)
+		if _, _, err := svc.ReconcileNode(ctx, node, snapshot.Devices, snapshot.Labels, snapshot.Managed, policies.Approval, nil, nil); err != nil {
+			t.Fatalf("reconcile node %s: %v", node.Name, err)
+		}
+	}
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := c.List(ctx, devices); err != nil {
+		t.Fatalf("list devices: %v", err)
+	}
+	out := make(map[string]deviceFlags, len(devices.Items))
+	for _, device := range devices.Items {
+		out[device.Name] = deviceFlags{Managed: device.Status.Managed, AutoAttach: device.Status.AutoAttach}
+	}
+	return out
+}
+
+func TestPreviewImpactMatchesAppliedChange(t *testing.T) {
+	ctx := context.Background()
+	scheme := newPreviewScheme(t)
+	draining := newPreviewNode("node-draining", nil)
+	draining.Spec.Taints = []corev1.Taint{{Key: invstate.ToBeDeletedByAutoscalerKey, Effect: corev1.TaintEffectNoSchedule}}
+	cl := newTestClient(t, scheme,
+		newPreviewNode("node-labeled", map[string]string{invstate.DefaultManagedNodeLabelKey: "true"}),
+		newPreviewNode("node-default", nil),
+		draining,
+	)
+
+	selector, err := labels.Parse("gpu.deckhouse.io/device.index=0")
+	if err != nil {
+		t.Fatalf("parse selector: %v", err)
+	}
+	current := Policies{
+		Managed:  invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey},
+		Approval: invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeManual},
+	}
+	proposed := Policies{
+		Managed:  invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey, EnabledByDefault: true},
+		Approval: invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeSelector, Selector: selector},
+	}
+
+	before := applyPolicies(t, ctx, cl, current)
+	poolDevice := invstate.BuildDeviceName("node-default", invstate.DeviceSnapshot{Index: "0", Vendor: "10de", Device: "20b0"})
+	device := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: poolDevice}, device); err != nil {
+		t.Fatalf("get %s: %v", poolDevice, err)
+	}
+	device.Status.PoolRef = &v1alpha1.GPUPoolReference{Name: "training", Namespace: "ml"}
+	if err := cl.Status().Update(ctx, device); err != nil {
+		t.Fatalf("assign pool: %v", err)
+	}
+
+	report, err := PreviewImpact(ctx, cl, current, proposed)
+	if err != nil {
+		t.Fatalf("PreviewImpact returned error: %v", err)
+	}
+	after := applyPolicies(t, ctx, cl, proposed)
+
+	applied := map[string]DeviceImpact{}
+	for name, was := range before {
+		now := after[name]
+		if was == now {
+			continue
+		}
+		impact := DeviceImpact{Name: name}
+		if was.Managed != now.Managed {
+			impact.Managed = &Change{From: was.Managed, To: now.Managed}
+		}
+		if was.AutoAttach != now.AutoAttach {
+			impact.AutoAttach = &Change{From: was.AutoAttach, To: now.AutoAttach}
+		}
+		applied[name] = impact
+	}
+	previewed := map[string]DeviceImpact{}
+	for _, impact := range report.Devices {
+		impact.Node, impact.Pool = "", ""
+		previewed[impact.Name] = impact
+	}
+	if !reflect.DeepEqual(previewed, applied) {
+		t.Fatalf("preview diverged from applied change:\npreview: %+v\napplied: %+v", previewed, applied)
+	}
+
+	if report.ManagedNodesChanged != 1 || report.Nodes[0].Name != "node-default" {
+		t.Fatalf("expected only node-default to flip, got %+v", report.Nodes)
+	}
+	if report.AutoAttachChanged != 2 {
+		t.Fatalf("expected two autoAttach changes, got %d", report.AutoAttachChanged)
+	}
+	if len(report.Pools) != 1 || report.Pools[0] != (PoolImpact{Name: "training", Namespace: "ml", Devices: 1}) {
+		t.Fatalf("unexpected pool impact: %+v", report.Pools)
+	}
+}
+
+func TestPreviewImpactNoChange(t *testing.T) {
+	ctx := context.Background()
+	scheme := newPreviewScheme(t)
+	cl := newTestClient(t, scheme, newPreviewNode("node-a", map[string]string{invstate.DefaultManagedNodeLabelKey: "true"}))
+	policies := Policies{
+		Managed:  invstate.ManagedNodesPolicy{LabelKey: invstate.DefaultManagedNodeLabelKey},
+		Approval: invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic},
+	}
+	applyPolicies(t, ctx, cl, policies)
+
+	report, err := PreviewImpact(ctx, cl, policies, policies)
+	if err != nil {
+		t.Fatalf("PreviewImpact returned error: %v", err)
+	}
+	if report.ManagedNodesChanged != 0 || report.AutoAttachChanged != 0 || len(report.Devices) != 0 || len(report.Pools) != 0 {
+		t.Fatalf("expected empty report, got %+v", report)
+	}
+}
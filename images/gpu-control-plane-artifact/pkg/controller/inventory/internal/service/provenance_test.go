@@ -0,0 +1,140 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func testFeatureSource(resourceVersion string, generation int64) *v1alpha1.GPUDataProvenance {
+	return &v1alpha1.GPUDataProvenance{NodeFeature: "gpu-operator/worker", ResourceVersion: resourceVersion, Generation: generation}
+}
+
+func TestDeviceProvenanceFollowsDataChanges(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-provenance")
+	snapshot := newTestSnapshot()
+	approval := invstate.DeviceApprovalPolicy{Mode: moduleconfig.DeviceApprovalModeAutomatic}
+	cl := newTestClient(t, scheme, node)
+	svc := NewDeviceService(cl, scheme, newTestRecorderLogger(8), nil, nil, nil)
+
+	devices, _, err := svc.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{snapshot}, nil, true, approval, testFeatureSource("10", 1), nil)
+	if err != nil || len(devices) != 1 {
+		t.Fatalf("create reconcile: devices=%d err=%v", len(devices), err)
+	}
+	key := types.NamespacedName{Name: devices[0].Name}
+	created := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, key, created); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	prov := created.Status.Provenance
+	if prov == nil || prov.NodeFeature != "gpu-operator/worker" || prov.ResourceVersion != "10" || prov.Generation != 1 || prov.CapturedAt.IsZero() {
+		t.Fatalf("expected provenance from the creating snapshot, got %+v", prov)
+	}
+	// Shift the stored capture time so a re-stamp within the same second is still visible.
+	stored := metav1.NewTime(prov.CapturedAt.Add(-time.Hour))
+	created.Status.Provenance.CapturedAt = stored
+	if err := cl.Status().Update(ctx, created); err != nil {
+		t.Fatalf("age provenance: %v", err)
+	}
+
+	// A newer NodeFeature revision carrying the same data is a no-op.
+	if _, _, err := svc.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{snapshot}, nil, true, approval, testFeatureSource("11", 1), nil); err != nil {
+		t.Fatalf("no-op reconcile: %v", err)
+	}
+	unchanged := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, key, unchanged); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if prov := unchanged.Status.Provenance; prov == nil || prov.ResourceVersion != "10" || !prov.CapturedAt.Equal(&stored) {
+		t.Fatalf("expected provenance to stay on the first revision, got %+v", prov)
+	}
+
+	snapshot.Product = "NVIDIA H100"
+	if _, _, err := svc.ReconcileNode(ctx, node, []invstate.DeviceSnapshot{snapshot}, nil, true, approval, testFeatureSource("12", 2), nil); err != nil {
+		t.Fatalf("data change reconcile: %v", err)
+	}
+	updated := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, key, updated); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if prov := updated.Status.Provenance; prov == nil || prov.ResourceVersion != "12" || prov.Generation != 2 || !prov.CapturedAt.After(stored.Time) {
+		t.Fatalf("expected provenance from the changing revision, got %+v", prov)
+	}
+}
+
+func TestNodeStateProvenanceFollowsDataChanges(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-state-provenance")
+	inv := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: node.Name}, Spec: v1alpha1.GPUNodeStateSpec{NodeName: node.Name}}
+	if err := controllerutil.SetOwnerReference(node, inv, scheme); err != nil {
+		t.Fatalf("set owner: %v", err)
+	}
+	cl := newTestClient(t, scheme, node, inv)
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(start)
+	svc := NewInventoryService(cl, scheme, nil, nil, nil)
+	svc.SetClock(clock)
+	devices := []*v1alpha1.GPUDevice{{}}
+
+	fetch := func() *v1alpha1.GPUDataProvenance {
+		t.Helper()
+		got := &v1alpha1.GPUNodeState{}
+		if err := cl.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+			t.Fatalf("get node state: %v", err)
+		}
+		return got.Status.Provenance
+	}
+
+	snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}, Provenance: testFeatureSource("20", 3)}
+	if err := svc.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("first reconcile: %v", err)
+	}
+	if prov := fetch(); prov == nil || prov.ResourceVersion != "20" || prov.Generation != 3 || !prov.CapturedAt.Time.Equal(start) {
+		t.Fatalf("expected provenance from the first snapshot, got %+v", prov)
+	}
+
+	clock.SetTime(start.Add(time.Minute))
+	snapshot.Provenance = testFeatureSource("21", 3)
+	if err := svc.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("no-op reconcile: %v", err)
+	}
+	if prov := fetch(); prov == nil || prov.ResourceVersion != "20" || !prov.CapturedAt.Time.Equal(start) {
+		t.Fatalf("expected provenance untouched by a no-op reconcile, got %+v", prov)
+	}
+
+	clock.SetTime(start.Add(2 * time.Minute))
+	snapshot.Devices = nil
+	snapshot.Provenance = testFeatureSource("22", 4)
+	if err := svc.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("data change reconcile: %v", err)
+	}
+	if prov := fetch(); prov == nil || prov.ResourceVersion != "22" || prov.Generation != 4 || !prov.CapturedAt.Time.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("expected provenance from the changing snapshot, got %+v", prov)
+	}
+}
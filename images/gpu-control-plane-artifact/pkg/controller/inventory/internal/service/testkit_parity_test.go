@@ -0,0 +1,103 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/contracts/testkit"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+// recordingHandler appends its name to a shared log and tags the device, so both runners can be compared.
+type recordingHandler struct {
+	name   string
+	ran    *[]string
+	result reconcile.Result
+	err    error
+}
+
+func (h recordingHandler) Name() string { return h.name }
+
+func (h recordingHandler) HandleDevice(_ context.Context, device *v1alpha1.GPUDevice) (reconcile.Result, error) {
+	*h.ran = append(*h.ran, h.name)
+	device.Labels["example.com/"+h.name] = "seen"
+	return h.result, h.err
+}
+
+// TestTestkitHarnessMatchesDeviceService keeps the public testkit honest: every chain must behave the same
+// through the harness and through the device service the controller runs.
+func TestTestkitHarnessMatchesDeviceService(t *testing.T) {
+	boom := errors.New("boom")
+	deferred := fmt.Errorf("scrape: %w", commonerrors.ErrTelemetryUnavailable)
+	for _, tc := range []struct {
+		name     string
+		middle   error
+		settings map[string]moduleconfig.HandlerSettings
+	}{
+		{name: "success"},
+		{name: "failure", middle: boom},
+		{name: "stop", middle: reconciler.ErrStopHandlerChain},
+		{name: "classified", middle: deferred},
+		{name: "disabled", settings: map[string]moduleconfig.HandlerSettings{"middle": {Enabled: false}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ran []string
+			chain := func() []recordingHandler {
+				return []recordingHandler{
+					{name: "first", ran: &ran, result: reconcile.Result{RequeueAfter: time.Minute}},
+					{name: "middle", ran: &ran, err: tc.middle, result: reconcile.Result{RequeueAfter: time.Second}},
+					{name: "last", ran: &ran},
+				}
+			}
+
+			handlers, named := []DeviceHandler{}, []reconciler.Named{}
+			kit := []testkit.DeviceHandler{}
+			for _, h := range chain() {
+				handlers, named, kit = append(handlers, h), append(named, h), append(kit, h)
+			}
+			runtime := NewHandlerRuntime()
+			runtime.Apply(tc.settings, named)
+			svc := NewDeviceService(nil, nil, nil, handlers, nil, nil)
+			svc.SetHandlerRuntime(runtime)
+
+			serviceDevice := testkit.NewDevice("worker", 0).Build()
+			serviceResult, serviceErr := svc.invokeHandlers(context.Background(), serviceDevice)
+			serviceRan := ran
+
+			ran = nil
+			kitDevice := testkit.NewDevice("worker", 0).Build()
+			outcome := testkit.NewHarness(kit...).Apply(tc.settings).Run(context.Background(), kitDevice)
+
+			if !slices.Equal(serviceRan, outcome.Ran) || !slices.Equal(ran, outcome.Ran) {
+				t.Fatalf("service ran %v, harness ran %v (recorded %v)", serviceRan, outcome.Ran, ran)
+			}
+			if serviceResult != outcome.Result || fmt.Sprint(serviceErr) != fmt.Sprint(outcome.Err) {
+				t.Fatalf("service returned %+v/%v, harness %+v/%v", serviceResult, serviceErr, outcome.Result, outcome.Err)
+			}
+			testkit.RequireLabels(t, kitDevice, serviceDevice.Labels)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+)
+
+// topologyFlags maps the device conditions worth a glance in kubectl describe to their summary flag.
+var topologyFlags = []struct {
+	condition string
+	flag      string
+}{
+	{condition: invstate.ConditionNodeUnreachable, flag: "unreachable"},
+	{condition: invstate.ConditionStale, flag: "stale"},
+	{condition: invstate.ConditionMIGDataConflict, flag: "mig-conflict"},
+	{condition: invstate.ConditionMemoryDataConflict, flag: "memory-conflict"},
+	{condition: invstate.ConditionFieldParseWarning, flag: "parse-warning"},
+	{condition: invstate.ConditionWaitingForValidation, flag: "awaiting-validation"},
+	{condition: invstate.ConditionCompatibilityBlocked, flag: "compatibility-blocked"},
+}
+
+// setTopologySummary renders the devices of the node into status.topologySummary. The summary is only a
+// convenience for humans: it is rebuilt from the devices on every reconcile and keeps its generatedAt while
+// the lines are unchanged, so an idle node compares equal and costs no status write.
+func setTopologySummary(status *v1alpha1.GPUNodeStateStatus, devices []*v1alpha1.GPUDevice, now time.Time) {
+	if len(devices) == 0 {
+		status.TopologySummary = nil
+		return
+	}
+	lines, omitted := topologySummaryLines(devices)
+	if current := status.TopologySummary; current != nil && current.Omitted == omitted && slices.Equal(current.Devices, lines) {
+		return
+	}
+	status.TopologySummary = &v1alpha1.GPUNodeTopologySummary{
+		Devices:     lines,
+		Omitted:     omitted,
+		GeneratedAt: metav1.NewTime(now),
+	}
+}
+
+func topologySummaryLines(devices []*v1alpha1.GPUDevice) ([]string, int32) {
+	ordered := make([]*v1alpha1.GPUDevice, 0, len(devices))
+	for _, device := range devices {
+		if device != nil {
+			ordered = append(ordered, device)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return deviceIndexLess(ordered[i].Labels[invstate.DeviceIndexLabelKey], ordered[j].Labels[invstate.DeviceIndexLabelKey])
+	})
+
+	var omitted int32
+	if extra := len(ordered) - objsize.MaxTopologySummaryLines; extra > 0 {
+		omitted = int32(extra)
+		ordered = ordered[:objsize.MaxTopologySummaryLines]
+	}
+	lines := make([]string, 0, len(ordered))
+	for _, device := range ordered {
+		line, _ := objsize.TruncateString(topologySummaryLine(device), objsize.MaxTopologySummaryLineLength)
+		lines = append(lines, line)
+	}
+	return lines, omitted
+}
+
+// topologySummaryLine renders "<index> <product> <memory>MiB mig=<strategy> state=<state> [flags]".
+func topologySummaryLine(device *v1alpha1.GPUDevice) string {
+	hw := device.Status.Hardware
+	index := device.Labels[invstate.DeviceIndexLabelKey]
+	if index == "" {
+		index = "?"
+	}
+	product := strings.TrimSpace(hw.Product)
+	if product == "" {
+		product = "unknown"
+	}
+	mig := "n/a"
+	if hw.MIG.Capable {
+		mig = string(v1alpha1.GPUMIGStrategyNone)
+		if hw.MIG.Strategy != "" {
+			mig = string(hw.MIG.Strategy)
+		}
+	}
+
+	line := fmt.Sprintf("%s %s %dMiB mig=%s state=%s", index, product, hw.MemoryMiB, mig, normalizeDeviceState(device.Status.State))
+	var flags []string
+	if !device.Status.Managed {
+		flags = append(flags, "unmanaged")
+	}
+	for _, f := range topologyFlags {
+		if apimeta.IsStatusConditionTrue(device.Status.Conditions, f.condition) {
+			flags = append(flags, f.flag)
+		}
+	}
+	if len(flags) > 0 {
+		line += " [" + strings.Join(flags, ",") + "]"
+	}
+	return line
+}
+
+// deviceIndexLess orders numeric indexes numerically and puts anything else after them.
+func deviceIndexLess(a, b string) bool {
+	ai, aErr := strconv.Atoi(a)
+	bi, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return ai < bi
+	case aErr == nil:
+		return true
+	case bErr == nil:
+		return false
+	default:
+		return a < b
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+)
+
+func summaryDevice(index, product string, memory int32, state v1alpha1.GPUDeviceState) *v1alpha1.GPUDevice {
+	device := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{invstate.DeviceIndexLabelKey: index}},
+	}
+	device.Status.Managed = true
+	device.Status.State = state
+	device.Status.Hardware.Product = product
+	device.Status.Hardware.MemoryMiB = memory
+	return device
+}
+
+func TestTopologySummaryLines(t *testing.T) {
+	mig := summaryDevice("1", "NVIDIA A100-SXM4-80GB", 81920, v1alpha1.GPUDeviceStateAssigned)
+	mig.Status.Hardware.MIG = v1alpha1.GPUMIGConfig{Capable: true, Strategy: v1alpha1.GPUMIGStrategyMixed}
+	flagged := summaryDevice("10", "NVIDIA L4", 23034, v1alpha1.GPUDeviceStateReady)
+	flagged.Status.Managed = false
+	flagged.Status.Conditions = []metav1.Condition{
+		{Type: invstate.ConditionNodeUnreachable, Status: metav1.ConditionTrue},
+		{Type: invstate.ConditionStale, Status: metav1.ConditionFalse},
+		{Type: invstate.ConditionWaitingForValidation, Status: metav1.ConditionTrue},
+	}
+	unknown := &v1alpha1.GPUDevice{}
+	unknown.Status.Managed = true
+
+	lines, omitted := topologySummaryLines([]*v1alpha1.GPUDevice{
+		flagged,
+		summaryDevice("2", "NVIDIA L4", 23034, v1alpha1.GPUDeviceStateReady),
+		unknown,
+		mig,
+	})
+
+	want := []string{
+		"1 NVIDIA A100-SXM4-80GB 81920MiB mig=Mixed state=Assigned",
+		"2 NVIDIA L4 23034MiB mig=n/a state=Ready",
+		"10 NVIDIA L4 23034MiB mig=n/a state=Ready [unmanaged,unreachable,awaiting-validation]",
+		"? unknown 0MiB mig=n/a state=Discovered",
+	}
+	if omitted != 0 || strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected summary (omitted=%d):\n%s", omitted, strings.Join(lines, "\n"))
+	}
+}
+
+func TestTopologySummaryLinesCapped(t *testing.T) {
+	devices := make([]*v1alpha1.GPUDevice, objsize.MaxTopologySummaryLines+5)
+	for i := range devices {
+		devices[i] = summaryDevice("0", strings.Repeat("x", 2*objsize.MaxTopologySummaryLineLength), 1, v1alpha1.GPUDeviceStateReady)
+	}
+
+	lines, omitted := topologySummaryLines(devices)
+
+	if len(lines) != objsize.MaxTopologySummaryLines || omitted != 5 {
+		t.Fatalf("expected %d lines and 5 omitted, got %d and %d", objsize.MaxTopologySummaryLines, len(lines), omitted)
+	}
+	if n := len([]rune(lines[0])); n != objsize.MaxTopologySummaryLineLength {
+		t.Fatalf("expected lines cut to %d runes, got %d", objsize.MaxTopologySummaryLineLength, n)
+	}
+}
+
+func TestInventoryServiceReconcileTopologySummaryWrites(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	node := newTestNode("node-summary")
+
+	writes := 0
+	base := newTestClient(t, scheme, node)
+	cl := interceptor.NewClient(base.(client.WithWatch), interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			writes++
+			return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	})
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(start)
+	svc := NewInventoryService(cl, scheme, nil, nil, nil)
+	svc.SetClock(clock)
+
+	snapshot := invstate.NodeSnapshot{FeatureDetected: true, Devices: []invstate.DeviceSnapshot{{Index: "0"}}}
+	devices := []*v1alpha1.GPUDevice{summaryDevice("0", "NVIDIA L4", 23034, v1alpha1.GPUDeviceStateReady)}
+	summary := func() *v1alpha1.GPUNodeTopologySummary {
+		t.Helper()
+		got := &v1alpha1.GPUNodeState{}
+		if err := base.Get(ctx, types.NamespacedName{Name: node.Name}, got); err != nil {
+			t.Fatalf("get inventory: %v", err)
+		}
+		return got.Status.TopologySummary
+	}
+
+	if err := svc.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	first := summary()
+	if writes != 1 || first == nil || len(first.Devices) != 1 || !first.GeneratedAt.Time.Equal(start) {
+		t.Fatalf("expected the summary in the first write, got writes=%d %+v", writes, first)
+	}
+
+	// The same devices a minute later render the same lines: no write, generatedAt kept.
+	clock.SetTime(start.Add(time.Minute))
+	if err := svc.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if got := summary(); writes != 1 || !got.GeneratedAt.Time.Equal(start) {
+		t.Fatalf("expected an unchanged summary to cost no write, got writes=%d %+v", writes, got)
+	}
+
+	devices[0].Status.State = v1alpha1.GPUDeviceStateFaulted
+	if err := svc.Reconcile(ctx, node, snapshot, devices); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	got := summary()
+	if writes != 2 || got.Devices[0] != "0 NVIDIA L4 23034MiB mig=n/a state=Faulted" || !got.GeneratedAt.Time.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected the changed device to regenerate the summary, got writes=%d %+v", writes, got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/version"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+// defaultCompatibilityRules are the known-broken combinations shipped with the module; a rule in
+// inventory.compatibilityRules with the same name replaces the default one.
+var defaultCompatibilityRules = []moduleconfig.CompatibilityRule{
+	{
+		Name:         "driver-535.104-toolkit-1.14.2-mig",
+		DriverRange:  "535.104",
+		ToolkitRange: "1.14.2",
+		Effect:       moduleconfig.CompatibilityEffectBlock,
+		Message:      "NVIDIA driver 535.104 with container toolkit 1.14.2 breaks MIG reconfiguration; upgrade either component",
+	},
+}
+
+// CompatibilityFacts are the node versions the rules are matched against; an empty fact is unknown.
+type CompatibilityFacts struct {
+	Driver  string
+	Toolkit string
+	// Kernel is the kernel release as reported by the node, e.g. 5.15.0-91-generic.
+	Kernel string
+}
+
+// NodeCompatibilityFacts collects the facts of a node: the driver and toolkit from its labels, the kernel from
+// the gfd-extender platform report and, while the extender has not reported, from the kubelet.
+func NodeCompatibilityFacts(node *corev1.Node, driver snapshot.Driver, platform *v1alpha1.GPUNodePlatform) CompatibilityFacts {
+	facts := CompatibilityFacts{Driver: driver.Version, Toolkit: driver.ToolkitVersion}
+	if platform != nil {
+		facts.Kernel = platform.KernelRelease
+	}
+	if facts.Kernel == "" && node != nil {
+		facts.Kernel = node.Status.NodeInfo.KernelVersion
+	}
+	return facts
+}
+
+// CompatibilityVerdict lists the rules a node matched; Effect is the strictest of them, empty when none matched.
+type CompatibilityVerdict struct {
+	Effect  moduleconfig.CompatibilityEffect
+	Matched []moduleconfig.CompatibilityRule
+}
+
+// Blocked reports whether a Block rule matched.
+func (v CompatibilityVerdict) Blocked() bool {
+	return v.Effect == moduleconfig.CompatibilityEffectBlock
+}
+
+// Message joins the matched rule names and messages in rule order.
+func (v CompatibilityVerdict) Message() string {
+	parts := make([]string, 0, len(v.Matched))
+	for _, rule := range v.Matched {
+		part := rule.Name
+		if rule.Message != "" {
+			part += ": " + rule.Message
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CompatibilityPolicy evaluates the default rules merged with the ones from module settings.
+type CompatibilityPolicy struct {
+	rules []compatibilityRule
+}
+
+type compatibilityRule struct {
+	moduleconfig.CompatibilityRule
+	driver  version.Range
+	toolkit version.Range
+	kernel  version.Range
+}
+
+// NewCompatibilityPolicy merges settings rules over the defaults by name: a settings rule replaces the default
+// one in place, new names are appended. Ranges are validated by the settings parser; a rule whose range still
+// fails to parse is dropped rather than matching everything.
+func NewCompatibilityPolicy(settings []moduleconfig.CompatibilityRule) CompatibilityPolicy {
+	merged := append([]moduleconfig.CompatibilityRule(nil), defaultCompatibilityRules...)
+	index := make(map[string]int, len(merged))
+	for i, rule := range merged {
+		index[rule.Name] = i
+	}
+	for _, rule := range settings {
+		if i, ok := index[rule.Name]; ok {
+			merged[i] = rule
+			continue
+		}
+		index[rule.Name] = len(merged)
+		merged = append(merged, rule)
+	}
+
+	policy := CompatibilityPolicy{rules: make([]compatibilityRule, 0, len(merged))}
+	for _, rule := range merged {
+		compiled, err := compileCompatibilityRule(rule)
+		if err != nil {
+			continue
+		}
+		policy.rules = append(policy.rules, compiled)
+	}
+	return policy
+}
+
+func compileCompatibilityRule(rule moduleconfig.CompatibilityRule) (compatibilityRule, error) {
+	compiled := compatibilityRule{CompatibilityRule: rule}
+	var err error
+	if compiled.driver, err = version.ParseRange(rule.DriverRange); err != nil {
+		return compiled, fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+	if compiled.toolkit, err = version.ParseRange(rule.ToolkitRange); err != nil {
+		return compiled, fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+	if compiled.kernel, err = version.ParseKernelRange(rule.KernelRange); err != nil {
+		return compiled, fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+	return compiled, nil
+}
+
+// Evaluate matches the facts against every rule. A rule matches when each range it sets contains the
+// corresponding fact; a range over an unknown fact never matches, so missing data does not block a node.
+func (p CompatibilityPolicy) Evaluate(facts CompatibilityFacts) CompatibilityVerdict {
+	var verdict CompatibilityVerdict
+	kernel := version.Kernel(facts.Kernel)
+	for _, rule := range p.rules {
+		if !rangeMatches(rule.driver, facts.Driver) || !rangeMatches(rule.toolkit, facts.Toolkit) || !rangeMatches(rule.kernel, kernel) {
+			continue
+		}
+		verdict.Matched = append(verdict.Matched, rule.CompatibilityRule)
+		if verdict.Effect != moduleconfig.CompatibilityEffectBlock {
+			verdict.Effect = rule.Effect
+		}
+	}
+	return verdict
+}
+
+func rangeMatches(r version.Range, fact string) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	fact = strings.TrimSpace(fact)
+	if fact == "" {
+		return false
+	}
+	ok, err := r.Contains(fact)
+	return err == nil && ok
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+func TestCompatibilityPolicyEvaluate(t *testing.T) {
+	policy := NewCompatibilityPolicy([]moduleconfig.CompatibilityRule{
+		{Name: "old-kernel", KernelRange: ">=5.4 <5.15.0-100", Effect: moduleconfig.CompatibilityEffectWarn, Message: "upgrade the kernel"},
+		{Name: "deadlock", DriverRange: ">=550 <550.54.15", KernelRange: "6.8", Effect: moduleconfig.CompatibilityEffectBlock},
+	})
+
+	tests := []struct {
+		name    string
+		facts   CompatibilityFacts
+		effect  moduleconfig.CompatibilityEffect
+		matched []string
+	}{
+		{
+			name:  "no match",
+			facts: CompatibilityFacts{Driver: "550.54.15", Toolkit: "1.16.1", Kernel: "6.8.0-45-generic"},
+		},
+		{
+			name:    "default block rule",
+			facts:   CompatibilityFacts{Driver: "535.104.05", Toolkit: "1.14.2", Kernel: "6.1.0"},
+			effect:  moduleconfig.CompatibilityEffectBlock,
+			matched: []string{"driver-535.104-toolkit-1.14.2-mig"},
+		},
+		{
+			name:  "bare version does not match a sibling",
+			facts: CompatibilityFacts{Driver: "535.104", Toolkit: "1.14.20"},
+		},
+		{
+			name:    "warn",
+			facts:   CompatibilityFacts{Driver: "550.54.15", Kernel: "5.15.0-91-generic"},
+			effect:  moduleconfig.CompatibilityEffectWarn,
+			matched: []string{"old-kernel"},
+		},
+		{
+			name:    "inclusive lower bound",
+			facts:   CompatibilityFacts{Kernel: "5.4"},
+			effect:  moduleconfig.CompatibilityEffectWarn,
+			matched: []string{"old-kernel"},
+		},
+		{
+			name:  "exclusive upper bound",
+			facts: CompatibilityFacts{Kernel: "5.15.0-100-generic"},
+		},
+		{
+			name:    "block wins over warn",
+			facts:   CompatibilityFacts{Driver: "550.54.14", Kernel: "6.8.0-45-generic"},
+			effect:  moduleconfig.CompatibilityEffectBlock,
+			matched: []string{"deadlock"},
+		},
+		{
+			name:  "driver just above the range",
+			facts: CompatibilityFacts{Driver: "550.54.15", Kernel: "6.8.0-45-generic"},
+		},
+		{
+			name:  "unknown fact never matches",
+			facts: CompatibilityFacts{Kernel: "6.8.0-45-generic"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			verdict := policy.Evaluate(tc.facts)
+			if verdict.Effect != tc.effect {
+				t.Fatalf("expected effect %q, got %q", tc.effect, verdict.Effect)
+			}
+			var names []string
+			for _, rule := range verdict.Matched {
+				names = append(names, rule.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.matched, ",") {
+				t.Fatalf("expected matched %v, got %v", tc.matched, names)
+			}
+		})
+	}
+}
+
+func TestCompatibilityPolicyWarnAndBlockTogether(t *testing.T) {
+	policy := NewCompatibilityPolicy([]moduleconfig.CompatibilityRule{
+		{Name: "warn", DriverRange: "550", Effect: moduleconfig.CompatibilityEffectWarn, Message: "known issue"},
+		{Name: "block", DriverRange: "550.54", Effect: moduleconfig.CompatibilityEffectBlock},
+	})
+	verdict := policy.Evaluate(CompatibilityFacts{Driver: "550.54.14"})
+	if !verdict.Blocked() || len(verdict.Matched) != 2 {
+		t.Fatalf("expected both rules to match and block, got %+v", verdict)
+	}
+	if got := verdict.Message(); got != "warn: known issue; block" {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
+
+func TestCompatibilityPolicySettingsOverrideDefaults(t *testing.T) {
+	defaults := NewCompatibilityPolicy(nil)
+	facts := CompatibilityFacts{Driver: "535.104.05", Toolkit: "1.14.2"}
+	if !defaults.Evaluate(facts).Blocked() {
+		t.Fatalf("expected the default rule to block")
+	}
+
+	relaxed := NewCompatibilityPolicy([]moduleconfig.CompatibilityRule{{
+		Name:         "driver-535.104-toolkit-1.14.2-mig",
+		DriverRange:  "535.104",
+		ToolkitRange: "1.14.2",
+		Effect:       moduleconfig.CompatibilityEffectWarn,
+		Message:      "MIG is not used here",
+	}})
+	verdict := relaxed.Evaluate(facts)
+	if verdict.Effect != moduleconfig.CompatibilityEffectWarn || len(verdict.Matched) != 1 || verdict.Matched[0].Message != "MIG is not used here" {
+		t.Fatalf("expected the settings rule to replace the default, got %+v", verdict)
+	}
+
+	extended := NewCompatibilityPolicy([]moduleconfig.CompatibilityRule{{Name: "site", ToolkitRange: "<1.15", Effect: moduleconfig.CompatibilityEffectWarn}})
+	if verdict := extended.Evaluate(facts); len(verdict.Matched) != 2 || !verdict.Blocked() {
+		t.Fatalf("expected a new rule to be added next to the defaults, got %+v", verdict)
+	}
+}
+
+func TestNodeCompatibilityFacts(t *testing.T) {
+	node := &corev1.Node{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KernelVersion: "5.15.0-91-generic"}}}
+	driver := snapshot.Driver{Version: "550.54.15", ToolkitVersion: "1.16.1"}
+
+	facts := NodeCompatibilityFacts(node, driver, nil)
+	if facts != (CompatibilityFacts{Driver: "550.54.15", Toolkit: "1.16.1", Kernel: "5.15.0-91-generic"}) {
+		t.Fatalf("expected the kubelet kernel without a platform report, got %+v", facts)
+	}
+	facts = NodeCompatibilityFacts(node, driver, &v1alpha1.GPUNodePlatform{KernelRelease: "6.8.0-45-generic"})
+	if facts.Kernel != "6.8.0-45-generic" {
+		t.Fatalf("expected the reported kernel release to win, got %q", facts.Kernel)
+	}
+}
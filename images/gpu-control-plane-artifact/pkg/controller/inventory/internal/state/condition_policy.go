@@ -0,0 +1,104 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// SuppressedReasonPrefix marks the reason of a condition suppressed by inventory.conditionPolicy.
+const SuppressedReasonPrefix = "Suppressed"
+
+// policyConditionTypes are the GPUNodeState conditions written by the inventory controller. Conditions owned by
+// other controllers are left alone, so the policy never fights their writes.
+var policyConditionTypes = map[string]struct{}{
+	ConditionInventoryComplete:        {},
+	ConditionNodeDraining:             {},
+	ConditionFieldParseWarning:        {},
+	ConditionSecureBootUnsignedDriver: {},
+	ConditionCompatibilityRuleMatched: {},
+}
+
+// ConditionPolicy is the compiled inventory.conditionPolicy. The zero value reports every condition as it is.
+type ConditionPolicy struct {
+	rules map[string]conditionRule
+}
+
+type conditionRule struct {
+	severity string
+	suppress labels.Selector
+}
+
+// NewConditionPolicy compiles the settings policy. Selectors are validated by the settings parser; one that still
+// fails to compile suppresses nothing rather than everything.
+func NewConditionPolicy(settings map[string]moduleconfig.ConditionPolicy) ConditionPolicy {
+	if len(settings) == 0 {
+		return ConditionPolicy{}
+	}
+	policy := ConditionPolicy{rules: make(map[string]conditionRule, len(settings))}
+	for conditionType, item := range settings {
+		rule := conditionRule{severity: string(item.Severity)}
+		if item.SuppressOnNodesMatching != nil {
+			if selector, err := metav1.LabelSelectorAsSelector(item.SuppressOnNodesMatching); err == nil {
+				rule.suppress = selector
+			}
+		}
+		policy.rules[conditionType] = rule
+	}
+	return policy
+}
+
+// Severity returns the configured severity of the condition type, or an empty string without one.
+func (p ConditionPolicy) Severity(conditionType string) string {
+	return p.rules[conditionType].severity
+}
+
+// Suppressed reports whether the condition type is suppressed on a node with the given labels.
+func (p ConditionPolicy) Suppressed(conditionType string, nodeLabels map[string]string) bool {
+	rule, ok := p.rules[conditionType]
+	if !ok || rule.suppress == nil {
+		return false
+	}
+	return rule.suppress.Matches(labels.Set(nodeLabels))
+}
+
+// Apply prefixes the reasons of the suppressed inventory conditions with SuppressedReasonPrefix. It expects
+// conditions computed after UnsuppressConditions, so the prefix is added exactly once.
+func (p ConditionPolicy) Apply(conditions []metav1.Condition, nodeLabels map[string]string) {
+	for i := range conditions {
+		if _, ok := policyConditionTypes[conditions[i].Type]; !ok {
+			continue
+		}
+		if p.Suppressed(conditions[i].Type, nodeLabels) {
+			conditions[i].Reason = SuppressedReasonPrefix + conditions[i].Reason
+		}
+	}
+}
+
+// UnsuppressConditions strips SuppressedReasonPrefix from the inventory conditions, so they are computed against
+// their plain reasons and a condition that is no longer suppressed loses the prefix.
+func UnsuppressConditions(conditions []metav1.Condition) {
+	for i := range conditions {
+		if _, ok := policyConditionTypes[conditions[i].Type]; !ok {
+			continue
+		}
+		conditions[i].Reason = strings.TrimPrefix(conditions[i].Reason, SuppressedReasonPrefix)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func TestConditionPolicySuppression(t *testing.T) {
+	policy := NewConditionPolicy(map[string]moduleconfig.ConditionPolicy{
+		ConditionInventoryComplete: {
+			Severity: moduleconfig.ConditionSeverityWarning,
+			SuppressOnNodesMatching: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "node-role/ingest", Operator: metav1.LabelSelectorOpExists},
+			}},
+		},
+		ConditionFieldParseWarning: {Severity: moduleconfig.ConditionSeverityInfo},
+	})
+	ingest := map[string]string{"node-role/ingest": ""}
+
+	if !policy.Suppressed(ConditionInventoryComplete, ingest) || policy.Suppressed(ConditionInventoryComplete, nil) {
+		t.Fatalf("expected suppression on matching nodes only")
+	}
+	if policy.Suppressed(ConditionFieldParseWarning, ingest) {
+		t.Fatalf("a policy without a selector must not suppress")
+	}
+	if policy.Severity(ConditionInventoryComplete) != "Warning" || policy.Severity(ConditionFieldParseWarning) != "Info" || policy.Severity(ConditionNodeDraining) != "" {
+		t.Fatalf("unexpected severities")
+	}
+
+	conds := []metav1.Condition{
+		{Type: ConditionInventoryComplete, Reason: ReasonNodeFeatureMissing},
+		{Type: NodeConditionDriverReady, Reason: "SuppressedByValidator"},
+	}
+	policy.Apply(conds, ingest)
+	if conds[0].Reason != "SuppressedNodeFeatureMissing" {
+		t.Fatalf("expected the suppressed reason, got %q", conds[0].Reason)
+	}
+	UnsuppressConditions(conds)
+	policy.Apply(conds, ingest)
+	if conds[0].Reason != "SuppressedNodeFeatureMissing" {
+		t.Fatalf("expected the prefix to be added once, got %q", conds[0].Reason)
+	}
+	UnsuppressConditions(conds)
+	policy.Apply(conds, nil)
+	if conds[0].Reason != ReasonNodeFeatureMissing {
+		t.Fatalf("expected the prefix to be dropped off matching nodes, got %q", conds[0].Reason)
+	}
+	if conds[1].Reason != "SuppressedByValidator" {
+		t.Fatalf("conditions of other controllers must be left alone, got %q", conds[1].Reason)
+	}
+}
+
+func TestConditionPolicyZeroValue(t *testing.T) {
+	conds := []metav1.Condition{{Type: ConditionInventoryComplete, Reason: ReasonInventorySynced}}
+	policy := NewConditionPolicy(nil)
+	policy.Apply(conds, map[string]string{"any": "label"})
+	if conds[0].Reason != ReasonInventorySynced || policy.Severity(ConditionInventoryComplete) != "" {
+		t.Fatalf("expected conditions to be reported as they are, got %+v", conds)
+	}
+}
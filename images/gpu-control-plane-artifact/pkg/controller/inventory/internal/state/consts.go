@@ -14,17 +14,35 @@
 
 package state
 
-import "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+import (
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
 
 const (
 	DeviceNodeIndexKey  = indexer.GPUDeviceNodeField
-	DeviceLabelPrefix   = "gpu.deckhouse.io/device."
+	DeviceLabelPrefix   = snapshot.DeviceLabelPrefix
 	DeviceNodeLabelKey  = "gpu.deckhouse.io/node"
 	DeviceIndexLabelKey = "gpu.deckhouse.io/device-index"
+	// DeviceConfidentialComputingLabelKey is "true" or "false" once the driver reports the CC mode, absent otherwise.
+	DeviceConfidentialComputingLabelKey = "gpu.deckhouse.io/confidential-computing"
+	// DeviceAttributeAnnotationPrefix prefixes the instance attributes mirrored by inventory.attributePassthroughPrefixes.
+	DeviceAttributeAnnotationPrefix = "gpu.deckhouse.io/attr."
+	// DeviceInventoryIDIndexKey finds devices by status.inventoryID whatever their name.
+	DeviceInventoryIDIndexKey = indexer.GPUDeviceInventoryIDField
 
 	// DefaultManagedNodeLabelKey marks nodes enabled for GPU inventory management.
 	DefaultManagedNodeLabelKey = "gpu.deckhouse.io/enabled"
 
+	// Node capability labels written when nodeLabeling is enabled.
+	NodeGPUPresentLabelKey       = "gpu.deckhouse.io/present"
+	NodeGPUCountLabelKey         = "gpu.deckhouse.io/count"
+	NodeGPUProductLabelKey       = "gpu.deckhouse.io/product"
+	NodeGPUMinMemoryGiBLabelKey  = "gpu.deckhouse.io/min-memory-gib"
+	NodeContainerRuntimeLabelKey = containerruntime.LabelKey
+
 	// NodeFeatureNodeNameLabel is NFD label with node name.
 	NodeFeatureNodeNameLabel = "nfd.node.kubernetes.io/node-name"
 
@@ -33,31 +51,103 @@ const (
 	ReasonInventorySynced      = "InventorySynced"
 	ReasonNoDevicesDiscovered  = "NoDevicesDiscovered"
 	ReasonNodeFeatureMissing   = "NodeFeatureMissing"
+	// ReasonDiscoveryAPIUnsupported means the cluster does not serve the NodeFeature version the controller reads.
+	ReasonDiscoveryAPIUnsupported = "DiscoveryAPIUnsupported"
+
+	// Node draining condition and reasons.
+	ConditionNodeDraining      = "NodeDraining"
+	ReasonNodeDeleting         = "NodeDeleting"
+	ReasonAutoscalerScaleDown  = "ClusterAutoscalerScaleDown"
+	ToBeDeletedByAutoscalerKey = "ToBeDeletedByClusterAutoscaler"
+
+	// Node unreachable device condition and reasons.
+	ConditionNodeUnreachable = poolcommon.DeviceConditionNodeUnreachable
+	ReasonNodeNotReady       = "NodeNotReady"
+
+	// Deletion limiter condition, reasons and override.
+	ConditionDeletionsThrottled = "DeletionsThrottled"
+	ReasonDeletionLimitReached  = "DeletionLimitReached"
+	// AllowMassDeletionAnnotation on a GPUNodeState lets its devices bypass the deletion limiter.
+	AllowMassDeletionAnnotation = "gpu.deckhouse.io/allow-mass-deletion"
+
+	// Stale device condition, set while the removal of a device that is no longer reported waits for its pool to
+	// release it; its reason is inuse.ReasonDeviceInUse.
+	ConditionStale = "Stale"
+
+	// Malformed hardware attribute condition and reason; the message lists the ignored fields.
+	ConditionFieldParseWarning = "FieldParseWarning"
+	ReasonMalformedAttributes  = "MalformedAttributes"
+
+	// Secure Boot condition and reason; set while Secure Boot is on and the loaded NVIDIA module is unsigned.
+	ConditionSecureBootUnsignedDriver = "SecureBootUnsignedDriver"
+	ReasonUnsignedDriverModule        = "UnsignedDriverModule"
+
+	// Compatibility rule conditions and reasons. The GPUNodeState condition lists every matched rule;
+	// the device condition is set only for Block rules and keeps the device out of AutoAttach and pool capacity.
+	ConditionCompatibilityRuleMatched = "CompatibilityRuleMatched"
+	ConditionCompatibilityBlocked     = poolcommon.DeviceConditionCompatibilityBlocked
+	ReasonCompatibilityWarning        = "CompatibilityWarning"
+	ReasonCompatibilityBlocked        = "CompatibilityBlocked"
+
+	// MIG data conflict condition and reason; set while the device instance and the node labels report different strategies.
+	ConditionMIGDataConflict  = "MIGDataConflict"
+	ReasonMIGStrategyMismatch = "MIGStrategyMismatch"
+
+	// Memory data conflict condition and reason; set while two memory sizes reported for the device differ
+	// by more than detection.MemoryTolerancePercent.
+	ConditionMemoryDataConflict = "MemoryDataConflict"
+	ReasonMemorySizeMismatch    = "MemorySizeMismatch"
+
+	// Handler runtime configuration condition and reasons.
+	ConditionHandlersConfigured  = "HandlersConfigured"
+	ReasonHandlersConfigured     = "HandlersConfigured"
+	ReasonHandlerConfigureFailed = "HandlerConfigureFailed"
+
+	// Validation gate condition and reasons, set while deviceApproval.requireValidationBeforeAttach holds AutoAttach back.
+	ConditionWaitingForValidation = "WaitingForValidation"
+	ReasonValidationPending       = "ValidationPending"
+	ReasonDriverChanged           = "DriverChanged"
+	ReasonValidationPassed        = "ValidationPassed"
+
+	// GPUNodeState conditions the bootstrap controller derives from the validator.
+	NodeConditionDriverReady  = "DriverReady"
+	NodeConditionToolkitReady = "ToolkitReady"
 
 	// Inventory events.
-	EventDeviceDetected    = "GPUDeviceDetected"
-	EventDeviceRemoved     = "GPUDeviceRemoved"
-	EventInventoryChanged  = "GPUInventoryConditionChanged"
-	EventDetectUnavailable = "GPUDetectionUnavailable"
+	EventDeviceDetected      = "GPUDeviceDetected"
+	EventInventoryChanged    = "GPUInventoryConditionChanged"
+	EventDetectUnavailable   = "GPUDetectionUnavailable"
+	EventUnknownHandler      = "GPUInventoryUnknownHandler"
+	EventLabelNotMigrated    = "GPUManagedLabelNotMigrated"
+	EventNodeUnreachable     = "GPUNodeUnreachable"
+	EventStaleDevicesRemoved = "GPUStaleDevicesRemoved"
+	EventDeletionsThrottled  = "GPUDeviceDeletionsThrottled"
+	// EventDuplicateDeviceMerged is recorded on the kept device when a younger copy with its inventoryID is deleted.
+	EventDuplicateDeviceMerged = "DuplicateDeviceMerged"
+	// EventUntrustedNodeFeature is recorded on the node when NodeFeatures outside the trusted namespaces are ignored.
+	EventUntrustedNodeFeature = "GPUUntrustedNodeFeature"
+	// EventCompatibilityRuleMatched is recorded on the node when the set of matched compatibility rules changes.
+	EventCompatibilityRuleMatched = "GPUCompatibilityRuleMatched"
 
 	// NFD/GFD labels.
-	GFDProductLabel            = "nvidia.com/gpu.product"
-	GFDMemoryLabel             = "nvidia.com/gpu.memory"
-	GFDComputeMajorLabel       = "nvidia.com/gpu.compute.major"
-	GFDComputeMinorLabel       = "nvidia.com/gpu.compute.minor"
-	GFDDriverVersionLabel      = "nvidia.com/gpu.driver"
-	GFDCudaRuntimeVersionLabel = "nvidia.com/cuda.runtime.version"
-	GFDCudaDriverMajorLabel    = "nvidia.com/cuda.driver.major"
-	GFDCudaDriverMinorLabel    = "nvidia.com/cuda.driver.minor"
-	GFDMigCapableLabel         = "nvidia.com/mig.capable"
-	GFDMigStrategyLabel        = "nvidia.com/mig.strategy"
-	GFDMigAltCapableLabel      = "nvidia.com/mig-capable"
-	GFDMigAltStrategyLabel     = "nvidia.com/mig-strategy"
-	DeckhouseToolkitInstalled  = "gpu.deckhouse.io/toolkit.installed"
-	DeckhouseToolkitReadyLabel = "gpu.deckhouse.io/toolkit.ready"
-
-	MIGProfileLabelPrefix = "nvidia.com/mig-"
-	VendorNvidia          = "10de"
+	GFDProductLabel              = snapshot.GFDProductLabel
+	GFDMemoryLabel               = snapshot.GFDMemoryLabel
+	GFDComputeMajorLabel         = snapshot.GFDComputeMajorLabel
+	GFDComputeMinorLabel         = snapshot.GFDComputeMinorLabel
+	GFDDriverVersionLabel        = snapshot.GFDDriverVersionLabel
+	GFDCudaRuntimeVersionLabel   = snapshot.GFDCudaRuntimeVersionLabel
+	GFDCudaDriverMajorLabel      = snapshot.GFDCudaDriverMajorLabel
+	GFDCudaDriverMinorLabel      = snapshot.GFDCudaDriverMinorLabel
+	GFDMigCapableLabel           = snapshot.GFDMigCapableLabel
+	GFDMigStrategyLabel          = snapshot.GFDMigStrategyLabel
+	GFDMigAltCapableLabel        = snapshot.GFDMigAltCapableLabel
+	GFDMigAltStrategyLabel       = snapshot.GFDMigAltStrategyLabel
+	DeckhouseToolkitInstalled    = snapshot.DeckhouseToolkitInstalled
+	DeckhouseToolkitReadyLabel   = snapshot.DeckhouseToolkitReadyLabel
+	DeckhouseToolkitVersionLabel = snapshot.DeckhouseToolkitVersionLabel
+
+	MIGProfileLabelPrefix = snapshot.MIGProfileLabelPrefix
+	VendorNvidia          = snapshot.VendorNvidia
 )
 
 const (
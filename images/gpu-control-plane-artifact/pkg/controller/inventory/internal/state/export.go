@@ -32,6 +32,10 @@ func BuildDeviceName(nodeName string, info DeviceSnapshot) string {
 	return buildDeviceName(nodeName, info)
 }
 
+func BuildTemplatedDeviceName(template, nodeName string, info DeviceSnapshot) string {
+	return buildTemplatedDeviceName(template, nodeName, info)
+}
+
 func BuildInventoryID(nodeName string, info DeviceSnapshot) string {
 	return buildInventoryID(nodeName, info)
 }
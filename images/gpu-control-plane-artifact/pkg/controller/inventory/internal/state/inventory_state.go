@@ -15,12 +15,7 @@
 package state
 
 import (
-	"context"
-
 	corev1 "k8s.io/api/core/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
@@ -30,8 +25,8 @@ type InventoryState interface {
 	NodeFeature() *nfdv1alpha1.NodeFeature
 	Snapshot() NodeSnapshot
 	ApprovalPolicy() DeviceApprovalPolicy
-	AllowCleanup() bool
-	OrphanDevices(ctx context.Context, c client.Client) (map[string]struct{}, error)
+	StalenessPolicy() StalenessPolicy
+	CompatibilityPolicy() CompatibilityPolicy
 	HasDevices() bool
 }
 
@@ -41,15 +36,26 @@ type inventoryState struct {
 	nodeFeature   *nfdv1alpha1.NodeFeature
 	managedPolicy ManagedNodesPolicy
 	approval      DeviceApprovalPolicy
+	staleness     StalenessPolicy
+	compatibility CompatibilityPolicy
 	snapshot      NodeSnapshot
 }
 
-func NewInventoryState(node *corev1.Node, feature *nfdv1alpha1.NodeFeature, managed ManagedNodesPolicy, approval DeviceApprovalPolicy) InventoryState {
+func NewInventoryState(
+	node *corev1.Node,
+	feature *nfdv1alpha1.NodeFeature,
+	managed ManagedNodesPolicy,
+	approval DeviceApprovalPolicy,
+	staleness StalenessPolicy,
+	compatibility CompatibilityPolicy,
+) InventoryState {
 	return &inventoryState{
 		node:          node,
 		nodeFeature:   feature,
 		managedPolicy: managed,
 		approval:      approval,
+		staleness:     staleness,
+		compatibility: compatibility,
 		snapshot:      BuildNodeSnapshot(node, feature, managed),
 	}
 }
@@ -70,20 +76,12 @@ func (s *inventoryState) ApprovalPolicy() DeviceApprovalPolicy {
 	return s.approval
 }
 
-func (s *inventoryState) AllowCleanup() bool {
-	return s.snapshot.FeatureDetected || len(s.snapshot.Devices) > 0
+func (s *inventoryState) StalenessPolicy() StalenessPolicy {
+	return s.staleness
 }
 
-func (s *inventoryState) OrphanDevices(ctx context.Context, c client.Client) (map[string]struct{}, error) {
-	existingDevices := &v1alpha1.GPUDeviceList{}
-	if err := c.List(ctx, existingDevices, client.MatchingFields{DeviceNodeIndexKey: s.node.Name}); err != nil {
-		return nil, err
-	}
-	orphanDevices := make(map[string]struct{}, len(existingDevices.Items))
-	for i := range existingDevices.Items {
-		orphanDevices[existingDevices.Items[i].Name] = struct{}{}
-	}
-	return orphanDevices, nil
+func (s *inventoryState) CompatibilityPolicy() CompatibilityPolicy {
+	return s.compatibility
 }
 
 func (s *inventoryState) HasDevices() bool {
@@ -15,83 +15,54 @@
 package state
 
 import (
-	"context"
-	"errors"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
-
-	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
 )
 
-func TestInventoryStateAllowCleanup(t *testing.T) {
-	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
-
-	state := NewInventoryState(node, nil, ManagedNodesPolicy{}, DeviceApprovalPolicy{})
-	if state.AllowCleanup() {
-		t.Fatalf("expected cleanup to be disabled without devices and features")
-	}
-
-	state = NewInventoryState(node, &nfdv1alpha1.NodeFeature{}, ManagedNodesPolicy{}, DeviceApprovalPolicy{})
-	if !state.AllowCleanup() {
-		t.Fatalf("expected cleanup to be enabled when NodeFeature detected")
+func TestInventoryStateReadsNodeTrimmedByCacheTransform(t *testing.T) {
+	since := metav1.NewTime(time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC))
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-a",
+			UID:  "uid-a",
+			Labels: map[string]string{
+				"gpu.deckhouse.io/managed":          "false",
+				"gpu.deckhouse.io/device.00.vendor": "10de",
+				"gpu.deckhouse.io/device.00.device": "20b0",
+				"gpu.deckhouse.io/device.00.class":  "0302",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, LastTransitionTime: since}},
+			Images:     []corev1.ContainerImage{{Names: []string{"registry/image:tag"}, SizeBytes: 1 << 30}},
+		},
 	}
-
-	node.Labels = map[string]string{
-		"gpu.deckhouse.io/device.00.vendor": "10de",
-		"gpu.deckhouse.io/device.00.device": "1db5",
-		"gpu.deckhouse.io/device.00.class":  "0302",
+	trimmed, err := commonobject.NodeCacheTransform()(node)
+	if err != nil {
+		t.Fatalf("transform: %v", err)
 	}
-	state = NewInventoryState(node, nil, ManagedNodesPolicy{}, DeviceApprovalPolicy{})
-	if !state.AllowCleanup() {
-		t.Fatalf("expected cleanup to be enabled when devices are present")
+	node = trimmed.(*corev1.Node)
+	if node.Status.Images != nil {
+		t.Fatalf("expected transform to drop images")
 	}
-}
-
-func TestInventoryStateOrphanDevicesListsExistingGPUDevices(t *testing.T) {
-	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
-	state := NewInventoryState(node, nil, ManagedNodesPolicy{}, DeviceApprovalPolicy{})
-
-	c := &delegatingClient{
-		list: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
-			listOpts := &client.ListOptions{}
-			for _, opt := range opts {
-				opt.ApplyToList(listOpts)
-			}
-
-			want := fields.SelectorFromSet(fields.Set{DeviceNodeIndexKey: "node-a"}).String()
-			if listOpts.FieldSelector == nil || listOpts.FieldSelector.String() != want {
-				return errors.New("unexpected field selector")
-			}
 
-			devices, ok := list.(*v1alpha1.GPUDeviceList)
-			if !ok {
-				return errors.New("unexpected list type")
-			}
-			devices.Items = []v1alpha1.GPUDevice{
-				{ObjectMeta: metav1.ObjectMeta{Name: "dev-a"}},
-				{ObjectMeta: metav1.ObjectMeta{Name: "dev-b"}},
-			}
-			return nil
-		},
-	}
+	policy := ManagedNodesPolicy{LabelKey: "gpu.deckhouse.io/managed", EnabledByDefault: true}
+	state := NewInventoryState(node, nil, policy, DeviceApprovalPolicy{}, StalenessPolicy{}, CompatibilityPolicy{})
 
-	orphanDevices, err := state.OrphanDevices(context.Background(), c)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(orphanDevices) != 2 {
-		t.Fatalf("expected 2 devices, got %d", len(orphanDevices))
+	snapshot := state.Snapshot()
+	if snapshot.Managed {
+		t.Fatalf("expected managed label to be honoured after transform")
 	}
-	if _, ok := orphanDevices["dev-a"]; !ok {
-		t.Fatalf("expected dev-a to be present")
+	if len(snapshot.Devices) != 1 || snapshot.Devices[0].Device != "20b0" {
+		t.Fatalf("expected device labels to survive the transform, got %+v", snapshot.Devices)
 	}
-	if _, ok := orphanDevices["dev-b"]; !ok {
-		t.Fatalf("expected dev-b to be present")
+	if got, notReady := NodeNotReadySince(state.Node()); !notReady || !got.Equal(since.Time) {
+		t.Fatalf("expected Ready condition to survive the transform, got %v (%v)", got, notReady)
 	}
 }
@@ -0,0 +1,56 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import "testing"
+
+func TestNodeManagedDuringLabelKeyTransition(t *testing.T) {
+	policy := ManagedNodesPolicy{LabelKey: "example.com/gpu", PreviousLabelKey: "gpu.deckhouse.io/enabled", EnabledByDefault: false}
+
+	cases := []struct {
+		name           string
+		labels         map[string]string
+		wantManaged    bool
+		wantUnmigrated string
+	}{
+		{"only previous key", map[string]string{"gpu.deckhouse.io/enabled": "true"}, true, "gpu.deckhouse.io/enabled"},
+		{"only new key", map[string]string{"example.com/gpu": "true"}, true, ""},
+		{"previous key wins on conflict", map[string]string{"gpu.deckhouse.io/enabled": "false", "example.com/gpu": "true"}, false, ""},
+		{"previous key enables over new false", map[string]string{"gpu.deckhouse.io/enabled": "true", "example.com/gpu": "false"}, true, ""},
+		{"no keys falls back to default", map[string]string{}, false, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nodeManaged(tc.labels, policy); got != tc.wantManaged {
+				t.Fatalf("expected managed=%t, got %t", tc.wantManaged, got)
+			}
+			if got := unmigratedLabelKey(tc.labels, policy); got != tc.wantUnmigrated {
+				t.Fatalf("expected unmigrated key %q, got %q", tc.wantUnmigrated, got)
+			}
+		})
+	}
+}
+
+func TestNodeManagedIgnoresPreviousKeyOutsideTransition(t *testing.T) {
+	policy := ManagedNodesPolicy{LabelKey: "example.com/gpu", EnabledByDefault: false}
+	labels := map[string]string{"gpu.deckhouse.io/enabled": "true"}
+
+	if nodeManaged(labels, policy) {
+		t.Fatalf("expected previous key to be ignored once the transition window is closed")
+	}
+	if got := unmigratedLabelKey(labels, policy); got != "" {
+		t.Fatalf("expected no unmigrated key outside transition, got %q", got)
+	}
+}
@@ -14,7 +14,13 @@
 
 package state
 
-import "strings"
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
 
 func sanitizeName(input string) string {
 	input = strings.ToLower(input)
@@ -54,6 +60,42 @@ func buildDeviceName(nodeName string, info deviceSnapshot) string {
 	return truncateName(base + "-" + suffix)
 }
 
+// buildTemplatedDeviceName renders a validated inventory.deviceNameTemplate. The default template keeps
+// the historical format, and {uuid8} falls back to it while the UUID is not known yet.
+func buildTemplatedDeviceName(template, nodeName string, info deviceSnapshot) string {
+	uuid := uuid8(info.UUID)
+	if template == "" || template == moduleconfig.DefaultDeviceNameTemplate ||
+		(uuid == "" && strings.Contains(template, moduleconfig.DeviceNameVarUUID8)) {
+		return buildDeviceName(nodeName, info)
+	}
+	name := strings.NewReplacer(
+		moduleconfig.DeviceNameVarNode, sanitizeName(nodeName),
+		moduleconfig.DeviceNameVarIndex, sanitizeName(info.Index),
+		moduleconfig.DeviceNameVarVendor, sanitizeName(info.Vendor),
+		moduleconfig.DeviceNameVarDevice, sanitizeName(info.Device),
+		moduleconfig.DeviceNameVarUUID8, uuid,
+	).Replace(template)
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength], "-.")
+	}
+	return name
+}
+
+// uuid8 returns the first eight hex digits of a GPU UUID such as GPU-1a2b3c4d-....
+func uuid8(uuid string) string {
+	uuid = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(uuid), "GPU-"))
+	var builder strings.Builder
+	for _, r := range uuid {
+		if (r >= 'a' && r <= 'f') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			if builder.Len() == 8 {
+				return builder.String()
+			}
+		}
+	}
+	return ""
+}
+
 func buildInventoryID(nodeName string, info deviceSnapshot) string {
 	base := sanitizeName(nodeName)
 	suffix := sanitizeName(info.Index + "-" + info.Vendor + "-" + info.Device)
@@ -0,0 +1,81 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NodeCapabilityLabelKeys lists every label the node labeling handler owns.
+var NodeCapabilityLabelKeys = []string{
+	NodeGPUPresentLabelKey,
+	NodeGPUCountLabelKey,
+	NodeGPUProductLabelKey,
+	NodeGPUMinMemoryGiBLabelKey,
+	NodeContainerRuntimeLabelKey,
+}
+
+// NodeCapabilityLabels projects the detected devices onto node labels. A node without devices gets
+// none; product and memory are left out when no device reports them.
+func NodeCapabilityLabels(devices []DeviceSnapshot) map[string]string {
+	if len(devices) == 0 {
+		return nil
+	}
+	labels := map[string]string{
+		NodeGPUPresentLabelKey: "true",
+		NodeGPUCountLabelKey:   strconv.Itoa(len(devices)),
+	}
+	if product := ProductLabelValue(dominantProduct(devices)); product != "" {
+		labels[NodeGPUProductLabelKey] = product
+	}
+	var minMemory int32
+	for _, device := range devices {
+		if device.MemoryMiB > 0 && (minMemory == 0 || device.MemoryMiB < minMemory) {
+			minMemory = device.MemoryMiB
+		}
+	}
+	if minMemory > 0 {
+		labels[NodeGPUMinMemoryGiBLabelKey] = strconv.Itoa(int(minMemory / 1024))
+	}
+	return labels
+}
+
+// ProductLabelValue turns a product name such as "NVIDIA H100 80GB HBM3/PCIe" into a valid label
+// value; an empty name yields "".
+func ProductLabelValue(product string) string {
+	if strings.TrimSpace(product) == "" {
+		return ""
+	}
+	return strings.TrimRight(truncateName(sanitizeName(product)), "-")
+}
+
+// dominantProduct returns the most common product; ties go to the lexically smallest name so the
+// label does not flap between reconciles.
+func dominantProduct(devices []DeviceSnapshot) string {
+	counts := make(map[string]int, len(devices))
+	for _, device := range devices {
+		if product := strings.TrimSpace(device.Product); product != "" {
+			counts[product]++
+		}
+	}
+	var best string
+	for product, count := range counts {
+		if best == "" || count > counts[best] || (count == counts[best] && product < best) {
+			best = product
+		}
+	}
+	return best
+}
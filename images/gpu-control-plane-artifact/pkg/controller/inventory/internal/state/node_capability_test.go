@@ -0,0 +1,81 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import "testing"
+
+func TestNodeCapabilityLabels(t *testing.T) {
+	if labels := NodeCapabilityLabels(nil); labels != nil {
+		t.Fatalf("expected no labels without devices, got %v", labels)
+	}
+
+	labels := NodeCapabilityLabels([]DeviceSnapshot{
+		{Index: "0", Product: "NVIDIA A100-SXM4-80GB", MemoryMiB: 81920},
+		{Index: "1", Product: "NVIDIA A100-SXM4-80GB", MemoryMiB: 81920},
+		{Index: "2", Product: "NVIDIA A100-SXM4-40GB", MemoryMiB: 40536},
+	})
+	want := map[string]string{
+		NodeGPUPresentLabelKey:      "true",
+		NodeGPUCountLabelKey:        "3",
+		NodeGPUProductLabelKey:      "nvidia-a100-sxm4-80gb",
+		NodeGPUMinMemoryGiBLabelKey: "39",
+	}
+	if len(labels) != len(want) {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+	for key, value := range want {
+		if labels[key] != value {
+			t.Fatalf("label %s = %q, want %q (all: %v)", key, labels[key], value, labels)
+		}
+	}
+
+	labels = NodeCapabilityLabels([]DeviceSnapshot{{Index: "0"}})
+	if _, ok := labels[NodeGPUProductLabelKey]; ok {
+		t.Fatalf("expected no product label without a product, got %v", labels)
+	}
+	if _, ok := labels[NodeGPUMinMemoryGiBLabelKey]; ok {
+		t.Fatalf("expected no memory label without memory, got %v", labels)
+	}
+}
+
+func TestNodeCapabilityLabelsProductTieIsStable(t *testing.T) {
+	devices := []DeviceSnapshot{{Product: "Tesla T4"}, {Product: "NVIDIA L4"}}
+	for i := 0; i < 10; i++ {
+		if got := NodeCapabilityLabels(devices)[NodeGPUProductLabelKey]; got != "nvidia-l4" {
+			t.Fatalf("expected the lexically smallest product on a tie, got %q", got)
+		}
+	}
+}
+
+func TestProductLabelValue(t *testing.T) {
+	cases := map[string]string{
+		"":                             "",
+		"   ":                          "",
+		"NVIDIA H100 80GB HBM3/PCIe":   "nvidia-h100-80gb-hbm3-pcie",
+		" Tesla  V100-SXM2-16GB ":      "tesla-v100-sxm2-16gb",
+		"NVIDIA RTX A6000 / Quadro":    "nvidia-rtx-a6000-quadro",
+		"GeForce RTX 4090 (24 GB) rev": "geforce-rtx-4090-24-gb-rev",
+	}
+	for product, want := range cases {
+		if got := ProductLabelValue(product); got != want {
+			t.Fatalf("ProductLabelValue(%q) = %q, want %q", product, got, want)
+		}
+	}
+
+	long := ProductLabelValue("NVIDIA " + "a-very-long-product-name-that-goes-on 0123456789 and on and on and on")
+	if len(long) > 63 || long[len(long)-1] == '-' {
+		t.Fatalf("expected a valid label value, got %q", long)
+	}
+}
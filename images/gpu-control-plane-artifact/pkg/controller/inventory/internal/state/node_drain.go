@@ -0,0 +1,40 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import corev1 "k8s.io/api/core/v1"
+
+// NodeDrainReason reports whether the node is going away, either because it is being deleted
+// or because cluster-autoscaler has selected it for scale-down, and returns the condition reason.
+func NodeDrainReason(node *corev1.Node) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	if node.GetDeletionTimestamp() != nil {
+		return ReasonNodeDeleting, true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == ToBeDeletedByAutoscalerKey {
+			return ReasonAutoscalerScaleDown, true
+		}
+	}
+	return "", false
+}
+
+// IsNodeDraining is a shorthand for NodeDrainReason when the reason is not needed.
+func IsNodeDraining(node *corev1.Node) bool {
+	_, draining := NodeDrainReason(node)
+	return draining
+}
@@ -16,32 +16,58 @@ package state
 
 import (
 	"context"
+	"sort"
 	"strconv"
 
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	moduleconfig "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
 )
 
-func FindNodeFeature(ctx context.Context, cl client.Client, nodeName string) (*nfdv1alpha1.NodeFeature, error) {
-	feature := &nfdv1alpha1.NodeFeature{}
-	feature, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: nodeName}, cl, feature)
-	if err != nil {
-		return nil, err
+// FindNodeFeature returns the NodeFeature of the node published in one of the trusted namespaces, preferring the
+// object named after the node. Labelled candidates from other namespaces are ignored and returned as namespace/name,
+// since anyone able to create a NodeFeature there could inject GPU data for a foreign node. An empty trusted list
+// only accepts the object named after the node in the default NFD namespace.
+func FindNodeFeature(ctx context.Context, cl client.Client, nodeName string, trusted []string) (*nfdv1alpha1.NodeFeature, []string, error) {
+	namespaces := trusted
+	if len(namespaces) == 0 {
+		namespaces = []string{moduleconfig.DefaultTrustedNodeFeatureNamespace}
 	}
-	if feature != nil {
-		return feature, nil
+	for _, namespace := range namespaces {
+		feature, err := commonobject.FetchObject(ctx, types.NamespacedName{Namespace: namespace, Name: nodeName}, cl, &nfdv1alpha1.NodeFeature{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if feature != nil {
+			return feature, nil, nil
+		}
 	}
 
 	list := &nfdv1alpha1.NodeFeatureList{}
 	if err := cl.List(ctx, list, client.MatchingLabels{NodeFeatureNodeNameLabel: nodeName}); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	allowed := make(map[string]struct{}, len(trusted))
+	for _, namespace := range trusted {
+		allowed[namespace] = struct{}{}
+	}
+	candidates := make([]nfdv1alpha1.NodeFeature, 0, len(list.Items))
+	var skipped []string
+	for _, item := range list.Items {
+		if _, ok := allowed[item.Namespace]; !ok {
+			skipped = append(skipped, item.Namespace+"/"+item.Name)
+			continue
+		}
+		candidates = append(candidates, item)
 	}
+	sort.Strings(skipped)
 
-	return chooseNodeFeature(list.Items, nodeName), nil
+	return chooseNodeFeature(candidates, nodeName), skipped, nil
 }
 
 func chooseNodeFeature(items []nfdv1alpha1.NodeFeature, nodeName string) *nfdv1alpha1.NodeFeature {
@@ -24,11 +24,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 )
 
 func TestFindNodeFeaturePrefersExactMatch(t *testing.T) {
 	scheme := newTestScheme(t)
-	exact := &nfdv1alpha1.NodeFeature{ObjectMeta: metav1.ObjectMeta{Name: "worker-exact", ResourceVersion: "5"}}
+	exact := &nfdv1alpha1.NodeFeature{ObjectMeta: metav1.ObjectMeta{Name: "worker-exact", Namespace: "gpu-operator", ResourceVersion: "5"}}
 	labeled := &nfdv1alpha1.NodeFeature{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            "worker-exact-labeled",
@@ -39,7 +41,7 @@ func TestFindNodeFeaturePrefersExactMatch(t *testing.T) {
 	}
 
 	client := newTestClient(scheme, exact, labeled)
-	feature, err := FindNodeFeature(context.Background(), client, "worker-exact")
+	feature, _, err := FindNodeFeature(context.Background(), client, "worker-exact", []string{"gpu-operator"})
 	if err != nil {
 		t.Fatalf("findNodeFeature returned error: %v", err)
 	}
@@ -68,7 +70,7 @@ func TestFindNodeFeatureSelectsNewestByResourceVersion(t *testing.T) {
 	}
 
 	client := newTestClient(scheme, older, newer)
-	feature, err := FindNodeFeature(context.Background(), client, "worker-rv")
+	feature, _, err := FindNodeFeature(context.Background(), client, "worker-rv", []string{"gpu-operator"})
 	if err != nil {
 		t.Fatalf("findNodeFeature returned error: %v", err)
 	}
@@ -77,8 +79,78 @@ func TestFindNodeFeatureSelectsNewestByResourceVersion(t *testing.T) {
 	}
 }
 
+func TestFindNodeFeatureIgnoresUntrustedNamespaces(t *testing.T) {
+	scheme := newTestScheme(t)
+	legit := &nfdv1alpha1.NodeFeature{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "worker-trust-nfd",
+			Namespace:       "nfd",
+			ResourceVersion: "5",
+			Labels:          map[string]string{NodeFeatureNodeNameLabel: "worker-trust"},
+		},
+	}
+	spoofed := &nfdv1alpha1.NodeFeature{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "worker-trust",
+			Namespace:       "tenant",
+			ResourceVersion: "9",
+			Labels:          map[string]string{NodeFeatureNodeNameLabel: "worker-trust"},
+		},
+	}
+
+	client := newTestClient(scheme, legit, spoofed)
+	feature, skipped, err := FindNodeFeature(context.Background(), client, "worker-trust", []string{"nfd"})
+	if err != nil {
+		t.Fatalf("findNodeFeature returned error: %v", err)
+	}
+	if feature == nil || feature.GetNamespace() != "nfd" {
+		t.Fatalf("expected the NodeFeature from the trusted namespace, got %+v", feature)
+	}
+	if len(skipped) != 1 || skipped[0] != "tenant/worker-trust" {
+		t.Fatalf("expected the spoofed candidate to be reported, got %v", skipped)
+	}
+
+	feature, skipped, err = FindNodeFeature(context.Background(), client, "worker-trust", []string{"other"})
+	if err != nil {
+		t.Fatalf("findNodeFeature returned error: %v", err)
+	}
+	if feature != nil || len(skipped) != 2 {
+		t.Fatalf("expected no NodeFeature and both candidates skipped, got %+v %v", feature, skipped)
+	}
+}
+
+func TestFindNodeFeatureEmptyTrustListAcceptsOnlyDefaultExactName(t *testing.T) {
+	scheme := newTestScheme(t)
+	exact := &nfdv1alpha1.NodeFeature{ObjectMeta: metav1.ObjectMeta{
+		Name:      "worker-default",
+		Namespace: moduleconfig.DefaultTrustedNodeFeatureNamespace,
+	}}
+	labeled := &nfdv1alpha1.NodeFeature{ObjectMeta: metav1.ObjectMeta{
+		Name:      "worker-labeled-nfd",
+		Namespace: moduleconfig.DefaultTrustedNodeFeatureNamespace,
+		Labels:    map[string]string{NodeFeatureNodeNameLabel: "worker-labeled"},
+	}}
+
+	client := newTestClient(scheme, exact, labeled)
+	feature, skipped, err := FindNodeFeature(context.Background(), client, "worker-default", []string{})
+	if err != nil {
+		t.Fatalf("findNodeFeature returned error: %v", err)
+	}
+	if feature == nil || feature.GetName() != "worker-default" || len(skipped) != 0 {
+		t.Fatalf("expected the exact-name default-namespace NodeFeature, got %+v %v", feature, skipped)
+	}
+
+	feature, skipped, err = FindNodeFeature(context.Background(), client, "worker-labeled", []string{})
+	if err != nil {
+		t.Fatalf("findNodeFeature returned error: %v", err)
+	}
+	if feature != nil || len(skipped) != 1 {
+		t.Fatalf("expected labelled candidates to be ignored with an empty trust list, got %+v %v", feature, skipped)
+	}
+}
+
 func TestFindNodeFeatureReturnsNilWhenMissing(t *testing.T) {
-	feature, err := FindNodeFeature(context.Background(), newTestClient(newTestScheme(t)), "absent")
+	feature, _, err := FindNodeFeature(context.Background(), newTestClient(newTestScheme(t)), "absent", nil)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -123,7 +195,7 @@ func TestFindNodeFeatureGetError(t *testing.T) {
 		},
 	}
 
-	_, err := FindNodeFeature(context.Background(), client, "node-error")
+	_, _, err := FindNodeFeature(context.Background(), client, "node-error", nil)
 	if !errors.Is(err, boom) {
 		t.Fatalf("expected get error, got %v", err)
 	}
@@ -143,7 +215,7 @@ func TestFindNodeFeatureListError(t *testing.T) {
 		},
 	}
 
-	_, err := FindNodeFeature(context.Background(), client, "node-list")
+	_, _, err := FindNodeFeature(context.Background(), client, "node-list", nil)
 	if !errors.Is(err, boom) {
 		t.Fatalf("expected list error, got %v", err)
 	}
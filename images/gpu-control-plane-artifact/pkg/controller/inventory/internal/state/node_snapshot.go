@@ -20,89 +20,74 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/deviceprovider"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
 )
 
+// buildNodeSnapshot adapts the Node and its NodeFeature to the hardware parser and evaluates
+// the managed-node policy on the merged labels.
 func buildNodeSnapshot(node *corev1.Node, feature *nfdv1alpha1.NodeFeature, policy ManagedNodesPolicy) nodeSnapshot {
-	labels := map[string]string{}
-	for key, value := range node.Labels {
-		labels[key] = value
-	}
+	in := snapshot.Input{NodeLabels: node.Labels, Vendors: deviceprovider.Default()}
 	if feature != nil {
-		for key, value := range feature.Spec.Labels {
-			if _, ok := labels[key]; !ok {
-				labels[key] = value
+		in.FeatureLabels = feature.Spec.Labels
+		if set, ok := feature.Spec.Features.Instances[snapshot.GPUInstanceFeature]; ok {
+			for _, inst := range set.Elements {
+				in.Instances = append(in.Instances, inst.Attributes)
 			}
 		}
 	}
+	parsed := snapshot.Parse(in)
 
-	devices := extractDeviceSnapshots(labels)
-	defaults := parseHardwareDefaults(labels)
-
-	for i := range devices {
-		if devices[i].Product == "" {
-			devices[i].Product = defaults.Product
-		}
-		if devices[i].NUMANode == nil && defaults.NUMANode != nil {
-			devices[i].NUMANode = defaults.NUMANode
-		}
-		if devices[i].PowerLimitMW == nil && defaults.PowerLimitMW != nil {
-			devices[i].PowerLimitMW = defaults.PowerLimitMW
-		}
-		if devices[i].SMCount == nil && defaults.SMCount != nil {
-			devices[i].SMCount = defaults.SMCount
-		}
-		if devices[i].MemBandwidth == nil && defaults.MemBandwidth != nil {
-			devices[i].MemBandwidth = defaults.MemBandwidth
-		}
-		if devices[i].PCIEGen == nil && defaults.PCIEGen != nil {
-			devices[i].PCIEGen = defaults.PCIEGen
-		}
-		if devices[i].PCIELinkWid == nil && defaults.PCIELinkWid != nil {
-			devices[i].PCIELinkWid = defaults.PCIELinkWid
-		}
-		if devices[i].Board == "" {
-			devices[i].Board = defaults.Board
-		}
-		if devices[i].Family == "" {
-			devices[i].Family = defaults.Family
-		}
-		if devices[i].Serial == "" {
-			devices[i].Serial = defaults.Serial
-		}
-		if devices[i].PState == "" {
-			devices[i].PState = defaults.PState
-		}
-		if devices[i].DisplayMode == "" {
-			devices[i].DisplayMode = defaults.DisplayMode
-		}
-		if devices[i].MemoryMiB == 0 {
-			devices[i].MemoryMiB = defaults.MemoryMiB
-		}
-		if devices[i].ComputeMajor == 0 {
-			devices[i].ComputeMajor = defaults.ComputeMajor
-		}
-		if devices[i].ComputeMinor == 0 {
-			devices[i].ComputeMinor = defaults.ComputeMinor
-		}
-		if migConfigEmpty(devices[i].MIG) {
-			devices[i].MIG = defaults.MIG
-		}
+	return nodeSnapshot{
+		Managed:            nodeManaged(parsed.Labels, policy),
+		FeatureDetected:    feature != nil,
+		Driver:             parsed.Driver,
+		Devices:            parsed.Devices,
+		Labels:             parsed.Labels,
+		UnmigratedLabelKey: unmigratedLabelKey(parsed.Labels, policy),
+		Warnings:           parsed.Warnings,
+		Errors:             parsed.Errors,
+		Provenance:         featureProvenance(feature),
 	}
-	devices = enrichDevicesFromFeature(devices, feature)
-	enrichDevicesFromCatalog(devices)
+}
 
-	return nodeSnapshot{
-		Managed:         nodeManaged(labels, policy),
-		FeatureDetected: feature != nil,
-		Driver:          parseDriverInfo(labels),
-		Devices:         devices,
-		Labels:          labels,
+// featureProvenance identifies the NodeFeature revision; the capture time is stamped by the writer.
+func featureProvenance(feature *nfdv1alpha1.NodeFeature) *v1alpha1.GPUDataProvenance {
+	if feature == nil {
+		return nil
+	}
+	return &v1alpha1.GPUDataProvenance{
+		NodeFeature:     feature.Namespace + "/" + feature.Name,
+		ResourceVersion: feature.ResourceVersion,
+		Generation:      feature.Generation,
 	}
 }
 
+// nodeManaged evaluates the managed-node label. During a label key transition either key is
+// authoritative and the previous one wins on conflict, so relabeling never flips a node.
 func nodeManaged(labels map[string]string, policy ManagedNodesPolicy) bool {
+	if policy.PreviousLabelKey != "" {
+		if val, ok := labels[policy.PreviousLabelKey]; ok {
+			return !strings.EqualFold(val, "false")
+		}
+	}
 	if val, ok := labels[policy.LabelKey]; ok {
 		return !strings.EqualFold(val, "false")
 	}
 	return policy.EnabledByDefault
 }
+
+func unmigratedLabelKey(labels map[string]string, policy ManagedNodesPolicy) string {
+	if policy.PreviousLabelKey == "" {
+		return ""
+	}
+	if _, ok := labels[policy.PreviousLabelKey]; !ok {
+		return ""
+	}
+	if _, ok := labels[policy.LabelKey]; ok {
+		return ""
+	}
+	return policy.PreviousLabelKey
+}
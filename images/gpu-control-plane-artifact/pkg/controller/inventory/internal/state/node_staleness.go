@@ -0,0 +1,94 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StalenessPolicy decides what happens to the inventory of a node that stays NotReady.
+// A zero Threshold disables the policy; a zero Retention keeps unreachable devices forever.
+type StalenessPolicy struct {
+	// Threshold is how long a node may stay NotReady before its devices are marked unreachable.
+	Threshold time.Duration
+	// Retention is how long unreachable devices are kept before they are deleted.
+	Retention time.Duration
+}
+
+// NodeStaleness is the outcome of evaluating a StalenessPolicy for a node at a point in time.
+type NodeStaleness struct {
+	// NotReadySince is the last transition time of the node Ready condition; zero for ready nodes.
+	NotReadySince time.Time
+	// Unreachable means the node has been NotReady for at least the threshold.
+	Unreachable bool
+	// Expired means the unreachable devices outlived the retention and should be deleted.
+	Expired bool
+	// RequeueAfter is the time left until the next transition, zero when none is pending.
+	RequeueAfter time.Duration
+}
+
+// Evaluate reports how long the node has been NotReady relative to the policy thresholds.
+func (p StalenessPolicy) Evaluate(node *corev1.Node, now time.Time) NodeStaleness {
+	if p.Threshold <= 0 {
+		return NodeStaleness{}
+	}
+	since, notReady := NodeNotReadySince(node)
+	if !notReady {
+		return NodeStaleness{}
+	}
+
+	result := NodeStaleness{NotReadySince: since}
+	elapsed := now.Sub(since)
+	if elapsed < p.Threshold {
+		result.RequeueAfter = p.Threshold - elapsed
+		return result
+	}
+	result.Unreachable = true
+	if p.Retention <= 0 {
+		return result
+	}
+	if deadline := p.Threshold + p.Retention; elapsed < deadline {
+		result.RequeueAfter = deadline - elapsed
+	} else {
+		result.Expired = true
+	}
+	return result
+}
+
+// NodeNotReadySince returns the time the node Ready condition left True. Nodes without a Ready
+// condition have not reported yet and are not considered NotReady.
+func NodeNotReadySince(node *corev1.Node) (time.Time, bool) {
+	if node == nil {
+		return time.Time{}, false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != corev1.NodeReady {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue {
+			return time.Time{}, false
+		}
+		return cond.LastTransitionTime.Time, true
+	}
+	return time.Time{}, false
+}
+
+// IsNodeReady is a shorthand for NodeNotReadySince when the transition time is not needed.
+func IsNodeReady(node *corev1.Node) bool {
+	_, notReady := NodeNotReadySince(node)
+	return !notReady
+}
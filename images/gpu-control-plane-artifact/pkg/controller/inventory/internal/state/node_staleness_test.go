@@ -0,0 +1,113 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStalenessPolicyEvaluate(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	nodeNotReadyFor := func(d time.Duration) *corev1.Node {
+		return &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+			Type:               corev1.NodeReady,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(now.Add(-d)),
+		}}}}
+	}
+	policy := StalenessPolicy{Threshold: 24 * time.Hour, Retention: 48 * time.Hour}
+
+	tests := []struct {
+		name   string
+		policy StalenessPolicy
+		node   *corev1.Node
+		want   NodeStaleness
+	}{
+		{
+			name:   "disabled",
+			policy: StalenessPolicy{},
+			node:   nodeNotReadyFor(100 * time.Hour),
+			want:   NodeStaleness{},
+		},
+		{
+			name:   "ready node",
+			policy: policy,
+			node: &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+				Type: corev1.NodeReady, Status: corev1.ConditionTrue,
+			}}}},
+			want: NodeStaleness{},
+		},
+		{
+			name:   "below threshold",
+			policy: policy,
+			node:   nodeNotReadyFor(time.Hour),
+			want:   NodeStaleness{NotReadySince: now.Add(-time.Hour), RequeueAfter: 23 * time.Hour},
+		},
+		{
+			name:   "unreachable within retention",
+			policy: policy,
+			node:   nodeNotReadyFor(30 * time.Hour),
+			want:   NodeStaleness{NotReadySince: now.Add(-30 * time.Hour), Unreachable: true, RequeueAfter: 42 * time.Hour},
+		},
+		{
+			name:   "unreachable without retention",
+			policy: StalenessPolicy{Threshold: 24 * time.Hour},
+			node:   nodeNotReadyFor(300 * time.Hour),
+			want:   NodeStaleness{NotReadySince: now.Add(-300 * time.Hour), Unreachable: true},
+		},
+		{
+			name:   "expired",
+			policy: policy,
+			node:   nodeNotReadyFor(72 * time.Hour),
+			want:   NodeStaleness{NotReadySince: now.Add(-72 * time.Hour), Unreachable: true, Expired: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Evaluate(tt.node, now)
+			if !got.NotReadySince.Equal(tt.want.NotReadySince) || got.Unreachable != tt.want.Unreachable ||
+				got.Expired != tt.want.Expired || got.RequeueAfter != tt.want.RequeueAfter {
+				t.Fatalf("unexpected staleness: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeNotReadySince(t *testing.T) {
+	if _, notReady := NodeNotReadySince(nil); notReady {
+		t.Fatalf("nil node must not be reported NotReady")
+	}
+	if !IsNodeReady(&corev1.Node{}) {
+		t.Fatalf("node without Ready condition must be treated as ready")
+	}
+
+	since := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	node := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+		{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+		{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, LastTransitionTime: since},
+	}}}
+	got, notReady := NodeNotReadySince(node)
+	if !notReady || !got.Equal(since.Time) {
+		t.Fatalf("unexpected NotReady since: %v %v", got, notReady)
+	}
+	if IsNodeReady(node) {
+		t.Fatalf("Ready=Unknown must not be reported ready")
+	}
+}
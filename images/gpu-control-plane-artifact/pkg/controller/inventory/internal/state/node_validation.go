@@ -0,0 +1,50 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// NodeValidation is the validator verdict the bootstrap controller recorded on a GPUNodeState.
+type NodeValidation struct {
+	// Passed is set while both DriverReady and ToolkitReady are True.
+	Passed bool
+	// Since is the moment the later of the two conditions became True, i.e. when the passing run landed.
+	Since time.Time
+}
+
+// NodeValidationFromState reads the validator verdict of a node; a missing GPUNodeState has not passed.
+func NodeValidationFromState(state *v1alpha1.GPUNodeState) NodeValidation {
+	if state == nil {
+		return NodeValidation{}
+	}
+	var since time.Time
+	for _, conditionType := range []string{NodeConditionDriverReady, NodeConditionToolkitReady} {
+		cond := meta.FindStatusCondition(state.Status.Conditions, conditionType)
+		if cond == nil || cond.Status != metav1.ConditionTrue {
+			return NodeValidation{}
+		}
+		if cond.LastTransitionTime.After(since) {
+			since = cond.LastTransitionTime.Time
+		}
+	}
+	return NodeValidation{Passed: true, Since: since}
+}
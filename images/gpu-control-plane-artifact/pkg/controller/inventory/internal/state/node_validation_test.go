@@ -0,0 +1,49 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestNodeValidationFromState(t *testing.T) {
+	earlier := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+	state := func(driver, toolkit metav1.ConditionStatus) *v1alpha1.GPUNodeState {
+		return &v1alpha1.GPUNodeState{Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{
+			{Type: NodeConditionDriverReady, Status: driver, LastTransitionTime: metav1.NewTime(earlier)},
+			{Type: NodeConditionToolkitReady, Status: toolkit, LastTransitionTime: metav1.NewTime(later)},
+		}}}
+	}
+
+	if got := NodeValidationFromState(nil); got.Passed {
+		t.Fatalf("missing node state must not pass: %+v", got)
+	}
+	if got := NodeValidationFromState(state(metav1.ConditionTrue, metav1.ConditionFalse)); got.Passed {
+		t.Fatalf("toolkit not ready must not pass: %+v", got)
+	}
+	if got := NodeValidationFromState(&v1alpha1.GPUNodeState{}); got.Passed {
+		t.Fatalf("node state without validator conditions must not pass: %+v", got)
+	}
+	got := NodeValidationFromState(state(metav1.ConditionTrue, metav1.ConditionTrue))
+	if !got.Passed || !got.Since.Equal(later) {
+		t.Fatalf("expected a pass since the later transition, got %+v", got)
+	}
+}
@@ -22,21 +22,26 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 	moduleconfig "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 )
 
 type ManagedNodesPolicy struct {
 	LabelKey         string
 	EnabledByDefault bool
+	// PreviousLabelKey is set only while the label key transition window is open.
+	PreviousLabelKey string
 }
 
 type DeviceApprovalPolicy struct {
 	Mode     moduleconfig.DeviceApprovalMode
 	Selector labels.Selector
+	// RequireValidation holds AutoAttach back until the node passed a validator run.
+	RequireValidation bool
 }
 
 func NewDeviceApprovalPolicy(cfg moduleconfig.DeviceApprovalSettings) (DeviceApprovalPolicy, error) {
-	policy := DeviceApprovalPolicy{Mode: cfg.Mode}
+	policy := DeviceApprovalPolicy{Mode: cfg.Mode, RequireValidation: cfg.RequireValidation}
 	if policy.Mode == "" {
 		policy.Mode = moduleconfig.DeviceApprovalModeManual
 	}
@@ -51,7 +56,7 @@ func NewDeviceApprovalPolicy(cfg moduleconfig.DeviceApprovalSettings) (DeviceApp
 		}
 		compiled, err := metav1.LabelSelectorAsSelector(selector)
 		if err != nil {
-			return DeviceApprovalPolicy{}, fmt.Errorf("compile device approval selector: %w", err)
+			return DeviceApprovalPolicy{}, commonerrors.Wrap(commonerrors.ErrConfigInvalid, fmt.Errorf("compile device approval selector: %w", err))
 		}
 		policy.Selector = compiled
 		return policy, nil
@@ -15,12 +15,14 @@
 package state
 
 import (
+	"errors"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 )
 
@@ -64,8 +66,8 @@ func TestNewDeviceApprovalPolicySelectorCompileError(t *testing.T) {
 			},
 		},
 	})
-	if err == nil {
-		t.Fatal("expected selector compile error")
+	if !errors.Is(err, commonerrors.ErrConfigInvalid) {
+		t.Fatalf("expected ErrConfigInvalid, got %v", err)
 	}
 }
 
@@ -134,6 +134,24 @@ func TestBuildNodeSnapshotManagedDisabled(t *testing.T) {
 	if snapshot.FeatureDetected {
 		t.Fatal("expected feature detected to be false without NodeFeature")
 	}
+	if snapshot.Provenance != nil {
+		t.Fatalf("expected no provenance without NodeFeature, got %+v", snapshot.Provenance)
+	}
+}
+
+func TestBuildNodeSnapshotRecordsFeatureProvenance(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-prov"}}
+	feature := &nfdv1alpha1.NodeFeature{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "d8-nfd", Name: "node-prov", ResourceVersion: "42", Generation: 7},
+	}
+
+	prov := buildNodeSnapshot(node, feature, defaultManagedPolicy()).Provenance
+	if prov == nil || prov.NodeFeature != "d8-nfd/node-prov" || prov.ResourceVersion != "42" || prov.Generation != 7 {
+		t.Fatalf("unexpected provenance %+v", prov)
+	}
+	if !prov.CapturedAt.IsZero() {
+		t.Fatalf("capture time is stamped by the writer, got %v", prov.CapturedAt)
+	}
 }
 
 func TestCanonicalIndexNormalization(t *testing.T) {
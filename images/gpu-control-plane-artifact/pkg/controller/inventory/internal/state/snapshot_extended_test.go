@@ -66,38 +66,29 @@ func TestBuildNodeSnapshotFillsHardwareDefaults(t *testing.T) {
 	}
 }
 
-func TestEnrichDevicesFromFeatureOverridesHardware(t *testing.T) {
-	devices := []deviceSnapshot{{
-		Index: "0", Vendor: "10de", Device: "1db4", Class: "0300",
-		NUMANode: ptrInt32(0),
-	}}
-
+func TestBuildNodeSnapshotReportsMalformedAttributes(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "worker-1",
+			Labels: map[string]string{
+				"gpu.deckhouse.io/device.0.vendor": "10de",
+				"gpu.deckhouse.io/device.0.device": "1db4",
+				"gpu.deckhouse.io/device.0.class":  "0300",
+			},
+		},
+	}
 	feature := &nfdv1alpha1.NodeFeature{
 		Spec: nfdv1alpha1.NodeFeatureSpec{
 			Features: nfdv1alpha1.Features{
 				Instances: map[string]nfdv1alpha1.InstanceFeatureSet{
 					"nvidia.com/gpu": {
 						Elements: []nfdv1alpha1.InstanceFeature{
-							{
-								Attributes: map[string]string{
-									"index":            "0",
-									"vendor":           "10de",
-									"device":           "1db4",
-									"class":            "0300",
-									"pci.address":      "0000:01:00.0",
-									"numa.node":        "2",
-									"power.limit":      "200",
-									"sm.count":         "80",
-									"memory.bandwidth": "1600",
-									"pcie.gen":         "5",
-									"pcie.link.width":  "8",
-									"board":            "board-2",
-									"family":           "hopper",
-									"serial":           "serial-2",
-									"pstate":           "P2",
-									"display_mode":     "Disabled",
-								},
-							},
+							{Attributes: map[string]string{"index": "0", "memory.total": "unknown"}},
+						},
+					},
+					"example.com/other": {
+						Elements: []nfdv1alpha1.InstanceFeature{
+							{Attributes: map[string]string{"index": "1", "vendor": "10de", "device": "2230", "class": "0302"}},
 						},
 					},
 				},
@@ -105,38 +96,21 @@ func TestEnrichDevicesFromFeatureOverridesHardware(t *testing.T) {
 		},
 	}
 
-	res := enrichDevicesFromFeature(devices, feature)
-	if len(res) != 1 {
-		t.Fatalf("expected one device, got %d", len(res))
+	snapshot := buildNodeSnapshot(node, feature, ManagedNodesPolicy{EnabledByDefault: true})
+	if len(snapshot.Devices) != 1 || snapshot.Devices[0].MemoryMiB != 0 {
+		t.Fatalf("expected foreign instances to be ignored and memory left unset, got %+v", snapshot.Devices)
 	}
-	dev := res[0]
-	assertIntPtr(t, dev.NUMANode, 2)
-	assertIntPtr(t, dev.PowerLimitMW, 200)
-	assertIntPtr(t, dev.SMCount, 80)
-	assertIntPtr(t, dev.MemBandwidth, 1600)
-	assertIntPtr(t, dev.PCIEGen, 5)
-	assertIntPtr(t, dev.PCIELinkWid, 8)
-	if dev.PCIAddress != "0000:01:00.0" {
-		t.Fatalf("expected pci address propagated, got %q", dev.PCIAddress)
+	if len(snapshot.Warnings) != 1 || snapshot.Warnings[0].Key != "memory.total" || snapshot.Warnings[0].Source != "instance/0" {
+		t.Fatalf("expected malformed memory to be reported, got %+v", snapshot.Warnings)
 	}
-	if dev.Board != "board-2" || dev.Family != "hopper" || dev.Serial != "serial-2" || dev.PState != "P2" || dev.DisplayMode != "Disabled" {
-		t.Fatalf("unexpected feature board/family/serial/pstate/display: %+v", dev)
+	if len(snapshot.Errors) != 0 {
+		t.Fatalf("unexpected errors: %+v", snapshot.Errors)
 	}
 }
 
-func TestParseOptionalInt32(t *testing.T) {
-	if val := parseOptionalInt32(""); val != nil {
-		t.Fatalf("expected nil for empty value, got %v", val)
-	}
-	val := parseOptionalInt32("42")
-	assertIntPtr(t, val, 42)
-}
-
 func assertIntPtr(t *testing.T, ptr *int32, expected int32) {
 	t.Helper()
 	if ptr == nil || *ptr != expected {
 		t.Fatalf("expected %d, got %v", expected, ptr)
 	}
 }
-
-func ptrInt32(v int32) *int32 { return &v }
@@ -15,42 +15,10 @@
 package state
 
 import (
-	"reflect"
 	"strings"
 	"testing"
 )
 
-func TestSortDeviceSnapshotsOrdersIndices(t *testing.T) {
-	devices := []deviceSnapshot{{Index: "10"}, {Index: "2"}, {Index: "001"}}
-	sortDeviceSnapshots(devices)
-	got := []string{devices[0].Index, devices[1].Index, devices[2].Index}
-	want := []string{"001", "10", "2"}
-	if !reflect.DeepEqual(got, want) {
-		t.Fatalf("unexpected order: %v", got)
-	}
-}
-
-func TestDeduplicateStrings(t *testing.T) {
-	input := []string{"fp32", "fp16", "fp32", "tf32"}
-	deduped := deduplicateStrings(input)
-	if !reflect.DeepEqual(deduped, []string{"fp32", "fp16", "tf32"}) {
-		t.Fatalf("unexpected deduplicate result: %v", deduped)
-	}
-}
-
-func TestCanonicalIndexVariants(t *testing.T) {
-	cases := map[string]string{
-		"":    "0",
-		"01":  "1",
-		"A12": "A12",
-	}
-	for in, want := range cases {
-		if got := canonicalIndex(in); got != want {
-			t.Fatalf("canonicalIndex(%q)=%q, want %q", in, got, want)
-		}
-	}
-}
-
 func TestTruncateNameLimitsLength(t *testing.T) {
 	long := strings.Repeat("a", 80)
 	if len(truncateName(long)) != 63 {
@@ -73,3 +41,26 @@ func TestBuildDeviceAndInventoryNames(t *testing.T) {
 		t.Fatalf("expected fallback device name, got %s", fallback)
 	}
 }
+
+func TestBuildTemplatedDeviceName(t *testing.T) {
+	info := deviceSnapshot{Index: "0", Vendor: "10de", Device: "1db5", UUID: "GPU-1A2B3C4D-5e6f-0000-0000-000000000000"}
+	if got, want := buildTemplatedDeviceName("", "Node_A", info), buildDeviceName("Node_A", info); got != want {
+		t.Fatalf("expected empty template to keep legacy name %s, got %s", want, got)
+	}
+	if got := buildTemplatedDeviceName("{node}-gpu-{uuid8}", "Node_A", info); got != "node-a-gpu-1a2b3c4d" {
+		t.Fatalf("unexpected templated name %s", got)
+	}
+	if got := buildTemplatedDeviceName("{node}.{index}", "Node_A", info); got != "node-a.0" {
+		t.Fatalf("unexpected templated name %s", got)
+	}
+
+	info.UUID = ""
+	if got, want := buildTemplatedDeviceName("{node}-gpu-{uuid8}", "Node_A", info), buildDeviceName("Node_A", info); got != want {
+		t.Fatalf("expected fallback to legacy name %s without UUID, got %s", want, got)
+	}
+
+	long := buildTemplatedDeviceName("{node}-{index}-"+strings.Repeat("x", 250), "node", info)
+	if len(long) > 253 || strings.HasSuffix(long, "-") {
+		t.Fatalf("expected name to be truncated to a valid subdomain, got %d chars", len(long))
+	}
+}
@@ -14,7 +14,10 @@
 
 package state
 
-import v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+import (
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
 
 type nodeSnapshot struct {
 	Managed         bool
@@ -22,37 +25,21 @@ type nodeSnapshot struct {
 	Driver          nodeDriverSnapshot
 	Devices         []deviceSnapshot
 	Labels          map[string]string
+	// UnmigratedLabelKey is the previous managed-node label key when the node carries only it.
+	UnmigratedLabelKey string
+	// Warnings and Errors report malformed GPU labels and attributes found while parsing.
+	Warnings []snapshot.Issue
+	Errors   []snapshot.Issue
+	// Provenance identifies the NodeFeature revision the snapshot was built from; nil without a NodeFeature.
+	Provenance *v1alpha1.GPUDataProvenance
+	// Platform is the host description gfd-extender reported in this reconcile; nil keeps the persisted one.
+	Platform *v1alpha1.GPUNodePlatform
+	// DisplayDevices are the display-only adapters split off Devices in this reconcile.
+	DisplayDevices []v1alpha1.GPUNodeDisplayDevice
+	// Compatibility lists the compatibility rules the node matched in this reconcile.
+	Compatibility CompatibilityVerdict
 }
 
-type nodeDriverSnapshot struct {
-	Version          string
-	CUDAVersion      string
-	ToolkitInstalled bool
-	ToolkitReady     bool
-}
+type nodeDriverSnapshot = snapshot.Driver
 
-type deviceSnapshot struct {
-	Index        string
-	Vendor       string
-	Device       string
-	Class        string
-	PCIAddress   string
-	Product      string
-	MemoryMiB    int32
-	ComputeMajor int32
-	ComputeMinor int32
-	UUID         string
-	Precision    []string
-	NUMANode     *int32
-	PowerLimitMW *int32
-	SMCount      *int32
-	MemBandwidth *int32
-	PCIEGen      *int32
-	PCIELinkWid  *int32
-	Board        string
-	Family       string
-	Serial       string
-	PState       string
-	DisplayMode  string
-	MIG          v1alpha1.GPUMIGConfig
-}
+type deviceSnapshot = snapshot.Device
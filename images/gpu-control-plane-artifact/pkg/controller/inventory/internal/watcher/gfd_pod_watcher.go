@@ -36,8 +36,9 @@ type GFDPodWatcher struct {
 	gfdApp string
 }
 
+// NewGFDPodWatcher watches the gfd-extender pods, whose readiness decides when detections can be scraped.
 func NewGFDPodWatcher() *GFDPodWatcher {
-	return &GFDPodWatcher{gfdApp: common.AppName(common.ComponentGPUFeatureDiscovery)}
+	return &GFDPodWatcher{gfdApp: common.AppName(common.ComponentGFDExtender)}
 }
 
 func (w *GFDPodWatcher) Watch(mgr manager.Manager, ctr controller.Controller) error {
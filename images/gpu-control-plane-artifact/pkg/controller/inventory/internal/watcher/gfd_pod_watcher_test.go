@@ -28,7 +28,7 @@ import (
 
 func TestNewGFDPodWatcher(t *testing.T) {
 	w := NewGFDPodWatcher()
-	if w.gfdApp != common.AppName(common.ComponentGPUFeatureDiscovery) {
+	if w.gfdApp != common.AppName(common.ComponentGFDExtender) {
 		t.Fatalf("unexpected gfd app label: %q", w.gfdApp)
 	}
 }
@@ -51,7 +51,7 @@ func TestMapGFDPodToNode(t *testing.T) {
 }
 
 func TestIsGFDPod(t *testing.T) {
-	gfdApp := common.AppName(common.ComponentGPUFeatureDiscovery)
+	gfdApp := common.AppName(common.ComponentGFDExtender)
 
 	if isGFDPod(nil, gfdApp) {
 		t.Fatalf("expected nil pod to not match")
@@ -88,7 +88,7 @@ func TestIsPodReady(t *testing.T) {
 }
 
 func TestGFDPodPredicatesBranches(t *testing.T) {
-	gfdApp := common.AppName(common.ComponentGPUFeatureDiscovery)
+	gfdApp := common.AppName(common.ComponentGFDExtender)
 	p := gfdPodPredicates(gfdApp)
 
 	readyPod := &corev1.Pod{
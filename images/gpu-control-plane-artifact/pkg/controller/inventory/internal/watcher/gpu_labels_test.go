@@ -111,6 +111,15 @@ func TestGpuLabelDifferenceDetection(t *testing.T) {
 		t.Fatalf("added relevant GPU label should be detected")
 	}
 
+	toolkitUpgrade := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "10de",
+		"nvidia.com/mig-1g.10gb.count":      "1",
+		"gpu.deckhouse.io/toolkit.version":  "1.14.3",
+	}
+	if !gpuLabelsDiffer(base, toolkitUpgrade) {
+		t.Fatalf("toolkit version change should be detected")
+	}
+
 	irrelevantChange := map[string]string{
 		"gpu.deckhouse.io/device.00.vendor": "10de",
 		"nvidia.com/mig-1g.10gb.count":      "1",
@@ -0,0 +1,138 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// ModuleConfigWatcher requeues every node with GPU devices after a ModuleConfig settings change. The requeues
+// are spread over inventory.requeueSpreadWindow so a change does not reconcile the whole fleet at once.
+type ModuleConfigWatcher struct {
+	log     logr.Logger
+	store   *moduleconfig.ModuleConfigStore
+	tracker *PropagationTracker
+	jitter  func(n int64) int64
+}
+
+func NewModuleConfigWatcher(log logr.Logger, store *moduleconfig.ModuleConfigStore, tracker *PropagationTracker) *ModuleConfigWatcher {
+	return &ModuleConfigWatcher{log: log, store: store, tracker: tracker, jitter: rand.Int63n}
+}
+
+func (w *ModuleConfigWatcher) Watch(mgr manager.Manager, ctr controller.Controller) error {
+	if w.store == nil {
+		return nil
+	}
+	return ctr.Watch(source.Channel(w.store.SettingsEvents(), &spreadRequeueHandler{watcher: w, reader: mgr.GetClient()}))
+}
+
+// Requeue adds every affected node to the queue after its spread delay and hands the nodes to the tracker.
+func (w *ModuleConfigWatcher) Requeue(ctx context.Context, reader client.Reader, q workqueue.RateLimitingInterface) int {
+	generation, updated := w.store.Generation()
+	nodes := w.affectedNodes(ctx, reader)
+	w.tracker.Expect(generation, updated, nodes)
+
+	window := w.store.Current().Inventory.RequeueSpreadWindow
+	for i, delay := range spreadDelays(len(nodes), window, w.jitter) {
+		q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Name: nodes[i]}}, delay)
+	}
+	if len(nodes) > 0 && w.log.GetSink() != nil {
+		w.log.Info("requeueing nodes after module settings change", "nodes", len(nodes), "window", window.String(), "generation", generation)
+	}
+	return len(nodes)
+}
+
+// affectedNodes returns the sorted names of the nodes that own at least one GPUDevice.
+func (w *ModuleConfigWatcher) affectedNodes(ctx context.Context, reader client.Reader) []string {
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := reader.List(ctx, devices); err != nil {
+		if w.log.GetSink() != nil {
+			w.log.Error(err, "list GPU devices to requeue after module settings change")
+		}
+		return nil
+	}
+	seen := make(map[string]struct{}, len(devices.Items))
+	nodes := make([]string, 0, len(devices.Items))
+	for i := range devices.Items {
+		node := strings.TrimSpace(devices.Items[i].Status.NodeName)
+		if node == "" {
+			continue
+		}
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// spreadDelays splits window into n equal slots and returns a delay at a random point of every slot, so the
+// requeues are evenly spread but do not line up across controller restarts. A zero window requeues at once.
+func spreadDelays(n int, window time.Duration, jitter func(n int64) int64) []time.Duration {
+	delays := make([]time.Duration, n)
+	if n == 0 || window <= 0 {
+		return delays
+	}
+	slot := window / time.Duration(n)
+	for i := range delays {
+		delays[i] = time.Duration(i) * slot
+		if slot > 0 {
+			delays[i] += time.Duration(jitter(int64(slot)))
+		}
+	}
+	return delays
+}
+
+// spreadRequeueHandler turns the settings event, which carries no object, into delayed per-node requeues;
+// handler.EnqueueRequestsFromMapFunc only adds requests immediately.
+type spreadRequeueHandler struct {
+	watcher *ModuleConfigWatcher
+	reader  client.Reader
+}
+
+var _ handler.EventHandler = (*spreadRequeueHandler)(nil)
+
+func (h *spreadRequeueHandler) Create(context.Context, event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *spreadRequeueHandler) Update(context.Context, event.UpdateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *spreadRequeueHandler) Delete(context.Context, event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *spreadRequeueHandler) Generic(ctx context.Context, _ event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.watcher.Requeue(ctx, h.reader, q)
+}
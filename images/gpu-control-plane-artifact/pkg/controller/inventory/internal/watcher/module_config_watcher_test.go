@@ -0,0 +1,150 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+type delayRecordingQueue struct {
+	workqueue.RateLimitingInterface
+	added map[string]time.Duration
+}
+
+func (q *delayRecordingQueue) AddAfter(item interface{}, delay time.Duration) {
+	q.added[item.(reconcile.Request).Name] = delay
+}
+
+type clientManager struct {
+	stubManager
+	client client.Client
+}
+
+func (m *clientManager) GetClient() client.Client { return m.client }
+
+type failingListReader struct{ client.Reader }
+
+func (failingListReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return errors.New("list failed")
+}
+
+func TestSpreadDelaysCoverWindowEvenly(t *testing.T) {
+	window := 2 * time.Minute
+	for _, jitter := range []func(int64) int64{
+		func(int64) int64 { return 0 },
+		func(n int64) int64 { return n - 1 },
+		func(n int64) int64 { return n / 2 },
+	} {
+		delays := spreadDelays(8, window, jitter)
+		if len(delays) != 8 {
+			t.Fatalf("expected 8 delays, got %d", len(delays))
+		}
+		slot := window / 8
+		for i, delay := range delays {
+			// Every node lands in its own slot, so no two requeues of a change share the same moment.
+			if delay < time.Duration(i)*slot || delay >= time.Duration(i+1)*slot {
+				t.Fatalf("delay %d = %s is outside its slot [%s, %s)", i, delay, time.Duration(i)*slot, time.Duration(i+1)*slot)
+			}
+		}
+	}
+}
+
+func TestSpreadDelaysImmediate(t *testing.T) {
+	for _, delay := range spreadDelays(3, 0, func(int64) int64 { t.Fatalf("unexpected jitter"); return 0 }) {
+		if delay != 0 {
+			t.Fatalf("expected a zero window to requeue at once, got %s", delay)
+		}
+	}
+	if delays := spreadDelays(0, time.Minute, nil); len(delays) != 0 {
+		t.Fatalf("expected no delays without nodes, got %v", delays)
+	}
+	// A window shorter than the node count leaves no room for jitter.
+	if delays := spreadDelays(4, 2*time.Nanosecond, func(int64) int64 { t.Fatalf("unexpected jitter"); return 0 }); delays[3] != 0 {
+		t.Fatalf("expected zero-width slots, got %v", delays)
+	}
+}
+
+func TestModuleConfigWatcherRequeueSpreadsNodes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	device := func(name, node string) *v1alpha1.GPUDevice {
+		return &v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     v1alpha1.GPUDeviceStatus{NodeName: node},
+		}
+	}
+	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		device("a-0", "node-a"), device("a-1", "node-a"), device("b-0", "node-b"), device("orphan", ""),
+	).Build()
+
+	state := moduleconfig.DefaultState()
+	state.Inventory.RequeueSpreadWindow = time.Minute
+	store := moduleconfig.NewModuleConfigStore(moduleconfig.DefaultState())
+	store.Update(state)
+
+	tracker := NewPropagationTracker(nil)
+	w := NewModuleConfigWatcher(testr.New(t), store, tracker)
+	w.jitter = func(int64) int64 { return 0 }
+	q := &delayRecordingQueue{added: map[string]time.Duration{}}
+
+	h := &spreadRequeueHandler{watcher: w, reader: cl}
+	h.Generic(context.Background(), event.GenericEvent{}, q)
+
+	if len(q.added) != 2 || q.added["node-a"] != 0 || q.added["node-b"] != 30*time.Second {
+		t.Fatalf("expected each node once, spread over the window, got %v", q.added)
+	}
+	if got := tracker.Pending(); got != 2 {
+		t.Fatalf("expected both nodes to be tracked, got %d", got)
+	}
+}
+
+func TestModuleConfigWatcherListError(t *testing.T) {
+	w := NewModuleConfigWatcher(testr.New(t), moduleconfig.NewModuleConfigStore(moduleconfig.DefaultState()), nil)
+	q := &delayRecordingQueue{added: map[string]time.Duration{}}
+	if n := w.Requeue(context.Background(), failingListReader{}, q); n != 0 || len(q.added) != 0 {
+		t.Fatalf("expected no requeues on list error, got %d %v", n, q.added)
+	}
+}
+
+func TestModuleConfigWatcherWatch(t *testing.T) {
+	if err := NewModuleConfigWatcher(testr.New(t), nil, nil).Watch(nil, nil); err != nil {
+		t.Fatalf("expected nil store to skip the watch, got %v", err)
+	}
+	ctr := &stubController{}
+	w := NewModuleConfigWatcher(testr.New(t), moduleconfig.NewModuleConfigStore(moduleconfig.DefaultState()), nil)
+	if err := w.Watch(&clientManager{}, ctr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ctr.watched) != 1 {
+		t.Fatalf("expected one source, got %d", len(ctr.watched))
+	}
+}
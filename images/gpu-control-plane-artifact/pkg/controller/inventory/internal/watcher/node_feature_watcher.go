@@ -49,6 +49,7 @@ const (
 	gfdMigStrategyLabel        = invstate.GFDMigStrategyLabel
 	gfdMigAltCapableLabel      = invstate.GFDMigAltCapableLabel
 	gfdMigAltStrategy          = invstate.GFDMigAltStrategyLabel
+	toolkitVersionLabel        = invstate.DeckhouseToolkitVersionLabel
 
 	nodeFeatureNodeNameLabel = invstate.NodeFeatureNodeNameLabel
 )
@@ -166,7 +167,8 @@ func gpuLabelsDiffer(oldLabels, newLabels map[string]string) bool {
 			gfdMigCapableLabel,
 			gfdMigStrategyLabel,
 			gfdMigAltCapableLabel,
-			gfdMigAltStrategy:
+			gfdMigAltStrategy,
+			toolkitVersionLabel:
 			return true
 		default:
 			return false
@@ -54,3 +54,61 @@ func TestNodePredicates(t *testing.T) {
 		t.Fatalf("expected update adding GPU labels to trigger")
 	}
 }
+
+func TestNodePredicatesDrainTransitions(t *testing.T) {
+	preds := nodePredicates()
+	labels := map[string]string{"gpu.deckhouse.io/device.00.vendor": "10de"}
+
+	plain := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+	tainted := plain.DeepCopy()
+	tainted.Spec.Taints = []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}}
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: plain, ObjectNew: tainted}) {
+		t.Fatalf("expected autoscaler taint to trigger reconcile")
+	}
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: tainted, ObjectNew: plain}) {
+		t.Fatalf("expected taint removal to trigger reconcile")
+	}
+
+	now := metav1.Now()
+	deleting := plain.DeepCopy()
+	deleting.DeletionTimestamp = &now
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: plain, ObjectNew: deleting}) {
+		t.Fatalf("expected deletion timestamp to trigger reconcile")
+	}
+
+	ready := plain.DeepCopy()
+	ready.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	notReady := plain.DeepCopy()
+	notReady.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}}
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: ready, ObjectNew: notReady}) {
+		t.Fatalf("expected Ready -> NotReady to trigger reconcile")
+	}
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: notReady, ObjectNew: ready}) {
+		t.Fatalf("expected NotReady -> Ready to trigger reconcile")
+	}
+}
+
+func TestNodePredicatesContainerRuntimeChange(t *testing.T) {
+	preds := nodePredicates()
+	labels := map[string]string{"gpu.deckhouse.io/device.00.vendor": "10de"}
+
+	containerd := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+	containerd.Status.NodeInfo.ContainerRuntimeVersion = "containerd://1.7.2"
+	crio := containerd.DeepCopy()
+	crio.Status.NodeInfo.ContainerRuntimeVersion = "cri-o://1.28.1"
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: containerd, ObjectNew: crio}) {
+		t.Fatalf("expected a runtime switch on a GPU node to trigger reconcile")
+	}
+
+	upgraded := containerd.DeepCopy()
+	upgraded.Status.NodeInfo.KernelVersion = "6.8.0-45-generic"
+	if !preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: containerd, ObjectNew: upgraded}) {
+		t.Fatalf("expected a kernel upgrade on a GPU node to trigger reconcile")
+	}
+
+	plainOld, plainNew := containerd.DeepCopy(), crio.DeepCopy()
+	plainOld.Labels, plainNew.Labels = nil, nil
+	if preds.Update(event.TypedUpdateEvent[*corev1.Node]{ObjectOld: plainOld, ObjectNew: plainNew}) {
+		t.Fatalf("expected a runtime switch on a node without GPUs to be filtered out")
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
 )
 
 type NodeWatcher struct{}
@@ -53,6 +55,19 @@ func nodePredicates() predicate.TypedPredicate[*corev1.Node] {
 			return hasGPUDeviceLabels(node.GetLabels())
 		},
 		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Node]) bool {
+			if invstate.IsNodeDraining(e.ObjectOld) != invstate.IsNodeDraining(e.ObjectNew) {
+				return true
+			}
+			if invstate.IsNodeReady(e.ObjectOld) != invstate.IsNodeReady(e.ObjectNew) {
+				return true
+			}
+			// A runtime switch keeps the labels but changes status.containerRuntime and the runtime label;
+			// a kernel upgrade changes the facts compatibility rules are matched against.
+			if e.ObjectOld != nil && e.ObjectNew != nil && hasGPUDeviceLabels(e.ObjectNew.GetLabels()) &&
+				(e.ObjectOld.Status.NodeInfo.ContainerRuntimeVersion != e.ObjectNew.Status.NodeInfo.ContainerRuntimeVersion ||
+					e.ObjectOld.Status.NodeInfo.KernelVersion != e.ObjectNew.Status.NodeInfo.KernelVersion) {
+				return true
+			}
 			return gpuNodeLabelsChanged(e.ObjectOld, e.ObjectNew)
 		},
 		DeleteFunc:  func(event.TypedDeleteEvent[*corev1.Node]) bool { return true },
@@ -0,0 +1,85 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"sync"
+	"time"
+
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+)
+
+// PropagationTracker measures how long a ModuleConfig settings change takes to reach the inventory: from the
+// store update to the successful reconcile of the last node requeued for it. Only the latest generation is
+// tracked; a newer change supersedes an unfinished one, whose latency is not observed.
+type PropagationTracker struct {
+	now     func() time.Time
+	observe func(time.Duration)
+
+	mu         sync.Mutex
+	generation uint64
+	updated    time.Time
+	pending    map[string]struct{}
+}
+
+func NewPropagationTracker(metrics *invmetrics.Metrics) *PropagationTracker {
+	return &PropagationTracker{now: time.Now, observe: metrics.ModuleConfigPropagationObserve}
+}
+
+// Expect starts tracking the nodes requeued for generation. A generation that is not newer than the tracked
+// one is ignored, so a repeated event does not restart the measurement.
+func (t *PropagationTracker) Expect(generation uint64, updated time.Time, nodes []string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if generation <= t.generation {
+		return
+	}
+	t.generation = generation
+	t.updated = updated
+	t.pending = make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		t.pending[node] = struct{}{}
+	}
+}
+
+// Done marks the node as reconciled with the current settings, or as gone. The latency is observed once the
+// last pending node is done.
+func (t *PropagationTracker) Done(node string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pending[node]; !ok {
+		return
+	}
+	delete(t.pending, node)
+	if len(t.pending) == 0 {
+		t.observe(t.now().Sub(t.updated))
+	}
+}
+
+// Pending returns how many nodes have not been reconciled since the tracked change.
+func (t *PropagationTracker) Pending() int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
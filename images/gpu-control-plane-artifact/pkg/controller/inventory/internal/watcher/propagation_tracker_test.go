@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTracker(now *time.Time, observed *[]time.Duration) *PropagationTracker {
+	tracker := NewPropagationTracker(nil)
+	tracker.now = func() time.Time { return *now }
+	tracker.observe = func(latency time.Duration) { *observed = append(*observed, latency) }
+	return tracker
+}
+
+func TestPropagationTrackerObservesLastNode(t *testing.T) {
+	updated := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := updated
+	var observed []time.Duration
+	tracker := newTestTracker(&now, &observed)
+
+	tracker.Expect(1, updated, []string{"node-a", "node-b"})
+	now = updated.Add(10 * time.Second)
+	tracker.Done("node-a")
+	tracker.Done("node-a")
+	tracker.Done("node-c")
+	if len(observed) != 0 {
+		t.Fatalf("expected no observation while a node is pending, got %v", observed)
+	}
+
+	now = updated.Add(90 * time.Second)
+	tracker.Done("node-b")
+	if len(observed) != 1 || observed[0] != 90*time.Second {
+		t.Fatalf("expected the latency of the last node, got %v", observed)
+	}
+	tracker.Done("node-b")
+	if len(observed) != 1 {
+		t.Fatalf("expected a generation to be observed once, got %v", observed)
+	}
+}
+
+func TestPropagationTrackerNewerGenerationSupersedes(t *testing.T) {
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+	now := first
+	var observed []time.Duration
+	tracker := newTestTracker(&now, &observed)
+
+	tracker.Expect(1, first, []string{"node-a", "node-b"})
+	tracker.Done("node-a")
+	tracker.Expect(2, second, []string{"node-a", "node-b"})
+	// A repeated or stale event does not restart the measurement.
+	tracker.Expect(2, second, []string{"node-a", "node-b", "node-c"})
+	tracker.Expect(1, first, []string{"node-d"})
+	if got := tracker.Pending(); got != 2 {
+		t.Fatalf("expected the newer generation to be tracked, got %d pending", got)
+	}
+
+	now = second.Add(20 * time.Second)
+	tracker.Done("node-a")
+	tracker.Done("node-b")
+	if len(observed) != 1 || observed[0] != 20*time.Second {
+		t.Fatalf("expected only the newer generation to be observed, got %v", observed)
+	}
+}
+
+func TestPropagationTrackerWithoutNodes(t *testing.T) {
+	now := time.Now()
+	var observed []time.Duration
+	tracker := newTestTracker(&now, &observed)
+	tracker.Expect(1, now, nil)
+	tracker.Done("node-a")
+	if len(observed) != 0 {
+		t.Fatalf("expected nothing to observe without affected nodes, got %v", observed)
+	}
+
+	var nilTracker *PropagationTracker
+	nilTracker.Expect(1, now, []string{"node-a"})
+	nilTracker.Done("node-a")
+	if nilTracker.Pending() != 0 {
+		t.Fatalf("expected a nil tracker to track nothing")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/fleet"
+	invhandler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/handler"
+	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+// benchFleetSizes are the node counts the benchmarks run at; -short stops at 100 so a quick local run
+// stays cheap. Run them before and after a change with
+//
+//	go test ./pkg/controller/inventory -run '^$' -bench . -benchmem -count 10
+//
+// and compare the results with benchstat. The fake client filters indexed lists by scanning every object
+// of the type, so part of the growth at large sizes is the fake client rather than the controller; use
+// hack/fleet-generator against a real API server to separate the two.
+var benchFleetSizes = []int{10, 100, 1000}
+
+// BenchmarkReconcile measures steady-state reconciles: every device already exists, so the numbers
+// reflect the work done on each resync of an unchanged fleet.
+func BenchmarkReconcile(b *testing.B) {
+	for _, size := range benchFleetSizes {
+		b.Run(fmt.Sprintf("nodes=%d", size), func(b *testing.B) {
+			bench := newInventoryBench(b, size)
+
+			b.Run("full", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					for _, node := range bench.fleet.Nodes {
+						if _, err := bench.reconciler.Reconcile(bench.ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(node)}); err != nil {
+							b.Fatalf("reconcile %s: %v", node.Name, err)
+						}
+					}
+				}
+			})
+
+			b.Run("snapshot", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					for j, node := range bench.fleet.Nodes {
+						_ = invstate.BuildNodeSnapshot(node, bench.fleet.NodeFeatures[j], bench.managed)
+					}
+				}
+			})
+
+			b.Run("devices", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					for j, node := range bench.fleet.Nodes {
+						snapshot := bench.snapshots[j]
+						if _, _, err := bench.reconciler.deviceSvc().ReconcileNode(bench.ctx, node, snapshot.Devices, snapshot.Labels, snapshot.Managed, bench.approval, snapshot.Provenance, bench.applyDriver(snapshot)); err != nil {
+							b.Fatalf("reconcile devices of %s: %v", node.Name, err)
+						}
+					}
+				}
+			})
+
+			b.Run("status", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					for j, node := range bench.fleet.Nodes {
+						if err := bench.reconciler.inventorySvc().Reconcile(bench.ctx, node, bench.snapshots[j], bench.devices[j]); err != nil {
+							b.Fatalf("reconcile inventory of %s: %v", node.Name, err)
+						}
+					}
+				}
+			})
+		})
+	}
+}
+
+type inventoryBench struct {
+	ctx        context.Context
+	fleet      fleet.Fleet
+	reconciler *Reconciler
+	managed    invstate.ManagedNodesPolicy
+	approval   invstate.DeviceApprovalPolicy
+	snapshots  []invstate.NodeSnapshot
+	devices    [][]*v1alpha1.GPUDevice
+}
+
+// newInventoryBench seeds a fake client with the synthetic fleet and runs one reconcile per node, so
+// the GPUDevices and GPUNodeStates exist before the timer starts.
+func newInventoryBench(b *testing.B, nodes int) *inventoryBench {
+	b.Helper()
+	if testing.Short() && nodes > 100 {
+		b.Skip("large fleet skipped in -short mode")
+	}
+
+	scheme := runtime.NewScheme()
+	if err := fleet.AddToScheme(scheme); err != nil {
+		b.Fatalf("build scheme: %v", err)
+	}
+	synthetic := fleet.Generate(fleet.Options{Nodes: nodes})
+	builder := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(synthetic.Objects()...).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}, &v1alpha1.GPUNodeState{})
+	for _, getter := range []indexer.IndexGetter{indexer.IndexGPUDeviceByNode, indexer.IndexGPUDeviceByInventoryID} {
+		obj, field, extract := getter()
+		builder = builder.WithIndex(obj, field, extract)
+	}
+
+	log := logr.Discard()
+	r, err := New(log, config.ControllerConfig{}, nil, []invservice.DeviceHandler{invhandler.NewDeviceStateHandler(log)}, nil, nil, nil)
+	if err != nil {
+		b.Fatalf("new reconciler: %v", err)
+	}
+	r.client = builder.Build()
+	r.scheme = scheme
+	r.recorder = eventrecord.NewEventRecorderLogger(benchRecorderProducer{}, ControllerName)
+
+	bench := &inventoryBench{
+		ctx:        logr.NewContext(context.Background(), log),
+		fleet:      synthetic,
+		reconciler: r,
+		managed:    r.managedPolicy(),
+		approval:   r.fallbackApproval,
+	}
+	for i, node := range synthetic.Nodes {
+		if _, err := r.Reconcile(bench.ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(node)}); err != nil {
+			b.Fatalf("seed reconcile %s: %v", node.Name, err)
+		}
+		snapshot := invstate.BuildNodeSnapshot(node, synthetic.NodeFeatures[i], bench.managed)
+		devices, _, err := r.deviceSvc().ReconcileNode(bench.ctx, node, snapshot.Devices, snapshot.Labels, snapshot.Managed, bench.approval, snapshot.Provenance, bench.applyDriver(snapshot))
+		if err != nil {
+			b.Fatalf("seed devices of %s: %v", node.Name, err)
+		}
+		if len(devices) != len(snapshot.Devices) {
+			b.Fatalf("expected %d devices on %s, got %d", len(snapshot.Devices), node.Name, len(devices))
+		}
+		bench.snapshots = append(bench.snapshots, snapshot)
+		bench.devices = append(bench.devices, devices)
+	}
+	return bench
+}
+
+// applyDriver mirrors the per-device hook of the inventory handler without gfd-extender detections.
+func (b *inventoryBench) applyDriver(snapshot invstate.NodeSnapshot) func(*v1alpha1.GPUDevice, invstate.DeviceSnapshot) {
+	return func(device *v1alpha1.GPUDevice, _ invstate.DeviceSnapshot) {
+		device.Status.DriverVersion = snapshot.Driver.Version
+	}
+}
+
+type benchRecorderProducer struct{}
+
+func (benchRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return &record.FakeRecorder{}
+}
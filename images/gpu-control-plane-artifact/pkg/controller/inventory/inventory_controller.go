@@ -30,6 +30,8 @@ import (
 	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
 	invwebhook "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/webhook"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
 )
@@ -40,6 +42,9 @@ func SetupController(
 	log logr.Logger,
 	cfg config.ControllerConfig,
 	store *moduleconfig.ModuleConfigStore,
+	guard *ownership.Guard,
+	sweeps *modulestatus.SweepTracker,
+	queue *NodeQueue,
 ) error {
 	baseLog := log.WithName("inventory")
 	handlers := []invservice.DeviceHandler{
@@ -51,7 +56,7 @@ func SetupController(
 		workers = 1
 	}
 
-	r, err := NewReconciler(baseLog, cfg, store, handlers)
+	r, err := NewReconciler(baseLog, cfg, store, handlers, guard, sweeps, queue)
 	if err != nil {
 		return err
 	}
@@ -25,12 +25,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
 	invhandler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/handler"
 	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	invwatcher "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/watcher"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/nfdapi"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
 )
 
 const (
@@ -57,6 +63,14 @@ type Reconciler struct {
 	store            *moduleconfig.ModuleConfigStore
 	fallbackManaged  invstate.ManagedNodesPolicy
 	fallbackApproval invstate.DeviceApprovalPolicy
+	handlerRuntime   *invservice.HandlerRuntime
+	deletionLimiter  *invservice.DeletionLimiter
+	nodeQueue        *NodeQueue
+	nodeFeatureAPI   *nfdapi.Checker
+	propagation      *invwatcher.PropagationTracker
+	metrics          *invmetrics.Metrics
+	guard            *ownership.Guard
+	sweeps           *modulestatus.SweepTracker
 
 	detectionCollector invhandler.DetectionCollector
 	cleanupService     invhandler.CleanupService
@@ -65,23 +79,29 @@ type Reconciler struct {
 	detectionClient    client.Client
 }
 
-func New(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []invservice.DeviceHandler) (*Reconciler, error) {
+// New builds the inventory reconciler; a nil guard lets it write every device and node state. Successful node
+// reconciles are recorded in sweeps, which may be nil. A nil queue is replaced by one only the reconciler uses.
+func New(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []invservice.DeviceHandler, guard *ownership.Guard, sweeps *modulestatus.SweepTracker, queue *NodeQueue) (*Reconciler, error) {
 	if cfg.Workers <= 0 {
 		cfg.Workers = 1
 	}
 	if cfg.ResyncPeriod <= 0 {
 		cfg.ResyncPeriod = defaultResyncPeriod
 	}
+	cfg.HTTPTimeouts = cfg.HTTPTimeouts.OrDefaults()
 
 	state := moduleconfig.DefaultState()
 	if store != nil {
 		state = store.Current()
 	}
 
-	managed, approval, err := managedAndApprovalFromState(state)
+	managed, approval, err := managedAndApprovalFromState(state, time.Now())
 	if err != nil {
 		return nil, err
 	}
+	if queue == nil {
+		queue = NewNodeQueue()
+	}
 
 	rec := &Reconciler{
 		log:              log,
@@ -90,20 +110,35 @@ func New(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.Modul
 		store:            store,
 		fallbackManaged:  managed,
 		fallbackApproval: approval,
+		handlerRuntime:   invservice.NewHandlerRuntime(),
+		nodeQueue:        queue,
+		nodeFeatureAPI:   nfdapi.Default,
+		metrics:          invmetrics.Default(),
+		guard:            guard,
+		sweeps:           sweeps,
 	}
+	rec.propagation = invwatcher.NewPropagationTracker(rec.metrics)
+	rec.deletionLimiter = invservice.NewDeletionLimiter(rec.maxDeletions)
 	rec.setResyncPeriod(cfg.ResyncPeriod)
 	rec.applyInventoryResync(state)
 
 	return rec, nil
 }
 
-func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []invservice.DeviceHandler) (*Reconciler, error) {
-	return New(log, cfg, store, handlers)
+func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []invservice.DeviceHandler, guard *ownership.Guard, sweeps *modulestatus.SweepTracker, queue *NodeQueue) (*Reconciler, error) {
+	return New(log, cfg, store, handlers, guard, sweeps, queue)
+}
+
+// SetMetrics replaces the metrics the reconciler and its services record into, which default to the
+// controller-runtime registry. It must be called before the services are built.
+func (r *Reconciler) SetMetrics(metrics *invmetrics.Metrics) {
+	r.metrics = metrics
+	r.propagation = invwatcher.NewPropagationTracker(metrics)
 }
 
 func (r *Reconciler) detectionSvc() invhandler.DetectionCollector {
 	if r.detectionCollector == nil || r.detectionClient != r.client {
-		r.detectionCollector = invservice.NewDetectionCollector(r.client)
+		r.detectionCollector = invservice.NewDetectionCollector(r.client, httpcall.New(httpcall.CategoryDetection, r.cfg.HTTPTimeouts.Detection), r.metrics)
 		r.detectionClient = r.client
 	}
 	return r.detectionCollector
@@ -111,44 +146,64 @@ func (r *Reconciler) detectionSvc() invhandler.DetectionCollector {
 
 func (r *Reconciler) cleanupSvc() invhandler.CleanupService {
 	if r.cleanupService == nil {
-		r.cleanupService = invservice.NewCleanupService(r.client, r.recorder)
+		r.cleanupService = invservice.NewCleanupService(r.client, r.recorder, r.deletionLimiter, r.metrics, r.guard)
 	}
 	return r.cleanupService
 }
 
 func (r *Reconciler) deviceSvc() invhandler.DeviceService {
 	if r.deviceService == nil {
-		r.deviceService = invservice.NewDeviceService(r.client, r.scheme, r.recorder, r.deviceHandlers)
+		r.deviceService = r.newDeviceService()
 	}
 	return r.deviceService
 }
 
+func (r *Reconciler) newDeviceService() *invservice.DeviceService {
+	svc := invservice.NewDeviceService(r.client, r.scheme, r.recorder, r.deviceHandlers, r.metrics, r.guard)
+	svc.SetHandlerRuntime(r.handlerRuntime)
+	svc.SetStatusWriteWorkers(r.cfg.StatusWriteWorkers)
+	svc.SetNameTemplate(r.deviceNameTemplate)
+	svc.SetAttributePassthroughPrefixes(r.attributePassthroughPrefixes)
+	return svc
+}
+
 func (r *Reconciler) inventorySvc() invhandler.InventoryService {
 	if r.inventoryService == nil {
-		r.inventoryService = invservice.NewInventoryService(r.client, r.scheme, r.recorder)
+		r.inventoryService = r.newInventoryService()
 	}
 	return r.inventoryService
 }
 
+func (r *Reconciler) newInventoryService() *invservice.InventoryService {
+	svc := invservice.NewInventoryService(r.client, r.scheme, r.recorder, r.metrics, r.guard)
+	svc.SetConditionPolicy(r.conditionPolicy)
+	svc.SetHandlerRuntime(r.handlerRuntime)
+	return svc
+}
+
 func (r *Reconciler) handlerChain() []Handler {
 	if r.handlers != nil {
 		return r.handlers
 	}
+	inventory := invhandler.NewInventoryHandler(
+		r.log.WithName("inventory"),
+		r.client,
+		r.deviceSvc(),
+		r.inventorySvc(),
+		r.cleanupSvc(),
+		r.detectionSvc(),
+		r.recorder,
+		r.metrics,
+	)
+	inventory.SetIncludeDisplayDevices(r.includeDisplayDevices)
 	r.handlers = []Handler{
-		invhandler.NewInventoryHandler(
-			r.log.WithName("inventory"),
-			r.client,
-			r.deviceSvc(),
-			r.inventorySvc(),
-			r.cleanupSvc(),
-			r.detectionSvc(),
-			r.recorder,
-		),
+		inventory,
+		invhandler.NewNodeLabelsHandler(r.client, r.nodeLabelingEnabled, r.managedPolicy),
 	}
 	return r.handlers
 }
 
-func managedAndApprovalFromState(state moduleconfig.State) (invstate.ManagedNodesPolicy, invstate.DeviceApprovalPolicy, error) {
+func managedAndApprovalFromState(state moduleconfig.State, now time.Time) (invstate.ManagedNodesPolicy, invstate.DeviceApprovalPolicy, error) {
 	managed := invstate.ManagedNodesPolicy{
 		LabelKey:         strings.TrimSpace(state.Settings.ManagedNodes.LabelKey),
 		EnabledByDefault: state.Settings.ManagedNodes.EnabledByDefault,
@@ -156,6 +211,9 @@ func managedAndApprovalFromState(state moduleconfig.State) (invstate.ManagedNode
 	if managed.LabelKey == "" {
 		managed.LabelKey = invstate.DefaultManagedNodeLabelKey
 	}
+	if state.Settings.ManagedNodes.TransitionActive(now) {
+		managed.PreviousLabelKey = state.Settings.ManagedNodes.PreviousLabelKey
+	}
 
 	approval, err := invstate.NewDeviceApprovalPolicy(state.Settings.DeviceApproval)
 	if err != nil {
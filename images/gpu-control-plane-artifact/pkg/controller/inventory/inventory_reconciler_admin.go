@@ -0,0 +1,108 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ErrQueueNotStarted is returned by RequeueAllNodes before the inventory controller started, e.g. on a replica
+// that is not the leader.
+var ErrQueueNotStarted = errors.New("inventory work queue is not started")
+
+// NodeQueue administers the inventory work queue during incidents: it requeues every node on demand and keeps
+// suppressed nodes from being reconciled until their suppression expires. Suppressions live in memory only.
+type NodeQueue struct {
+	now func() time.Time
+
+	mu         sync.Mutex
+	reader     client.Reader
+	queue      workqueue.RateLimitingInterface
+	suppressed map[string]time.Time
+}
+
+// NewNodeQueue returns a queue to hand to the inventory controller and the admin API. It requeues nothing until
+// the controller started.
+func NewNodeQueue() *NodeQueue {
+	return &NodeQueue{now: time.Now, suppressed: make(map[string]time.Time)}
+}
+
+// source captures the controller work queue once the controller starts; it emits no events of its own.
+func (q *NodeQueue) source(reader client.Reader) source.Source {
+	return source.Func(func(_ context.Context, queue workqueue.RateLimitingInterface) error {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.reader = reader
+		q.queue = queue
+		return nil
+	})
+}
+
+// RequeueAllNodes enqueues every node known to the cache and returns how many were enqueued.
+func (q *NodeQueue) RequeueAllNodes(ctx context.Context) (int, error) {
+	q.mu.Lock()
+	reader, queue := q.reader, q.queue
+	q.mu.Unlock()
+	if queue == nil {
+		return 0, ErrQueueNotStarted
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes); err != nil {
+		return 0, fmt.Errorf("list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		queue.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&nodes.Items[i])})
+	}
+	return len(nodes.Items), nil
+}
+
+// Suppress keeps the node from being reconciled for ttl and returns when the suppression expires. Suppressing a
+// suppressed node replaces its expiry.
+func (q *NodeQueue) Suppress(node string, ttl time.Duration) time.Time {
+	until := q.now().Add(ttl)
+	q.mu.Lock()
+	q.suppressed[node] = until
+	q.mu.Unlock()
+	return until
+}
+
+// Suppressed reports whether the node is suppressed right now, forgetting expired suppressions.
+func (q *NodeQueue) Suppressed(node string) bool {
+	if q == nil {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	until, ok := q.suppressed[node]
+	if !ok {
+		return false
+	}
+	if !q.now().Before(until) {
+		delete(q.suppressed, node)
+		return false
+	}
+	return true
+}
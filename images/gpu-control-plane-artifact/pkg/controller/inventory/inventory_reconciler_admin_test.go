@@ -0,0 +1,142 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+)
+
+func TestReconcileSkipsSuppressedNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	reads := 0
+	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			reads++
+			return errors.New("poison pill")
+		},
+	}).Build()
+
+	r, err := New(logr.Discard(), config.ControllerConfig{}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new reconciler: %v", err)
+	}
+	r.client = cl
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.nodeQueue.now = func() time.Time { return now }
+	r.nodeQueue.Suppress("node-a", 30*time.Minute)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "node-a"}}
+	res, err := r.Reconcile(context.Background(), req)
+	if err != nil || res != (ctrl.Result{}) {
+		t.Fatalf("expected a suppressed node to return immediately, got %+v, %v", res, err)
+	}
+	if reads != 0 {
+		t.Fatalf("expected no reads for a suppressed node, got %d", reads)
+	}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "node-b"}}); err == nil || reads != 1 {
+		t.Fatalf("expected other nodes to be reconciled, got reads=%d err=%v", reads, err)
+	}
+
+	// Once the TTL expires the node is reconciled again.
+	now = now.Add(30 * time.Minute)
+	if _, err := r.Reconcile(context.Background(), req); err == nil || reads != 2 {
+		t.Fatalf("expected the node to be reconciled after the TTL, got reads=%d err=%v", reads, err)
+	}
+}
+
+func TestNodeQueueSuppressionTTL(t *testing.T) {
+	q := NewNodeQueue()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	if q.Suppressed("node-a") {
+		t.Fatalf("expected no suppression by default")
+	}
+	if until := q.Suppress("node-a", time.Minute); !until.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected expiry %s", until)
+	}
+	now = now.Add(59 * time.Second)
+	if !q.Suppressed("node-a") {
+		t.Fatalf("expected the node to be suppressed within the TTL")
+	}
+
+	// Suppressing again replaces the expiry.
+	q.Suppress("node-a", 10*time.Minute)
+	now = now.Add(5 * time.Minute)
+	if !q.Suppressed("node-a") {
+		t.Fatalf("expected the extended suppression to hold")
+	}
+	now = now.Add(5 * time.Minute)
+	if q.Suppressed("node-a") {
+		t.Fatalf("expected the suppression to expire")
+	}
+	if _, ok := q.suppressed["node-a"]; ok {
+		t.Fatalf("expected the expired suppression to be forgotten")
+	}
+
+	var nilQueue *NodeQueue
+	if nilQueue.Suppressed("node-a") {
+		t.Fatalf("expected a nil queue to suppress nothing")
+	}
+}
+
+func TestNodeQueueRequeueAllNodes(t *testing.T) {
+	q := NewNodeQueue()
+	if _, err := q.RequeueAllNodes(context.Background()); !errors.Is(err, ErrQueueNotStarted) {
+		t.Fatalf("expected ErrQueueNotStarted, got %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	builder := clientfake.NewClientBuilder().WithScheme(scheme)
+	for i := 0; i < 5; i++ {
+		builder = builder.WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)}})
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	if err := q.source(builder.Build()).Start(context.Background(), queue); err != nil {
+		t.Fatalf("start source: %v", err)
+	}
+
+	count, err := q.RequeueAllNodes(context.Background())
+	if err != nil {
+		t.Fatalf("requeue all: %v", err)
+	}
+	if count != 5 || queue.Len() != 5 {
+		t.Fatalf("expected 5 nodes to be enqueued, got count=%d len=%d", count, queue.Len())
+	}
+	// Nodes already waiting in the queue are not duplicated.
+	if count, err := q.RequeueAllNodes(context.Background()); err != nil || count != 5 || queue.Len() != 5 {
+		t.Fatalf("expected the queue to deduplicate, got count=%d len=%d err=%v", count, queue.Len(), err)
+	}
+}
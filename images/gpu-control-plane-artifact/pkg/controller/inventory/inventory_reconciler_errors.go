@@ -0,0 +1,53 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+)
+
+// resolveError maps the error of a node reconcile onto the workqueue outcome through the shared policy
+// table: unclassified errors are retried with backoff, conflicts and missing telemetry are requeued, and
+// a missing NodeFeature or invalid settings drop the reconcile until a watch event brings the node back.
+func (r *Reconciler) resolveError(ctx context.Context, node *corev1.Node, res ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil {
+		return res, nil
+	}
+	log := logr.FromContextOrDiscard(ctx)
+	policy := commonerrors.PolicyFor(err)
+	switch policy.Action {
+	case commonerrors.ActionRequeueAfter:
+		log.V(1).Info("inventory reconcile deferred", "reason", err.Error(), "requeueAfter", policy.RequeueAfter)
+	case commonerrors.ActionDrop:
+		log.V(1).Info("inventory reconcile dropped", "reason", err.Error())
+		if policy.EventReason != "" && r.recorder != nil {
+			r.recorder.WithLogging(log).Eventf(
+				node,
+				corev1.EventTypeWarning,
+				policy.EventReason,
+				"GPU inventory of node %s is not reconciled: %v",
+				node.Name,
+				err,
+			)
+		}
+	}
+	return commonerrors.Result(res, err)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
+	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+// syncHandlerSettings pushes the handlers section of ModuleConfig into the handler runtime, so
+// handlers can be disabled or re-tuned without a restart.
+func (r *Reconciler) syncHandlerSettings(ctx context.Context) {
+	if r.store == nil {
+		return
+	}
+
+	handlers := make([]ctrlreconciler.Named, 0, len(r.deviceHandlers)+1)
+	for _, handler := range r.handlerChain() {
+		handlers = append(handlers, handler)
+	}
+	for _, handler := range r.deviceHandlers {
+		handlers = append(handlers, handler)
+	}
+
+	unknown := r.handlerRuntime.Apply(r.store.Current().Handlers, handlers)
+	if len(unknown) == 0 || r.recorder == nil {
+		return
+	}
+	mc := &mcapi.ModuleConfig{ObjectMeta: metav1.ObjectMeta{Name: moduleconfig.ModuleConfigName}}
+	for _, name := range unknown {
+		r.recorder.WithLogging(logr.FromContextOrDiscard(ctx)).Eventf(
+			mc,
+			corev1.EventTypeWarning,
+			invstate.EventUnknownHandler,
+			"settings.handlers.%s does not match any inventory handler and is ignored",
+			name,
+		)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func TestSyncHandlerSettingsConfiguresNodeLabels(t *testing.T) {
+	state := moduleconfig.DefaultState()
+	state.Handlers = map[string]moduleconfig.HandlerSettings{
+		"node-labels": {Enabled: true, Settings: json.RawMessage(`{"skipLabels":["example.com/not-owned"]}`)},
+	}
+	store := moduleconfig.NewModuleConfigStore(state)
+	r, err := New(logr.Discard(), config.ControllerConfig{}, store, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new reconciler: %v", err)
+	}
+
+	r.syncHandlerSettings(context.Background())
+	if errs := r.handlerRuntime.ConfigureErrors(); len(errs) != 1 || errs["node-labels"] == nil {
+		t.Fatalf("expected the node-labels settings to be rejected, got %v", errs)
+	}
+
+	state.Handlers["node-labels"] = moduleconfig.HandlerSettings{
+		Enabled:  true,
+		Settings: json.RawMessage(`{"skipLabels":["` + invstate.NodeGPUProductLabelKey + `"]}`),
+	}
+	store.Update(state)
+	r.syncHandlerSettings(context.Background())
+	if errs := r.handlerRuntime.ConfigureErrors(); len(errs) != 0 {
+		t.Fatalf("expected the node-labels settings to be applied, got %v", errs)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	promdto "github.com/prometheus/client_model/go"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+)
+
+func TestReconcilersWithSeparateRegistriesDoNotShareMetrics(t *testing.T) {
+	newReconciler := func(t *testing.T) (*Reconciler, *prometheus.Registry) {
+		t.Helper()
+		r, err := New(logr.Discard(), config.ControllerConfig{}, nil, nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("new reconciler: %v", err)
+		}
+		registry := prometheus.NewRegistry()
+		metrics, err := invmetrics.New(registry)
+		if err != nil {
+			t.Fatalf("register inventory metrics: %v", err)
+		}
+		r.SetMetrics(metrics)
+		r.nodeQueue.Suppress("node-a", time.Hour)
+		return r, registry
+	}
+	first, firstRegistry := newReconciler(t)
+	second, secondRegistry := newReconciler(t)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: "node-a"}}
+	for i := 0; i < 2; i++ {
+		if _, err := first.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("reconcile: %v", err)
+		}
+	}
+	if _, err := second.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	first.inventorySvc().UpdateDeviceMetrics("node-a", []*v1alpha1.GPUDevice{{}})
+
+	if got := gatheredValue(t, firstRegistry, invmetrics.InventorySuppressedTotal); got != 2 {
+		t.Fatalf("expected 2 suppressed reconciles on the first registry, got %f", got)
+	}
+	if got := gatheredValue(t, secondRegistry, invmetrics.InventorySuppressedTotal); got != 1 {
+		t.Fatalf("expected 1 suppressed reconcile on the second registry, got %f", got)
+	}
+	if got := gatheredValue(t, firstRegistry, invmetrics.InventoryDevicesTotalMetric); got != 1 {
+		t.Fatalf("expected the device gauge on the first registry, got %f", got)
+	}
+	if got := gatheredValue(t, secondRegistry, invmetrics.InventoryDevicesTotalMetric); got != 0 {
+		t.Fatalf("expected no device gauge on the second registry, got %f", got)
+	}
+}
+
+// gatheredValue sums the samples of a gauge or counter family in the registry; an absent family reads as zero.
+func gatheredValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	var sum float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			sum += sampleValue(metric)
+		}
+	}
+	return sum
+}
+
+func sampleValue(metric *promdto.Metric) float64 {
+	if metric.GetCounter() != nil {
+		return metric.GetCounter().GetValue()
+	}
+	return metric.GetGauge().GetValue()
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/nfdapi"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+func TestReconcileReportsUnsupportedNodeFeatureAPI(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	inventory := &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GPUNodeStateSpec{NodeName: "node-a"},
+	}
+	nodeFeatureReads := 0
+	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(node, inventory).WithStatusSubresource(inventory).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*nfdv1alpha1.NodeFeature); ok {
+					nodeFeatureReads++
+					return errors.New("no matches for kind NodeFeature")
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "nfd.k8s-sigs.io/v1alpha2",
+		APIResources: []metav1.APIResource{{Name: "nodefeatures", Kind: nfdapi.Kind, Namespaced: true}},
+	}}}}
+	checker := nfdapi.NewChecker(logr.Discard(), dc)
+	if err := checker.Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	r, err := New(logr.Discard(), config.ControllerConfig{}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("new reconciler: %v", err)
+	}
+	r.client = cl
+	r.scheme = scheme
+	r.nodeFeatureAPI = checker
+
+	res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "node-a"}})
+	if err != nil || res != (ctrl.Result{}) {
+		t.Fatalf("expected the node to be reported without a retry, got %+v, %v", res, err)
+	}
+	if nodeFeatureReads != 0 {
+		t.Fatalf("expected no NodeFeature reads while the API is unsupported, got %d", nodeFeatureReads)
+	}
+	got := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "node-a"}, got); err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, invstate.ConditionInventoryComplete)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != invstate.ReasonDiscoveryAPIUnsupported {
+		t.Fatalf("unexpected inventory condition: %+v", cond)
+	}
+
+	// Once the compiled version is served, NodeFeatures are read again.
+	dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+		GroupVersion: "nfd.k8s-sigs.io/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "nodefeatures", Kind: nfdapi.Kind, Namespaced: true}},
+	})
+	if err := checker.Check(); err != nil || !checker.Supported() {
+		t.Fatalf("expected the upgraded API to be supported, err=%v", err)
+	}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "node-a"}}); err == nil || nodeFeatureReads == 0 {
+		t.Fatalf("expected NodeFeature reads after recovery, got reads=%d err=%v", nodeFeatureReads, err)
+	}
+}
@@ -24,7 +24,7 @@ import (
 func (r *Reconciler) currentPolicies() (invstate.ManagedNodesPolicy, invstate.DeviceApprovalPolicy) {
 	if r.store != nil {
 		state := r.store.Current()
-		managed, approval, err := managedAndApprovalFromState(state)
+		managed, approval, err := managedAndApprovalFromState(state, time.Now())
 		if err != nil {
 			if r.log.GetSink() != nil {
 				r.log.Error(err, "failed to build device approval policy from store, using fallback")
@@ -37,6 +37,87 @@ func (r *Reconciler) currentPolicies() (invstate.ManagedNodesPolicy, invstate.De
 	return r.fallbackManaged, r.fallbackApproval
 }
 
+func (r *Reconciler) managedPolicy() invstate.ManagedNodesPolicy {
+	managed, _ := r.currentPolicies()
+	return managed
+}
+
+// nodeLabelingEnabled reports nodeLabeling.enabled; the node labels handler strips its labels when it is off.
+func (r *Reconciler) nodeLabelingEnabled() bool {
+	return r.store != nil && r.store.Current().Settings.NodeLabeling.Enabled
+}
+
+// stalenessPolicy reads inventory.staleNodeThreshold and inventory.staleDeviceRetention on every reconcile.
+func (r *Reconciler) stalenessPolicy() invstate.StalenessPolicy {
+	inventory := moduleconfig.DefaultState().Inventory
+	if r.store != nil {
+		inventory = r.store.Current().Inventory
+	}
+	return invstate.StalenessPolicy{Threshold: inventory.StaleNodeThreshold, Retention: inventory.StaleDeviceRetention}
+}
+
+// compatibilityPolicy merges inventory.compatibilityRules over the default rules on every reconcile,
+// so a settings change re-evaluates each node on its next sync.
+func (r *Reconciler) compatibilityPolicy() invstate.CompatibilityPolicy {
+	if r.store == nil {
+		return invstate.NewCompatibilityPolicy(nil)
+	}
+	return invstate.NewCompatibilityPolicy(r.store.Current().Inventory.CompatibilityRules)
+}
+
+// conditionPolicy compiles inventory.conditionPolicy on every reconcile, so a changed policy reaches each node on
+// its next sync.
+func (r *Reconciler) conditionPolicy() invstate.ConditionPolicy {
+	if r.store == nil {
+		return invstate.ConditionPolicy{}
+	}
+	return invstate.NewConditionPolicy(r.store.Current().Inventory.ConditionPolicy)
+}
+
+// maxDeletions resolves inventory.maxDeletionsPerSweep for the shared deletion limiter.
+func (r *Reconciler) maxDeletions(known int) int {
+	inventory := moduleconfig.DefaultState().Inventory
+	if r.store != nil {
+		inventory = r.store.Current().Inventory
+	}
+	return inventory.MaxDeletions(known)
+}
+
+// deviceNameTemplate is read on every reconcile; it only affects devices created afterwards.
+func (r *Reconciler) deviceNameTemplate() string {
+	if r.store == nil {
+		return moduleconfig.DefaultDeviceNameTemplate
+	}
+	return r.store.Current().Inventory.DeviceNameTemplate
+}
+
+// includeDisplayDevices is read on every reconcile, so enabling it creates the missing devices on the next sync.
+func (r *Reconciler) includeDisplayDevices() bool {
+	return r.store != nil && r.store.Current().Inventory.IncludeDisplayDevices
+}
+
+// attributePassthroughPrefixes is read on every reconcile, so devices pick up a changed list on their next sync.
+func (r *Reconciler) attributePassthroughPrefixes() []string {
+	if r.store == nil {
+		return nil
+	}
+	return r.store.Current().Inventory.AttributePassthroughPrefixes
+}
+
+// trustedNodeFeatureNamespaces is read on every reconcile, like the other inventory settings.
+func (r *Reconciler) trustedNodeFeatureNamespaces() []string {
+	if r.store == nil {
+		return moduleconfig.DefaultState().Inventory.TrustedNodeFeatureNamespaces
+	}
+	return r.store.Current().Inventory.TrustedNodeFeatureNamespaces
+}
+
+// paused reports whether settings.paused freezes the module. GPUNodeState carries the ModulePaused
+// condition from the bootstrap controller, so inventory has nothing to mark.
+func (r *Reconciler) paused() bool {
+	return r.store != nil && r.store.Current().Paused
+}
+
 func (r *Reconciler) applyInventoryResync(state moduleconfig.State) {
 	if state.Inventory.ResyncPeriod == "" {
 		return
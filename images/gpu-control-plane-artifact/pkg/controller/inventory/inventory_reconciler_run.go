@@ -16,14 +16,21 @@ package inventory
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
 	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
 	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 )
@@ -32,32 +39,100 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	logger := log.FromContext(ctx).WithValues("node", req.Name)
 	ctx = logr.NewContext(ctx, logger)
 
+	// A paused module must not even read: returning without a requeue lets the queue drain.
+	if r.paused() {
+		logger.V(2).Info("module paused, skipping inventory reconciliation")
+		return ctrl.Result{}, nil
+	}
+	// A suppressed node is dropped without reading it, so a poison-pill object stops being retried.
+	if r.nodeQueue.Suppressed(req.Name) {
+		logger.V(1).Info("node suppressed through the admin API, skipping inventory reconciliation")
+		r.metrics.InventoryReconcileSuppressedInc(req.Name)
+		return ctrl.Result{}, nil
+	}
+
 	node := &corev1.Node{}
 	node, err := commonobject.FetchObject(ctx, req.NamespacedName, r.client, node)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if node == nil {
-		// Rely on ownerReferences GC; avoid aggressive cleanup that may fire on transient cache misses.
-		logger.V(1).Info("node removed, skipping reconciliation")
-		r.cleanupSvc().ClearMetrics(req.Name)
-		return ctrl.Result{}, nil
+		return r.finalizeRemovedNode(ctx, req.Name)
 	}
 
+	res, err := r.reconcileNode(ctx, node)
+	outcome := err
+	if errors.Is(err, commonerrors.ErrNodeFeatureMissing) {
+		// A node without a NodeFeature is waiting for NFD, not failing.
+		outcome = nil
+	}
+	r.inventorySvc().RecordReconcile(ctx, node.Name, outcome)
+	return r.resolveError(ctx, node, res, err)
+}
+
+func (r *Reconciler) reconcileNode(ctx context.Context, node *corev1.Node) (ctrl.Result, error) {
 	managedPolicy, approvalPolicy := r.currentPolicies()
 
-	nodeFeature, err := invstate.FindNodeFeature(ctx, r.client, node.Name)
+	// Without the compiled NodeFeature version every node would look like it is waiting for NFD; report the
+	// actual cause instead and wait for the checker to requeue the nodes once the version is served.
+	if !r.nodeFeatureAPI.Supported() {
+		return ctrl.Result{}, r.inventorySvc().MarkNodeFeatureAPIUnsupported(ctx, node, r.nodeFeatureAPI.Message())
+	}
+
+	nodeFeature, untrusted, err := invstate.FindNodeFeature(ctx, r.client, node.Name, r.trustedNodeFeatureNamespaces())
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	r.inventorySvc().ReportUntrustedNodeFeatures(ctx, node, untrusted)
 
-	state := invstate.NewInventoryState(node, nodeFeature, managedPolicy, approvalPolicy)
+	state := invstate.NewInventoryState(node, nodeFeature, managedPolicy, approvalPolicy, r.stalenessPolicy(), r.compatibilityPolicy())
+
+	r.syncHandlerSettings(ctx)
 
 	rec := ctrlreconciler.NewBaseReconciler[Handler](r.handlerChain())
+	rec.SetHandlerFilter(r.handlerRuntime.Enabled)
 	rec.SetHandlerExecutor(func(ctx context.Context, h Handler) (reconcile.Result, error) {
 		return h.Handle(ctx, state)
 	})
 	rec.SetResourceUpdater(func(ctx context.Context) error { return nil })
 
-	return rec.Reconcile(ctx)
+	res, err := rec.Reconcile(ctx)
+	if err == nil {
+		r.sweeps.NodeReconciled(node.Name, time.Now())
+		r.propagation.Done(node.Name)
+	}
+	return res, err
+}
+
+// finalizeRemovedNode runs once the Node object is gone. Inventory is only removed eagerly when the
+// node was observed draining beforehand; otherwise rely on ownerReferences GC to avoid aggressive
+// cleanup that may fire on transient cache misses.
+func (r *Reconciler) finalizeRemovedNode(ctx context.Context, nodeName string) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	r.sweeps.NodeRemoved(nodeName)
+	r.propagation.Done(nodeName)
+
+	inventory, err := commonobject.FetchObject(ctx, types.NamespacedName{Name: nodeName}, r.client, &v1alpha1.GPUNodeState{})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if inventory != nil && apimeta.IsStatusConditionTrue(inventory.Status.Conditions, invstate.ConditionNodeDraining) {
+		logger.V(1).Info("drained node removed, cleaning up inventory")
+		err := r.cleanupSvc().CleanupNode(ctx, nodeName)
+		var throttled *invservice.DeletionsThrottledError
+		if errors.As(err, &throttled) {
+			logger.Info("GPU device deletions throttled", "deferred", throttled.Deferred, "retryAfter", throttled.RetryAfter)
+			return ctrl.Result{RequeueAfter: throttled.RetryAfter}, nil
+		}
+		var held *invservice.DevicesInUseError
+		if errors.As(err, &held) {
+			logger.Info("GPU devices still in use, deletion deferred", "held", held.Held, "retryAfter", held.RetryAfter)
+			return ctrl.Result{RequeueAfter: held.RetryAfter}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	logger.V(1).Info("node removed, skipping reconciliation")
+	r.cleanupSvc().ClearMetrics(nodeName)
+	return ctrl.Result{}, nil
 }
@@ -16,15 +16,20 @@ package inventory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
 	invwatcher "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/watcher"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/watchers"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
@@ -43,35 +48,70 @@ func (r *Reconciler) SetupController(ctx context.Context, mgr manager.Manager, c
 	r.recorder = eventrecord.NewEventRecorderLogger(mgr, ControllerName).
 		WithLogging(r.log.WithName(ControllerName))
 	if r.detectionCollector == nil {
-		r.detectionCollector = invservice.NewDetectionCollector(r.client)
+		r.detectionCollector = invservice.NewDetectionCollector(r.client, httpcall.New(httpcall.CategoryDetection, r.cfg.HTTPTimeouts.Detection), r.metrics)
 	}
 	if r.cleanupService == nil {
-		r.cleanupService = invservice.NewCleanupService(r.client, r.recorder)
+		r.cleanupService = invservice.NewCleanupService(r.client, r.recorder, r.deletionLimiter, r.metrics, r.guard)
 	}
 	if r.deviceService == nil {
-		r.deviceService = invservice.NewDeviceService(r.client, r.scheme, r.recorder, r.deviceHandlers)
+		r.deviceService = r.newDeviceService()
 	}
 	if r.inventoryService == nil {
-		r.inventoryService = invservice.NewInventoryService(r.client, r.scheme, r.recorder)
+		r.inventoryService = r.newInventoryService()
 	}
 
 	if idx := mgr.GetFieldIndexer(); idx != nil {
-		obj, field, extract := indexer.IndexGPUDeviceByNode()
-		if err := idx.IndexField(ctx, obj, field, extract); err != nil {
-			return err
+		for _, getter := range []indexer.IndexGetter{
+			indexer.IndexGPUDeviceByNode,
+			indexer.IndexGPUDeviceByInventoryID,
+		} {
+			obj, field, extract := getter()
+			if err := idx.IndexField(ctx, obj, field, extract); err != nil {
+				return err
+			}
 		}
 	}
 
-	for _, w := range []Watcher{
-		invwatcher.NewNodeWatcher(),
-		invwatcher.NewNodeFeatureWatcher(),
+	// A NodeFeature watch on a version the cluster does not serve never syncs, so it is only registered once the
+	// version is served; until then nodes are reported with DiscoveryAPIUnsupported.
+	nodeFeatureWatcher := invwatcher.NewNodeFeatureWatcher()
+	list := []Watcher{invwatcher.NewNodeWatcher()}
+	nodeFeatureWatched := r.nodeFeatureAPI.Supported()
+	if nodeFeatureWatched {
+		list = append(list, nodeFeatureWatcher)
+	} else {
+		r.log.Info("NodeFeature watch skipped", "reason", r.nodeFeatureAPI.Message())
+	}
+	list = append(list,
 		invwatcher.NewGFDPodWatcher(),
 		invwatcher.NewNodeStateWatcher(),
-	} {
+		watchers.NewModulePauseWatcher(r.log.WithName("watcher.modulePause"), r.store, func() client.ObjectList { return &corev1.NodeList{} }),
+		invwatcher.NewModuleConfigWatcher(r.log.WithName("watcher.moduleConfig"), r.store, r.propagation),
+	)
+	for _, w := range list {
 		if err := w.Watch(mgr, ctr); err != nil {
 			return fmt.Errorf("failed to run watcher %s: %w", reflect.TypeOf(w).Elem().Name(), err)
 		}
 	}
+	if err := ctr.Watch(r.nodeQueue.source(mgr.GetCache())); err != nil {
+		return fmt.Errorf("capture inventory work queue: %w", err)
+	}
+
+	r.nodeFeatureAPI.OnRecovered(func() {
+		if !nodeFeatureWatched {
+			if err := nodeFeatureWatcher.Watch(mgr, ctr); err != nil {
+				r.log.Error(err, "failed to register NodeFeature watch")
+				return
+			}
+			nodeFeatureWatched = true
+		}
+		count, err := r.nodeQueue.RequeueAllNodes(ctx)
+		if err != nil && !errors.Is(err, ErrQueueNotStarted) {
+			r.log.Error(err, "failed to requeue nodes after NodeFeature API recovery")
+			return
+		}
+		r.log.Info("NodeFeature API served again, requeued nodes", "nodes", count)
+	})
 
 	return nil
 }
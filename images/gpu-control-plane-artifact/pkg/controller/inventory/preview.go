@@ -0,0 +1,50 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+type (
+	ImpactReport = invservice.ImpactReport
+	NodeImpact   = invservice.NodeImpact
+	DeviceImpact = invservice.DeviceImpact
+	PoolImpact   = invservice.PoolImpact
+	Change       = invservice.Change
+)
+
+// PreviewSettings reports how the inventory would change if proposed replaced current. Both states go
+// through managedAndApprovalFromState, exactly like the store-driven reconcile path.
+func PreviewSettings(ctx context.Context, c client.Client, current, proposed moduleconfig.State, now time.Time) (ImpactReport, error) {
+	currentManaged, currentApproval, err := managedAndApprovalFromState(current, now)
+	if err != nil {
+		return ImpactReport{}, err
+	}
+	proposedManaged, proposedApproval, err := managedAndApprovalFromState(proposed, now)
+	if err != nil {
+		return ImpactReport{}, err
+	}
+	return invservice.PreviewImpact(ctx, c,
+		invservice.Policies{Managed: currentManaged, Approval: currentApproval, TrustedNodeFeatureNamespaces: current.Inventory.TrustedNodeFeatureNamespaces},
+		invservice.Policies{Managed: proposedManaged, Approval: proposedApproval, TrustedNodeFeatureNamespaces: proposed.Inventory.TrustedNodeFeatureNamespaces},
+	)
+}
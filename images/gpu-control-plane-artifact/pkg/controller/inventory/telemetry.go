@@ -0,0 +1,55 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/httpcall"
+	invservice "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/service"
+	invstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory/internal/state"
+)
+
+// TelemetrySource reads GPU utilization from the same gfd-extender detections the inventory scrapes.
+type TelemetrySource struct {
+	collector invservice.DetectionCollector
+}
+
+// NewTelemetrySource builds a telemetry source backed by the gfd-extender pods; each scrape is bounded by timeout.
+// Telemetry scrapes record no inventory metrics, so the detection schema gauge follows the inventory alone.
+func NewTelemetrySource(c client.Client, timeout time.Duration) *TelemetrySource {
+	return &TelemetrySource{collector: invservice.NewDetectionCollector(c, httpcall.New(httpcall.CategoryTelemetry, timeout), nil)}
+}
+
+// Utilization returns the GPU utilization ratio of the node devices keyed by device name.
+// Devices gfd-extender does not report are absent from the result.
+func (s *TelemetrySource) Utilization(ctx context.Context, node string, devices []v1alpha1.GPUDevice) (map[string]float64, error) {
+	detections, err := s.collector.Collect(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]float64, len(devices))
+	for i := range devices {
+		device := &devices[i]
+		if value, ok := detections.GPUUtilization(device.Status.Hardware.UUID, device.Labels[invstate.DeviceIndexLabelKey]); ok {
+			result[device.Name] = value
+		}
+	}
+	return result, nil
+}
@@ -14,12 +14,40 @@
 
 package moduleconfig
 
+import "encoding/json"
+
 // Clone performs a deep copy of the state to guarantee isolation between store consumers.
 func (s State) Clone() State {
 	clone := s
 	if s.Settings.DeviceApproval.Selector != nil {
 		clone.Settings.DeviceApproval.Selector = s.Settings.DeviceApproval.Selector.DeepCopy()
 	}
+	if s.Handlers != nil {
+		clone.Handlers = make(map[string]HandlerSettings, len(s.Handlers))
+		for name, settings := range s.Handlers {
+			settings.Settings = append(json.RawMessage(nil), settings.Settings...)
+			clone.Handlers[name] = settings
+		}
+	}
+	if s.PoolTemplates != nil {
+		clone.PoolTemplates = make([]PoolTemplate, len(s.PoolTemplates))
+		for i, template := range s.PoolTemplates {
+			template.PoolSpec = *template.PoolSpec.DeepCopy()
+			clone.PoolTemplates[i] = template
+		}
+	}
+	if s.Inventory.CompatibilityRules != nil {
+		clone.Inventory.CompatibilityRules = append([]CompatibilityRule(nil), s.Inventory.CompatibilityRules...)
+	}
+	if s.Inventory.ConditionPolicy != nil {
+		clone.Inventory.ConditionPolicy = make(map[string]ConditionPolicy, len(s.Inventory.ConditionPolicy))
+		for conditionType, policy := range s.Inventory.ConditionPolicy {
+			if policy.SuppressOnNodesMatching != nil {
+				policy.SuppressOnNodesMatching = policy.SuppressOnNodesMatching.DeepCopy()
+			}
+			clone.Inventory.ConditionPolicy[conditionType] = policy
+		}
+	}
 	clone.Sanitized = deepCopySanitizedMap(s.Sanitized)
 	return clone
 }
@@ -14,6 +14,8 @@
 
 package moduleconfig
 
+import "time"
+
 const (
 	DefaultNodeLabelKey           = "gpu.deckhouse.io/enabled"
 	DefaultDeviceApprovalMode     = DeviceApprovalModeManual
@@ -21,18 +23,29 @@ const (
 	DefaultSchedulingTopology     = "topology.kubernetes.io/zone"
 	DefaultMonitoringService      = true
 	DefaultInventoryResyncPeriod  = ""
+	DefaultDeviceNameTemplate     = DeviceNameVarNode + "-" + DeviceNameVarIndex + "-" + DeviceNameVarVendor + "-" + DeviceNameVarDevice
 	DefaultLogLevel               = "Info"
 	DefaultHTTPSMode              = HTTPSModeCertManager
 	DefaultHTTPSCertManagerIssuer = "letsencrypt"
+
+	DefaultLabelKeyTransitionWindow = 168 * time.Hour
+	DefaultStaleNodeThreshold       = 24 * time.Hour
+	DefaultStaleDeviceRetention     = time.Duration(0)
+	DefaultMaxDeletionsPerSweep     = "10%"
+	DefaultRequeueSpreadWindow      = 2 * time.Minute
+	DefaultImageDigestCacheTTL      = time.Hour
+	// DefaultTrustedNodeFeatureNamespace is where the node-feature-discovery module publishes NodeFeatures.
+	DefaultTrustedNodeFeatureNamespace = "d8-node-feature-discovery"
 )
 
 func DefaultState() State {
 	settings := Settings{
-		ManagedNodes:   ManagedNodesSettings{LabelKey: DefaultNodeLabelKey, EnabledByDefault: true},
+		ManagedNodes:   ManagedNodesSettings{LabelKey: DefaultNodeLabelKey, EnabledByDefault: true, TransitionWindow: DefaultLabelKeyTransitionWindow},
 		DeviceApproval: DeviceApprovalSettings{Mode: DeviceApprovalModeManual},
 		Scheduling:     SchedulingSettings{DefaultStrategy: DefaultSchedulingStrategy, TopologyKey: DefaultSchedulingTopology},
 		Placement:      PlacementSettings{},
 		Monitoring:     MonitoringSettings{ServiceMonitor: DefaultMonitoringService},
+		ImageDigests:   ImageDigestSettings{CacheTTL: DefaultImageDigestCacheTTL},
 		LogLevel:       DefaultLogLevel,
 	}
 	sanitized := map[string]any{
@@ -46,8 +59,16 @@ func DefaultState() State {
 		"https":          map[string]any{"mode": string(DefaultHTTPSMode), "certManager": map[string]any{"clusterIssuerName": DefaultHTTPSCertManagerIssuer}},
 	}
 	return State{
-		Settings:  settings,
-		Inventory: InventorySettings{ResyncPeriod: DefaultInventoryResyncPeriod},
+		Settings: settings,
+		Inventory: InventorySettings{
+			ResyncPeriod:                 DefaultInventoryResyncPeriod,
+			DeviceNameTemplate:           DefaultDeviceNameTemplate,
+			StaleNodeThreshold:           DefaultStaleNodeThreshold,
+			StaleDeviceRetention:         DefaultStaleDeviceRetention,
+			MaxDeletionsPerSweep:         DefaultMaxDeletionsPerSweep,
+			RequeueSpreadWindow:          DefaultRequeueSpreadWindow,
+			TrustedNodeFeatureNamespaces: []string{DefaultTrustedNodeFeatureNamespace},
+		},
 		HTTPS:     HTTPSSettings{Mode: DefaultHTTPSMode, CertManagerIssuer: DefaultHTTPSCertManagerIssuer},
 		Sanitized: sanitized,
 	}
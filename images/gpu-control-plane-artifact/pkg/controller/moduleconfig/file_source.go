@@ -0,0 +1,139 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
+)
+
+// DefaultFileSourceInterval is how often the settings file is re-read. Polling the content
+// instead of watching inodes survives the symlink swaps kubelet uses for ConfigMap volumes.
+const DefaultFileSourceInterval = 10 * time.Second
+
+// FileSource feeds a ModuleConfigStore from a mounted YAML file with the same schema as
+// ModuleConfig spec.settings. It is meant for installs without the ModuleConfig CRD: when the
+// ModuleConfig object exists it wins and the file is ignored.
+type FileSource struct {
+	log      logr.Logger
+	path     string
+	store    *ModuleConfigStore
+	reader   client.Reader
+	interval time.Duration
+
+	content    []byte
+	overridden bool
+}
+
+// NewFileSource creates a file source; reader is used to detect the ModuleConfig object and may be nil.
+func NewFileSource(log logr.Logger, path string, store *ModuleConfigStore, reader client.Reader) *FileSource {
+	return &FileSource{
+		log:      log,
+		path:     path,
+		store:    store,
+		reader:   reader,
+		interval: DefaultFileSourceInterval,
+	}
+}
+
+// Sync re-reads the file and updates the store when its content changed. Invalid content is
+// reported and the previous state is kept.
+func (s *FileSource) Sync(ctx context.Context) error {
+	present, err := s.moduleConfigPresent(ctx)
+	if err != nil {
+		return err
+	}
+	if present {
+		if !s.overridden {
+			s.log.Info("ModuleConfig exists, ignoring module settings file", "moduleConfig", ModuleConfigName, "path", s.path)
+		}
+		s.overridden = true
+		s.content = nil
+		return nil
+	}
+	s.overridden = false
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("read module settings file: %w", err)
+	}
+	if s.content != nil && bytes.Equal(data, s.content) {
+		return nil
+	}
+	s.content = data
+
+	state, err := parseSettingsFile(data)
+	if err != nil {
+		return fmt.Errorf("parse module settings file %s: %w", s.path, err)
+	}
+	s.store.Update(state)
+	s.log.Info("module settings loaded from file", "path", s.path)
+	return nil
+}
+
+// Start polls the file until ctx is cancelled.
+func (s *FileSource) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Sync(ctx); err != nil {
+				s.log.Error(err, "keeping previous module settings")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection reports false: webhooks on every replica read the store.
+func (s *FileSource) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *FileSource) moduleConfigPresent(ctx context.Context) (bool, error) {
+	if s.reader == nil {
+		return false, nil
+	}
+	err := s.reader.Get(ctx, client.ObjectKey{Name: ModuleConfigName}, &mcapi.ModuleConfig{})
+	switch {
+	case err == nil:
+		return true, nil
+	case apierrors.IsNotFound(err), meta.IsNoMatchError(err):
+		return false, nil
+	default:
+		return false, fmt.Errorf("get ModuleConfig %s: %w", ModuleConfigName, err)
+	}
+}
+
+func parseSettingsFile(data []byte) (State, error) {
+	settings := map[string]any{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return State{}, fmt.Errorf("decode: %w", err)
+	}
+	return Parse(Input{Settings: settings})
+}
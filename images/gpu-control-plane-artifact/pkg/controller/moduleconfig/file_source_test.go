@@ -0,0 +1,173 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
+)
+
+func TestFileSourceLoadsSettingsWithoutModuleConfigCRD(t *testing.T) {
+	path := writeSettingsFile(t, t.TempDir(), "scheduling:\n  defaultStrategy: BinPack\n")
+	noCRD := fake.NewClientBuilder().WithScheme(moduleConfigScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "deckhouse.io", Kind: "ModuleConfig"}}
+		},
+	}).Build()
+
+	for name, reader := range map[string]client.Reader{"no reader": nil, "no CRD": noCRD} {
+		t.Run(name, func(t *testing.T) {
+			store := NewModuleConfigStore(DefaultState())
+			source := NewFileSource(testr.New(t), path, store, reader)
+			if err := source.Sync(context.Background()); err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+			if got := store.Current().Settings.Scheduling.DefaultStrategy; got != "BinPack" {
+				t.Fatalf("expected settings from file, got strategy %q", got)
+			}
+		})
+	}
+}
+
+func TestFileSourceIgnoredWhenModuleConfigExists(t *testing.T) {
+	path := writeSettingsFile(t, t.TempDir(), "scheduling:\n  defaultStrategy: BinPack\n")
+	mc := &mcapi.ModuleConfig{ObjectMeta: metav1.ObjectMeta{Name: ModuleConfigName}}
+	reader := fake.NewClientBuilder().WithScheme(moduleConfigScheme(t)).WithObjects(mc).Build()
+
+	store := NewModuleConfigStore(DefaultState())
+	source := NewFileSource(testr.New(t), path, store, reader)
+	if err := source.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := store.Current().Settings.Scheduling.DefaultStrategy; got != DefaultSchedulingStrategy {
+		t.Fatalf("expected ModuleConfig to win over file, got strategy %q", got)
+	}
+
+	if err := reader.Delete(context.Background(), mc); err != nil {
+		t.Fatalf("delete ModuleConfig: %v", err)
+	}
+	if err := source.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := store.Current().Settings.Scheduling.DefaultStrategy; got != "BinPack" {
+		t.Fatalf("expected file settings once ModuleConfig is gone, got strategy %q", got)
+	}
+}
+
+func TestFileSourceInvalidFileKeepsPreviousState(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSettingsFile(t, dir, "scheduling:\n  defaultStrategy: BinPack\n")
+	store := NewModuleConfigStore(DefaultState())
+	source := NewFileSource(testr.New(t), path, store, nil)
+	if err := source.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	writeSettingsFile(t, dir, "scheduling:\n  defaultStrategy: Random\n")
+	if err := source.Sync(context.Background()); err == nil {
+		t.Fatalf("expected invalid settings to be reported")
+	}
+	if got := store.Current().Settings.Scheduling.DefaultStrategy; got != "BinPack" {
+		t.Fatalf("expected previous state to be kept, got strategy %q", got)
+	}
+
+	writeSettingsFile(t, dir, "scheduling: [")
+	if err := source.Sync(context.Background()); err == nil {
+		t.Fatalf("expected malformed YAML to be reported")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove settings: %v", err)
+	}
+	if err := source.Sync(context.Background()); err == nil {
+		t.Fatalf("expected missing file to be reported")
+	}
+	if got := store.Current().Settings.Scheduling.DefaultStrategy; got != "BinPack" {
+		t.Fatalf("expected previous state to be kept, got strategy %q", got)
+	}
+}
+
+func TestFileSourceFollowsConfigMapSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "..2025_01_01")
+	second := filepath.Join(dir, "..2025_01_02")
+	for dataDir, strategy := range map[string]string{first: "BinPack", second: "Spread"} {
+		if err := os.Mkdir(dataDir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		writeSettingsFile(t, dataDir, "scheduling:\n  defaultStrategy: "+strategy+"\n")
+	}
+	dataLink := filepath.Join(dir, "..data")
+	if err := os.Symlink(first, dataLink); err != nil {
+		t.Fatalf("symlink ..data: %v", err)
+	}
+	path := filepath.Join(dir, "settings.yaml")
+	if err := os.Symlink(filepath.Join("..data", "settings.yaml"), path); err != nil {
+		t.Fatalf("symlink settings: %v", err)
+	}
+
+	store := NewModuleConfigStore(DefaultState())
+	source := NewFileSource(testr.New(t), path, store, nil)
+	if err := source.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := store.Current().Settings.Scheduling.DefaultStrategy; got != "BinPack" {
+		t.Fatalf("unexpected strategy %q", got)
+	}
+
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(second, tmpLink); err != nil {
+		t.Fatalf("symlink tmp: %v", err)
+	}
+	if err := os.Rename(tmpLink, dataLink); err != nil {
+		t.Fatalf("swap ..data: %v", err)
+	}
+	if err := source.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := store.Current().Settings.Scheduling.DefaultStrategy; got != "Spread" {
+		t.Fatalf("expected swapped settings, got strategy %q", got)
+	}
+}
+
+func writeSettingsFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "settings.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+	return path
+}
+
+func moduleConfigScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := mcapi.AddToScheme(scheme); err != nil {
+		t.Fatalf("add moduleconfig scheme: %v", err)
+	}
+	return scheme
+}
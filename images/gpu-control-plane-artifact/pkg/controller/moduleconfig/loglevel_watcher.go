@@ -0,0 +1,136 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
+)
+
+// LogLevelWatcher keeps the process log level in line with settings.logLevel. Besides the settings
+// file, nothing refreshes the store at runtime, so it also copies logLevel from the ModuleConfig
+// object into the store; changing the level then takes effect without restarting the Pod.
+type LogLevelWatcher struct {
+	log      logr.Logger
+	store    *ModuleConfigStore
+	level    *logger.Level
+	reader   client.Reader
+	interval time.Duration
+	events   <-chan struct{}
+}
+
+// NewLogLevelWatcher creates a watcher; reader is used to read the ModuleConfig object and may be nil.
+func NewLogLevelWatcher(log logr.Logger, store *ModuleConfigStore, level *logger.Level, reader client.Reader) *LogLevelWatcher {
+	return &LogLevelWatcher{
+		log:      log,
+		store:    store,
+		level:    level,
+		reader:   reader,
+		interval: DefaultFileSourceInterval,
+		events:   store.LogLevelEvents(),
+	}
+}
+
+// Start applies the stored level and follows its changes until ctx is cancelled.
+func (w *LogLevelWatcher) Start(ctx context.Context) error {
+	if err := w.Sync(ctx); err != nil {
+		w.log.Error(err, "keeping previous log level")
+	}
+	w.apply()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.events:
+			w.apply()
+		case <-ticker.C:
+			if err := w.Sync(ctx); err != nil {
+				w.log.Error(err, "keeping previous log level")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection reports false: every replica logs.
+func (w *LogLevelWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync copies settings.logLevel of the ModuleConfig object into the store. A missing object or
+// CRD leaves the store to the other sources.
+func (w *LogLevelWatcher) Sync(ctx context.Context) error {
+	if w.reader == nil {
+		return nil
+	}
+	mc := &mcapi.ModuleConfig{}
+	err := w.reader.Get(ctx, client.ObjectKey{Name: ModuleConfigName}, mc)
+	switch {
+	case apierrors.IsNotFound(err), meta.IsNoMatchError(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("get ModuleConfig %s: %w", ModuleConfigName, err)
+	}
+
+	var raw json.RawMessage
+	if value, ok := mc.Spec.Settings["logLevel"]; ok {
+		if raw, err = json.Marshal(value); err != nil {
+			return fmt.Errorf("encode logLevel: %w", err)
+		}
+	}
+	level, err := parseLogLevel(raw)
+	if err != nil {
+		return err
+	}
+
+	state := w.store.Current()
+	if state.Settings.LogLevel == level {
+		return nil
+	}
+	state.Settings.LogLevel = level
+	if state.Sanitized != nil {
+		state.Sanitized["logLevel"] = level
+	}
+	w.store.Update(state)
+	return nil
+}
+
+// apply switches the level and reports the transition twice, so it is visible whichever of the
+// old and new levels the reader has been running with.
+func (w *LogLevelWatcher) apply() {
+	next := w.store.Current().Settings.LogLevel
+	previous := w.level.Name()
+	if next == previous {
+		return
+	}
+	w.log.Info("changing log level", "from", previous, "to", next)
+	if _, err := w.level.Set(next); err != nil {
+		w.log.Error(err, "keeping previous log level", "level", previous)
+		return
+	}
+	w.log.Info("log level changed", "from", previous, "to", next)
+}
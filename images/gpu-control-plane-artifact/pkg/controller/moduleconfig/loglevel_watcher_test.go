@@ -0,0 +1,169 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
+)
+
+// syncBuffer lets the test read log output while the watcher goroutine writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogLevelWatcherFollowsStore(t *testing.T) {
+	out := &syncBuffer{}
+	level := logger.NewLevel(DefaultLogLevel)
+	log := zap.New(zap.WriteTo(out), zap.Level(level))
+	store := NewModuleConfigStore(DefaultState())
+	watcher := NewLogLevelWatcher(log.WithName("log-level"), store, level, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	log.V(1).Info("debug before")
+
+	debug := DefaultState()
+	debug.Settings.LogLevel = "Debug"
+	store.Update(debug)
+	waitForLevel(t, level, "Debug")
+	log.V(1).Info("debug after")
+
+	errorOnly := DefaultState()
+	errorOnly.Settings.LogLevel = "Error"
+	store.Update(errorOnly)
+	waitForLevel(t, level, "Error")
+	log.Info("info suppressed")
+	log.Error(nil, "error kept")
+
+	got := out.String()
+	for _, want := range []string{"debug after", "error kept", "changing log level", "log level changed"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"debug before", "info suppressed"} {
+		if strings.Contains(got, unwanted) {
+			t.Fatalf("expected %q to be suppressed:\n%s", unwanted, got)
+		}
+	}
+	// Lowering the level to Error is announced only while Debug is still in effect.
+	if n := strings.Count(got, `"to":"Error"`); n != 1 {
+		t.Fatalf("expected a single transition record to Error, got %d:\n%s", n, got)
+	}
+}
+
+func TestLogLevelWatcherSyncsModuleConfig(t *testing.T) {
+	mc := &mcapi.ModuleConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: ModuleConfigName},
+		Spec:       mcapi.ModuleConfigSpec{Settings: mcapi.SettingsValues{"logLevel": "debug"}},
+	}
+	reader := fake.NewClientBuilder().WithScheme(moduleConfigScheme(t)).WithObjects(mc).Build()
+	store := NewModuleConfigStore(DefaultState())
+	events := store.LogLevelEvents()
+	watcher := NewLogLevelWatcher(testr.New(t), store, logger.NewLevel(DefaultLogLevel), reader)
+
+	if err := watcher.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	state := store.Current()
+	if state.Settings.LogLevel != "Debug" || state.Sanitized["logLevel"] != "Debug" {
+		t.Fatalf("expected ModuleConfig logLevel in the store, got %q / %v", state.Settings.LogLevel, state.Sanitized["logLevel"])
+	}
+	select {
+	case <-events:
+	default:
+		t.Fatalf("expected a log level event")
+	}
+
+	mc.Spec.Settings = mcapi.SettingsValues{}
+	if err := reader.Update(context.Background(), mc); err != nil {
+		t.Fatalf("update ModuleConfig: %v", err)
+	}
+	if err := watcher.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := store.Current().Settings.LogLevel; got != DefaultLogLevel {
+		t.Fatalf("expected an unset logLevel to fall back to %s, got %q", DefaultLogLevel, got)
+	}
+
+	mc.Spec.Settings = mcapi.SettingsValues{"logLevel": "Verbose"}
+	if err := reader.Update(context.Background(), mc); err != nil {
+		t.Fatalf("update ModuleConfig: %v", err)
+	}
+	if err := watcher.Sync(context.Background()); err == nil {
+		t.Fatalf("expected an error for an unknown logLevel")
+	}
+	if got := store.Current().Settings.LogLevel; got != DefaultLogLevel {
+		t.Fatalf("expected the previous level to be kept, got %q", got)
+	}
+}
+
+func TestLogLevelWatcherWithoutModuleConfig(t *testing.T) {
+	reader := fake.NewClientBuilder().WithScheme(moduleConfigScheme(t)).Build()
+	state := DefaultState()
+	state.Settings.LogLevel = "Warn"
+	store := NewModuleConfigStore(state)
+	watcher := NewLogLevelWatcher(testr.New(t), store, logger.NewLevel(DefaultLogLevel), reader)
+
+	if err := watcher.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := store.Current().Settings.LogLevel; got != "Warn" {
+		t.Fatalf("expected store to keep its level without a ModuleConfig, got %q", got)
+	}
+}
+
+func waitForLevel(t *testing.T, level *logger.Level, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for level.Name() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for level %s, got %s", want, level.Name())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
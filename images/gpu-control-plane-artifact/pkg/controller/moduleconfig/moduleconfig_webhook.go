@@ -27,7 +27,8 @@ import (
 	mcapi "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig/api"
 )
 
-const moduleConfigName = "gpu-control-plane"
+// ModuleConfigName is the name of the ModuleConfig object holding module settings.
+const ModuleConfigName = "gpu-control-plane"
 
 func SetupWebhookWithManager(mgr manager.Manager, log logr.Logger) error {
 	if mgr.GetWebhookServer() == nil {
@@ -53,7 +54,7 @@ func (v *ModuleConfigValidator) ValidateCreate(_ context.Context, obj runtime.Ob
 	if !ok {
 		return nil, fmt.Errorf("expected ModuleConfig but got %T", obj)
 	}
-	if mc.GetName() != moduleConfigName {
+	if mc.GetName() != ModuleConfigName {
 		return nil, nil
 	}
 	return nil, validateModuleConfig(mc)
@@ -68,7 +69,7 @@ func (v *ModuleConfigValidator) ValidateUpdate(_ context.Context, oldObj, newObj
 	if !ok {
 		return nil, fmt.Errorf("expected new ModuleConfig but got %T", newObj)
 	}
-	if newMC.GetName() != moduleConfigName {
+	if newMC.GetName() != ModuleConfigName {
 		return nil, nil
 	}
 	if oldMC.GetGeneration() == newMC.GetGeneration() {
@@ -17,9 +17,17 @@ package moduleconfig
 import (
 	"encoding/json"
 	"fmt"
+
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 )
 
+// Parse validates the ModuleConfig input; every failure is marked with commonerrors.ErrConfigInvalid.
 func Parse(input Input) (State, error) {
+	state, err := parse(input)
+	return state, commonerrors.Wrap(commonerrors.ErrConfigInvalid, err)
+}
+
+func parse(input Input) (State, error) {
 	state := DefaultState()
 	if input.Enabled != nil {
 		state.Enabled = *input.Enabled
@@ -40,6 +48,12 @@ func Parse(input Input) (State, error) {
 	}
 	state.Settings.ManagedNodes = managed
 	state.Sanitized["managedNodes"] = map[string]any{"labelKey": managed.LabelKey, "enabledByDefault": managed.EnabledByDefault}
+	if managed.PreviousLabelKey != "" {
+		state.Sanitized["managedNodes"].(map[string]any)["previousLabelKey"] = managed.PreviousLabelKey
+	}
+	if managed.TransitionWindow != DefaultLabelKeyTransitionWindow {
+		state.Sanitized["managedNodes"].(map[string]any)["labelKeyTransitionWindow"] = formatWindow(managed.TransitionWindow)
+	}
 
 	approval, selector, err := parseApproval(raw["deviceApproval"])
 	if err != nil {
@@ -50,6 +64,9 @@ func Parse(input Input) (State, error) {
 	if selector != nil {
 		m["selector"] = selector
 	}
+	if approval.RequireValidation {
+		m["requireValidationBeforeAttach"] = true
+	}
 	state.Sanitized["deviceApproval"] = m
 
 	scheduling, err := parseScheduling(raw["scheduling"])
@@ -58,6 +75,15 @@ func Parse(input Input) (State, error) {
 	}
 	state.Settings.Scheduling = scheduling
 	state.Sanitized["scheduling"] = map[string]any{"defaultStrategy": scheduling.DefaultStrategy, "topologyKey": scheduling.TopologyKey}
+	if scheduling.PoolNodeLabels {
+		state.Sanitized["scheduling"].(map[string]any)["poolNodeLabels"] = true
+	}
+	if scheduling.ColocationHints {
+		state.Sanitized["scheduling"].(map[string]any)["colocationHints"] = true
+	}
+	if scheduling.SharedPoolNames {
+		state.Sanitized["scheduling"].(map[string]any)["sharedPoolNames"] = true
+	}
 
 	monitoring, err := parseMonitoring(raw["monitoring"])
 	if err != nil {
@@ -66,6 +92,15 @@ func Parse(input Input) (State, error) {
 	state.Settings.Monitoring = monitoring
 	state.Sanitized["monitoring"] = map[string]any{"serviceMonitor": monitoring.ServiceMonitor}
 
+	nodeLabeling, err := parseNodeLabeling(raw["nodeLabeling"])
+	if err != nil {
+		return state, err
+	}
+	state.Settings.NodeLabeling = nodeLabeling
+	if nodeLabeling.Enabled {
+		state.Sanitized["nodeLabeling"] = map[string]any{"enabled": true}
+	}
+
 	logLevel, err := parseLogLevel(raw["logLevel"])
 	if err != nil {
 		return state, err
@@ -83,6 +118,36 @@ func Parse(input Input) (State, error) {
 	}
 	state.Inventory = inventory
 	state.Sanitized["inventory"] = map[string]any{"resyncPeriod": inventory.ResyncPeriod}
+	if inventory.DeviceNameTemplate != DefaultDeviceNameTemplate {
+		state.Sanitized["inventory"].(map[string]any)["deviceNameTemplate"] = inventory.DeviceNameTemplate
+	}
+	if inventory.StaleNodeThreshold != DefaultStaleNodeThreshold {
+		state.Sanitized["inventory"].(map[string]any)["staleNodeThreshold"] = formatWindow(inventory.StaleNodeThreshold)
+	}
+	if inventory.StaleDeviceRetention != DefaultStaleDeviceRetention {
+		state.Sanitized["inventory"].(map[string]any)["staleDeviceRetention"] = formatWindow(inventory.StaleDeviceRetention)
+	}
+	if inventory.MaxDeletionsPerSweep != "" && inventory.MaxDeletionsPerSweep != DefaultMaxDeletionsPerSweep {
+		state.Sanitized["inventory"].(map[string]any)["maxDeletionsPerSweep"] = inventory.MaxDeletionsPerSweep
+	}
+	if inventory.RequeueSpreadWindow != DefaultRequeueSpreadWindow {
+		state.Sanitized["inventory"].(map[string]any)["requeueSpreadWindow"] = formatWindow(inventory.RequeueSpreadWindow)
+	}
+	if len(inventory.AttributePassthroughPrefixes) > 0 {
+		state.Sanitized["inventory"].(map[string]any)["attributePassthroughPrefixes"] = append([]string(nil), inventory.AttributePassthroughPrefixes...)
+	}
+	if !trustsDefaultNodeFeatureNamespaces(inventory.TrustedNodeFeatureNamespaces) {
+		state.Sanitized["inventory"].(map[string]any)["trustedNodeFeatureNamespaces"] = append([]string{}, inventory.TrustedNodeFeatureNamespaces...)
+	}
+	if inventory.IncludeDisplayDevices {
+		state.Sanitized["inventory"].(map[string]any)["includeDisplayDevices"] = true
+	}
+	if len(inventory.CompatibilityRules) > 0 {
+		state.Sanitized["inventory"].(map[string]any)["compatibilityRules"] = sanitizeCompatibilityRules(inventory.CompatibilityRules)
+	}
+	if len(inventory.ConditionPolicy) > 0 {
+		state.Sanitized["inventory"].(map[string]any)["conditionPolicy"] = sanitizeConditionPolicy(inventory.ConditionPolicy)
+	}
 
 	https, httpsMap, err := parseHTTPS(raw["https"], input.Global)
 	if err != nil {
@@ -91,10 +156,55 @@ func Parse(input Input) (State, error) {
 	state.HTTPS = https
 	state.Sanitized["https"] = httpsMap
 
+	handlers, handlersMap, err := parseHandlers(raw["handlers"])
+	if err != nil {
+		return state, err
+	}
+	if handlers != nil {
+		state.Handlers = handlers
+		state.Sanitized["handlers"] = handlersMap
+	}
+
+	templates, templatesList, err := parsePoolTemplates(raw["poolTemplates"])
+	if err != nil {
+		return state, err
+	}
+	if templates != nil {
+		state.PoolTemplates = templates
+		state.Sanitized["poolTemplates"] = templatesList
+	}
+
 	if ha := parseBool(raw["highAvailability"]); ha != nil {
 		state.HighAvailability = ha
 		state.Sanitized["highAvailability"] = *ha
 	}
 
+	if migrate := parseBool(raw["migrateFromGPUOperator"]); migrate != nil && *migrate {
+		state.Settings.Migration.FromGPUOperator = true
+		state.Sanitized["migrateFromGPUOperator"] = true
+	}
+	if adopt := parseBool(raw["adoptExisting"]); adopt != nil && *adopt {
+		state.Settings.Migration.AdoptExisting = true
+		state.Sanitized["adoptExisting"] = true
+	}
+	if legacy := parseBool(raw["legacyPrivileged"]); legacy != nil && *legacy {
+		state.Settings.Security.LegacyPrivileged = true
+		state.Sanitized["legacyPrivileged"] = true
+	}
+
+	imageDigests, err := parseImageDigests(raw["imageDigests"])
+	if err != nil {
+		return state, err
+	}
+	state.Settings.ImageDigests = imageDigests
+	if imageDigests.Enabled {
+		state.Sanitized["imageDigests"] = imageDigests.values()
+	}
+
+	if paused := parseBool(raw["paused"]); paused != nil && *paused {
+		state.Paused = true
+		state.Sanitized["paused"] = true
+	}
+
 	return state, nil
 }
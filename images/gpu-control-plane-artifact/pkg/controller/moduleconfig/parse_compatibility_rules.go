@@ -0,0 +1,106 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/version"
+)
+
+func parseCompatibilityRules(raw json.RawMessage) ([]CompatibilityRule, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var payload []struct {
+		Name         string `json:"name"`
+		DriverRange  string `json:"driverRange"`
+		ToolkitRange string `json:"toolkitRange"`
+		KernelRange  string `json:"kernelRange"`
+		Effect       string `json:"effect"`
+		Message      string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("parse inventory.compatibilityRules: %w", err)
+	}
+
+	rules := make([]CompatibilityRule, 0, len(payload))
+	seen := make(map[string]struct{}, len(payload))
+	for i, item := range payload {
+		rule := CompatibilityRule{
+			Name:         strings.TrimSpace(item.Name),
+			DriverRange:  strings.TrimSpace(item.DriverRange),
+			ToolkitRange: strings.TrimSpace(item.ToolkitRange),
+			KernelRange:  strings.TrimSpace(item.KernelRange),
+			Effect:       CompatibilityEffect(strings.TrimSpace(item.Effect)),
+			Message:      strings.TrimSpace(item.Message),
+		}
+		if rule.Name == "" {
+			return nil, fmt.Errorf("parse inventory.compatibilityRules[%d].name: must not be empty", i)
+		}
+		if _, ok := seen[rule.Name]; ok {
+			return nil, fmt.Errorf("parse inventory.compatibilityRules[%d].name: duplicate rule %q", i, rule.Name)
+		}
+		seen[rule.Name] = struct{}{}
+
+		if rule.DriverRange == "" && rule.ToolkitRange == "" && rule.KernelRange == "" {
+			return nil, fmt.Errorf("parse inventory.compatibilityRules.%s: at least one of driverRange, toolkitRange and kernelRange is required", rule.Name)
+		}
+		for _, field := range []struct {
+			name, value string
+			parse       func(string) (version.Range, error)
+		}{
+			{"driverRange", rule.DriverRange, version.ParseRange},
+			{"toolkitRange", rule.ToolkitRange, version.ParseRange},
+			{"kernelRange", rule.KernelRange, version.ParseKernelRange},
+		} {
+			if _, err := field.parse(field.value); err != nil {
+				return nil, fmt.Errorf("parse inventory.compatibilityRules.%s.%s: %w", rule.Name, field.name, err)
+			}
+		}
+		switch rule.Effect {
+		case CompatibilityEffectWarn, CompatibilityEffectBlock:
+		default:
+			return nil, fmt.Errorf("parse inventory.compatibilityRules.%s.effect: unknown effect %q, expected Block or Warn", rule.Name, rule.Effect)
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// sanitizeCompatibilityRules renders the rules back in the settings layout, leaving out unset fields.
+func sanitizeCompatibilityRules(rules []CompatibilityRule) []any {
+	out := make([]any, 0, len(rules))
+	for _, rule := range rules {
+		entry := map[string]any{"name": rule.Name, "effect": string(rule.Effect)}
+		for key, value := range map[string]string{
+			"driverRange":  rule.DriverRange,
+			"toolkitRange": rule.ToolkitRange,
+			"kernelRange":  rule.KernelRange,
+			"message":      rule.Message,
+		} {
+			if value != "" {
+				entry[key] = value
+			}
+		}
+		out = append(out, entry)
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCompatibilityRules(t *testing.T) {
+	state, err := Parse(Input{Settings: map[string]any{
+		"inventory": map[string]any{
+			"compatibilityRules": []any{
+				map[string]any{
+					"name":         " mig-break ",
+					"driverRange":  "535.104",
+					"toolkitRange": "=1.14.2",
+					"effect":       "Block",
+					"message":      "MIG reconfiguration fails",
+				},
+				map[string]any{"name": "old-kernel", "kernelRange": "<5.15.0-100", "effect": "Warn"},
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []CompatibilityRule{
+		{Name: "mig-break", DriverRange: "535.104", ToolkitRange: "=1.14.2", Effect: CompatibilityEffectBlock, Message: "MIG reconfiguration fails"},
+		{Name: "old-kernel", KernelRange: "<5.15.0-100", Effect: CompatibilityEffectWarn},
+	}
+	if !reflect.DeepEqual(state.Inventory.CompatibilityRules, want) {
+		t.Fatalf("unexpected rules: %+v", state.Inventory.CompatibilityRules)
+	}
+	sanitized := state.Sanitized["inventory"].(map[string]any)["compatibilityRules"].([]any)
+	if len(sanitized) != 2 || sanitized[1].(map[string]any)["kernelRange"] != "<5.15.0-100" {
+		t.Fatalf("unexpected sanitized rules: %#v", sanitized)
+	}
+	if _, ok := sanitized[1].(map[string]any)["driverRange"]; ok {
+		t.Fatalf("expected unset ranges to be left out: %#v", sanitized[1])
+	}
+	if values := state.Values()["inventory"].(map[string]any); !reflect.DeepEqual(values["compatibilityRules"], sanitized) {
+		t.Fatalf("expected values to carry the rules, got %#v", values["compatibilityRules"])
+	}
+}
+
+func TestParseCompatibilityRulesErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rules any
+		want  string
+	}{
+		"type":      {rules: "Block", want: "parse inventory.compatibilityRules"},
+		"no name":   {rules: []any{map[string]any{"driverRange": "535", "effect": "Warn"}}, want: "compatibilityRules[0].name"},
+		"duplicate": {rules: []any{map[string]any{"name": "a", "driverRange": "535", "effect": "Warn"}, map[string]any{"name": "a", "kernelRange": "6", "effect": "Warn"}}, want: "duplicate rule"},
+		"no range":  {rules: []any{map[string]any{"name": "a", "effect": "Warn"}}, want: "at least one of"},
+		"bad range": {rules: []any{map[string]any{"name": "a", "toolkitRange": "~1.14", "effect": "Warn"}}, want: "compatibilityRules.a.toolkitRange"},
+		"effect":    {rules: []any{map[string]any{"name": "a", "driverRange": "535", "effect": "Deny"}}, want: "unknown effect"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(Input{Settings: map[string]any{"inventory": map[string]any{"compatibilityRules": tc.rules}}})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// parseConditionPolicy reads inventory.conditionPolicy. Selectors are compiled here, so a policy that reaches the
+// controllers always selects nodes the way it reads.
+func parseConditionPolicy(raw json.RawMessage) (map[string]ConditionPolicy, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var payload map[string]struct {
+		Severity                string          `json:"severity"`
+		SuppressOnNodesMatching json.RawMessage `json:"suppressOnNodesMatching"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("parse inventory.conditionPolicy: %w", err)
+	}
+
+	policies := make(map[string]ConditionPolicy, len(payload))
+	for key, item := range payload {
+		conditionType := strings.TrimSpace(key)
+		if conditionType == "" {
+			return nil, fmt.Errorf("parse inventory.conditionPolicy: condition type must not be empty")
+		}
+		if _, ok := policies[conditionType]; ok {
+			return nil, fmt.Errorf("parse inventory.conditionPolicy: duplicate condition type %q", conditionType)
+		}
+		policy := ConditionPolicy{Severity: ConditionSeverity(strings.TrimSpace(item.Severity))}
+		switch policy.Severity {
+		case "", ConditionSeverityInfo, ConditionSeverityWarning, ConditionSeverityCritical:
+		default:
+			return nil, fmt.Errorf("parse inventory.conditionPolicy.%s.severity: unknown severity %q, expected Info, Warning or Critical", conditionType, policy.Severity)
+		}
+		if len(item.SuppressOnNodesMatching) > 0 && string(item.SuppressOnNodesMatching) != "null" {
+			selector, _, err := parseSelector(item.SuppressOnNodesMatching)
+			if err != nil {
+				return nil, fmt.Errorf("parse inventory.conditionPolicy.%s.suppressOnNodesMatching: %w", conditionType, err)
+			}
+			if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+				return nil, fmt.Errorf("parse inventory.conditionPolicy.%s.suppressOnNodesMatching: %w", conditionType, err)
+			}
+			policy.SuppressOnNodesMatching = selector
+		}
+		if policy.Severity == "" && policy.SuppressOnNodesMatching == nil {
+			return nil, fmt.Errorf("parse inventory.conditionPolicy.%s: at least one of severity and suppressOnNodesMatching is required", conditionType)
+		}
+		policies[conditionType] = policy
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	return policies, nil
+}
+
+// sanitizeConditionPolicy renders the policy back in the settings layout, leaving out unset fields.
+func sanitizeConditionPolicy(policies map[string]ConditionPolicy) map[string]any {
+	out := make(map[string]any, len(policies))
+	for conditionType, policy := range policies {
+		entry := map[string]any{}
+		if policy.Severity != "" {
+			entry["severity"] = string(policy.Severity)
+		}
+		if selector := policy.SuppressOnNodesMatching; selector != nil {
+			entry["suppressOnNodesMatching"] = selectorValues(selector)
+		}
+		out[conditionType] = entry
+	}
+	return out
+}
+
+// selectorValues renders a selector in the layout parseSelector returns.
+func selectorValues(selector *metav1.LabelSelector) map[string]any {
+	out := map[string]any{}
+	if len(selector.MatchLabels) > 0 {
+		labels := make(map[string]string, len(selector.MatchLabels))
+		for key, value := range selector.MatchLabels {
+			labels[key] = value
+		}
+		out["matchLabels"] = labels
+	}
+	if len(selector.MatchExpressions) > 0 {
+		expressions := make([]map[string]any, 0, len(selector.MatchExpressions))
+		for _, requirement := range selector.MatchExpressions {
+			expressions = append(expressions, map[string]any{
+				"key":      requirement.Key,
+				"operator": string(requirement.Operator),
+				"values":   append([]string(nil), requirement.Values...),
+			})
+		}
+		out["matchExpressions"] = expressions
+	}
+	return out
+}
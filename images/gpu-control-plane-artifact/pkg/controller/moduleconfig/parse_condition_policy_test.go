@@ -0,0 +1,84 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseConditionPolicy(t *testing.T) {
+	state, err := Parse(Input{Settings: map[string]any{
+		"inventory": map[string]any{
+			"conditionPolicy": map[string]any{
+				"InventoryComplete": map[string]any{
+					"severity":                "Critical",
+					"suppressOnNodesMatching": map[string]any{"matchLabels": map[string]any{"node-role/ingest": "true"}},
+				},
+				"FieldParseWarning": map[string]any{"severity": " Info "},
+			},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]ConditionPolicy{
+		"InventoryComplete": {
+			Severity:                ConditionSeverityCritical,
+			SuppressOnNodesMatching: &metav1.LabelSelector{MatchLabels: map[string]string{"node-role/ingest": "true"}},
+		},
+		"FieldParseWarning": {Severity: ConditionSeverityInfo},
+	}
+	if !reflect.DeepEqual(state.Inventory.ConditionPolicy, want) {
+		t.Fatalf("unexpected policy: %+v", state.Inventory.ConditionPolicy)
+	}
+	sanitized := state.Sanitized["inventory"].(map[string]any)["conditionPolicy"].(map[string]any)
+	if entry := sanitized["FieldParseWarning"].(map[string]any); len(entry) != 1 || entry["severity"] != "Info" {
+		t.Fatalf("expected unset fields to be left out: %#v", entry)
+	}
+	if values := state.Values()["inventory"].(map[string]any); !reflect.DeepEqual(values["conditionPolicy"], sanitized) {
+		t.Fatalf("expected values to carry the policy, got %#v", values["conditionPolicy"])
+	}
+
+	clone := state.Clone()
+	clone.Inventory.ConditionPolicy["InventoryComplete"].SuppressOnNodesMatching.MatchLabels["node-role/ingest"] = "false"
+	if state.Inventory.ConditionPolicy["InventoryComplete"].SuppressOnNodesMatching.MatchLabels["node-role/ingest"] != "true" {
+		t.Fatalf("clone must not share selectors")
+	}
+}
+
+func TestParseConditionPolicyErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		policy any
+		want   string
+	}{
+		"type":           {policy: []any{"InventoryComplete"}, want: "parse inventory.conditionPolicy"},
+		"empty type":     {policy: map[string]any{" ": map[string]any{"severity": "Info"}}, want: "condition type must not be empty"},
+		"severity":       {policy: map[string]any{"InventoryComplete": map[string]any{"severity": "Page"}}, want: "unknown severity"},
+		"empty entry":    {policy: map[string]any{"InventoryComplete": map[string]any{}}, want: "at least one of"},
+		"empty selector": {policy: map[string]any{"InventoryComplete": map[string]any{"suppressOnNodesMatching": map[string]any{}}}, want: "conditionPolicy.InventoryComplete.suppressOnNodesMatching"},
+		"label key":      {policy: map[string]any{"InventoryComplete": map[string]any{"suppressOnNodesMatching": map[string]any{"matchLabels": map[string]any{"bad key": "x"}}}}, want: "conditionPolicy.InventoryComplete.suppressOnNodesMatching"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(Input{Settings: map[string]any{"inventory": map[string]any{"conditionPolicy": tc.policy}}})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -15,8 +15,13 @@
 package moduleconfig
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 )
 
 func TestParse(t *testing.T) {
@@ -78,7 +83,16 @@ func TestParse(t *testing.T) {
 						"mode":              "CustomCertificate",
 						"customCertificate": map[string]any{"secretName": "corp-secret"},
 					},
-					"highAvailability": true,
+					"highAvailability":       true,
+					"paused":                 true,
+					"migrateFromGPUOperator": true,
+					"adoptExisting":          true,
+					"legacyPrivileged":       true,
+					"imageDigests": map[string]any{
+						"requireDigests": true,
+						"pullSecretRef":  map[string]any{"name": "registry"},
+						"cacheTTL":       "30m",
+					},
 				},
 			},
 			check: func(t *testing.T, got State) {
@@ -109,6 +123,19 @@ func TestParse(t *testing.T) {
 				if got.HighAvailability == nil || !*got.HighAvailability {
 					t.Fatalf("expected highAvailability true")
 				}
+				if !got.Paused || got.Sanitized["paused"] != true {
+					t.Fatalf("expected paused true, got %t (sanitized %v)", got.Paused, got.Sanitized["paused"])
+				}
+				if !got.Settings.Migration.FromGPUOperator || !got.Settings.Migration.AdoptExisting || got.Sanitized["adoptExisting"] != true {
+					t.Fatalf("unexpected migration settings: %+v (sanitized %v)", got.Settings.Migration, got.Sanitized)
+				}
+				if !got.Settings.Security.LegacyPrivileged || got.Sanitized["legacyPrivileged"] != true {
+					t.Fatalf("unexpected security settings: %+v (sanitized %v)", got.Settings.Security, got.Sanitized)
+				}
+				wantDigests := ImageDigestSettings{Enabled: true, RequireDigests: true, PullSecretName: "registry", CacheTTL: 30 * time.Minute}
+				if got.Settings.ImageDigests != wantDigests || got.Sanitized["imageDigests"].(map[string]any)["cacheTTL"] != "30m" {
+					t.Fatalf("unexpected imageDigests settings: %+v (sanitized %v)", got.Settings.ImageDigests, got.Sanitized["imageDigests"])
+				}
 			},
 		},
 		{
@@ -120,6 +147,188 @@ func TestParse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "label key transition",
+			input: Input{Settings: map[string]any{
+				"managedNodes": map[string]any{"labelKey": "example.com/gpu", "previousLabelKey": " gpu.deckhouse.io/enabled ", "labelKeyTransitionWindow": "72h"},
+			}},
+			check: func(t *testing.T, got State) {
+				managed := got.Settings.ManagedNodes
+				if managed.PreviousLabelKey != DefaultNodeLabelKey || managed.TransitionWindow != 72*time.Hour {
+					t.Fatalf("unexpected transition settings: %+v", managed)
+				}
+				sanitized := got.Sanitized["managedNodes"].(map[string]any)
+				if sanitized["previousLabelKey"] != DefaultNodeLabelKey || sanitized["labelKeyTransitionWindow"] != "72h" {
+					t.Fatalf("unexpected sanitized managedNodes: %#v", sanitized)
+				}
+			},
+		},
+		{
+			name: "device name template",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"deviceNameTemplate": " {node}-gpu-{uuid8} "},
+			}},
+			check: func(t *testing.T, got State) {
+				if got.Inventory.DeviceNameTemplate != "{node}-gpu-{uuid8}" {
+					t.Fatalf("unexpected device name template: %q", got.Inventory.DeviceNameTemplate)
+				}
+				sanitized := got.Sanitized["inventory"].(map[string]any)
+				if sanitized["deviceNameTemplate"] != "{node}-gpu-{uuid8}" {
+					t.Fatalf("unexpected sanitized inventory: %#v", sanitized)
+				}
+			},
+		},
+		{
+			name: "stale node settings",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"staleNodeThreshold": "12h", "staleDeviceRetention": "168h"},
+			}},
+			check: func(t *testing.T, got State) {
+				if got.Inventory.StaleNodeThreshold != 12*time.Hour || got.Inventory.StaleDeviceRetention != 168*time.Hour {
+					t.Fatalf("unexpected stale node settings: %+v", got.Inventory)
+				}
+				sanitized := got.Sanitized["inventory"].(map[string]any)
+				if sanitized["staleNodeThreshold"] != "12h" || sanitized["staleDeviceRetention"] != "168h" {
+					t.Fatalf("unexpected sanitized inventory: %#v", sanitized)
+				}
+			},
+		},
+		{
+			name: "requeue spread window",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"requeueSpreadWindow": "300s"},
+			}},
+			check: func(t *testing.T, got State) {
+				if got.Inventory.RequeueSpreadWindow != 5*time.Minute {
+					t.Fatalf("unexpected requeue spread window %s", got.Inventory.RequeueSpreadWindow)
+				}
+				if sanitized := got.Sanitized["inventory"].(map[string]any); sanitized["requeueSpreadWindow"] != "5m" {
+					t.Fatalf("unexpected sanitized inventory: %#v", sanitized)
+				}
+			},
+		},
+		{
+			name: "node labeling enabled",
+			input: Input{Settings: map[string]any{
+				"nodeLabeling": map[string]any{"enabled": true},
+			}},
+			check: func(t *testing.T, got State) {
+				if !got.Settings.NodeLabeling.Enabled {
+					t.Fatalf("expected node labeling to be enabled")
+				}
+				if sanitized, ok := got.Sanitized["nodeLabeling"].(map[string]any); !ok || sanitized["enabled"] != true {
+					t.Fatalf("unexpected sanitized nodeLabeling: %#v", got.Sanitized["nodeLabeling"])
+				}
+			},
+		},
+		{
+			name: "max deletions per sweep",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"maxDeletionsPerSweep": "25"},
+			}},
+			check: func(t *testing.T, got State) {
+				if got.Inventory.MaxDeletionsPerSweep != "25" || got.Inventory.MaxDeletions(1000) != 25 {
+					t.Fatalf("unexpected deletion limit: %+v", got.Inventory)
+				}
+				sanitized := got.Sanitized["inventory"].(map[string]any)
+				if sanitized["maxDeletionsPerSweep"] != "25" {
+					t.Fatalf("unexpected sanitized inventory: %#v", sanitized)
+				}
+			},
+		},
+		{
+			name: "attribute passthrough prefixes",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"attributePassthroughPrefixes": []any{" acme.com/ ", "acme.com/", "rack."}},
+			}},
+			check: func(t *testing.T, got State) {
+				if !reflect.DeepEqual(got.Inventory.AttributePassthroughPrefixes, []string{"acme.com/", "rack."}) {
+					t.Fatalf("unexpected attribute passthrough prefixes: %v", got.Inventory.AttributePassthroughPrefixes)
+				}
+				sanitized := got.Sanitized["inventory"].(map[string]any)
+				if !reflect.DeepEqual(sanitized["attributePassthroughPrefixes"], []string{"acme.com/", "rack."}) {
+					t.Fatalf("unexpected sanitized inventory: %#v", sanitized)
+				}
+				values := got.Values()["inventory"].(map[string]any)
+				if !reflect.DeepEqual(values["attributePassthroughPrefixes"], []string{"acme.com/", "rack."}) {
+					t.Fatalf("unexpected inventory values: %#v", values)
+				}
+			},
+		},
+		{
+			name:  "include display devices",
+			input: Input{Settings: map[string]any{"inventory": map[string]any{"includeDisplayDevices": true}}},
+			check: func(t *testing.T, got State) {
+				if !got.Inventory.IncludeDisplayDevices {
+					t.Fatalf("expected includeDisplayDevices to be enabled")
+				}
+				if got.Sanitized["inventory"].(map[string]any)["includeDisplayDevices"] != true {
+					t.Fatalf("unexpected sanitized inventory: %#v", got.Sanitized["inventory"])
+				}
+				if got.Values()["inventory"].(map[string]any)["includeDisplayDevices"] != true {
+					t.Fatalf("unexpected inventory values: %#v", got.Values()["inventory"])
+				}
+				if _, ok := DefaultState().Values()["inventory"].(map[string]any)["includeDisplayDevices"]; ok {
+					t.Fatalf("display devices must be excluded by default")
+				}
+			},
+		},
+		{
+			name:  "trusted node feature namespaces default",
+			input: Input{Settings: map[string]any{}},
+			check: func(t *testing.T, got State) {
+				if !reflect.DeepEqual(got.Inventory.TrustedNodeFeatureNamespaces, []string{DefaultTrustedNodeFeatureNamespace}) {
+					t.Fatalf("unexpected trusted namespaces: %v", got.Inventory.TrustedNodeFeatureNamespaces)
+				}
+				if _, ok := got.Sanitized["inventory"].(map[string]any)["trustedNodeFeatureNamespaces"]; ok {
+					t.Fatalf("default trusted namespaces must not be sanitized: %#v", got.Sanitized["inventory"])
+				}
+			},
+		},
+		{
+			name: "trusted node feature namespaces explicit empty",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"trustedNodeFeatureNamespaces": []any{}},
+			}},
+			check: func(t *testing.T, got State) {
+				if got.Inventory.TrustedNodeFeatureNamespaces == nil || len(got.Inventory.TrustedNodeFeatureNamespaces) != 0 {
+					t.Fatalf("expected an empty trusted namespace list, got %v", got.Inventory.TrustedNodeFeatureNamespaces)
+				}
+				sanitized := got.Sanitized["inventory"].(map[string]any)
+				if !reflect.DeepEqual(sanitized["trustedNodeFeatureNamespaces"], []string{}) {
+					t.Fatalf("unexpected sanitized inventory: %#v", sanitized)
+				}
+			},
+		},
+		{
+			name: "trusted node feature namespaces list",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"trustedNodeFeatureNamespaces": []any{" nfd ", "nfd", "gpu-operator"}},
+			}},
+			check: func(t *testing.T, got State) {
+				if !reflect.DeepEqual(got.Inventory.TrustedNodeFeatureNamespaces, []string{"nfd", "gpu-operator"}) {
+					t.Fatalf("unexpected trusted namespaces: %v", got.Inventory.TrustedNodeFeatureNamespaces)
+				}
+				values := got.Values()["inventory"].(map[string]any)
+				if !reflect.DeepEqual(values["trustedNodeFeatureNamespaces"], []string{"nfd", "gpu-operator"}) {
+					t.Fatalf("unexpected inventory values: %#v", values)
+				}
+			},
+		},
+		{
+			name: "stale node threshold disabled",
+			input: Input{Settings: map[string]any{
+				"inventory": map[string]any{"staleNodeThreshold": "0s"},
+			}},
+			check: func(t *testing.T, got State) {
+				if got.Inventory.StaleNodeThreshold != 0 {
+					t.Fatalf("expected disabled stale node threshold, got %s", got.Inventory.StaleNodeThreshold)
+				}
+				if got.Inventory.StaleDeviceRetention != DefaultStaleDeviceRetention {
+					t.Fatalf("expected default retention, got %s", got.Inventory.StaleDeviceRetention)
+				}
+			},
+		},
 		{
 			name:  "null inventory",
 			input: Input{Settings: map[string]any{"inventory": nil}},
@@ -127,6 +336,18 @@ func TestParse(t *testing.T) {
 				if got.Inventory.ResyncPeriod != DefaultInventoryResyncPeriod {
 					t.Fatalf("expected default inventory period")
 				}
+				if got.Inventory.DeviceNameTemplate != DefaultDeviceNameTemplate {
+					t.Fatalf("expected default device name template, got %q", got.Inventory.DeviceNameTemplate)
+				}
+				if _, ok := got.Sanitized["inventory"].(map[string]any)["deviceNameTemplate"]; ok {
+					t.Fatalf("expected default template to stay out of sanitized settings")
+				}
+				if got.Inventory.StaleNodeThreshold != DefaultStaleNodeThreshold {
+					t.Fatalf("expected default stale node threshold, got %s", got.Inventory.StaleNodeThreshold)
+				}
+				if _, ok := got.Sanitized["inventory"].(map[string]any)["staleNodeThreshold"]; ok {
+					t.Fatalf("expected default stale node threshold to stay out of sanitized settings")
+				}
 			},
 		},
 	}
@@ -150,15 +371,35 @@ func TestParseErrors(t *testing.T) {
 	}{
 		{"encode settings", Input{Settings: map[string]any{"invalid": marshalError{}}}, "encode settings.invalid"},
 		{"managed nodes", Input{Settings: map[string]any{"managedNodes": map[string]any{"enabledByDefault": "oops"}}}, "decode managedNodes"},
+		{"transition window error", Input{Settings: map[string]any{"managedNodes": map[string]any{"labelKeyTransitionWindow": "1w"}}}, "parse managedNodes.labelKeyTransitionWindow"},
 		{"unknown approval mode", Input{Settings: map[string]any{"deviceApproval": map[string]any{"mode": "unsupported"}}}, "unknown deviceApproval.mode"},
 		{"selector error", Input{Settings: map[string]any{"deviceApproval": map[string]any{"mode": "Selector", "selector": map[string]any{"matchLabels": map[string]any{"": "value"}}}}}, "matchLabels"},
 		{"scheduling error", Input{Settings: map[string]any{"scheduling": map[string]any{"defaultStrategy": "invalid"}}}, "unknown scheduling"},
 		{"monitoring decode", Input{Settings: map[string]any{"monitoring": "oops"}}, "decode monitoring"},
+		{"node labeling decode", Input{Settings: map[string]any{"nodeLabeling": "oops"}}, "decode nodeLabeling"},
 		{"logLevel decode", Input{Settings: map[string]any{"logLevel": 42}}, "decode logLevel"},
 		{"logLevel unknown", Input{Settings: map[string]any{"logLevel": "verbose"}}, "unknown logLevel"},
 		{"inventory decode", Input{Settings: map[string]any{"inventory": "oops"}}, "decode inventory settings"},
 		{"inventory error", Input{Settings: map[string]any{"inventory": map[string]any{"resyncPeriod": "bad"}}}, "parse inventory"},
 		{"inventory duration overflow", Input{Settings: map[string]any{"inventory": map[string]any{"resyncPeriod": "9223372036854775808h"}}}, "parse inventory"},
+		{"device name unknown variable", Input{Settings: map[string]any{"inventory": map[string]any{"deviceNameTemplate": "{node}-{serial}"}}}, "unknown variable {serial}"},
+		{"device name unterminated variable", Input{Settings: map[string]any{"inventory": map[string]any{"deviceNameTemplate": "{node}-{index"}}}, "unterminated variable"},
+		{"device name not unique", Input{Settings: map[string]any{"inventory": map[string]any{"deviceNameTemplate": "{node}-{vendor}"}}}, "keep names unique"},
+		{"device name without node", Input{Settings: map[string]any{"inventory": map[string]any{"deviceNameTemplate": "gpu-{index}"}}}, "keep names unique"},
+		{"device name invalid characters", Input{Settings: map[string]any{"inventory": map[string]any{"deviceNameTemplate": "{node}_GPU_{index}"}}}, "DNS-1123"},
+		{"stale node threshold pattern", Input{Settings: map[string]any{"inventory": map[string]any{"staleNodeThreshold": "1d"}}}, "parse inventory.staleNodeThreshold"},
+		{"stale device retention pattern", Input{Settings: map[string]any{"inventory": map[string]any{"staleDeviceRetention": "-1h"}}}, "parse inventory.staleDeviceRetention"},
+		{"requeue spread window pattern", Input{Settings: map[string]any{"inventory": map[string]any{"requeueSpreadWindow": "2 minutes"}}}, "parse inventory.requeueSpreadWindow"},
+		{"attribute passthrough type", Input{Settings: map[string]any{"inventory": map[string]any{"attributePassthroughPrefixes": "acme."}}}, "parse inventory.attributePassthroughPrefixes"},
+		{"attribute passthrough empty prefix", Input{Settings: map[string]any{"inventory": map[string]any{"attributePassthroughPrefixes": []any{" "}}}}, "empty prefix"},
+		{"trusted namespaces type", Input{Settings: map[string]any{"inventory": map[string]any{"trustedNodeFeatureNamespaces": "nfd"}}}, "parse inventory.trustedNodeFeatureNamespaces"},
+		{"trusted namespaces empty entry", Input{Settings: map[string]any{"inventory": map[string]any{"trustedNodeFeatureNamespaces": []any{" "}}}}, "empty namespace"},
+		{"max deletions pattern", Input{Settings: map[string]any{"inventory": map[string]any{"maxDeletionsPerSweep": "ten"}}}, "parse inventory.maxDeletionsPerSweep"},
+		{"device name too long", Input{Settings: map[string]any{"inventory": map[string]any{"deviceNameTemplate": "{node}-" + strings.Repeat("x", 200) + "-{index}"}}}, "DNS-1123"},
+		{"image digests decode", Input{Settings: map[string]any{"imageDigests": "oops"}}, "decode imageDigests"},
+		{"image digests cache TTL pattern", Input{Settings: map[string]any{"imageDigests": map[string]any{"cacheTTL": "1d"}}}, "parse imageDigests.cacheTTL"},
+		{"image digests zero cache TTL", Input{Settings: map[string]any{"imageDigests": map[string]any{"cacheTTL": "0s"}}}, "must be positive"},
+		{"image digests secret namespace without name", Input{Settings: map[string]any{"imageDigests": map[string]any{"pullSecretRef": map[string]any{"namespace": "ns"}}}}, "parse imageDigests.pullSecretRef"},
 		{"https decode", Input{Settings: map[string]any{"https": "oops"}}, "decode https settings"},
 		{"https unknown mode", Input{Settings: map[string]any{"https": map[string]any{"mode": "unsupported"}}}, "unknown https.mode"},
 		{"https custom certificate missing secret", Input{Settings: map[string]any{"https": map[string]any{"mode": "CustomCertificate"}}}, "secretName must be set"},
@@ -167,9 +408,35 @@ func TestParseErrors(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			_, err := Parse(tc.input)
-			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			if !errors.Is(err, commonerrors.ErrConfigInvalid) {
+				t.Fatalf("expected ErrConfigInvalid, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
 				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
 			}
 		})
 	}
 }
+
+func TestInventoryMaxDeletions(t *testing.T) {
+	cases := []struct {
+		value string
+		known int
+		want  int
+	}{
+		{"", 200, 20},
+		{"10%", 200, 20},
+		{"10%", 5, 1},
+		{"10%", 0, 1},
+		{"15%", 10, 2},
+		{"7", 1000, 7},
+		{"0", 1000, 0},
+		{"0%", 1000, 0},
+	}
+	for _, tc := range cases {
+		got := InventorySettings{MaxDeletionsPerSweep: tc.value}.MaxDeletions(tc.known)
+		if got != tc.want {
+			t.Fatalf("MaxDeletions(%q, %d) = %d, want %d", tc.value, tc.known, got, tc.want)
+		}
+	}
+}
@@ -29,12 +29,14 @@ func parseApproval(raw json.RawMessage) (DeviceApprovalSettings, map[string]any,
 		return settings, nil, nil
 	}
 	var payload struct {
-		Mode     string          `json:"mode"`
-		Selector json.RawMessage `json:"selector"`
+		Mode                          string          `json:"mode"`
+		Selector                      json.RawMessage `json:"selector"`
+		RequireValidationBeforeAttach bool            `json:"requireValidationBeforeAttach"`
 	}
 	if err := json.Unmarshal(raw, &payload); err != nil {
 		return settings, nil, fmt.Errorf("decode deviceApproval: %w", err)
 	}
+	settings.RequireValidation = payload.RequireValidationBeforeAttach
 	if mode := normalizeApprovalMode(payload.Mode); mode != "" {
 		settings.Mode = mode
 	} else if strings.TrimSpace(payload.Mode) != "" {
@@ -47,7 +49,7 @@ func parseApproval(raw json.RawMessage) (DeviceApprovalSettings, map[string]any,
 		}
 		sel, mapped, err := parseSelector(payload.Selector)
 		if err != nil {
-			return settings, nil, err
+			return settings, nil, fmt.Errorf("parse deviceApproval.selector: %w", err)
 		}
 		settings.Selector = sel
 		selector = mapped
@@ -91,7 +93,7 @@ func parseSelector(raw json.RawMessage) (*metav1.LabelSelector, map[string]any,
 			k := strings.TrimSpace(key)
 			v := strings.TrimSpace(value)
 			if k == "" || v == "" {
-				return nil, nil, errors.New("deviceApproval.selector.matchLabels keys and values must be non-empty")
+				return nil, nil, errors.New("selector.matchLabels keys and values must be non-empty")
 			}
 			labels[k] = v
 			selector.MatchLabels[k] = v
@@ -108,7 +110,7 @@ func parseSelector(raw json.RawMessage) (*metav1.LabelSelector, map[string]any,
 			}
 			key := strings.TrimSpace(item.Key)
 			if key == "" {
-				return nil, nil, errors.New("deviceApproval.selector.matchExpressions[].key must be set")
+				return nil, nil, errors.New("selector.matchExpressions[].key must be set")
 			}
 			values := make([]string, 0, len(item.Values))
 			for _, v := range item.Values {
@@ -137,7 +139,7 @@ func parseSelector(raw json.RawMessage) (*metav1.LabelSelector, map[string]any,
 		mapped["matchExpressions"] = exprMap
 	}
 	if selector.MatchLabels == nil && len(selector.MatchExpressions) == 0 {
-		return nil, nil, errors.New("deviceApproval.selector must define matchLabels or matchExpressions")
+		return nil, nil, errors.New("selector must define matchLabels or matchExpressions")
 	}
 	return selector, mapped, nil
 }
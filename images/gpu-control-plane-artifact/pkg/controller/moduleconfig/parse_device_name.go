@@ -0,0 +1,82 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Variables available to inventory.deviceNameTemplate.
+const (
+	DeviceNameVarNode   = "{node}"
+	DeviceNameVarIndex  = "{index}"
+	DeviceNameVarVendor = "{vendor}"
+	DeviceNameVarDevice = "{device}"
+	DeviceNameVarUUID8  = "{uuid8}"
+)
+
+var deviceNameVars = []string{DeviceNameVarNode, DeviceNameVarIndex, DeviceNameVarVendor, DeviceNameVarDevice, DeviceNameVarUUID8}
+
+// deviceNameSample renders templates at config load with the longest node name a label allows,
+// so a template that passes here yields valid names for every node.
+var deviceNameSample = strings.NewReplacer(
+	DeviceNameVarNode, strings.Repeat("n", validation.DNS1123LabelMaxLength),
+	DeviceNameVarIndex, "0",
+	DeviceNameVarVendor, "10de",
+	DeviceNameVarDevice, "2230",
+	DeviceNameVarUUID8, "0123abcd",
+)
+
+func validateDeviceNameTemplate(template string) error {
+	rest := template
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return fmt.Errorf("unterminated variable in %q", template)
+		}
+		name := rest[open : open+end+1]
+		if !isDeviceNameVar(name) {
+			return fmt.Errorf("unknown variable %s, allowed: %s", name, strings.Join(deviceNameVars, ", "))
+		}
+		rest = rest[open+end+1:]
+	}
+	if strings.Contains(rest, "}") {
+		return fmt.Errorf("unbalanced '}' in %q", template)
+	}
+	if !strings.Contains(template, DeviceNameVarNode) ||
+		(!strings.Contains(template, DeviceNameVarIndex) && !strings.Contains(template, DeviceNameVarUUID8)) {
+		return fmt.Errorf("template must reference %s and one of %s or %s to keep names unique", DeviceNameVarNode, DeviceNameVarIndex, DeviceNameVarUUID8)
+	}
+	if errs := validation.IsDNS1123Subdomain(deviceNameSample.Replace(template)); len(errs) > 0 {
+		return fmt.Errorf("rendered name is not a valid DNS-1123 subdomain: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func isDeviceNameVar(name string) bool {
+	for _, v := range deviceNameVars {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
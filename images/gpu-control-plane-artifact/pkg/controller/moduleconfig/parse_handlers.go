@@ -0,0 +1,62 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func parseHandlers(raw json.RawMessage) (map[string]HandlerSettings, map[string]any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, nil
+	}
+	var payload map[string]struct {
+		Enabled  *bool           `json:"enabled"`
+		Settings json.RawMessage `json:"settings"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, nil, fmt.Errorf("decode handlers settings: %w", err)
+	}
+	if len(payload) == 0 {
+		return nil, nil, nil
+	}
+
+	handlers := make(map[string]HandlerSettings, len(payload))
+	sanitized := make(map[string]any, len(payload))
+	for name, item := range payload {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" {
+			return nil, nil, fmt.Errorf("parse handlers: handler name must not be empty")
+		}
+		settings := HandlerSettings{Enabled: true}
+		if item.Enabled != nil {
+			settings.Enabled = *item.Enabled
+		}
+		entry := map[string]any{"enabled": settings.Enabled}
+		if len(item.Settings) > 0 && string(item.Settings) != "null" {
+			var decoded any
+			if err := json.Unmarshal(item.Settings, &decoded); err != nil {
+				return nil, nil, fmt.Errorf("decode handlers.%s.settings: %w", trimmed, err)
+			}
+			settings.Settings = append(json.RawMessage(nil), item.Settings...)
+			entry["settings"] = decoded
+		}
+		handlers[trimmed] = settings
+		sanitized[trimmed] = entry
+	}
+	return handlers, sanitized, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import "testing"
+
+func TestParseHandlers(t *testing.T) {
+	state, err := Parse(Input{Settings: map[string]any{
+		"handlers": map[string]any{
+			"device-state": map[string]any{"enabled": false},
+			"telemetry":    map[string]any{"settings": map[string]any{"interval": "30s"}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if state.HandlerEnabled("device-state") {
+		t.Fatalf("expected device-state to be disabled")
+	}
+	if !state.HandlerEnabled("telemetry") || !state.HandlerEnabled("not-configured") {
+		t.Fatalf("expected handlers to be enabled unless disabled explicitly")
+	}
+	if got := string(state.Handlers["telemetry"].Settings); got != `{"interval":"30s"}` {
+		t.Fatalf("unexpected settings blob %q", got)
+	}
+	sanitized := state.Sanitized["handlers"].(map[string]any)
+	if sanitized["device-state"].(map[string]any)["enabled"] != false {
+		t.Fatalf("unexpected sanitized handlers: %+v", sanitized)
+	}
+
+	clone := state.Clone()
+	clone.Handlers["telemetry"].Settings[0] = '['
+	if string(state.Handlers["telemetry"].Settings) != `{"interval":"30s"}` {
+		t.Fatalf("expected clone to copy settings blobs")
+	}
+}
+
+func TestParseHandlersDefaultsAndErrors(t *testing.T) {
+	state, err := Parse(Input{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if state.Handlers != nil {
+		t.Fatalf("expected no handler settings by default, got %+v", state.Handlers)
+	}
+	if _, ok := state.Sanitized["handlers"]; ok {
+		t.Fatalf("expected handlers to be omitted from sanitized settings")
+	}
+
+	if _, err := Parse(Input{Settings: map[string]any{"handlers": []any{"x"}}}); err == nil {
+		t.Fatalf("expected decode error for non-object handlers")
+	}
+	if _, err := Parse(Input{Settings: map[string]any{"handlers": map[string]any{" ": map[string]any{}}}}); err == nil {
+		t.Fatalf("expected error for empty handler name")
+	}
+}
@@ -114,6 +114,7 @@ func TestParseApproval(t *testing.T) {
 		raw          json.RawMessage
 		expectMode   DeviceApprovalMode
 		wantSelector bool
+		wantRequire  bool
 		wantErr      string
 	}{
 		{name: "defaults", expectMode: DeviceApprovalModeManual},
@@ -122,6 +123,7 @@ func TestParseApproval(t *testing.T) {
 		{name: "trimmed mode", raw: json.RawMessage(`{"mode":"  Selector  "}`), expectMode: DeviceApprovalModeSelector, wantSelector: false},
 		{name: "selector missing body", raw: json.RawMessage(`{"mode":"Selector"}`), expectMode: DeviceApprovalModeSelector, wantSelector: false},
 		{name: "selector with body", raw: json.RawMessage(`{"mode":"Selector","selector":{"matchLabels":{"gpu":"true"}}}`), expectMode: DeviceApprovalModeSelector, wantSelector: true},
+		{name: "require validation", raw: json.RawMessage(`{"mode":"Automatic","requireValidationBeforeAttach":true}`), expectMode: DeviceApprovalModeAutomatic, wantRequire: true},
 		{name: "invalid", raw: json.RawMessage(`{"mode":"unknown"}`), wantErr: "unknown deviceApproval.mode"},
 		{name: "decode error", raw: json.RawMessage(`"oops"`), wantErr: "decode deviceApproval"},
 	}
@@ -147,6 +149,9 @@ func TestParseApproval(t *testing.T) {
 			if !tc.wantSelector && (got.Selector != nil || mapped != nil) {
 				t.Fatalf("expected selector to be nil")
 			}
+			if got.RequireValidation != tc.wantRequire {
+				t.Fatalf("unexpected requireValidation: %v", got.RequireValidation)
+			}
 		})
 	}
 }
@@ -0,0 +1,87 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseImageDigests reads the imageDigests setting. requireDigests implies enabled: strict pinning without
+// resolution would block every pool.
+func parseImageDigests(raw json.RawMessage) (ImageDigestSettings, error) {
+	settings := ImageDigestSettings{CacheTTL: DefaultImageDigestCacheTTL}
+	if len(raw) == 0 || string(raw) == "null" {
+		return settings, nil
+	}
+	var payload struct {
+		Enabled        *bool `json:"enabled"`
+		RequireDigests *bool `json:"requireDigests"`
+		PullSecretRef  struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"pullSecretRef"`
+		CacheTTL string `json:"cacheTTL"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return settings, fmt.Errorf("decode imageDigests: %w", err)
+	}
+	if payload.Enabled != nil {
+		settings.Enabled = *payload.Enabled
+	}
+	if payload.RequireDigests != nil && *payload.RequireDigests {
+		settings.Enabled, settings.RequireDigests = true, true
+	}
+	settings.PullSecretName = strings.TrimSpace(payload.PullSecretRef.Name)
+	settings.PullSecretNamespace = strings.TrimSpace(payload.PullSecretRef.Namespace)
+	if settings.PullSecretName == "" && settings.PullSecretNamespace != "" {
+		return settings, fmt.Errorf("parse imageDigests.pullSecretRef: namespace %q is set without a name", settings.PullSecretNamespace)
+	}
+	if trimmed := strings.TrimSpace(payload.CacheTTL); trimmed != "" {
+		if !inventoryResyncPattern.MatchString(trimmed) {
+			return settings, fmt.Errorf("parse imageDigests.cacheTTL: value %q does not match ^\\d+(s|m|h)$", trimmed)
+		}
+		ttl, err := time.ParseDuration(trimmed)
+		if err != nil {
+			return settings, fmt.Errorf("parse imageDigests.cacheTTL: %w", err)
+		}
+		if ttl <= 0 {
+			return settings, fmt.Errorf("parse imageDigests.cacheTTL: value %q must be positive", trimmed)
+		}
+		settings.CacheTTL = ttl
+	}
+	return settings, nil
+}
+
+// values renders the settings in the schema form.
+func (s ImageDigestSettings) values() map[string]any {
+	result := map[string]any{"enabled": s.Enabled}
+	if s.RequireDigests {
+		result["requireDigests"] = true
+	}
+	if s.PullSecretName != "" {
+		ref := map[string]any{"name": s.PullSecretName}
+		if s.PullSecretNamespace != "" {
+			ref["namespace"] = s.PullSecretNamespace
+		}
+		result["pullSecretRef"] = ref
+	}
+	if s.CacheTTL != DefaultImageDigestCacheTTL {
+		result["cacheTTL"] = formatWindow(s.CacheTTL)
+	}
+	return result
+}
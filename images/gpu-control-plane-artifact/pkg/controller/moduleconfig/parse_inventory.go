@@ -18,19 +18,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
-var inventoryResyncPattern = regexp.MustCompile(`^\d+(s|m|h)$`)
+var (
+	inventoryResyncPattern = regexp.MustCompile(`^\d+(s|m|h)$`)
+	maxDeletionsPattern    = regexp.MustCompile(`^\d+%?$`)
+)
 
 func parseInventory(raw json.RawMessage) (InventorySettings, error) {
-	settings := InventorySettings{ResyncPeriod: DefaultInventoryResyncPeriod}
+	settings := InventorySettings{
+		ResyncPeriod:                 DefaultInventoryResyncPeriod,
+		DeviceNameTemplate:           DefaultDeviceNameTemplate,
+		StaleNodeThreshold:           DefaultStaleNodeThreshold,
+		StaleDeviceRetention:         DefaultStaleDeviceRetention,
+		MaxDeletionsPerSweep:         DefaultMaxDeletionsPerSweep,
+		RequeueSpreadWindow:          DefaultRequeueSpreadWindow,
+		TrustedNodeFeatureNamespaces: []string{DefaultTrustedNodeFeatureNamespace},
+	}
 	if len(raw) == 0 || string(raw) == "null" {
 		return settings, nil
 	}
 	var payload struct {
-		ResyncPeriod string `json:"resyncPeriod"`
+		ResyncPeriod         string `json:"resyncPeriod"`
+		DeviceNameTemplate   string `json:"deviceNameTemplate"`
+		StaleNodeThreshold   string `json:"staleNodeThreshold"`
+		StaleDeviceRetention string `json:"staleDeviceRetention"`
+		MaxDeletionsPerSweep string `json:"maxDeletionsPerSweep"`
+		RequeueSpreadWindow  string `json:"requeueSpreadWindow"`
+		// AttributePassthroughPrefixes is decoded separately so a wrong type names the field.
+		AttributePassthroughPrefixes json.RawMessage `json:"attributePassthroughPrefixes"`
+		TrustedNodeFeatureNamespaces json.RawMessage `json:"trustedNodeFeatureNamespaces"`
+		IncludeDisplayDevices        bool            `json:"includeDisplayDevices"`
+		CompatibilityRules           json.RawMessage `json:"compatibilityRules"`
+		ConditionPolicy              json.RawMessage `json:"conditionPolicy"`
 	}
 	if err := json.Unmarshal(raw, &payload); err != nil {
 		return settings, fmt.Errorf("decode inventory settings: %w", err)
@@ -44,5 +67,149 @@ func parseInventory(raw json.RawMessage) (InventorySettings, error) {
 		}
 		settings.ResyncPeriod = trimmed
 	}
+	if trimmed := strings.TrimSpace(payload.DeviceNameTemplate); trimmed != "" {
+		if err := validateDeviceNameTemplate(trimmed); err != nil {
+			return settings, fmt.Errorf("parse inventory.deviceNameTemplate: %w", err)
+		}
+		settings.DeviceNameTemplate = trimmed
+	}
+	if trimmed := strings.TrimSpace(payload.StaleNodeThreshold); trimmed != "" {
+		threshold, err := parseInventoryDuration("staleNodeThreshold", trimmed)
+		if err != nil {
+			return settings, err
+		}
+		settings.StaleNodeThreshold = threshold
+	}
+	if trimmed := strings.TrimSpace(payload.StaleDeviceRetention); trimmed != "" {
+		retention, err := parseInventoryDuration("staleDeviceRetention", trimmed)
+		if err != nil {
+			return settings, err
+		}
+		settings.StaleDeviceRetention = retention
+	}
+	if trimmed := strings.TrimSpace(payload.MaxDeletionsPerSweep); trimmed != "" {
+		if !maxDeletionsPattern.MatchString(trimmed) {
+			return settings, fmt.Errorf("parse inventory.maxDeletionsPerSweep: value %q does not match ^\\d+%%?$", trimmed)
+		}
+		settings.MaxDeletionsPerSweep = trimmed
+	}
+	if trimmed := strings.TrimSpace(payload.RequeueSpreadWindow); trimmed != "" {
+		window, err := parseInventoryDuration("requeueSpreadWindow", trimmed)
+		if err != nil {
+			return settings, err
+		}
+		settings.RequeueSpreadWindow = window
+	}
+	prefixes, err := parseAttributePassthroughPrefixes(payload.AttributePassthroughPrefixes)
+	if err != nil {
+		return settings, err
+	}
+	settings.AttributePassthroughPrefixes = prefixes
+	if namespaces, set, err := parseTrustedNodeFeatureNamespaces(payload.TrustedNodeFeatureNamespaces); err != nil {
+		return settings, err
+	} else if set {
+		settings.TrustedNodeFeatureNamespaces = namespaces
+	}
+	settings.IncludeDisplayDevices = payload.IncludeDisplayDevices
+	rules, err := parseCompatibilityRules(payload.CompatibilityRules)
+	if err != nil {
+		return settings, err
+	}
+	settings.CompatibilityRules = rules
+	policy, err := parseConditionPolicy(payload.ConditionPolicy)
+	if err != nil {
+		return settings, err
+	}
+	settings.ConditionPolicy = policy
 	return settings, nil
 }
+
+// parseTrustedNodeFeatureNamespaces trims and deduplicates the namespaces, keeping their order. set is false when
+// the field is absent, so the default applies; an explicit empty list is kept as is.
+func parseTrustedNodeFeatureNamespaces(raw json.RawMessage) ([]string, bool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, false, nil
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, false, fmt.Errorf("parse inventory.trustedNodeFeatureNamespaces: %w", err)
+	}
+	namespaces := []string{}
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		namespace := strings.TrimSpace(value)
+		if namespace == "" {
+			return nil, false, fmt.Errorf("parse inventory.trustedNodeFeatureNamespaces: empty namespace")
+		}
+		if _, ok := seen[namespace]; ok {
+			continue
+		}
+		seen[namespace] = struct{}{}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, true, nil
+}
+
+// trustsDefaultNodeFeatureNamespaces reports whether the list is the default one, which is left out of the sanitized settings.
+func trustsDefaultNodeFeatureNamespaces(namespaces []string) bool {
+	return len(namespaces) == 1 && namespaces[0] == DefaultTrustedNodeFeatureNamespace
+}
+
+// parseAttributePassthroughPrefixes trims and deduplicates the prefixes, keeping their order.
+func parseAttributePassthroughPrefixes(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("parse inventory.attributePassthroughPrefixes: %w", err)
+	}
+	var prefixes []string
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		prefix := strings.TrimSpace(value)
+		if prefix == "" {
+			return nil, fmt.Errorf("parse inventory.attributePassthroughPrefixes: empty prefix would mirror every attribute")
+		}
+		if _, ok := seen[prefix]; ok {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// MaxDeletions resolves MaxDeletionsPerSweep against the number of known devices. A percentage
+// never rounds down to zero, so a non-empty fleet can always lose at least one device; zero
+// means the limit is disabled.
+func (s InventorySettings) MaxDeletions(known int) int {
+	value := strings.TrimSpace(s.MaxDeletionsPerSweep)
+	if value == "" {
+		value = DefaultMaxDeletionsPerSweep
+	}
+	percent := strings.HasSuffix(value, "%")
+	n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	if !percent {
+		return n
+	}
+	limit := (known*n + 99) / 100
+	if limit < 1 {
+		limit = 1
+	}
+	return limit
+}
+
+func parseInventoryDuration(field, value string) (time.Duration, error) {
+	if !inventoryResyncPattern.MatchString(value) {
+		return 0, fmt.Errorf("parse inventory.%s: value %q does not match ^\\d+(s|m|h)$", field, value)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse inventory.%s: %w", field, err)
+	}
+	return duration, nil
+}
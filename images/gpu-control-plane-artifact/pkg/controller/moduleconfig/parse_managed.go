@@ -18,16 +18,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 func parseManaged(raw json.RawMessage) (ManagedNodesSettings, error) {
-	settings := ManagedNodesSettings{LabelKey: DefaultNodeLabelKey, EnabledByDefault: true}
+	settings := ManagedNodesSettings{LabelKey: DefaultNodeLabelKey, EnabledByDefault: true, TransitionWindow: DefaultLabelKeyTransitionWindow}
 	if len(raw) == 0 || string(raw) == "null" {
 		return settings, nil
 	}
 	var payload struct {
-		LabelKey         string `json:"labelKey"`
-		EnabledByDefault *bool  `json:"enabledByDefault"`
+		LabelKey                 string `json:"labelKey"`
+		EnabledByDefault         *bool  `json:"enabledByDefault"`
+		PreviousLabelKey         string `json:"previousLabelKey"`
+		LabelKeyTransitionWindow string `json:"labelKeyTransitionWindow"`
 	}
 	if err := json.Unmarshal(raw, &payload); err != nil {
 		return settings, fmt.Errorf("decode managedNodes: %w", err)
@@ -38,5 +41,30 @@ func parseManaged(raw json.RawMessage) (ManagedNodesSettings, error) {
 	if payload.EnabledByDefault != nil {
 		settings.EnabledByDefault = *payload.EnabledByDefault
 	}
+	if v := strings.TrimSpace(payload.PreviousLabelKey); v != "" && v != settings.LabelKey {
+		settings.PreviousLabelKey = v
+	}
+	if trimmed := strings.TrimSpace(payload.LabelKeyTransitionWindow); trimmed != "" {
+		if !inventoryResyncPattern.MatchString(trimmed) {
+			return settings, fmt.Errorf("parse managedNodes.labelKeyTransitionWindow: value %q does not match ^\\d+(s|m|h)$", trimmed)
+		}
+		window, err := time.ParseDuration(trimmed)
+		if err != nil {
+			return settings, fmt.Errorf("parse managedNodes.labelKeyTransitionWindow: %w", err)
+		}
+		settings.TransitionWindow = window
+	}
 	return settings, nil
 }
+
+// formatWindow renders a window in the ^\d+(s|m|h)$ form accepted by the schema.
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
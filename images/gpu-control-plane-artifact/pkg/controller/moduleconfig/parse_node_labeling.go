@@ -0,0 +1,37 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func parseNodeLabeling(raw json.RawMessage) (NodeLabelingSettings, error) {
+	var settings NodeLabelingSettings
+	if len(raw) == 0 || string(raw) == "null" {
+		return settings, nil
+	}
+	var payload struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return settings, fmt.Errorf("decode nodeLabeling settings: %w", err)
+	}
+	if payload.Enabled != nil {
+		settings.Enabled = *payload.Enabled
+	}
+	return settings, nil
+}
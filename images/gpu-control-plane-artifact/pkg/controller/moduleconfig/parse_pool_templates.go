@@ -0,0 +1,181 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// PoolTemplateVarProduct expands to the DNS-safe product of the matched devices in poolTemplates[].poolName.
+const PoolTemplateVarProduct = "{product}"
+
+func parsePoolTemplates(raw json.RawMessage) ([]PoolTemplate, []any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, nil
+	}
+	var payload []struct {
+		Name  string `json:"name"`
+		Match struct {
+			Product      string `json:"product"`
+			MinMemoryMiB int32  `json:"minMemoryMiB"`
+		} `json:"match"`
+		PoolName           string          `json:"poolName"`
+		PoolSpec           json.RawMessage `json:"poolSpec"`
+		UnmatchedRetention string          `json:"unmatchedRetention"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, nil, fmt.Errorf("decode poolTemplates settings: %w", err)
+	}
+	if len(payload) == 0 {
+		return nil, nil, nil
+	}
+
+	templates := make([]PoolTemplate, 0, len(payload))
+	sanitized := make([]any, 0, len(payload))
+	seen := make(map[string]struct{}, len(payload))
+	for i, item := range payload {
+		name := strings.TrimSpace(item.Name)
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("parse poolTemplates[%d].name: %q is not a valid DNS-1123 label: %s", i, name, strings.Join(errs, "; "))
+		}
+		if _, ok := seen[name]; ok {
+			return nil, nil, fmt.Errorf("parse poolTemplates[%d].name: duplicate template %q", i, name)
+		}
+		seen[name] = struct{}{}
+
+		template := PoolTemplate{Name: name, ProductPattern: strings.TrimSpace(item.Match.Product), MinMemoryMiB: item.Match.MinMemoryMiB}
+		if _, err := regexp.Compile(template.ProductPattern); err != nil {
+			return nil, nil, fmt.Errorf("parse poolTemplates.%s.match.product: %w", name, err)
+		}
+		if template.MinMemoryMiB < 0 {
+			return nil, nil, fmt.Errorf("parse poolTemplates.%s.match.minMemoryMiB: must not be negative", name)
+		}
+
+		template.PoolName = strings.TrimSpace(item.PoolName)
+		if template.PoolName == "" {
+			template.PoolName = name
+		}
+		if err := validatePoolNameTemplate(template.PoolName); err != nil {
+			return nil, nil, fmt.Errorf("parse poolTemplates.%s.poolName: %w", name, err)
+		}
+
+		if len(item.PoolSpec) == 0 || string(item.PoolSpec) == "null" {
+			return nil, nil, fmt.Errorf("parse poolTemplates.%s.poolSpec: must be set", name)
+		}
+		// Unknown fields are rejected so a typo does not silently produce pools with defaults.
+		decoder := json.NewDecoder(bytes.NewReader(item.PoolSpec))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&template.PoolSpec); err != nil {
+			return nil, nil, fmt.Errorf("decode poolTemplates.%s.poolSpec: %w", name, err)
+		}
+		var spec map[string]any
+		if err := json.Unmarshal(item.PoolSpec, &spec); err != nil {
+			return nil, nil, fmt.Errorf("decode poolTemplates.%s.poolSpec: %w", name, err)
+		}
+
+		if trimmed := strings.TrimSpace(item.UnmatchedRetention); trimmed != "" {
+			if !inventoryResyncPattern.MatchString(trimmed) {
+				return nil, nil, fmt.Errorf("parse poolTemplates.%s.unmatchedRetention: value %q does not match ^\\d+(s|m|h)$", name, trimmed)
+			}
+			retention, err := time.ParseDuration(trimmed)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parse poolTemplates.%s.unmatchedRetention: %w", name, err)
+			}
+			template.UnmatchedRetention = retention
+		}
+
+		entry := map[string]any{"name": name, "poolName": template.PoolName, "poolSpec": spec}
+		match := map[string]any{}
+		if template.ProductPattern != "" {
+			match["product"] = template.ProductPattern
+		}
+		if template.MinMemoryMiB > 0 {
+			match["minMemoryMiB"] = template.MinMemoryMiB
+		}
+		if len(match) > 0 {
+			entry["match"] = match
+		}
+		if template.UnmatchedRetention > 0 {
+			entry["unmatchedRetention"] = formatWindow(template.UnmatchedRetention)
+		}
+		templates = append(templates, template)
+		sanitized = append(sanitized, entry)
+	}
+	return templates, sanitized, nil
+}
+
+// validatePoolNameTemplate renders the name with a one-letter product; longer products are truncated by the controller.
+func validatePoolNameTemplate(template string) error {
+	rendered := strings.ReplaceAll(template, PoolTemplateVarProduct, "p")
+	if strings.ContainsAny(rendered, "{}") {
+		return fmt.Errorf("unknown variable in %q, allowed: %s", template, PoolTemplateVarProduct)
+	}
+	if errs := validation.IsDNS1123Label(rendered); len(errs) > 0 {
+		return fmt.Errorf("rendered name is not a valid DNS-1123 label: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ProductPoolName turns a device product into a DNS-1123 label fragment, e.g. "NVIDIA A100-PCIE-40GB" -> "nvidia-a100-pcie-40gb".
+func ProductPoolName(product string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(product) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// RenderPoolName expands the template's pool name for the given product, keeping the result within a DNS-1123 label.
+func (t PoolTemplate) RenderPoolName(product string) string {
+	name := strings.ReplaceAll(t.PoolName, PoolTemplateVarProduct, ProductPoolName(product))
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123LabelMaxLength], "-")
+	}
+	return name
+}
+
+// PerProduct reports whether the template yields one pool per matched product.
+func (t PoolTemplate) PerProduct() bool {
+	return strings.Contains(t.PoolName, PoolTemplateVarProduct)
+}
+
+// Matches reports whether the device hardware satisfies the template match rules.
+func (t PoolTemplate) Matches(hw v1alpha1.GPUDeviceHardware) bool {
+	if t.MinMemoryMiB > 0 && hw.MemoryMiB < t.MinMemoryMiB {
+		return false
+	}
+	if t.ProductPattern == "" {
+		return true
+	}
+	re, err := regexp.Compile(t.ProductPattern)
+	return err == nil && re.MatchString(hw.Product)
+}
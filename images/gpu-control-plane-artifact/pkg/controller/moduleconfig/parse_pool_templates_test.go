@@ -0,0 +1,126 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moduleconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestParsePoolTemplates(t *testing.T) {
+	state, err := Parse(Input{Settings: map[string]any{
+		"poolTemplates": []any{
+			map[string]any{
+				"name":               "a100",
+				"match":              map[string]any{"product": "A100", "minMemoryMiB": 40000},
+				"poolName":           "auto-{product}",
+				"poolSpec":           map[string]any{"resource": map[string]any{"unit": "Card"}},
+				"unmatchedRetention": "24h",
+			},
+			map[string]any{"name": "any", "poolSpec": map[string]any{"resource": map[string]any{"unit": "Card", "slicesPerUnit": 2}}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(state.PoolTemplates) != 2 {
+		t.Fatalf("expected two templates, got %+v", state.PoolTemplates)
+	}
+	a100 := state.PoolTemplates[0]
+	if a100.ProductPattern != "A100" || a100.MinMemoryMiB != 40000 || a100.UnmatchedRetention != 24*time.Hour || a100.PoolSpec.Resource.Unit != "Card" {
+		t.Fatalf("unexpected template: %+v", a100)
+	}
+	if !a100.PerProduct() || a100.RenderPoolName("NVIDIA A100-PCIE-40GB") != "auto-nvidia-a100-pcie-40gb" {
+		t.Fatalf("unexpected pool name %q", a100.RenderPoolName("NVIDIA A100-PCIE-40GB"))
+	}
+	if !a100.Matches(v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100-PCIE-40GB", MemoryMiB: 40960}) ||
+		a100.Matches(v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100-PCIE-40GB", MemoryMiB: 20480}) ||
+		a100.Matches(v1alpha1.GPUDeviceHardware{Product: "NVIDIA L4", MemoryMiB: 40960}) {
+		t.Fatalf("unexpected match results")
+	}
+	if other := state.PoolTemplates[1]; other.PoolName != "any" || other.PerProduct() || !other.Matches(v1alpha1.GPUDeviceHardware{}) {
+		t.Fatalf("expected pool name to default to the template name and match everything: %+v", other)
+	}
+
+	sanitized := state.Sanitized["poolTemplates"].([]any)
+	entry := sanitized[0].(map[string]any)
+	if entry["unmatchedRetention"] != "24h" || entry["poolName"] != "auto-{product}" || entry["match"].(map[string]any)["product"] != "A100" {
+		t.Fatalf("unexpected sanitized template: %+v", entry)
+	}
+	if _, ok := sanitized[1].(map[string]any)["match"]; ok {
+		t.Fatalf("expected empty match to be omitted: %+v", sanitized[1])
+	}
+
+	clone := state.Clone()
+	clone.PoolTemplates[0].PoolSpec.Resource.Unit = "MIG"
+	if state.PoolTemplates[0].PoolSpec.Resource.Unit != "Card" {
+		t.Fatalf("expected clone to copy pool specs")
+	}
+}
+
+func TestParsePoolTemplatesErrors(t *testing.T) {
+	spec := map[string]any{"resource": map[string]any{"unit": "Card"}}
+	cases := []struct {
+		name    string
+		raw     any
+		wantErr string
+	}{
+		{name: "decode", raw: "oops", wantErr: "decode poolTemplates settings"},
+		{name: "missing name", raw: []any{map[string]any{"poolSpec": spec}}, wantErr: "poolTemplates[0].name"},
+		{name: "duplicate", raw: []any{map[string]any{"name": "a", "poolSpec": spec}, map[string]any{"name": "a", "poolSpec": spec}}, wantErr: "duplicate template"},
+		{name: "bad regex", raw: []any{map[string]any{"name": "a", "match": map[string]any{"product": "("}, "poolSpec": spec}}, wantErr: "match.product"},
+		{name: "negative memory", raw: []any{map[string]any{"name": "a", "match": map[string]any{"minMemoryMiB": -1}, "poolSpec": spec}}, wantErr: "minMemoryMiB"},
+		{name: "unknown variable", raw: []any{map[string]any{"name": "a", "poolName": "{node}", "poolSpec": spec}}, wantErr: "unknown variable"},
+		{name: "invalid pool name", raw: []any{map[string]any{"name": "a", "poolName": "Pool_{product}", "poolSpec": spec}}, wantErr: "DNS-1123"},
+		{name: "missing spec", raw: []any{map[string]any{"name": "a"}}, wantErr: "poolSpec: must be set"},
+		{name: "unknown spec field", raw: []any{map[string]any{"name": "a", "poolSpec": map[string]any{"resurce": map[string]any{}}}}, wantErr: "decode poolTemplates.a.poolSpec"},
+		{name: "bad retention", raw: []any{map[string]any{"name": "a", "poolSpec": spec, "unmatchedRetention": "1d"}}, wantErr: "unmatchedRetention"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(Input{Settings: map[string]any{"poolTemplates": tc.raw}})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	state, err := Parse(Input{Settings: map[string]any{"poolTemplates": []any{}}})
+	if err != nil || state.PoolTemplates != nil {
+		t.Fatalf("expected empty list to keep templates unset, got %+v, %v", state.PoolTemplates, err)
+	}
+	if _, ok := state.Sanitized["poolTemplates"]; ok {
+		t.Fatalf("expected poolTemplates to be omitted from sanitized settings")
+	}
+}
+
+func TestProductPoolName(t *testing.T) {
+	for product, want := range map[string]string{
+		"NVIDIA A100-PCIE-40GB": "nvidia-a100-pcie-40gb",
+		"  Tesla  T4 ":          "tesla-t4",
+		"":                      "",
+	} {
+		if got := ProductPoolName(product); got != want {
+			t.Fatalf("ProductPoolName(%q) = %q, want %q", product, got, want)
+		}
+	}
+	long := PoolTemplate{PoolName: "{product}"}.RenderPoolName(strings.Repeat("a", 70) + " b")
+	if len(long) > 63 || strings.HasSuffix(long, "-") {
+		t.Fatalf("expected truncated label, got %q", long)
+	}
+}
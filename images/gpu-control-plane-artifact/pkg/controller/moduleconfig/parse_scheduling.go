@@ -28,6 +28,9 @@ func parseScheduling(raw json.RawMessage) (SchedulingSettings, error) {
 	var payload struct {
 		DefaultStrategy string `json:"defaultStrategy"`
 		TopologyKey     string `json:"topologyKey"`
+		PoolNodeLabels  bool   `json:"poolNodeLabels"`
+		ColocationHints bool   `json:"colocationHints"`
+		SharedPoolNames bool   `json:"sharedPoolNames"`
 	}
 	if err := json.Unmarshal(raw, &payload); err != nil {
 		return settings, fmt.Errorf("decode scheduling settings: %w", err)
@@ -42,6 +45,9 @@ func parseScheduling(raw json.RawMessage) (SchedulingSettings, error) {
 		topo = DefaultSchedulingTopology
 	}
 	settings.TopologyKey = topo
+	settings.PoolNodeLabels = payload.PoolNodeLabels
+	settings.ColocationHints = payload.ColocationHints
+	settings.SharedPoolNames = payload.SharedPoolNames
 	return settings, nil
 }
 
@@ -86,6 +86,9 @@ func TestParseScheduling(t *testing.T) {
 		{name: "null raw", raw: json.RawMessage("null"), expect: SchedulingSettings{DefaultStrategy: DefaultSchedulingStrategy, TopologyKey: DefaultSchedulingTopology}},
 		{name: "spread with blank topology", raw: json.RawMessage(`{"defaultStrategy":"Spread","topologyKey":"   "}`), expect: SchedulingSettings{DefaultStrategy: "Spread", TopologyKey: DefaultSchedulingTopology}},
 		{name: "binpack trims topology", raw: json.RawMessage(`{"defaultStrategy":"BinPack","topologyKey":" zone "}`), expect: SchedulingSettings{DefaultStrategy: "BinPack", TopologyKey: "zone"}},
+		{name: "pool node labels", raw: json.RawMessage(`{"poolNodeLabels":true}`), expect: SchedulingSettings{DefaultStrategy: DefaultSchedulingStrategy, TopologyKey: DefaultSchedulingTopology, PoolNodeLabels: true}},
+		{name: "colocation hints", raw: json.RawMessage(`{"colocationHints":true}`), expect: SchedulingSettings{DefaultStrategy: DefaultSchedulingStrategy, TopologyKey: DefaultSchedulingTopology, ColocationHints: true}},
+		{name: "shared pool names", raw: json.RawMessage(`{"sharedPoolNames":true}`), expect: SchedulingSettings{DefaultStrategy: DefaultSchedulingStrategy, TopologyKey: DefaultSchedulingTopology, SharedPoolNames: true}},
 		{name: "unknown strategy", raw: json.RawMessage(`{"defaultStrategy":"invalid"}`), wantErr: "unknown scheduling"},
 		{name: "decode error", raw: json.RawMessage(`"oops"`), wantErr: "decode scheduling"},
 	}
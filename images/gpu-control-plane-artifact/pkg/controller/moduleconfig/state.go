@@ -14,7 +14,14 @@
 
 package moduleconfig
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
 
 type Input struct {
 	Enabled  *bool
@@ -34,7 +41,36 @@ type State struct {
 	Inventory        InventorySettings
 	HighAvailability *bool
 	HTTPS            HTTPSSettings
+	Handlers         map[string]HandlerSettings
+	PoolTemplates    []PoolTemplate
 	Sanitized        map[string]any
+	// Paused freezes every reconciler until it is cleared; metrics, probes and webhooks keep running.
+	Paused bool
+}
+
+// HandlerSettings toggles a single reconcile handler and carries its opaque runtime settings.
+type HandlerSettings struct {
+	Enabled  bool
+	Settings json.RawMessage
+}
+
+// HandlerEnabled reports whether the named handler should run; handlers are enabled unless explicitly disabled.
+func (s State) HandlerEnabled(name string) bool {
+	settings, ok := s.Handlers[name]
+	return !ok || settings.Enabled
+}
+
+// PoolTemplate describes ClusterGPUPools the controller keeps for the discovered devices it matches.
+type PoolTemplate struct {
+	Name string
+	// ProductPattern is a regular expression matched against the device product; empty matches every product.
+	ProductPattern string
+	MinMemoryMiB   int32
+	// PoolName names the pools; PoolTemplateVarProduct yields one pool per matched product.
+	PoolName string
+	PoolSpec v1alpha1.GPUPoolSpec
+	// UnmatchedRetention is how long a pool outlives its last matching device; zero keeps it.
+	UnmatchedRetention time.Duration
 }
 
 type Settings struct {
@@ -43,18 +79,67 @@ type Settings struct {
 	Scheduling     SchedulingSettings
 	Placement      PlacementSettings
 	Monitoring     MonitoringSettings
+	NodeLabeling   NodeLabelingSettings
+	Migration      MigrationSettings
+	Security       SecuritySettings
+	ImageDigests   ImageDigestSettings
 	LogLevel       string
 }
 
 type ManagedNodesSettings struct {
 	LabelKey         string
 	EnabledByDefault bool
+	// PreviousLabelKey stays authoritative next to LabelKey while nodes are relabeled.
+	PreviousLabelKey string
+	TransitionWindow time.Duration
+	// TransitionStarted is stamped by the store when it first sees PreviousLabelKey.
+	TransitionStarted time.Time
+}
+
+// TransitionActive reports whether PreviousLabelKey must still be honoured at the given time.
+func (s ManagedNodesSettings) TransitionActive(now time.Time) bool {
+	if s.PreviousLabelKey == "" || s.PreviousLabelKey == s.LabelKey || s.TransitionStarted.IsZero() {
+		return false
+	}
+	return now.Before(s.TransitionStarted.Add(s.TransitionWindow))
 }
 
 type MonitoringSettings struct {
 	ServiceMonitor bool
 }
 
+// NodeLabelingSettings controls the projection of inventory facts onto Node labels.
+type NodeLabelingSettings struct {
+	Enabled bool
+}
+
+// MigrationSettings controls how pools treat the DaemonSets left behind by the upstream NVIDIA gpu-operator.
+type MigrationSettings struct {
+	// FromGPUOperator keeps pool components from starting next to running gpu-operator components.
+	FromGPUOperator bool
+	// AdoptExisting lets a pool take over the gpu-operator DaemonSets annotated for it instead of creating its own.
+	AdoptExisting bool
+}
+
+// SecuritySettings controls the security contexts of the rendered pool and bootstrap components.
+type SecuritySettings struct {
+	// LegacyPrivileged renders the privileged containers of earlier releases instead of the restricted profiles.
+	LegacyPrivileged bool
+}
+
+// ImageDigestSettings controls the pinning of the pool workload images to the digests their tags resolve to.
+type ImageDigestSettings struct {
+	Enabled bool
+	// RequireDigests blocks rendering while an image cannot be resolved instead of falling back to its tag.
+	RequireDigests bool
+	// PullSecretName and PullSecretNamespace name the dockerconfigjson Secret used against the registry API. An
+	// empty name resolves anonymously, an empty namespace means the module namespace.
+	PullSecretName      string
+	PullSecretNamespace string
+	// CacheTTL is how long a resolved digest is used before the tag is resolved again.
+	CacheTTL time.Duration
+}
+
 type DeviceApprovalMode string
 
 const (
@@ -66,11 +151,21 @@ const (
 type DeviceApprovalSettings struct {
 	Mode     DeviceApprovalMode
 	Selector *metav1.LabelSelector
+	// RequireValidation keeps AutoAttach off until the node passed a validator run since its driver last changed.
+	RequireValidation bool
 }
 
 type SchedulingSettings struct {
 	DefaultStrategy string
 	TopologyKey     string
+	// PoolNodeLabels enables per-pool selection labels on nodes contributing capacity to pools.
+	PoolNodeLabels bool
+	// ColocationHints lets the pod webhook prefer nodes already running replicas of the same
+	// Deployment on time-sliced pools, for pods that opt in.
+	ColocationHints bool
+	// SharedPoolNames lets GPUPools of the same name in different namespaces advertise their shared resource
+	// name as long as their nodes do not overlap.
+	SharedPoolNames bool
 }
 
 type PlacementSettings struct {
@@ -79,6 +174,73 @@ type PlacementSettings struct {
 
 type InventorySettings struct {
 	ResyncPeriod string
+	// DeviceNameTemplate names newly created GPUDevices; existing devices keep their names.
+	DeviceNameTemplate string
+	// StaleNodeThreshold is how long a node may stay NotReady before its devices are marked
+	// unreachable and excluded from pool capacity; zero disables the check.
+	StaleNodeThreshold time.Duration
+	// StaleDeviceRetention is how long unreachable devices are kept before they are deleted;
+	// zero keeps them until the node comes back or is removed.
+	StaleDeviceRetention time.Duration
+	// MaxDeletionsPerSweep caps GPUDevice deletions within the deletion window, either as an
+	// absolute number ("25") or as a share of known devices ("10%"); "0" disables the cap.
+	MaxDeletionsPerSweep string
+	// RequeueSpreadWindow spreads the node requeues caused by a settings change over this window instead of
+	// requeueing every node at once; zero requeues them immediately.
+	RequeueSpreadWindow time.Duration
+	// AttributePassthroughPrefixes selects the NodeFeature instance attributes mirrored onto GPUDevice
+	// annotations under gpu.deckhouse.io/attr.<key>.
+	AttributePassthroughPrefixes []string
+	// TrustedNodeFeatureNamespaces lists the namespaces whose NodeFeatures the inventory reads; an empty list only
+	// accepts the NodeFeature named after the node in DefaultTrustedNodeFeatureNamespace.
+	TrustedNodeFeatureNamespaces []string
+	// IncludeDisplayDevices keeps display-only adapters as GPUDevices; by default they are only listed on GPUNodeState.
+	IncludeDisplayDevices bool
+	// CompatibilityRules extends the built-in driver, toolkit and kernel compatibility rules; a rule named like a
+	// built-in one replaces it.
+	CompatibilityRules []CompatibilityRule
+	// ConditionPolicy tunes how GPUNodeState conditions are reported, keyed by condition type.
+	ConditionPolicy map[string]ConditionPolicy
+}
+
+// ConditionSeverity is exported as the severity label of the inventory condition gauge so alert rules can route on it.
+type ConditionSeverity string
+
+const (
+	ConditionSeverityInfo     ConditionSeverity = "Info"
+	ConditionSeverityWarning  ConditionSeverity = "Warning"
+	ConditionSeverityCritical ConditionSeverity = "Critical"
+)
+
+// ConditionPolicy describes how one condition type is reported. A suppressed condition is still computed, but its
+// reason gets the Suppressed prefix and it is left out of the condition gauge.
+type ConditionPolicy struct {
+	// Severity is empty when the policy only suppresses.
+	Severity ConditionSeverity
+	// SuppressOnNodesMatching selects the nodes the condition is suppressed on; nil suppresses it nowhere.
+	SuppressOnNodesMatching *metav1.LabelSelector
+}
+
+// CompatibilityEffect is what a matching compatibility rule does to a node.
+type CompatibilityEffect string
+
+const (
+	// CompatibilityEffectWarn reports the node through a condition and an event.
+	CompatibilityEffectWarn CompatibilityEffect = "Warn"
+	// CompatibilityEffectBlock additionally keeps the node's devices from AutoAttach and pool capacity.
+	CompatibilityEffectBlock CompatibilityEffect = "Block"
+)
+
+// CompatibilityRule describes a driver, toolkit and kernel combination known to be broken. A rule matches a
+// node when every range it sets contains the node's version; a range whose version the node does not report
+// never matches.
+type CompatibilityRule struct {
+	Name         string
+	DriverRange  string
+	ToolkitRange string
+	KernelRange  string
+	Effect       CompatibilityEffect
+	Message      string
 }
 
 type HTTPSMode string
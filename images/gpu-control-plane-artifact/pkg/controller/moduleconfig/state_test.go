@@ -15,6 +15,7 @@
 package moduleconfig
 
 import (
+	"reflect"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,13 +32,18 @@ func TestValues(t *testing.T) {
 				Mode:     DeviceApprovalModeSelector,
 				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
 			},
-			Scheduling: SchedulingSettings{DefaultStrategy: "BinPack", TopologyKey: "zone"},
-			Monitoring: MonitoringSettings{ServiceMonitor: false},
-			LogLevel:   "Debug",
+			Scheduling:   SchedulingSettings{DefaultStrategy: "BinPack", TopologyKey: "zone"},
+			Monitoring:   MonitoringSettings{ServiceMonitor: false},
+			NodeLabeling: NodeLabelingSettings{Enabled: true},
+			Migration:    MigrationSettings{FromGPUOperator: true},
+			Security:     SecuritySettings{LegacyPrivileged: true},
+			ImageDigests: ImageDigestSettings{Enabled: true, PullSecretName: "registry", PullSecretNamespace: "infra", CacheTTL: DefaultImageDigestCacheTTL},
+			LogLevel:     "Debug",
 		},
 		Inventory:        InventorySettings{ResyncPeriod: "45s"},
 		HTTPS:            HTTPSSettings{Mode: HTTPSModeCustomCertificate, CustomCertificateSecret: "secret"},
 		HighAvailability: boolPtrState(true),
+		Paused:           true,
 		Sanitized:        map[string]any{"managedNodes": map[string]any{}},
 	}
 
@@ -49,6 +55,22 @@ func TestValues(t *testing.T) {
 	if !values["highAvailability"].(bool) {
 		t.Fatalf("expected highAvailability flag")
 	}
+	if !values["paused"].(bool) {
+		t.Fatalf("expected paused flag")
+	}
+	if values["migrateFromGPUOperator"] != true || values["adoptExisting"] != nil {
+		t.Fatalf("unexpected migration values: %v, %v", values["migrateFromGPUOperator"], values["adoptExisting"])
+	}
+	if values["legacyPrivileged"] != true {
+		t.Fatalf("expected legacyPrivileged flag, got %v", values["legacyPrivileged"])
+	}
+	wantDigests := map[string]any{"enabled": true, "pullSecretRef": map[string]any{"name": "registry", "namespace": "infra"}}
+	if !reflect.DeepEqual(values["imageDigests"], wantDigests) {
+		t.Fatalf("unexpected imageDigests values: %v", values["imageDigests"])
+	}
+	if !values["nodeLabeling"].(map[string]any)["enabled"].(bool) {
+		t.Fatalf("expected nodeLabeling flag")
+	}
 	if monitor := values["monitoring"].(map[string]any)["serviceMonitor"].(bool); monitor {
 		t.Fatalf("expected serviceMonitor value propagated")
 	}
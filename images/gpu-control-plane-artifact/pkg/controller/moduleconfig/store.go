@@ -14,17 +14,37 @@
 
 package moduleconfig
 
-import "sync"
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
 
 // ModuleConfigStore keeps the current module State for controllers.
 type ModuleConfigStore struct {
 	mu    sync.RWMutex
 	state State
+	now   func() time.Time
+
+	// generation counts the updates that changed the state; updated is when the last one was stored.
+	generation uint64
+	updated    time.Time
+
+	pauseListeners    []chan event.GenericEvent
+	logLevelListeners []chan struct{}
+	settingsListeners []chan event.GenericEvent
 }
 
 // NewModuleConfigStore initialises store with provided state.
 func NewModuleConfigStore(state State) *ModuleConfigStore {
-	return &ModuleConfigStore{state: state.Clone()}
+	s := &ModuleConfigStore{now: time.Now}
+	s.state = state.Clone()
+	if s.state.Settings.ManagedNodes.PreviousLabelKey != "" {
+		s.state.Settings.ManagedNodes.TransitionStarted = s.now()
+	}
+	return s
 }
 
 // Current returns a copy of the current state.
@@ -34,9 +54,114 @@ func (s *ModuleConfigStore) Current() State {
 	return s.state.Clone()
 }
 
-// Update replaces stored state.
+// Update replaces stored state. An update that changes the managed-node label key retains the
+// previous key, so nodes carrying only the old label stay managed during the transition window.
 func (s *ModuleConfigStore) Update(state State) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.state = state.Clone()
+	next := state.Clone()
+	s.carryLabelKeyTransition(&next.Settings.ManagedNodes, s.state.Settings.ManagedNodes)
+	if next.Paused != s.state.Paused {
+		s.notifyPause()
+	}
+	if next.Settings.LogLevel != s.state.Settings.LogLevel {
+		notify(s.logLevelListeners)
+	}
+	if !reflect.DeepEqual(next, s.state) {
+		s.generation++
+		s.updated = s.now()
+		if settingsChanged(next, s.state) {
+			notifyGeneric(s.settingsListeners)
+		}
+	}
+	s.state = next
+}
+
+// Generation returns how many updates changed the state and when the last of them was stored.
+func (s *ModuleConfigStore) Generation() (uint64, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.generation, s.updated
+}
+
+// SettingsEvents returns a channel that receives an event whenever an update changes settings other than
+// settings.paused and settings.logLevel, which have their own channels. The event carries no object; the
+// change is read with Current and Generation. Events for a listener that has not drained the previous one
+// are coalesced.
+func (s *ModuleConfigStore) SettingsEvents() <-chan event.GenericEvent {
+	ch := make(chan event.GenericEvent, 1)
+	s.mu.Lock()
+	s.settingsListeners = append(s.settingsListeners, ch)
+	s.mu.Unlock()
+	return ch
+}
+
+// PauseEvents returns a channel that receives an event whenever settings.paused flips, so controllers
+// can requeue all their objects. The event carries no object. Events for a listener that has not
+// drained the previous one are coalesced.
+func (s *ModuleConfigStore) PauseEvents() <-chan event.GenericEvent {
+	ch := make(chan event.GenericEvent, 1)
+	s.mu.Lock()
+	s.pauseListeners = append(s.pauseListeners, ch)
+	s.mu.Unlock()
+	return ch
+}
+
+// LogLevelEvents returns a channel that is signalled whenever settings.logLevel changes; the new
+// level is read with Current. Signals for a listener that has not drained the previous one are coalesced.
+func (s *ModuleConfigStore) LogLevelEvents() <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.logLevelListeners = append(s.logLevelListeners, ch)
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *ModuleConfigStore) notifyPause() {
+	notifyGeneric(s.pauseListeners)
+}
+
+func notifyGeneric(listeners []chan event.GenericEvent) {
+	for _, ch := range listeners {
+		select {
+		case ch <- event.GenericEvent{}:
+		default:
+		}
+	}
+}
+
+// settingsChanged ignores the fields whose changes are propagated through PauseEvents and LogLevelEvents.
+func settingsChanged(next, prev State) bool {
+	next, prev = next.Clone(), prev.Clone()
+	next.Paused, prev.Paused = false, false
+	next.Settings.LogLevel, prev.Settings.LogLevel = "", ""
+	delete(next.Sanitized, "paused")
+	delete(prev.Sanitized, "paused")
+	delete(next.Sanitized, "logLevel")
+	delete(prev.Sanitized, "logLevel")
+	return !reflect.DeepEqual(next, prev)
+}
+
+func notify(listeners []chan struct{}) {
+	for _, ch := range listeners {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *ModuleConfigStore) carryLabelKeyTransition(next *ManagedNodesSettings, prev ManagedNodesSettings) {
+	switch {
+	case next.LabelKey != prev.LabelKey:
+		if next.PreviousLabelKey == "" {
+			next.PreviousLabelKey = prev.LabelKey
+		}
+		next.TransitionStarted = s.now()
+	case next.PreviousLabelKey == "" || next.PreviousLabelKey == prev.PreviousLabelKey:
+		next.PreviousLabelKey = prev.PreviousLabelKey
+		next.TransitionStarted = prev.TransitionStarted
+	default:
+		next.TransitionStarted = s.now()
+	}
 }
@@ -14,7 +14,10 @@
 
 package moduleconfig
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestModuleConfigStoreCloneOnCurrent(t *testing.T) {
 	initial := DefaultState()
@@ -43,3 +46,157 @@ func TestModuleConfigStoreCloneOnUpdate(t *testing.T) {
 		t.Fatalf("Update must clone state, got %s", current.Settings.ManagedNodes.LabelKey)
 	}
 }
+
+func TestModuleConfigStoreRetainsPreviousLabelKey(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewModuleConfigStore(DefaultState())
+	store.now = func() time.Time { return now }
+
+	updated := DefaultState()
+	updated.Settings.ManagedNodes.LabelKey = "example.com/gpu"
+	store.Update(updated)
+
+	managed := store.Current().Settings.ManagedNodes
+	if managed.PreviousLabelKey != DefaultNodeLabelKey || !managed.TransitionStarted.Equal(now) {
+		t.Fatalf("expected previous key to be retained, got %+v", managed)
+	}
+
+	// Later updates without a key change keep the transition running from the original change.
+	now = now.Add(time.Hour)
+	store.Update(updated)
+	managed = store.Current().Settings.ManagedNodes
+	if managed.PreviousLabelKey != DefaultNodeLabelKey || !managed.TransitionStarted.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected transition to be carried over, got %+v", managed)
+	}
+	if !managed.TransitionActive(now) {
+		t.Fatalf("expected transition to be active within the window")
+	}
+	if managed.TransitionActive(now.Add(DefaultLabelKeyTransitionWindow)) {
+		t.Fatalf("expected transition to expire after the window")
+	}
+
+	// Shrinking the window closes the transition early.
+	closed := updated.Clone()
+	closed.Settings.ManagedNodes.TransitionWindow = 0
+	store.Update(closed)
+	if store.Current().Settings.ManagedNodes.TransitionActive(now) {
+		t.Fatalf("expected zero window to close the transition")
+	}
+}
+
+func TestModuleConfigStoreStampsExplicitPreviousLabelKey(t *testing.T) {
+	state := DefaultState()
+	state.Settings.ManagedNodes.LabelKey = "example.com/gpu"
+	state.Settings.ManagedNodes.PreviousLabelKey = DefaultNodeLabelKey
+
+	store := NewModuleConfigStore(state)
+
+	managed := store.Current().Settings.ManagedNodes
+	if managed.TransitionStarted.IsZero() || !managed.TransitionActive(time.Now()) {
+		t.Fatalf("expected configured transition to start with the store, got %+v", managed)
+	}
+}
+
+func TestModuleConfigStorePauseEvents(t *testing.T) {
+	store := NewModuleConfigStore(DefaultState())
+	events := store.PauseEvents()
+
+	store.Update(DefaultState())
+	select {
+	case <-events:
+		t.Fatalf("expected no event when paused does not change")
+	default:
+	}
+
+	paused := DefaultState()
+	paused.Paused = true
+	store.Update(paused)
+	// A second flip before the listener drains is coalesced into the pending event.
+	store.Update(DefaultState())
+	store.Update(paused)
+
+	select {
+	case <-events:
+	default:
+		t.Fatalf("expected event after pause flip")
+	}
+	select {
+	case <-events:
+		t.Fatalf("expected pending events to be coalesced")
+	default:
+	}
+
+	store.Update(DefaultState())
+	select {
+	case <-events:
+	default:
+		t.Fatalf("expected event after resume")
+	}
+}
+
+func TestModuleConfigStoreLogLevelEvents(t *testing.T) {
+	store := NewModuleConfigStore(DefaultState())
+	events := store.LogLevelEvents()
+
+	paused := DefaultState()
+	paused.Paused = true
+	store.Update(paused)
+	select {
+	case <-events:
+		t.Fatalf("expected no event when logLevel does not change")
+	default:
+	}
+
+	debug := DefaultState()
+	debug.Settings.LogLevel = "Debug"
+	store.Update(debug)
+	select {
+	case <-events:
+	default:
+		t.Fatalf("expected event after logLevel change")
+	}
+	if got := store.Current().Settings.LogLevel; got != "Debug" {
+		t.Fatalf("expected the new level in the store, got %q", got)
+	}
+}
+
+func TestModuleConfigStoreSettingsEventsAndGeneration(t *testing.T) {
+	store := NewModuleConfigStore(DefaultState())
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	events := store.SettingsEvents()
+
+	store.Update(DefaultState())
+	if gen, _ := store.Generation(); gen != 0 {
+		t.Fatalf("expected an unchanged state to keep generation 0, got %d", gen)
+	}
+
+	paused := DefaultState()
+	paused.Paused = true
+	paused.Sanitized["paused"] = true
+	store.Update(paused)
+	select {
+	case <-events:
+		t.Fatalf("expected a pause flip to be left to PauseEvents")
+	default:
+	}
+	if gen, updated := store.Generation(); gen != 1 || !updated.Equal(now) {
+		t.Fatalf("expected generation 1 stamped at %s, got %d at %s", now, gen, updated)
+	}
+	if store.Current().Sanitized["paused"] != true {
+		t.Fatalf("expected the stored state to keep its sanitized values")
+	}
+
+	now = now.Add(time.Minute)
+	changed := DefaultState()
+	changed.Inventory.StaleNodeThreshold = time.Hour
+	store.Update(changed)
+	select {
+	case <-events:
+	default:
+		t.Fatalf("expected event after a settings change")
+	}
+	if gen, updated := store.Generation(); gen != 2 || !updated.Equal(now) {
+		t.Fatalf("expected generation 2 stamped at %s, got %d at %s", now, gen, updated)
+	}
+}
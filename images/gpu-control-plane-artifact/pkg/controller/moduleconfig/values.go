@@ -30,9 +30,60 @@ func (s State) Values() map[string]any {
 	if s.Settings.DeviceApproval.Selector != nil {
 		result["deviceApproval"].(map[string]any)["selector"] = selectorToMap(*s.Settings.DeviceApproval.Selector)
 	}
+	if s.Settings.DeviceApproval.RequireValidation {
+		result["deviceApproval"].(map[string]any)["requireValidationBeforeAttach"] = true
+	}
+	if s.Settings.ManagedNodes.PreviousLabelKey != "" {
+		result["managedNodes"].(map[string]any)["previousLabelKey"] = s.Settings.ManagedNodes.PreviousLabelKey
+	}
+	if s.Settings.ManagedNodes.TransitionWindow != DefaultLabelKeyTransitionWindow {
+		result["managedNodes"].(map[string]any)["labelKeyTransitionWindow"] = formatWindow(s.Settings.ManagedNodes.TransitionWindow)
+	}
+	if s.Inventory.DeviceNameTemplate != "" && s.Inventory.DeviceNameTemplate != DefaultDeviceNameTemplate {
+		result["inventory"].(map[string]any)["deviceNameTemplate"] = s.Inventory.DeviceNameTemplate
+	}
+	if s.Inventory.StaleNodeThreshold != DefaultStaleNodeThreshold {
+		result["inventory"].(map[string]any)["staleNodeThreshold"] = formatWindow(s.Inventory.StaleNodeThreshold)
+	}
+	if s.Inventory.StaleDeviceRetention != DefaultStaleDeviceRetention {
+		result["inventory"].(map[string]any)["staleDeviceRetention"] = formatWindow(s.Inventory.StaleDeviceRetention)
+	}
+	if s.Inventory.MaxDeletionsPerSweep != "" && s.Inventory.MaxDeletionsPerSweep != DefaultMaxDeletionsPerSweep {
+		result["inventory"].(map[string]any)["maxDeletionsPerSweep"] = s.Inventory.MaxDeletionsPerSweep
+	}
+	if s.Inventory.RequeueSpreadWindow != DefaultRequeueSpreadWindow {
+		result["inventory"].(map[string]any)["requeueSpreadWindow"] = formatWindow(s.Inventory.RequeueSpreadWindow)
+	}
+	if len(s.Inventory.AttributePassthroughPrefixes) > 0 {
+		result["inventory"].(map[string]any)["attributePassthroughPrefixes"] = append([]string(nil), s.Inventory.AttributePassthroughPrefixes...)
+	}
+	if !trustsDefaultNodeFeatureNamespaces(s.Inventory.TrustedNodeFeatureNamespaces) {
+		result["inventory"].(map[string]any)["trustedNodeFeatureNamespaces"] = append([]string{}, s.Inventory.TrustedNodeFeatureNamespaces...)
+	}
+	if s.Inventory.IncludeDisplayDevices {
+		result["inventory"].(map[string]any)["includeDisplayDevices"] = true
+	}
+	if len(s.Inventory.CompatibilityRules) > 0 {
+		result["inventory"].(map[string]any)["compatibilityRules"] = sanitizeCompatibilityRules(s.Inventory.CompatibilityRules)
+	}
+	if len(s.Inventory.ConditionPolicy) > 0 {
+		result["inventory"].(map[string]any)["conditionPolicy"] = sanitizeConditionPolicy(s.Inventory.ConditionPolicy)
+	}
 	if s.Settings.Scheduling.TopologyKey != "" {
 		result["scheduling"].(map[string]any)["topologyKey"] = s.Settings.Scheduling.TopologyKey
 	}
+	if s.Settings.Scheduling.PoolNodeLabels {
+		result["scheduling"].(map[string]any)["poolNodeLabels"] = true
+	}
+	if s.Settings.Scheduling.ColocationHints {
+		result["scheduling"].(map[string]any)["colocationHints"] = true
+	}
+	if s.Settings.Scheduling.SharedPoolNames {
+		result["scheduling"].(map[string]any)["sharedPoolNames"] = true
+	}
+	if s.Settings.NodeLabeling.Enabled {
+		result["nodeLabeling"] = map[string]any{"enabled": true}
+	}
 	switch s.HTTPS.Mode {
 	case HTTPSModeCertManager:
 		result["https"].(map[string]any)["certManager"] = map[string]any{"clusterIssuerName": s.HTTPS.CertManagerIssuer}
@@ -42,6 +93,21 @@ func (s State) Values() map[string]any {
 	if s.HighAvailability != nil {
 		result["highAvailability"] = *s.HighAvailability
 	}
+	if s.Settings.Migration.FromGPUOperator {
+		result["migrateFromGPUOperator"] = true
+	}
+	if s.Settings.Migration.AdoptExisting {
+		result["adoptExisting"] = true
+	}
+	if s.Settings.Security.LegacyPrivileged {
+		result["legacyPrivileged"] = true
+	}
+	if s.Settings.ImageDigests.Enabled {
+		result["imageDigests"] = s.Settings.ImageDigests.values()
+	}
+	if s.Paused {
+		result["paused"] = true
+	}
 	return result
 }
 
@@ -0,0 +1,316 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/gfdextender"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/snapshot"
+)
+
+type staticSyncer bool
+
+func (s staticSyncer) WaitForCacheSync(context.Context) bool { return bool(s) }
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add apps scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	return scheme
+}
+
+func clusterObjects() []client.Object {
+	return []client.Object{
+		&v1alpha1.GPUNodeState{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-a"},
+			Spec:       v1alpha1.GPUNodeStateSpec{NodeName: "worker-a"},
+			Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{
+				{Type: "ReadyForPooling", Status: metav1.ConditionTrue},
+				{Type: "WorkloadsDegraded", Status: metav1.ConditionFalse},
+			}},
+		},
+		&v1alpha1.GPUNodeState{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-b"},
+			Spec:       v1alpha1.GPUNodeStateSpec{NodeName: "worker-b"},
+			Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{
+				{Type: "DriverReady", Status: metav1.ConditionFalse},
+			}},
+		},
+		&v1alpha1.GPUNodeState{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-c"},
+			Spec:       v1alpha1.GPUNodeStateSpec{NodeName: "worker-c"},
+			Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{
+				{Type: "NodeDraining", Status: metav1.ConditionTrue},
+			}},
+		},
+		&v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-a-0"},
+			Status:     v1alpha1.GPUDeviceStatus{NodeName: "worker-a", Managed: true},
+		},
+		&v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-a-1"},
+			Status:     v1alpha1.GPUDeviceStatus{NodeName: "worker-a", Managed: true},
+		},
+		&v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-b-0"},
+			Status:     v1alpha1.GPUDeviceStatus{NodeName: "worker-b"},
+		},
+		&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "team"}},
+		&v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+	}
+}
+
+func TestBuildStatusContent(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(clusterObjects()...).Build()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	sweeps := NewSweepTracker()
+	sweeps.NodeReconciled("worker-a", now.Add(-time.Minute))
+	sweeps.NodeReconciled("worker-b", now.Add(-3*time.Minute))
+	sweeps.NodeReconciled("worker-c", now.Add(-2*time.Minute))
+
+	status, err := Build(context.Background(), cl, moduleconfig.DefaultState(), sweeps, "controller-0", now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	hash, _ := snapshot.SettingsHash(moduleconfig.DefaultState())
+	if status.SchemaVersion != SchemaVersion || status.Leader != "controller-0" || status.SettingsHash != hash {
+		t.Fatalf("unexpected identity fields: %+v", status)
+	}
+	if status.ControllerVersion == "" || !status.UpdatedAt.Time.Equal(now) {
+		t.Fatalf("unexpected version or timestamp: %+v", status)
+	}
+	if status.ManagedNodes != 3 || status.ManagedDevices != 2 || status.Pools != 2 {
+		t.Fatalf("unexpected counts: %+v", status)
+	}
+	if status.FailingNodes != 2 {
+		t.Fatalf("expected worker-b and draining worker-c to be failing, got %d", status.FailingNodes)
+	}
+	if status.LastSweepTime == nil || !status.LastSweepTime.Time.Equal(now.Add(-3*time.Minute)) {
+		t.Fatalf("expected sweep time of the least recently reconciled node, got %v", status.LastSweepTime)
+	}
+}
+
+func TestBuildStatusReportsGFDExtenderCondition(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	enabled := moduleconfig.DefaultState()
+	enabled.Enabled = true
+
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	status, err := Build(context.Background(), cl, moduleconfig.DefaultState(), NewSweepTracker(), "controller-0", now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(status.Conditions) != 0 {
+		t.Fatalf("expected no module conditions while the module is disabled, got %+v", status.Conditions)
+	}
+
+	status, err = Build(context.Background(), cl, enabled, NewSweepTracker(), "controller-0", now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(status.Conditions) != 1 || status.Conditions[0].Reason != gfdextender.ReasonNotDeployed {
+		t.Fatalf("expected a missing gfd-extender to be reported, got %+v", status.Conditions)
+	}
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: gfdextender.Name(), Namespace: common.WorkloadsNamespace},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2},
+	}
+	cl = clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ds).Build()
+	status, err = Build(context.Background(), cl, enabled, NewSweepTracker(), "controller-0", now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(status.Conditions) != 1 || status.Conditions[0].Type != gfdextender.ConditionReady || status.Conditions[0].Status != metav1.ConditionTrue {
+		t.Fatalf("expected a ready gfd-extender condition, got %+v", status.Conditions)
+	}
+}
+
+func TestSweepTrackerRequiresEveryNode(t *testing.T) {
+	sweeps := NewSweepTracker()
+	now := time.Now()
+	sweeps.NodeReconciled("worker-a", now)
+
+	if _, ok := sweeps.LastSweep([]string{"worker-a", "worker-b"}); ok {
+		t.Fatalf("sweep must not complete while a node was never reconciled")
+	}
+	sweeps.NodeReconciled("worker-b", now)
+	if _, ok := sweeps.LastSweep([]string{"worker-a", "worker-b"}); !ok {
+		t.Fatalf("expected sweep to complete once every node was reconciled")
+	}
+	sweeps.NodeRemoved("worker-a")
+	if _, ok := sweeps.LastSweep([]string{"worker-a"}); ok {
+		t.Fatalf("expected removed node to be forgotten")
+	}
+	if _, ok := sweeps.LastSweep(nil); ok {
+		t.Fatalf("expected no sweep without nodes")
+	}
+}
+
+func readStatus(t *testing.T, cl client.Client) Status {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: common.WorkloadsNamespace, Name: ConfigMapName}, cm); err != nil {
+		t.Fatalf("get status configmap: %v", err)
+	}
+	var status Status
+	if err := json.Unmarshal([]byte(cm.Data[DataKey]), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	return status
+}
+
+func TestRunOnceRateLimitsUpdates(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(clusterObjects()...).Build()
+	store := moduleconfig.NewModuleConfigStore(moduleconfig.DefaultState())
+	runner := NewRunner(testr.New(t), cl, cl, staticSyncer(true), store, NewSweepTracker(), NewUsageTracker(), "controller-0")
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	runner.now = func() time.Time { return now }
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if got := readStatus(t, cl); got.ManagedNodes != 3 || !got.UpdatedAt.Time.Equal(now) {
+		t.Fatalf("unexpected initial status: %+v", got)
+	}
+
+	if err := cl.Create(context.Background(), &v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "extra"}}); err != nil {
+		t.Fatalf("create pool: %v", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if got := readStatus(t, cl); got.Pools != 2 {
+		t.Fatalf("expected update within a minute to be skipped, got %+v", got)
+	}
+
+	now = now.Add(30 * time.Second)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if got := readStatus(t, cl); got.Pools != 3 || !got.UpdatedAt.Time.Equal(now) {
+		t.Fatalf("expected update after a minute, got %+v", got)
+	}
+}
+
+func TestRunOnceNewLeaderWritesImmediately(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(clusterObjects()...).Build()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	first := NewRunner(testr.New(t), cl, cl, staticSyncer(true), nil, NewSweepTracker(), NewUsageTracker(), "controller-0")
+	first.now = func() time.Time { return now }
+	if err := first.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	second := NewRunner(testr.New(t), cl, cl, staticSyncer(true), nil, NewSweepTracker(), NewUsageTracker(), "controller-1")
+	second.now = func() time.Time { return now.Add(time.Second) }
+	if err := second.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if got := readStatus(t, cl); got.Leader != "controller-1" {
+		t.Fatalf("expected new leader to rewrite the status, got %+v", got)
+	}
+}
+
+func TestRunOnceSkipsWhenCacheNotSynced(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	runner := NewRunner(testr.New(t), cl, cl, staticSyncer(false), nil, NewSweepTracker(), NewUsageTracker(), "controller-0")
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	list := &corev1.ConfigMapList{}
+	if err := cl.List(context.Background(), list); err != nil {
+		t.Fatalf("list configmaps: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Fatalf("expected no status while cache is not synced")
+	}
+}
+
+func TestUsageTrackerSetPod(t *testing.T) {
+	tracker := NewUsageTracker()
+	first := types.NamespacedName{Namespace: "team-a", Name: "train-0"}
+	second := types.NamespacedName{Namespace: "team-a", Name: "train-1"}
+
+	changed := tracker.SetPod(first, map[string]int64{"team-a/cards": 2, "shared-mig": 3})
+	if !reflect.DeepEqual(changed, map[UsageKey]int64{{"team-a", "team-a/cards"}: 2, {"team-a", "shared-mig"}: 3}) {
+		t.Fatalf("unexpected changes after the first pod: %v", changed)
+	}
+	changed = tracker.SetPod(second, map[string]int64{"team-a/cards": 1})
+	if !reflect.DeepEqual(changed, map[UsageKey]int64{{"team-a", "team-a/cards"}: 3}) {
+		t.Fatalf("unexpected changes after the second pod: %v", changed)
+	}
+	if changed = tracker.SetPod(second, map[string]int64{"team-a/cards": 1}); len(changed) != 0 {
+		t.Fatalf("expected a repeated pod update to change nothing, got %v", changed)
+	}
+
+	changed = tracker.SetPod(first, nil)
+	if !reflect.DeepEqual(changed, map[UsageKey]int64{{"team-a", "team-a/cards"}: 1, {"team-a", "shared-mig"}: 0}) {
+		t.Fatalf("unexpected changes after removing the first pod: %v", changed)
+	}
+	want := []NamespaceUsage{{Namespace: "team-a", Pool: "team-a/cards", Units: 1}}
+	if got := tracker.Report(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Report() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunOnceReportsNamespaceUsage(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(clusterObjects()...).Build()
+	usage := NewUsageTracker()
+	usage.SetPod(types.NamespacedName{Namespace: "team-b", Name: "infer"}, map[string]int64{"shared-mig": 4})
+	usage.SetPod(types.NamespacedName{Namespace: "team-a", Name: "train"}, map[string]int64{"team-a/cards": 2})
+	runner := NewRunner(testr.New(t), cl, cl, staticSyncer(true), nil, NewSweepTracker(), usage, "controller-0")
+
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	want := []NamespaceUsage{
+		{Namespace: "team-a", Pool: "team-a/cards", Units: 2},
+		{Namespace: "team-b", Pool: "shared-mig", Units: 4},
+	}
+	if got := readStatus(t, cl).NamespaceUsage; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected namespace usage: %+v", got)
+	}
+}
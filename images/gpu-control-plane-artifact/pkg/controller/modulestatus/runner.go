@@ -0,0 +1,188 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	statusmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/modulestatus"
+)
+
+const (
+	// ConfigMapName is the well-known name of the module status ConfigMap.
+	ConfigMapName = "gpu-control-plane-status"
+	// DataKey is the ConfigMap key storing the serialized status.
+	DataKey = "status.json"
+	// MinUpdateInterval bounds how often the status is rewritten.
+	MinUpdateInterval = time.Minute
+
+	cacheSyncWindow = 5 * time.Second
+)
+
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// Runner keeps the module status ConfigMap and metrics up to date while this replica is the leader.
+type Runner struct {
+	log       logr.Logger
+	client    client.Client
+	reader    client.Reader
+	syncer    cacheSyncer
+	store     *moduleconfig.ModuleConfigStore
+	sweeps    *SweepTracker
+	usage     *UsageTracker
+	namespace string
+	leader    string
+	interval  time.Duration
+	now       func() time.Time
+
+	lastWrite time.Time
+}
+
+// NewRunner builds a status runner; reader and syncer are normally the manager cache.
+func NewRunner(log logr.Logger, c client.Client, reader client.Reader, syncer cacheSyncer, store *moduleconfig.ModuleConfigStore, sweeps *SweepTracker, usage *UsageTracker, leader string) *Runner {
+	return &Runner{
+		log:       log,
+		client:    c,
+		reader:    reader,
+		syncer:    syncer,
+		store:     store,
+		sweeps:    sweeps,
+		usage:     usage,
+		namespace: common.WorkloadsNamespace,
+		leader:    leader,
+		interval:  MinUpdateInterval,
+		now:       time.Now,
+	}
+}
+
+// SetupRunner registers the status runner with the manager; sweeps and usage are the trackers the inventory and
+// namespace usage controllers feed.
+func SetupRunner(mgr ctrl.Manager, log logr.Logger, store *moduleconfig.ModuleConfigStore, sweeps *SweepTracker, usage *UsageTracker, leader string) error {
+	cache := mgr.GetCache()
+	if cache == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+	statusmetrics.Register()
+	if err := mgr.Add(NewRunner(log.WithName("module-status"), mgr.GetClient(), cache, cache, store, sweeps, usage, leader)); err != nil {
+		return fmt.Errorf("add module status runner: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection keeps a single writer; a new leader starts the runner and writes right away.
+func (r *Runner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the status loop until the context is cancelled.
+func (r *Runner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RunOnce(ctx); err != nil {
+			r.log.Error(err, "failed to update module status")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce refreshes the status unless it was written less than the update interval ago. It is a no-op while the
+// cache is not synced.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	now := r.now()
+	if !r.lastWrite.IsZero() && now.Sub(r.lastWrite) < r.interval {
+		return nil
+	}
+
+	if r.syncer != nil {
+		syncCtx, cancel := context.WithTimeout(ctx, cacheSyncWindow)
+		synced := r.syncer.WaitForCacheSync(syncCtx)
+		cancel()
+		if !synced {
+			r.log.V(1).Info("cache not synced, skipping module status update")
+			return nil
+		}
+	}
+
+	state := moduleconfig.DefaultState()
+	if r.store != nil {
+		state = r.store.Current()
+	}
+
+	status, err := Build(ctx, r.reader, state, r.sweeps, r.leader, now)
+	if err != nil {
+		return err
+	}
+	status.NamespaceUsage = r.usage.Report()
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("encode module status: %w", err)
+	}
+	if err := r.write(ctx, string(data)); err != nil {
+		return err
+	}
+	r.lastWrite = now
+
+	var lastSweep time.Time
+	if status.LastSweepTime != nil {
+		lastSweep = status.LastSweepTime.Time
+	}
+	statusmetrics.ModuleStatusSet(status.ManagedNodes, status.ManagedDevices, status.Pools, status.FailingNodes, lastSweep)
+	statusmetrics.ModuleStatusInfoSet(status.ControllerVersion, status.Leader, status.SettingsHash)
+	return nil
+}
+
+func (r *Runner) write(ctx context.Context, data string) error {
+	key := types.NamespacedName{Namespace: r.namespace, Name: ConfigMapName}
+	current, err := commonobject.FetchObject(ctx, key, r.client, &corev1.ConfigMap{})
+	if err != nil {
+		return fmt.Errorf("get module status: %w", err)
+	}
+	if current == nil {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: r.namespace},
+			Data:       map[string]string{DataKey: data},
+		}
+		if err := r.client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("create module status: %w", err)
+		}
+		return nil
+	}
+	current.Data = map[string]string{DataKey: data}
+	if err := r.client.Update(ctx, current); err != nil {
+		return fmt.Errorf("update module status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package modulestatus maintains a single ConfigMap summarising the health of the whole module.
+package modulestatus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/gfdextender"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/nfdapi"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/snapshot"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
+)
+
+// SchemaVersion identifies the status layout; bump it on incompatible changes.
+const SchemaVersion = "gpu.deckhouse.io/status/v1"
+
+// negativeConditions are node conditions that signal a problem when True rather than when False.
+var negativeConditions = map[string]struct{}{
+	"NodeDraining":      {},
+	"WorkloadsDegraded": {},
+}
+
+// Status is the module health summary stored in the status ConfigMap.
+type Status struct {
+	SchemaVersion     string       `json:"schemaVersion"`
+	ControllerVersion string       `json:"controllerVersion"`
+	GitCommit         string       `json:"gitCommit"`
+	Leader            string       `json:"leader"`
+	ManagedNodes      int          `json:"managedNodes"`
+	ManagedDevices    int          `json:"managedDevices"`
+	Pools             int          `json:"pools"`
+	FailingNodes      int          `json:"failingNodes"`
+	SettingsHash      string       `json:"settingsHash"`
+	LastSweepTime     *metav1.Time `json:"lastSweepTime,omitempty"`
+	UpdatedAt         metav1.Time  `json:"updatedAt"`
+	// NamespaceUsage lists the pool units requested by the scheduled pods of every namespace.
+	NamespaceUsage []NamespaceUsage `json:"namespaceUsage,omitempty"`
+	// Conditions report module-level workloads owned by the controller, such as the gfd-extender DaemonSet, and
+	// whether the cluster serves the NodeFeature API version the controller reads.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Build assembles the status from the provided reader. Callers are expected to pass the manager cache.
+func Build(ctx context.Context, reader client.Reader, state moduleconfig.State, sweeps *SweepTracker, leader string, now time.Time) (Status, error) {
+	hash, err := snapshot.SettingsHash(state)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{
+		SchemaVersion:     SchemaVersion,
+		ControllerVersion: version.Version(),
+		GitCommit:         version.GitCommit(),
+		Leader:            leader,
+		SettingsHash:      hash,
+		UpdatedAt:         metav1.NewTime(now.UTC()),
+	}
+
+	nodeStates := &v1alpha1.GPUNodeStateList{}
+	if err := reader.List(ctx, nodeStates); err != nil {
+		return Status{}, fmt.Errorf("list GPUNodeStates: %w", err)
+	}
+	nodes := make([]string, 0, len(nodeStates.Items))
+	for _, item := range nodeStates.Items {
+		name := item.Spec.NodeName
+		if name == "" {
+			name = item.Name
+		}
+		nodes = append(nodes, name)
+		if failing(item.Status.Conditions) {
+			status.FailingNodes++
+		}
+	}
+	status.ManagedNodes = len(nodes)
+	if sweep, ok := sweeps.LastSweep(nodes); ok {
+		t := metav1.NewTime(sweep.UTC())
+		status.LastSweepTime = &t
+	}
+
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := reader.List(ctx, devices); err != nil {
+		return Status{}, fmt.Errorf("list GPUDevices: %w", err)
+	}
+	for _, item := range devices.Items {
+		if item.Status.Managed {
+			status.ManagedDevices++
+		}
+	}
+
+	pools := &v1alpha1.GPUPoolList{}
+	if err := reader.List(ctx, pools); err != nil {
+		return Status{}, fmt.Errorf("list GPUPools: %w", err)
+	}
+	clusterPools := &v1alpha1.ClusterGPUPoolList{}
+	if err := reader.List(ctx, clusterPools); err != nil {
+		return Status{}, fmt.Errorf("list ClusterGPUPools: %w", err)
+	}
+	status.Pools = len(pools.Items) + len(clusterPools.Items)
+
+	if state.Enabled {
+		key := types.NamespacedName{Namespace: common.WorkloadsNamespace, Name: gfdextender.Name()}
+		ds := &appsv1.DaemonSet{}
+		if err := reader.Get(ctx, key, ds); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return Status{}, fmt.Errorf("get gfd-extender DaemonSet: %w", err)
+			}
+			ds = nil
+		}
+		status.Conditions = append(status.Conditions, gfdextender.ReadyCondition(ds))
+	}
+	if cond, ok := nfdapi.Default.Condition(); ok {
+		status.Conditions = append(status.Conditions, cond)
+	}
+
+	return status, nil
+}
+
+func failing(conds []metav1.Condition) bool {
+	for _, cond := range conds {
+		if _, negative := negativeConditions[cond.Type]; negative {
+			if cond.Status == metav1.ConditionTrue {
+				return true
+			}
+			continue
+		}
+		if cond.Status == metav1.ConditionFalse {
+			return true
+		}
+	}
+	return false
+}
+
+// SweepTracker remembers when each node was last reconciled successfully by this process.
+type SweepTracker struct {
+	mu         sync.Mutex
+	reconciled map[string]time.Time
+}
+
+// NewSweepTracker returns an empty tracker.
+func NewSweepTracker() *SweepTracker {
+	return &SweepTracker{reconciled: map[string]time.Time{}}
+}
+
+// NodeReconciled records a successful reconcile of the node.
+func (t *SweepTracker) NodeReconciled(node string, at time.Time) {
+	if t == nil || node == "" {
+		return
+	}
+	t.mu.Lock()
+	t.reconciled[node] = at
+	t.mu.Unlock()
+}
+
+// NodeRemoved forgets a node that no longer exists.
+func (t *SweepTracker) NodeRemoved(node string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	delete(t.reconciled, node)
+	t.mu.Unlock()
+}
+
+// LastSweep returns the time by which every listed node was reconciled at least once. It reports false while any of
+// them has not been reconciled yet, so a fresh leader does not claim a sweep it has not finished.
+func (t *SweepTracker) LastSweep(nodes []string) (time.Time, bool) {
+	if t == nil || len(nodes) == 0 {
+		return time.Time{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var oldest time.Time
+	for _, node := range nodes {
+		at, ok := t.reconciled[node]
+		if !ok {
+			return time.Time{}, false
+		}
+		if oldest.IsZero() || at.Before(oldest) {
+			oldest = at
+		}
+	}
+	return oldest, true
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+import (
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// NamespaceUsage is the number of pool units the scheduled pods of a namespace request from one pool.
+type NamespaceUsage struct {
+	Namespace string `json:"namespace"`
+	// Pool is <namespace>/<name> for a GPUPool and <name> for a ClusterGPUPool.
+	Pool  string `json:"pool"`
+	Units int64  `json:"units"`
+}
+
+// UsageKey identifies the units one namespace requests from one pool.
+type UsageKey struct {
+	Namespace string
+	Pool      string
+}
+
+// UsageTracker sums the pool units requested per namespace. It is updated one pod at a time, so a pod event
+// never requires the other pods of the cluster to be listed again.
+type UsageTracker struct {
+	mu     sync.Mutex
+	pods   map[types.NamespacedName]map[string]int64
+	totals map[UsageKey]int64
+}
+
+// NewUsageTracker returns an empty tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{
+		pods:   map[types.NamespacedName]map[string]int64{},
+		totals: map[UsageKey]int64{},
+	}
+}
+
+// SetPod replaces the units the pod requests, keyed by pool, and returns the namespace totals that changed. A
+// changed total of zero means the namespace no longer uses the pool. An empty units map forgets the pod.
+func (t *UsageTracker) SetPod(pod types.NamespacedName, units map[string]int64) map[UsageKey]int64 {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.pods[pod]
+	current := make(map[string]int64, len(units))
+	for pool, value := range units {
+		if value > 0 {
+			current[pool] = value
+		}
+	}
+	if len(current) == 0 {
+		delete(t.pods, pod)
+	} else {
+		t.pods[pod] = current
+	}
+
+	changed := map[UsageKey]int64{}
+	apply := func(pool string, delta int64) {
+		if delta == 0 {
+			return
+		}
+		key := UsageKey{Namespace: pod.Namespace, Pool: pool}
+		total := t.totals[key] + delta
+		if total <= 0 {
+			total = 0
+			delete(t.totals, key)
+		} else {
+			t.totals[key] = total
+		}
+		changed[key] = total
+	}
+	for pool, value := range previous {
+		apply(pool, current[pool]-value)
+	}
+	for pool, value := range current {
+		if _, seen := previous[pool]; !seen {
+			apply(pool, value)
+		}
+	}
+	return changed
+}
+
+// Report returns the non-zero totals ordered by namespace and pool.
+func (t *UsageTracker) Report() []NamespaceUsage {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := make([]NamespaceUsage, 0, len(t.totals))
+	for key, units := range t.totals {
+		report = append(report, NamespaceUsage{Namespace: key.Namespace, Pool: key.Pool, Units: units})
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Namespace != report[j].Namespace {
+			return report[i].Namespace < report[j].Namespace
+		}
+		return report[i].Pool < report[j].Pool
+	})
+	return report
+}
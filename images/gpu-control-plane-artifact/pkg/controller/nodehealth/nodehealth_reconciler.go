@@ -0,0 +1,187 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodehealth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/nodehealth"
+	healthmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/nodehealth"
+)
+
+// Reconciler keeps the health class of one GPUNodeState, keyed by node name, in its status and metrics.
+type Reconciler struct {
+	client client.Client
+	log    logr.Logger
+	store  *moduleconfig.ModuleConfigStore
+	guard  *ownership.Guard
+}
+
+// New builds the reconciler; a nil guard lets it patch every node state.
+func New(log logr.Logger, store *moduleconfig.ModuleConfigStore, guard *ownership.Guard) *Reconciler {
+	return &Reconciler{log: log, store: store, guard: guard}
+}
+
+var _ reconcile.Reconciler = (*Reconciler)(nil)
+
+func (r *Reconciler) SetupController(_ context.Context, mgr manager.Manager, ctr controller.Controller) error {
+	r.client = mgr.GetClient()
+
+	c := mgr.GetCache()
+	if c == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+
+	if err := ctr.Watch(
+		source.Kind(c, &v1alpha1.GPUNodeState{}, &handler.TypedEnqueueRequestForObject[*v1alpha1.GPUNodeState]{}, nodeStatePredicates()),
+	); err != nil {
+		return fmt.Errorf("error setting watch on GPUNodeState: %w", err)
+	}
+	if err := ctr.Watch(
+		source.Kind(c, &v1alpha1.GPUDevice{}, handler.TypedEnqueueRequestsFromMapFunc(mapDeviceToNode), devicePredicates()),
+	); err != nil {
+		return fmt.Errorf("error setting watch on GPUDevice: %w", err)
+	}
+	return nil
+}
+
+func nodeStatePredicates() predicate.TypedPredicate[*v1alpha1.GPUNodeState] {
+	return predicate.TypedFuncs[*v1alpha1.GPUNodeState]{
+		CreateFunc:  func(event.TypedCreateEvent[*v1alpha1.GPUNodeState]) bool { return true },
+		DeleteFunc:  func(event.TypedDeleteEvent[*v1alpha1.GPUNodeState]) bool { return true },
+		GenericFunc: func(event.TypedGenericEvent[*v1alpha1.GPUNodeState]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[*v1alpha1.GPUNodeState]) bool {
+			oldState, newState := e.ObjectOld, e.ObjectNew
+			if oldState == nil || newState == nil {
+				return true
+			}
+			for _, conditionType := range []string{"InventoryComplete", "DriverReady"} {
+				if !conditionEqual(oldState, newState, conditionType) {
+					return true
+				}
+			}
+			// A status write by another controller must not drop the class.
+			return newState.Status.Health == ""
+		},
+	}
+}
+
+func conditionEqual(oldState, newState *v1alpha1.GPUNodeState, conditionType string) bool {
+	oldCond := apimeta.FindStatusCondition(oldState.Status.Conditions, conditionType)
+	newCond := apimeta.FindStatusCondition(newState.Status.Conditions, conditionType)
+	if oldCond == nil || newCond == nil {
+		return oldCond == newCond
+	}
+	return oldCond.Status == newCond.Status && oldCond.Reason == newCond.Reason
+}
+
+func devicePredicates() predicate.TypedPredicate[*v1alpha1.GPUDevice] {
+	return predicate.TypedFuncs[*v1alpha1.GPUDevice]{
+		CreateFunc:  func(event.TypedCreateEvent[*v1alpha1.GPUDevice]) bool { return true },
+		DeleteFunc:  func(event.TypedDeleteEvent[*v1alpha1.GPUDevice]) bool { return true },
+		GenericFunc: func(event.TypedGenericEvent[*v1alpha1.GPUDevice]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[*v1alpha1.GPUDevice]) bool {
+			oldDev, newDev := e.ObjectOld, e.ObjectNew
+			if oldDev == nil || newDev == nil {
+				return true
+			}
+			return oldDev.Status.State != newDev.Status.State || oldDev.Status.NodeName != newDev.Status.NodeName
+		},
+	}
+}
+
+func mapDeviceToNode(_ context.Context, device *v1alpha1.GPUDevice) []reconcile.Request {
+	if device == nil || device.Status.NodeName == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: device.Status.NodeName}}}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx).WithValues("node", req.Name)
+	ctx = logr.NewContext(ctx, log)
+
+	inventory, err := commonobject.FetchObject(ctx, req.NamespacedName, r.client, &v1alpha1.GPUNodeState{})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if inventory == nil {
+		healthmetrics.NodeHealthDelete(req.Name)
+		return ctrl.Result{}, nil
+	}
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping node health")
+		return ctrl.Result{}, nil
+	}
+
+	nodeName := inventory.Spec.NodeName
+	if nodeName == "" {
+		nodeName = inventory.Name
+	}
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := r.client.List(ctx, devices, client.MatchingFields{indexer.GPUDeviceNodeField: nodeName}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	class, reasons := nodehealth.Classify(inventory.Status.Conditions, devices.Items)
+	healthmetrics.NodeHealthSet(inventory.Name, string(class))
+
+	if inventory.Status.Health == class && slices.Equal(inventory.Status.HealthReasons, reasons) {
+		return ctrl.Result{}, nil
+	}
+	if _, tooNew := reconciler.SchemaTooNew(inventory); tooNew {
+		return ctrl.Result{}, nil
+	}
+	if allowed, err := r.guard.MayWrite(ctx, inventory); err != nil || !allowed {
+		return ctrl.Result{}, err
+	}
+
+	original := inventory.DeepCopy()
+	inventory.Status.Health = class
+	inventory.Status.HealthReasons = reasons
+	if err := objsize.Guard(inventory); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.client.Status().Patch(ctx, inventory, client.MergeFrom(original)); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if original.Status.Health != class {
+		log.V(1).Info("node health changed", "from", original.Status.Health, "to", class, "reasons", reasons)
+	}
+	return ctrl.Result{}, nil
+}
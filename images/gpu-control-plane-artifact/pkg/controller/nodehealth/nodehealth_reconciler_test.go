@@ -0,0 +1,181 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodehealth
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/nodehealth"
+)
+
+func newTestReconciler(t *testing.T, objs ...client.Object) (*Reconciler, client.Client) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	obj, field, extract := indexer.IndexGPUDeviceByNode()
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.GPUNodeState{}).
+		WithIndex(obj, field, extract).
+		Build()
+
+	r := New(testr.New(t), moduleconfig.NewModuleConfigStore(moduleconfig.State{Enabled: true}), nil)
+	r.client = cl
+	return r, cl
+}
+
+func reconcileNode(t *testing.T, r *Reconciler, name string) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+}
+
+func healthyNodeState(name string) *v1alpha1.GPUNodeState {
+	return &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.GPUNodeStateSpec{NodeName: name},
+		Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{
+			{Type: "InventoryComplete", Status: metav1.ConditionTrue, Reason: "InventorySynced"},
+			{Type: "DriverReady", Status: metav1.ConditionTrue, Reason: "Validated"},
+		}},
+	}
+}
+
+func nodeDevice(name, node string, state v1alpha1.GPUDeviceState) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: node, State: state},
+	}
+}
+
+func TestReconcileWritesHealthClass(t *testing.T) {
+	ctx := context.Background()
+	r, cl := newTestReconciler(t,
+		healthyNodeState("node-a"),
+		nodeDevice("gpu-a", "node-a", v1alpha1.GPUDeviceStateReady),
+		nodeDevice("gpu-b", "node-b", v1alpha1.GPUDeviceStateFaulted),
+	)
+
+	reconcileNode(t, r, "node-a")
+	state := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: "node-a"}, state); err != nil {
+		t.Fatalf("get node state: %v", err)
+	}
+	if state.Status.Health != v1alpha1.GPUNodeHealthHealthy || state.Status.HealthReasons != nil {
+		t.Fatalf("expected Healthy without reasons, got %s %q", state.Status.Health, state.Status.HealthReasons)
+	}
+
+	device := &v1alpha1.GPUDevice{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: "gpu-a"}, device); err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	device.Status.State = v1alpha1.GPUDeviceStateFaulted
+	if err := cl.Update(ctx, device); err != nil {
+		t.Fatalf("fault device: %v", err)
+	}
+
+	reconcileNode(t, r, "node-a")
+	if err := cl.Get(ctx, types.NamespacedName{Name: "node-a"}, state); err != nil {
+		t.Fatalf("get node state: %v", err)
+	}
+	want := []string{nodehealth.ReasonDevicesFaulted + ": 1 of 1"}
+	if state.Status.Health != v1alpha1.GPUNodeHealthDegraded || !slices.Equal(state.Status.HealthReasons, want) {
+		t.Fatalf("expected Degraded with %q, got %s %q", want, state.Status.Health, state.Status.HealthReasons)
+	}
+}
+
+func TestReconcileSkipsWriteWhenUnchanged(t *testing.T) {
+	state := healthyNodeState("node-a")
+	state.Status.Health = v1alpha1.GPUNodeHealthHealthy
+	r, cl := newTestReconciler(t, state)
+
+	before := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "node-a"}, before); err != nil {
+		t.Fatalf("get node state: %v", err)
+	}
+	reconcileNode(t, r, "node-a")
+	after := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: "node-a"}, after); err != nil {
+		t.Fatalf("get node state: %v", err)
+	}
+	if before.ResourceVersion != after.ResourceVersion {
+		t.Fatalf("expected no write, resourceVersion changed from %s to %s", before.ResourceVersion, after.ResourceVersion)
+	}
+}
+
+func TestReconcileIgnoresDeletedNodeState(t *testing.T) {
+	r, _ := newTestReconciler(t)
+	reconcileNode(t, r, "gone")
+}
+
+func TestPredicates(t *testing.T) {
+	degraded := healthyNodeState("node-a")
+	degraded.Status.Conditions[1].Status = metav1.ConditionFalse
+	healthy := healthyNodeState("node-a")
+	healthy.Status.Health = v1alpha1.GPUNodeHealthHealthy
+	relabelled := healthy.DeepCopy()
+	relabelled.Labels = map[string]string{"a": "b"}
+
+	statePred := nodeStatePredicates()
+	if !statePred.Update(updateEvent(healthy, degraded)) {
+		t.Fatalf("expected a DriverReady change to trigger")
+	}
+	if statePred.Update(updateEvent(healthy, relabelled)) {
+		t.Fatalf("expected an unrelated change to be ignored")
+	}
+
+	ready := nodeDevice("gpu-a", "node-a", v1alpha1.GPUDeviceStateReady)
+	faulted := nodeDevice("gpu-a", "node-a", v1alpha1.GPUDeviceStateFaulted)
+	devPred := devicePredicates()
+	if !devPred.Update(deviceUpdateEvent(ready, faulted)) {
+		t.Fatalf("expected a device state change to trigger")
+	}
+	if devPred.Update(deviceUpdateEvent(ready, ready.DeepCopy())) {
+		t.Fatalf("expected an unchanged device to be ignored")
+	}
+	if got := mapDeviceToNode(context.Background(), faulted); len(got) != 1 || got[0].Name != "node-a" {
+		t.Fatalf("expected the device to map to its node, got %v", got)
+	}
+	if got := mapDeviceToNode(context.Background(), nodeDevice("gpu-x", "", "")); got != nil {
+		t.Fatalf("expected an unbound device to map to nothing, got %v", got)
+	}
+}
+
+func updateEvent(oldObj, newObj *v1alpha1.GPUNodeState) event.TypedUpdateEvent[*v1alpha1.GPUNodeState] {
+	return event.TypedUpdateEvent[*v1alpha1.GPUNodeState]{ObjectOld: oldObj, ObjectNew: newObj}
+}
+
+func deviceUpdateEvent(oldObj, newObj *v1alpha1.GPUDevice) event.TypedUpdateEvent[*v1alpha1.GPUDevice] {
+	return event.TypedUpdateEvent[*v1alpha1.GPUDevice]{ObjectOld: oldObj, ObjectNew: newObj}
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package objsize keeps GPU objects far from the etcd request size limit. Before a status write the unbounded parts
+// of the status are cut to fixed caps, and a write whose object would still exceed the byte limit is refused with
+// ErrTooLarge instead of an opaque "etcdserver: request too large".
+package objsize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"unicode/utf8"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	sizemetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/objsize"
+)
+
+// Caps of the status lists and strings. Handlers building these fields use the same constants, so truncation
+// here only triggers for data that bypassed them.
+const (
+	// MaxHistoryEntries bounds GPUDevice status.history; the oldest transitions are dropped first.
+	MaxHistoryEntries = 10
+	// MaxDisplayDevices bounds GPUNodeState status.displayDevices; entries past the cap are dropped.
+	MaxDisplayDevices = 32
+	// MaxTopologySummaryLines bounds GPUNodeState status.topologySummary.devices; lines past the cap are counted
+	// in omitted.
+	MaxTopologySummaryLines = 64
+	// MaxTopologySummaryLineLength bounds every topology summary line, in runes.
+	MaxTopologySummaryLineLength = 160
+	// MaxConditionMessageLength bounds every condition message, in runes.
+	MaxConditionMessageLength = 1024
+	// MaxReconcileErrorLength bounds GPUNodeState status.lastReconcileError, in runes.
+	MaxReconcileErrorLength = 256
+	// DefaultMaxObjectBytes is the serialized object size above which a write is refused. etcd rejects requests
+	// above 1.5 MiB by default; the margin covers managedFields and concurrent growth.
+	DefaultMaxObjectBytes = 512 * 1024
+)
+
+// Truncated field names reported by Trim and exported as the field label of gpu_status_truncations_total.
+const (
+	FieldHistory            = "history"
+	FieldDisplayDevices     = "displayDevices"
+	FieldTopologySummary    = "topologySummary"
+	FieldConditions         = "conditions"
+	FieldLastReconcileError = "lastReconcileError"
+)
+
+// ErrTooLarge is wrapped by Guard when an object exceeds the byte limit after truncation.
+var ErrTooLarge = errors.New("object exceeds the size limit")
+
+var maxObjectBytes atomic.Int64
+
+// SetMaxObjectBytes sets the byte limit enforced by Guard; zero or a negative value restores DefaultMaxObjectBytes.
+func SetMaxObjectBytes(limit int64) {
+	maxObjectBytes.Store(limit)
+}
+
+// MaxObjectBytes returns the byte limit enforced by Guard.
+func MaxObjectBytes() int64 {
+	if limit := maxObjectBytes.Load(); limit > 0 {
+		return limit
+	}
+	return DefaultMaxObjectBytes
+}
+
+// Guard trims the status of obj in place, counts every truncated field and fails with ErrTooLarge when the
+// serialized object is still above MaxObjectBytes. It is called right before a status write.
+func Guard(obj client.Object) error {
+	kind := kindOf(obj)
+	for _, field := range Trim(obj) {
+		sizemetrics.StatusTruncatedInc(kind, field)
+	}
+	size, err := Size(obj)
+	if err != nil {
+		return err
+	}
+	if limit := MaxObjectBytes(); size > limit {
+		sizemetrics.StatusWriteRejectedInc(kind)
+		return fmt.Errorf("%w: %s %s would be %d bytes, the limit is %d", ErrTooLarge, kind, obj.GetName(), size, limit)
+	}
+	return nil
+}
+
+// Size returns the length of the JSON encoding of obj, which is what the API server stores.
+func Size(obj client.Object) (int64, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0, fmt.Errorf("encode %s %s: %w", kindOf(obj), obj.GetName(), err)
+	}
+	return int64(len(data)), nil
+}
+
+// Trim cuts the status fields of obj to their caps in a fixed order and returns the names of the fields it changed.
+// Condition messages are trimmed for every object with conditions.
+func Trim(obj client.Object) []string {
+	var fields []string
+	switch v := obj.(type) {
+	case *v1alpha1.GPUDevice:
+		if trimHistory(&v.Status.History) {
+			fields = append(fields, FieldHistory)
+		}
+	case *v1alpha1.GPUNodeState:
+		if len(v.Status.DisplayDevices) > MaxDisplayDevices {
+			v.Status.DisplayDevices = append([]v1alpha1.GPUNodeDisplayDevice(nil), v.Status.DisplayDevices[:MaxDisplayDevices]...)
+			fields = append(fields, FieldDisplayDevices)
+		}
+		if trimTopologySummary(v.Status.TopologySummary) {
+			fields = append(fields, FieldTopologySummary)
+		}
+		if message, cut := TruncateString(v.Status.LastReconcileError, MaxReconcileErrorLength); cut {
+			v.Status.LastReconcileError = message
+			fields = append(fields, FieldLastReconcileError)
+		}
+	}
+	if conds := conditions.NewConditionsAccessor(obj).Conditions(); conds != nil && trimConditionMessages(*conds) {
+		fields = append(fields, FieldConditions)
+	}
+	return fields
+}
+
+// TrimHistory keeps the newest MaxHistoryEntries transitions of an oldest-first history.
+func TrimHistory(history []v1alpha1.GPUDeviceStateTransition) []v1alpha1.GPUDeviceStateTransition {
+	if len(history) > MaxHistoryEntries {
+		history = history[len(history)-MaxHistoryEntries:]
+	}
+	return append([]v1alpha1.GPUDeviceStateTransition(nil), history...)
+}
+
+// TruncateString cuts s to at most limit runes and reports whether it did.
+func TruncateString(s string, limit int) (string, bool) {
+	if utf8.RuneCountInString(s) <= limit {
+		return s, false
+	}
+	return string([]rune(s)[:limit]), true
+}
+
+func trimHistory(history *[]v1alpha1.GPUDeviceStateTransition) bool {
+	if len(*history) <= MaxHistoryEntries {
+		return false
+	}
+	*history = TrimHistory(*history)
+	return true
+}
+
+func trimTopologySummary(summary *v1alpha1.GPUNodeTopologySummary) bool {
+	if summary == nil {
+		return false
+	}
+	cut := false
+	if extra := len(summary.Devices) - MaxTopologySummaryLines; extra > 0 {
+		summary.Devices = append([]string(nil), summary.Devices[:MaxTopologySummaryLines]...)
+		summary.Omitted += int32(extra)
+		cut = true
+	}
+	for i := range summary.Devices {
+		if line, ok := TruncateString(summary.Devices[i], MaxTopologySummaryLineLength); ok {
+			summary.Devices[i] = line
+			cut = true
+		}
+	}
+	return cut
+}
+
+func trimConditionMessages(conds []metav1.Condition) bool {
+	cut := false
+	for i := range conds {
+		if message, ok := TruncateString(conds[i].Message, MaxConditionMessageLength); ok {
+			conds[i].Message = message
+			cut = true
+		}
+	}
+	return cut
+}
+
+func kindOf(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objsize
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func transitions(n int) []v1alpha1.GPUDeviceStateTransition {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := make([]v1alpha1.GPUDeviceStateTransition, 0, n)
+	for i := 0; i < n; i++ {
+		history = append(history, v1alpha1.GPUDeviceStateTransition{
+			From:   v1alpha1.GPUDeviceStateReady,
+			To:     v1alpha1.GPUDeviceStateFaulted,
+			Reason: "Step",
+			Time:   metav1.NewTime(base.Add(time.Duration(i) * time.Minute)),
+		})
+	}
+	return history
+}
+
+func TestTrimDeviceDropsOldestHistoryFirst(t *testing.T) {
+	history := transitions(MaxHistoryEntries + 5)
+	device := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Status: v1alpha1.GPUDeviceStatus{
+			History: history,
+			Conditions: []metav1.Condition{
+				{Type: "Ready", Message: strings.Repeat("m", MaxConditionMessageLength+10)},
+				{Type: "Managed", Message: "short"},
+			},
+		},
+	}
+
+	fields := Trim(device)
+	if len(fields) != 2 || fields[0] != FieldHistory || fields[1] != FieldConditions {
+		t.Fatalf("unexpected truncated fields: %v", fields)
+	}
+	got := device.Status.History
+	if len(got) != MaxHistoryEntries {
+		t.Fatalf("expected %d history entries, got %d", MaxHistoryEntries, len(got))
+	}
+	if !got[0].Time.Equal(&history[5].Time) || !got[MaxHistoryEntries-1].Time.Equal(&history[len(history)-1].Time) {
+		t.Fatalf("expected the oldest entries to be dropped, kept %v .. %v", got[0].Time, got[MaxHistoryEntries-1].Time)
+	}
+	if n := len([]rune(device.Status.Conditions[0].Message)); n != MaxConditionMessageLength {
+		t.Fatalf("expected message cut to %d runes, got %d", MaxConditionMessageLength, n)
+	}
+	if device.Status.Conditions[1].Message != "short" {
+		t.Fatalf("short message must be kept, got %q", device.Status.Conditions[1].Message)
+	}
+
+	if fields := Trim(device); len(fields) != 0 {
+		t.Fatalf("expected a trimmed device to stay unchanged, got %v", fields)
+	}
+}
+
+func TestTrimNodeState(t *testing.T) {
+	display := make([]v1alpha1.GPUNodeDisplayDevice, MaxDisplayDevices+3)
+	for i := range display {
+		display[i].Index = string(rune('a' + i))
+	}
+	state := &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+		Status: v1alpha1.GPUNodeStateStatus{
+			DisplayDevices:     display,
+			LastReconcileError: strings.Repeat("é", MaxReconcileErrorLength+1),
+		},
+	}
+
+	fields := Trim(state)
+	if len(fields) != 2 || fields[0] != FieldDisplayDevices || fields[1] != FieldLastReconcileError {
+		t.Fatalf("unexpected truncated fields: %v", fields)
+	}
+	if len(state.Status.DisplayDevices) != MaxDisplayDevices || state.Status.DisplayDevices[MaxDisplayDevices-1].Index != display[MaxDisplayDevices-1].Index {
+		t.Fatalf("expected the first %d display devices kept, got %d", MaxDisplayDevices, len(state.Status.DisplayDevices))
+	}
+	if state.Status.LastReconcileError != strings.Repeat("é", MaxReconcileErrorLength) {
+		t.Fatalf("expected the error cut on a rune boundary, got %d bytes", len(state.Status.LastReconcileError))
+	}
+}
+
+func TestTrimTopologySummary(t *testing.T) {
+	lines := make([]string, MaxTopologySummaryLines+2)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	lines[0] = strings.Repeat("x", MaxTopologySummaryLineLength+10)
+	state := &v1alpha1.GPUNodeState{
+		ObjectMeta: metav1.ObjectMeta{Name: "node"},
+		Status: v1alpha1.GPUNodeStateStatus{
+			TopologySummary: &v1alpha1.GPUNodeTopologySummary{Devices: lines, Omitted: 1},
+		},
+	}
+
+	if fields := Trim(state); len(fields) != 1 || fields[0] != FieldTopologySummary {
+		t.Fatalf("unexpected truncated fields: %v", fields)
+	}
+	summary := state.Status.TopologySummary
+	if len(summary.Devices) != MaxTopologySummaryLines || summary.Omitted != 3 {
+		t.Fatalf("expected %d lines and 3 omitted, got %d and %d", MaxTopologySummaryLines, len(summary.Devices), summary.Omitted)
+	}
+	if n := len([]rune(summary.Devices[0])); n != MaxTopologySummaryLineLength {
+		t.Fatalf("expected the line cut to %d runes, got %d", MaxTopologySummaryLineLength, n)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	t.Cleanup(func() { SetMaxObjectBytes(0) })
+
+	if MaxObjectBytes() != DefaultMaxObjectBytes {
+		t.Fatalf("expected default limit %d, got %d", DefaultMaxObjectBytes, MaxObjectBytes())
+	}
+
+	oversizedHistory := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Status:     v1alpha1.GPUDeviceStatus{History: transitions(2000)},
+	}
+	if err := Guard(oversizedHistory); err != nil {
+		t.Fatalf("expected truncation to bring the device under the limit, got %v", err)
+	}
+	if len(oversizedHistory.Status.History) != MaxHistoryEntries {
+		t.Fatalf("expected history trimmed, got %d entries", len(oversizedHistory.Status.History))
+	}
+
+	SetMaxObjectBytes(1024)
+	uncapped := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Status: v1alpha1.GPUDeviceStatus{Hardware: v1alpha1.GPUDeviceHardware{
+			Precision: strings.Split(strings.Repeat("fp16,", 500), ","),
+		}},
+	}
+	err := Guard(uncapped)
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GPUDevice dev") || !strings.Contains(err.Error(), "limit is 1024") {
+		t.Fatalf("expected the object and the limit in the error, got %q", err)
+	}
+
+	SetMaxObjectBytes(-1)
+	if MaxObjectBytes() != DefaultMaxObjectBytes {
+		t.Fatalf("expected a negative limit to restore the default, got %d", MaxObjectBytes())
+	}
+}
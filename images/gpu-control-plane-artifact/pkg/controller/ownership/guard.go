@@ -0,0 +1,201 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	ownmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/ownership"
+)
+
+const (
+	// LeaseSuffix is appended to the leader election ID to name the instance heartbeat Lease.
+	LeaseSuffix = "-instance"
+	// livenessCacheTTL bounds how often the Lease of a foreign instance is read.
+	livenessCacheTTL = 10 * time.Second
+)
+
+// Identity names a control-plane installation by its namespace and leader election ID.
+type Identity struct {
+	Namespace string
+	Name      string
+}
+
+// ParseIdentity parses the namespace/name form stored in the controller-instance annotation.
+func ParseIdentity(raw string) (Identity, bool) {
+	namespace, name, ok := strings.Cut(strings.TrimSpace(raw), "/")
+	if !ok || namespace == "" || name == "" {
+		return Identity{}, false
+	}
+	return Identity{Namespace: namespace, Name: name}, true
+}
+
+func (i Identity) String() string {
+	return i.Namespace + "/" + i.Name
+}
+
+// LeaseKey is the heartbeat Lease the instance renews while it is running.
+func (i Identity) LeaseKey() client.ObjectKey {
+	return client.ObjectKey{Namespace: i.Namespace, Name: i.Name + LeaseSuffix}
+}
+
+type livenessEntry struct {
+	alive     bool
+	checkedAt time.Time
+}
+
+// Guard decides whether this instance may write an object claimed through the controller-instance annotation.
+// Objects claimed by another instance whose heartbeat Lease is still renewed are left alone; stale claims are adopted.
+// A nil Guard allows every write and stamps nothing.
+type Guard struct {
+	log        logr.Logger
+	self       Identity
+	reader     client.Reader
+	forceAdopt bool
+	now        func() time.Time
+
+	mu       sync.Mutex
+	liveness map[Identity]livenessEntry
+}
+
+// NewGuard creates a guard for the given instance; reader is used to read heartbeat Leases and should bypass the cache.
+func NewGuard(log logr.Logger, self Identity, reader client.Reader, forceAdopt bool) *Guard {
+	return &Guard{
+		log:        log,
+		self:       self,
+		reader:     reader,
+		forceAdopt: forceAdopt,
+		now:        time.Now,
+		liveness:   make(map[Identity]livenessEntry),
+	}
+}
+
+// Self returns the identity the guard stamps on objects.
+func (g *Guard) Self() Identity {
+	return g.self
+}
+
+// Stamp claims the object for this instance and reports whether the annotation changed.
+// Callers only stamp objects they are writing anyway, after MayWrite allowed it.
+func (g *Guard) Stamp(obj metav1.Object) bool {
+	if g == nil {
+		return false
+	}
+	want := g.self.String()
+	current := obj.GetAnnotations()
+	if current[annotations.ControllerInstance] == want {
+		return false
+	}
+	updated := make(map[string]string, len(current)+1)
+	for key, value := range current {
+		updated[key] = value
+	}
+	updated[annotations.ControllerInstance] = want
+	obj.SetAnnotations(updated)
+	return true
+}
+
+// MayWrite reports whether this instance may modify the object. A conflict with a live instance is logged
+// and counted; the caller skips the write and retries on its next reconcile.
+func (g *Guard) MayWrite(ctx context.Context, obj client.Object) (bool, error) {
+	if g == nil || obj == nil {
+		return true, nil
+	}
+	raw, ok := obj.GetAnnotations()[annotations.ControllerInstance]
+	if !ok || raw == g.self.String() {
+		return true, nil
+	}
+	owner, ok := ParseIdentity(raw)
+	if !ok {
+		g.log.Info("ignoring malformed controller-instance claim", "object", objectRef(obj), "claimedBy", raw)
+		return true, nil
+	}
+	if g.forceAdopt {
+		g.log.Info("force-adopting object claimed by another instance", "object", objectRef(obj), "claimedBy", raw, "instance", g.self.String())
+		return true, nil
+	}
+
+	alive, err := g.instanceAlive(ctx, owner)
+	if err != nil {
+		return false, err
+	}
+	if !alive {
+		g.log.Info("adopting object claimed by a stale instance", "object", objectRef(obj), "claimedBy", raw, "instance", g.self.String())
+		return true, nil
+	}
+
+	ownmetrics.OwnershipConflictInc(raw)
+	g.log.Error(fmt.Errorf("object is claimed by live instance %s", raw),
+		"OWNERSHIP CONFLICT: another gpu-control-plane installation manages this object, skipping write; remove the duplicate installation or restart this one with --force-adopt",
+		"object", objectRef(obj), "claimedBy", raw, "instance", g.self.String())
+	return false, nil
+}
+
+func (g *Guard) instanceAlive(ctx context.Context, owner Identity) (bool, error) {
+	now := g.now()
+	g.mu.Lock()
+	entry, ok := g.liveness[owner]
+	g.mu.Unlock()
+	if ok && now.Sub(entry.checkedAt) < livenessCacheTTL {
+		return entry.alive, nil
+	}
+
+	lease := &coordinationv1.Lease{}
+	alive := false
+	err := g.reader.Get(ctx, owner.LeaseKey(), lease)
+	switch {
+	case err == nil:
+		alive = leaseAlive(lease, now)
+	case apierrors.IsNotFound(err):
+	default:
+		return false, fmt.Errorf("get heartbeat lease of instance %s: %w", owner, err)
+	}
+
+	g.mu.Lock()
+	g.liveness[owner] = livenessEntry{alive: alive, checkedAt: now}
+	g.mu.Unlock()
+	return alive, nil
+}
+
+func leaseAlive(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	expires := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.Before(expires)
+}
+
+func objectRef(obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	if ns := obj.GetNamespace(); ns != "" {
+		return kind + "/" + ns + "/" + obj.GetName()
+	}
+	return kind + "/" + obj.GetName()
+}
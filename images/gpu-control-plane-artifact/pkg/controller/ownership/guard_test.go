@@ -0,0 +1,229 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownership
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+)
+
+var (
+	instanceA = Identity{Namespace: "d8-gpu", Name: "gpu-control-plane-controller-leader-election"}
+	instanceB = Identity{Namespace: "d8-gpu-migration", Name: "gpu-control-plane-controller-leader-election"}
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	if err := coordinationv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add coordination scheme: %v", err)
+	}
+	return scheme
+}
+
+func renewHeartbeat(t *testing.T, cl client.Client, self Identity, now time.Time) {
+	t.Helper()
+	hb := NewHeartbeat(logr.Discard(), cl, cl, self, "pod")
+	hb.now = func() time.Time { return now }
+	if err := hb.Renew(context.Background()); err != nil {
+		t.Fatalf("renew heartbeat of %s: %v", self, err)
+	}
+}
+
+func newTestGuard(cl client.Reader, self Identity, forceAdopt bool, now time.Time) *Guard {
+	g := NewGuard(logr.Discard(), self, cl, forceAdopt)
+	g.now = func() time.Time { return now }
+	return g
+}
+
+func claimed(obj metav1.Object, by Identity) {
+	obj.SetAnnotations(map[string]string{annotations.ControllerInstance: by.String()})
+}
+
+func TestParseIdentity(t *testing.T) {
+	id, ok := ParseIdentity(" d8-gpu/leader ")
+	if !ok || id != (Identity{Namespace: "d8-gpu", Name: "leader"}) {
+		t.Fatalf("unexpected identity %+v (ok=%v)", id, ok)
+	}
+	for _, raw := range []string{"", "d8-gpu", "/leader", "d8-gpu/"} {
+		if _, ok := ParseIdentity(raw); ok {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+	if key := id.LeaseKey(); key.Namespace != "d8-gpu" || key.Name != "leader"+LeaseSuffix {
+		t.Fatalf("unexpected lease key %s", key)
+	}
+}
+
+func TestNilGuardAllowsEverything(t *testing.T) {
+	var g *Guard
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	claimed(node, instanceB)
+	allowed, err := g.MayWrite(context.Background(), node)
+	if err != nil || !allowed {
+		t.Fatalf("nil guard must allow writes, got %v, %v", allowed, err)
+	}
+	if g.Stamp(node) {
+		t.Fatalf("nil guard must not stamp")
+	}
+}
+
+func TestStamp(t *testing.T) {
+	g := newTestGuard(nil, instanceA, false, time.Now())
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Annotations: map[string]string{"keep": "me"}}}
+	if !g.Stamp(obj) {
+		t.Fatalf("expected first stamp to change annotations")
+	}
+	if obj.Annotations[annotations.ControllerInstance] != instanceA.String() || obj.Annotations["keep"] != "me" {
+		t.Fatalf("unexpected annotations %v", obj.Annotations)
+	}
+	if g.Stamp(obj) {
+		t.Fatalf("expected second stamp to be a no-op")
+	}
+}
+
+func TestTwoInstancesOnlyClaimHolderWrites(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cl := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	renewHeartbeat(t, cl, instanceA, now)
+	renewHeartbeat(t, cl, instanceB, now)
+
+	guardA := newTestGuard(cl, instanceA, false, now)
+	guardB := newTestGuard(cl, instanceB, false, now)
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	for name, g := range map[string]*Guard{"A": guardA, "B": guardB} {
+		if allowed, err := g.MayWrite(context.Background(), obj); err != nil || !allowed {
+			t.Fatalf("instance %s must be allowed to claim an unclaimed object, got %v, %v", name, allowed, err)
+		}
+	}
+
+	guardA.Stamp(obj)
+	if allowed, err := guardA.MayWrite(context.Background(), obj); err != nil || !allowed {
+		t.Fatalf("claim holder must keep writing, got %v, %v", allowed, err)
+	}
+	if allowed, err := guardB.MayWrite(context.Background(), obj); err != nil || allowed {
+		t.Fatalf("second instance must not write an object claimed by a live instance, got %v, %v", allowed, err)
+	}
+}
+
+func TestStaleClaimIsAdopted(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cl := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	renewHeartbeat(t, cl, instanceA, now.Add(-DefaultHeartbeatDuration-time.Second))
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	claimed(obj, instanceA)
+
+	guardB := newTestGuard(cl, instanceB, false, now)
+	if allowed, err := guardB.MayWrite(context.Background(), obj); err != nil || !allowed {
+		t.Fatalf("expected expired claim to be adopted, got %v, %v", allowed, err)
+	}
+
+	missing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	claimed(missing, Identity{Namespace: "gone", Name: "leader"})
+	if allowed, err := guardB.MayWrite(context.Background(), missing); err != nil || !allowed {
+		t.Fatalf("expected claim without heartbeat lease to be adopted, got %v, %v", allowed, err)
+	}
+}
+
+func TestForceAdopt(t *testing.T) {
+	now := time.Now()
+	cl := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	renewHeartbeat(t, cl, instanceA, now)
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	claimed(obj, instanceA)
+
+	guardB := newTestGuard(cl, instanceB, true, now)
+	if allowed, err := guardB.MayWrite(context.Background(), obj); err != nil || !allowed {
+		t.Fatalf("expected force-adopt to take over a live claim, got %v, %v", allowed, err)
+	}
+	if !guardB.Stamp(obj) || obj.Annotations[annotations.ControllerInstance] != instanceB.String() {
+		t.Fatalf("expected claim to move to %s, got %v", instanceB, obj.Annotations)
+	}
+}
+
+func TestMalformedClaimIsIgnored(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Annotations: map[string]string{annotations.ControllerInstance: "garbage"}}}
+	guard := newTestGuard(nil, instanceA, false, time.Now())
+	if allowed, err := guard.MayWrite(context.Background(), obj); err != nil || !allowed {
+		t.Fatalf("expected malformed claim to be ignored, got %v, %v", allowed, err)
+	}
+}
+
+type countingReader struct {
+	client.Reader
+	gets int
+	err  error
+}
+
+func (r *countingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	r.gets++
+	if r.err != nil {
+		return r.err
+	}
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func TestLivenessIsCached(t *testing.T) {
+	now := time.Now()
+	cl := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	renewHeartbeat(t, cl, instanceA, now)
+	reader := &countingReader{Reader: cl}
+	guardB := newTestGuard(reader, instanceB, false, now)
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	claimed(obj, instanceA)
+	for i := 0; i < 3; i++ {
+		if allowed, _ := guardB.MayWrite(context.Background(), obj); allowed {
+			t.Fatalf("expected live claim to block writes")
+		}
+	}
+	if reader.gets != 1 {
+		t.Fatalf("expected a single lease read within the cache TTL, got %d", reader.gets)
+	}
+
+	guardB.now = func() time.Time { return now.Add(livenessCacheTTL) }
+	_, _ = guardB.MayWrite(context.Background(), obj)
+	if reader.gets != 2 {
+		t.Fatalf("expected lease to be re-read after the cache TTL, got %d", reader.gets)
+	}
+}
+
+func TestLeaseReadErrorIsReturned(t *testing.T) {
+	reader := &countingReader{err: errors.New("boom")}
+	guard := newTestGuard(reader, instanceB, false, time.Now())
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	claimed(obj, instanceA)
+	if allowed, err := guard.MayWrite(context.Background(), obj); err == nil || allowed {
+		t.Fatalf("expected lease read error to block the write, got %v, %v", allowed, err)
+	}
+}
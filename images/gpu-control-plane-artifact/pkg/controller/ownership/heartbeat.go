@@ -0,0 +1,122 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultHeartbeatInterval = 10 * time.Second
+	DefaultHeartbeatDuration = 40 * time.Second
+)
+
+// Heartbeat renews the instance Lease other installations consult before touching objects claimed by this one.
+// It runs on every replica: the instance is alive as long as any of its pods is.
+type Heartbeat struct {
+	log      logr.Logger
+	client   client.Client
+	reader   client.Reader
+	self     Identity
+	holder   string
+	interval time.Duration
+	duration time.Duration
+	now      func() time.Time
+}
+
+// NewHeartbeat creates the heartbeat runnable; holder identifies the replica renewing the Lease.
+func NewHeartbeat(log logr.Logger, c client.Client, reader client.Reader, self Identity, holder string) *Heartbeat {
+	return &Heartbeat{
+		log:      log,
+		client:   c,
+		reader:   reader,
+		self:     self,
+		holder:   holder,
+		interval: DefaultHeartbeatInterval,
+		duration: DefaultHeartbeatDuration,
+		now:      time.Now,
+	}
+}
+
+// NeedLeaderElection makes standby replicas keep the instance alive during leader failover.
+func (h *Heartbeat) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the Lease until the context is cancelled.
+func (h *Heartbeat) Start(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.Renew(ctx); err != nil {
+			h.log.Error(err, "failed to renew instance heartbeat lease", "lease", h.self.LeaseKey().String())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Renew creates or refreshes the heartbeat Lease.
+func (h *Heartbeat) Renew(ctx context.Context) error {
+	key := h.self.LeaseKey()
+	now := metav1.NewMicroTime(h.now())
+	duration := ptr.To(int32(h.duration / time.Second))
+
+	lease := &coordinationv1.Lease{}
+	err := h.reader.Get(ctx, key, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(h.holder),
+				LeaseDurationSeconds: duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := h.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("create lease %s: %w", key, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get lease %s: %w", key, err)
+	}
+
+	lease.Spec.HolderIdentity = ptr.To(h.holder)
+	lease.Spec.LeaseDurationSeconds = duration
+	lease.Spec.RenewTime = &now
+	if err := h.client.Update(ctx, lease); err != nil {
+		// Another replica renewed first; the next tick retries with a fresh copy.
+		if apierrors.IsConflict(err) {
+			return nil
+		}
+		return fmt.Errorf("update lease %s: %w", key, err)
+	}
+	return nil
+}
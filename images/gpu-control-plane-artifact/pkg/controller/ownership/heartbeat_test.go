@@ -0,0 +1,83 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownership
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHeartbeatCreatesAndRenewsLease(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cl := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	hb := NewHeartbeat(logr.Discard(), cl, cl, instanceA, "pod-a")
+	if hb.NeedLeaderElection() {
+		t.Fatalf("heartbeat must run on every replica")
+	}
+
+	hb.now = func() time.Time { return start }
+	if err := hb.Renew(context.Background()); err != nil {
+		t.Fatalf("create lease: %v", err)
+	}
+	lease := &coordinationv1.Lease{}
+	if err := cl.Get(context.Background(), instanceA.LeaseKey(), lease); err != nil {
+		t.Fatalf("get lease: %v", err)
+	}
+	if *lease.Spec.HolderIdentity != "pod-a" || *lease.Spec.LeaseDurationSeconds != int32(DefaultHeartbeatDuration/time.Second) {
+		t.Fatalf("unexpected lease spec %+v", lease.Spec)
+	}
+	if !leaseAlive(lease, start.Add(DefaultHeartbeatDuration-time.Second)) || leaseAlive(lease, start.Add(DefaultHeartbeatDuration)) {
+		t.Fatalf("unexpected lease liveness window")
+	}
+
+	later := start.Add(time.Minute)
+	hb.now = func() time.Time { return later }
+	if err := hb.Renew(context.Background()); err != nil {
+		t.Fatalf("renew lease: %v", err)
+	}
+	if err := cl.Get(context.Background(), instanceA.LeaseKey(), lease); err != nil {
+		t.Fatalf("get lease: %v", err)
+	}
+	if !lease.Spec.RenewTime.Time.Equal(later) {
+		t.Fatalf("expected renew time %s, got %s", later, lease.Spec.RenewTime.Time)
+	}
+	if !lease.Spec.AcquireTime.Time.Equal(start) {
+		t.Fatalf("expected acquire time to be kept, got %s", lease.Spec.AcquireTime.Time)
+	}
+}
+
+func TestHeartbeatStartStopsOnCancel(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	hb := NewHeartbeat(logr.Discard(), cl, cl, instanceA, "pod-a")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := hb.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := cl.Get(context.Background(), instanceA.LeaseKey(), &coordinationv1.Lease{}); err != nil {
+		t.Fatalf("expected lease renewed before exit: %v", err)
+	}
+}
+
+func TestLeaseAliveRequiresRenewAndDuration(t *testing.T) {
+	if leaseAlive(&coordinationv1.Lease{}, time.Now()) {
+		t.Fatalf("lease without renew time must not be alive")
+	}
+}
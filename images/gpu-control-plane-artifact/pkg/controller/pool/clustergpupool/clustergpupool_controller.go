@@ -29,13 +29,16 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 	cgphandler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/clustergpupool/internal/handler"
 	cgpwebhook "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/clustergpupool/internal/webhook"
+	poolshared "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/shared"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 	pooladmission "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/admission"
 	poolcompat "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/compatibility"
 	poolconfig "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
 	pooldpvalidation "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/dpvalidation"
+	poolnodelabels "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/nodelabels"
 	poolnodemark "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/nodemark"
 	poolselection "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/selection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
 )
 
@@ -47,6 +50,7 @@ func SetupController(
 	log logr.Logger,
 	cfg config.ControllerConfig,
 	store *moduleconfig.ModuleConfigStore,
+	shared poolshared.Dependencies,
 ) error {
 	baseLog := log.WithName("cluster-gpupool")
 
@@ -55,15 +59,26 @@ func SetupController(
 	if store != nil {
 		state := store.Current()
 		workloadCfg.CustomTolerationKeys = state.Settings.Placement.CustomTolerationKeys
+		workloadCfg.MigrateFromGPUOperator = state.Settings.Migration.FromGPUOperator
+		workloadCfg.AdoptExisting = state.Settings.Migration.AdoptExisting
+		workloadCfg.LegacyPrivileged = state.Settings.Security.LegacyPrivileged
 	}
+	workloadCfg.ImageResolver = shared.ImageResolver.Current
+
+	recorder := eventrecord.NewEventRecorderLogger(mgr, ControllerName)
+	selection := poolselection.NewSelectionSyncHandler(baseLog.WithName("selection-sync"), client)
+	selection.SetRecorder(recorder)
+	dpValidation := pooldpvalidation.NewDPValidationHandler(baseLog.WithName("dp-validation"), client)
+	dpValidation.SetRecorder(recorder)
 
 	handlers := []Handler{
+		cgphandler.WrapPoolHandler(poolnodelabels.NewNodeLabelsHandler(baseLog.WithName("node-labels"), client, store, shared.NodeLabelLimiter)),
 		cgphandler.WrapPoolHandler(poolcompat.NewCompatibilityCheckHandler()),
 		cgphandler.WrapPoolHandler(poolconfig.NewConfigCheckHandler(client)),
-		cgphandler.WrapPoolHandler(poolselection.NewSelectionSyncHandler(baseLog.WithName("selection-sync"), client)),
+		cgphandler.WrapPoolHandler(selection),
 		cgphandler.WrapPoolHandler(poolnodemark.NewNodeMarkHandler(baseLog.WithName("node-mark"), client)),
-		cgphandler.WrapPoolHandler(cgphandler.NewWorkloadHandler(baseLog.WithName("workload"), client, workloadCfg)),
-		cgphandler.WrapPoolHandler(pooldpvalidation.NewDPValidationHandler(baseLog.WithName("dp-validation"), client)),
+		cgphandler.WrapPoolHandler(cgphandler.NewWorkloadHandler(baseLog.WithName("workload"), client, workloadCfg, recorder, shared.Guard)),
+		cgphandler.WrapPoolHandler(dpValidation),
 	}
 
 	workers := cfg.Workers
@@ -71,7 +86,7 @@ func SetupController(
 		workers = 1
 	}
 
-	r := NewReconciler(baseLog, cfg, store, handlers)
+	r := NewReconciler(baseLog, cfg, store, handlers, shared.Guard)
 
 	c, err := controller.New(ControllerName, mgr, controller.Options{
 		Reconciler:              r,
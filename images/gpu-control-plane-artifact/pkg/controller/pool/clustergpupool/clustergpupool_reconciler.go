@@ -30,6 +30,7 @@ import (
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	cgpstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/clustergpupool/internal/state"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/clustergpupool/internal/watcher"
 	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
@@ -51,9 +52,10 @@ type Reconciler struct {
 	cfg      config.ControllerConfig
 	store    *moduleconfig.ModuleConfigStore
 	handlers []Handler
+	guard    *ownership.Guard
 }
 
-func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []Handler) *Reconciler {
+func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []Handler, guard *ownership.Guard) *Reconciler {
 	if cfg.Workers <= 0 {
 		cfg.Workers = 1
 	}
@@ -62,6 +64,7 @@ func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleco
 		log:      log,
 		store:    store,
 		handlers: handlers,
+		guard:    guard,
 	}
 }
 
@@ -84,6 +87,8 @@ func (r *Reconciler) SetupController(_ context.Context, mgr manager.Manager, ctr
 	for _, w := range []Watcher{
 		watchers.NewClusterGPUPoolGPUDeviceWatcher(r.log.WithName("watcher.device")),
 		watchers.NewClusterGPUPoolValidatorPodWatcher(r.log.WithName("watcher.validatorPod")),
+		watchers.NewClusterGPUPoolWorkloadWatcher(r.log.WithName("watcher.workload")),
+		watchers.NewModulePauseWatcher(r.log.WithName("watcher.modulePause"), r.store, func() client.ObjectList { return &v1alpha1.ClusterGPUPoolList{} }),
 	} {
 		if err := w.Watch(mgr, ctr); err != nil {
 			return fmt.Errorf("failed to run watcher %s: %w", reflect.TypeOf(w).Elem().Name(), err)
@@ -100,6 +105,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	resource := ctrlreconciler.NewResource(
 		req.NamespacedName,
 		r.client,
+		r.guard,
 		func() *v1alpha1.ClusterGPUPool { return &v1alpha1.ClusterGPUPool{} },
 		func(obj *v1alpha1.ClusterGPUPool) v1alpha1.GPUPoolStatus { return obj.Status },
 	)
@@ -112,6 +118,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	clusterPool := resource.Changed()
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping ClusterGPUPool reconciliation")
+		ctrlreconciler.MarkModulePaused(clusterPool)
+		return reconcile.Result{}, resource.Update(ctx)
+	}
+	ctrlreconciler.ClearModulePaused(clusterPool)
 
 	pool := &v1alpha1.GPUPool{
 		TypeMeta:   clusterPool.TypeMeta,
@@ -130,6 +142,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	})
 	rec.SetResourceUpdater(func(ctx context.Context) error {
 		clusterPool.Status = pool.Status
+		clusterPool.Finalizers = pool.Finalizers
 		return resource.Update(ctx)
 	})
 
@@ -69,7 +69,7 @@ func newScheme(t *testing.T) *runtime.Scheme {
 }
 
 func TestNewNormalisesWorkers(t *testing.T) {
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 0}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 0}, nil, nil, nil)
 	if rec.cfg.Workers != 1 {
 		t.Fatalf("expected workers defaulted to 1, got %d", rec.cfg.Workers)
 	}
@@ -87,7 +87,7 @@ func TestReconcileClusterPoolPersistsStatus(t *testing.T) {
 	handlerA := &stubHandler{name: "a", result: reconcile.Result{Requeue: true}}
 	handlerB := &stubHandler{name: "b", result: reconcile.Result{RequeueAfter: time.Second}}
 
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handlerA, handlerB})
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handlerA, handlerB}, nil)
 	rec.client = cl
 
 	res, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Name: "pool"}})
@@ -114,7 +114,7 @@ func TestReconcileNotFound(t *testing.T) {
 	scheme := newScheme(t)
 	cl := clientfake.NewClientBuilder().WithScheme(scheme).Build()
 
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = cl
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Name: "missing"}}); err != nil {
@@ -128,7 +128,7 @@ func TestReconcileHandlerError(t *testing.T) {
 	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).WithStatusSubresource(pool).Build()
 
 	handler := &stubHandler{name: "boom", err: errors.New("handler fail")}
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handler})
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handler}, nil)
 	rec.client = cl
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Name: "pool"}}); err == nil {
@@ -140,7 +140,7 @@ func TestReconcileHandlerError(t *testing.T) {
 }
 
 func TestReconcileGetError(t *testing.T) {
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = &failingClient{err: errors.New("get fail")}
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Name: "pool"}}); err == nil {
@@ -149,7 +149,7 @@ func TestReconcileGetError(t *testing.T) {
 }
 
 func TestReconcileWrapsAPIError(t *testing.T) {
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = &failingClient{err: apierrors.NewConflict(schema.GroupResource{Group: v1alpha1.GroupVersion.Group, Resource: "clustergpupools"}, "pool", errors.New("boom"))}
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Name: "pool"}}); err == nil {
@@ -32,7 +32,7 @@ func PoolPredicates() predicate.TypedPredicate[*v1alpha1.ClusterGPUPool] {
 			if oldPool == nil || newPool == nil {
 				return true
 			}
-			return !poolSpecEqual(oldPool.Spec, newPool.Spec)
+			return !poolSpecEqual(oldPool.Spec, newPool.Spec) || !oldPool.DeletionTimestamp.Equal(newPool.DeletionTimestamp)
 		},
 		DeleteFunc:  func(event.TypedDeleteEvent[*v1alpha1.ClusterGPUPool]) bool { return true },
 		GenericFunc: func(event.TypedGenericEvent[*v1alpha1.ClusterGPUPool]) bool { return false },
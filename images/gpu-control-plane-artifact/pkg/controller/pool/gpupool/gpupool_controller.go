@@ -30,13 +30,17 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 	gphandler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/gpupool/internal/handler"
 	gpwebhook "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/gpupool/internal/webhook"
+	poolshared "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/shared"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 	pooladmission "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/admission"
 	poolcompat "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/compatibility"
 	poolconfig "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
 	pooldpvalidation "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/dpvalidation"
+	poolnodelabels "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/nodelabels"
 	poolnodemark "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/nodemark"
+	poolresourcename "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/resourcename"
 	poolselection "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/selection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
 )
 
@@ -48,6 +52,7 @@ func SetupController(
 	log logr.Logger,
 	cfg config.ControllerConfig,
 	store *moduleconfig.ModuleConfigStore,
+	shared poolshared.Dependencies,
 ) error {
 	baseLog := log.WithName("gpupool")
 
@@ -56,15 +61,29 @@ func SetupController(
 	if store != nil {
 		state := store.Current()
 		workloadCfg.CustomTolerationKeys = state.Settings.Placement.CustomTolerationKeys
+		workloadCfg.MigrateFromGPUOperator = state.Settings.Migration.FromGPUOperator
+		workloadCfg.AdoptExisting = state.Settings.Migration.AdoptExisting
+		workloadCfg.LegacyPrivileged = state.Settings.Security.LegacyPrivileged
 	}
+	workloadCfg.ImageResolver = shared.ImageResolver.Current
+
+	recorder := eventrecord.NewEventRecorderLogger(mgr, ControllerName)
+	selection := poolselection.NewSelectionSyncHandler(baseLog.WithName("selection-sync"), client)
+	selection.SetRecorder(recorder)
+	dpValidation := pooldpvalidation.NewDPValidationHandler(baseLog.WithName("dp-validation"), client)
+	dpValidation.SetRecorder(recorder)
+	resourceNames := poolresourcename.NewConflictHandler(client, store)
+	resourceNames.SetRecorder(recorder)
 
 	handlers := []Handler{
+		gphandler.WrapPoolHandler(poolnodelabels.NewNodeLabelsHandler(baseLog.WithName("node-labels"), client, store, shared.NodeLabelLimiter)),
 		gphandler.WrapPoolHandler(poolcompat.NewCompatibilityCheckHandler()),
 		gphandler.WrapPoolHandler(poolconfig.NewConfigCheckHandler(client)),
-		gphandler.WrapPoolHandler(poolselection.NewSelectionSyncHandler(baseLog.WithName("selection-sync"), client)),
+		gphandler.WrapPoolHandler(resourceNames),
+		gphandler.WrapPoolHandler(selection),
 		gphandler.WrapPoolHandler(poolnodemark.NewNodeMarkHandler(baseLog.WithName("node-mark"), client)),
-		gphandler.WrapPoolHandler(gphandler.NewWorkloadHandler(baseLog.WithName("workload"), client, workloadCfg)),
-		gphandler.WrapPoolHandler(pooldpvalidation.NewDPValidationHandler(baseLog.WithName("dp-validation"), client)),
+		gphandler.WrapPoolHandler(gphandler.NewWorkloadHandler(baseLog.WithName("workload"), client, workloadCfg, recorder, shared.Guard)),
+		gphandler.WrapPoolHandler(dpValidation),
 	}
 
 	workers := cfg.Workers
@@ -72,7 +91,7 @@ func SetupController(
 		workers = 1
 	}
 
-	r := NewReconciler(baseLog, cfg, store, handlers)
+	r := NewReconciler(baseLog, cfg, store, handlers, shared.Guard)
 
 	c, err := controller.New(ControllerName, mgr, controller.Options{
 		Reconciler:              r,
@@ -105,7 +124,7 @@ func SetupController(
 
 		if err := builder.WebhookManagedBy(mgr).
 			For(&corev1.Pod{}).
-			WithDefaulter(gpwebhook.NewPodDefaulter(baseLog, store, client)).
+			WithDefaulter(gpwebhook.NewPodDefaulter(baseLog, store, client, eventrecord.NewEventRecorderLogger(mgr, ControllerName))).
 			WithValidator(gpwebhook.NewPodValidator(baseLog, client)).
 			Complete(); err != nil {
 			return err
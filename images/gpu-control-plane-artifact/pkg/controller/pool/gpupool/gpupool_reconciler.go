@@ -31,9 +31,11 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	gpstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/gpupool/internal/state"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/gpupool/internal/watcher"
 	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	poolresourcename "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/resourcename"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/watchers"
 )
 
@@ -52,9 +54,10 @@ type Reconciler struct {
 	cfg      config.ControllerConfig
 	store    *moduleconfig.ModuleConfigStore
 	handlers []Handler
+	guard    *ownership.Guard
 }
 
-func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []Handler) *Reconciler {
+func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleconfig.ModuleConfigStore, handlers []Handler, guard *ownership.Guard) *Reconciler {
 	if cfg.Workers <= 0 {
 		cfg.Workers = 1
 	}
@@ -64,6 +67,7 @@ func NewReconciler(log logr.Logger, cfg config.ControllerConfig, store *moduleco
 		cfg:      cfg,
 		store:    store,
 		handlers: handlers,
+		guard:    guard,
 	}
 }
 
@@ -75,10 +79,12 @@ func (r *Reconciler) SetupController(ctx context.Context, mgr manager.Manager, c
 	if idx := mgr.GetFieldIndexer(); idx != nil {
 		for _, getter := range []indexer.IndexGetter{
 			indexer.IndexNodeByTaintKey,
+			indexer.IndexGPUDeviceByUUID,
 			indexer.IndexGPUDeviceByPoolRefName,
 			indexer.IndexGPUDeviceByNamespacedAssignment,
 			indexer.IndexGPUDeviceByClusterAssignment,
 			indexer.IndexGPUPoolByName,
+			indexer.IndexGPUPoolByResourceName,
 		} {
 			obj, field, extract := getter()
 			if err := idx.IndexField(ctx, obj, field, extract); err != nil {
@@ -97,10 +103,17 @@ func (r *Reconciler) SetupController(ctx context.Context, mgr manager.Manager, c
 	); err != nil {
 		return fmt.Errorf("error setting watch on GPUPool: %w", err)
 	}
+	if err := ctr.Watch(
+		source.Kind(c, &v1alpha1.GPUPool{}, watcher.ResourceNamePeers(r.log.WithName("watcher.resourceName"), r.client), watcher.PoolPredicates()),
+	); err != nil {
+		return fmt.Errorf("error setting resource name watch on GPUPool: %w", err)
+	}
 
 	for _, w := range []Watcher{
 		watchers.NewGPUPoolGPUDeviceWatcher(r.log.WithName("watcher.device")),
 		watchers.NewGPUPoolValidatorPodWatcher(r.log.WithName("watcher.validatorPod")),
+		watchers.NewGPUPoolWorkloadWatcher(r.log.WithName("watcher.workload")),
+		watchers.NewModulePauseWatcher(r.log.WithName("watcher.modulePause"), r.store, func() client.ObjectList { return &v1alpha1.GPUPoolList{} }),
 	} {
 		if err := w.Watch(mgr, ctr); err != nil {
 			return fmt.Errorf("failed to run watcher %s: %w", reflect.TypeOf(w).Elem().Name(), err)
@@ -117,6 +130,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	resource := ctrlreconciler.NewResource(
 		req.NamespacedName,
 		r.client,
+		r.guard,
 		func() *v1alpha1.GPUPool { return &v1alpha1.GPUPool{} },
 		func(obj *v1alpha1.GPUPool) v1alpha1.GPUPoolStatus { return obj.Status },
 	)
@@ -125,10 +139,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 	if resource.IsEmpty() {
 		log.V(2).Info("GPUPool removed")
+		poolresourcename.Forget(req.Namespace, req.Name)
 		return reconcile.Result{}, nil
 	}
 
 	pool := resource.Changed()
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping GPUPool reconciliation")
+		ctrlreconciler.MarkModulePaused(pool)
+		return reconcile.Result{}, resource.Update(ctx)
+	}
+	ctrlreconciler.ClearModulePaused(pool)
+
 	s := gpstate.New(r.client, pool)
 
 	rec := ctrlreconciler.NewBaseReconciler(r.handlers)
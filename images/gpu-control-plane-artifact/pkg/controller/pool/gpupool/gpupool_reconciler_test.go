@@ -22,16 +22,20 @@ import (
 
 	"github.com/go-logr/logr/testr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 	gpstate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/gpupool/internal/state"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 )
 
 type failingClient struct {
@@ -69,7 +73,7 @@ func newScheme(t *testing.T) *runtime.Scheme {
 }
 
 func TestNewNormalisesWorkers(t *testing.T) {
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 0}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 0}, nil, nil, nil)
 	if rec.cfg.Workers != 1 {
 		t.Fatalf("expected workers defaulted to 1, got %d", rec.cfg.Workers)
 	}
@@ -87,7 +91,7 @@ func TestReconcileAggregatesResultsAndPersistsStatus(t *testing.T) {
 	handlerA := &stubHandler{name: "a", result: reconcile.Result{Requeue: true}}
 	handlerB := &stubHandler{name: "b", result: reconcile.Result{RequeueAfter: time.Second}}
 
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handlerA, handlerB})
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handlerA, handlerB}, nil)
 	rec.client = cl
 
 	res, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "pool"}})
@@ -116,7 +120,7 @@ func TestReconcileHandlerError(t *testing.T) {
 	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).WithStatusSubresource(pool).Build()
 
 	handler := &stubHandler{name: "boom", err: errors.New("handler fail")}
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handler})
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, []Handler{handler}, nil)
 	rec.client = cl
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "pool"}}); err == nil {
@@ -131,7 +135,7 @@ func TestReconcileNotFound(t *testing.T) {
 	scheme := newScheme(t)
 	cl := clientfake.NewClientBuilder().WithScheme(scheme).Build()
 
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = cl
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "missing"}}); err != nil {
@@ -140,7 +144,7 @@ func TestReconcileNotFound(t *testing.T) {
 }
 
 func TestReconcileGetError(t *testing.T) {
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = &failingClient{err: errors.New("get fail")}
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "pool"}}); err == nil {
@@ -149,10 +153,67 @@ func TestReconcileGetError(t *testing.T) {
 }
 
 func TestReconcileWrapsAPIError(t *testing.T) {
-	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil)
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, nil, nil, nil)
 	rec.client = &failingClient{err: apierrors.NewConflict(schema.GroupResource{Group: v1alpha1.GroupVersion.Group, Resource: "gpupools"}, "pool", errors.New("boom"))}
 
 	if _, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "pool"}}); err == nil {
 		t.Fatalf("expected API error")
 	}
 }
+
+func TestReconcilePausedSkipsHandlersWithoutWrites(t *testing.T) {
+	scheme := newScheme(t)
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"}}
+	writes := 0
+	cl := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pool).
+		WithStatusSubresource(pool).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				writes++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, sub string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				writes++
+				return c.SubResource(sub).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	state := moduleconfig.DefaultState()
+	state.Enabled = true
+	state.Paused = true
+	store := moduleconfig.NewModuleConfigStore(state)
+	handler := &stubHandler{name: "paused"}
+	rec := NewReconciler(testr.New(t), config.ControllerConfig{}, store, []Handler{handler}, nil)
+	rec.client = cl
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "ns", Name: "pool"}}
+
+	for i := 0; i < 3; i++ {
+		res, err := rec.Reconcile(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Requeue || res.RequeueAfter != 0 {
+			t.Fatalf("expected paused reconcile not to requeue, got %+v", res)
+		}
+		if i == 0 {
+			writes = 0
+		}
+	}
+	if writes != 0 {
+		t.Fatalf("expected zero writes once ModulePaused is recorded, got %d", writes)
+	}
+	if handler.calls != 0 {
+		t.Fatalf("expected handlers skipped while paused, got %d", handler.calls)
+	}
+
+	updated := &v1alpha1.GPUPool{}
+	if err := cl.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	if cond := apimeta.FindStatusCondition(updated.Status.Conditions, reconciler.ConditionModulePaused); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("expected ModulePaused=True, got %+v", updated.Status.Conditions)
+	}
+}
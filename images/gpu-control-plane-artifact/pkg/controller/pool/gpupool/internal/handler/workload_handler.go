@@ -22,9 +22,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
 	poolworkload "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/workload"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
 // WorkloadHandler reconciles per-pool workloads (device-plugin, validator, MIG manager).
@@ -32,10 +34,11 @@ type WorkloadHandler struct {
 	deps deps.Deps
 }
 
-func NewWorkloadHandler(log logr.Logger, c client.Client, cfg config.WorkloadConfig) *WorkloadHandler {
-	return &WorkloadHandler{
-		deps: poolworkload.NewDeps(log, c, cfg),
-	}
+func NewWorkloadHandler(log logr.Logger, c client.Client, cfg config.WorkloadConfig, recorder eventrecord.EventRecorderLogger, guard *ownership.Guard) *WorkloadHandler {
+	d := poolworkload.NewDeps(log, c, cfg)
+	d.Recorder = recorder
+	d.Guard = guard
+	return &WorkloadHandler{deps: d}
 }
 
 func (h *WorkloadHandler) Name() string {
@@ -17,6 +17,7 @@ package watcher
 import (
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
@@ -44,6 +45,17 @@ func TestPoolPredicates(t *testing.T) {
 	if !pred.Update(event.TypedUpdateEvent[*v1alpha1.GPUPool]{ObjectOld: old, ObjectNew: newDiff}) {
 		t.Fatalf("expected update with spec change to trigger")
 	}
+	newReqs := old.DeepCopy()
+	newReqs.Spec.Requirements = &v1alpha1.GPUPoolRequirements{MinDriverVersion: "535.104.05"}
+	if !pred.Update(event.TypedUpdateEvent[*v1alpha1.GPUPool]{ObjectOld: old, ObjectNew: newReqs}) {
+		t.Fatalf("expected requirements change to trigger re-evaluation")
+	}
+	deleting := old.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	if !pred.Update(event.TypedUpdateEvent[*v1alpha1.GPUPool]{ObjectOld: old, ObjectNew: deleting}) {
+		t.Fatalf("expected deletion mark to trigger")
+	}
 	if !pred.Update(event.TypedUpdateEvent[*v1alpha1.GPUPool]{ObjectOld: nil, ObjectNew: newDiff}) {
 		t.Fatalf("expected update with nil old to pass through")
 	}
@@ -0,0 +1,50 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/names"
+)
+
+// ResourceNamePeers enqueues the other GPUPools advertising the same extended resource name, so a pool blocked by
+// a resource name conflict is re-checked as soon as the pool holding the name is renamed, moved or deleted.
+func ResourceNamePeers(log logr.Logger, c client.Client) handler.TypedEventHandler[*v1alpha1.GPUPool] {
+	return handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, pool *v1alpha1.GPUPool) []reconcile.Request {
+		list := &v1alpha1.GPUPoolList{}
+		if err := c.List(ctx, list, client.MatchingFields{indexer.GPUPoolResourceNameField: names.PoolResourceName(pool)}); err != nil {
+			log.Error(err, "list GPUPools by resource name", "pool", pool.Name, "namespace", pool.Namespace)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for i := range list.Items {
+			peer := &list.Items[i]
+			if peer.Namespace == pool.Namespace && peer.Name == pool.Name {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: peer.Namespace, Name: peer.Name}})
+		}
+		return reqs
+	})
+}
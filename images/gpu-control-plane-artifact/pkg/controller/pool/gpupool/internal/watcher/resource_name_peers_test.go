@@ -0,0 +1,73 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+func TestResourceNamePeersEnqueuesSameNamedPools(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	obj, field, extract := indexer.IndexGPUPoolByResourceName()
+	cl := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(obj, field, extract).
+		WithObjects(
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "team-a"}},
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "team-b"}},
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-b"}},
+		).
+		Build()
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	deleted := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "team-c"}}
+	ResourceNamePeers(testr.New(t), cl).Delete(context.Background(), event.TypedDeleteEvent[*v1alpha1.GPUPool]{Object: deleted}, queue)
+
+	if queue.Len() != 2 {
+		t.Fatalf("expected both same-named pools to be enqueued, got %d", queue.Len())
+	}
+	seen := map[types.NamespacedName]bool{}
+	for queue.Len() > 0 {
+		item, _ := queue.Get()
+		seen[item.(reconcile.Request).NamespacedName] = true
+		queue.Done(item)
+	}
+	if !seen[types.NamespacedName{Namespace: "team-a", Name: "pool"}] || !seen[types.NamespacedName{Namespace: "team-b", Name: "pool"}] {
+		t.Fatalf("unexpected requests %v", seen)
+	}
+
+	// The pool itself is not its own peer.
+	ResourceNamePeers(testr.New(t), cl).Create(context.Background(), event.TypedCreateEvent[*v1alpha1.GPUPool]{Object: &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-b"}}}, queue)
+	if queue.Len() != 0 {
+		t.Fatalf("expected no peers for a unique pool, got %d", queue.Len())
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+const (
+	// colocateAnnotation opts a pod into co-location hints on time-sliced pools.
+	colocateAnnotation = "gpu.deckhouse.io/colocate"
+	// eventColocationHintApplied is recorded on the pool whenever a hint is injected.
+	eventColocationHintApplied = "ColocationHintApplied"
+
+	// colocationLookupTimeout bounds the placement lookup so a cold cache cannot stall admission.
+	colocationLookupTimeout = time.Second
+	maxColocationNodes      = 5
+	colocationWeightStep    = 20
+)
+
+type colocationNode struct {
+	name     string
+	replicas int
+}
+
+// ensureColocationHint prefers nodes that already run replicas of the pod's Deployment in the pool.
+// The hint is best effort and never rejects a pod: any lookup failure leaves the pod unchanged.
+func (d *PodDefaulter) ensureColocationHint(ctx context.Context, pod *corev1.Pod, namespace string, poolRef poolRequest, pool *v1alpha1.GPUPool) {
+	if pod.Annotations[colocateAnnotation] != "true" || !d.colocationEnabled() || d.client == nil {
+		return
+	}
+	if pool == nil || pool.Spec.Resource.SlicesPerUnit <= 1 {
+		return
+	}
+	deployment, ok := deploymentOf(pod)
+	if !ok {
+		return
+	}
+
+	nodes, err := d.replicaNodes(ctx, namespace, poolRef, deployment)
+	if err != nil {
+		d.log.Info("skip colocation hint: pod placement unavailable", "pool", poolRef.name, "deployment", deployment, "error", err.Error())
+		return
+	}
+	if !addColocationAffinity(pod, nodes) {
+		return
+	}
+
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.name)
+	}
+	d.recordColocationHint(pod, namespace, poolRef, pool, deployment, names)
+}
+
+func (d *PodDefaulter) colocationEnabled() bool {
+	return d.store != nil && d.store.Current().Settings.Scheduling.ColocationHints
+}
+
+// deploymentOf resolves the Deployment behind a ReplicaSet-owned pod; the pod-template-hash suffix
+// is stripped so replicas from previous rollouts count as well.
+func deploymentOf(pod *corev1.Pod) (string, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "ReplicaSet" {
+		return "", false
+	}
+	hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if hash == "" || !strings.HasSuffix(owner.Name, "-"+hash) {
+		return "", false
+	}
+	return strings.TrimSuffix(owner.Name, "-"+hash), true
+}
+
+// replicaNodes returns nodes running scheduled replicas of the Deployment in the pool, busiest first.
+func (d *PodDefaulter) replicaNodes(ctx context.Context, namespace string, poolRef poolRequest, deployment string) ([]colocationNode, error) {
+	scope := poolcommon.PoolScopeNamespaced
+	if poolRef.keyPrefix == clusterPoolResourcePrefix {
+		scope = poolcommon.PoolScopeCluster
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, colocationLookupTimeout)
+	defer cancel()
+	pods := &corev1.PodList{}
+	if err := d.client.List(lookupCtx, pods,
+		client.InNamespace(namespace),
+		client.MatchingLabels{poolcommon.PoolNameKey: poolRef.name, poolcommon.PoolScopeKey: scope},
+	); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for i := range pods.Items {
+		replica := &pods.Items[i]
+		if replica.Spec.NodeName == "" || replica.DeletionTimestamp != nil {
+			continue
+		}
+		if replica.Status.Phase == corev1.PodSucceeded || replica.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if name, ok := deploymentOf(replica); !ok || name != deployment {
+			continue
+		}
+		counts[replica.Spec.NodeName]++
+	}
+
+	nodes := make([]colocationNode, 0, len(counts))
+	for name, replicas := range counts {
+		nodes = append(nodes, colocationNode{name: name, replicas: replicas})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].replicas != nodes[j].replicas {
+			return nodes[i].replicas > nodes[j].replicas
+		}
+		return nodes[i].name < nodes[j].name
+	})
+	if len(nodes) > maxColocationNodes {
+		nodes = nodes[:maxColocationNodes]
+	}
+	return nodes, nil
+}
+
+// addColocationAffinity appends one preferred term per node, weighted by its replica count, and
+// reports whether the pod changed. A node field selector takes a single value, hence one term each.
+func addColocationAffinity(pod *corev1.Pod, nodes []colocationNode) bool {
+	if len(nodes) == 0 {
+		return false
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	affinity := pod.Spec.Affinity.NodeAffinity
+
+	changed := false
+	for _, node := range nodes {
+		if hasNodeNamePreference(affinity.PreferredDuringSchedulingIgnoredDuringExecution, node.name) {
+			continue
+		}
+		affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.PreferredSchedulingTerm{
+			Weight: int32(min(100, colocationWeightStep*node.replicas)),
+			Preference: corev1.NodeSelectorTerm{
+				MatchFields: []corev1.NodeSelectorRequirement{{
+					Key:      metav1.ObjectNameField,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{node.name},
+				}},
+			},
+		})
+		changed = true
+	}
+	return changed
+}
+
+func hasNodeNamePreference(terms []corev1.PreferredSchedulingTerm, node string) bool {
+	for _, term := range terms {
+		for _, field := range term.Preference.MatchFields {
+			if field.Key == metav1.ObjectNameField && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 && field.Values[0] == node {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *PodDefaulter) recordColocationHint(pod *corev1.Pod, namespace string, poolRef poolRequest, pool *v1alpha1.GPUPool, deployment string, nodes []string) {
+	if d.recorder == nil {
+		return
+	}
+	var target client.Object = pool
+	if poolRef.keyPrefix == clusterPoolResourcePrefix {
+		target = &v1alpha1.ClusterGPUPool{ObjectMeta: pool.ObjectMeta}
+	}
+	podName := pod.Name
+	if podName == "" {
+		podName = pod.GenerateName
+	}
+	d.recorder.WithLogging(d.log).Eventf(
+		target,
+		corev1.EventTypeNormal,
+		eventColocationHintApplied,
+		"pod %s/%s of Deployment %s prefers nodes %s",
+		namespace,
+		podName,
+		deployment,
+		strings.Join(nodes, ", "),
+	)
+}
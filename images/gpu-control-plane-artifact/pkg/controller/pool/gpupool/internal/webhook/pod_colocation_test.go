@@ -0,0 +1,230 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
+func colocationPool() *v1alpha1.GPUPool {
+	return &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "gpu-ns"},
+		Spec: v1alpha1.GPUPoolSpec{
+			Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 4},
+			Scheduling: v1alpha1.GPUPoolSchedulingSpec{
+				Strategy:      v1alpha1.GPUPoolSchedulingBinPack,
+				TaintsEnabled: ptr.To(false),
+			},
+		},
+	}
+}
+
+func replicaPod(name, replicaSet, hash, node string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "gpu-ns",
+			Labels: map[string]string{
+				"pod-template-hash":     hash,
+				poolcommon.PoolNameKey:  "pool-a",
+				poolcommon.PoolScopeKey: poolcommon.PoolScopeNamespaced,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       replicaSet,
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func incomingReplica(annotated bool) *corev1.Pod {
+	pod := replicaPod("", "trainer-7d9f", "7d9f", "")
+	pod.GenerateName = "trainer-7d9f-"
+	delete(pod.Labels, poolcommon.PoolNameKey)
+	delete(pod.Labels, poolcommon.PoolScopeKey)
+	if annotated {
+		pod.Annotations = map[string]string{colocateAnnotation: "true"}
+	}
+	pod.Spec.Containers = []corev1.Container{{
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceName(localPoolResourcePrefix + "pool-a"): resource.MustParse("1")},
+		},
+	}}
+	return pod
+}
+
+func newColocationDefaulter(t *testing.T, enabled bool, funcs *interceptor.Funcs, objs ...client.Object) (*PodDefaulter, *record.FakeRecorder) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append([]client.Object{enabledNS("gpu-ns"), colocationPool()}, objs...)...)
+	if funcs != nil {
+		builder = builder.WithInterceptorFuncs(*funcs)
+	}
+	state := moduleconfig.DefaultState()
+	state.Settings.Scheduling.ColocationHints = enabled
+	rec := record.NewFakeRecorder(4)
+	recorder := eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: rec}, "test")
+	return NewPodDefaulter(testr.New(t), moduleconfig.NewModuleConfigStore(state), builder.Build(), recorder), rec
+}
+
+func preferredNodes(pod *corev1.Pod) map[string]int32 {
+	nodes := map[string]int32{}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return nodes
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		for _, field := range term.Preference.MatchFields {
+			if field.Key == metav1.ObjectNameField {
+				nodes[field.Values[0]] = term.Weight
+			}
+		}
+	}
+	return nodes
+}
+
+func TestPodDefaulterColocationHintPrefersReplicaNodes(t *testing.T) {
+	replicas := []client.Object{
+		replicaPod("trainer-a", "trainer-7d9f", "7d9f", "node-1"),
+		replicaPod("trainer-b", "trainer-7d9f", "7d9f", "node-1"),
+		// An older rollout of the same Deployment still counts.
+		replicaPod("trainer-c", "trainer-55aa", "55aa", "node-2"),
+		// Another Deployment and unscheduled replicas are ignored.
+		replicaPod("other-a", "other-1234", "1234", "node-3"),
+		replicaPod("trainer-d", "trainer-7d9f", "7d9f", ""),
+	}
+	d, rec := newColocationDefaulter(t, true, nil, replicas...)
+
+	pod := incomingReplica(true)
+	if err := d.Default(context.Background(), pod); err != nil {
+		t.Fatalf("Default returned error: %v", err)
+	}
+
+	nodes := preferredNodes(pod)
+	if len(nodes) != 2 || nodes["node-1"] != 2*colocationWeightStep || nodes["node-2"] != colocationWeightStep {
+		t.Fatalf("unexpected colocation preferences: %v", nodes)
+	}
+	select {
+	case event := <-rec.Events:
+		if !strings.Contains(event, eventColocationHintApplied) || !strings.Contains(event, "Deployment trainer") || !strings.Contains(event, "node-1, node-2") {
+			t.Fatalf("unexpected event: %s", event)
+		}
+	default:
+		t.Fatalf("expected a ColocationHintApplied event")
+	}
+
+	// Re-invocation does not duplicate the terms.
+	if err := d.Default(context.Background(), pod); err != nil {
+		t.Fatalf("Default returned error: %v", err)
+	}
+	if got := len(pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution); got != 2 {
+		t.Fatalf("expected idempotent mutation, got %d terms", got)
+	}
+}
+
+func TestPodDefaulterColocationHintSkips(t *testing.T) {
+	replica := replicaPod("trainer-a", "trainer-7d9f", "7d9f", "node-1")
+
+	cases := []struct {
+		name      string
+		enabled   bool
+		annotated bool
+	}{
+		{name: "without annotation", enabled: true, annotated: false},
+		{name: "disabled in module settings", enabled: false, annotated: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, rec := newColocationDefaulter(t, tc.enabled, nil, replica)
+			pod := incomingReplica(tc.annotated)
+			if err := d.Default(context.Background(), pod); err != nil {
+				t.Fatalf("Default returned error: %v", err)
+			}
+			if pod.Spec.Affinity != nil {
+				t.Fatalf("expected no affinity, got %+v", pod.Spec.Affinity)
+			}
+			if len(rec.Events) != 0 {
+				t.Fatalf("expected no events, got %d", len(rec.Events))
+			}
+		})
+	}
+}
+
+func TestPodDefaulterColocationHintFailsOpenOnColdCache(t *testing.T) {
+	funcs := &interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*corev1.PodList); ok {
+				return context.DeadlineExceeded
+			}
+			return c.List(ctx, list, opts...)
+		},
+	}
+	d, rec := newColocationDefaulter(t, true, funcs, replicaPod("trainer-a", "trainer-7d9f", "7d9f", "node-1"))
+
+	pod := incomingReplica(true)
+	if err := d.Default(context.Background(), pod); err != nil {
+		t.Fatalf("cold cache must not reject the pod, got %v", err)
+	}
+	if pod.Spec.Affinity != nil || len(rec.Events) != 0 {
+		t.Fatalf("expected the pod to be admitted without a hint, got %+v", pod.Spec.Affinity)
+	}
+	if pod.Labels[poolcommon.PoolNameKey] != "pool-a" {
+		t.Fatalf("expected the regular pool mutations to still apply, got %v", pod.Labels)
+	}
+}
+
+func TestPodDefaulterColocationHintRequiresTimeSlicing(t *testing.T) {
+	d, _ := newColocationDefaulter(t, true, nil, replicaPod("trainer-a", "trainer-7d9f", "7d9f", "node-1"))
+	pool := colocationPool()
+	pool.Spec.Resource.SlicesPerUnit = 1
+
+	pod := incomingReplica(true)
+	d.ensureColocationHint(context.Background(), pod, "gpu-ns", poolRequest{name: "pool-a", keyPrefix: localPoolResourcePrefix}, pool)
+	if pod.Spec.Affinity != nil {
+		t.Fatalf("expected no hint on an exclusive pool, got %+v", pod.Spec.Affinity)
+	}
+}
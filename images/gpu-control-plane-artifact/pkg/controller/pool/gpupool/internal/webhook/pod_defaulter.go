@@ -26,19 +26,22 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
 type PodDefaulter struct {
-	log    logr.Logger
-	store  *moduleconfig.ModuleConfigStore
-	client client.Client
+	log      logr.Logger
+	store    *moduleconfig.ModuleConfigStore
+	client   client.Client
+	recorder eventrecord.EventRecorderLogger
 }
 
-func NewPodDefaulter(log logr.Logger, store *moduleconfig.ModuleConfigStore, c client.Client) *PodDefaulter {
+func NewPodDefaulter(log logr.Logger, store *moduleconfig.ModuleConfigStore, c client.Client, recorder eventrecord.EventRecorderLogger) *PodDefaulter {
 	return &PodDefaulter{
-		log:    log.WithName("pod-webhook"),
-		store:  store,
-		client: c,
+		log:      log.WithName("pod-webhook"),
+		store:    store,
+		client:   c,
+		recorder: recorder,
 	}
 }
 
@@ -112,6 +115,7 @@ func (d *PodDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 		}
 	}
 
+	d.ensureColocationHint(ctx, pod, namespace, poolRef, poolObj)
 	ensureCustomTolerations(pod, d.store)
 	return nil
 }
@@ -47,7 +47,7 @@ func TestPodDefaulterDefaultErrorPaths(t *testing.T) {
 		},
 	}
 
-	d := NewPodDefaulter(testr.New(t), nil, nil)
+	d := NewPodDefaulter(testr.New(t), nil, nil, nil)
 	if err := d.Default(context.Background(), &corev1.Namespace{}); err == nil {
 		t.Fatalf("expected type error")
 	}
@@ -99,7 +99,7 @@ func TestPodDefaulterDefaultErrorPaths(t *testing.T) {
 		state.Settings.Scheduling.DefaultStrategy = string(v1alpha1.GPUPoolSchedulingSpread)
 		state.Settings.Scheduling.TopologyKey = "zone"
 		store := moduleconfig.NewModuleConfigStore(state)
-		d = NewPodDefaulter(testr.New(t), store, nil)
+		d = NewPodDefaulter(testr.New(t), store, nil, nil)
 
 		withConflictConstraint := pod.DeepCopy()
 		withConflictConstraint.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
@@ -142,7 +142,7 @@ func TestPodDefaulterDefaultClientErrors(t *testing.T) {
 		},
 	}
 
-	d := NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("list error")})
+	d := NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("list error")}, nil)
 	if err := d.Default(context.Background(), pod.DeepCopy()); err == nil {
 		t.Fatalf("expected node tolerations list error")
 	}
@@ -155,7 +155,7 @@ func TestPodDefaulterDefaultClientErrors(t *testing.T) {
 		t.Fatalf("update pool: %v", err)
 	}
 
-	d = NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("list error")})
+	d = NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("list error")}, nil)
 	if err := d.Default(context.Background(), pod.DeepCopy()); err == nil {
 		t.Fatalf("expected topologyLabelPresent list error")
 	}
@@ -77,7 +77,7 @@ func TestEnsureNodeTolerationsBranches(t *testing.T) {
 	}
 
 	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
-	d := NewPodDefaulter(testr.New(t), nil, cl)
+	d := NewPodDefaulter(testr.New(t), nil, cl, nil)
 
 	pod := &corev1.Pod{Spec: corev1.PodSpec{Tolerations: []corev1.Toleration{{Key: "k1", Operator: corev1.TolerationOpExists}}}}
 	if err := d.ensureNodeTolerations(context.Background(), pod, pool); err != nil {
@@ -117,7 +117,7 @@ func TestCollectPoolNodeTaintsPrefixAndListError(t *testing.T) {
 	}
 
 	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build()
-	d := NewPodDefaulter(testr.New(t), nil, base)
+	d := NewPodDefaulter(testr.New(t), nil, base, nil)
 	taints, err := d.collectPoolNodeTaints(context.Background(), pool)
 	if err != nil {
 		t.Fatalf("collectPoolNodeTaints: %v", err)
@@ -126,7 +126,7 @@ func TestCollectPoolNodeTaintsPrefixAndListError(t *testing.T) {
 		t.Fatalf("expected deduped taints, got %+v", taints)
 	}
 
-	d = NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("boom")})
+	d = NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("boom")}, nil)
 	if _, err := d.collectPoolNodeTaints(context.Background(), pool); err == nil {
 		t.Fatalf("expected list error")
 	}
@@ -137,19 +137,19 @@ func TestTopologyLabelPresentBranches(t *testing.T) {
 	_ = corev1.AddToScheme(scheme)
 
 	base := fake.NewClientBuilder().WithScheme(scheme).Build()
-	d := NewPodDefaulter(testr.New(t), nil, base)
+	d := NewPodDefaulter(testr.New(t), nil, base, nil)
 
 	ok, err := d.topologyLabelPresent(context.Background(), "gpu.deckhouse.io/pool-a", "pool-a", "")
 	if err != nil || ok {
 		t.Fatalf("expected (false,nil) for empty topologyKey, got (%v,%v)", ok, err)
 	}
 
-	d = NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("boom")})
+	d = NewPodDefaulter(testr.New(t), nil, listErrorClient{Client: base, err: errors.New("boom")}, nil)
 	if _, err := d.topologyLabelPresent(context.Background(), "gpu.deckhouse.io/pool-a", "pool-a", "zone"); err == nil {
 		t.Fatalf("expected list error")
 	}
 
-	d = NewPodDefaulter(testr.New(t), nil, base)
+	d = NewPodDefaulter(testr.New(t), nil, base, nil)
 	ok, err = d.topologyLabelPresent(context.Background(), "gpu.deckhouse.io/pool-a", "pool-a", "zone")
 	if err != nil || !ok {
 		t.Fatalf("expected (true,nil) for no nodes yet, got (%v,%v)", ok, err)
@@ -159,7 +159,7 @@ func TestTopologyLabelPresentBranches(t *testing.T) {
 	nodeWithLabel := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{poolKey: "pool-a", "zone": "a"}}}
 	nodeWithoutLabel := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n2", Labels: map[string]string{poolKey: "pool-a"}}}
 	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodeWithLabel, nodeWithoutLabel).Build()
-	d = NewPodDefaulter(testr.New(t), nil, cl)
+	d = NewPodDefaulter(testr.New(t), nil, cl, nil)
 
 	ok, err = d.topologyLabelPresent(context.Background(), poolKey, "pool-a", "zone")
 	if err != nil || !ok {
@@ -167,7 +167,7 @@ func TestTopologyLabelPresentBranches(t *testing.T) {
 	}
 
 	cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodeWithoutLabel).Build()
-	d = NewPodDefaulter(testr.New(t), nil, cl)
+	d = NewPodDefaulter(testr.New(t), nil, cl, nil)
 	ok, err = d.topologyLabelPresent(context.Background(), poolKey, "pool-a", "zone")
 	if err != nil || ok {
 		t.Fatalf("expected missing label to return false, got (%v,%v)", ok, err)
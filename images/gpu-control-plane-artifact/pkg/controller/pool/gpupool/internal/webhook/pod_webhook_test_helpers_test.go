@@ -39,7 +39,7 @@ type podMutatorAdapter struct {
 
 func newPodMutator(log logr.Logger, store *moduleconfig.ModuleConfigStore, c client.Client) *podMutatorAdapter {
 	return &podMutatorAdapter{
-		defaulter: NewPodDefaulter(log, store, c),
+		defaulter: NewPodDefaulter(log, store, c, nil),
 	}
 }
 
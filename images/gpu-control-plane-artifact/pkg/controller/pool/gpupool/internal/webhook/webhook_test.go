@@ -75,6 +75,69 @@ func TestGPUPoolDefaulterAddsDefaults(t *testing.T) {
 	}
 }
 
+func TestGPUPoolDefaulterWritesCanonicalSpecBack(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	handlers := []AdmissionHandler{pooladmission.NewPoolValidationHandler(testr.New(t))}
+
+	rawBytes, _ := json.Marshal(v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
+		Spec: v1alpha1.GPUPoolSpec{
+			Backend:  "deviceplugin",
+			Resource: v1alpha1.GPUPoolResourceSpec{Unit: "card"},
+		},
+	})
+	req := cradmission.Request{AdmissionRequest: admv1.AdmissionRequest{
+		Operation: admv1.Create,
+		Object:    runtime.RawExtension{Raw: rawBytes},
+	}}
+
+	defaulter := cradmission.WithCustomDefaulter(scheme, &v1alpha1.GPUPool{}, NewGPUPoolDefaulter(testr.New(t), handlers))
+	resp := defaulter.Handle(context.Background(), req)
+	if !resp.Allowed {
+		t.Fatalf("expected allowed response, got denied: %v", resp.Result)
+	}
+
+	patched := map[string]any{}
+	for _, p := range resp.Patches {
+		patched[p.Path] = p.Value
+	}
+	want := map[string]any{
+		"/spec/backend":                "DevicePlugin",
+		"/spec/resource/unit":          "Card",
+		"/spec/resource/slicesPerUnit": float64(1),
+	}
+	for path, value := range want {
+		if fmt.Sprint(patched[path]) != fmt.Sprint(value) {
+			t.Fatalf("expected patch %s=%v, got patches %+v", path, value, resp.Patches)
+		}
+	}
+}
+
+func TestGPUPoolDefaulterRejectsInvalidResourceName(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	handlers := []AdmissionHandler{pooladmission.NewPoolValidationHandler(testr.New(t))}
+
+	rawBytes, _ := json.Marshal(v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-with-a-name-that-does-not-fit-into-an-extended-resource-name", Namespace: "ns"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+	})
+	req := cradmission.Request{AdmissionRequest: admv1.AdmissionRequest{
+		Operation: admv1.Create,
+		Object:    runtime.RawExtension{Raw: rawBytes},
+	}}
+
+	defaulter := cradmission.WithCustomDefaulter(scheme, &v1alpha1.GPUPool{}, NewGPUPoolDefaulter(testr.New(t), handlers))
+	if resp := defaulter.Handle(context.Background(), req); resp.Allowed {
+		t.Fatalf("expected pool with an invalid extended resource name to be denied")
+	}
+}
+
 func TestGPUPoolValidatorRejectsImmutableChanges(t *testing.T) {
 	scheme := runtime.NewScheme()
 	if err := v1alpha1.AddToScheme(scheme); err != nil {
@@ -0,0 +1,51 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shared holds the state the GPUPool and ClusterGPUPool controllers share. Both controllers
+// write to the same nodes and render the same images, so one instance is built per manager and passed to both.
+package shared
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	poolimages "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/images"
+	poolnodelabels "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/nodelabels"
+)
+
+// nodeLabelWriteInterval spaces pool label writes to a single node.
+const nodeLabelWriteInterval = time.Second
+
+// Dependencies are the objects shared by the pool controllers.
+type Dependencies struct {
+	// NodeLabelLimiter throttles pool label writes per node across both controllers.
+	NodeLabelLimiter *poolnodelabels.NodeWriteLimiter
+	// ImageResolver pins the pool workload images with the current imageDigests setting and one digest cache.
+	ImageResolver *poolimages.Resolver
+	// Guard claims the pools and their workloads for this instance; nil writes them without a claim.
+	Guard *ownership.Guard
+}
+
+// NewDependencies builds the state shared by the pool controllers of one manager. The image pull secret is read
+// through reader, the imageDigests setting through store.
+func NewDependencies(reader client.Reader, store *moduleconfig.ModuleConfigStore, guard *ownership.Guard) Dependencies {
+	return Dependencies{
+		NodeLabelLimiter: poolnodelabels.NewNodeWriteLimiter(nodeLabelWriteInterval),
+		ImageResolver:    poolimages.NewResolver(reader, store),
+		Guard:            guard,
+	}
+}
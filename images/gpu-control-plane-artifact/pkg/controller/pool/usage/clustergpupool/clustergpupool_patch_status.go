@@ -17,22 +17,29 @@ package clustergpupool
 import (
 	"context"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	pustate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal/state"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
 )
 
 func (r *Reconciler) patchStatus(ctx context.Context, key types.NamespacedName, used int32) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		current := &v1alpha1.ClusterGPUPool{}
 		if err := r.client.Get(ctx, key, current); err != nil {
+			if apierrors.IsNotFound(err) {
+				capmetrics.PoolSaturationDelete(key.Name)
+			}
 			return client.IgnoreNotFound(err)
 		}
 		original := current.DeepCopy()
 
 		total := current.Status.Capacity.Total
+		updateSaturationMetric(key.Name, used, total)
 		available := total - used
 		if available < 0 {
 			available = 0
@@ -47,3 +54,13 @@ func (r *Reconciler) patchStatus(ctx context.Context, key types.NamespacedName,
 		return r.client.Status().Patch(ctx, current, client.MergeFrom(original))
 	})
 }
+
+// updateSaturationMetric refreshes gpu_pool_saturation_ratio on every reconcile, including the ones that do
+// not change the status; a pool without capacity has no ratio and its series is dropped.
+func updateSaturationMetric(pool string, used, total int32) {
+	if ratio, ok := pustate.SaturationRatio(used, total); ok {
+		capmetrics.PoolSaturationSet(pool, ratio)
+		return
+	}
+	capmetrics.PoolSaturationDelete(pool)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -26,6 +27,7 @@ import (
 	puinternal "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal"
 	pustate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal/state"
 	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
 )
 
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
@@ -36,9 +38,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.V(2).Info("module disabled, skipping cluster pool usage reconciliation")
 		return reconcile.Result{}, nil
 	}
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping cluster pool usage reconciliation")
+		return reconcile.Result{}, nil
+	}
 
 	pool := &v1alpha1.ClusterGPUPool{}
 	if err := r.client.Get(ctx, types.NamespacedName{Name: req.Name}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			capmetrics.PoolSaturationDelete(req.Name)
+		}
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
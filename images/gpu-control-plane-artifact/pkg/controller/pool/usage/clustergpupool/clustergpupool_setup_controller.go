@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -27,6 +28,7 @@ import (
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	puwatcher "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal/watcher"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/watchers"
 )
 
 func (r *Reconciler) SetupController(_ context.Context, mgr manager.Manager, ctr controller.Controller) error {
@@ -54,5 +56,10 @@ func (r *Reconciler) SetupController(_ context.Context, mgr manager.Manager, ctr
 		return fmt.Errorf("error setting watch on GPU workload Pods: %w", err)
 	}
 
+	pauseWatcher := watchers.NewModulePauseWatcher(r.log.WithName("watcher.modulePause"), r.store, func() client.ObjectList { return &v1alpha1.ClusterGPUPoolList{} })
+	if err := pauseWatcher.Watch(mgr, ctr); err != nil {
+		return fmt.Errorf("error setting watch on module pause: %w", err)
+	}
+
 	return nil
 }
@@ -32,6 +32,7 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
@@ -59,7 +60,7 @@ func TestClusterPoolUsageReconcileSkipsWhenDisabled(t *testing.T) {
 
 func TestClusterPoolUsageReconcileIgnoresNotFound(t *testing.T) {
 	scheme := newScheme(t)
-	cl := clientfake.NewClientBuilder().WithScheme(scheme).Build()
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).WithScheme(scheme).Build()
 	r := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 1}, nil)
 	r.client = cl
 	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "pool"}}); err != nil {
@@ -95,7 +96,7 @@ func TestClusterPoolUsageReconcileNoopWhenUpToDate(t *testing.T) {
 		Status: corev1.PodStatus{Phase: corev1.PodRunning},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.ClusterGPUPool{}).
 		WithObjects(pool, pod).
@@ -117,7 +118,7 @@ func TestClusterPoolUsageReconcileRetryGetNotFoundIsIgnored(t *testing.T) {
 		},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.ClusterGPUPool{}).
 		WithObjects(pool).
@@ -164,7 +165,7 @@ func TestClusterPoolUsageReconcileReturnsListError(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
 		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
 	}
-	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).Build()
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).WithScheme(scheme).WithObjects(pool).Build()
 
 	r := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 1}, nil)
 	r.client = listPodsErrorClient{Client: cl}
@@ -179,7 +180,7 @@ func TestClusterPoolUsageReconcileReturnsStatusPatchError(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "cluster-a"},
 		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
 	}
-	base := clientfake.NewClientBuilder().
+	base := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.ClusterGPUPool{}).
 		WithObjects(pool).
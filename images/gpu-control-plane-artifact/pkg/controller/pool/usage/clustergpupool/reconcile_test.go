@@ -30,6 +30,7 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
 
@@ -100,7 +101,7 @@ func TestClusterGPUPoolUsageReconcileUpdatesUsedAvailable(t *testing.T) {
 		Status: corev1.PodStatus{Phase: corev1.PodPending},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.ClusterGPUPool{}).
 		WithObjects(pool, pod1, pod2, unscheduled).
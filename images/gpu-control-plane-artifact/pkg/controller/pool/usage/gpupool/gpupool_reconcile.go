@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -25,6 +26,8 @@ import (
 	puinternal "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal"
 	pustate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal/state"
 	ctrlreconciler "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/resourcename"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
 )
 
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
@@ -35,9 +38,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.V(2).Info("module disabled, skipping pool usage reconciliation")
 		return reconcile.Result{}, nil
 	}
+	if r.store != nil && r.store.Current().Paused {
+		log.V(2).Info("module paused, skipping pool usage reconciliation")
+		return reconcile.Result{}, nil
+	}
 
 	pool := &v1alpha1.GPUPool{}
 	if err := r.client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			capmetrics.PoolSaturationDelete(resourcename.MetricPoolName(req.Namespace, req.Name))
+		}
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -32,6 +32,7 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
@@ -59,7 +60,7 @@ func TestPoolUsageReconcileSkipsWhenDisabled(t *testing.T) {
 
 func TestPoolUsageReconcileIgnoresNotFound(t *testing.T) {
 	scheme := newScheme(t)
-	cl := clientfake.NewClientBuilder().WithScheme(scheme).Build()
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).WithScheme(scheme).Build()
 	r := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 1}, nil)
 	r.client = cl
 	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "pool"}}); err != nil {
@@ -95,7 +96,7 @@ func TestPoolUsageReconcileNoopWhenUpToDate(t *testing.T) {
 		Status: corev1.PodStatus{Phase: corev1.PodRunning},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.GPUPool{}).
 		WithObjects(pool, pod).
@@ -137,7 +138,7 @@ func TestPoolUsageReconcileClampsAvailableToZeroWhenOverused(t *testing.T) {
 		Status: corev1.PodStatus{Phase: corev1.PodRunning},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.GPUPool{}).
 		WithObjects(pool, pod).
@@ -171,7 +172,7 @@ func TestPoolUsageReconcileRetryGetNotFoundIsIgnored(t *testing.T) {
 		},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.GPUPool{}).
 		WithObjects(pool).
@@ -218,7 +219,7 @@ func TestPoolUsageReconcileReturnsListError(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "ns1"},
 		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
 	}
-	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(pool).Build()
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).WithScheme(scheme).WithObjects(pool).Build()
 
 	r := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 1}, nil)
 	r.client = listPodsErrorClient{Client: cl}
@@ -233,7 +234,7 @@ func TestPoolUsageReconcileReturnsStatusPatchError(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "ns1"},
 		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
 	}
-	base := clientfake.NewClientBuilder().
+	base := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.GPUPool{}).
 		WithObjects(pool).
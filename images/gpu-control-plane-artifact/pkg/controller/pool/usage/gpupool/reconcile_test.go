@@ -16,6 +16,7 @@ package gpupool
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
@@ -27,10 +28,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
 )
 
 func newScheme(t *testing.T) *runtime.Scheme {
@@ -119,7 +123,7 @@ func TestGPUPoolUsageReconcileUpdatesUsedAvailable(t *testing.T) {
 		Status: corev1.PodStatus{Phase: corev1.PodRunning},
 	}
 
-	cl := clientfake.NewClientBuilder().
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
 		WithScheme(scheme).
 		WithStatusSubresource(&v1alpha1.GPUPool{}).
 		WithObjects(pool, pod1, unscheduled, finished, initTakesMax).
@@ -143,3 +147,122 @@ func TestGPUPoolUsageReconcileUpdatesUsedAvailable(t *testing.T) {
 		t.Fatalf("expected available=5, got %d", got.Status.Capacity.Available)
 	}
 }
+
+func saturationGauge(t *testing.T, pool string) (float64, bool) {
+	t.Helper()
+	families, err := crmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != capmetrics.PoolSaturationRatio {
+			continue
+		}
+		for _, metric := range family.Metric {
+			for _, label := range metric.Label {
+				if label.GetName() == "pool" && label.GetValue() == pool {
+					return metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func usagePod(name, namespace string, resourceName corev1.ResourceName, units string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeName: "node1",
+			Containers: []corev1.Container{{
+				Name: "c",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{resourceName: resource.MustParse(units)},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestGPUPoolUsageReconcileReportsSaturation(t *testing.T) {
+	tests := []struct {
+		name   string
+		pool   string
+		slices int32
+		total  int32
+		pods   []string
+		want   float64
+	}{
+		// Four whole cards, three attached to workloads.
+		{name: "whole card", pool: "cards", slices: 1, total: 4, pods: []string{"1", "1", "1"}, want: 0.75},
+		// Two cards split into four time slices each, two slices consumed.
+		{name: "time sliced", pool: "sliced", slices: 4, total: 8, pods: []string{"1", "1"}, want: 0.25},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := newScheme(t)
+			pool := &v1alpha1.GPUPool{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.pool, Namespace: "ns1"},
+				Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{SlicesPerUnit: tc.slices}},
+				Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: tc.total}},
+			}
+			objs := []client.Object{pool}
+			resourceName := corev1.ResourceName("gpu.deckhouse.io/" + tc.pool)
+			for i, units := range tc.pods {
+				objs = append(objs, usagePod(fmt.Sprintf("p%d", i), "ns1", resourceName, units))
+			}
+			// Pods of another pool never count towards this one.
+			objs = append(objs, usagePod("other", "ns1", "gpu.deckhouse.io/other", "1"))
+
+			cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
+				WithScheme(scheme).
+				WithStatusSubresource(&v1alpha1.GPUPool{}).
+				WithObjects(objs...).
+				Build()
+			r := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 1}, nil)
+			r.client = cl
+
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)}); err != nil {
+				t.Fatalf("reconcile: %v", err)
+			}
+			got, ok := saturationGauge(t, "ns1/"+tc.pool)
+			if !ok || got != tc.want {
+				t.Fatalf("expected saturation %v, got %v (present=%t)", tc.want, got, ok)
+			}
+		})
+	}
+}
+
+func TestGPUPoolUsageReconcileDeletesSaturationWithPool(t *testing.T) {
+	scheme := newScheme(t)
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "gone", Namespace: "ns1"},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 2}},
+	}
+	cl := clientfake.NewClientBuilder().WithIndex(indexer.IndexPodByGPUResource()).
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.GPUPool{}).
+		WithObjects(pool, usagePod("p", "ns1", "gpu.deckhouse.io/gone", "1")).
+		Build()
+	r := NewReconciler(testr.New(t), config.ControllerConfig{Workers: 1}, nil)
+	r.client = cl
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if got, ok := saturationGauge(t, "ns1/gone"); !ok || got != 0.5 {
+		t.Fatalf("expected saturation 0.5, got %v (present=%t)", got, ok)
+	}
+
+	if err := cl.Delete(context.Background(), pool); err != nil {
+		t.Fatalf("delete pool: %v", err)
+	}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("reconcile after delete: %v", err)
+	}
+	if _, ok := saturationGauge(t, "ns1/gone"); ok {
+		t.Fatalf("expected the saturation series to be deleted with the pool")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	pustate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal/state"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
@@ -43,17 +44,15 @@ func (h *gpuPoolUsageHandler) Name() string {
 func (h *gpuPoolUsageHandler) Handle(ctx context.Context, s pustate.GPUPoolState) (reconcile.Result, error) {
 	pool := s.Pool()
 
+	resourceName := corev1.ResourceName(poolcommon.NamespacedPoolResourcePrefix + "/" + pool.Name)
 	pods := &corev1.PodList{}
 	if err := s.Client().List(ctx, pods,
 		client.InNamespace(pool.Namespace),
-		client.MatchingLabels{
-			poolcommon.PoolNameKey:  pool.Name,
-			poolcommon.PoolScopeKey: poolcommon.PoolScopeNamespaced,
-		}); err != nil {
+		client.MatchingFields{indexer.PodGPUResourceField: string(resourceName)},
+	); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	resourceName := corev1.ResourceName("gpu.deckhouse.io/" + pool.Name)
 	var used int64
 	for i := range pods.Items {
 		pod := &pods.Items[i]
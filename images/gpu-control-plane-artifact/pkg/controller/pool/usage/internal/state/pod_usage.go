@@ -76,3 +76,12 @@ func ClampInt64ToInt32(value int64) int32 {
 	}
 	return int32(value)
 }
+
+// SaturationRatio is the share of the pool units consumed by scheduled workloads. It is not defined for
+// pools without capacity. Overcommitted pools, e.g. after devices left, report a ratio above one.
+func SaturationRatio(used, total int32) (float64, bool) {
+	if total <= 0 {
+		return 0, false
+	}
+	return float64(used) / float64(total), true
+}
@@ -213,3 +213,15 @@ func TestClampInt64ToInt32(t *testing.T) {
 		})
 	}
 }
+
+func TestSaturationRatio(t *testing.T) {
+	if _, ok := SaturationRatio(1, 0); ok {
+		t.Fatalf("expected no ratio for a pool without capacity")
+	}
+	if got, ok := SaturationRatio(3, 4); !ok || got != 0.75 {
+		t.Fatalf("expected 0.75, got %v (ok=%t)", got, ok)
+	}
+	if got, ok := SaturationRatio(6, 4); !ok || got != 1.5 {
+		t.Fatalf("expected overcommit to be reported as 1.5, got %v (ok=%t)", got, ok)
+	}
+}
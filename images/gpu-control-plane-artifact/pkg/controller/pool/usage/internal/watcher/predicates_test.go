@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
@@ -177,6 +178,35 @@ func TestGPUWorkloadPodPredicates(t *testing.T) {
 	}
 }
 
+func TestGPUResourcePodPredicates(t *testing.T) {
+	p := GPUResourcePodPredicates()
+	plain := &corev1.Pod{}
+	gpu := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"cluster.gpu.deckhouse.io/shared": resource.MustParse("1")}},
+	}}}}
+
+	if p.Create(event.TypedCreateEvent[*corev1.Pod]{Object: plain}) || !p.Create(event.TypedCreateEvent[*corev1.Pod]{Object: gpu}) {
+		t.Fatalf("expected create to pass only for pods requesting pool resources")
+	}
+	if !p.Delete(event.TypedDeleteEvent[*corev1.Pod]{Object: gpu}) {
+		t.Fatalf("expected delete of a GPU pod to pass")
+	}
+
+	finished := gpu.DeepCopy()
+	finished.Status.Phase = corev1.PodSucceeded
+	if !p.Update(event.TypedUpdateEvent[*corev1.Pod]{ObjectOld: gpu, ObjectNew: finished}) {
+		t.Fatalf("expected a phase change to pass")
+	}
+	relabelled := gpu.DeepCopy()
+	relabelled.Annotations = map[string]string{"note": "x"}
+	if p.Update(event.TypedUpdateEvent[*corev1.Pod]{ObjectOld: gpu, ObjectNew: relabelled}) {
+		t.Fatalf("expected unrelated changes to be filtered")
+	}
+	if p.Update(event.TypedUpdateEvent[*corev1.Pod]{ObjectOld: plain, ObjectNew: plain.DeepCopy()}) {
+		t.Fatalf("expected updates of pods without pool resources to be filtered")
+	}
+}
+
 func TestIsGPUWorkloadPod(t *testing.T) {
 	if isGPUWorkloadPod(nil, poolcommon.PoolScopeNamespaced) {
 		t.Fatalf("expected nil pod to be false")
@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
 
@@ -68,3 +69,35 @@ func isGPUWorkloadPod(pod *corev1.Pod, scope string) bool {
 	}
 	return pod.Labels[poolcommon.PoolScopeKey] == scope
 }
+
+// GPUResourcePodPredicates passes the events of pods requesting pool resources that can change how many units
+// the pod holds: scheduling, phase, deletion and the pool labels.
+func GPUResourcePodPredicates() predicate.TypedPredicate[*corev1.Pod] {
+	return predicate.TypedFuncs[*corev1.Pod]{
+		CreateFunc: func(e event.TypedCreateEvent[*corev1.Pod]) bool {
+			return requestsPoolResources(e.Object)
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod]) bool {
+			oldPod, newPod := e.ObjectOld, e.ObjectNew
+			if oldPod == nil || newPod == nil {
+				return true
+			}
+			if !requestsPoolResources(oldPod) && !requestsPoolResources(newPod) {
+				return false
+			}
+			return oldPod.Spec.NodeName != newPod.Spec.NodeName ||
+				oldPod.Status.Phase != newPod.Status.Phase ||
+				(oldPod.DeletionTimestamp == nil) != (newPod.DeletionTimestamp == nil) ||
+				oldPod.Labels[poolcommon.PoolNameKey] != newPod.Labels[poolcommon.PoolNameKey] ||
+				oldPod.Labels[poolcommon.PoolScopeKey] != newPod.Labels[poolcommon.PoolScopeKey]
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Pod]) bool {
+			return requestsPoolResources(e.Object)
+		},
+		GenericFunc: func(event.TypedGenericEvent[*corev1.Pod]) bool { return false },
+	}
+}
+
+func requestsPoolResources(pod *corev1.Pod) bool {
+	return len(indexer.PodGPUResources(pod)) > 0
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	pustate "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/internal/state"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
+)
+
+// Reconcile replaces the contribution of the requested pod; a pod that is gone contributes nothing.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("pod", req.String())
+
+	var units map[string]int64
+	pod := &corev1.Pod{}
+	if err := r.client.Get(ctx, req.NamespacedName, pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	} else {
+		units = podUnits(pod)
+	}
+
+	for key, total := range r.usage.SetPod(req.NamespacedName, units) {
+		if total == 0 {
+			capmetrics.NamespaceRequestedUnitsDelete(key.Namespace, key.Pool)
+			continue
+		}
+		capmetrics.NamespaceRequestedUnitsSet(key.Namespace, key.Pool, total)
+		log.V(2).Info("namespace usage changed", "namespace", key.Namespace, "pool", key.Pool, "units", total)
+	}
+	return reconcile.Result{}, nil
+}
+
+// podUnits returns the pool units the pod requests keyed by pool. Unscheduled and terminated pods request none.
+func podUnits(pod *corev1.Pod) map[string]int64 {
+	if !pustate.PodCountsTowardsUsage(pod) {
+		return nil
+	}
+	units := map[string]int64{}
+	for _, name := range indexer.PodGPUResources(pod) {
+		if value := pustate.RequestedResources(pod, corev1.ResourceName(name)); value > 0 {
+			units[poolOf(pod, name)] += value
+		}
+	}
+	return units
+}
+
+// poolOf names the pool behind a requested resource the way the pool saturation metric does: <namespace>/<name>
+// for a GPUPool and <name> for a ClusterGPUPool. The pool label set by the admission webhook wins over the
+// resource name, which also carries the MIG profile for mixed layouts.
+func poolOf(pod *corev1.Pod, resource string) string {
+	prefix, name, _ := strings.Cut(resource, "/")
+	scope := poolcommon.PoolScopeNamespaced
+	if prefix == poolcommon.ClusterPoolResourcePrefix {
+		scope = poolcommon.PoolScopeCluster
+	}
+	if label := strings.TrimSpace(pod.Labels[poolcommon.PoolNameKey]); label != "" && pod.Labels[poolcommon.PoolScopeKey] == scope {
+		name = label
+	}
+	if scope == poolcommon.PoolScopeCluster {
+		return name
+	}
+	return pod.Namespace + "/" + name
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
+)
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	return clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func gpuPod(namespace, name, pool, scope string, resources map[string]string) *corev1.Pod {
+	limits := corev1.ResourceList{}
+	for resourceName, quantity := range resources {
+		limits[corev1.ResourceName(resourceName)] = resource.MustParse(quantity)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{poolcommon.PoolNameKey: pool, poolcommon.PoolScopeKey: scope},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{Limits: limits}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func reconcileAll(t *testing.T, r *Reconciler, pods ...*corev1.Pod) {
+	t.Helper()
+	for _, pod := range pods {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+			t.Fatalf("reconcile %s: %v", pod.Name, err)
+		}
+	}
+}
+
+func requestedUnits(t *testing.T, namespace, pool string) (float64, bool) {
+	t.Helper()
+	families, err := crmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != capmetrics.NamespaceRequestedUnits {
+			continue
+		}
+		for _, metric := range family.Metric {
+			labels := map[string]string{}
+			for _, label := range metric.Label {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["namespace"] == namespace && labels["pool"] == pool {
+				return metric.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestReconcileMixedResourceNamespace(t *testing.T) {
+	cards := gpuPod("team-mixed", "train", "cards", poolcommon.PoolScopeNamespaced, map[string]string{"gpu.deckhouse.io/cards": "2"})
+	slices := gpuPod("team-mixed", "infer", "shared-mig", poolcommon.PoolScopeCluster, map[string]string{
+		"cluster.gpu.deckhouse.io/shared-mig-3g.40gb": "1",
+		"cluster.gpu.deckhouse.io/shared-mig-1g.10gb": "2",
+	})
+	more := gpuPod("team-mixed", "train-2", "cards", poolcommon.PoolScopeNamespaced, map[string]string{"gpu.deckhouse.io/cards": "1"})
+	pending := gpuPod("team-mixed", "pending", "cards", poolcommon.PoolScopeNamespaced, map[string]string{"gpu.deckhouse.io/cards": "4"})
+	pending.Spec.NodeName = ""
+
+	usage := modulestatus.NewUsageTracker()
+	r := NewReconciler(testr.New(t), usage)
+	r.client = newClient(t, cards, slices, more, pending)
+	reconcileAll(t, r, cards, slices, more, pending)
+
+	want := []modulestatus.NamespaceUsage{
+		{Namespace: "team-mixed", Pool: "shared-mig", Units: 3},
+		{Namespace: "team-mixed", Pool: "team-mixed/cards", Units: 3},
+	}
+	if got := usage.Report(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Report() = %+v, want %+v", got, want)
+	}
+	if v, ok := requestedUnits(t, "team-mixed", "team-mixed/cards"); !ok || v != 3 {
+		t.Fatalf("expected 3 whole cards, got %v (present=%t)", v, ok)
+	}
+	if v, ok := requestedUnits(t, "team-mixed", "shared-mig"); !ok || v != 3 {
+		t.Fatalf("expected 3 MIG instances, got %v (present=%t)", v, ok)
+	}
+}
+
+func TestReconcilePodCompletionRemovesUsage(t *testing.T) {
+	ctx := context.Background()
+	first := gpuPod("team-done", "job-0", "cards", poolcommon.PoolScopeNamespaced, map[string]string{"gpu.deckhouse.io/cards": "2"})
+	second := gpuPod("team-done", "job-1", "cards", poolcommon.PoolScopeNamespaced, map[string]string{"gpu.deckhouse.io/cards": "1"})
+
+	usage := modulestatus.NewUsageTracker()
+	r := NewReconciler(testr.New(t), usage)
+	r.client = newClient(t, first, second)
+	reconcileAll(t, r, first, second)
+
+	finished := &corev1.Pod{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: "team-done", Name: "job-0"}, finished); err != nil {
+		t.Fatalf("get pod: %v", err)
+	}
+	finished.Status.Phase = corev1.PodSucceeded
+	if err := r.client.Status().Update(ctx, finished); err != nil {
+		t.Fatalf("update pod: %v", err)
+	}
+	reconcileAll(t, r, first)
+
+	if v, ok := requestedUnits(t, "team-done", "team-done/cards"); !ok || v != 1 {
+		t.Fatalf("expected the completed pod to release its units, got %v (present=%t)", v, ok)
+	}
+}
+
+func TestReconcileRemovesMetricWhenNamespaceEmpties(t *testing.T) {
+	ctx := context.Background()
+	pod := gpuPod("team-gone", "job", "shared", poolcommon.PoolScopeCluster, map[string]string{"cluster.gpu.deckhouse.io/shared": "1"})
+
+	usage := modulestatus.NewUsageTracker()
+	r := NewReconciler(testr.New(t), usage)
+	r.client = newClient(t, pod)
+	reconcileAll(t, r, pod)
+	if _, ok := requestedUnits(t, "team-gone", "shared"); !ok {
+		t.Fatalf("expected usage series for the running pod")
+	}
+
+	if err := r.client.Delete(ctx, pod); err != nil {
+		t.Fatalf("delete pod: %v", err)
+	}
+	reconcileAll(t, r, pod)
+
+	if _, ok := requestedUnits(t, "team-gone", "shared"); ok {
+		t.Fatalf("expected the series to be removed once the namespace holds no units")
+	}
+	if got := usage.Report(); len(got) != 0 {
+		t.Fatalf("expected empty report, got %+v", got)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package namespaces reports the pool units requested per namespace across all GPU pools.
+package namespaces
+
+import (
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
+)
+
+// Reconciler folds one pod at a time into the namespace usage totals.
+type Reconciler struct {
+	client client.Client
+	log    logr.Logger
+	usage  *modulestatus.UsageTracker
+}
+
+func NewReconciler(log logr.Logger, usage *modulestatus.UsageTracker) *Reconciler {
+	return &Reconciler{
+		log:   log,
+		usage: usage,
+	}
+}
+
+var _ reconcile.Reconciler = (*Reconciler)(nil)
@@ -24,9 +24,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/modulestatus"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/clustergpupool"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/gpupool"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/pool/usage/namespaces"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/logger"
 )
@@ -34,6 +37,7 @@ import (
 const (
 	gpupoolControllerName        = "gpu-pool-usage-controller"
 	clusterGPUPoolControllerName = "cluster-gpu-pool-usage-controller"
+	namespaceControllerName      = "namespace-gpu-usage-controller"
 )
 
 func SetupController(
@@ -42,13 +46,24 @@ func SetupController(
 	log logr.Logger,
 	cfg config.ControllerConfig,
 	store *moduleconfig.ModuleConfigStore,
+	usage *modulestatus.UsageTracker,
 ) error {
+	// Both usage controllers list pods through this index, so it is registered once for the pair.
+	if idx := mgr.GetFieldIndexer(); idx != nil {
+		obj, field, extract := indexer.IndexPodByGPUResource()
+		if err := idx.IndexField(ctx, obj, field, extract); err != nil {
+			return err
+		}
+	}
 	if err := setupGPUPoolUsageController(ctx, mgr, log, cfg, store); err != nil {
 		return err
 	}
 	if err := setupClusterGPUPoolUsageController(ctx, mgr, log, cfg, store); err != nil {
 		return err
 	}
+	if err := setupNamespaceUsageController(ctx, mgr, log, cfg, usage); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -121,3 +136,38 @@ func setupClusterGPUPoolUsageController(
 	baseLog.Info("Initialized ClusterGPUPool usage controller")
 	return nil
 }
+
+func setupNamespaceUsageController(
+	ctx context.Context,
+	mgr ctrl.Manager,
+	log logr.Logger,
+	cfg config.ControllerConfig,
+	usage *modulestatus.UsageTracker,
+) error {
+	baseLog := log.WithName("namespace.usage")
+	r := namespaces.NewReconciler(baseLog, usage)
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	c, err := controller.New(namespaceControllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: workers,
+		RecoverPanic:            ptr.To(true),
+		LogConstructor:          logger.NewConstructor(baseLog),
+		CacheSyncTimeout:        10 * time.Minute,
+		NewQueue:                reconciler.NewNamedQueue(reconciler.UsePriorityQueue()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := r.SetupController(ctx, mgr, c); err != nil {
+		return err
+	}
+
+	baseLog.Info("Initialized namespace GPU usage controller")
+	return nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pooltemplate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+const (
+	// LabelKey marks ClusterGPUPools managed by a pool template; the value is the template name.
+	LabelKey = "gpu.deckhouse.io/pool-template"
+	// HashAnnotation stores the hash of the spec last rendered from the template.
+	HashAnnotation = "gpu.deckhouse.io/pool-template-hash"
+	// UnmatchedSinceAnnotation records when the template stopped matching any device (RFC3339).
+	UnmatchedSinceAnnotation = "gpu.deckhouse.io/pool-template-unmatched-since"
+
+	// Interval is how often templates are evaluated.
+	Interval = 30 * time.Second
+
+	cacheSyncWindow = 5 * time.Second
+)
+
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// Runner keeps the ClusterGPUPools described by the poolTemplates module setting in line with discovered devices.
+type Runner struct {
+	log      logr.Logger
+	client   client.Client
+	reader   client.Reader
+	syncer   cacheSyncer
+	store    *moduleconfig.ModuleConfigStore
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewRunner builds a pool template runner; reader and syncer are normally the manager cache.
+func NewRunner(log logr.Logger, c client.Client, reader client.Reader, syncer cacheSyncer, store *moduleconfig.ModuleConfigStore) *Runner {
+	return &Runner{
+		log:      log,
+		client:   c,
+		reader:   reader,
+		syncer:   syncer,
+		store:    store,
+		interval: Interval,
+		now:      time.Now,
+	}
+}
+
+// SetupRunner registers the pool template runner with the manager.
+func SetupRunner(mgr ctrl.Manager, log logr.Logger, store *moduleconfig.ModuleConfigStore) error {
+	cache := mgr.GetCache()
+	if cache == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+	baseLog := log.WithName("pool-template")
+	if err := mgr.Add(NewRunner(baseLog, mgr.GetClient(), cache, cache, store)); err != nil {
+		return fmt.Errorf("add pool template runner: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection keeps a single writer across controller replicas.
+func (r *Runner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start evaluates the templates until the context is cancelled.
+func (r *Runner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RunOnce(ctx); err != nil {
+			r.log.Error(err, "failed to apply pool templates")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// desiredPool is a pool rendered from a template together with the products of the devices it was rendered for.
+type desiredPool struct {
+	template moduleconfig.PoolTemplate
+	products map[string]struct{}
+}
+
+// RunOnce creates, updates and retires template-managed pools. Pools without LabelKey are never touched.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	if r.syncer != nil {
+		syncCtx, cancel := context.WithTimeout(ctx, cacheSyncWindow)
+		synced := r.syncer.WaitForCacheSync(syncCtx)
+		cancel()
+		if !synced {
+			r.log.V(1).Info("cache not synced, skipping pool templates")
+			return nil
+		}
+	}
+
+	state := moduleconfig.DefaultState()
+	if r.store != nil {
+		state = r.store.Current()
+	}
+	if state.Paused {
+		return nil
+	}
+
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := r.reader.List(ctx, devices); err != nil {
+		return fmt.Errorf("list GPUDevices: %w", err)
+	}
+	pools := &v1alpha1.ClusterGPUPoolList{}
+	if err := r.reader.List(ctx, pools); err != nil {
+		return fmt.Errorf("list ClusterGPUPools: %w", err)
+	}
+
+	desired := r.desiredPools(state.PoolTemplates, devices.Items)
+	templates := make(map[string]moduleconfig.PoolTemplate, len(state.PoolTemplates))
+	for _, template := range state.PoolTemplates {
+		templates[template.Name] = template
+	}
+	existing := make(map[string]*v1alpha1.ClusterGPUPool, len(pools.Items))
+	for i := range pools.Items {
+		existing[pools.Items[i].Name] = &pools.Items[i]
+	}
+
+	now := r.now()
+	var errs []error
+	for _, name := range sortedKeys(desired) {
+		if err := r.ensurePool(ctx, name, desired[name], existing[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		owner, managed := pool.Labels[LabelKey]
+		if _, wanted := desired[pool.Name]; !managed || wanted {
+			continue
+		}
+		template, ok := templates[owner]
+		if !ok {
+			// Without its template there is no retention to apply; the pool is left for the operator.
+			continue
+		}
+		if err := r.retirePool(ctx, pool, template, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Runner) desiredPools(templates []moduleconfig.PoolTemplate, devices []v1alpha1.GPUDevice) map[string]*desiredPool {
+	desired := make(map[string]*desiredPool)
+	for _, template := range templates {
+		for i := range devices {
+			device := &devices[i]
+			if !device.Status.Managed || !template.Matches(device.Status.Hardware) {
+				continue
+			}
+			product := device.Status.Hardware.Product
+			if template.PerProduct() && moduleconfig.ProductPoolName(product) == "" {
+				continue
+			}
+			name := template.RenderPoolName(product)
+			pool, ok := desired[name]
+			if !ok {
+				pool = &desiredPool{template: template, products: map[string]struct{}{}}
+				desired[name] = pool
+			} else if pool.template.Name != template.Name {
+				// Earlier templates win name clashes.
+				continue
+			}
+			if product != "" {
+				pool.products[product] = struct{}{}
+			}
+		}
+	}
+	return desired
+}
+
+func (r *Runner) ensurePool(ctx context.Context, name string, desired *desiredPool, current *v1alpha1.ClusterGPUPool) error {
+	spec := desiredSpec(desired)
+	hash, err := specHash(spec)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		pool := &v1alpha1.ClusterGPUPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{LabelKey: desired.template.Name},
+				Annotations: map[string]string{HashAnnotation: hash},
+			},
+			Spec: spec,
+		}
+		if err := r.client.Create(ctx, pool); err != nil {
+			return fmt.Errorf("create ClusterGPUPool %s: %w", name, err)
+		}
+		r.log.Info("created pool from template", "pool", name, "template", desired.template.Name)
+		return nil
+	}
+	if owner, ok := current.Labels[LabelKey]; !ok || owner != desired.template.Name {
+		r.log.V(1).Info("pool name taken, skipping template", "pool", name, "template", desired.template.Name, "owner", owner)
+		return nil
+	}
+
+	_, unmatched := current.Annotations[UnmatchedSinceAnnotation]
+	if current.Annotations[HashAnnotation] == hash && !unmatched {
+		return nil
+	}
+	updated := current.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	delete(updated.Annotations, UnmatchedSinceAnnotation)
+	if updated.Annotations[HashAnnotation] != hash {
+		updated.Annotations[HashAnnotation] = hash
+		updated.Spec = spec
+	}
+	if err := r.client.Patch(ctx, updated, client.MergeFrom(current)); err != nil {
+		return fmt.Errorf("update ClusterGPUPool %s: %w", name, err)
+	}
+	r.log.Info("updated pool from template", "pool", name, "template", desired.template.Name)
+	return nil
+}
+
+// retirePool stamps when a template-managed pool lost its last matching device and deletes it once the template
+// retention has passed. A zero retention keeps the pool.
+func (r *Runner) retirePool(ctx context.Context, pool *v1alpha1.ClusterGPUPool, template moduleconfig.PoolTemplate, now time.Time) error {
+	since, err := time.Parse(time.RFC3339, pool.Annotations[UnmatchedSinceAnnotation])
+	if err != nil {
+		updated := pool.DeepCopy()
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[UnmatchedSinceAnnotation] = now.UTC().Format(time.RFC3339)
+		if err := r.client.Patch(ctx, updated, client.MergeFrom(pool)); err != nil {
+			return fmt.Errorf("mark ClusterGPUPool %s unmatched: %w", pool.Name, err)
+		}
+		return nil
+	}
+	if template.UnmatchedRetention <= 0 || now.Sub(since) < template.UnmatchedRetention {
+		return nil
+	}
+	if err := commonobject.DeleteObject(ctx, r.client, pool); err != nil {
+		return fmt.Errorf("delete ClusterGPUPool %s: %w", pool.Name, err)
+	}
+	r.log.Info("deleted unmatched pool", "pool", pool.Name, "template", template.Name, "unmatchedSince", since)
+	return nil
+}
+
+// desiredSpec copies the template spec; without a device selector or an explicit device list the pool
+// selects the matched products.
+func desiredSpec(desired *desiredPool) v1alpha1.GPUPoolSpec {
+	spec := *desired.template.PoolSpec.DeepCopy()
+	if spec.DeviceSelector == nil && len(spec.Devices) == 0 && len(desired.products) > 0 {
+		spec.DeviceSelector = &v1alpha1.GPUPoolDeviceSelector{
+			Include: v1alpha1.GPUPoolSelectorRules{Products: sortedKeys(desired.products)},
+		}
+	}
+	return spec
+}
+
+func specHash(spec v1alpha1.GPUPoolSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("encode pool spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
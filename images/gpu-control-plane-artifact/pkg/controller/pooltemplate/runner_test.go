@@ -0,0 +1,259 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pooltemplate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+type staticSyncer bool
+
+func (s staticSyncer) WaitForCacheSync(context.Context) bool { return bool(s) }
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	return scheme
+}
+
+func device(name, product string, memoryMiB int32) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1alpha1.GPUDeviceStatus{
+			Managed:  true,
+			Hardware: v1alpha1.GPUDeviceHardware{Product: product, MemoryMiB: memoryMiB},
+		},
+	}
+}
+
+func parseState(t *testing.T, templates ...map[string]any) moduleconfig.State {
+	t.Helper()
+	list := make([]any, 0, len(templates))
+	for _, template := range templates {
+		list = append(list, template)
+	}
+	state, err := moduleconfig.Parse(moduleconfig.Input{Settings: map[string]any{"poolTemplates": list}})
+	if err != nil {
+		t.Fatalf("parse module config: %v", err)
+	}
+	return state
+}
+
+func a100Template(slices int) map[string]any {
+	return map[string]any{
+		"name":               "a100",
+		"match":              map[string]any{"product": "A100", "minMemoryMiB": 40000},
+		"poolName":           "auto-{product}",
+		"poolSpec":           map[string]any{"resource": map[string]any{"unit": "Card", "slicesPerUnit": slices}},
+		"unmatchedRetention": "1h",
+	}
+}
+
+func newRunner(t *testing.T, state moduleconfig.State, objects ...client.Object) (*Runner, client.Client) {
+	t.Helper()
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objects...).Build()
+	runner := NewRunner(testr.New(t), cl, cl, staticSyncer(true), moduleconfig.NewModuleConfigStore(state))
+	return runner, cl
+}
+
+func getPool(t *testing.T, cl client.Client, name string) *v1alpha1.ClusterGPUPool {
+	t.Helper()
+	pool := &v1alpha1.ClusterGPUPool{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: name}, pool); err != nil {
+		t.Fatalf("get pool %s: %v", name, err)
+	}
+	return pool
+}
+
+func TestRunOnceCreatesPoolOnFirstMatch(t *testing.T) {
+	runner, cl := newRunner(t, parseState(t, a100Template(1)),
+		device("gpu-a", "NVIDIA A100-PCIE-40GB", 40960),
+		device("gpu-b", "NVIDIA A100-PCIE-40GB", 40960),
+		device("gpu-small", "NVIDIA A100-PCIE-20GB", 20480),
+		device("gpu-l4", "NVIDIA L4", 40960),
+	)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	pools := &v1alpha1.ClusterGPUPoolList{}
+	if err := cl.List(context.Background(), pools); err != nil {
+		t.Fatalf("list pools: %v", err)
+	}
+	if len(pools.Items) != 1 {
+		t.Fatalf("expected one pool, got %+v", pools.Items)
+	}
+	pool := pools.Items[0]
+	if pool.Name != "auto-nvidia-a100-pcie-40gb" || pool.Labels[LabelKey] != "a100" || pool.Annotations[HashAnnotation] == "" {
+		t.Fatalf("unexpected pool metadata: %+v", pool.ObjectMeta)
+	}
+	if pool.Spec.DeviceSelector == nil || len(pool.Spec.DeviceSelector.Include.Products) != 1 || pool.Spec.DeviceSelector.Include.Products[0] != "NVIDIA A100-PCIE-40GB" {
+		t.Fatalf("expected the pool to select the matched product, got %+v", pool.Spec.DeviceSelector)
+	}
+}
+
+func TestRunOnceUpdatesPoolOnTemplateChange(t *testing.T) {
+	gpu := device("gpu-a", "NVIDIA A100-PCIE-40GB", 40960)
+	runner, cl := newRunner(t, parseState(t, a100Template(1)), gpu)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	before := getPool(t, cl, "auto-nvidia-a100-pcie-40gb")
+
+	runner.store.Update(parseState(t, a100Template(4)))
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	after := getPool(t, cl, "auto-nvidia-a100-pcie-40gb")
+	if after.Spec.Resource.SlicesPerUnit != 4 {
+		t.Fatalf("expected spec to follow the template, got %+v", after.Spec.Resource)
+	}
+	if after.Annotations[HashAnnotation] == before.Annotations[HashAnnotation] {
+		t.Fatalf("expected spec hash to change")
+	}
+
+	// An unchanged template leaves the pool alone.
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if again := getPool(t, cl, after.Name); again.ResourceVersion != after.ResourceVersion {
+		t.Fatalf("expected no write for an unchanged template")
+	}
+}
+
+func TestRunOnceDeletesPoolAfterRetention(t *testing.T) {
+	gpu := device("gpu-a", "NVIDIA A100-PCIE-40GB", 40960)
+	runner, cl := newRunner(t, parseState(t, a100Template(1)), gpu)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	runner.now = func() time.Time { return now }
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	if err := cl.Delete(context.Background(), gpu); err != nil {
+		t.Fatalf("delete device: %v", err)
+	}
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	pool := getPool(t, cl, "auto-nvidia-a100-pcie-40gb")
+	if pool.Annotations[UnmatchedSinceAnnotation] != now.Format(time.RFC3339) {
+		t.Fatalf("expected unmatched timestamp, got %+v", pool.Annotations)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	getPool(t, cl, pool.Name)
+
+	now = now.Add(30 * time.Minute)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if err := cl.Get(context.Background(), types.NamespacedName{Name: pool.Name}, &v1alpha1.ClusterGPUPool{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected pool to be deleted after retention, got %v", err)
+	}
+}
+
+func TestRunOnceClearsUnmatchedWhenDevicesReturn(t *testing.T) {
+	pool := &v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{
+		Name:        "auto-nvidia-a100-pcie-40gb",
+		Labels:      map[string]string{LabelKey: "a100"},
+		Annotations: map[string]string{UnmatchedSinceAnnotation: "2025-03-01T12:00:00Z"},
+	}}
+	runner, cl := newRunner(t, parseState(t, a100Template(1)), pool, device("gpu-a", "NVIDIA A100-PCIE-40GB", 40960))
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	got := getPool(t, cl, pool.Name)
+	if _, ok := got.Annotations[UnmatchedSinceAnnotation]; ok {
+		t.Fatalf("expected unmatched timestamp to be cleared: %+v", got.Annotations)
+	}
+	if got.Spec.Resource.Unit != "Card" {
+		t.Fatalf("expected spec to be rendered, got %+v", got.Spec)
+	}
+}
+
+func TestRunOnceLeavesManualPoolsAlone(t *testing.T) {
+	manual := &v1alpha1.ClusterGPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "auto-nvidia-a100-pcie-40gb"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "1g.5gb"}},
+	}
+	other := &v1alpha1.ClusterGPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Annotations: map[string]string{UnmatchedSinceAnnotation: "2000-01-01T00:00:00Z"}},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+	}
+	runner, cl := newRunner(t, parseState(t, a100Template(2)), manual, other, device("gpu-a", "NVIDIA A100-PCIE-40GB", 40960))
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	got := getPool(t, cl, manual.Name)
+	if got.Spec.Resource.Unit != "MIG" || got.Labels[LabelKey] != "" {
+		t.Fatalf("expected manual pool to stay untouched, got %+v", got)
+	}
+	getPool(t, cl, other.Name)
+}
+
+func TestRunOnceKeepsPoolsOfRemovedTemplates(t *testing.T) {
+	pool := &v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{
+		Name:        "auto-l4",
+		Labels:      map[string]string{LabelKey: "l4"},
+		Annotations: map[string]string{UnmatchedSinceAnnotation: "2000-01-01T00:00:00Z"},
+	}}
+	runner, cl := newRunner(t, parseState(t, a100Template(1)), pool)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	getPool(t, cl, pool.Name)
+}
+
+func TestRunOnceSkipsWhenPausedOrNotSynced(t *testing.T) {
+	state := parseState(t, a100Template(1))
+	state.Paused = true
+	runner, cl := newRunner(t, state, device("gpu-a", "NVIDIA A100-PCIE-40GB", 40960))
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	runner.store.Update(parseState(t, a100Template(1)))
+	runner.syncer = staticSyncer(false)
+	if err := runner.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	pools := &v1alpha1.ClusterGPUPoolList{}
+	if err := cl.List(context.Background(), pools); err != nil {
+		t.Fatalf("list pools: %v", err)
+	}
+	if len(pools.Items) != 0 {
+		t.Fatalf("expected no pools, got %+v", pools.Items)
+	}
+}
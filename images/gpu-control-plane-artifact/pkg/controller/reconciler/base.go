@@ -16,6 +16,7 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"time"
@@ -23,6 +24,8 @@ import (
 	"github.com/go-logr/logr"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 )
 
 // ErrStopHandlerChain is a sentinel error allowing handlers to stop further execution.
@@ -41,6 +44,14 @@ type (
 		Finalize(ctx context.Context) error
 	}
 
+	// Configurable marks handlers that accept opaque runtime settings from ModuleConfig.
+	Configurable interface {
+		Configure(settings json.RawMessage) error
+	}
+
+	// HandlerFilter decides at reconcile time whether the named handler runs.
+	HandlerFilter func(name string) bool
+
 	// ResourceUpdater persists the final resource state.
 	ResourceUpdater func(ctx context.Context) error
 
@@ -53,6 +64,7 @@ type BaseReconciler[H any] struct {
 	handlers []H
 	update   ResourceUpdater
 	execute  HandlerExecutor[H]
+	filter   HandlerFilter
 }
 
 // NewBaseReconciler constructs a BaseReconciler for the provided handlers.
@@ -70,6 +82,11 @@ func (b *BaseReconciler[H]) SetHandlerExecutor(execute HandlerExecutor[H]) {
 	b.execute = execute
 }
 
+// SetHandlerFilter configures which handlers are skipped; without a filter every handler runs.
+func (b *BaseReconciler[H]) SetHandlerFilter(filter HandlerFilter) {
+	b.filter = filter
+}
+
 // Reconcile executes handlers sequentially, applies updates and finalizers.
 func (b *BaseReconciler[H]) Reconcile(ctx context.Context) (reconcile.Result, error) {
 	if b.update == nil {
@@ -83,17 +100,18 @@ func (b *BaseReconciler[H]) Reconcile(ctx context.Context) (reconcile.Result, er
 	log.V(2).Info("start reconciliation")
 
 	var (
-		result reconcile.Result
-		errs   error
+		result   reconcile.Result
+		errs     error
+		deferred error
 	)
 
 	for _, handler := range b.handlers {
-		handlerName := reflect.TypeOf(handler).String()
-		if named, ok := any(handler).(Named); ok {
-			handlerName = named.Name()
-		}
-
+		handlerName := nameOf(handler)
 		handlerLog := log.WithValues("handler", handlerName)
+		if !b.enabled(handlerName) {
+			handlerLog.V(2).Info("handler disabled, skipping")
+			continue
+		}
 		handlerCtx := logr.NewContext(ctx, handlerLog)
 
 		res, err := b.execute(handlerCtx, handler)
@@ -108,6 +126,10 @@ func (b *BaseReconciler[H]) Reconcile(ctx context.Context) (reconcile.Result, er
 		case k8serrors.IsConflict(err):
 			handlerLog.V(1).Info("conflict occurred during handler execution", "err", err)
 			res = MergeResults(res, reconcile.Result{RequeueAfter: conflictRequeueAfter})
+		case commonerrors.Classified(err):
+			// Classified errors do not stop the chain; the caller maps them via commonerrors.PolicyFor.
+			handlerLog.V(1).Info("handler deferred", "err", err)
+			deferred = errors.Join(deferred, err)
 		default:
 			handlerLog.Error(err, "handler failed")
 			errs = errors.Join(errs, err)
@@ -134,7 +156,7 @@ finalize:
 
 	for _, handler := range b.handlers {
 		finalizer, ok := any(handler).(Finalizer)
-		if !ok {
+		if !ok || !b.enabled(nameOf(handler)) {
 			continue
 		}
 		if err := finalizer.Finalize(ctx); err != nil {
@@ -147,6 +169,10 @@ finalize:
 		log.Error(errs, "handler chain finished with errors")
 		return reconcile.Result{}, errs
 	}
+	if deferred != nil {
+		log.V(1).Info("handler chain deferred", "err", deferred)
+		return result, deferred
+	}
 
 	log.V(2).Info("reconciliation completed",
 		"requeue", result.Requeue,
@@ -154,3 +180,14 @@ finalize:
 
 	return result, nil
 }
+
+func (b *BaseReconciler[H]) enabled(name string) bool {
+	return b.filter == nil || b.filter(name)
+}
+
+func nameOf(handler any) string {
+	if named, ok := handler.(Named); ok {
+		return named.Name()
+	}
+	return reflect.TypeOf(handler).String()
+}
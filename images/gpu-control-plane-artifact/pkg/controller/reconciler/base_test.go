@@ -26,6 +26,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
 )
 
 type stubHandler struct {
@@ -202,6 +204,29 @@ func TestReconcileHandlerErrorStopsFurtherHandlers(t *testing.T) {
 	}
 }
 
+func TestReconcileClassifiedErrorKeepsChainRunning(t *testing.T) {
+	missing := fmt.Errorf("node worker-1: %w", commonerrors.ErrNodeFeatureMissing)
+
+	handlers := []*stubHandler{{name: "a", err: missing}, {name: "b", result: reconcile.Result{RequeueAfter: time.Minute}}}
+	rec := NewBaseReconciler(handlers)
+	rec.SetHandlerExecutor(func(ctx context.Context, h *stubHandler) (reconcile.Result, error) {
+		h.calls++
+		return h.result, h.err
+	})
+	rec.SetResourceUpdater(func(context.Context) error { return nil })
+
+	res, err := rec.Reconcile(newContext(t))
+	if !errors.Is(err, commonerrors.ErrNodeFeatureMissing) {
+		t.Fatalf("expected classified error to be returned, got %v", err)
+	}
+	if handlers[1].calls != 1 {
+		t.Fatalf("expected second handler executed, got %d calls", handlers[1].calls)
+	}
+	if res.RequeueAfter != time.Minute {
+		t.Fatalf("expected result of the remaining handlers, got %+v", res)
+	}
+}
+
 func TestReconcileAggregatesUpdateAndHandlerErrors(t *testing.T) {
 	handlerErr := errors.New("handler failed")
 	updateErr := errors.New("update failed")
@@ -270,3 +295,24 @@ func TestMergeResultsPrefersImmediateRequeue(t *testing.T) {
 		t.Fatalf("unexpected merged result: %+v", res)
 	}
 }
+
+func TestReconcileHandlerFilterSkipsDisabledHandlers(t *testing.T) {
+	handlers := []*stubHandler{{name: "enabled"}, {name: "disabled"}}
+	rec := NewBaseReconciler(handlers)
+	rec.SetHandlerExecutor(func(_ context.Context, h *stubHandler) (reconcile.Result, error) {
+		h.calls++
+		return reconcile.Result{}, nil
+	})
+	rec.SetResourceUpdater(func(context.Context) error { return nil })
+	rec.SetHandlerFilter(func(name string) bool { return name != "disabled" })
+
+	if _, err := rec.Reconcile(newContext(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handlers[0].calls != 1 || handlers[0].finalizerCalls != 1 {
+		t.Fatalf("expected enabled handler to run and finalize, got calls=%d finalize=%d", handlers[0].calls, handlers[0].finalizerCalls)
+	}
+	if handlers[1].calls != 0 || handlers[1].finalizerCalls != 0 {
+		t.Fatalf("expected disabled handler to be skipped, got calls=%d finalize=%d", handlers[1].calls, handlers[1].finalizerCalls)
+	}
+}
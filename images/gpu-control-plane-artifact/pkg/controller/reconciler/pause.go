@@ -0,0 +1,53 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+)
+
+const (
+	// ConditionModulePaused is kept on primary objects while settings.paused freezes the module.
+	ConditionModulePaused = "ModulePaused"
+	reasonModulePaused    = "ModulePaused"
+)
+
+// MarkModulePaused sets ModulePaused=True on obj. ObservedGeneration is left alone, so spec edits made
+// while paused do not cause status writes.
+func MarkModulePaused(obj client.Object) {
+	conds := conditions.NewConditionsAccessor(obj).Conditions()
+	if conds == nil {
+		return
+	}
+	builder := conditions.NewConditionBuilder(conditions.ConditionType(ConditionModulePaused)).
+		Status(metav1.ConditionTrue).
+		Reason(conditions.CommonReason(reasonModulePaused)).
+		Message("module is paused via settings.paused, the object is not reconciled")
+	if existing := apimeta.FindStatusCondition(*conds, ConditionModulePaused); existing != nil {
+		builder.Generation(existing.ObservedGeneration)
+	}
+	conditions.SetCondition(builder, conds)
+}
+
+// ClearModulePaused removes the ModulePaused condition once reconciliation resumed.
+func ClearModulePaused(obj client.Object) {
+	if conds := conditions.NewConditionsAccessor(obj).Conditions(); conds != nil {
+		apimeta.RemoveStatusCondition(conds, ConditionModulePaused)
+	}
+}
@@ -30,6 +30,8 @@ import (
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/patch"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 )
 
 type ResourceObject[T, ST any] interface {
@@ -52,12 +54,15 @@ type Resource[T ResourceObject[T, ST], ST any] struct {
 	objFactory      ObjectFactory[T]
 	objStatusGetter ObjectStatusGetter[T, ST]
 	client          client.Client
+	guard           *ownership.Guard
 }
 
-func NewResource[T ResourceObject[T, ST], ST any](name types.NamespacedName, client client.Client, objFactory ObjectFactory[T], objStatusGetter ObjectStatusGetter[T, ST]) *Resource[T, ST] {
+// NewResource wraps the object for a fetch-modify-update cycle. A nil guard lets every update through.
+func NewResource[T ResourceObject[T, ST], ST any](name types.NamespacedName, client client.Client, guard *ownership.Guard, objFactory ObjectFactory[T], objStatusGetter ObjectStatusGetter[T, ST]) *Resource[T, ST] {
 	return &Resource[T, ST]{
 		name:            name,
 		client:          client,
+		guard:           guard,
 		objFactory:      objFactory,
 		objStatusGetter: objStatusGetter,
 	}
@@ -129,14 +134,42 @@ func (r *Resource[T, ST]) Update(ctx context.Context) error {
 		return nil
 	}
 
-	rewriteObject(r.changedObj)
+	// Objects claimed by another live installation are left to it entirely.
+	if allowed, err := r.guard.MayWrite(ctx, r.currentObj); err != nil || !allowed {
+		return err
+	}
+
+	// Objects written by a controller with a newer status schema are not rewritten: the status would
+	// lose fields this controller does not know about. Metadata is still patched op by op.
+	observed, tooNew := SchemaTooNew(r.currentObj)
+	if tooNew {
+		if err := r.markSchemaTooNew(ctx, observed); err != nil {
+			return err
+		}
+	} else {
+		rewriteObject(r.changedObj)
+		clearSchemaTooNew(r.changedObj)
+	}
+
+	statusChanged := !tooNew && !reflect.DeepEqual(r.getObjStatus(r.currentObj), r.getObjStatus(r.changedObj))
+	if !tooNew && (statusChanged || !r.metadataEqual()) {
+		StampReconciledBy(r.changedObj, r.guard)
+	}
 
-	if !reflect.DeepEqual(r.getObjStatus(r.currentObj), r.getObjStatus(r.changedObj)) {
+	if statusChanged {
+		// Metadata-only writes are never refused, so finalizers can always be removed.
+		if err := objsize.Guard(r.changedObj); err != nil {
+			return err
+		}
 		finalizers := r.changedObj.GetFinalizers()
 		labels := r.changedObj.GetLabels()
 		annotations := r.changedObj.GetAnnotations()
-		if err := r.client.Status().Update(ctx, r.changedObj); err != nil {
-			return fmt.Errorf("error updating status subresource: %w", err)
+		// A merge patch against the fetched object only carries the fields this controller changed, so
+		// status fields written by a newer version survive a downgrade. The resourceVersion keeps the
+		// write conflicting like an update would.
+		statusPatch := client.MergeFromWithOptions(r.currentObj, client.MergeFromWithOptimisticLock{})
+		if err := r.client.Status().Patch(ctx, r.changedObj, statusPatch); err != nil {
+			return fmt.Errorf("error patching status subresource: %w", err)
 		}
 		r.changedObj.SetFinalizers(finalizers)
 		r.changedObj.SetLabels(labels)
@@ -175,6 +208,23 @@ func (r *Resource[T, ST]) Update(ctx context.Context) error {
 	return nil
 }
 
+func (r *Resource[T, ST]) metadataEqual() bool {
+	return slices.Equal(r.currentObj.GetFinalizers(), r.changedObj.GetFinalizers()) &&
+		maps.Equal(r.currentObj.GetAnnotations(), r.changedObj.GetAnnotations()) &&
+		maps.Equal(r.currentObj.GetLabels(), r.changedObj.GetLabels())
+}
+
+func (r *Resource[T, ST]) markSchemaTooNew(ctx context.Context, observed int) error {
+	marked := r.currentObj.DeepCopy()
+	if !MarkSchemaTooNew(marked, observed) {
+		return nil
+	}
+	if err := r.client.Status().Patch(ctx, marked, client.MergeFrom(r.currentObj)); err != nil {
+		return fmt.Errorf("error marking schema too new: %w", err)
+	}
+	return nil
+}
+
 func (r *Resource[T, ST]) JSONPatchOpsForFinalizers() []patch.JSONPatchOperation {
 	return []patch.JSONPatchOperation{
 		patch.NewJSONPatchOperation(patch.PatchReplaceOp, "/metadata/finalizers", r.changedObj.GetFinalizers()),
@@ -16,6 +16,8 @@ package reconciler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
 )
 
 func TestResourceFetchEmpty(t *testing.T) {
@@ -38,6 +41,7 @@ func TestResourceFetchEmpty(t *testing.T) {
 	resource := NewResource(
 		types.NamespacedName{Name: "pool", Namespace: "ns"},
 		cl,
+		nil,
 		func() *v1alpha1.GPUPool { return &v1alpha1.GPUPool{} },
 		func(obj *v1alpha1.GPUPool) v1alpha1.GPUPoolStatus { return obj.Status },
 	)
@@ -76,6 +80,7 @@ func TestResourceUpdateStatusAndMetadata(t *testing.T) {
 	resource := NewResource(
 		types.NamespacedName{Name: "pool", Namespace: "ns"},
 		cl,
+		nil,
 		func() *v1alpha1.GPUPool { return &v1alpha1.GPUPool{} },
 		func(obj *v1alpha1.GPUPool) v1alpha1.GPUPoolStatus { return obj.Status },
 	)
@@ -140,6 +145,7 @@ func TestResourceUpdateMetadataOnly(t *testing.T) {
 	resource := NewResource(
 		types.NamespacedName{Name: "pool", Namespace: "ns"},
 		cl,
+		nil,
 		func() *v1alpha1.GPUPool { return &v1alpha1.GPUPool{} },
 		func(obj *v1alpha1.GPUPool) v1alpha1.GPUPoolStatus { return obj.Status },
 	)
@@ -163,3 +169,48 @@ func TestResourceUpdateMetadataOnly(t *testing.T) {
 		t.Fatalf("expected annotation updated, got %q", stored.Annotations["note"])
 	}
 }
+
+func TestResourceUpdateRefusesOversizedStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	objsize.SetMaxObjectBytes(512)
+	t.Cleanup(func() { objsize.SetMaxObjectBytes(0) })
+
+	state := &v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.GPUNodeState{}).
+		WithObjects(state).
+		Build()
+
+	resource := NewResource(
+		types.NamespacedName{Name: "node"},
+		cl,
+		nil,
+		func() *v1alpha1.GPUNodeState { return &v1alpha1.GPUNodeState{} },
+		func(obj *v1alpha1.GPUNodeState) v1alpha1.GPUNodeStateStatus { return obj.Status },
+	)
+	if err := resource.Fetch(context.Background()); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	changed := resource.Changed()
+	for i := 0; i < 20; i++ {
+		changed.Status.Conditions = append(changed.Status.Conditions, metav1.Condition{
+			Type:    fmt.Sprintf("Condition%d", i),
+			Status:  metav1.ConditionTrue,
+			Reason:  "Test",
+			Message: "status that does not fit",
+		})
+	}
+
+	if err := resource.Update(context.Background()); !errors.Is(err, objsize.ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	stored := &v1alpha1.GPUNodeState{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "node"}, stored); err != nil {
+		t.Fatalf("get node state: %v", err)
+	}
+	if len(stored.Status.Conditions) != 0 {
+		t.Fatalf("expected the oversized status not to be written, got %d conditions", len(stored.Status.Conditions))
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/conditions"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	schemametrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/schema"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
+)
+
+const (
+	// ConditionSchemaTooNew is set on objects written by a controller with a newer status schema.
+	ConditionSchemaTooNew = "SchemaTooNew"
+	ReasonSchemaTooNew    = "SchemaTooNew"
+)
+
+// StampReconciledBy records the running controller version and schema on the object and claims it
+// for the guard's instance. It reports whether the annotations changed; callers only stamp objects they are writing anyway.
+func StampReconciledBy(obj metav1.Object, guard *ownership.Guard) bool {
+	claimed := guard.Stamp(obj)
+	want := map[string]string{
+		annotations.LastReconciledBy: version.Version(),
+		annotations.SchemaVersion:    strconv.Itoa(version.SchemaVersion),
+	}
+	current := obj.GetAnnotations()
+	changed := false
+	for key, value := range want {
+		if current[key] != value {
+			changed = true
+		}
+	}
+	if !changed {
+		return claimed
+	}
+	updated := make(map[string]string, len(current)+len(want))
+	for key, value := range current {
+		updated[key] = value
+	}
+	for key, value := range want {
+		updated[key] = value
+	}
+	obj.SetAnnotations(updated)
+	return true
+}
+
+// SchemaTooNew returns the schema version recorded on the object when it is newer than the one
+// the running controller understands; every such observation is counted. Missing or malformed
+// annotations are treated as compatible.
+func SchemaTooNew(obj metav1.Object) (int, bool) {
+	raw, ok := obj.GetAnnotations()[annotations.SchemaVersion]
+	if !ok {
+		return 0, false
+	}
+	observed, err := strconv.Atoi(raw)
+	if err != nil || observed <= version.SchemaVersion {
+		return 0, false
+	}
+	schemametrics.NewerSchemaObservedInc(kindOf(obj), schemametrics.MarkerSchemaVersion)
+	return observed, true
+}
+
+func kindOf(obj metav1.Object) string {
+	if typed, ok := obj.(runtime.Object); ok {
+		if kind := typed.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+			return kind
+		}
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
+
+// MarkSchemaTooNew sets the SchemaTooNew condition on the object and reports whether it changed.
+func MarkSchemaTooNew(obj client.Object, observed int) bool {
+	conds := conditions.NewConditionsAccessor(obj).Conditions()
+	if conds == nil {
+		return false
+	}
+	builder := conditions.NewConditionBuilder(conditions.ConditionType(ConditionSchemaTooNew)).
+		Status(metav1.ConditionTrue).
+		Reason(conditions.CommonReason(ReasonSchemaTooNew)).
+		Message(fmt.Sprintf("object was written with status schema %d, controller %s understands up to %d; status is left untouched", observed, version.Version(), version.SchemaVersion)).
+		Generation(obj.GetGeneration())
+	want := builder.Condition()
+	if existing := conditions.FindStatusCondition(*conds, want.Type); existing != nil &&
+		existing.Status == want.Status && existing.Message == want.Message && existing.ObservedGeneration == want.ObservedGeneration {
+		return false
+	}
+	conditions.SetCondition(builder, conds)
+	return true
+}
+
+func clearSchemaTooNew(obj client.Object) {
+	conds := conditions.NewConditionsAccessor(obj).Conditions()
+	if conds == nil {
+		return
+	}
+	out := (*conds)[:0]
+	for _, cond := range *conds {
+		if cond.Type != ConditionSchemaTooNew {
+			out = append(out, cond)
+		}
+	}
+	*conds = out
+}
@@ -0,0 +1,245 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
+)
+
+type poolWrites struct {
+	patches       int
+	statusUpdates int
+	statusPatches int
+}
+
+func newCountingPoolClient(t *testing.T, pool *v1alpha1.GPUPool, writes *poolWrites) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.GPUPool{}).
+		WithObjects(pool).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				writes.patches++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, sub string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				writes.statusUpdates++
+				return c.SubResource(sub).Update(ctx, obj, opts...)
+			},
+			SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				writes.statusPatches++
+				return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+}
+
+func newPoolResource(cl client.Client) *Resource[*v1alpha1.GPUPool, v1alpha1.GPUPoolStatus] {
+	return NewResource(
+		types.NamespacedName{Name: "pool", Namespace: "ns"},
+		cl,
+		nil,
+		func() *v1alpha1.GPUPool { return &v1alpha1.GPUPool{} },
+		func(obj *v1alpha1.GPUPool) v1alpha1.GPUPoolStatus { return obj.Status },
+	)
+}
+
+func TestResourceUpdateStampsOnlyWhenWriting(t *testing.T) {
+	ctx := context.Background()
+	writes := &poolWrites{}
+	cl := newCountingPoolClient(t, &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
+	}, writes)
+
+	resource := newPoolResource(cl)
+	if err := resource.Fetch(ctx); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if err := resource.Update(ctx); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if *writes != (poolWrites{}) {
+		t.Fatalf("expected no writes for an unchanged object, got %+v", *writes)
+	}
+
+	if err := resource.Fetch(ctx); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	resource.Changed().Status.Capacity.Total = 2
+	if err := resource.Update(ctx); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	stored := &v1alpha1.GPUPool{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "pool", Namespace: "ns"}, stored); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	if stored.Annotations[annotations.LastReconciledBy] != version.Version() ||
+		stored.Annotations[annotations.SchemaVersion] != strconv.Itoa(version.SchemaVersion) {
+		t.Fatalf("expected version stamp on written object, got %v", stored.Annotations)
+	}
+
+	// Once stamped by this version, later status writes do not patch metadata again.
+	*writes = poolWrites{}
+	if err := resource.Fetch(ctx); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	resource.Changed().Status.Capacity.Total = 3
+	if err := resource.Update(ctx); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if *writes != (poolWrites{statusPatches: 1}) {
+		t.Fatalf("expected a single status patch, got %+v", *writes)
+	}
+}
+
+func TestResourceUpdateSkipsObjectsWithNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	writes := &poolWrites{}
+	newer := strconv.Itoa(version.SchemaVersion + 1)
+	cl := newCountingPoolClient(t, &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				annotations.LastReconciledBy: "v99.0.0",
+				annotations.SchemaVersion:    newer,
+			},
+		},
+		Status: v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}, writes)
+
+	resource := newPoolResource(cl)
+	if err := resource.Fetch(ctx); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	resource.Changed().Status.Capacity.Total = 7
+	if err := resource.Update(ctx); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	stored := &v1alpha1.GPUPool{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "pool", Namespace: "ns"}, stored); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	if stored.Status.Capacity.Total != 1 {
+		t.Fatalf("expected status to stay untouched, got total=%d", stored.Status.Capacity.Total)
+	}
+	if !meta.IsStatusConditionTrue(stored.Status.Conditions, ConditionSchemaTooNew) {
+		t.Fatalf("expected SchemaTooNew condition, got %+v", stored.Status.Conditions)
+	}
+	if stored.Annotations[annotations.LastReconciledBy] != "v99.0.0" || stored.Annotations[annotations.SchemaVersion] != newer {
+		t.Fatalf("expected newer stamp to be preserved, got %v", stored.Annotations)
+	}
+	if *writes != (poolWrites{statusPatches: 1}) {
+		t.Fatalf("expected only the condition patch, got %+v", *writes)
+	}
+
+	// The condition is already surfaced, so the next pass writes nothing.
+	*writes = poolWrites{}
+	if err := resource.Fetch(ctx); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	resource.Changed().Status.Capacity.Total = 7
+	if err := resource.Update(ctx); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if *writes != (poolWrites{}) {
+		t.Fatalf("expected no writes, got %+v", *writes)
+	}
+}
+
+func TestResourceUpdateKeepsUnknownStatusFields(t *testing.T) {
+	ctx := context.Background()
+	var patches [][]byte
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.GPUPool{}).
+		WithObjects(&v1alpha1.GPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
+			Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				data, err := patch.Data(obj)
+				if err != nil {
+					return err
+				}
+				patches = append(patches, data)
+				return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	resource := newPoolResource(cl)
+	if err := resource.Fetch(ctx); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	resource.Changed().Status.Capacity.Total = 4
+	if err := resource.Update(ctx); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("expected one status patch, got %d", len(patches))
+	}
+
+	// The stored object as a newer controller left it: a field and a capacity key this version does not know.
+	stored := []byte(`{"status":{"capacity":{"total":1,"overcommitted":3},"placement":{"mode":"Packed"}}}`)
+	merged, err := jsonpatch.MergePatch(stored, patches[0])
+	if err != nil {
+		t.Fatalf("apply patch: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(merged, &result); err != nil {
+		t.Fatalf("decode patched object: %v", err)
+	}
+	status := result["status"].(map[string]any)
+	capacity := status["capacity"].(map[string]any)
+	if capacity["total"] != float64(4) {
+		t.Fatalf("expected owned field to be written, got %v", capacity)
+	}
+	if capacity["overcommitted"] != float64(3) || status["placement"] == nil {
+		t.Fatalf("expected unknown status fields to survive, got %s", merged)
+	}
+}
+
+func TestSchemaTooNewIgnoresMalformedAnnotations(t *testing.T) {
+	for _, value := range []string{"", "abc", strconv.Itoa(version.SchemaVersion)} {
+		obj := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotations.SchemaVersion: value}}}
+		if _, tooNew := SchemaTooNew(obj); tooNew {
+			t.Fatalf("expected %q to be treated as compatible", value)
+		}
+	}
+}
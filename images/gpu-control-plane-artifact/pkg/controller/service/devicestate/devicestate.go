@@ -0,0 +1,123 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devicestate moves GPUDevices between lifecycle states and keeps their bounded transition history.
+package devicestate
+
+import (
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+const (
+	// HistoryLimit bounds the number of transitions kept in GPUDevice status.
+	HistoryLimit = objsize.MaxHistoryEntries
+	// DedupWindow is how long a repeated transition stays silent after it was last recorded.
+	DedupWindow = 5 * time.Minute
+	// EventStateChanged is the event reason used for device state transitions.
+	EventStateChanged = "StateChanged"
+)
+
+// Transition describes the outcome of SetState.
+type Transition struct {
+	// Entry is the history entry recorded for the transition.
+	Entry v1alpha1.GPUDeviceStateTransition
+	// Changed reports whether the device state was modified.
+	Changed bool
+	// Notify reports whether an event should be emitted once the status write succeeds.
+	Notify bool
+}
+
+// States lists the lifecycle states this controller understands.
+var States = []v1alpha1.GPUDeviceState{
+	v1alpha1.GPUDeviceStateDiscovered,
+	v1alpha1.GPUDeviceStateValidating,
+	v1alpha1.GPUDeviceStateReady,
+	v1alpha1.GPUDeviceStatePendingAssignment,
+	v1alpha1.GPUDeviceStateAssigned,
+	v1alpha1.GPUDeviceStateReserved,
+	v1alpha1.GPUDeviceStateInUse,
+	v1alpha1.GPUDeviceStateFaulted,
+}
+
+// Foreign reports whether state was written by a newer controller: it is set but not one of States. A foreign state
+// is terminal for this controller, the device keeps it until a version that understands it takes over again.
+func Foreign(state v1alpha1.GPUDeviceState) bool {
+	return state != "" && !slices.Contains(States, state)
+}
+
+// SetState moves the device to the target state and appends the transition to Status.History, dropping the oldest
+// entries beyond HistoryLimit. Devices without a state yet are initialised without a history entry, devices in a
+// Foreign state are left as they are. Deduplication is derived from the history itself, so it survives controller
+// restarts.
+func SetState(device *v1alpha1.GPUDevice, to v1alpha1.GPUDeviceState, reason string, now time.Time) Transition {
+	from := device.Status.State
+	if from == to || Foreign(from) {
+		return Transition{}
+	}
+	device.Status.State = to
+	if from == "" {
+		return Transition{Changed: true}
+	}
+
+	entry := v1alpha1.GPUDeviceStateTransition{
+		From:   from,
+		To:     to,
+		Reason: reason,
+		Time:   metav1.NewTime(now),
+	}
+	notify := !seenWithin(device.Status.History, from, to, now)
+
+	device.Status.History = objsize.TrimHistory(append(device.Status.History, entry))
+
+	return Transition{Entry: entry, Changed: true, Notify: notify}
+}
+
+// seenWithin reports whether the from->to transition is already recorded less than DedupWindow before now.
+func seenWithin(history []v1alpha1.GPUDeviceStateTransition, from, to v1alpha1.GPUDeviceState, now time.Time) bool {
+	for _, entry := range history {
+		if entry.From == from && entry.To == to && now.Sub(entry.Time.Time) < DedupWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify emits the event for a transition returned by SetState unless it was deduplicated.
+func Notify(recorder eventrecord.EventRecorderLogger, log logr.Logger, device *v1alpha1.GPUDevice, transition Transition) {
+	if recorder == nil || !transition.Notify {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	if transition.Entry.To == v1alpha1.GPUDeviceStateFaulted {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.WithLogging(log).Eventf(
+		device,
+		eventType,
+		EventStateChanged,
+		"GPU device state changed from %s to %s (%s)",
+		transition.Entry.From,
+		transition.Entry.To,
+		transition.Entry.Reason,
+	)
+}
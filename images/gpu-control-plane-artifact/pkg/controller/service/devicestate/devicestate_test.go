@@ -0,0 +1,194 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devicestate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder { return p.recorder }
+
+func newDevice(state v1alpha1.GPUDeviceState) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-0"},
+		Status:     v1alpha1.GPUDeviceStatus{State: state},
+	}
+}
+
+func TestSetStateNoop(t *testing.T) {
+	device := newDevice(v1alpha1.GPUDeviceStateReady)
+	if tr := SetState(device, v1alpha1.GPUDeviceStateReady, "Same", time.Now()); tr.Changed || tr.Notify {
+		t.Fatalf("expected no transition, got %+v", tr)
+	}
+	if len(device.Status.History) != 0 {
+		t.Fatalf("expected empty history, got %+v", device.Status.History)
+	}
+}
+
+func TestSetStateInitialisesWithoutHistory(t *testing.T) {
+	device := newDevice("")
+	tr := SetState(device, v1alpha1.GPUDeviceStateDiscovered, "Created", time.Now())
+	if !tr.Changed || tr.Notify {
+		t.Fatalf("unexpected transition %+v", tr)
+	}
+	if device.Status.State != v1alpha1.GPUDeviceStateDiscovered || len(device.Status.History) != 0 {
+		t.Fatalf("unexpected status %+v", device.Status)
+	}
+}
+
+func TestSetStateKeepsForeignState(t *testing.T) {
+	device := newDevice("Quarantined")
+	if !Foreign(device.Status.State) || Foreign("") || Foreign(v1alpha1.GPUDeviceStateFaulted) {
+		t.Fatalf("unexpected Foreign classification")
+	}
+	if tr := SetState(device, v1alpha1.GPUDeviceStateReady, "InfrastructureReady", time.Now()); tr.Changed || tr.Notify {
+		t.Fatalf("expected no transition away from a foreign state, got %+v", tr)
+	}
+	if device.Status.State != "Quarantined" || len(device.Status.History) != 0 {
+		t.Fatalf("expected foreign state to be kept, got %+v", device.Status)
+	}
+}
+
+func TestSetStateTruncatesHistory(t *testing.T) {
+	device := newDevice(v1alpha1.GPUDeviceStateDiscovered)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	states := []v1alpha1.GPUDeviceState{
+		v1alpha1.GPUDeviceStateValidating,
+		v1alpha1.GPUDeviceStateReady,
+		v1alpha1.GPUDeviceStatePendingAssignment,
+		v1alpha1.GPUDeviceStateAssigned,
+	}
+	for i := 0; i < 14; i++ {
+		SetState(device, states[i%len(states)], "Step", base.Add(time.Duration(i)*time.Hour))
+	}
+
+	history := device.Status.History
+	if len(history) != HistoryLimit {
+		t.Fatalf("expected %d entries, got %d", HistoryLimit, len(history))
+	}
+	if !history[0].Time.Time.Equal(base.Add(4*time.Hour)) || !history[HistoryLimit-1].Time.Time.Equal(base.Add(13*time.Hour)) {
+		t.Fatalf("expected the newest entries oldest first, got %v .. %v", history[0].Time, history[HistoryLimit-1].Time)
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].From != history[i-1].To {
+			t.Fatalf("history is not contiguous at %d: %+v", i, history)
+		}
+	}
+	if cap(history) != HistoryLimit {
+		t.Fatalf("expected trimmed history to drop the old backing array, cap=%d", cap(history))
+	}
+}
+
+func TestSetStateDeduplicatesWithinWindow(t *testing.T) {
+	device := newDevice(v1alpha1.GPUDeviceStateReady)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if tr := SetState(device, v1alpha1.GPUDeviceStateFaulted, "XidError", now); !tr.Notify {
+		t.Fatalf("expected first transition to notify")
+	}
+	if tr := SetState(device, v1alpha1.GPUDeviceStateReady, "Recovered", now.Add(time.Second)); !tr.Notify {
+		t.Fatalf("expected reverse transition to notify")
+	}
+	if tr := SetState(device, v1alpha1.GPUDeviceStateFaulted, "XidError", now.Add(time.Minute)); tr.Notify || !tr.Changed {
+		t.Fatalf("expected repeated transition inside the window to be recorded silently, got %+v", tr)
+	}
+	if len(device.Status.History) != 3 {
+		t.Fatalf("expected suppressed transitions to stay in history, got %+v", device.Status.History)
+	}
+	SetState(device, v1alpha1.GPUDeviceStateReady, "Recovered", now.Add(2*time.Minute))
+	if tr := SetState(device, v1alpha1.GPUDeviceStateFaulted, "XidError", now.Add(time.Minute+DedupWindow)); !tr.Notify {
+		t.Fatalf("expected transition to notify once the window elapsed")
+	}
+}
+
+func TestSetStateFlappingStaysSilentUntilSettled(t *testing.T) {
+	device := newDevice(v1alpha1.GPUDeviceStateReady)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	flap := func(start time.Time, steps int) int {
+		notified := 0
+		for i := 0; i < steps; i++ {
+			target := v1alpha1.GPUDeviceStateFaulted
+			if device.Status.State == v1alpha1.GPUDeviceStateFaulted {
+				target = v1alpha1.GPUDeviceStateReady
+			}
+			if SetState(device, target, "Flap", start.Add(time.Duration(i)*30*time.Second)).Notify {
+				notified++
+			}
+		}
+		return notified
+	}
+
+	// Twenty minutes of flapping every 30s: only the first Ready->Faulted and Faulted->Ready fire.
+	if got := flap(now, 40); got != 2 {
+		t.Fatalf("expected 2 events while flapping, got %d", got)
+	}
+	if len(device.Status.History) != HistoryLimit {
+		t.Fatalf("history must stay bounded while flapping, got %d", len(device.Status.History))
+	}
+	// Once the device settles for longer than the window the next flap is reported again.
+	if got := flap(now.Add(20*time.Minute+DedupWindow), 2); got != 2 {
+		t.Fatalf("expected events after the device settled, got %d", got)
+	}
+}
+
+func TestSetStateDedupSurvivesRestart(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	device := newDevice(v1alpha1.GPUDeviceStateReady)
+	SetState(device, v1alpha1.GPUDeviceStateFaulted, "XidError", now)
+	SetState(device, v1alpha1.GPUDeviceStateReady, "Recovered", now.Add(time.Second))
+
+	// A fresh controller only sees the persisted status.
+	restored := device.DeepCopy()
+	if tr := SetState(restored, v1alpha1.GPUDeviceStateFaulted, "XidError", now.Add(time.Minute)); tr.Notify {
+		t.Fatalf("expected persisted history to suppress the event after restart")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	fake := record.NewFakeRecorder(4)
+	recorder := eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: fake}, "test")
+	device := newDevice(v1alpha1.GPUDeviceStateReady)
+	now := time.Now()
+
+	Notify(recorder, testr.New(t), device, SetState(device, v1alpha1.GPUDeviceStateFaulted, "XidError", now))
+	Notify(recorder, testr.New(t), device, SetState(device, v1alpha1.GPUDeviceStateReady, "Recovered", now))
+	Notify(recorder, testr.New(t), device, SetState(device, v1alpha1.GPUDeviceStateFaulted, "XidError", now))
+	Notify(nil, testr.New(t), device, Transition{Notify: true})
+
+	if len(fake.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(fake.Events))
+	}
+	first := <-fake.Events
+	if !strings.HasPrefix(first, "Warning "+EventStateChanged) || !strings.Contains(first, "from Ready to Faulted (XidError)") {
+		t.Fatalf("unexpected event %q", first)
+	}
+	if second := <-fake.Events; !strings.HasPrefix(second, "Normal "+EventStateChanged) {
+		t.Fatalf("unexpected event %q", second)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gfdextender renders and maintains the gfd-extender DaemonSet whose detections the inventory controller
+// scrapes. The controller owns it so the extender always runs the build that matches the controller.
+package gfdextender
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+)
+
+const (
+	// AuthModeTokenReview makes the extender validate scrape tokens through the TokenReview API.
+	AuthModeTokenReview = "TokenReview"
+	// AuthModeNone serves detections without authentication.
+	AuthModeNone = "None"
+
+	defaultHostSysPath = "/sys"
+)
+
+// Config carries the gfd-extender settings the module templates pass to the controller.
+type Config struct {
+	// Enabled is bootstrap.gfd.gfdExtender.enabled; a disabled extender is removed.
+	Enabled   bool
+	Namespace string
+	Image     string
+	Port      int32
+	AuthMode  string
+	// AllowedUsers is the identity admitted by TokenReview, the controller service account.
+	AllowedUsers string
+	HostSysPath  string
+	// LegacyPrivileged is the legacyPrivileged module setting; it renders the privileged container of earlier releases.
+	LegacyPrivileged bool
+}
+
+// DefaultsFromEnv reads the gfd-extender settings from the controller environment.
+func DefaultsFromEnv() Config {
+	ns := strings.TrimSpace(os.Getenv("POD_NAMESPACE"))
+	if ns == "" {
+		ns = common.WorkloadsNamespace
+	}
+	enabled := true
+	if raw := strings.TrimSpace(os.Getenv("GFD_EXTENDER_ENABLED")); raw != "" {
+		if parsed, err := strconv.ParseBool(raw); err == nil {
+			enabled = parsed
+		}
+	}
+	port := detection.DefaultPort
+	if raw := strings.TrimSpace(os.Getenv("GFD_EXTENDER_PORT")); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 32); err == nil && parsed > 0 {
+			port = int32(parsed)
+		}
+	}
+	authMode := AuthModeTokenReview
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("GFD_EXTENDER_AUTH_MODE")), AuthModeNone) {
+		authMode = AuthModeNone
+	}
+	hostSys := strings.TrimSpace(os.Getenv("GFD_EXTENDER_HOST_SYS_PATH"))
+	if hostSys == "" {
+		hostSys = defaultHostSysPath
+	}
+	return Config{
+		Enabled:      enabled,
+		Namespace:    ns,
+		Image:        strings.TrimSpace(os.Getenv("GFD_EXTENDER_IMAGE")),
+		Port:         port,
+		AuthMode:     authMode,
+		AllowedUsers: "system:serviceaccount:" + ns + ":" + common.ControllerDeploymentName,
+		HostSysPath:  hostSys,
+	}
+}
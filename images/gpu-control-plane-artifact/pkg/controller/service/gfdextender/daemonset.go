@@ -0,0 +1,191 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfdextender
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
+)
+
+const (
+	// ContainerName is the container the detection collector looks the port up in.
+	ContainerName = "gfd-extender"
+	// VersionAnnotation carries the controller version on the pod template, so a controller upgrade rolls the
+	// extender to the matching build.
+	VersionAnnotation = "gpu.deckhouse.io/controller-version"
+
+	driverRootVolume = "driver-root"
+	hostSysVolume    = "host-sys"
+	hostDriverRoot   = "/run/nvidia/driver"
+	containerLibPath = "/usr/local/nvidia/lib64:/usr/local/nvidia/lib:/driver-root/usr/lib/x86_64-linux-gnu:/driver-root/usr/lib64:/driver-root/lib64:/driver-root/lib:/driver-root/compat/lib64:/driver-root/compat/lib:/usr/lib/x86_64-linux-gnu:/usr/lib64:/lib64:/lib"
+	containerPath    = "/driver-root/usr/bin:/driver-root/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+	// The host os-release is mounted apart from the image's own, which would describe the container.
+	osReleaseVolume    = "host-os-release"
+	hostOSRelease      = "/etc/os-release"
+	containerOSRelease = "/host-etc/os-release"
+)
+
+// Name is the name of the DaemonSet, its pods' app label and its service account.
+func Name() string {
+	return common.AppName(common.ComponentGFDExtender)
+}
+
+// DaemonSet renders the gfd-extender DaemonSet, restricted to the nodes the managed-node policy selects.
+func DaemonSet(cfg Config, managed moduleconfig.ManagedNodesSettings) *appsv1.DaemonSet {
+	name := Name()
+	selector := map[string]string{"app": name}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cfg.Namespace,
+			Labels: map[string]string{
+				"app":       name,
+				"component": string(common.ComponentGFDExtender),
+				"module":    "gpu-control-plane",
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":       name,
+						"component": string(common.ComponentGFDExtender),
+						"module":    "gpu-control-plane",
+					},
+					Annotations: map[string]string{VersionAnnotation: version.Version()},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: name,
+					RuntimeClassName:   ptr.To("nvidia"),
+					PriorityClassName:  "system-node-critical",
+					NodeSelector:       nodeSelector(managed),
+					Affinity:           nodeAffinity(managed),
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+						{Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+					},
+					SecurityContext: kube.PodSecurityContext(cfg.LegacyPrivileged),
+					Containers:      []corev1.Container{container(cfg)},
+					Volumes: []corev1.Volume{
+						hostPathVolume(hostSysVolume, cfg.HostSysPath, corev1.HostPathDirectory),
+						hostPathVolume(driverRootVolume, hostDriverRoot, corev1.HostPathDirectoryOrCreate),
+						hostPathVolume(osReleaseVolume, hostOSRelease, corev1.HostPathFile),
+					},
+				},
+			},
+		},
+	}
+}
+
+func container(cfg Config) corev1.Container {
+	return corev1.Container{
+		Name:            ContainerName,
+		Image:           cfg.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args:            []string{fmt.Sprintf("--addr=0.0.0.0:%d", cfg.Port)},
+		Env: []corev1.EnvVar{
+			{Name: "GFD_EXTENDER_AUTH_MODE", Value: cfg.AuthMode},
+			{Name: "GFD_EXTENDER_ALLOWED_USERS", Value: cfg.AllowedUsers},
+			{Name: "NVIDIA_VISIBLE_DEVICES", Value: "all"},
+			{Name: "NVIDIA_DRIVER_CAPABILITIES", Value: "compute,utility"},
+			{Name: "LD_LIBRARY_PATH", Value: containerLibPath},
+			{Name: "PATH", Value: containerPath},
+			{Name: "GFD_EXTENDER_SYS_ROOT", Value: cfg.HostSysPath},
+			{Name: "GFD_EXTENDER_OS_RELEASE_PATH", Value: containerOSRelease},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: detection.PortName, ContainerPort: cfg.Port, Protocol: corev1.ProtocolTCP},
+		},
+		SecurityContext: securityContext(cfg.LegacyPrivileged),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("20m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: driverRootVolume, MountPath: "/driver-root", ReadOnly: true, MountPropagation: ptr.To(corev1.MountPropagationHostToContainer)},
+			{Name: hostSysVolume, MountPath: cfg.HostSysPath, ReadOnly: true},
+			{Name: osReleaseVolume, MountPath: containerOSRelease, ReadOnly: true},
+		},
+	}
+}
+
+// securityContext runs the extender unprivileged: the nvidia runtime injects the GPU devices and the host
+// files it reads are mounted read-only.
+func securityContext(legacy bool) *corev1.SecurityContext {
+	if legacy {
+		return kube.LegacyPrivilegedSecurityContext()
+	}
+	return kube.RestrictedSecurityContext()
+}
+
+// nodeSelector pins the extender to explicitly enabled nodes when nodes are opt-in; opt-out is expressed by
+// nodeAffinity, since a selector cannot match an absent label.
+func nodeSelector(managed moduleconfig.ManagedNodesSettings) map[string]string {
+	selector := map[string]string{corev1.LabelOSStable: "linux"}
+	if !managed.EnabledByDefault {
+		selector[labelKey(managed)] = "true"
+	}
+	return selector
+}
+
+func nodeAffinity(managed moduleconfig.ManagedNodesSettings) *corev1.Affinity {
+	if !managed.EnabledByDefault {
+		return nil
+	}
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      labelKey(managed),
+						Operator: corev1.NodeSelectorOpNotIn,
+						Values:   []string{"false"},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func labelKey(managed moduleconfig.ManagedNodesSettings) string {
+	if managed.LabelKey != "" {
+		return managed.LabelKey
+	}
+	return moduleconfig.DefaultNodeLabelKey
+}
+
+func hostPathVolume(name, path string, kind corev1.HostPathType) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{Path: path, Type: ptr.To(kind)},
+		},
+	}
+}
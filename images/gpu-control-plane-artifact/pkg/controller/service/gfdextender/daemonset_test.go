@@ -0,0 +1,174 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfdextender
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
+)
+
+func testConfig() Config {
+	return Config{
+		Enabled:      true,
+		Namespace:    common.WorkloadsNamespace,
+		Image:        "registry.example/gfd-extender:v1",
+		Port:         detection.DefaultPort,
+		AuthMode:     AuthModeTokenReview,
+		AllowedUsers: "system:serviceaccount:d8-gpu-control-plane:gpu-control-plane-controller",
+		HostSysPath:  "/sys",
+	}
+}
+
+func TestDaemonSetRender(t *testing.T) {
+	ds := DaemonSet(testConfig(), moduleconfig.ManagedNodesSettings{LabelKey: "gpu.deckhouse.io/enabled", EnabledByDefault: true})
+
+	if ds.Name != Name() || ds.Namespace != common.WorkloadsNamespace {
+		t.Fatalf("unexpected DaemonSet identity %s/%s", ds.Namespace, ds.Name)
+	}
+	pod := ds.Spec.Template
+	if pod.Labels["app"] != Name() || ds.Spec.Selector.MatchLabels["app"] != Name() {
+		t.Fatalf("pod labels and selector must use the app name, got %v / %v", pod.Labels, ds.Spec.Selector.MatchLabels)
+	}
+	if pod.Annotations[VersionAnnotation] != version.Version() {
+		t.Fatalf("expected controller version annotation, got %q", pod.Annotations[VersionAnnotation])
+	}
+	if pod.Spec.ServiceAccountName != Name() {
+		t.Fatalf("unexpected service account %q", pod.Spec.ServiceAccountName)
+	}
+	if len(pod.Spec.Containers) != 1 || pod.Spec.Containers[0].Name != ContainerName {
+		t.Fatalf("expected a single %s container, got %+v", ContainerName, pod.Spec.Containers)
+	}
+	c := pod.Spec.Containers[0]
+	if c.Image != "registry.example/gfd-extender:v1" {
+		t.Fatalf("unexpected image %q", c.Image)
+	}
+	if len(c.Ports) != 1 || c.Ports[0].Name != detection.PortName || c.Ports[0].ContainerPort != detection.DefaultPort {
+		t.Fatalf("unexpected ports %+v", c.Ports)
+	}
+	if len(c.Args) != 1 || c.Args[0] != "--addr=0.0.0.0:2376" {
+		t.Fatalf("unexpected args %v", c.Args)
+	}
+	env := map[string]string{}
+	for _, item := range c.Env {
+		env[item.Name] = item.Value
+	}
+	if env["GFD_EXTENDER_AUTH_MODE"] != AuthModeTokenReview || env["GFD_EXTENDER_ALLOWED_USERS"] == "" {
+		t.Fatalf("unexpected auth env %v", env)
+	}
+	if env["GFD_EXTENDER_SYS_ROOT"] != "/sys" || env["GFD_EXTENDER_OS_RELEASE_PATH"] != containerOSRelease {
+		t.Fatalf("unexpected platform env %v", env)
+	}
+	mounted := false
+	for _, m := range c.VolumeMounts {
+		if m.Name == osReleaseVolume && m.MountPath == containerOSRelease && m.ReadOnly {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Fatalf("expected the host os-release to be mounted read-only, got %+v", c.VolumeMounts)
+	}
+}
+
+func TestDaemonSetSecurityContext(t *testing.T) {
+	managed := moduleconfig.ManagedNodesSettings{LabelKey: "gpu.deckhouse.io/enabled", EnabledByDefault: true}
+	pod := DaemonSet(testConfig(), managed).Spec.Template.Spec
+	if sc := pod.SecurityContext; sc.SeccompProfile == nil || sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Fatalf("expected the RuntimeDefault seccomp profile on the pod, got %+v", sc)
+	}
+	sc := pod.Containers[0].SecurityContext
+	if *sc.Privileged || *sc.AllowPrivilegeEscalation || !*sc.ReadOnlyRootFilesystem || sc.Capabilities == nil || len(sc.Capabilities.Add) != 0 {
+		t.Fatalf("expected a restricted extender container, got %+v", sc)
+	}
+
+	cfg := testConfig()
+	cfg.LegacyPrivileged = true
+	pod = DaemonSet(cfg, managed).Spec.Template.Spec
+	if pod.SecurityContext.SeccompProfile != nil || !*pod.Containers[0].SecurityContext.Privileged {
+		t.Fatalf("expected the legacy privileged extender, got %+v %+v", pod.SecurityContext, pod.Containers[0].SecurityContext)
+	}
+}
+
+func TestDaemonSetNodeSelectorOptIn(t *testing.T) {
+	ds := DaemonSet(testConfig(), moduleconfig.ManagedNodesSettings{LabelKey: "example.com/gpu", EnabledByDefault: false})
+
+	spec := ds.Spec.Template.Spec
+	if spec.NodeSelector["example.com/gpu"] != "true" || spec.NodeSelector[corev1.LabelOSStable] != "linux" {
+		t.Fatalf("expected opt-in label in node selector, got %v", spec.NodeSelector)
+	}
+	if spec.Affinity != nil {
+		t.Fatalf("expected no affinity for opt-in nodes, got %+v", spec.Affinity)
+	}
+}
+
+func TestDaemonSetNodeAffinityOptOut(t *testing.T) {
+	ds := DaemonSet(testConfig(), moduleconfig.ManagedNodesSettings{EnabledByDefault: true})
+
+	spec := ds.Spec.Template.Spec
+	if _, ok := spec.NodeSelector[moduleconfig.DefaultNodeLabelKey]; ok {
+		t.Fatalf("opt-out nodes must not be selected by label, got %v", spec.NodeSelector)
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil {
+		t.Fatalf("expected node affinity excluding disabled nodes")
+	}
+	expr := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0]
+	if expr.Key != moduleconfig.DefaultNodeLabelKey || expr.Operator != corev1.NodeSelectorOpNotIn || len(expr.Values) != 1 || expr.Values[0] != "false" {
+		t.Fatalf("unexpected affinity expression %+v", expr)
+	}
+}
+
+func TestDefaultsFromEnvImageOverride(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "gpu-ns")
+	t.Setenv("GFD_EXTENDER_IMAGE", " registry.example/gfd-extender@sha256:abc ")
+	t.Setenv("GFD_EXTENDER_PORT", "3000")
+	t.Setenv("GFD_EXTENDER_AUTH_MODE", "none")
+	t.Setenv("GFD_EXTENDER_ENABLED", "false")
+
+	cfg := DefaultsFromEnv()
+	if cfg.Image != "registry.example/gfd-extender@sha256:abc" || cfg.Namespace != "gpu-ns" || cfg.Port != 3000 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.AuthMode != AuthModeNone || cfg.Enabled {
+		t.Fatalf("expected auth disabled and extender disabled, got %+v", cfg)
+	}
+	if cfg.AllowedUsers != "system:serviceaccount:gpu-ns:"+common.ControllerDeploymentName {
+		t.Fatalf("unexpected allowed users %q", cfg.AllowedUsers)
+	}
+	if got := DaemonSet(cfg, moduleconfig.ManagedNodesSettings{}).Spec.Template.Spec.Containers[0].Image; got != cfg.Image {
+		t.Fatalf("expected rendered image %q, got %q", cfg.Image, got)
+	}
+}
+
+func TestDefaultsFromEnvFallbacks(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "")
+	t.Setenv("GFD_EXTENDER_IMAGE", "")
+	t.Setenv("GFD_EXTENDER_PORT", "bad")
+	t.Setenv("GFD_EXTENDER_AUTH_MODE", "")
+	t.Setenv("GFD_EXTENDER_ENABLED", "")
+	t.Setenv("GFD_EXTENDER_HOST_SYS_PATH", "")
+
+	cfg := DefaultsFromEnv()
+	if !cfg.Enabled || cfg.Namespace != common.WorkloadsNamespace || cfg.Port != detection.DefaultPort {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if cfg.AuthMode != AuthModeTokenReview || cfg.HostSysPath != "/sys" || cfg.Image != "" {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+}
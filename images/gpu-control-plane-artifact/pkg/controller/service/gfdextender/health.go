@@ -0,0 +1,58 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfdextender
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionReady is the module-level condition reporting the DaemonSet health.
+	ConditionReady = "GFDExtenderReady"
+
+	ReasonReady          = "Ready"
+	ReasonNotDeployed    = "NotDeployed"
+	ReasonRollingUpdate  = "RollingUpdate"
+	ReasonPodsNotReady   = "PodsNotReady"
+	ReasonNoManagedNodes = "NoManagedNodes"
+)
+
+// ReadyCondition summarises the DaemonSet status; a nil DaemonSet is reported as not deployed.
+func ReadyCondition(ds *appsv1.DaemonSet) metav1.Condition {
+	cond := metav1.Condition{Type: ConditionReady, Status: metav1.ConditionFalse}
+	switch {
+	case ds == nil:
+		cond.Reason = ReasonNotDeployed
+		cond.Message = "gfd-extender DaemonSet is not deployed"
+	case ds.Status.ObservedGeneration < ds.Generation || ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled:
+		cond.Reason = ReasonRollingUpdate
+		cond.Message = fmt.Sprintf("%d of %d pods run the current template", ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)
+	case ds.Status.DesiredNumberScheduled == 0:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = ReasonNoManagedNodes
+		cond.Message = "no managed GPU nodes to schedule on"
+	case ds.Status.NumberReady < ds.Status.DesiredNumberScheduled:
+		cond.Reason = ReasonPodsNotReady
+		cond.Message = fmt.Sprintf("%d of %d pods are ready", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+	default:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = ReasonReady
+		cond.Message = fmt.Sprintf("%d pods are ready", ds.Status.NumberReady)
+	}
+	return cond
+}
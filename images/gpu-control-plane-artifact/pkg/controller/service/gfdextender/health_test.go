@@ -0,0 +1,58 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfdextender
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadyCondition(t *testing.T) {
+	daemonSet := func(generation, observed int64, desired, updated, ready int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     observed,
+				DesiredNumberScheduled: desired,
+				UpdatedNumberScheduled: updated,
+				NumberReady:            ready,
+			},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		ds     *appsv1.DaemonSet
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{name: "missing", ds: nil, status: metav1.ConditionFalse, reason: ReasonNotDeployed},
+		{name: "stale generation", ds: daemonSet(2, 1, 3, 3, 3), status: metav1.ConditionFalse, reason: ReasonRollingUpdate},
+		{name: "rolling", ds: daemonSet(1, 1, 3, 1, 3), status: metav1.ConditionFalse, reason: ReasonRollingUpdate},
+		{name: "no nodes", ds: daemonSet(1, 1, 0, 0, 0), status: metav1.ConditionTrue, reason: ReasonNoManagedNodes},
+		{name: "pods not ready", ds: daemonSet(1, 1, 3, 3, 2), status: metav1.ConditionFalse, reason: ReasonPodsNotReady},
+		{name: "ready", ds: daemonSet(1, 1, 3, 3, 3), status: metav1.ConditionTrue, reason: ReasonReady},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cond := ReadyCondition(tc.ds)
+			if cond.Type != ConditionReady || cond.Status != tc.status || cond.Reason != tc.reason {
+				t.Fatalf("unexpected condition %+v", cond)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfdextender
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/ops"
+)
+
+// Reconcile renders the DaemonSet while both the module and the extender are enabled and removes it otherwise.
+// The guard claims the DaemonSet for this instance; nil writes it without a claim.
+func Reconcile(ctx context.Context, c client.Client, guard *ownership.Guard, cfg Config, state moduleconfig.State) error {
+	if !state.Enabled || !cfg.Enabled {
+		return Cleanup(ctx, c, cfg.Namespace)
+	}
+	if cfg.Image == "" {
+		return fmt.Errorf("gfd-extender image is not configured")
+	}
+	cfg.LegacyPrivileged = state.Settings.Security.LegacyPrivileged
+	return ops.CreateOrUpdate(ctx, c, guard, DaemonSet(cfg, state.Settings.ManagedNodes), nil)
+}
+
+// Cleanup removes the DaemonSet; its pods go with it.
+func Cleanup(ctx context.Context, c client.Client, namespace string) error {
+	return commonobject.DeleteObject(ctx, c, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: Name(), Namespace: namespace}})
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gfdextender
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add apps scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func enabledState() moduleconfig.State {
+	state := moduleconfig.DefaultState()
+	state.Enabled = true
+	return state
+}
+
+func getDaemonSet(t *testing.T, cl client.Client) (*appsv1.DaemonSet, bool) {
+	t.Helper()
+	ds := &appsv1.DaemonSet{}
+	err := cl.Get(context.Background(), client.ObjectKey{Namespace: testConfig().Namespace, Name: Name()}, ds)
+	if apierrors.IsNotFound(err) {
+		return nil, false
+	}
+	if err != nil {
+		t.Fatalf("get DaemonSet: %v", err)
+	}
+	return ds, true
+}
+
+func TestReconcileCreatesAndUpdatesDaemonSet(t *testing.T) {
+	cl := newClient(t)
+	cfg := testConfig()
+
+	if err := Reconcile(context.Background(), cl, nil, cfg, enabledState()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	ds, ok := getDaemonSet(t, cl)
+	if !ok || len(ds.OwnerReferences) != 0 {
+		t.Fatalf("expected DaemonSet without owner references, got %+v", ds)
+	}
+
+	cfg.Image = "registry.example/gfd-extender:v2"
+	if err := Reconcile(context.Background(), cl, nil, cfg, enabledState()); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	ds, _ = getDaemonSet(t, cl)
+	if got := ds.Spec.Template.Spec.Containers[0].Image; got != cfg.Image {
+		t.Fatalf("expected image to follow the controller settings, got %q", got)
+	}
+}
+
+func TestReconcileRequiresImage(t *testing.T) {
+	cl := newClient(t)
+	cfg := testConfig()
+	cfg.Image = ""
+
+	if err := Reconcile(context.Background(), cl, nil, cfg, enabledState()); err == nil {
+		t.Fatalf("expected error without an image")
+	}
+	if _, ok := getDaemonSet(t, cl); ok {
+		t.Fatalf("expected no DaemonSet without an image")
+	}
+}
+
+func TestReconcileCleansUpOnDisable(t *testing.T) {
+	existing := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: Name(), Namespace: testConfig().Namespace}}
+
+	t.Run("module disabled", func(t *testing.T) {
+		cl := newClient(t, existing.DeepCopy())
+		if err := Reconcile(context.Background(), cl, nil, testConfig(), moduleconfig.DefaultState()); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		if _, ok := getDaemonSet(t, cl); ok {
+			t.Fatalf("expected DaemonSet to be removed when the module is disabled")
+		}
+	})
+
+	t.Run("extender disabled", func(t *testing.T) {
+		cl := newClient(t, existing.DeepCopy())
+		cfg := testConfig()
+		cfg.Enabled = false
+		if err := Reconcile(context.Background(), cl, nil, cfg, enabledState()); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		if _, ok := getDaemonSet(t, cl); ok {
+			t.Fatalf("expected DaemonSet to be removed when the extender is disabled")
+		}
+	})
+
+	t.Run("already absent", func(t *testing.T) {
+		if err := Cleanup(context.Background(), newClient(t), testConfig().Namespace); err != nil {
+			t.Fatalf("Cleanup: %v", err)
+		}
+	})
+}
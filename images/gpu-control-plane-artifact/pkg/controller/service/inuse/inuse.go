@@ -0,0 +1,86 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inuse decides whether a GPUDevice is still used by a workload and keeps the in-use-protection
+// finalizer in step with that decision.
+package inuse
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+const (
+	// FinalizerName keeps a GPUDevice while a pool has it reserved or attached.
+	FinalizerName = "gpu.deckhouse.io/in-use-protection"
+	// ForceDeleteAnnotation set to "true" lifts the protection, for emergencies such as a lost node.
+	ForceDeleteAnnotation = "gpu.deckhouse.io/force-delete"
+
+	// ConditionDeletionBlocked is set on a device whose deletion waits for it to be released.
+	ConditionDeletionBlocked = "DeletionBlocked"
+	// ReasonDeviceInUse explains DeletionBlocked and the deferred removal of a device by the inventory.
+	ReasonDeviceInUse = "DeviceInUse"
+	// EventDeletionBlocked is recorded when a protected device is deleted.
+	EventDeletionBlocked = "GPUDeviceDeletionBlocked"
+	// EventForceDeleted is recorded when the protection of a device is lifted through ForceDeleteAnnotation.
+	EventForceDeleted = "GPUDeviceForceDeleted"
+)
+
+// InUse reports whether a workload may depend on the device: it is reserved for, or attached to, a pool.
+func InUse(device *v1alpha1.GPUDevice) bool {
+	switch device.Status.State {
+	case v1alpha1.GPUDeviceStateReserved, v1alpha1.GPUDeviceStateAssigned, v1alpha1.GPUDeviceStateInUse:
+		return true
+	}
+	return false
+}
+
+// Forced reports whether the protection of the device was lifted through ForceDeleteAnnotation.
+func Forced(device *v1alpha1.GPUDevice) bool {
+	return strings.TrimSpace(device.Annotations[ForceDeleteAnnotation]) == "true"
+}
+
+// Protected reports whether the device must not be deleted yet.
+func Protected(device *v1alpha1.GPUDevice) bool {
+	return InUse(device) && !Forced(device)
+}
+
+// SyncFinalizer adds the finalizer to a protected device and removes it from any other, and reports whether the
+// finalizers changed. A device already being deleted never gets the finalizer added.
+func SyncFinalizer(device *v1alpha1.GPUDevice) bool {
+	if !Protected(device) {
+		return controllerutil.RemoveFinalizer(device, FinalizerName)
+	}
+	if device.DeletionTimestamp != nil {
+		return false
+	}
+	return controllerutil.AddFinalizer(device, FinalizerName)
+}
+
+// Describe explains what still uses the device and how to release it.
+func Describe(device *v1alpha1.GPUDevice) string {
+	holder := "a GPU pool"
+	if ref := device.Status.PoolRef; ref != nil && ref.Name != "" {
+		holder = "pool " + ref.Name
+		if ref.Namespace != "" {
+			holder = "pool " + ref.Namespace + "/" + ref.Name
+		}
+	}
+	return fmt.Sprintf("device is %s in %s; release it from the pool or annotate it with %s=true to remove it anyway",
+		device.Status.State, holder, ForceDeleteAnnotation)
+}
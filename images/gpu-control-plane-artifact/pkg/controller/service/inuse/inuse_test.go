@@ -0,0 +1,89 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inuse
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestProtected(t *testing.T) {
+	cases := []struct {
+		state  v1alpha1.GPUDeviceState
+		forced bool
+		want   bool
+	}{
+		{state: v1alpha1.GPUDeviceStateReady},
+		{state: v1alpha1.GPUDeviceStatePendingAssignment},
+		{state: v1alpha1.GPUDeviceStateFaulted},
+		{state: v1alpha1.GPUDeviceStateReserved, want: true},
+		{state: v1alpha1.GPUDeviceStateAssigned, want: true},
+		{state: v1alpha1.GPUDeviceStateInUse, want: true},
+		{state: v1alpha1.GPUDeviceStateInUse, forced: true},
+	}
+	for _, tc := range cases {
+		device := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{State: tc.state}}
+		if tc.forced {
+			device.Annotations = map[string]string{ForceDeleteAnnotation: " true "}
+		}
+		if got := Protected(device); got != tc.want {
+			t.Fatalf("Protected(%s, forced=%v) = %v, want %v", tc.state, tc.forced, got, tc.want)
+		}
+	}
+}
+
+func TestSyncFinalizer(t *testing.T) {
+	device := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateAssigned}}
+	if !SyncFinalizer(device) || !controllerutil.ContainsFinalizer(device, FinalizerName) {
+		t.Fatalf("expected finalizer to be added")
+	}
+	if SyncFinalizer(device) {
+		t.Fatalf("expected no change on a second sync")
+	}
+
+	device.Status.State = v1alpha1.GPUDeviceStateReady
+	if !SyncFinalizer(device) || controllerutil.ContainsFinalizer(device, FinalizerName) {
+		t.Fatalf("expected finalizer to be removed from a released device")
+	}
+
+	now := metav1.Now()
+	deleting := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+		Status:     v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateInUse},
+	}
+	if SyncFinalizer(deleting) || controllerutil.ContainsFinalizer(deleting, FinalizerName) {
+		t.Fatalf("expected no finalizer to be added to a device being deleted")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	device := &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{State: v1alpha1.GPUDeviceStateAssigned}}
+	if got := Describe(device); !strings.Contains(got, "a GPU pool") || !strings.Contains(got, ForceDeleteAnnotation) {
+		t.Fatalf("unexpected description without pool: %q", got)
+	}
+	device.Status.PoolRef = &v1alpha1.GPUPoolReference{Name: "shared"}
+	if got := Describe(device); !strings.Contains(got, "pool shared;") {
+		t.Fatalf("unexpected description for a cluster pool: %q", got)
+	}
+	device.Status.PoolRef.Namespace = "team"
+	if got := Describe(device); !strings.Contains(got, "pool team/shared;") {
+		t.Fatalf("unexpected description for a namespaced pool: %q", got)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nfdapi checks that the cluster serves the NodeFeature API version the controller is built against.
+package nfdapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+)
+
+const (
+	// Kind is the NodeFeature kind the inventory watches.
+	Kind = "NodeFeature"
+
+	// ConditionServed is the module-level condition reporting whether the compiled NodeFeature version is served.
+	ConditionServed = "NodeFeatureAPIServed"
+
+	ReasonServed          = "Served"
+	ReasonUnsupported     = "DiscoveryAPIUnsupported"
+	ReasonDiscoveryFailed = "DiscoveryFailed"
+
+	// RecheckInterval is how often the discovery API is queried again, so installing or upgrading NFD is picked
+	// up without a controller restart.
+	RecheckInterval = time.Minute
+)
+
+type discoveryClient interface {
+	ServerGroups() (*metav1.APIGroupList, error)
+	ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error)
+}
+
+// Checker tracks the NodeFeature versions served by the cluster. Until the first successful check the compiled
+// version is assumed to be served, and a failed check keeps the previous verdict.
+type Checker struct {
+	compiled schema.GroupVersion
+	interval time.Duration
+
+	mu        sync.RWMutex
+	log       logr.Logger
+	discovery discoveryClient
+	metrics   *invmetrics.Metrics
+	checked   bool
+	served    []string
+	supported bool
+	lastErr   error
+	recovered []func()
+}
+
+// NewChecker builds a checker that records no metrics; a nil discovery client makes Check a no-op.
+func NewChecker(log logr.Logger, dc discoveryClient) *Checker {
+	return &Checker{
+		compiled:  nfdv1alpha1.SchemeGroupVersion,
+		interval:  RecheckInterval,
+		log:       log,
+		discovery: dc,
+		supported: true,
+	}
+}
+
+// Default is the checker consulted by the inventory controller and the module status.
+var Default = NewChecker(logr.Discard(), nil)
+
+// SetupChecker points Default at the cluster discovery API and the default inventory metrics, runs the first check
+// before the controllers are registered and keeps re-checking while the manager runs.
+func SetupChecker(mgr ctrl.Manager, log logr.Logger) error {
+	cfg := mgr.GetConfig()
+	if cfg == nil {
+		log.V(1).Info("no REST config, NodeFeature API check disabled")
+		return nil
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create discovery client: %w", err)
+	}
+	Default.bind(log.WithName("nodefeature-api"), dc, invmetrics.Default())
+	if err := Default.Check(); err != nil {
+		Default.log.Error(err, "NodeFeature API check failed, assuming the compiled version is served")
+	}
+	if err := mgr.Add(Default); err != nil {
+		return fmt.Errorf("add NodeFeature API checker: %w", err)
+	}
+	return nil
+}
+
+func (c *Checker) bind(log logr.Logger, dc discoveryClient, metrics *invmetrics.Metrics) {
+	c.mu.Lock()
+	c.log = log
+	c.discovery = dc
+	c.metrics = metrics
+	c.mu.Unlock()
+}
+
+// NeedLeaderElection is false: every replica registers its own watches.
+func (c *Checker) NeedLeaderElection() bool {
+	return false
+}
+
+// Start re-checks the discovery API until the context is cancelled.
+func (c *Checker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := c.Check(); err != nil {
+			c.log.V(1).Info("NodeFeature API check failed", "error", err.Error())
+		}
+	}
+}
+
+// Check queries the discovery API once. Listeners registered with OnRecovered run when the compiled version becomes
+// served after a check found it missing.
+func (c *Checker) Check() error {
+	c.mu.RLock()
+	dc := c.discovery
+	c.mu.RUnlock()
+	if dc == nil {
+		return nil
+	}
+
+	served, supported, err := c.query(dc)
+
+	c.mu.Lock()
+	if err != nil {
+		c.lastErr = err
+		c.mu.Unlock()
+		return err
+	}
+	changed := !c.checked || c.supported != supported || !slices.Equal(c.served, served)
+	recovered := c.checked && !c.supported && supported
+	c.checked, c.served, c.supported, c.lastErr = true, served, supported, nil
+	listeners := slices.Clone(c.recovered)
+	log, metrics := c.log, c.metrics
+	c.mu.Unlock()
+
+	metrics.NodeFeatureAPISet(served, c.compiled.Version, supported)
+	if changed {
+		if supported {
+			log.Info("NodeFeature API served", "served", served, "compiled", c.compiled.String())
+		} else {
+			log.Info("NodeFeature API version the controller is built against is not served, NodeFeatures are ignored", "served", served, "compiled", c.compiled.String())
+		}
+	}
+	if recovered {
+		for _, fn := range listeners {
+			fn()
+		}
+	}
+	return nil
+}
+
+func (c *Checker) query(dc discoveryClient) ([]string, bool, error) {
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return nil, false, fmt.Errorf("list API groups: %w", err)
+	}
+	served := []string{}
+	for _, group := range groups.Groups {
+		if group.Name != c.compiled.Group {
+			continue
+		}
+		for _, version := range group.Versions {
+			served = append(served, version.Version)
+		}
+	}
+	slices.Sort(served)
+	if !slices.Contains(served, c.compiled.Version) {
+		return served, false, nil
+	}
+
+	resources, err := dc.ServerResourcesForGroupVersion(c.compiled.String())
+	if apierrors.IsNotFound(err) {
+		return served, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("list %s resources: %w", c.compiled.String(), err)
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == Kind {
+			return served, true, nil
+		}
+	}
+	return served, false, nil
+}
+
+// Supported reports whether the compiled NodeFeature version is served; a nil checker assumes it is.
+func (c *Checker) Supported() bool {
+	if c == nil {
+		return true
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.supported
+}
+
+// OnRecovered registers fn to run every time the compiled version becomes served again.
+func (c *Checker) OnRecovered(fn func()) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.recovered = append(c.recovered, fn)
+	c.mu.Unlock()
+}
+
+// Message describes the served versions for conditions and logs.
+func (c *Checker) Message() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.messageLocked()
+}
+
+func (c *Checker) messageLocked() string {
+	served := "none"
+	if len(c.served) > 0 {
+		served = strings.Join(c.served, ", ")
+	}
+	if c.supported {
+		return fmt.Sprintf("%s/%s is served (served versions: %s)", c.compiled.String(), Kind, served)
+	}
+	return fmt.Sprintf("%s/%s is not served by the cluster (served versions: %s); install or upgrade node-feature-discovery to a release serving it", c.compiled.String(), Kind, served)
+}
+
+// Condition summarises the last check; ok is false until the first check completed or failed.
+func (c *Checker) Condition() (metav1.Condition, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	switch {
+	case c.lastErr != nil:
+		return metav1.Condition{Type: ConditionServed, Status: metav1.ConditionUnknown, Reason: ReasonDiscoveryFailed, Message: c.lastErr.Error()}, true
+	case !c.checked:
+		return metav1.Condition{}, false
+	case c.supported:
+		return metav1.Condition{Type: ConditionServed, Status: metav1.ConditionTrue, Reason: ReasonServed, Message: c.messageLocked()}, true
+	default:
+		return metav1.Condition{Type: ConditionServed, Status: metav1.ConditionFalse, Reason: ReasonUnsupported, Message: c.messageLocked()}, true
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nfdapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func nfdResources(versions ...string) []*metav1.APIResourceList {
+	lists := make([]*metav1.APIResourceList, 0, len(versions))
+	for _, version := range versions {
+		lists = append(lists, &metav1.APIResourceList{
+			GroupVersion: "nfd.k8s-sigs.io/" + version,
+			APIResources: []metav1.APIResource{{Name: "nodefeatures", Kind: Kind, Namespaced: true}},
+		})
+	}
+	return lists
+}
+
+func newFakeDiscovery(versions ...string) *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: nfdResources(versions...)}}
+}
+
+func TestCheckerSupported(t *testing.T) {
+	checker := NewChecker(testr.New(t), newFakeDiscovery("v1alpha1", "v1alpha2"))
+	if _, ok := checker.Condition(); ok {
+		t.Fatalf("expected no condition before the first check")
+	}
+	if err := checker.Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !checker.Supported() {
+		t.Fatalf("expected compiled version to be supported")
+	}
+	cond, ok := checker.Condition()
+	if !ok || cond.Status != metav1.ConditionTrue || cond.Reason != ReasonServed {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+}
+
+func TestCheckerUnsupported(t *testing.T) {
+	checker := NewChecker(testr.New(t), newFakeDiscovery("v1alpha2"))
+	if err := checker.Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if checker.Supported() {
+		t.Fatalf("expected compiled version to be unsupported")
+	}
+	cond, _ := checker.Condition()
+	if cond.Status != metav1.ConditionFalse || cond.Reason != ReasonUnsupported {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+	if got := checker.Message(); got != "nfd.k8s-sigs.io/v1alpha1/NodeFeature is not served by the cluster (served versions: v1alpha2); install or upgrade node-feature-discovery to a release serving it" {
+		t.Fatalf("unexpected message %q", got)
+	}
+
+	missing := NewChecker(testr.New(t), newFakeDiscovery())
+	if err := missing.Check(); err != nil || missing.Supported() {
+		t.Fatalf("expected a cluster without NFD to be unsupported, err=%v", err)
+	}
+}
+
+func TestCheckerUpgradeAtRuntime(t *testing.T) {
+	dc := newFakeDiscovery()
+	checker := NewChecker(testr.New(t), dc)
+	recovered := 0
+	checker.OnRecovered(func() { recovered++ })
+
+	if err := checker.Check(); err != nil || checker.Supported() {
+		t.Fatalf("expected unsupported before NFD is installed, err=%v", err)
+	}
+	dc.Resources = nfdResources("v1alpha1")
+	if err := checker.Check(); err != nil || !checker.Supported() {
+		t.Fatalf("expected support after NFD is installed, err=%v", err)
+	}
+	if err := checker.Check(); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if recovered != 1 {
+		t.Fatalf("expected one recovery notification, got %d", recovered)
+	}
+
+	dc.Resources = nfdResources("v1alpha2")
+	if err := checker.Check(); err != nil || checker.Supported() {
+		t.Fatalf("expected unsupported after NFD dropped the compiled version, err=%v", err)
+	}
+}
+
+// failingDiscovery wraps a discovery client and fails every call while err is set.
+type failingDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	err error
+}
+
+func (d *failingDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.FakeDiscovery.ServerGroups()
+}
+
+func TestCheckerKeepsVerdictOnDiscoveryError(t *testing.T) {
+	dc := &failingDiscovery{FakeDiscovery: newFakeDiscovery(), err: errors.New("connection refused")}
+	checker := NewChecker(testr.New(t), dc)
+	if err := checker.Check(); err == nil {
+		t.Fatalf("expected discovery error")
+	}
+	if !checker.Supported() {
+		t.Fatalf("expected the compiled version to be assumed served until a check succeeds")
+	}
+	cond, ok := checker.Condition()
+	if !ok || cond.Status != metav1.ConditionUnknown || cond.Reason != ReasonDiscoveryFailed {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+
+	dc.err = nil
+	if err := checker.Check(); err != nil || checker.Supported() {
+		t.Fatalf("expected unsupported once discovery answers, err=%v", err)
+	}
+	dc.err = errors.New("timeout")
+	if err := checker.Check(); err == nil || checker.Supported() {
+		t.Fatalf("expected a failed check to keep the previous verdict, err=%v", err)
+	}
+}
+
+func TestCheckerWithoutDiscovery(t *testing.T) {
+	checker := NewChecker(testr.New(t), nil)
+	if err := checker.Check(); err != nil || !checker.Supported() {
+		t.Fatalf("expected checker without discovery client to assume support, err=%v", err)
+	}
+}
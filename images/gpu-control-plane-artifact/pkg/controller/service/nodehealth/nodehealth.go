@@ -0,0 +1,80 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nodehealth rolls the conditions of a GPUNodeState and the states of the node's devices up into a
+// single health class.
+package nodehealth
+
+import (
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+const (
+	conditionInventoryComplete = "InventoryComplete"
+	conditionDriverReady       = "DriverReady"
+
+	// ReasonInventoryIncomplete is reported while InventoryComplete is not True.
+	ReasonInventoryIncomplete = "InventoryIncomplete"
+	// ReasonDriverNotReady is reported while DriverReady is not True.
+	ReasonDriverNotReady = "DriverNotReady"
+	// ReasonDevicesFaulted is reported while any device of the node is Faulted.
+	ReasonDevicesFaulted = "DevicesFaulted"
+)
+
+// Classify returns the health class of a node and, for a Degraded node, why. A node is Healthy when InventoryComplete
+// and DriverReady are True and none of its devices is Faulted. Reasons come in a fixed order and carry the reason of
+// the failing condition, or how many devices are Faulted.
+func Classify(conditions []metav1.Condition, devices []v1alpha1.GPUDevice) (v1alpha1.GPUNodeHealth, []string) {
+	var reasons []string
+	if reason, ok := conditionFailure(conditions, conditionInventoryComplete, ReasonInventoryIncomplete); ok {
+		reasons = append(reasons, reason)
+	}
+	if reason, ok := conditionFailure(conditions, conditionDriverReady, ReasonDriverNotReady); ok {
+		reasons = append(reasons, reason)
+	}
+	faulted := 0
+	for i := range devices {
+		if devices[i].Status.State == v1alpha1.GPUDeviceStateFaulted {
+			faulted++
+		}
+	}
+	if faulted > 0 {
+		reasons = append(reasons, fmt.Sprintf("%s: %d of %d", ReasonDevicesFaulted, faulted, len(devices)))
+	}
+
+	if len(reasons) == 0 {
+		return v1alpha1.GPUNodeHealthHealthy, nil
+	}
+	return v1alpha1.GPUNodeHealthDegraded, reasons
+}
+
+// conditionFailure reports whether conditionType is not True and describes why; a missing condition counts as not True.
+func conditionFailure(conditions []metav1.Condition, conditionType, reason string) (string, bool) {
+	cond := apimeta.FindStatusCondition(conditions, conditionType)
+	switch {
+	case cond == nil:
+		return reason + ": condition missing", true
+	case cond.Status == metav1.ConditionTrue:
+		return "", false
+	case cond.Reason != "":
+		return reason + ": " + cond.Reason, true
+	default:
+		return reason, true
+	}
+}
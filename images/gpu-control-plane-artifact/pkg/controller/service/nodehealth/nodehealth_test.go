@@ -0,0 +1,116 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodehealth
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestClassify(t *testing.T) {
+	inventoryComplete := metav1.Condition{Type: conditionInventoryComplete, Status: metav1.ConditionTrue, Reason: "InventorySynced"}
+	inventoryIncomplete := metav1.Condition{Type: conditionInventoryComplete, Status: metav1.ConditionFalse, Reason: "NodeFeatureMissing"}
+	inventoryUnknown := metav1.Condition{Type: conditionInventoryComplete, Status: metav1.ConditionUnknown}
+	driverReady := metav1.Condition{Type: conditionDriverReady, Status: metav1.ConditionTrue, Reason: "Validated"}
+	driverNotReady := metav1.Condition{Type: conditionDriverReady, Status: metav1.ConditionFalse, Reason: "ValidatorFailed"}
+
+	device := func(state v1alpha1.GPUDeviceState) v1alpha1.GPUDevice {
+		return v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{State: state}}
+	}
+	healthyDevices := []v1alpha1.GPUDevice{device(v1alpha1.GPUDeviceStateReady), device(v1alpha1.GPUDeviceStateInUse)}
+	faultedDevices := []v1alpha1.GPUDevice{device(v1alpha1.GPUDeviceStateFaulted), device(v1alpha1.GPUDeviceStateReady), device(v1alpha1.GPUDeviceStateFaulted)}
+
+	inventoryReasons := map[string]*metav1.Condition{
+		"complete":   &inventoryComplete,
+		"incomplete": &inventoryIncomplete,
+		"unknown":    &inventoryUnknown,
+		"missing":    nil,
+	}
+	driverReasons := map[string]*metav1.Condition{
+		"ready":     &driverReady,
+		"not ready": &driverNotReady,
+		"missing":   nil,
+	}
+	deviceSets := map[string][]v1alpha1.GPUDevice{
+		"no devices": nil,
+		"healthy":    healthyDevices,
+		"faulted":    faultedDevices,
+	}
+	wantInventory := map[string]string{
+		"incomplete": ReasonInventoryIncomplete + ": NodeFeatureMissing",
+		"unknown":    ReasonInventoryIncomplete,
+		"missing":    ReasonInventoryIncomplete + ": condition missing",
+	}
+	wantDriver := map[string]string{
+		"not ready": ReasonDriverNotReady + ": ValidatorFailed",
+		"missing":   ReasonDriverNotReady + ": condition missing",
+	}
+	wantDevices := map[string]string{
+		"faulted": ReasonDevicesFaulted + ": 2 of 3",
+	}
+
+	// Every combination of inventory, driver and device state.
+	for invName, invCond := range inventoryReasons {
+		for drvName, drvCond := range driverReasons {
+			for devName, devices := range deviceSets {
+				t.Run(fmt.Sprintf("inventory %s, driver %s, %s", invName, drvName, devName), func(t *testing.T) {
+					var conditions []metav1.Condition
+					if invCond != nil {
+						conditions = append(conditions, *invCond)
+					}
+					if drvCond != nil {
+						conditions = append(conditions, *drvCond)
+					}
+
+					var want []string
+					for _, reason := range []string{wantInventory[invName], wantDriver[drvName], wantDevices[devName]} {
+						if reason != "" {
+							want = append(want, reason)
+						}
+					}
+					wantClass := v1alpha1.GPUNodeHealthHealthy
+					if len(want) > 0 {
+						wantClass = v1alpha1.GPUNodeHealthDegraded
+					}
+
+					class, reasons := Classify(conditions, devices)
+					if class != wantClass {
+						t.Fatalf("expected class %s, got %s", wantClass, class)
+					}
+					if !slices.Equal(reasons, want) {
+						t.Fatalf("expected reasons %q, got %q", want, reasons)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestClassifyIgnoresUnrelatedConditions(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: conditionInventoryComplete, Status: metav1.ConditionTrue},
+		{Type: conditionDriverReady, Status: metav1.ConditionTrue},
+		{Type: "ToolkitReady", Status: metav1.ConditionFalse},
+		{Type: "MonitoringReady", Status: metav1.ConditionFalse},
+	}
+	if class, reasons := Classify(conditions, nil); class != v1alpha1.GPUNodeHealthHealthy || reasons != nil {
+		t.Fatalf("expected Healthy without reasons, got %s %q", class, reasons)
+	}
+}
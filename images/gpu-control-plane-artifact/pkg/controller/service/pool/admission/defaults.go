@@ -15,6 +15,8 @@
 package admission
 
 import (
+	"strings"
+
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 )
 
@@ -24,6 +26,10 @@ const (
 )
 
 func applyDefaults(spec *v1alpha1.GPUPoolSpec) {
+	spec.Provider = canonicalEnum(spec.Provider, defaultProvider)
+	spec.Backend = canonicalEnum(spec.Backend, "DevicePlugin", "DRA")
+	spec.Resource.Unit = canonicalEnum(spec.Resource.Unit, "Card", "MIG")
+
 	if spec.Provider == "" {
 		spec.Provider = defaultProvider
 	}
@@ -40,3 +46,15 @@ func applyDefaults(spec *v1alpha1.GPUPoolSpec) {
 		spec.Scheduling.TopologyKey = "topology.kubernetes.io/zone"
 	}
 }
+
+// canonicalEnum maps case-insensitive input onto the spelling the CRD enum expects, so "card" is
+// stored as "Card" instead of being rejected by schema validation. Unknown values are only trimmed.
+func canonicalEnum(value string, allowed ...string) string {
+	value = strings.TrimSpace(value)
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return candidate
+		}
+	}
+	return value
+}
@@ -15,6 +15,7 @@
 package admission
 
 import (
+	"reflect"
 	"testing"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
@@ -39,3 +40,45 @@ func TestApplyDefaults(t *testing.T) {
 		t.Fatalf("topologyKey must not be forced for BinPack, got %q", spec.Scheduling.TopologyKey)
 	}
 }
+
+func TestApplyDefaultsCanonicalizesEnums(t *testing.T) {
+	tests := []struct {
+		name string
+		in   v1alpha1.GPUPoolSpec
+		want v1alpha1.GPUPoolSpec
+	}{
+		{
+			name: "lowercase card",
+			in:   v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "card"}},
+			want: v1alpha1.GPUPoolSpec{Provider: defaultProvider, Backend: defaultBackend, Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1}},
+		},
+		{
+			name: "mixed case mig and backend",
+			in: v1alpha1.GPUPoolSpec{
+				Provider: "NVIDIA",
+				Backend:  "dra",
+				Resource: v1alpha1.GPUPoolResourceSpec{Unit: " Mig ", MIGProfile: "1g.10gb", SlicesPerUnit: 2},
+			},
+			want: v1alpha1.GPUPoolSpec{
+				Provider: defaultProvider,
+				Backend:  "DRA",
+				Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "1g.10gb", SlicesPerUnit: 2},
+			},
+		},
+		{
+			name: "unknown unit kept for validation",
+			in:   v1alpha1.GPUPoolSpec{Backend: "deviceplugin", Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Slice"}},
+			want: v1alpha1.GPUPoolSpec{Provider: defaultProvider, Backend: defaultBackend, Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Slice", SlicesPerUnit: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.in
+			applyDefaults(&spec)
+			if spec.Provider != tt.want.Provider || spec.Backend != tt.want.Backend || !reflect.DeepEqual(spec.Resource, tt.want.Resource) {
+				t.Fatalf("unexpected spec: got %+v, want %+v", spec, tt.want)
+			}
+		})
+	}
+}
@@ -44,12 +44,17 @@ func (h *PoolValidationHandler) SyncPool(_ context.Context, pool *v1alpha1.GPUPo
 		return reconcile.Result{}, fmt.Errorf("metadata.name must be set")
 	}
 	applyDefaults(&pool.Spec)
+	if err := validateExtendedResourceName(poolResourceName(pool)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("metadata.name: %w", err)
+	}
 
 	checks := []validators.SpecValidator{
 		validators.Provider(defaultProvider),
 		validators.Resource(),
 		validators.Selectors(),
+		validators.Devices(),
 		validators.Scheduling(),
+		validators.ComponentOverrides(pool.Namespace != ""),
 	}
 	if err := validators.Run(checks, &pool.Spec); err != nil {
 		return reconcile.Result{}, err
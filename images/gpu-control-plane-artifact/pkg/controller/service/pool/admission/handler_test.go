@@ -16,6 +16,7 @@ package admission
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
@@ -62,4 +63,28 @@ func TestSyncPoolAppliesDefaultsAndValidatesProvider(t *testing.T) {
 			t.Fatalf("expected defaults applied, got %+v", pool.Spec)
 		}
 	})
+
+	t.Run("lowercase-unit", func(t *testing.T) {
+		pool := &v1alpha1.GPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+			Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "card"}},
+		}
+		if _, err := h.SyncPool(context.Background(), pool); err != nil {
+			t.Fatalf("SyncPool: %v", err)
+		}
+		if pool.Spec.Resource.Unit != "Card" || pool.Spec.Resource.SlicesPerUnit != 1 {
+			t.Fatalf("expected canonical unit and default slices, got %+v", pool.Spec.Resource)
+		}
+	})
+
+	t.Run("resource-name-too-long", func(t *testing.T) {
+		pool := &v1alpha1.GPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 64)},
+			Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+		}
+		_, err := h.SyncPool(context.Background(), pool)
+		if err == nil || !strings.Contains(err.Error(), "extended resource name") {
+			t.Fatalf("expected extended resource name error, got %v", err)
+		}
+	})
 }
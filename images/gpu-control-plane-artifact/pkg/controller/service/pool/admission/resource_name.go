@@ -0,0 +1,55 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+// poolResourceName is the extended resource the pool advertises on nodes and that pods request.
+func poolResourceName(pool *v1alpha1.GPUPool) string {
+	return poolcommon.PoolResourcePrefixFor(pool) + "/" + pool.Name
+}
+
+// validateExtendedResourceName rejects names kubelet would refuse to advertise: the name must be a
+// domain-prefixed qualified name outside the kubernetes.io and k8s.io namespaces.
+func validateExtendedResourceName(name string) error {
+	domain, _, ok := strings.Cut(name, "/")
+	if !ok || domain == "" {
+		return fmt.Errorf("extended resource name %q must be domain-prefixed", name)
+	}
+	if isReservedResourceDomain(domain) {
+		return fmt.Errorf("extended resource name %q uses the reserved %s namespace", name, domain)
+	}
+	if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+		return fmt.Errorf("extended resource name %q is invalid: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func isReservedResourceDomain(domain string) bool {
+	for _, reserved := range []string{"kubernetes.io", "k8s.io"} {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestPoolResourceName(t *testing.T) {
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "a100", Namespace: "ns"}}
+	if got := poolResourceName(pool); got != "gpu.deckhouse.io/a100" {
+		t.Fatalf("unexpected namespaced resource name %q", got)
+	}
+	pool = &v1alpha1.GPUPool{TypeMeta: metav1.TypeMeta{Kind: "ClusterGPUPool"}, ObjectMeta: metav1.ObjectMeta{Name: "a100"}}
+	if got := poolResourceName(pool); got != "cluster.gpu.deckhouse.io/a100" {
+		t.Fatalf("unexpected cluster resource name %q", got)
+	}
+}
+
+func TestValidateExtendedResourceName(t *testing.T) {
+	valid := []string{"gpu.deckhouse.io/a100", "cluster.gpu.deckhouse.io/pool-1", "example.com/gpu.small"}
+	for _, name := range valid {
+		if err := validateExtendedResourceName(name); err != nil {
+			t.Fatalf("expected %q to be valid: %v", name, err)
+		}
+	}
+
+	invalid := []string{
+		"a100",
+		"/a100",
+		"kubernetes.io/gpu",
+		"nvidia.kubernetes.io/gpu",
+		"k8s.io/gpu",
+		"gpu.deckhouse.io/Pool_",
+		"gpu.deckhouse.io/" + strings.Repeat("a", 64),
+	}
+	for _, name := range invalid {
+		if err := validateExtendedResourceName(name); err == nil {
+			t.Fatalf("expected %q to be rejected", name)
+		}
+	}
+}
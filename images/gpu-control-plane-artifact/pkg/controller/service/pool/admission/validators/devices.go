@@ -0,0 +1,73 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// Devices checks spec.devices: every reference names a device by exactly one of uuid or name, each device
+// is listed once, and an explicit list is not combined with deviceSelector or nodeSelector.
+func Devices() SpecValidator {
+	return func(spec *v1alpha1.GPUPoolSpec) error {
+		if len(spec.Devices) == 0 {
+			return nil
+		}
+		if sel := spec.DeviceSelector; sel != nil && (!selectorRulesEmpty(sel.Include) || !selectorRulesEmpty(sel.Exclude)) {
+			return fmt.Errorf("devices cannot be combined with deviceSelector")
+		}
+		if !labelSelectorEmpty(spec.NodeSelector) {
+			return fmt.Errorf("devices cannot be combined with nodeSelector")
+		}
+
+		seen := make(map[string]struct{}, len(spec.Devices))
+		for i := range spec.Devices {
+			ref := &spec.Devices[i]
+			ref.UUID = strings.TrimSpace(ref.UUID)
+			ref.Name = strings.TrimSpace(ref.Name)
+			if (ref.UUID == "") == (ref.Name == "") {
+				return fmt.Errorf("devices[%d]: exactly one of uuid or name must be set", i)
+			}
+			key := "uuid/" + ref.UUID
+			if ref.Name != "" {
+				key = "name/" + ref.Name
+			}
+			if _, ok := seen[key]; ok {
+				return fmt.Errorf("devices[%d]: %q is listed more than once", i, ref.UUID+ref.Name)
+			}
+			seen[key] = struct{}{}
+		}
+		return nil
+	}
+}
+
+func selectorRulesEmpty(r v1alpha1.GPUPoolSelectorRules) bool {
+	return len(r.InventoryIDs) == 0 &&
+		len(r.Products) == 0 &&
+		len(r.PCIVendors) == 0 &&
+		len(r.PCIDevices) == 0 &&
+		len(r.MIGProfiles) == 0 &&
+		r.MIGCapable == nil &&
+		r.ConfidentialComputing == nil
+}
+
+func labelSelectorEmpty(sel *metav1.LabelSelector) bool {
+	return sel == nil || len(sel.MatchLabels) == 0 && len(sel.MatchExpressions) == 0
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestDevicesValidator(t *testing.T) {
+	validate := Devices()
+
+	if err := validate(&v1alpha1.GPUPoolSpec{DeviceSelector: &v1alpha1.GPUPoolDeviceSelector{
+		Include: v1alpha1.GPUPoolSelectorRules{Products: []string{"NVIDIA A100"}},
+	}}); err != nil {
+		t.Fatalf("expected a selector pool to be valid, got %v", err)
+	}
+
+	spec := &v1alpha1.GPUPoolSpec{
+		DeviceSelector: &v1alpha1.GPUPoolDeviceSelector{},
+		NodeSelector:   &metav1.LabelSelector{},
+		Devices:        []v1alpha1.GPUDeviceReference{{UUID: " GPU-a "}, {Name: "node1-0"}},
+	}
+	if err := validate(spec); err != nil {
+		t.Fatalf("expected empty selectors next to a device list to be valid, got %v", err)
+	}
+	if spec.Devices[0].UUID != "GPU-a" {
+		t.Fatalf("expected references to be trimmed, got %+v", spec.Devices[0])
+	}
+
+	cases := []struct {
+		name    string
+		spec    *v1alpha1.GPUPoolSpec
+		wantErr string
+	}{
+		{
+			name: "device selector",
+			spec: &v1alpha1.GPUPoolSpec{
+				DeviceSelector: &v1alpha1.GPUPoolDeviceSelector{Exclude: v1alpha1.GPUPoolSelectorRules{PCIVendors: []string{"10de"}}},
+				Devices:        []v1alpha1.GPUDeviceReference{{UUID: "GPU-a"}},
+			},
+			wantErr: "devices cannot be combined with deviceSelector",
+		},
+		{
+			name: "node selector",
+			spec: &v1alpha1.GPUPoolSpec{
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+				Devices:      []v1alpha1.GPUDeviceReference{{UUID: "GPU-a"}},
+			},
+			wantErr: "devices cannot be combined with nodeSelector",
+		},
+		{
+			name:    "empty reference",
+			spec:    &v1alpha1.GPUPoolSpec{Devices: []v1alpha1.GPUDeviceReference{{UUID: "GPU-a"}, {}}},
+			wantErr: "devices[1]: exactly one of uuid or name must be set",
+		},
+		{
+			name:    "both fields",
+			spec:    &v1alpha1.GPUPoolSpec{Devices: []v1alpha1.GPUDeviceReference{{UUID: "GPU-a", Name: "node1-0"}}},
+			wantErr: "devices[0]: exactly one of uuid or name must be set",
+		},
+		{
+			name:    "duplicate",
+			spec:    &v1alpha1.GPUPoolSpec{Devices: []v1alpha1.GPUDeviceReference{{UUID: "GPU-a"}, {UUID: "GPU-a "}}},
+			wantErr: `devices[1]: "GPU-a" is listed more than once`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validate(tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
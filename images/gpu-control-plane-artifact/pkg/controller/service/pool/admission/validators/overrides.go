@@ -0,0 +1,45 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validators
+
+import (
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/overrides"
+)
+
+func ComponentOverrides(namespaced bool) SpecValidator {
+	return func(spec *v1alpha1.GPUPoolSpec) error {
+		components := []struct {
+			field     string
+			overrides *v1alpha1.GPUPoolComponentOverrides
+			reserved  overrides.Reserved
+		}{
+			{"devicePlugin", spec.DevicePlugin, overrides.DevicePlugin},
+			{"migManager", spec.MIGManager, overrides.MIGManager},
+		}
+		for _, c := range components {
+			if err := overrides.Validate(c.field, c.overrides, c.reserved); err != nil {
+				return err
+			}
+			if !namespaced {
+				continue
+			}
+			if err := overrides.ValidateNamespaced(c.field, c.overrides); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestComponentOverridesValidator(t *testing.T) {
+	validate := ComponentOverrides(false)
+
+	extras := func() *v1alpha1.GPUPoolComponentOverrides {
+		return &v1alpha1.GPUPoolComponentOverrides{
+			ExtraEnv:          []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+			ExtraVolumes:      []corev1.Volume{{Name: "certs", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/etc/pki"}}}},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "certs", MountPath: "/etc/pki", ReadOnly: true}},
+		}
+	}
+
+	if err := validate(&v1alpha1.GPUPoolSpec{}); err != nil {
+		t.Fatalf("expected a pool without overrides to be valid, got %v", err)
+	}
+	if err := validate(&v1alpha1.GPUPoolSpec{DevicePlugin: extras(), MIGManager: extras()}); err != nil {
+		t.Fatalf("expected valid overrides, got %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		spec    func() *v1alpha1.GPUPoolSpec
+		wantErr string
+	}{
+		{
+			name: "device plugin env",
+			spec: func() *v1alpha1.GPUPoolSpec {
+				o := extras()
+				o.ExtraEnv = append(o.ExtraEnv, corev1.EnvVar{Name: "NVIDIA_VISIBLE_DEVICES", Value: "none"})
+				return &v1alpha1.GPUPoolSpec{DevicePlugin: o}
+			},
+			wantErr: `devicePlugin.extraEnv[1]: "NVIDIA_VISIBLE_DEVICES" is managed by the controller`,
+		},
+		{
+			name: "device plugin volume",
+			spec: func() *v1alpha1.GPUPoolSpec {
+				o := extras()
+				o.ExtraVolumes[0].Name = "device-plugin"
+				return &v1alpha1.GPUPoolSpec{DevicePlugin: o}
+			},
+			wantErr: `devicePlugin.extraVolumes[0]: "device-plugin" is managed by the controller`,
+		},
+		{
+			name: "device plugin mount path",
+			spec: func() *v1alpha1.GPUPoolSpec {
+				o := extras()
+				o.ExtraVolumeMounts[0].MountPath = "/var/lib/kubelet"
+				return &v1alpha1.GPUPoolSpec{DevicePlugin: o}
+			},
+			wantErr: `devicePlugin.extraVolumeMounts[0]: "/var/lib/kubelet" overlaps "/var/lib/kubelet/device-plugins"`,
+		},
+		{
+			name: "mig manager env",
+			spec: func() *v1alpha1.GPUPoolSpec {
+				o := extras()
+				o.ExtraEnv[0].Name = "WITH_REBOOT"
+				return &v1alpha1.GPUPoolSpec{MIGManager: o}
+			},
+			wantErr: `migManager.extraEnv[0]: "WITH_REBOOT" is managed by the controller`,
+		},
+		{
+			name: "mig manager mount path",
+			spec: func() *v1alpha1.GPUPoolSpec {
+				o := extras()
+				o.ExtraVolumeMounts[0].MountPath = "/host/etc"
+				return &v1alpha1.GPUPoolSpec{MIGManager: o}
+			},
+			wantErr: `migManager.extraVolumeMounts[0]: "/host/etc" overlaps "/host"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validate(tc.spec())
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestComponentOverridesValidatorNamespaced(t *testing.T) {
+	validate := ComponentOverrides(true)
+
+	literal := &v1alpha1.GPUPoolComponentOverrides{
+		ExtraEnv:          []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+		ExtraVolumes:      []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+		ExtraVolumeMounts: []corev1.VolumeMount{{Name: "scratch", MountPath: "/scratch"}},
+	}
+	if err := validate(&v1alpha1.GPUPoolSpec{DevicePlugin: literal, MIGManager: literal}); err != nil {
+		t.Fatalf("expected literal env and emptyDir volumes to be valid on a GPUPool, got %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		spec    *v1alpha1.GPUPoolSpec
+		wantErr string
+	}{
+		{
+			name: "mig manager host path",
+			spec: &v1alpha1.GPUPoolSpec{MIGManager: &v1alpha1.GPUPoolComponentOverrides{
+				ExtraVolumes:      []corev1.Volume{{Name: "rootfs", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}}},
+				ExtraVolumeMounts: []corev1.VolumeMount{{Name: "rootfs", MountPath: "/rootfs"}},
+			}},
+			wantErr: "migManager.extraVolumes[0]: only emptyDir and downwardAPI volumes are allowed on a namespaced GPUPool",
+		},
+		{
+			name: "device plugin secret volume",
+			spec: &v1alpha1.GPUPoolSpec{DevicePlugin: &v1alpha1.GPUPoolComponentOverrides{
+				ExtraVolumes: []corev1.Volume{{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "module-secret"}}}},
+			}},
+			wantErr: "devicePlugin.extraVolumes[0]: only emptyDir and downwardAPI volumes are allowed on a namespaced GPUPool",
+		},
+		{
+			name: "mig manager env from secret",
+			spec: &v1alpha1.GPUPoolSpec{MIGManager: &v1alpha1.GPUPoolComponentOverrides{
+				ExtraEnv: []corev1.EnvVar{{
+					Name:      "TOKEN",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "module-secret"}, Key: "token"}},
+				}},
+			}},
+			wantErr: "migManager.extraEnv[0]: valueFrom is not allowed on a namespaced GPUPool",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validate(tc.spec)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
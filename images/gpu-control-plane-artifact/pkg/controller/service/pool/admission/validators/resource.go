@@ -16,8 +16,10 @@ package validators
 
 import (
 	"fmt"
+	"strings"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/hardware/nvidia/migplacement"
 )
 
 func Resource() SpecValidator {
@@ -30,9 +32,21 @@ func Resource() SpecValidator {
 			if spec.Resource.MIGProfile != "" {
 				return fmt.Errorf("resource.migProfile is not allowed when unit=Card")
 			}
+			if len(spec.Resource.MIGLayout) > 0 {
+				return fmt.Errorf("resource.migLayout is not allowed when unit=Card")
+			}
 		case "MIG":
+			if len(spec.Resource.MIGLayout) > 0 {
+				if spec.Resource.MIGProfile != "" {
+					return fmt.Errorf("resource.migProfile and resource.migLayout are mutually exclusive")
+				}
+				if err := validateMIGLayout(spec.Resource.MIGLayout); err != nil {
+					return err
+				}
+				break
+			}
 			if spec.Resource.MIGProfile == "" {
-				return fmt.Errorf("resource.migProfile is required when unit=MIG")
+				return fmt.Errorf("resource.migProfile or resource.migLayout is required when unit=MIG")
 			}
 			if !isValidMIGProfile(spec.Resource.MIGProfile) {
 				return fmt.Errorf("resource.migProfile %q has invalid format", spec.Resource.MIGProfile)
@@ -59,3 +73,26 @@ func Resource() SpecValidator {
 		return nil
 	}
 }
+
+// validateMIGLayout checks the profiles of a mixed layout and rejects a layout that fits no GPU model with a
+// known placement table offering its profiles.
+func validateMIGLayout(layout []v1alpha1.GPUPoolMIGLayoutEntry) error {
+	counts := make(map[string]int32, len(layout))
+	for i, entry := range layout {
+		profile := strings.ToLower(strings.TrimSpace(entry.Profile))
+		if !isValidMIGProfile(profile) {
+			return fmt.Errorf("resource.migLayout[%d].profile %q has invalid format", i, entry.Profile)
+		}
+		if entry.Count < 1 {
+			return fmt.Errorf("resource.migLayout[%d].count must be >= 1", i)
+		}
+		if _, dup := counts[profile]; dup {
+			return fmt.Errorf("resource.migLayout[%d].profile %q is listed twice", i, entry.Profile)
+		}
+		counts[profile] = entry.Count
+	}
+	if err := migplacement.Achievable(counts); err != nil {
+		return fmt.Errorf("resource.migLayout is not achievable: %w", err)
+	}
+	return nil
+}
@@ -56,6 +56,47 @@ func TestResourceValidator(t *testing.T) {
 			spec:    &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "bad", SlicesPerUnit: 1}},
 			wantErr: true,
 		},
+		{
+			name: "valid-mig-layout",
+			spec: &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", SlicesPerUnit: 1, MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{
+				{Profile: "3g.40gb", Count: 1}, {Profile: "1g.10gb", Count: 3},
+			}}},
+		},
+		{
+			name: "mig-layout-and-profile",
+			spec: &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "1g.10gb", SlicesPerUnit: 1, MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{
+				{Profile: "1g.10gb", Count: 1},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "card-with-mig-layout",
+			spec: &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1, MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{
+				{Profile: "1g.10gb", Count: 1},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "mig-layout-duplicate-profile",
+			spec: &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", SlicesPerUnit: 1, MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{
+				{Profile: "1g.10gb", Count: 1}, {Profile: "1G.10GB", Count: 2},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "mig-layout-zero-count",
+			spec: &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", SlicesPerUnit: 1, MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{
+				{Profile: "1g.10gb", Count: 0},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "mig-layout-unachievable",
+			spec: &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", SlicesPerUnit: 1, MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{
+				{Profile: "3g.40gb", Count: 2}, {Profile: "1g.10gb", Count: 2},
+			}}},
+			wantErr: true,
+		},
 		{
 			name:    "unsupported-unit",
 			spec:    &v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Other", SlicesPerUnit: 1}},
@@ -20,19 +20,21 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migration"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 )
 
 // PoolResources removes per-pool workloads when backend/provider changes.
 func PoolResources(ctx context.Context, c client.Client, namespace, poolName string) error {
-	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
-		Name:      fmt.Sprintf("nvidia-device-plugin-%s", poolName),
-		Namespace: namespace,
-	}}
-	if err := commonobject.DeleteObject(ctx, c, ds); err != nil {
+	if err := daemonSetWithBudget(ctx, c, namespace, fmt.Sprintf("nvidia-device-plugin-%s", poolName)); err != nil {
+		return err
+	}
+	if err := adoptedDaemonSets(ctx, c, namespace, poolName, migration.DevicePlugin); err != nil {
 		return err
 	}
 	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
@@ -42,26 +44,24 @@ func PoolResources(ctx context.Context, c client.Client, namespace, poolName str
 	if err := commonobject.DeleteObject(ctx, c, cm); err != nil {
 		return err
 	}
+	if err := rbac.Delete(ctx, c, namespace, rbac.DevicePlugin, poolName); err != nil {
+		return err
+	}
 	if err := MIGResources(ctx, c, namespace, poolName); err != nil {
 		return err
 	}
-	validator := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
-		Name:      fmt.Sprintf("nvidia-operator-validator-%s", poolName),
-		Namespace: namespace,
-	}}
-	if err := commonobject.DeleteObject(ctx, c, validator); err != nil {
+	if err := daemonSetWithBudget(ctx, c, namespace, fmt.Sprintf("nvidia-operator-validator-%s", poolName)); err != nil {
 		return err
 	}
-	return nil
+	return rbac.Delete(ctx, c, namespace, rbac.Validator, poolName)
 }
 
 // MIGResources removes MIG manager workloads for the pool.
 func MIGResources(ctx context.Context, c client.Client, namespace, poolName string) error {
-	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
-		Name:      fmt.Sprintf("nvidia-mig-manager-%s", poolName),
-		Namespace: namespace,
-	}}
-	if err := commonobject.DeleteObject(ctx, c, ds); err != nil {
+	if err := daemonSetWithBudget(ctx, c, namespace, fmt.Sprintf("nvidia-mig-manager-%s", poolName)); err != nil {
+		return err
+	}
+	if err := adoptedDaemonSets(ctx, c, namespace, poolName, migration.MIGManager); err != nil {
 		return err
 	}
 	for _, name := range []string{
@@ -77,5 +77,28 @@ func MIGResources(ctx context.Context, c client.Client, namespace, poolName stri
 			return err
 		}
 	}
+	return rbac.Delete(ctx, c, namespace, rbac.MIGManager, poolName)
+}
+
+// daemonSetWithBudget removes a per-pool DaemonSet and the PodDisruptionBudget rendered alongside it.
+func daemonSetWithBudget(ctx context.Context, c client.Client, namespace, name string) error {
+	meta := metav1.ObjectMeta{Name: name, Namespace: namespace}
+	if err := commonobject.DeleteObject(ctx, c, &appsv1.DaemonSet{ObjectMeta: meta}); err != nil {
+		return err
+	}
+	return commonobject.DeleteObject(ctx, c, &policyv1.PodDisruptionBudget{ObjectMeta: meta})
+}
+
+// adoptedDaemonSets removes the gpu-operator DaemonSets of the component the pool took over.
+func adoptedDaemonSets(ctx context.Context, c client.Client, namespace, poolName string, component migration.Component) error {
+	names, err := migration.Adopted(ctx, c, namespace, poolName, component)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := daemonSetWithBudget(ctx, c, namespace, name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
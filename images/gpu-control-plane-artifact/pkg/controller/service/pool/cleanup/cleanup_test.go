@@ -21,6 +21,10 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -45,16 +49,21 @@ func TestCleanupPoolResourcesErrorPaths(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
 
 	tests := []struct {
 		name   string
 		failOn int
 	}{
 		{name: "device plugin daemonset delete", failOn: 1},
-		{name: "device plugin configmap delete", failOn: 2},
-		{name: "mig manager daemonset delete", failOn: 3},
-		{name: "mig manager configmap delete", failOn: 4},
-		{name: "validator daemonset delete", failOn: 7},
+		{name: "device plugin pdb delete", failOn: 2},
+		{name: "device plugin configmap delete", failOn: 3},
+		{name: "mig manager daemonset delete", failOn: 4},
+		{name: "mig manager pdb delete", failOn: 5},
+		{name: "mig manager configmap delete", failOn: 6},
+		{name: "validator daemonset delete", failOn: 9},
+		{name: "validator pdb delete", failOn: 10},
 	}
 
 	for _, tc := range tests {
@@ -68,3 +77,60 @@ func TestCleanupPoolResourcesErrorPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanupPoolResourcesRemovesPodDisruptionBudgets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+
+	names := []string{"nvidia-device-plugin-alpha", "nvidia-mig-manager-alpha", "nvidia-operator-validator-alpha"}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, name := range names {
+		builder = builder.WithObjects(
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}},
+			&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"}},
+		)
+	}
+	cl := builder.Build()
+
+	if err := PoolResources(context.Background(), cl, "ns", "alpha"); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	for _, name := range names {
+		err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: name}, &policyv1.PodDisruptionBudget{})
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("expected PodDisruptionBudget %s to be deleted, got %v", name, err)
+		}
+	}
+}
+
+func TestCleanupPoolResourcesRemovesAdoptedDaemonSets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+
+	adopted := metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset", Namespace: "ns", Labels: map[string]string{"pool": "alpha"}}
+	foreign := metav1.ObjectMeta{Name: "nvidia-mig-manager", Namespace: "ns", Labels: map[string]string{"pool": "beta"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&appsv1.DaemonSet{ObjectMeta: adopted},
+		&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: adopted.Name, Namespace: "ns"}},
+		&appsv1.DaemonSet{ObjectMeta: foreign},
+	).Build()
+
+	if err := PoolResources(context.Background(), cl, "ns", "alpha"); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: adopted.Name}, &appsv1.DaemonSet{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the adopted DaemonSet to be deleted, got %v", err)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: adopted.Name}, &policyv1.PodDisruptionBudget{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the adopted PodDisruptionBudget to be deleted, got %v", err)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: foreign.Name}, &appsv1.DaemonSet{}); err != nil {
+		t.Fatalf("expected another pool's DaemonSet to be kept: %v", err)
+	}
+}
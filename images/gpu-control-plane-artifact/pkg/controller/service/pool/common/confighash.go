@@ -0,0 +1,75 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigHashAnnotation on a DaemonSet pod template carries the hash of the ConfigMaps its pods mount. A
+// config change alters the template, so the pods roll through the regular DaemonSet update.
+const ConfigHashAnnotation = "gpu.deckhouse.io/config-hash"
+
+// StampConfigHash sets ConfigHashAnnotation on the pod template to the hash of the given ConfigMaps.
+func StampConfigHash(template *corev1.PodTemplateSpec, cms ...*corev1.ConfigMap) {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[ConfigHashAnnotation] = ConfigHash(cms...)
+}
+
+// ConfigHash hashes the data of the given ConfigMaps. Keys are taken in sorted order and YAML or JSON
+// values are re-serialized with sorted mapping keys first, so equivalent documents hash the same
+// regardless of how they were written.
+func ConfigHash(cms ...*corev1.ConfigMap) string {
+	h := sha256.New()
+	for _, cm := range cms {
+		if cm == nil {
+			continue
+		}
+		keys := make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(h, "%d:%s", len(cm.Name), cm.Name)
+		for _, key := range keys {
+			value := canonicalConfigValue(key, cm.Data[key])
+			fmt.Fprintf(h, "%d:%s%d:%s", len(key), key, len(value), value)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalConfigValue normalizes structured documents; anything else, including documents that fail to
+// parse, is hashed verbatim.
+func canonicalConfigValue(key, value string) string {
+	if !strings.HasSuffix(key, ".yaml") && !strings.HasSuffix(key, ".yml") && !strings.HasSuffix(key, ".json") {
+		return value
+	}
+	// YAMLToJSON goes through Go maps, so mapping keys come out sorted.
+	out, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return value
+	}
+	return string(out)
+}
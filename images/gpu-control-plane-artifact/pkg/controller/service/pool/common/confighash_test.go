@@ -0,0 +1,71 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func configMap(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}, Data: data}
+}
+
+func TestConfigHashIgnoresKeyOrder(t *testing.T) {
+	a := configMap("cfg", map[string]string{
+		"config.yaml": "version: v1\nflags:\n  migStrategy: none\n  resourcePrefix: gpu.deckhouse.io\n",
+		"extra.json":  `{"b":1,"a":{"y":true,"x":"z"}}`,
+	})
+	b := configMap("cfg", map[string]string{
+		"extra.json":  `{"a":{"x":"z","y":true},"b":1}`,
+		"config.yaml": "flags:\n  resourcePrefix: gpu.deckhouse.io\n  migStrategy: none\nversion: v1\n",
+	})
+	if ConfigHash(a) != ConfigHash(b) {
+		t.Fatalf("expected equivalent configs to hash the same")
+	}
+
+	c := configMap("cfg", map[string]string{
+		"config.yaml": "version: v1\nflags:\n  migStrategy: single\n  resourcePrefix: gpu.deckhouse.io\n",
+		"extra.json":  `{"b":1,"a":{"y":true,"x":"z"}}`,
+	})
+	if ConfigHash(a) == ConfigHash(c) {
+		t.Fatalf("expected a config change to change the hash")
+	}
+}
+
+func TestConfigHashKeepsNonStructuredValuesVerbatim(t *testing.T) {
+	a := configMap("scripts", map[string]string{"run.sh": "#!/bin/sh\necho a\necho b\n"})
+	b := configMap("scripts", map[string]string{"run.sh": "#!/bin/sh\necho a echo b\n"})
+	if ConfigHash(a) == ConfigHash(b) {
+		t.Fatalf("expected scripts to be hashed verbatim")
+	}
+	if ConfigHash(a, nil) != ConfigHash(a) {
+		t.Fatalf("expected nil ConfigMaps to be skipped")
+	}
+	if ConfigHash(configMap("one", nil)) == ConfigHash(configMap("two", nil)) {
+		t.Fatalf("expected ConfigMap names to be part of the hash")
+	}
+}
+
+func TestStampConfigHash(t *testing.T) {
+	template := &corev1.PodTemplateSpec{}
+	cm := configMap("cfg", map[string]string{"config.yaml": "version: v1\n"})
+	StampConfigHash(template, cm)
+	if got := template.Annotations[ConfigHashAnnotation]; got == "" || got != ConfigHash(cm) {
+		t.Fatalf("unexpected config hash annotation %q", got)
+	}
+}
@@ -25,6 +25,16 @@ const (
 	DeviceIgnoreKey    = "gpu.deckhouse.io/ignore"
 	DeviceNodeLabelKey = "gpu.deckhouse.io/node"
 
+	// DeviceConditionNodeUnreachable is set by inventory on devices of a node that stayed NotReady
+	// beyond the stale threshold; such devices do not contribute pool capacity.
+	DeviceConditionNodeUnreachable = "NodeUnreachable"
+	// DeviceConditionCompatibilityBlocked is set by inventory on devices of a node that matched a Block
+	// compatibility rule; such devices do not contribute pool capacity either.
+	DeviceConditionCompatibilityBlocked = "CompatibilityBlocked"
+
+	// MIGStrategyMixed is the device plugin MIG strategy of pools that split devices into several profiles.
+	MIGStrategyMixed = "mixed"
+
 	NamespacedAssignmentAnnotation = commonannotations.GPUDeviceAssignment
 	ClusterAssignmentAnnotation    = commonannotations.ClusterGPUDeviceAssignment
 )
@@ -17,6 +17,8 @@ package common
 import (
 	"strings"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 )
 
@@ -27,6 +29,22 @@ func IsDeviceIgnored(dev *v1alpha1.GPUDevice) bool {
 	return strings.EqualFold(dev.Labels[DeviceIgnoreKey], "true")
 }
 
+// IsDeviceUnreachable reports whether inventory marked the device's node as unreachable.
+func IsDeviceUnreachable(dev *v1alpha1.GPUDevice) bool {
+	if dev == nil {
+		return false
+	}
+	return apimeta.IsStatusConditionTrue(dev.Status.Conditions, DeviceConditionNodeUnreachable)
+}
+
+// IsDeviceCompatibilityBlocked reports whether inventory found the device's node on a blocked driver, toolkit or kernel combination.
+func IsDeviceCompatibilityBlocked(dev *v1alpha1.GPUDevice) bool {
+	if dev == nil {
+		return false
+	}
+	return apimeta.IsStatusConditionTrue(dev.Status.Conditions, DeviceConditionCompatibilityBlocked)
+}
+
 func DeviceNodeName(dev *v1alpha1.GPUDevice) string {
 	if dev == nil {
 		return ""
@@ -63,3 +63,33 @@ func PoolValueFromKey(key string) string {
 	parts := strings.Split(key, "/")
 	return parts[len(parts)-1]
 }
+
+// AdvancedFor returns the advanced component options of the pool, zero when the pool sets none.
+func AdvancedFor(pool *v1alpha1.GPUPool) v1alpha1.GPUPoolAdvancedSpec {
+	if pool == nil || pool.Spec.Advanced == nil {
+		return v1alpha1.GPUPoolAdvancedSpec{}
+	}
+	return *pool.Spec.Advanced
+}
+
+// MIGLayoutCounts returns the MIG instances per device a mixed-layout pool asks for, keyed by lower-cased
+// profile. It is nil for pools that use the migProfile shortcut or expose whole cards.
+func MIGLayoutCounts(pool *v1alpha1.GPUPool) map[string]int32 {
+	if pool == nil || len(pool.Spec.Resource.MIGLayout) == 0 {
+		return nil
+	}
+	counts := make(map[string]int32, len(pool.Spec.Resource.MIGLayout))
+	for _, entry := range pool.Spec.Resource.MIGLayout {
+		counts[strings.ToLower(strings.TrimSpace(entry.Profile))] += entry.Count
+	}
+	return counts
+}
+
+// MIGStrategyFor returns the MIG strategy the pool components run with: mixed for a pool with a MIG layout,
+// the controller default otherwise.
+func MIGStrategyFor(pool *v1alpha1.GPUPool, fallback string) string {
+	if pool != nil && len(pool.Spec.Resource.MIGLayout) > 0 {
+		return MIGStrategyMixed
+	}
+	return fallback
+}
@@ -30,23 +30,29 @@ func FilterDevices(devices []v1alpha1.GPUDevice, sel *v1alpha1.GPUPoolDeviceSele
 
 	out := make([]v1alpha1.GPUDevice, 0, len(devices))
 	for _, dev := range devices {
-		if matchesExclude(sel.Exclude, dev) {
-			continue
-		}
-		if matchesInclude(sel.Include, dev) {
+		if MatchesDeviceSelector(dev, sel) {
 			out = append(out, dev)
 		}
 	}
 	return out
 }
 
+// MatchesDeviceSelector reports whether a single device passes the include/exclude selectors; nil matches every device.
+func MatchesDeviceSelector(dev v1alpha1.GPUDevice, sel *v1alpha1.GPUPoolDeviceSelector) bool {
+	if sel == nil {
+		return true
+	}
+	return !matchesExclude(sel.Exclude, dev) && matchesInclude(sel.Include, dev)
+}
+
 func matchesInclude(include v1alpha1.GPUPoolSelectorRules, dev v1alpha1.GPUDevice) bool {
 	if len(include.InventoryIDs) == 0 &&
 		len(include.Products) == 0 &&
 		len(include.PCIVendors) == 0 &&
 		len(include.PCIDevices) == 0 &&
 		len(include.MIGProfiles) == 0 &&
-		include.MIGCapable == nil {
+		include.MIGCapable == nil &&
+		include.ConfidentialComputing == nil {
 		return true
 	}
 
@@ -70,6 +76,9 @@ func matchesInclude(include v1alpha1.GPUPoolSelectorRules, dev v1alpha1.GPUDevic
 	if include.MIGCapable != nil && dev.Status.Hardware.MIG.Capable != *include.MIGCapable {
 		return false
 	}
+	if include.ConfidentialComputing != nil && confidentialComputing(dev) != *include.ConfidentialComputing {
+		return false
+	}
 	if len(include.MIGProfiles) > 0 && !anyMIGProfile(include.MIGProfiles, migProfiles(dev.Status.Hardware.MIG)) {
 		return false
 	}
@@ -97,12 +106,21 @@ func matchesExclude(exclude v1alpha1.GPUPoolSelectorRules, dev v1alpha1.GPUDevic
 	if exclude.MIGCapable != nil && dev.Status.Hardware.MIG.Capable == *exclude.MIGCapable {
 		return true
 	}
+	if exclude.ConfidentialComputing != nil && confidentialComputing(dev) == *exclude.ConfidentialComputing {
+		return true
+	}
 	if len(exclude.MIGProfiles) > 0 && anyMIGProfile(exclude.MIGProfiles, migProfiles(dev.Status.Hardware.MIG)) {
 		return true
 	}
 	return false
 }
 
+// confidentialComputing treats devices that do not report the mode as running outside it.
+func confidentialComputing(dev v1alpha1.GPUDevice) bool {
+	cc := dev.Status.Hardware.ConfidentialComputing
+	return cc != nil && cc.Enabled
+}
+
 func anyMIGProfile(want []string, supported []string) bool {
 	for _, w := range want {
 		if contains(supported, w) {
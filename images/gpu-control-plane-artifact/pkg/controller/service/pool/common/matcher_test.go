@@ -189,3 +189,26 @@ func TestMatchesIncludeAndExcludeAdditionalBranches(t *testing.T) {
 		}
 	})
 }
+
+func TestMatchesConfidentialComputing(t *testing.T) {
+	cc := v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{Name: "cc"}}
+	cc.Status.Hardware.ConfidentialComputing = &v1alpha1.GPUConfidentialComputing{Enabled: true}
+	plain := v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{Name: "plain"}}
+	plain.Status.Hardware.ConfidentialComputing = &v1alpha1.GPUConfidentialComputing{}
+	unknown := v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{Name: "unknown"}}
+	devices := []v1alpha1.GPUDevice{cc, plain, unknown}
+
+	enabled, disabled := true, false
+	got := FilterDevices(devices, &v1alpha1.GPUPoolDeviceSelector{Include: v1alpha1.GPUPoolSelectorRules{ConfidentialComputing: &enabled}})
+	if len(got) != 1 || got[0].Name != "cc" {
+		t.Fatalf("expected only the CC device, got %+v", got)
+	}
+	got = FilterDevices(devices, &v1alpha1.GPUPoolDeviceSelector{Include: v1alpha1.GPUPoolSelectorRules{ConfidentialComputing: &disabled}})
+	if len(got) != 2 || got[0].Name != "plain" || got[1].Name != "unknown" {
+		t.Fatalf("expected non-CC and unreported devices, got %+v", got)
+	}
+	got = FilterDevices(devices, &v1alpha1.GPUPoolDeviceSelector{Exclude: v1alpha1.GPUPoolSelectorRules{ConfidentialComputing: &enabled}})
+	if len(got) != 2 || got[0].Name != "plain" || got[1].Name != "unknown" {
+		t.Fatalf("expected CC device to be excluded, got %+v", got)
+	}
+}
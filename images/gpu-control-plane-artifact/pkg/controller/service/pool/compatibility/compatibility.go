@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/deviceprovider"
 )
 
 const (
@@ -40,7 +41,7 @@ func (h *CompatibilityCheckHandler) Name() string {
 }
 
 func (h *CompatibilityCheckHandler) HandlePool(_ context.Context, pool *v1alpha1.GPUPool) (reconcile.Result, error) {
-	supportedProvider := pool.Spec.Provider == "" || pool.Spec.Provider == "Nvidia"
+	_, supportedProvider := deviceprovider.Default().Lookup(pool.Spec.Provider)
 	supportedBackend := pool.Spec.Backend == "" || pool.Spec.Backend == "DevicePlugin"
 
 	cond := metav1.Condition{
@@ -16,7 +16,11 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/imagedigest"
 )
 
 // WorkloadConfig carries per-pool workload settings.
@@ -27,6 +31,54 @@ type WorkloadConfig struct {
 	DefaultMIGStrategy   string
 	CustomTolerationKeys []string
 	ValidatorImage       string
+	// DriverInstallType is the default for pools that do not set spec.driverInstallType.
+	DriverInstallType v1alpha1.GPUPoolDriverInstallType
+	// DriverRoot is the host path of a preinstalled driver installation.
+	DriverRoot string
+	// PriorityClassName is set on per-pool DaemonSets when the class exists in the cluster.
+	PriorityClassName string
+	// ExternalRBAC stops rendering per-pool ServiceAccounts and roles; pods then run as the shared, pre-created accounts.
+	ExternalRBAC bool
+	// MigrateFromGPUOperator blocks pool components while upstream gpu-operator DaemonSets of the same kind run.
+	MigrateFromGPUOperator bool
+	// AdoptExisting lets a pool render into the upstream DaemonSets annotated for it.
+	AdoptExisting bool
+	// LegacyPrivileged renders the privileged security contexts of earlier releases instead of the restricted profiles.
+	LegacyPrivileged bool
+	// ImageResolver yields the resolver pinning the images above to the digests their tags resolve to. It is asked
+	// on every reconcile, so a changed imageDigests setting applies without a restart; nil, or a nil resolver,
+	// renders the images as configured.
+	ImageResolver ImageResolverSource
+}
+
+// ImageResolverSource returns the digest resolver of the current settings, nil while resolution is off.
+type ImageResolverSource func() *imagedigest.Resolver
+
+// CurrentImageResolver returns the resolver ImageResolver yields now, nil without a source.
+func (c WorkloadConfig) CurrentImageResolver() *imagedigest.Resolver {
+	if c.ImageResolver == nil {
+		return nil
+	}
+	return c.ImageResolver()
+}
+
+// DriverInstallTypeFor resolves the driver install type of the pool, falling back to the workload default.
+func (c WorkloadConfig) DriverInstallTypeFor(pool *v1alpha1.GPUPool) v1alpha1.GPUPoolDriverInstallType {
+	if pool != nil && pool.Spec.DriverInstallType != "" {
+		return pool.Spec.DriverInstallType
+	}
+	if c.DriverInstallType == v1alpha1.GPUPoolDriverInstallPreinstalled {
+		return v1alpha1.GPUPoolDriverInstallPreinstalled
+	}
+	return v1alpha1.GPUPoolDriverInstallOperator
+}
+
+// HostDriverRoot returns the host driver root, defaulting to the host root filesystem.
+func (c WorkloadConfig) HostDriverRoot() string {
+	if root := strings.TrimSpace(c.DriverRoot); root != "" {
+		return root
+	}
+	return "/"
 }
 
 // DefaultsFromEnv reads environment defaults for workload settings.
@@ -39,11 +91,24 @@ func DefaultsFromEnv() WorkloadConfig {
 	if strategy == "" {
 		strategy = "none"
 	}
+	priorityClass := strings.TrimSpace(os.Getenv("POOL_WORKLOAD_PRIORITY_CLASS"))
+	if priorityClass == "" {
+		priorityClass = "system-node-critical"
+	}
+	installType := v1alpha1.GPUPoolDriverInstallOperator
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("NVIDIA_DRIVER_INSTALL_TYPE")), string(v1alpha1.GPUPoolDriverInstallPreinstalled)) {
+		installType = v1alpha1.GPUPoolDriverInstallPreinstalled
+	}
+	externalRBAC, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("POOL_WORKLOAD_EXTERNAL_RBAC")))
 	return WorkloadConfig{
 		Namespace:          ns,
 		DevicePluginImage:  strings.TrimSpace(os.Getenv("NVIDIA_DEVICE_PLUGIN_IMAGE")),
 		MIGManagerImage:    strings.TrimSpace(os.Getenv("NVIDIA_MIG_MANAGER_IMAGE")),
 		DefaultMIGStrategy: strategy,
 		ValidatorImage:     strings.TrimSpace(os.Getenv("NVIDIA_VALIDATOR_IMAGE")),
+		DriverInstallType:  installType,
+		DriverRoot:         strings.TrimSpace(os.Getenv("NVIDIA_DRIVER_ROOT")),
+		PriorityClassName:  priorityClass,
+		ExternalRBAC:       externalRBAC,
 	}
 }
@@ -0,0 +1,64 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package critical
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+)
+
+// PriorityClassName returns the configured priority class for per-pool DaemonSets. A class that cannot be
+// resolved is omitted with a warning, so a missing PriorityClass never blocks pool workloads.
+func PriorityClassName(ctx context.Context, d deps.Deps) string {
+	name := d.Config.PriorityClassName
+	if name == "" {
+		return ""
+	}
+	pc := &schedulingv1.PriorityClass{}
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: name}, pc); err != nil {
+		if apierrors.IsNotFound(err) {
+			d.Log.Info("priority class not found, rendering DaemonSet without priorityClassName", "priorityClass", name)
+		} else {
+			d.Log.Error(err, "failed to resolve priority class, rendering DaemonSet without priorityClassName", "priorityClass", name)
+		}
+		return ""
+	}
+	return name
+}
+
+// PodDisruptionBudget builds a PDB that lets at most one pod of the DaemonSet be evicted at a time.
+func PodDisruptionBudget(ds *appsv1.DaemonSet) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(1)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ds.Name,
+			Namespace: ds.Namespace,
+			Labels:    ds.Labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       ds.Spec.Selector.DeepCopy(),
+		},
+	}
+}
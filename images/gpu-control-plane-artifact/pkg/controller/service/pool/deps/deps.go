@@ -19,7 +19,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
 // Deps bundles shared workload dependencies for subpackages.
@@ -28,4 +31,10 @@ type Deps struct {
 	Client            client.Client
 	Config            config.WorkloadConfig
 	CustomTolerations []corev1.Toleration
+	// Recorder is optional; events are skipped when it is nil.
+	Recorder eventrecord.EventRecorderLogger
+	// Runtime is the container runtime profile of the pool being rendered; zero means the default profile.
+	Runtime containerruntime.Profile
+	// Guard claims the rendered objects for this instance; nil writes them without a claim.
+	Guard *ownership.Guard
 }
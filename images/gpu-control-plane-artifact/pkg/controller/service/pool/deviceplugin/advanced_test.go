@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+)
+
+func getDaemonSet(t *testing.T, cl client.Client) *appsv1.DaemonSet {
+	t.Helper()
+	ds := &appsv1.DaemonSet{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha"}, ds); err != nil {
+		t.Fatalf("get DaemonSet: %v", err)
+	}
+	return ds
+}
+
+func hasMount(container corev1.Container, path string) bool {
+	for _, mount := range container.VolumeMounts {
+		if mount.MountPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+func hasHostPathVolume(spec corev1.PodSpec, path string) bool {
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil && volume.HostPath.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReconcileAdvancedOptions(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	pool := driftPool()
+	pool.Spec.Advanced = &v1alpha1.GPUPoolAdvancedSpec{GPUDirectRDMA: true, HostNetwork: true}
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	spec := getDaemonSet(t, d.Client).Spec.Template.Spec
+	container := spec.Containers[0]
+	for _, path := range []string{kube.InfinibandDir, kube.HostModulesDir} {
+		if !hasMount(container, path) || !hasHostPathVolume(spec, path) {
+			t.Fatalf("expected %s to be mounted from the host, got mounts %+v volumes %+v", path, container.VolumeMounts, spec.Volumes)
+		}
+	}
+	if caps := container.SecurityContext.Capabilities; caps == nil || len(caps.Add) != 1 || caps.Add[0] != "IPC_LOCK" {
+		t.Fatalf("expected IPC_LOCK for GPUDirect RDMA, got %+v", caps)
+	}
+	if !spec.HostNetwork || spec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		t.Fatalf("expected host network with cluster DNS, got hostNetwork=%t dnsPolicy=%q", spec.HostNetwork, spec.DNSPolicy)
+	}
+
+	// Turning the options off removes everything they added.
+	pool.Spec.Advanced = nil
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	spec = getDaemonSet(t, d.Client).Spec.Template.Spec
+	container = spec.Containers[0]
+	for _, path := range []string{kube.InfinibandDir, kube.HostModulesDir} {
+		if hasMount(container, path) || hasHostPathVolume(spec, path) {
+			t.Fatalf("expected %s to be removed, got mounts %+v volumes %+v", path, container.VolumeMounts, spec.Volumes)
+		}
+	}
+	if caps := container.SecurityContext.Capabilities; caps != nil && len(caps.Add) != 0 {
+		t.Fatalf("expected no extra capabilities, got %+v", caps)
+	}
+	if spec.HostNetwork || spec.DNSPolicy != "" {
+		t.Fatalf("expected pod network, got hostNetwork=%t dnsPolicy=%q", spec.HostNetwork, spec.DNSPolicy)
+	}
+}
@@ -43,10 +43,28 @@ func devicePluginConfigMap(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUP
 		"replicas": int(replicas),
 	}}
 
+	// A mixed layout advertises every MIG profile under its own name; the device plugin matches MIG devices
+	// by profile, so the patterns are profile names rather than GPU identifiers.
+	var migs []map[string]any
+	if len(pool.Spec.Resource.MIGLayout) > 0 {
+		resources = resources[:0]
+		for _, entry := range pool.Spec.Resource.MIGLayout {
+			name := names.MIGProfileResourceName(pool, entry.Profile)
+			migs = append(migs, map[string]any{
+				"pattern": strings.ToLower(strings.TrimSpace(entry.Profile)),
+				"name":    name,
+			})
+			resources = append(resources, map[string]any{
+				"name":     name,
+				"replicas": int(replicas),
+			})
+		}
+	}
+
 	cfg := map[string]any{
 		"version": "v1",
 		"flags": map[string]any{
-			"migStrategy":    d.Config.DefaultMIGStrategy,
+			"migStrategy":    poolcommon.MIGStrategyFor(pool, d.Config.DefaultMIGStrategy),
 			"resourcePrefix": poolcommon.PoolResourcePrefixFor(pool),
 		},
 		"plugin": map[string]any{
@@ -72,9 +90,13 @@ func devicePluginConfigMap(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUP
 		}
 	}
 
-	cfg["resources"] = map[string]any{
+	resourcesCfg := map[string]any{
 		"gpus": gpus,
 	}
+	if len(migs) > 0 {
+		resourcesCfg["mig"] = migs
+	}
+	cfg["resources"] = resourcesCfg
 
 	if hasSharing {
 		cfg["sharing"] = map[string]any{
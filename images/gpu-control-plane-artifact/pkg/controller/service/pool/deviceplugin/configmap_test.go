@@ -0,0 +1,98 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+)
+
+type renderedConfig struct {
+	Flags struct {
+		MIGStrategy string `json:"migStrategy"`
+	} `json:"flags"`
+	Resources struct {
+		GPUs []map[string]string `json:"gpus"`
+		MIG  []map[string]string `json:"mig"`
+	} `json:"resources"`
+	Sharing struct {
+		TimeSlicing struct {
+			Resources []struct {
+				Name     string `json:"name"`
+				Replicas int    `json:"replicas"`
+			} `json:"resources"`
+		} `json:"timeSlicing"`
+	} `json:"sharing"`
+}
+
+func renderConfig(t *testing.T, pool *v1alpha1.GPUPool) renderedConfig {
+	t.Helper()
+	d := deps.Deps{Config: config.WorkloadConfig{Namespace: "ns", DefaultMIGStrategy: "single"}}
+	cm := devicePluginConfigMap(context.Background(), d, pool)
+	var cfg renderedConfig
+	if err := yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg); err != nil {
+		t.Fatalf("unmarshal rendered config: %v", err)
+	}
+	return cfg
+}
+
+func TestDevicePluginConfigSingleProfileKeepsDefaultStrategy(t *testing.T) {
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "single", Namespace: "ns"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "1g.10gb"}},
+	}
+	cfg := renderConfig(t, pool)
+	if cfg.Flags.MIGStrategy != "single" {
+		t.Fatalf("expected controller default strategy, got %q", cfg.Flags.MIGStrategy)
+	}
+	if len(cfg.Resources.MIG) != 0 {
+		t.Fatalf("single-profile pool must not rename MIG resources, got %v", cfg.Resources.MIG)
+	}
+}
+
+func TestDevicePluginConfigMixedLayout(t *testing.T) {
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "mixed", Namespace: "ns"},
+		Spec: v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{
+			Unit:          "MIG",
+			SlicesPerUnit: 2,
+			MIGLayout:     []v1alpha1.GPUPoolMIGLayoutEntry{{Profile: "3g.40gb", Count: 1}, {Profile: "1g.10gb", Count: 3}},
+		}},
+	}
+	cfg := renderConfig(t, pool)
+
+	if cfg.Flags.MIGStrategy != "mixed" {
+		t.Fatalf("expected mixed strategy, got %q", cfg.Flags.MIGStrategy)
+	}
+	wantMIG := []map[string]string{
+		{"pattern": "3g.40gb", "name": "mixed-3g.40gb"},
+		{"pattern": "1g.10gb", "name": "mixed-1g.10gb"},
+	}
+	if !reflect.DeepEqual(cfg.Resources.MIG, wantMIG) {
+		t.Fatalf("unexpected MIG resources: %v", cfg.Resources.MIG)
+	}
+	shared := cfg.Sharing.TimeSlicing.Resources
+	if len(shared) != 2 || shared[0].Name != "mixed-3g.40gb" || shared[1].Name != "mixed-1g.10gb" || shared[0].Replicas != 2 {
+		t.Fatalf("expected time-slicing per profile resource, got %+v", shared)
+	}
+}
@@ -25,12 +25,15 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/critical"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/overrides"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/tolerations"
 )
 
-func devicePluginDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) *appsv1.DaemonSet {
+func devicePluginDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool, hc healthChecks) *appsv1.DaemonSet {
 	poolKey := poolcommon.PoolLabelKey(pool)
 	mergedTolerations := tolerations.Merge([]corev1.Toleration{
 		{
@@ -40,7 +43,8 @@ func devicePluginDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUP
 			Effect:   corev1.TaintEffectNoSchedule,
 		},
 	}, append(d.CustomTolerations, tolerations.PoolNodeTolerations(ctx, d.Client, pool)...))
-	return &appsv1.DaemonSet{
+	advanced := poolcommon.AdvancedFor(pool)
+	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("nvidia-device-plugin-%s", pool.Name),
 			Namespace: d.Config.Namespace,
@@ -65,12 +69,12 @@ func devicePluginDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUP
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: "nvidia-device-plugin",
+					ServiceAccountName: rbac.ServiceAccountName(d, rbac.DevicePlugin, pool),
+					PriorityClassName:  critical.PriorityClassName(ctx, d),
 					Tolerations:        mergedTolerations,
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsUser:    ptr.To[int64](0),
-						RunAsNonRoot: ptr.To(false),
-					},
+					HostNetwork:        advanced.HostNetwork,
+					DNSPolicy:          kube.HostNetworkDNSPolicy(advanced.HostNetwork),
+					SecurityContext:    kube.PodSecurityContext(d.Config.LegacyPrivileged),
 					Affinity: &corev1.Affinity{
 						NodeAffinity: &corev1.NodeAffinity{
 							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
@@ -94,58 +98,110 @@ func devicePluginDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUP
 							Image:           d.Config.DevicePluginImage,
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Command:         []string{"/nvidia-device-plugin"},
-							SecurityContext: &corev1.SecurityContext{
-								Privileged:               ptr.To(true),
-								RunAsUser:                ptr.To[int64](0),
-								RunAsNonRoot:             ptr.To(false),
-								AllowPrivilegeEscalation: ptr.To(true),
-								ReadOnlyRootFilesystem:   ptr.To(false),
-							},
+							SecurityContext: devicePluginSecurityContext(d.Config.LegacyPrivileged, advanced),
 							// pass-device-specs aligns with plugin config; device list/id strategies are set via ConfigMap.
-							Args: []string{"--config-file=/config/config.yaml", "--pass-device-specs=true", "--fail-on-init-error=false"},
-							Env: []corev1.EnvVar{
-								{Name: "NVIDIA_VISIBLE_DEVICES", Value: "all"},
-								{Name: "NVIDIA_RESOURCE_PREFIX", Value: poolcommon.PoolResourcePrefixFor(pool)},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{Name: "device-plugin", MountPath: "/var/lib/kubelet/device-plugins"},
-								{Name: "config", MountPath: "/config"},
-								{Name: "dev", MountPath: "/dev"},
-							},
+							Args:         []string{"--config-file=/config/config.yaml", "--pass-device-specs=true", "--fail-on-init-error=false"},
+							Env:          append(devicePluginEnv(d, pool), hc.env()...),
+							VolumeMounts: append(devicePluginVolumeMounts(d, pool), hc.volumeMounts()...),
 						},
 					},
-					Volumes: []corev1.Volume{
-						{
-							Name: "device-plugin",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/var/lib/kubelet/device-plugins",
-									Type: kube.HostPathType(corev1.HostPathDirectory),
-								},
-							},
-						},
-						{
-							Name: "dev",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/dev",
-									Type: kube.HostPathType(corev1.HostPathDirectory),
-								},
-							},
-						},
-						{
-							Name: "config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: fmt.Sprintf("nvidia-device-plugin-%s-config", pool.Name),
-									},
-								},
-							},
-						},
+					Volumes: append(devicePluginVolumes(d, pool), hc.volumes()...),
+				},
+			},
+		},
+	}
+	// Pool overrides go last so the rendered configuration above is never replaced.
+	overrides.Apply(&ds.Spec.Template.Spec, "device-plugin", pool.Spec.DevicePlugin, pool.Namespace != "")
+	return ds
+}
+
+// devicePluginSecurityContext runs the plugin unprivileged: it only serves the kubelet socket and hands device
+// specs to the runtime. IPC_LOCK is added for GPUDirect RDMA, which pins GPU memory for the NIC.
+func devicePluginSecurityContext(legacy bool, advanced v1alpha1.GPUPoolAdvancedSpec) *corev1.SecurityContext {
+	var add []corev1.Capability
+	if advanced.GPUDirectRDMA {
+		add = append(add, kube.CapabilityIPCLock)
+	}
+	if !legacy {
+		return kube.RestrictedSecurityContext(add...)
+	}
+	sc := kube.LegacyPrivilegedSecurityContext()
+	if len(add) > 0 {
+		sc.Capabilities = &corev1.Capabilities{Add: add}
+	}
+	return sc
+}
+
+func devicePluginEnv(d deps.Deps, pool *v1alpha1.GPUPool) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "NVIDIA_VISIBLE_DEVICES", Value: "all"},
+		{Name: "NVIDIA_RESOURCE_PREFIX", Value: poolcommon.PoolResourcePrefixFor(pool)},
+	}
+	if d.Config.DriverInstallTypeFor(pool) == v1alpha1.GPUPoolDriverInstallPreinstalled {
+		env = append(env,
+			corev1.EnvVar{Name: "NVIDIA_DRIVER_ROOT", Value: d.Config.HostDriverRoot()},
+			corev1.EnvVar{Name: "CONTAINER_DRIVER_ROOT", Value: kube.ContainerDriverRoot},
+		)
+	}
+	return env
+}
+
+func devicePluginVolumeMounts(d deps.Deps, pool *v1alpha1.GPUPool) []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{
+		{Name: "device-plugin", MountPath: "/var/lib/kubelet/device-plugins"},
+		{Name: "config", MountPath: "/config"},
+		{Name: "dev", MountPath: "/dev"},
+	}
+	if d.Config.DriverInstallTypeFor(pool) == v1alpha1.GPUPoolDriverInstallPreinstalled {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:             "driver-root",
+			MountPath:        kube.ContainerDriverRoot,
+			ReadOnly:         true,
+			MountPropagation: ptr.To(corev1.MountPropagationHostToContainer),
+		})
+	}
+	if poolcommon.AdvancedFor(pool).GPUDirectRDMA {
+		mounts = append(mounts, kube.RDMAVolumeMounts()...)
+	}
+	return mounts
+}
+
+func devicePluginVolumes(d deps.Deps, pool *v1alpha1.GPUPool) []corev1.Volume {
+	volumes := []corev1.Volume{
+		{
+			Name: "device-plugin",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/var/lib/kubelet/device-plugins",
+					Type: kube.HostPathType(corev1.HostPathDirectory),
+				},
+			},
+		},
+		{
+			Name: "dev",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/dev",
+					Type: kube.HostPathType(corev1.HostPathDirectory),
+				},
+			},
+		},
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: fmt.Sprintf("nvidia-device-plugin-%s-config", pool.Name),
 					},
 				},
 			},
 		},
 	}
+	if poolcommon.AdvancedFor(pool).GPUDirectRDMA {
+		volumes = append(volumes, kube.RDMAVolumes()...)
+	}
+	if d.Config.DriverInstallTypeFor(pool) == v1alpha1.GPUPoolDriverInstallPreinstalled {
+		volumes = append(volumes, kube.HostDriverRootVolume("driver-root", d.Config.HostDriverRoot()))
+	}
+	return volumes
 }
@@ -0,0 +1,115 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+)
+
+const (
+	// UnmanagedAnnotation on the device-plugin ConfigMap stops the renderer from writing it, so a
+	// hand-tuned config survives reconciles. The pool reports the exception via ConditionConfigUnmanaged.
+	UnmanagedAnnotation = "gpu.deckhouse.io/unmanaged"
+	// ConditionConfigUnmanaged is True while the pool's device-plugin ConfigMap is marked unmanaged.
+	ConditionConfigUnmanaged = "DevicePluginConfigUnmanaged"
+	// EventConfigDriftReverted is emitted on the pool when manual ConfigMap edits are overwritten.
+	EventConfigDriftReverted = "ConfigDriftReverted"
+
+	// renderedHashAnnotation records the data the renderer last wrote, which tells manual edits apart
+	// from renderer updates.
+	renderedHashAnnotation = "gpu.deckhouse.io/rendered-config-hash"
+)
+
+func isUnmanaged(cm *corev1.ConfigMap) bool {
+	return cm != nil && cm.Annotations[UnmanagedAnnotation] == "true"
+}
+
+func stampRenderedHash(cm *corev1.ConfigMap) {
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[renderedHashAnnotation] = dataHash(cm.Data)
+}
+
+// driftedKeys returns the keys that were edited by hand since the renderer last wrote current and that
+// the desired config overwrites. ConfigMaps without a recorded hash predate drift tracking and are
+// not reported.
+func driftedKeys(current, desired *corev1.ConfigMap) []string {
+	if current == nil {
+		return nil
+	}
+	rendered := current.Annotations[renderedHashAnnotation]
+	if rendered == "" || rendered == dataHash(current.Data) {
+		return nil
+	}
+	var keys []string
+	for key, value := range current.Data {
+		if want, ok := desired.Data[key]; !ok || want != value {
+			keys = append(keys, key)
+		}
+	}
+	for key := range desired.Data {
+		if _, ok := current.Data[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func dataHash(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%d:%s%d:%s", len(key), key, len(data[key]), data[key])
+	}
+	return sha256Hex(b.String())
+}
+
+func recordDriftReverted(d deps.Deps, pool *v1alpha1.GPUPool, cm *corev1.ConfigMap, keys []string) {
+	if d.Recorder == nil {
+		return
+	}
+	d.Recorder.WithLogging(d.Log).Eventf(
+		pool,
+		corev1.EventTypeWarning,
+		EventConfigDriftReverted,
+		"reverted manual changes to ConfigMap %s/%s, keys: %s",
+		cm.Namespace, cm.Name, strings.Join(keys, ", "),
+	)
+}
+
+func setUnmanagedCondition(pool *v1alpha1.GPUPool, cm *corev1.ConfigMap) {
+	meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+		Type:               ConditionConfigUnmanaged,
+		Status:             metav1.ConditionTrue,
+		Reason:             "UnmanagedAnnotation",
+		Message:            fmt.Sprintf("ConfigMap %s/%s is annotated %s=true, the device-plugin config is not rendered", cm.Namespace, cm.Name, UnmanagedAnnotation),
+		ObservedGeneration: pool.Generation,
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+)
+
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
+func newDriftDeps(t *testing.T) (deps.Deps, *record.FakeRecorder) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	rec := record.NewFakeRecorder(8)
+	return deps.Deps{
+		Log:      testr.New(t),
+		Client:   withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build(),
+		Config:   config.WorkloadConfig{Namespace: "ns", DevicePluginImage: "dp:tag", DefaultMIGStrategy: "single"},
+		Recorder: eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: rec}, "test"),
+	}, rec
+}
+
+func driftPool() *v1alpha1.GPUPool {
+	return &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns", UID: "alpha-uid"}}
+}
+
+func getConfigMap(t *testing.T, cl client.Client) *corev1.ConfigMap {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha-config"}, cm); err != nil {
+		t.Fatalf("get ConfigMap: %v", err)
+	}
+	return cm
+}
+
+func drainEvents(rec *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-rec.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestReconcileRevertsManualConfigEdits(t *testing.T) {
+	d, rec := newDriftDeps(t)
+	pool := driftPool()
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("initial reconcile: %v", err)
+	}
+	rendered := getConfigMap(t, d.Client).Data["config.yaml"]
+	if events := drainEvents(rec); len(events) != 0 {
+		t.Fatalf("expected no events on create, got %v", events)
+	}
+
+	cm := getConfigMap(t, d.Client)
+	cm.Data["config.yaml"] = "version: v1\nsharing: {}\n"
+	cm.Data["debug.yaml"] = "verbose: true\n"
+	if err := d.Client.Update(context.Background(), cm); err != nil {
+		t.Fatalf("edit ConfigMap: %v", err)
+	}
+
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	reverted := getConfigMap(t, d.Client)
+	if len(reverted.Data) != 1 || reverted.Data["config.yaml"] != rendered {
+		t.Fatalf("expected rendered config only, got %v", reverted.Data)
+	}
+	events := drainEvents(rec)
+	if len(events) != 1 || !strings.Contains(events[0], EventConfigDriftReverted) || !strings.Contains(events[0], "keys: config.yaml, debug.yaml") {
+		t.Fatalf("expected a single drift event naming both keys, got %v", events)
+	}
+
+	// A clean reconcile afterwards does not report drift again.
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if events := drainEvents(rec); len(events) != 0 {
+		t.Fatalf("expected no events without drift, got %v", events)
+	}
+}
+
+func TestReconcileRendererUpdateIsNotDrift(t *testing.T) {
+	d, rec := newDriftDeps(t)
+	pool := driftPool()
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("initial reconcile: %v", err)
+	}
+
+	pool.Spec.Resource.SlicesPerUnit = 4
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if !strings.Contains(getConfigMap(t, d.Client).Data["config.yaml"], "timeSlicing") {
+		t.Fatalf("expected renderer update to be applied")
+	}
+	if events := drainEvents(rec); len(events) != 0 {
+		t.Fatalf("expected no drift events for renderer updates, got %v", events)
+	}
+}
+
+func TestReconcileUnmanagedConfigMap(t *testing.T) {
+	d, rec := newDriftDeps(t)
+	pool := driftPool()
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("initial reconcile: %v", err)
+	}
+
+	const patched = "version: v1\nsharing:\n  timeSlicing: {}\n"
+	cm := getConfigMap(t, d.Client)
+	cm.Annotations[UnmanagedAnnotation] = "true"
+	cm.Data["config.yaml"] = patched
+	if err := d.Client.Update(context.Background(), cm); err != nil {
+		t.Fatalf("edit ConfigMap: %v", err)
+	}
+
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if got := getConfigMap(t, d.Client).Data["config.yaml"]; got != patched {
+		t.Fatalf("expected unmanaged config to be kept, got %q", got)
+	}
+	cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionConfigUnmanaged)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("expected %s=True, got %+v", ConditionConfigUnmanaged, pool.Status.Conditions)
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha"}, ds); err != nil {
+		t.Fatalf("get DaemonSet: %v", err)
+	}
+	if ds.Spec.Template.Annotations[poolcommon.ConfigHashAnnotation] != poolcommon.ConfigHash(getConfigMap(t, d.Client)) {
+		t.Fatalf("expected DaemonSet to follow the unmanaged config")
+	}
+	if events := drainEvents(rec); len(events) != 0 {
+		t.Fatalf("expected no events while unmanaged, got %v", events)
+	}
+
+	// Dropping the annotation hands the ConfigMap back to the renderer.
+	cm = getConfigMap(t, d.Client)
+	delete(cm.Annotations, UnmanagedAnnotation)
+	if err := d.Client.Update(context.Background(), cm); err != nil {
+		t.Fatalf("update ConfigMap: %v", err)
+	}
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if getConfigMap(t, d.Client).Data["config.yaml"] == patched {
+		t.Fatalf("expected config to be rendered again")
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionConfigUnmanaged) != nil {
+		t.Fatalf("expected %s to be removed", ConditionConfigUnmanaged)
+	}
+	if events := drainEvents(rec); len(events) != 1 || !strings.Contains(events[0], EventConfigDriftReverted) {
+		t.Fatalf("expected the hand-tuned config to be reported as reverted, got %v", events)
+	}
+}
+
+func TestReconcileRollsDaemonSetOnConfigChange(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	pool := driftPool()
+	getHash := func() string {
+		t.Helper()
+		ds := &appsv1.DaemonSet{}
+		if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha"}, ds); err != nil {
+			t.Fatalf("get DaemonSet: %v", err)
+		}
+		return ds.Spec.Template.Annotations[poolcommon.ConfigHashAnnotation]
+	}
+
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("initial reconcile: %v", err)
+	}
+	initial := getHash()
+	if initial == "" {
+		t.Fatalf("expected %s on the pod template", poolcommon.ConfigHashAnnotation)
+	}
+
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("repeat reconcile: %v", err)
+	}
+	if got := getHash(); got != initial {
+		t.Fatalf("expected the hash to stay %q without config changes, got %q", initial, got)
+	}
+
+	pool.Spec.Resource.SlicesPerUnit = 4
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile after slicesPerUnit change: %v", err)
+	}
+	if got := getHash(); got == initial {
+		t.Fatalf("expected the hash to change with the config")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+)
+
+const (
+	// ConditionHealthChecksUnavailable is True while spec.healthChecks is enabled but no DCGM host engine serves
+	// the pool; the device plugin then runs with its default health checks.
+	ConditionHealthChecksUnavailable = "HealthChecksUnavailable"
+	// ReasonDCGMHostEngineMissing is set on ConditionHealthChecksUnavailable.
+	ReasonDCGMHostEngineMissing = "DCGMHostEngineMissing"
+
+	// DefaultDCGMSocket is where the module's DCGM host engine listens on the node.
+	DefaultDCGMSocket = "/run/nvidia/dcgm/nv-hostengine.sock"
+
+	dcgmSocketVolume = "dcgm-socket"
+	dcgmSocketDir    = "/run/dcgm"
+)
+
+// healthChecks is the health check setup rendered into the device plugin.
+type healthChecks struct {
+	// disabled turns every plugin health check off.
+	disabled bool
+	// dcgm enables DCGM checks through the host engine socket at socket.
+	dcgm      bool
+	socket    string
+	threshold int32
+}
+
+// resolveHealthChecks decides what spec.healthChecks renders to. An enabled pool without a DCGM host engine
+// gets ConditionHealthChecksUnavailable and the plugin defaults, since a missing socket would fail the plugin.
+func resolveHealthChecks(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) (healthChecks, error) {
+	spec := pool.Spec.HealthChecks
+	if spec == nil || !spec.Enabled {
+		meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionHealthChecksUnavailable)
+		return healthChecks{disabled: spec != nil}, nil
+	}
+
+	hc := healthChecks{dcgm: true, socket: spec.DCGMSocket, threshold: spec.UnhealthyThreshold}
+	if hc.threshold < 1 {
+		hc.threshold = 1
+	}
+	// An explicit socket points at a host engine managed outside the module, which the controller cannot see.
+	if hc.socket == "" {
+		hc.socket = DefaultDCGMSocket
+		ready, err := dcgmHostEngineReady(ctx, d.Client)
+		if err != nil {
+			return healthChecks{}, err
+		}
+		if !ready {
+			meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+				Type:               ConditionHealthChecksUnavailable,
+				Status:             metav1.ConditionTrue,
+				Reason:             ReasonDCGMHostEngineMissing,
+				Message:            fmt.Sprintf("healthChecks are enabled but DaemonSet %s/%s has no ready DCGM host engine; set healthChecks.dcgmSocket to use an external one", common.WorkloadsNamespace, common.AppName(common.ComponentDCGM)),
+				ObservedGeneration: pool.Generation,
+			})
+			return healthChecks{}, nil
+		}
+	}
+	meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionHealthChecksUnavailable)
+	return hc, nil
+}
+
+func dcgmHostEngineReady(ctx context.Context, c client.Client) (bool, error) {
+	list := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, list,
+		client.InNamespace(common.WorkloadsNamespace),
+		client.MatchingLabels{"app": common.AppName(common.ComponentDCGM)}); err != nil {
+		return false, fmt.Errorf("list DCGM DaemonSets: %w", err)
+	}
+	for i := range list.Items {
+		if list.Items[i].Status.NumberReady > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (hc healthChecks) containerSocket() string {
+	return path.Join(dcgmSocketDir, path.Base(hc.socket))
+}
+
+func (hc healthChecks) env() []corev1.EnvVar {
+	switch {
+	case hc.disabled:
+		return []corev1.EnvVar{{Name: "DP_DISABLE_HEALTHCHECKS", Value: "all"}}
+	case hc.dcgm:
+		return []corev1.EnvVar{
+			{Name: "DP_HEALTHCHECK_BACKEND", Value: "dcgm"},
+			{Name: "DCGM_HOSTENGINE_SOCKET", Value: hc.containerSocket()},
+			{Name: "DP_HEALTHCHECK_UNHEALTHY_THRESHOLD", Value: strconv.Itoa(int(hc.threshold))},
+		}
+	}
+	return nil
+}
+
+func (hc healthChecks) volumeMounts() []corev1.VolumeMount {
+	if !hc.dcgm {
+		return nil
+	}
+	return []corev1.VolumeMount{{Name: dcgmSocketVolume, MountPath: dcgmSocketDir, MountPropagation: ptr.To(corev1.MountPropagationHostToContainer)}}
+}
+
+// volumes mounts the socket's directory, which survives host engine restarts that recreate the socket.
+func (hc healthChecks) volumes() []corev1.Volume {
+	if !hc.dcgm {
+		return nil
+	}
+	return []corev1.Volume{{
+		Name: dcgmSocketVolume,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: path.Dir(hc.socket),
+				Type: kube.HostPathType(corev1.HostPathDirectory),
+			},
+		},
+	}}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+)
+
+func dcgmDaemonSet(ready int32) *appsv1.DaemonSet {
+	name := common.AppName(common.ComponentDCGM)
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.WorkloadsNamespace, Labels: map[string]string{"app": name}},
+		Status:     appsv1.DaemonSetStatus{NumberReady: ready},
+	}
+}
+
+func envValue(container corev1.Container, name string) (string, bool) {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return env.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestReconcileRendersDCGMHealthChecks(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	if err := d.Client.Create(context.Background(), dcgmDaemonSet(2)); err != nil {
+		t.Fatalf("create DCGM DaemonSet: %v", err)
+	}
+	pool := driftPool()
+	pool.Spec.HealthChecks = &v1alpha1.GPUPoolHealthChecksSpec{Enabled: true, UnhealthyThreshold: 3}
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	spec := getDaemonSet(t, d.Client).Spec.Template.Spec
+	container := spec.Containers[0]
+	if v, _ := envValue(container, "DCGM_HOSTENGINE_SOCKET"); v != "/run/dcgm/nv-hostengine.sock" {
+		t.Fatalf("expected the container socket path, got %q", v)
+	}
+	if v, _ := envValue(container, "DP_HEALTHCHECK_UNHEALTHY_THRESHOLD"); v != "3" {
+		t.Fatalf("expected threshold 3, got %q", v)
+	}
+	if _, ok := envValue(container, "DP_DISABLE_HEALTHCHECKS"); ok {
+		t.Fatalf("health checks must not be disabled, got env %+v", container.Env)
+	}
+	if !hasMount(container, dcgmSocketDir) || !hasHostPathVolume(spec, "/run/nvidia/dcgm") {
+		t.Fatalf("expected the DCGM socket directory to be mounted, got mounts %+v volumes %+v", container.VolumeMounts, spec.Volumes)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionHealthChecksUnavailable) != nil {
+		t.Fatalf("expected no HealthChecksUnavailable condition, got %+v", pool.Status.Conditions)
+	}
+
+	// Turning health checks off removes the mounts and disables the plugin's checks.
+	pool.Spec.HealthChecks.Enabled = false
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	spec = getDaemonSet(t, d.Client).Spec.Template.Spec
+	container = spec.Containers[0]
+	if hasMount(container, dcgmSocketDir) || hasHostPathVolume(spec, "/run/nvidia/dcgm") {
+		t.Fatalf("expected the DCGM socket to be removed, got mounts %+v volumes %+v", container.VolumeMounts, spec.Volumes)
+	}
+	if v, _ := envValue(container, "DP_DISABLE_HEALTHCHECKS"); v != "all" {
+		t.Fatalf("expected health checks disabled, got %q", v)
+	}
+	if _, ok := envValue(container, "DCGM_HOSTENGINE_SOCKET"); ok {
+		t.Fatalf("expected no DCGM env, got %+v", container.Env)
+	}
+
+	// Unsetting the block restores the plugin defaults.
+	pool.Spec.HealthChecks = nil
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	container = getDaemonSet(t, d.Client).Spec.Template.Spec.Containers[0]
+	if _, ok := envValue(container, "DP_DISABLE_HEALTHCHECKS"); ok {
+		t.Fatalf("expected plugin defaults, got env %+v", container.Env)
+	}
+}
+
+func TestReconcileHealthChecksWithoutDCGM(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	if err := d.Client.Create(context.Background(), dcgmDaemonSet(0)); err != nil {
+		t.Fatalf("create DCGM DaemonSet: %v", err)
+	}
+	pool := driftPool()
+	pool.Spec.HealthChecks = &v1alpha1.GPUPoolHealthChecksSpec{Enabled: true}
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionHealthChecksUnavailable)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != ReasonDCGMHostEngineMissing {
+		t.Fatalf("expected HealthChecksUnavailable, got %+v", cond)
+	}
+	spec := getDaemonSet(t, d.Client).Spec.Template.Spec
+	container := spec.Containers[0]
+	if hasMount(container, dcgmSocketDir) || len(spec.Volumes) != len(devicePluginVolumes(d, pool)) {
+		t.Fatalf("expected no DCGM socket without a host engine, got mounts %+v volumes %+v", container.VolumeMounts, spec.Volumes)
+	}
+	if _, ok := envValue(container, "DCGM_HOSTENGINE_SOCKET"); ok {
+		t.Fatalf("expected no DCGM env, got %+v", container.Env)
+	}
+
+	// An external host engine socket does not depend on the module's DCGM.
+	pool.Spec.HealthChecks.DCGMSocket = "/var/run/nvidia-dcgm/dcgm.sock"
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionHealthChecksUnavailable) != nil {
+		t.Fatalf("expected the condition to clear, got %+v", pool.Status.Conditions)
+	}
+	spec = getDaemonSet(t, d.Client).Spec.Template.Spec
+	container = spec.Containers[0]
+	if !hasHostPathVolume(spec, "/var/run/nvidia-dcgm") {
+		t.Fatalf("expected the external socket directory, got %+v", spec.Volumes)
+	}
+	if v, _ := envValue(container, "DCGM_HOSTENGINE_SOCKET"); v != "/run/dcgm/dcgm.sock" {
+		t.Fatalf("unexpected socket env %q", v)
+	}
+	if v, _ := envValue(container, "DP_HEALTHCHECK_UNHEALTHY_THRESHOLD"); v != "1" {
+		t.Fatalf("expected the default threshold, got %q", v)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migration"
+)
+
+func gpuOperatorDevicePlugin(annotations map[string]string) *appsv1.DaemonSet {
+	labels := map[string]string{"app": "nvidia-device-plugin-daemonset"}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nvidia-device-plugin-daemonset",
+			Namespace:   "ns",
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "nvidia.com/v1", Kind: "ClusterPolicy", Name: "cluster-policy", UID: "cp-uid", Controller: ptr.To(true),
+			}},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, CurrentNumberScheduled: 1},
+	}
+}
+
+func TestReconcileBlockedByGPUOperatorDevicePlugin(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	d.Config.MigrateFromGPUOperator = true
+	if err := d.Client.Create(context.Background(), gpuOperatorDevicePlugin(nil)); err != nil {
+		t.Fatalf("create upstream DaemonSet: %v", err)
+	}
+	pool := driftPool()
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(pool.Status.Conditions, migration.ConditionMigrationBlocked) {
+		t.Fatalf("expected MigrationBlocked, got %+v", pool.Status.Conditions)
+	}
+	err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha"}, &appsv1.DaemonSet{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no pool DaemonSet while blocked, got %v", err)
+	}
+}
+
+func TestReconcileAdoptsGPUOperatorDevicePlugin(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	d.Config.MigrateFromGPUOperator = true
+	d.Config.AdoptExisting = true
+	if err := d.Client.Create(context.Background(), gpuOperatorDevicePlugin(map[string]string{migration.AdoptAnnotation: "alpha"})); err != nil {
+		t.Fatalf("create upstream DaemonSet: %v", err)
+	}
+	pool := driftPool()
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, migration.ConditionMigrationBlocked) != nil {
+		t.Fatalf("expected no MigrationBlocked, got %+v", pool.Status.Conditions)
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-daemonset"}, ds); err != nil {
+		t.Fatalf("get adopted DaemonSet: %v", err)
+	}
+	if ds.Labels["pool"] != "alpha" || ds.Labels["app"] != "nvidia-device-plugin" {
+		t.Fatalf("expected the adopted DaemonSet to be relabelled, got %v", ds.Labels)
+	}
+	if len(ds.OwnerReferences) != 1 || ds.OwnerReferences[0].Kind != "GPUPool" || ds.OwnerReferences[0].Name != "alpha" {
+		t.Fatalf("expected the pool to own the adopted DaemonSet, got %+v", ds.OwnerReferences)
+	}
+	if ds.Spec.Template.Spec.Containers[0].Image != "dp:tag" || ds.Spec.Template.Labels["app"] != "nvidia-device-plugin-daemonset" {
+		t.Fatalf("expected the rendered pod template under the upstream selector, got %+v", ds.Spec.Template)
+	}
+	if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha"}, &appsv1.DaemonSet{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no second device plugin DaemonSet, got %v", err)
+	}
+	if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-daemonset"}, &policyv1.PodDisruptionBudget{}); err != nil {
+		t.Fatalf("expected a PodDisruptionBudget for the adopted DaemonSet: %v", err)
+	}
+
+	// A second pass keeps rendering into the adopted DaemonSet.
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-device-plugin-alpha"}, &appsv1.DaemonSet{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no second device plugin DaemonSet, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/overrides"
+)
+
+func TestReconcileAppliesDevicePluginOverrides(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	pool := driftPool()
+	pool.Spec.DevicePlugin = &v1alpha1.GPUPoolComponentOverrides{
+		ExtraEnv:          []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+		ExtraVolumes:      []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+		ExtraVolumeMounts: []corev1.VolumeMount{{Name: "scratch", MountPath: "/scratch"}},
+	}
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	ds := getDaemonSet(t, d.Client)
+	spec := ds.Spec.Template.Spec
+	container := spec.Containers[0]
+	if last := container.Env[len(container.Env)-1]; last.Name != "HTTPS_PROXY" {
+		t.Fatalf("expected the extra env to be applied last, got %+v", container.Env)
+	}
+	if v, _ := envValue(container, "NVIDIA_VISIBLE_DEVICES"); v != "all" {
+		t.Fatalf("expected the rendered env to stay in place, got %q", v)
+	}
+	if !hasMount(container, "/scratch") || !hasVolume(spec, "scratch") {
+		t.Fatalf("expected the extra volume to be mounted, got mounts %+v volumes %+v", container.VolumeMounts, spec.Volumes)
+	}
+
+	// Dropping the overrides must update the DaemonSet rather than leave the extras behind.
+	pool.Spec.DevicePlugin = nil
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	updated := getDaemonSet(t, d.Client)
+	if updated.ResourceVersion == ds.ResourceVersion {
+		t.Fatalf("expected the DaemonSet to be updated")
+	}
+	spec = updated.Spec.Template.Spec
+	container = spec.Containers[0]
+	if _, ok := envValue(container, "HTTPS_PROXY"); ok || hasMount(container, "/scratch") || hasVolume(spec, "scratch") {
+		t.Fatalf("expected the extras to be removed, got env %+v mounts %+v volumes %+v", container.Env, container.VolumeMounts, spec.Volumes)
+	}
+}
+
+// TestDevicePluginReservedNames keeps overrides.DevicePlugin in step with what the renderer can produce.
+func TestDevicePluginReservedNames(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	variants := []struct {
+		install v1alpha1.GPUPoolDriverInstallType
+		hc      healthChecks
+	}{
+		{install: v1alpha1.GPUPoolDriverInstallOperator, hc: healthChecks{dcgm: true, socket: DefaultDCGMSocket, threshold: 1}},
+		{install: v1alpha1.GPUPoolDriverInstallPreinstalled, hc: healthChecks{disabled: true}},
+	}
+	for _, v := range variants {
+		pool := driftPool()
+		pool.Spec.DriverInstallType = v.install
+		pool.Spec.Advanced = &v1alpha1.GPUPoolAdvancedSpec{GPUDirectRDMA: true}
+		spec := devicePluginDaemonSet(context.Background(), d, pool, v.hc).Spec.Template.Spec
+
+		for _, env := range spec.Containers[0].Env {
+			if !slices.Contains(overrides.DevicePlugin.Env, env.Name) {
+				t.Errorf("env %s is rendered but not reserved", env.Name)
+			}
+		}
+		for _, volume := range spec.Volumes {
+			if !slices.Contains(overrides.DevicePlugin.Volumes, volume.Name) {
+				t.Errorf("volume %s is rendered but not reserved", volume.Name)
+			}
+		}
+		for _, mount := range spec.Containers[0].VolumeMounts {
+			if !slices.Contains(overrides.DevicePlugin.MountPaths, mount.MountPath) {
+				t.Errorf("mount path %s is rendered but not reserved", mount.MountPath)
+			}
+		}
+	}
+}
+
+func hasVolume(spec corev1.PodSpec, name string) bool {
+	return slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == name })
+}
@@ -18,27 +18,68 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/critical"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/hostports"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migration"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/ops"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 )
 
-// Reconcile ensures the device plugin ConfigMap and DaemonSet are up to date.
+// Reconcile ensures the device plugin RBAC, ConfigMap, DaemonSet and PodDisruptionBudget are up to date. The
+// renderer owns the whole ConfigMap data: manual edits and extra keys are reverted, unless the ConfigMap is
+// annotated as unmanaged.
 func Reconcile(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) error {
+	if err := rbac.Reconcile(ctx, d, rbac.DevicePlugin, pool); err != nil {
+		return err
+	}
+
 	cm := devicePluginConfigMap(ctx, d, pool)
-	if err := ops.CreateOrUpdate(ctx, d.Client, cm, pool); err != nil {
-		return fmt.Errorf("reconcile device-plugin ConfigMap: %w", err)
+	current, err := commonobject.FetchObject(ctx, client.ObjectKeyFromObject(cm), d.Client, &corev1.ConfigMap{})
+	if err != nil {
+		return fmt.Errorf("get device-plugin ConfigMap: %w", err)
+	}
+	if isUnmanaged(current) {
+		setUnmanagedCondition(pool, current)
+		// The DaemonSet keeps following the live config, so the hand-tuned one is rolled out.
+		cm = current
+	} else {
+		meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionConfigUnmanaged)
+		stampRenderedHash(cm)
+		drifted := driftedKeys(current, cm)
+		if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, cm, pool); err != nil {
+			return fmt.Errorf("reconcile device-plugin ConfigMap: %w", err)
+		}
+		if len(drifted) > 0 {
+			recordDriftReverted(d, pool, cm, drifted)
+		}
 	}
 
-	ds := devicePluginDaemonSet(ctx, d, pool)
-	configHash := sha256Hex(cm.Data["config.yaml"])
-	if ds.Spec.Template.Annotations == nil {
-		ds.Spec.Template.Annotations = map[string]string{}
+	hc, err := resolveHealthChecks(ctx, d, pool)
+	if err != nil {
+		return err
 	}
-	ds.Spec.Template.Annotations["gpu.deckhouse.io/device-plugin-config-hash"] = configHash
-	if err := ops.CreateOrUpdate(ctx, d.Client, ds, pool); err != nil {
+	ds := devicePluginDaemonSet(ctx, d, pool, hc)
+	poolcommon.StampConfigHash(&ds.Spec.Template, cm)
+	if blocked, err := migration.Guard(ctx, d, pool, migration.DevicePlugin, ds); err != nil || blocked {
+		return err
+	}
+	if blocked, err := hostports.Guard(ctx, d.Client, pool, ds); err != nil || blocked {
+		return err
+	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, ds, pool); err != nil {
 		return fmt.Errorf("reconcile device-plugin DaemonSet: %w", err)
 	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, critical.PodDisruptionBudget(ds), pool); err != nil {
+		return fmt.Errorf("reconcile device-plugin PodDisruptionBudget: %w", err)
+	}
 
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/go-logr/logr/testr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +38,7 @@ func TestReconcileErrorPaths(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"}}
@@ -46,8 +48,11 @@ func TestReconcileErrorPaths(t *testing.T) {
 		failOn   int
 		contains string
 	}{
-		{name: "configmap", failOn: 1, contains: "reconcile device-plugin ConfigMap"},
-		{name: "daemonset", failOn: 2, contains: "reconcile device-plugin DaemonSet"},
+		{name: "serviceaccount", failOn: 1, contains: "reconcile nvidia-device-plugin ServiceAccount"},
+		{name: "clusterrole", failOn: 2, contains: "reconcile nvidia-device-plugin ClusterRole"},
+		{name: "clusterrolebinding", failOn: 3, contains: "reconcile nvidia-device-plugin ClusterRoleBinding"},
+		{name: "configmap", failOn: 4, contains: "reconcile device-plugin ConfigMap"},
+		{name: "daemonset", failOn: 5, contains: "reconcile device-plugin DaemonSet"},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,59 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceplugin
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+)
+
+func TestDevicePluginRestrictedSecurityContext(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	pool := driftPool()
+	pool.Spec.Advanced = &v1alpha1.GPUPoolAdvancedSpec{GPUDirectRDMA: true}
+
+	spec := devicePluginDaemonSet(context.Background(), d, pool, healthChecks{}).Spec.Template.Spec
+
+	if sc := spec.SecurityContext; sc.SeccompProfile == nil || sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Fatalf("expected the RuntimeDefault seccomp profile on the pod, got %+v", sc)
+	}
+	sc := spec.Containers[0].SecurityContext
+	if *sc.Privileged || *sc.AllowPrivilegeEscalation || !*sc.ReadOnlyRootFilesystem {
+		t.Fatalf("expected an unprivileged read-only device plugin, got %+v", sc)
+	}
+	if caps := sc.Capabilities; len(caps.Drop) != 1 || caps.Drop[0] != "ALL" || len(caps.Add) != 1 || caps.Add[0] != kube.CapabilityIPCLock {
+		t.Fatalf("expected every capability dropped but IPC_LOCK, got %+v", caps)
+	}
+}
+
+func TestDevicePluginLegacyPrivileged(t *testing.T) {
+	d, _ := newDriftDeps(t)
+	d.Config.LegacyPrivileged = true
+
+	spec := devicePluginDaemonSet(context.Background(), d, driftPool(), healthChecks{}).Spec.Template.Spec
+
+	if spec.SecurityContext.SeccompProfile != nil {
+		t.Fatalf("expected no seccomp profile in legacy mode, got %+v", spec.SecurityContext.SeccompProfile)
+	}
+	sc := spec.Containers[0].SecurityContext
+	if !*sc.Privileged || !*sc.AllowPrivilegeEscalation || *sc.ReadOnlyRootFilesystem || sc.Capabilities != nil {
+		t.Fatalf("expected the legacy privileged context, got %+v", sc)
+	}
+}
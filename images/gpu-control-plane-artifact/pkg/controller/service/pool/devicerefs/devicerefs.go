@@ -0,0 +1,151 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devicerefs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+const (
+	// ConditionNotFound is True while some entries of spec.devices do not resolve to a device the pool can use.
+	ConditionNotFound = "DevicesNotFound"
+
+	reasonNotFound = "DevicesNotFound"
+	reasonFound    = "AllDevicesFound"
+
+	// maxListedRefs bounds the references put into the condition message.
+	maxListedRefs = 10
+)
+
+// Explicit reports whether the pool lists its devices in spec.devices instead of selecting them.
+func Explicit(pool *v1alpha1.GPUPool) bool {
+	return pool != nil && len(pool.Spec.Devices) > 0
+}
+
+// Resolution is the outcome of resolving spec.devices against the device index.
+type Resolution struct {
+	// Devices holds every referenced device once, in spec order.
+	Devices []v1alpha1.GPUDevice
+	// NotFound lists the references that match no device or a device already owned by another pool.
+	NotFound []string
+}
+
+// Resolve looks up the devices referenced by spec.devices. Missing references do not fail the pass:
+// the pool is built from what resolved and the rest is reported through SetCondition.
+func Resolve(ctx context.Context, c client.Client, pool *v1alpha1.GPUPool) (Resolution, error) {
+	var res Resolution
+	seen := make(map[string]struct{}, len(pool.Spec.Devices))
+	for _, ref := range pool.Spec.Devices {
+		dev, err := lookup(ctx, c, ref)
+		if err != nil {
+			return Resolution{}, err
+		}
+		label := refLabel(ref)
+		if dev == nil {
+			res.NotFound = append(res.NotFound, label)
+			continue
+		}
+		if owner := dev.Status.PoolRef; owner != nil && owner.Name != "" && !poolcommon.PoolRefMatchesPool(pool, owner) {
+			res.NotFound = append(res.NotFound, fmt.Sprintf("%s (owned by pool %s)", label, poolName(owner)))
+			continue
+		}
+		if _, ok := seen[dev.Name]; ok {
+			continue
+		}
+		seen[dev.Name] = struct{}{}
+		res.Devices = append(res.Devices, *dev)
+	}
+	return res, nil
+}
+
+func lookup(ctx context.Context, c client.Client, ref v1alpha1.GPUDeviceReference) (*v1alpha1.GPUDevice, error) {
+	if name := strings.TrimSpace(ref.Name); name != "" {
+		return commonobject.FetchObject(ctx, client.ObjectKey{Name: name}, c, &v1alpha1.GPUDevice{})
+	}
+	uuid := strings.TrimSpace(ref.UUID)
+	if uuid == "" {
+		return nil, nil
+	}
+	list := &v1alpha1.GPUDeviceList{}
+	if err := c.List(ctx, list, client.MatchingFields{indexer.GPUDeviceUUIDField: uuid}); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	// A UUID belongs to one card; pick deterministically if a stale object still carries it.
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	return &list.Items[0], nil
+}
+
+func refLabel(ref v1alpha1.GPUDeviceReference) string {
+	if name := strings.TrimSpace(ref.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(ref.UUID)
+}
+
+func poolName(ref *v1alpha1.GPUPoolReference) string {
+	if ref.Namespace == "" {
+		return ref.Name
+	}
+	return ref.Namespace + "/" + ref.Name
+}
+
+// SetCondition records the unresolved references of an explicit pool and drops the condition from selector pools.
+func SetCondition(pool *v1alpha1.GPUPool, res Resolution) {
+	if !Explicit(pool) {
+		meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionNotFound)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:               ConditionNotFound,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonFound,
+		Message:            "all listed devices were found",
+		ObservedGeneration: pool.Generation,
+	}
+	if len(res.NotFound) > 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = reasonNotFound
+		cond.Message = notFoundMessage(res.NotFound)
+	}
+	meta.SetStatusCondition(&pool.Status.Conditions, cond)
+}
+
+func notFoundMessage(refs []string) string {
+	listed := refs
+	if len(listed) > maxListedRefs {
+		listed = listed[:maxListedRefs]
+	}
+	msg := fmt.Sprintf("%d listed device(s) not found: %s", len(refs), strings.Join(listed, ", "))
+	if rest := len(refs) - len(listed); rest > 0 {
+		msg += fmt.Sprintf(" and %d more", rest)
+	}
+	return msg
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devicerefs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+func device(name, uuid string, owner *v1alpha1.GPUPoolReference) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: "node1",
+			PoolRef:  owner,
+			Hardware: v1alpha1.GPUDeviceHardware{UUID: uuid},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(indexer.IndexGPUDeviceByUUID()).
+		WithObjects(
+			device("node1-0", "GPU-a", nil),
+			device("node1-1", "GPU-b", &v1alpha1.GPUPoolReference{Name: "pool", Namespace: "ns"}),
+			device("node2-0", "GPU-c", &v1alpha1.GPUPoolReference{Name: "other", Namespace: "ns"}),
+		).
+		Build()
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
+		Spec: v1alpha1.GPUPoolSpec{Devices: []v1alpha1.GPUDeviceReference{
+			{UUID: "GPU-a"},
+			{Name: "node1-1"},
+			{UUID: "GPU-missing"},
+			{Name: "node1-0"},
+			{UUID: "GPU-c"},
+			{Name: "gone"},
+		}},
+	}
+
+	res, err := Resolve(context.Background(), cl, pool)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	var names []string
+	for _, dev := range res.Devices {
+		names = append(names, dev.Name)
+	}
+	if strings.Join(names, ",") != "node1-0,node1-1" {
+		t.Fatalf("expected each resolvable device once in spec order, got %v", names)
+	}
+	want := "GPU-missing,GPU-c (owned by pool ns/other),gone"
+	if got := strings.Join(res.NotFound, ","); got != want {
+		t.Fatalf("unexpected unresolved references %q", got)
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Generation: 4},
+		Spec:       v1alpha1.GPUPoolSpec{Devices: []v1alpha1.GPUDeviceReference{{UUID: "GPU-a"}}},
+	}
+
+	SetCondition(pool, Resolution{})
+	cond := apimeta.FindStatusCondition(pool.Status.Conditions, ConditionNotFound)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.ObservedGeneration != 4 {
+		t.Fatalf("expected DevicesNotFound=False, got %+v", cond)
+	}
+
+	var missing []string
+	for i := 0; i < 12; i++ {
+		missing = append(missing, fmt.Sprintf("GPU-%02d", i))
+	}
+	SetCondition(pool, Resolution{NotFound: missing})
+	cond = apimeta.FindStatusCondition(pool.Status.Conditions, ConditionNotFound)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonNotFound {
+		t.Fatalf("expected DevicesNotFound=True, got %+v", cond)
+	}
+	if !strings.HasPrefix(cond.Message, "12 listed device(s) not found: GPU-00, GPU-01") || !strings.HasSuffix(cond.Message, "GPU-09 and 2 more") {
+		t.Fatalf("unexpected condition message %q", cond.Message)
+	}
+
+	pool.Spec.Devices = nil
+	SetCondition(pool, Resolution{})
+	if apimeta.FindStatusCondition(pool.Status.Conditions, ConditionNotFound) != nil {
+		t.Fatalf("expected the condition to be removed from a selector pool")
+	}
+}
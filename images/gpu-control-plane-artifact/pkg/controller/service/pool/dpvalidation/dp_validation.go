@@ -17,6 +17,7 @@ package dpvalidation
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -25,16 +26,22 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/devicestate"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/devicerefs"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/deviceprovider"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
 // DPValidationHandler tracks per-pool validator readiness per node and updates device states.
 // PendingAssignment -> Assigned when validator pod Ready on device node.
 type DPValidationHandler struct {
-	log     logr.Logger
-	client  client.Client
-	ns      string
-	podList func(ctx context.Context, cl client.Client, opts ...client.ListOption) (*corev1.PodList, error)
+	log      logr.Logger
+	client   client.Client
+	recorder eventrecord.EventRecorderLogger
+	ns       string
+	podList  func(ctx context.Context, cl client.Client, opts ...client.ListOption) (*corev1.PodList, error)
 }
 
 func NewDPValidationHandler(log logr.Logger, cl client.Client) *DPValidationHandler {
@@ -56,6 +63,11 @@ func NewDPValidationHandler(log logr.Logger, cl client.Client) *DPValidationHand
 	}
 }
 
+// SetRecorder enables Events for the device state transitions made by the handler.
+func (h *DPValidationHandler) SetRecorder(recorder eventrecord.EventRecorderLogger) {
+	h.recorder = recorder
+}
+
 func (h *DPValidationHandler) Name() string {
 	return "dp-validation"
 }
@@ -64,6 +76,10 @@ func (h *DPValidationHandler) HandlePool(ctx context.Context, pool *v1alpha1.GPU
 	if h.client == nil {
 		return reconcile.Result{}, nil
 	}
+	healthLabels := deviceprovider.Default().ForPool(pool).HealthPodLabels(pool)
+	if healthLabels == nil {
+		return reconcile.Result{}, nil
+	}
 
 	assignmentKey := poolcommon.AssignmentAnnotationKey(pool)
 	assignmentField := indexer.GPUDeviceNamespacedAssignmentField
@@ -76,7 +92,7 @@ func (h *DPValidationHandler) HandlePool(ctx context.Context, pool *v1alpha1.GPU
 	{
 		l, err := h.podList(ctx, h.client,
 			client.InNamespace(h.ns),
-			client.MatchingLabels{"app": "nvidia-operator-validator", "pool": pool.Name},
+			client.MatchingLabels(healthLabels),
 		)
 		if err != nil && client.IgnoreNotFound(err) != nil {
 			return reconcile.Result{}, err
@@ -96,38 +112,51 @@ func (h *DPValidationHandler) HandlePool(ctx context.Context, pool *v1alpha1.GPU
 		}
 	}
 
+	// Devices of a pool with spec.devices carry no assignment annotation; the poolRef set by selection marks them.
 	devices := &v1alpha1.GPUDeviceList{}
-	if err := h.client.List(ctx, devices, client.MatchingFields{assignmentField: pool.Name}); err != nil {
+	explicit := devicerefs.Explicit(pool)
+	listField := assignmentField
+	if explicit {
+		listField = indexer.GPUDevicePoolRefNameField
+	}
+	if err := h.client.List(ctx, devices, client.MatchingFields{listField: pool.Name}); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	changed := false
 	for i := range devices.Items {
 		dev := &devices.Items[i]
+		if explicit && !poolcommon.PoolRefMatchesPool(pool, dev.Status.PoolRef) {
+			continue
+		}
 		node := poolcommon.DeviceNodeName(dev)
 		if node == "" {
 			continue
 		}
-		target := dev.Status.State
+		target, reason := dev.Status.State, ""
 		switch dev.Status.State {
 		case v1alpha1.GPUDeviceStatePendingAssignment:
 			if readyNodes[node] {
-				target = v1alpha1.GPUDeviceStateAssigned
+				target, reason = v1alpha1.GPUDeviceStateAssigned, "ValidatorReady"
 			}
 		case v1alpha1.GPUDeviceStateAssigned:
 			// If validator is not ready (e.g., failing), keep device pending validation.
 			if !readyNodes[node] {
-				target = v1alpha1.GPUDeviceStatePendingAssignment
+				target, reason = v1alpha1.GPUDeviceStatePendingAssignment, "ValidatorNotReady"
 			}
 		}
 		if target == dev.Status.State {
 			continue
 		}
 		orig := dev.DeepCopy()
-		dev.Status.State = target
+		transition := devicestate.SetState(dev, target, reason, time.Now())
+		if err := objsize.Guard(dev); err != nil {
+			return reconcile.Result{}, err
+		}
 		if err := h.client.Status().Patch(ctx, dev, client.MergeFrom(orig)); err != nil {
 			return reconcile.Result{}, err
 		}
+		devicestate.Notify(h.recorder, h.log, dev, transition)
 		changed = true
 		h.log.V(1).Info("updated device state based on DP validator", "device", dev.Name, "state", target)
 	}
@@ -16,19 +16,31 @@ package dpvalidation
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/devicestate"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
 )
 
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
 func TestDPValidationHandlerName(t *testing.T) {
 	h := NewDPValidationHandler(testr.New(t), nil)
 	if h.Name() != "dp-validation" {
@@ -75,6 +87,8 @@ func TestDPValidationTransitions(t *testing.T) {
 		Build()
 	h := NewDPValidationHandler(testr.New(t), cl)
 	h.ns = "ns"
+	events := record.NewFakeRecorder(4)
+	h.SetRecorder(eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: events}, "test"))
 
 	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}, Status: v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}}}
 	if _, err := h.HandlePool(context.Background(), pool); err != nil {
@@ -88,6 +102,75 @@ func TestDPValidationTransitions(t *testing.T) {
 	if updated.Status.State != v1alpha1.GPUDeviceStateAssigned {
 		t.Fatalf("expected state Assigned, got %s", updated.Status.State)
 	}
+	history := updated.Status.History
+	if len(history) != 1 || history[0].From != v1alpha1.GPUDeviceStatePendingAssignment ||
+		history[0].To != v1alpha1.GPUDeviceStateAssigned || history[0].Reason != "ValidatorReady" {
+		t.Fatalf("unexpected history %+v", history)
+	}
+	select {
+	case event := <-events.Events:
+		if !strings.Contains(event, devicestate.EventStateChanged) {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected state change event")
+	}
+}
+
+func TestDPValidationPromotesDevicesOfExplicitPool(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	device := func(name, refNamespace string) *v1alpha1.GPUDevice {
+		return &v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1alpha1.GPUDeviceStatus{
+				State:    v1alpha1.GPUDeviceStatePendingAssignment,
+				NodeName: "node1",
+				PoolRef:  &v1alpha1.GPUPoolReference{Name: "pool", Namespace: refNamespace},
+			},
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "validator",
+			Namespace: "ns",
+			Labels:    map[string]string{"app": "nvidia-operator-validator", "pool": "pool"},
+		},
+		Spec:   corev1.PodSpec{NodeName: "node1"},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+	}
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().
+		WithScheme(scheme)).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}).
+		WithObjects(device("listed", "ns"), device("same-name-other-ns", "other"), pod).
+		Build()
+	h := NewDPValidationHandler(testr.New(t), cl)
+	h.ns = "ns"
+
+	// Listed devices carry no assignment annotation; the poolRef set by selection identifies them.
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns"},
+		Spec:       v1alpha1.GPUPoolSpec{Devices: []v1alpha1.GPUDeviceReference{{Name: "listed"}}},
+	}
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+
+	for name, want := range map[string]v1alpha1.GPUDeviceState{
+		"listed":             v1alpha1.GPUDeviceStateAssigned,
+		"same-name-other-ns": v1alpha1.GPUDeviceStatePendingAssignment,
+	} {
+		updated := &v1alpha1.GPUDevice{}
+		if err := cl.Get(context.Background(), client.ObjectKey{Name: name}, updated); err != nil {
+			t.Fatalf("fetch %s: %v", name, err)
+		}
+		if updated.Status.State != want {
+			t.Fatalf("expected %s to be %s, got %s", name, want, updated.Status.State)
+		}
+	}
 }
 
 func TestDPValidationBackToPendingWhenNotReady(t *testing.T) {
@@ -0,0 +1,223 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hostports keeps pools from rendering workloads that bind the same host port on a shared node.
+package hostports
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+const (
+	// ConditionHostPortConflict is True while a pool component is not rendered because another pool's component
+	// binds one of its host ports on a shared node.
+	ConditionHostPortConflict = "HostPortConflict"
+	// ReasonPortInUse is set on ConditionHostPortConflict.
+	ReasonPortInUse = "PortInUse"
+)
+
+// Port is a host port bound by a pod.
+type Port struct {
+	Port     int32
+	Protocol corev1.Protocol
+}
+
+func (p Port) String() string {
+	return fmt.Sprintf("%d/%s", p.Port, p.Protocol)
+}
+
+// Ports lists the host ports bound by pods of the spec: every container port with hostNetwork, explicit hostPorts
+// otherwise.
+func Ports(spec corev1.PodSpec) []Port {
+	seen := map[Port]struct{}{}
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, container := range containers {
+			for _, port := range container.Ports {
+				host := port.HostPort
+				if spec.HostNetwork {
+					host = port.ContainerPort
+				}
+				if host <= 0 {
+					continue
+				}
+				protocol := port.Protocol
+				if protocol == "" {
+					protocol = corev1.ProtocolTCP
+				}
+				seen[Port{Port: host, Protocol: protocol}] = struct{}{}
+			}
+		}
+	}
+	ports := make([]Port, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+	return ports
+}
+
+// Conflict is a host port of a rendered DaemonSet that another pool's DaemonSet binds on the same node.
+type Conflict struct {
+	Port      Port
+	DaemonSet string
+	Node      string
+}
+
+// Conflicts compares the host ports of a rendered pool DaemonSet with the DaemonSets other pools run in the same
+// namespace and reports the ports bound on a node both may schedule to.
+func Conflicts(ctx context.Context, c client.Client, ds *appsv1.DaemonSet) ([]Conflict, error) {
+	ports := Ports(ds.Spec.Template.Spec)
+	if len(ports) == 0 {
+		return nil, nil
+	}
+	wanted := make(map[Port]struct{}, len(ports))
+	for _, port := range ports {
+		wanted[port] = struct{}{}
+	}
+
+	list := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, list, client.InNamespace(ds.Namespace), client.HasLabels{"pool"}); err != nil {
+		return nil, fmt.Errorf("list pool DaemonSets: %w", err)
+	}
+	var nodes *corev1.NodeList
+	var conflicts []Conflict
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Name == ds.Name || other.Labels["pool"] == ds.Labels["pool"] {
+			continue
+		}
+		var shared []Port
+		for _, port := range Ports(other.Spec.Template.Spec) {
+			if _, ok := wanted[port]; ok {
+				shared = append(shared, port)
+			}
+		}
+		if len(shared) == 0 {
+			continue
+		}
+		if nodes == nil {
+			nodes = &corev1.NodeList{}
+			if err := c.List(ctx, nodes); err != nil {
+				return nil, fmt.Errorf("list nodes: %w", err)
+			}
+		}
+		node, ok := sharedNode(nodes.Items, ds, other)
+		if !ok {
+			continue
+		}
+		for _, port := range shared {
+			conflicts = append(conflicts, Conflict{Port: port, DaemonSet: other.Name, Node: node})
+		}
+	}
+	return conflicts, nil
+}
+
+// Guard reports whether the DaemonSet must not be rendered because of host port conflicts, setting
+// ConditionHostPortConflict on the pool when it is blocked. Clearing the condition is left to the caller, which
+// guards every component of the pool.
+func Guard(ctx context.Context, c client.Client, pool *v1alpha1.GPUPool, ds *appsv1.DaemonSet) (bool, error) {
+	conflicts, err := Conflicts(ctx, c, ds)
+	if err != nil || len(conflicts) == 0 {
+		return false, err
+	}
+	parts := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		parts = append(parts, fmt.Sprintf("%s used by %s on node %s", conflict.Port, conflict.DaemonSet, conflict.Node))
+	}
+	message := fmt.Sprintf("DaemonSet %s is not rendered: host port %s", ds.Name, strings.Join(parts, ", "))
+	if existing := meta.FindStatusCondition(pool.Status.Conditions, ConditionHostPortConflict); existing != nil && existing.Status == metav1.ConditionTrue {
+		message = existing.Message + "; " + message
+	}
+	meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+		Type:               ConditionHostPortConflict,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonPortInUse,
+		Message:            message,
+		ObservedGeneration: pool.Generation,
+	})
+	return true, nil
+}
+
+// sharedNode returns a node both DaemonSets may schedule to according to their required node affinity.
+func sharedNode(nodes []corev1.Node, a, b *appsv1.DaemonSet) (string, bool) {
+	for i := range nodes {
+		node := &nodes[i]
+		if schedulable(a, node.Labels) && schedulable(b, node.Labels) {
+			return node.Name, true
+		}
+	}
+	return "", false
+}
+
+func schedulable(ds *appsv1.DaemonSet, nodeLabels map[string]string) bool {
+	spec := ds.Spec.Template.Spec
+	if len(spec.NodeSelector) > 0 && !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(nodeLabels)) {
+		return false
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for _, term := range terms {
+		if termMatches(term, nodeLabels) {
+			return true
+		}
+	}
+	return len(terms) == 0
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// termMatches evaluates the label expressions of a node selector term; field expressions match any node, so an
+// unsupported term errs on the side of reporting a conflict.
+func termMatches(term corev1.NodeSelectorTerm, nodeLabels map[string]string) bool {
+	selector := labels.NewSelector()
+	for _, expr := range term.MatchExpressions {
+		op, ok := nodeSelectorOperators[expr.Operator]
+		if !ok {
+			continue
+		}
+		req, err := labels.NewRequirement(expr.Key, op, expr.Values)
+		if err != nil {
+			continue
+		}
+		selector = selector.Add(*req)
+	}
+	return selector.Matches(labels.Set(nodeLabels))
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostports
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func poolDaemonSet(name, pool string, hostNetwork bool, ports ...int32) *appsv1.DaemonSet {
+	container := corev1.Container{Name: "main"}
+	for _, port := range ports {
+		container.Ports = append(container.Ports, corev1.ContainerPort{ContainerPort: port})
+	}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{"pool": pool}},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			HostNetwork: hostNetwork,
+			Containers:  []corev1.Container{container},
+			Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      "gpu.deckhouse.io/" + pool,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{pool},
+					}}}},
+				},
+			}},
+		}}},
+	}
+}
+
+func poolNode(name string, pools ...string) *corev1.Node {
+	labels := map[string]string{}
+	for _, pool := range pools {
+		labels["gpu.deckhouse.io/"+pool] = pool
+	}
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add apps scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestPorts(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Ports: []corev1.ContainerPort{
+		{ContainerPort: 9400},
+		{ContainerPort: 9401, HostPort: 19401},
+		{ContainerPort: 53, Protocol: corev1.ProtocolUDP},
+	}}}}
+	if got := Ports(spec); len(got) != 1 || got[0] != (Port{Port: 19401, Protocol: corev1.ProtocolTCP}) {
+		t.Fatalf("expected only the explicit host port without hostNetwork, got %v", got)
+	}
+	spec.HostNetwork = true
+	got := Ports(spec)
+	want := []Port{{53, corev1.ProtocolUDP}, {9400, corev1.ProtocolTCP}, {9401, corev1.ProtocolTCP}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v with hostNetwork, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v with hostNetwork, got %v", want, got)
+		}
+	}
+}
+
+func TestGuardRejectsPortUsedOnSharedNode(t *testing.T) {
+	ds := poolDaemonSet("exporter-alpha", "alpha", true, 9400)
+	cl := newClient(t,
+		poolNode("shared", "alpha", "beta"),
+		poolNode("beta-only", "beta"),
+		poolDaemonSet("exporter-beta", "beta", true, 9400),
+		// Same pool and other ports never conflict.
+		poolDaemonSet("validator-alpha", "alpha", true, 9400),
+		poolDaemonSet("exporter-gamma", "gamma", true, 9500),
+	)
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Generation: 3}}
+
+	blocked, err := Guard(context.Background(), cl, pool, ds)
+	if err != nil {
+		t.Fatalf("Guard returned error: %v", err)
+	}
+	if !blocked {
+		t.Fatal("expected the DaemonSet to be blocked")
+	}
+	cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionHostPortConflict)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != ReasonPortInUse || cond.ObservedGeneration != 3 {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+	if !strings.Contains(cond.Message, "9400/TCP used by exporter-beta on node shared") || strings.Contains(cond.Message, "gamma") {
+		t.Fatalf("unexpected condition message %q", cond.Message)
+	}
+}
+
+func TestGuardAllowsDisjointNodes(t *testing.T) {
+	ds := poolDaemonSet("exporter-alpha", "alpha", true, 9400)
+	cl := newClient(t,
+		poolNode("alpha-only", "alpha"),
+		poolNode("beta-only", "beta"),
+		poolDaemonSet("exporter-beta", "beta", true, 9400),
+	)
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}
+
+	blocked, err := Guard(context.Background(), cl, pool, ds)
+	if err != nil || blocked {
+		t.Fatalf("expected pools on disjoint nodes to share a port, got blocked=%t err=%v", blocked, err)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionHostPortConflict) != nil {
+		t.Fatalf("expected no condition, got %+v", pool.Status.Conditions)
+	}
+}
+
+func TestGuardIgnoresPodNetworkPorts(t *testing.T) {
+	ds := poolDaemonSet("exporter-alpha", "alpha", false, 9400)
+	cl := newClient(t, poolNode("shared", "alpha", "beta"), poolDaemonSet("exporter-beta", "beta", true, 9400))
+
+	blocked, err := Guard(context.Background(), cl, &v1alpha1.GPUPool{}, ds)
+	if err != nil || blocked {
+		t.Fatalf("expected container ports without hostNetwork to be ignored, got blocked=%t err=%v", blocked, err)
+	}
+}
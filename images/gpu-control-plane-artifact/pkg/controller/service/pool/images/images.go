@@ -0,0 +1,125 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package images pins the images rendered into the pool workloads to the digests their tags resolve to.
+//
+// A pinned reference keeps its tag next to the digest, so a tag that moves, or a configured tag that changes,
+// changes the pod template and rolls the DaemonSets like any other image update.
+package images
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/imagedigest"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+)
+
+const (
+	// ConditionImageDigestUnresolved is True while an image of the pool workloads could not be resolved to a digest.
+	ConditionImageDigestUnresolved = "ImageDigestUnresolved"
+	// ReasonTagFallback means the unresolved images are rendered by tag.
+	ReasonTagFallback = "TagFallback"
+	// ReasonDigestRequired means rendering stopped because requireDigests is set.
+	ReasonDigestRequired = "DigestRequired"
+)
+
+// Resolver is the imageDigests setting of the module config store turned into a digest resolver. The setting is
+// read on every Current call, so enabling it, requireDigests and the pull secret take effect without a restart,
+// and one digest cache serves both pool controllers.
+type Resolver struct {
+	store    *moduleconfig.ModuleConfigStore
+	resolver *imagedigest.Resolver
+}
+
+// NewResolver builds the resolver of the imageDigests setting in store. The pull secret is read through reader,
+// so it does not have to be cached.
+func NewResolver(reader client.Reader, store *moduleconfig.ModuleConfigStore) *Resolver {
+	return &Resolver{store: store, resolver: imagedigest.NewResolver(reader, imagedigest.Options{})}
+}
+
+// Current returns the digest resolver configured by the current setting; nil while the setting is off. It is a
+// config.ImageResolverSource.
+func (r *Resolver) Current() *imagedigest.Resolver {
+	if r == nil || r.store == nil {
+		return nil
+	}
+	settings := r.store.Current().Settings.ImageDigests
+	if !settings.Enabled {
+		return nil
+	}
+	r.resolver.Configure(options(settings))
+	return r.resolver
+}
+
+func options(settings moduleconfig.ImageDigestSettings) imagedigest.Options {
+	opts := imagedigest.Options{CacheTTL: settings.CacheTTL, RequireDigests: settings.RequireDigests}
+	if settings.PullSecretName != "" {
+		namespace := settings.PullSecretNamespace
+		if namespace == "" {
+			namespace = config.DefaultsFromEnv().Namespace
+		}
+		opts.PullSecret = types.NamespacedName{Namespace: namespace, Name: settings.PullSecretName}
+	}
+	return opts
+}
+
+// Pin replaces the configured images in cfg with digest references through the current cfg.ImageResolver and sets
+// ConditionImageDigestUnresolved on the pool when some of them stay unresolved. It reports whether rendering must
+// stop, which is only the case when the resolver requires digests. Without a resolver cfg is left as it is.
+func Pin(ctx context.Context, cfg *config.WorkloadConfig, pool *v1alpha1.GPUPool) (blocked bool) {
+	resolver := cfg.CurrentImageResolver()
+	if resolver == nil {
+		return false
+	}
+
+	var failures []string
+	failed := map[string]bool{}
+	for _, image := range []*string{&cfg.DevicePluginImage, &cfg.MIGManagerImage, &cfg.ValidatorImage} {
+		pinned, err := resolver.Resolve(ctx, *image)
+		if err != nil {
+			// The validator falls back to the device-plugin image; report each image once.
+			if !failed[*image] {
+				failed[*image] = true
+				failures = append(failures, err.Error())
+			}
+			continue
+		}
+		*image = pinned
+	}
+	if len(failures) == 0 {
+		return false
+	}
+
+	reason, message := ReasonTagFallback, "images are rendered by tag"
+	if resolver.RequireDigests() {
+		reason, message = ReasonDigestRequired, "workloads are not rendered until every image resolves to a digest"
+	}
+	meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+		Type:               ConditionImageDigestUnresolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            fmt.Sprintf("%s: %s", message, strings.Join(failures, "; ")),
+		ObservedGeneration: pool.Generation,
+	})
+	return resolver.RequireDigests()
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/imagedigest"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+)
+
+// newRegistry serves the digests of the listed repository:tag pairs without authentication.
+func newRegistry(t *testing.T, digests map[string]string) (string, *http.Client) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo, tag, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		digest, ok := digests[repo+":"+tag]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "https://"), server.Client()
+}
+
+func TestResolverFollowsSettings(t *testing.T) {
+	store := moduleconfig.NewModuleConfigStore(moduleconfig.State{})
+	resolver := NewResolver(nil, store)
+	if resolver.Current() != nil {
+		t.Fatalf("a disabled setting must not yield a resolver")
+	}
+
+	store.Update(moduleconfig.State{Settings: moduleconfig.Settings{ImageDigests: moduleconfig.ImageDigestSettings{
+		Enabled: true, RequireDigests: true, CacheTTL: moduleconfig.DefaultImageDigestCacheTTL,
+	}}})
+	current := resolver.Current()
+	if current == nil || !current.RequireDigests() || current.CacheTTL() != moduleconfig.DefaultImageDigestCacheTTL {
+		t.Fatalf("unexpected resolver: %+v", current)
+	}
+
+	store.Update(moduleconfig.State{Settings: moduleconfig.Settings{ImageDigests: moduleconfig.ImageDigestSettings{
+		Enabled: true, CacheTTL: moduleconfig.DefaultImageDigestCacheTTL,
+	}}})
+	if next := resolver.Current(); next != current || next.RequireDigests() {
+		t.Fatalf("expected the same resolver without requireDigests, got %+v", next)
+	}
+
+	var unset *Resolver
+	if unset.Current() != nil {
+		t.Fatalf("a nil resolver must not yield a resolver")
+	}
+}
+
+func TestPinWithoutResolver(t *testing.T) {
+	cfg := config.WorkloadConfig{DevicePluginImage: "dp:tag"}
+	pool := &v1alpha1.GPUPool{}
+	if Pin(context.Background(), &cfg, pool) || cfg.DevicePluginImage != "dp:tag" || len(pool.Status.Conditions) != 0 {
+		t.Fatalf("expected the config untouched without a resolver, got %+v", cfg)
+	}
+}
+
+func TestPinResolvesEveryImage(t *testing.T) {
+	host, httpClient := newRegistry(t, map[string]string{"gpu/dp:v1": "sha256:aaa", "gpu/mig:v1": "sha256:bbb"})
+	resolver := imagedigest.NewResolver(fake.NewClientBuilder().Build(), imagedigest.Options{RequireDigests: true}).WithHTTPClient(httpClient)
+	cfg := config.WorkloadConfig{
+		DevicePluginImage: host + "/gpu/dp:v1",
+		MIGManagerImage:   host + "/gpu/mig:v1",
+		ValidatorImage:    host + "/gpu/dp:v1",
+		ImageResolver:     func() *imagedigest.Resolver { return resolver },
+	}
+	pool := &v1alpha1.GPUPool{}
+
+	if Pin(context.Background(), &cfg, pool) {
+		t.Fatalf("resolved images must not block rendering")
+	}
+	if cfg.DevicePluginImage != host+"/gpu/dp:v1@sha256:aaa" || cfg.ValidatorImage != cfg.DevicePluginImage || cfg.MIGManagerImage != host+"/gpu/mig:v1@sha256:bbb" {
+		t.Fatalf("unexpected pinned images: %+v", cfg)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionImageDigestUnresolved) != nil {
+		t.Fatalf("unexpected condition: %+v", pool.Status.Conditions)
+	}
+}
+
+func TestPinFallbackAndStrict(t *testing.T) {
+	host, httpClient := newRegistry(t, map[string]string{"gpu/dp:v1": "sha256:aaa"})
+	for _, strict := range []bool{false, true} {
+		resolver := imagedigest.NewResolver(fake.NewClientBuilder().Build(), imagedigest.Options{RequireDigests: strict}).WithHTTPClient(httpClient)
+		cfg := config.WorkloadConfig{
+			DevicePluginImage: host + "/gpu/dp:v1",
+			MIGManagerImage:   host + "/gpu/missing:v1",
+			ValidatorImage:    host + "/gpu/missing:v1",
+			ImageResolver:     func() *imagedigest.Resolver { return resolver },
+		}
+		pool := &v1alpha1.GPUPool{}
+
+		if blocked := Pin(context.Background(), &cfg, pool); blocked != strict {
+			t.Fatalf("strict=%t: blocked = %t", strict, blocked)
+		}
+		if cfg.ValidatorImage != host+"/gpu/missing:v1" || cfg.MIGManagerImage != cfg.ValidatorImage || cfg.DevicePluginImage != host+"/gpu/dp:v1@sha256:aaa" {
+			t.Fatalf("strict=%t: expected the tag for the unresolved image only, got %+v", strict, cfg)
+		}
+		cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionImageDigestUnresolved)
+		wantReason := ReasonTagFallback
+		if strict {
+			wantReason = ReasonDigestRequired
+		}
+		if cond == nil || cond.Reason != wantReason || strings.Count(cond.Message, "gpu/missing:v1:") != 1 {
+			t.Fatalf("strict=%t: unexpected condition %+v", strict, cond)
+		}
+	}
+}
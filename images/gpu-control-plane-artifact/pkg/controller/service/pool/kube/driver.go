@@ -0,0 +1,35 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerDriverRoot is where a preinstalled host driver root is mounted inside pool workloads.
+const ContainerDriverRoot = "/driver-root"
+
+// HostDriverRootVolume exposes a preinstalled driver installation rooted at root on the host.
+func HostDriverRootVolume(name, root string) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: root,
+				Type: HostPathType(corev1.HostPathDirectory),
+			},
+		},
+	}
+}
@@ -20,3 +20,11 @@ import corev1 "k8s.io/api/core/v1"
 func HostPathType(t corev1.HostPathType) *corev1.HostPathType {
 	return &t
 }
+
+// HostNetworkDNSPolicy keeps cluster DNS for pods in the host network namespace; other pods use the default.
+func HostNetworkDNSPolicy(hostNetwork bool) corev1.DNSPolicy {
+	if hostNetwork {
+		return corev1.DNSClusterFirstWithHostNet
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import corev1 "k8s.io/api/core/v1"
+
+const (
+	// InfinibandDir holds the RDMA character devices used by GPUDirect RDMA.
+	InfinibandDir = "/dev/infiniband"
+	// HostModulesDir holds the host kernel modules, including nvidia-peermem.
+	HostModulesDir = "/lib/modules"
+
+	infinibandVolume  = "infiniband"
+	hostModulesVolume = "host-modules"
+)
+
+// RDMAVolumes exposes the host RDMA devices and kernel modules needed by GPUDirect RDMA.
+func RDMAVolumes() []corev1.Volume {
+	return []corev1.Volume{
+		{
+			Name: infinibandVolume,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: InfinibandDir,
+					Type: HostPathType(corev1.HostPathDirectory),
+				},
+			},
+		},
+		{
+			Name: hostModulesVolume,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: HostModulesDir,
+					Type: HostPathType(corev1.HostPathDirectory),
+				},
+			},
+		},
+	}
+}
+
+// RDMAVolumeMounts mounts RDMAVolumes at their host paths.
+func RDMAVolumeMounts() []corev1.VolumeMount {
+	return []corev1.VolumeMount{
+		{Name: infinibandVolume, MountPath: InfinibandDir},
+		{Name: hostModulesVolume, MountPath: HostModulesDir, ReadOnly: true},
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// Privileges the rendered components genuinely need. Everything else runs with the restricted profile: no
+// privilege escalation, every capability dropped, the RuntimeDefault seccomp profile and a read-only root
+// filesystem.
+const (
+	// CapabilityIPCLock lets the device plugin pin GPU memory for the NIC when GPUDirect RDMA is enabled.
+	CapabilityIPCLock corev1.Capability = "IPC_LOCK"
+	// MIGManagerPrivileged keeps the MIG manager privileged: repartitioning creates /dev/nvidia-caps nodes the
+	// container device cgroup would not admit, and it stops host GPU clients through the host root and PID
+	// namespace.
+	MIGManagerPrivileged = true
+)
+
+// PodSecurityContext runs the pod as root, which the NVIDIA components expect, under the RuntimeDefault seccomp
+// profile. The legacy profile leaves seccomp unset, as releases before the restricted profiles did.
+func PodSecurityContext(legacy bool) *corev1.PodSecurityContext {
+	sc := &corev1.PodSecurityContext{
+		RunAsUser:    ptr.To[int64](0),
+		RunAsNonRoot: ptr.To(false),
+	}
+	if !legacy {
+		sc.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	return sc
+}
+
+// RestrictedSecurityContext is the unprivileged root context with every capability dropped except add.
+func RestrictedSecurityContext(add ...corev1.Capability) *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		Privileged:               ptr.To(false),
+		RunAsUser:                ptr.To[int64](0),
+		RunAsNonRoot:             ptr.To(false),
+		AllowPrivilegeEscalation: ptr.To(false),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: add},
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// LegacyPrivilegedSecurityContext is the privileged context rendered before the restricted profiles; the
+// legacyPrivileged module setting brings it back for clusters where the restricted profile breaks.
+func LegacyPrivilegedSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		Privileged:               ptr.To(true),
+		RunAsUser:                ptr.To[int64](0),
+		RunAsNonRoot:             ptr.To(false),
+		AllowPrivilegeEscalation: ptr.To(true),
+		ReadOnlyRootFilesystem:   ptr.To(false),
+	}
+}
+
+// ValidationsMountPropagation is Bidirectional only for the legacy privileged containers: the kubelet refuses
+// Bidirectional propagation for unprivileged ones, and the validator only writes files there.
+func ValidationsMountPropagation(legacy bool) *corev1.MountPropagationMode {
+	if legacy {
+		return ptr.To(corev1.MountPropagationBidirectional)
+	}
+	return ptr.To(corev1.MountPropagationHostToContainer)
+}
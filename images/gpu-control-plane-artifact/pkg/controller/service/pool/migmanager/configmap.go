@@ -16,6 +16,7 @@ package migmanager
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,7 +36,7 @@ func migManagerConfigMap(d deps.Deps, pool *v1alpha1.GPUPool) *corev1.ConfigMap
 				"devices": []map[string]any{{
 					"pciBusId":   "all",
 					"migEnabled": true,
-					"migDevices": []map[string]any{{"profile": pool.Spec.Resource.MIGProfile}},
+					"migDevices": migDevices(pool),
 				}},
 			},
 		},
@@ -55,6 +56,22 @@ func migManagerConfigMap(d deps.Deps, pool *v1alpha1.GPUPool) *corev1.ConfigMap
 	}
 }
 
+// migDevices lists the instances the MIG manager creates on every device: each profile of a mixed layout with
+// its count, or the single migProfile.
+func migDevices(pool *v1alpha1.GPUPool) []map[string]any {
+	if len(pool.Spec.Resource.MIGLayout) == 0 {
+		return []map[string]any{{"profile": pool.Spec.Resource.MIGProfile}}
+	}
+	out := make([]map[string]any, 0, len(pool.Spec.Resource.MIGLayout))
+	for _, entry := range pool.Spec.Resource.MIGLayout {
+		out = append(out, map[string]any{
+			"profile": strings.ToLower(strings.TrimSpace(entry.Profile)),
+			"count":   int(entry.Count),
+		})
+	}
+	return out
+}
+
 func migManagerScriptsConfigMap(d deps.Deps, pool *v1alpha1.GPUPool) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -24,9 +24,13 @@ import (
 	"k8s.io/utils/ptr"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/critical"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/overrides"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/tolerations"
 )
 
@@ -36,7 +40,7 @@ func migManagerDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPoo
 	clientsName := fmt.Sprintf("nvidia-mig-manager-%s-gpu-clients", pool.Name)
 	scriptsName := fmt.Sprintf("nvidia-mig-manager-%s-scripts", pool.Name)
 
-	return &appsv1.DaemonSet{
+	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("nvidia-mig-manager-%s", pool.Name),
 			Namespace: d.Config.Namespace,
@@ -61,10 +65,12 @@ func migManagerDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPoo
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: "nvidia-mig-manager",
+					ServiceAccountName: rbac.ServiceAccountName(d, rbac.MIGManager, pool),
+					PriorityClassName:  critical.PriorityClassName(ctx, d),
 					HostPID:            true,
 					HostNetwork:        true,
 					DNSPolicy:          corev1.DNSClusterFirstWithHostNet,
+					SecurityContext:    migManagerPodSecurityContext(d.Config.LegacyPrivileged),
 					Tolerations: tolerations.Merge([]corev1.Toleration{
 						{Key: "node.kubernetes.io/unschedulable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
 						{Key: "mig-reconfigure", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
@@ -93,7 +99,7 @@ func migManagerDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPoo
 							Image:           d.Config.MIGManagerImage,
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Args:            []string{"-config-file=/mig-parted-config/config.yaml"},
-							SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+							SecurityContext: migManagerSecurityContext(d.Config.LegacyPrivileged),
 							VolumeMounts: []corev1.VolumeMount{
 								{Name: "host-root", MountPath: "/host"},
 								{Name: "host-sys", MountPath: "/sys"},
@@ -103,7 +109,7 @@ func migManagerDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPoo
 								{Name: "mig-scripts", MountPath: "/usr/bin/reconfigure-mig.sh", SubPath: "reconfigure-mig.sh"},
 								{Name: "mig-scripts", MountPath: "/usr/bin/prestop.sh", SubPath: "prestop.sh"},
 							},
-							Env: []corev1.EnvVar{
+							Env: append([]corev1.EnvVar{
 								{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
 								{Name: "CONFIG_FILE", Value: "/mig-parted-config/config.yaml"},
 								{Name: "GPU_CLIENTS_FILE", Value: "/gpu-clients/clients.yaml"},
@@ -114,7 +120,7 @@ func migManagerDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPoo
 								{Name: "DEFAULT_GPU_CLIENTS_NAMESPACE", Value: d.Config.Namespace},
 								{Name: "WITH_SHUTDOWN_HOST_GPU_CLIENTS", Value: "true"},
 								{Name: "WITH_REBOOT", Value: "false"},
-							},
+							}, runtimeEnv(d.Runtime)...),
 							Lifecycle: &corev1.Lifecycle{
 								PreStop: &corev1.LifecycleHandler{
 									Exec: &corev1.ExecAction{
@@ -136,4 +142,45 @@ func migManagerDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPoo
 			},
 		},
 	}
+	// Pool overrides go last so the rendered configuration above is never replaced.
+	overrides.Apply(&ds.Spec.Template.Spec, "mig-manager", pool.Spec.MIGManager, pool.Namespace != "")
+	return ds
+}
+
+// runtimeEnv points the MIG manager at the container runtime of the pool nodes; the paths are on the host, below
+// HOST_ROOT_MOUNT.
+func runtimeEnv(profile containerruntime.Profile) []corev1.EnvVar {
+	profile = profile.OrDefault()
+	env := []corev1.EnvVar{
+		{Name: "CONTAINER_RUNTIME", Value: string(profile.Runtime)},
+		{Name: "RUNTIME_SOCKET", Value: profile.Socket},
+		{Name: "RUNTIME_CONFIG", Value: profile.ConfigPath},
+	}
+	if profile.HooksDir != "" {
+		env = append(env, corev1.EnvVar{Name: "RUNTIME_HOOKS_DIR", Value: profile.HooksDir})
+	}
+	return env
+}
+
+// migManagerPodSecurityContext leaves the pod context unset in legacy mode, as earlier releases rendered it.
+func migManagerPodSecurityContext(legacy bool) *corev1.PodSecurityContext {
+	if legacy {
+		return nil
+	}
+	return kube.PodSecurityContext(false)
+}
+
+// migManagerSecurityContext is the one elevated context of a pool: see kube.MIGManagerPrivileged. It still
+// spells out every field, so a scanner reads the intent instead of defaults.
+func migManagerSecurityContext(legacy bool) *corev1.SecurityContext {
+	if legacy {
+		return &corev1.SecurityContext{Privileged: ptr.To(true)}
+	}
+	return &corev1.SecurityContext{
+		Privileged:               ptr.To(kube.MIGManagerPrivileged),
+		RunAsUser:                ptr.To[int64](0),
+		RunAsNonRoot:             ptr.To(false),
+		AllowPrivilegeEscalation: ptr.To(true),
+		ReadOnlyRootFilesystem:   ptr.To(false),
+	}
 }
@@ -19,30 +19,49 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/critical"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/hostports"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migration"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/ops"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 )
 
-// Reconcile ensures the MIG manager ConfigMaps and DaemonSet are up to date.
+// Reconcile ensures the MIG manager RBAC, ConfigMaps, DaemonSet and PodDisruptionBudget are up to date.
 func Reconcile(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) error {
+	if err := rbac.Reconcile(ctx, d, rbac.MIGManager, pool); err != nil {
+		return err
+	}
+
 	configCM := migManagerConfigMap(d, pool)
-	if err := ops.CreateOrUpdate(ctx, d.Client, configCM, pool); err != nil {
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, configCM, pool); err != nil {
 		return fmt.Errorf("reconcile MIG manager config: %w", err)
 	}
 
 	scriptsCM := migManagerScriptsConfigMap(d, pool)
-	if err := ops.CreateOrUpdate(ctx, d.Client, scriptsCM, pool); err != nil {
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, scriptsCM, pool); err != nil {
 		return fmt.Errorf("reconcile MIG manager scripts: %w", err)
 	}
 
 	clientsCM := migManagerClientsConfigMap(d, pool)
-	if err := ops.CreateOrUpdate(ctx, d.Client, clientsCM, pool); err != nil {
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, clientsCM, pool); err != nil {
 		return fmt.Errorf("reconcile MIG manager clients: %w", err)
 	}
 
 	ds := migManagerDaemonSet(ctx, d, pool)
-	if err := ops.CreateOrUpdate(ctx, d.Client, ds, pool); err != nil {
+	poolcommon.StampConfigHash(&ds.Spec.Template, configCM, scriptsCM, clientsCM)
+	if blocked, err := migration.Guard(ctx, d, pool, migration.MIGManager, ds); err != nil || blocked {
+		return err
+	}
+	if blocked, err := hostports.Guard(ctx, d.Client, pool, ds); err != nil || blocked {
+		return err
+	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, ds, pool); err != nil {
 		return fmt.Errorf("reconcile MIG manager DaemonSet: %w", err)
 	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, critical.PodDisruptionBudget(ds), pool); err != nil {
+		return fmt.Errorf("reconcile MIG manager PodDisruptionBudget: %w", err)
+	}
 	return nil
 }
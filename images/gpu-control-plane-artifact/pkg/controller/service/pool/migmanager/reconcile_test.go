@@ -17,25 +17,32 @@ package migmanager
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/overrides"
 )
 
 func TestReconcileErrorPaths(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	pool := &v1alpha1.GPUPool{
@@ -48,10 +55,13 @@ func TestReconcileErrorPaths(t *testing.T) {
 		failOn   int
 		contains string
 	}{
-		{name: "config", failOn: 1, contains: "reconcile MIG manager config"},
-		{name: "scripts", failOn: 2, contains: "reconcile MIG manager scripts"},
-		{name: "clients", failOn: 3, contains: "reconcile MIG manager clients"},
-		{name: "daemonset", failOn: 4, contains: "reconcile MIG manager DaemonSet"},
+		{name: "serviceaccount", failOn: 1, contains: "reconcile nvidia-mig-manager ServiceAccount"},
+		{name: "clusterrole", failOn: 2, contains: "reconcile nvidia-mig-manager ClusterRole"},
+		{name: "clusterrolebinding", failOn: 3, contains: "reconcile nvidia-mig-manager ClusterRoleBinding"},
+		{name: "config", failOn: 4, contains: "reconcile MIG manager config"},
+		{name: "scripts", failOn: 5, contains: "reconcile MIG manager scripts"},
+		{name: "clients", failOn: 6, contains: "reconcile MIG manager clients"},
+		{name: "daemonset", failOn: 7, contains: "reconcile MIG manager DaemonSet"},
 	}
 
 	for _, tc := range tests {
@@ -83,3 +93,191 @@ func (c *createNthErrorClient) Create(ctx context.Context, obj client.Object, op
 	}
 	return c.Client.Create(ctx, obj, opts...)
 }
+
+func TestReconcileStampsConfigHash(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{MIGProfile: "1g.10gb"}},
+	}
+	d := deps.Deps{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.WorkloadConfig{Namespace: "ns", MIGManagerImage: "mig:tag"},
+	}
+	getHash := func() string {
+		t.Helper()
+		ds := &appsv1.DaemonSet{}
+		if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-mig-manager-alpha"}, ds); err != nil {
+			t.Fatalf("get DaemonSet: %v", err)
+		}
+		return ds.Spec.Template.Annotations[poolcommon.ConfigHashAnnotation]
+	}
+
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	initial := getHash()
+	if initial == "" {
+		t.Fatalf("expected %s on the pod template", poolcommon.ConfigHashAnnotation)
+	}
+
+	pool.Spec.Resource.MIGProfile = "2g.20gb"
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile after profile change: %v", err)
+	}
+	if got := getHash(); got == initial {
+		t.Fatalf("expected the hash to change with the MIG config")
+	}
+}
+
+func TestReconcileAppliesMIGManagerOverrides(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"},
+		Spec: v1alpha1.GPUPoolSpec{
+			Resource: v1alpha1.GPUPoolResourceSpec{MIGProfile: "1g.10gb"},
+			MIGManager: &v1alpha1.GPUPoolComponentOverrides{
+				ExtraEnv:          []corev1.EnvVar{{Name: "MIG_PARTED_DEBUG", Value: "true"}},
+				ExtraVolumes:      []corev1.Volume{{Name: "hooks", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+				ExtraVolumeMounts: []corev1.VolumeMount{{Name: "hooks", MountPath: "/hooks"}},
+			},
+		},
+	}
+	d := deps.Deps{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.WorkloadConfig{Namespace: "ns", MIGManagerImage: "mig:tag"},
+	}
+	getSpec := func() corev1.PodSpec {
+		t.Helper()
+		ds := &appsv1.DaemonSet{}
+		if err := d.Client.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "nvidia-mig-manager-alpha"}, ds); err != nil {
+			t.Fatalf("get DaemonSet: %v", err)
+		}
+		return ds.Spec.Template.Spec
+	}
+	countExtras := func(spec corev1.PodSpec) int {
+		c := spec.Containers[0]
+		n := 0
+		for _, found := range []bool{
+			slices.ContainsFunc(c.Env, func(e corev1.EnvVar) bool { return e.Name == "MIG_PARTED_DEBUG" }),
+			slices.ContainsFunc(c.VolumeMounts, func(m corev1.VolumeMount) bool { return m.MountPath == "/hooks" }),
+			slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == "hooks" }),
+		} {
+			if found {
+				n++
+			}
+		}
+		return n
+	}
+
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	spec := getSpec()
+	if countExtras(spec) != 3 {
+		t.Fatalf("expected the overrides to be applied, got %+v", spec.Containers[0])
+	}
+	container := spec.Containers[0]
+	for _, env := range container.Env[:len(container.Env)-1] {
+		if !slices.Contains(overrides.MIGManager.Env, env.Name) {
+			t.Errorf("env %s is rendered but not reserved", env.Name)
+		}
+	}
+	for _, volume := range spec.Volumes[:len(spec.Volumes)-1] {
+		if !slices.Contains(overrides.MIGManager.Volumes, volume.Name) {
+			t.Errorf("volume %s is rendered but not reserved", volume.Name)
+		}
+	}
+	for _, mount := range container.VolumeMounts[:len(container.VolumeMounts)-1] {
+		if !slices.Contains(overrides.MIGManager.MountPaths, mount.MountPath) {
+			t.Errorf("mount path %s is rendered but not reserved", mount.MountPath)
+		}
+	}
+
+	pool.Spec.MIGManager = nil
+	if err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("reconcile after removing overrides: %v", err)
+	}
+	if spec := getSpec(); countExtras(spec) != 0 {
+		t.Fatalf("expected the overrides to be removed, got %+v", spec.Containers[0])
+	}
+}
+
+func TestMIGManagerSecurityContext(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"}}
+	d := deps.Deps{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.WorkloadConfig{Namespace: "ns", MIGManagerImage: "mig:tag"},
+	}
+
+	spec := migManagerDaemonSet(context.Background(), d, pool).Spec.Template.Spec
+	if sc := spec.SecurityContext; sc == nil || sc.SeccompProfile == nil || sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Fatalf("expected the RuntimeDefault seccomp profile on the pod, got %+v", sc)
+	}
+	// Repartitioning stays privileged, with the rest of the context spelled out.
+	sc := spec.Containers[0].SecurityContext
+	if !*sc.Privileged || sc.RunAsUser == nil || *sc.RunAsUser != 0 || sc.AllowPrivilegeEscalation == nil || sc.ReadOnlyRootFilesystem == nil {
+		t.Fatalf("expected an explicit privileged context, got %+v", sc)
+	}
+
+	d.Config.LegacyPrivileged = true
+	spec = migManagerDaemonSet(context.Background(), d, pool).Spec.Template.Spec
+	if spec.SecurityContext != nil {
+		t.Fatalf("expected no pod security context in legacy mode, got %+v", spec.SecurityContext)
+	}
+	if sc := spec.Containers[0].SecurityContext; !*sc.Privileged || sc.RunAsUser != nil {
+		t.Fatalf("expected the legacy privileged context, got %+v", sc)
+	}
+}
+
+func TestMIGManagerRuntimeEnv(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"}}
+	d := deps.Deps{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.WorkloadConfig{Namespace: "ns", MIGManagerImage: "mig:tag"},
+	}
+	envOf := func(d deps.Deps) map[string]string {
+		env := map[string]string{}
+		for _, item := range migManagerDaemonSet(context.Background(), d, pool).Spec.Template.Spec.Containers[0].Env {
+			env[item.Name] = item.Value
+		}
+		return env
+	}
+
+	// Without a resolved profile the containerd paths rendered before runtimes were detected are kept.
+	env := envOf(d)
+	if env["CONTAINER_RUNTIME"] != "containerd" || env["RUNTIME_SOCKET"] != "/run/containerd/containerd.sock" || env["RUNTIME_CONFIG"] != "/etc/containerd/config.toml" {
+		t.Fatalf("unexpected containerd env %v", env)
+	}
+	if _, ok := env["RUNTIME_HOOKS_DIR"]; ok {
+		t.Fatalf("expected no hooks dir for containerd, got %v", env)
+	}
+
+	d.Runtime = containerruntime.ProfileFor(containerruntime.CRIO)
+	env = envOf(d)
+	if env["CONTAINER_RUNTIME"] != "cri-o" || env["RUNTIME_SOCKET"] != "/var/run/crio/crio.sock" ||
+		env["RUNTIME_CONFIG"] != "/etc/crio/crio.conf" || env["RUNTIME_HOOKS_DIR"] != "/usr/share/containers/oci/hooks.d" {
+		t.Fatalf("unexpected cri-o env %v", env)
+	}
+	if env["HOST_ROOT_MOUNT"] != "/host" {
+		t.Fatalf("expected the static env to be kept, got %v", env)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migration keeps pools from rendering components next to the DaemonSets of an upstream NVIDIA gpu-operator
+// installation, and lets pools take those DaemonSets over instead of creating their own.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+)
+
+const (
+	// ConditionMigrationBlocked is True while a pool component is not rendered because a gpu-operator DaemonSet of
+	// the same component still runs in the workloads namespace.
+	ConditionMigrationBlocked = "MigrationBlocked"
+	// ReasonUpstreamComponentRunning is set on ConditionMigrationBlocked.
+	ReasonUpstreamComponentRunning = "UpstreamComponentRunning"
+	// AdoptAnnotation on a gpu-operator DaemonSet names the pool that takes it over when adoptExisting is enabled.
+	AdoptAnnotation = "gpu.deckhouse.io/adopt"
+	// RecheckInterval is how often a blocked pool looks at the gpu-operator DaemonSets again; they are not watched.
+	RecheckInterval = time.Minute
+)
+
+// Component is a pool component that gpu-operator deploys as well.
+type Component struct {
+	// Upstream lists the names gpu-operator gives the DaemonSets of the component.
+	Upstream []string
+}
+
+var (
+	// DevicePlugin registers with the kubelet through the same socket directory as the gpu-operator device plugin.
+	DevicePlugin = Component{Upstream: []string{"nvidia-device-plugin-daemonset"}}
+	// MIGManager reconfigures the same GPUs as the gpu-operator MIG manager.
+	MIGManager = Component{Upstream: []string{"nvidia-mig-manager"}}
+)
+
+// Guard checks the rendered DaemonSet of a component against the gpu-operator DaemonSets in its namespace and
+// reports whether it must not be rendered, setting ConditionMigrationBlocked on the pool. Scaled-down upstream
+// DaemonSets and the ones already taken over by a pool are ignored. With adoptExisting, an upstream DaemonSet
+// annotated for the pool is taken over: ds is rewritten to its name and selector, and the DaemonSet the pool
+// rendered before is removed. Clearing the condition is left to the caller, which guards every component of the
+// pool.
+func Guard(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool, component Component, ds *appsv1.DaemonSet) (bool, error) {
+	if !d.Config.MigrateFromGPUOperator {
+		return false, nil
+	}
+
+	var adopted *appsv1.DaemonSet
+	var conflicts []string
+	for _, name := range component.Upstream {
+		if name == ds.Name {
+			continue
+		}
+		upstream, err := commonobject.FetchObject(ctx, client.ObjectKey{Namespace: ds.Namespace, Name: name}, d.Client, &appsv1.DaemonSet{})
+		if err != nil {
+			return false, fmt.Errorf("get gpu-operator DaemonSet %s: %w", name, err)
+		}
+		if upstream == nil {
+			continue
+		}
+		if owner := upstream.Labels["pool"]; owner != "" {
+			if owner == pool.Name && adopted == nil {
+				adopted = upstream
+			}
+			continue
+		}
+		if d.Config.AdoptExisting && upstream.Annotations[AdoptAnnotation] == pool.Name && adopted == nil {
+			adopted = upstream
+			continue
+		}
+		if scaledDown(upstream) {
+			continue
+		}
+		conflicts = append(conflicts, name)
+	}
+
+	if len(conflicts) > 0 {
+		setBlocked(pool, ds, conflicts)
+		return true, nil
+	}
+	if adopted != nil {
+		return false, takeOver(ctx, d.Client, pool, ds, adopted)
+	}
+	return false, nil
+}
+
+// scaledDown reports whether the DaemonSet runs no pods, e.g. after gpu-operator disabled the component through
+// its node labels.
+func scaledDown(ds *appsv1.DaemonSet) bool {
+	return ds.Status.DesiredNumberScheduled == 0 && ds.Status.CurrentNumberScheduled == 0 && ds.Status.NumberMisscheduled == 0
+}
+
+func setBlocked(pool *v1alpha1.GPUPool, ds *appsv1.DaemonSet, conflicts []string) {
+	message := fmt.Sprintf("DaemonSet %s is not rendered: gpu-operator DaemonSet %s still runs; scale it down or annotate it with %s=%s and enable adoptExisting",
+		ds.Name, strings.Join(conflicts, ", "), AdoptAnnotation, pool.Name)
+	if existing := meta.FindStatusCondition(pool.Status.Conditions, ConditionMigrationBlocked); existing != nil && existing.Status == metav1.ConditionTrue {
+		message = existing.Message + "; " + message
+	}
+	meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+		Type:               ConditionMigrationBlocked,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonUpstreamComponentRunning,
+		Message:            message,
+		ObservedGeneration: pool.Generation,
+	})
+}
+
+// takeOver makes ds render into the upstream DaemonSet. The upstream owner references are dropped first, since
+// the gpu-operator ClusterPolicy is the controller of its objects and a second controller reference is rejected.
+func takeOver(ctx context.Context, c client.Client, pool *v1alpha1.GPUPool, ds, upstream *appsv1.DaemonSet) error {
+	if refs := poolOwnerReferences(upstream.OwnerReferences); len(refs) != len(upstream.OwnerReferences) {
+		upstream.OwnerReferences = refs
+		if err := c.Update(ctx, upstream); err != nil {
+			return fmt.Errorf("release gpu-operator DaemonSet %s: %w", upstream.Name, err)
+		}
+	}
+
+	rendered := metav1.ObjectMeta{Name: ds.Name, Namespace: ds.Namespace}
+	ds.Name = upstream.Name
+	if ds.Annotations == nil {
+		ds.Annotations = map[string]string{}
+	}
+	ds.Annotations[AdoptAnnotation] = pool.Name
+	// The selector is immutable, so the pods also carry the upstream labels it matches.
+	ds.Spec.Selector = upstream.Spec.Selector.DeepCopy()
+	if ds.Spec.Template.Labels == nil {
+		ds.Spec.Template.Labels = map[string]string{}
+	}
+	if ds.Spec.Selector != nil {
+		for key, value := range ds.Spec.Selector.MatchLabels {
+			ds.Spec.Template.Labels[key] = value
+		}
+	}
+
+	if err := commonobject.DeleteObject(ctx, c, &appsv1.DaemonSet{ObjectMeta: rendered}); err != nil {
+		return fmt.Errorf("delete DaemonSet %s replaced by %s: %w", rendered.Name, upstream.Name, err)
+	}
+	if err := commonobject.DeleteObject(ctx, c, &policyv1.PodDisruptionBudget{ObjectMeta: rendered}); err != nil {
+		return fmt.Errorf("delete PodDisruptionBudget %s replaced by %s: %w", rendered.Name, upstream.Name, err)
+	}
+	return nil
+}
+
+func poolOwnerReferences(refs []metav1.OwnerReference) []metav1.OwnerReference {
+	kept := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.APIVersion == v1alpha1.GroupVersion.String() && (ref.Kind == "GPUPool" || ref.Kind == "ClusterGPUPool") {
+			kept = append(kept, ref)
+		}
+	}
+	return kept
+}
+
+// Adopted lists the upstream DaemonSets of the component the pool took over, so cleanup removes them with the
+// pool's own objects.
+func Adopted(ctx context.Context, c client.Client, namespace, poolName string, component Component) ([]string, error) {
+	var names []string
+	for _, name := range component.Upstream {
+		ds, err := commonobject.FetchObject(ctx, client.ObjectKey{Namespace: namespace, Name: name}, c, &appsv1.DaemonSet{})
+		if err != nil {
+			return nil, fmt.Errorf("get gpu-operator DaemonSet %s: %w", name, err)
+		}
+		if ds != nil && ds.Labels["pool"] == poolName {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+)
+
+// upstreamDaemonSet is a gpu-operator DaemonSet owned by its ClusterPolicy and scheduled on the given number of nodes.
+func upstreamDaemonSet(name string, scheduled int32) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels:    map[string]string{"app": name, "app.kubernetes.io/managed-by": "gpu-operator"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "nvidia.com/v1",
+				Kind:       "ClusterPolicy",
+				Name:       "cluster-policy",
+				UID:        "cp-uid",
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}}},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: scheduled, CurrentNumberScheduled: scheduled},
+	}
+}
+
+func annotated(ds *appsv1.DaemonSet, pool string) *appsv1.DaemonSet {
+	ds.Annotations = map[string]string{AdoptAnnotation: pool}
+	return ds
+}
+
+func renderedDaemonSet() *appsv1.DaemonSet {
+	labels := map[string]string{"app": "nvidia-device-plugin", "pool": "alpha"}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-alpha", Namespace: "ns", Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nvidia-device-plugin", "pool": "alpha"}}},
+		},
+	}
+}
+
+func newDeps(t *testing.T, migrate, adopt bool, objs ...client.Object) deps.Deps {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, appsv1.AddToScheme, policyv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("add scheme: %v", err)
+		}
+	}
+	return deps.Deps{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&appsv1.DaemonSet{}).Build(),
+		Config: config.WorkloadConfig{Namespace: "ns", MigrateFromGPUOperator: migrate, AdoptExisting: adopt},
+	}
+}
+
+func testPool() *v1alpha1.GPUPool {
+	return &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns", Generation: 3}}
+}
+
+func TestGuardConflicts(t *testing.T) {
+	cases := []struct {
+		name      string
+		migrate   bool
+		adopt     bool
+		objs      []client.Object
+		blocked   bool
+		conflicts []string
+	}{
+		{name: "migration off", objs: []client.Object{upstreamDaemonSet("nvidia-device-plugin-daemonset", 2)}},
+		{name: "no upstream objects", migrate: true},
+		{name: "running upstream", migrate: true, objs: []client.Object{upstreamDaemonSet("nvidia-device-plugin-daemonset", 2)}, blocked: true, conflicts: []string{"nvidia-device-plugin-daemonset"}},
+		{name: "scaled down upstream", migrate: true, objs: []client.Object{upstreamDaemonSet("nvidia-device-plugin-daemonset", 0)}},
+		{
+			name:      "annotated without adoptExisting",
+			migrate:   true,
+			objs:      []client.Object{annotated(upstreamDaemonSet("nvidia-device-plugin-daemonset", 2), "alpha")},
+			blocked:   true,
+			conflicts: []string{"nvidia-device-plugin-daemonset"},
+		},
+		{
+			name:      "annotated for another pool",
+			migrate:   true,
+			adopt:     true,
+			objs:      []client.Object{annotated(upstreamDaemonSet("nvidia-device-plugin-daemonset", 2), "beta")},
+			blocked:   true,
+			conflicts: []string{"nvidia-device-plugin-daemonset"},
+		},
+		{
+			name:    "taken over by another pool",
+			migrate: true,
+			objs: []client.Object{func() client.Object {
+				ds := upstreamDaemonSet("nvidia-device-plugin-daemonset", 2)
+				ds.Labels = map[string]string{"app": "nvidia-device-plugin", "pool": "beta"}
+				return ds
+			}()},
+		},
+		{name: "other component", migrate: true, objs: []client.Object{upstreamDaemonSet("nvidia-mig-manager", 2)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newDeps(t, tc.migrate, tc.adopt, tc.objs...)
+			pool := testPool()
+			ds := renderedDaemonSet()
+			blocked, err := Guard(context.Background(), d, pool, DevicePlugin, ds)
+			if err != nil {
+				t.Fatalf("guard: %v", err)
+			}
+			if blocked != tc.blocked {
+				t.Fatalf("expected blocked=%t, got %t", tc.blocked, blocked)
+			}
+			if ds.Name != "nvidia-device-plugin-alpha" {
+				t.Fatalf("expected the rendered name to be kept, got %s", ds.Name)
+			}
+			cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionMigrationBlocked)
+			if !tc.blocked {
+				if cond != nil {
+					t.Fatalf("expected no condition, got %+v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != ReasonUpstreamComponentRunning || cond.ObservedGeneration != 3 {
+				t.Fatalf("unexpected condition %+v", cond)
+			}
+			for _, name := range tc.conflicts {
+				if !strings.Contains(cond.Message, name) {
+					t.Fatalf("expected %s in message %q", name, cond.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestGuardAppendsMessages(t *testing.T) {
+	d := newDeps(t, true, false, upstreamDaemonSet("nvidia-device-plugin-daemonset", 1), upstreamDaemonSet("nvidia-mig-manager", 1))
+	pool := testPool()
+	if blocked, err := Guard(context.Background(), d, pool, DevicePlugin, renderedDaemonSet()); err != nil || !blocked {
+		t.Fatalf("expected device plugin to be blocked, got %t, %v", blocked, err)
+	}
+	mig := renderedDaemonSet()
+	mig.Name = "nvidia-mig-manager-alpha"
+	if blocked, err := Guard(context.Background(), d, pool, MIGManager, mig); err != nil || !blocked {
+		t.Fatalf("expected MIG manager to be blocked, got %t, %v", blocked, err)
+	}
+	cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionMigrationBlocked)
+	if cond == nil || !strings.Contains(cond.Message, "nvidia-device-plugin-daemonset") || !strings.Contains(cond.Message, "nvidia-mig-manager still runs") {
+		t.Fatalf("expected both conflicts in the message, got %+v", cond)
+	}
+}
+
+func TestGuardTakesOverAnnotatedDaemonSet(t *testing.T) {
+	previous := renderedDaemonSet()
+	budget := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: previous.Name, Namespace: "ns"}}
+	upstream := annotated(upstreamDaemonSet("nvidia-device-plugin-daemonset", 2), "alpha")
+	upstream.OwnerReferences = append(upstream.OwnerReferences, metav1.OwnerReference{
+		APIVersion: v1alpha1.GroupVersion.String(), Kind: "GPUPool", Name: "alpha", UID: "alpha-uid",
+	})
+	d := newDeps(t, true, true, upstream, previous, budget)
+	pool := testPool()
+	ds := renderedDaemonSet()
+
+	blocked, err := Guard(context.Background(), d, pool, DevicePlugin, ds)
+	if err != nil || blocked {
+		t.Fatalf("expected the takeover to proceed, got %t, %v", blocked, err)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionMigrationBlocked) != nil {
+		t.Fatalf("expected no condition, got %+v", pool.Status.Conditions)
+	}
+	if ds.Name != "nvidia-device-plugin-daemonset" || ds.Annotations[AdoptAnnotation] != "alpha" {
+		t.Fatalf("expected the upstream name and adopt annotation, got %s %v", ds.Name, ds.Annotations)
+	}
+	if got := ds.Spec.Selector.MatchLabels; len(got) != 1 || got["app"] != "nvidia-device-plugin-daemonset" {
+		t.Fatalf("expected the upstream selector, got %v", got)
+	}
+	if got := ds.Spec.Template.Labels; got["app"] != "nvidia-device-plugin-daemonset" || got["pool"] != "alpha" {
+		t.Fatalf("expected the pods to match the upstream selector and keep the pool label, got %v", got)
+	}
+	if ds.Labels["pool"] != "alpha" {
+		t.Fatalf("expected the rendered labels to be kept, got %v", ds.Labels)
+	}
+
+	live := &appsv1.DaemonSet{}
+	if err := d.Client.Get(context.Background(), client.ObjectKeyFromObject(upstream), live); err != nil {
+		t.Fatalf("get upstream: %v", err)
+	}
+	if len(live.OwnerReferences) != 1 || live.OwnerReferences[0].Kind != "GPUPool" {
+		t.Fatalf("expected only the pool owner reference to be kept, got %+v", live.OwnerReferences)
+	}
+	if err := d.Client.Get(context.Background(), client.ObjectKeyFromObject(previous), &appsv1.DaemonSet{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the previously rendered DaemonSet to be deleted, got %v", err)
+	}
+	if err := d.Client.Get(context.Background(), client.ObjectKeyFromObject(budget), &policyv1.PodDisruptionBudget{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the previously rendered PodDisruptionBudget to be deleted, got %v", err)
+	}
+}
+
+func TestGuardKeepsRenderingIntoAdoptedDaemonSet(t *testing.T) {
+	adopted := upstreamDaemonSet("nvidia-device-plugin-daemonset", 2)
+	adopted.Labels = map[string]string{"app": "nvidia-device-plugin", "pool": "alpha"}
+	adopted.OwnerReferences = nil
+	// adoptExisting may be turned off once the takeover happened.
+	d := newDeps(t, true, false, adopted)
+	ds := renderedDaemonSet()
+	if blocked, err := Guard(context.Background(), d, testPool(), DevicePlugin, ds); err != nil || blocked {
+		t.Fatalf("expected no block, got %t, %v", blocked, err)
+	}
+	if ds.Name != "nvidia-device-plugin-daemonset" {
+		t.Fatalf("expected the adopted DaemonSet to be rendered, got %s", ds.Name)
+	}
+}
+
+func TestAdopted(t *testing.T) {
+	adopted := upstreamDaemonSet("nvidia-device-plugin-daemonset", 2)
+	adopted.Labels = map[string]string{"pool": "alpha"}
+	d := newDeps(t, true, true, adopted, upstreamDaemonSet("nvidia-mig-manager", 1))
+
+	names, err := Adopted(context.Background(), d.Client, "ns", "alpha", DevicePlugin)
+	if err != nil || len(names) != 1 || names[0] != "nvidia-device-plugin-daemonset" {
+		t.Fatalf("unexpected adopted device plugins %v, %v", names, err)
+	}
+	if names, err := Adopted(context.Background(), d.Client, "ns", "beta", DevicePlugin); err != nil || len(names) != 0 {
+		t.Fatalf("expected nothing adopted by another pool, got %v, %v", names, err)
+	}
+	if names, err := Adopted(context.Background(), d.Client, "ns", "alpha", MIGManager); err != nil || len(names) != 0 {
+		t.Fatalf("expected the unlabelled MIG manager not to count, got %v, %v", names, err)
+	}
+}
@@ -43,3 +43,22 @@ func ResolveResourceName(pool *v1alpha1.GPUPool, rawName string) string {
 
 	return name
 }
+
+// MIGProfileResourceName is the resource a mixed-layout pool advertises for one MIG profile, without the prefix.
+func MIGProfileResourceName(pool *v1alpha1.GPUPool, profile string) string {
+	return fmt.Sprintf("%s-%s", ResolveResourceName(pool, pool.Name), strings.ToLower(strings.TrimSpace(profile)))
+}
+
+// PoolResourceNames lists the fully qualified resources the pool advertises: one per MIG profile for a
+// mixed-layout pool, the single pool resource otherwise.
+func PoolResourceNames(pool *v1alpha1.GPUPool) []string {
+	if len(pool.Spec.Resource.MIGLayout) == 0 {
+		return []string{PoolResourceName(pool)}
+	}
+	prefix := poolcommon.PoolResourcePrefixFor(pool)
+	out := make([]string, 0, len(pool.Spec.Resource.MIGLayout))
+	for _, entry := range pool.Spec.Resource.MIGLayout {
+		out = append(out, fmt.Sprintf("%s/%s", prefix, MIGProfileResourceName(pool, entry.Profile)))
+	}
+	return out
+}
@@ -33,3 +33,26 @@ func TestResolveResourceNameUsesPoolNameAndStripsPrefix(t *testing.T) {
 		t.Fatalf("expected prefix to be stripped, got %q", got)
 	}
 }
+
+func TestMIGProfileResourceNames(t *testing.T) {
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "mixed", Namespace: "ns"},
+		Spec: v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{
+			Unit:      "MIG",
+			MIGLayout: []v1alpha1.GPUPoolMIGLayoutEntry{{Profile: "3g.40gb", Count: 1}, {Profile: " 1G.10GB ", Count: 3}},
+		}},
+	}
+	if got := MIGProfileResourceName(pool, "1G.10GB"); got != "mixed-1g.10gb" {
+		t.Fatalf("unexpected profile resource name %q", got)
+	}
+	got := PoolResourceNames(pool)
+	want := []string{"gpu.deckhouse.io/mixed-3g.40gb", "gpu.deckhouse.io/mixed-1g.10gb"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("PoolResourceNames() = %v, want %v", got, want)
+	}
+
+	pool.Spec.Resource.MIGLayout = nil
+	if got := PoolResourceNames(pool); len(got) != 1 || got[0] != "gpu.deckhouse.io/mixed" {
+		t.Fatalf("single resource expected without a layout, got %v", got)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodelabels
+
+import (
+	"sync"
+	"time"
+)
+
+// NodeWriteLimiter allows at most one label write per node within the interval. One limiter is shared
+// by the GPUPool and ClusterGPUPool controllers, since both write labels to the same nodes.
+type NodeWriteLimiter struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// NewNodeWriteLimiter returns a limiter that spaces label writes to one node by the interval.
+func NewNodeWriteLimiter(interval time.Duration) *NodeWriteLimiter {
+	return &NodeWriteLimiter{interval: interval, now: time.Now, last: map[string]time.Time{}}
+}
+
+// reserve records a write for the node and returns zero, or returns how long to wait before the next one.
+func (l *NodeWriteLimiter) reserve(node string) time.Duration {
+	if l == nil || l.interval <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if last, ok := l.last[node]; ok {
+		if wait := last.Add(l.interval).Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	l.last[node] = now
+	return 0
+}
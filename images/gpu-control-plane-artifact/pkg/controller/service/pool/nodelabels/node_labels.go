@@ -0,0 +1,282 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodelabels
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+const (
+	// FinalizerName keeps the pool until its node labels are removed.
+	FinalizerName = "gpu.deckhouse.io/pool-node-labels"
+
+	poolLabelValue = "true"
+)
+
+// Keys are the node labels maintained for a single pool.
+type Keys struct {
+	Pool string
+	Unit string
+}
+
+// KeysFor returns gpu.deckhouse.io/pool.<name> and gpu.deckhouse.io/pool.<name>.unit
+// (cluster.gpu.deckhouse.io/... for ClusterGPUPool). Namespaced pools with the same name share keys.
+func KeysFor(pool *v1alpha1.GPUPool) Keys {
+	base := fmt.Sprintf("%s/pool.%s", poolcommon.PoolResourcePrefixFor(pool), pool.Name)
+	return Keys{Pool: base, Unit: base + ".unit"}
+}
+
+func (k Keys) validate() error {
+	for _, key := range []string{k.Pool, k.Unit} {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid node label key %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// NodeLabelsHandler maintains pool selection labels on nodes contributing capacity to the pool.
+type NodeLabelsHandler struct {
+	log     logr.Logger
+	client  client.Client
+	store   *moduleconfig.ModuleConfigStore
+	limiter *NodeWriteLimiter
+}
+
+// NewNodeLabelsHandler builds the handler; a nil limiter leaves node writes unthrottled.
+func NewNodeLabelsHandler(log logr.Logger, c client.Client, store *moduleconfig.ModuleConfigStore, limiter *NodeWriteLimiter) *NodeLabelsHandler {
+	return &NodeLabelsHandler{log: log, client: c, store: store, limiter: limiter}
+}
+
+func (h *NodeLabelsHandler) Name() string {
+	return "node-labels"
+}
+
+func (h *NodeLabelsHandler) enabled() bool {
+	return h.store != nil && h.store.Current().Settings.Scheduling.PoolNodeLabels
+}
+
+func (h *NodeLabelsHandler) HandlePool(ctx context.Context, pool *v1alpha1.GPUPool) (reconcile.Result, error) {
+	if h.client == nil {
+		return reconcile.Result{}, fmt.Errorf("client is required")
+	}
+
+	keys := KeysFor(pool)
+	deleting := pool.DeletionTimestamp != nil
+
+	if deleting || !h.enabled() {
+		// Labels are only ever written while the finalizer is present, so without it there is nothing to clean up.
+		if !controllerutil.ContainsFinalizer(pool, FinalizerName) {
+			return reconcile.Result{}, nil
+		}
+		res, err := h.release(ctx, pool, keys, h.enabled())
+		if err != nil || res.RequeueAfter > 0 {
+			return res, err
+		}
+		controllerutil.RemoveFinalizer(pool, FinalizerName)
+		if deleting {
+			return reconcile.Result{}, reconciler.ErrStopHandlerChain
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if err := keys.validate(); err != nil {
+		h.log.Error(err, "skip pool node labels", "pool", pool.Name)
+		return reconcile.Result{}, nil
+	}
+	controllerutil.AddFinalizer(pool, FinalizerName)
+
+	siblings, err := h.siblingPools(ctx, pool)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	nodes, err := h.contributingNodes(ctx, pool, append(siblings, *pool))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return h.sync(ctx, keys, nodes, pool.Spec.Resource.Unit)
+}
+
+// release drops the labels of a pool that is deleted or no longer labelled. A namespaced pool shares its
+// keys with same-named pools in other namespaces, so while labelling is enabled their nodes keep the labels.
+func (h *NodeLabelsHandler) release(ctx context.Context, pool *v1alpha1.GPUPool, keys Keys, enabled bool) (reconcile.Result, error) {
+	if !enabled {
+		// Labelling is disabled for every pool at once, so nothing is left to keep.
+		return h.sync(ctx, keys, nil, "")
+	}
+	siblings, err := h.siblingPools(ctx, pool)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(siblings) == 0 {
+		return h.sync(ctx, keys, nil, "")
+	}
+	nodes, err := h.contributingNodes(ctx, pool, siblings)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return h.sync(ctx, keys, nodes, siblings[0].Spec.Resource.Unit)
+}
+
+// siblingPools returns the live namespaced pools in other namespaces that share the label keys of the pool,
+// ordered by namespace. A ClusterGPUPool name is unique, so it has none.
+func (h *NodeLabelsHandler) siblingPools(ctx context.Context, pool *v1alpha1.GPUPool) ([]v1alpha1.GPUPool, error) {
+	if poolcommon.PoolResourcePrefixFor(pool) == poolcommon.ClusterPoolResourcePrefix {
+		return nil, nil
+	}
+	pools := &v1alpha1.GPUPoolList{}
+	if err := h.client.List(ctx, pools); err != nil {
+		return nil, err
+	}
+
+	var siblings []v1alpha1.GPUPool
+	for _, other := range pools.Items {
+		if other.Name != pool.Name || other.Namespace == pool.Namespace || other.DeletionTimestamp != nil {
+			continue
+		}
+		siblings = append(siblings, other)
+	}
+	sort.Slice(siblings, func(i, j int) bool { return siblings[i].Namespace < siblings[j].Namespace })
+	return siblings, nil
+}
+
+// contributingNodes returns nodes hosting devices of the given pools, which all share the label keys of the pool.
+func (h *NodeLabelsHandler) contributingNodes(ctx context.Context, pool *v1alpha1.GPUPool, pools []v1alpha1.GPUPool) (map[string]struct{}, error) {
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := h.client.List(ctx, devices, client.MatchingFields{indexer.GPUDevicePoolRefNameField: pool.Name}); err != nil {
+		return nil, err
+	}
+
+	clusterScoped := poolcommon.PoolResourcePrefixFor(pool) == poolcommon.ClusterPoolResourcePrefix
+	namespaces := make(map[string]struct{}, len(pools))
+	for i := range pools {
+		namespaces[pools[i].Namespace] = struct{}{}
+	}
+	nodes := make(map[string]struct{})
+	for i := range devices.Items {
+		dev := &devices.Items[i]
+		if poolcommon.IsDeviceIgnored(dev) {
+			continue
+		}
+		ref := dev.Status.PoolRef
+		if ref == nil || ref.Name != pool.Name || (strings.TrimSpace(ref.Namespace) == "") != clusterScoped {
+			continue
+		}
+		if _, ok := namespaces[strings.TrimSpace(ref.Namespace)]; !clusterScoped && !ok {
+			continue
+		}
+		if nodeName := poolcommon.DeviceNodeName(dev); nodeName != "" {
+			nodes[nodeName] = struct{}{}
+		}
+	}
+	return nodes, nil
+}
+
+// sync converges pool labels: desired nodes get both keys, every other labelled node loses them.
+// Nodes throttled by the limiter are retried through RequeueAfter.
+func (h *NodeLabelsHandler) sync(ctx context.Context, keys Keys, desired map[string]struct{}, unit string) (reconcile.Result, error) {
+	targets := make(map[string]struct{}, len(desired))
+	for name := range desired {
+		targets[name] = struct{}{}
+	}
+	for _, key := range []string{keys.Pool, keys.Unit} {
+		labelled := &corev1.NodeList{}
+		if err := h.client.List(ctx, labelled, client.HasLabels{key}); err != nil {
+			return reconcile.Result{}, err
+		}
+		for i := range labelled.Items {
+			targets[labelled.Items[i].Name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result reconcile.Result
+	for _, name := range names {
+		_, want := desired[name]
+		wait, err := h.syncNode(ctx, name, keys, want, unit)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if wait > 0 && (result.RequeueAfter == 0 || wait < result.RequeueAfter) {
+			result.RequeueAfter = wait
+		}
+	}
+	return result, nil
+}
+
+func (h *NodeLabelsHandler) syncNode(ctx context.Context, nodeName string, keys Keys, want bool, unit string) (time.Duration, error) {
+	node, err := commonobject.FetchObject(ctx, client.ObjectKey{Name: nodeName}, h.client, &corev1.Node{})
+	if err != nil || node == nil {
+		return 0, err
+	}
+	original := node.DeepCopy()
+
+	desired := map[string]string{}
+	if want {
+		desired[keys.Pool] = poolLabelValue
+		if unit != "" {
+			desired[keys.Unit] = unit
+		}
+	}
+
+	changed := false
+	for _, key := range []string{keys.Pool, keys.Unit} {
+		value, ok := desired[key]
+		current, exists := node.Labels[key]
+		switch {
+		case ok && (!exists || current != value):
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[key] = value
+			changed = true
+		case !ok && exists:
+			delete(node.Labels, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return 0, nil
+	}
+
+	if wait := h.limiter.reserve(nodeName); wait > 0 {
+		return wait, nil
+	}
+	// A merge patch only carries the keys touched above, so labels owned by others are never clobbered.
+	return 0, h.client.Patch(ctx, node, client.MergeFrom(original))
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodelabels
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+)
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.GPUDevice{}, indexer.GPUDevicePoolRefNameField, func(obj client.Object) []string {
+			dev, ok := obj.(*v1alpha1.GPUDevice)
+			if !ok || dev.Status.PoolRef == nil || dev.Status.PoolRef.Name == "" {
+				return nil
+			}
+			return []string{dev.Status.PoolRef.Name}
+		}).
+		WithObjects(objs...).
+		Build()
+}
+
+func newHandler(t *testing.T, cl client.Client, enabled bool) *NodeLabelsHandler {
+	t.Helper()
+	state := moduleconfig.DefaultState()
+	state.Settings.Scheduling.PoolNodeLabels = enabled
+	return NewNodeLabelsHandler(testr.New(t), cl, moduleconfig.NewModuleConfigStore(state), nil)
+}
+
+func device(name, node string, ref v1alpha1.GPUPoolReference) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: node, PoolRef: &ref},
+	}
+}
+
+func namespacedPool(name, unit string) *v1alpha1.GPUPool {
+	return &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: unit}},
+	}
+}
+
+func getNode(t *testing.T, cl client.Client, name string) *corev1.Node {
+	t.Helper()
+	node := &corev1.Node{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: name}, node); err != nil {
+		t.Fatalf("get node %s: %v", name, err)
+	}
+	return node
+}
+
+func TestNodeLabelsHandlerNameAndClientRequirement(t *testing.T) {
+	h := NewNodeLabelsHandler(testr.New(t), nil, nil, nil)
+	if h.Name() != "node-labels" {
+		t.Fatalf("unexpected handler name: %s", h.Name())
+	}
+	if _, err := h.HandlePool(context.Background(), namespacedPool("a", "Card")); err == nil {
+		t.Fatalf("expected error when client is nil")
+	}
+}
+
+func TestNodeLabelsAddsAndRemovesLabels(t *testing.T) {
+	cl := newClient(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"other": "keep"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{
+			"gpu.deckhouse.io/pool.train":      "true",
+			"gpu.deckhouse.io/pool.train.unit": "Card",
+		}}},
+		device("dev-1", "node1", v1alpha1.GPUPoolReference{Name: "train", Namespace: "team"}),
+	)
+	h := newHandler(t, cl, true)
+	pool := namespacedPool("train", "MIG")
+
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(pool, FinalizerName) {
+		t.Fatalf("expected finalizer to be added")
+	}
+
+	node1 := getNode(t, cl, "node1")
+	if node1.Labels["gpu.deckhouse.io/pool.train"] != "true" || node1.Labels["gpu.deckhouse.io/pool.train.unit"] != "MIG" {
+		t.Fatalf("expected pool labels on node1, got %v", node1.Labels)
+	}
+	if node1.Labels["other"] != "keep" {
+		t.Fatalf("expected unrelated labels to survive, got %v", node1.Labels)
+	}
+	node2 := getNode(t, cl, "node2")
+	if _, ok := node2.Labels["gpu.deckhouse.io/pool.train"]; ok {
+		t.Fatalf("expected pool labels to be removed from node2, got %v", node2.Labels)
+	}
+	if _, ok := node2.Labels["gpu.deckhouse.io/pool.train.unit"]; ok {
+		t.Fatalf("expected unit label to be removed from node2, got %v", node2.Labels)
+	}
+}
+
+func TestNodeLabelsRemovedOnPoolDelete(t *testing.T) {
+	cl := newClient(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{
+			"cluster.gpu.deckhouse.io/pool.shared":      "true",
+			"cluster.gpu.deckhouse.io/pool.shared.unit": "Card",
+		}}},
+		device("dev-1", "node1", v1alpha1.GPUPoolReference{Name: "shared"}),
+	)
+	h := newHandler(t, cl, true)
+	now := metav1.Now()
+	pool := &v1alpha1.GPUPool{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterGPUPool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", DeletionTimestamp: &now, Finalizers: []string{FinalizerName}},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+	}
+
+	if _, err := h.HandlePool(context.Background(), pool); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected handler chain to stop, got %v", err)
+	}
+	if controllerutil.ContainsFinalizer(pool, FinalizerName) {
+		t.Fatalf("expected finalizer to be removed")
+	}
+	node := getNode(t, cl, "node1")
+	if len(node.Labels) != 0 {
+		t.Fatalf("expected pool labels to be removed, got %v", node.Labels)
+	}
+}
+
+func TestNodeLabelsKeptForSameNamedPoolOnDelete(t *testing.T) {
+	labelled := map[string]string{
+		"gpu.deckhouse.io/pool.train":      "true",
+		"gpu.deckhouse.io/pool.train.unit": "Card",
+	}
+	now := metav1.Now()
+	deleted := namespacedPool("train", "Card")
+	deleted.DeletionTimestamp = &now
+	deleted.Finalizers = []string{FinalizerName}
+	remaining := namespacedPool("train", "Card")
+	remaining.Namespace = "research"
+
+	cl := newClient(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: maps.Clone(labelled)}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: maps.Clone(labelled)}},
+		deleted,
+		remaining,
+		device("dev-1", "node1", v1alpha1.GPUPoolReference{Name: "train", Namespace: "team"}),
+		device("dev-2", "node2", v1alpha1.GPUPoolReference{Name: "train", Namespace: "research"}),
+	)
+	h := newHandler(t, cl, true)
+
+	if _, err := h.HandlePool(context.Background(), deleted); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected handler chain to stop, got %v", err)
+	}
+	if controllerutil.ContainsFinalizer(deleted, FinalizerName) {
+		t.Fatalf("expected finalizer to be removed")
+	}
+	if len(getNode(t, cl, "node1").Labels) != 0 {
+		t.Fatalf("expected labels of the deleted pool to be removed, got %v", getNode(t, cl, "node1").Labels)
+	}
+	if got := getNode(t, cl, "node2").Labels; !maps.Equal(got, labelled) {
+		t.Fatalf("expected labels of the same-named pool to stay, got %v", got)
+	}
+}
+
+func TestNodeLabelsNodeInTwoPools(t *testing.T) {
+	cl := newClient(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		device("dev-1", "node1", v1alpha1.GPUPoolReference{Name: "train", Namespace: "team"}),
+		device("dev-2", "node1", v1alpha1.GPUPoolReference{Name: "infer", Namespace: "team"}),
+	)
+	h := newHandler(t, cl, true)
+
+	for _, pool := range []*v1alpha1.GPUPool{namespacedPool("train", "Card"), namespacedPool("infer", "MIG")} {
+		if _, err := h.HandlePool(context.Background(), pool); err != nil {
+			t.Fatalf("HandlePool %s: %v", pool.Name, err)
+		}
+	}
+
+	labels := getNode(t, cl, "node1").Labels
+	if labels["gpu.deckhouse.io/pool.train"] != "true" || labels["gpu.deckhouse.io/pool.train.unit"] != "Card" {
+		t.Fatalf("expected train labels, got %v", labels)
+	}
+	if labels["gpu.deckhouse.io/pool.infer"] != "true" || labels["gpu.deckhouse.io/pool.infer.unit"] != "MIG" {
+		t.Fatalf("expected infer labels, got %v", labels)
+	}
+}
+
+func TestNodeLabelsCleanupWhenDisabled(t *testing.T) {
+	cl := newClient(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"gpu.deckhouse.io/pool.train": "true"}}},
+		device("dev-1", "node1", v1alpha1.GPUPoolReference{Name: "train", Namespace: "team"}),
+	)
+	h := newHandler(t, cl, false)
+	pool := namespacedPool("train", "Card")
+
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if _, ok := getNode(t, cl, "node1").Labels["gpu.deckhouse.io/pool.train"]; !ok {
+		t.Fatalf("expected labels to be left alone without the finalizer")
+	}
+
+	controllerutil.AddFinalizer(pool, FinalizerName)
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if controllerutil.ContainsFinalizer(pool, FinalizerName) {
+		t.Fatalf("expected finalizer to be dropped when disabled")
+	}
+	if len(getNode(t, cl, "node1").Labels) != 0 {
+		t.Fatalf("expected labels to be removed when disabled")
+	}
+}
+
+func TestNodeLabelsThrottledWriteRequeues(t *testing.T) {
+	cl := newClient(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		device("dev-1", "node1", v1alpha1.GPUPoolReference{Name: "train", Namespace: "team"}),
+	)
+	h := newHandler(t, cl, true)
+	h.limiter = NewNodeWriteLimiter(time.Minute)
+	h.limiter.reserve("node1")
+
+	res, err := h.HandlePool(context.Background(), namespacedPool("train", "Card"))
+	if err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if res.RequeueAfter <= 0 {
+		t.Fatalf("expected requeue for throttled node, got %+v", res)
+	}
+	if len(getNode(t, cl, "node1").Labels) != 0 {
+		t.Fatalf("expected throttled node to stay untouched")
+	}
+}
+
+func TestNodeWriteLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewNodeWriteLimiter(time.Second)
+	l.now = func() time.Time { return now }
+
+	if wait := l.reserve("a"); wait != 0 {
+		t.Fatalf("expected first write to pass, got %s", wait)
+	}
+	if wait := l.reserve("a"); wait != time.Second {
+		t.Fatalf("expected second write to wait, got %s", wait)
+	}
+	if wait := l.reserve("b"); wait != 0 {
+		t.Fatalf("expected other node to pass, got %s", wait)
+	}
+	now = now.Add(time.Second)
+	if wait := l.reserve("a"); wait != 0 {
+		t.Fatalf("expected write after interval to pass, got %s", wait)
+	}
+}
@@ -20,6 +20,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -27,9 +29,13 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 )
 
-func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, pool *v1alpha1.GPUPool) error {
+// CreateOrUpdate creates obj or brings the live object to it, owned by pool. A nil pool renders an object without
+// an owner reference, for workloads that belong to the module rather than to a pool. Live objects the guard
+// refuses are left alone; a nil guard claims nothing and writes every object.
+func CreateOrUpdate(ctx context.Context, c client.Client, guard *ownership.Guard, obj client.Object, pool *v1alpha1.GPUPool) error {
 	desired := obj.DeepCopyObject().(client.Object)
 
 	switch want := desired.(type) {
@@ -41,11 +47,16 @@ func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, poo
 		}
 		if current == nil {
 			addOwner(want, pool)
+			guard.Stamp(want)
 			return c.Create(ctx, want)
 		}
+		if allowed, err := guard.MayWrite(ctx, current); err != nil || !allowed {
+			return err
+		}
 		hadOwner := hasOwner(current, pool)
 		addOwner(want, pool)
 		addOwner(current, pool)
+		guard.Stamp(want)
 		if hadOwner && configMapEqual(current, want) {
 			return nil
 		}
@@ -62,11 +73,16 @@ func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, poo
 		}
 		if current == nil {
 			addOwner(want, pool)
+			guard.Stamp(want)
 			return c.Create(ctx, want)
 		}
+		if allowed, err := guard.MayWrite(ctx, current); err != nil || !allowed {
+			return err
+		}
 		hadOwner := hasOwner(current, pool)
 		addOwner(want, pool)
 		addOwner(current, pool)
+		guard.Stamp(want)
 		if hadOwner && daemonSetEqual(current, want) {
 			return nil
 		}
@@ -74,12 +90,124 @@ func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, poo
 		current.Annotations = want.Annotations
 		current.Spec = want.Spec
 		return c.Update(ctx, current)
+	case *policyv1.PodDisruptionBudget:
+		current := &policyv1.PodDisruptionBudget{}
+		current, err := commonobject.FetchObject(ctx, client.ObjectKeyFromObject(want), c, current)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			addOwner(want, pool)
+			guard.Stamp(want)
+			return c.Create(ctx, want)
+		}
+		if allowed, err := guard.MayWrite(ctx, current); err != nil || !allowed {
+			return err
+		}
+		hadOwner := hasOwner(current, pool)
+		addOwner(want, pool)
+		addOwner(current, pool)
+		// PDB annotations are not rendered, so the claim is stamped on the live object.
+		claimed := guard.Stamp(current)
+		if hadOwner && !claimed && podDisruptionBudgetEqual(current, want) {
+			return nil
+		}
+		current.Labels = want.Labels
+		current.Spec = want.Spec
+		return c.Update(ctx, current)
+	case *corev1.ServiceAccount:
+		current := &corev1.ServiceAccount{}
+		current, err := commonobject.FetchObject(ctx, client.ObjectKeyFromObject(want), c, current)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			addOwner(want, pool)
+			guard.Stamp(want)
+			return c.Create(ctx, want)
+		}
+		if allowed, err := guard.MayWrite(ctx, current); err != nil || !allowed {
+			return err
+		}
+		hadOwner := hasOwner(current, pool)
+		addOwner(want, pool)
+		addOwner(current, pool)
+		guard.Stamp(want)
+		if hadOwner && serviceAccountEqual(current, want) {
+			return nil
+		}
+		current.Labels = want.Labels
+		current.Annotations = want.Annotations
+		return c.Update(ctx, current)
+	case *rbacv1.ClusterRole:
+		current := &rbacv1.ClusterRole{}
+		current, err := commonobject.FetchObject(ctx, client.ObjectKeyFromObject(want), c, current)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			addOwner(want, pool)
+			guard.Stamp(want)
+			return c.Create(ctx, want)
+		}
+		if allowed, err := guard.MayWrite(ctx, current); err != nil || !allowed {
+			return err
+		}
+		hadOwner := hasOwner(current, pool)
+		addOwner(want, pool)
+		addOwner(current, pool)
+		guard.Stamp(want)
+		if hadOwner && clusterRoleEqual(current, want) {
+			return nil
+		}
+		current.Labels = want.Labels
+		current.Annotations = want.Annotations
+		current.Rules = want.Rules
+		return c.Update(ctx, current)
+	case *rbacv1.ClusterRoleBinding:
+		current := &rbacv1.ClusterRoleBinding{}
+		current, err := commonobject.FetchObject(ctx, client.ObjectKeyFromObject(want), c, current)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			addOwner(want, pool)
+			guard.Stamp(want)
+			return c.Create(ctx, want)
+		}
+		if allowed, err := guard.MayWrite(ctx, current); err != nil || !allowed {
+			return err
+		}
+		if !apiequality.Semantic.DeepEqual(current.RoleRef, want.RoleRef) {
+			// roleRef is immutable, so a binding to another role is replaced.
+			if err := commonobject.DeleteObject(ctx, c, current); err != nil {
+				return err
+			}
+			addOwner(want, pool)
+			guard.Stamp(want)
+			return c.Create(ctx, want)
+		}
+		hadOwner := hasOwner(current, pool)
+		addOwner(want, pool)
+		addOwner(current, pool)
+		guard.Stamp(want)
+		if hadOwner && clusterRoleBindingEqual(current, want) {
+			return nil
+		}
+		current.Labels = want.Labels
+		current.Annotations = want.Annotations
+		current.Subjects = want.Subjects
+		return c.Update(ctx, current)
 	default:
 		return fmt.Errorf("unsupported object type %T", obj)
 	}
 }
 
 func addOwner(obj client.Object, pool *v1alpha1.GPUPool) {
+	// Module-level workloads have no pool owner; they are removed by explicit cleanup.
+	if pool == nil {
+		return
+	}
 	// Namespaced GPUPool cannot own resources in a different namespace; rely on explicit cleanup for those.
 	if pool.Namespace != "" && obj.GetNamespace() != pool.Namespace {
 		return
@@ -110,6 +238,9 @@ func addOwner(obj client.Object, pool *v1alpha1.GPUPool) {
 }
 
 func hasOwner(obj client.Object, pool *v1alpha1.GPUPool) bool {
+	if pool == nil {
+		return true
+	}
 	kind := pool.Kind
 	if kind == "" {
 		if pool.Namespace == "" {
@@ -140,3 +271,29 @@ func daemonSetEqual(current, desired *appsv1.DaemonSet) bool {
 		apiequality.Semantic.DeepEqual(current.Spec, desired.Spec) &&
 		apiequality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences)
 }
+
+func serviceAccountEqual(current, desired *corev1.ServiceAccount) bool {
+	return apiequality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		apiequality.Semantic.DeepEqual(current.Annotations, desired.Annotations) &&
+		apiequality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences)
+}
+
+func clusterRoleEqual(current, desired *rbacv1.ClusterRole) bool {
+	return apiequality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		apiequality.Semantic.DeepEqual(current.Annotations, desired.Annotations) &&
+		apiequality.Semantic.DeepEqual(current.Rules, desired.Rules) &&
+		apiequality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences)
+}
+
+func clusterRoleBindingEqual(current, desired *rbacv1.ClusterRoleBinding) bool {
+	return apiequality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		apiequality.Semantic.DeepEqual(current.Annotations, desired.Annotations) &&
+		apiequality.Semantic.DeepEqual(current.Subjects, desired.Subjects) &&
+		apiequality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences)
+}
+
+func podDisruptionBudgetEqual(current, desired *policyv1.PodDisruptionBudget) bool {
+	return apiequality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		apiequality.Semantic.DeepEqual(current.Spec, desired.Spec) &&
+		apiequality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences)
+}
@@ -19,15 +19,22 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/ownership"
 )
 
 type getErrorClient struct {
@@ -57,7 +64,7 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}, Data: map[string]string{"k": "v"}}
-		if err := CreateOrUpdate(context.Background(), cl, cm, pool); err != nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, cm, pool); err != nil {
 			t.Fatalf("createOrUpdate: %v", err)
 		}
 		got := &corev1.ConfigMap{}
@@ -76,7 +83,7 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
 
 		desired := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}, Data: map[string]string{"k": "v"}}
-		if err := CreateOrUpdate(context.Background(), cl, desired, pool); err != nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, desired, pool); err != nil {
 			t.Fatalf("createOrUpdate: %v", err)
 		}
 	})
@@ -86,7 +93,7 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
 
 		desired := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}, Data: map[string]string{"k": "new"}}
-		if err := CreateOrUpdate(context.Background(), cl, desired, pool); err != nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, desired, pool); err != nil {
 			t.Fatalf("createOrUpdate: %v", err)
 		}
 		got := &corev1.ConfigMap{}
@@ -103,7 +110,7 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 
 		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
 		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns"}}
-		if err := CreateOrUpdate(context.Background(), cl, ds, pool); err != nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, ds, pool); err != nil {
 			t.Fatalf("createOrUpdate create: %v", err)
 		}
 
@@ -116,7 +123,7 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 		}
 
 		desiredNoop := existing.DeepCopy()
-		if err := CreateOrUpdate(context.Background(), cl, desiredNoop, pool); err != nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, desiredNoop, pool); err != nil {
 			t.Fatalf("createOrUpdate noop: %v", err)
 		}
 
@@ -125,7 +132,7 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 			desiredUpdate.Labels = map[string]string{}
 		}
 		desiredUpdate.Labels["x"] = "y"
-		if err := CreateOrUpdate(context.Background(), cl, desiredUpdate, pool); err != nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, desiredUpdate, pool); err != nil {
 			t.Fatalf("createOrUpdate update: %v", err)
 		}
 	})
@@ -134,22 +141,61 @@ func TestCreateOrUpdateConfigMapAndDaemonSetBranches(t *testing.T) {
 		base := fake.NewClientBuilder().WithScheme(scheme).Build()
 		errClient := getErrorClient{Client: base, err: errors.New("boom")}
 
-		if err := CreateOrUpdate(context.Background(), errClient, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}}, pool); err == nil {
+		if err := CreateOrUpdate(context.Background(), errClient, nil, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}}, pool); err == nil {
 			t.Fatalf("expected get error for ConfigMap")
 		}
-		if err := CreateOrUpdate(context.Background(), errClient, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns"}}, pool); err == nil {
+		if err := CreateOrUpdate(context.Background(), errClient, nil, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns"}}, pool); err == nil {
 			t.Fatalf("expected get error for DaemonSet")
 		}
 	})
 
 	t.Run("unsupported type errors", func(t *testing.T) {
 		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
-		if err := CreateOrUpdate(context.Background(), cl, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"}}, pool); err == nil {
+		if err := CreateOrUpdate(context.Background(), cl, nil, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"}}, pool); err == nil {
 			t.Fatalf("expected unsupported type error")
 		}
 	})
 }
 
+func TestCreateOrUpdatePodDisruptionBudget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns", UID: types.UID("uid")}}
+	one := intstr.FromInt32(1)
+	two := intstr.FromInt32(2)
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	desired := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "ns"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &two},
+	}
+	if err := CreateOrUpdate(context.Background(), cl, nil, desired, pool); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	desired.Spec.MaxUnavailable = &one
+	if err := CreateOrUpdate(context.Background(), cl, nil, desired, pool); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	got := &policyv1.PodDisruptionBudget{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(desired), got); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !hasOwner(got, pool) {
+		t.Fatalf("expected owner reference on PodDisruptionBudget")
+	}
+	if got.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Fatalf("expected maxUnavailable to be updated, got %v", got.Spec.MaxUnavailable)
+	}
+
+	if err := CreateOrUpdate(context.Background(), getErrorClient{Client: cl, err: errors.New("boom")}, nil, desired, pool); err == nil {
+		t.Fatalf("expected get error")
+	}
+}
+
 func TestAddOwnerSkipsCrossNamespaceAndDoesNotDuplicate(t *testing.T) {
 	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns", UID: "uid"}}
 
@@ -167,6 +213,26 @@ func TestAddOwnerSkipsCrossNamespaceAndDoesNotDuplicate(t *testing.T) {
 	}
 }
 
+func TestCreateOrUpdateWithoutPoolSetsNoOwner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+
+	existing := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns", Labels: map[string]string{"v": "old"}}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	desired := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns", Labels: map[string]string{"v": "new"}}}
+	if err := CreateOrUpdate(context.Background(), cl, nil, desired, nil); err != nil {
+		t.Fatalf("createOrUpdate: %v", err)
+	}
+	got := &appsv1.DaemonSet{}
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(desired), got); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Labels["v"] != "new" || len(got.OwnerReferences) != 0 {
+		t.Fatalf("expected update without owner references, got labels=%v ownerRefs=%v", got.Labels, got.OwnerReferences)
+	}
+}
+
 func TestHasOwnerUsesClusterKindFallback(t *testing.T) {
 	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}}
 
@@ -185,3 +251,147 @@ func TestHasOwnerUsesClusterKindFallback(t *testing.T) {
 		t.Fatalf("expected hasOwner=true for cluster pool kind fallback")
 	}
 }
+
+func TestCreateOrUpdateOwnershipClaim(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	leases := fake.NewClientBuilder().Build()
+	newGuard := func(namespace string) *ownership.Guard {
+		self := ownership.Identity{Namespace: namespace, Name: "leader"}
+		if err := ownership.NewHeartbeat(logr.Discard(), leases, leases, self, "pod").Renew(context.Background()); err != nil {
+			t.Fatalf("renew heartbeat: %v", err)
+		}
+		return ownership.NewGuard(logr.Discard(), self, leases, false)
+	}
+	guardA, guardB := newGuard("d8-gpu"), newGuard("d8-gpu-migration")
+
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "ns", UID: types.UID("uid")}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	render := func(image string) []client.Object {
+		return []client.Object{
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}, Data: map[string]string{"image": image}},
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns"},
+				Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "c", Image: image}},
+				}}},
+			},
+			&policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "ns"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{MaxUnavailable: ptr.To(intstr.FromString(image))},
+			},
+		}
+	}
+	apply := func(guard *ownership.Guard, image string) {
+		t.Helper()
+		for _, obj := range render(image) {
+			if err := CreateOrUpdate(context.Background(), cl, guard, obj, pool); err != nil {
+				t.Fatalf("createOrUpdate %s: %v", obj.GetName(), err)
+			}
+		}
+	}
+	assertState := func(wantImage string, wantClaim ownership.Identity) {
+		t.Helper()
+		for name, obj := range map[string]client.Object{"cm": &corev1.ConfigMap{}, "ds": &appsv1.DaemonSet{}, "pdb": &policyv1.PodDisruptionBudget{}} {
+			var image string
+			if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, obj); err != nil {
+				t.Fatalf("get %s: %v", name, err)
+			}
+			switch o := obj.(type) {
+			case *corev1.ConfigMap:
+				image = o.Data["image"]
+			case *appsv1.DaemonSet:
+				image = o.Spec.Template.Spec.Containers[0].Image
+			case *policyv1.PodDisruptionBudget:
+				image = o.Spec.MaxUnavailable.StrVal
+			}
+			if image != wantImage {
+				t.Fatalf("%s: expected %q, got %q", name, wantImage, image)
+			}
+			if got := obj.GetAnnotations()[annotations.ControllerInstance]; got != wantClaim.String() {
+				t.Fatalf("%s: expected claim %s, got %q", name, wantClaim, got)
+			}
+		}
+	}
+
+	apply(guardA, "v1")
+	assertState("v1", guardA.Self())
+
+	apply(guardB, "v2")
+	assertState("v1", guardA.Self())
+
+	apply(guardA, "v3")
+	assertState("v3", guardA.Self())
+}
+
+func TestCreateOrUpdateRBACObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	ctx := context.Background()
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool", UID: types.UID("uid")}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: "ns", Labels: map[string]string{"app": "a"}}}
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "role"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}}},
+	}
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "role"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "role"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "sa", Namespace: "ns"}},
+	}
+	for _, obj := range []client.Object{sa, role, binding} {
+		if err := CreateOrUpdate(ctx, cl, nil, obj, pool); err != nil {
+			t.Fatalf("create %T: %v", obj, err)
+		}
+	}
+	gotRole := &rbacv1.ClusterRole{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "role"}, gotRole); err != nil {
+		t.Fatalf("get role: %v", err)
+	}
+	if !hasOwner(gotRole, pool) {
+		t.Fatalf("expected cluster pool to own the ClusterRole")
+	}
+	rv := gotRole.ResourceVersion
+	if err := CreateOrUpdate(ctx, cl, nil, role, pool); err != nil {
+		t.Fatalf("noop role: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "role"}, gotRole); err != nil || gotRole.ResourceVersion != rv {
+		t.Fatalf("expected unchanged ClusterRole to be left alone, err=%v rv=%s->%s", err, rv, gotRole.ResourceVersion)
+	}
+
+	role.Rules[0].Verbs = []string{"get", "patch"}
+	if err := CreateOrUpdate(ctx, cl, nil, role, pool); err != nil {
+		t.Fatalf("update role: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "role"}, gotRole); err != nil || len(gotRole.Rules[0].Verbs) != 2 {
+		t.Fatalf("expected updated rules, err=%v rules=%+v", err, gotRole.Rules)
+	}
+
+	sa.Labels = map[string]string{"app": "b"}
+	if err := CreateOrUpdate(ctx, cl, nil, sa, pool); err != nil {
+		t.Fatalf("update sa: %v", err)
+	}
+	gotSA := &corev1.ServiceAccount{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "sa"}, gotSA); err != nil || gotSA.Labels["app"] != "b" {
+		t.Fatalf("expected updated ServiceAccount labels, err=%v labels=%v", err, gotSA.Labels)
+	}
+
+	// roleRef is immutable, so a binding to a different role is replaced.
+	binding.RoleRef.Name = "other"
+	if err := CreateOrUpdate(ctx, cl, nil, binding, pool); err != nil {
+		t.Fatalf("replace binding: %v", err)
+	}
+	gotBinding := &rbacv1.ClusterRoleBinding{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "role"}, gotBinding); err != nil || gotBinding.RoleRef.Name != "other" {
+		t.Fatalf("expected binding to reference the new role, err=%v roleRef=%+v", err, gotBinding.RoleRef)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package overrides applies the per-pool extraEnv, extraVolumeMounts and extraVolumes escape hatch to rendered
+// components and rejects entries that would collide with what the renderer manages.
+package overrides
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
+)
+
+// Reserved lists the env names, volume names and mount paths the renderer may set on a component, whatever the pool
+// options; overrides may not reuse them even when the current options leave them out.
+type Reserved struct {
+	Env        []string
+	Volumes    []string
+	MountPaths []string
+}
+
+var (
+	// DevicePlugin is reserved on the device plugin container and pod.
+	DevicePlugin = Reserved{
+		Env: []string{
+			"NVIDIA_VISIBLE_DEVICES", "NVIDIA_RESOURCE_PREFIX", "NVIDIA_DRIVER_ROOT", "CONTAINER_DRIVER_ROOT",
+			"DP_DISABLE_HEALTHCHECKS", "DP_HEALTHCHECK_BACKEND", "DCGM_HOSTENGINE_SOCKET", "DP_HEALTHCHECK_UNHEALTHY_THRESHOLD",
+		},
+		Volumes: []string{
+			"device-plugin", "dev", "config", "driver-root", "run-nvidia-validations", "dcgm-socket", "infiniband", "host-modules",
+		},
+		MountPaths: []string{
+			"/var/lib/kubelet/device-plugins", "/config", "/dev", kube.ContainerDriverRoot, "/run/dcgm",
+			kube.InfinibandDir, kube.HostModulesDir,
+		},
+	}
+	// MIGManager is reserved on the MIG manager container and pod.
+	MIGManager = Reserved{
+		Env: []string{
+			"NODE_NAME", "CONFIG_FILE", "GPU_CLIENTS_FILE", "HOST_ROOT_MOUNT", "HOST_NVIDIA_DIR", "HOST_KUBELET_SYSTEMD_SERVICE",
+			"HOST_MIG_MANAGER_STATE_FILE", "DEFAULT_GPU_CLIENTS_NAMESPACE", "WITH_SHUTDOWN_HOST_GPU_CLIENTS", "WITH_REBOOT",
+			"CONTAINER_RUNTIME", "RUNTIME_SOCKET", "RUNTIME_CONFIG", "RUNTIME_HOOKS_DIR",
+		},
+		Volumes: []string{"host-root", "host-sys", "dev", "config", "gpu-clients", "mig-scripts"},
+		MountPaths: []string{
+			"/host", "/sys", "/gpu-clients", "/mig-parted-config", "/dev", "/usr/bin/reconfigure-mig.sh", "/usr/bin/prestop.sh",
+		},
+	}
+)
+
+// Validate checks overrides against reserved; field prefixes the error, e.g. "devicePlugin". Each mount must use one
+// of the extra volumes, and no mount path may equal, contain or sit inside a reserved one.
+func Validate(field string, o *v1alpha1.GPUPoolComponentOverrides, reserved Reserved) error {
+	if o == nil {
+		return nil
+	}
+
+	envs := make(map[string]struct{}, len(o.ExtraEnv))
+	for i, env := range o.ExtraEnv {
+		name := strings.TrimSpace(env.Name)
+		switch {
+		case name == "":
+			return fmt.Errorf("%s.extraEnv[%d].name must be set", field, i)
+		case slices.Contains(reserved.Env, name):
+			return fmt.Errorf("%s.extraEnv[%d]: %q is managed by the controller", field, i, name)
+		}
+		if _, dup := envs[name]; dup {
+			return fmt.Errorf("%s.extraEnv[%d]: duplicate name %q", field, i, name)
+		}
+		envs[name] = struct{}{}
+	}
+
+	volumes := make(map[string]struct{}, len(o.ExtraVolumes))
+	for i, volume := range o.ExtraVolumes {
+		name := strings.TrimSpace(volume.Name)
+		switch {
+		case name == "":
+			return fmt.Errorf("%s.extraVolumes[%d].name must be set", field, i)
+		case slices.Contains(reserved.Volumes, name):
+			return fmt.Errorf("%s.extraVolumes[%d]: %q is managed by the controller", field, i, name)
+		}
+		if _, dup := volumes[name]; dup {
+			return fmt.Errorf("%s.extraVolumes[%d]: duplicate name %q", field, i, name)
+		}
+		volumes[name] = struct{}{}
+	}
+
+	paths := make(map[string]struct{}, len(o.ExtraVolumeMounts))
+	for i, mount := range o.ExtraVolumeMounts {
+		if _, ok := volumes[mount.Name]; !ok {
+			return fmt.Errorf("%s.extraVolumeMounts[%d]: volume %q is not listed in extraVolumes", field, i, mount.Name)
+		}
+		if !path.IsAbs(mount.MountPath) {
+			return fmt.Errorf("%s.extraVolumeMounts[%d].mountPath must be an absolute path", field, i)
+		}
+		mountPath := path.Clean(mount.MountPath)
+		for _, managed := range reserved.MountPaths {
+			if overlaps(mountPath, managed) {
+				return fmt.Errorf("%s.extraVolumeMounts[%d]: %q overlaps %q managed by the controller", field, i, mountPath, managed)
+			}
+		}
+		if _, dup := paths[mountPath]; dup {
+			return fmt.Errorf("%s.extraVolumeMounts[%d]: duplicate mountPath %q", field, i, mountPath)
+		}
+		paths[mountPath] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateNamespaced applies the extra limits of a namespaced GPUPool on top of Validate. Its components run in the
+// module namespace, the MIG manager privileged, so its owner gets neither host paths nor the Secrets and ConfigMaps
+// of that namespace: env values must be literal and volumes are limited to emptyDir and downwardAPI.
+func ValidateNamespaced(field string, o *v1alpha1.GPUPoolComponentOverrides) error {
+	if o == nil {
+		return nil
+	}
+	for i, env := range o.ExtraEnv {
+		if env.ValueFrom != nil {
+			return fmt.Errorf("%s.extraEnv[%d]: valueFrom is not allowed on a namespaced GPUPool", field, i)
+		}
+	}
+	for i, volume := range o.ExtraVolumes {
+		if !namespacedSource(volume.VolumeSource) {
+			return fmt.Errorf("%s.extraVolumes[%d]: only emptyDir and downwardAPI volumes are allowed on a namespaced GPUPool", field, i)
+		}
+	}
+	return nil
+}
+
+// Apply appends overrides to the named container and its pod after the renderer has filled them in. Entries that
+// already exist are skipped, so a pool admitted before validation tightened cannot replace a rendered one; for the
+// same reason a namespaced pool also drops what ValidateNamespaced rejects, together with the mounts of such volumes.
+func Apply(spec *corev1.PodSpec, container string, o *v1alpha1.GPUPoolComponentOverrides, namespaced bool) {
+	if o == nil {
+		return
+	}
+	var target *corev1.Container
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == container {
+			target = &spec.Containers[i]
+			break
+		}
+	}
+	if target == nil {
+		return
+	}
+
+	for _, env := range o.ExtraEnv {
+		if namespaced && env.ValueFrom != nil {
+			continue
+		}
+		if !slices.ContainsFunc(target.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name }) {
+			target.Env = append(target.Env, *env.DeepCopy())
+		}
+	}
+	dropped := make(map[string]struct{})
+	for _, volume := range o.ExtraVolumes {
+		if namespaced && !namespacedSource(volume.VolumeSource) {
+			dropped[volume.Name] = struct{}{}
+			continue
+		}
+		if !slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == volume.Name }) {
+			spec.Volumes = append(spec.Volumes, *volume.DeepCopy())
+		}
+	}
+	for _, mount := range o.ExtraVolumeMounts {
+		if _, ok := dropped[mount.Name]; ok {
+			continue
+		}
+		if !slices.ContainsFunc(target.VolumeMounts, func(m corev1.VolumeMount) bool { return path.Clean(m.MountPath) == path.Clean(mount.MountPath) }) {
+			target.VolumeMounts = append(target.VolumeMounts, *mount.DeepCopy())
+		}
+	}
+}
+
+// namespacedSource reports whether source is one a namespaced GPUPool may use.
+func namespacedSource(source corev1.VolumeSource) bool {
+	source.EmptyDir = nil
+	source.DownwardAPI = nil
+	return source == corev1.VolumeSource{}
+}
+
+func overlaps(a, b string) bool {
+	return a == b || strings.HasPrefix(a, strings.TrimSuffix(b, "/")+"/") || strings.HasPrefix(b, strings.TrimSuffix(a, "/")+"/")
+}
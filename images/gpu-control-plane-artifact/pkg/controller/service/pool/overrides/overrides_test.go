@@ -0,0 +1,236 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overrides
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	reserved := Reserved{Env: []string{"MANAGED"}, Volumes: []string{"config"}, MountPaths: []string{"/config"}}
+	volume := corev1.Volume{Name: "extra", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+
+	cases := []struct {
+		name    string
+		o       *v1alpha1.GPUPoolComponentOverrides
+		wantErr string
+	}{
+		{name: "nil"},
+		{
+			name: "valid",
+			o: &v1alpha1.GPUPoolComponentOverrides{
+				ExtraEnv:          []corev1.EnvVar{{Name: "EXTRA", Value: "1"}},
+				ExtraVolumes:      []corev1.Volume{volume},
+				ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/configs"}},
+			},
+		},
+		{name: "empty env name", o: &v1alpha1.GPUPoolComponentOverrides{ExtraEnv: []corev1.EnvVar{{Name: " "}}}, wantErr: "c.extraEnv[0].name must be set"},
+		{name: "reserved env", o: &v1alpha1.GPUPoolComponentOverrides{ExtraEnv: []corev1.EnvVar{{Name: "MANAGED"}}}, wantErr: `"MANAGED" is managed by the controller`},
+		{name: "duplicate env", o: &v1alpha1.GPUPoolComponentOverrides{ExtraEnv: []corev1.EnvVar{{Name: "A"}, {Name: "A"}}}, wantErr: `c.extraEnv[1]: duplicate name "A"`},
+		{name: "empty volume name", o: &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{{}}}, wantErr: "c.extraVolumes[0].name must be set"},
+		{name: "reserved volume", o: &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{{Name: "config"}}}, wantErr: `"config" is managed by the controller`},
+		{name: "duplicate volume", o: &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{volume, volume}}, wantErr: `c.extraVolumes[1]: duplicate name "extra"`},
+		{
+			name:    "mount of unknown volume",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/other"}}},
+			wantErr: `volume "config" is not listed in extraVolumes`,
+		},
+		{
+			name:    "relative mount path",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{volume}, ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "data"}}},
+			wantErr: "mountPath must be an absolute path",
+		},
+		{
+			name:    "reserved mount path",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{volume}, ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/config/"}}},
+			wantErr: `"/config" overlaps "/config"`,
+		},
+		{
+			name:    "mount inside reserved path",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{volume}, ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/config/extra"}}},
+			wantErr: `"/config/extra" overlaps "/config"`,
+		},
+		{
+			name:    "mount over reserved path",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{volume}, ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/"}}},
+			wantErr: `"/" overlaps "/config"`,
+		},
+		{
+			name: "duplicate mount path",
+			o: &v1alpha1.GPUPoolComponentOverrides{
+				ExtraVolumes:      []corev1.Volume{volume},
+				ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/data"}, {Name: "extra", MountPath: "/data/"}},
+			},
+			wantErr: `c.extraVolumeMounts[1]: duplicate mountPath "/data"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate("c", tc.o, reserved)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "sidecar"},
+			{
+				Name:         "main",
+				Env:          []corev1.EnvVar{{Name: "MANAGED", Value: "rendered"}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/config"}},
+			},
+		},
+		Volumes: []corev1.Volume{{Name: "config"}},
+	}
+	o := &v1alpha1.GPUPoolComponentOverrides{
+		ExtraEnv:          []corev1.EnvVar{{Name: "MANAGED", Value: "override"}, {Name: "EXTRA", Value: "1"}},
+		ExtraVolumes:      []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}, {Name: "extra"}},
+		ExtraVolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/config/"}, {Name: "extra", MountPath: "/extra"}},
+	}
+
+	Apply(&spec, "main", o, false)
+
+	main := spec.Containers[1]
+	if len(main.Env) != 2 || main.Env[0].Value != "rendered" || main.Env[1].Name != "EXTRA" {
+		t.Fatalf("expected the extra env after the rendered one, got %+v", main.Env)
+	}
+	if len(spec.Volumes) != 2 || spec.Volumes[0].EmptyDir != nil || spec.Volumes[1].Name != "extra" {
+		t.Fatalf("expected the extra volume after the rendered one, got %+v", spec.Volumes)
+	}
+	if len(main.VolumeMounts) != 2 || main.VolumeMounts[0].Name != "config" || main.VolumeMounts[1].MountPath != "/extra" {
+		t.Fatalf("expected the extra mount after the rendered one, got %+v", main.VolumeMounts)
+	}
+	if sidecar := spec.Containers[0]; len(sidecar.Env) != 0 || len(sidecar.VolumeMounts) != 0 {
+		t.Fatalf("expected other containers untouched, got %+v", sidecar)
+	}
+
+	// The pool spec is copied, not aliased.
+	main.Env[1].Value = "changed"
+	if o.ExtraEnv[1].Value != "1" {
+		t.Fatalf("expected overrides to be copied into the pod spec")
+	}
+
+	before := spec.DeepCopy()
+	Apply(&spec, "missing", o, false)
+	Apply(&spec, "main", nil, false)
+	if len(spec.Volumes) != len(before.Volumes) {
+		t.Fatalf("expected no changes for a missing container or nil overrides")
+	}
+}
+
+func TestValidateNamespaced(t *testing.T) {
+	valid := &v1alpha1.GPUPoolComponentOverrides{
+		ExtraEnv: []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+		ExtraVolumes: []corev1.Volume{
+			{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			{Name: "podinfo", VolumeSource: corev1.VolumeSource{DownwardAPI: &corev1.DownwardAPIVolumeSource{}}},
+		},
+	}
+	if err := ValidateNamespaced("devicePlugin", valid); err != nil {
+		t.Fatalf("expected literal env and emptyDir/downwardAPI volumes to be valid, got %v", err)
+	}
+	if err := ValidateNamespaced("devicePlugin", nil); err != nil {
+		t.Fatalf("expected nil overrides to be valid, got %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		o       *v1alpha1.GPUPoolComponentOverrides
+		wantErr string
+	}{
+		{
+			name: "secret env",
+			o: &v1alpha1.GPUPoolComponentOverrides{ExtraEnv: []corev1.EnvVar{{
+				Name:      "TOKEN",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "module-secret"}, Key: "token"}},
+			}}},
+			wantErr: "devicePlugin.extraEnv[0]: valueFrom is not allowed",
+		},
+		{
+			name:    "host path",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{{Name: "root", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}}}},
+			wantErr: "devicePlugin.extraVolumes[0]: only emptyDir and downwardAPI volumes are allowed",
+		},
+		{
+			name:    "secret volume",
+			o:       &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "module-secret"}}}}},
+			wantErr: "devicePlugin.extraVolumes[0]: only emptyDir and downwardAPI volumes are allowed",
+		},
+		{
+			name: "emptyDir with a second source",
+			o: &v1alpha1.GPUPoolComponentOverrides{ExtraVolumes: []corev1.Volume{{Name: "mixed", VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+				HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+			}}}},
+			wantErr: "devicePlugin.extraVolumes[0]: only emptyDir and downwardAPI volumes are allowed",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNamespaced("devicePlugin", tc.o)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyNamespacedDropsHostAccess(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}}
+	o := &v1alpha1.GPUPoolComponentOverrides{
+		ExtraEnv: []corev1.EnvVar{
+			{Name: "EXTRA", Value: "1"},
+			{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+		},
+		ExtraVolumes: []corev1.Volume{
+			{Name: "root", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}},
+			{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		},
+		ExtraVolumeMounts: []corev1.VolumeMount{{Name: "root", MountPath: "/rootfs"}, {Name: "scratch", MountPath: "/scratch"}},
+	}
+
+	cluster := spec.DeepCopy()
+	Apply(cluster, "main", o, false)
+	if len(cluster.Volumes) != 2 || len(cluster.Containers[0].Env) != 2 || len(cluster.Containers[0].VolumeMounts) != 2 {
+		t.Fatalf("expected a cluster pool to keep every entry, got %+v", cluster)
+	}
+
+	Apply(&spec, "main", o, true)
+	main := spec.Containers[0]
+	if len(main.Env) != 1 || main.Env[0].Name != "EXTRA" {
+		t.Fatalf("expected valueFrom env to be dropped, got %+v", main.Env)
+	}
+	if len(spec.Volumes) != 1 || spec.Volumes[0].Name != "scratch" {
+		t.Fatalf("expected the host path volume to be dropped, got %+v", spec.Volumes)
+	}
+	if len(main.VolumeMounts) != 1 || main.VolumeMounts[0].Name != "scratch" {
+		t.Fatalf("expected the mount of the dropped volume to go too, got %+v", main.VolumeMounts)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/ops"
+)
+
+const clusterRolePrefix = "d8:gpu-control-plane:"
+
+// Component is a per-pool workload that runs under its own ServiceAccount.
+type Component struct {
+	// Name prefixes the per-pool objects and names the shared ServiceAccount used with external RBAC.
+	Name string
+	// Rules are the only API permissions the component pods get.
+	Rules []rbacv1.PolicyRule
+}
+
+var (
+	// DevicePlugin reads its node to pick the per-node configuration.
+	DevicePlugin = Component{Name: "nvidia-device-plugin", Rules: nodeRules("get", "list", "watch")}
+	// MIGManager watches its node for the requested MIG layout and relabels it with the applied state.
+	MIGManager = Component{Name: "nvidia-mig-manager", Rules: nodeRules("get", "list", "watch", "update", "patch")}
+	// Validator checks the pool resource in the node allocatable; WITH_WORKLOAD is off, so it never creates pods.
+	Validator = Component{Name: "nvidia-operator-validator", Rules: nodeRules("get")}
+)
+
+func nodeRules(verbs ...string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: verbs}}
+}
+
+// ObjectName returns the name of the ServiceAccount rendered for the component of the pool.
+func ObjectName(c Component, poolName string) string {
+	return fmt.Sprintf("%s-%s", c.Name, poolName)
+}
+
+// ClusterRoleName returns the name of the ClusterRole and ClusterRoleBinding rendered for the component of the pool.
+func ClusterRoleName(c Component, poolName string) string {
+	return clusterRolePrefix + ObjectName(c, poolName)
+}
+
+// ServiceAccountName returns the ServiceAccount the component pods of the pool run as.
+func ServiceAccountName(d deps.Deps, c Component, pool *v1alpha1.GPUPool) string {
+	if d.Config.ExternalRBAC {
+		return c.Name
+	}
+	return ObjectName(c, pool.Name)
+}
+
+// Objects renders the ServiceAccount, ClusterRole and ClusterRoleBinding of the component for the pool. Nodes are
+// cluster-scoped, so the rules need a ClusterRole even though the pods live in the module namespace.
+func Objects(d deps.Deps, c Component, pool *v1alpha1.GPUPool) (*corev1.ServiceAccount, *rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
+	labels := map[string]string{
+		"app":  c.Name,
+		"pool": pool.Name,
+	}
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ObjectName(c, pool.Name),
+			Namespace: d.Config.Namespace,
+			Labels:    labels,
+		},
+	}
+	roleName := ClusterRoleName(c, pool.Name)
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: roleName, Labels: labels},
+		Rules:      c.Rules,
+	}
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: roleName, Labels: labels},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     roleName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      sa.Name,
+			Namespace: sa.Namespace,
+		}},
+	}
+	return sa, role, binding
+}
+
+// Reconcile ensures the RBAC of the component; it does nothing when RBAC is managed outside the controller.
+func Reconcile(ctx context.Context, d deps.Deps, c Component, pool *v1alpha1.GPUPool) error {
+	if d.Config.ExternalRBAC {
+		return nil
+	}
+	sa, role, binding := Objects(d, c, pool)
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, sa, pool); err != nil {
+		return fmt.Errorf("reconcile %s ServiceAccount: %w", c.Name, err)
+	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, role, pool); err != nil {
+		return fmt.Errorf("reconcile %s ClusterRole: %w", c.Name, err)
+	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, binding, pool); err != nil {
+		return fmt.Errorf("reconcile %s ClusterRoleBinding: %w", c.Name, err)
+	}
+	return nil
+}
+
+// Delete removes the RBAC rendered for the component of the pool. Cluster-scoped objects of namespaced pools carry
+// no owner reference, so this is their only cleanup.
+func Delete(ctx context.Context, cl client.Client, namespace string, c Component, poolName string) error {
+	roleName := ClusterRoleName(c, poolName)
+	for _, obj := range []client.Object{
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: roleName}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: roleName}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: ObjectName(c, poolName), Namespace: namespace}},
+	} {
+		if err := commonobject.DeleteObject(ctx, cl, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
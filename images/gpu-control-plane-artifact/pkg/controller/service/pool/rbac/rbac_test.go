@@ -0,0 +1,127 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+)
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func TestComponentRules(t *testing.T) {
+	cases := map[string]struct {
+		component Component
+		verbs     []string
+	}{
+		"device-plugin": {component: DevicePlugin, verbs: []string{"get", "list", "watch"}},
+		"mig-manager":   {component: MIGManager, verbs: []string{"get", "list", "watch", "update", "patch"}},
+		"validator":     {component: Validator, verbs: []string{"get"}},
+	}
+	for name, tc := range cases {
+		want := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: tc.verbs}}
+		_, role, _ := Objects(deps.Deps{Config: config.WorkloadConfig{Namespace: "gpu-ns"}}, tc.component, &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}})
+		if !reflect.DeepEqual(role.Rules, want) {
+			t.Fatalf("%s: unexpected rules %+v", name, role.Rules)
+		}
+	}
+}
+
+func TestServiceAccountName(t *testing.T) {
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}
+	if got := ServiceAccountName(deps.Deps{}, MIGManager, pool); got != "nvidia-mig-manager-alpha" {
+		t.Fatalf("unexpected per-pool ServiceAccount %q", got)
+	}
+	external := deps.Deps{Config: config.WorkloadConfig{ExternalRBAC: true}}
+	if got := ServiceAccountName(external, MIGManager, pool); got != "nvidia-mig-manager" {
+		t.Fatalf("unexpected shared ServiceAccount %q", got)
+	}
+}
+
+func TestReconcileAndDelete(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(newScheme()).Build()
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", UID: "alpha-uid"}}
+	d := deps.Deps{Client: cl, Config: config.WorkloadConfig{Namespace: "gpu-ns"}}
+
+	if err := Reconcile(ctx, d, Validator, pool); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-operator-validator-alpha"}, sa); err != nil {
+		t.Fatalf("get ServiceAccount: %v", err)
+	}
+	if len(sa.OwnerReferences) != 1 || sa.OwnerReferences[0].Name != "alpha" {
+		t.Fatalf("expected pool owner on ServiceAccount, got %+v", sa.OwnerReferences)
+	}
+	binding := &rbacv1.ClusterRoleBinding{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "d8:gpu-control-plane:nvidia-operator-validator-alpha"}, binding); err != nil {
+		t.Fatalf("get ClusterRoleBinding: %v", err)
+	}
+	if binding.RoleRef.Kind != "ClusterRole" || binding.RoleRef.Name != "d8:gpu-control-plane:nvidia-operator-validator-alpha" {
+		t.Fatalf("unexpected roleRef %+v", binding.RoleRef)
+	}
+
+	if err := Delete(ctx, cl, "gpu-ns", Validator, "alpha"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected ServiceAccount to be deleted, got %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(binding), &rbacv1.ClusterRoleBinding{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected ClusterRoleBinding to be deleted, got %v", err)
+	}
+	if err := cl.Get(ctx, client.ObjectKey{Name: binding.Name}, &rbacv1.ClusterRole{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected ClusterRole to be deleted, got %v", err)
+	}
+	if err := Delete(ctx, cl, "gpu-ns", Validator, "alpha"); err != nil {
+		t.Fatalf("Delete of missing objects must succeed: %v", err)
+	}
+}
+
+func TestReconcileSkipsExternalRBAC(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(newScheme()).Build()
+	d := deps.Deps{Client: cl, Config: config.WorkloadConfig{Namespace: "gpu-ns", ExternalRBAC: true}}
+	if err := Reconcile(ctx, d, DevicePlugin, &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	list := &corev1.ServiceAccountList{}
+	if err := cl.List(ctx, list); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Fatalf("expected no ServiceAccounts with external RBAC, got %d", len(list.Items))
+	}
+}
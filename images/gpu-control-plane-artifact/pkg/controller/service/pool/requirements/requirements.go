@@ -0,0 +1,163 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requirements
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/version"
+)
+
+const (
+	// ConditionNotMet is True while some selected devices are excluded from capacity by spec.requirements.
+	ConditionNotMet = "PoolRequirementsNotMet"
+
+	reasonNotMet = "RequirementsNotMet"
+	reasonMet    = "RequirementsMet"
+
+	MinDriverVersion     = "minDriverVersion"
+	MinComputeCapability = "minComputeCapability"
+	MinMemoryMiB         = "minMemoryMiB"
+	RequiredPrecisions   = "requiredPrecisions"
+
+	// maxListedDevices bounds the device names put into the condition message.
+	maxListedDevices = 10
+)
+
+// Unmet returns the requirements the device does not satisfy. Unknown device data (no reported driver,
+// memory or compute capability) fails the corresponding requirement: the pool cannot vouch for it.
+func Unmet(req *v1alpha1.GPUPoolRequirements, dev *v1alpha1.GPUDevice) []string {
+	if req == nil || dev == nil {
+		return nil
+	}
+
+	var unmet []string
+	if want := strings.TrimSpace(req.MinDriverVersion); want != "" && !atLeast(dev.Status.DriverVersion, want) {
+		unmet = append(unmet, MinDriverVersion)
+	}
+	if want := strings.TrimSpace(req.MinComputeCapability); want != "" && !atLeast(dev.Status.Hardware.ComputeCapability, want) {
+		unmet = append(unmet, MinComputeCapability)
+	}
+	if req.MinMemoryMiB > 0 && dev.Status.Hardware.MemoryMiB < req.MinMemoryMiB {
+		unmet = append(unmet, MinMemoryMiB)
+	}
+	if !hasPrecisions(dev.Status.Hardware.Precision, req.RequiredPrecisions) {
+		unmet = append(unmet, RequiredPrecisions)
+	}
+	return unmet
+}
+
+func atLeast(have, want string) bool {
+	if strings.TrimSpace(have) == "" {
+		return false
+	}
+	ok, err := version.AtLeast(have, want)
+	return err == nil && ok
+}
+
+func hasPrecisions(supported, required []string) bool {
+	if len(required) == 0 {
+		return true
+	}
+	have := make(map[string]struct{}, len(supported))
+	for _, p := range supported {
+		have[strings.ToLower(strings.TrimSpace(p))] = struct{}{}
+	}
+	for _, p := range required {
+		name := strings.ToLower(strings.TrimSpace(p))
+		if name == "" {
+			continue
+		}
+		if _, ok := have[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Report accumulates devices excluded by pool requirements during a capacity pass.
+type Report struct {
+	devices []string
+	counts  map[string]int
+}
+
+// Exclude records a device together with the requirements it failed.
+func (r *Report) Exclude(name string, unmet []string) {
+	if r.counts == nil {
+		r.counts = make(map[string]int)
+	}
+	r.devices = append(r.devices, name)
+	for _, req := range unmet {
+		r.counts[req]++
+	}
+}
+
+// Excluded returns the number of devices recorded.
+func (r *Report) Excluded() int {
+	return len(r.devices)
+}
+
+// SetCondition publishes the report as the PoolRequirementsNotMet condition. Pools without requirements
+// carry no condition at all.
+func SetCondition(pool *v1alpha1.GPUPool, report Report) {
+	if pool.Spec.Requirements == nil {
+		meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionNotMet)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:               ConditionNotMet,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonMet,
+		Message:            "all selected devices meet pool requirements",
+		ObservedGeneration: pool.Generation,
+	}
+	if report.Excluded() > 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = reasonNotMet
+		cond.Message = report.message()
+	}
+	meta.SetStatusCondition(&pool.Status.Conditions, cond)
+}
+
+func (r *Report) message() string {
+	keys := make([]string, 0, len(r.counts))
+	for key := range r.counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	counts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		counts = append(counts, fmt.Sprintf("%s=%d", key, r.counts[key]))
+	}
+
+	devices := append([]string(nil), r.devices...)
+	sort.Strings(devices)
+	listed := devices
+	if len(listed) > maxListedDevices {
+		listed = listed[:maxListedDevices]
+	}
+	msg := fmt.Sprintf("%d device(s) excluded from capacity (%s): %s", len(devices), strings.Join(counts, ", "), strings.Join(listed, ", "))
+	if rest := len(devices) - len(listed); rest > 0 {
+		msg += fmt.Sprintf(" and %d more", rest)
+	}
+	return msg
+}
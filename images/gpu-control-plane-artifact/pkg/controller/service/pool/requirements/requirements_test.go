@@ -0,0 +1,131 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requirements
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func a100() *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "a100"},
+		Status: v1alpha1.GPUDeviceStatus{
+			DriverVersion: "535.104.05",
+			Hardware: v1alpha1.GPUDeviceHardware{
+				MemoryMiB:         40960,
+				ComputeCapability: "8.0",
+				Precision:         []string{"bf16", "fp16", "fp32", "fp64"},
+			},
+		},
+	}
+}
+
+func TestUnmet(t *testing.T) {
+	unknown := &v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{Name: "unknown"}}
+
+	tests := []struct {
+		name string
+		req  *v1alpha1.GPUPoolRequirements
+		dev  *v1alpha1.GPUDevice
+		want []string
+	}{
+		{name: "no requirements", req: nil, dev: a100()},
+		{name: "empty requirements", req: &v1alpha1.GPUPoolRequirements{}, dev: unknown},
+		{name: "driver met", req: &v1alpha1.GPUPoolRequirements{MinDriverVersion: "535.54.03"}, dev: a100()},
+		{name: "driver too old", req: &v1alpha1.GPUPoolRequirements{MinDriverVersion: "550"}, dev: a100(), want: []string{MinDriverVersion}},
+		{name: "driver unknown", req: &v1alpha1.GPUPoolRequirements{MinDriverVersion: "470"}, dev: unknown, want: []string{MinDriverVersion}},
+		{name: "compute met", req: &v1alpha1.GPUPoolRequirements{MinComputeCapability: "7.5"}, dev: a100()},
+		{name: "compute too low", req: &v1alpha1.GPUPoolRequirements{MinComputeCapability: "9.0"}, dev: a100(), want: []string{MinComputeCapability}},
+		{name: "compute unknown", req: &v1alpha1.GPUPoolRequirements{MinComputeCapability: "7.0"}, dev: unknown, want: []string{MinComputeCapability}},
+		{name: "memory met", req: &v1alpha1.GPUPoolRequirements{MinMemoryMiB: 40960}, dev: a100()},
+		{name: "memory too small", req: &v1alpha1.GPUPoolRequirements{MinMemoryMiB: 81920}, dev: a100(), want: []string{MinMemoryMiB}},
+		{name: "precisions met case-insensitively", req: &v1alpha1.GPUPoolRequirements{RequiredPrecisions: []string{"BF16", " fp64"}}, dev: a100()},
+		{name: "precision missing", req: &v1alpha1.GPUPoolRequirements{RequiredPrecisions: []string{"fp16", "fp8"}}, dev: a100(), want: []string{RequiredPrecisions}},
+		{
+			name: "combined",
+			req: &v1alpha1.GPUPoolRequirements{
+				MinDriverVersion:     "550.54.15",
+				MinComputeCapability: "8.0",
+				MinMemoryMiB:         81920,
+				RequiredPrecisions:   []string{"fp8"},
+			},
+			dev:  a100(),
+			want: []string{MinDriverVersion, MinMemoryMiB, RequiredPrecisions},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unmet(tt.req, tt.dev); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Unmet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetConditionRemovedWithoutRequirements(t *testing.T) {
+	pool := &v1alpha1.GPUPool{}
+	pool.Status.Conditions = []metav1.Condition{{Type: ConditionNotMet, Status: metav1.ConditionTrue, Reason: reasonNotMet}}
+
+	SetCondition(pool, Report{})
+
+	if apimeta.FindStatusCondition(pool.Status.Conditions, ConditionNotMet) != nil {
+		t.Fatalf("expected condition to be removed, got %+v", pool.Status.Conditions)
+	}
+}
+
+func TestSetConditionAllDevicesMeet(t *testing.T) {
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	pool.Spec.Requirements = &v1alpha1.GPUPoolRequirements{MinMemoryMiB: 1}
+
+	SetCondition(pool, Report{})
+
+	cond := apimeta.FindStatusCondition(pool.Status.Conditions, ConditionNotMet)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != reasonMet || cond.ObservedGeneration != 2 {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+}
+
+func TestSetConditionListsBoundedDevices(t *testing.T) {
+	pool := &v1alpha1.GPUPool{}
+	pool.Spec.Requirements = &v1alpha1.GPUPoolRequirements{MinDriverVersion: "550", MinMemoryMiB: 81920}
+
+	var report Report
+	for i := maxListedDevices + 2; i > 0; i-- {
+		report.Exclude(fmt.Sprintf("gpu-%02d", i), []string{MinDriverVersion})
+	}
+	report.Exclude("gpu-00", []string{MinDriverVersion, MinMemoryMiB})
+
+	SetCondition(pool, report)
+
+	cond := apimeta.FindStatusCondition(pool.Status.Conditions, ConditionNotMet)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonNotMet {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+	if !strings.HasPrefix(cond.Message, "13 device(s) excluded from capacity (minDriverVersion=13, minMemoryMiB=1): gpu-00, gpu-01,") {
+		t.Fatalf("unexpected message prefix %q", cond.Message)
+	}
+	if !strings.HasSuffix(cond.Message, "gpu-09 and 3 more") {
+		t.Fatalf("expected the device list to be bounded, got %q", cond.Message)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcename
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/names"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
+)
+
+const (
+	// ConditionResourceNameConflict is True while the pool is not rendered because an older pool advertises the
+	// same extended resource name.
+	ConditionResourceNameConflict = "ResourceNameConflict"
+	// ReasonResourceNameInUse is set on ConditionResourceNameConflict.
+	ReasonResourceNameInUse = "ResourceNameInUse"
+)
+
+// ConflictHandler keeps GPUPools that resolve to the same extended resource name from rendering device plugins
+// that would advertise it side by side. The oldest pool keeps the name; every newer one is stopped before device
+// selection until the older pool is gone. Pools whose nodes do not overlap may share the name when the
+// scheduling.sharedPoolNames setting is on.
+type ConflictHandler struct {
+	client   client.Client
+	store    *moduleconfig.ModuleConfigStore
+	recorder eventrecord.EventRecorderLogger
+}
+
+func NewConflictHandler(c client.Client, store *moduleconfig.ModuleConfigStore) *ConflictHandler {
+	return &ConflictHandler{client: c, store: store}
+}
+
+// SetRecorder enables the Events naming the pool that holds the resource name.
+func (h *ConflictHandler) SetRecorder(recorder eventrecord.EventRecorderLogger) {
+	h.recorder = recorder
+}
+
+func (h *ConflictHandler) Name() string {
+	return "resource-name"
+}
+
+func (h *ConflictHandler) HandlePool(ctx context.Context, pool *v1alpha1.GPUPool) (reconcile.Result, error) {
+	if h.client == nil || pool.Namespace == "" || pool.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	resourceName := names.PoolResourceName(pool)
+	holder, err := h.holder(ctx, pool, resourceName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	key := MetricPoolName(pool.Namespace, pool.Name)
+	if holder == nil {
+		meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionResourceNameConflict)
+		capmetrics.PoolResourceNameConflictDelete(key)
+		return reconcile.Result{}, nil
+	}
+
+	message := fmt.Sprintf("extended resource %s is already advertised by GPUPool %s/%s; rename the pool to render it", resourceName, holder.Namespace, holder.Name)
+	if !meta.IsStatusConditionTrue(pool.Status.Conditions, ConditionResourceNameConflict) && h.recorder != nil {
+		h.recorder.Event(pool, corev1.EventTypeWarning, ConditionResourceNameConflict, message)
+	}
+	meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+		Type:               ConditionResourceNameConflict,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonResourceNameInUse,
+		Message:            message,
+		ObservedGeneration: pool.Generation,
+	})
+	capmetrics.PoolResourceNameConflictSet(key)
+	return reconcile.Result{}, reconciler.ErrStopHandlerChain
+}
+
+// holder returns the oldest other pool that keeps the resource name from this pool, or nil when there is none.
+func (h *ConflictHandler) holder(ctx context.Context, pool *v1alpha1.GPUPool, resourceName string) (*v1alpha1.GPUPool, error) {
+	list := &v1alpha1.GPUPoolList{}
+	if err := h.client.List(ctx, list, client.MatchingFields{indexer.GPUPoolResourceNameField: resourceName}); err != nil {
+		return nil, fmt.Errorf("list GPUPools by resource name: %w", err)
+	}
+	var older []*v1alpha1.GPUPool
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.UID == pool.UID || (other.Namespace == pool.Namespace && other.Name == pool.Name) {
+			continue
+		}
+		if olderThan(other, pool) {
+			older = append(older, other)
+		}
+	}
+	if len(older) == 0 {
+		return nil, nil
+	}
+	sort.Slice(older, func(i, j int) bool { return olderThan(older[i], older[j]) })
+
+	if h.store == nil || !h.store.Current().Settings.Scheduling.SharedPoolNames {
+		return older[0], nil
+	}
+	nodes := &corev1.NodeList{}
+	if err := h.client.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	for _, other := range older {
+		// Both pools render the same name-keyed device plugin, so only identical resource units can share it.
+		if !reflect.DeepEqual(other.Spec.Resource, pool.Spec.Resource) {
+			return other, nil
+		}
+		if nodesOverlap(nodes.Items, pool, other) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// olderThan orders pools by creation time, breaking ties by namespace so every replica picks the same holder.
+func olderThan(a, b *v1alpha1.GPUPool) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Namespace < b.Namespace
+}
+
+// nodesOverlap reports whether a node matches both pools; a selector that does not parse is treated as
+// matching everything, so a broken spec never unlocks sharing.
+func nodesOverlap(nodes []corev1.Node, a, b *v1alpha1.GPUPool) bool {
+	selA, selB := nodeSelector(a), nodeSelector(b)
+	for i := range nodes {
+		set := labels.Set(nodes[i].Labels)
+		if selA.Matches(set) && selB.Matches(set) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeSelector(pool *v1alpha1.GPUPool) labels.Selector {
+	if pool.Spec.NodeSelector == nil {
+		return labels.Everything()
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pool.Spec.NodeSelector)
+	if err != nil {
+		return labels.Everything()
+	}
+	return selector
+}
+
+// MetricPoolName is the pool label of the per-pool series: namespace/name for GPUPools and the bare name for
+// ClusterGPUPools, so namespaced and cluster pools of the same name stay apart.
+func MetricPoolName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// Forget drops the conflict series of a removed pool.
+func Forget(namespace, name string) {
+	capmetrics.PoolResourceNameConflictDelete(MetricPoolName(namespace, name))
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcename
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/reconciler"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
+)
+
+type fakeRecorderProducer struct {
+	recorder record.EventRecorder
+}
+
+func (p fakeRecorderProducer) GetEventRecorderFor(string) record.EventRecorder {
+	return p.recorder
+}
+
+var created = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newPool(namespace, name string, age time.Duration, nodeSelector map[string]string) *v1alpha1.GPUPool {
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			UID:               types.UID(namespace + "-" + name),
+			Generation:        1,
+			CreationTimestamp: metav1.NewTime(created.Add(-age)),
+		},
+		Spec: v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+	}
+	if nodeSelector != nil {
+		pool.Spec.NodeSelector = &metav1.LabelSelector{MatchLabels: nodeSelector}
+	}
+	return pool
+}
+
+func newNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	obj, field, extract := indexer.IndexGPUPoolByResourceName()
+	return fake.NewClientBuilder().WithScheme(scheme).WithIndex(obj, field, extract).WithObjects(objs...).Build()
+}
+
+func newHandler(cl client.Client, shared bool) (*ConflictHandler, *record.FakeRecorder) {
+	state := moduleconfig.DefaultState()
+	state.Settings.Scheduling.SharedPoolNames = shared
+	h := NewConflictHandler(cl, moduleconfig.NewModuleConfigStore(state))
+	events := record.NewFakeRecorder(8)
+	h.SetRecorder(eventrecord.NewEventRecorderLogger(fakeRecorderProducer{recorder: events}, "test"))
+	return h, events
+}
+
+func conflictGauge(t *testing.T, pool string) bool {
+	t.Helper()
+	families, err := crmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != capmetrics.PoolResourceNameConflict {
+			continue
+		}
+		for _, metric := range family.Metric {
+			for _, label := range metric.Label {
+				if label.GetName() == "pool" && label.GetValue() == pool {
+					return metric.GetGauge().GetValue() == 1
+				}
+			}
+		}
+	}
+	return false
+}
+
+func TestConflictHandlerName(t *testing.T) {
+	if name := NewConflictHandler(nil, nil).Name(); name != "resource-name" {
+		t.Fatalf("unexpected name %q", name)
+	}
+}
+
+func TestConflictHandlerBlocksNewerPool(t *testing.T) {
+	older := newPool("team-a", "pool", time.Hour, nil)
+	newer := newPool("team-b", "pool", 0, nil)
+	cl := newClient(t, older, newer, newNode("node-1", nil))
+	h, events := newHandler(cl, false)
+
+	if _, err := h.HandlePool(context.Background(), newer); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected the newer pool to stop the chain, got %v", err)
+	}
+	cond := meta.FindStatusCondition(newer.Status.Conditions, ConditionResourceNameConflict)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != ReasonResourceNameInUse || !strings.Contains(cond.Message, "team-a/pool") {
+		t.Fatalf("expected a conflict naming the older pool, got %+v", cond)
+	}
+	select {
+	case event := <-events.Events:
+		if !strings.Contains(event, ConditionResourceNameConflict) || !strings.Contains(event, "team-a/pool") {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected an event naming the other pool")
+	}
+	if !conflictGauge(t, "team-b/pool") {
+		t.Fatalf("expected the conflict to be counted")
+	}
+
+	// The condition is refreshed without repeating the event.
+	if _, err := h.HandlePool(context.Background(), newer); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected the conflict to persist, got %v", err)
+	}
+	if len(events.Events) != 0 {
+		t.Fatalf("expected a single event per conflict")
+	}
+
+	// The older pool keeps rendering.
+	if _, err := h.HandlePool(context.Background(), older); err != nil {
+		t.Fatalf("expected the older pool to proceed, got %v", err)
+	}
+	if meta.FindStatusCondition(older.Status.Conditions, ConditionResourceNameConflict) != nil || conflictGauge(t, "team-a/pool") {
+		t.Fatalf("expected no conflict on the older pool")
+	}
+}
+
+func TestConflictHandlerClearsOnResolution(t *testing.T) {
+	older := newPool("team-a", "pool", time.Hour, nil)
+	newer := newPool("team-b", "pool", 0, nil)
+	cl := newClient(t, older, newer)
+	h, _ := newHandler(cl, false)
+
+	if _, err := h.HandlePool(context.Background(), newer); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	// Renaming the older pool recreates it under another name.
+	if err := cl.Delete(context.Background(), older); err != nil {
+		t.Fatalf("delete older pool: %v", err)
+	}
+	if err := cl.Create(context.Background(), newPool("team-a", "pool-renamed", time.Hour, nil)); err != nil {
+		t.Fatalf("create renamed pool: %v", err)
+	}
+
+	if _, err := h.HandlePool(context.Background(), newer); err != nil {
+		t.Fatalf("expected the conflict to be resolved, got %v", err)
+	}
+	if meta.FindStatusCondition(newer.Status.Conditions, ConditionResourceNameConflict) != nil {
+		t.Fatalf("expected the condition to be cleared")
+	}
+	if conflictGauge(t, "team-b/pool") {
+		t.Fatalf("expected the conflict series to be removed")
+	}
+}
+
+func TestConflictHandlerDisjointNodes(t *testing.T) {
+	older := newPool("team-a", "pool", time.Hour, map[string]string{"team": "a"})
+	newer := newPool("team-b", "pool", 0, map[string]string{"team": "b"})
+	nodes := []client.Object{newNode("node-a", map[string]string{"team": "a"}), newNode("node-b", map[string]string{"team": "b"})}
+
+	// Without the setting disjoint pools still conflict.
+	h, _ := newHandler(newClient(t, append(nodes, older, newer)...), false)
+	if _, err := h.HandlePool(context.Background(), newer.DeepCopy()); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected a conflict without sharedPoolNames, got %v", err)
+	}
+
+	h, _ = newHandler(newClient(t, append(nodes, older, newer)...), true)
+	if _, err := h.HandlePool(context.Background(), newer.DeepCopy()); err != nil {
+		t.Fatalf("expected disjoint pools to share the name, got %v", err)
+	}
+
+	// A pool without a node selector overlaps with every other pool.
+	overlapping := newPool("team-b", "pool", 0, nil)
+	h, _ = newHandler(newClient(t, append(nodes, older, overlapping)...), true)
+	if _, err := h.HandlePool(context.Background(), overlapping); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected overlapping pools to conflict, got %v", err)
+	}
+
+	// Different resource units cannot share the rendered device plugin.
+	mig := newer.DeepCopy()
+	mig.Spec.Resource = v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "1g.10gb"}
+	h, _ = newHandler(newClient(t, append(nodes, older, mig)...), true)
+	if _, err := h.HandlePool(context.Background(), mig); !errors.Is(err, reconciler.ErrStopHandlerChain) {
+		t.Fatalf("expected different resource units to conflict, got %v", err)
+	}
+}
+
+func TestConflictHandlerIgnoresClusterAndDeletingPools(t *testing.T) {
+	h, _ := newHandler(newClient(t), false)
+	if _, err := h.HandlePool(context.Background(), &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "pool"}}); err != nil {
+		t.Fatalf("expected cluster pools to be skipped, got %v", err)
+	}
+	deleting := newPool("team-b", "pool", 0, nil)
+	deleting.DeletionTimestamp = &metav1.Time{Time: created}
+	if _, err := h.HandlePool(context.Background(), deleting); err != nil {
+		t.Fatalf("expected deleting pools to be skipped, got %v", err)
+	}
+}
+
+func TestMetricPoolName(t *testing.T) {
+	if got := MetricPoolName("team-a", "pool"); got != "team-a/pool" {
+		t.Fatalf("expected namespaced pool label, got %q", got)
+	}
+	if got := MetricPoolName("", "pool"); got != "pool" {
+		t.Fatalf("expected bare cluster pool label, got %q", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runtimes picks the container runtime profile rendered into the workloads of a pool.
+//
+// Runtime-specific paths are parameterized per pool: a pool whose nodes all run the same runtime gets its
+// profile, and a pool spanning several runtimes is flagged and keeps the containerd defaults.
+package runtimes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+const (
+	// ConditionContainerRuntimeMixed is True while the nodes of a pool run different container runtimes, so no
+	// single runtime profile fits all of them.
+	ConditionContainerRuntimeMixed = "ContainerRuntimeMixed"
+	// ReasonMultipleRuntimes is set on ConditionContainerRuntimeMixed.
+	ReasonMultipleRuntimes = "MultipleRuntimes"
+)
+
+// Resolve returns the runtime profile for the workloads of the pool. It sets ConditionContainerRuntimeMixed when the
+// pool nodes run different runtimes and removes it otherwise; nodes that report no runtime are ignored.
+func Resolve(ctx context.Context, c client.Client, pool *v1alpha1.GPUPool) (containerruntime.Profile, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes, client.MatchingLabels{poolcommon.PoolLabelKey(pool): pool.Name}); err != nil {
+		return containerruntime.Profile{}, fmt.Errorf("list pool nodes: %w", err)
+	}
+	byRuntime := map[containerruntime.Name][]string{}
+	for i := range nodes.Items {
+		if detected := containerruntime.FromNode(&nodes.Items[i]); detected.Name != "" {
+			byRuntime[detected.Name] = append(byRuntime[detected.Name], nodes.Items[i].Name)
+		}
+	}
+
+	if len(byRuntime) <= 1 {
+		meta.RemoveStatusCondition(&pool.Status.Conditions, ConditionContainerRuntimeMixed)
+		for name := range byRuntime {
+			return containerruntime.ProfileFor(name), nil
+		}
+		return containerruntime.DefaultProfile(), nil
+	}
+
+	parts := make([]string, 0, len(byRuntime))
+	for name, names := range byRuntime {
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("%s on %s", name, strings.Join(names, ", ")))
+	}
+	sort.Strings(parts)
+	meta.SetStatusCondition(&pool.Status.Conditions, metav1.Condition{
+		Type:   ConditionContainerRuntimeMixed,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonMultipleRuntimes,
+		Message: fmt.Sprintf("pool nodes run different container runtimes (%s); workloads are rendered with the %s paths",
+			strings.Join(parts, "; "), containerruntime.DefaultProfile().Runtime),
+		ObservedGeneration: pool.Generation,
+	})
+	return containerruntime.DefaultProfile(), nil
+}
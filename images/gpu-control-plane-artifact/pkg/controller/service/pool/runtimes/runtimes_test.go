@@ -0,0 +1,101 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/containerruntime"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+)
+
+func poolNode(pool *v1alpha1.GPUPool, name, runtimeVersion string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{poolcommon.PoolLabelKey(pool): pool.Name}}}
+	node.Status.NodeInfo.ContainerRuntimeVersion = runtimeVersion
+	return node
+}
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestResolveHomogeneousPool(t *testing.T) {
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}
+	other := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "beta"}}
+	pool.Status.Conditions = []metav1.Condition{{Type: ConditionContainerRuntimeMixed, Status: metav1.ConditionTrue, Reason: ReasonMultipleRuntimes}}
+	c := newClient(t,
+		poolNode(pool, "crio-a", "cri-o://1.28.1"),
+		poolNode(pool, "crio-b", "cri-o://1.29.0"),
+		poolNode(pool, "unreported", ""),
+		poolNode(other, "containerd-a", "containerd://1.7.2"),
+	)
+
+	profile, err := Resolve(context.Background(), c, pool)
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if profile != containerruntime.ProfileFor(containerruntime.CRIO) {
+		t.Fatalf("expected the cri-o profile, got %+v", profile)
+	}
+	if meta.FindStatusCondition(pool.Status.Conditions, ConditionContainerRuntimeMixed) != nil {
+		t.Fatalf("expected the mixed condition to be cleared, got %+v", pool.Status.Conditions)
+	}
+}
+
+func TestResolveMixedPool(t *testing.T) {
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Generation: 3}}
+	c := newClient(t,
+		poolNode(pool, "gpu-2", "cri-o://1.28.1"),
+		poolNode(pool, "gpu-1", "containerd://1.7.2"),
+	)
+
+	profile, err := Resolve(context.Background(), c, pool)
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if profile != containerruntime.DefaultProfile() {
+		t.Fatalf("expected a mixed pool to keep the default profile, got %+v", profile)
+	}
+	cond := meta.FindStatusCondition(pool.Status.Conditions, ConditionContainerRuntimeMixed)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != ReasonMultipleRuntimes || cond.ObservedGeneration != 3 {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+	if !strings.Contains(cond.Message, "containerd on gpu-1; cri-o on gpu-2") {
+		t.Fatalf("expected the message to name the runtimes and nodes, got %q", cond.Message)
+	}
+}
+
+func TestResolveEmptyPool(t *testing.T) {
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}
+	profile, err := Resolve(context.Background(), newClient(t), pool)
+	if err != nil || profile != containerruntime.DefaultProfile() {
+		t.Fatalf("expected the default profile, got %+v, %v", profile, err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -31,19 +32,32 @@ import (
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/objsize"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/devicestate"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/devicerefs"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/requirements"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/eventrecord"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/hardware/nvidia/migplacement"
 )
 
-// SelectionSyncHandler picks devices matching the pool selectors and updates pool status.
+// SelectionSyncHandler picks devices matching the pool selectors, or the devices listed in spec.devices,
+// and updates pool status.
 type SelectionSyncHandler struct {
-	log    logr.Logger
-	client client.Client
+	log      logr.Logger
+	client   client.Client
+	recorder eventrecord.EventRecorderLogger
 }
 
 func NewSelectionSyncHandler(log logr.Logger, c client.Client) *SelectionSyncHandler {
 	return &SelectionSyncHandler{log: log, client: c}
 }
 
+// SetRecorder enables Events for the device state transitions made by the handler.
+func (h *SelectionSyncHandler) SetRecorder(recorder eventrecord.EventRecorderLogger) {
+	h.recorder = recorder
+}
+
 func (h *SelectionSyncHandler) Name() string {
 	return "selection-sync"
 }
@@ -73,10 +87,22 @@ func (h *SelectionSyncHandler) HandlePool(ctx context.Context, pool *v1alpha1.GP
 			return reconcile.Result{}, apierrors.NewBadRequest("invalid nodeSelector")
 		}
 	}
-	// Collect devices explicitly assigned to this pool via annotation and matching selectors.
+	// Collect devices explicitly assigned to this pool via annotation and matching selectors,
+	// or, for a pool with spec.devices, the listed devices regardless of annotations and labels.
+	candidates := assignedDevices.Items
+	explicit := devicerefs.Explicit(pool)
+	var resolved devicerefs.Resolution
+	if explicit {
+		var err error
+		resolved, err = devicerefs.Resolve(ctx, h.client, pool)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		candidates = resolved.Devices
+	}
 	assigned := make([]v1alpha1.GPUDevice, 0)
-	for i := range assignedDevices.Items {
-		dev := assignedDevices.Items[i]
+	for i := range candidates {
+		dev := candidates[i]
 		if poolcommon.IsDeviceIgnored(&dev) {
 			continue
 		}
@@ -87,7 +113,9 @@ func (h *SelectionSyncHandler) HandlePool(ctx context.Context, pool *v1alpha1.GP
 		}
 		assigned = append(assigned, dev)
 	}
-	assigned = poolcommon.FilterDevices(assigned, pool.Spec.DeviceSelector)
+	if !explicit {
+		assigned = poolcommon.FilterDevices(assigned, pool.Spec.DeviceSelector)
+	}
 
 	// Group by node and sort deterministically to apply maxDevicesPerNode.
 	byNode := map[string][]v1alpha1.GPUDevice{}
@@ -126,7 +154,11 @@ func (h *SelectionSyncHandler) HandlePool(ctx context.Context, pool *v1alpha1.GP
 	var (
 		totalUnits int32
 		toUpdate   []v1alpha1.GPUDevice
+		unmet      requirements.Report
+		placements map[string]int32
+		byProfile  map[string]int32
 	)
+	migPool := pool.Spec.Resource.Unit == "MIG"
 
 	for _, devs := range byNode {
 		var takenOnNode int32
@@ -135,13 +167,40 @@ func (h *SelectionSyncHandler) HandlePool(ctx context.Context, pool *v1alpha1.GP
 				toUpdate = append(toUpdate, dev)
 			}
 
+			// Devices of a node that stayed NotReady too long keep their assignment so they come
+			// back on recovery, but they no longer contribute capacity.
+			if poolcommon.IsDeviceUnreachable(&dev) {
+				continue
+			}
+			// Likewise for devices of a node running a driver, toolkit or kernel combination a Block rule refuses.
+			if poolcommon.IsDeviceCompatibilityBlocked(&dev) {
+				continue
+			}
+			// Devices short of spec.requirements stay assigned but add no capacity until they are upgraded.
+			if failed := requirements.Unmet(pool.Spec.Requirements, &dev); len(failed) > 0 {
+				unmet.Exclude(dev.Name, failed)
+				continue
+			}
 			// Pool capacity is a static upper bound derived from assignment annotations,
 			// not a real-time availability signal. Runtime readiness (validator/device-plugin)
 			// is tracked separately via device states and pool conditions.
 			if pool.Spec.Resource.MaxDevicesPerNode != nil && takenOnNode >= *pool.Spec.Resource.MaxDevicesPerNode {
 				continue
 			}
-			units := h.unitsForDevice(dev, pool)
+			var units int32
+			if migPool {
+				placements = addPlacements(placements, dev)
+				perProfile, err := migUnitsForDevice(dev, pool)
+				if err != nil {
+					h.log.V(1).Info("device cannot hold the pool MIG layout", "pool", pool.Name, "device", dev.Name, "reason", err.Error())
+				}
+				units = sumUnits(perProfile)
+				if units > 0 {
+					byProfile = addProfileUnits(byProfile, perProfile)
+				}
+			} else {
+				units = h.unitsForDevice(dev, pool)
+			}
 			if units <= 0 {
 				continue
 			}
@@ -150,21 +209,43 @@ func (h *SelectionSyncHandler) HandlePool(ctx context.Context, pool *v1alpha1.GP
 		}
 	}
 
-	// Unassign devices that still point to this pool but no longer carry the assignment annotation.
+	// Unassign devices that still point to this pool but no longer carry the assignment annotation,
+	// or are no longer listed in spec.devices.
+	member := func(dev *v1alpha1.GPUDevice) bool { return dev.Annotations[assignmentKey] == pool.Name }
+	if explicit {
+		listed := make(map[string]struct{}, len(resolved.Devices))
+		for i := range resolved.Devices {
+			listed[resolved.Devices[i].Name] = struct{}{}
+		}
+		member = func(dev *v1alpha1.GPUDevice) bool {
+			_, ok := listed[dev.Name]
+			return ok
+		}
+	}
 	for i := range poolRefDevices.Items {
 		dev := &poolRefDevices.Items[i]
-		if dev.Annotations[assignmentKey] == pool.Name {
+		if member(dev) {
 			continue
 		}
 		if !poolcommon.PoolRefMatchesPool(pool, dev.Status.PoolRef) {
 			continue
 		}
-		if err := h.clearDevicePool(ctx, dev.Name, pool.Name, pool.Namespace, assignmentKey); err != nil {
+		var err error
+		if explicit {
+			err = h.releaseDevice(ctx, dev.Name, pool.Name, pool.Namespace, member)
+		} else {
+			err = h.clearDevicePool(ctx, dev.Name, pool.Name, pool.Namespace, assignmentKey)
+		}
+		if err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
 	pool.Status.Capacity.Total = totalUnits
+	pool.Status.Capacity.PlacementsAvailable = placements
+	pool.Status.Capacity.ByProfile = byProfile
+	requirements.SetCondition(pool, unmet)
+	devicerefs.SetCondition(pool, resolved)
 
 	for i := range toUpdate {
 		dev := toUpdate[i]
@@ -173,7 +254,7 @@ func (h *SelectionSyncHandler) HandlePool(ctx context.Context, pool *v1alpha1.GP
 		}
 	}
 
-	h.log.V(2).Info("synchronised pool selection", "pool", pool.Name, "assignedDevices", len(assigned), "capacity", totalUnits)
+	h.log.V(2).Info("synchronised pool selection", "pool", pool.Name, "assignedDevices", len(assigned), "capacity", totalUnits, "requirementsNotMet", unmet.Excluded())
 	return reconcile.Result{}, nil
 }
 
@@ -185,24 +266,26 @@ func deviceSortKey(dev v1alpha1.GPUDevice) string {
 	return dev.Name
 }
 
+// addPlacements adds the MIG profiles that could still be created on dev. Devices without a known
+// placement table are left out rather than guessed.
+func addPlacements(placements map[string]int32, dev v1alpha1.GPUDevice) map[string]int32 {
+	available, ok := migplacement.ForDevice(dev.Status.Hardware)
+	if !ok {
+		return placements
+	}
+	if placements == nil {
+		placements = make(map[string]int32, len(available))
+	}
+	for profile, count := range available {
+		placements[profile] += count
+	}
+	return placements
+}
+
 func (h *SelectionSyncHandler) unitsForDevice(dev v1alpha1.GPUDevice, pool *v1alpha1.GPUPool) int32 {
 	if pool.Spec.Resource.Unit == "MIG" {
-		if pool.Spec.Resource.MIGProfile == "" {
-			return 0
-		}
-		var profileCount int32
-		for _, t := range dev.Status.Hardware.MIG.Types {
-			if t.Name == pool.Spec.Resource.MIGProfile {
-				profileCount += t.Count
-			}
-		}
-		if profileCount == 0 {
-			return 0
-		}
-		if pool.Spec.Resource.SlicesPerUnit > 0 {
-			return profileCount * pool.Spec.Resource.SlicesPerUnit
-		}
-		return profileCount
+		perProfile, _ := migUnitsForDevice(dev, pool)
+		return sumUnits(perProfile)
 	}
 	if pool.Spec.Resource.SlicesPerUnit > 0 {
 		return pool.Spec.Resource.SlicesPerUnit
@@ -210,6 +293,56 @@ func (h *SelectionSyncHandler) unitsForDevice(dev v1alpha1.GPUDevice, pool *v1al
 	return 1
 }
 
+// migUnitsForDevice returns the capacity a device adds to a MIG pool per profile: the instances of every
+// profile the pool advertises, multiplied by slicesPerUnit. A device whose placement table cannot hold the
+// mixed layout of the pool adds nothing, and the error tells why.
+func migUnitsForDevice(dev v1alpha1.GPUDevice, pool *v1alpha1.GPUPool) (map[string]int32, error) {
+	wanted := poolcommon.MIGLayoutCounts(pool)
+	if wanted == nil {
+		profile := strings.ToLower(strings.TrimSpace(pool.Spec.Resource.MIGProfile))
+		if profile == "" {
+			return nil, nil
+		}
+		wanted = map[string]int32{profile: 0}
+	} else if checked, err := migplacement.CheckLayout(dev.Status.Hardware, wanted); checked && err != nil {
+		return nil, err
+	}
+
+	slices := int32(1)
+	if pool.Spec.Resource.SlicesPerUnit > 0 {
+		slices = pool.Spec.Resource.SlicesPerUnit
+	}
+	var out map[string]int32
+	for _, t := range dev.Status.Hardware.MIG.Types {
+		if _, ok := wanted[t.Name]; !ok || t.Count <= 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]int32, len(wanted))
+		}
+		out[t.Name] += t.Count * slices
+	}
+	return out, nil
+}
+
+func sumUnits(perProfile map[string]int32) int32 {
+	var total int32
+	for _, units := range perProfile {
+		total += units
+	}
+	return total
+}
+
+func addProfileUnits(total, perProfile map[string]int32) map[string]int32 {
+	if total == nil {
+		total = make(map[string]int32, len(perProfile))
+	}
+	for profile, units := range perProfile {
+		total[profile] += units
+	}
+	return total
+}
+
 func needsAssignmentUpdate(dev v1alpha1.GPUDevice, poolName, poolNamespace string) bool {
 	ref := dev.Status.PoolRef
 	if ref == nil || ref.Name != poolName {
@@ -245,17 +378,30 @@ func (h *SelectionSyncHandler) assignDeviceWithRetry(ctx context.Context, name,
 		}
 		current.Status.PoolRef = ref
 		// Do not transition to Assigned without DP validator: Ready -> PendingAssignment.
+		var transition devicestate.Transition
 		if current.Status.State == v1alpha1.GPUDeviceStateReady {
-			current.Status.State = v1alpha1.GPUDeviceStatePendingAssignment
+			transition = devicestate.SetState(current, v1alpha1.GPUDeviceStatePendingAssignment, "PoolSelected", time.Now())
+		}
+		if err := objsize.Guard(current); err != nil {
+			return err
 		}
 		if err := h.client.Status().Patch(ctx, current, client.MergeFrom(orig)); err != nil {
 			return client.IgnoreNotFound(err)
 		}
+		devicestate.Notify(h.recorder, h.log, current, transition)
 		return nil
 	})
 }
 
 func (h *SelectionSyncHandler) clearDevicePool(ctx context.Context, name, poolName, poolNamespace, assignmentKey string) error {
+	return h.releaseDevice(ctx, name, poolName, poolNamespace, func(dev *v1alpha1.GPUDevice) bool {
+		return dev.Annotations[assignmentKey] == poolName
+	})
+}
+
+// releaseDevice clears the poolRef of a device that is no longer a member of the pool. member is re-checked
+// against the latest object so a device that joined again meanwhile keeps its assignment.
+func (h *SelectionSyncHandler) releaseDevice(ctx context.Context, name, poolName, poolNamespace string, member func(*v1alpha1.GPUDevice) bool) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		current := &v1alpha1.GPUDevice{}
 		current, err := commonobject.FetchObject(ctx, client.ObjectKey{Name: name}, h.client, current)
@@ -265,7 +411,7 @@ func (h *SelectionSyncHandler) clearDevicePool(ctx context.Context, name, poolNa
 		if current == nil {
 			return nil
 		}
-		if current.Annotations[assignmentKey] == poolName {
+		if member(current) {
 			return nil
 		}
 		ref := current.Status.PoolRef
@@ -281,14 +427,19 @@ func (h *SelectionSyncHandler) clearDevicePool(ctx context.Context, name, poolNa
 		}
 		orig := current.DeepCopy()
 		current.Status.PoolRef = nil
+		var transition devicestate.Transition
 		if current.Status.State == v1alpha1.GPUDeviceStateAssigned ||
 			current.Status.State == v1alpha1.GPUDeviceStateReserved ||
 			current.Status.State == v1alpha1.GPUDeviceStatePendingAssignment {
-			current.Status.State = v1alpha1.GPUDeviceStateReady
+			transition = devicestate.SetState(current, v1alpha1.GPUDeviceStateReady, "PoolReleased", time.Now())
+		}
+		if err := objsize.Guard(current); err != nil {
+			return err
 		}
 		if err := h.client.Status().Patch(ctx, current, client.MergeFrom(orig)); err != nil {
 			return client.IgnoreNotFound(err)
 		}
+		devicestate.Notify(h.recorder, h.log, current, transition)
 		return nil
 	})
 }
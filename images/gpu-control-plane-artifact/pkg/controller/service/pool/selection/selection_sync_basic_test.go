@@ -15,6 +15,7 @@
 package selection
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
@@ -82,6 +83,48 @@ func TestSelectionSyncUnitsForDevice(t *testing.T) {
 	}
 }
 
+func TestMIGUnitsForDeviceMixedLayout(t *testing.T) {
+	dev := v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{
+		Hardware: v1alpha1.GPUDeviceHardware{
+			Product:   "NVIDIA A100-SXM4-80GB",
+			MemoryMiB: 81920,
+			MIG: v1alpha1.GPUMIGConfig{Capable: true, Types: []v1alpha1.GPUMIGTypeCapacity{
+				{Name: "3g.40gb", Count: 1},
+				{Name: "1g.10gb", Count: 3},
+				{Name: "2g.20gb", Count: 1},
+			}},
+		},
+	}}
+	pool := &v1alpha1.GPUPool{Spec: v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{
+		Unit:          "MIG",
+		SlicesPerUnit: 2,
+		MIGLayout:     []v1alpha1.GPUPoolMIGLayoutEntry{{Profile: "3g.40gb", Count: 1}, {Profile: "1g.10gb", Count: 3}},
+	}}}
+
+	perProfile, err := migUnitsForDevice(dev, pool)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(perProfile, map[string]int32{"3g.40gb": 2, "1g.10gb": 6}) {
+		t.Fatalf("unexpected per-profile units: %v", perProfile)
+	}
+	h := NewSelectionSyncHandler(testr.New(t), nil)
+	if got := h.unitsForDevice(dev, pool); got != 8 {
+		t.Fatalf("expected 8 units, got %d", got)
+	}
+
+	total := addProfileUnits(nil, perProfile)
+	total = addProfileUnits(total, map[string]int32{"1g.10gb": 3})
+	if !reflect.DeepEqual(total, map[string]int32{"3g.40gb": 2, "1g.10gb": 9}) {
+		t.Fatalf("unexpected pool by-profile capacity: %v", total)
+	}
+
+	pool.Spec.Resource.MIGLayout = []v1alpha1.GPUPoolMIGLayoutEntry{{Profile: "3g.40gb", Count: 2}, {Profile: "1g.10gb", Count: 2}}
+	if perProfile, err := migUnitsForDevice(dev, pool); err == nil || perProfile != nil {
+		t.Fatalf("expected an unachievable layout to add no capacity, got %v (%v)", perProfile, err)
+	}
+}
+
 func TestSelectionSyncNeedsAssignmentUpdate(t *testing.T) {
 	dev := v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{}}
 	if !needsAssignmentUpdate(dev, "pool", "ns") {
@@ -16,10 +16,12 @@ package selection
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,6 +29,8 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/devicerefs"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/requirements"
 )
 
 func TestSelectionSyncHandlePoolAssignsAndClears(t *testing.T) {
@@ -197,3 +201,306 @@ func TestSelectionSyncHandlePoolNodeSelectorFiltersNodesAndSkipsZeroUnits(t *tes
 		t.Fatalf("unexpected capacity total: %d", pool.Status.Capacity.Total)
 	}
 }
+
+func TestSelectionSyncHandlePoolExcludesUnreachableDevicesFromCapacity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "ns"},
+		Spec: v1alpha1.GPUPoolSpec{
+			Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1},
+		},
+	}
+
+	reachable := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "reachable",
+			Annotations: map[string]string{poolcommon.NamespacedAssignmentAnnotation: "pool-a"},
+		},
+		Status: v1alpha1.GPUDeviceStatus{NodeName: "node1", State: v1alpha1.GPUDeviceStateReady},
+	}
+	unreachable := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "unreachable",
+			Annotations: map[string]string{poolcommon.NamespacedAssignmentAnnotation: "pool-a"},
+		},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: "node2",
+			State:    v1alpha1.GPUDeviceStateAssigned,
+			PoolRef:  &v1alpha1.GPUPoolReference{Name: "pool-a", Namespace: "ns"},
+			Conditions: []metav1.Condition{{
+				Type:   poolcommon.DeviceConditionNodeUnreachable,
+				Status: metav1.ConditionTrue,
+				Reason: "NodeNotReady",
+			}},
+		},
+	}
+	blocked := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "blocked",
+			Annotations: map[string]string{poolcommon.NamespacedAssignmentAnnotation: "pool-a"},
+		},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: "node3",
+			State:    v1alpha1.GPUDeviceStateReady,
+			Conditions: []metav1.Condition{{
+				Type:   poolcommon.DeviceConditionCompatibilityBlocked,
+				Status: metav1.ConditionTrue,
+				Reason: "CompatibilityBlocked",
+			}},
+		},
+	}
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().
+		WithScheme(scheme)).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}).
+		WithObjects(reachable, unreachable, blocked).
+		Build()
+
+	h := NewSelectionSyncHandler(testr.New(t), cl)
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+
+	if pool.Status.Capacity.Total != 1 {
+		t.Fatalf("expected unreachable and compatibility-blocked devices to be excluded from capacity, got %d", pool.Status.Capacity.Total)
+	}
+
+	kept := &v1alpha1.GPUDevice{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "unreachable"}, kept); err != nil {
+		t.Fatalf("get unreachable: %v", err)
+	}
+	if kept.Status.PoolRef == nil || kept.Status.PoolRef.Name != "pool-a" {
+		t.Fatalf("expected unreachable device to keep its poolRef, got %+v", kept.Status.PoolRef)
+	}
+}
+
+func TestSelectionSyncHandlePoolUsesExplicitDeviceList(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench", Namespace: "ns", Generation: 2},
+		Spec: v1alpha1.GPUPoolSpec{
+			Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1},
+			// The defaulting webhook leaves an empty selector behind; it must not filter a listed pool.
+			DeviceSelector: &v1alpha1.GPUPoolDeviceSelector{},
+			Devices: []v1alpha1.GPUDeviceReference{
+				{UUID: "GPU-a"},
+				{Name: "node2-0"},
+				{UUID: "GPU-missing"},
+			},
+		},
+	}
+	device := func(name, node, uuid string) *v1alpha1.GPUDevice {
+		return &v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1alpha1.GPUDeviceStatus{
+				NodeName: node,
+				State:    v1alpha1.GPUDeviceStateReady,
+				Hardware: v1alpha1.GPUDeviceHardware{UUID: uuid},
+			},
+		}
+	}
+	annotated := device("node1-1", "node1", "GPU-annotated")
+	annotated.Annotations = map[string]string{poolcommon.NamespacedAssignmentAnnotation: "bench"}
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().
+		WithScheme(scheme)).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}).
+		WithObjects(device("node1-0", "node1", "GPU-a"), device("node2-0", "node2", "GPU-b"), annotated).
+		Build()
+
+	h := NewSelectionSyncHandler(testr.New(t), cl)
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+
+	if pool.Status.Capacity.Total != 2 {
+		t.Fatalf("expected the two resolved devices to add capacity, got %d", pool.Status.Capacity.Total)
+	}
+	poolRef := func(name string) *v1alpha1.GPUPoolReference {
+		t.Helper()
+		dev := &v1alpha1.GPUDevice{}
+		if err := cl.Get(context.Background(), client.ObjectKey{Name: name}, dev); err != nil {
+			t.Fatalf("get %s: %v", name, err)
+		}
+		return dev.Status.PoolRef
+	}
+	for _, name := range []string{"node1-0", "node2-0"} {
+		if ref := poolRef(name); ref == nil || ref.Name != "bench" || ref.Namespace != "ns" {
+			t.Fatalf("expected %s to join the pool, got %+v", name, ref)
+		}
+	}
+	if ref := poolRef("node1-1"); ref != nil {
+		t.Fatalf("expected an annotated but unlisted device to stay out of the pool, got %+v", ref)
+	}
+	cond := apimeta.FindStatusCondition(pool.Status.Conditions, devicerefs.ConditionNotFound)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Message != "1 listed device(s) not found: GPU-missing" {
+		t.Fatalf("expected DevicesNotFound=True listing the missing UUID, got %+v", cond)
+	}
+
+	// Dropping a device from the list releases it.
+	pool.Spec.Devices = []v1alpha1.GPUDeviceReference{{UUID: "GPU-a"}}
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if pool.Status.Capacity.Total != 1 {
+		t.Fatalf("expected capacity of the remaining device, got %d", pool.Status.Capacity.Total)
+	}
+	released := &v1alpha1.GPUDevice{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "node2-0"}, released); err != nil {
+		t.Fatalf("get node2-0: %v", err)
+	}
+	if released.Status.PoolRef != nil || released.Status.State != v1alpha1.GPUDeviceStateReady {
+		t.Fatalf("expected the removed device to be released to Ready, got ref=%+v state=%s", released.Status.PoolRef, released.Status.State)
+	}
+	if ref := poolRef("node1-0"); ref == nil || ref.Name != "bench" {
+		t.Fatalf("expected the listed device to stay in the pool, got %+v", ref)
+	}
+	cond = apimeta.FindStatusCondition(pool.Status.Conditions, devicerefs.ConditionNotFound)
+	if cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected DevicesNotFound=False, got %+v", cond)
+	}
+}
+
+func TestSelectionSyncHandlePoolExcludesDevicesBelowRequirements(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "ns", Generation: 3},
+		Spec: v1alpha1.GPUPoolSpec{
+			Resource:     v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1},
+			Requirements: &v1alpha1.GPUPoolRequirements{MinDriverVersion: "535.104.05", MinMemoryMiB: 40000},
+		},
+	}
+	device := func(name, driver string, memory int32) *v1alpha1.GPUDevice {
+		return &v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{poolcommon.NamespacedAssignmentAnnotation: "pool-a"},
+			},
+			Status: v1alpha1.GPUDeviceStatus{
+				NodeName:      "node1",
+				State:         v1alpha1.GPUDeviceStateReady,
+				DriverVersion: driver,
+				Hardware:      v1alpha1.GPUDeviceHardware{MemoryMiB: memory},
+			},
+		}
+	}
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().
+		WithScheme(scheme)).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}).
+		WithObjects(
+			device("fit", "550.54.15", 81920),
+			device("old-driver", "470.82.01", 81920),
+			device("small", "535.104.05", 16384),
+		).
+		Build()
+
+	h := NewSelectionSyncHandler(testr.New(t), cl)
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+
+	if pool.Status.Capacity.Total != 1 {
+		t.Fatalf("expected only the conforming device to add capacity, got %d", pool.Status.Capacity.Total)
+	}
+	cond := apimeta.FindStatusCondition(pool.Status.Conditions, requirements.ConditionNotMet)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != 3 {
+		t.Fatalf("expected PoolRequirementsNotMet=True, got %+v", cond)
+	}
+	want := "2 device(s) excluded from capacity (minDriverVersion=1, minMemoryMiB=1): old-driver, small"
+	if cond.Message != want {
+		t.Fatalf("unexpected condition message %q", cond.Message)
+	}
+
+	excluded := &v1alpha1.GPUDevice{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Name: "small"}, excluded); err != nil {
+		t.Fatalf("get small: %v", err)
+	}
+	if excluded.Status.PoolRef == nil || excluded.Status.PoolRef.Name != "pool-a" {
+		t.Fatalf("expected excluded device to stay assigned, got %+v", excluded.Status.PoolRef)
+	}
+
+	// Relaxing the requirements brings the devices back on the next pass.
+	pool.Spec.Requirements = &v1alpha1.GPUPoolRequirements{MinDriverVersion: "470"}
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if pool.Status.Capacity.Total != 3 {
+		t.Fatalf("expected all devices to add capacity, got %d", pool.Status.Capacity.Total)
+	}
+	cond = apimeta.FindStatusCondition(pool.Status.Conditions, requirements.ConditionNotMet)
+	if cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected PoolRequirementsNotMet=False, got %+v", cond)
+	}
+}
+
+func TestSelectionSyncHandlePoolReportsMIGPlacementsAvailable(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "ns"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "3g.40gb"}},
+	}
+	device := func(name string, types ...v1alpha1.GPUMIGTypeCapacity) *v1alpha1.GPUDevice {
+		return &v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{poolcommon.NamespacedAssignmentAnnotation: "pool-a"}},
+			Status: v1alpha1.GPUDeviceStatus{
+				NodeName: "node1",
+				Hardware: v1alpha1.GPUDeviceHardware{
+					Product:   "NVIDIA A100-SXM4-80GB",
+					MemoryMiB: 81920,
+					MIG:       v1alpha1.GPUMIGConfig{Capable: true, Types: types},
+				},
+			},
+		}
+	}
+	// One 3g.40gb instance is placed on the first GPU, the second GPU has MIG enabled but no instances.
+	placed := device("placed", v1alpha1.GPUMIGTypeCapacity{Name: "3g.40gb", Count: 1})
+	empty := device("empty")
+	unknown := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "unknown", Annotations: map[string]string{poolcommon.NamespacedAssignmentAnnotation: "pool-a"}},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: "node1",
+			Hardware: v1alpha1.GPUDeviceHardware{Product: "NVIDIA L40S", MIG: v1alpha1.GPUMIGConfig{Capable: true}},
+		},
+	}
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().
+		WithScheme(scheme)).
+		WithStatusSubresource(&v1alpha1.GPUDevice{}).
+		WithObjects(placed, empty, unknown).
+		Build()
+
+	h := NewSelectionSyncHandler(testr.New(t), cl)
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+
+	if pool.Status.Capacity.Total != 1 {
+		t.Fatalf("unexpected capacity total: %d", pool.Status.Capacity.Total)
+	}
+	want := map[string]int32{"7g.80gb": 1, "4g.40gb": 1, "3g.40gb": 3, "2g.20gb": 4, "1g.20gb": 6, "1g.10gb": 10}
+	if !reflect.DeepEqual(pool.Status.Capacity.PlacementsAvailable, want) {
+		t.Fatalf("unexpected placements:\n got %v\nwant %v", pool.Status.Capacity.PlacementsAvailable, want)
+	}
+
+	// Card pools do not report placements.
+	pool.Spec.Resource = v1alpha1.GPUPoolResourceSpec{Unit: "Card"}
+	if _, err := h.HandlePool(context.Background(), pool); err != nil {
+		t.Fatalf("HandlePool: %v", err)
+	}
+	if pool.Status.Capacity.PlacementsAvailable != nil {
+		t.Fatalf("expected no placements for card pools, got %v", pool.Status.Capacity.PlacementsAvailable)
+	}
+}
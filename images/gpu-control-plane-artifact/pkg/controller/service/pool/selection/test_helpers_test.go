@@ -58,5 +58,7 @@ func withPoolDeviceIndexes(builder *fake.ClientBuilder) *fake.ClientBuilder {
 		return nil
 	})
 
+	builder = builder.WithIndex(indexer.IndexGPUDeviceByUUID())
+
 	return builder
 }
@@ -25,9 +25,11 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/critical"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/kube"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/names"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/tolerations"
 )
 
@@ -41,6 +43,7 @@ func validatorDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool
 			Value:    pool.Name,
 		},
 	}, append(d.CustomTolerations, tolerations.PoolNodeTolerations(ctx, d.Client, pool)...))
+	hostNetwork := poolcommon.AdvancedFor(pool).HostNetwork
 	return &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("nvidia-operator-validator-%s", pool.Name),
@@ -66,12 +69,12 @@ func validatorDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: "nvidia-operator-validator",
+					ServiceAccountName: rbac.ServiceAccountName(d, rbac.Validator, pool),
+					PriorityClassName:  critical.PriorityClassName(ctx, d),
 					Tolerations:        mergedTolerations,
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsUser:    ptr.To[int64](0),
-						RunAsNonRoot: ptr.To(false),
-					},
+					HostNetwork:        hostNetwork,
+					DNSPolicy:          kube.HostNetworkDNSPolicy(hostNetwork),
+					SecurityContext:    kube.PodSecurityContext(d.Config.LegacyPrivileged),
 					Affinity: &corev1.Affinity{
 						NodeAffinity: &corev1.NodeAffinity{
 							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
@@ -96,97 +99,125 @@ func validatorDaemonSet(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool
 							Image:           d.Config.ValidatorImage,
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Command:         []string{"/bin/busybox", "sh", "-c", "echo all validations are successful; exec /bin/busybox sleep infinity"},
-							SecurityContext: &corev1.SecurityContext{
-								Privileged: ptr.To(true),
-							},
+							SecurityContext: watchdogSecurityContext(d.Config.LegacyPrivileged),
 							Env: []corev1.EnvVar{
 								{Name: "PATH", Value: "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
 							},
 							VolumeMounts: []corev1.VolumeMount{
-								{Name: "run-nvidia-validations", MountPath: "/run/nvidia/validations", MountPropagation: &[]corev1.MountPropagationMode{corev1.MountPropagationBidirectional}[0]},
-							},
-						},
-					},
-					Volumes: []corev1.Volume{
-						{
-							Name: "run-nvidia-validations",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/run/nvidia/validations",
-									Type: kube.HostPathType(corev1.HostPathDirectoryOrCreate),
-								},
-							},
-						},
-						{
-							Name: "kubelet-device-plugins",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/var/lib/kubelet/device-plugins",
-									Type: kube.HostPathType(corev1.HostPathDirectory),
-								},
-							},
-						},
-						{
-							Name: "kubelet-pod-resources",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/var/lib/kubelet/pod-resources",
-									Type: kube.HostPathType(corev1.HostPathDirectory),
-								},
+								{Name: "run-nvidia-validations", MountPath: "/run/nvidia/validations", MountPropagation: kube.ValidationsMountPropagation(d.Config.LegacyPrivileged)},
 							},
 						},
 					},
+					Volumes: validatorVolumes(d, pool),
 				},
 			},
 		},
 	}
 }
 
-// validatorInitContainers always injects plugin-validation; resource name is passed explicitly so validator can see custom resources.
-func validatorInitContainers(d deps.Deps, pool *v1alpha1.GPUPool) []corev1.Container {
-	return []corev1.Container{
+func validatorVolumes(d deps.Deps, pool *v1alpha1.GPUPool) []corev1.Volume {
+	volumes := []corev1.Volume{
 		{
-			Name:            "plugin-validation",
-			Image:           d.Config.ValidatorImage,
-			ImagePullPolicy: corev1.PullIfNotPresent,
-			Command:         []string{"/usr/bin/nvidia-validator"},
-			SecurityContext: &corev1.SecurityContext{
-				Privileged:               ptr.To(true),
-				RunAsUser:                ptr.To[int64](0),
-				RunAsNonRoot:             ptr.To(false),
-				AllowPrivilegeEscalation: ptr.To(true),
-				ReadOnlyRootFilesystem:   ptr.To(false),
+			Name: "run-nvidia-validations",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/run/nvidia/validations",
+					Type: kube.HostPathType(corev1.HostPathDirectoryOrCreate),
+				},
 			},
-			Env: []corev1.EnvVar{
-				{Name: "PATH", Value: "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
-				{Name: "COMPONENT", Value: "plugin"},
-				{Name: "WITH_WAIT", Value: "true"},
-				{Name: "WITH_WORKLOAD", Value: "false"},
-				// Validator must look for the exact resource name exposed by the device plugin (prefix + pool name).
-				{Name: "NVIDIA_RESOURCE_NAME", Value: names.PoolResourceName(pool)},
-				{Name: "MIG_STRATEGY", Value: d.Config.DefaultMIGStrategy},
-				{
-					Name: "NODE_NAME",
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
-					},
+		},
+		{
+			Name: "kubelet-device-plugins",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/var/lib/kubelet/device-plugins",
+					Type: kube.HostPathType(corev1.HostPathDirectory),
 				},
-				{
-					Name: "OPERATOR_NAMESPACE",
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
-					},
+			},
+		},
+		{
+			Name: "kubelet-pod-resources",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/var/lib/kubelet/pod-resources",
+					Type: kube.HostPathType(corev1.HostPathDirectory),
+				},
+			},
+		},
+	}
+	if d.Config.DriverInstallTypeFor(pool) == v1alpha1.GPUPoolDriverInstallPreinstalled {
+		volumes = append(volumes, kube.HostDriverRootVolume("driver-root", d.Config.HostDriverRoot()))
+	}
+	return volumes
+}
+
+// validatorInitContainers always injects plugin-validation; resource name is passed explicitly so validator can see custom resources.
+// A preinstalled driver is validated in place through the host driver root.
+func validatorInitContainers(d deps.Deps, pool *v1alpha1.GPUPool) []corev1.Container {
+	pluginValidation := corev1.Container{
+		Name:            "plugin-validation",
+		Image:           d.Config.ValidatorImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/usr/bin/nvidia-validator"},
+		SecurityContext: pluginValidationSecurityContext(d.Config.LegacyPrivileged),
+		Env: []corev1.EnvVar{
+			{Name: "PATH", Value: "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+			{Name: "COMPONENT", Value: "plugin"},
+			{Name: "WITH_WAIT", Value: "true"},
+			{Name: "WITH_WORKLOAD", Value: "false"},
+			// Validator must look for the exact resource name exposed by the device plugin (prefix + pool name).
+			// A mixed-layout pool advertises one resource per profile; the first one proves the plugin is up.
+			{Name: "NVIDIA_RESOURCE_NAME", Value: names.PoolResourceNames(pool)[0]},
+			{Name: "MIG_STRATEGY", Value: poolcommon.MIGStrategyFor(pool, d.Config.DefaultMIGStrategy)},
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
 				},
-				{Name: "OUTPUT_DIR", Value: "/run/nvidia/validations"},
-				{Name: "VALIDATOR_IMAGE", Value: d.Config.ValidatorImage},
-				{Name: "VALIDATOR_IMAGE_PULL_POLICY", Value: "IfNotPresent"},
-				{Name: "VALIDATOR_RUNTIME_CLASS", Value: "nvidia"},
 			},
-			VolumeMounts: []corev1.VolumeMount{
-				{Name: "run-nvidia-validations", MountPath: "/run/nvidia/validations", MountPropagation: &[]corev1.MountPropagationMode{corev1.MountPropagationBidirectional}[0]},
-				{Name: "kubelet-device-plugins", MountPath: "/var/lib/kubelet/device-plugins", ReadOnly: true},
-				{Name: "kubelet-pod-resources", MountPath: "/var/lib/kubelet/pod-resources", ReadOnly: true},
+			{
+				Name: "OPERATOR_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+				},
 			},
+			{Name: "OUTPUT_DIR", Value: "/run/nvidia/validations"},
+			{Name: "VALIDATOR_IMAGE", Value: d.Config.ValidatorImage},
+			{Name: "VALIDATOR_IMAGE_PULL_POLICY", Value: "IfNotPresent"},
+			{Name: "VALIDATOR_RUNTIME_CLASS", Value: "nvidia"},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "run-nvidia-validations", MountPath: "/run/nvidia/validations", MountPropagation: kube.ValidationsMountPropagation(d.Config.LegacyPrivileged)},
+			{Name: "kubelet-device-plugins", MountPath: "/var/lib/kubelet/device-plugins", ReadOnly: true},
+			{Name: "kubelet-pod-resources", MountPath: "/var/lib/kubelet/pod-resources", ReadOnly: true},
 		},
 	}
+
+	if d.Config.DriverInstallTypeFor(pool) == v1alpha1.GPUPoolDriverInstallPreinstalled {
+		pluginValidation.Env = append(pluginValidation.Env, corev1.EnvVar{Name: "NVIDIA_DRIVER_ROOT", Value: kube.ContainerDriverRoot})
+		pluginValidation.VolumeMounts = append(pluginValidation.VolumeMounts, corev1.VolumeMount{
+			Name:             "driver-root",
+			MountPath:        kube.ContainerDriverRoot,
+			ReadOnly:         true,
+			MountPropagation: ptr.To(corev1.MountPropagationHostToContainer),
+		})
+	}
+	return []corev1.Container{pluginValidation}
+}
+
+// pluginValidationSecurityContext runs the plugin validation unprivileged: it reads the node allocatable
+// through the API and writes a ready file into the validations directory.
+func pluginValidationSecurityContext(legacy bool) *corev1.SecurityContext {
+	if legacy {
+		return kube.LegacyPrivilegedSecurityContext()
+	}
+	return kube.RestrictedSecurityContext()
+}
+
+// watchdogSecurityContext keeps the pod running after the validations; it needs no privileges at all.
+func watchdogSecurityContext(legacy bool) *corev1.SecurityContext {
+	if legacy {
+		return &corev1.SecurityContext{Privileged: ptr.To(true)}
+	}
+	return kube.RestrictedSecurityContext()
 }
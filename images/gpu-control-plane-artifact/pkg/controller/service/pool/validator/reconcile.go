@@ -19,20 +19,32 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/critical"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/hostports"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/ops"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/rbac"
 )
 
-// Reconcile ensures the validator DaemonSet is up to date.
+// Reconcile ensures the validator RBAC, DaemonSet and its PodDisruptionBudget are up to date.
 func Reconcile(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) error {
 	if d.Config.ValidatorImage == "" {
 		return fmt.Errorf("validator image is not configured")
 	}
+	if err := rbac.Reconcile(ctx, d, rbac.Validator, pool); err != nil {
+		return err
+	}
 
 	ds := validatorDaemonSet(ctx, d, pool)
-	if err := ops.CreateOrUpdate(ctx, d.Client, ds, pool); err != nil {
+	if blocked, err := hostports.Guard(ctx, d.Client, pool, ds); err != nil || blocked {
+		return err
+	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, ds, pool); err != nil {
 		return fmt.Errorf("reconcile validator DaemonSet: %w", err)
 	}
+	if err := ops.CreateOrUpdate(ctx, d.Client, d.Guard, critical.PodDisruptionBudget(ds), pool); err != nil {
+		return fmt.Errorf("reconcile validator PodDisruptionBudget: %w", err)
+	}
 
 	return nil
 }
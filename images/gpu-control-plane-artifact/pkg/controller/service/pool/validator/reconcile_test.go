@@ -22,6 +22,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +37,7 @@ func TestReconcileErrorPaths(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"}}
@@ -45,7 +47,7 @@ func TestReconcileErrorPaths(t *testing.T) {
 	}
 
 	base := fake.NewClientBuilder().WithScheme(scheme).Build()
-	cl := &createNthErrorClient{Client: base, failOn: 1, err: errors.New("create error")}
+	cl := &createNthErrorClient{Client: base, failOn: 4, err: errors.New("create error")}
 	d := deps.Deps{Client: cl, Config: config.WorkloadConfig{Namespace: "ns", ValidatorImage: "val:tag"}}
 
 	if err := Reconcile(context.Background(), d, pool); err == nil || !strings.Contains(err.Error(), "reconcile validator DaemonSet") {
@@ -68,3 +70,41 @@ func (c *createNthErrorClient) Create(ctx context.Context, obj client.Object, op
 	}
 	return c.Client.Create(ctx, obj, opts...)
 }
+
+func TestValidatorSecurityContext(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "ns"}}
+	d := deps.Deps{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Config: config.WorkloadConfig{Namespace: "ns", ValidatorImage: "val:tag"},
+	}
+
+	spec := validatorDaemonSet(context.Background(), d, pool).Spec.Template.Spec
+	if sc := spec.SecurityContext; sc.SeccompProfile == nil || sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+		t.Fatalf("expected the RuntimeDefault seccomp profile on the pod, got %+v", sc)
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		sc := c.SecurityContext
+		if *sc.Privileged || *sc.AllowPrivilegeEscalation || !*sc.ReadOnlyRootFilesystem || len(sc.Capabilities.Drop) != 1 {
+			t.Fatalf("expected %s to run restricted, got %+v", c.Name, sc)
+		}
+		for _, m := range c.VolumeMounts {
+			if m.MountPropagation != nil && *m.MountPropagation == corev1.MountPropagationBidirectional {
+				t.Fatalf("expected no Bidirectional mount in %s, got %+v", c.Name, m)
+			}
+		}
+	}
+
+	d.Config.LegacyPrivileged = true
+	spec = validatorDaemonSet(context.Background(), d, pool).Spec.Template.Spec
+	if spec.SecurityContext.SeccompProfile != nil {
+		t.Fatalf("expected no seccomp profile in legacy mode, got %+v", spec.SecurityContext.SeccompProfile)
+	}
+	for _, c := range []corev1.Container{spec.InitContainers[0], spec.Containers[0]} {
+		if !*c.SecurityContext.Privileged || *c.VolumeMounts[0].MountPropagation != corev1.MountPropagationBidirectional {
+			t.Fatalf("expected %s privileged with a Bidirectional mount in legacy mode, got %+v %+v", c.Name, c.SecurityContext, c.VolumeMounts[0])
+		}
+	}
+}
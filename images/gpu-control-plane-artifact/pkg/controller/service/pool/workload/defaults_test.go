@@ -52,3 +52,15 @@ func TestApplyDefaultsUsesEnvFallbacks(t *testing.T) {
 		t.Fatalf("unexpected validator image: %q", cfg.ValidatorImage)
 	}
 }
+
+func TestApplyDefaultsExternalRBACFromEnv(t *testing.T) {
+	t.Setenv("POOL_WORKLOAD_EXTERNAL_RBAC", "true")
+	if cfg := ApplyDefaults(config.WorkloadConfig{}); !cfg.ExternalRBAC {
+		t.Fatalf("expected external RBAC to be enabled from env")
+	}
+
+	t.Setenv("POOL_WORKLOAD_EXTERNAL_RBAC", "")
+	if cfg := ApplyDefaults(config.WorkloadConfig{}); cfg.ExternalRBAC {
+		t.Fatalf("expected per-pool RBAC to be rendered by default")
+	}
+}
@@ -38,6 +38,18 @@ func ApplyDefaults(cfg config.WorkloadConfig) config.WorkloadConfig {
 	if cfg.DefaultMIGStrategy == "" {
 		cfg.DefaultMIGStrategy = defaults.DefaultMIGStrategy
 	}
+	if cfg.DriverInstallType == "" {
+		cfg.DriverInstallType = defaults.DriverInstallType
+	}
+	if cfg.DriverRoot == "" {
+		cfg.DriverRoot = defaults.DriverRoot
+	}
+	if cfg.PriorityClassName == "" {
+		cfg.PriorityClassName = defaults.PriorityClassName
+	}
+	if !cfg.ExternalRBAC {
+		cfg.ExternalRBAC = defaults.ExternalRBAC
+	}
 	if cfg.ValidatorImage == "" {
 		if defaults.ValidatorImage != "" {
 			cfg.ValidatorImage = defaults.ValidatorImage
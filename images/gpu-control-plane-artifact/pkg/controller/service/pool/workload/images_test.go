@@ -0,0 +1,150 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/imagedigest"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/images"
+)
+
+func newDigestTestClient(t *testing.T) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	return withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()
+}
+
+// newDigestRegistry serves the digests of the listed repository:tag pairs; every other manifest is unavailable.
+func newDigestRegistry(t *testing.T, digests map[string]string) (string, *http.Client) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo, tag, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		digest, ok := digests[repo+":"+tag]
+		if !ok {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "https://"), server.Client()
+}
+
+func TestReconcileRendersDigestPinnedImages(t *testing.T) {
+	host, httpClient := newDigestRegistry(t, map[string]string{"gpu/dp:v1": "sha256:aaa", "gpu/dp:v2": "sha256:bbb"})
+	cl := newDigestTestClient(t)
+	resolver := imagedigest.NewResolver(cl, imagedigest.Options{}).WithHTTPClient(httpClient)
+	d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+		Namespace:         "gpu-ns",
+		DevicePluginImage: host + "/gpu/dp:v1",
+		ImageResolver:     func() *imagedigest.Resolver { return resolver },
+	})
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "gpu-ns", UID: "12345"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1}},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}
+	ctx := context.Background()
+
+	res, err := Reconcile(ctx, d, pool)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.RequeueAfter != imagedigest.DefaultCacheTTL {
+		t.Fatalf("expected a requeue at the cache TTL, got %+v", res)
+	}
+	dsKey := client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-alpha"}
+	ds := &appsv1.DaemonSet{}
+	if err := cl.Get(ctx, dsKey, ds); err != nil {
+		t.Fatalf("get daemonset: %v", err)
+	}
+	if image := ds.Spec.Template.Spec.Containers[0].Image; image != host+"/gpu/dp:v1@sha256:aaa" {
+		t.Fatalf("expected the digest-pinned image, got %q", image)
+	}
+	if d.Config.DevicePluginImage != host+"/gpu/dp:v1" {
+		t.Fatalf("pinning must not leak into the shared config, got %q", d.Config.DevicePluginImage)
+	}
+
+	// A changed tag resolves at once and rolls the DaemonSet through its pod template.
+	d.Config.DevicePluginImage = host + "/gpu/dp:v2"
+	if _, err := Reconcile(ctx, d, pool); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := cl.Get(ctx, dsKey, ds); err != nil {
+		t.Fatalf("get daemonset: %v", err)
+	}
+	if image := ds.Spec.Template.Spec.Containers[0].Image; image != host+"/gpu/dp:v2@sha256:bbb" {
+		t.Fatalf("expected the new digest-pinned image, got %q", image)
+	}
+}
+
+func TestReconcileBlocksWithoutDigestsWhenRequired(t *testing.T) {
+	host, httpClient := newDigestRegistry(t, map[string]string{})
+	cl := newDigestTestClient(t)
+	resolver := imagedigest.NewResolver(cl, imagedigest.Options{RequireDigests: true}).WithHTTPClient(httpClient)
+	d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+		Namespace:         "gpu-ns",
+		DevicePluginImage: host + "/gpu/dp:v1",
+		ImageResolver:     func() *imagedigest.Resolver { return resolver },
+	})
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "gpu-ns", UID: "12345"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1}},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}
+	ctx := context.Background()
+
+	res, err := Reconcile(ctx, d, pool)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.RequeueAfter != imagedigest.RetryInterval {
+		t.Fatalf("expected a retry while blocked, got %+v", res)
+	}
+	cond := meta.FindStatusCondition(pool.Status.Conditions, images.ConditionImageDigestUnresolved)
+	if cond == nil || cond.Reason != images.ReasonDigestRequired {
+		t.Fatalf("expected DigestRequired, got %+v", pool.Status.Conditions)
+	}
+	err = cl.Get(ctx, client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-alpha"}, &appsv1.DaemonSet{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no DaemonSet while digests are required, got %v", err)
+	}
+}
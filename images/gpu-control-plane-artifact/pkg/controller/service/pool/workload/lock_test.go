@@ -0,0 +1,152 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimepkg "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+)
+
+// yieldingClient gives other goroutines a chance to run between writes, widening any interleaving window.
+type yieldingClient struct {
+	client.Client
+}
+
+func (c yieldingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	runtime.Gosched()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c yieldingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	runtime.Gosched()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestKeyedMutexSerialisesSameKeyOnly(t *testing.T) {
+	m := newKeyedMutex()
+
+	unlockA := m.lock("a")
+	acquired := make(chan struct{})
+	go func() {
+		unlock := m.lock("a")
+		close(acquired)
+		unlock()
+	}()
+
+	unlockB := m.lock("b")
+	unlockB()
+
+	select {
+	case <-acquired:
+		t.Fatalf("second lock on the same key must wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlockA()
+	<-acquired
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.locks) != 0 {
+		t.Fatalf("expected released keys to be forgotten, got %d", len(m.locks))
+	}
+}
+
+func TestReconcileConcurrentConflictingSpecsConverge(t *testing.T) {
+	scheme := runtimepkg.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	newPool := func(unit string) *v1alpha1.GPUPool {
+		pool := &v1alpha1.GPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "race", UID: "race-uid"},
+			Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: unit}},
+			Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+		}
+		if unit == "MIG" {
+			pool.Spec.Resource.MIGProfile = "1g.10gb"
+		}
+		return pool
+	}
+
+	migObjects := []client.Object{
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "gpu-ns", Name: "nvidia-mig-manager-race"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "gpu-ns", Name: "nvidia-mig-manager-race-config"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "gpu-ns", Name: "nvidia-mig-manager-race-scripts"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "gpu-ns", Name: "nvidia-mig-manager-race-gpu-clients"}},
+	}
+
+	for i := 0; i < 25; i++ {
+		cl := yieldingClient{Client: withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()}
+		d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+			Namespace:         "gpu-ns",
+			DevicePluginImage: "device-plugin:tag",
+			MIGManagerImage:   "mig-manager:tag",
+			ValidatorImage:    "validator:tag",
+		})
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		for _, unit := range []string{"MIG", "Card"} {
+			wg.Add(1)
+			go func(pool *v1alpha1.GPUPool) {
+				defer wg.Done()
+				if _, err := Reconcile(context.Background(), d, pool); err != nil {
+					errs <- err
+				}
+			}(newPool(unit))
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("Reconcile: %v", err)
+		}
+
+		present := 0
+		for _, obj := range migObjects {
+			err := cl.Get(context.Background(), client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object))
+			switch {
+			case err == nil:
+				present++
+			case !apierrors.IsNotFound(err):
+				t.Fatalf("get %s: %v", obj.GetName(), err)
+			}
+		}
+		if present != 0 && present != len(migObjects) {
+			t.Fatalf("iteration %d: half-rendered MIG state, %d of %d objects present", i, present, len(migObjects))
+		}
+	}
+}
@@ -17,19 +17,24 @@ package workload
 import (
 	"context"
 	"fmt"
-	"strings"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/imagedigest"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/cleanup"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deviceplugin"
-	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migmanager"
-	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/validator"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/hostports"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/images"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migration"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/runtimes"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/deviceprovider"
 )
 
-// Reconcile ensures per-pool workloads (device-plugin, MIG manager, validator) are deployed.
+// Reconcile ensures the per-pool workloads of the pool's device provider are deployed. Concurrent reconciles of
+// the same pool are serialised so a cleanup from one spec never interleaves with the render of another.
 func Reconcile(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) (reconcile.Result, error) {
 	if d.Client == nil {
 		return reconcile.Result{}, fmt.Errorf("client is required")
@@ -41,8 +46,17 @@ func Reconcile(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) (reconc
 		return reconcile.Result{}, fmt.Errorf("device-plugin image is not configured")
 	}
 
-	// Only Nvidia/DevicePlugin supported for now.
-	if pool.Spec.Provider != "" && pool.Spec.Provider != "Nvidia" {
+	unlock := poolLocks.lock(pool.Name)
+	defer unlock()
+	// Every rendered component re-checks its host ports below.
+	meta.RemoveStatusCondition(&pool.Status.Conditions, hostports.ConditionHostPortConflict)
+	meta.RemoveStatusCondition(&pool.Status.Conditions, migration.ConditionMigrationBlocked)
+	meta.RemoveStatusCondition(&pool.Status.Conditions, runtimes.ConditionContainerRuntimeMixed)
+	meta.RemoveStatusCondition(&pool.Status.Conditions, images.ConditionImageDigestUnresolved)
+
+	// Pools of an unregistered provider are left alone; only the DevicePlugin backend is rendered.
+	provider, ok := deviceprovider.Default().Lookup(pool.Spec.Provider)
+	if !ok {
 		return reconcile.Result{}, nil
 	}
 	if pool.Spec.Backend != "" && pool.Spec.Backend != "DevicePlugin" {
@@ -57,23 +71,24 @@ func Reconcile(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) (reconc
 			return reconcile.Result{}, cleanup.PoolResources(ctx, d.Client, d.Config.Namespace, pool.Name)
 		}
 	}
-	if err := deviceplugin.Reconcile(ctx, d, pool); err != nil {
+	profile, err := runtimes.Resolve(ctx, d.Client, pool)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
-	if err := validator.Reconcile(ctx, d, pool); err != nil {
+	d.Runtime = profile
+	// The pinned images only live in this copy of the config; the next reconcile resolves them again from the cache.
+	if images.Pin(ctx, &d.Config, pool) {
+		return reconcile.Result{RequeueAfter: imagedigest.RetryInterval}, nil
+	}
+	if err := provider.RenderPool(ctx, d, pool); err != nil {
 		return reconcile.Result{}, err
 	}
-
-	if strings.EqualFold(pool.Spec.Resource.Unit, "MIG") {
-		if d.Config.MIGManagerImage == "" {
-			d.Log.Info("MIG pool detected but MIG manager image not configured, skipping MIG manager reconcile", "pool", pool.Name)
-		} else if err := migmanager.Reconcile(ctx, d, pool); err != nil {
-			return reconcile.Result{}, err
-		}
-	} else {
-		if err := cleanup.MIGResources(ctx, d.Client, d.Config.Namespace, pool.Name); err != nil {
-			return reconcile.Result{}, err
-		}
+	if meta.IsStatusConditionTrue(pool.Status.Conditions, migration.ConditionMigrationBlocked) {
+		return reconcile.Result{RequeueAfter: migration.RecheckInterval}, nil
+	}
+	if resolver := d.Config.CurrentImageResolver(); resolver != nil {
+		// Resolve the tags again once the cached digests expire, so a moved tag rolls the workloads.
+		return reconcile.Result{RequeueAfter: resolver.CacheTTL()}, nil
 	}
 
 	return reconcile.Result{}, nil
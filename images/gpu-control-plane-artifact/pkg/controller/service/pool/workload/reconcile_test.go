@@ -22,6 +22,11 @@ import (
 	"github.com/go-logr/logr/testr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,12 +36,16 @@ import (
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migration"
 )
 
 func TestReconcileCreatesDevicePluginResources(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()
@@ -135,10 +144,11 @@ func TestReconcileCreatesDevicePluginResources(t *testing.T) {
 	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-operator-validator-alpha"}, validator); err != nil {
 		t.Fatalf("get validator daemonset: %v", err)
 	}
-	if len(validator.Spec.Template.Spec.InitContainers) == 0 {
+	pluginValidation := findInitContainer(validator.Spec.Template.Spec.InitContainers, "plugin-validation")
+	if pluginValidation == nil {
 		t.Fatalf("plugin validation init container missing")
 	}
-	if !mountExists(validator.Spec.Template.Spec.InitContainers[0].VolumeMounts, "/var/lib/kubelet/device-plugins") {
+	if !mountExists(pluginValidation.VolumeMounts, "/var/lib/kubelet/device-plugins") {
 		t.Fatalf("expected kubelet device-plugins mount on validator")
 	}
 }
@@ -147,6 +157,9 @@ func TestReconcileCreatesResourcesWhenPoolHasPendingAssignments(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	device := &v1alpha1.GPUDevice{
@@ -189,6 +202,9 @@ func TestReconcileEnablesPluginValidationWhenConfigured(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -215,13 +231,14 @@ func TestReconcileEnablesPluginValidationWhenConfigured(t *testing.T) {
 	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-operator-validator-alpha"}, validator); err != nil {
 		t.Fatalf("get validator daemonset: %v", err)
 	}
-	if len(validator.Spec.Template.Spec.InitContainers) != 1 {
-		t.Fatalf("expected plugin validation init container when enabled, got %d", len(validator.Spec.Template.Spec.InitContainers))
+	pluginValidation := findInitContainer(validator.Spec.Template.Spec.InitContainers, "plugin-validation")
+	if pluginValidation == nil {
+		t.Fatalf("expected plugin validation init container when enabled, got %+v", validator.Spec.Template.Spec.InitContainers)
 	}
-	if validator.Spec.Template.Spec.InitContainers[0].Image != "validator:tag" {
-		t.Fatalf("unexpected validator image: %s", validator.Spec.Template.Spec.InitContainers[0].Image)
+	if pluginValidation.Image != "validator:tag" {
+		t.Fatalf("unexpected validator image: %s", pluginValidation.Image)
 	}
-	if !mountExists(validator.Spec.Template.Spec.InitContainers[0].VolumeMounts, "/var/lib/kubelet/device-plugins") {
+	if !mountExists(pluginValidation.VolumeMounts, "/var/lib/kubelet/device-plugins") {
 		t.Fatalf("expected kubelet device-plugins mount when enabled")
 	}
 }
@@ -230,6 +247,9 @@ func TestReconcileCreatesMIGManagerResources(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -301,6 +321,9 @@ func TestReconcileCleansUpForNonDevicePluginBackend(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
 
 	dpCMName := "nvidia-device-plugin-alpha-config"
@@ -351,6 +374,82 @@ func TestReconcileCleansUpForNonDevicePluginBackend(t *testing.T) {
 	}
 }
 
+func TestReconcileRendersPriorityClassAndDisruptionBudgets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	for _, tc := range []struct {
+		name     string
+		objects  []client.Object
+		expected string
+	}{
+		{
+			name:     "existing priority class",
+			objects:  []client.Object{&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "gpu-critical"}, Value: 2000001000}},
+			expected: "gpu-critical",
+		},
+		{name: "missing priority class is omitted", expected: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithObjects(tc.objects...).Build()
+			d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+				Namespace:         "gpu-ns",
+				DevicePluginImage: "device-plugin:tag",
+				ValidatorImage:    "validator:tag",
+				PriorityClassName: "gpu-critical",
+			})
+			pool := &v1alpha1.GPUPool{
+				ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "gpu-ns", UID: "12345"},
+				Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+				Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+			}
+
+			if _, err := Reconcile(context.Background(), d, pool); err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+
+			names := []string{"nvidia-device-plugin-alpha", "nvidia-operator-validator-alpha"}
+			for _, name := range names {
+				ds := &appsv1.DaemonSet{}
+				if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: name}, ds); err != nil {
+					t.Fatalf("get daemonset %s: %v", name, err)
+				}
+				if ds.Spec.Template.Spec.PriorityClassName != tc.expected {
+					t.Fatalf("expected priorityClassName %q on %s, got %q", tc.expected, name, ds.Spec.Template.Spec.PriorityClassName)
+				}
+				pdb := &policyv1.PodDisruptionBudget{}
+				if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: name}, pdb); err != nil {
+					t.Fatalf("get pdb %s: %v", name, err)
+				}
+				if pdb.Spec.MaxUnavailable == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+					t.Fatalf("unexpected maxUnavailable on %s: %v", name, pdb.Spec.MaxUnavailable)
+				}
+				if pdb.Spec.Selector == nil || pdb.Spec.Selector.MatchLabels["app"] != ds.Spec.Selector.MatchLabels["app"] || pdb.Spec.Selector.MatchLabels["pool"] != "alpha" {
+					t.Fatalf("pdb selector does not match daemonset on %s: %+v", name, pdb.Spec.Selector)
+				}
+				if len(pdb.OwnerReferences) != 1 || pdb.OwnerReferences[0].Name != "alpha" {
+					t.Fatalf("owner reference not set on pdb %s", name)
+				}
+			}
+
+			pool.Spec.Backend = "DRA"
+			if _, err := Reconcile(context.Background(), d, pool); err != nil {
+				t.Fatalf("Reconcile after backend switch: %v", err)
+			}
+			for _, name := range names {
+				if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: name}, &policyv1.PodDisruptionBudget{}); err == nil {
+					t.Fatalf("pdb %s should be deleted for non-DP backend", name)
+				}
+			}
+		})
+	}
+}
+
 func hasToleration(list []corev1.Toleration, expected corev1.Toleration) bool {
 	for _, t := range list {
 		if t.Key == expected.Key && t.Operator == expected.Operator && t.Value == expected.Value && t.Effect == expected.Effect {
@@ -368,3 +467,314 @@ func mountExists(mounts []corev1.VolumeMount, path string) bool {
 	}
 	return false
 }
+
+func findInitContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+func volumeHostPath(volumes []corev1.Volume, name string) string {
+	for _, v := range volumes {
+		if v.Name == name && v.HostPath != nil {
+			return v.HostPath.Path
+		}
+	}
+	return ""
+}
+
+func TestReconcileRendersDriverInstallTypes(t *testing.T) {
+	render := func(t *testing.T, cfg config.WorkloadConfig, installType v1alpha1.GPUPoolDriverInstallType) (*appsv1.DaemonSet, *appsv1.DaemonSet) {
+		t.Helper()
+		scheme := runtime.NewScheme()
+		_ = corev1.AddToScheme(scheme)
+		_ = appsv1.AddToScheme(scheme)
+		_ = policyv1.AddToScheme(scheme)
+		_ = rbacv1.AddToScheme(scheme)
+		_ = schedulingv1.AddToScheme(scheme)
+		_ = v1alpha1.AddToScheme(scheme)
+
+		cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()
+		cfg.Namespace = "gpu-ns"
+		cfg.DevicePluginImage = "device-plugin:tag"
+		cfg.ValidatorImage = "validator:tag"
+		d := NewDeps(testr.New(t), cl, cfg)
+		pool := &v1alpha1.GPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "gpu-ns", UID: "1234"},
+			Spec: v1alpha1.GPUPoolSpec{
+				Resource:          v1alpha1.GPUPoolResourceSpec{Unit: "Card"},
+				DriverInstallType: installType,
+			},
+			Status: v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+		}
+		if _, err := Reconcile(context.Background(), d, pool); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+
+		plugin := &appsv1.DaemonSet{}
+		if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-alpha"}, plugin); err != nil {
+			t.Fatalf("get device plugin daemonset: %v", err)
+		}
+		validator := &appsv1.DaemonSet{}
+		if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-operator-validator-alpha"}, validator); err != nil {
+			t.Fatalf("get validator daemonset: %v", err)
+		}
+		return plugin, validator
+	}
+
+	t.Run("operator", func(t *testing.T) {
+		plugin, validator := render(t, config.WorkloadConfig{}, "")
+
+		if len(plugin.Spec.Template.Spec.InitContainers) != 0 {
+			t.Fatalf("expected no device plugin init containers, got %+v", plugin.Spec.Template.Spec.InitContainers)
+		}
+		if inits := validator.Spec.Template.Spec.InitContainers; len(inits) != 1 || inits[0].Name != "plugin-validation" {
+			t.Fatalf("expected only plugin validation on the validator, got %+v", inits)
+		}
+		if volumeHostPath(plugin.Spec.Template.Spec.Volumes, "run-nvidia-validations") != "" {
+			t.Fatalf("device plugin must not mount validations, got %+v", plugin.Spec.Template.Spec.Volumes)
+		}
+		if volumeHostPath(plugin.Spec.Template.Spec.Volumes, "driver-root") != "" || volumeHostPath(validator.Spec.Template.Spec.Volumes, "driver-root") != "" {
+			t.Fatalf("host driver root must not be mounted for the operator-managed driver")
+		}
+	})
+
+	t.Run("preinstalled from pool", func(t *testing.T) {
+		plugin, validator := render(t, config.WorkloadConfig{DriverRoot: "/opt/nvidia"}, v1alpha1.GPUPoolDriverInstallPreinstalled)
+
+		if len(plugin.Spec.Template.Spec.InitContainers) != 0 {
+			t.Fatalf("expected no device plugin init containers, got %+v", plugin.Spec.Template.Spec.InitContainers)
+		}
+		pluginValidation := findInitContainer(validator.Spec.Template.Spec.InitContainers, "plugin-validation")
+		if pluginValidation == nil || !mountExists(pluginValidation.VolumeMounts, "/driver-root") {
+			t.Fatalf("expected plugin validation to check the host installation, got %+v", pluginValidation)
+		}
+		if volumeHostPath(plugin.Spec.Template.Spec.Volumes, "driver-root") != "/opt/nvidia" || volumeHostPath(validator.Spec.Template.Spec.Volumes, "driver-root") != "/opt/nvidia" {
+			t.Fatalf("expected host driver root volumes, got plugin=%+v validator=%+v", plugin.Spec.Template.Spec.Volumes, validator.Spec.Template.Spec.Volumes)
+		}
+		if volumeHostPath(plugin.Spec.Template.Spec.Volumes, "run-nvidia-validations") != "" {
+			t.Fatalf("device plugin must not mount validations for a preinstalled driver")
+		}
+		if !mountExists(plugin.Spec.Template.Spec.Containers[0].VolumeMounts, "/driver-root") {
+			t.Fatalf("expected device plugin to mount the host driver root")
+		}
+	})
+
+	t.Run("preinstalled from config default", func(t *testing.T) {
+		plugin, _ := render(t, config.WorkloadConfig{DriverInstallType: v1alpha1.GPUPoolDriverInstallPreinstalled}, "")
+
+		if volumeHostPath(plugin.Spec.Template.Spec.Volumes, "driver-root") != "/" {
+			t.Fatalf("expected driver root to default to /, got %+v", plugin.Spec.Template.Spec.Volumes)
+		}
+	})
+
+	t.Run("pool overrides preinstalled default", func(t *testing.T) {
+		plugin, _ := render(t, config.WorkloadConfig{DriverInstallType: v1alpha1.GPUPoolDriverInstallPreinstalled}, v1alpha1.GPUPoolDriverInstallOperator)
+
+		if volumeHostPath(plugin.Spec.Template.Spec.Volumes, "driver-root") != "" {
+			t.Fatalf("expected pool setting to win over the config default, got %+v", plugin.Spec.Template.Spec.Volumes)
+		}
+	})
+}
+
+func TestReconcileRendersAndCleansUpPoolRBAC(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()
+	d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+		Namespace:         "gpu-ns",
+		DevicePluginImage: "device-plugin:tag",
+		MIGManagerImage:   "mig-manager:tag",
+		ValidatorImage:    "validator:tag",
+	})
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "gamma", UID: "gamma-uid"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG", MIGProfile: "1g.10gb"}},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}
+	if _, err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	for _, component := range []string{"nvidia-device-plugin", "nvidia-mig-manager", "nvidia-operator-validator"} {
+		name := component + "-gamma"
+		if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: name}, &corev1.ServiceAccount{}); err != nil {
+			t.Fatalf("get %s ServiceAccount: %v", component, err)
+		}
+		binding := &rbacv1.ClusterRoleBinding{}
+		if err := cl.Get(context.Background(), client.ObjectKey{Name: "d8:gpu-control-plane:" + name}, binding); err != nil {
+			t.Fatalf("get %s ClusterRoleBinding: %v", component, err)
+		}
+		if len(binding.Subjects) != 1 || binding.Subjects[0].Name != name || binding.Subjects[0].Namespace != "gpu-ns" {
+			t.Fatalf("unexpected %s binding subjects: %+v", component, binding.Subjects)
+		}
+		ds := &appsv1.DaemonSet{}
+		if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: name}, ds); err != nil {
+			t.Fatalf("get %s DaemonSet: %v", component, err)
+		}
+		if ds.Spec.Template.Spec.ServiceAccountName != name {
+			t.Fatalf("expected %s pods to run as %s, got %q", component, name, ds.Spec.Template.Spec.ServiceAccountName)
+		}
+	}
+
+	pool.Spec.Backend = "DRA"
+	if _, err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("Reconcile cleanup: %v", err)
+	}
+	for _, component := range []string{"nvidia-device-plugin", "nvidia-mig-manager", "nvidia-operator-validator"} {
+		name := component + "-gamma"
+		if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: name}, &corev1.ServiceAccount{}); !apierrors.IsNotFound(err) {
+			t.Fatalf("expected %s ServiceAccount to be removed, got %v", component, err)
+		}
+		if err := cl.Get(context.Background(), client.ObjectKey{Name: "d8:gpu-control-plane:" + name}, &rbacv1.ClusterRole{}); !apierrors.IsNotFound(err) {
+			t.Fatalf("expected %s ClusterRole to be removed, got %v", component, err)
+		}
+		if err := cl.Get(context.Background(), client.ObjectKey{Name: "d8:gpu-control-plane:" + name}, &rbacv1.ClusterRoleBinding{}); !apierrors.IsNotFound(err) {
+			t.Fatalf("expected %s ClusterRoleBinding to be removed, got %v", component, err)
+		}
+	}
+}
+
+func TestReconcileExternalRBACUsesSharedServiceAccounts(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()
+	d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+		Namespace:         "gpu-ns",
+		DevicePluginImage: "device-plugin:tag",
+		ValidatorImage:    "validator:tag",
+		ExternalRBAC:      true,
+	})
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "delta", UID: "delta-uid"},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}
+	if _, err := Reconcile(context.Background(), d, pool); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-delta"}, ds); err != nil {
+		t.Fatalf("get device-plugin DaemonSet: %v", err)
+	}
+	if ds.Spec.Template.Spec.ServiceAccountName != "nvidia-device-plugin" {
+		t.Fatalf("expected shared ServiceAccount, got %q", ds.Spec.Template.Spec.ServiceAccountName)
+	}
+	if err := cl.Get(context.Background(), client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-delta"}, &corev1.ServiceAccount{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no per-pool ServiceAccount with external RBAC, got %v", err)
+	}
+}
+
+func TestReconcileRecreatesDeletedWorkloads(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme)).Build()
+	d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+		Namespace:         "gpu-ns",
+		DevicePluginImage: "device-plugin:tag",
+		ValidatorImage:    "validator:tag",
+	})
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "gpu-ns", UID: "12345"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1}},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}
+	ctx := context.Background()
+	if _, err := Reconcile(ctx, d, pool); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	dsKey := client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-alpha"}
+	cmKey := client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-alpha-config"}
+	if err := cl.Delete(ctx, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dsKey.Name, Namespace: dsKey.Namespace}}); err != nil {
+		t.Fatalf("delete daemonset: %v", err)
+	}
+	if err := cl.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cmKey.Name, Namespace: cmKey.Namespace}}); err != nil {
+		t.Fatalf("delete configmap: %v", err)
+	}
+
+	// The workload watcher requeues the pool on deletion; the next pass renders both objects again.
+	if _, err := Reconcile(ctx, d, pool); err != nil {
+		t.Fatalf("Reconcile after deletion: %v", err)
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := cl.Get(ctx, dsKey, ds); err != nil {
+		t.Fatalf("expected the DaemonSet to be recreated: %v", err)
+	}
+	if ds.Labels["pool"] != "alpha" || len(ds.OwnerReferences) != 1 {
+		t.Fatalf("recreated DaemonSet lost its pool metadata: %+v", ds.ObjectMeta)
+	}
+	if err := cl.Get(ctx, cmKey, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("expected the ConfigMap to be recreated: %v", err)
+	}
+}
+
+func TestReconcileRequeuesWhileMigrationBlocked(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = policyv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	_ = schedulingv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	upstream := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset", Namespace: "gpu-ns"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, CurrentNumberScheduled: 1},
+	}
+	cl := withPoolDeviceIndexes(fake.NewClientBuilder().WithScheme(scheme).WithObjects(upstream)).Build()
+	d := NewDeps(testr.New(t), cl, config.WorkloadConfig{
+		Namespace:              "gpu-ns",
+		DevicePluginImage:      "device-plugin:tag",
+		ValidatorImage:         "validator:tag",
+		MigrateFromGPUOperator: true,
+	})
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "gpu-ns", UID: "12345"},
+		Spec:       v1alpha1.GPUPoolSpec{Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card", SlicesPerUnit: 1}},
+		Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 1}},
+	}
+	ctx := context.Background()
+	res, err := Reconcile(ctx, d, pool)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.RequeueAfter != migration.RecheckInterval {
+		t.Fatalf("expected a recheck while blocked, got %+v", res)
+	}
+
+	// Once gpu-operator is gone the condition clears and the pool DaemonSet is rendered.
+	if err := cl.Delete(ctx, upstream); err != nil {
+		t.Fatalf("delete upstream DaemonSet: %v", err)
+	}
+	res, err = Reconcile(ctx, d, pool)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.RequeueAfter != 0 || meta.FindStatusCondition(pool.Status.Conditions, migration.ConditionMigrationBlocked) != nil {
+		t.Fatalf("expected the block to clear, got %+v %+v", res, pool.Status.Conditions)
+	}
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: "gpu-ns", Name: "nvidia-device-plugin-alpha"}, &appsv1.DaemonSet{}); err != nil {
+		t.Fatalf("expected the pool DaemonSet: %v", err)
+	}
+}
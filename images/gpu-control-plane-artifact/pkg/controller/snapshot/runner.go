@@ -0,0 +1,197 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	commonobject "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/object"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+const (
+	// LabelKey marks ConfigMaps that hold inventory snapshots.
+	LabelKey = "gpu.deckhouse.io/snapshot"
+	// DataKey is the ConfigMap key storing the serialized snapshot.
+	DataKey = "snapshot.json"
+	// SchemaVersionAnnotation duplicates the schema version so tooling can filter without decoding data.
+	SchemaVersionAnnotation = "gpu.deckhouse.io/snapshot-schema-version"
+
+	namePrefix      = "gpu-control-plane-snapshot-"
+	cacheSyncWindow = 5 * time.Second
+)
+
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// Runner periodically writes snapshots into ConfigMaps and prunes older ones.
+type Runner struct {
+	log       logr.Logger
+	client    client.Client
+	reader    client.Reader
+	syncer    cacheSyncer
+	store     *moduleconfig.ModuleConfigStore
+	namespace string
+	interval  time.Duration
+	retain    int
+	now       func() time.Time
+}
+
+// NewRunner builds a snapshot runner; reader and syncer are normally the manager cache.
+func NewRunner(log logr.Logger, c client.Client, reader client.Reader, syncer cacheSyncer, store *moduleconfig.ModuleConfigStore, cfg config.SnapshotConfig) *Runner {
+	retain := cfg.Retain
+	if retain <= 0 {
+		retain = 1
+	}
+	return &Runner{
+		log:       log,
+		client:    c,
+		reader:    reader,
+		syncer:    syncer,
+		store:     store,
+		namespace: common.WorkloadsNamespace,
+		interval:  cfg.Interval,
+		retain:    retain,
+		now:       time.Now,
+	}
+}
+
+// SetupRunner registers the snapshot runner with the manager when snapshots are enabled.
+func SetupRunner(mgr ctrl.Manager, log logr.Logger, cfg config.SnapshotConfig, store *moduleconfig.ModuleConfigStore) error {
+	baseLog := log.WithName("snapshot")
+	if cfg.Interval <= 0 {
+		baseLog.V(1).Info("inventory snapshots disabled")
+		return nil
+	}
+	cache := mgr.GetCache()
+	if cache == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+	if err := mgr.Add(NewRunner(baseLog, mgr.GetClient(), cache, cache, store, cfg)); err != nil {
+		return fmt.Errorf("add snapshot runner: %w", err)
+	}
+	baseLog.Info("Initialized inventory snapshot runner", "interval", cfg.Interval, "retain", cfg.Retain)
+	return nil
+}
+
+// NeedLeaderElection keeps a single writer across controller replicas.
+func (r *Runner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the snapshot loop until the context is cancelled.
+func (r *Runner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RunOnce(ctx); err != nil {
+			r.log.Error(err, "failed to write inventory snapshot")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce writes a single snapshot and prunes old ones. It is a no-op while the cache is not synced.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	if r.syncer != nil {
+		syncCtx, cancel := context.WithTimeout(ctx, cacheSyncWindow)
+		synced := r.syncer.WaitForCacheSync(syncCtx)
+		cancel()
+		if !synced {
+			r.log.V(1).Info("cache not synced, skipping inventory snapshot")
+			return nil
+		}
+	}
+
+	state := moduleconfig.DefaultState()
+	if r.store != nil {
+		state = r.store.Current()
+	}
+
+	now := r.now()
+	snap, err := Build(ctx, r.reader, state, now)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        snapshotName(now),
+			Namespace:   r.namespace,
+			Labels:      map[string]string{LabelKey: "true"},
+			Annotations: map[string]string{SchemaVersionAnnotation: SchemaVersion},
+		},
+		Data: map[string]string{DataKey: string(data)},
+	}
+	if err := r.client.Create(ctx, cm); err != nil {
+		return fmt.Errorf("create snapshot %s: %w", cm.Name, err)
+	}
+
+	return r.prune(ctx, cm.Name)
+}
+
+func (r *Runner) prune(ctx context.Context, latest string) error {
+	list := &corev1.ConfigMapList{}
+	if err := r.reader.List(ctx, list, client.InNamespace(r.namespace), client.MatchingLabels{LabelKey: "true"}); err != nil {
+		return fmt.Errorf("list snapshots: %w", err)
+	}
+
+	// The cache may not observe the ConfigMap we just created yet; account for it explicitly.
+	names := []string{latest}
+	for _, item := range list.Items {
+		if item.Name != latest {
+			names = append(names, item.Name)
+		}
+	}
+	// Names embed a zero-padded timestamp, so reverse lexical order is newest first.
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	if len(names) <= r.retain {
+		return nil
+	}
+
+	for _, name := range names[r.retain:] {
+		stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.namespace}}
+		if err := commonobject.DeleteObject(ctx, r.client, stale); err != nil {
+			return fmt.Errorf("delete snapshot %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func snapshotName(now time.Time) string {
+	return fmt.Sprintf("%s%020d", namePrefix, now.UTC().UnixNano())
+}
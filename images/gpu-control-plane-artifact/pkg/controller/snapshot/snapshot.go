@@ -0,0 +1,190 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// SchemaVersion identifies the snapshot layout; bump it on incompatible changes so support tooling can branch.
+const SchemaVersion = "gpu.deckhouse.io/snapshot/v1"
+
+// Snapshot is a compact cluster-wide summary of the GPU inventory.
+type Snapshot struct {
+	SchemaVersion string          `json:"schemaVersion"`
+	GeneratedAt   metav1.Time     `json:"generatedAt"`
+	SettingsHash  string          `json:"settingsHash"`
+	Nodes         []NodeSummary   `json:"nodes"`
+	Devices       []DeviceSummary `json:"devices"`
+	Pools         []PoolSummary   `json:"pools"`
+}
+
+// NodeSummary mirrors GPUNodeState conditions for a single node.
+type NodeSummary struct {
+	Name       string            `json:"name"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// DeviceSummary describes a GPUDevice state and health.
+type DeviceSummary struct {
+	Name        string            `json:"name"`
+	Node        string            `json:"node,omitempty"`
+	InventoryID string            `json:"inventoryID,omitempty"`
+	Product     string            `json:"product,omitempty"`
+	State       string            `json:"state,omitempty"`
+	Managed     bool              `json:"managed"`
+	Pool        string            `json:"pool,omitempty"`
+	Conditions  map[string]string `json:"conditions,omitempty"`
+}
+
+// PoolSummary describes GPUPool/ClusterGPUPool capacity.
+type PoolSummary struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Backend   string `json:"backend,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+	Total     int32  `json:"total"`
+	Available int32  `json:"available"`
+	Used      int32  `json:"used"`
+}
+
+// Build assembles a snapshot from the provided reader. Callers are expected to pass the manager cache.
+func Build(ctx context.Context, reader client.Reader, state moduleconfig.State, now time.Time) (Snapshot, error) {
+	hash, err := SettingsHash(state)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snap := Snapshot{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   metav1.NewTime(now.UTC()),
+		SettingsHash:  hash,
+		Nodes:         []NodeSummary{},
+		Devices:       []DeviceSummary{},
+		Pools:         []PoolSummary{},
+	}
+
+	nodeStates := &v1alpha1.GPUNodeStateList{}
+	if err := reader.List(ctx, nodeStates); err != nil {
+		return Snapshot{}, fmt.Errorf("list GPUNodeStates: %w", err)
+	}
+	for _, item := range nodeStates.Items {
+		name := item.Spec.NodeName
+		if name == "" {
+			name = item.Name
+		}
+		snap.Nodes = append(snap.Nodes, NodeSummary{Name: name, Conditions: conditionStatuses(item.Status.Conditions)})
+	}
+	sort.Slice(snap.Nodes, func(i, j int) bool { return snap.Nodes[i].Name < snap.Nodes[j].Name })
+
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := reader.List(ctx, devices); err != nil {
+		return Snapshot{}, fmt.Errorf("list GPUDevices: %w", err)
+	}
+	for _, item := range devices.Items {
+		summary := DeviceSummary{
+			Name:        item.Name,
+			Node:        item.Status.NodeName,
+			InventoryID: item.Status.InventoryID,
+			Product:     item.Status.Hardware.Product,
+			State:       string(item.Status.State),
+			Managed:     item.Status.Managed,
+			Conditions:  conditionStatuses(item.Status.Conditions),
+		}
+		if ref := item.Status.PoolRef; ref != nil {
+			summary.Pool = ref.Name
+			if ref.Namespace != "" {
+				summary.Pool = ref.Namespace + "/" + ref.Name
+			}
+		}
+		snap.Devices = append(snap.Devices, summary)
+	}
+	sort.Slice(snap.Devices, func(i, j int) bool { return snap.Devices[i].Name < snap.Devices[j].Name })
+
+	pools := &v1alpha1.GPUPoolList{}
+	if err := reader.List(ctx, pools); err != nil {
+		return Snapshot{}, fmt.Errorf("list GPUPools: %w", err)
+	}
+	for _, item := range pools.Items {
+		snap.Pools = append(snap.Pools, poolSummary("GPUPool", item.Namespace, item.Name, item.Spec, item.Status))
+	}
+
+	clusterPools := &v1alpha1.ClusterGPUPoolList{}
+	if err := reader.List(ctx, clusterPools); err != nil {
+		return Snapshot{}, fmt.Errorf("list ClusterGPUPools: %w", err)
+	}
+	for _, item := range clusterPools.Items {
+		snap.Pools = append(snap.Pools, poolSummary("ClusterGPUPool", "", item.Name, item.Spec, item.Status))
+	}
+	sort.Slice(snap.Pools, func(i, j int) bool {
+		a, b := snap.Pools[i], snap.Pools[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return snap, nil
+}
+
+func poolSummary(kind, namespace, name string, spec v1alpha1.GPUPoolSpec, status v1alpha1.GPUPoolStatus) PoolSummary {
+	return PoolSummary{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Backend:   spec.Backend,
+		Unit:      spec.Resource.Unit,
+		Total:     status.Capacity.Total,
+		Available: status.Capacity.Available,
+		Used:      status.Capacity.Used,
+	}
+}
+
+func conditionStatuses(conds []metav1.Condition) map[string]string {
+	if len(conds) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(conds))
+	for _, cond := range conds {
+		out[cond.Type] = string(cond.Status)
+	}
+	return out
+}
+
+// SettingsHash fingerprints sanitized module settings without leaking their values into the bundle.
+func SettingsHash(state moduleconfig.State) (string, error) {
+	data, err := json.Marshal(state.Sanitized)
+	if err != nil {
+		return "", fmt.Errorf("encode module settings: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
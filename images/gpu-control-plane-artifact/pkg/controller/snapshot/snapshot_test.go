@@ -0,0 +1,218 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+type staticSyncer bool
+
+func (s staticSyncer) WaitForCacheSync(context.Context) bool { return bool(s) }
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	return scheme
+}
+
+func inventoryObjects() []client.Object {
+	return []client.Object{
+		&v1alpha1.GPUNodeState{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-a"},
+			Spec:       v1alpha1.GPUNodeStateSpec{NodeName: "worker-a"},
+			Status: v1alpha1.GPUNodeStateStatus{Conditions: []metav1.Condition{
+				{Type: "ReadyForPooling", Status: metav1.ConditionTrue},
+			}},
+		},
+		&v1alpha1.GPUDevice{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-a-0-10de-2230"},
+			Status: v1alpha1.GPUDeviceStatus{
+				NodeName:    "worker-a",
+				InventoryID: "worker-a-0000:17:00.0",
+				State:       v1alpha1.GPUDeviceStateAssigned,
+				Managed:     true,
+				PoolRef:     &v1alpha1.GPUPoolReference{Name: "pool-a", Namespace: "team"},
+				Hardware:    v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100"},
+				Conditions:  []metav1.Condition{{Type: "Healthy", Status: metav1.ConditionTrue}},
+			},
+		},
+		&v1alpha1.GPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "team"},
+			Spec:       v1alpha1.GPUPoolSpec{Backend: "DevicePlugin", Resource: v1alpha1.GPUPoolResourceSpec{Unit: "Card"}},
+			Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 4, Available: 3, Used: 1}},
+		},
+		&v1alpha1.ClusterGPUPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec:       v1alpha1.GPUPoolSpec{Backend: "DRA", Resource: v1alpha1.GPUPoolResourceSpec{Unit: "MIG"}},
+			Status:     v1alpha1.GPUPoolStatus{Capacity: v1alpha1.GPUPoolCapacityStatus{Total: 7, Available: 7}},
+		},
+	}
+}
+
+func TestBuildSnapshotContent(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(inventoryObjects()...).Build()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	snap, err := Build(context.Background(), cl, moduleconfig.DefaultState(), now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if snap.SchemaVersion != SchemaVersion {
+		t.Fatalf("unexpected schema version %q", snap.SchemaVersion)
+	}
+	if !snap.GeneratedAt.Time.Equal(now) {
+		t.Fatalf("unexpected generatedAt %s", snap.GeneratedAt)
+	}
+	if len(snap.SettingsHash) != 64 {
+		t.Fatalf("expected sha256 settings hash, got %q", snap.SettingsHash)
+	}
+	if len(snap.Nodes) != 1 || snap.Nodes[0].Conditions["ReadyForPooling"] != "True" {
+		t.Fatalf("unexpected nodes: %+v", snap.Nodes)
+	}
+	if len(snap.Devices) != 1 {
+		t.Fatalf("expected one device, got %+v", snap.Devices)
+	}
+	dev := snap.Devices[0]
+	if dev.State != "Assigned" || dev.Pool != "team/pool-a" || dev.Product != "NVIDIA A100" || dev.Conditions["Healthy"] != "True" {
+		t.Fatalf("unexpected device summary: %+v", dev)
+	}
+	if len(snap.Pools) != 2 {
+		t.Fatalf("expected two pools, got %+v", snap.Pools)
+	}
+	if snap.Pools[0].Kind != "ClusterGPUPool" || snap.Pools[0].Total != 7 {
+		t.Fatalf("unexpected cluster pool summary: %+v", snap.Pools[0])
+	}
+	if snap.Pools[1].Kind != "GPUPool" || snap.Pools[1].Used != 1 || snap.Pools[1].Available != 3 {
+		t.Fatalf("unexpected pool summary: %+v", snap.Pools[1])
+	}
+
+	other := moduleconfig.DefaultState()
+	other.Sanitized["logLevel"] = "Debug"
+	changed, err := Build(context.Background(), cl, other, now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if changed.SettingsHash == snap.SettingsHash {
+		t.Fatalf("expected settings hash to change with settings")
+	}
+}
+
+func TestRunOnceRotatesSnapshots(t *testing.T) {
+	objs := inventoryObjects()
+	for _, name := range []string{
+		namePrefix + "00000000000000000001",
+		namePrefix + "00000000000000000002",
+		namePrefix + "00000000000000000003",
+	} {
+		objs = append(objs, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: common.WorkloadsNamespace,
+			Labels:    map[string]string{LabelKey: "true"},
+		}})
+	}
+	unrelated := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: common.WorkloadsNamespace}}
+	objs = append(objs, unrelated)
+
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+	store := moduleconfig.NewModuleConfigStore(moduleconfig.DefaultState())
+	r := NewRunner(testr.New(t), cl, cl, staticSyncer(true), store, config.SnapshotConfig{Interval: time.Minute, Retain: 2})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := cl.List(context.Background(), list, client.InNamespace(common.WorkloadsNamespace), client.MatchingLabels{LabelKey: "true"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	got := map[string]corev1.ConfigMap{}
+	for _, item := range list.Items {
+		got[item.Name] = item
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 retained snapshots, got %v", len(got))
+	}
+	latest, ok := got[snapshotName(now)]
+	if !ok {
+		t.Fatalf("expected newest snapshot to be retained, got %v", got)
+	}
+	if _, ok := got[namePrefix+"00000000000000000003"]; !ok {
+		t.Fatalf("expected previous snapshot to be retained, got %v", got)
+	}
+	if latest.Annotations[SchemaVersionAnnotation] != SchemaVersion {
+		t.Fatalf("expected schema version annotation, got %v", latest.Annotations)
+	}
+
+	var decoded Snapshot
+	if err := json.Unmarshal([]byte(latest.Data[DataKey]), &decoded); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+	if decoded.SchemaVersion != SchemaVersion || len(decoded.Devices) != 1 {
+		t.Fatalf("unexpected decoded snapshot: %+v", decoded)
+	}
+
+	if err := cl.Get(context.Background(), client.ObjectKeyFromObject(unrelated), &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("expected unrelated ConfigMap to survive pruning: %v", err)
+	}
+}
+
+func TestRunOnceSkipsWhenCacheNotSynced(t *testing.T) {
+	cl := clientfake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(inventoryObjects()...).Build()
+	r := NewRunner(testr.New(t), cl, cl, staticSyncer(false), nil, config.SnapshotConfig{Interval: time.Minute, Retain: 1})
+
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := cl.List(context.Background(), list); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Fatalf("expected no snapshot while cache is not synced, got %d", len(list.Items))
+	}
+}
+
+func TestSetupRunnerDisabledByDefault(t *testing.T) {
+	// A nil manager proves the disabled path never touches it.
+	if err := SetupRunner(nil, testr.New(t), config.DefaultSystem().Snapshot, nil); err != nil {
+		t.Fatalf("SetupRunner: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package utilization maintains rolling GPU utilization statistics per pool and persists them into pool status.
+package utilization
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/statistics"
+)
+
+// WindowSpec describes one rolling window reported in pool status.
+type WindowSpec struct {
+	Name   string
+	Span   time.Duration
+	Bucket time.Duration
+}
+
+// Windows are the rolling windows reported for every pool.
+var Windows = []WindowSpec{
+	{Name: "1h", Span: time.Hour, Bucket: time.Minute},
+	{Name: "24h", Span: 24 * time.Hour, Bucket: 15 * time.Minute},
+}
+
+// Aggregator keeps per-pool utilization windows in memory. It is safe for concurrent use.
+type Aggregator struct {
+	mu    sync.Mutex
+	pools map[v1alpha1.GPUPoolReference]*poolStats
+}
+
+type poolStats struct {
+	windows   []*statistics.Window
+	reporting int32
+	unknown   int32
+	restored  bool
+}
+
+// NewAggregator creates an empty aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{pools: map[v1alpha1.GPUPoolReference]*poolStats{}}
+}
+
+func (a *Aggregator) stats(pool v1alpha1.GPUPoolReference) *poolStats {
+	stats, ok := a.pools[pool]
+	if !ok {
+		stats = &poolStats{}
+		for _, spec := range Windows {
+			stats.windows = append(stats.windows, statistics.NewWindow(spec.Span, spec.Bucket))
+		}
+		a.pools[pool] = stats
+	}
+	return stats
+}
+
+// Observe records one sampling round of a pool: a value per device with telemetry and the number of
+// devices without it. Devices without telemetry are unknown and do not pull the statistics towards zero.
+func (a *Aggregator) Observe(pool v1alpha1.GPUPoolReference, now time.Time, values []float64, unknown int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats := a.stats(pool)
+	stats.reporting = int32(len(values))
+	stats.unknown = int32(unknown)
+	for _, window := range stats.windows {
+		for _, value := range values {
+			window.Add(now, value)
+		}
+	}
+}
+
+// Restore seeds the pool windows from a status persisted before a restart. Callers restore a pool before
+// its first write; later calls are no-ops, so a status written by this process is never folded back
+// into its own statistics.
+func (a *Aggregator) Restore(pool v1alpha1.GPUPoolReference, status *v1alpha1.GPUPoolUtilizationStatus) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats, ok := a.pools[pool]
+	if ok && stats.restored {
+		return
+	}
+	if status == nil {
+		if ok {
+			stats.restored = true
+		}
+		return
+	}
+	stats = a.stats(pool)
+	stats.restored = true
+
+	stats.reporting = status.ReportingDevices
+	stats.unknown = status.UnknownDevices
+	for i, spec := range Windows {
+		for _, persisted := range status.Windows {
+			if persisted.Window != spec.Name {
+				continue
+			}
+			stats.windows[i].Restore(statistics.Summary{
+				Average: float64(persisted.AveragePercent) / 100,
+				P95:     float64(persisted.P95Percent) / 100,
+				Samples: persisted.Samples,
+			}, status.UpdatedAt.Time)
+		}
+	}
+}
+
+// Report returns the pool statistics at now; ok is false when the pool was never observed.
+func (a *Aggregator) Report(pool v1alpha1.GPUPoolReference, now time.Time) (*v1alpha1.GPUPoolUtilizationStatus, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats, ok := a.pools[pool]
+	if !ok {
+		return nil, false
+	}
+	status := &v1alpha1.GPUPoolUtilizationStatus{
+		UpdatedAt:        metav1.NewTime(now),
+		ReportingDevices: stats.reporting,
+		UnknownDevices:   stats.unknown,
+	}
+	for i, spec := range Windows {
+		summary := stats.windows[i].Summary(now)
+		if summary.Samples == 0 {
+			continue
+		}
+		status.Windows = append(status.Windows, v1alpha1.GPUPoolUtilizationWindow{
+			Window:         spec.Name,
+			AveragePercent: toPercent(summary.Average),
+			P95Percent:     toPercent(summary.P95),
+			Samples:        summary.Samples,
+		})
+	}
+	return status, true
+}
+
+// Forget drops the statistics of a pool that no longer exists.
+func (a *Aggregator) Forget(pool v1alpha1.GPUPoolReference) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.pools, pool)
+}
+
+// Pools returns the observed pools in a stable order.
+func (a *Aggregator) Pools() []v1alpha1.GPUPoolReference {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pools := make([]v1alpha1.GPUPoolReference, 0, len(a.pools))
+	for pool := range a.pools {
+		pools = append(pools, pool)
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		if pools[i].Namespace != pools[j].Namespace {
+			return pools[i].Namespace < pools[j].Namespace
+		}
+		return pools[i].Name < pools[j].Name
+	})
+	return pools
+}
+
+func toPercent(ratio float64) int32 {
+	return int32(math.Round(ratio * 100))
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utilization
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+var (
+	epoch    = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	poolA    = v1alpha1.GPUPoolReference{Name: "a", Namespace: "team"}
+	clusterB = v1alpha1.GPUPoolReference{Name: "b"}
+)
+
+func window(t *testing.T, status *v1alpha1.GPUPoolUtilizationStatus, name string) v1alpha1.GPUPoolUtilizationWindow {
+	t.Helper()
+	for _, w := range status.Windows {
+		if w.Window == name {
+			return w
+		}
+	}
+	t.Fatalf("window %s not reported in %+v", name, status.Windows)
+	return v1alpha1.GPUPoolUtilizationWindow{}
+}
+
+func TestAggregatorWindows(t *testing.T) {
+	a := NewAggregator()
+	// Two hours ago the pool ran hot, over the last hour it is mostly idle.
+	for i := 0; i < 60; i++ {
+		a.Observe(poolA, epoch.Add(time.Duration(i)*time.Minute), []float64{0.9, 0.9}, 0)
+	}
+	for i := 60; i < 120; i++ {
+		a.Observe(poolA, epoch.Add(time.Duration(i)*time.Minute), []float64{0.1, 0.1}, 1)
+	}
+
+	status, ok := a.Report(poolA, epoch.Add(119*time.Minute))
+	if !ok {
+		t.Fatal("expected pool to be reported")
+	}
+	if status.ReportingDevices != 2 || status.UnknownDevices != 1 {
+		t.Fatalf("unexpected device counts %+v", status)
+	}
+	hour := window(t, status, "1h")
+	if hour.AveragePercent != 10 || hour.P95Percent != 10 || hour.Samples != 120 {
+		t.Fatalf("unexpected 1h window %+v", hour)
+	}
+	day := window(t, status, "24h")
+	if day.AveragePercent != 50 || day.P95Percent != 90 || day.Samples != 240 {
+		t.Fatalf("unexpected 24h window %+v", day)
+	}
+	if !status.UpdatedAt.Time.Equal(epoch.Add(119 * time.Minute)) {
+		t.Fatalf("unexpected updatedAt %v", status.UpdatedAt)
+	}
+}
+
+func TestAggregatorUnknownDevicesAreNotZero(t *testing.T) {
+	a := NewAggregator()
+	a.Observe(poolA, epoch, []float64{0.8}, 3)
+
+	status, _ := a.Report(poolA, epoch)
+	if hour := window(t, status, "1h"); hour.AveragePercent != 80 || hour.Samples != 1 {
+		t.Fatalf("devices without telemetry must not lower utilization, got %+v", hour)
+	}
+
+	a.Observe(clusterB, epoch, nil, 2)
+	status, ok := a.Report(clusterB, epoch)
+	if !ok || len(status.Windows) != 0 || status.UnknownDevices != 2 {
+		t.Fatalf("expected a pool without telemetry to report no windows, got %+v", status)
+	}
+}
+
+func TestAggregatorRestore(t *testing.T) {
+	a := NewAggregator()
+	if _, ok := a.Report(poolA, epoch); ok {
+		t.Fatal("expected unknown pool not to be reported")
+	}
+	a.Restore(poolA, nil)
+	if _, ok := a.Report(poolA, epoch); ok {
+		t.Fatal("restoring a pool without persisted status must not report it")
+	}
+
+	persisted := &v1alpha1.GPUPoolUtilizationStatus{
+		UpdatedAt:        metav1.NewTime(epoch),
+		ReportingDevices: 4,
+		Windows: []v1alpha1.GPUPoolUtilizationWindow{
+			{Window: "1h", AveragePercent: 60, P95Percent: 90, Samples: 240},
+			{Window: "24h", AveragePercent: 40, P95Percent: 80, Samples: 5760},
+		},
+	}
+	a.Restore(clusterB, persisted)
+	status, ok := a.Report(clusterB, epoch.Add(30*time.Minute))
+	if !ok || status.ReportingDevices != 4 {
+		t.Fatalf("expected restored pool to be reported, got %+v", status)
+	}
+	if hour := window(t, status, "1h"); hour.AveragePercent != 60 || hour.Samples != 120 {
+		t.Fatalf("expected half of the restored 1h samples, got %+v", hour)
+	}
+	if day := window(t, status, "24h"); day.AveragePercent != 40 || day.P95Percent != 80 || day.Samples != 5640 {
+		t.Fatalf("unexpected restored 24h window %+v", day)
+	}
+
+	// A status written by this process later must not be folded in again.
+	a.Restore(clusterB, &v1alpha1.GPUPoolUtilizationStatus{
+		UpdatedAt: metav1.NewTime(epoch.Add(30 * time.Minute)),
+		Windows:   []v1alpha1.GPUPoolUtilizationWindow{{Window: "1h", AveragePercent: 100, P95Percent: 100, Samples: 1000}},
+	})
+	if hour := window(t, mustReport(t, a, clusterB, epoch.Add(30*time.Minute)), "1h"); hour.AveragePercent != 60 {
+		t.Fatalf("expected second restore to be ignored, got %+v", hour)
+	}
+
+	// A pool observed before its first persist still picks up the previous instance's status.
+	a.Observe(poolA, epoch, []float64{0}, 0)
+	a.Restore(poolA, persisted)
+	if hour := window(t, mustReport(t, a, poolA, epoch), "1h"); hour.Samples != 241 {
+		t.Fatalf("expected restored and live samples, got %+v", hour)
+	}
+}
+
+func TestAggregatorForgetAndPools(t *testing.T) {
+	a := NewAggregator()
+	a.Observe(clusterB, epoch, []float64{0.5}, 0)
+	a.Observe(poolA, epoch, []float64{0.5}, 0)
+
+	pools := a.Pools()
+	if len(pools) != 2 || pools[0] != clusterB || pools[1] != poolA {
+		t.Fatalf("unexpected pool order %+v", pools)
+	}
+	a.Forget(poolA)
+	if _, ok := a.Report(poolA, epoch); ok {
+		t.Fatal("expected forgotten pool not to be reported")
+	}
+}
+
+func mustReport(t *testing.T, a *Aggregator, pool v1alpha1.GPUPoolReference, now time.Time) *v1alpha1.GPUPoolUtilizationStatus {
+	t.Helper()
+	status, ok := a.Report(pool, now)
+	if !ok {
+		t.Fatalf("pool %+v not reported", pool)
+	}
+	return status
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utilization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/resourcename"
+	utilmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/utilization"
+)
+
+const cacheSyncWindow = 5 * time.Second
+
+// TelemetrySource returns the GPU utilization ratio of node devices keyed by device name.
+// Devices without telemetry are absent from the result.
+type TelemetrySource interface {
+	Utilization(ctx context.Context, node string, devices []v1alpha1.GPUDevice) (map[string]float64, error)
+}
+
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// Runner samples device telemetry, folds it into per-pool windows and periodically writes them to pool status.
+type Runner struct {
+	log             logr.Logger
+	client          client.Client
+	reader          client.Reader
+	syncer          cacheSyncer
+	source          TelemetrySource
+	stats           *Aggregator
+	sampleInterval  time.Duration
+	persistInterval time.Duration
+	now             func() time.Time
+	lastPersist     time.Time
+}
+
+// NewRunner builds a utilization runner; reader and syncer are normally the manager cache.
+func NewRunner(log logr.Logger, c client.Client, reader client.Reader, syncer cacheSyncer, source TelemetrySource, cfg config.UtilizationConfig) *Runner {
+	return &Runner{
+		log:             log,
+		client:          c,
+		reader:          reader,
+		syncer:          syncer,
+		source:          source,
+		stats:           NewAggregator(),
+		sampleInterval:  cfg.SampleInterval,
+		persistInterval: cfg.PersistInterval,
+		now:             time.Now,
+	}
+}
+
+// SetupRunner registers the utilization runner with the manager when statistics are enabled.
+func SetupRunner(mgr ctrl.Manager, log logr.Logger, cfg config.UtilizationConfig, source TelemetrySource) error {
+	baseLog := log.WithName("utilization")
+	if cfg.SampleInterval <= 0 {
+		baseLog.V(1).Info("pool utilization statistics disabled")
+		return nil
+	}
+	cache := mgr.GetCache()
+	if cache == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+	utilmetrics.Register()
+	if err := mgr.Add(NewRunner(baseLog, mgr.GetClient(), cache, cache, source, cfg)); err != nil {
+		return fmt.Errorf("add utilization runner: %w", err)
+	}
+	baseLog.Info("Initialized pool utilization runner", "sampleInterval", cfg.SampleInterval, "persistInterval", cfg.PersistInterval)
+	return nil
+}
+
+// NeedLeaderElection keeps a single writer across controller replicas.
+func (r *Runner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the sampling loop until the context is cancelled.
+func (r *Runner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RunOnce(ctx); err != nil {
+			r.log.Error(err, "failed to update pool utilization")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce takes one sample and persists the statistics when the persist interval has elapsed.
+// It is a no-op while the cache is not synced.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	if r.syncer != nil {
+		syncCtx, cancel := context.WithTimeout(ctx, cacheSyncWindow)
+		synced := r.syncer.WaitForCacheSync(syncCtx)
+		cancel()
+		if !synced {
+			r.log.V(1).Info("cache not synced, skipping pool utilization sample")
+			return nil
+		}
+	}
+
+	if err := r.Sample(ctx); err != nil {
+		return err
+	}
+	now := r.now()
+	if !r.lastPersist.IsZero() && now.Sub(r.lastPersist) < r.persistInterval {
+		return nil
+	}
+	if err := r.Persist(ctx); err != nil {
+		return err
+	}
+	r.lastPersist = now
+	return nil
+}
+
+// Sample scrapes the telemetry of every pooled device and records one round per pool.
+func (r *Runner) Sample(ctx context.Context) error {
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := r.reader.List(ctx, devices); err != nil {
+		return fmt.Errorf("list GPU devices: %w", err)
+	}
+
+	byNode := map[string][]v1alpha1.GPUDevice{}
+	for _, device := range devices.Items {
+		if device.Status.PoolRef == nil || device.Status.PoolRef.Name == "" || device.Status.NodeName == "" {
+			continue
+		}
+		byNode[device.Status.NodeName] = append(byNode[device.Status.NodeName], device)
+	}
+
+	type round struct {
+		values  []float64
+		unknown int
+	}
+	rounds := map[v1alpha1.GPUPoolReference]*round{}
+	for _, pool := range r.stats.Pools() {
+		rounds[pool] = &round{}
+	}
+	for node, nodeDevices := range byNode {
+		values, err := r.source.Utilization(ctx, node, nodeDevices)
+		if err != nil {
+			r.log.V(1).Info("GPU telemetry unavailable, counting node devices as unknown", "node", node, "error", err.Error())
+		}
+		for _, device := range nodeDevices {
+			pool := *device.Status.PoolRef
+			entry, ok := rounds[pool]
+			if !ok {
+				entry = &round{}
+				rounds[pool] = entry
+			}
+			if value, ok := values[device.Name]; ok {
+				entry.values = append(entry.values, value)
+			} else {
+				entry.unknown++
+			}
+		}
+	}
+
+	now := r.now()
+	for pool, entry := range rounds {
+		r.stats.Observe(pool, now, entry.values, entry.unknown)
+	}
+	return nil
+}
+
+// Persist writes the statistics into the status of every pool and mirrors them to metrics.
+func (r *Runner) Persist(ctx context.Context) error {
+	pools := &v1alpha1.GPUPoolList{}
+	if err := r.reader.List(ctx, pools); err != nil {
+		return fmt.Errorf("list GPU pools: %w", err)
+	}
+	clusterPools := &v1alpha1.ClusterGPUPoolList{}
+	if err := r.reader.List(ctx, clusterPools); err != nil {
+		return fmt.Errorf("list cluster GPU pools: %w", err)
+	}
+
+	now := r.now()
+	seen := map[v1alpha1.GPUPoolReference]struct{}{}
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		ref := v1alpha1.GPUPoolReference{Name: pool.Name, Namespace: pool.Namespace}
+		seen[ref] = struct{}{}
+		if err := r.persistPool(ctx, pool, ref, &pool.Status, now); err != nil {
+			return err
+		}
+	}
+	for i := range clusterPools.Items {
+		pool := &clusterPools.Items[i]
+		ref := v1alpha1.GPUPoolReference{Name: pool.Name}
+		seen[ref] = struct{}{}
+		if err := r.persistPool(ctx, pool, ref, &pool.Status, now); err != nil {
+			return err
+		}
+	}
+
+	for _, ref := range r.stats.Pools() {
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		r.stats.Forget(ref)
+		for _, spec := range Windows {
+			utilmetrics.PoolUtilizationDelete(resourcename.MetricPoolName(ref.Namespace, ref.Name), spec.Name)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) persistPool(ctx context.Context, obj client.Object, ref v1alpha1.GPUPoolReference, status *v1alpha1.GPUPoolStatus, now time.Time) error {
+	r.stats.Restore(ref, status.Utilization)
+	report, ok := r.stats.Report(ref, now)
+	if !ok {
+		return nil
+	}
+	r.exportMetrics(ref, report)
+
+	original, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("copy pool %s", resourcename.MetricPoolName(ref.Namespace, ref.Name))
+	}
+	status.Utilization = report
+	if err := r.client.Status().Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("patch utilization of pool %s: %w", resourcename.MetricPoolName(ref.Namespace, ref.Name), err)
+	}
+	return nil
+}
+
+func (r *Runner) exportMetrics(ref v1alpha1.GPUPoolReference, report *v1alpha1.GPUPoolUtilizationStatus) {
+	pool := resourcename.MetricPoolName(ref.Namespace, ref.Name)
+	for _, spec := range Windows {
+		found := false
+		for _, window := range report.Windows {
+			if window.Window != spec.Name {
+				continue
+			}
+			found = true
+			utilmetrics.PoolUtilizationSet(pool, spec.Name, float64(window.AveragePercent)/100, float64(window.P95Percent)/100)
+		}
+		if !found {
+			utilmetrics.PoolUtilizationDelete(pool, spec.Name)
+		}
+	}
+}
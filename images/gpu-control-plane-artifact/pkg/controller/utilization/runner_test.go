@@ -0,0 +1,260 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utilization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	promdto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	utilmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/utilization"
+)
+
+type staticSyncer bool
+
+func (s staticSyncer) WaitForCacheSync(context.Context) bool { return bool(s) }
+
+type fakeSource struct {
+	values map[string]map[string]float64
+	errs   map[string]error
+	calls  []string
+}
+
+func (s *fakeSource) Utilization(_ context.Context, node string, _ []v1alpha1.GPUDevice) (map[string]float64, error) {
+	s.calls = append(s.calls, node)
+	if err := s.errs[node]; err != nil {
+		return nil, err
+	}
+	return s.values[node], nil
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func pooledDevice(name, node string, pool v1alpha1.GPUPoolReference) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: node, PoolRef: &pool},
+	}
+}
+
+func newTestRunner(t *testing.T, source TelemetrySource, clock *fakeClock, objs ...client.Object) (*Runner, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	c := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.GPUPool{}, &v1alpha1.ClusterGPUPool{}).
+		Build()
+	r := NewRunner(testr.New(t), c, c, staticSyncer(true), source, config.UtilizationConfig{SampleInterval: time.Minute, PersistInterval: 5 * time.Minute})
+	r.now = clock.Now
+	return r, c
+}
+
+func gaugeValue(t *testing.T, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	families, err := crmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.Metric {
+			if matches(metric, labels) {
+				return metric.GetGauge().GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func matches(metric *promdto.Metric, labels map[string]string) bool {
+	found := 0
+	for _, pair := range metric.Label {
+		if want, ok := labels[pair.GetName()]; ok {
+			if pair.GetValue() != want {
+				return false
+			}
+			found++
+		}
+	}
+	return found == len(labels)
+}
+
+func TestRunnerSamplesAndPersists(t *testing.T) {
+	utilmetrics.Register()
+	clock := &fakeClock{now: epoch}
+	source := &fakeSource{
+		values: map[string]map[string]float64{
+			"worker-a": {"gpu-a0": 0.6, "gpu-a1": 0.2},
+		},
+		errs: map[string]error{"worker-b": errors.New("gfd-extender unavailable")},
+	}
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team"}}
+	clusterPool := &v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "b"}}
+	r, c := newTestRunner(t, source, clock,
+		pool, clusterPool,
+		pooledDevice("gpu-a0", "worker-a", poolA),
+		pooledDevice("gpu-a1", "worker-a", poolA),
+		pooledDevice("gpu-b0", "worker-b", clusterB),
+		&v1alpha1.GPUDevice{ObjectMeta: metav1.ObjectMeta{Name: "gpu-free"}, Status: v1alpha1.GPUDeviceStatus{NodeName: "worker-c"}},
+	)
+
+	ctx := context.Background()
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	for _, node := range source.calls {
+		if node == "worker-c" {
+			t.Fatal("nodes without pooled devices must not be scraped")
+		}
+	}
+
+	stored := &v1alpha1.GPUPool{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pool), stored); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	util := stored.Status.Utilization
+	if util == nil || util.ReportingDevices != 2 || util.UnknownDevices != 0 {
+		t.Fatalf("unexpected pool utilization %+v", util)
+	}
+	if hour := window(t, util, "1h"); hour.AveragePercent != 40 || hour.P95Percent != 60 || hour.Samples != 2 {
+		t.Fatalf("unexpected 1h window %+v", hour)
+	}
+	if value, ok := gaugeValue(t, utilmetrics.PoolUtilizationRatio, map[string]string{"pool": "team/a", "window": "24h"}); !ok || value != 0.4 {
+		t.Fatalf("expected pool utilization metric 0.4, got %v (present=%t)", value, ok)
+	}
+
+	storedCluster := &v1alpha1.ClusterGPUPool{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(clusterPool), storedCluster); err != nil {
+		t.Fatalf("get cluster pool: %v", err)
+	}
+	if util := storedCluster.Status.Utilization; util == nil || util.UnknownDevices != 1 || len(util.Windows) != 0 {
+		t.Fatalf("expected unreachable telemetry to be unknown, got %+v", util)
+	}
+	if _, ok := gaugeValue(t, utilmetrics.PoolUtilizationRatio, map[string]string{"pool": "b", "window": "1h"}); ok {
+		t.Fatal("expected no metric for a pool without telemetry")
+	}
+
+	// Within the persist interval samples accumulate without status writes.
+	clock.now = epoch.Add(time.Minute)
+	source.values["worker-a"] = map[string]float64{"gpu-a0": 1, "gpu-a1": 1}
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pool), stored); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	if hour := window(t, stored.Status.Utilization, "1h"); hour.Samples != 2 {
+		t.Fatalf("expected status to be persisted only once per interval, got %+v", hour)
+	}
+
+	clock.now = epoch.Add(5 * time.Minute)
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pool), stored); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	if hour := window(t, stored.Status.Utilization, "1h"); hour.Samples != 6 || hour.AveragePercent != 80 {
+		t.Fatalf("unexpected 1h window after persist interval %+v", hour)
+	}
+}
+
+func TestRunnerRestoresAfterRestart(t *testing.T) {
+	clock := &fakeClock{now: epoch.Add(30 * time.Minute)}
+	pool := &v1alpha1.GPUPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team"},
+		Status: v1alpha1.GPUPoolStatus{Utilization: &v1alpha1.GPUPoolUtilizationStatus{
+			UpdatedAt:        metav1.NewTime(epoch),
+			ReportingDevices: 1,
+			Windows: []v1alpha1.GPUPoolUtilizationWindow{
+				{Window: "1h", AveragePercent: 80, P95Percent: 90, Samples: 60},
+			},
+		}},
+	}
+	source := &fakeSource{values: map[string]map[string]float64{"worker-a": {"gpu-a0": 0.2}}}
+	r, c := newTestRunner(t, source, clock, pool, pooledDevice("gpu-a0", "worker-a", poolA))
+
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	stored := &v1alpha1.GPUPool{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(pool), stored); err != nil {
+		t.Fatalf("get pool: %v", err)
+	}
+	// 30 restored samples at 80% and one live sample at 20%.
+	if hour := window(t, stored.Status.Utilization, "1h"); hour.Samples != 31 || hour.AveragePercent != 78 {
+		t.Fatalf("expected restored statistics to survive the restart, got %+v", hour)
+	}
+}
+
+func TestRunnerForgetsDeletedPools(t *testing.T) {
+	clock := &fakeClock{now: epoch}
+	source := &fakeSource{values: map[string]map[string]float64{"worker-a": {"gpu-a0": 0.5}}}
+	pool := &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team"}}
+	device := pooledDevice("gpu-a0", "worker-a", poolA)
+	r, c := newTestRunner(t, source, clock, pool, device)
+
+	ctx := context.Background()
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if err := c.Delete(ctx, pool); err != nil {
+		t.Fatalf("delete pool: %v", err)
+	}
+	if err := c.Delete(ctx, device); err != nil {
+		t.Fatalf("delete device: %v", err)
+	}
+	clock.now = epoch.Add(5 * time.Minute)
+	if err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if pools := r.stats.Pools(); len(pools) != 0 {
+		t.Fatalf("expected deleted pool to be forgotten, got %+v", pools)
+	}
+	if _, ok := gaugeValue(t, utilmetrics.PoolUtilizationRatio, map[string]string{"pool": "team/a", "window": "1h"}); ok {
+		t.Fatal("expected metrics of a deleted pool to be removed")
+	}
+}
+
+func TestRunnerSkipsUnsyncedCache(t *testing.T) {
+	source := &fakeSource{}
+	r, _ := newTestRunner(t, source, &fakeClock{now: epoch}, pooledDevice("gpu-a0", "worker-a", poolA))
+	r.syncer = staticSyncer(false)
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if len(source.calls) != 0 {
+		t.Fatalf("expected no scrapes before the cache syncs, got %v", source.calls)
+	}
+}
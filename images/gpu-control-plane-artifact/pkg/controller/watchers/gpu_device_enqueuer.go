@@ -26,6 +26,7 @@ import (
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonannotations "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
 
 type GPUPoolGPUDeviceEnqueuer struct {
@@ -55,10 +56,26 @@ func (e *GPUPoolGPUDeviceEnqueuer) EnqueueRequests(ctx context.Context, dev *v1a
 		targetPools[ann] = struct{}{}
 	}
 
-	if len(targetPools) == 0 || e.cl == nil {
+	if e.cl == nil {
 		return requestsFromSet(reqSet)
 	}
 
+	if selectorCandidate(dev) {
+		list := &v1alpha1.GPUPoolList{}
+		if err := e.cl.List(ctx, list); err != nil {
+			if e.log.GetSink() != nil {
+				e.log.Error(err, "list GPUPools to match device selectors", "device", dev.Name)
+			}
+		} else {
+			for i := range list.Items {
+				pool := &list.Items[i]
+				if poolcommon.MatchesDeviceSelector(*dev, pool.Spec.DeviceSelector) {
+					reqSet[types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name}] = struct{}{}
+				}
+			}
+		}
+	}
+
 	for poolName := range targetPools {
 		list := &v1alpha1.GPUPoolList{}
 		if err := e.cl.List(ctx, list, client.MatchingFields{indexer.GPUPoolNameField: poolName}); err != nil {
@@ -76,13 +93,16 @@ func (e *GPUPoolGPUDeviceEnqueuer) EnqueueRequests(ctx context.Context, dev *v1a
 	return requestsFromSet(reqSet)
 }
 
-type ClusterGPUPoolGPUDeviceEnqueuer struct{}
+type ClusterGPUPoolGPUDeviceEnqueuer struct {
+	log logr.Logger
+	cl  client.Client
+}
 
-func NewClusterGPUPoolGPUDeviceEnqueuer() *ClusterGPUPoolGPUDeviceEnqueuer {
-	return &ClusterGPUPoolGPUDeviceEnqueuer{}
+func NewClusterGPUPoolGPUDeviceEnqueuer(log logr.Logger, cl client.Client) *ClusterGPUPoolGPUDeviceEnqueuer {
+	return &ClusterGPUPoolGPUDeviceEnqueuer{log: log, cl: cl}
 }
 
-func (e *ClusterGPUPoolGPUDeviceEnqueuer) EnqueueRequests(_ context.Context, dev *v1alpha1.GPUDevice) []reconcile.Request {
+func (e *ClusterGPUPoolGPUDeviceEnqueuer) EnqueueRequests(ctx context.Context, dev *v1alpha1.GPUDevice) []reconcile.Request {
 	if dev == nil {
 		return nil
 	}
@@ -97,9 +117,31 @@ func (e *ClusterGPUPoolGPUDeviceEnqueuer) EnqueueRequests(_ context.Context, dev
 		targets[types.NamespacedName{Name: ann}] = struct{}{}
 	}
 
+	if e.cl != nil && selectorCandidate(dev) {
+		list := &v1alpha1.ClusterGPUPoolList{}
+		if err := e.cl.List(ctx, list); err != nil {
+			if e.log.GetSink() != nil {
+				e.log.Error(err, "list ClusterGPUPools to match device selectors", "device", dev.Name)
+			}
+		} else {
+			for i := range list.Items {
+				pool := &list.Items[i]
+				if poolcommon.MatchesDeviceSelector(*dev, pool.Spec.DeviceSelector) {
+					targets[types.NamespacedName{Name: pool.Name}] = struct{}{}
+				}
+			}
+		}
+	}
+
 	return requestsFromSet(targets)
 }
 
+// selectorCandidate reports whether pool selectors may pick the device up, mirroring the selection handler:
+// devices without a node or marked as ignored only reach the pools they are already assigned to.
+func selectorCandidate(dev *v1alpha1.GPUDevice) bool {
+	return poolcommon.DeviceNodeName(dev) != "" && !poolcommon.IsDeviceIgnored(dev)
+}
+
 func requestsFromSet(reqSet map[types.NamespacedName]struct{}) []reconcile.Request {
 	if len(reqSet) == 0 {
 		return nil
@@ -23,6 +23,7 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonannotations "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
 
 type GPUDeviceFilter struct {
@@ -59,12 +60,36 @@ func gpuDeviceChanged(oldDev, newDev *v1alpha1.GPUDevice, assignmentAnnotation s
 	if oldDev.Status.State != newDev.Status.State || oldDev.Status.NodeName != newDev.Status.NodeName {
 		return true
 	}
+	if oldDev.Status.Managed != newDev.Status.Managed || oldDev.Status.AutoAttach != newDev.Status.AutoAttach {
+		return true
+	}
+	if poolcommon.IsDeviceIgnored(oldDev) != poolcommon.IsDeviceIgnored(newDev) {
+		return true
+	}
 	if oldDev.Status.Hardware.UUID != newDev.Status.Hardware.UUID {
 		return true
 	}
+	if poolcommon.IsDeviceUnreachable(oldDev) != poolcommon.IsDeviceUnreachable(newDev) ||
+		poolcommon.IsDeviceCompatibilityBlocked(oldDev) != poolcommon.IsDeviceCompatibilityBlocked(newDev) {
+		return true
+	}
 	if !equality.Semantic.DeepEqual(oldDev.Status.Hardware.MIG, newDev.Status.Hardware.MIG) {
 		return true
 	}
+	// Pool selectors and requirements are evaluated against the driver version and hardware capabilities;
+	// conditions and history are reporting only and never change a pool's view of the device.
+	if !equality.Semantic.DeepEqual(oldDev.Status.Hardware.PCI, newDev.Status.Hardware.PCI) ||
+		!equality.Semantic.DeepEqual(oldDev.Status.Hardware.ConfidentialComputing, newDev.Status.Hardware.ConfidentialComputing) ||
+		oldDev.Status.InventoryID != newDev.Status.InventoryID {
+		return true
+	}
+	if oldDev.Status.DriverVersion != newDev.Status.DriverVersion ||
+		oldDev.Status.Hardware.Product != newDev.Status.Hardware.Product ||
+		oldDev.Status.Hardware.MemoryMiB != newDev.Status.Hardware.MemoryMiB ||
+		oldDev.Status.Hardware.ComputeCapability != newDev.Status.Hardware.ComputeCapability ||
+		!equality.Semantic.DeepEqual(oldDev.Status.Hardware.Precision, newDev.Status.Hardware.Precision) {
+		return true
+	}
 	oldRef := normalizedPoolRef(oldDev.Status.PoolRef, assignmentAnnotation)
 	newRef := normalizedPoolRef(newDev.Status.PoolRef, assignmentAnnotation)
 	if (oldRef == nil) != (newRef == nil) {
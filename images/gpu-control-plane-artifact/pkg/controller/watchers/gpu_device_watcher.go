@@ -74,13 +74,13 @@ type ClusterGPUPoolGPUDeviceWatcher struct {
 func NewClusterGPUPoolGPUDeviceWatcher(log logr.Logger) *ClusterGPUPoolGPUDeviceWatcher {
 	return &ClusterGPUPoolGPUDeviceWatcher{
 		log:      log,
-		enqueuer: NewClusterGPUPoolGPUDeviceEnqueuer(),
+		enqueuer: NewClusterGPUPoolGPUDeviceEnqueuer(log, nil),
 	}
 }
 
 func (w *ClusterGPUPoolGPUDeviceWatcher) enqueue(ctx context.Context, dev *v1alpha1.GPUDevice) []reconcile.Request {
 	if w.enqueuer == nil {
-		w.enqueuer = NewClusterGPUPoolGPUDeviceEnqueuer()
+		w.enqueuer = NewClusterGPUPoolGPUDeviceEnqueuer(w.log, nil)
 	}
 	return w.enqueuer.EnqueueRequests(ctx, dev)
 }
@@ -91,6 +91,8 @@ func (w *ClusterGPUPoolGPUDeviceWatcher) Watch(mgr manager.Manager, ctr controll
 		return fmt.Errorf("manager cache is required")
 	}
 
+	w.enqueuer = NewClusterGPUPoolGPUDeviceEnqueuer(w.log, mgr.GetClient())
+
 	return ctr.Watch(
 		source.Kind(
 			cache,
@@ -28,6 +28,7 @@ import (
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonannotations "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
 
 func TestGPUPoolGPUDeviceWatcherEnqueueBranches(t *testing.T) {
@@ -128,3 +129,89 @@ func TestClusterGPUPoolGPUDeviceWatcherEnqueueBranches(t *testing.T) {
 		t.Fatalf("expected nil requests when no assignment, got %#v", got)
 	}
 }
+
+func TestGPUPoolGPUDeviceEnqueuerMatchesSelectorsOnce(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	a100 := &v1alpha1.GPUPoolDeviceSelector{Include: v1alpha1.GPUPoolSelectorRules{Products: []string{"NVIDIA A100"}}}
+	h100 := &v1alpha1.GPUPoolDeviceSelector{Include: v1alpha1.GPUPoolSelectorRules{Products: []string{"NVIDIA H100"}}}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&v1alpha1.GPUPool{}, indexer.GPUPoolNameField, func(obj client.Object) []string {
+			return []string{obj.GetName()}
+		}).
+		WithObjects(
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "a100", Namespace: "ns1"}, Spec: v1alpha1.GPUPoolSpec{DeviceSelector: a100}},
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "any", Namespace: "ns2"}},
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "h100", Namespace: "ns3"}, Spec: v1alpha1.GPUPoolSpec{DeviceSelector: h100}},
+		).
+		Build()
+	e := NewGPUPoolGPUDeviceEnqueuer(testr.New(t), cl)
+
+	dev := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev", Annotations: map[string]string{commonannotations.GPUDeviceAssignment: "a100"}},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: "node",
+			PoolRef:  &v1alpha1.GPUPoolReference{Name: "a100", Namespace: "ns1"},
+			Hardware: v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100"},
+		},
+	}
+	reqs := e.EnqueueRequests(ctx, dev)
+	got := map[string]int{}
+	for _, req := range reqs {
+		got[req.Namespace+"/"+req.Name]++
+	}
+	if len(reqs) != 2 || got["ns1/a100"] != 1 || got["ns2/any"] != 1 {
+		t.Fatalf("expected each matching pool exactly once, got %#v", reqs)
+	}
+
+	dev.Labels = map[string]string{poolcommon.DeviceIgnoreKey: "true"}
+	reqs = e.EnqueueRequests(ctx, dev)
+	if len(reqs) != 1 || reqs[0].Name != "a100" {
+		t.Fatalf("expected ignored device to reach only its assigned pool, got %#v", reqs)
+	}
+
+	if got := NewGPUPoolGPUDeviceEnqueuer(testr.New(t), &failingListClient{err: errors.New("list fail")}).EnqueueRequests(ctx, &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{NodeName: "node"}}); got != nil {
+		t.Fatalf("expected nil requests on list error, got %#v", got)
+	}
+}
+
+func TestClusterGPUPoolGPUDeviceEnqueuerMatchesSelectorsOnce(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	mig := true
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "mig"}, Spec: v1alpha1.GPUPoolSpec{DeviceSelector: &v1alpha1.GPUPoolDeviceSelector{Include: v1alpha1.GPUPoolSelectorRules{MIGCapable: &mig}}}},
+			&v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "no-a100"}, Spec: v1alpha1.GPUPoolSpec{DeviceSelector: &v1alpha1.GPUPoolDeviceSelector{Exclude: v1alpha1.GPUPoolSelectorRules{Products: []string{"NVIDIA A100"}}}}},
+			&v1alpha1.ClusterGPUPool{ObjectMeta: metav1.ObjectMeta{Name: "any"}},
+		).
+		Build()
+	e := NewClusterGPUPoolGPUDeviceEnqueuer(testr.New(t), cl)
+
+	dev := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev", Annotations: map[string]string{commonannotations.ClusterGPUDeviceAssignment: "mig"}},
+		Status: v1alpha1.GPUDeviceStatus{
+			NodeName: "node",
+			PoolRef:  &v1alpha1.GPUPoolReference{Name: "mig"},
+			Hardware: v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100", MIG: v1alpha1.GPUMIGConfig{Capable: true}},
+		},
+	}
+	reqs := e.EnqueueRequests(ctx, dev)
+	got := map[string]int{}
+	for _, req := range reqs {
+		got[req.Name]++
+	}
+	if len(reqs) != 2 || got["mig"] != 1 || got["any"] != 1 {
+		t.Fatalf("expected each matching cluster pool exactly once, got %#v", reqs)
+	}
+
+	if got := e.EnqueueRequests(ctx, &v1alpha1.GPUDevice{Status: v1alpha1.GPUDeviceStatus{Hardware: v1alpha1.GPUDeviceHardware{Product: "NVIDIA H100"}}}); got != nil {
+		t.Fatalf("expected device without node to skip selector matching, got %#v", got)
+	}
+}
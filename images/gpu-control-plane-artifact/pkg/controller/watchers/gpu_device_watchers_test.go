@@ -22,6 +22,7 @@ import (
 
 	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonannotations "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/annotations"
+	poolcommon "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/common"
 )
 
 func TestGPUDevicePredicates(t *testing.T) {
@@ -184,10 +185,66 @@ func TestGPUDeviceChangedDetectsRelevantFields(t *testing.T) {
 			if !gpuDeviceChanged(base, changed, tt.assignmentAnnotation) {
 				t.Fatalf("expected poolRef namespace change to be detected")
 			}
+
+			changed = base.DeepCopy()
+			changed.Status.Conditions = []metav1.Condition{{Type: poolcommon.DeviceConditionNodeUnreachable, Status: metav1.ConditionTrue, Reason: "NodeNotReady"}}
+			if !gpuDeviceChanged(base, changed, tt.assignmentAnnotation) {
+				t.Fatalf("expected unreachable flip to be detected")
+			}
+
+			changed = base.DeepCopy()
+			changed.Status.Conditions = []metav1.Condition{{Type: poolcommon.DeviceConditionCompatibilityBlocked, Status: metav1.ConditionTrue, Reason: "CompatibilityBlocked"}}
+			if !gpuDeviceChanged(base, changed, tt.assignmentAnnotation) {
+				t.Fatalf("expected compatibility block flip to be detected")
+			}
+
+			for name, mutate := range map[string]func(*v1alpha1.GPUDevice){
+				"driver version":     func(d *v1alpha1.GPUDevice) { d.Status.DriverVersion = "550.54.15" },
+				"memory":             func(d *v1alpha1.GPUDevice) { d.Status.Hardware.MemoryMiB = 81920 },
+				"compute capability": func(d *v1alpha1.GPUDevice) { d.Status.Hardware.ComputeCapability = "9.0" },
+				"precision":          func(d *v1alpha1.GPUDevice) { d.Status.Hardware.Precision = []string{"bf16"} },
+				"product":            func(d *v1alpha1.GPUDevice) { d.Status.Hardware.Product = "NVIDIA H100" },
+				"pci device":         func(d *v1alpha1.GPUDevice) { d.Status.Hardware.PCI.Device = "2330" },
+				"managed":            func(d *v1alpha1.GPUDevice) { d.Status.Managed = true },
+				"autoAttach":         func(d *v1alpha1.GPUDevice) { d.Status.AutoAttach = true },
+				"ignore label":       func(d *v1alpha1.GPUDevice) { d.Labels = map[string]string{poolcommon.DeviceIgnoreKey: "true"} },
+			} {
+				changed = base.DeepCopy()
+				mutate(changed)
+				if !gpuDeviceChanged(base, changed, tt.assignmentAnnotation) {
+					t.Fatalf("expected %s change to be detected", name)
+				}
+			}
 		})
 	}
 }
 
+func TestGPUDeviceChangedIgnoresTelemetryOnlyUpdates(t *testing.T) {
+	base := &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev", ResourceVersion: "1"},
+		Status: v1alpha1.GPUDeviceStatus{
+			State:    v1alpha1.GPUDeviceStateReady,
+			NodeName: "node",
+			Hardware: v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100", MemoryMiB: 40960},
+		},
+	}
+
+	telemetry := base.DeepCopy()
+	telemetry.ResourceVersion = "2"
+	telemetry.Status.Conditions = []metav1.Condition{{Type: "ReadyForPooling", Status: metav1.ConditionTrue, Reason: "Validated"}}
+	telemetry.Status.History = []v1alpha1.GPUDeviceStateTransition{{From: v1alpha1.GPUDeviceStateDiscovered, To: v1alpha1.GPUDeviceStateReady, Reason: "Validated"}}
+
+	for _, annotation := range []string{commonannotations.GPUDeviceAssignment, commonannotations.ClusterGPUDeviceAssignment} {
+		if gpuDeviceChanged(base, telemetry, annotation) {
+			t.Fatalf("expected telemetry-only update to be ignored for %s", annotation)
+		}
+		p := NewGPUDeviceFilter(annotation).Predicates()
+		if p.Update(event.TypedUpdateEvent[*v1alpha1.GPUDevice]{ObjectOld: base, ObjectNew: telemetry}) {
+			t.Fatalf("expected update predicate to drop telemetry-only update for %s", annotation)
+		}
+	}
+}
+
 func poolRefForAssignment(assignmentAnnotation string) *v1alpha1.GPUPoolReference {
 	ref := &v1alpha1.GPUPoolReference{Name: "pool"}
 	if assignmentAnnotation == commonannotations.GPUDeviceAssignment {
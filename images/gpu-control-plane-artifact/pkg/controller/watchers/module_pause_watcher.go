@@ -0,0 +1,79 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+// ModulePauseWatcher requeues every object of a controller when settings.paused flips: on pause so the
+// objects get the ModulePaused condition, on resume to resync whatever changed while the module was frozen.
+type ModulePauseWatcher struct {
+	log     logr.Logger
+	store   *moduleconfig.ModuleConfigStore
+	newList func() client.ObjectList
+}
+
+func NewModulePauseWatcher(log logr.Logger, store *moduleconfig.ModuleConfigStore, newList func() client.ObjectList) *ModulePauseWatcher {
+	return &ModulePauseWatcher{log: log, store: store, newList: newList}
+}
+
+func (w *ModulePauseWatcher) Watch(mgr manager.Manager, ctr controller.Controller) error {
+	if w.store == nil {
+		return nil
+	}
+	cl := mgr.GetClient()
+	return ctr.Watch(source.Channel(w.store.PauseEvents(), handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, _ client.Object) []reconcile.Request {
+			return w.EnqueueRequests(ctx, cl)
+		},
+	)))
+}
+
+// EnqueueRequests lists every object of the watched kind.
+func (w *ModulePauseWatcher) EnqueueRequests(ctx context.Context, cl client.Reader) []reconcile.Request {
+	list := w.newList()
+	if err := cl.List(ctx, list); err != nil {
+		if w.log.GetSink() != nil {
+			w.log.Error(err, "list objects to requeue after module pause change")
+		}
+		return nil
+	}
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		if w.log.GetSink() != nil {
+			w.log.Error(err, "extract objects to requeue after module pause change")
+		}
+		return nil
+	}
+	reqs := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+	}
+	return reqs
+}
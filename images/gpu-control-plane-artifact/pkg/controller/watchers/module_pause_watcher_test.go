@@ -0,0 +1,73 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func newGPUPoolList() client.ObjectList { return &v1alpha1.GPUPoolList{} }
+
+func TestModulePauseWatcherEnqueuesEveryObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	var objs []client.Object
+	for _, ns := range []string{"team-a", "team-b"} {
+		for _, name := range []string{"a", "b", "c"} {
+			objs = append(objs, &v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}})
+		}
+	}
+	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	w := NewModulePauseWatcher(testr.New(t), nil, newGPUPoolList)
+	reqs := w.EnqueueRequests(context.Background(), cl)
+	if len(reqs) != len(objs) {
+		t.Fatalf("expected %d requests, got %d: %+v", len(objs), len(reqs), reqs)
+	}
+	seen := map[string]bool{}
+	for _, req := range reqs {
+		seen[req.String()] = true
+	}
+	for _, obj := range objs {
+		if key := client.ObjectKeyFromObject(obj).String(); !seen[key] {
+			t.Fatalf("expected request for %s", key)
+		}
+	}
+}
+
+func TestModulePauseWatcherListError(t *testing.T) {
+	w := NewModulePauseWatcher(testr.New(t), nil, newGPUPoolList)
+	if reqs := w.EnqueueRequests(context.Background(), &failingListClient{err: errors.New("list fail")}); reqs != nil {
+		t.Fatalf("expected nil requests on list error, got %+v", reqs)
+	}
+}
+
+func TestModulePauseWatcherWatchWithoutStore(t *testing.T) {
+	if err := NewModulePauseWatcher(testr.New(t), nil, newGPUPoolList).Watch(nil, nil); err != nil {
+		t.Fatalf("expected nil store to skip the watch, got %v", err)
+	}
+}
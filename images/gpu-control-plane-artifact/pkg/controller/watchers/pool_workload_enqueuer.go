@@ -0,0 +1,105 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+// poolOwner returns the GPUPool or ClusterGPUPool owner reference of obj, if any.
+func poolOwner(obj client.Object) (metav1.OwnerReference, bool) {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.APIVersion != v1alpha1.GroupVersion.String() {
+			continue
+		}
+		if ref.Kind == "GPUPool" || ref.Kind == "ClusterGPUPool" {
+			return ref, true
+		}
+	}
+	return metav1.OwnerReference{}, false
+}
+
+type GPUPoolWorkloadEnqueuer struct {
+	log logr.Logger
+	cl  client.Client
+}
+
+func NewGPUPoolWorkloadEnqueuer(log logr.Logger, cl client.Client) *GPUPoolWorkloadEnqueuer {
+	return &GPUPoolWorkloadEnqueuer{log: log, cl: cl}
+}
+
+// EnqueueRequests maps a rendered object to its GPUPool. The owner reference is used when the pool lives in the
+// workloads namespace; objects of pools elsewhere cannot carry one and are mapped through the pool label.
+func (e *GPUPoolWorkloadEnqueuer) EnqueueRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !isPoolWorkload(obj) {
+		return nil
+	}
+	if owner, ok := poolOwner(obj); ok {
+		if owner.Kind != "GPUPool" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}}}
+	}
+
+	if e.cl == nil {
+		return nil
+	}
+	poolName := strings.TrimSpace(obj.GetLabels()["pool"])
+	list := &v1alpha1.GPUPoolList{}
+	if err := e.cl.List(ctx, list, client.MatchingFields{indexer.GPUPoolNameField: poolName}); err != nil {
+		if e.log.GetSink() != nil {
+			e.log.Error(err, "list GPUPool by name to map workload event", "object", obj.GetName(), "pool", poolName)
+		}
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		pool := list.Items[i]
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: pool.Namespace, Name: pool.Name},
+		})
+	}
+	return reqs
+}
+
+type ClusterGPUPoolWorkloadEnqueuer struct{}
+
+func NewClusterGPUPoolWorkloadEnqueuer() *ClusterGPUPoolWorkloadEnqueuer {
+	return &ClusterGPUPoolWorkloadEnqueuer{}
+}
+
+// EnqueueRequests maps a rendered object to the ClusterGPUPool named by its owner reference, which cluster pools
+// always set.
+func (e *ClusterGPUPoolWorkloadEnqueuer) EnqueueRequests(_ context.Context, obj client.Object) []reconcile.Request {
+	if !isPoolWorkload(obj) {
+		return nil
+	}
+	owner, ok := poolOwner(obj)
+	if !ok || owner.Kind != "ClusterGPUPool" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: owner.Name}}}
+}
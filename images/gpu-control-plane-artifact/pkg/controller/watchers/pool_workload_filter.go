@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// poolWorkloadApps are the app labels of the per-pool DaemonSets and ConfigMaps rendered by the workload handler.
+var poolWorkloadApps = map[string]struct{}{
+	"nvidia-device-plugin":      {},
+	"nvidia-mig-manager":        {},
+	"nvidia-operator-validator": {},
+}
+
+// PoolWorkloadFilter passes deletions and spec or metadata edits of rendered pool objects. Creations are the
+// renderer's own and status updates (DaemonSet pod counts) change nothing the renderer owns, so both are dropped.
+type PoolWorkloadFilter struct{}
+
+func NewPoolWorkloadFilter() PoolWorkloadFilter {
+	return PoolWorkloadFilter{}
+}
+
+func (f PoolWorkloadFilter) DaemonSetPredicates() predicate.TypedPredicate[*appsv1.DaemonSet] {
+	return predicate.TypedFuncs[*appsv1.DaemonSet]{
+		CreateFunc: func(event.TypedCreateEvent[*appsv1.DaemonSet]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[*appsv1.DaemonSet]) bool {
+			oldDS, newDS := e.ObjectOld, e.ObjectNew
+			if oldDS == nil || newDS == nil {
+				return false
+			}
+			if !isPoolWorkload(oldDS) && !isPoolWorkload(newDS) {
+				return false
+			}
+			return oldDS.Generation != newDS.Generation || poolWorkloadMetadataChanged(oldDS, newDS)
+		},
+		DeleteFunc:  func(e event.TypedDeleteEvent[*appsv1.DaemonSet]) bool { return isPoolWorkload(e.Object) },
+		GenericFunc: func(event.TypedGenericEvent[*appsv1.DaemonSet]) bool { return false },
+	}
+}
+
+func (f PoolWorkloadFilter) ConfigMapPredicates() predicate.TypedPredicate[*corev1.ConfigMap] {
+	return predicate.TypedFuncs[*corev1.ConfigMap]{
+		CreateFunc: func(event.TypedCreateEvent[*corev1.ConfigMap]) bool { return false },
+		UpdateFunc: func(e event.TypedUpdateEvent[*corev1.ConfigMap]) bool {
+			oldCM, newCM := e.ObjectOld, e.ObjectNew
+			if oldCM == nil || newCM == nil {
+				return false
+			}
+			if !isPoolWorkload(oldCM) && !isPoolWorkload(newCM) {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(oldCM.Data, newCM.Data) ||
+				!equality.Semantic.DeepEqual(oldCM.BinaryData, newCM.BinaryData) ||
+				poolWorkloadMetadataChanged(oldCM, newCM)
+		},
+		DeleteFunc:  func(e event.TypedDeleteEvent[*corev1.ConfigMap]) bool { return isPoolWorkload(e.Object) },
+		GenericFunc: func(event.TypedGenericEvent[*corev1.ConfigMap]) bool { return false },
+	}
+}
+
+func poolWorkloadMetadataChanged(oldObj, newObj client.Object) bool {
+	return !equality.Semantic.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) ||
+		!equality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()) ||
+		!equality.Semantic.DeepEqual(oldObj.GetOwnerReferences(), newObj.GetOwnerReferences())
+}
+
+func isPoolWorkload(obj client.Object) bool {
+	if obj == nil {
+		return false
+	}
+	labels := obj.GetLabels()
+	if _, ok := poolWorkloadApps[labels["app"]]; !ok {
+		return false
+	}
+	return strings.TrimSpace(labels["pool"]) != ""
+}
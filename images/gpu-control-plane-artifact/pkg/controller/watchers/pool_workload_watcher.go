@@ -0,0 +1,94 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+type workloadEnqueuer interface {
+	EnqueueRequests(ctx context.Context, obj client.Object) []reconcile.Request
+}
+
+// watchPoolWorkloads requeues the owning pool when one of its rendered DaemonSets or ConfigMaps is deleted or
+// edited out of band, so the renderer restores it without waiting for a spec change or resync.
+func watchPoolWorkloads(mgr manager.Manager, ctr controller.Controller, enqueuer workloadEnqueuer) error {
+	cache := mgr.GetCache()
+	if cache == nil {
+		return fmt.Errorf("manager cache is required")
+	}
+
+	filter := NewPoolWorkloadFilter()
+	if err := ctr.Watch(
+		source.Kind(
+			cache,
+			&appsv1.DaemonSet{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, ds *appsv1.DaemonSet) []reconcile.Request {
+				return enqueuer.EnqueueRequests(ctx, ds)
+			}),
+			filter.DaemonSetPredicates(),
+		),
+	); err != nil {
+		return fmt.Errorf("watch DaemonSets: %w", err)
+	}
+	if err := ctr.Watch(
+		source.Kind(
+			cache,
+			&corev1.ConfigMap{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, cm *corev1.ConfigMap) []reconcile.Request {
+				return enqueuer.EnqueueRequests(ctx, cm)
+			}),
+			filter.ConfigMapPredicates(),
+		),
+	); err != nil {
+		return fmt.Errorf("watch ConfigMaps: %w", err)
+	}
+	return nil
+}
+
+type GPUPoolWorkloadWatcher struct {
+	log logr.Logger
+}
+
+func NewGPUPoolWorkloadWatcher(log logr.Logger) *GPUPoolWorkloadWatcher {
+	return &GPUPoolWorkloadWatcher{log: log}
+}
+
+func (w *GPUPoolWorkloadWatcher) Watch(mgr manager.Manager, ctr controller.Controller) error {
+	return watchPoolWorkloads(mgr, ctr, NewGPUPoolWorkloadEnqueuer(w.log, mgr.GetClient()))
+}
+
+type ClusterGPUPoolWorkloadWatcher struct {
+	log logr.Logger
+}
+
+func NewClusterGPUPoolWorkloadWatcher(log logr.Logger) *ClusterGPUPoolWorkloadWatcher {
+	return &ClusterGPUPoolWorkloadWatcher{log: log}
+}
+
+func (w *ClusterGPUPoolWorkloadWatcher) Watch(mgr manager.Manager, ctr controller.Controller) error {
+	return watchPoolWorkloads(mgr, ctr, NewClusterGPUPoolWorkloadEnqueuer())
+}
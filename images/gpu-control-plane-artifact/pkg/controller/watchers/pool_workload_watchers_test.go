@@ -0,0 +1,224 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+func poolDaemonSet(pool string, owners ...metav1.OwnerReference) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "nvidia-device-plugin-" + pool,
+		Namespace:       "d8-gpu-control-plane",
+		Labels:          map[string]string{"app": "nvidia-device-plugin", "pool": pool},
+		OwnerReferences: owners,
+		Generation:      1,
+	}}
+}
+
+func poolOwnerRef(kind, name string) metav1.OwnerReference {
+	return metav1.OwnerReference{APIVersion: v1alpha1.GroupVersion.String(), Kind: kind, Name: name}
+}
+
+func TestPoolWorkloadDaemonSetPredicates(t *testing.T) {
+	p := NewPoolWorkloadFilter().DaemonSetPredicates()
+	ds := poolDaemonSet("alpha")
+	foreign := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"app": "other", "pool": "alpha"}}}
+
+	if p.Create(event.TypedCreateEvent[*appsv1.DaemonSet]{Object: ds}) {
+		t.Fatalf("expected creations to be ignored")
+	}
+	if !p.Delete(event.TypedDeleteEvent[*appsv1.DaemonSet]{Object: ds}) {
+		t.Fatalf("expected deletion of a pool DaemonSet to pass")
+	}
+	if p.Delete(event.TypedDeleteEvent[*appsv1.DaemonSet]{Object: foreign}) {
+		t.Fatalf("expected deletion of a foreign DaemonSet to be ignored")
+	}
+
+	statusOnly := ds.DeepCopy()
+	statusOnly.ResourceVersion = "2"
+	statusOnly.Status.NumberReady = 3
+	statusOnly.Status.DesiredNumberScheduled = 3
+	if p.Update(event.TypedUpdateEvent[*appsv1.DaemonSet]{ObjectOld: ds, ObjectNew: statusOnly}) {
+		t.Fatalf("expected status-only updates to be ignored")
+	}
+
+	specEdit := ds.DeepCopy()
+	specEdit.Generation = 2
+	if !p.Update(event.TypedUpdateEvent[*appsv1.DaemonSet]{ObjectOld: ds, ObjectNew: specEdit}) {
+		t.Fatalf("expected spec edits to pass")
+	}
+
+	relabeled := ds.DeepCopy()
+	relabeled.Labels["pool"] = "beta"
+	if !p.Update(event.TypedUpdateEvent[*appsv1.DaemonSet]{ObjectOld: ds, ObjectNew: relabeled}) {
+		t.Fatalf("expected label edits to pass")
+	}
+
+	disowned := poolDaemonSet("alpha", poolOwnerRef("ClusterGPUPool", "alpha"))
+	if !p.Update(event.TypedUpdateEvent[*appsv1.DaemonSet]{ObjectOld: disowned, ObjectNew: ds}) {
+		t.Fatalf("expected owner reference removal to pass")
+	}
+
+	foreignEdit := foreign.DeepCopy()
+	foreignEdit.Generation = 5
+	if p.Update(event.TypedUpdateEvent[*appsv1.DaemonSet]{ObjectOld: foreign, ObjectNew: foreignEdit}) {
+		t.Fatalf("expected foreign DaemonSet edits to be ignored")
+	}
+	if p.Update(event.TypedUpdateEvent[*appsv1.DaemonSet]{ObjectOld: nil, ObjectNew: ds}) {
+		t.Fatalf("expected nil old object to be ignored")
+	}
+	if p.Generic(event.TypedGenericEvent[*appsv1.DaemonSet]{Object: ds}) {
+		t.Fatalf("expected generic events to be ignored")
+	}
+}
+
+func TestPoolWorkloadConfigMapPredicates(t *testing.T) {
+	p := NewPoolWorkloadFilter().ConfigMapPredicates()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-alpha-config", Labels: map[string]string{"app": "nvidia-device-plugin", "pool": "alpha"}},
+		Data:       map[string]string{"config.yaml": "version: v1"},
+	}
+
+	if p.Create(event.TypedCreateEvent[*corev1.ConfigMap]{Object: cm}) {
+		t.Fatalf("expected creations to be ignored")
+	}
+	if !p.Delete(event.TypedDeleteEvent[*corev1.ConfigMap]{Object: cm}) {
+		t.Fatalf("expected deletion of a pool ConfigMap to pass")
+	}
+	if p.Delete(event.TypedDeleteEvent[*corev1.ConfigMap]{Object: &corev1.ConfigMap{}}) {
+		t.Fatalf("expected deletion of an unrelated ConfigMap to be ignored")
+	}
+
+	touched := cm.DeepCopy()
+	touched.ResourceVersion = "7"
+	if p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{ObjectOld: cm, ObjectNew: touched}) {
+		t.Fatalf("expected resourceVersion-only updates to be ignored")
+	}
+
+	edited := cm.DeepCopy()
+	edited.Data["config.yaml"] = "version: v2"
+	if !p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{ObjectOld: cm, ObjectNew: edited}) {
+		t.Fatalf("expected data edits to pass")
+	}
+
+	annotated := cm.DeepCopy()
+	annotated.Annotations = map[string]string{"gpu.deckhouse.io/unmanaged": "true"}
+	if !p.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{ObjectOld: cm, ObjectNew: annotated}) {
+		t.Fatalf("expected annotation edits to pass")
+	}
+	if p.Generic(event.TypedGenericEvent[*corev1.ConfigMap]{Object: cm}) {
+		t.Fatalf("expected generic events to be ignored")
+	}
+}
+
+func TestGPUPoolWorkloadEnqueuer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	obj, field, extract := indexer.IndexGPUPoolByName()
+	cl := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "alpha", Namespace: "team-a"}},
+			&v1alpha1.GPUPool{ObjectMeta: metav1.ObjectMeta{Name: "beta", Namespace: "team-b"}},
+		).
+		WithIndex(obj, field, extract).
+		Build()
+	e := NewGPUPoolWorkloadEnqueuer(testr.New(t), cl)
+
+	reqs := e.EnqueueRequests(context.Background(), poolDaemonSet("alpha"))
+	if len(reqs) != 1 || reqs[0].NamespacedName != (types.NamespacedName{Namespace: "team-a", Name: "alpha"}) {
+		t.Fatalf("expected the labelled pool to be enqueued, got %+v", reqs)
+	}
+
+	reqs = e.EnqueueRequests(context.Background(), poolDaemonSet("gamma", poolOwnerRef("GPUPool", "gamma")))
+	if len(reqs) != 1 || reqs[0].NamespacedName != (types.NamespacedName{Namespace: "d8-gpu-control-plane", Name: "gamma"}) {
+		t.Fatalf("expected the owning pool to be enqueued, got %+v", reqs)
+	}
+
+	if reqs := e.EnqueueRequests(context.Background(), poolDaemonSet("beta", poolOwnerRef("ClusterGPUPool", "beta"))); len(reqs) != 0 {
+		t.Fatalf("expected cluster pool objects to be skipped, got %+v", reqs)
+	}
+	if reqs := e.EnqueueRequests(context.Background(), &appsv1.DaemonSet{}); len(reqs) != 0 {
+		t.Fatalf("expected unrelated objects to be skipped, got %+v", reqs)
+	}
+	if reqs := NewGPUPoolWorkloadEnqueuer(testr.New(t), nil).EnqueueRequests(context.Background(), poolDaemonSet("alpha")); len(reqs) != 0 {
+		t.Fatalf("expected no requests without a client, got %+v", reqs)
+	}
+
+	failing := NewGPUPoolWorkloadEnqueuer(testr.New(t), &failingListClient{Client: cl, err: errors.New("list failed")})
+	if reqs := failing.EnqueueRequests(context.Background(), poolDaemonSet("alpha")); len(reqs) != 0 {
+		t.Fatalf("expected no requests on list error, got %+v", reqs)
+	}
+}
+
+func TestClusterGPUPoolWorkloadEnqueuer(t *testing.T) {
+	e := NewClusterGPUPoolWorkloadEnqueuer()
+
+	reqs := e.EnqueueRequests(context.Background(), poolDaemonSet("shared", poolOwnerRef("ClusterGPUPool", "shared")))
+	if len(reqs) != 1 || reqs[0].NamespacedName != (types.NamespacedName{Name: "shared"}) {
+		t.Fatalf("expected the owning cluster pool to be enqueued, got %+v", reqs)
+	}
+	if reqs := e.EnqueueRequests(context.Background(), poolDaemonSet("alpha")); len(reqs) != 0 {
+		t.Fatalf("expected objects without a cluster pool owner to be skipped, got %+v", reqs)
+	}
+	if reqs := e.EnqueueRequests(context.Background(), poolDaemonSet("alpha", poolOwnerRef("GPUPool", "alpha"))); len(reqs) != 0 {
+		t.Fatalf("expected namespaced pool objects to be skipped, got %+v", reqs)
+	}
+}
+
+func TestPoolWorkloadWatchersRegisterSources(t *testing.T) {
+	for name, w := range map[string]interface {
+		Watch(mgr manager.Manager, ctr controller.Controller) error
+	}{
+		"GPUPool":        NewGPUPoolWorkloadWatcher(testr.New(t)),
+		"ClusterGPUPool": NewClusterGPUPoolWorkloadWatcher(testr.New(t)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctr := &stubController{}
+			if err := w.Watch(&stubManager{cache: &fakeCache{}}, ctr); err != nil {
+				t.Fatalf("Watch: %v", err)
+			}
+			if len(ctr.watched) != 2 {
+				t.Fatalf("expected DaemonSet and ConfigMap sources, got %d", len(ctr.watched))
+			}
+
+			if err := w.Watch(&stubManager{}, &stubController{}); err == nil {
+				t.Fatalf("expected an error without a cache")
+			}
+			if err := w.Watch(&stubManager{cache: &fakeCache{}}, &stubController{err: errors.New("watch failed")}); err == nil {
+				t.Fatalf("expected the watch error to be returned")
+			}
+		})
+	}
+}
@@ -17,7 +17,13 @@ package watchers
 import (
 	"context"
 
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 type failingListClient struct {
@@ -28,3 +34,38 @@ type failingListClient struct {
 func (f *failingListClient) List(context.Context, client.ObjectList, ...client.ListOption) error {
 	return f.err
 }
+
+type fakeCache struct{ cache.Cache }
+
+type stubManager struct {
+	manager.Manager
+	cache  cache.Cache
+	client client.Client
+}
+
+func (m *stubManager) GetCache() cache.Cache { return m.cache }
+
+func (m *stubManager) GetClient() client.Client { return m.client }
+
+type stubController struct {
+	watched []source.Source
+	err     error
+}
+
+func (c *stubController) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (c *stubController) Watch(src source.Source) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.watched = append(c.watched, src)
+	return nil
+}
+
+func (c *stubController) Start(context.Context) error { return nil }
+
+func (c *stubController) GetLogger() logr.Logger { return logr.Discard() }
+
+var _ controller.Controller = (*stubController)(nil)
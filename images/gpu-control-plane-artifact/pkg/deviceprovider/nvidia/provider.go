@@ -0,0 +1,84 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nvidia is the device provider for NVIDIA GPUs: the PCI name catalog, the per-pool
+// device-plugin, validator and MIG manager, and the validator readiness check.
+package nvidia
+
+import (
+	"context"
+	"strings"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/cleanup"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deviceplugin"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/migmanager"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/validator"
+	nvidiacatalog "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/hardware/nvidia"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+// ProviderName is the pool spec.provider value served by this provider.
+const ProviderName = "Nvidia"
+
+// Provider implements deviceprovider.DeviceProvider for PCI vendor 10de.
+type Provider struct{}
+
+func New() *Provider {
+	return &Provider{}
+}
+
+func (*Provider) VendorID() string { return snapshot.VendorNvidia }
+
+func (*Provider) Name() string { return ProviderName }
+
+func (*Provider) Identify(device snapshot.Device) bool {
+	return strings.ToLower(device.Vendor) == snapshot.VendorNvidia
+}
+
+// Enrich names the product from the PCI catalog when the labels did not carry it.
+func (*Provider) Enrich(device *snapshot.Device) {
+	if device.Product != "" {
+		return
+	}
+	key := strings.ToLower(device.Vendor) + ":" + strings.ToLower(device.Device)
+	if name, ok := nvidiacatalog.DeviceNames[key]; ok {
+		device.Product = name
+	}
+}
+
+// RenderPool deploys the device-plugin and validator, plus the MIG manager for MIG pools.
+func (*Provider) RenderPool(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) error {
+	if err := deviceplugin.Reconcile(ctx, d, pool); err != nil {
+		return err
+	}
+	if err := validator.Reconcile(ctx, d, pool); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(pool.Spec.Resource.Unit, "MIG") {
+		return cleanup.MIGResources(ctx, d.Client, d.Config.Namespace, pool.Name)
+	}
+	if d.Config.MIGManagerImage == "" {
+		d.Log.Info("MIG pool detected but MIG manager image not configured, skipping MIG manager reconcile", "pool", pool.Name)
+		return nil
+	}
+	return migmanager.Reconcile(ctx, d, pool)
+}
+
+// HealthPodLabels selects the pool's nvidia-operator-validator pods.
+func (*Provider) HealthPodLabels(pool *v1alpha1.GPUPool) map[string]string {
+	return map[string]string{"app": "nvidia-operator-validator", "pool": pool.Name}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvidia
+
+import (
+	"testing"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+func TestIdentifyClaimsNvidiaOnly(t *testing.T) {
+	p := New()
+	if !p.Identify(snapshot.Device{Vendor: "10de"}) || !p.Identify(snapshot.Device{Vendor: "10DE"}) {
+		t.Fatalf("expected NVIDIA devices to be identified")
+	}
+	if p.Identify(snapshot.Device{Vendor: "1002"}) {
+		t.Fatalf("expected other vendors to be rejected")
+	}
+}
+
+func TestEnrichFillsProductFromCatalog(t *testing.T) {
+	p := New()
+
+	device := snapshot.Device{Vendor: "10de", Device: "1DB6"}
+	p.Enrich(&device)
+	if device.Product != "GV100GL [Tesla V100 PCIe 32GB]" {
+		t.Fatalf("expected catalog product, got %q", device.Product)
+	}
+
+	labelled := snapshot.Device{Vendor: "10de", Device: "1db6", Product: "Custom"}
+	p.Enrich(&labelled)
+	if labelled.Product != "Custom" {
+		t.Fatalf("expected labelled product to be kept, got %q", labelled.Product)
+	}
+
+	unknown := snapshot.Device{Vendor: "10de", Device: "ffff"}
+	p.Enrich(&unknown)
+	if unknown.Product != "" {
+		t.Fatalf("expected unknown device to stay unnamed, got %q", unknown.Product)
+	}
+}
+
+func TestHealthPodLabelsSelectValidator(t *testing.T) {
+	pool := &v1alpha1.GPUPool{}
+	pool.Name = "pool-a"
+	labels := New().HealthPodLabels(pool)
+	if labels["app"] != "nvidia-operator-validator" || labels["pool"] != "pool-a" {
+		t.Fatalf("unexpected health labels %v", labels)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deviceprovider keeps vendor-specific GPU logic behind DeviceProvider implementations
+// registered by PCI vendor ID. The inventory parser and the pool controllers dispatch through a
+// Registry; vendors without a provider fall back to Generic.
+package deviceprovider
+
+import (
+	"context"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+// DeviceProvider is the vendor-specific part of inventory and pool management.
+type DeviceProvider interface {
+	// VendorID is the lower-case PCI vendor ID the provider is registered under, e.g. "10de".
+	VendorID() string
+	// Name is the pool spec.provider value the provider serves, e.g. "Nvidia".
+	Name() string
+	// Identify reports whether a device announced through node labels belongs to the inventory.
+	Identify(device snapshot.Device) bool
+	// Enrich fills hardware fields the vendor derives from the PCI identifiers.
+	Enrich(device *snapshot.Device)
+	// RenderPool deploys the per-pool node components and removes the ones the pool no longer needs.
+	RenderPool(ctx context.Context, d deps.Deps, pool *v1alpha1.GPUPool) error
+	// HealthPodLabels selects the per-pool pods whose readiness on a node gates the pool's devices
+	// there; nil means the provider has no health check.
+	HealthPodLabels(pool *v1alpha1.GPUPool) map[string]string
+}
+
+// Generic serves vendors without a dedicated provider: devices keep their PCI data only, nothing is
+// rendered and no health check gates them. It does not claim devices during discovery.
+type Generic struct{}
+
+func (Generic) VendorID() string { return "" }
+
+func (Generic) Name() string { return "Generic" }
+
+func (Generic) Identify(snapshot.Device) bool { return false }
+
+func (Generic) Enrich(*snapshot.Device) {}
+
+func (Generic) RenderPool(context.Context, deps.Deps, *v1alpha1.GPUPool) error { return nil }
+
+func (Generic) HealthPodLabels(*v1alpha1.GPUPool) map[string]string { return nil }
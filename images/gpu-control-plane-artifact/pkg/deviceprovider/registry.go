@@ -0,0 +1,121 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceprovider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/deviceprovider/nvidia"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+// DefaultPoolProvider is the provider of pools that leave spec.provider empty, matching the CRD default.
+const DefaultPoolProvider = nvidia.ProviderName
+
+// Registry resolves providers by PCI vendor ID and by pool provider name.
+type Registry struct {
+	mu       sync.RWMutex
+	byVendor map[string]DeviceProvider
+	byName   map[string]DeviceProvider
+}
+
+var _ snapshot.Vendors = (*Registry)(nil)
+
+// NewRegistry registers the given providers; it fails on the same conditions as Register.
+func NewRegistry(providers ...DeviceProvider) (*Registry, error) {
+	r := &Registry{byVendor: map[string]DeviceProvider{}, byName: map[string]DeviceProvider{}}
+	for _, provider := range providers {
+		if err := r.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+var defaultRegistry = sync.OnceValue(func() *Registry {
+	r, err := NewRegistry(nvidia.New())
+	if err != nil {
+		panic(err)
+	}
+	return r
+})
+
+// Default returns the registry with the built-in providers.
+func Default() *Registry {
+	return defaultRegistry()
+}
+
+// Register adds a provider. A vendor ID or provider name may only be registered once.
+func (r *Registry) Register(provider DeviceProvider) error {
+	vendor := strings.ToLower(strings.TrimSpace(provider.VendorID()))
+	name := provider.Name()
+	if vendor == "" || name == "" {
+		return fmt.Errorf("device provider %q: vendor ID and name are required", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.byVendor[vendor]; ok {
+		return fmt.Errorf("device provider %q: vendor %s is already served by %q", name, vendor, existing.Name())
+	}
+	if _, ok := r.byName[name]; ok {
+		return fmt.Errorf("device provider %q is already registered", name)
+	}
+	r.byVendor[vendor] = provider
+	r.byName[name] = provider
+	return nil
+}
+
+// ForVendor returns the provider of a PCI vendor ID, Generic when none is registered.
+func (r *Registry) ForVendor(vendor string) DeviceProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if provider, ok := r.byVendor[strings.ToLower(strings.TrimSpace(vendor))]; ok {
+		return provider
+	}
+	return Generic{}
+}
+
+// Lookup returns the provider serving a pool spec.provider value; empty selects DefaultPoolProvider.
+func (r *Registry) Lookup(name string) (DeviceProvider, bool) {
+	if name == "" {
+		name = DefaultPoolProvider
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.byName[name]
+	return provider, ok
+}
+
+// ForPool returns the provider of the pool, Generic when its provider is not registered.
+func (r *Registry) ForPool(pool *v1alpha1.GPUPool) DeviceProvider {
+	if provider, ok := r.Lookup(pool.Spec.Provider); ok {
+		return provider
+	}
+	return Generic{}
+}
+
+// Identify dispatches to the provider of the device vendor.
+func (r *Registry) Identify(device snapshot.Device) bool {
+	return r.ForVendor(device.Vendor).Identify(device)
+}
+
+// Enrich dispatches to the provider of the device vendor.
+func (r *Registry) Enrich(device *snapshot.Device) {
+	r.ForVendor(device.Vendor).Enrich(device)
+}
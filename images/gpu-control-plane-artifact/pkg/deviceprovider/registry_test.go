@@ -0,0 +1,145 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deviceprovider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/service/pool/deps"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/inventory/snapshot"
+)
+
+type fakeProvider struct {
+	vendor   string
+	name     string
+	enriched []string
+}
+
+func (p *fakeProvider) VendorID() string { return p.vendor }
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Identify(snapshot.Device) bool { return true }
+
+func (p *fakeProvider) Enrich(device *snapshot.Device) {
+	p.enriched = append(p.enriched, device.Device)
+	device.Product = p.name + "-" + device.Device
+}
+
+func (p *fakeProvider) RenderPool(context.Context, deps.Deps, *v1alpha1.GPUPool) error { return nil }
+
+func (p *fakeProvider) HealthPodLabels(pool *v1alpha1.GPUPool) map[string]string {
+	return map[string]string{"pool": pool.Name}
+}
+
+func TestRegistryDispatchesByVendor(t *testing.T) {
+	amd := &fakeProvider{vendor: "1002", name: "AMD"}
+	intel := &fakeProvider{vendor: "8086", name: "Intel"}
+	r, err := NewRegistry(amd, intel)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	device := snapshot.Device{Vendor: "1002", Device: "74a1"}
+	if !r.Identify(device) {
+		t.Fatalf("expected device of a registered vendor to be identified")
+	}
+	r.Enrich(&device)
+	if device.Product != "AMD-74a1" || len(amd.enriched) != 1 || len(intel.enriched) != 0 {
+		t.Fatalf("expected AMD provider to enrich the device, got product=%q amd=%v intel=%v", device.Product, amd.enriched, intel.enriched)
+	}
+	if got := r.ForVendor("8086 "); got != intel {
+		t.Fatalf("expected vendor lookup to trim input, got %v", got)
+	}
+	if got := r.ForVendor("1002"); got != amd {
+		t.Fatalf("expected AMD provider, got %v", got)
+	}
+
+	pool := &v1alpha1.GPUPool{Spec: v1alpha1.GPUPoolSpec{Provider: "Intel"}}
+	pool.Name = "pool-a"
+	if got := r.ForPool(pool); got != intel {
+		t.Fatalf("expected Intel provider for pool, got %v", got)
+	}
+}
+
+func TestRegistryRejectsDoubleRegistration(t *testing.T) {
+	r, err := NewRegistry(&fakeProvider{vendor: "1002", name: "AMD"})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	if err := r.Register(&fakeProvider{vendor: "1002", name: "Other"}); err == nil || !strings.Contains(err.Error(), "already served") {
+		t.Fatalf("expected duplicate vendor error, got %v", err)
+	}
+	if err := r.Register(&fakeProvider{vendor: "1002", name: "AMD"}); err == nil {
+		t.Fatalf("expected duplicate provider error")
+	}
+	if err := r.Register(&fakeProvider{vendor: "8086", name: "AMD"}); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+	if err := r.Register(&fakeProvider{name: "NoVendor"}); err == nil {
+		t.Fatalf("expected error for provider without vendor ID")
+	}
+	if _, err := NewRegistry(&fakeProvider{vendor: "1002", name: "A"}, &fakeProvider{vendor: "1002", name: "B"}); err == nil {
+		t.Fatalf("expected NewRegistry to fail on duplicate vendor")
+	}
+}
+
+func TestRegistryFallsBackToGeneric(t *testing.T) {
+	r, err := NewRegistry(&fakeProvider{vendor: "1002", name: "AMD"})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	if _, ok := r.ForVendor("abcd").(Generic); !ok {
+		t.Fatalf("expected Generic provider for unknown vendor")
+	}
+	device := snapshot.Device{Vendor: "abcd", Device: "0001"}
+	if r.Identify(device) {
+		t.Fatalf("expected Generic provider not to claim devices")
+	}
+	r.Enrich(&device)
+	if device.Product != "" {
+		t.Fatalf("expected Generic provider to leave the device as is, got %q", device.Product)
+	}
+
+	pool := &v1alpha1.GPUPool{Spec: v1alpha1.GPUPoolSpec{Provider: "Unknown"}}
+	provider := r.ForPool(pool)
+	if _, ok := provider.(Generic); !ok {
+		t.Fatalf("expected Generic provider for unknown pool provider, got %v", provider)
+	}
+	if labels := provider.HealthPodLabels(pool); labels != nil {
+		t.Fatalf("expected Generic provider without health check, got %v", labels)
+	}
+	if err := provider.RenderPool(context.Background(), deps.Deps{}, pool); err != nil {
+		t.Fatalf("expected Generic RenderPool to be a no-op, got %v", err)
+	}
+	if _, ok := r.Lookup("Unknown"); ok {
+		t.Fatalf("expected Lookup to report unknown provider")
+	}
+}
+
+func TestDefaultRegistryServesNvidia(t *testing.T) {
+	provider, ok := Default().Lookup("")
+	if !ok || provider.Name() != DefaultPoolProvider {
+		t.Fatalf("expected empty provider to resolve to %s, got %v (ok=%t)", DefaultPoolProvider, provider, ok)
+	}
+	if got := Default().ForVendor(snapshot.VendorNvidia); got != provider {
+		t.Fatalf("expected NVIDIA vendor to resolve to the default pool provider, got %v", got)
+	}
+}
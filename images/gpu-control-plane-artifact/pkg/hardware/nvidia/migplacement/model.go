@@ -0,0 +1,92 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migplacement
+
+import (
+	"strings"
+)
+
+// Profile describes where a MIG profile can be placed: it occupies Size consecutive memory slices
+// starting at one of Starts.
+type Profile struct {
+	Name   string
+	Size   int
+	Starts []int
+}
+
+// Model is the MIG placement table of one GPU model, as published in the NVIDIA MIG user guide.
+type Model struct {
+	Name     string
+	Slices   int
+	Profiles []Profile
+}
+
+// eightSliceModel builds the table shared by A100 and H100: both split the GPU into 8 memory slices
+// with the same placement rules and differ only in profile names.
+func eightSliceModel(name, full, four, three, two, oneDouble, one string) Model {
+	return Model{
+		Name:   name,
+		Slices: 8,
+		Profiles: []Profile{
+			{Name: full, Size: 8, Starts: []int{0}},
+			{Name: four, Size: 4, Starts: []int{0}},
+			{Name: three, Size: 4, Starts: []int{0, 4}},
+			{Name: two, Size: 2, Starts: []int{0, 2, 4}},
+			{Name: oneDouble, Size: 2, Starts: []int{0, 2, 4, 6}},
+			{Name: one, Size: 1, Starts: []int{0, 1, 2, 3, 4, 5, 6}},
+		},
+	}
+}
+
+var models = map[string]Model{
+	"A100-40GB": eightSliceModel("A100-40GB", "7g.40gb", "4g.20gb", "3g.20gb", "2g.10gb", "1g.10gb", "1g.5gb"),
+	"A100-80GB": eightSliceModel("A100-80GB", "7g.80gb", "4g.40gb", "3g.40gb", "2g.20gb", "1g.20gb", "1g.10gb"),
+	"H100-80GB": eightSliceModel("H100-80GB", "7g.80gb", "4g.40gb", "3g.40gb", "2g.20gb", "1g.20gb", "1g.10gb"),
+	"H100-94GB": eightSliceModel("H100-94GB", "7g.94gb", "4g.47gb", "3g.47gb", "2g.24gb", "1g.24gb", "1g.12gb"),
+}
+
+// ModelFor picks the placement table for a GPU by its product name and memory size.
+func ModelFor(product string, memoryMiB int32) (Model, bool) {
+	product = strings.ToUpper(product)
+	gib := memoryMiB / 1024
+	switch {
+	case strings.Contains(product, "A100"):
+		if gib > 0 && gib <= 48 {
+			return models["A100-40GB"], true
+		}
+		return models["A100-80GB"], true
+	case strings.Contains(product, "H100"):
+		if gib > 88 {
+			return models["H100-94GB"], true
+		}
+		return models["H100-80GB"], true
+	}
+	return Model{}, false
+}
+
+// Profile returns the placement rules of a profile. Media-extension variants such as 1g.10gb+me share
+// the placements of their base profile.
+func (m Model) Profile(name string) (Profile, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if base, _, ok := strings.Cut(name, "+"); ok {
+		name = base
+	}
+	for _, p := range m.Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migplacement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// Layout is the set of memory slices of a GPU occupied by MIG instances.
+type Layout struct {
+	model Model
+	used  []bool
+}
+
+// Empty returns the layout of a GPU without MIG instances.
+func Empty(m Model) Layout {
+	return Layout{model: m, used: make([]bool, m.Slices)}
+}
+
+// Pack reconstructs a layout from per-profile instance counts. GFD only reports counts, not where the
+// instances sit, so they are packed the way the MIG manager creates them: larger profiles first, each
+// at the lowest free start.
+func Pack(m Model, counts map[string]int32) (Layout, error) {
+	type request struct {
+		profile Profile
+		count   int32
+	}
+	requests := make([]request, 0, len(counts))
+	for name, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		p, ok := m.Profile(name)
+		if !ok {
+			return Layout{}, fmt.Errorf("profile %q is unknown for %s", name, m.Name)
+		}
+		requests = append(requests, request{profile: p, count: count})
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		a, b := requests[i].profile, requests[j].profile
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if len(a.Starts) != len(b.Starts) {
+			return len(a.Starts) < len(b.Starts)
+		}
+		return a.Name < b.Name
+	})
+
+	layout := Empty(m)
+	for _, req := range requests {
+		for i := int32(0); i < req.count; i++ {
+			if !layout.place(req.profile) {
+				return Layout{}, fmt.Errorf("%d instances of %s do not fit on %s", req.count, req.profile.Name, m.Name)
+			}
+		}
+	}
+	return layout, nil
+}
+
+// Available returns for every profile of the model how many more instances fit into the free slices.
+func (l Layout) Available() map[string]int32 {
+	out := make(map[string]int32, len(l.model.Profiles))
+	for _, p := range l.model.Profiles {
+		probe := Layout{model: l.model, used: append([]bool(nil), l.used...)}
+		var n int32
+		for probe.place(p) {
+			n++
+		}
+		out[p.Name] = n
+	}
+	return out
+}
+
+func (l Layout) place(p Profile) bool {
+	for _, start := range p.Starts {
+		if l.free(start, p.Size) {
+			for i := start; i < start+p.Size; i++ {
+				l.used[i] = true
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (l Layout) free(start, size int) bool {
+	if start+size > len(l.used) {
+		return false
+	}
+	for i := start; i < start+size; i++ {
+		if l.used[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ForDevice returns the profiles that could still be created on a device given its current MIG
+// instances. A MIG-capable device without instances offers its full profile set. ok is false for
+// devices that are not MIG capable, have no known placement table or report an impossible layout.
+func ForDevice(hw v1alpha1.GPUDeviceHardware) (map[string]int32, bool) {
+	if !hw.MIG.Capable {
+		return nil, false
+	}
+	m, ok := ModelFor(hw.Product, hw.MemoryMiB)
+	if !ok {
+		return nil, false
+	}
+	counts := make(map[string]int32, len(hw.MIG.Types))
+	for _, t := range hw.MIG.Types {
+		counts[t.Name] += t.Count
+	}
+	layout, err := Pack(m, counts)
+	if err != nil {
+		return nil, false
+	}
+	return layout.Available(), true
+}
+
+// CheckLayout reports whether a per-device layout, given as instance counts per profile, can be created on
+// the device. checked is false when the device has no known placement table and the layout cannot be judged.
+func CheckLayout(hw v1alpha1.GPUDeviceHardware, counts map[string]int32) (checked bool, err error) {
+	m, ok := ModelFor(hw.Product, hw.MemoryMiB)
+	if !ok {
+		return false, nil
+	}
+	_, err = Pack(m, counts)
+	return true, err
+}
+
+// Achievable returns an error when the layout fits none of the known GPU models that offer all of its
+// profiles. A layout whose profiles no known model offers together is not judged: it may target a GPU
+// without a placement table here.
+func Achievable(counts map[string]int32) error {
+	names := make([]string, 0, len(models))
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var reasons []string
+	for _, name := range names {
+		m := models[name]
+		if !offersAll(m, counts) {
+			continue
+		}
+		if _, err := Pack(m, counts); err != nil {
+			reasons = append(reasons, err.Error())
+			continue
+		}
+		return nil
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return fmt.Errorf("layout fits no GPU model offering its profiles: %s", strings.Join(reasons, "; "))
+}
+
+func offersAll(m Model, counts map[string]int32) bool {
+	for name := range counts {
+		if _, ok := m.Profile(name); !ok {
+			return false
+		}
+	}
+	return true
+}
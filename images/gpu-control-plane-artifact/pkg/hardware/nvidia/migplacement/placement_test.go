@@ -0,0 +1,171 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migplacement
+
+import (
+	"reflect"
+	"testing"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestModelFor(t *testing.T) {
+	tests := []struct {
+		product string
+		memory  int32
+		want    string
+	}{
+		{product: "NVIDIA A100-PCIE-40GB", memory: 40960, want: "A100-40GB"},
+		{product: "NVIDIA A100-SXM4-80GB", memory: 81920, want: "A100-80GB"},
+		{product: "NVIDIA H100 80GB HBM3", memory: 81559, want: "H100-80GB"},
+		{product: "NVIDIA H100 NVL", memory: 95830, want: "H100-94GB"},
+		{product: "Tesla T4", memory: 15360},
+	}
+	for _, tc := range tests {
+		m, ok := ModelFor(tc.product, tc.memory)
+		if ok != (tc.want != "") || m.Name != tc.want {
+			t.Fatalf("ModelFor(%q, %d) = %q/%t, want %q", tc.product, tc.memory, m.Name, ok, tc.want)
+		}
+	}
+}
+
+func TestPackAvailableA100Matrix(t *testing.T) {
+	m := models["A100-40GB"]
+	tests := []struct {
+		name   string
+		counts map[string]int32
+		want   map[string]int32
+	}{
+		{
+			name:   "empty",
+			counts: nil,
+			want:   map[string]int32{"7g.40gb": 1, "4g.20gb": 1, "3g.20gb": 2, "2g.10gb": 3, "1g.10gb": 4, "1g.5gb": 7},
+		},
+		{
+			name:   "one 3g",
+			counts: map[string]int32{"3g.20gb": 1},
+			want:   map[string]int32{"7g.40gb": 0, "4g.20gb": 0, "3g.20gb": 1, "2g.10gb": 1, "1g.10gb": 2, "1g.5gb": 3},
+		},
+		{
+			name:   "4g and 3g",
+			counts: map[string]int32{"4g.20gb": 1, "3g.20gb": 1},
+			want:   map[string]int32{"7g.40gb": 0, "4g.20gb": 0, "3g.20gb": 0, "2g.10gb": 0, "1g.10gb": 0, "1g.5gb": 0},
+		},
+		{
+			name:   "three 2g",
+			counts: map[string]int32{"2g.10gb": 3},
+			want:   map[string]int32{"7g.40gb": 0, "4g.20gb": 0, "3g.20gb": 0, "2g.10gb": 0, "1g.10gb": 1, "1g.5gb": 1},
+		},
+		{
+			name:   "seven 1g leave the last slice unusable",
+			counts: map[string]int32{"1g.5gb": 7},
+			want:   map[string]int32{"7g.40gb": 0, "4g.20gb": 0, "3g.20gb": 0, "2g.10gb": 0, "1g.10gb": 0, "1g.5gb": 0},
+		},
+		{
+			name:   "mixed with media extensions",
+			counts: map[string]int32{"2g.10gb": 1, "1g.5gb+me": 1, "1g.5gb": 1},
+			want:   map[string]int32{"7g.40gb": 0, "4g.20gb": 0, "3g.20gb": 1, "2g.10gb": 1, "1g.10gb": 2, "1g.5gb": 3},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			layout, err := Pack(m, tc.counts)
+			if err != nil {
+				t.Fatalf("Pack returned error: %v", err)
+			}
+			if got := layout.Available(); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected availability:\n got %v\nwant %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPackAvailableH100Matrix(t *testing.T) {
+	layout, err := Pack(models["H100-80GB"], map[string]int32{"3g.40gb": 1, "1g.20gb": 1})
+	if err != nil {
+		t.Fatalf("Pack returned error: %v", err)
+	}
+	want := map[string]int32{"7g.80gb": 0, "4g.40gb": 0, "3g.40gb": 0, "2g.20gb": 0, "1g.20gb": 1, "1g.10gb": 1}
+	if got := layout.Available(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected availability:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestPackRejectsImpossibleLayouts(t *testing.T) {
+	m := models["A100-40GB"]
+	if _, err := Pack(m, map[string]int32{"3g.20gb": 3}); err == nil {
+		t.Fatalf("expected three 3g instances not to fit")
+	}
+	if _, err := Pack(m, map[string]int32{"7g.40gb": 1, "1g.5gb": 1}); err == nil {
+		t.Fatalf("expected 1g next to 7g not to fit")
+	}
+	if _, err := Pack(m, map[string]int32{"5g.25gb": 1}); err == nil {
+		t.Fatalf("expected unknown profile to be rejected")
+	}
+}
+
+func TestForDevice(t *testing.T) {
+	hw := v1alpha1.GPUDeviceHardware{
+		Product:   "NVIDIA A100-SXM4-80GB",
+		MemoryMiB: 81920,
+		MIG:       v1alpha1.GPUMIGConfig{Capable: true},
+	}
+	got, ok := ForDevice(hw)
+	if !ok || got["7g.80gb"] != 1 || got["1g.10gb"] != 7 {
+		t.Fatalf("expected full profile set for a device without instances, got %v/%t", got, ok)
+	}
+
+	hw.MIG.Types = []v1alpha1.GPUMIGTypeCapacity{{Name: "3g.40gb", Count: 2}}
+	got, ok = ForDevice(hw)
+	if !ok || got["3g.40gb"] != 0 || got["1g.10gb"] != 0 {
+		t.Fatalf("expected a full GPU to offer nothing, got %v/%t", got, ok)
+	}
+
+	hw.MIG.Types = []v1alpha1.GPUMIGTypeCapacity{{Name: "3g.40gb", Count: 3}}
+	if _, ok := ForDevice(hw); ok {
+		t.Fatalf("expected impossible layout to be skipped")
+	}
+	if _, ok := ForDevice(v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100-SXM4-80GB"}); ok {
+		t.Fatalf("expected devices without MIG support to be skipped")
+	}
+	if _, ok := ForDevice(v1alpha1.GPUDeviceHardware{Product: "NVIDIA L4", MIG: v1alpha1.GPUMIGConfig{Capable: true}}); ok {
+		t.Fatalf("expected devices without placement table to be skipped")
+	}
+}
+
+func TestCheckLayout(t *testing.T) {
+	a100 := v1alpha1.GPUDeviceHardware{Product: "NVIDIA A100-SXM4-80GB", MemoryMiB: 81920}
+	if checked, err := CheckLayout(a100, map[string]int32{"3g.40gb": 1, "1g.10gb": 3}); !checked || err != nil {
+		t.Fatalf("half 3g.40gb, rest 1g.10gb must fit: checked=%t err=%v", checked, err)
+	}
+	if checked, err := CheckLayout(a100, map[string]int32{"3g.40gb": 2, "1g.10gb": 2}); !checked || err == nil {
+		t.Fatalf("two 3g.40gb leave no room for 1g.10gb: checked=%t err=%v", checked, err)
+	}
+	if checked, err := CheckLayout(v1alpha1.GPUDeviceHardware{Product: "Tesla T4"}, map[string]int32{"1g.6gb": 1}); checked || err != nil {
+		t.Fatalf("unknown model must not be judged: checked=%t err=%v", checked, err)
+	}
+}
+
+func TestAchievable(t *testing.T) {
+	if err := Achievable(map[string]int32{"3g.40gb": 1, "1g.10gb": 3}); err != nil {
+		t.Fatalf("mixed layout fits an 80GB A100: %v", err)
+	}
+	if err := Achievable(map[string]int32{"4g.40gb": 1, "3g.40gb": 1, "1g.10gb": 1}); err == nil {
+		t.Fatalf("expected 4g+3g+1g to be rejected")
+	}
+	if err := Achievable(map[string]int32{"1g.6gb": 4}); err != nil {
+		t.Fatalf("profiles of GPUs without a placement table must not be judged: %v", err)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/canonical"
+)
+
+func (p *parser) extractDevices(labels map[string]string) []Device {
+	devices := make(map[string]Device)
+	for key, value := range labels {
+		suffix, ok := strings.CutPrefix(key, DeviceLabelPrefix)
+		if !ok {
+			continue
+		}
+		parts := strings.SplitN(suffix, ".", 2)
+		if len(parts) != 2 {
+			p.drop(labelSource, key, value, "device label has no field")
+			continue
+		}
+		index := canonicalIndex(parts[0])
+		field := parts[1]
+
+		info := devices[index]
+		info.Index = index
+
+		switch field {
+		case "vendor":
+			info.Vendor = strings.ToLower(value)
+		case "device":
+			info.Device = strings.ToLower(value)
+		case "class":
+			info.Class = strings.ToLower(value)
+		case "product":
+			info.Product = value
+		case "memoryMiB":
+			info.MemoryMiB = p.memoryMiB(labelSource, key, value)
+		}
+
+		devices[index] = info
+	}
+
+	result := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		if device.Vendor != "" && p.vendors != nil && !p.vendors.Identify(device) {
+			continue
+		}
+		if device.Vendor == "" || device.Device == "" || device.Class == "" {
+			p.markIncomplete(labelSource, DeviceLabelPrefix+device.Index)
+			continue
+		}
+		result = append(result, device)
+	}
+
+	sortDevices(result)
+	return result
+}
+
+// markIncomplete records a device that lacks vendor, device or class identifiers. The error is
+// withdrawn when the NodeFeature instances describe the same device completely.
+func (p *parser) markIncomplete(source, key string) {
+	if p.incomplete == nil {
+		p.incomplete = map[string]Issue{}
+	}
+	p.incomplete[source+"|"+key] = Issue{Source: source, Key: key, Reason: "device has no vendor, device or class identifier"}
+}
+
+func (p *parser) hardwareDefaults(labels map[string]string) Device {
+	major, minor := p.computeCapability(labelSource, GFDComputeMajorLabel, GFDComputeMinorLabel,
+		labels[GFDComputeMajorLabel], labels[GFDComputeMinorLabel])
+	defaults := Device{
+		Product:      firstNonEmpty(labels[GFDProductLabel]),
+		MemoryMiB:    p.memoryMiB(labelSource, GFDMemoryLabel, labels[GFDMemoryLabel]),
+		ComputeMajor: major,
+		ComputeMinor: minor,
+		Board:        strings.TrimSpace(labels["nvidia.com/gpu.board"]),
+		Family:       strings.TrimSpace(labels["nvidia.com/gpu.family"]),
+		Serial:       strings.TrimSpace(labels["nvidia.com/gpu.serial"]),
+		PState:       strings.TrimSpace(labels["nvidia.com/gpu.pstate"]),
+		DisplayMode:  strings.TrimSpace(labels["nvidia.com/gpu.display_mode"]),
+		MIG:          p.migConfig(labels),
+	}
+	optional := func(key string) *int32 {
+		return p.optionalInt32(labelSource, key, labels[key])
+	}
+	defaults.NUMANode = optional("nvidia.com/gpu.numa.node")
+	defaults.PowerLimitMW = optional("nvidia.com/gpu.power.limit")
+	defaults.SMCount = optional("nvidia.com/gpu.sm.count")
+	defaults.MemBandwidth = optional("nvidia.com/gpu.memory.bandwidth")
+	defaults.PCIEGen = optional("nvidia.com/gpu.pcie.gen")
+	defaults.PCIELinkWid = optional("nvidia.com/gpu.pcie.link.width")
+
+	if !defaults.MIG.Capable && len(defaults.MIG.Types) > 0 {
+		defaults.MIG.Capable = true
+	}
+	if !defaults.MIG.Capable && len(defaults.MIG.ProfilesSupported) > 0 {
+		defaults.MIG.Capable = true
+	}
+	if !migConfigEmpty(defaults.MIG) {
+		defaults.MIG.Source = v1alpha1.GPUMIGDataSourceNodeLabels
+	}
+
+	return defaults
+}
+
+// applyHardwareDefaults fills per-device fields from the node-wide GFD labels.
+func applyHardwareDefaults(devices []Device, defaults Device) {
+	for i := range devices {
+		if devices[i].Product == "" {
+			devices[i].Product = defaults.Product
+		}
+		if devices[i].NUMANode == nil && defaults.NUMANode != nil {
+			devices[i].NUMANode = defaults.NUMANode
+		}
+		if devices[i].PowerLimitMW == nil && defaults.PowerLimitMW != nil {
+			devices[i].PowerLimitMW = defaults.PowerLimitMW
+		}
+		if devices[i].SMCount == nil && defaults.SMCount != nil {
+			devices[i].SMCount = defaults.SMCount
+		}
+		if devices[i].MemBandwidth == nil && defaults.MemBandwidth != nil {
+			devices[i].MemBandwidth = defaults.MemBandwidth
+		}
+		if devices[i].PCIEGen == nil && defaults.PCIEGen != nil {
+			devices[i].PCIEGen = defaults.PCIEGen
+		}
+		if devices[i].PCIELinkWid == nil && defaults.PCIELinkWid != nil {
+			devices[i].PCIELinkWid = defaults.PCIELinkWid
+		}
+		if devices[i].Board == "" {
+			devices[i].Board = defaults.Board
+		}
+		if devices[i].Family == "" {
+			devices[i].Family = defaults.Family
+		}
+		if devices[i].Serial == "" {
+			devices[i].Serial = defaults.Serial
+		}
+		if devices[i].PState == "" {
+			devices[i].PState = defaults.PState
+		}
+		if devices[i].DisplayMode == "" {
+			devices[i].DisplayMode = defaults.DisplayMode
+		}
+		if devices[i].MemoryMiB == 0 {
+			devices[i].MemoryMiB = defaults.MemoryMiB
+		}
+		if devices[i].ComputeMajor == 0 {
+			devices[i].ComputeMajor = defaults.ComputeMajor
+		}
+		if devices[i].ComputeMinor == 0 {
+			devices[i].ComputeMinor = defaults.ComputeMinor
+		}
+		if migConfigEmpty(devices[i].MIG) {
+			devices[i].MIG = defaults.MIG
+		}
+	}
+}
+
+func (p *parser) enrichFromInstances(devices []Device, instances []map[string]string) []Device {
+	indexMap := make(map[string]int, len(devices))
+	for i := range devices {
+		indexMap[devices[i].Index] = i
+	}
+
+	for _, attrs := range instances {
+		if attrs == nil {
+			continue
+		}
+		index := canonicalIndex(attrs["index"])
+		source := "instance/" + index
+		i, ok := indexMap[index]
+		if !ok {
+			vendor := strings.ToLower(attrs["vendor"])
+			device := strings.ToLower(attrs["device"])
+			class := strings.ToLower(attrs["class"])
+			if vendor == "" || device == "" || class == "" {
+				p.markIncomplete(source, "index")
+				continue
+			}
+			devices = append(devices, Device{
+				Index:  index,
+				Vendor: vendor,
+				Device: device,
+				Class:  class,
+			})
+			i = len(devices) - 1
+			applyHardwareDefaults(devices[i:], p.defaults)
+			indexMap[index] = i
+			delete(p.incomplete, labelSource+"|"+DeviceLabelPrefix+index)
+		}
+
+		if vendor := strings.ToLower(attrs["vendor"]); vendor != "" && devices[i].Vendor == "" {
+			devices[i].Vendor = vendor
+		}
+		if device := strings.ToLower(attrs["device"]); device != "" && devices[i].Device == "" {
+			devices[i].Device = device
+		}
+		if class := strings.ToLower(attrs["class"]); class != "" && devices[i].Class == "" {
+			devices[i].Class = class
+		}
+
+		if uuid := strings.TrimSpace(attrs["uuid"]); uuid != "" {
+			devices[i].UUID = uuid
+		}
+		if addr := strings.TrimSpace(attrs["pci.address"]); addr != "" {
+			devices[i].PCIAddress = addr
+		}
+		if mem := p.memoryMiB(source, "memory.total", attrs["memory.total"]); mem > 0 {
+			devices[i].MemoryMiB = mem
+		}
+		major, minor := p.computeCapability(source, "compute.major", "compute.minor", attrs["compute.major"], attrs["compute.minor"])
+		if major != 0 {
+			devices[i].ComputeMajor = major
+		}
+		if minor != 0 {
+			devices[i].ComputeMinor = minor
+		}
+		if product := strings.TrimSpace(attrs["product"]); product != "" && devices[i].Product == "" {
+			devices[i].Product = product
+		}
+		if numa := p.optionalInt32(source, "numa.node", attrs["numa.node"]); numa != nil {
+			devices[i].NUMANode = numa
+		}
+		if limit := p.optionalInt32(source, "power.limit", attrs["power.limit"]); limit != nil {
+			devices[i].PowerLimitMW = limit
+		}
+		if sm := p.optionalInt32(source, "sm.count", attrs["sm.count"]); sm != nil {
+			devices[i].SMCount = sm
+		}
+		if bw := p.optionalInt32(source, "memory.bandwidth", attrs["memory.bandwidth"]); bw != nil {
+			devices[i].MemBandwidth = bw
+		}
+		if gen := p.optionalInt32(source, "pcie.gen", attrs["pcie.gen"]); gen != nil {
+			devices[i].PCIEGen = gen
+		}
+		if width := p.optionalInt32(source, "pcie.link.width", attrs["pcie.link.width"]); width != nil {
+			devices[i].PCIELinkWid = width
+		}
+		if board := strings.TrimSpace(attrs["board"]); board != "" && devices[i].Board == "" {
+			devices[i].Board = board
+		}
+		if family := strings.TrimSpace(attrs["family"]); family != "" && devices[i].Family == "" {
+			devices[i].Family = family
+		}
+		if serial := strings.TrimSpace(attrs["serial"]); serial != "" && devices[i].Serial == "" {
+			devices[i].Serial = serial
+		}
+		if pstate := strings.TrimSpace(attrs["pstate"]); pstate != "" && devices[i].PState == "" {
+			devices[i].PState = pstate
+		}
+		if display := strings.TrimSpace(attrs["display_mode"]); display != "" && devices[i].DisplayMode == "" {
+			devices[i].DisplayMode = display
+		}
+
+		if mig, capableSet := p.instanceMIG(source, attrs); capableSet || !migConfigEmpty(mig) {
+			devices[i].MIG, devices[i].MIGConflict = mergeMIG(mig, capableSet, p.defaults.MIG)
+		}
+		if precisions := p.precision(source, attrs); len(precisions) > 0 {
+			devices[i].Precision = precisions
+		}
+		devices[i].Attributes = maps.Clone(attrs)
+	}
+
+	sortDevices(devices)
+	return devices
+}
+
+func (p *parser) precision(source string, attrs map[string]string) []string {
+	var values []string
+
+	if raw := attrs["precision"]; raw != "" {
+		values = append(values, splitAndNormalizeList(raw)...)
+	}
+
+	for key, value := range attrs {
+		name, ok := strings.CutPrefix(key, "precision.")
+		if !ok {
+			continue
+		}
+		if p.bool(source, key, value) {
+			values = append(values, name)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	values = deduplicateStrings(values)
+	sort.Strings(values)
+	return values
+}
+
+// enrich lets the vendor fill in what the labels and instances left out.
+func (p *parser) enrich(devices []Device) {
+	if p.vendors == nil {
+		return
+	}
+	for i := range devices {
+		p.vendors.Enrich(&devices[i])
+	}
+}
+
+// canonicalizeDevices orders the set-like fields once every source has contributed, so vendor
+// enrichment cannot reintroduce an order that depends on map iteration.
+func canonicalizeDevices(devices []Device) {
+	for i := range devices {
+		devices[i].Precision = canonical.Strings(devices[i].Precision)
+		canonical.MIG(&devices[i].MIG)
+	}
+}
+
+func sortDevices(devices []Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Index < devices[j].Index
+	})
+}
+
+func canonicalIndex(index string) string {
+	index = strings.TrimSpace(index)
+	if index == "" {
+		return "0"
+	}
+	if i, err := strconv.Atoi(index); err == nil {
+		return strconv.Itoa(i)
+	}
+	return index
+}
+
+func splitAndNormalizeList(input string) []string {
+	var result []string
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t'
+	})
+	for _, f := range fields {
+		if trimmed := strings.TrimSpace(f); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+func deduplicateStrings(items []string) []string {
+	seen := make(map[string]struct{}, len(items))
+	var out []string
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
+
+func firstExisting(labels map[string]string, keys ...string) (string, string, bool) {
+	for _, key := range keys {
+		if value, ok := labels[key]; ok {
+			return key, value, true
+		}
+	}
+	return "", "", false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,378 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// nvidiaVendors stands in for the provider registry with only the NVIDIA provider registered.
+type nvidiaVendors struct{}
+
+func (nvidiaVendors) Identify(device Device) bool { return device.Vendor == VendorNvidia }
+
+func (nvidiaVendors) Enrich(device *Device) {
+	if device.Product == "" && device.Vendor == VendorNvidia && device.Device == "1db6" {
+		device.Product = "Tesla V100-PCIE-32GB"
+	}
+}
+
+func TestExtractDevicesFiltersNonNvidia(t *testing.T) {
+	labels := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "abcd",
+		"gpu.deckhouse.io/device.00.device": "1234",
+		"gpu.deckhouse.io/device.00.class":  "0302",
+	}
+	p := &parser{vendors: nvidiaVendors{}}
+	if devices := p.extractDevices(labels); len(devices) != 0 {
+		t.Fatalf("expected non-NVIDIA devices to be filtered, got %+v", devices)
+	}
+	if len(p.incomplete) != 0 {
+		t.Fatalf("expected foreign devices to be skipped silently, got %+v", p.incomplete)
+	}
+
+	labels["gpu.deckhouse.io/device.00.vendor"] = "10de"
+	labels["gpu.deckhouse.io/device.00.memoryMiB"] = "16384"
+	devices := p.extractDevices(labels)
+	if len(devices) != 1 || devices[0].MemoryMiB != 16384 {
+		t.Fatalf("expected one NVIDIA device, got %+v", devices)
+	}
+}
+
+func TestExtractDevicesSkipsMalformedEntries(t *testing.T) {
+	labels := map[string]string{
+		"gpu.deckhouse.io/device.00":               "broken",
+		"gpu.deckhouse.io/device.01.vendor":        "10de",
+		"gpu.deckhouse.io/device.01.device":        "2230",
+		"gpu.deckhouse.io/device.01.class":         "0302",
+		"gpu.deckhouse.io/device.02.vendor":        "10de",
+		"gpu.deckhouse.io/device.02.device":        "1db5",
+		"gpu.deckhouse.io/device.02.class":         "",
+		"gpu.deckhouse.io/device.02.memoryMiB":     "11000",
+		"gpu.deckhouse.io/device.03.vendor":        "10de",
+		"gpu.deckhouse.io/device.03.device":        "1db5",
+		"gpu.deckhouse.io/device.03.class":         "0302",
+		"gpu.deckhouse.io/device.03.product":       "GPU Product",
+		"gpu.deckhouse.io/device.03.memoryMiB":     "12 GiB",
+		"gpu.deckhouse.io/device.03.compute.major": "8",
+		"gpu.deckhouse.io/device.03.compute.minor": "9",
+	}
+
+	p := &parser{}
+	devices := p.extractDevices(labels)
+	if len(devices) != 2 {
+		t.Fatalf("expected two valid devices, got %+v", devices)
+	}
+	if devices[0].Index != "1" || devices[1].Index != "3" {
+		t.Fatalf("unexpected indices: %+v", devices)
+	}
+	if devices[1].Product != "GPU Product" || devices[1].MemoryMiB != 12288 {
+		t.Fatalf("expected enriched product and memory, got %+v", devices[1])
+	}
+	if len(p.errors) != 1 || p.errors[0].Key != "gpu.deckhouse.io/device.00" {
+		t.Fatalf("expected the field-less label to be reported, got %+v", p.errors)
+	}
+	if _, ok := p.incomplete[labelSource+"|gpu.deckhouse.io/device.2"]; !ok || len(p.incomplete) != 1 {
+		t.Fatalf("expected device 2 to be reported as incomplete, got %+v", p.incomplete)
+	}
+}
+
+func TestEnrichFromInstancesCreatesMissingDevices(t *testing.T) {
+	devices := []Device{
+		{Index: "0", Vendor: "10de", Device: "1db6", Class: "0302"},
+	}
+
+	p := &parser{}
+	enriched := p.enrichFromInstances(devices, []map[string]string{
+		{
+			"index":        "0",
+			"uuid":         "GPU-0",
+			"memory.total": "16384 MiB",
+		},
+		{
+			"index":  "1",
+			"vendor": "10de",
+			"device": "2230",
+			"class":  "0300",
+			"uuid":   "GPU-1",
+		},
+		{
+			"index": "2",
+			"uuid":  "GPU-2",
+			// missing vendor/device/class -> should be skipped
+		},
+	})
+	if len(enriched) != 2 {
+		t.Fatalf("expected two devices after enrichment, got %+v", enriched)
+	}
+	if enriched[1].Index != "1" || enriched[1].Vendor != "10de" || enriched[1].Device != "2230" || enriched[1].UUID != "GPU-1" {
+		t.Fatalf("unexpected device created from feature: %+v", enriched[1])
+	}
+	if _, ok := p.incomplete["instance/2|index"]; !ok || len(p.incomplete) != 1 {
+		t.Fatalf("expected instance 2 to be reported as incomplete, got %+v", p.incomplete)
+	}
+	if enriched[0].Attributes["memory.total"] != "16384 MiB" || enriched[1].Attributes["device"] != "2230" {
+		t.Fatalf("expected instance attributes to be kept verbatim, got %+v / %+v", enriched[0].Attributes, enriched[1].Attributes)
+	}
+}
+
+func TestEnrichFromInstancesSkipsEmptyAttributes(t *testing.T) {
+	devices := []Device{{Index: "0", Vendor: "10de", Device: "1db5", Class: "0300"}}
+	enriched := (&parser{}).enrichFromInstances(devices, []map[string]string{
+		nil,
+		{"index": "1", "vendor": "", "device": ""},
+	})
+	if len(enriched) != 1 || enriched[0].Index != "0" {
+		t.Fatalf("expected devices untouched, got %+v", enriched)
+	}
+}
+
+func TestEnrichFromInstancesIgnoresUnknownIndex(t *testing.T) {
+	devices := []Device{{Index: "0"}}
+	result := (&parser{}).enrichFromInstances(devices, []map[string]string{
+		{"index": "1", "uuid": "ignored"},
+	})
+	if len(result) != 1 || result[0].UUID != "" {
+		t.Fatalf("expected device without matching index unchanged, got %+v", result)
+	}
+}
+
+func TestEnrichFromInstancesPropagatesAttributes(t *testing.T) {
+	devices := []Device{{Index: "0"}}
+	result := (&parser{}).enrichFromInstances(devices, []map[string]string{{
+		"index":          "0",
+		"uuid":           "GPU-123",
+		"product":        "Feature Product",
+		"precision":      "fp32,tf32",
+		"precision.bf16": "true",
+	}})
+	if len(result) != 1 {
+		t.Fatalf("expected single device, got %+v", result)
+	}
+	if result[0].UUID != "GPU-123" || result[0].Product != "Feature Product" {
+		t.Fatalf("unexpected enrichment result %+v", result[0])
+	}
+	if !reflect.DeepEqual(result[0].Precision, []string{"bf16", "fp32", "tf32"}) {
+		t.Fatalf("expected precision to be normalised, got %+v", result[0].Precision)
+	}
+}
+
+func TestEnrichFromInstancesOverridesMetrics(t *testing.T) {
+	devices := []Device{{Index: "5"}}
+	result := (&parser{}).enrichFromInstances(devices, []map[string]string{
+		{
+			"index":          "5",
+			"memory.total":   "24576 MiB",
+			"compute.major":  "9",
+			"compute.minor":  "9",
+			"product":        "Feature GPU",
+			"precision":      "fp64",
+			"precision.fp32": "true",
+		},
+		nil,
+		{"index": ""},
+	})
+	if len(result) != 1 {
+		t.Fatalf("expected device to be updated, got %+v", result)
+	}
+	device := result[0]
+	if device.MemoryMiB != 24576 || device.ComputeMajor != 9 || device.ComputeMinor != 9 {
+		t.Fatalf("expected metrics override, got %+v", device)
+	}
+	if device.Product != "Feature GPU" {
+		t.Fatalf("expected product override, got %s", device.Product)
+	}
+	if !reflect.DeepEqual(device.Precision, []string{"fp32", "fp64"}) {
+		t.Fatalf("expected precision override, got %+v", device.Precision)
+	}
+}
+
+func TestEnrichFromInstancesFillsMissingIds(t *testing.T) {
+	devices := []Device{{Index: "1"}}
+	result := (&parser{}).enrichFromInstances(devices, []map[string]string{{
+		"index":            "1",
+		"vendor":           "10DE",
+		"device":           "2203",
+		"class":            "0300",
+		"pci.address":      "0000:01:00.0",
+		"memory.total":     "24576 MiB",
+		"compute.major":    "8",
+		"compute.minor":    "0",
+		"numa.node":        "0",
+		"power.limit":      "250",
+		"sm.count":         "108",
+		"memory.bandwidth": "1500",
+		"pcie.gen":         "4",
+		"pcie.link.width":  "16",
+		"board":            "PG132",
+		"family":           "Ampere",
+		"serial":           "ABC123",
+		"pstate":           "P0",
+		"display_mode":     "Disabled",
+		"precision":        "fp16,fp32",
+	}})
+	if len(result) != 1 {
+		t.Fatalf("expected one device, got %+v", result)
+	}
+	dev := result[0]
+	if dev.Vendor != "10de" || dev.Device != "2203" || dev.Class != "0300" {
+		t.Fatalf("expected pci ids set, got %+v", dev)
+	}
+	if dev.PCIAddress != "0000:01:00.0" || dev.MemoryMiB != 24576 || dev.ComputeMajor != 8 || dev.ComputeMinor != 0 {
+		t.Fatalf("expected metrics filled, got %+v", dev)
+	}
+	assertIntPtr(t, dev.NUMANode, 0)
+	assertIntPtr(t, dev.PowerLimitMW, 250)
+	assertIntPtr(t, dev.SMCount, 108)
+	assertIntPtr(t, dev.MemBandwidth, 1500)
+	assertIntPtr(t, dev.PCIEGen, 4)
+	assertIntPtr(t, dev.PCIELinkWid, 16)
+	if dev.Board != "PG132" || dev.Family != "Ampere" || dev.Serial != "ABC123" || dev.PState != "P0" || dev.DisplayMode != "Disabled" {
+		t.Fatalf("expected board/family/serial/pstate/display set, got %+v", dev)
+	}
+	if !reflect.DeepEqual(dev.Precision, []string{"fp16", "fp32"}) {
+		t.Fatalf("expected precision set, got %+v", dev.Precision)
+	}
+}
+
+func TestEnrichFromInstancesOverridesHardware(t *testing.T) {
+	zero := int32(0)
+	devices := []Device{{
+		Index: "0", Vendor: "10de", Device: "1db4", Class: "0300",
+		NUMANode: &zero,
+	}}
+
+	res := (&parser{}).enrichFromInstances(devices, []map[string]string{{
+		"index":            "0",
+		"vendor":           "10de",
+		"device":           "1db4",
+		"class":            "0300",
+		"pci.address":      "0000:01:00.0",
+		"numa.node":        "2",
+		"power.limit":      "200",
+		"sm.count":         "80",
+		"memory.bandwidth": "1600",
+		"pcie.gen":         "5",
+		"pcie.link.width":  "8",
+		"board":            "board-2",
+		"family":           "hopper",
+		"serial":           "serial-2",
+		"pstate":           "P2",
+		"display_mode":     "Disabled",
+	}})
+	if len(res) != 1 {
+		t.Fatalf("expected one device, got %d", len(res))
+	}
+	dev := res[0]
+	assertIntPtr(t, dev.NUMANode, 2)
+	assertIntPtr(t, dev.PowerLimitMW, 200)
+	assertIntPtr(t, dev.SMCount, 80)
+	assertIntPtr(t, dev.MemBandwidth, 1600)
+	assertIntPtr(t, dev.PCIEGen, 5)
+	assertIntPtr(t, dev.PCIELinkWid, 8)
+	if dev.PCIAddress != "0000:01:00.0" {
+		t.Fatalf("expected pci address propagated, got %q", dev.PCIAddress)
+	}
+	if dev.Board != "board-2" || dev.Family != "hopper" || dev.Serial != "serial-2" || dev.PState != "P2" || dev.DisplayMode != "Disabled" {
+		t.Fatalf("unexpected feature board/family/serial/pstate/display: %+v", dev)
+	}
+}
+
+func TestSortDevicesOrdersIndices(t *testing.T) {
+	devices := []Device{{Index: "10"}, {Index: "2"}, {Index: "001"}}
+	sortDevices(devices)
+	got := []string{devices[0].Index, devices[1].Index, devices[2].Index}
+	want := []string{"001", "10", "2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected order: %v", got)
+	}
+}
+
+func TestDeduplicateStrings(t *testing.T) {
+	input := []string{"fp32", "fp16", "fp32", "tf32"}
+	deduped := deduplicateStrings(input)
+	if !reflect.DeepEqual(deduped, []string{"fp32", "fp16", "tf32"}) {
+		t.Fatalf("unexpected deduplicate result: %v", deduped)
+	}
+}
+
+func TestCanonicalIndexVariants(t *testing.T) {
+	cases := map[string]string{
+		"":    "0",
+		"01":  "1",
+		"A12": "A12",
+	}
+	for in, want := range cases {
+		if got := canonicalIndex(in); got != want {
+			t.Fatalf("canonicalIndex(%q)=%q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseWithoutVendorsKeepsIdentifiedDevices(t *testing.T) {
+	labels := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "abcd",
+		"gpu.deckhouse.io/device.00.device": "1234",
+		"gpu.deckhouse.io/device.00.class":  "0302",
+		"gpu.deckhouse.io/device.01.vendor": "10de",
+		"gpu.deckhouse.io/device.01.device": "1db6",
+		"gpu.deckhouse.io/device.01.class":  "0302",
+	}
+	if got := Parse(Input{NodeLabels: labels}); len(got.Devices) != 2 || got.Devices[1].Product != "" {
+		t.Fatalf("expected both devices without enrichment, got %+v", got.Devices)
+	}
+
+	got := Parse(Input{NodeLabels: labels, Vendors: nvidiaVendors{}})
+	if len(got.Devices) != 1 || got.Devices[0].Product != "Tesla V100-PCIE-32GB" {
+		t.Fatalf("expected the vendor to claim and enrich the NVIDIA device, got %+v", got.Devices)
+	}
+}
+
+// shufflingVendors appends capabilities in an order of its own, as a map-backed catalog would.
+type shufflingVendors struct{ nvidiaVendors }
+
+func (shufflingVendors) Enrich(device *Device) {
+	device.Precision = append(device.Precision, "tf32", "fp32", "bf16", "tf32")
+	device.MIG.ProfilesSupported = append(device.MIG.ProfilesSupported, "7g.80gb", "1g.10gb")
+	device.MIG.Types = append(device.MIG.Types,
+		v1alpha1.GPUMIGTypeCapacity{Name: "7g.80gb", Count: 1},
+		v1alpha1.GPUMIGTypeCapacity{Name: "1g.10gb", Count: 7},
+	)
+}
+
+func TestParseCanonicalizesEnrichedDevices(t *testing.T) {
+	labels := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "10de",
+		"gpu.deckhouse.io/device.00.device": "2330",
+		"gpu.deckhouse.io/device.00.class":  "0302",
+	}
+	got := Parse(Input{NodeLabels: labels, Vendors: shufflingVendors{}})
+	if len(got.Devices) != 1 {
+		t.Fatalf("expected one device, got %+v", got.Devices)
+	}
+	device := got.Devices[0]
+	if want := []string{"bf16", "fp32", "tf32"}; !reflect.DeepEqual(device.Precision, want) {
+		t.Fatalf("precision=%v, want %v", device.Precision, want)
+	}
+	if want := []string{"1g.10gb", "7g.80gb"}; !reflect.DeepEqual(device.MIG.ProfilesSupported, want) {
+		t.Fatalf("profiles=%v, want %v", device.MIG.ProfilesSupported, want)
+	}
+	if len(device.MIG.Types) != 2 || device.MIG.Types[0].Name != "1g.10gb" || device.MIG.Types[1].Name != "7g.80gb" {
+		t.Fatalf("expected MIG types ordered by name, got %+v", device.MIG.Types)
+	}
+}
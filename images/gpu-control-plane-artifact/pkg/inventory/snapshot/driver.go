@@ -12,15 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package state
+package snapshot
 
 import "strings"
 
-func parseDriverInfo(labels map[string]string) nodeDriverSnapshot {
-	driverVersion := strings.TrimSpace(labels[gfdDriverVersionLabel])
+func (p *parser) driver(labels map[string]string) Driver {
+	driverVersion := strings.TrimSpace(labels[GFDDriverVersionLabel])
 
-	cudaMajor := strings.TrimSpace(labels[gfdCudaDriverMajorLabel])
-	cudaMinor := strings.TrimSpace(labels[gfdCudaDriverMinorLabel])
+	cudaMajor := strings.TrimSpace(labels[GFDCudaDriverMajorLabel])
+	cudaMinor := strings.TrimSpace(labels[GFDCudaDriverMinorLabel])
 	var cudaVersion string
 	switch {
 	case cudaMajor != "":
@@ -28,22 +28,21 @@ func parseDriverInfo(labels map[string]string) nodeDriverSnapshot {
 		if cudaMinor != "" {
 			cudaVersion += "." + cudaMinor
 		}
-	case labels[gfdCudaRuntimeVersionLabel] != "":
-		cudaVersion = strings.TrimSpace(labels[gfdCudaRuntimeVersionLabel])
-	default:
-		cudaVersion = ""
+	case labels[GFDCudaRuntimeVersionLabel] != "":
+		cudaVersion = strings.TrimSpace(labels[GFDCudaRuntimeVersionLabel])
 	}
 
-	toolkitInstalled := parseBool(labels[deckhouseToolkitInstalled])
-	toolkitReady := parseBool(labels[deckhouseToolkitReadyLabel])
+	toolkitInstalled := p.bool(labelSource, DeckhouseToolkitInstalled, labels[DeckhouseToolkitInstalled])
+	toolkitReady := p.bool(labelSource, DeckhouseToolkitReadyLabel, labels[DeckhouseToolkitReadyLabel])
 	if toolkitReady && !toolkitInstalled {
 		toolkitInstalled = true
 	}
 
-	return nodeDriverSnapshot{
+	return Driver{
 		Version:          driverVersion,
 		CUDAVersion:      cudaVersion,
 		ToolkitInstalled: toolkitInstalled,
 		ToolkitReady:     toolkitReady,
+		ToolkitVersion:   strings.TrimSpace(labels[DeckhouseToolkitVersionLabel]),
 	}
 }
@@ -0,0 +1,139 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func FuzzParseMemoryMiB(f *testing.F) {
+	for _, seed := range []string{"", "40536 MiB", "40536MiB", "40 GiB", "1.5 TiB", "12 gb", "unknown", "512foobar", "-1", "99999999999 MiB", "  8 mb  "} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		mib, err := ParseMemoryMiB(value)
+		if err != nil {
+			if mib != 0 {
+				t.Fatalf("ParseMemoryMiB(%q) returned %d alongside error %v", value, mib, err)
+			}
+			return
+		}
+		if mib < 0 {
+			t.Fatalf("ParseMemoryMiB(%q) returned negative %d", value, mib)
+		}
+		again, err := ParseMemoryMiB(fmt.Sprintf("%d MiB", mib))
+		if err != nil || again != mib {
+			t.Fatalf("round trip of %d MiB returned %d (%v)", mib, again, err)
+		}
+	})
+}
+
+func FuzzParseComputeCapability(f *testing.F) {
+	for _, seed := range [][2]string{{"8", "0"}, {"9", ""}, {"", ""}, {"x", "1"}, {"-1", "0"}, {"8", "-3"}, {"99999999999", "0"}, {"8.6", "6"}} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, major, minor string) {
+		maj, mnr, err := ParseComputeCapability(major, minor)
+		if maj < 0 || mnr < 0 {
+			t.Fatalf("ParseComputeCapability(%q, %q) returned negative %d.%d", major, minor, maj, mnr)
+		}
+		if errors.Is(err, ErrMissingMinorVersion) && mnr != 0 {
+			t.Fatalf("missing minor version reported with minor %d", mnr)
+		}
+	})
+}
+
+func FuzzParseMIGProfileLabel(f *testing.F) {
+	for _, seed := range []string{"nvidia.com/mig-1g.10gb.count", "nvidia.com/mig-1g.10gb.engines.copy", "nvidia.com/mig-capable", "nvidia.com/mig-1g.", "nvidia.com/mig-.5gb.count", "nvidia.com/gpu.product", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, key string) {
+		profile, metric, err := ParseMIGProfileLabel(key)
+		if err != nil {
+			if profile != "" || metric != "" {
+				t.Fatalf("ParseMIGProfileLabel(%q) returned %q/%q alongside error %v", key, profile, metric, err)
+			}
+			return
+		}
+		if !strings.HasPrefix(key, MIGProfileLabelPrefix) || !strings.HasSuffix(key, "."+metric) {
+			t.Fatalf("ParseMIGProfileLabel(%q) returned %q/%q that does not match the key", key, profile, metric)
+		}
+		if profile == "" || metric == "" || profile != strings.ToLower(profile) {
+			t.Fatalf("ParseMIGProfileLabel(%q) returned malformed %q/%q", key, profile, metric)
+		}
+	})
+}
+
+// decodeAttributes turns "key=value" lines into an attribute map so the fuzzer can mutate whole
+// label and instance sets.
+func decodeAttributes(raw string) map[string]string {
+	attrs := map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		attrs[key] = value
+	}
+	return attrs
+}
+
+func FuzzParse(f *testing.F) {
+	identity := "index=0\nvendor=10de\ndevice=2330\nclass=0302"
+	for _, seed := range [][2]string{
+		{"", identity + "\nmemory.total=NaN MiB"},
+		{"", identity + "\ncompute.major=8.0\ncompute.minor=0"},
+		{DeviceLabelPrefix + "=x\n" + DeviceLabelPrefix + "0=10de", identity},
+		{GFDMemoryLabel + "=80 GiB\n" + GFDComputeMajorLabel + "=9\n" + GFDComputeMinorLabel + "=0", identity + "\nnuma.node=-1\npcie.gen=4.0"},
+		{MIGProfileLabelPrefix + "1g.\n" + MIGProfileLabelPrefix + "1g.10gb.count=7.5", "index=\nprecision.fp64=maybe"},
+		{DeviceLabelPrefix + "1.vendor=10de\n" + DeviceLabelPrefix + "1.device=2330\n" + DeviceLabelPrefix + "1.class=0302", "index=01\nmemory.total=99999999999 TiB"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, labels, instance string) {
+		in := Input{NodeLabels: decodeAttributes(labels), Instances: []map[string]string{decodeAttributes(instance)}}
+		snap := Parse(in)
+		if again := Parse(in); !reflect.DeepEqual(snap, again) {
+			t.Fatalf("Parse is not deterministic:\n%+v\n%+v", snap, again)
+		}
+
+		seen := map[string]bool{}
+		for _, dev := range snap.Devices {
+			if seen[dev.Index] {
+				t.Fatalf("device index %q reported twice: %+v", dev.Index, snap.Devices)
+			}
+			seen[dev.Index] = true
+			if dev.MemoryMiB < 0 || dev.ComputeMajor < 0 || dev.ComputeMinor < 0 {
+				t.Fatalf("negative hardware values in %+v", dev)
+			}
+			for _, value := range []*int32{dev.NUMANode, dev.PowerLimitMW, dev.SMCount, dev.MemBandwidth, dev.PCIEGen, dev.PCIELinkWid} {
+				if value != nil && *value < 0 {
+					t.Fatalf("negative optional value in %+v", dev)
+				}
+			}
+			for _, migType := range dev.MIG.Types {
+				if migType.Count < 0 {
+					t.Fatalf("negative MIG count in %+v", dev.MIG)
+				}
+			}
+		}
+		for _, issue := range append(snap.Warnings, snap.Errors...) {
+			if issue.Source == "" || issue.Reason == "" {
+				t.Fatalf("issue without source or reason: %+v", issue)
+			}
+		}
+	})
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// ErrNotMIGProfile is returned for labels that do not describe a MIG profile at all, such as
+// the nvidia.com/mig-capable and nvidia.com/mig-strategy labels.
+var ErrNotMIGProfile = errors.New("not a MIG profile label")
+
+// ParseMIGProfileLabel splits a GFD label such as "nvidia.com/mig-1g.10gb.count" into the
+// lower-cased profile name ("1g.10gb") and the metric ("count").
+func ParseMIGProfileLabel(key string) (string, string, error) {
+	return parseMIGProfileKey(key, MIGProfileLabelPrefix)
+}
+
+func parseMIGProfileKey(key, prefix string) (string, string, error) {
+	trimmed, ok := strings.CutPrefix(key, prefix)
+	if !ok {
+		return "", "", ErrNotMIGProfile
+	}
+	firstDot := strings.Index(trimmed, ".")
+	if firstDot == -1 {
+		return "", "", ErrNotMIGProfile
+	}
+	secondDot := strings.Index(trimmed[firstDot+1:], ".")
+	if secondDot == -1 {
+		return "", "", fmt.Errorf("profile %q has no metric", trimmed)
+	}
+	secondDot += firstDot + 1
+
+	profile := strings.ToLower(trimmed[:secondDot])
+	metric := trimmed[secondDot+1:]
+	if firstDot == 0 || secondDot == firstDot+1 {
+		return "", "", fmt.Errorf("profile %q is incomplete", trimmed[:secondDot])
+	}
+	if metric == "" {
+		return "", "", fmt.Errorf("profile %q has an empty metric", profile)
+	}
+	return profile, metric, nil
+}
+
+// migMetricPriority ranks the GFD metrics that carry an instance count, highest first.
+var migMetricPriority = map[string]int{
+	"count":     3,
+	"ready":     2,
+	"available": 1,
+}
+
+// Device instance attributes carrying the MIG data of a single GPU. Profile counters use the
+// GFD label layout without the vendor prefix, e.g. "mig-1g.10gb.count".
+const (
+	instanceMIGCapable       = "mig.capable"
+	instanceMIGStrategy      = "mig.strategy"
+	instanceMIGProfiles      = "mig.profiles"
+	instanceMIGProfilePrefix = "mig-"
+)
+
+// migConfig reads the node-wide MIG data published as GFD labels.
+func (p *parser) migConfig(labels map[string]string) v1alpha1.GPUMIGConfig {
+	cfg := v1alpha1.GPUMIGConfig{}
+
+	if key, value, ok := firstExisting(labels, GFDMigCapableLabel, GFDMigAltCapableLabel); ok {
+		cfg.Capable = p.bool(labelSource, key, value)
+	}
+	if key, value, ok := firstExisting(labels, GFDMigStrategyLabel, GFDMigAltStrategyLabel); ok {
+		cfg.Strategy = p.migStrategy(labelSource, key, value)
+	}
+	cfg.ProfilesSupported, cfg.Types = p.migProfiles(labelSource, MIGProfileLabelPrefix, labels)
+	return cfg
+}
+
+// instanceMIG reads the MIG data of one device instance. capableSet tells an explicit
+// mig.capable=false apart from an instance that does not report the capability at all.
+func (p *parser) instanceMIG(source string, attrs map[string]string) (cfg v1alpha1.GPUMIGConfig, capableSet bool) {
+	if value := strings.TrimSpace(attrs[instanceMIGCapable]); value != "" {
+		if parsed, ok := parseBool(value); ok {
+			cfg.Capable, capableSet = parsed, true
+		} else {
+			p.warn(source, instanceMIGCapable, value, fmt.Errorf("not a boolean"))
+		}
+	}
+	if value := strings.TrimSpace(attrs[instanceMIGStrategy]); value != "" {
+		cfg.Strategy = p.migStrategy(source, instanceMIGStrategy, value)
+	}
+	profiles, types := p.migProfiles(source, instanceMIGProfilePrefix, attrs)
+	for _, profile := range splitAndNormalizeList(attrs[instanceMIGProfiles]) {
+		profiles = append(profiles, strings.TrimPrefix(strings.ToLower(profile), instanceMIGProfilePrefix))
+	}
+	cfg.ProfilesSupported = deduplicateStrings(profiles)
+	sort.Strings(cfg.ProfilesSupported)
+	cfg.Types = types
+	return cfg, capableSet
+}
+
+func (p *parser) migStrategy(source, key, value string) v1alpha1.GPUMIGStrategy {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "single":
+		return v1alpha1.GPUMIGStrategySingle
+	case "mixed":
+		return v1alpha1.GPUMIGStrategyMixed
+	case "none":
+		return v1alpha1.GPUMIGStrategyNone
+	default:
+		p.warn(source, key, value, fmt.Errorf("unknown MIG strategy"))
+		return v1alpha1.GPUMIGStrategyNone
+	}
+}
+
+// migProfiles collects the profiles and per-profile counts from keys such as <prefix>1g.10gb.count.
+func (p *parser) migProfiles(source, prefix string, attrs map[string]string) ([]string, []v1alpha1.GPUMIGTypeCapacity) {
+	type migCountAccumulator struct {
+		capability v1alpha1.GPUMIGTypeCapacity
+		priority   int
+	}
+
+	typeAccumulator := map[string]*migCountAccumulator{}
+	profiles := map[string]struct{}{}
+
+	for key, value := range attrs {
+		profile, metric, err := parseMIGProfileKey(key, prefix)
+		if err != nil {
+			if !errors.Is(err, ErrNotMIGProfile) {
+				p.warn(source, key, value, err)
+			}
+			continue
+		}
+		if value == "" {
+			continue
+		}
+
+		profiles[profile] = struct{}{}
+
+		priority, ok := migMetricPriority[metric]
+		if !ok {
+			continue
+		}
+		count, err := ParseInt32(value)
+		if err != nil {
+			p.warn(source, key, value, err)
+			continue
+		}
+
+		entry := typeAccumulator[profile]
+		if entry == nil {
+			entry = &migCountAccumulator{capability: v1alpha1.GPUMIGTypeCapacity{Name: profile}}
+			typeAccumulator[profile] = entry
+		}
+		if priority > entry.priority {
+			entry.priority = priority
+			entry.capability.Count = count
+		}
+	}
+
+	var supported []string
+	if len(profiles) > 0 {
+		supported = make([]string, 0, len(profiles))
+		for profile := range profiles {
+			supported = append(supported, profile)
+		}
+		sort.Strings(supported)
+	}
+
+	var types []v1alpha1.GPUMIGTypeCapacity
+	if len(typeAccumulator) > 0 {
+		types = make([]v1alpha1.GPUMIGTypeCapacity, 0, len(typeAccumulator))
+		for _, entry := range typeAccumulator {
+			types = append(types, entry.capability)
+		}
+		sort.Slice(types, func(i, j int) bool {
+			return types[i].Name < types[j].Name
+		})
+	}
+
+	return supported, types
+}
+
+// mergeMIG resolves the MIG config of a device from its instance attributes and the node-wide labels.
+// Each field comes from the instance when it reports one and from the node labels otherwise, and Source
+// records which of them contributed. A strategy reported differently by both sources is returned as a
+// conflict: the instance value is kept, but the caller surfaces the disagreement.
+func mergeMIG(instance v1alpha1.GPUMIGConfig, capableSet bool, node v1alpha1.GPUMIGConfig) (v1alpha1.GPUMIGConfig, string) {
+	merged := v1alpha1.GPUMIGConfig{}
+	fromInstance, fromNode := false, false
+
+	switch {
+	case capableSet:
+		merged.Capable, fromInstance = instance.Capable, true
+	case node.Capable:
+		merged.Capable, fromNode = true, true
+	}
+	switch {
+	case instance.Strategy != "":
+		merged.Strategy, fromInstance = instance.Strategy, true
+	case node.Strategy != "":
+		merged.Strategy, fromNode = node.Strategy, true
+	}
+	switch {
+	case len(instance.ProfilesSupported) > 0:
+		merged.ProfilesSupported, fromInstance = instance.ProfilesSupported, true
+	case len(node.ProfilesSupported) > 0:
+		merged.ProfilesSupported, fromNode = node.ProfilesSupported, true
+	}
+	switch {
+	case len(instance.Types) > 0:
+		merged.Types, fromInstance = instance.Types, true
+	case len(node.Types) > 0:
+		merged.Types, fromNode = node.Types, true
+	}
+	if len(merged.ProfilesSupported) > 0 || len(merged.Types) > 0 {
+		merged.Capable = true
+	}
+	merged.Source = migSource(fromInstance, fromNode)
+
+	conflict := ""
+	if instance.Strategy != "" && node.Strategy != "" && instance.Strategy != node.Strategy {
+		conflict = fmt.Sprintf("instance reports MIG strategy %s, node labels report %s", instance.Strategy, node.Strategy)
+	}
+	return merged, conflict
+}
+
+func migSource(fromInstance, fromNode bool) v1alpha1.GPUMIGDataSource {
+	switch {
+	case fromInstance && fromNode:
+		return v1alpha1.GPUMIGDataSourceMerged
+	case fromInstance:
+		return v1alpha1.GPUMIGDataSourceInstance
+	case fromNode:
+		return v1alpha1.GPUMIGDataSourceNodeLabels
+	default:
+		return ""
+	}
+}
+
+func migConfigEmpty(cfg v1alpha1.GPUMIGConfig) bool {
+	return !cfg.Capable && cfg.Strategy == "" && len(cfg.ProfilesSupported) == 0 && len(cfg.Types) == 0
+}
@@ -0,0 +1,313 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+func TestParseMIGProfileLabel(t *testing.T) {
+	profile, metric, err := ParseMIGProfileLabel("nvidia.com/mig-1G.10gb.count")
+	if err != nil || profile != "1g.10gb" || metric != "count" {
+		t.Fatalf("unexpected result %q %q (%v)", profile, metric, err)
+	}
+	profile, metric, err = ParseMIGProfileLabel("nvidia.com/mig-1g.10gb.engines.copy")
+	if err != nil || profile != "1g.10gb" || metric != "engines.copy" {
+		t.Fatalf("unexpected nested metric %q %q (%v)", profile, metric, err)
+	}
+
+	for _, key := range []string{"nvidia.com/mig-capable", "nvidia.com/mig-strategy", "nvidia.com/gpu.product"} {
+		if _, _, err := ParseMIGProfileLabel(key); !errors.Is(err, ErrNotMIGProfile) {
+			t.Fatalf("expected %q to be treated as non-profile label, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"nvidia.com/mig-1g.profile", "nvidia.com/mig-1g.5gb.", "nvidia.com/mig-.5gb.count", "nvidia.com/mig-1g..count"} {
+		_, _, err := ParseMIGProfileLabel(key)
+		if err == nil || errors.Is(err, ErrNotMIGProfile) {
+			t.Fatalf("expected %q to be reported as malformed, got %v", key, err)
+		}
+	}
+}
+
+func TestParseHardwareDefaultsSetsCapableWhenProfilesWithoutTypes(t *testing.T) {
+	labels := map[string]string{
+		// Unsupported metric -> profiles supported, but types remain empty.
+		"nvidia.com/mig-1g.10gb.unknown": "1",
+	}
+	defaults := (&parser{}).hardwareDefaults(labels)
+	if !defaults.MIG.Capable {
+		t.Fatalf("expected MIG to become capable when profiles are present")
+	}
+	if len(defaults.MIG.ProfilesSupported) != 1 || defaults.MIG.ProfilesSupported[0] != "1g.10gb" {
+		t.Fatalf("unexpected profilesSupported: %+v", defaults.MIG.ProfilesSupported)
+	}
+	if len(defaults.MIG.Types) != 0 {
+		t.Fatalf("expected no types for unknown metric, got %+v", defaults.MIG.Types)
+	}
+}
+
+func TestParseMIGConfigCollectsMetrics(t *testing.T) {
+	labels := map[string]string{
+		GFDMigCapableLabel:                       "true",
+		GFDMigStrategyLabel:                      "mixed",
+		"nvidia.com/mig-1g.10gb.count":           "2",
+		"nvidia.com/mig-1g.10gb.engines.copy":    "4",
+		"nvidia.com/mig-1g.10gb.engines.encoder": "1",
+		"nvidia.com/mig-1g.10gb.engines.decoder": "1",
+		"nvidia.com/mig-1g.10gb.engines.ofa":     "1",
+		"nvidia.com/mig-1g.10gb.memory":          "10240",
+		"nvidia.com/mig-1g.10gb.multiprocessors": "14",
+	}
+
+	p := &parser{}
+	cfg := p.migConfig(labels)
+	if !cfg.Capable || cfg.Strategy != v1alpha1.GPUMIGStrategyMixed {
+		t.Fatalf("unexpected MIG config: %+v", cfg)
+	}
+	if len(cfg.ProfilesSupported) != 1 || cfg.ProfilesSupported[0] != "1g.10gb" {
+		t.Fatalf("unexpected profiles: %+v", cfg.ProfilesSupported)
+	}
+	if len(cfg.Types) != 1 {
+		t.Fatalf("expected single MIG type, got %+v", cfg.Types)
+	}
+	migType := cfg.Types[0]
+	if migType.Count != 2 || migType.Name != "1g.10gb" {
+		t.Fatalf("unexpected MIG type capacity: %+v", migType)
+	}
+	if len(p.warnings) != 0 {
+		t.Fatalf("unexpected warnings: %+v", p.warnings)
+	}
+}
+
+func TestParseMIGConfigVariants(t *testing.T) {
+	cfg := (&parser{}).migConfig(map[string]string{
+		GFDMigCapableLabel:             "true",
+		"nvidia.com/mig.strategy":      "mixed",
+		"nvidia.com/mig-1g.10gb.count": "2",
+	})
+	if !cfg.Capable {
+		t.Fatal("expected MIG capable true")
+	}
+	if cfg.Strategy != v1alpha1.GPUMIGStrategyMixed {
+		t.Fatalf("unexpected strategy: %s", cfg.Strategy)
+	}
+	if len(cfg.Types) != 1 || cfg.Types[0].Name != "1g.10gb" || cfg.Types[0].Count != 2 {
+		t.Fatalf("unexpected MIG types: %+v", cfg.Types)
+	}
+
+	cfg = (&parser{}).migConfig(map[string]string{
+		GFDMigAltCapableLabel: "false",
+	})
+	if cfg.Capable {
+		t.Fatal("expected MIG capable false from alt label")
+	}
+	if len(cfg.Types) != 0 {
+		t.Fatalf("unexpected MIG types when not capable: %+v", cfg.Types)
+	}
+}
+
+func TestParseMIGConfigIgnoresMalformedKeys(t *testing.T) {
+	labels := map[string]string{
+		"nvidia.com/mig-foo":           "1",
+		"nvidia.com/mig-1g":            "",
+		"nvidia.com/mig-1g.profile":    "",
+		"nvidia.com/mig-1g.5gb.":       "",
+		"nvidia.com/mig-3g.40gb.count": "",
+		"nvidia.com/mig-2g.20gb.count": "two",
+	}
+	p := &parser{}
+	cfg := p.migConfig(labels)
+	if len(cfg.Types) != 0 {
+		t.Fatalf("expected malformed labels to be ignored, got %+v", cfg.Types)
+	}
+	warned := map[string]bool{}
+	for _, issue := range p.warnings {
+		warned[issue.Key] = true
+	}
+	for _, key := range []string{"nvidia.com/mig-1g.profile", "nvidia.com/mig-1g.5gb.", "nvidia.com/mig-2g.20gb.count"} {
+		if !warned[key] {
+			t.Fatalf("expected warning for %s, got %+v", key, p.warnings)
+		}
+	}
+	if warned["nvidia.com/mig-foo"] || warned["nvidia.com/mig-3g.40gb.count"] {
+		t.Fatalf("unexpected warnings for non-profile or empty labels: %+v", p.warnings)
+	}
+}
+
+func TestParseMIGConfigUnknownStrategy(t *testing.T) {
+	p := &parser{}
+	cfg := p.migConfig(map[string]string{
+		"nvidia.com/mig.strategy": "unsupported",
+	})
+	if cfg.Strategy != v1alpha1.GPUMIGStrategyNone {
+		t.Fatalf("expected strategy fallback to none, got %s", cfg.Strategy)
+	}
+	if len(p.warnings) != 1 || p.warnings[0].Key != GFDMigStrategyLabel {
+		t.Fatalf("expected unknown strategy to be reported, got %+v", p.warnings)
+	}
+}
+
+func TestParseMIGConfigSortsMultipleTypes(t *testing.T) {
+	labels := map[string]string{
+		"nvidia.com/mig.capable":       "true",
+		"nvidia.com/mig-1g.10gb.count": "2",
+		"nvidia.com/mig-2g.20gb.count": "1",
+	}
+	cfg := (&parser{}).migConfig(labels)
+	if len(cfg.Types) != 2 {
+		t.Fatalf("expected two types, got %+v", cfg.Types)
+	}
+	if cfg.Types[0].Name != "1g.10gb" || cfg.Types[1].Name != "2g.20gb" {
+		t.Fatalf("expected sorted types, got %+v", cfg.Types)
+	}
+}
+
+func TestParseMIGConfigAlternativeLabels(t *testing.T) {
+	cfg := (&parser{}).migConfig(map[string]string{
+		GFDMigAltCapableLabel:              "true",
+		GFDMigAltStrategyLabel:             "single",
+		"nvidia.com/mig-2g.20gb.count":     "1",
+		"nvidia.com/mig-2g.20gb.ready":     "1",
+		"nvidia.com/mig-2g.20gb.available": "1",
+	})
+	if !cfg.Capable || cfg.Strategy != v1alpha1.GPUMIGStrategySingle {
+		t.Fatalf("expected capability and strategy from alternative labels, got %+v", cfg)
+	}
+	if len(cfg.Types) != 1 || cfg.Types[0].Name != "2g.20gb" {
+		t.Fatalf("expected alt label to produce type, got %+v", cfg.Types)
+	}
+}
+
+func TestParseResolvesMIGFromInstanceAndNodeLabels(t *testing.T) {
+	device := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "10de",
+		"gpu.deckhouse.io/device.00.device": "20b5",
+		"gpu.deckhouse.io/device.00.class":  "0302",
+	}
+	withLabels := func(extra map[string]string) map[string]string {
+		labels := map[string]string{}
+		for k, v := range device {
+			labels[k] = v
+		}
+		for k, v := range extra {
+			labels[k] = v
+		}
+		return labels
+	}
+	nodeMIG := map[string]string{
+		GFDMigCapableLabel:             "true",
+		GFDMigStrategyLabel:            "single",
+		"nvidia.com/mig-1g.10gb.count": "7",
+	}
+
+	cases := []struct {
+		name     string
+		labels   map[string]string
+		instance map[string]string
+		want     v1alpha1.GPUMIGConfig
+		conflict bool
+	}{
+		{
+			name:   "label only",
+			labels: withLabels(nodeMIG),
+			want: v1alpha1.GPUMIGConfig{
+				Capable:           true,
+				Strategy:          v1alpha1.GPUMIGStrategySingle,
+				ProfilesSupported: []string{"1g.10gb"},
+				Types:             []v1alpha1.GPUMIGTypeCapacity{{Name: "1g.10gb", Count: 7}},
+				Source:            v1alpha1.GPUMIGDataSourceNodeLabels,
+			},
+		},
+		{
+			name:   "instance only",
+			labels: withLabels(nil),
+			instance: map[string]string{
+				"mig.capable":           "true",
+				"mig.strategy":          "mixed",
+				"mig.profiles":          "MIG-3g.40gb,1g.10gb",
+				"mig-3g.40gb.available": "2",
+			},
+			want: v1alpha1.GPUMIGConfig{
+				Capable:           true,
+				Strategy:          v1alpha1.GPUMIGStrategyMixed,
+				ProfilesSupported: []string{"1g.10gb", "3g.40gb"},
+				Types:             []v1alpha1.GPUMIGTypeCapacity{{Name: "3g.40gb", Count: 2}},
+				Source:            v1alpha1.GPUMIGDataSourceInstance,
+			},
+		},
+		{
+			name:     "both agree",
+			labels:   withLabels(nodeMIG),
+			instance: map[string]string{"mig.strategy": "Single", "mig.profiles": "1g.10gb,7g.80gb"},
+			want: v1alpha1.GPUMIGConfig{
+				Capable:           true,
+				Strategy:          v1alpha1.GPUMIGStrategySingle,
+				ProfilesSupported: []string{"1g.10gb", "7g.80gb"},
+				Types:             []v1alpha1.GPUMIGTypeCapacity{{Name: "1g.10gb", Count: 7}},
+				Source:            v1alpha1.GPUMIGDataSourceMerged,
+			},
+		},
+		{
+			name:     "conflict",
+			labels:   withLabels(nodeMIG),
+			instance: map[string]string{"mig.capable": "true", "mig.strategy": "mixed"},
+			want: v1alpha1.GPUMIGConfig{
+				Capable:           true,
+				Strategy:          v1alpha1.GPUMIGStrategyMixed,
+				ProfilesSupported: []string{"1g.10gb"},
+				Types:             []v1alpha1.GPUMIGTypeCapacity{{Name: "1g.10gb", Count: 7}},
+				Source:            v1alpha1.GPUMIGDataSourceMerged,
+			},
+			conflict: true,
+		},
+		{
+			name:     "instance disables MIG",
+			labels:   withLabels(map[string]string{GFDMigCapableLabel: "true"}),
+			instance: map[string]string{"mig.capable": "false"},
+			want:     v1alpha1.GPUMIGConfig{Source: v1alpha1.GPUMIGDataSourceInstance},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := Input{NodeLabels: tc.labels}
+			if tc.instance != nil {
+				attrs := map[string]string{"index": "0"}
+				for k, v := range tc.instance {
+					attrs[k] = v
+				}
+				in.Instances = []map[string]string{attrs}
+			}
+			got := Parse(in)
+			if len(got.Devices) != 1 {
+				t.Fatalf("expected one device, got %+v", got.Devices)
+			}
+			dev := got.Devices[0]
+			if !reflect.DeepEqual(dev.MIG, tc.want) {
+				t.Fatalf("MIG=%+v, want %+v", dev.MIG, tc.want)
+			}
+			if (dev.MIGConflict != "") != tc.conflict {
+				t.Fatalf("unexpected conflict %q", dev.MIGConflict)
+			}
+			if tc.conflict && dev.MIGConflict != "instance reports MIG strategy Mixed, node labels report Single" {
+				t.Fatalf("unexpected conflict message %q", dev.MIGConflict)
+			}
+		})
+	}
+}
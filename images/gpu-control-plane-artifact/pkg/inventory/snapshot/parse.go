@@ -0,0 +1,218 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/detection"
+)
+
+var (
+	// ErrNegative is returned for quantities below zero.
+	ErrNegative = errors.New("value must not be negative")
+	// ErrOutOfRange is returned for quantities that do not fit into int32.
+	ErrOutOfRange = errors.New("value is out of range")
+	// ErrMissingMinorVersion is returned when the compute capability lacks a minor version.
+	ErrMissingMinorVersion = errors.New("compute capability minor version is missing")
+)
+
+// ParseMemoryMiB converts a memory size such as "40536 MiB", "40536MiB", "40 GiB" or "42505273344 B" to MiB.
+// A value without a unit is taken as MiB. Byte and KiB sizes are rounded down to whole MiB through
+// detection.Bytes, the same conversion the detection API uses. An empty value yields zero without an error.
+func ParseMemoryMiB(value string) (int32, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, unit := splitNumber(value, true)
+	if number == "" {
+		return 0, fmt.Errorf("no numeric value in %q", value)
+	}
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %q: %w", number, err)
+	}
+	if parsed < 0 {
+		return 0, ErrNegative
+	}
+
+	switch strings.ToLower(unit) {
+	case "b":
+		return bytesToMiB(parsed)
+	case "ki", "kib", "kb":
+		return bytesToMiB(parsed * 1024)
+	case "", "mi", "mib", "mb":
+	case "gi", "gib", "gb":
+		parsed *= 1024
+	case "ti", "tib", "tb":
+		parsed *= 1024 * 1024
+	default:
+		return 0, fmt.Errorf("unknown memory unit %q", unit)
+	}
+
+	parsed = math.Round(parsed)
+	if parsed > math.MaxInt32 {
+		return 0, ErrOutOfRange
+	}
+	return int32(parsed), nil
+}
+
+func bytesToMiB(bytes float64) (int32, error) {
+	if bytes >= float64(detection.MiB(math.MaxInt32).Bytes()) {
+		return 0, ErrOutOfRange
+	}
+	return int32(detection.Bytes(bytes).MiB()), nil
+}
+
+// ParseInt32 parses a non-negative integer, optionally followed by a unit such as "1410 MHz".
+// An empty value yields zero without an error.
+func ParseInt32(value string) (int32, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, unit := splitNumber(value, false)
+	if number == "" {
+		return 0, fmt.Errorf("no numeric value in %q", value)
+	}
+	if strings.IndexFunc(unit, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+		return 0, fmt.Errorf("unexpected suffix %q", unit)
+	}
+	parsed, err := strconv.ParseInt(number, 10, 32)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrOutOfRange
+		}
+		return 0, fmt.Errorf("parse %q: %w", number, err)
+	}
+	if parsed < 0 {
+		return 0, ErrNegative
+	}
+	return int32(parsed), nil
+}
+
+// ParseComputeCapability parses the CUDA compute capability reported as separate major and minor
+// values. When only the minor version is missing the major version is returned together with
+// ErrMissingMinorVersion, so callers can keep the partial value and report the gap.
+func ParseComputeCapability(major, minor string) (int32, int32, error) {
+	major = strings.TrimSpace(major)
+	minor = strings.TrimSpace(minor)
+	if major == "" && minor == "" {
+		return 0, 0, nil
+	}
+	if major == "" {
+		return 0, 0, errors.New("compute capability major version is missing")
+	}
+	maj, err := ParseInt32(major)
+	if err != nil {
+		return 0, 0, fmt.Errorf("major version: %w", err)
+	}
+	if minor == "" {
+		return maj, 0, ErrMissingMinorVersion
+	}
+	mnr, err := ParseInt32(minor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("minor version: %w", err)
+	}
+	return maj, mnr, nil
+}
+
+// splitNumber separates the leading signed number from the trimmed remainder.
+func splitNumber(value string, allowFraction bool) (string, string) {
+	end := 0
+	for i, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+		case r == '.' && allowFraction:
+		case (r == '-' || r == '+') && i == 0:
+		default:
+			return value[:end], strings.TrimSpace(value[end:])
+		}
+		end = i + 1
+	}
+	return value, ""
+}
+
+// parseBool reports the boolean value and whether the value was recognised at all.
+func parseBool(value string) (bool, bool) {
+	switch strings.TrimSpace(strings.ToLower(value)) {
+	case "true", "1", "yes", "on":
+		return true, true
+	case "false", "0", "no", "off":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func (p *parser) memoryMiB(source, key, value string) int32 {
+	parsed, err := ParseMemoryMiB(value)
+	if err != nil {
+		p.warn(source, key, value, err)
+	}
+	return parsed
+}
+
+func (p *parser) int32(source, key, value string) int32 {
+	parsed, err := ParseInt32(value)
+	if err != nil {
+		p.warn(source, key, value, err)
+	}
+	return parsed
+}
+
+func (p *parser) optionalInt32(source, key, value string) *int32 {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parsed, err := ParseInt32(value)
+	if err != nil {
+		p.warn(source, key, value, err)
+		return nil
+	}
+	return &parsed
+}
+
+func (p *parser) computeCapability(source, majorKey, minorKey, major, minor string) (int32, int32) {
+	maj, mnr, err := ParseComputeCapability(major, minor)
+	if err == nil {
+		return maj, mnr
+	}
+	if _, majorErr := ParseInt32(major); strings.TrimSpace(major) == "" || majorErr != nil {
+		p.warn(source, majorKey, major, err)
+	} else {
+		p.warn(source, minorKey, minor, err)
+	}
+	return maj, mnr
+}
+
+func (p *parser) bool(source, key, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
+	}
+	parsed, ok := parseBool(value)
+	if !ok {
+		p.warn(source, key, value, fmt.Errorf("not a boolean"))
+	}
+	return parsed
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMemoryMiBVariants(t *testing.T) {
+	cases := map[string]int32{
+		"":             0,
+		"40960 MiB":    40960,
+		"40536MiB":     40536,
+		"40536":        40536,
+		"40 GiB":       40960,
+		"12GiB":        12288,
+		"16 GB":        16384,
+		"0.5 TiB":      524288,
+		" 1024 mib":    1024,
+		"1073741824 B": 1024,
+		"42505273344B": 40536,
+		"1048576 KiB":  1024,
+		"1048575 B":    0,
+	}
+	for in, want := range cases {
+		got, err := ParseMemoryMiB(in)
+		if err != nil {
+			t.Fatalf("ParseMemoryMiB(%q) returned error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("ParseMemoryMiB(%q)=%d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParseMemoryMiBRejectsMalformedValues(t *testing.T) {
+	for _, in := range []string{"unknown", "512foobar", "MiB", "1.2.3 MiB", "NaN", "-"} {
+		if got, err := ParseMemoryMiB(in); err == nil {
+			t.Fatalf("expected error for %q, got %d", in, got)
+		}
+	}
+	if _, err := ParseMemoryMiB("-512 MiB"); !errors.Is(err, ErrNegative) {
+		t.Fatalf("expected ErrNegative for negative memory, got %v", err)
+	}
+}
+
+func TestParseMemoryMiBHandlesErrRange(t *testing.T) {
+	for _, in := range []string{strings.Repeat("9", 400) + " MiB", "4096 TiB", "2251799813685248 B"} {
+		if _, err := ParseMemoryMiB(in); err == nil {
+			t.Fatalf("expected overflow error for %q", in)
+		}
+	}
+}
+
+func TestParseInt32Variants(t *testing.T) {
+	cases := map[string]int32{
+		"":       0,
+		"42":     42,
+		"42 MHz": 42,
+		"250W":   250,
+		"007":    7,
+		"+3":     3,
+	}
+	for in, want := range cases {
+		got, err := ParseInt32(in)
+		if err != nil {
+			t.Fatalf("ParseInt32(%q) returned error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("ParseInt32(%q)=%d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParseInt32RejectsMalformedValues(t *testing.T) {
+	for _, in := range []string{"not-a-number", "unknown", "4.5", "12-13"} {
+		if got, err := ParseInt32(in); err == nil {
+			t.Fatalf("expected error for %q, got %d", in, got)
+		}
+	}
+	if _, err := ParseInt32("-1"); !errors.Is(err, ErrNegative) {
+		t.Fatalf("expected ErrNegative, got %v", err)
+	}
+	if _, err := ParseInt32(strings.Repeat("9", 40)); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestParseComputeCapability(t *testing.T) {
+	major, minor, err := ParseComputeCapability("8", "6")
+	if err != nil || major != 8 || minor != 6 {
+		t.Fatalf("unexpected result %d.%d (%v)", major, minor, err)
+	}
+	if major, minor, err := ParseComputeCapability("", ""); err != nil || major != 0 || minor != 0 {
+		t.Fatalf("expected empty capability without error, got %d.%d (%v)", major, minor, err)
+	}
+
+	major, minor, err = ParseComputeCapability("9", "")
+	if !errors.Is(err, ErrMissingMinorVersion) || major != 9 || minor != 0 {
+		t.Fatalf("expected major with missing minor error, got %d.%d (%v)", major, minor, err)
+	}
+	if _, _, err := ParseComputeCapability("", "0"); err == nil {
+		t.Fatalf("expected error for missing major version")
+	}
+	if _, _, err := ParseComputeCapability("-8", "0"); !errors.Is(err, ErrNegative) {
+		t.Fatalf("expected negative major to be rejected, got %v", err)
+	}
+	if _, _, err := ParseComputeCapability("8", "unknown"); err == nil {
+		t.Fatalf("expected malformed minor to be rejected")
+	}
+}
+
+func TestParseBoolVariants(t *testing.T) {
+	for in, want := range map[string]bool{"true": true, "YES": true, "1": true, "off": false, "false": false} {
+		got, ok := parseBool(in)
+		if !ok || got != want {
+			t.Fatalf("parseBool(%q)=%v,%v", in, got, ok)
+		}
+	}
+	if _, ok := parseBool("maybe"); ok {
+		t.Fatalf("expected unknown value to be unrecognised")
+	}
+}
+
+func TestParserOptionalInt32(t *testing.T) {
+	p := &parser{}
+	if val := p.optionalInt32(labelSource, "k", ""); val != nil {
+		t.Fatalf("expected nil for empty value, got %v", val)
+	}
+	assertIntPtr(t, p.optionalInt32(labelSource, "k", "42"), 42)
+	if val := p.optionalInt32(labelSource, "k", "-1"); val != nil {
+		t.Fatalf("expected nil for negative value, got %v", *val)
+	}
+	if len(p.warnings) != 1 || p.warnings[0].Key != "k" || p.warnings[0].Value != "-1" {
+		t.Fatalf("expected a warning for the negative value, got %+v", p.warnings)
+	}
+}
+
+func TestParseDriverInfoRuntimeFallback(t *testing.T) {
+	p := &parser{}
+	info := p.driver(map[string]string{
+		GFDDriverVersionLabel:        "535.80.10",
+		GFDCudaRuntimeVersionLabel:   "12.4",
+		DeckhouseToolkitReadyLabel:   "true",
+		DeckhouseToolkitInstalled:    "false",
+		DeckhouseToolkitVersionLabel: " 1.14.2 ",
+	})
+	if info.CUDAVersion != "12.4" {
+		t.Fatalf("expected runtime version fallback, got %s", info.CUDAVersion)
+	}
+	if !info.ToolkitInstalled || !info.ToolkitReady {
+		t.Fatalf("expected toolkit installed to be forced when ready, got %+v", info)
+	}
+	if info.ToolkitVersion != "1.14.2" {
+		t.Fatalf("expected trimmed toolkit version, got %q", info.ToolkitVersion)
+	}
+	if len(p.warnings) != 0 {
+		t.Fatalf("unexpected warnings: %+v", p.warnings)
+	}
+}
+
+func assertIntPtr(t *testing.T, ptr *int32, expected int32) {
+	t.Helper()
+	if ptr == nil || *ptr != expected {
+		t.Fatalf("expected %d, got %v", expected, ptr)
+	}
+}
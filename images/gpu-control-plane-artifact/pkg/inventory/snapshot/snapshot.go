@@ -0,0 +1,172 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot parses the GPU labels and NodeFeature instance attributes published by
+// NFD, GFD and gfd-extender into a typed per-node hardware snapshot. It contains no policy:
+// callers decide which nodes are managed and which devices are attached.
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+)
+
+// Input is the raw hardware description of a node.
+type Input struct {
+	// NodeLabels are the labels of the Node object. They take precedence over FeatureLabels.
+	NodeLabels map[string]string
+	// FeatureLabels are the labels published through the node's NodeFeature.
+	FeatureLabels map[string]string
+	// Instances are the attribute sets of the nvidia.com/gpu NodeFeature instance feature.
+	Instances []map[string]string
+	// Vendors claims labelled devices and fills in vendor data; nil keeps every identified device as reported.
+	Vendors Vendors
+}
+
+// Vendors is the vendor-specific part of parsing, implemented by the device provider registry.
+type Vendors interface {
+	// Identify reports whether a device announced through node labels belongs to the inventory.
+	Identify(device Device) bool
+	// Enrich fills fields the vendor can derive from the PCI identifiers, such as the product name.
+	Enrich(device *Device)
+}
+
+// Snapshot is the parsed hardware description of a node.
+type Snapshot struct {
+	// Labels are the merged node and NodeFeature labels the snapshot was built from.
+	Labels  map[string]string
+	Driver  Driver
+	Devices []Device
+	// Warnings list values that were malformed and ignored; the affected field is left unset.
+	Warnings []Issue
+	// Errors list device entries that were dropped because they could not be identified.
+	Errors []Issue
+}
+
+// Driver describes the NVIDIA driver and toolkit state of a node.
+type Driver struct {
+	Version          string
+	CUDAVersion      string
+	ToolkitInstalled bool
+	ToolkitReady     bool
+	// ToolkitVersion is the NVIDIA container toolkit version, empty when the node does not report it.
+	ToolkitVersion string
+}
+
+// Device describes a single GPU found on a node.
+type Device struct {
+	Index        string
+	Vendor       string
+	Device       string
+	Class        string
+	PCIAddress   string
+	Product      string
+	MemoryMiB    int32
+	ComputeMajor int32
+	ComputeMinor int32
+	UUID         string
+	Precision    []string
+	NUMANode     *int32
+	PowerLimitMW *int32
+	SMCount      *int32
+	MemBandwidth *int32
+	PCIEGen      *int32
+	PCIELinkWid  *int32
+	Board        string
+	Family       string
+	Serial       string
+	PState       string
+	DisplayMode  string
+	MIG          v1alpha1.GPUMIGConfig
+	// MIGConflict explains a MIG strategy the instance and the node labels disagree on; empty when they agree.
+	MIGConflict string
+	// Attributes are the instance attributes the device was reported with, verbatim.
+	Attributes map[string]string
+}
+
+// Issue points at a label or instance attribute that could not be used as reported.
+type Issue struct {
+	// Source is "label" for node and NodeFeature labels or "instance/<index>" for instance attributes.
+	Source string
+	Key    string
+	Value  string
+	Reason string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s=%q: %s", i.Source, i.Key, i.Value, i.Reason)
+}
+
+// Parse builds a snapshot from the node labels, NodeFeature labels and instance attributes.
+func Parse(in Input) Snapshot {
+	labels := make(map[string]string, len(in.NodeLabels)+len(in.FeatureLabels))
+	for key, value := range in.NodeLabels {
+		labels[key] = value
+	}
+	for key, value := range in.FeatureLabels {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+		}
+	}
+
+	p := &parser{vendors: in.Vendors}
+	devices := p.extractDevices(labels)
+	p.defaults = p.hardwareDefaults(labels)
+	applyHardwareDefaults(devices, p.defaults)
+	devices = p.enrichFromInstances(devices, in.Instances)
+	p.enrich(devices)
+	canonicalizeDevices(devices)
+	driver := p.driver(labels)
+	for _, issue := range p.incomplete {
+		p.errors = append(p.errors, issue)
+	}
+
+	return Snapshot{
+		Labels:   labels,
+		Driver:   driver,
+		Devices:  devices,
+		Warnings: sortIssues(p.warnings),
+		Errors:   sortIssues(p.errors),
+	}
+}
+
+// parser accumulates issues while the snapshot is assembled.
+type parser struct {
+	warnings   []Issue
+	errors     []Issue
+	incomplete map[string]Issue
+	// defaults are the node-wide GFD values, also applied to devices known only from instances.
+	defaults Device
+	vendors  Vendors
+}
+
+func (p *parser) warn(source, key, value string, err error) {
+	p.warnings = append(p.warnings, Issue{Source: source, Key: key, Value: value, Reason: err.Error()})
+}
+
+func (p *parser) drop(source, key, value, reason string) {
+	p.errors = append(p.errors, Issue{Source: source, Key: key, Value: value, Reason: reason})
+}
+
+func sortIssues(issues []Issue) []Issue {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Source != issues[j].Source {
+			return issues[i].Source < issues[j].Source
+		}
+		return issues[i].Key < issues[j].Key
+	})
+	return issues
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import "testing"
+
+func TestParseMergesLabelsWithNodePrecedence(t *testing.T) {
+	snap := Parse(Input{
+		NodeLabels: map[string]string{
+			"gpu.deckhouse.io/device.0.vendor": "10de",
+			"gpu.deckhouse.io/device.0.device": "1db4",
+			"gpu.deckhouse.io/device.0.class":  "0300",
+			GFDProductLabel:                    "Node Product",
+		},
+		FeatureLabels: map[string]string{
+			GFDProductLabel:            "Feature Product",
+			GFDDriverVersionLabel:      "535.104.05",
+			GFDCudaRuntimeVersionLabel: "12.2",
+		},
+	})
+	if len(snap.Devices) != 1 || snap.Devices[0].Product != "Node Product" {
+		t.Fatalf("expected node labels to win, got %+v", snap.Devices)
+	}
+	if snap.Driver.Version != "535.104.05" {
+		t.Fatalf("expected driver version from feature labels, got %+v", snap.Driver)
+	}
+	if len(snap.Warnings) != 0 || len(snap.Errors) != 0 {
+		t.Fatalf("unexpected issues: %+v %+v", snap.Warnings, snap.Errors)
+	}
+}
+
+func TestParseReportsMalformedValues(t *testing.T) {
+	snap := Parse(Input{
+		NodeLabels: map[string]string{
+			"gpu.deckhouse.io/device.0.vendor":    "10de",
+			"gpu.deckhouse.io/device.0.device":    "1db4",
+			"gpu.deckhouse.io/device.0.class":     "0300",
+			"gpu.deckhouse.io/device.0.memoryMiB": "-1",
+			GFDComputeMajorLabel:                  "8",
+		},
+		Instances: []map[string]string{
+			{"index": "0", "sm.count": "unknown"},
+		},
+	})
+	if len(snap.Devices) != 1 {
+		t.Fatalf("expected one device, got %+v", snap.Devices)
+	}
+	dev := snap.Devices[0]
+	if dev.MemoryMiB != 0 || dev.SMCount != nil || dev.ComputeMajor != 8 {
+		t.Fatalf("expected malformed fields to stay unset, got %+v", dev)
+	}
+	keys := map[string]bool{}
+	for _, issue := range snap.Warnings {
+		keys[issue.Key] = true
+	}
+	for _, key := range []string{"gpu.deckhouse.io/device.0.memoryMiB", GFDComputeMinorLabel, "sm.count"} {
+		if !keys[key] {
+			t.Fatalf("expected warning for %s, got %+v", key, snap.Warnings)
+		}
+	}
+}
+
+func TestParseReportsUnidentifiedDevices(t *testing.T) {
+	snap := Parse(Input{
+		NodeLabels: map[string]string{
+			"gpu.deckhouse.io/device.0.vendor": "10de",
+			"gpu.deckhouse.io/device.0.device": "1db4",
+		},
+	})
+	if len(snap.Devices) != 0 {
+		t.Fatalf("expected device without class to be dropped, got %+v", snap.Devices)
+	}
+	if len(snap.Errors) != 1 || snap.Errors[0].Source != labelSource {
+		t.Fatalf("expected incomplete device to be reported, got %+v", snap.Errors)
+	}
+
+	snap = Parse(Input{
+		NodeLabels: map[string]string{
+			"gpu.deckhouse.io/device.0.vendor": "10de",
+			"gpu.deckhouse.io/device.0.device": "1db4",
+		},
+		Instances: []map[string]string{
+			{"index": "0", "vendor": "10de", "device": "1db4", "class": "0302"},
+		},
+	})
+	if len(snap.Devices) != 1 || snap.Devices[0].Class != "0302" {
+		t.Fatalf("expected instance attributes to complete the device, got %+v", snap.Devices)
+	}
+	if len(snap.Errors) != 0 {
+		t.Fatalf("expected no errors once the device is complete, got %+v", snap.Errors)
+	}
+}
+
+// Regression inputs seen from a misbehaving GFD: each malformed value must leave its field unset
+// and be reported instead of panicking or being replaced by a zero value silently.
+func TestParseMalformedInstanceAttributes(t *testing.T) {
+	identity := map[string]string{"index": "0", "vendor": "10de", "device": "2330", "class": "0302"}
+	with := func(key, value string) map[string]string {
+		attrs := map[string]string{key: value}
+		for k, v := range identity {
+			attrs[k] = v
+		}
+		return attrs
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		attrs  map[string]string
+		key    string
+		check  func(Device) bool
+	}{
+		{
+			name:   "NaN memory",
+			labels: map[string]string{GFDMemoryLabel: "81559"},
+			attrs:  with("memory.total", "NaN MiB"),
+			key:    "memory.total",
+			check:  func(d Device) bool { return d.MemoryMiB == 81559 },
+		},
+		{
+			name:   "float compute major",
+			labels: map[string]string{GFDComputeMajorLabel: "9", GFDComputeMinorLabel: "0"},
+			attrs:  with("compute.major", "8.0"),
+			key:    "compute.major",
+			check:  func(d Device) bool { return d.ComputeMajor == 9 && d.ComputeMinor == 0 },
+		},
+		{
+			name:   "device label without field",
+			labels: map[string]string{DeviceLabelPrefix + "0": "10de", DeviceLabelPrefix: ""},
+			attrs:  identity,
+			key:    DeviceLabelPrefix + "0",
+			check:  func(d Device) bool { return d.Vendor == VendorNvidia },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := Parse(Input{NodeLabels: tt.labels, Instances: []map[string]string{tt.attrs}})
+			if len(snap.Devices) != 1 || !tt.check(snap.Devices[0]) {
+				t.Fatalf("unexpected devices %+v", snap.Devices)
+			}
+			reported := false
+			for _, issue := range append(snap.Warnings, snap.Errors...) {
+				if issue.Key == tt.key {
+					reported = true
+				}
+			}
+			if !reported {
+				t.Fatalf("expected %s to be reported, got warnings %+v errors %+v", tt.key, snap.Warnings, snap.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/httpauth"
+)
+
+const (
+	defaultTokenCacheTTL = time.Minute
+
+	// readVerb and readResource are the RBAC permission a caller needs to read the inventory: the API serves
+	// the whole fleet, so the caller must be allowed to list GPUDevices cluster-wide.
+	readVerb     = "list"
+	readResource = "gpudevices"
+)
+
+// Authenticator resolves bearer tokens presented by API clients to the identity of the caller.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (user authenticationv1.UserInfo, ok bool, err error)
+}
+
+// Authorizer decides whether an authenticated caller may read the inventory.
+type Authorizer interface {
+	Authorize(ctx context.Context, user authenticationv1.UserInfo) (bool, error)
+}
+
+// ttlCache keeps positive review answers briefly, so frequent dashboard polls do not translate into apiserver calls.
+type ttlCache[V any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[[sha256.Size]byte]ttlEntry[V]
+	nextSweep time.Time
+}
+
+type ttlEntry[V any] struct {
+	value  V
+	expiry time.Time
+}
+
+func newTTLCache[V any]() *ttlCache[V] {
+	return &ttlCache[V]{ttl: defaultTokenCacheTTL, now: time.Now, entries: make(map[[sha256.Size]byte]ttlEntry[V])}
+}
+
+func (c *ttlCache[V]) get(key [sha256.Size]byte) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok && c.now().After(entry.expiry) {
+		delete(c.entries, key)
+		ok = false
+	}
+	return entry.value, ok
+}
+
+// put stores value under key. Rotated tokens and changed group sets are never asked for again, so at most once per
+// ttl the expired entries are swept here and the cache stays bounded by the callers of the last ttl.
+func (c *ttlCache[V]) put(key [sha256.Size]byte, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if !now.Before(c.nextSweep) {
+		for k, entry := range c.entries {
+			if now.After(entry.expiry) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	c.entries[key] = ttlEntry[V]{value: value, expiry: now.Add(c.ttl)}
+}
+
+// TokenReviewAuthenticator validates tokens through the TokenReview API and caches the identity of
+// accepted tokens briefly.
+type TokenReviewAuthenticator struct {
+	client client.Client
+	cache  *ttlCache[authenticationv1.UserInfo]
+}
+
+// NewTokenReviewAuthenticator constructs an authenticator backed by TokenReview.
+func NewTokenReviewAuthenticator(c client.Client) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{client: c, cache: newTTLCache[authenticationv1.UserInfo]()}
+}
+
+// Authenticate implements Authenticator.
+func (a *TokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (authenticationv1.UserInfo, bool, error) {
+	key := sha256.Sum256([]byte(token))
+	if user, ok := a.cache.get(key); ok {
+		return user, true, nil
+	}
+
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := a.client.Create(ctx, review); err != nil {
+		return authenticationv1.UserInfo{}, false, fmt.Errorf("create TokenReview: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return authenticationv1.UserInfo{}, false, nil
+	}
+
+	a.cache.put(key, review.Status.User)
+	return review.Status.User, true, nil
+}
+
+// SubjectAccessReviewAuthorizer admits callers allowed to list GPUDevices cluster-wide, checked through the
+// SubjectAccessReview API. Allowed answers are cached briefly per identity; denials are not, so a granted
+// role takes effect on the next request.
+type SubjectAccessReviewAuthorizer struct {
+	client client.Client
+	cache  *ttlCache[struct{}]
+}
+
+// NewSubjectAccessReviewAuthorizer constructs an authorizer backed by SubjectAccessReview.
+func NewSubjectAccessReviewAuthorizer(c client.Client) *SubjectAccessReviewAuthorizer {
+	return &SubjectAccessReviewAuthorizer{client: c, cache: newTTLCache[struct{}]()}
+}
+
+// Authorize implements Authorizer.
+func (a *SubjectAccessReviewAuthorizer) Authorize(ctx context.Context, user authenticationv1.UserInfo) (bool, error) {
+	key := sha256.Sum256([]byte(strings.Join(append([]string{user.Username, user.UID}, user.Groups...), "\x00")))
+	if _, ok := a.cache.get(key); ok {
+		return true, nil
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Verb:     readVerb,
+			Group:    v1alpha1.GroupVersion.Group,
+			Resource: readResource,
+		},
+	}}
+	if err := a.client.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("create SubjectAccessReview: %w", err)
+	}
+	if !review.Status.Allowed {
+		return false, nil
+	}
+
+	a.cache.put(key, struct{}{})
+	return true, nil
+}
+
+func (h *Handler) withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := httpauth.BearerToken(r)
+		if token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		user, ok, err := h.auth.Authenticate(r.Context(), token)
+		if err != nil {
+			h.log.Error(err, "failed to authenticate request", "path", r.URL.Path)
+			http.Error(w, "authentication failed", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		allowed, err := h.authz.Authorize(r.Context(), user)
+		if err != nil {
+			h.log.Error(err, "failed to authorize request", "path", r.URL.Path, "user", user.Username)
+			http.Error(w, "authorization failed", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			h.log.Info("rejected inventory request", "path", r.URL.Path, "user", user.Username)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestSubjectAccessReviewAuthorizer(t *testing.T) {
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	cl := clientfake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			reviews = append(reviews, review.Spec)
+			review.Status.Allowed = review.Spec.User == testReader
+			return nil
+		},
+	}).Build()
+	authz := NewSubjectAccessReviewAuthorizer(cl)
+
+	reader := authenticationv1.UserInfo{Username: testReader, Groups: []string{"system:serviceaccounts"}}
+	for range 2 {
+		allowed, err := authz.Authorize(context.Background(), reader)
+		if err != nil || !allowed {
+			t.Fatalf("expected reader to be allowed, got %v, %v", allowed, err)
+		}
+	}
+	if len(reviews) != 1 {
+		t.Fatalf("expected the allowed answer to be cached, got %d reviews", len(reviews))
+	}
+	attrs := reviews[0].ResourceAttributes
+	if attrs == nil || attrs.Verb != "list" || attrs.Group != "gpu.deckhouse.io" || attrs.Resource != "gpudevices" || attrs.Namespace != "" {
+		t.Fatalf("unexpected resource attributes %+v", attrs)
+	}
+	if len(reviews[0].Groups) != 1 || reviews[0].Groups[0] != "system:serviceaccounts" {
+		t.Fatalf("expected caller groups in the review, got %+v", reviews[0])
+	}
+
+	other := authenticationv1.UserInfo{Username: "system:serviceaccount:default:default"}
+	for range 2 {
+		allowed, err := authz.Authorize(context.Background(), other)
+		if err != nil || allowed {
+			t.Fatalf("expected other service account to be denied, got %v, %v", allowed, err)
+		}
+	}
+	if len(reviews) != 3 {
+		t.Fatalf("expected denials not to be cached, got %d reviews", len(reviews))
+	}
+}
+
+func TestTTLCacheSweepsExpiredEntries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newTTLCache[struct{}]()
+	cache.now = func() time.Time { return now }
+
+	for i := range 3 {
+		cache.put(sha256.Sum256([]byte{byte(i)}), struct{}{})
+	}
+	now = now.Add(2 * cache.ttl)
+	cache.put(sha256.Sum256([]byte("rotated")), struct{}{})
+	if len(cache.entries) != 1 {
+		t.Fatalf("expected expired entries to be swept on put, got %d entries", len(cache.entries))
+	}
+	if _, ok := cache.get(sha256.Sum256([]byte("rotated"))); !ok {
+		t.Fatalf("expected the fresh entry to be kept")
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 500
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Device is the API representation of a GPUDevice.
+type Device struct {
+	Name        string `json:"name"`
+	Node        string `json:"node,omitempty"`
+	InventoryID string `json:"inventoryID,omitempty"`
+	State       string `json:"state,omitempty"`
+	Managed     bool   `json:"managed"`
+	// Pool is namespace/name for a GPUPool and the name for a ClusterGPUPool.
+	Pool       string            `json:"pool,omitempty"`
+	Product    string            `json:"product,omitempty"`
+	UUID       string            `json:"uuid,omitempty"`
+	PCIAddress string            `json:"pciAddress,omitempty"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// DeviceList is a single page of devices.
+type DeviceList struct {
+	Items []Device `json:"items"`
+	Page  int      `json:"page"`
+	Limit int      `json:"limit"`
+	Total int      `json:"total"`
+}
+
+// Node is the API representation of a GPU node with its devices.
+type Node struct {
+	Name       string            `json:"name"`
+	Conditions map[string]string `json:"conditions,omitempty"`
+	Devices    []Device          `json:"devices"`
+}
+
+// Handler serves the read-only inventory API and the ModuleConfig impact preview. All reads go through the provided reader,
+// which is expected to be the manager cache.
+type Handler struct {
+	reader    client.Reader
+	auth      Authenticator
+	authz     Authorizer
+	previewer Previewer
+	log       logr.Logger
+}
+
+// NewHandler constructs the API handler. Every route requires a caller that auth authenticates and authz admits.
+func NewHandler(log logr.Logger, reader client.Reader, auth Authenticator, authz Authorizer) *Handler {
+	return &Handler{reader: reader, auth: auth, authz: authz, log: log}
+}
+
+// Routes returns the HTTP routes with authentication and authorization applied.
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/devices", h.listDevices)
+	mux.HandleFunc("GET /api/v1/nodes/{name}", h.getNode)
+	mux.HandleFunc("POST /api/v1/preview/moduleconfig", h.previewModuleConfig)
+	mux.HandleFunc("GET /api/openapi.json", h.openAPI)
+	return h.withAuth(mux)
+}
+
+func (h *Handler) listDevices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, err := positiveInt(query.Get("page"), 1)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid page: %v", err), http.StatusBadRequest)
+		return
+	}
+	limit, err := positiveInt(query.Get("limit"), defaultPageSize)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid limit: %v", err), http.StatusBadRequest)
+		return
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	node := query.Get("node")
+	state := query.Get("state")
+	pool := query.Get("pool")
+
+	var opts []client.ListOption
+	if node != "" {
+		opts = append(opts, client.MatchingFields{indexer.GPUDeviceNodeField: node})
+	}
+	list := &v1alpha1.GPUDeviceList{}
+	if err := h.reader.List(r.Context(), list, opts...); err != nil {
+		h.log.Error(err, "failed to list GPUDevices from cache")
+		http.Error(w, "failed to list devices", http.StatusInternalServerError)
+		return
+	}
+
+	matched := make([]v1alpha1.GPUDevice, 0, len(list.Items))
+	for _, item := range list.Items {
+		if state != "" && string(item.Status.State) != state {
+			continue
+		}
+		if pool != "" && poolKey(item.Status.PoolRef) != pool {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name < matched[j].Name })
+
+	start := (page - 1) * limit
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+	window := matched[start:end]
+
+	etag := computeETag(fmt.Sprintf("devices|%s|%s|%s|%d|%d|%d", node, state, pool, page, limit, len(matched)), objectMetas(window))
+	if notModified(w, r, etag) {
+		return
+	}
+
+	resp := DeviceList{Items: make([]Device, 0, len(window)), Page: page, Limit: limit, Total: len(matched)}
+	for i := range window {
+		resp.Items = append(resp.Items, toDevice(&window[i]))
+	}
+	h.writeJSON(w, resp)
+}
+
+func (h *Handler) getNode(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	nodeState := &v1alpha1.GPUNodeState{}
+	if err := h.reader.Get(r.Context(), client.ObjectKey{Name: name}, nodeState); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			http.Error(w, "node not found", http.StatusNotFound)
+			return
+		}
+		h.log.Error(err, "failed to get GPUNodeState from cache", "node", name)
+		http.Error(w, "failed to get node", http.StatusInternalServerError)
+		return
+	}
+
+	devices := &v1alpha1.GPUDeviceList{}
+	if err := h.reader.List(r.Context(), devices, client.MatchingFields{indexer.GPUDeviceNodeField: name}); err != nil {
+		h.log.Error(err, "failed to list GPUDevices from cache", "node", name)
+		http.Error(w, "failed to list devices", http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(devices.Items, func(i, j int) bool { return devices.Items[i].Name < devices.Items[j].Name })
+
+	metas := append([]metav1.Object{&nodeState.ObjectMeta}, objectMetas(devices.Items)...)
+	etag := computeETag("node|"+name, metas)
+	if notModified(w, r, etag) {
+		return
+	}
+
+	resp := Node{Name: name, Conditions: conditionStatuses(nodeState.Status.Conditions), Devices: make([]Device, 0, len(devices.Items))}
+	for i := range devices.Items {
+		resp.Devices = append(resp.Devices, toDevice(&devices.Items[i]))
+	}
+	h.writeJSON(w, resp)
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		h.log.Error(err, "failed to encode response")
+	}
+}
+
+func toDevice(dev *v1alpha1.GPUDevice) Device {
+	out := Device{
+		Name:        dev.Name,
+		Node:        dev.Status.NodeName,
+		InventoryID: dev.Status.InventoryID,
+		State:       string(dev.Status.State),
+		Managed:     dev.Status.Managed,
+		Product:     dev.Status.Hardware.Product,
+		UUID:        dev.Status.Hardware.UUID,
+		PCIAddress:  dev.Status.Hardware.PCI.Address,
+		Conditions:  conditionStatuses(dev.Status.Conditions),
+	}
+	out.Pool = poolKey(dev.Status.PoolRef)
+	return out
+}
+
+// poolKey identifies the pool of a device: namespace/name for a GPUPool, the bare name for a ClusterGPUPool, so
+// same-named pools of different namespaces and scopes stay apart.
+func poolKey(ref *v1alpha1.GPUPoolReference) string {
+	if ref == nil || ref.Name == "" {
+		return ""
+	}
+	if ref.Namespace != "" {
+		return ref.Namespace + "/" + ref.Name
+	}
+	return ref.Name
+}
+
+func conditionStatuses(conds []metav1.Condition) map[string]string {
+	if len(conds) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(conds))
+	for _, cond := range conds {
+		out[cond.Type] = string(cond.Status)
+	}
+	return out
+}
+
+func objectMetas(devices []v1alpha1.GPUDevice) []metav1.Object {
+	out := make([]metav1.Object, 0, len(devices))
+	for i := range devices {
+		out = append(out, &devices[i].ObjectMeta)
+	}
+	return out
+}
+
+// computeETag fingerprints the request scope and the resourceVersions of the returned objects,
+// so unchanged polls are answered without encoding a body.
+func computeETag(scope string, objs []metav1.Object) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(scope))
+	for _, obj := range objs {
+		_, _ = fmt.Fprintf(h, "|%s=%s", obj.GetName(), obj.GetResourceVersion())
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+func positiveInt(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, err
+	}
+	if v <= 0 {
+		return 0, fmt.Errorf("must be positive, got %d", v)
+	}
+	return v, nil
+}
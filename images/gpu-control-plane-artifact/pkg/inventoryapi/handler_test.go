@@ -0,0 +1,259 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/indexer"
+)
+
+const (
+	testToken      = "valid-token"
+	forbiddenToken = "forbidden-token"
+	testReader     = "system:serviceaccount:monitoring:dashboard"
+)
+
+type staticAuthenticator struct{}
+
+func (staticAuthenticator) Authenticate(_ context.Context, token string) (authenticationv1.UserInfo, bool, error) {
+	switch token {
+	case testToken:
+		return authenticationv1.UserInfo{Username: testReader}, true, nil
+	case forbiddenToken:
+		return authenticationv1.UserInfo{Username: "system:serviceaccount:default:default"}, true, nil
+	}
+	return authenticationv1.UserInfo{}, false, nil
+}
+
+type staticAuthorizer struct{}
+
+func (staticAuthorizer) Authorize(_ context.Context, user authenticationv1.UserInfo) (bool, error) {
+	return user.Username == testReader, nil
+}
+
+func newTestHandler(t *testing.T, objs ...client.Object) http.Handler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add gpu scheme: %v", err)
+	}
+	cl := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&v1alpha1.GPUDevice{}, indexer.GPUDeviceNodeField, func(obj client.Object) []string {
+			return []string{obj.(*v1alpha1.GPUDevice).Status.NodeName}
+		}).
+		Build()
+	return NewHandler(testr.New(t), cl, staticAuthenticator{}, staticAuthorizer{}).Routes()
+}
+
+func device(name, node string, state v1alpha1.GPUDeviceState) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: node, State: state},
+	}
+}
+
+func do(t *testing.T, h http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeDevices(t *testing.T, rec *httptest.ResponseRecorder) DeviceList {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var list DeviceList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return list
+}
+
+func TestListDevicesFiltering(t *testing.T) {
+	h := newTestHandler(t,
+		device("a-0", "node-a", v1alpha1.GPUDeviceStateReady),
+		device("a-1", "node-a", v1alpha1.GPUDeviceStateAssigned),
+		device("b-0", "node-b", v1alpha1.GPUDeviceStateReady),
+	)
+
+	list := decodeDevices(t, do(t, h, "/api/v1/devices?node=node-a", nil))
+	if list.Total != 2 || list.Items[0].Name != "a-0" || list.Items[1].Name != "a-1" {
+		t.Fatalf("unexpected node filter result: %+v", list)
+	}
+
+	list = decodeDevices(t, do(t, h, "/api/v1/devices?state=Ready", nil))
+	if list.Total != 2 || list.Items[0].Name != "a-0" || list.Items[1].Name != "b-0" {
+		t.Fatalf("unexpected state filter result: %+v", list)
+	}
+
+	list = decodeDevices(t, do(t, h, "/api/v1/devices?node=node-b&state=Assigned", nil))
+	if list.Total != 0 || len(list.Items) != 0 {
+		t.Fatalf("expected empty result, got %+v", list)
+	}
+}
+
+func TestListDevicesFiltersByPoolScope(t *testing.T) {
+	pooled := func(name, namespace, pool string) *v1alpha1.GPUDevice {
+		dev := device(name, "node-a", v1alpha1.GPUDeviceStateAssigned)
+		dev.Status.PoolRef = &v1alpha1.GPUPoolReference{Name: pool, Namespace: namespace}
+		return dev
+	}
+	h := newTestHandler(t,
+		pooled("a-0", "team-a", "shared"),
+		pooled("a-1", "team-b", "shared"),
+		pooled("a-2", "", "shared"),
+	)
+
+	list := decodeDevices(t, do(t, h, "/api/v1/devices?pool=team-a/shared", nil))
+	if list.Total != 1 || list.Items[0].Name != "a-0" || list.Items[0].Pool != "team-a/shared" {
+		t.Fatalf("expected only the team-a pool, got %+v", list)
+	}
+
+	list = decodeDevices(t, do(t, h, "/api/v1/devices?pool=shared", nil))
+	if list.Total != 1 || list.Items[0].Name != "a-2" || list.Items[0].Pool != "shared" {
+		t.Fatalf("expected only the cluster pool, got %+v", list)
+	}
+}
+
+func TestListDevicesPagination(t *testing.T) {
+	var objs []client.Object
+	for i := 0; i < 5; i++ {
+		objs = append(objs, device(fmt.Sprintf("dev-%d", i), "node-a", v1alpha1.GPUDeviceStateReady))
+	}
+	h := newTestHandler(t, objs...)
+
+	list := decodeDevices(t, do(t, h, "/api/v1/devices?limit=2&page=2", nil))
+	if list.Total != 5 || list.Page != 2 || list.Limit != 2 || len(list.Items) != 2 || list.Items[0].Name != "dev-2" {
+		t.Fatalf("unexpected second page: %+v", list)
+	}
+
+	list = decodeDevices(t, do(t, h, "/api/v1/devices?limit=2&page=4", nil))
+	if list.Total != 5 || len(list.Items) != 0 {
+		t.Fatalf("expected empty page past the end, got %+v", list)
+	}
+
+	list = decodeDevices(t, do(t, h, "/api/v1/devices?limit=10000", nil))
+	if list.Limit != maxPageSize {
+		t.Fatalf("expected limit to be capped at %d, got %d", maxPageSize, list.Limit)
+	}
+
+	if rec := do(t, h, "/api/v1/devices?page=0", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for page=0, got %d", rec.Code)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	h := newTestHandler(t,
+		&v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Spec: v1alpha1.GPUNodeStateSpec{NodeName: "node-a"}},
+		device("a-0", "node-a", v1alpha1.GPUDeviceStateReady),
+	)
+
+	for _, path := range []string{"/api/v1/devices", "/api/v1/nodes/node-a"} {
+		first := do(t, h, path, nil)
+		if first.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected status %d", path, first.Code)
+		}
+		etag := first.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: expected ETag header", path)
+		}
+
+		second := do(t, h, path, http.Header{"If-None-Match": []string{etag}})
+		if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+			t.Fatalf("%s: expected 304 with empty body, got %d %q", path, second.Code, second.Body.String())
+		}
+
+		stale := do(t, h, path, http.Header{"If-None-Match": []string{`"stale"`}})
+		if stale.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200 for stale ETag, got %d", path, stale.Code)
+		}
+	}
+}
+
+func TestGetNode(t *testing.T) {
+	h := newTestHandler(t,
+		&v1alpha1.GPUNodeState{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Spec: v1alpha1.GPUNodeStateSpec{NodeName: "node-a"}},
+		device("a-0", "node-a", v1alpha1.GPUDeviceStateReady),
+		device("b-0", "node-b", v1alpha1.GPUDeviceStateReady),
+	)
+
+	rec := do(t, h, "/api/v1/nodes/node-a", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	var node Node
+	if err := json.Unmarshal(rec.Body.Bytes(), &node); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if node.Name != "node-a" || len(node.Devices) != 1 || node.Devices[0].Name != "a-0" {
+		t.Fatalf("unexpected node: %+v", node)
+	}
+
+	if rec := do(t, h, "/api/v1/nodes/missing", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown node, got %d", rec.Code)
+	}
+}
+
+func TestRequiresBearerToken(t *testing.T) {
+	h := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for rejected token, got %d", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+forbiddenToken)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a caller not allowed to read the inventory, got %d", rec.Code)
+	}
+
+	if rec := do(t, h, "/api/openapi.json", nil); rec.Code != http.StatusOK || !json.Valid(rec.Body.Bytes()) {
+		t.Fatalf("expected valid OpenAPI document, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonerrors "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/errors"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+const maxPreviewBodyBytes = 1 << 20
+
+// PreviewRequest carries proposed ModuleConfig settings in the same shape as spec.settings.
+type PreviewRequest struct {
+	Settings map[string]any `json:"settings"`
+}
+
+// Previewer computes the impact of proposed settings without applying them.
+type Previewer interface {
+	Preview(ctx context.Context, settings map[string]any) (inventory.ImpactReport, error)
+}
+
+// ModuleConfigPreviewer evaluates proposed settings against the running module state.
+type ModuleConfigPreviewer struct {
+	client client.Client
+	store  *moduleconfig.ModuleConfigStore
+	now    func() time.Time
+}
+
+// NewModuleConfigPreviewer constructs a previewer; reads go through c, which is expected to be cache-backed.
+func NewModuleConfigPreviewer(c client.Client, store *moduleconfig.ModuleConfigStore) *ModuleConfigPreviewer {
+	return &ModuleConfigPreviewer{client: c, store: store, now: time.Now}
+}
+
+// Preview implements Previewer.
+func (p *ModuleConfigPreviewer) Preview(ctx context.Context, settings map[string]any) (inventory.ImpactReport, error) {
+	current := moduleconfig.DefaultState()
+	if p.store != nil {
+		current = p.store.Current()
+	}
+	proposed, err := moduleconfig.Parse(moduleconfig.Input{Settings: settings})
+	if err != nil {
+		return inventory.ImpactReport{}, err
+	}
+	// The module hook pins the managed-node label key when rendering the controller config, so the
+	// running key stays authoritative regardless of what the proposal says.
+	proposed.Settings.ManagedNodes.LabelKey = current.Settings.ManagedNodes.LabelKey
+	return inventory.PreviewSettings(ctx, p.client, current, proposed, p.now())
+}
+
+// SetPreviewer enables the ModuleConfig impact preview endpoint.
+func (h *Handler) SetPreviewer(p Previewer) {
+	h.previewer = p
+}
+
+func (h *Handler) previewModuleConfig(w http.ResponseWriter, r *http.Request) {
+	if h.previewer == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var req PreviewRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPreviewBodyBytes)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	report, err := h.previewer.Preview(r.Context(), req.Settings)
+	if err != nil {
+		// Settings the module would reject are the request's fault, not the server's.
+		if errors.Is(err, commonerrors.ErrConfigInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.log.Error(err, "failed to preview ModuleConfig settings")
+		http.Error(w, "failed to preview settings", http.StatusInternalServerError)
+		return
+	}
+	h.writeJSON(w, report)
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/inventory"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+const pinnedLabelKey = "gpu.deckhouse.io/dp-enabled"
+
+func gpuNode(name string, extra map[string]string) *corev1.Node {
+	nodeLabels := map[string]string{
+		"gpu.deckhouse.io/device.00.vendor": "10de",
+		"gpu.deckhouse.io/device.00.device": "20b0",
+		"gpu.deckhouse.io/device.00.class":  "0302",
+	}
+	for k, v := range extra {
+		nodeLabels[k] = v
+	}
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels}}
+}
+
+func inventoriedDevice(node string) *v1alpha1.GPUDevice {
+	return &v1alpha1.GPUDevice{
+		ObjectMeta: metav1.ObjectMeta{Name: node + "-0-10de-20b0"},
+		Status:     v1alpha1.GPUDeviceStatus{NodeName: node, InventoryID: node + "-0-10de-20b0"},
+	}
+}
+
+func newPreviewHandler(t *testing.T, objs ...client.Object) http.Handler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{v1alpha1.AddToScheme, corev1.AddToScheme, nfdv1alpha1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("add scheme: %v", err)
+		}
+	}
+	scheme.AddKnownTypes(nfdv1alpha1.SchemeGroupVersion, &nfdv1alpha1.NodeFeatureList{})
+	cl := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	state := moduleconfig.DefaultState()
+	state.Settings.ManagedNodes.LabelKey = pinnedLabelKey
+	h := NewHandler(testr.New(t), cl, staticAuthenticator{}, staticAuthorizer{})
+	h.SetPreviewer(NewModuleConfigPreviewer(cl, moduleconfig.NewModuleConfigStore(state)))
+	return h.Routes()
+}
+
+func postPreview(t *testing.T, h http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/preview/moduleconfig", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPreviewModuleConfig(t *testing.T) {
+	h := newPreviewHandler(t,
+		gpuNode("node-a", nil),
+		gpuNode("node-b", map[string]string{pinnedLabelKey: "false"}),
+		inventoriedDevice("node-a"),
+		inventoriedDevice("node-b"),
+	)
+
+	rec := postPreview(t, h, `{"settings":{"deviceApproval":{"mode":"Automatic"}}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var report inventory.ImpactReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// node-b stays unmanaged because the running label key is kept, even though the proposal
+	// falls back to the default key.
+	if report.ManagedNodesChanged != 0 || report.AutoAttachChanged != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.Devices) != 1 || report.Devices[0].Name != "node-a-0-10de-20b0" || *report.Devices[0].AutoAttach != (inventory.Change{From: false, To: true}) {
+		t.Fatalf("unexpected device impact: %+v", report.Devices)
+	}
+}
+
+func TestPreviewModuleConfigRejectsInvalidSettings(t *testing.T) {
+	h := newPreviewHandler(t)
+
+	if rec := postPreview(t, h, `{"settings":{"deviceApproval":{"mode":"Sometimes"}}}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid settings, got %d", rec.Code)
+	}
+	if rec := postPreview(t, h, `not json`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed body, got %d", rec.Code)
+	}
+}
+
+func TestPreviewModuleConfigDisabled(t *testing.T) {
+	h := newTestHandler(t)
+
+	if rec := postPreview(t, h, `{}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a previewer, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventoryapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/config"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/controller/moduleconfig"
+)
+
+const (
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Server runs the inventory API on its own listener, separate from metrics and health probes.
+type Server struct {
+	cfg     config.InventoryAPIConfig
+	handler http.Handler
+	log     logr.Logger
+}
+
+// NewServer constructs a Server.
+func NewServer(log logr.Logger, cfg config.InventoryAPIConfig, handler *Handler) *Server {
+	return &Server{cfg: cfg, handler: handler.Routes(), log: log}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica serves reads from its own cache.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.cfg.BindAddress,
+		Handler:           s.handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.log.Info("inventory API started", "addr", s.cfg.BindAddress, "tls", s.cfg.CertFile != "")
+		if s.cfg.CertFile != "" {
+			errCh <- srv.ListenAndServeTLS(s.cfg.CertFile, s.cfg.KeyFile)
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// SetupServer registers the inventory API with the manager. It is a no-op when no bind address is configured.
+func SetupServer(mgr manager.Manager, log logr.Logger, cfg config.InventoryAPIConfig, store *moduleconfig.ModuleConfigStore) error {
+	if cfg.BindAddress == "" {
+		return nil
+	}
+	if _, err := listenaddr.Parse(cfg.BindAddress); err != nil {
+		return fmt.Errorf("inventory API bind address: %w", err)
+	}
+	handler := NewHandler(log, mgr.GetCache(), NewTokenReviewAuthenticator(mgr.GetClient()), NewSubjectAccessReviewAuthorizer(mgr.GetClient()))
+	handler.SetPreviewer(NewModuleConfigPreviewer(mgr.GetClient(), store))
+	return mgr.Add(NewServer(log, cfg, handler))
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Level is the log level shared by every logger built from it. Set takes effect for all of
+// them at once, so the level can follow the module settings without recreating loggers.
+type Level struct {
+	mu     sync.Mutex
+	name   string
+	atomic zap.AtomicLevel
+}
+
+// NewLevel returns a level set to name; an unknown name falls back to Info.
+func NewLevel(name string) *Level {
+	l := &Level{name: "Info", atomic: zap.NewAtomicLevelAt(zapcore.InfoLevel)}
+	_, _ = l.Set(name)
+	return l
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (l *Level) Enabled(level zapcore.Level) bool {
+	return l.atomic.Enabled(level)
+}
+
+// Name returns the current level in the module settings spelling.
+func (l *Level) Name() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.name
+}
+
+// Set switches the level to one of Debug, Info, Warn or Error and returns the previous one.
+func (l *Level) Set(name string) (string, error) {
+	var level zapcore.Level
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		name, level = "Debug", zapcore.DebugLevel
+	case "info":
+		name, level = "Info", zapcore.InfoLevel
+	case "warn":
+		name, level = "Warn", zapcore.WarnLevel
+	case "error":
+		name, level = "Error", zapcore.ErrorLevel
+	default:
+		return l.Name(), fmt.Errorf("unknown log level %q", name)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	previous := l.name
+	l.name = name
+	l.atomic.SetLevel(level)
+	return previous, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestLevelAppliesToExistingLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	level := NewLevel("Info")
+	log := zap.New(zap.WriteTo(&buf), zap.Level(level))
+
+	log.V(1).Info("debug before")
+	log.Info("info before")
+
+	if previous, err := level.Set("Debug"); err != nil || previous != "Info" {
+		t.Fatalf("unexpected Set result: previous=%q err=%v", previous, err)
+	}
+	log.V(1).Info("debug after")
+
+	if _, err := level.Set("Error"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	log.Info("info suppressed")
+	log.Error(nil, "error kept")
+
+	out := buf.String()
+	for _, want := range []string{"info before", "debug after", "error kept"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"debug before", "info suppressed"} {
+		if strings.Contains(out, unwanted) {
+			t.Fatalf("expected %q to be suppressed:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestLevelRejectsUnknownName(t *testing.T) {
+	level := NewLevel("verbose")
+	if level.Name() != "Info" {
+		t.Fatalf("expected unknown initial level to fall back to Info, got %q", level.Name())
+	}
+	if _, err := level.Set("trace"); err == nil {
+		t.Fatalf("expected an error for an unknown level")
+	}
+	if level.Name() != "Info" {
+		t.Fatalf("expected level to stay Info, got %q", level.Name())
+	}
+	if _, err := level.Set(" warn "); err != nil || level.Name() != "Warn" {
+		t.Fatalf("expected case-insensitive names, got %q err=%v", level.Name(), err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alerts renders the alert catalog registered next to the controller metrics as Prometheus rules.
+package alerts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/bootstrap"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/modulestatus"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/ownership"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/utilization"
+)
+
+// GroupName is the rule group the catalog is rendered into.
+const GroupName = "kubernetes.gpu_control_plane.controller_metrics"
+
+// GeneratedHeader opens the rule file rendered into monitoring/prometheus-rules.
+const GeneratedHeader = "# Code generated by `gpuctl alerts export --groups`. DO NOT EDIT.\n"
+
+// healthGroup places the alerts into the same Deckhouse alert group as the hand-written controller alerts.
+const healthGroup = "D8GPUControlPlaneHealth,tier=~tier,prometheus=deckhouse,kubernetes=~kubernetes"
+
+// Catalog registers every controller metric family and returns the alerts defined next to them.
+func Catalog() []metrics.Alert {
+	bootstrap.Register()
+	capacity.Register()
+	inventory.Register()
+	modulestatus.Register()
+	ownership.Register()
+	utilization.Register()
+	return metrics.Alerts()
+}
+
+// Validate checks that every alert is built on a registered metric and only relies on labels the metric has,
+// so renaming a metric without its alert is caught by tests rather than by a silent alert in production.
+func Validate(alerts []metrics.Alert) error {
+	var errs []error
+	for _, alert := range alerts {
+		if alert.Name == "" || alert.Expr == "" || alert.Severity == "" || alert.Summary == "" {
+			errs = append(errs, fmt.Errorf("alert %q: name, expr, severity and summary are required", alert.Name))
+			continue
+		}
+		labels, ok := metrics.Described(alert.Metric)
+		if !ok {
+			errs = append(errs, fmt.Errorf("alert %s: metric %q is not registered", alert.Name, alert.Metric))
+			continue
+		}
+		if !strings.Contains(alert.Expr, alert.Metric) {
+			errs = append(errs, fmt.Errorf("alert %s: expression does not use metric %q", alert.Name, alert.Metric))
+		}
+		for _, label := range alert.Labels {
+			if !slices.Contains(labels, label) {
+				errs = append(errs, fmt.Errorf("alert %s: metric %q has no label %q", alert.Name, alert.Metric, label))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Group is a Prometheus rule group.
+type Group struct {
+	Name  string `yaml:"name"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a Prometheus alerting rule.
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// PrometheusRule is the monitoring.coreos.com/v1 object carrying rule groups.
+type PrometheusRule struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   PrometheusRuleMeta `yaml:"metadata"`
+	Spec       PrometheusRuleSpec `yaml:"spec"`
+}
+
+type PrometheusRuleMeta struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace"`
+	Labels    map[string]string `yaml:"labels"`
+}
+
+type PrometheusRuleSpec struct {
+	Groups []Group `yaml:"groups"`
+}
+
+// Groups converts the alerts into a single rule group following the Deckhouse alert annotations.
+func Groups(alerts []metrics.Alert) []Group {
+	group := Group{Name: GroupName, Rules: make([]Rule, 0, len(alerts))}
+	for _, alert := range alerts {
+		annotations := map[string]string{
+			"plk_protocol_version": "1",
+			"plk_markup_format":    "markdown",
+			"plk_create_group_if_not_exists__d8_gpu_control_plane_health": healthGroup,
+			"plk_grouped_by__d8_gpu_control_plane_health":                 healthGroup,
+			"summary": alert.Summary,
+		}
+		if alert.Description != "" {
+			annotations["description"] = alert.Description
+		}
+		group.Rules = append(group.Rules, Rule{
+			Alert:       alert.Name,
+			Expr:        alert.Expr,
+			For:         promDuration(alert.For),
+			Labels:      map[string]string{"severity_level": alert.Severity, "tier": "cluster"},
+			Annotations: annotations,
+		})
+	}
+	return []Group{group}
+}
+
+// NewPrometheusRule wraps the rule groups into a PrometheusRule object.
+func NewPrometheusRule(name, namespace string, alerts []metrics.Alert) PrometheusRule {
+	return PrometheusRule{
+		APIVersion: "monitoring.coreos.com/v1",
+		Kind:       "PrometheusRule",
+		Metadata: PrometheusRuleMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"prometheus": "main", "component": "rules"},
+		},
+		Spec: PrometheusRuleSpec{Groups: Groups(alerts)},
+	}
+}
+
+// Marshal encodes v as YAML with two-space indentation.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// promDuration formats a duration the way rule files spell it ("15m" rather than "15m0s").
+func promDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return ""
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+)
+
+// rulesFile is the generated rule file rendered by the Helm chart, relative to this package.
+var rulesFile = filepath.Join("..", "..", "..", "..", "..", "monitoring", "prometheus-rules", "gpu-controller-metrics.yaml")
+
+func TestCatalogMatchesRegisteredMetrics(t *testing.T) {
+	catalog := Catalog()
+	if len(catalog) == 0 {
+		t.Fatal("expected a non-empty alert catalog")
+	}
+	if err := Validate(catalog); err != nil {
+		t.Fatalf("alert catalog refers to metrics the controller does not register:\n%v", err)
+	}
+}
+
+func TestValidateReportsDrift(t *testing.T) {
+	Catalog()
+	err := Validate([]metrics.Alert{
+		{Name: "Renamed", Metric: "gpu_inventory_devices", Expr: "gpu_inventory_devices == 0", Severity: "6", Summary: "s"},
+		{Name: "WrongLabel", Metric: inventory.InventoryDevicesTotalMetric, Labels: []string{"pool"}, Expr: "max by (pool) (" + inventory.InventoryDevicesTotalMetric + ")", Severity: "6", Summary: "s"},
+		{Name: "OtherMetric", Metric: inventory.InventoryDevicesTotalMetric, Expr: "up == 0", Severity: "6", Summary: "s"},
+		{Name: "Incomplete", Metric: inventory.InventoryDevicesTotalMetric},
+	})
+	if err == nil {
+		t.Fatal("expected drift to be reported")
+	}
+	for _, want := range []string{
+		`metric "gpu_inventory_devices" is not registered`,
+		`has no label "pool"`,
+		"expression does not use metric",
+		"name, expr, severity and summary are required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestGroupsRendersDeckhouseRule(t *testing.T) {
+	groups := Groups([]metrics.Alert{{
+		Name:     "Example",
+		Metric:   "m",
+		Expr:     "m > 0",
+		For:      90 * time.Second,
+		Severity: "5",
+		Summary:  "summary",
+	}})
+	if len(groups) != 1 || groups[0].Name != GroupName || len(groups[0].Rules) != 1 {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	rule := groups[0].Rules[0]
+	if rule.For != "90s" || rule.Labels["severity_level"] != "5" || rule.Labels["tier"] != "cluster" {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+	if rule.Annotations["plk_grouped_by__d8_gpu_control_plane_health"] != healthGroup || rule.Annotations["summary"] != "summary" {
+		t.Fatalf("unexpected annotations: %+v", rule.Annotations)
+	}
+	if _, ok := rule.Annotations["description"]; ok {
+		t.Fatalf("expected no empty description, got %+v", rule.Annotations)
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	for in, want := range map[time.Duration]string{
+		0:                "",
+		30 * time.Second: "30s",
+		15 * time.Minute: "15m",
+		2 * time.Hour:    "2h",
+		90 * time.Minute: "90m",
+	} {
+		if got := promDuration(in); got != want {
+			t.Errorf("promDuration(%s)=%q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCheckedInRulesAreUpToDate(t *testing.T) {
+	checkedIn, err := os.ReadFile(rulesFile)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("module rule files are not part of this checkout")
+	}
+	if err != nil {
+		t.Fatalf("read %s: %v", rulesFile, err)
+	}
+	rendered, err := Marshal(Groups(Catalog()))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if string(checkedIn) != GeneratedHeader+string(rendered) {
+		t.Fatalf("%s is out of date; run `make alerts`", rulesFile)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alert is a suggested alerting rule on a metric the controller emits. Alerts are registered next to the
+// metric definitions, so renaming a metric and forgetting its alert fails the catalog consistency test.
+type Alert struct {
+	// Name is the alert name, e.g. D8GPUInventoryDeletionsThrottled.
+	Name string
+	// Metric is the metric the expression is built on.
+	Metric string
+	// Labels are the metric labels the expression relies on.
+	Labels []string
+	Expr   string
+	For    time.Duration
+	// Severity is the Deckhouse severity_level, "1" being the most severe.
+	Severity    string
+	Summary     string
+	Description string
+}
+
+var catalog = struct {
+	sync.Mutex
+	metrics map[string][]string
+	alerts  map[string]Alert
+}{
+	metrics: make(map[string][]string),
+	alerts:  make(map[string]Alert),
+}
+
+func recordMetric(metric string, labelNames []string) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	catalog.metrics[metric] = slices.Clone(labelNames)
+}
+
+// Described returns the label names of a metric registered through MustRegisterGauge or MustRegisterCounter.
+func Described(metric string) ([]string, bool) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	labels, ok := catalog.metrics[metric]
+	return slices.Clone(labels), ok
+}
+
+// RegisterAlerts adds alerts to the catalog; an alert registered again under the same name replaces the previous one.
+func RegisterAlerts(alerts ...Alert) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	for _, alert := range alerts {
+		catalog.alerts[alert.Name] = alert
+	}
+}
+
+// Alerts returns the registered alerts ordered by name.
+func Alerts() []Alert {
+	catalog.Lock()
+	defer catalog.Unlock()
+	out := make([]Alert, 0, len(catalog.alerts))
+	for _, alert := range catalog.alerts {
+		out = append(out, alert)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+
+var alerts = []metrics.Alert{
+	{
+		Name:     "D8GPUBootstrapHandlerErrors",
+		Metric:   BootstrapHandlerErrorsTotal,
+		Labels:   []string{"handler"},
+		Expr:     "sum by (handler) (increase(" + BootstrapHandlerErrorsTotal + "[15m])) > 0",
+		Severity: "6",
+		Summary:  "The GPU bootstrap handler {{ $labels.handler }} keeps failing.",
+		Description: "Nodes whose bootstrap fails do not get their GPU workloads validated and stay out of the pools.\n" +
+			"\n" +
+			"The recommended course of action:\n" +
+			"1. Check the controller logs: `kubectl -n d8-gpu-control-plane logs deploy/gpu-control-plane-controller | grep {{ $labels.handler }}`\n",
+	},
+}
@@ -30,6 +30,7 @@ func Register() {
 		metrics.MustRegisterGauge(storage, BootstrapNodePhaseMetric, []string{"node", "phase"}, "Current bootstrap phase per node.")
 		metrics.MustRegisterGauge(storage, BootstrapConditionMetric, []string{"node", "condition"}, "Bootstrap conditions that are true for a node.")
 		metrics.MustRegisterCounter(storage, BootstrapHandlerErrorsTotal, []string{"handler"}, "Number of bootstrap handler failures.")
+		metrics.RegisterAlerts(alerts...)
 	})
 }
 
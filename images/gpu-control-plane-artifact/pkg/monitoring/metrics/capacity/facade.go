@@ -0,0 +1,70 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+func PoolSaturationSet(pool string, ratio float64) {
+	if pool == "" {
+		return
+	}
+
+	groupedStorage().GaugeSet(pool, PoolSaturationRatio, ratio, map[string]string{
+		"pool": pool,
+	})
+}
+
+func PoolSaturationDelete(pool string) {
+	if pool == "" {
+		return
+	}
+
+	groupedStorage().ExpireGroupMetricByName(pool, PoolSaturationRatio)
+}
+
+func PoolResourceNameConflictSet(pool string) {
+	if pool == "" {
+		return
+	}
+
+	groupedStorage().GaugeSet(pool, PoolResourceNameConflict, 1, map[string]string{
+		"pool": pool,
+	})
+}
+
+func PoolResourceNameConflictDelete(pool string) {
+	if pool == "" {
+		return
+	}
+
+	groupedStorage().ExpireGroupMetricByName(pool, PoolResourceNameConflict)
+}
+
+func NamespaceRequestedUnitsSet(namespace, pool string, units int64) {
+	if namespace == "" || pool == "" {
+		return
+	}
+
+	groupedStorage().GaugeSet(namespace+"|"+pool, NamespaceRequestedUnits, float64(units), map[string]string{
+		"namespace": namespace,
+		"pool":      pool,
+	})
+}
+
+func NamespaceRequestedUnitsDelete(namespace, pool string) {
+	if namespace == "" || pool == "" {
+		return
+	}
+
+	groupedStorage().ExpireGroupMetricByName(namespace+"|"+pool, NamespaceRequestedUnits)
+}
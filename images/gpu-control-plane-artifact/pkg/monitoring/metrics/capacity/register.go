@@ -0,0 +1,39 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"sync"
+
+	metricsstorage "github.com/deckhouse/deckhouse/pkg/metrics-storage"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+)
+
+var registerOnce = new(sync.Once)
+
+func Register() {
+	registerOnce.Do(func() {
+		storage := metrics.Registerer()
+		metrics.MustRegisterGauge(storage, PoolSaturationRatio, []string{"pool"}, "Share of the pool capacity consumed by scheduled workloads: requested units divided by the total pool units.")
+		metrics.MustRegisterGauge(storage, PoolResourceNameConflict, []string{"pool"}, "Set to 1 while the pool is not rendered because an older pool advertises the same extended resource name.")
+		metrics.MustRegisterGauge(storage, NamespaceRequestedUnits, []string{"namespace", "pool"}, "Pool units requested by the scheduled, non-terminated pods of a namespace.")
+	})
+}
+
+func groupedStorage() metricsstorage.GroupedStorage {
+	Register()
+	return metrics.GroupedStorage()
+}
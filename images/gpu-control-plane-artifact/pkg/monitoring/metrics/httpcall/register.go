@@ -0,0 +1,37 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcall
+
+import (
+	"sync"
+
+	metricsstorage "github.com/deckhouse/deckhouse/pkg/metrics-storage"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+)
+
+var registerOnce = new(sync.Once)
+
+func Register() {
+	registerOnce.Do(func() {
+		storage := metrics.Registerer()
+		metrics.MustRegisterCounter(storage, CallsTotal, []string{"category", "outcome"}, "Number of outbound HTTP calls made by the controllers by category and outcome; timeout means the call's own deadline fired, canceled that the reconcile ended first.")
+	})
+}
+
+func groupedStorage() metricsstorage.GroupedStorage {
+	Register()
+	return metrics.GroupedStorage()
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inventory
+
+import (
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+)
+
+var alerts = []metrics.Alert{
+	{
+		Name:     "D8GPUInventoryDeletionsThrottled",
+		Metric:   InventoryDeletionsThrottled,
+		Expr:     "sum(increase(" + InventoryDeletionsThrottled + "[15m])) > 0",
+		Severity: "4",
+		Summary:  "The GPU inventory controller is holding back GPUDevice deletions.",
+		Description: "More GPUDevice objects were due for deletion than `inventory.maxDeletionsPerSweep` allows, which usually points to a fleet-wide outage rather than real decommissions.\n" +
+			"\n" +
+			"The recommended course of action:\n" +
+			"1. Find the affected nodes: `kubectl get gpunodestates -o json | jq -r '.items[] | select(.status.conditions[]? | .type == \"DeletionsThrottled\" and .status == \"True\") | .metadata.name'`\n" +
+			"2. Check why the nodes are NotReady or removed before letting the deletions through.\n" +
+			"3. For an intentional decommission, annotate the GPUNodeState objects: `kubectl annotate gpunodestate <node> gpu.deckhouse.io/allow-mass-deletion=true`\n",
+	},
+	{
+		Name:     "D8GPUInventoryNodeLostDevices",
+		Metric:   InventoryDevicesTotalMetric,
+		Labels:   []string{"node"},
+		Expr:     "max by (node) (" + InventoryDevicesTotalMetric + ") == 0 and max by (node) (" + InventoryDevicesTotalMetric + " offset 30m) > 0",
+		For:      15 * time.Minute,
+		Severity: "5",
+		Summary:  "GPUs disappeared from the inventory of node {{ $labels.node }}.",
+		Description: "The node reported GPU devices 30 minutes ago and reports none now, which usually means the driver, NFD or GFD stopped publishing them.\n" +
+			"\n" +
+			"The recommended course of action:\n" +
+			"1. Check the node inventory: `kubectl get gpunodestate {{ $labels.node }} -o yaml`\n" +
+			"2. Check the NodeFeature of the node: `kubectl get nodefeatures -A -l nfd.node.kubernetes.io/node-name={{ $labels.node }} -o yaml`\n",
+	},
+	{
+		Name:     "D8GPUInventoryIncomplete",
+		Metric:   InventoryConditionMetric,
+		Labels:   []string{"node", "condition"},
+		Expr:     "max by (node) (" + InventoryConditionMetric + "{condition=\"InventoryComplete\"}) == 0",
+		For:      30 * time.Minute,
+		Severity: "6",
+		Summary:  "The GPU inventory of node {{ $labels.node }} is incomplete.",
+		Description: "The InventoryComplete condition of the node has been False for 30 minutes: its NodeFeature is missing or lists no GPU devices.\n" +
+			"\n" +
+			"The recommended course of action:\n" +
+			"1. Check the condition reason: `kubectl get gpunodestate {{ $labels.node }} -o jsonpath='{.status.conditions[?(@.type==\"InventoryComplete\")]}'`\n" +
+			"2. Check the NFD worker on the node: `kubectl -n d8-cloud-instance-manager get pods -o wide | grep {{ $labels.node }}`\n",
+	},
+	{
+		Name:     "D8GPUInventoryHandlerErrors",
+		Metric:   InventoryHandlerErrorsTotal,
+		Labels:   []string{"handler"},
+		Expr:     "sum by (handler) (increase(" + InventoryHandlerErrorsTotal + "[15m])) > 0",
+		Severity: "6",
+		Summary:  "The GPU inventory handler {{ $labels.handler }} keeps failing.",
+		Description: "The recommended course of action:\n" +
+			"1. Check the controller logs: `kubectl -n d8-gpu-control-plane logs deploy/gpu-control-plane-controller | grep {{ $labels.handler }}`\n",
+	},
+}
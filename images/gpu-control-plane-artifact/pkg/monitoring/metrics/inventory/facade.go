@@ -14,76 +14,217 @@
 
 package inventory
 
-func InventoryDevicesSet(node string, count int) {
-	if node == "" {
+import (
+	"strconv"
+	"time"
+)
+
+func (m *Metrics) InventoryDevicesSet(node string, count int) {
+	if m == nil || node == "" {
 		return
 	}
 
-	groupedStorage().GaugeSet(node, InventoryDevicesTotalMetric, float64(count), map[string]string{
+	m.storage.GaugeSet(node, InventoryDevicesTotalMetric, float64(count), map[string]string{
 		"node": node,
 	})
 }
 
-func InventoryDevicesDelete(node string) {
-	if node == "" {
+func (m *Metrics) InventoryDevicesDelete(node string) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.storage.ExpireGroupMetricByName(node, InventoryDevicesTotalMetric)
+}
+
+func (m *Metrics) InventoryDevicesUnallocatedSet(node string, count int) {
+	if m == nil || node == "" {
 		return
 	}
 
-	groupedStorage().ExpireGroupMetricByName(node, InventoryDevicesTotalMetric)
+	m.storage.GaugeSet(node, InventoryDevicesUnallocated, float64(count), map[string]string{
+		"node": node,
+	})
 }
 
-func InventoryConditionSet(node, condition string, value bool) {
-	if node == "" || condition == "" {
+func (m *Metrics) InventoryDevicesUnallocatedDelete(node string) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.storage.ExpireGroupMetricByName(node, InventoryDevicesUnallocated)
+}
+
+// InventoryConditionSet reports the condition status; severity comes from inventory.conditionPolicy and is empty
+// without one. The group is expired first, so a changed severity does not leave the old series behind.
+func (m *Metrics) InventoryConditionSet(node, condition, severity string, value bool) {
+	if m == nil || node == "" || condition == "" {
 		return
 	}
 
 	group := node + "|" + condition
-	groupedStorage().GaugeSet(group, InventoryConditionMetric, boolToFloat(value), map[string]string{
+	storage := m.storage
+	storage.ExpireGroupMetricByName(group, InventoryConditionMetric)
+	storage.GaugeSet(group, InventoryConditionMetric, boolToFloat(value), map[string]string{
 		"node":      node,
 		"condition": condition,
+		"severity":  severity,
 	})
 }
 
-func InventoryConditionDelete(node, condition string) {
-	if node == "" || condition == "" {
+func (m *Metrics) InventoryConditionDelete(node, condition string) {
+	if m == nil || node == "" || condition == "" {
 		return
 	}
 
 	group := node + "|" + condition
-	groupedStorage().ExpireGroupMetricByName(group, InventoryConditionMetric)
+	m.storage.ExpireGroupMetricByName(group, InventoryConditionMetric)
 }
 
-func InventoryDeviceStateSet(node, state string, count int) {
-	if node == "" || state == "" {
+func (m *Metrics) InventoryDeviceStateSet(node, state string, count int) {
+	if m == nil || node == "" || state == "" {
 		return
 	}
 
 	group := node + "|" + state
-	groupedStorage().GaugeSet(group, InventoryDeviceStateMetric, float64(count), map[string]string{
+	m.storage.GaugeSet(group, InventoryDeviceStateMetric, float64(count), map[string]string{
 		"node":  node,
 		"state": state,
 	})
 }
 
-func InventoryDeviceStateDelete(node, state string) {
-	if node == "" || state == "" {
+func (m *Metrics) InventoryDeviceStateDelete(node, state string) {
+	if m == nil || node == "" || state == "" {
 		return
 	}
 
 	group := node + "|" + state
-	groupedStorage().ExpireGroupMetricByName(group, InventoryDeviceStateMetric)
+	m.storage.ExpireGroupMetricByName(group, InventoryDeviceStateMetric)
+}
+
+func (m *Metrics) InventoryDeviceWritesSet(node string, count int) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.storage.GaugeSet(node, InventoryDeviceWritesMetric, float64(count), map[string]string{
+		"node": node,
+	})
+}
+
+func (m *Metrics) InventoryDeviceWritesDelete(node string) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.storage.ExpireGroupMetricByName(node, InventoryDeviceWritesMetric)
+}
+
+// InventoryUnmigratedLabelKeySet marks the node as carrying only the previous label key and reports whether the
+// key differs from the one already recorded for it. A nil Metrics keeps no record, so it always reports a change.
+func (m *Metrics) InventoryUnmigratedLabelKeySet(node, labelKey string) bool {
+	if m == nil || node == "" || labelKey == "" {
+		return labelKey != ""
+	}
+
+	m.unmigratedMu.Lock()
+	defer m.unmigratedMu.Unlock()
+	if m.unmigrated[node] == labelKey {
+		return false
+	}
+	m.storage.ExpireGroupMetricByName(node, InventoryUnmigratedLabelKey)
+	m.storage.GaugeSet(node, InventoryUnmigratedLabelKey, 1, map[string]string{
+		"node":      node,
+		"label_key": labelKey,
+	})
+	m.unmigrated[node] = labelKey
+	return true
+}
+
+func (m *Metrics) InventoryUnmigratedLabelKeyDelete(node string) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.unmigratedMu.Lock()
+	defer m.unmigratedMu.Unlock()
+	delete(m.unmigrated, node)
+	m.storage.ExpireGroupMetricByName(node, InventoryUnmigratedLabelKey)
 }
 
-func InventoryHandlerErrorInc(handler string) {
-	if handler == "" {
+func (m *Metrics) InventoryDetectionSchemaSet(node string, version int) {
+	if m == nil || node == "" {
 		return
 	}
 
-	groupedStorage().CounterAdd(handler, InventoryHandlerErrorsTotal, 1, map[string]string{
+	m.storage.GaugeSet(node, InventoryDetectionSchema, float64(version), map[string]string{
+		"node": node,
+	})
+}
+
+func (m *Metrics) InventoryDetectionSchemaDelete(node string) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.storage.ExpireGroupMetricByName(node, InventoryDetectionSchema)
+}
+
+func (m *Metrics) InventoryHandlerErrorInc(handler string) {
+	if m == nil || handler == "" {
+		return
+	}
+
+	m.storage.CounterAdd(handler, InventoryHandlerErrorsTotal, 1, map[string]string{
 		"handler": handler,
 	})
 }
 
+func (m *Metrics) InventoryDeletionsThrottledAdd(node string, deferred int) {
+	if m == nil || node == "" || deferred <= 0 {
+		return
+	}
+
+	m.storage.CounterAdd(node+"|deletions-throttled", InventoryDeletionsThrottled, float64(deferred), map[string]string{
+		"node": node,
+	})
+}
+
+func (m *Metrics) InventoryReconcileSuppressedInc(node string) {
+	if m == nil || node == "" {
+		return
+	}
+
+	m.storage.CounterAdd(node+"|reconcile-suppressed", InventorySuppressedTotal, 1, map[string]string{
+		"node": node,
+	})
+}
+
+// nodeFeatureAPIGroup holds the NodeFeature API metrics, which are replaced as a whole on every check.
+const nodeFeatureAPIGroup = "nodefeature-api"
+
+func (m *Metrics) NodeFeatureAPISet(served []string, compiled string, supported bool) {
+	if m == nil {
+		return
+	}
+	storage := m.storage
+	storage.ExpireGroupMetrics(nodeFeatureAPIGroup)
+	for _, version := range served {
+		storage.GaugeSet(nodeFeatureAPIGroup, NodeFeatureAPIServed, 1, map[string]string{
+			"version":  version,
+			"compiled": strconv.FormatBool(version == compiled),
+		})
+	}
+	storage.GaugeSet(nodeFeatureAPIGroup, NodeFeatureAPISupported, boolToFloat(supported), nil)
+}
+
+func (m *Metrics) ModuleConfigPropagationObserve(latency time.Duration) {
+	if m == nil {
+		return
+	}
+	m.storage.HistogramObserve("moduleconfig-propagation", ModuleConfigPropagation, latency.Seconds(), nil, moduleConfigPropagationBuckets)
+}
+
 func boolToFloat(value bool) float64 {
 	if value {
 		return 1
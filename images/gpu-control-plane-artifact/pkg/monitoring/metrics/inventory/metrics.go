@@ -19,4 +19,16 @@ const (
 	InventoryConditionMetric    = "gpu_inventory_condition"
 	InventoryDeviceStateMetric  = "gpu_inventory_devices_state"
 	InventoryHandlerErrorsTotal = "gpu_inventory_handler_errors_total"
+	InventoryDeviceWritesMetric = "gpu_inventory_device_writes"
+	InventoryUnmigratedLabelKey = "gpu_inventory_node_label_key_unmigrated"
+	InventoryDetectionSchema    = "gpu_inventory_detection_schema_version"
+	InventoryDeletionsThrottled = "gpu_inventory_device_deletions_throttled_total"
+	InventoryDevicesUnallocated = "gpu_node_devices_unallocated"
+	InventorySuppressedTotal    = "gpu_inventory_reconcile_suppressed_total"
+	NodeFeatureAPIServed        = "gpu_inventory_nodefeature_api_served"
+	NodeFeatureAPISupported     = "gpu_inventory_nodefeature_api_supported"
+	ModuleConfigPropagation     = "gpu_inventory_moduleconfig_propagation_seconds"
 )
+
+// moduleConfigPropagationBuckets cover an immediate requeue up to a spread window of several minutes.
+var moduleConfigPropagationBuckets = []float64{1, 5, 15, 30, 60, 120, 180, 300, 600, 1200}
@@ -18,23 +18,63 @@ import (
 	"sync"
 
 	metricsstorage "github.com/deckhouse/deckhouse/pkg/metrics-storage"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
 )
 
-var registerOnce = new(sync.Once)
+var (
+	defaultMetrics *Metrics
+	defaultOnce    = new(sync.Once)
+)
 
-func Register() {
-	registerOnce.Do(func() {
-		storage := metrics.Registerer()
-		metrics.MustRegisterGauge(storage, InventoryDevicesTotalMetric, []string{"node"}, "Number of GPU devices discovered on a node.")
-		metrics.MustRegisterGauge(storage, InventoryConditionMetric, []string{"node", "condition"}, "Inventory condition status (0 or 1).")
-		metrics.MustRegisterGauge(storage, InventoryDeviceStateMetric, []string{"node", "state"}, "Number of GPU devices on a node grouped by state.")
-		metrics.MustRegisterCounter(storage, InventoryHandlerErrorsTotal, []string{"handler"}, "Number of errors returned by inventory handlers.")
+// Metrics records the inventory metrics into one storage. A nil Metrics records nothing.
+type Metrics struct {
+	storage metricsstorage.GroupedStorage
+
+	// unmigrated mirrors the label_key of the InventoryUnmigratedLabelKey series per node.
+	unmigratedMu sync.Mutex
+	unmigrated   map[string]string
+}
+
+// New registers the inventory metrics on a storage of their own, collected through registerer. Instances built on
+// separate registerers never observe each other's values, which keeps parallel tests independent.
+func New(registerer prometheus.Registerer) (*Metrics, error) {
+	storage, err := metrics.NewStorage(registerer)
+	if err != nil {
+		return nil, err
+	}
+	register(storage)
+	return &Metrics{storage: storage.Grouped(), unmigrated: map[string]string{}}, nil
+}
+
+// Default returns the inventory metrics of the controller, served from the controller-runtime registry.
+func Default() *Metrics {
+	defaultOnce.Do(func() {
+		register(metrics.Registerer())
+		metrics.RegisterAlerts(alerts...)
+		defaultMetrics = &Metrics{storage: metrics.GroupedStorage(), unmigrated: map[string]string{}}
 	})
+	return defaultMetrics
+}
+
+// Register adds the default inventory metrics and their alerts to the controller-runtime registry.
+func Register() {
+	Default()
 }
 
-func groupedStorage() metricsstorage.GroupedStorage {
-	Register()
-	return metrics.GroupedStorage()
+func register(storage metricsstorage.Registerer) {
+	metrics.MustRegisterGauge(storage, InventoryDevicesTotalMetric, []string{"node"}, "Number of GPU devices discovered on a node.")
+	metrics.MustRegisterGauge(storage, InventoryConditionMetric, []string{"node", "condition", "severity"}, "Inventory condition status (0 or 1); severity is set by inventory.conditionPolicy.")
+	metrics.MustRegisterGauge(storage, InventoryDeviceStateMetric, []string{"node", "state"}, "Number of GPU devices on a node grouped by state.")
+	metrics.MustRegisterGauge(storage, InventoryDeviceWritesMetric, []string{"node"}, "Number of GPUDevice API writes issued by the last inventory reconcile of a node.")
+	metrics.MustRegisterGauge(storage, InventoryUnmigratedLabelKey, []string{"node", "label_key"}, "Set to 1 for nodes that carry only the previous managed-node label key; sum it to count unmigrated nodes.")
+	metrics.MustRegisterGauge(storage, InventoryDetectionSchema, []string{"node"}, "gfd-extender detection API schema version consumed by the last inventory scrape of a node.")
+	metrics.MustRegisterGauge(storage, InventoryDevicesUnallocated, []string{"node"}, "Number of healthy GPU devices on a node that are not assigned to any pool.")
+	metrics.MustRegisterGauge(storage, NodeFeatureAPIServed, []string{"version", "compiled"}, "Set to 1 for every NodeFeature API version served by the cluster; compiled marks the version the controller is built against.")
+	metrics.MustRegisterGauge(storage, NodeFeatureAPISupported, nil, "Whether the cluster serves the NodeFeature API version the controller is built against (0 or 1).")
+	metrics.MustRegisterCounter(storage, InventoryHandlerErrorsTotal, []string{"handler"}, "Number of errors returned by inventory handlers.")
+	metrics.MustRegisterCounter(storage, InventorySuppressedTotal, []string{"node"}, "Number of inventory reconciles skipped because the node is suppressed through the admin API.")
+	metrics.MustRegisterCounter(storage, InventoryDeletionsThrottled, []string{"node"}, "Number of GPUDevice deletions deferred by inventory.maxDeletionsPerSweep.")
+	metrics.MustRegisterHistogram(storage, ModuleConfigPropagation, nil, moduleConfigPropagationBuckets, "Time from a ModuleConfig settings change to the successful inventory reconcile of the last node it affected.")
 }
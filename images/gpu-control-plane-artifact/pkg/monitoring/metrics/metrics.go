@@ -20,7 +20,10 @@ import (
 
 	metricsstorage "github.com/deckhouse/deckhouse/pkg/metrics-storage"
 	msoptions "github.com/deckhouse/deckhouse/pkg/metrics-storage/options"
+	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/version"
 )
 
 var (
@@ -28,19 +31,30 @@ var (
 	registerOnce  = new(sync.Once)
 )
 
-// Register adds metrics storage to the controller-runtime metrics registry.
+// Register adds metrics storage and the build info gauge to the controller-runtime metrics registry.
 func Register() {
 	registerOnce.Do(func() {
-		ms := metricsstorage.NewMetricStorage(metricsstorage.WithNewRegistry())
-
-		if err := crmetrics.Registry.Register(ms.Collector()); err != nil {
-			panic(fmt.Errorf("register metrics storage: %w", err))
+		ms, err := NewStorage(crmetrics.Registry)
+		if err != nil {
+			panic(err)
+		}
+		if err := crmetrics.Registry.Register(version.NewBuildInfoCollector()); err != nil {
+			panic(fmt.Errorf("register build info: %w", err))
 		}
 
 		metricStorage = ms
 	})
 }
 
+// NewStorage returns a metrics storage backed by its own registry, which is collected through registerer.
+func NewStorage(registerer prometheus.Registerer) (metricsstorage.Storage, error) {
+	ms := metricsstorage.NewMetricStorage(metricsstorage.WithNewRegistry())
+	if err := registerer.Register(ms.Collector()); err != nil {
+		return nil, fmt.Errorf("register metrics storage: %w", err)
+	}
+	return ms, nil
+}
+
 func Registerer() metricsstorage.Registerer {
 	Register()
 	return metricStorage
@@ -56,6 +70,7 @@ func MustRegisterGauge(storage metricsstorage.Registerer, metric string, labelNa
 	if err != nil {
 		panic(fmt.Errorf("register gauge %q: %w", metric, err))
 	}
+	recordMetric(metric, labelNames)
 }
 
 func MustRegisterCounter(storage metricsstorage.Registerer, metric string, labelNames []string, help string) {
@@ -63,4 +78,13 @@ func MustRegisterCounter(storage metricsstorage.Registerer, metric string, label
 	if err != nil {
 		panic(fmt.Errorf("register counter %q: %w", metric, err))
 	}
+	recordMetric(metric, labelNames)
+}
+
+func MustRegisterHistogram(storage metricsstorage.Registerer, metric string, labelNames []string, buckets []float64, help string) {
+	_, err := storage.RegisterHistogram(metric, labelNames, buckets, msoptions.WithHelp(help))
+	if err != nil {
+		panic(fmt.Errorf("register histogram %q: %w", metric, err))
+	}
+	recordMetric(metric, labelNames)
 }
@@ -17,72 +17,113 @@ package metrics_test
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	promdto "github.com/prometheus/client_model/go"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	bootmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/bootstrap"
+	capmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/capacity"
+	httpmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/httpcall"
 	invmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/inventory"
+	statusmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/modulestatus"
+	healthmetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/nodehealth"
+	sizemetrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/objsize"
+	schemametrics "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics/schema"
 )
 
 func TestInventoryMetricsFacadeSetAndDelete(t *testing.T) {
 	node := "node-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
 	cond := "cond-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
 	state := "state-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
+	metrics, gatherer := newInventoryMetrics(t)
 
-	invmetrics.InventoryDevicesSet("", 10)
-	if _, ok := findMetric(t, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": ""}); ok {
+	metrics.InventoryDevicesSet("", 10)
+	if _, ok := findMetricIn(t, gatherer, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": ""}); ok {
 		t.Fatalf("expected empty node to be ignored")
 	}
 
-	invmetrics.InventoryDevicesSet(node, 2)
-	if v, ok := gaugeValue(t, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": node}); !ok || v != 2 {
+	metrics.InventoryDevicesSet(node, 2)
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": node}); !ok || v != 2 {
 		t.Fatalf("expected inventory devices gauge=2, got %f (present=%t)", v, ok)
 	}
-	invmetrics.InventoryDevicesDelete(node)
-	if _, ok := findMetric(t, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": node}); ok {
+	metrics.InventoryDevicesDelete(node)
+	if _, ok := findMetricIn(t, gatherer, invmetrics.InventoryDevicesTotalMetric, map[string]string{"node": node}); ok {
 		t.Fatalf("expected inventory devices gauge cleared")
 	}
 
-	invmetrics.InventoryConditionSet(node, cond, true)
-	if v, ok := gaugeValue(t, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond}); !ok || v != 1 {
+	metrics.InventoryConditionSet(node, cond, "", true)
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond}); !ok || v != 1 {
 		t.Fatalf("expected inventory condition gauge=1, got %f (present=%t)", v, ok)
 	}
-	invmetrics.InventoryConditionSet(node, cond, false)
-	if v, ok := gaugeValue(t, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond}); !ok || v != 0 {
+	metrics.InventoryConditionSet(node, cond, "", false)
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond}); !ok || v != 0 {
 		t.Fatalf("expected inventory condition gauge=0, got %f (present=%t)", v, ok)
 	}
-	invmetrics.InventoryConditionDelete(node, cond)
-	if _, ok := findMetric(t, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond}); ok {
+	metrics.InventoryConditionSet(node, cond, "Critical", false)
+	if _, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond, "severity": "Critical"}); !ok {
+		t.Fatalf("expected inventory condition gauge with severity")
+	}
+	if _, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond, "severity": ""}); ok {
+		t.Fatalf("expected the series without severity to be replaced")
+	}
+	metrics.InventoryConditionDelete(node, cond)
+	if _, ok := findMetricIn(t, gatherer, invmetrics.InventoryConditionMetric, map[string]string{"node": node, "condition": cond}); ok {
 		t.Fatalf("expected inventory condition gauge cleared")
 	}
 
-	invmetrics.InventoryDeviceStateSet(node, state, 3)
-	if v, ok := gaugeValue(t, invmetrics.InventoryDeviceStateMetric, map[string]string{"node": node, "state": state}); !ok || v != 3 {
+	metrics.InventoryDeviceStateSet(node, state, 3)
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryDeviceStateMetric, map[string]string{"node": node, "state": state}); !ok || v != 3 {
 		t.Fatalf("expected inventory device state gauge=3, got %f (present=%t)", v, ok)
 	}
-	invmetrics.InventoryDeviceStateDelete(node, state)
-	if _, ok := findMetric(t, invmetrics.InventoryDeviceStateMetric, map[string]string{"node": node, "state": state}); ok {
+	metrics.InventoryDeviceStateDelete(node, state)
+	if _, ok := findMetricIn(t, gatherer, invmetrics.InventoryDeviceStateMetric, map[string]string{"node": node, "state": state}); ok {
 		t.Fatalf("expected inventory device state gauge cleared")
 	}
 }
 
+func TestCapacityMetricsFacadeSetAndDelete(t *testing.T) {
+	pool := "ns/pool-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
+	node := "node-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
+
+	capmetrics.PoolSaturationSet(pool, 0.75)
+	if v, ok := gaugeValue(t, capmetrics.PoolSaturationRatio, map[string]string{"pool": pool}); !ok || v != 0.75 {
+		t.Fatalf("expected pool saturation gauge=0.75, got %f (present=%t)", v, ok)
+	}
+	capmetrics.PoolSaturationDelete(pool)
+	if _, ok := findMetric(t, capmetrics.PoolSaturationRatio, map[string]string{"pool": pool}); ok {
+		t.Fatalf("expected pool saturation gauge cleared")
+	}
+
+	metrics, gatherer := newInventoryMetrics(t)
+	metrics.InventoryDevicesUnallocatedSet(node, 3)
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.InventoryDevicesUnallocated, map[string]string{"node": node}); !ok || v != 3 {
+		t.Fatalf("expected unallocated devices gauge=3, got %f (present=%t)", v, ok)
+	}
+	metrics.InventoryDevicesUnallocatedDelete(node)
+	if _, ok := findMetricIn(t, gatherer, invmetrics.InventoryDevicesUnallocated, map[string]string{"node": node}); ok {
+		t.Fatalf("expected unallocated devices gauge cleared")
+	}
+}
+
 func TestHandlerErrorCounters(t *testing.T) {
 	handlerInventory := "handler-inv-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
 	handlerBootstrap := "handler-boot-" + strings.ToLower(strings.ReplaceAll(t.Name(), "/", "-"))
+	metrics, gatherer := newInventoryMetrics(t)
 
-	beforeInv := counterValueOrZero(t, invmetrics.InventoryHandlerErrorsTotal, map[string]string{"handler": handlerInventory})
-	beforeBoot := counterValueOrZero(t, bootmetrics.BootstrapHandlerErrorsTotal, map[string]string{"handler": handlerBootstrap})
+	beforeInv := counterValueOrZero(t, gatherer, invmetrics.InventoryHandlerErrorsTotal, map[string]string{"handler": handlerInventory})
+	beforeBoot := counterValueOrZero(t, crmetrics.Registry, bootmetrics.BootstrapHandlerErrorsTotal, map[string]string{"handler": handlerBootstrap})
 
-	invmetrics.InventoryHandlerErrorInc(handlerInventory)
-	invmetrics.InventoryHandlerErrorInc(handlerInventory)
-	gotInv := counterValueOrZero(t, invmetrics.InventoryHandlerErrorsTotal, map[string]string{"handler": handlerInventory})
+	metrics.InventoryHandlerErrorInc(handlerInventory)
+	metrics.InventoryHandlerErrorInc(handlerInventory)
+	gotInv := counterValueOrZero(t, gatherer, invmetrics.InventoryHandlerErrorsTotal, map[string]string{"handler": handlerInventory})
 	if gotInv-beforeInv != 2 {
 		t.Fatalf("expected inventory handler errors counter to increase by 2, got delta=%f", gotInv-beforeInv)
 	}
 
 	bootmetrics.BootstrapHandlerErrorInc(handlerBootstrap)
-	gotBoot := counterValueOrZero(t, bootmetrics.BootstrapHandlerErrorsTotal, map[string]string{"handler": handlerBootstrap})
+	gotBoot := counterValueOrZero(t, crmetrics.Registry, bootmetrics.BootstrapHandlerErrorsTotal, map[string]string{"handler": handlerBootstrap})
 	if gotBoot-beforeBoot != 1 {
 		t.Fatalf("expected bootstrap handler errors counter to increase by 1, got delta=%f", gotBoot-beforeBoot)
 	}
@@ -113,16 +154,25 @@ func TestBootstrapMetricsFacadeSetAndDelete(t *testing.T) {
 }
 
 func TestFacadeFunctionsIgnoreEmptyInputs(t *testing.T) {
-	invmetrics.InventoryDevicesDelete("")
-	invmetrics.InventoryConditionSet("", "cond", true)
-	invmetrics.InventoryConditionSet("node", "", true)
-	invmetrics.InventoryConditionDelete("", "cond")
-	invmetrics.InventoryConditionDelete("node", "")
-	invmetrics.InventoryDeviceStateSet("", "state", 1)
-	invmetrics.InventoryDeviceStateSet("node", "", 1)
-	invmetrics.InventoryDeviceStateDelete("", "state")
-	invmetrics.InventoryDeviceStateDelete("node", "")
-	invmetrics.InventoryHandlerErrorInc("")
+	metrics, _ := newInventoryMetrics(t)
+	metrics.InventoryDevicesDelete("")
+	metrics.InventoryConditionSet("", "cond", "", true)
+	metrics.InventoryConditionSet("node", "", "", true)
+	metrics.InventoryConditionDelete("", "cond")
+	metrics.InventoryConditionDelete("node", "")
+	metrics.InventoryDeviceStateSet("", "state", 1)
+	metrics.InventoryDeviceStateSet("node", "", 1)
+	metrics.InventoryDeviceStateDelete("", "state")
+	metrics.InventoryDeviceStateDelete("node", "")
+	metrics.InventoryHandlerErrorInc("")
+	metrics.InventoryDevicesUnallocatedSet("", 1)
+	metrics.InventoryDevicesUnallocatedDelete("")
+	var disabled *invmetrics.Metrics
+	disabled.InventoryDevicesSet("node", 1)
+	disabled.NodeFeatureAPISet([]string{"v1alpha1"}, "v1alpha1", true)
+	disabled.ModuleConfigPropagationObserve(time.Second)
+	capmetrics.PoolSaturationSet("", 1)
+	capmetrics.PoolSaturationDelete("")
 
 	bootmetrics.BootstrapPhaseSet("", "phase")
 	bootmetrics.BootstrapPhaseSet("node", "")
@@ -135,6 +185,149 @@ func TestFacadeFunctionsIgnoreEmptyInputs(t *testing.T) {
 	bootmetrics.BootstrapHandlerErrorInc("")
 }
 
+func TestModuleStatusMetricsFacade(t *testing.T) {
+	sweep := time.Unix(1700000000, 0)
+	statusmetrics.ModuleStatusSet(3, 5, 2, 1, sweep)
+	for name, want := range map[string]float64{
+		statusmetrics.ModuleManagedNodesMetric:   3,
+		statusmetrics.ModuleManagedDevicesMetric: 5,
+		statusmetrics.ModulePoolsMetric:          2,
+		statusmetrics.ModuleFailingNodesMetric:   1,
+		statusmetrics.ModuleLastSweepMetric:      1700000000,
+	} {
+		if v, ok := gaugeValue(t, name, nil); !ok || v != want {
+			t.Fatalf("expected %s=%f, got %f (present=%t)", name, want, v, ok)
+		}
+	}
+	statusmetrics.ModuleStatusSet(3, 5, 2, 1, time.Time{})
+	if _, ok := findMetric(t, statusmetrics.ModuleLastSweepMetric, nil); ok {
+		t.Fatalf("expected last sweep gauge cleared without a completed sweep")
+	}
+
+	statusmetrics.ModuleStatusInfoSet("v1", "controller-0", "hash-a")
+	statusmetrics.ModuleStatusInfoSet("v1", "controller-1", "hash-a")
+	if _, ok := findMetric(t, statusmetrics.ModuleStatusInfoMetric, map[string]string{"leader": "controller-0"}); ok {
+		t.Fatalf("expected previous leader info series to be replaced")
+	}
+	if v, ok := gaugeValue(t, statusmetrics.ModuleStatusInfoMetric, map[string]string{"leader": "controller-1", "settings_hash": "hash-a"}); !ok || v != 1 {
+		t.Fatalf("expected info gauge for the new leader, got %f (present=%t)", v, ok)
+	}
+}
+
+func TestNodeFeatureAPIMetricsFacade(t *testing.T) {
+	metrics, gatherer := newInventoryMetrics(t)
+	metrics.NodeFeatureAPISet([]string{"v1alpha1", "v1alpha2"}, "v1alpha1", true)
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.NodeFeatureAPIServed, map[string]string{"version": "v1alpha1", "compiled": "true"}); !ok || v != 1 {
+		t.Fatalf("expected compiled version to be reported, got %f (present=%t)", v, ok)
+	}
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.NodeFeatureAPISupported, nil); !ok || v != 1 {
+		t.Fatalf("expected supported gauge=1, got %f (present=%t)", v, ok)
+	}
+
+	metrics.NodeFeatureAPISet([]string{"v1alpha2"}, "v1alpha1", false)
+	if _, ok := findMetricIn(t, gatherer, invmetrics.NodeFeatureAPIServed, map[string]string{"version": "v1alpha1"}); ok {
+		t.Fatalf("expected version that is no longer served to be cleared")
+	}
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.NodeFeatureAPIServed, map[string]string{"version": "v1alpha2", "compiled": "false"}); !ok || v != 1 {
+		t.Fatalf("expected served version gauge, got %f (present=%t)", v, ok)
+	}
+	if v, ok := gaugeValueIn(t, gatherer, invmetrics.NodeFeatureAPISupported, nil); !ok || v != 0 {
+		t.Fatalf("expected supported gauge=0, got %f (present=%t)", v, ok)
+	}
+}
+
+func TestObjectSizeMetricsFacade(t *testing.T) {
+	truncated := map[string]string{"kind": "GPUDevice", "field": "history"}
+	rejected := map[string]string{"kind": "GPUDevice"}
+	beforeTruncated := counterValueOrZero(t, crmetrics.Registry, sizemetrics.StatusTruncationsTotal, truncated)
+	beforeRejected := counterValueOrZero(t, crmetrics.Registry, sizemetrics.StatusWriteRejectionsTotal, rejected)
+
+	sizemetrics.StatusTruncatedInc("GPUDevice", "history")
+	sizemetrics.StatusTruncatedInc("GPUDevice", "")
+	sizemetrics.StatusWriteRejectedInc("GPUDevice")
+	sizemetrics.StatusWriteRejectedInc("")
+
+	if got := counterValueOrZero(t, crmetrics.Registry, sizemetrics.StatusTruncationsTotal, truncated); got-beforeTruncated != 1 {
+		t.Fatalf("expected truncations counter to increase by 1, got delta=%f", got-beforeTruncated)
+	}
+	if got := counterValueOrZero(t, crmetrics.Registry, sizemetrics.StatusWriteRejectionsTotal, rejected); got-beforeRejected != 1 {
+		t.Fatalf("expected rejections counter to increase by 1, got delta=%f", got-beforeRejected)
+	}
+}
+
+func TestNewerSchemaMetricsFacade(t *testing.T) {
+	labels := map[string]string{"kind": "GPUDevice", "marker": schemametrics.MarkerState}
+	before := counterValueOrZero(t, crmetrics.Registry, schemametrics.NewerSchemaObjectsTotal, labels)
+
+	schemametrics.NewerSchemaObservedInc("GPUDevice", schemametrics.MarkerState)
+	schemametrics.NewerSchemaObservedInc("GPUDevice", "")
+	schemametrics.NewerSchemaObservedInc("", schemametrics.MarkerState)
+
+	if got := counterValueOrZero(t, crmetrics.Registry, schemametrics.NewerSchemaObjectsTotal, labels); got-before != 1 {
+		t.Fatalf("expected newer schema counter to increase by 1, got delta=%f", got-before)
+	}
+}
+
+func TestHTTPCallMetricsFacade(t *testing.T) {
+	timeout := map[string]string{"category": "detection", "outcome": "timeout"}
+	canceled := map[string]string{"category": "detection", "outcome": "canceled"}
+	beforeTimeout := counterValueOrZero(t, crmetrics.Registry, httpmetrics.CallsTotal, timeout)
+	beforeCanceled := counterValueOrZero(t, crmetrics.Registry, httpmetrics.CallsTotal, canceled)
+
+	httpmetrics.CallInc("detection", "timeout")
+	httpmetrics.CallInc("detection", "timeout")
+	httpmetrics.CallInc("detection", "canceled")
+	httpmetrics.CallInc("", "timeout")
+	httpmetrics.CallInc("detection", "")
+
+	if got := counterValueOrZero(t, crmetrics.Registry, httpmetrics.CallsTotal, timeout); got-beforeTimeout != 2 {
+		t.Fatalf("expected timeout counter to increase by 2, got delta=%f", got-beforeTimeout)
+	}
+	if got := counterValueOrZero(t, crmetrics.Registry, httpmetrics.CallsTotal, canceled); got-beforeCanceled != 1 {
+		t.Fatalf("expected canceled counter to increase by 1, got delta=%f", got-beforeCanceled)
+	}
+}
+
+func TestNodeHealthMetricsFacade(t *testing.T) {
+	nodeA := "node-a-" + strings.ToLower(t.Name())
+	nodeB := "node-b-" + strings.ToLower(t.Name())
+	count := func(class string) float64 {
+		t.Helper()
+		v, ok := gaugeValue(t, healthmetrics.NodesByHealth, map[string]string{"class": class})
+		if !ok {
+			t.Fatalf("expected %s count to be reported", class)
+		}
+		return v
+	}
+
+	healthmetrics.NodeHealthSet("", "Healthy")
+	healthmetrics.NodeHealthSet(nodeA, "Healthy")
+	healthmetrics.NodeHealthSet(nodeB, "Healthy")
+	if v, ok := gaugeValue(t, healthmetrics.NodeHealth, map[string]string{"node": nodeA, "class": "Healthy"}); !ok || v != 1 {
+		t.Fatalf("expected node health gauge=1, got %f (present=%t)", v, ok)
+	}
+	if got := count("Healthy"); got != 2 {
+		t.Fatalf("expected 2 healthy nodes, got %f", got)
+	}
+
+	healthmetrics.NodeHealthSet(nodeA, "Degraded")
+	if _, ok := findMetric(t, healthmetrics.NodeHealth, map[string]string{"node": nodeA, "class": "Healthy"}); ok {
+		t.Fatalf("expected the previous class of the node to be cleared")
+	}
+	if got, degraded := count("Healthy"), count("Degraded"); got != 1 || degraded != 1 {
+		t.Fatalf("expected 1 healthy and 1 degraded node, got %f and %f", got, degraded)
+	}
+
+	healthmetrics.NodeHealthDelete(nodeA)
+	healthmetrics.NodeHealthDelete(nodeB)
+	if _, ok := findMetric(t, healthmetrics.NodeHealth, map[string]string{"node": nodeA}); ok {
+		t.Fatalf("expected node health gauge cleared")
+	}
+	if got, degraded := count("Healthy"), count("Degraded"); got != 0 || degraded != 0 {
+		t.Fatalf("expected counts to drop to 0, got %f and %f", got, degraded)
+	}
+}
+
 func labelsMatch(metric *promdto.Metric, expected map[string]string) bool {
 	for name, want := range expected {
 		found := false
@@ -154,10 +347,27 @@ func labelsMatch(metric *promdto.Metric, expected map[string]string) bool {
 	return true
 }
 
+// newInventoryMetrics returns inventory metrics on a private registry, so the test observes only its own values.
+func newInventoryMetrics(t *testing.T) (*invmetrics.Metrics, prometheus.Gatherer) {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	metrics, err := invmetrics.New(registry)
+	if err != nil {
+		t.Fatalf("register inventory metrics: %v", err)
+	}
+	return metrics, registry
+}
+
 func findMetric(t *testing.T, name string, labels map[string]string) (*promdto.Metric, bool) {
 	t.Helper()
+	return findMetricIn(t, crmetrics.Registry, name, labels)
+}
+
+func findMetricIn(t *testing.T, gatherer prometheus.Gatherer, name string, labels map[string]string) (*promdto.Metric, bool) {
+	t.Helper()
 
-	families, err := crmetrics.Registry.Gather()
+	families, err := gatherer.Gather()
 	if err != nil {
 		t.Fatalf("gather metrics: %v", err)
 	}
@@ -177,20 +387,20 @@ func findMetric(t *testing.T, name string, labels map[string]string) (*promdto.M
 	return nil, false
 }
 
-func counterValue(t *testing.T, name string, labels map[string]string) (float64, bool) {
+func counterValue(t *testing.T, gatherer prometheus.Gatherer, name string, labels map[string]string) (float64, bool) {
 	t.Helper()
 
-	metric, ok := findMetric(t, name, labels)
+	metric, ok := findMetricIn(t, gatherer, name, labels)
 	if !ok || metric.Counter == nil {
 		return 0, false
 	}
 	return metric.Counter.GetValue(), true
 }
 
-func counterValueOrZero(t *testing.T, name string, labels map[string]string) float64 {
+func counterValueOrZero(t *testing.T, gatherer prometheus.Gatherer, name string, labels map[string]string) float64 {
 	t.Helper()
 
-	v, ok := counterValue(t, name, labels)
+	v, ok := counterValue(t, gatherer, name, labels)
 	if !ok {
 		return 0
 	}
@@ -199,8 +409,13 @@ func counterValueOrZero(t *testing.T, name string, labels map[string]string) flo
 
 func gaugeValue(t *testing.T, name string, labels map[string]string) (float64, bool) {
 	t.Helper()
+	return gaugeValueIn(t, crmetrics.Registry, name, labels)
+}
+
+func gaugeValueIn(t *testing.T, gatherer prometheus.Gatherer, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
 
-	metric, ok := findMetric(t, name, labels)
+	metric, ok := findMetricIn(t, gatherer, name, labels)
 	if !ok || metric.Gauge == nil {
 		return 0, false
 	}
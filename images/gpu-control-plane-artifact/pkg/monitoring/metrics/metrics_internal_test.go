@@ -93,4 +93,13 @@ func TestMustRegisterHelpersPanicOnErrors(t *testing.T) {
 		}()
 		MustRegisterCounter(reg, "metric", nil, "help")
 	})
+
+	t.Run("histogram", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic")
+			}
+		}()
+		MustRegisterHistogram(reg, "metric", nil, []float64{1}, "help")
+	})
 }
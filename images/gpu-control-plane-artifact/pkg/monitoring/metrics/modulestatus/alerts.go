@@ -0,0 +1,35 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+import (
+	"time"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+)
+
+var alerts = []metrics.Alert{
+	{
+		Name:     "D8GPUControlPlaneFailingNodes",
+		Metric:   ModuleFailingNodesMetric,
+		Expr:     "max(" + ModuleFailingNodesMetric + ") > 0",
+		For:      30 * time.Minute,
+		Severity: "6",
+		Summary:  "{{ $value }} GPU nodes have a failing condition.",
+		Description: "The recommended course of action:\n" +
+			"1. Check the module status summary: `kubectl -n d8-gpu-control-plane get configmap gpu-control-plane-status -o jsonpath='{.data.status\\.json}'`\n" +
+			"2. Inspect the conditions of the GPUNodeState objects: `kubectl get gpunodestates -o yaml`\n",
+	},
+}
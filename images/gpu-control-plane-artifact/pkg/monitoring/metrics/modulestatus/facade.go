@@ -0,0 +1,46 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+import "time"
+
+const (
+	countsGroup = "module-status"
+	infoGroup   = "module-status-info"
+)
+
+func ModuleStatusSet(nodes, devices, pools, failing int, lastSweep time.Time) {
+	storage := groupedStorage()
+	storage.GaugeSet(countsGroup, ModuleManagedNodesMetric, float64(nodes), nil)
+	storage.GaugeSet(countsGroup, ModuleManagedDevicesMetric, float64(devices), nil)
+	storage.GaugeSet(countsGroup, ModulePoolsMetric, float64(pools), nil)
+	storage.GaugeSet(countsGroup, ModuleFailingNodesMetric, float64(failing), nil)
+	if lastSweep.IsZero() {
+		storage.ExpireGroupMetricByName(countsGroup, ModuleLastSweepMetric)
+		return
+	}
+	storage.GaugeSet(countsGroup, ModuleLastSweepMetric, float64(lastSweep.Unix()), nil)
+}
+
+// ModuleStatusInfoSet replaces the info series so a changed leader or settings hash never leaves a stale one behind.
+func ModuleStatusInfoSet(version, leader, settingsHash string) {
+	storage := groupedStorage()
+	storage.ExpireGroupMetricByName(infoGroup, ModuleStatusInfoMetric)
+	storage.GaugeSet(infoGroup, ModuleStatusInfoMetric, 1, map[string]string{
+		"version":       version,
+		"leader":        leader,
+		"settings_hash": settingsHash,
+	})
+}
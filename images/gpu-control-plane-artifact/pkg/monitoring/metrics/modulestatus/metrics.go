@@ -0,0 +1,24 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+const (
+	ModuleManagedNodesMetric   = "gpu_control_plane_managed_nodes"
+	ModuleManagedDevicesMetric = "gpu_control_plane_managed_devices"
+	ModulePoolsMetric          = "gpu_control_plane_pools"
+	ModuleFailingNodesMetric   = "gpu_control_plane_failing_nodes"
+	ModuleLastSweepMetric      = "gpu_control_plane_last_sweep_timestamp_seconds"
+	ModuleStatusInfoMetric     = "gpu_control_plane_status_info"
+)
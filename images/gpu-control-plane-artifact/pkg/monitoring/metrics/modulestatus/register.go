@@ -0,0 +1,43 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modulestatus
+
+import (
+	"sync"
+
+	metricsstorage "github.com/deckhouse/deckhouse/pkg/metrics-storage"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+)
+
+var registerOnce = new(sync.Once)
+
+func Register() {
+	registerOnce.Do(func() {
+		storage := metrics.Registerer()
+		metrics.MustRegisterGauge(storage, ModuleManagedNodesMetric, nil, "Number of GPUNodeStates managed by the module.")
+		metrics.MustRegisterGauge(storage, ModuleManagedDevicesMetric, nil, "Number of GPUDevices marked as managed.")
+		metrics.MustRegisterGauge(storage, ModulePoolsMetric, nil, "Number of GPUPools and ClusterGPUPools.")
+		metrics.MustRegisterGauge(storage, ModuleFailingNodesMetric, nil, "Number of GPU nodes with at least one failing condition.")
+		metrics.MustRegisterGauge(storage, ModuleLastSweepMetric, nil, "Unix time by which every managed node was reconciled at least once by the current leader.")
+		metrics.MustRegisterGauge(storage, ModuleStatusInfoMetric, []string{"version", "leader", "settings_hash"}, "Module status summary labels; the value is always 1.")
+		metrics.RegisterAlerts(alerts...)
+	})
+}
+
+func groupedStorage() metricsstorage.GroupedStorage {
+	Register()
+	return metrics.GroupedStorage()
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodehealth
+
+import "sync"
+
+const countsGroup = "nodehealth"
+
+var (
+	mu sync.Mutex
+	// classes is the last class reported per node; gpu_nodes_by_health is derived from it.
+	classes = map[string]string{}
+	// known keeps every class seen so far, so a class whose last node left reports 0 instead of vanishing.
+	known = map[string]struct{}{}
+)
+
+func NodeHealthSet(node, class string) {
+	if node == "" || class == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	storage := groupedStorage()
+	storage.ExpireGroupMetricByName(nodeGroup(node), NodeHealth)
+	storage.GaugeSet(nodeGroup(node), NodeHealth, 1, map[string]string{
+		"node":  node,
+		"class": class,
+	})
+	classes[node] = class
+	known[class] = struct{}{}
+	publishCounts()
+}
+
+func NodeHealthDelete(node string) {
+	if node == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	groupedStorage().ExpireGroupMetricByName(nodeGroup(node), NodeHealth)
+	if _, ok := classes[node]; !ok {
+		return
+	}
+	delete(classes, node)
+	publishCounts()
+}
+
+func publishCounts() {
+	counts := make(map[string]int, len(known))
+	for class := range known {
+		counts[class] = 0
+	}
+	for _, class := range classes {
+		counts[class]++
+	}
+
+	storage := groupedStorage()
+	for class, count := range counts {
+		storage.GaugeSet(countsGroup, NodesByHealth, float64(count), map[string]string{"class": class})
+	}
+}
+
+func nodeGroup(node string) string {
+	return countsGroup + "/" + node
+}
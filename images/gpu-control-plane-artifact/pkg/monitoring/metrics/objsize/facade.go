@@ -12,24 +12,25 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package state
+package objsize
 
-import (
-	"strings"
+func StatusTruncatedInc(kind, field string) {
+	if kind == "" || field == "" {
+		return
+	}
 
-	nvidiacatalog "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/hardware/nvidia"
-)
+	groupedStorage().CounterAdd(kind+"|"+field, StatusTruncationsTotal, 1, map[string]string{
+		"kind":  kind,
+		"field": field,
+	})
+}
 
-func enrichDevicesFromCatalog(devices []deviceSnapshot) {
-	for i := range devices {
-		if devices[i].Product != "" {
-			continue
-		}
-		if vendor := strings.ToLower(devices[i].Vendor); vendor == "10de" {
-			key := vendor + ":" + strings.ToLower(devices[i].Device)
-			if name, ok := nvidiacatalog.DeviceNames[key]; ok {
-				devices[i].Product = name
-			}
-		}
+func StatusWriteRejectedInc(kind string) {
+	if kind == "" {
+		return
 	}
+
+	groupedStorage().CounterAdd(kind+"|rejected", StatusWriteRejectionsTotal, 1, map[string]string{
+		"kind": kind,
+	})
 }
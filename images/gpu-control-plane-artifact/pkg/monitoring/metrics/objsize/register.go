@@ -0,0 +1,38 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objsize
+
+import (
+	"sync"
+
+	metricsstorage "github.com/deckhouse/deckhouse/pkg/metrics-storage"
+
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+)
+
+var registerOnce = new(sync.Once)
+
+func Register() {
+	registerOnce.Do(func() {
+		storage := metrics.Registerer()
+		metrics.MustRegisterCounter(storage, StatusTruncationsTotal, []string{"kind", "field"}, "Number of status writes in which a field was cut to its size cap.")
+		metrics.MustRegisterCounter(storage, StatusWriteRejectionsTotal, []string{"kind"}, "Number of status writes refused because the object exceeded the size limit after truncation.")
+	})
+}
+
+func groupedStorage() metricsstorage.GroupedStorage {
+	Register()
+	return metrics.GroupedStorage()
+}
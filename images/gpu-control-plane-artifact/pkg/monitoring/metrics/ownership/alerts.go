@@ -0,0 +1,32 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownership
+
+import "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/monitoring/metrics"
+
+var alerts = []metrics.Alert{
+	{
+		Name:     "D8GPUOwnershipConflicts",
+		Metric:   OwnershipConflictsTotal,
+		Labels:   []string{"claimed_by"},
+		Expr:     "sum by (claimed_by) (increase(" + OwnershipConflictsTotal + "[15m])) > 0",
+		Severity: "5",
+		Summary:  "GPU objects are claimed by another control-plane installation ({{ $labels.claimed_by }}).",
+		Description: "Two live installations of the module manage the same cluster objects; this one skips its writes to them.\n" +
+			"\n" +
+			"The recommended course of action:\n" +
+			"1. Find the installation named in the `claimed_by` label and remove it or scope it to other nodes.\n",
+	},
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+const (
+	NewerSchemaObjectsTotal = "gpu_newer_schema_objects_total"
+
+	// MarkerSchemaVersion is an annotation naming a status schema newer than the controller understands.
+	MarkerSchemaVersion = "schemaVersion"
+	// MarkerState is a GPUDevice state the controller does not know.
+	MarkerState = "state"
+)
@@ -0,0 +1,41 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utilization
+
+func PoolUtilizationSet(pool, window string, average, p95 float64) {
+	if pool == "" || window == "" {
+		return
+	}
+
+	group := pool + "|" + window
+	labels := map[string]string{
+		"pool":   pool,
+		"window": window,
+	}
+	storage := groupedStorage()
+	storage.GaugeSet(group, PoolUtilizationRatio, average, labels)
+	storage.GaugeSet(group, PoolUtilizationP95Ratio, p95, labels)
+}
+
+func PoolUtilizationDelete(pool, window string) {
+	if pool == "" || window == "" {
+		return
+	}
+
+	group := pool + "|" + window
+	storage := groupedStorage()
+	storage.ExpireGroupMetricByName(group, PoolUtilizationRatio)
+	storage.ExpireGroupMetricByName(group, PoolUtilizationP95Ratio)
+}
@@ -23,6 +23,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 	commonpod "github.com/aleksandr-podmoskovniy/gpu-control-plane/controller/pkg/common/pod"
 )
 
@@ -30,6 +31,8 @@ const (
 	moduleLabelKey   = "module"
 	moduleLabelValue = "gpu-control-plane"
 	nodeNameField    = "spec.nodeName"
+
+	driverValidationContainer = "driver-validation"
 )
 
 // Validator encapsulates bootstrap validation health checks for GPU nodes.
@@ -58,6 +61,9 @@ type Config struct {
 	GFDApp             string
 	DCGMApp            string
 	DCGMExporterApp    string
+	// DriverInstallType is the configured driver source; only a preinstalled driver is ready
+	// before the validator as a whole.
+	DriverInstallType v1alpha1.GPUPoolDriverInstallType
 }
 
 func NewValidator(cl client.Client, cfg Config) Validator {
@@ -84,11 +90,13 @@ func (v *workloadValidator) Status(ctx context.Context, nodeName string) (Result
 	}
 
 	validatorReady := hasReadyBootstrapValidator(pods.Items, v.cfg.ValidatorApp)
+	driverValidated := hasValidatedBootstrapDriver(pods.Items, v.cfg.ValidatorApp)
 	gfdReady := hasReadyPod(pods.Items, v.cfg.GFDApp)
 	dcgmReady := hasReadyPod(pods.Items, v.cfg.DCGMApp)
 	exporterReady := hasReadyPod(pods.Items, v.cfg.DCGMExporterApp)
 
-	result.DriverReady = validatorReady
+	preinstalled := v.cfg.DriverInstallType == v1alpha1.GPUPoolDriverInstallPreinstalled
+	result.DriverReady = validatorReady || (preinstalled && driverValidated)
 	result.ToolkitReady = validatorReady
 	result.GFDReady = gfdReady
 	result.DCGMReady = dcgmReady
@@ -132,6 +140,27 @@ func hasReadyBootstrapValidator(pods []corev1.Pod, app string) bool {
 	return false
 }
 
+// hasValidatedBootstrapDriver reports whether the bootstrap validator already passed its
+// driver-validation step. Preinstalled host drivers have no toolkit pod to wait for, so for them the
+// driver is considered ready as soon as that init container succeeds.
+func hasValidatedBootstrapDriver(pods []corev1.Pod, app string) bool {
+	for i := range pods {
+		p := &pods[i]
+		if p.Labels["app"] != app || strings.TrimSpace(p.Labels["pool"]) != "" {
+			continue
+		}
+		for _, status := range p.Status.InitContainerStatuses {
+			if status.Name != driverValidationContainer {
+				continue
+			}
+			if term := status.State.Terminated; term != nil && term.ExitCode == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (v *workloadValidator) listPods(ctx context.Context, nodeName string) (*corev1.PodList, error) {
 	pods := &corev1.PodList{}
 	opts := []client.ListOption{
@@ -26,6 +26,8 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/aleksandr-podmoskovniy/gpu-control-plane/api/gpu/v1alpha1"
 )
 
 func TestValidatorStatusUsesNodeScopedPods(t *testing.T) {
@@ -235,6 +237,53 @@ func (c *failingClient) List(ctx context.Context, list client.ObjectList, opts .
 	return c.Client.List(ctx, list, opts...)
 }
 
+func TestValidatorStatusDriverReadyAfterDriverValidation(t *testing.T) {
+	scheme := clientgoscheme.Scheme
+	cfg := applyDefaults(Config{})
+
+	validator := newReadyPod("validator-node1", "node-1", moduleLabelValue, cfg.ValidatorApp, corev1.ConditionFalse)
+	validator.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  driverValidationContainer,
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+	}}
+	poolValidator := newReadyPodWithLabels("validator-pool-a", "node-2", moduleLabelValue, cfg.ValidatorApp, corev1.ConditionFalse, map[string]string{"pool": "pool-a"})
+	poolValidator.Status.InitContainerStatuses = validator.Status.InitContainerStatuses
+
+	cl := clientfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&corev1.Pod{}, nodeNameField, indexPodByNodeName).
+		WithObjects(validator, poolValidator).
+		Build()
+
+	status, err := NewValidator(cl, Config{}).Status(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.DriverReady {
+		t.Fatalf("expected an operator-managed driver to wait for the validator, got %+v", status)
+	}
+
+	preinstalled := Config{DriverInstallType: v1alpha1.GPUPoolDriverInstallPreinstalled}
+	status, err = NewValidator(cl, preinstalled).Status(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !status.DriverReady {
+		t.Fatalf("expected driver ready once driver-validation succeeded, got %+v", status)
+	}
+	if status.ToolkitReady || status.Ready {
+		t.Fatalf("expected toolkit and overall readiness to wait for the validator, got %+v", status)
+	}
+
+	status, err = NewValidator(cl, preinstalled).Status(context.Background(), "node-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.DriverReady {
+		t.Fatalf("expected pool-scoped validator to be ignored, got %+v", status)
+	}
+}
+
 func newReadyPod(name, node, module, app string, status corev1.ConditionStatus) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,43 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package version exposes build information injected at link time.
+package version
+
+import "runtime"
+
+// SchemaVersion is the status schema the running controllers understand. Bump it whenever the status
+// structure of the objects they write changes incompatibly, so older controllers stop rewriting them.
+const SchemaVersion = 1
+
+// Populated via -ldflags "-X .../pkg/version.version=... -X .../pkg/version.gitCommit=...".
+var (
+	version   = "dev"
+	gitCommit = "unknown"
+)
+
+// Version returns the module version the binary was built from.
+func Version() string {
+	return version
+}
+
+// GitCommit returns the commit the binary was built from.
+func GitCommit() string {
+	return gitCommit
+}
+
+// GoVersion returns the Go toolchain version the binary was built with.
+func GoVersion() string {
+	return runtime.Version()
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBuildInfoCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewBuildInfoCollector())
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	if len(families) != 1 || families[0].GetName() != BuildInfoMetric {
+		t.Fatalf("unexpected metric families: %v", families)
+	}
+	labels := map[string]string{}
+	for _, pair := range families[0].GetMetric()[0].GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	if labels["version"] != "dev" || labels["git_commit"] != "unknown" || labels["go_version"] != runtime.Version() {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+	if got := testutil.ToFloat64(NewBuildInfoCollector()); got != 1 {
+		t.Fatalf("expected value 1, got %v", got)
+	}
+}
@@ -270,6 +270,38 @@ func buildControllerConfig(cfg map[string]any) map[string]any {
 	if scheduling, ok := cfg["scheduling"]; ok {
 		moduleSection["scheduling"] = scheduling
 	}
+	if handlers, ok := cfg["handlers"]; ok {
+		moduleSection["handlers"] = handlers
+	}
+	if nodeLabeling, ok := cfg["nodeLabeling"]; ok {
+		moduleSection["nodeLabeling"] = nodeLabeling
+	}
+	if paused, ok := cfg["paused"].(bool); ok && paused {
+		moduleSection["paused"] = true
+	}
+	if inventoryRaw, ok := cfg["inventory"].(map[string]any); ok {
+		inventory := make(map[string]any)
+		for _, key := range []string{"deviceNameTemplate", "staleNodeThreshold", "staleDeviceRetention", "maxDeletionsPerSweep", "requeueSpreadWindow"} {
+			if value, ok := inventoryRaw[key].(string); ok && strings.TrimSpace(value) != "" {
+				inventory[key] = value
+			}
+		}
+		if prefixes, ok := inventoryRaw["attributePassthroughPrefixes"].([]any); ok && len(prefixes) > 0 {
+			inventory["attributePassthroughPrefixes"] = prefixes
+		}
+		if rules, ok := inventoryRaw["compatibilityRules"].([]any); ok && len(rules) > 0 {
+			inventory["compatibilityRules"] = rules
+		}
+		if include, ok := inventoryRaw["includeDisplayDevices"].(bool); ok && include {
+			inventory["includeDisplayDevices"] = true
+		}
+		if len(inventory) > 0 {
+			moduleSection["inventory"] = inventory
+		}
+	}
+	if templates, ok := cfg["poolTemplates"].([]any); ok && len(templates) > 0 {
+		moduleSection["poolTemplates"] = templates
+	}
 	if len(moduleSection) > 0 {
 		result["module"] = moduleSection
 	}
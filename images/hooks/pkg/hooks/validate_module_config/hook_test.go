@@ -606,6 +606,141 @@ func TestBuildControllerConfigModuleSectionOnly(t *testing.T) {
 	}
 }
 
+func TestBuildControllerConfigPassesHandlers(t *testing.T) {
+	handlers := map[string]any{"device-state": map[string]any{"enabled": false}}
+
+	result := buildControllerConfig(map[string]any{"handlers": handlers})
+	module, ok := result["module"].(map[string]any)
+	if !ok || module["handlers"] == nil {
+		t.Fatalf("module section missing handlers: %#v", result)
+	}
+}
+
+func TestBuildControllerConfigPassesDeviceNameTemplate(t *testing.T) {
+	result := buildControllerConfig(map[string]any{
+		"inventory": map[string]any{"resyncPeriod": "30s", "deviceNameTemplate": "{node}-{uuid8}"},
+	})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	inventory, ok := module["inventory"].(map[string]any)
+	if !ok || inventory["deviceNameTemplate"] != "{node}-{uuid8}" {
+		t.Fatalf("module section missing deviceNameTemplate: %#v", result)
+	}
+}
+
+func TestBuildControllerConfigPassesStaleNodeSettings(t *testing.T) {
+	result := buildControllerConfig(map[string]any{
+		"inventory": map[string]any{"staleNodeThreshold": "12h", "staleDeviceRetention": "168h", "maxDeletionsPerSweep": "5%"},
+	})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	inventory, ok := module["inventory"].(map[string]any)
+	if !ok || inventory["staleNodeThreshold"] != "12h" || inventory["staleDeviceRetention"] != "168h" || inventory["maxDeletionsPerSweep"] != "5%" {
+		t.Fatalf("module section missing stale node settings: %#v", result)
+	}
+	if _, ok := inventory["deviceNameTemplate"]; ok {
+		t.Fatalf("unexpected deviceNameTemplate: %#v", inventory)
+	}
+}
+
+func TestBuildControllerConfigPassesAttributePassthroughPrefixes(t *testing.T) {
+	result := buildControllerConfig(map[string]any{
+		"inventory": map[string]any{"attributePassthroughPrefixes": []any{"acme.com/"}},
+	})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	inventory, ok := module["inventory"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing inventory: %#v", result)
+	}
+	prefixes, ok := inventory["attributePassthroughPrefixes"].([]any)
+	if !ok || len(prefixes) != 1 || prefixes[0] != "acme.com/" {
+		t.Fatalf("module section missing attributePassthroughPrefixes: %#v", inventory)
+	}
+
+	if result := buildControllerConfig(map[string]any{"inventory": map[string]any{"attributePassthroughPrefixes": []any{}}}); result != nil {
+		t.Fatalf("expected no controller config for an empty prefix list, got %#v", result)
+	}
+}
+
+func TestBuildControllerConfigPassesCompatibilityRules(t *testing.T) {
+	rule := map[string]any{"name": "old-kernel", "kernelRange": "<5.4", "effect": "Warn"}
+	result := buildControllerConfig(map[string]any{"inventory": map[string]any{"compatibilityRules": []any{rule}}})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	inventory, ok := module["inventory"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing inventory: %#v", result)
+	}
+	if rules, ok := inventory["compatibilityRules"].([]any); !ok || len(rules) != 1 {
+		t.Fatalf("module section missing compatibilityRules: %#v", inventory)
+	}
+}
+
+func TestBuildControllerConfigPassesIncludeDisplayDevices(t *testing.T) {
+	result := buildControllerConfig(map[string]any{"inventory": map[string]any{"includeDisplayDevices": true}})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	inventory, ok := module["inventory"].(map[string]any)
+	if !ok || inventory["includeDisplayDevices"] != true {
+		t.Fatalf("module section missing includeDisplayDevices: %#v", result)
+	}
+
+	if result := buildControllerConfig(map[string]any{"inventory": map[string]any{"includeDisplayDevices": false}}); result != nil {
+		t.Fatalf("expected no controller config when display devices are excluded, got %#v", result)
+	}
+}
+
+func TestBuildControllerConfigPassesPoolTemplates(t *testing.T) {
+	templates := []any{map[string]any{"name": "l4", "poolName": "l4", "poolSpec": map[string]any{"resource": map[string]any{"unit": "Card"}}}}
+	result := buildControllerConfig(map[string]any{"poolTemplates": templates})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	if got, ok := module["poolTemplates"].([]any); !ok || len(got) != 1 {
+		t.Fatalf("module section missing poolTemplates: %#v", result)
+	}
+
+	if result := buildControllerConfig(map[string]any{"poolTemplates": []any{}}); result != nil {
+		t.Fatalf("expected no controller config without pool templates, got %#v", result)
+	}
+}
+
+func TestBuildControllerConfigPassesPaused(t *testing.T) {
+	result := buildControllerConfig(map[string]any{"paused": true})
+	module, ok := result["module"].(map[string]any)
+	if !ok || module["paused"] != true {
+		t.Fatalf("module section missing paused: %#v", result)
+	}
+
+	if result := buildControllerConfig(map[string]any{"paused": false}); result != nil {
+		t.Fatalf("expected no controller config when not paused, got %#v", result)
+	}
+}
+
+func TestBuildControllerConfigPassesNodeLabeling(t *testing.T) {
+	result := buildControllerConfig(map[string]any{"nodeLabeling": map[string]any{"enabled": true}})
+	module, ok := result["module"].(map[string]any)
+	if !ok {
+		t.Fatalf("module section missing: %#v", result)
+	}
+	nodeLabeling, ok := module["nodeLabeling"].(map[string]any)
+	if !ok || nodeLabeling["enabled"] != true {
+		t.Fatalf("module section missing nodeLabeling: %#v", result)
+	}
+}
+
 func TestGlobalHTTPSDefaultsNilInput(t *testing.T) {
 	got := globalHTTPSDefaults(nil)
 	if got.Mode != "" || got.CertManagerIssuer != "" || got.CustomSecret != "" {
@@ -17,10 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"fmt"
 	log "log/slog"
 	"net/http"
 	"os"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/images/kube-api-rewriter/pkg/gpu"
 	logutil "github.com/aleksandr-podmoskovniy/gpu-control-plane/images/kube-api-rewriter/pkg/log"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/images/kube-api-rewriter/pkg/monitoring/healthz"
@@ -54,7 +57,7 @@ import (
 
 const (
 	loopbackAddr              = "127.0.0.1"
-	anyAddr                   = "0.0.0.0"
+	anyAddr                   = "" // every address of both IP families
 	defaultAPIClientProxyPort = "23915"
 	defaultWebhookProxyPort   = "24192"
 )
@@ -207,6 +210,17 @@ func main() {
 		httpServers = append(httpServers, pprofSrv)
 	}
 
+	// Reject malformed listen addresses before starting anything, so the container fails with a clear reason.
+	for _, srv := range httpServers {
+		addr, err := listenaddr.Parse(srv.ListenAddr)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: invalid listen address", srv.InstanceDesc), logutil.SlogErr(err))
+			exitFunc(1)
+			return
+		}
+		srv.ListenAddr = addr
+	}
+
 	// Start all registered servers and block the main process until at least one server stops.
 	group := server.NewRunnableGroup()
 	for i := range httpServers {
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"net"
 	"os"
 	"path/filepath"
 	"sync/atomic"
@@ -116,12 +117,22 @@ func TestMainStartsServersAndExits(t *testing.T) {
 	}
 	t.Setenv("RULES_PATH", rulesPath)
 
-	t.Setenv("CLIENT_PROXY_PORT", "invalid")
+	// Occupy a port so every proxy listener fails to bind.
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = busy.Close() })
+	_, busyPort, _ := net.SplitHostPort(busy.Addr().String())
+
+	t.Setenv("CLIENT_PROXY_PORT", busyPort)
 	t.Setenv("WEBHOOK_ADDRESS", "https://127.0.0.1:9443")
-	t.Setenv("WEBHOOK_PROXY_PORT", "invalid")
+	t.Setenv("WEBHOOK_PROXY_ADDRESS", "127.0.0.1")
+	t.Setenv("WEBHOOK_PROXY_PORT", busyPort)
+	t.Setenv(MonitoringBindAddress, "127.0.0.1:"+busyPort)
 
 	// Enable pprof branch.
-	t.Setenv("PPROF_BIND_ADDRESS", "127.0.0.1:invalid")
+	t.Setenv("PPROF_BIND_ADDRESS", "127.0.0.1:"+busyPort)
 
 	var code atomic.Int32
 	origExit := exitFunc
@@ -135,6 +146,27 @@ func TestMainStartsServersAndExits(t *testing.T) {
 	}
 }
 
+func TestMainInvalidListenAddressExits(t *testing.T) {
+	metrics.Registry = prometheus.NewRegistry()
+
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+	t.Setenv("WEBHOOK_ADDRESS", "")
+	t.Setenv("CLIENT_PROXY_ADDRESS", "::1")
+	t.Setenv(MonitoringBindAddress, "::1:9090")
+
+	var code atomic.Int32
+	code.Store(-1)
+	origExit := exitFunc
+	exitFunc = func(c int) { code.Store(int32(c)) }
+	t.Cleanup(func() { exitFunc = origExit })
+
+	main()
+
+	if code.Load() != 1 {
+		t.Fatalf("expected exit code 1 for an unbracketed IPv6 address, got %d", code.Load())
+	}
+}
+
 func writeTempKubeconfig(t *testing.T) string {
 	t.Helper()
 
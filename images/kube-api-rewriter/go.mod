@@ -3,6 +3,7 @@ module github.com/aleksandr-podmoskovniy/gpu-control-plane/images/kube-api-rewri
 go 1.24.6
 
 require (
+	github.com/aleksandr-podmoskovniy/gpu-control-plane/api v0.0.0
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/josephburnett/jd v1.9.2
 	github.com/kr/text v0.2.0
@@ -61,6 +62,7 @@ require (
 replace google.golang.org/protobuf => google.golang.org/protobuf v1.33.0
 
 replace (
+	github.com/aleksandr-podmoskovniy/gpu-control-plane/api => ../../api
 	golang.org/x/net => golang.org/x/net v0.40.0
 	golang.org/x/oauth2 => golang.org/x/oauth2 v0.27.0
 	k8s.io/api => k8s.io/api v0.30.11
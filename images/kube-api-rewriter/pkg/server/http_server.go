@@ -26,6 +26,8 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/aleksandr-podmoskovniy/gpu-control-plane/api/listenaddr"
+
 	logutil "github.com/aleksandr-podmoskovniy/gpu-control-plane/images/kube-api-rewriter/pkg/log"
 	"github.com/aleksandr-podmoskovniy/gpu-control-plane/images/kube-api-rewriter/pkg/tls/certmanager"
 )
@@ -155,7 +157,7 @@ func (s *HTTPServer) Stop() {
 	}
 }
 
-// ConstructListenAddr return ip:port with defaults.
+// ConstructListenAddr return host:port with defaults. IPv6 hosts are bracketed.
 func ConstructListenAddr(addr, port, defaultAddr, defaultPort string) string {
 	if addr == "" {
 		addr = defaultAddr
@@ -163,5 +165,5 @@ func ConstructListenAddr(addr, port, defaultAddr, defaultPort string) string {
 	if port == "" {
 		port = defaultPort
 	}
-	return addr + ":" + port
+	return listenaddr.Join(addr, port)
 }
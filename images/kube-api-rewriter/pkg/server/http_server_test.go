@@ -160,4 +160,14 @@ func TestConstructListenAddr(t *testing.T) {
 	if addr != "0.0.0.0:9090" {
 		t.Fatalf("unexpected addr override: %s", addr)
 	}
+
+	addr = ConstructListenAddr("::1", "", "127.0.0.1", "8080")
+	if addr != "[::1]:8080" {
+		t.Fatalf("expected bracketed IPv6 addr: %s", addr)
+	}
+
+	addr = ConstructListenAddr("", "", "", "8080")
+	if addr != ":8080" {
+		t.Fatalf("expected addr on all families: %s", addr)
+	}
 }
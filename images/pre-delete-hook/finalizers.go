@@ -0,0 +1,84 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// releaseFinalizers strips the finalizers listed on the resource from every object it targets. They belong to
+// the controllers scaled down before the deletion phase, so nothing else would remove them and the objects would
+// stay in Terminating, blocking CRD removal.
+func (p *PreDeleteHook) releaseFinalizers(ctx context.Context, client dynamic.ResourceInterface, res Resource) error {
+	if len(res.Finalizers) == 0 {
+		return nil
+	}
+	if res.Name != "" {
+		return releaseObjectFinalizers(ctx, client, res.Name, res.Finalizers)
+	}
+
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: res.Selector})
+	if err != nil {
+		return fmt.Errorf("list objects: %w", err)
+	}
+	for _, item := range list.Items {
+		// A namespaced resource listed across namespaces is updated in the namespace of each object.
+		itemClient := client
+		if namespace := item.GetNamespace(); namespace != res.Namespace {
+			itemClient = p.dynamicClient.Resource(res.GVR).Namespace(namespace)
+		}
+		if err := releaseObjectFinalizers(ctx, itemClient, item.GetName(), res.Finalizers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func releaseObjectFinalizers(ctx context.Context, client dynamic.ResourceInterface, name string, finalizers []string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current := obj.GetFinalizers()
+		kept := slices.DeleteFunc(slices.Clone(current), func(finalizer string) bool {
+			return slices.Contains(finalizers, finalizer)
+		})
+		if len(kept) == len(current) {
+			return nil
+		}
+		obj.SetFinalizers(kept)
+		if _, err := client.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		slog.Info("Finalizers released", slog.String("name", name), slog.Any("finalizers", finalizers))
+		return nil
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("release finalizers of %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const inUseProtection = "gpu.deckhouse.io/in-use-protection"
+
+func newDevice(name string, finalizers ...string) *unstructured.Unstructured {
+	device := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gpu.deckhouse.io/v1alpha1",
+		"kind":       "GPUDevice",
+		"metadata":   map[string]any{"name": name},
+	}}
+	device.SetFinalizers(finalizers)
+	return device
+}
+
+// The controller that owns in-use-protection is scaled down first, so the hook has to release the finalizer of
+// assigned devices itself before deleting them, or they would stay in Terminating and block CRD removal.
+func TestRunReleasesFinalizersAfterScaleDownBeforeDeleting(t *testing.T) {
+	client := newFakeDynamic(
+		newWorkload("Deployment", "gpu-controller", map[string]any{}),
+		newDevice("assigned", inUseProtection, "example.com/other"),
+		newDevice("free"),
+	)
+	namespaced := newDevice("namespaced", "gpu.deckhouse.io/pool-node-labels")
+	namespaced.SetNamespace("team-a")
+	if err := client.Tracker().Create(gpuDevicesGVR, namespaced, "team-a"); err != nil {
+		t.Fatalf("create namespaced object: %v", err)
+	}
+	hook := &PreDeleteHook{
+		dynamicClient: client,
+		WaitTimeout:   time.Second,
+		resources: []Resource{
+			{GVR: gpuDevicesGVR, Finalizers: []string{inUseProtection, "gpu.deckhouse.io/pool-node-labels"}},
+			{Action: ActionScaleDown, GVR: deploymentsGVR, Name: "gpu-controller", Namespace: "d8-gpu-control-plane"},
+		},
+	}
+
+	hook.Run(context.Background())
+
+	var verbs []string
+	for _, action := range client.Actions() {
+		switch action.GetVerb() {
+		case "patch", "update", "delete-collection":
+			verbs = append(verbs, action.GetVerb()+" "+action.GetResource().Resource)
+		}
+	}
+	want := []string{"patch deployments", "update gpudevices", "update gpudevices", "delete-collection gpudevices"}
+	if !slices.Equal(verbs, want) {
+		t.Fatalf("expected %v, got %v", want, verbs)
+	}
+}
+
+func TestReleaseFinalizersKeepsForeignFinalizers(t *testing.T) {
+	client := newFakeDynamic(newDevice("assigned", inUseProtection, "example.com/other"))
+	hook := &PreDeleteHook{dynamicClient: client}
+	res := Resource{GVR: gpuDevicesGVR, Name: "assigned", Finalizers: []string{inUseProtection}}
+
+	if err := hook.releaseFinalizers(context.Background(), hook.resourceClient(res), res); err != nil {
+		t.Fatalf("release finalizers: %v", err)
+	}
+	device, err := client.Resource(gpuDevicesGVR).Get(context.Background(), "assigned", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get device: %v", err)
+	}
+	if got := device.GetFinalizers(); !slices.Equal(got, []string{"example.com/other"}) {
+		t.Fatalf("expected only the listed finalizer to be removed, got %v", got)
+	}
+
+	// A missing object has nothing to release.
+	res.Name = "missing"
+	if err := hook.releaseFinalizers(context.Background(), hook.resourceClient(res), res); err != nil {
+		t.Fatalf("expected a missing object to be skipped, got %v", err)
+	}
+}
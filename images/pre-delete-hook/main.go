@@ -16,11 +16,11 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -31,13 +31,25 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// ActionDelete removes the resource (default when action is omitted).
+	ActionDelete = "delete"
+	// ActionScaleDown scales a workload to zero before any deletion starts.
+	ActionScaleDown = "scaleDown"
+
+	defaultMaxParallel = 5
 )
 
 type Resource struct {
-	GVR       schema.GroupVersionResource `json:"gvr"`
-	Name      string                      `json:"name"`
-	Namespace string                      `json:"namespace,omitempty"`
-	Selector  string                      `json:"selector,omitempty"`
+	Action     string                      `json:"action,omitempty"`
+	GVR        schema.GroupVersionResource `json:"gvr"`
+	Name       string                      `json:"name"`
+	Namespace  string                      `json:"namespace,omitempty"`
+	Selector   string                      `json:"selector,omitempty"`
+	Finalizers []string                    `json:"finalizers,omitempty"`
 }
 
 func (r *Resource) gvrString() string {
@@ -49,7 +61,11 @@ type PreDeleteHook struct {
 	resources       []Resource
 	KubeConfigPath  string        `env:"KUBECONFIG"`
 	ResourcesString string        `env:"RESOURCES"`
+	ResourcesFile   string        `env:"RESOURCES_FILE"`
 	WaitTimeout     time.Duration `env:"WAIT_TIMEOUT" env-default:"300s"`
+	MaxParallel     int           `env:"MAX_PARALLEL" env-default:"5"`
+	QPS             float32       `env:"QPS" env-default:"5"`
+	Burst           int           `env:"BURST" env-default:"10"`
 }
 
 func NewPreDeleteHook() (*PreDeleteHook, error) {
@@ -59,18 +75,29 @@ func NewPreDeleteHook() (*PreDeleteHook, error) {
 		return nil, fmt.Errorf("load environment: %w", err)
 	}
 
-	if hook.ResourcesString == "" {
-		return nil, fmt.Errorf("RESOURCES env can't be empty")
+	raw, source, err := readResources(hook.ResourcesFile, hook.ResourcesString)
+	if err != nil {
+		return nil, err
 	}
+	if raw == "" {
+		return nil, fmt.Errorf("%s can't be empty", source)
+	}
+	slog.Info("Loading resources", slog.String("source", source))
 
-	if err := json.Unmarshal([]byte(hook.ResourcesString), &hook.resources); err != nil {
-		return nil, fmt.Errorf("decode RESOURCES env: %w", err)
+	resources, err := decodeResources(source, raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", source, err)
+	}
+	if err := validateResources(resources); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", source, err)
 	}
+	hook.resources = resources
 
 	cfg, err := hook.buildConfig()
 	if err != nil {
 		return nil, fmt.Errorf("create kubernetes config: %w", err)
 	}
+	hook.applyRateLimit(cfg)
 
 	client, err := dynamicClientFactory(cfg)
 	if err != nil {
@@ -88,26 +115,95 @@ func (p *PreDeleteHook) buildConfig() (*rest.Config, error) {
 	return rest.InClusterConfig()
 }
 
-func (p *PreDeleteHook) Run(ctx context.Context) {
+// applyRateLimit bounds the total request rate of every poll and delete issued through the client,
+// independently of how many resources are processed in parallel.
+func (p *PreDeleteHook) applyRateLimit(cfg *rest.Config) {
+	if p.QPS <= 0 {
+		return
+	}
+	burst := p.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	cfg.QPS = p.QPS
+	cfg.Burst = burst
+	cfg.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(p.QPS, burst)
+}
+
+func (p *PreDeleteHook) parallelism() int {
+	if p.MaxParallel <= 0 {
+		return defaultMaxParallel
+	}
+	return p.MaxParallel
+}
+
+// Run scales the listed workloads down and then deletes the remaining resources. When a workload is not scaled
+// down, nothing is deleted: a controller that is still running could recreate the objects or block their removal.
+func (p *PreDeleteHook) Run(ctx context.Context) error {
 	if len(p.resources) == 0 {
 		slog.Info("nothing to delete")
-		return
+		return nil
 	}
 
-	var wg sync.WaitGroup
-	for _, resource := range p.resources {
-		res := resource
+	// Scale controllers down first so they cannot recreate objects removed in the deletion phase.
+	var scaleDowns, deletions []Resource
+	for _, res := range p.resources {
+		if res.Action == ActionScaleDown {
+			scaleDowns = append(scaleDowns, res)
+			continue
+		}
+		deletions = append(deletions, res)
+	}
 
-		slog.Info("Deleting resource ...",
-			slog.String("gvr", res.gvrString()),
-			slog.String("namespace", res.Namespace),
-			slog.String("name", res.Name),
-		)
+	var failed atomic.Int32
+	p.runParallel(ctx, scaleDowns, "Scaling down resource ...", func(ctx context.Context, res Resource) {
+		if err := p.scaleDownResource(ctx, res); err != nil {
+			slog.Error("Failed to scale down workload",
+				slog.Any("err", err),
+				slog.String("gvr", res.gvrString()),
+				slog.String("namespace", res.Namespace),
+				slog.String("name", res.Name),
+			)
+			failed.Add(1)
+		}
+	})
+	if n := failed.Load(); n > 0 {
+		return fmt.Errorf("%d of %d workloads were not scaled down, resources are not deleted", n, len(scaleDowns))
+	}
+
+	p.runParallel(ctx, deletions, "Deleting resource ...", p.deleteResource)
+	return nil
+}
+
+func (p *PreDeleteHook) runParallel(ctx context.Context, resources []Resource, msg string, fn func(context.Context, Resource)) {
+	var (
+		wg       sync.WaitGroup
+		queued   atomic.Int32
+		inFlight atomic.Int32
+	)
+	sem := make(chan struct{}, p.parallelism())
+	queued.Store(int32(len(resources)))
+
+	for _, resource := range resources {
+		res := resource
 
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			p.deleteResource(ctx, res)
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			running := inFlight.Add(1)
+			slog.Info(msg,
+				slog.String("gvr", res.gvrString()),
+				slog.String("namespace", res.Namespace),
+				slog.String("name", res.Name),
+				slog.Int("inFlight", int(running)),
+				slog.Int("queued", int(queued.Add(-1))),
+			)
+			fn(ctx, res)
+			inFlight.Add(-1)
 		}()
 	}
 
@@ -117,6 +213,15 @@ func (p *PreDeleteHook) Run(ctx context.Context) {
 func (p *PreDeleteHook) deleteResource(ctx context.Context, res Resource) {
 	resourceClient := p.resourceClient(res)
 
+	if err := p.releaseFinalizers(ctx, resourceClient, res); err != nil {
+		slog.Error("Failed to release finalizers",
+			slog.Any("err", err),
+			slog.String("gvr", res.gvrString()),
+			slog.String("namespace", res.Namespace),
+			slog.String("name", res.Name),
+		)
+	}
+
 	if res.Name == "" {
 		if err := resourceClient.DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: res.Selector}); err != nil {
 			p.handleDeleteError(err, res)
@@ -254,6 +359,11 @@ func (p *PreDeleteHook) resourceClient(res Resource) dynamic.ResourceInterface {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		exitFunc(runValidate(os.Args[2:], os.Getenv("RESOURCES"), os.Stdout))
+		return
+	}
+
 	ctx := context.Background()
 
 	hook, err := newPreDeleteHook()
@@ -263,7 +373,10 @@ func main() {
 		return
 	}
 
-	hook.Run(ctx)
+	if err := hook.Run(ctx); err != nil {
+		slog.Error("Pre-delete hook failed", slog.Any("err", err))
+		exitFunc(1)
+	}
 }
 
 var (
@@ -17,9 +17,11 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -72,6 +74,96 @@ func TestNewPreDeleteHookSuccess(t *testing.T) {
 	}
 }
 
+func TestNewPreDeleteHookParallelismAndRateLimitDefaults(t *testing.T) {
+	t.Setenv("RESOURCES", `[{"gvr":{"group":"deckhouse.io","version":"v1","resource":"tests"},"name":"test"}]`)
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+
+	var captured *rest.Config
+	orig := dynamicClientFactory
+	dynamicClientFactory = func(cfg *rest.Config) (*dynamic.DynamicClient, error) {
+		captured = cfg
+		return orig(cfg)
+	}
+	t.Cleanup(func() { dynamicClientFactory = orig })
+
+	hook, err := NewPreDeleteHook()
+	if err != nil {
+		t.Fatalf("unexpected error creating hook: %v", err)
+	}
+	if hook.MaxParallel != 5 || hook.QPS != 5 || hook.Burst != 10 {
+		t.Fatalf("unexpected defaults: maxParallel=%d qps=%v burst=%d", hook.MaxParallel, hook.QPS, hook.Burst)
+	}
+	if captured == nil || captured.RateLimiter == nil {
+		t.Fatal("expected rate limiter to be wired into the client config")
+	}
+	if captured.QPS != 5 || captured.Burst != 10 {
+		t.Fatalf("unexpected client limits: qps=%v burst=%d", captured.QPS, captured.Burst)
+	}
+}
+
+func TestNewPreDeleteHookParallelismAndRateLimitFromEnv(t *testing.T) {
+	t.Setenv("RESOURCES", `[]`)
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+	t.Setenv("MAX_PARALLEL", "2")
+	t.Setenv("QPS", "1.5")
+	t.Setenv("BURST", "3")
+
+	hook, err := NewPreDeleteHook()
+	if err != nil {
+		t.Fatalf("unexpected error creating hook: %v", err)
+	}
+	if hook.MaxParallel != 2 || hook.QPS != 1.5 || hook.Burst != 3 {
+		t.Fatalf("unexpected settings: maxParallel=%d qps=%v burst=%d", hook.MaxParallel, hook.QPS, hook.Burst)
+	}
+}
+
+func TestApplyRateLimit(t *testing.T) {
+	cfg := &rest.Config{}
+	(&PreDeleteHook{}).applyRateLimit(cfg)
+	if cfg.RateLimiter != nil || cfg.QPS != 0 {
+		t.Fatalf("expected zero QPS to leave client defaults, got %#v", cfg)
+	}
+
+	cfg = &rest.Config{}
+	(&PreDeleteHook{QPS: 2}).applyRateLimit(cfg)
+	if cfg.RateLimiter == nil || cfg.QPS != 2 || cfg.Burst != 1 {
+		t.Fatalf("expected burst to fall back to 1, got qps=%v burst=%d", cfg.QPS, cfg.Burst)
+	}
+}
+
+func TestRunLimitsParallelDeletes(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		maxParallel int
+		want        int32
+	}{
+		{name: "explicit limit", maxParallel: 3, want: 3},
+		{name: "default limit", maxParallel: 0, want: defaultMaxParallel},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gvr := schema.GroupVersionResource{Group: "deckhouse.io", Version: "v1", Resource: "tests"}
+			stub := &concurrencyResource{gr: gvr.GroupResource(), hold: 20 * time.Millisecond}
+			hook := &PreDeleteHook{
+				dynamicClient: &fakeDynamicClient{iface: &concurrencyNamespaceable{concurrencyResource: stub}},
+				MaxParallel:   tc.maxParallel,
+				WaitTimeout:   time.Second,
+			}
+			for i := 0; i < 12; i++ {
+				hook.resources = append(hook.resources, Resource{GVR: gvr, Name: fmt.Sprintf("test-%d", i)})
+			}
+
+			hook.Run(context.Background())
+
+			if got := stub.calls.Load(); got != 12 {
+				t.Fatalf("expected 12 deletes, got %d", got)
+			}
+			if got := stub.maxInFlight.Load(); got != tc.want {
+				t.Fatalf("expected at most %d deletes in flight, observed %d", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestDeleteResourceHandlesDeleteError(t *testing.T) {
 	resIface := &fakeResource{deleteErr: errors.New("boom")}
 	hook := &PreDeleteHook{dynamicClient: &fakeDynamicClient{iface: &fakeNamespaceable{fakeResource: resIface}}}
@@ -312,6 +404,27 @@ func TestMainExecutesHook(t *testing.T) {
 	}
 }
 
+func TestMainExitsWhenScaleDownFails(t *testing.T) {
+	t.Cleanup(func() {
+		newPreDeleteHook = NewPreDeleteHook
+		exitFunc = os.Exit
+	})
+
+	newPreDeleteHook = func() (*PreDeleteHook, error) {
+		return &PreDeleteHook{resources: []Resource{
+			{Action: ActionScaleDown, GVR: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, Name: "x"},
+		}}, nil
+	}
+	var exited atomic.Int32
+	exitFunc = func(code int) { exited.Store(int32(code)) }
+
+	main()
+
+	if exited.Load() != 1 {
+		t.Fatalf("expected exit code 1, got %d", exited.Load())
+	}
+}
+
 func TestBuildConfigUsesKubeconfig(t *testing.T) {
 	hook := &PreDeleteHook{KubeConfigPath: writeTempKubeconfig(t)}
 	if _, err := hook.buildConfig(); err != nil {
@@ -499,6 +612,46 @@ func (f *fakeNamespaceable) Cluster(ns string) dynamic.ResourceInterface {
 	return f
 }
 
+// concurrencyResource blocks every Delete for a short while and records the peak number of concurrent calls.
+type concurrencyResource struct {
+	dynamic.ResourceInterface
+	gr          schema.GroupResource
+	hold        time.Duration
+	mu          sync.Mutex
+	inFlight    int32
+	maxInFlight atomic.Int32
+	calls       atomic.Int32
+}
+
+func (c *concurrencyResource) Delete(context.Context, string, metav1.DeleteOptions, ...string) error {
+	c.calls.Add(1)
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight.Load() {
+		c.maxInFlight.Store(c.inFlight)
+	}
+	c.mu.Unlock()
+
+	time.Sleep(c.hold)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *concurrencyResource) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, kerrors.NewNotFound(c.gr, name)
+}
+
+type concurrencyNamespaceable struct {
+	*concurrencyResource
+}
+
+func (c *concurrencyNamespaceable) Namespace(string) dynamic.ResourceInterface {
+	return c.concurrencyResource
+}
+
 type listResponse struct {
 	list *unstructured.UnstructuredList
 	err  error
@@ -0,0 +1,118 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const resourcesEnvSource = "RESOURCES env"
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// readResources returns the RESOURCES payload and where it came from. A RESOURCES_FILE path wins over the RESOURCES
+// env, which stays as the fallback.
+func readResources(file, env string) (string, string, error) {
+	if file == "" {
+		return env, resourcesEnvSource, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", file, fmt.Errorf("read %s: %w", file, err)
+	}
+	return string(data), file, nil
+}
+
+// decodeResources parses a RESOURCES payload without checking its entries. The payload is JSON or YAML: a .json,
+// .yaml or .yml source decides, otherwise a payload starting with '[' is JSON.
+func decodeResources(source, raw string) ([]Resource, error) {
+	if isYAML(source, raw) {
+		return decodeYAML(raw)
+	}
+	return decodeJSON(raw)
+}
+
+func isYAML(source, raw string) bool {
+	switch strings.ToLower(filepath.Ext(source)) {
+	case ".json":
+		return false
+	case ".yaml", ".yml":
+		return true
+	}
+	return !strings.HasPrefix(strings.TrimSpace(raw), "[")
+}
+
+func decodeJSON(raw string) ([]Resource, error) {
+	var resources []Resource
+	if err := json.Unmarshal([]byte(raw), &resources); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, withPosition(raw, syntaxErr.Offset, err)
+		case errors.As(err, &typeErr):
+			return nil, withPosition(raw, typeErr.Offset, err)
+		}
+		return nil, err
+	}
+	return resources, nil
+}
+
+func decodeYAML(raw string) ([]Resource, error) {
+	data, err := yaml.YAMLToJSON([]byte(raw))
+	if err != nil {
+		return nil, withLineExcerpt(raw, err)
+	}
+	var resources []Resource
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// withPosition turns the offset of a JSON error, which counts the offending byte, into its line and column.
+func withPosition(raw string, offset int64, err error) error {
+	offset--
+	if offset < 0 || offset >= int64(len(raw)) {
+		return err
+	}
+	before := raw[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := int(offset) - strings.LastIndex(before, "\n")
+	return fmt.Errorf("line %d, column %d: %w", line, column, err)
+}
+
+// withLineExcerpt appends the offending source line to a YAML error, which only reports its line number.
+func withLineExcerpt(raw string, err error) error {
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	n, _ := strconv.Atoi(match[1])
+	lines := strings.Split(raw, "\n")
+	if n < 1 || n > len(lines) {
+		return err
+	}
+	return fmt.Errorf("%w (line %d: %q)", err, n, strings.TrimRight(lines[n-1], "\r"))
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const yamlPayload = `
+- action: scaleDown
+  gvr: {group: apps, version: v1, resource: deployments}
+  name: controller
+  namespace: d8-gpu-control-plane
+- gvr:
+    group: gpu.deckhouse.io
+    version: v1alpha1
+    resource: gpupools
+`
+
+func writeResourcesFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write resources file: %v", err)
+	}
+	return path
+}
+
+func TestNewPreDeleteHookReadsJSONFile(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+	t.Setenv("RESOURCES", "")
+	t.Setenv("RESOURCES_FILE", writeResourcesFile(t, "resources.json", cleanPayload))
+
+	hook, err := NewPreDeleteHook()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hook.resources) != 3 || hook.resources[2].Selector != "app in (gpu, dra)" {
+		t.Fatalf("unexpected resources: %#v", hook.resources)
+	}
+}
+
+func TestNewPreDeleteHookReadsYAMLFile(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+	t.Setenv("RESOURCES_FILE", writeResourcesFile(t, "resources.yaml", yamlPayload))
+
+	hook, err := NewPreDeleteHook()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hook.resources) != 2 {
+		t.Fatalf("expected two resources, got %#v", hook.resources)
+	}
+	first, second := hook.resources[0], hook.resources[1]
+	if first.Action != ActionScaleDown || first.GVR.Resource != "deployments" || first.Namespace != "d8-gpu-control-plane" {
+		t.Fatalf("unexpected first resource: %#v", first)
+	}
+	if second.GVR.Group != "gpu.deckhouse.io" || second.GVR.Version != "v1alpha1" || second.GVR.Resource != "gpupools" {
+		t.Fatalf("unexpected second resource: %#v", second)
+	}
+}
+
+func TestNewPreDeleteHookFallsBackToEnv(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+	t.Setenv("RESOURCES_FILE", "")
+	t.Setenv("RESOURCES", cleanPayload)
+
+	hook, err := NewPreDeleteHook()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hook.resources) != 3 {
+		t.Fatalf("expected the env payload, got %#v", hook.resources)
+	}
+}
+
+func TestNewPreDeleteHookFileWinsOverEnv(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeTempKubeconfig(t))
+	t.Setenv("RESOURCES", cleanPayload)
+	t.Setenv("RESOURCES_FILE", writeResourcesFile(t, "resources.yaml", yamlPayload))
+
+	hook, err := NewPreDeleteHook()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hook.resources) != 2 {
+		t.Fatalf("expected the file payload, got %#v", hook.resources)
+	}
+}
+
+func TestNewPreDeleteHookFileErrors(t *testing.T) {
+	t.Setenv("RESOURCES", cleanPayload)
+
+	t.Setenv("RESOURCES_FILE", filepath.Join(t.TempDir(), "absent.yaml"))
+	if _, err := NewPreDeleteHook(); err == nil || !strings.Contains(err.Error(), "read ") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+
+	path := writeResourcesFile(t, "resources.yaml", "")
+	t.Setenv("RESOURCES_FILE", path)
+	if _, err := NewPreDeleteHook(); err == nil || !strings.Contains(err.Error(), path+" can't be empty") {
+		t.Fatalf("expected empty file error, got %v", err)
+	}
+}
+
+func TestDecodeResourcesMalformedYAMLReportsLine(t *testing.T) {
+	malformed := "- gvr:\n    group: apps\n    version: v1\n  resource: [deployments\n"
+	_, err := decodeResources("resources.yaml", malformed)
+	if err == nil {
+		t.Fatal("expected decode error")
+	}
+	if !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), `"  resource: [deployments"`) {
+		t.Fatalf("expected the error to point at line 4, got %v", err)
+	}
+}
+
+func TestDecodeResourcesMalformedJSONReportsPosition(t *testing.T) {
+	_, err := decodeResources("resources.json", "[\n  {\"name\": \"a\",}\n]")
+	if err == nil || !strings.Contains(err.Error(), "line 2, column 16") {
+		t.Fatalf("expected line and column in the error, got %v", err)
+	}
+}
+
+func TestDecodeResourcesDetectsFormatByContent(t *testing.T) {
+	resources, err := decodeResources(resourcesEnvSource, yamlPayload)
+	if err != nil || len(resources) != 2 {
+		t.Fatalf("expected YAML env payload to decode, got %v, %#v", err, resources)
+	}
+	resources, err = decodeResources(resourcesEnvSource, cleanPayload)
+	if err != nil || len(resources) != 3 {
+		t.Fatalf("expected JSON env payload to decode, got %v, %#v", err, resources)
+	}
+}
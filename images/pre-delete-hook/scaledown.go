@@ -0,0 +1,128 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// scaleDownNodeSelectorKey is a node selector no node carries; it drains DaemonSet pods
+// without deleting the DaemonSet itself.
+const scaleDownNodeSelectorKey = "gpu.deckhouse.io/pre-delete-hook-scaled-down"
+
+// workloadScale describes how to scale a workload kind to zero and observe the result.
+type workloadScale struct {
+	patch      map[string]any
+	readyField []string
+}
+
+var workloadScales = map[string]workloadScale{
+	"deployments": {
+		patch:      map[string]any{"spec": map[string]any{"replicas": 0}},
+		readyField: []string{"status", "readyReplicas"},
+	},
+	"statefulsets": {
+		patch:      map[string]any{"spec": map[string]any{"replicas": 0}},
+		readyField: []string{"status", "readyReplicas"},
+	},
+	"daemonsets": {
+		patch: map[string]any{"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+			"nodeSelector": map[string]any{scaleDownNodeSelectorKey: "true"},
+		}}}},
+		readyField: []string{"status", "numberReady"},
+	},
+}
+
+// readyCount reads the ready counter of a scaled workload; absent fields are reported as zero.
+func readyCount(obj *unstructured.Unstructured, field []string) (int64, error) {
+	value, _, err := unstructured.NestedInt64(obj.Object, field...)
+	if err != nil {
+		return 0, fmt.Errorf("read %v: %w", field, err)
+	}
+	return value, nil
+}
+
+// scaleDownResource scales the workload to zero and waits until no pod of it is ready. Only a workload that is
+// absent or reports zero ready pods counts as scaled down; any other outcome is returned as an error.
+func (p *PreDeleteHook) scaleDownResource(ctx context.Context, res Resource) error {
+	scale, ok := workloadScales[res.GVR.Resource]
+	if !ok {
+		return fmt.Errorf("scaleDown is not supported for %s", res.GVR.Resource)
+	}
+
+	data, err := json.Marshal(scale.patch)
+	if err != nil {
+		return fmt.Errorf("encode scale down patch: %w", err)
+	}
+
+	resourceClient := p.resourceClient(res)
+	if _, err := resourceClient.Patch(ctx, res.Name, types.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+		if errors.IsNotFound(err) {
+			slog.Info("Workload already absent",
+				slog.String("gvr", res.gvrString()),
+				slog.String("namespace", res.Namespace),
+				slog.String("name", res.Name),
+			)
+			return nil
+		}
+		return fmt.Errorf("scale down workload: %w", err)
+	}
+
+	return p.waitForScaleDown(ctx, resourceClient, res, scale.readyField)
+}
+
+func (p *PreDeleteHook) waitForScaleDown(ctx context.Context, client dynamic.ResourceInterface, res Resource, field []string) error {
+	deadline := time.Now().Add(p.WaitTimeout)
+	for time.Now().Before(deadline) {
+		obj, err := client.Get(ctx, res.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("check workload status: %w", err)
+		}
+
+		ready, err := readyCount(obj, field)
+		if err != nil {
+			return fmt.Errorf("read workload status: %w", err)
+		}
+		if ready == 0 {
+			slog.Info("Workload is scaled down",
+				slog.String("gvr", res.gvrString()),
+				slog.String("namespace", res.Namespace),
+				slog.String("name", res.Name),
+			)
+			return nil
+		}
+
+		select {
+		case <-sleepAfter(2 * time.Second):
+		case <-ctx.Done():
+			return fmt.Errorf("wait for workload scale down: %w", ctx.Err())
+		}
+	}
+
+	return fmt.Errorf("timeout waiting for workload scale down after %s", p.WaitTimeout)
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	daemonSetsGVR  = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	testsGVR       = schema.GroupVersionResource{Group: "deckhouse.io", Version: "v1", Resource: "tests"}
+	gpuDevicesGVR  = schema.GroupVersionResource{Group: "gpu.deckhouse.io", Version: "v1alpha1", Resource: "gpudevices"}
+)
+
+func newWorkload(kind, name string, status map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name, "namespace": "d8-gpu-control-plane"},
+		"spec":       map[string]any{"replicas": int64(2)},
+		"status":     status,
+	}}
+}
+
+func newFakeDynamic(objs ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList",
+		daemonSetsGVR:  "DaemonSetList",
+		testsGVR:       "TestList",
+		gpuDevicesGVR:  "GPUDeviceList",
+	}, objs...)
+}
+
+func TestScaleDownDeployment(t *testing.T) {
+	client := newFakeDynamic(newWorkload("Deployment", "gpu-controller", map[string]any{"readyReplicas": int64(2)}))
+	// Simulate the deployment controller: once replicas reach zero, ready pods disappear on the next read.
+	client.PrependReactor("patch", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		obj, _ := client.Tracker().Get(deploymentsGVR, "d8-gpu-control-plane", "gpu-controller")
+		u := obj.(*unstructured.Unstructured)
+		_ = unstructured.SetNestedField(u.Object, int64(0), "status", "readyReplicas")
+		_ = client.Tracker().Update(deploymentsGVR, u, "d8-gpu-control-plane")
+		return false, nil, nil
+	})
+	hook := &PreDeleteHook{dynamicClient: client, WaitTimeout: time.Second}
+
+	hook.scaleDownResource(context.Background(), Resource{
+		Action:    ActionScaleDown,
+		GVR:       deploymentsGVR,
+		Name:      "gpu-controller",
+		Namespace: "d8-gpu-control-plane",
+	})
+
+	obj, err := client.Resource(deploymentsGVR).Namespace("d8-gpu-control-plane").Get(context.Background(), "gpu-controller", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get deployment: %v", err)
+	}
+	replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if replicas != 0 {
+		t.Fatalf("expected replicas to be 0, got %d", replicas)
+	}
+}
+
+func TestScaleDownDaemonSetWaitsForPodsToDrain(t *testing.T) {
+	client := newFakeDynamic(newWorkload("DaemonSet", "gpu-handler", map[string]any{"numberReady": int64(3)}))
+	gets := 0
+	client.PrependReactor("get", "daemonsets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		if gets < 2 {
+			return false, nil, nil
+		}
+		obj, _ := client.Tracker().Get(daemonSetsGVR, "d8-gpu-control-plane", "gpu-handler")
+		u := obj.DeepCopyObject().(*unstructured.Unstructured)
+		_ = unstructured.SetNestedField(u.Object, int64(0), "status", "numberReady")
+		return true, u, nil
+	})
+	hook := &PreDeleteHook{dynamicClient: client, WaitTimeout: time.Second}
+
+	originalSleep := sleepAfter
+	sleepAfter = func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+	defer func() { sleepAfter = originalSleep }()
+
+	hook.scaleDownResource(context.Background(), Resource{
+		Action:    ActionScaleDown,
+		GVR:       daemonSetsGVR,
+		Name:      "gpu-handler",
+		Namespace: "d8-gpu-control-plane",
+	})
+
+	if gets < 2 {
+		t.Fatalf("expected status to be polled until numberReady reached zero, got %d gets", gets)
+	}
+	obj, err := client.Tracker().Get(daemonSetsGVR, "d8-gpu-control-plane", "gpu-handler")
+	if err != nil {
+		t.Fatalf("get daemonset: %v", err)
+	}
+	selector, _, _ := unstructured.NestedStringMap(obj.(*unstructured.Unstructured).Object, "spec", "template", "spec", "nodeSelector")
+	if selector[scaleDownNodeSelectorKey] != "true" {
+		t.Fatalf("expected poison node selector, got %v", selector)
+	}
+}
+
+func TestScaleDownMissingWorkload(t *testing.T) {
+	hook := &PreDeleteHook{dynamicClient: newFakeDynamic(), WaitTimeout: time.Second}
+
+	if err := hook.scaleDownResource(context.Background(), Resource{Action: ActionScaleDown, GVR: deploymentsGVR, Name: "absent", Namespace: "ns"}); err != nil {
+		t.Fatalf("expected an absent workload to count as scaled down, got %v", err)
+	}
+}
+
+func TestScaleDownUnsupportedResource(t *testing.T) {
+	client := newFakeDynamic()
+	hook := &PreDeleteHook{dynamicClient: client, WaitTimeout: time.Second}
+
+	if err := hook.scaleDownResource(context.Background(), Resource{Action: ActionScaleDown, GVR: testsGVR, Name: "x"}); err == nil {
+		t.Fatalf("expected an error for an unsupported resource")
+	}
+	if len(client.Actions()) != 0 {
+		t.Fatalf("expected no API calls for unsupported resource, got %v", client.Actions())
+	}
+}
+
+func TestRunScalesDownBeforeDeleting(t *testing.T) {
+	test := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "deckhouse.io/v1",
+		"kind":       "Test",
+		"metadata":   map[string]any{"name": "sample"},
+	}}
+	client := newFakeDynamic(newWorkload("Deployment", "gpu-controller", map[string]any{}), test)
+	hook := &PreDeleteHook{
+		dynamicClient: client,
+		WaitTimeout:   time.Second,
+		resources: []Resource{
+			{GVR: testsGVR, Name: "sample"},
+			{Action: ActionScaleDown, GVR: deploymentsGVR, Name: "gpu-controller", Namespace: "d8-gpu-control-plane"},
+		},
+	}
+
+	hook.Run(context.Background())
+
+	var verbs []string
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" || action.GetVerb() == "delete" {
+			verbs = append(verbs, action.GetVerb()+" "+action.GetResource().Resource)
+		}
+	}
+	if len(verbs) != 2 || verbs[0] != "patch deployments" || verbs[1] != "delete tests" {
+		t.Fatalf("expected scale down before deletion, got %v", verbs)
+	}
+}
+
+func TestScaleDownFailsUnlessReadyDropsToZero(t *testing.T) {
+	res := Resource{Action: ActionScaleDown, GVR: deploymentsGVR, Name: "gpu-controller", Namespace: "d8-gpu-control-plane"}
+	for name, tc := range map[string]struct {
+		status  map[string]any
+		getErr  error
+		timeout time.Duration
+		cancel  bool
+	}{
+		"get error":        {status: map[string]any{"readyReplicas": int64(1)}, getErr: errors.New("boom"), timeout: time.Second},
+		"unreadable":       {status: map[string]any{"readyReplicas": "one"}, timeout: time.Second},
+		"context canceled": {status: map[string]any{"readyReplicas": int64(1)}, timeout: time.Minute, cancel: true},
+		"timeout":          {status: map[string]any{"readyReplicas": int64(1)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeDynamic(newWorkload("Deployment", res.Name, tc.status))
+			if tc.getErr != nil {
+				client.PrependReactor("get", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.getErr
+				})
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.cancel {
+				cancel()
+			} else {
+				defer cancel()
+			}
+			hook := &PreDeleteHook{dynamicClient: client, WaitTimeout: tc.timeout}
+
+			if err := hook.scaleDownResource(ctx, res); err == nil {
+				t.Fatalf("expected the scale down to fail")
+			}
+		})
+	}
+}
+
+func TestRunSkipsDeletionWhenScaleDownFails(t *testing.T) {
+	client := newFakeDynamic(newWorkload("Deployment", "gpu-controller", map[string]any{"readyReplicas": int64(1)}))
+	client.PrependReactor("get", "deployments", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("boom")
+	})
+	hook := &PreDeleteHook{
+		dynamicClient: client,
+		WaitTimeout:   time.Second,
+		resources: []Resource{
+			{Action: ActionScaleDown, GVR: deploymentsGVR, Name: "gpu-controller", Namespace: "d8-gpu-control-plane"},
+			{GVR: testsGVR, Name: "sample"},
+		},
+	}
+
+	if err := hook.Run(context.Background()); err == nil {
+		t.Fatalf("expected Run to fail")
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete" {
+			t.Fatalf("expected no deletion while the controller may still run, got %v", client.Actions())
+		}
+	}
+}
+
+func TestResourceActionDecoding(t *testing.T) {
+	var resources []Resource
+	raw := `[{"action":"scaleDown","gvr":{"group":"apps","version":"v1","resource":"deployments"},"name":"gpu-controller","namespace":"x"},{"gvr":{"group":"gpu.deckhouse.io","version":"v1alpha1","resource":"gpupools"}}]`
+	if err := json.Unmarshal([]byte(raw), &resources); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resources[0].Action != ActionScaleDown || resources[1].Action != "" {
+		t.Fatalf("unexpected actions: %+v", resources)
+	}
+}
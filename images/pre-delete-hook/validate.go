@@ -0,0 +1,137 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// validateResources reports every structural problem of the payload, one joined error per entry.
+func validateResources(resources []Resource) error {
+	var errs []error
+	for i, res := range resources {
+		for _, problem := range resourceProblems(res) {
+			errs = append(errs, fmt.Errorf("resources[%d]: %s", i, problem))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func resourceProblems(res Resource) []string {
+	var problems []string
+	switch res.Action {
+	case "", ActionDelete, ActionScaleDown:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown action %q", res.Action))
+	}
+	// The core API group is empty, so only version and resource are mandatory.
+	if res.GVR.Version == "" {
+		problems = append(problems, "gvr.version is empty")
+	}
+	if res.GVR.Resource == "" {
+		problems = append(problems, "gvr.resource is empty")
+	}
+	if res.Name != "" && res.Selector != "" {
+		problems = append(problems, "name and selector are mutually exclusive")
+	}
+	if res.Selector != "" {
+		if _, err := labels.Parse(res.Selector); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid selector %q: %v", res.Selector, err))
+		}
+	}
+	if res.Action == ActionScaleDown {
+		if res.Name == "" {
+			problems = append(problems, "scaleDown requires a name")
+		}
+		if _, ok := workloadScales[res.GVR.Resource]; !ok && res.GVR.Resource != "" {
+			problems = append(problems, fmt.Sprintf("scaleDown is not supported for %s", res.GVR.Resource))
+		}
+		if len(res.Finalizers) > 0 {
+			problems = append(problems, "finalizers are only released on deletion")
+		}
+	}
+	return problems
+}
+
+// describeTarget renders what a resource entry would act on.
+func describeTarget(res Resource) string {
+	scope := "cluster"
+	if res.Namespace != "" {
+		scope = "namespace " + res.Namespace
+	}
+	switch {
+	case res.Name != "":
+		return fmt.Sprintf("%s (%s)", res.Name, scope)
+	case res.Selector != "":
+		return fmt.Sprintf("objects matching %q (%s)", res.Selector, scope)
+	default:
+		return fmt.Sprintf("all objects (%s)", scope)
+	}
+}
+
+// runValidate implements the validate subcommand: it checks RESOURCES from the environment or --file
+// (RESOURCES_FILE by default) and prints what the hook would target. It never talks to a cluster.
+func runValidate(args []string, env string, out io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	file := fs.String("file", os.Getenv("RESOURCES_FILE"), "read the RESOURCES payload, JSON or YAML, from a file instead of the RESOURCES env")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	raw, source, err := readResources(*file, env)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	if raw == "" {
+		fmt.Fprintf(out, "%s is empty\n", source)
+		return 1
+	}
+
+	resources, err := decodeResources(source, raw)
+	if err != nil {
+		fmt.Fprintf(out, "decode %s: %v\n", source, err)
+		return 1
+	}
+
+	problems := 0
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for i, res := range resources {
+		action := res.Action
+		if action == "" {
+			action = ActionDelete
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i, action, res.gvrString(), describeTarget(res))
+		for _, problem := range resourceProblems(res) {
+			fmt.Fprintf(tw, "\tERROR\t%s\t\n", problem)
+			problems++
+		}
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(out, "%d resources, %d problems\n", len(resources), problems)
+	if problems > 0 {
+		return 1
+	}
+	return 0
+}
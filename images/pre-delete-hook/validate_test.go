@@ -0,0 +1,141 @@
+// Copyright 2025 Flant JSC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const cleanPayload = `[
+  {"action":"scaleDown","gvr":{"group":"apps","version":"v1","resource":"deployments"},"name":"controller","namespace":"d8-gpu-control-plane"},
+  {"gvr":{"group":"gpu.deckhouse.io","version":"v1alpha1","resource":"gpupools"},"name":""},
+  {"gvr":{"group":"","version":"v1","resource":"configmaps"},"selector":"app in (gpu, dra)","namespace":"d8-gpu-control-plane"}
+]`
+
+func TestValidateResourcesAcceptsCleanPayload(t *testing.T) {
+	resources, err := decodeResources(resourcesEnvSource, cleanPayload)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if err := validateResources(resources); err != nil {
+		t.Fatalf("expected clean payload, got %v", err)
+	}
+}
+
+func TestValidateResourcesReportsProblems(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "deckhouse.io", Version: "v1", Resource: "tests"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	tests := []struct {
+		name string
+		res  Resource
+		want string
+	}{
+		{name: "empty version", res: Resource{GVR: schema.GroupVersionResource{Resource: "tests"}, Name: "a"}, want: "gvr.version is empty"},
+		{name: "empty resource", res: Resource{GVR: schema.GroupVersionResource{Version: "v1"}, Name: "a"}, want: "gvr.resource is empty"},
+		{name: "name and selector", res: Resource{GVR: gvr, Name: "a", Selector: "app=x"}, want: "name and selector are mutually exclusive"},
+		{name: "invalid selector", res: Resource{GVR: gvr, Selector: "app in (x"}, want: "invalid selector"},
+		{name: "unknown action", res: Resource{GVR: gvr, Name: "a", Action: "purge"}, want: `unknown action "purge"`},
+		{name: "scaleDown without name", res: Resource{GVR: deployments, Action: ActionScaleDown}, want: "scaleDown requires a name"},
+		{name: "scaleDown unsupported kind", res: Resource{GVR: gvr, Name: "a", Action: ActionScaleDown}, want: "scaleDown is not supported for tests"},
+		{name: "scaleDown with finalizers", res: Resource{GVR: deployments, Name: "a", Action: ActionScaleDown, Finalizers: []string{"x"}}, want: "finalizers are only released on deletion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateResources([]Resource{{GVR: gvr, Name: "ok"}, tt.res})
+			if err == nil || !strings.Contains(err.Error(), "resources[1]: "+tt.want) {
+				t.Fatalf("expected %q for resources[1], got %v", tt.want, err)
+			}
+			if strings.Contains(err.Error(), "resources[0]") {
+				t.Fatalf("expected valid entry to pass, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewPreDeleteHookRejectsInvalidResources(t *testing.T) {
+	t.Setenv("RESOURCES", `[{"gvr":{"group":"deckhouse.io","resource":"tests"},"name":"test"}]`)
+	if _, err := NewPreDeleteHook(); err == nil || !strings.Contains(err.Error(), "invalid RESOURCES env") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestRunValidatePrintsReport(t *testing.T) {
+	var out bytes.Buffer
+	if code := runValidate(nil, cleanPayload, &out); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, out.String())
+	}
+	for _, want := range []string{
+		"scaleDown",
+		"controller (namespace d8-gpu-control-plane)",
+		"all objects (cluster)",
+		`objects matching "app in (gpu, dra)"`,
+		"3 resources, 0 problems",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected report to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunValidateReadsFileAndFailsOnProblems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resources.json")
+	payload := `[{"gvr":{"group":"apps","version":"v1","resource":"deployments"},"name":"a","selector":"app=x"}]`
+	if err := os.WriteFile(path, []byte(payload), 0o600); err != nil {
+		t.Fatalf("write payload: %v", err)
+	}
+
+	var out bytes.Buffer
+	if code := runValidate([]string{"--file", path}, cleanPayload, &out); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "ERROR") || !strings.Contains(out.String(), "1 resources, 1 problems") {
+		t.Fatalf("expected the file payload to be reported, got:\n%s", out.String())
+	}
+}
+
+func TestRunValidateInputErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		code int
+		want string
+	}{
+		{name: "empty env", code: 1, want: "RESOURCES env is empty"},
+		{name: "invalid json", env: "not-json", code: 1, want: "decode RESOURCES env"},
+		{name: "missing file", args: []string{"--file", filepath.Join(t.TempDir(), "absent.json")}, code: 1, want: "read "},
+		{name: "unknown flag", args: []string{"--bogus"}, code: 2, want: "flag provided but not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if code := runValidate(tt.args, tt.env, &out); code != tt.code {
+				t.Fatalf("expected exit code %d, got %d: %s", tt.code, code, out.String())
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Fatalf("expected output to contain %q, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
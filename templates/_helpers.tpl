@@ -105,6 +105,10 @@ deckhouse-gpu-kernel-os
 {{- default "/" (dig "internal" "nvidiaDriverRoot" "" .Values.gpuControlPlane) -}}
 {{- end -}}
 
+{{- define "gpuControlPlane.nvidiaDriverInstallType" -}}
+{{- default "Operator" (dig "internal" "nvidiaDriverInstallType" "" .Values.gpuControlPlane) -}}
+{{- end -}}
+
 {{- define "gpuControlPlane.cdiRoot" -}}
 {{- default "/etc/cdi" (dig "internal" "cdiRoot" "" .Values.gpuControlPlane) -}}
 {{- end -}}